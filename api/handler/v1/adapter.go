@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +19,48 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	// job ownership is transported as reserved labels of job specification
+	labelOwnershipEmail        = "ownership.email"
+	labelOwnershipTeam         = "ownership.team"
+	labelOwnershipSlackChannel = "ownership.slack_channel"
+
+	// pinned task plugin version is transported as reserved label of job specification
+	labelTaskVersion = "task.version"
+
+	// template engine of assets is transported as reserved label of job specification
+	labelTaskTemplateEngine = "task.template_engine"
+
+	// auto-heal policy is transported as reserved labels of job specification
+	labelAutoHealWindow    = "auto_heal.window"
+	labelAutoHealMaxPerDay = "auto_heal.max_per_day"
+
+	// schedule exceptions are transported as reserved labels of job specification
+	// with comma separated values
+	labelScheduleSkipDates = "schedule.skip_dates"
+	labelScheduleExtraRuns = "schedule.extra_runs"
+	labelScheduleCalendars = "schedule.calendars"
+
+	// cap on active runs is transported as reserved label of job specification
+	labelScheduleMaxActiveRuns = "schedule.max_active_runs"
+
+	// retention of destination is transported as reserved label of job specification
+	labelRetentionPartitionDays = "lifecycle.retention.partition_days"
+
+	// replay presets are transported as reserved labels of job specification
+	// prefixed to their name, with json encoded values
+	labelReplayPresetPrefix = "replay_preset."
+
+	// sensors of dependencies are transported as reserved labels of job
+	// specification prefixed to name of dependency, with json encoded values
+	labelDependencySensorPrefix = "dependency_sensor."
+
+	// freshness assertions of dependencies are transported as reserved labels
+	// of job specification prefixed to name of dependency, with json encoded
+	// values
+	labelDependencyFreshnessPrefix = "dependency_freshness."
+)
+
 // Note: all config keys will be converted to upper case automatically
 type Adapter struct {
 	pluginRepo             models.PluginRepository
@@ -91,16 +135,64 @@ func (adapt *Adapter) FromJobProto(spec *pb.JobSpecification) (models.JobSpec, e
 			})
 		}
 	}
-	return models.JobSpec{
+	labels, ownership := fromOwnershipLabels(spec.Labels)
+	labels, taskVersion := fromTaskVersionLabel(labels)
+	labels, templateEngine, err := fromTemplateEngineLabel(labels)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	labels, autoHeal, err := fromAutoHealLabels(labels)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	labels, exceptions, err := fromScheduleExceptionLabels(labels)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	labels, maxActiveRuns, err := fromMaxActiveRunsLabel(labels)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	labels, replayPresets, err := fromReplayPresetLabels(labels)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	labels, retention, err := fromRetentionLabel(labels)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	labels, sensors, err := fromDependencySensorLabels(labels)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	for name, sensor := range sensors {
+		dep := dependencies[name]
+		dep.Sensor = sensor
+		dependencies[name] = dep
+	}
+	labels, freshnesses, err := fromDependencyFreshnessLabels(labels)
+	if err != nil {
+		return models.JobSpec{}, err
+	}
+	for name, freshness := range freshnesses {
+		dep := dependencies[name]
+		dep.Freshness = freshness
+		dependencies[name] = dep
+	}
+	// specs deployed by older clients are upgraded to the latest version
+	return models.SpecMigrations.MigrateJob(models.JobSpec{
 		Version:     int(spec.Version),
 		Name:        spec.Name,
 		Owner:       spec.Owner,
+		Ownership:   ownership,
 		Description: spec.Description,
-		Labels:      spec.Labels,
+		Labels:      labels,
 		Schedule: models.JobSpecSchedule{
-			Interval:  spec.Interval,
-			StartDate: startDate,
-			EndDate:   endDate,
+			Interval:      spec.Interval,
+			StartDate:     startDate,
+			EndDate:       endDate,
+			Exceptions:    exceptions,
+			MaxActiveRuns: maxActiveRuns,
 		},
 		Assets: models.JobAssets{}.FromMap(spec.Assets),
 		Behavior: models.JobSpecBehavior{
@@ -111,16 +203,444 @@ func (adapt *Adapter) FromJobProto(spec *pb.JobSpecification) (models.JobSpec, e
 				Delay:              retryDelay,
 				ExponentialBackoff: retryExponentialBackoff,
 			},
-			Notify: notifiers,
+			Notify:        notifiers,
+			AutoHeal:      autoHeal,
+			ReplayPresets: replayPresets,
 		},
 		Task: models.JobSpecTask{
-			Unit:   execUnit,
-			Config: taskConfigs,
-			Window: window,
+			Unit:           execUnit,
+			Version:        taskVersion,
+			Config:         taskConfigs,
+			Window:         window,
+			TemplateEngine: templateEngine,
 		},
 		Dependencies: dependencies,
 		Hooks:        hooks,
-	}, nil
+		Lifecycle: models.JobSpecLifecycle{
+			Retention: retention,
+		},
+	})
+}
+
+// toOwnershipLabels returns a copy of labels with non empty ownership fields added
+func toOwnershipLabels(labels map[string]string, ownership models.JobSpecOwnership) map[string]string {
+	if ownership.IsEmpty() {
+		return labels
+	}
+	withOwnership := map[string]string{}
+	for k, v := range labels {
+		withOwnership[k] = v
+	}
+	for key, val := range map[string]string{
+		labelOwnershipEmail:        ownership.Email,
+		labelOwnershipTeam:         ownership.Team,
+		labelOwnershipSlackChannel: ownership.SlackChannel,
+	} {
+		if val != "" {
+			withOwnership[key] = val
+		}
+	}
+	return withOwnership
+}
+
+// fromOwnershipLabels separates ownership fields from rest of the labels
+func fromOwnershipLabels(labels map[string]string) (map[string]string, models.JobSpecOwnership) {
+	ownership := models.JobSpecOwnership{
+		Email:        labels[labelOwnershipEmail],
+		Team:         labels[labelOwnershipTeam],
+		SlackChannel: labels[labelOwnershipSlackChannel],
+	}
+	if ownership.IsEmpty() {
+		return labels, ownership
+	}
+	rest := map[string]string{}
+	for k, v := range labels {
+		switch k {
+		case labelOwnershipEmail, labelOwnershipTeam, labelOwnershipSlackChannel:
+		default:
+			rest[k] = v
+		}
+	}
+	return rest, ownership
+}
+
+// toTaskVersionLabel returns a copy of labels with pinned task version added
+func toTaskVersionLabel(labels map[string]string, version string) map[string]string {
+	if version == "" {
+		return labels
+	}
+	withVersion := map[string]string{}
+	for k, v := range labels {
+		withVersion[k] = v
+	}
+	withVersion[labelTaskVersion] = version
+	return withVersion
+}
+
+// fromTaskVersionLabel separates pinned task version from rest of the labels
+func fromTaskVersionLabel(labels map[string]string) (map[string]string, string) {
+	version, ok := labels[labelTaskVersion]
+	if !ok {
+		return labels, ""
+	}
+	rest := map[string]string{}
+	for k, v := range labels {
+		if k != labelTaskVersion {
+			rest[k] = v
+		}
+	}
+	return rest, version
+}
+
+// toTemplateEngineLabel returns a copy of labels with template engine of assets added if set
+func toTemplateEngineLabel(labels map[string]string, engine string) map[string]string {
+	if engine == "" {
+		return labels
+	}
+	withEngine := map[string]string{}
+	for k, v := range labels {
+		withEngine[k] = v
+	}
+	withEngine[labelTaskTemplateEngine] = engine
+	return withEngine
+}
+
+// fromTemplateEngineLabel separates template engine of assets from rest of the labels
+func fromTemplateEngineLabel(labels map[string]string) (map[string]string, string, error) {
+	engine, ok := labels[labelTaskTemplateEngine]
+	if !ok {
+		return labels, "", nil
+	}
+	if engine != models.TemplateEngineGo && engine != models.TemplateEngineJinja {
+		return nil, "", errors.Errorf("invalid label %s: %s", labelTaskTemplateEngine, engine)
+	}
+	rest := map[string]string{}
+	for k, v := range labels {
+		if k != labelTaskTemplateEngine {
+			rest[k] = v
+		}
+	}
+	return rest, engine, nil
+}
+
+// toAutoHealLabels returns a copy of labels with auto-heal policy added if enabled
+func toAutoHealLabels(labels map[string]string, autoHeal models.JobSpecBehaviorAutoHeal) map[string]string {
+	if !autoHeal.IsEnabled() {
+		return labels
+	}
+	withAutoHeal := map[string]string{}
+	for k, v := range labels {
+		withAutoHeal[k] = v
+	}
+	withAutoHeal[labelAutoHealMaxPerDay] = strconv.Itoa(autoHeal.MaxPerDay)
+	if autoHeal.Window > 0 {
+		withAutoHeal[labelAutoHealWindow] = autoHeal.Window.String()
+	}
+	return withAutoHeal
+}
+
+// fromAutoHealLabels separates auto-heal policy from rest of the labels
+func fromAutoHealLabels(labels map[string]string) (map[string]string, models.JobSpecBehaviorAutoHeal, error) {
+	autoHeal := models.JobSpecBehaviorAutoHeal{}
+	maxPerDay, hasMax := labels[labelAutoHealMaxPerDay]
+	window, hasWindow := labels[labelAutoHealWindow]
+	if !hasMax && !hasWindow {
+		return labels, autoHeal, nil
+	}
+	var err error
+	if hasMax {
+		if autoHeal.MaxPerDay, err = strconv.Atoi(maxPerDay); err != nil {
+			return nil, autoHeal, errors.Wrapf(err, "invalid label %s", labelAutoHealMaxPerDay)
+		}
+	}
+	if hasWindow {
+		if autoHeal.Window, err = time.ParseDuration(window); err != nil {
+			return nil, autoHeal, errors.Wrapf(err, "invalid label %s", labelAutoHealWindow)
+		}
+	}
+	rest := map[string]string{}
+	for k, v := range labels {
+		switch k {
+		case labelAutoHealMaxPerDay, labelAutoHealWindow:
+		default:
+			rest[k] = v
+		}
+	}
+	return rest, autoHeal, nil
+}
+
+// toScheduleExceptionLabels returns a copy of labels with schedule exceptions added
+func toScheduleExceptionLabels(labels map[string]string, exceptions models.JobSpecScheduleExceptions) map[string]string {
+	if exceptions.IsEmpty() {
+		return labels
+	}
+	withExceptions := map[string]string{}
+	for k, v := range labels {
+		withExceptions[k] = v
+	}
+	var skipDates, extraRuns []string
+	for _, date := range exceptions.SkipDates {
+		skipDates = append(skipDates, date.Format(models.JobDatetimeLayout))
+	}
+	for _, run := range exceptions.ExtraRuns {
+		extraRuns = append(extraRuns, run.Format(time.RFC3339))
+	}
+	for key, val := range map[string][]string{
+		labelScheduleSkipDates: skipDates,
+		labelScheduleExtraRuns: extraRuns,
+		labelScheduleCalendars: exceptions.Calendars,
+	} {
+		if len(val) > 0 {
+			withExceptions[key] = strings.Join(val, ",")
+		}
+	}
+	return withExceptions
+}
+
+// fromScheduleExceptionLabels separates schedule exceptions from rest of the labels
+func fromScheduleExceptionLabels(labels map[string]string) (map[string]string, models.JobSpecScheduleExceptions, error) {
+	exceptions := models.JobSpecScheduleExceptions{}
+	rest := map[string]string{}
+	for k, v := range labels {
+		switch k {
+		case labelScheduleSkipDates:
+			for _, raw := range strings.Split(v, ",") {
+				date, err := time.Parse(models.JobDatetimeLayout, strings.TrimSpace(raw))
+				if err != nil {
+					return nil, exceptions, errors.Wrapf(err, "invalid label %s", labelScheduleSkipDates)
+				}
+				exceptions.SkipDates = append(exceptions.SkipDates, date)
+			}
+		case labelScheduleExtraRuns:
+			for _, raw := range strings.Split(v, ",") {
+				run, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+				if err != nil {
+					return nil, exceptions, errors.Wrapf(err, "invalid label %s", labelScheduleExtraRuns)
+				}
+				exceptions.ExtraRuns = append(exceptions.ExtraRuns, run.UTC())
+			}
+		case labelScheduleCalendars:
+			for _, name := range strings.Split(v, ",") {
+				exceptions.Calendars = append(exceptions.Calendars, strings.TrimSpace(name))
+			}
+		default:
+			rest[k] = v
+		}
+	}
+	if exceptions.IsEmpty() {
+		return labels, exceptions, nil
+	}
+	return rest, exceptions, nil
+}
+
+// toMaxActiveRunsLabel returns a copy of labels with cap on active runs added if set
+func toMaxActiveRunsLabel(labels map[string]string, maxActiveRuns int) map[string]string {
+	if maxActiveRuns == 0 {
+		return labels
+	}
+	withMaxActiveRuns := map[string]string{}
+	for k, v := range labels {
+		withMaxActiveRuns[k] = v
+	}
+	withMaxActiveRuns[labelScheduleMaxActiveRuns] = strconv.Itoa(maxActiveRuns)
+	return withMaxActiveRuns
+}
+
+// fromMaxActiveRunsLabel separates cap on active runs from rest of the labels
+func fromMaxActiveRunsLabel(labels map[string]string) (map[string]string, int, error) {
+	value, ok := labels[labelScheduleMaxActiveRuns]
+	if !ok {
+		return labels, 0, nil
+	}
+	maxActiveRuns, err := strconv.Atoi(value)
+	if err != nil || maxActiveRuns < 0 {
+		return nil, 0, errors.Errorf("invalid label %s: %s", labelScheduleMaxActiveRuns, value)
+	}
+	rest := map[string]string{}
+	for k, v := range labels {
+		if k != labelScheduleMaxActiveRuns {
+			rest[k] = v
+		}
+	}
+	return rest, maxActiveRuns, nil
+}
+
+// toRetentionLabel returns a copy of labels with retention of destination
+// added if set, disabled retention is kept so it isn't inherited again
+func toRetentionLabel(labels map[string]string, retention models.JobSpecRetention) map[string]string {
+	if retention.PartitionDays == 0 {
+		return labels
+	}
+	withRetention := map[string]string{}
+	for k, v := range labels {
+		withRetention[k] = v
+	}
+	withRetention[labelRetentionPartitionDays] = strconv.Itoa(retention.PartitionDays)
+	return withRetention
+}
+
+// fromRetentionLabel separates retention of destination from rest of the labels
+func fromRetentionLabel(labels map[string]string) (map[string]string, models.JobSpecRetention, error) {
+	value, ok := labels[labelRetentionPartitionDays]
+	if !ok {
+		return labels, models.JobSpecRetention{}, nil
+	}
+	partitionDays, err := strconv.Atoi(value)
+	if err != nil || partitionDays < models.RetentionDisabled {
+		return nil, models.JobSpecRetention{}, errors.Errorf("invalid label %s: %s", labelRetentionPartitionDays, value)
+	}
+	rest := map[string]string{}
+	for k, v := range labels {
+		if k != labelRetentionPartitionDays {
+			rest[k] = v
+		}
+	}
+	return rest, models.JobSpecRetention{PartitionDays: partitionDays}, nil
+}
+
+// toReplayPresetLabels returns a copy of labels with replay presets added
+func toReplayPresetLabels(labels map[string]string, presets map[string]models.JobSpecReplayPreset) (map[string]string, error) {
+	if len(presets) == 0 {
+		return labels, nil
+	}
+	withPresets := map[string]string{}
+	for k, v := range labels {
+		withPresets[k] = v
+	}
+	for name, preset := range presets {
+		encoded, err := json.Marshal(preset)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode replay preset %s", name)
+		}
+		withPresets[labelReplayPresetPrefix+name] = string(encoded)
+	}
+	return withPresets, nil
+}
+
+// fromReplayPresetLabels separates replay presets from rest of the labels
+func fromReplayPresetLabels(labels map[string]string) (map[string]string, map[string]models.JobSpecReplayPreset, error) {
+	var presets map[string]models.JobSpecReplayPreset
+	rest := map[string]string{}
+	for k, v := range labels {
+		if !strings.HasPrefix(k, labelReplayPresetPrefix) {
+			rest[k] = v
+			continue
+		}
+		var preset models.JobSpecReplayPreset
+		if err := json.Unmarshal([]byte(v), &preset); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid label %s", k)
+		}
+		if presets == nil {
+			presets = map[string]models.JobSpecReplayPreset{}
+		}
+		presets[strings.TrimPrefix(k, labelReplayPresetPrefix)] = preset
+	}
+	if presets == nil {
+		return labels, nil, nil
+	}
+	return rest, presets, nil
+}
+
+// toDependencySensorLabels returns a copy of labels with configured sensors
+// of dependencies added
+func toDependencySensorLabels(labels map[string]string, dependencies map[string]models.JobSpecDependency) (map[string]string, error) {
+	var withSensors map[string]string
+	for name, dep := range dependencies {
+		if dep.Sensor == (models.JobSpecDependencySensor{}) {
+			continue
+		}
+		if withSensors == nil {
+			withSensors = map[string]string{}
+			for k, v := range labels {
+				withSensors[k] = v
+			}
+		}
+		encoded, err := json.Marshal(dep.Sensor)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode sensor of dependency %s", name)
+		}
+		withSensors[labelDependencySensorPrefix+name] = string(encoded)
+	}
+	if withSensors == nil {
+		return labels, nil
+	}
+	return withSensors, nil
+}
+
+// fromDependencySensorLabels separates sensors of dependencies from rest of
+// the labels
+func fromDependencySensorLabels(labels map[string]string) (map[string]string, map[string]models.JobSpecDependencySensor, error) {
+	var sensors map[string]models.JobSpecDependencySensor
+	rest := map[string]string{}
+	for k, v := range labels {
+		if !strings.HasPrefix(k, labelDependencySensorPrefix) {
+			rest[k] = v
+			continue
+		}
+		var sensor models.JobSpecDependencySensor
+		if err := json.Unmarshal([]byte(v), &sensor); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid label %s", k)
+		}
+		if sensors == nil {
+			sensors = map[string]models.JobSpecDependencySensor{}
+		}
+		sensors[strings.TrimPrefix(k, labelDependencySensorPrefix)] = sensor
+	}
+	if sensors == nil {
+		return labels, nil, nil
+	}
+	return rest, sensors, nil
+}
+
+// toDependencyFreshnessLabels returns a copy of labels with enabled
+// freshness assertions of dependencies added
+func toDependencyFreshnessLabels(labels map[string]string, dependencies map[string]models.JobSpecDependency) (map[string]string, error) {
+	var withFreshness map[string]string
+	for name, dep := range dependencies {
+		if !dep.Freshness.Enabled {
+			continue
+		}
+		if withFreshness == nil {
+			withFreshness = map[string]string{}
+			for k, v := range labels {
+				withFreshness[k] = v
+			}
+		}
+		encoded, err := json.Marshal(dep.Freshness)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode freshness of dependency %s", name)
+		}
+		withFreshness[labelDependencyFreshnessPrefix+name] = string(encoded)
+	}
+	if withFreshness == nil {
+		return labels, nil
+	}
+	return withFreshness, nil
+}
+
+// fromDependencyFreshnessLabels separates freshness assertions of
+// dependencies from rest of the labels
+func fromDependencyFreshnessLabels(labels map[string]string) (map[string]string, map[string]models.JobSpecDependencyFreshness, error) {
+	var freshnesses map[string]models.JobSpecDependencyFreshness
+	rest := map[string]string{}
+	for k, v := range labels {
+		if !strings.HasPrefix(k, labelDependencyFreshnessPrefix) {
+			rest[k] = v
+			continue
+		}
+		var freshness models.JobSpecDependencyFreshness
+		if err := json.Unmarshal([]byte(v), &freshness); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid label %s", k)
+		}
+		if freshnesses == nil {
+			freshnesses = map[string]models.JobSpecDependencyFreshness{}
+		}
+		freshnesses[strings.TrimPrefix(k, labelDependencyFreshnessPrefix)] = freshness
+	}
+	if freshnesses == nil {
+		return labels, nil, nil
+	}
+	return rest, freshnesses, nil
 }
 
 func prepareWindow(windowSize, windowOffset, truncateTo string) (models.JobSpecTaskWindow, error) {
@@ -163,6 +683,25 @@ func (adapt *Adapter) ToJobProto(spec models.JobSpec) (*pb.JobSpecification, err
 		})
 	}
 
+	labels := toOwnershipLabels(spec.Labels, spec.Ownership)
+	labels = toTaskVersionLabel(labels, spec.Task.Version)
+	labels = toTemplateEngineLabel(labels, spec.Task.TemplateEngine)
+	labels = toAutoHealLabels(labels, spec.Behavior.AutoHeal)
+	labels = toScheduleExceptionLabels(labels, spec.Schedule.Exceptions)
+	labels = toMaxActiveRunsLabel(labels, spec.Schedule.MaxActiveRuns)
+	labels = toRetentionLabel(labels, spec.Lifecycle.Retention)
+	labels, err = toReplayPresetLabels(labels, spec.Behavior.ReplayPresets)
+	if err != nil {
+		return nil, err
+	}
+	labels, err = toDependencySensorLabels(labels, spec.Dependencies)
+	if err != nil {
+		return nil, err
+	}
+	labels, err = toDependencyFreshnessLabels(labels, spec.Dependencies)
+	if err != nil {
+		return nil, err
+	}
 	conf := &pb.JobSpecification{
 		Version:          int32(spec.Version),
 		Name:             spec.Name,
@@ -179,7 +718,7 @@ func (adapt *Adapter) ToJobProto(spec models.JobSpec) (*pb.JobSpecification, err
 		Dependencies:     []*pb.JobDependency{},
 		Hooks:            adaptedHook,
 		Description:      spec.Description,
-		Labels:           spec.Labels,
+		Labels:           labels,
 		Behavior: &pb.JobSpecification_Behavior{
 			Retry: &pb.JobSpecification_Behavior_Retry{
 				Count:              int32(spec.Behavior.Retry.Count),
@@ -427,7 +966,11 @@ func (adapt *Adapter) FromResourceProto(spec *pb.ResourceSpecification, storeNam
 	if err != nil {
 		return models.ResourceSpec{}, err
 	}
-	return typeController.Adapter().FromProtobuf(buf)
+	resourceSpec, err := typeController.Adapter().FromProtobuf(buf)
+	if err != nil {
+		return models.ResourceSpec{}, err
+	}
+	return models.SpecMigrations.MigrateResource(resourceSpec)
 }
 
 func (adapt *Adapter) ToReplayExecutionTreeNode(res *tree.TreeNode) (*pb.ReplayExecutionTreeNode, error) {
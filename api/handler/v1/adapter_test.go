@@ -108,6 +108,259 @@ func TestAdapter(t *testing.T) {
 		assert.Equal(t, jobSpec, original)
 		assert.Nil(t, err)
 	})
+	t.Run("should carry pinned task version to and from proto", func(t *testing.T) {
+		execUnit1 := new(mock.BasePlugin)
+		execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "sample-task",
+		}, nil)
+		defer execUnit1.AssertExpectations(t)
+
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "sample-task").Return(&models.Plugin{
+			Base: execUnit1,
+		}, nil)
+		adapter := v1.NewAdapter(pluginRepo, nil)
+
+		jobSpec := models.JobSpec{
+			Name: "test-job",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 10, 6, 0, 0, 0, 0, time.UTC),
+				Interval:  "@daily",
+			},
+			Labels: map[string]string{
+				"orchestrator": "optimus",
+			},
+			Task: models.JobSpecTask{
+				Unit:    &models.Plugin{Base: execUnit1},
+				Version: "1.2.0",
+				Config:  models.JobSpecConfigs{},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+			Assets:       *models.JobAssets{}.New(nil),
+			Dependencies: map[string]models.JobSpecDependency{},
+		}
+
+		inProto, err := adapter.ToJobProto(jobSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, "1.2.0", inProto.Labels["task.version"])
+		original, err := adapter.FromJobProto(inProto)
+		assert.Nil(t, err)
+		assert.Equal(t, "1.2.0", original.Task.Version)
+		assert.Equal(t, jobSpec.Labels, original.Labels)
+	})
+	t.Run("should carry auto-heal policy to and from proto", func(t *testing.T) {
+		execUnit1 := new(mock.BasePlugin)
+		execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "sample-task",
+		}, nil)
+		defer execUnit1.AssertExpectations(t)
+
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "sample-task").Return(&models.Plugin{
+			Base: execUnit1,
+		}, nil)
+		adapter := v1.NewAdapter(pluginRepo, nil)
+
+		jobSpec := models.JobSpec{
+			Name: "test-job",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 10, 6, 0, 0, 0, 0, time.UTC),
+				Interval:  "@daily",
+			},
+			Behavior: models.JobSpecBehavior{
+				AutoHeal: models.JobSpecBehaviorAutoHeal{
+					Window:    time.Hour * 48,
+					MaxPerDay: 2,
+				},
+			},
+			Labels: map[string]string{
+				"orchestrator": "optimus",
+			},
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{Base: execUnit1},
+				Config: models.JobSpecConfigs{},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+			Assets:       *models.JobAssets{}.New(nil),
+			Dependencies: map[string]models.JobSpecDependency{},
+		}
+
+		inProto, err := adapter.ToJobProto(jobSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, "48h0m0s", inProto.Labels["auto_heal.window"])
+		assert.Equal(t, "2", inProto.Labels["auto_heal.max_per_day"])
+		original, err := adapter.FromJobProto(inProto)
+		assert.Nil(t, err)
+		assert.Equal(t, jobSpec.Behavior.AutoHeal, original.Behavior.AutoHeal)
+		assert.Equal(t, jobSpec.Labels, original.Labels)
+	})
+	t.Run("should carry retention of destination to and from proto", func(t *testing.T) {
+		execUnit1 := new(mock.BasePlugin)
+		execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "sample-task",
+		}, nil)
+		defer execUnit1.AssertExpectations(t)
+
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "sample-task").Return(&models.Plugin{
+			Base: execUnit1,
+		}, nil)
+		adapter := v1.NewAdapter(pluginRepo, nil)
+
+		jobSpec := models.JobSpec{
+			Name: "test-job",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 10, 6, 0, 0, 0, 0, time.UTC),
+				Interval:  "@daily",
+			},
+			Labels: map[string]string{
+				"orchestrator": "optimus",
+			},
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{Base: execUnit1},
+				Config: models.JobSpecConfigs{},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+			Assets:       *models.JobAssets{}.New(nil),
+			Dependencies: map[string]models.JobSpecDependency{},
+			Lifecycle: models.JobSpecLifecycle{
+				Retention: models.JobSpecRetention{PartitionDays: 400},
+			},
+		}
+
+		inProto, err := adapter.ToJobProto(jobSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, "400", inProto.Labels["lifecycle.retention.partition_days"])
+		original, err := adapter.FromJobProto(inProto)
+		assert.Nil(t, err)
+		assert.Equal(t, jobSpec.Lifecycle, original.Lifecycle)
+		assert.Equal(t, jobSpec.Labels, original.Labels)
+
+		// disabled retention is carried so it isn't inherited again
+		jobSpec.Lifecycle.Retention.PartitionDays = models.RetentionDisabled
+		inProto, err = adapter.ToJobProto(jobSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, "-1", inProto.Labels["lifecycle.retention.partition_days"])
+		original, err = adapter.FromJobProto(inProto)
+		assert.Nil(t, err)
+		assert.Equal(t, jobSpec.Lifecycle, original.Lifecycle)
+		assert.False(t, original.Lifecycle.Retention.IsEnabled())
+	})
+	t.Run("should carry replay presets to and from proto", func(t *testing.T) {
+		execUnit1 := new(mock.BasePlugin)
+		execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "sample-task",
+		}, nil)
+		defer execUnit1.AssertExpectations(t)
+
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "sample-task").Return(&models.Plugin{
+			Base: execUnit1,
+		}, nil)
+		adapter := v1.NewAdapter(pluginRepo, nil)
+
+		jobSpec := models.JobSpec{
+			Name: "test-job",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 10, 6, 0, 0, 0, 0, time.UTC),
+				Interval:  "@daily",
+			},
+			Behavior: models.JobSpecBehavior{
+				ReplayPresets: map[string]models.JobSpecReplayPreset{
+					"last_week":    {Start: "last:7", Selector: "tier=critical"},
+					"full_history": {Force: true, ChunkDays: 30},
+				},
+			},
+			Labels: map[string]string{
+				"orchestrator": "optimus",
+			},
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{Base: execUnit1},
+				Config: models.JobSpecConfigs{},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+			Assets:       *models.JobAssets{}.New(nil),
+			Dependencies: map[string]models.JobSpecDependency{},
+		}
+
+		inProto, err := adapter.ToJobProto(jobSpec)
+		assert.Nil(t, err)
+		assert.Contains(t, inProto.Labels, "replay_preset.last_week")
+		original, err := adapter.FromJobProto(inProto)
+		assert.Nil(t, err)
+		assert.Equal(t, jobSpec.Behavior.ReplayPresets, original.Behavior.ReplayPresets)
+		assert.Equal(t, jobSpec.Labels, original.Labels)
+	})
+	t.Run("should carry sensors and freshness of dependencies to and from proto", func(t *testing.T) {
+		execUnit1 := new(mock.BasePlugin)
+		execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "sample-task",
+		}, nil)
+		defer execUnit1.AssertExpectations(t)
+
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "sample-task").Return(&models.Plugin{
+			Base: execUnit1,
+		}, nil)
+		adapter := v1.NewAdapter(pluginRepo, nil)
+
+		jobSpec := models.JobSpec{
+			Name: "test-job",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 10, 6, 0, 0, 0, 0, time.UTC),
+				Interval:  "@daily",
+			},
+			Labels: map[string]string{
+				"orchestrator": "optimus",
+			},
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{Base: execUnit1},
+				Config: models.JobSpecConfigs{},
+				Window: models.JobSpecTaskWindow{
+					Size:       time.Hour * 24,
+					TruncateTo: "d",
+				},
+			},
+			Assets: *models.JobAssets{}.New(nil),
+			Dependencies: map[string]models.JobSpecDependency{
+				"upstream-job": {
+					Type: models.JobSpecDependencyTypeIntra,
+					Sensor: models.JobSpecDependencySensor{
+						Timeout:      6 * time.Hour,
+						PokeInterval: 10 * time.Minute,
+					},
+					Freshness: models.JobSpecDependencyFreshness{
+						Enabled:   true,
+						Tolerance: time.Hour,
+					},
+				},
+				"other-job": {Type: models.JobSpecDependencyTypeIntra},
+			},
+		}
+
+		inProto, err := adapter.ToJobProto(jobSpec)
+		assert.Nil(t, err)
+		assert.Contains(t, inProto.Labels, "dependency_sensor.upstream-job")
+		assert.NotContains(t, inProto.Labels, "dependency_sensor.other-job")
+		assert.Contains(t, inProto.Labels, "dependency_freshness.upstream-job")
+		assert.NotContains(t, inProto.Labels, "dependency_freshness.other-job")
+		original, err := adapter.FromJobProto(inProto)
+		assert.Nil(t, err)
+		assert.Equal(t, jobSpec.Dependencies, original.Dependencies)
+		assert.Equal(t, jobSpec.Labels, original.Labels)
+	})
 }
 
 func TestAdapter_FromProjectProtoWithSecrets(t *testing.T) {
@@ -0,0 +1,523 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// MetadataAuthorization carries an api token as "Bearer <token>", http
+	// clients send it as Authorization header
+	MetadataAuthorization = "authorization"
+
+	// APITokenActorPrefix prefixes name of the token recorded as actor of
+	// calls made with it
+	APITokenActorPrefix = "token/"
+
+	apiTokenScheme      = "Bearer "
+	apiTokenSecretBytes = 32
+	apiTokenPrefix      = "opt_"
+
+	// internalPrincipal is the principal of calls server makes to itself
+	// without naming the actor they are made on behalf of
+	internalPrincipal = "optimus"
+)
+
+type principalKey struct{}
+
+// tokenRPCActions are the actions a token needs on project of the request
+// for methods of runtime service, an empty action only needs a valid token.
+// Tokens can't call methods missing here
+var tokenRPCActions = map[string]string{
+	"Version":                     "",
+	"ListProjects":                models.TokenActionRead,
+	"ListProjectNamespaces":       models.TokenActionRead,
+	"ReadJobSpecification":        models.TokenActionRead,
+	"ListJobSpecification":        models.TokenActionRead,
+	"DumpJobSpecification":        models.TokenActionRead,
+	"CheckJobSpecification":       models.TokenActionRead,
+	"CheckJobSpecifications":      models.TokenActionRead,
+	"JobStatus":                   models.TokenActionRead,
+	"GetWindow":                   models.TokenActionRead,
+	"ListResourceSpecification":   models.TokenActionRead,
+	"ReadResource":                models.TokenActionRead,
+	"ReplayDryRun":                models.TokenActionRead,
+	"RegisterProject":             models.TokenActionDeploy,
+	"RegisterProjectNamespace":    models.TokenActionDeploy,
+	"RegisterSecret":              models.TokenActionDeploy,
+	"CreateJobSpecification":      models.TokenActionDeploy,
+	"DeleteJobSpecification":      models.TokenActionDeploy,
+	"DeployJobSpecification":      models.TokenActionDeploy,
+	"CreateResource":              models.TokenActionDeploy,
+	"UpdateResource":              models.TokenActionDeploy,
+	"DeployResourceSpecification": models.TokenActionDeploy,
+	"RegisterInstance":            models.TokenActionDeploy,
+	"RegisterJobEvent":            models.TokenActionDeploy,
+	"Replay":                      models.TokenActionReplay,
+}
+
+// tokenPublicPaths are served over http without a token even if one is
+// required, they are probed by infrastructure or guard themselves
+var tokenPublicPaths = []string{"/ping", "/livez", "/readyz", "/metrics", "/ui/", "/schema/", "/webhook/"}
+
+// TokenAuthenticator validates api tokens sent with calls and lets them act
+// only within their scopes. Calls without a token are let through unless
+// tokens are required
+type TokenAuthenticator struct {
+	repo         store.APITokenRepository
+	requireToken bool
+	Now          func() time.Time
+
+	// internalSecret is sent by calls server makes to itself, e.g. git
+	// sync deployments, which are let through like calls without a token.
+	// It is generated on start and never leaves the process
+	internalSecret string
+}
+
+// InternalDialOptions are the options of connections server makes to its
+// own grpc api, calls made on them are let through even if tokens are
+// required
+func (a *TokenAuthenticator) InternalDialOptions() []grpc.DialOption {
+	header := apiTokenScheme + a.internalSecret
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{},
+			cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(metadata.AppendToOutgoingContext(ctx, MetadataAuthorization, header), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+			method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(metadata.AppendToOutgoingContext(ctx, MetadataAuthorization, header), desc, cc, method, opts...)
+		}),
+	}
+}
+
+// UnaryServerInterceptor rejects unary calls with a token not allowed to
+// make them, calls made with a token have its name as principal
+func (a *TokenAuthenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		token, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			if err := a.authorize(*token, info.FullMethod, req); err != nil {
+				return nil, err
+			}
+		}
+		return handler(a.withPrincipal(ctx, token), req)
+	}
+}
+
+// StreamServerInterceptor rejects streaming calls with a token not allowed
+// to make them once the request is received from client
+func (a *TokenAuthenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		token, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &tokenServerStream{
+			ServerStream:  ss,
+			ctx:           a.withPrincipal(ss.Context(), token),
+			authenticator: a,
+			token:         token,
+			method:        info.FullMethod,
+		})
+	}
+}
+
+// authenticate returns the token sent with the call, nil if none is sent
+func (a *TokenAuthenticator) authenticate(ctx context.Context) (*models.APIToken, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(MetadataAuthorization); len(vals) > 0 {
+			header = vals[0]
+		}
+	}
+	token, err := a.lookup(header)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return token, nil
+}
+
+func (a *TokenAuthenticator) lookup(header string) (*models.APIToken, error) {
+	if header == "" {
+		if a.requireToken {
+			return nil, errors.New("api token is required")
+		}
+		return nil, nil
+	}
+	if !strings.HasPrefix(header, apiTokenScheme) {
+		return nil, errors.New("api token should be sent as Bearer <token>")
+	}
+	if a.isInternal(header) {
+		return nil, nil
+	}
+	secret := strings.TrimPrefix(header, apiTokenScheme)
+	token, err := a.repo.GetByHash(hashAPIToken(secret))
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, errors.New("invalid api token")
+		}
+		return nil, errors.Wrap(err, "failed to validate api token")
+	}
+	if !token.IsActive(a.Now()) {
+		return nil, errors.Errorf("api token %s is expired or revoked", token.Name)
+	}
+	return &token, nil
+}
+
+// isInternal checks if authorization header is sent by a call server makes
+// to itself
+func (a *TokenAuthenticator) isInternal(header string) bool {
+	secret := strings.TrimPrefix(header, apiTokenScheme)
+	return strings.HasPrefix(header, apiTokenScheme) &&
+		subtle.ConstantTimeCompare([]byte(secret), []byte(a.internalSecret)) == 1
+}
+
+// authorize checks if token is allowed to call method with req
+func (a *TokenAuthenticator) authorize(token models.APIToken, method string, req interface{}) error {
+	action, ok := tokenRPCActions[path.Base(method)]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "api token %s can't call %s", token.Name, path.Base(method))
+	}
+	if action == "" {
+		return nil
+	}
+	if projectName := auditProjectName(req); !token.Allows(action, projectName) {
+		return status.Error(codes.PermissionDenied, tokenDeniedMessage(token, action, projectName))
+	}
+	return nil
+}
+
+func tokenDeniedMessage(token models.APIToken, action, projectName string) string {
+	if projectName == "" {
+		return fmt.Sprintf("api token %s is not allowed to %s all projects", token.Name, action)
+	}
+	return fmt.Sprintf("api token %s is not allowed to %s project %s", token.Name, action, projectName)
+}
+
+// withPrincipal records who the call is authenticated as, name of its token
+// or for calls server makes to itself the actor they are made on behalf of.
+// Calls without a token have no principal
+func (a *TokenAuthenticator) withPrincipal(ctx context.Context, token *models.APIToken) context.Context {
+	if token != nil {
+		return context.WithValue(ctx, principalKey{}, APITokenActorPrefix+token.Name)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vals := md.Get(MetadataAuthorization); len(vals) == 0 || !a.isInternal(vals[0]) {
+		return ctx
+	}
+	principal := internalPrincipal
+	if vals := md.Get(MetadataActor); len(vals) > 0 && vals[0] != "" {
+		principal = vals[0]
+	}
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// Principal is who a call is authenticated as, false for calls made
+// without a token
+func Principal(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey{}).(string)
+	return principal, ok
+}
+
+// tokenServerStream authorizes the first message received on a stream
+type tokenServerStream struct {
+	grpc.ServerStream
+	ctx           context.Context
+	authenticator *TokenAuthenticator
+	// token is nil for calls made without one
+	token   *models.APIToken
+	method  string
+	checked bool
+}
+
+func (s *tokenServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *tokenServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.checked && s.token != nil {
+		s.checked = true
+		return s.authenticator.authorize(*s.token, s.method, m)
+	}
+	return nil
+}
+
+// HTTPHandler validates tokens sent to http endpoints other than the api
+// gateway, whose calls are checked by the grpc interceptors. Reads need read
+// of project query param, admin endpoints admin and the rest deploy. Calls
+// made with a token are authenticated as the token
+func (a *TokenAuthenticator) HTTPHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		for _, public := range tokenPublicPaths {
+			if r.URL.Path == public || (strings.HasSuffix(public, "/") && strings.HasPrefix(r.URL.Path, public)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		token, err := a.lookup(r.Header.Get(MetadataAuthorization))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if token == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		action := models.TokenActionDeploy
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/admin/"):
+			action = models.TokenActionAdmin
+		case r.Method == http.MethodGet || r.Method == http.MethodHead:
+			action = models.TokenActionRead
+		}
+		if projectName := r.URL.Query().Get("project"); !token.Allows(action, projectName) {
+			http.Error(w, tokenDeniedMessage(*token, action, projectName), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(a.withPrincipal(r.Context(), token)))
+	})
+}
+
+// AdminHandler guards admin endpoints, e.g. the ones managing tokens, freezes
+// and quotas, they need a token allowed to admin all projects. Until tokens are required
+// or such a token exists they are open, so the first admin token can be
+// created
+func (a *TokenAuthenticator) AdminHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := a.lookup(r.Header.Get(MetadataAuthorization))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if token == nil {
+			open, err := a.adminOpen()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !open {
+				http.Error(w, "admin api token is required", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !token.Allows(models.TokenActionAdmin, "") {
+			http.Error(w, tokenDeniedMessage(*token, models.TokenActionAdmin, ""), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(a.withPrincipal(r.Context(), token)))
+	})
+}
+
+// adminOpen checks if admin endpoints can be called without a token, which
+// is until tokens are required or an active token can admin all projects
+func (a *TokenAuthenticator) adminOpen() (bool, error) {
+	if a.requireToken {
+		return false, nil
+	}
+	tokens, err := a.repo.GetAll()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to read api tokens")
+	}
+	for _, token := range tokens {
+		if token.IsActive(a.Now()) && token.Allows(models.TokenActionAdmin, "") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// APITokenRequest creates an api token
+type APITokenRequest struct {
+	Name string `json:"name"`
+	// Scopes are written as action:project, e.g. deploy:my-project
+	Scopes []string `json:"scopes"`
+	// Actor creating the token is taken from the admin token the request is
+	// made with, it is only kept for the first admin token
+	Actor     string     `json:"actor,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APITokenResponse is an api token served over http, its secret is only
+// served once when the token is created
+type APITokenResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Token     string     `json:"token,omitempty"`
+	Scopes    []string   `json:"scopes"`
+	Actor     string     `json:"actor,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ServeHTTP lets admins manage api tokens, GET lists them latest first,
+// POST creates one with request body and DELETE revokes the one in id
+// query param
+func (a *TokenAuthenticator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var resp interface{}
+	switch r.Method {
+	case http.MethodGet:
+		tokens, err := a.repo.GetAll()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		list := []APITokenResponse{}
+		for _, token := range tokens {
+			list = append(list, apiTokenResponse(token))
+		}
+		resp = list
+	case http.MethodPost:
+		var req APITokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, errors.Wrap(err, "invalid api token").Error(), http.StatusBadRequest)
+			return
+		}
+		if principal, ok := Principal(r.Context()); ok {
+			req.Actor = principal
+		}
+		token, secret, err := a.newToken(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := a.repo.Insert(&token); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		created := apiTokenResponse(token)
+		created.Token = secret
+		resp = created
+	case http.MethodDelete:
+		id, err := uuid.Parse(r.URL.Query().Get("id"))
+		if err != nil {
+			http.Error(w, "invalid api token id: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := a.repo.Revoke(id, a.Now()); err != nil {
+			if errors.Is(err, store.ErrResourceNotFound) {
+				http.Error(w, "api token "+id.String()+" not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newToken builds a token from request with a random secret, returning the
+// secret which is not kept anywhere
+func (a *TokenAuthenticator) newToken(req APITokenRequest) (models.APIToken, string, error) {
+	if req.Name == "" {
+		return models.APIToken{}, "", errors.New("name is required")
+	}
+	if len(req.Scopes) == 0 {
+		return models.APIToken{}, "", errors.New("at least a scope is required")
+	}
+	token := models.APIToken{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Actor:     req.Actor,
+		CreatedAt: a.Now(),
+	}
+	for _, value := range req.Scopes {
+		scope, err := models.ParseTokenScope(value)
+		if err != nil {
+			return models.APIToken{}, "", err
+		}
+		token.Scopes = append(token.Scopes, scope)
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(a.Now()) {
+			return models.APIToken{}, "", errors.New("expires_at should be in future")
+		}
+		token.ExpiresAt = *req.ExpiresAt
+	}
+	raw := make([]byte, apiTokenSecretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return models.APIToken{}, "", err
+	}
+	secret := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	token.Hash = hashAPIToken(secret)
+	return token, secret, nil
+}
+
+func apiTokenResponse(token models.APIToken) APITokenResponse {
+	resp := APITokenResponse{
+		ID:        token.ID.String(),
+		Name:      token.Name,
+		Scopes:    []string{},
+		Actor:     token.Actor,
+		CreatedAt: token.CreatedAt,
+	}
+	for _, scope := range token.Scopes {
+		resp.Scopes = append(resp.Scopes, scope.String())
+	}
+	if !token.ExpiresAt.IsZero() {
+		expiresAt := token.ExpiresAt
+		resp.ExpiresAt = &expiresAt
+	}
+	if !token.RevokedAt.IsZero() {
+		revokedAt := token.RevokedAt
+		resp.RevokedAt = &revokedAt
+	}
+	return resp
+}
+
+// hashAPIToken is the sha256 of secret of a token, tokens are looked up by it
+func hashAPIToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewTokenAuthenticator(repo store.APITokenRepository, requireToken bool) *TokenAuthenticator {
+	return &TokenAuthenticator{
+		repo:         repo,
+		requireToken: requireToken,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+		internalSecret: uuid.New().String(),
+	}
+}
@@ -0,0 +1,313 @@
+package v1_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTokenAuthenticator(t *testing.T) {
+	now := time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)
+	ciToken := models.APIToken{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "ci",
+		Scopes: []models.TokenScope{
+			{Action: models.TokenActionDeploy, Project: "a-data-project"},
+			{Action: models.TokenActionRead, Project: models.TokenScopeAllProjects},
+		},
+	}
+	tokenHash := func(secret string) string {
+		sum := sha256.Sum256([]byte(secret))
+		return hex.EncodeToString(sum[:])
+	}
+	withToken := func(secret string) context.Context {
+		return metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(v1.MetadataAuthorization, "Bearer "+secret))
+	}
+	replayInfo := &grpc.UnaryServerInfo{
+		FullMethod: "/odpf.optimus.RuntimeService/Replay",
+	}
+	createJobInfo := &grpc.UnaryServerInfo{
+		FullMethod: "/odpf.optimus.RuntimeService/CreateJobSpecification",
+	}
+
+	t.Run("UnaryServerInterceptor", func(t *testing.T) {
+		t.Run("should allow calls within scopes of the token and authenticate them as the token", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetByHash", tokenHash("opt_secret")).Return(ciToken, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+
+			var principal string
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				v1.MetadataAuthorization, "Bearer opt_secret",
+				v1.MetadataActor, "alice",
+			))
+			resp, err := authenticator.UnaryServerInterceptor()(ctx,
+				&pb.CreateJobSpecificationRequest{ProjectName: "a-data-project"}, createJobInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					principal, _ = v1.Principal(ctx)
+					return &pb.CreateJobSpecificationResponse{Success: true}, nil
+				})
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.CreateJobSpecificationResponse{Success: true}, resp)
+			assert.Equal(t, "token/ci", principal)
+		})
+		t.Run("should deny calls outside scopes of the token", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetByHash", tokenHash("opt_secret")).Return(ciToken, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+
+			_, err := authenticator.UnaryServerInterceptor()(withToken("opt_secret"),
+				&pb.ReplayRequest{ProjectName: "a-data-project"}, replayInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					t.Fatal("handler should not be called")
+					return nil, nil
+				})
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+			assert.Equal(t, "api token ci is not allowed to replay project a-data-project", status.Convert(err).Message())
+		})
+		t.Run("should reject unknown and revoked tokens", func(t *testing.T) {
+			revoked := ciToken
+			revoked.RevokedAt = now.Add(-time.Hour)
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetByHash", tokenHash("opt_unknown")).Return(models.APIToken{}, store.ErrResourceNotFound)
+			tokenRepo.On("GetByHash", tokenHash("opt_revoked")).Return(revoked, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+
+			for _, secret := range []string{"opt_unknown", "opt_revoked"} {
+				_, err := authenticator.UnaryServerInterceptor()(withToken(secret),
+					&pb.ReplayRequest{ProjectName: "a-data-project"}, replayInfo,
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						t.Fatal("handler should not be called")
+						return nil, nil
+					})
+				assert.Equal(t, codes.Unauthenticated, status.Code(err), secret)
+			}
+		})
+		t.Run("should let calls without a token through unless tokens are required", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			defer tokenRepo.AssertExpectations(t)
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return &pb.ReplayResponse{Id: "replay-id"}, nil
+			}
+			req := &pb.ReplayRequest{ProjectName: "a-data-project"}
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(v1.MetadataActor, "alice"))
+			_, err := v1.NewTokenAuthenticator(tokenRepo, false).UnaryServerInterceptor()(ctx, req, replayInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					_, ok := v1.Principal(ctx)
+					assert.False(t, ok)
+					return handler(ctx, req)
+				})
+			assert.Nil(t, err)
+
+			_, err = v1.NewTokenAuthenticator(tokenRepo, true).UnaryServerInterceptor()(context.Background(), req, replayInfo, handler)
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	})
+	t.Run("HTTPHandler", func(t *testing.T) {
+		tokenRepo := new(mock.APITokenRepository)
+		tokenRepo.On("GetByHash", tokenHash("opt_secret")).Return(ciToken, nil)
+		defer tokenRepo.AssertExpectations(t)
+
+		authenticator := v1.NewTokenAuthenticator(tokenRepo, true)
+		authenticator.Now = func() time.Time { return now }
+		handler := authenticator.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		cases := []struct {
+			method string
+			target string
+			token  string
+			code   int
+		}{
+			{http.MethodGet, "/ping", "", http.StatusOK},
+			{http.MethodGet, "/job-stats?project=b-data-project", "", http.StatusUnauthorized},
+			{http.MethodGet, "/job-stats?project=b-data-project", "opt_secret", http.StatusOK},
+			{http.MethodPost, "/job-pause?project=a-data-project", "opt_secret", http.StatusOK},
+			{http.MethodPost, "/job-pause?project=b-data-project", "opt_secret", http.StatusForbidden},
+			{http.MethodGet, "/admin/tokens", "opt_secret", http.StatusForbidden},
+		}
+		for _, c := range cases {
+			req := httptest.NewRequest(c.method, c.target, nil)
+			if c.token != "" {
+				req.Header.Set("Authorization", "Bearer "+c.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, c.code, rec.Code, c.method+" "+c.target)
+		}
+	})
+	t.Run("AdminHandler", func(t *testing.T) {
+		adminToken := models.APIToken{
+			ID:     uuid.Must(uuid.NewRandom()),
+			Name:   "admin",
+			Scopes: []models.TokenScope{{Action: models.TokenActionAdmin, Project: models.TokenScopeAllProjects}},
+		}
+		var principal string
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, _ = v1.Principal(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+		call := func(handler http.Handler, secret string) int {
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit", nil)
+			req.Header.Set(v1.MetadataActor, "alice")
+			if secret != "" {
+				req.Header.Set("Authorization", "Bearer "+secret)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec.Code
+		}
+
+		t.Run("should need a token allowed to admin all projects", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetByHash", tokenHash("opt_secret")).Return(ciToken, nil)
+			tokenRepo.On("GetByHash", tokenHash("opt_admin")).Return(adminToken, nil)
+			tokenRepo.On("GetAll").Return([]models.APIToken{ciToken, adminToken}, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+			handler := authenticator.AdminHandler(next)
+
+			assert.Equal(t, http.StatusUnauthorized, call(handler, ""))
+			assert.Equal(t, http.StatusForbidden, call(handler, "opt_secret"))
+			assert.Equal(t, http.StatusOK, call(handler, "opt_admin"))
+			assert.Equal(t, "token/admin", principal)
+		})
+		t.Run("should be open until an admin token exists", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetAll").Return([]models.APIToken{ciToken}, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+			assert.Equal(t, http.StatusOK, call(authenticator.AdminHandler(next), ""))
+			assert.Equal(t, http.StatusUnauthorized, call(v1.NewTokenAuthenticator(tokenRepo, true).AdminHandler(next), ""))
+		})
+		t.Run("should reject tokens which can't admin all projects on every admin endpoint", func(t *testing.T) {
+			projectAdminToken := models.APIToken{
+				ID:     uuid.Must(uuid.NewRandom()),
+				Name:   "project-admin",
+				Scopes: []models.TokenScope{{Action: models.TokenActionAdmin, Project: "a-data-project"}},
+			}
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetByHash", tokenHash("opt_secret")).Return(ciToken, nil)
+			tokenRepo.On("GetByHash", tokenHash("opt_project_admin")).Return(projectAdminToken, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+			targets := []string{"/admin/freeze", "/admin/quota", "/admin/archive", "/admin/promote",
+				"/admin/template-migration", "/admin/changelog", "/admin/stale-jobs", "/admin/replication"}
+			adminMux := http.NewServeMux()
+			for _, target := range targets {
+				adminMux.Handle(target, next)
+			}
+			handler := authenticator.HTTPHandler(authenticator.AdminHandler(adminMux))
+
+			for _, target := range targets {
+				for _, secret := range []string{"opt_secret", "opt_project_admin"} {
+					req := httptest.NewRequest(http.MethodPut, target+"?project=a-data-project", nil)
+					req.Header.Set("Authorization", "Bearer "+secret)
+					rec := httptest.NewRecorder()
+					handler.ServeHTTP(rec, req)
+					assert.Equal(t, http.StatusForbidden, rec.Code, secret+" "+target)
+				}
+			}
+		})
+	})
+	t.Run("ServeHTTP", func(t *testing.T) {
+		t.Run("should create a token and serve its secret once", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("Insert", mock2.AnythingOfType("*models.APIToken")).Return(nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/tokens",
+				strings.NewReader(`{"name": "ci", "scopes": ["deploy:a-data-project", "read:*"]}`))
+			rec := httptest.NewRecorder()
+			authenticator.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var resp v1.APITokenResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, "ci", resp.Name)
+			assert.Equal(t, []string{"deploy:a-data-project", "read:*"}, resp.Scopes)
+			assert.True(t, strings.HasPrefix(resp.Token, "opt_"))
+
+			inserted := tokenRepo.Calls[0].Arguments.Get(0).(*models.APIToken)
+			assert.Equal(t, tokenHash(resp.Token), inserted.Hash)
+			assert.Equal(t, now, inserted.CreatedAt)
+		})
+		t.Run("should reject tokens with invalid scopes", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			defer tokenRepo.AssertExpectations(t)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/tokens",
+				strings.NewReader(`{"name": "ci", "scopes": ["write:a-data-project"]}`))
+			rec := httptest.NewRecorder()
+			v1.NewTokenAuthenticator(tokenRepo, false).ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+		t.Run("should list tokens without their secrets", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetAll").Return([]models.APIToken{ciToken}, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			rec := httptest.NewRecorder()
+			v1.NewTokenAuthenticator(tokenRepo, false).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/tokens", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var resp []v1.APITokenResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Len(t, resp, 1)
+			assert.Equal(t, ciToken.ID.String(), resp[0].ID)
+			assert.Empty(t, resp[0].Token)
+		})
+		t.Run("should revoke a token", func(t *testing.T) {
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("Revoke", ciToken.ID, now).Return(nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+
+			rec := httptest.NewRecorder()
+			authenticator.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/tokens?id="+ciToken.ID.String(), nil))
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		})
+	})
+}
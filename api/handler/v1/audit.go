@@ -0,0 +1,348 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// MetadataActor names the user an api call is made on behalf of. Audit
+	// log trusts it only for calls server makes to itself, e.g. git sync
+	// deployments, other calls are recorded with their principal
+	MetadataActor = "x-actor"
+
+	auditUnknownActor = "unknown"
+
+	// auditMaxMessageBytes caps the error message of failed http calls kept
+	auditMaxMessageBytes = 512
+)
+
+// mutatingRPCs are the methods of runtime service which modify state
+// and need to be recorded in audit log
+var mutatingRPCs = map[string]bool{
+	"DeployJobSpecification":      true,
+	"CreateJobSpecification":      true,
+	"DeleteJobSpecification":      true,
+	"RegisterProject":             true,
+	"RegisterProjectNamespace":    true,
+	"RegisterSecret":              true,
+	"RegisterInstance":            true,
+	"RegisterJobEvent":            true,
+	"DeployResourceSpecification": true,
+	"CreateResource":              true,
+	"UpdateResource":              true,
+	"Replay":                      true,
+}
+
+// AuditLogger records every mutating api call in the audit log
+type AuditLogger struct {
+	repo store.AuditLogRepository
+	Now  func() time.Time
+}
+
+// UnaryServerInterceptor records unary calls after they are handled
+func (a *AuditLogger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !isMutatingRPC(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		resp, err := handler(ctx, req)
+		a.record(ctx, info.FullMethod, req, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records streaming calls once the stream is closed,
+// first message received from client is treated as the request payload
+func (a *AuditLogger) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isMutatingRPC(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		wrapped := &auditServerStream{ServerStream: ss}
+		err := handler(srv, wrapped)
+		a.record(ss.Context(), info.FullMethod, wrapped.req, err)
+		return err
+	}
+}
+
+func (a *AuditLogger) record(ctx context.Context, method string, req interface{}, callErr error) {
+	entry := &models.AuditEntry{
+		Actor:       auditActor(ctx),
+		RPC:         method,
+		ProjectName: auditProjectName(req),
+		PayloadHash: auditPayloadHash(req),
+		Outcome:     models.AuditOutcomeSuccess,
+		CreatedAt:   a.Now(),
+	}
+	if callErr != nil {
+		entry.Outcome = models.AuditOutcomeFailure
+		entry.Message = callErr.Error()
+	}
+
+	// failing to audit should not fail the request itself
+	if err := a.repo.Insert(entry); err != nil {
+		logger.E("failed to record audit entry for ", method, ": ", err)
+	}
+}
+
+// HTTPHandler records calls to http endpoints made with methods which modify
+// state, anything other than GET, HEAD and OPTIONS, once they are handled.
+// Calls are recorded with method and path as rpc, e.g. POST /replay-plan,
+// project of the project query param and hash of method, url and body of
+// the request as payload. Calls to the api gateway are left to the grpc
+// interceptors
+func (a *AuditLogger) HTTPHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") || !isMutatingHTTPMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		payload := sha256.New()
+		fmt.Fprintf(payload, "%s %s\n", r.Method, r.URL.RequestURI())
+		if r.Body != nil {
+			r.Body = &auditRequestBody{ReadCloser: r.Body, hash: payload}
+		}
+		rec := &auditResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		entry := &models.AuditEntry{
+			Actor:       auditHTTPActor(r),
+			RPC:         r.Method + " " + r.URL.Path,
+			ProjectName: r.URL.Query().Get("project"),
+			PayloadHash: hex.EncodeToString(payload.Sum(nil)),
+			Outcome:     models.AuditOutcomeSuccess,
+			CreatedAt:   a.Now(),
+		}
+		if rec.status >= http.StatusBadRequest {
+			entry.Outcome = models.AuditOutcomeFailure
+			entry.Message = strings.TrimSpace(rec.message.String())
+			if entry.Message == "" {
+				entry.Message = http.StatusText(rec.status)
+			}
+		}
+		if err := a.repo.Insert(entry); err != nil {
+			logger.E("failed to record audit entry for ", entry.RPC, ": ", err)
+		}
+	})
+}
+
+// auditRequestBody hashes the request body as handler reads it
+type auditRequestBody struct {
+	io.ReadCloser
+	hash io.Writer
+}
+
+func (b *auditRequestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	return n, err
+}
+
+// auditResponseWriter captures status of the response along with the start
+// of its body for failed calls, which is the error message
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	message     bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	if w.status >= http.StatusBadRequest && w.message.Len() < auditMaxMessageBytes {
+		rest := auditMaxMessageBytes - w.message.Len()
+		if len(p) < rest {
+			rest = len(p)
+		}
+		w.message.Write(p[:rest])
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// auditServerStream captures the first message received on a stream
+type auditServerStream struct {
+	grpc.ServerStream
+	req interface{}
+}
+
+func (s *auditServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.req == nil {
+		s.req = m
+	}
+	return err
+}
+
+// AuditLogResponse is a single audit entry served over http
+type AuditLogResponse struct {
+	ID          string    `json:"id"`
+	Actor       string    `json:"actor"`
+	RPC         string    `json:"rpc"`
+	ProjectName string    `json:"project_name,omitempty"`
+	PayloadHash string    `json:"payload_hash,omitempty"`
+	Outcome     string    `json:"outcome"`
+	Message     string    `json:"message,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ListHandler serves audit entries as json, entries can be filtered using
+// actor, rpc, project, outcome, since, until(RFC3339) and limit query params
+func (a *AuditLogger) ListHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseAuditFilter(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entries, err := a.repo.List(filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		resp := []AuditLogResponse{}
+		for _, entry := range entries {
+			resp = append(resp, AuditLogResponse{
+				ID:          entry.ID.String(),
+				Actor:       entry.Actor,
+				RPC:         entry.RPC,
+				ProjectName: entry.ProjectName,
+				PayloadHash: entry.PayloadHash,
+				Outcome:     entry.Outcome,
+				Message:     entry.Message,
+				CreatedAt:   entry.CreatedAt,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func parseAuditFilter(query url.Values) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		Actor:       query.Get("actor"),
+		RPC:         query.Get("rpc"),
+		ProjectName: query.Get("project"),
+		Outcome:     query.Get("outcome"),
+	}
+	var err error
+	if val := query.Get("since"); val != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, val); err != nil {
+			return filter, errors.Wrap(err, "invalid since")
+		}
+	}
+	if val := query.Get("until"); val != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, val); err != nil {
+			return filter, errors.Wrap(err, "invalid until")
+		}
+	}
+	if val := query.Get("limit"); val != "" {
+		if filter.Limit, err = strconv.Atoi(val); err != nil {
+			return filter, errors.Wrap(err, "invalid limit")
+		}
+	}
+	return filter, nil
+}
+
+func isMutatingRPC(fullMethod string) bool {
+	return mutatingRPCs[path.Base(fullMethod)]
+}
+
+func isMutatingHTTPMethod(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+}
+
+// auditActor identifies the caller by the principal it is authenticated as
+// and falls back to peer address for calls made without a token
+func auditActor(ctx context.Context) string {
+	if principal, ok := Principal(ctx); ok {
+		return principal
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return auditUnknownActor
+}
+
+// auditHTTPActor identifies the caller of an http endpoint like auditActor,
+// falling back to the remote address
+func auditHTTPActor(r *http.Request) string {
+	if principal, ok := Principal(r.Context()); ok {
+		return principal
+	}
+	if r.RemoteAddr != "" {
+		return r.RemoteAddr
+	}
+	return auditUnknownActor
+}
+
+func auditProjectName(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetProjectName() string }:
+		return r.GetProjectName()
+	case interface {
+		GetProject() *pb.ProjectSpecification
+	}:
+		return r.GetProject().GetName()
+	}
+	return ""
+}
+
+func auditPayloadHash(req interface{}) string {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return ""
+	}
+	raw, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+func NewAuditLogger(repo store.AuditLogRepository) *AuditLogger {
+	return &AuditLogger{
+		repo: repo,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
@@ -0,0 +1,187 @@
+package v1_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAuditLogger(t *testing.T) {
+	logger.InitWithWriter("INFO", ioutil.Discard)
+	now := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("UnaryServerInterceptor", func(t *testing.T) {
+		t.Run("should record successful mutating calls with their principal as actor", func(t *testing.T) {
+			auditRepo := new(mock.AuditLogRepository)
+			defer auditRepo.AssertExpectations(t)
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetByHash", mock2.AnythingOfType("string")).Return(models.APIToken{
+				Name:   "ci",
+				Scopes: []models.TokenScope{{Action: models.TokenActionDeploy, Project: models.TokenScopeAllProjects}},
+			}, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			auditLogger := v1.NewAuditLogger(auditRepo)
+			auditLogger.Now = func() time.Time { return now }
+
+			var recorded *models.AuditEntry
+			auditRepo.On("Insert", mock2.AnythingOfType("*models.AuditEntry")).Run(func(args mock2.Arguments) {
+				recorded = args.Get(0).(*models.AuditEntry)
+			}).Return(nil)
+
+			// actor sent by client is not trusted over the token it is sent with
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				v1.MetadataActor, "alice@example.io",
+				v1.MetadataAuthorization, "Bearer opt_secret",
+			))
+			req := &pb.RegisterSecretRequest{ProjectName: "a-data-project", SecretName: "hello", Value: "world"}
+			info := &grpc.UnaryServerInfo{
+				FullMethod: "/odpf.optimus.RuntimeService/RegisterSecret",
+			}
+			resp, err := v1.NewTokenAuthenticator(tokenRepo, false).UnaryServerInterceptor()(ctx, req, info,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return auditLogger.UnaryServerInterceptor()(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+						return &pb.RegisterSecretResponse{Success: true}, nil
+					})
+				})
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.RegisterSecretResponse{Success: true}, resp)
+
+			assert.Equal(t, "token/ci", recorded.Actor)
+			assert.Equal(t, "/odpf.optimus.RuntimeService/RegisterSecret", recorded.RPC)
+			assert.Equal(t, "a-data-project", recorded.ProjectName)
+			assert.Len(t, recorded.PayloadHash, 64)
+			assert.Equal(t, models.AuditOutcomeSuccess, recorded.Outcome)
+			assert.Equal(t, now, recorded.CreatedAt)
+		})
+		t.Run("should record failed mutating calls and not fail if audit insert fails", func(t *testing.T) {
+			auditRepo := new(mock.AuditLogRepository)
+			defer auditRepo.AssertExpectations(t)
+
+			var recorded *models.AuditEntry
+			auditRepo.On("Insert", mock2.AnythingOfType("*models.AuditEntry")).Run(func(args mock2.Arguments) {
+				recorded = args.Get(0).(*models.AuditEntry)
+			}).Return(errors.New("db down"))
+
+			auditLogger := v1.NewAuditLogger(auditRepo)
+			req := &pb.RegisterProjectRequest{Project: &pb.ProjectSpecification{Name: "a-data-project"}}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(v1.MetadataActor, "alice@example.io"))
+			_, err := auditLogger.UnaryServerInterceptor()(ctx, req, &grpc.UnaryServerInfo{
+				FullMethod: "/odpf.optimus.RuntimeService/RegisterProject",
+			}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, errors.New("invalid project")
+			})
+			assert.Equal(t, "invalid project", err.Error())
+
+			assert.Equal(t, "unknown", recorded.Actor)
+			assert.Equal(t, "a-data-project", recorded.ProjectName)
+			assert.Equal(t, models.AuditOutcomeFailure, recorded.Outcome)
+			assert.Equal(t, "invalid project", recorded.Message)
+		})
+		t.Run("should skip read only calls", func(t *testing.T) {
+			auditRepo := new(mock.AuditLogRepository)
+			defer auditRepo.AssertExpectations(t)
+
+			auditLogger := v1.NewAuditLogger(auditRepo)
+			_, err := auditLogger.UnaryServerInterceptor()(context.Background(), &pb.ListProjectsRequest{}, &grpc.UnaryServerInfo{
+				FullMethod: "/odpf.optimus.RuntimeService/ListProjects",
+			}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return &pb.ListProjectsResponse{}, nil
+			})
+			assert.Nil(t, err)
+		})
+	})
+	t.Run("HTTPHandler", func(t *testing.T) {
+		t.Run("should record mutating calls with the token they are made with as actor", func(t *testing.T) {
+			auditRepo := new(mock.AuditLogRepository)
+			defer auditRepo.AssertExpectations(t)
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetByHash", mock2.AnythingOfType("string")).Return(models.APIToken{
+				Name:   "ci",
+				Scopes: []models.TokenScope{{Action: models.TokenActionDeploy, Project: models.TokenScopeAllProjects}},
+			}, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			auditLogger := v1.NewAuditLogger(auditRepo)
+			auditLogger.Now = func() time.Time { return now }
+
+			var recorded *models.AuditEntry
+			auditRepo.On("Insert", mock2.AnythingOfType("*models.AuditEntry")).Run(func(args mock2.Arguments) {
+				recorded = args.Get(0).(*models.AuditEntry)
+			}).Return(nil)
+
+			var body string
+			handler := v1.NewTokenAuthenticator(tokenRepo, false).HTTPHandler(auditLogger.HTTPHandler(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					raw, _ := ioutil.ReadAll(r.Body)
+					body = string(raw)
+					w.WriteHeader(http.StatusCreated)
+				})))
+			req := httptest.NewRequest(http.MethodPost, "/replay-plan?project=a-data-project", strings.NewReader(`{"job_name":"a-job"}`))
+			req.Header.Set("Authorization", "Bearer opt_secret")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, `{"job_name":"a-job"}`, body)
+			assert.Equal(t, "token/ci", recorded.Actor)
+			assert.Equal(t, "POST /replay-plan", recorded.RPC)
+			assert.Equal(t, "a-data-project", recorded.ProjectName)
+			assert.Len(t, recorded.PayloadHash, 64)
+			assert.Equal(t, models.AuditOutcomeSuccess, recorded.Outcome)
+			assert.Equal(t, now, recorded.CreatedAt)
+		})
+		t.Run("should record failed calls with the error served", func(t *testing.T) {
+			auditRepo := new(mock.AuditLogRepository)
+			defer auditRepo.AssertExpectations(t)
+
+			var recorded *models.AuditEntry
+			auditRepo.On("Insert", mock2.AnythingOfType("*models.AuditEntry")).Run(func(args mock2.Arguments) {
+				recorded = args.Get(0).(*models.AuditEntry)
+			}).Return(nil)
+
+			handler := v1.NewAuditLogger(auditRepo).HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "project a-data-project is frozen", http.StatusConflict)
+			}))
+			req := httptest.NewRequest(http.MethodPut, "/admin/quota?project=a-data-project", nil)
+			req.RemoteAddr = "10.0.0.1:5000"
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusConflict, rec.Code)
+			assert.Equal(t, "10.0.0.1:5000", recorded.Actor)
+			assert.Equal(t, "PUT /admin/quota", recorded.RPC)
+			assert.Equal(t, models.AuditOutcomeFailure, recorded.Outcome)
+			assert.Equal(t, "project a-data-project is frozen", recorded.Message)
+		})
+		t.Run("should skip read only calls and calls to the api gateway", func(t *testing.T) {
+			auditRepo := new(mock.AuditLogRepository)
+			defer auditRepo.AssertExpectations(t)
+
+			handler := v1.NewAuditLogger(auditRepo).HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			for _, req := range []*http.Request{
+				httptest.NewRequest(http.MethodGet, "/admin/quota?project=a-data-project", nil),
+				httptest.NewRequest(http.MethodPost, "/api/v1/project", nil),
+			} {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				assert.Equal(t, http.StatusOK, rec.Code)
+			}
+		})
+	})
+}
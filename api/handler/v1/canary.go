@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// MetadataCanary set in request metadata of job deployment first deploys
+	// canary of the added and modified jobs, see job.CanaryJobSpec. Jobs are
+	// deployed only if every canary is loaded by scheduler, and with
+	// CanaryRun, a triggered run of every canary succeeds
+	MetadataCanary = "x-canary"
+
+	CanaryDeploy = "deploy"
+	CanaryRun    = "run"
+)
+
+var (
+	// CanaryTimeout bounds the wait for canary jobs to be loaded and run
+	CanaryTimeout = 15 * time.Minute
+
+	// CanaryPollInterval is the interval scheduler is polled at for the
+	// state of canary jobs
+	CanaryPollInterval = 15 * time.Second
+)
+
+func canaryMode(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", nil
+	}
+	vals := md.Get(MetadataCanary)
+	if len(vals) == 0 {
+		return "", nil
+	}
+	switch vals[0] {
+	case "", CanaryDeploy, CanaryRun:
+		return vals[0], nil
+	}
+	return "", status.Errorf(codes.InvalidArgument, "invalid canary mode %s, should be %s or %s", vals[0], CanaryDeploy, CanaryRun)
+}
+
+// changedJobs returns jobs added or modified compared to previousJobs
+func changedJobs(previousJobs, currentJobs []models.JobSpec) []models.JobSpec {
+	changed := map[string]bool{}
+	for _, change := range job.DiffJobSpecs(previousJobs, currentJobs) {
+		if change.Type != models.JobChangeTypeRemoved {
+			changed[change.Name] = true
+		}
+	}
+	var jobSpecs []models.JobSpec
+	for _, jobSpec := range currentJobs {
+		if changed[jobSpec.Name] {
+			jobSpecs = append(jobSpecs, jobSpec)
+		}
+	}
+	return jobSpecs
+}
+
+// deployCanary deploys canary of jobs and waits for scheduler to load, and
+// optionally run, all of them. Canary jobs are deleted once done
+func (sv *RuntimeServiceServer) deployCanary(namespaceSpec models.NamespaceSpec, jobSpecs []models.JobSpec, run bool,
+	respStream pb.RuntimeService_DeployJobSpecificationServer, observer progress.Observer) error {
+	if len(jobSpecs) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(respStream.Context(), CanaryTimeout)
+	defer cancel()
+	defer func() {
+		// canary are deleted even if client is gone
+		if err := sv.jobSvc.DeleteCanary(context.Background(), namespaceSpec, jobSpecs); err != nil {
+			logger.E("failed to delete canary jobs: ", err)
+		}
+	}()
+
+	if err := sv.jobSvc.DeployCanary(ctx, namespaceSpec, jobSpecs, observer); err != nil {
+		return status.Errorf(codes.FailedPrecondition, "%s\ncanary deployment failed, jobs are not deployed", err.Error())
+	}
+
+	canaryProject := job.CanaryProject(namespaceSpec.ProjectSpec)
+	checkErrors := make([]error, len(jobSpecs))
+	var wg sync.WaitGroup
+	for idx, jobSpec := range jobSpecs {
+		wg.Add(1)
+		go func(idx int, jobName string) {
+			defer wg.Done()
+			checkErrors[idx] = sv.checkCanary(ctx, canaryProject, jobName, run)
+		}(idx, job.CanaryJobName(jobSpec.Name))
+	}
+	wg.Wait()
+
+	// stream is written from a single goroutine
+	var canaryErrors error
+	for idx, jobSpec := range jobSpecs {
+		resp := &pb.DeployJobSpecificationResponse{
+			JobName: job.CanaryJobName(jobSpec.Name),
+			Message: "canary succeeded",
+		}
+		if err := checkErrors[idx]; err != nil {
+			resp.Message = "canary failed: " + err.Error()
+			canaryErrors = multierror.Append(canaryErrors, errors.Wrapf(err, "canary of %s failed", jobSpec.Name))
+		}
+		if err := respStream.Send(resp); err != nil {
+			logger.E(errors.Wrapf(err, "failed to send canary notification for: %s", resp.JobName))
+		}
+	}
+	if canaryErrors != nil {
+		return status.Errorf(codes.FailedPrecondition, "%s\ncanary deployment failed, jobs are not deployed", canaryErrors.Error())
+	}
+	return nil
+}
+
+// checkCanary waits for canary job to be loaded by scheduler, and if run is
+// set, for a triggered run of it to succeed
+func (sv *RuntimeServiceServer) checkCanary(ctx context.Context, projSpec models.ProjectSpec, jobName string, run bool) error {
+	if err := pollCanary(ctx, func() (bool, error) {
+		return sv.scheduler.IsJobLoaded(ctx, projSpec, jobName)
+	}); err != nil {
+		return errors.Wrap(err, "job is not loaded by scheduler")
+	}
+	if !run {
+		return nil
+	}
+
+	scheduledAt := time.Now().UTC().Truncate(time.Second)
+	if err := sv.scheduler.RunJob(ctx, projSpec, jobName, scheduledAt); err != nil {
+		return err
+	}
+	return pollCanary(ctx, func() (bool, error) {
+		runs, err := sv.scheduler.GetDagRunStatus(ctx, projSpec, jobName, scheduledAt, scheduledAt, 1)
+		if err != nil {
+			return false, err
+		}
+		for _, run := range runs {
+			switch run.State {
+			case models.JobStatusStateSuccess:
+				return true, nil
+			case models.JobStatusStateFailed:
+				return false, errors.Errorf("run scheduled at %s failed", scheduledAt.Format(time.RFC3339))
+			}
+		}
+		return false, nil
+	})
+}
+
+// pollCanary calls check every CanaryPollInterval until it is done
+func pollCanary(ctx context.Context, check func() (bool, error)) error {
+	ticker := time.NewTicker(CanaryPollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
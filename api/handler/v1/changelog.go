@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	changelogDefaultLimit = 10
+)
+
+// ChangelogFieldResponse is the change in a single field of a job
+type ChangelogFieldResponse struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// ChangelogJobResponse is how a job changed in a deployment
+type ChangelogJobResponse struct {
+	Name   string                   `json:"name"`
+	Type   string                   `json:"type"`
+	Fields []ChangelogFieldResponse `json:"fields,omitempty"`
+}
+
+// ChangelogResultResponse is the outcome of deploying a job
+type ChangelogResultResponse struct {
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
+// ChangelogResponse is the changelog of a deployment served over http
+type ChangelogResponse struct {
+	ID           string                    `json:"id"`
+	Namespace    string                    `json:"namespace"`
+	Actor        string                    `json:"actor,omitempty"`
+	Changes      []ChangelogJobResponse    `json:"changes"`
+	SnapshotHash string                    `json:"snapshot_hash,omitempty"`
+	Results      []ChangelogResultResponse `json:"results,omitempty"`
+	Failed       bool                      `json:"failed"`
+	RollbackOf   string                    `json:"rollback_of,omitempty"`
+	CreatedAt    time.Time                 `json:"created_at"`
+}
+
+// ChangelogHandler serves latest deploy changelogs of a project as json,
+// project query param is required, namespace and limit are optional
+type ChangelogHandler struct {
+	repo               store.DeployChangelogRepository
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *ChangelogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	projectName := query.Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	limit := changelogDefaultLimit
+	if val := query.Get("limit"); val != "" {
+		var err error
+		if limit, err = strconv.Atoi(val); err != nil || limit <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	changelogs, err := h.repo.GetLatest(projSpec, query.Get("namespace"), limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := []ChangelogResponse{}
+	for _, changelog := range changelogs {
+		changes := []ChangelogJobResponse{}
+		for _, change := range changelog.Changes {
+			var fields []ChangelogFieldResponse
+			for _, field := range change.Fields {
+				fields = append(fields, ChangelogFieldResponse{
+					Field: field.Field,
+					Old:   field.Old,
+					New:   field.New,
+				})
+			}
+			changes = append(changes, ChangelogJobResponse{
+				Name:   change.Name,
+				Type:   string(change.Type),
+				Fields: fields,
+			})
+		}
+		var results []ChangelogResultResponse
+		for _, result := range changelog.Results {
+			results = append(results, ChangelogResultResponse{
+				Name:    result.Name,
+				Success: result.Success,
+				Message: result.Message,
+			})
+		}
+		var rollbackOf string
+		if changelog.RollbackOf != uuid.Nil {
+			rollbackOf = changelog.RollbackOf.String()
+		}
+		resp = append(resp, ChangelogResponse{
+			ID:           changelog.ID.String(),
+			Namespace:    changelog.Namespace,
+			Actor:        changelog.Actor,
+			Changes:      changes,
+			SnapshotHash: changelog.SnapshotHash,
+			Results:      results,
+			Failed:       changelog.Failed,
+			RollbackOf:   rollbackOf,
+			CreatedAt:    changelog.CreatedAt,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewChangelogHandler(repo store.DeployChangelogRepository, projectRepoFactory ProjectRepoFactory) *ChangelogHandler {
+	return &ChangelogHandler{
+		repo:               repo,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// CheckpointRequest is a checkpoint saved over http
+type CheckpointRequest struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CheckpointResponse is a checkpoint served over http
+type CheckpointResponse struct {
+	Name      string    `json:"name"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointHandler lets runs of a job identified by project and job query
+// params persist checkpoints, which are compiled in the context of later runs.
+// GET lists checkpoints of the job, PUT saves the one in body and DELETE
+// deletes the one in name query param
+type CheckpointHandler struct {
+	checkpointRepo     store.JobCheckpointRepository
+	jobSvc             models.JobService
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *CheckpointHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	projectName := r.URL.Query().Get("project")
+	jobName := r.URL.Query().Get("job")
+	if projectName == "" || jobName == "" {
+		http.Error(w, "project and job are required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobSpec, _, err := h.jobSvc.GetByNameForProject(jobName, projSpec)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "job "+jobName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.list(w, jobSpec)
+	case http.MethodPut:
+		var req CheckpointRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid checkpoint: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		checkpoint := models.JobCheckpoint{Name: req.Name, Value: req.Value}
+		if err := checkpoint.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.checkpointRepo.Save(jobSpec, checkpoint); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		if err := h.checkpointRepo.Delete(jobSpec, name); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CheckpointHandler) list(w http.ResponseWriter, jobSpec models.JobSpec) {
+	checkpoints, err := h.checkpointRepo.GetAll(jobSpec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := []CheckpointResponse{}
+	for _, checkpoint := range checkpoints {
+		resp = append(resp, CheckpointResponse{
+			Name:      checkpoint.Name,
+			Value:     checkpoint.Value,
+			UpdatedAt: checkpoint.UpdatedAt,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewCheckpointHandler(checkpointRepo store.JobCheckpointRepository, jobSvc models.JobService,
+	projectRepoFactory ProjectRepoFactory) *CheckpointHandler {
+	return &CheckpointHandler{
+		checkpointRepo:     checkpointRepo,
+		jobSvc:             jobSvc,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,83 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpointHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-job",
+	}
+	setup := func() (*mock.ProjectRepoFactory, *mock.JobService) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+		return projectRepoFactory, jobService
+	}
+
+	t.Run("should list checkpoints of job", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		defer jobService.AssertExpectations(t)
+
+		updatedAt := time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC)
+		checkpointRepo := new(mock.JobCheckpointRepository)
+		checkpointRepo.On("GetAll", jobSpec).Return([]models.JobCheckpoint{
+			{Name: "LAST_OFFSET", Value: "250", UpdatedAt: updatedAt},
+		}, nil)
+		defer checkpointRepo.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewCheckpointHandler(checkpointRepo, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/checkpoints?project=a-data-project&job=a-job", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp []v1.CheckpointResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, []v1.CheckpointResponse{{Name: "LAST_OFFSET", Value: "250", UpdatedAt: updatedAt}}, resp)
+	})
+	t.Run("should save checkpoint of job", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		defer jobService.AssertExpectations(t)
+
+		checkpointRepo := new(mock.JobCheckpointRepository)
+		checkpointRepo.On("Save", jobSpec, models.JobCheckpoint{Name: "LAST_OFFSET", Value: "300"}).Return(nil)
+		defer checkpointRepo.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewCheckpointHandler(checkpointRepo, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPut, "/checkpoints?project=a-data-project&job=a-job",
+				strings.NewReader(`{"name": "LAST_OFFSET", "value": "300"}`)))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("should not save checkpoint with invalid name", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		checkpointRepo := new(mock.JobCheckpointRepository)
+
+		rec := httptest.NewRecorder()
+		v1.NewCheckpointHandler(checkpointRepo, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPut, "/checkpoints?project=a-data-project&job=a-job",
+				strings.NewReader(`{"name": "last-offset", "value": "300"}`)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		checkpointRepo.AssertNotCalled(t, "Save")
+	})
+}
@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// MetadataCommitRepository and MetadataCommitSHA set in request metadata
+	// of job deployment post result of the deployment back to the commit
+	// specifications are of, see models.CommitStatusPublisher.
+	// MetadataCommitSpecPath is the directory of job specifications of the
+	// namespace in repository, failed jobs are annotated at their spec file
+	// in it
+	MetadataCommitRepository = "x-commit-repository"
+	MetadataCommitSHA        = "x-commit-sha"
+	MetadataCommitSpecPath   = "x-commit-spec-path"
+
+	// CommitStatusName prefixes name of the statuses posted, followed by
+	// the namespace deployed
+	CommitStatusName = "optimus/deploy"
+
+	// jobs are expected in the directory their name defaults to in create
+	// job command, e.g. job a.b is at a/b/job.yaml
+	commitJobSpecFile = "job.yaml"
+
+	commitStatusTimeout = time.Second * 30
+)
+
+// commitRef returns the commit of the specifications deployed if request
+// metadata has one
+func commitRef(ctx context.Context) (models.CommitRef, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return models.CommitRef{}, false
+	}
+	value := func(key string) string {
+		if vals := md.Get(key); len(vals) > 0 {
+			return strings.TrimSpace(vals[0])
+		}
+		return ""
+	}
+	ref := models.CommitRef{
+		Repository: value(MetadataCommitRepository),
+		SHA:        value(MetadataCommitSHA),
+		SpecPath:   value(MetadataCommitSpecPath),
+	}
+	return ref, ref.Repository != "" && ref.SHA != ""
+}
+
+// publishDeployStatus posts result of a job deployment to the commit it is
+// of, deployment is already done so failing to post is only logged
+func (sv *RuntimeServiceServer) publishDeployStatus(projSpec models.ProjectSpec, namespaceSpec models.NamespaceSpec,
+	ref models.CommitRef, results *jobResultObserver, deployErr error) {
+	if sv.commitStatusSvc == nil {
+		return
+	}
+	commitStatus := models.CommitStatus{
+		Ref:   ref,
+		Name:  CommitStatusName + "/" + namespaceSpec.Name,
+		State: models.CommitStateSuccess,
+	}
+
+	results.mu.Lock()
+	jobs := len(results.results)
+	for _, result := range results.results {
+		if result.Success {
+			continue
+		}
+		annotation := models.CommitAnnotation{
+			Title:   result.Name,
+			Message: result.Message,
+		}
+		if ref.SpecPath != "" {
+			annotation.Path = path.Join(ref.SpecPath, strings.ReplaceAll(result.Name, ".", "/"), commitJobSpecFile)
+		}
+		commitStatus.Annotations = append(commitStatus.Annotations, annotation)
+	}
+	results.mu.Unlock()
+
+	switch {
+	case len(commitStatus.Annotations) > 0:
+		commitStatus.State = models.CommitStateFailure
+		commitStatus.Description = fmt.Sprintf("%d of %d jobs failed to deploy to %s", len(commitStatus.Annotations), jobs, namespaceSpec.Name)
+	case deployErr != nil:
+		commitStatus.State = models.CommitStateFailure
+		commitStatus.Description = fmt.Sprintf("deployment to %s failed: %s", namespaceSpec.Name, status.Convert(deployErr).Message())
+	default:
+		commitStatus.Description = fmt.Sprintf("deployed %d jobs to %s", jobs, namespaceSpec.Name)
+	}
+
+	// stream of request may be done by now
+	ctx, cancel := context.WithTimeout(context.Background(), commitStatusTimeout)
+	defer cancel()
+	if err := sv.commitStatusSvc.Publish(ctx, projSpec, commitStatus); err != nil {
+		logger.E("failed to publish commit status of ", ref.SHA, " to ", ref.Repository, ": ", err)
+	}
+}
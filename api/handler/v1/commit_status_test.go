@@ -0,0 +1,122 @@
+package v1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/metadata"
+
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+)
+
+func TestDeployCommitStatus(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "dev-test-namespace-1",
+		ProjectSpec: projectSpec,
+	}
+	commitCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		v1.MetadataCommitRepository, "github.com/odpf/specs",
+		v1.MetadataCommitSHA, "abc123",
+		v1.MetadataCommitSpecPath, "specs/jobs",
+	))
+
+	setup := func(t *testing.T, uploadErr error) (*v1.RuntimeServiceServer, *mock.CommitStatusPublisher) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		namespaceRepository := new(mock.NamespaceRepository)
+		namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+		namespaceRepoFact := new(mock.NamespaceRepoFactory)
+		namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{}, nil)
+		jobService.On("CheckDestinations", namespaceSpec, mock2.Anything).Return(nil)
+		jobService.On("KeepOnly", namespaceSpec, mock2.Anything, mock2.Anything).Return(nil)
+		jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Run(func(args mock2.Arguments) {
+			observer := args.Get(2).(progress.Observer)
+			observer.Notify(&job.EventJobUpload{Job: models.JobSpec{Name: "team.a-data-job"}, Err: uploadErr})
+			observer.Notify(&job.EventJobUpload{Job: models.JobSpec{Name: "team.other-job"}})
+		}).Return(nil)
+
+		publisher := new(mock.CommitStatusPublisher)
+		return v1.NewRuntimeServiceServer("1.0.1", jobService, nil, nil, projectRepoFactory, namespaceRepoFact,
+			nil, v1.NewAdapter(nil, nil), nil, nil, nil, nil, nil, nil, publisher), publisher
+	}
+	deployRequest := &pb.DeployJobSpecificationRequest{ProjectName: projectSpec.Name, Namespace: namespaceSpec.Name}
+
+	t.Run("should post success to commit of deployment", func(t *testing.T) {
+		runtimeServiceServer, publisher := setup(t, nil)
+		publisher.On("Publish", mock2.Anything, projectSpec, models.CommitStatus{
+			Ref: models.CommitRef{
+				Repository: "github.com/odpf/specs",
+				SHA:        "abc123",
+				SpecPath:   "specs/jobs",
+			},
+			Name:        "optimus/deploy/dev-test-namespace-1",
+			State:       models.CommitStateSuccess,
+			Description: "deployed 2 jobs to dev-test-namespace-1",
+		}).Return(nil)
+		defer publisher.AssertExpectations(t)
+
+		grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+		grpcRespStream.On("Context").Return(commitCtx)
+		grpcRespStream.On("Send", mock2.Anything).Return(nil)
+
+		assert.Nil(t, runtimeServiceServer.DeployJobSpecification(deployRequest, grpcRespStream))
+	})
+	t.Run("should annotate jobs failed to deploy at their spec file", func(t *testing.T) {
+		runtimeServiceServer, publisher := setup(t, errors.New("failed to compile"))
+		publisher.On("Publish", mock2.Anything, projectSpec, models.CommitStatus{
+			Ref: models.CommitRef{
+				Repository: "github.com/odpf/specs",
+				SHA:        "abc123",
+				SpecPath:   "specs/jobs",
+			},
+			Name:        "optimus/deploy/dev-test-namespace-1",
+			State:       models.CommitStateFailure,
+			Description: "1 of 2 jobs failed to deploy to dev-test-namespace-1",
+			Annotations: []models.CommitAnnotation{
+				{
+					Path:    "specs/jobs/team/a-data-job/job.yaml",
+					Title:   "team.a-data-job",
+					Message: "failed to compile",
+				},
+			},
+		}).Return(errors.New("git host unavailable"))
+		defer publisher.AssertExpectations(t)
+
+		grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+		grpcRespStream.On("Context").Return(commitCtx)
+		grpcRespStream.On("Send", mock2.Anything).Return(nil)
+
+		// failing to post the status doesn't fail the deployment
+		assert.Nil(t, runtimeServiceServer.DeployJobSpecification(deployRequest, grpcRespStream))
+	})
+	t.Run("should not post status of deployments without a commit", func(t *testing.T) {
+		runtimeServiceServer, publisher := setup(t, nil)
+		defer publisher.AssertExpectations(t)
+
+		grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+		grpcRespStream.On("Context").Return(context.Background())
+		grpcRespStream.On("Send", mock2.Anything).Return(nil)
+
+		assert.Nil(t, runtimeServiceServer.DeployJobSpecification(deployRequest, grpcRespStream))
+	})
+}
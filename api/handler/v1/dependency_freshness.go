@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// DependencyFreshnessChecker asserts freshness of destination of a
+// dependency of a job for a run
+type DependencyFreshnessChecker interface {
+	Check(ctx context.Context, projSpec models.ProjectSpec, jobName, dependencyProjectName, dependencyName string,
+		scheduledAt time.Time) (models.DependencyFreshness, error)
+}
+
+// DependencyFreshnessResponse is freshness of destination of a dependency
+// served over http
+type DependencyFreshnessResponse struct {
+	Destination    string     `json:"destination,omitempty"`
+	WindowStart    time.Time  `json:"window_start"`
+	Supported      bool       `json:"supported"`
+	LastModifiedAt *time.Time `json:"last_modified_at,omitempty"`
+	Fresh          bool       `json:"fresh"`
+}
+
+// DependencyFreshnessHandler serves freshness of destination of dependency
+// named by dependency query param of a job identified by project and job
+// query params, for its run scheduled at scheduled_at. Dependencies of
+// other projects are named with dependency_project. Compiled dags call it
+// before the task of a run starts to fail fast on stale dependencies
+type DependencyFreshnessHandler struct {
+	checker            DependencyFreshnessChecker
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *DependencyFreshnessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	projectName := query.Get("project")
+	jobName := query.Get("job")
+	dependencyName := query.Get("dependency")
+	if projectName == "" || jobName == "" || dependencyName == "" {
+		http.Error(w, "project, job and dependency are required", http.StatusBadRequest)
+		return
+	}
+	scheduledAt, err := time.Parse(time.RFC3339, query.Get("scheduled_at"))
+	if err != nil {
+		http.Error(w, "invalid scheduled_at, expected RFC3339: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	freshness, err := h.checker.Check(r.Context(), projSpec, jobName, query.Get("dependency_project"),
+		dependencyName, scheduledAt)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := DependencyFreshnessResponse{
+		Destination: freshness.Destination,
+		WindowStart: freshness.WindowStart,
+		Supported:   freshness.Supported,
+		Fresh:       freshness.Fresh,
+	}
+	if !freshness.LastModifiedAt.IsZero() {
+		lastModifiedAt := freshness.LastModifiedAt
+		resp.LastModifiedAt = &lastModifiedAt
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewDependencyFreshnessHandler(checker DependencyFreshnessChecker, projectRepoFactory ProjectRepoFactory) *DependencyFreshnessHandler {
+	return &DependencyFreshnessHandler{
+		checker:            checker,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,79 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestDependencyFreshnessHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	scheduledAt := time.Date(2021, 7, 1, 2, 0, 0, 0, time.UTC)
+	windowStart := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+	newProjectRepoFactory := func() *mock.ProjectRepoFactory {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		return projectRepoFactory
+	}
+
+	t.Run("should serve freshness of destination of dependency for the run", func(t *testing.T) {
+		lastModifiedAt := windowStart.Add(-3 * time.Hour)
+		checker := new(mock.DependencyFreshnessChecker)
+		defer checker.AssertExpectations(t)
+		checker.On("Check", mock2.Anything, projectSpec, "revenue", "b-data-project", "sessions", scheduledAt).Return(
+			models.DependencyFreshness{
+				Destination:    "bigquery://proj:raw.sessions",
+				WindowStart:    windowStart,
+				Supported:      true,
+				LastModifiedAt: lastModifiedAt,
+			}, nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewDependencyFreshnessHandler(checker, newProjectRepoFactory()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/dependency-freshness?project=a-data-project&job=revenue&dependency_project=b-data-project"+
+				"&dependency=sessions&scheduled_at=2021-07-01T02:00:00Z", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.DependencyFreshnessResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.DependencyFreshnessResponse{
+			Destination:    "bigquery://proj:raw.sessions",
+			WindowStart:    windowStart,
+			Supported:      true,
+			LastModifiedAt: &lastModifiedAt,
+			Fresh:          false,
+		}, resp)
+	})
+	t.Run("should respond not found for unknown jobs", func(t *testing.T) {
+		checker := new(mock.DependencyFreshnessChecker)
+		checker.On("Check", mock2.Anything, projectSpec, "revenue", "", "sessions", scheduledAt).Return(
+			models.DependencyFreshness{}, errors.Wrap(store.ErrResourceNotFound, "failed to find job revenue"))
+
+		rec := httptest.NewRecorder()
+		v1.NewDependencyFreshnessHandler(checker, newProjectRepoFactory()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/dependency-freshness?project=a-data-project&job=revenue&dependency=sessions&scheduled_at=2021-07-01T02:00:00Z", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("should reject requests without a valid scheduled_at", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v1.NewDependencyFreshnessHandler(new(mock.DependencyFreshnessChecker), newProjectRepoFactory()).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/dependency-freshness?project=a-data-project&job=revenue&dependency=sessions", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// DestinationProducerResponse is a job producing a destination served over http
+type DestinationProducerResponse struct {
+	Destination string    `json:"destination"`
+	Project     string    `json:"project"`
+	Namespace   string    `json:"namespace"`
+	Job         string    `json:"job"`
+	Owner       string    `json:"owner,omitempty"`
+	Task        string    `json:"task"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type DestinationsResponse struct {
+	Producers []DestinationProducerResponse `json:"producers"`
+}
+
+// DestinationHandler serves the registry of destinations of jobs across all
+// projects. destination query param returns producers of the destination,
+// not found if it has none, otherwise destinations starting with prefix param
+// are listed up to limit
+type DestinationHandler struct {
+	registry store.DestinationRegistry
+}
+
+func (h *DestinationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	var limit int
+	if val := query.Get("limit"); val != "" {
+		var err error
+		if limit, err = strconv.Atoi(val); err != nil || limit <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var (
+		producers []models.DestinationProducer
+		err       error
+	)
+	if destination := query.Get("destination"); destination != "" {
+		producers, err = h.registry.GetProducers(destination)
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "no job produces "+destination, http.StatusNotFound)
+			return
+		}
+	} else {
+		producers, err = h.registry.List(query.Get("prefix"), limit)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := DestinationsResponse{Producers: []DestinationProducerResponse{}}
+	for _, producer := range producers {
+		resp.Producers = append(resp.Producers, DestinationProducerResponse{
+			Destination: producer.Destination,
+			Project:     producer.Project,
+			Namespace:   producer.Namespace,
+			Job:         producer.Job,
+			Owner:       producer.Owner,
+			Task:        producer.Task,
+			UpdatedAt:   producer.UpdatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewDestinationHandler(registry store.DestinationRegistry) *DestinationHandler {
+	return &DestinationHandler{
+		registry: registry,
+	}
+}
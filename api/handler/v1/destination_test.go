@@ -0,0 +1,69 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDestinationHandler(t *testing.T) {
+	updatedAt := time.Date(2021, 6, 1, 2, 0, 0, 0, time.UTC)
+
+	t.Run("should return producers of destination", func(t *testing.T) {
+		registry := new(mock.DestinationRegistry)
+		registry.On("GetProducers", "shop-project:finance.daily_revenue").Return([]models.DestinationProducer{{
+			Destination: "shop-project:finance.daily_revenue", Project: "shop", Namespace: "finance",
+			Job: "daily_revenue", Owner: "finance@example.com", Task: "bq2bq", UpdatedAt: updatedAt,
+		}}, nil)
+		defer registry.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewDestinationHandler(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/destinations?destination=shop-project:finance.daily_revenue", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.DestinationsResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.DestinationsResponse{
+			Producers: []v1.DestinationProducerResponse{{
+				Destination: "shop-project:finance.daily_revenue", Project: "shop", Namespace: "finance",
+				Job: "daily_revenue", Owner: "finance@example.com", Task: "bq2bq", UpdatedAt: updatedAt,
+			}},
+		}, resp)
+	})
+	t.Run("should return not found for destinations without producers", func(t *testing.T) {
+		registry := new(mock.DestinationRegistry)
+		registry.On("GetProducers", "shop-project:finance.unknown").Return([]models.DestinationProducer{}, store.ErrResourceNotFound)
+		defer registry.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewDestinationHandler(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/destinations?destination=shop-project:finance.unknown", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("should list destinations by prefix", func(t *testing.T) {
+		registry := new(mock.DestinationRegistry)
+		registry.On("List", "shop-project:", 10).Return([]models.DestinationProducer{}, nil)
+		defer registry.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewDestinationHandler(registry).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/destinations?prefix=shop-project:&limit=10", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"producers": []}`, rec.Body.String())
+	})
+	t.Run("should reject invalid limit", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v1.NewDestinationHandler(new(mock.DestinationRegistry)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/destinations?limit=-1", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// DurationAnomalyGetter returns runs of jobs of a project which took much
+// longer than their recent runs
+type DurationAnomalyGetter interface {
+	GetAnomalies(projectName string) []models.DurationAnomaly
+}
+
+// DurationAnomalyResponse is a duration anomaly served over http
+type DurationAnomalyResponse struct {
+	Namespace   string    `json:"namespace"`
+	JobName     string    `json:"job_name"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Duration    string    `json:"duration"`
+	Baseline    string    `json:"baseline"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// DurationAnomalyHandler serves duration anomalies of a project identified by
+// project query param, optionally of a single job identified by job query param
+type DurationAnomalyHandler struct {
+	anomalies          DurationAnomalyGetter
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *DurationAnomalyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	jobName := r.URL.Query().Get("job")
+	resp := []DurationAnomalyResponse{}
+	for _, anomaly := range h.anomalies.GetAnomalies(projSpec.Name) {
+		if jobName != "" && anomaly.JobName != jobName {
+			continue
+		}
+		resp = append(resp, DurationAnomalyResponse{
+			Namespace:   anomaly.NamespaceName,
+			JobName:     anomaly.JobName,
+			ScheduledAt: anomaly.ScheduledAt,
+			Duration:    anomaly.Duration.String(),
+			Baseline:    anomaly.Baseline.String(),
+			DetectedAt:  anomaly.DetectedAt,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewDurationAnomalyHandler(anomalies DurationAnomalyGetter, projectRepoFactory ProjectRepoFactory) *DurationAnomalyHandler {
+	return &DurationAnomalyHandler{
+		anomalies:          anomalies,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,253 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// FreezeViolationType identifies the precondition failure detail
+	// sent with calls rejected because of project freeze
+	FreezeViolationType = "PROJECT_FREEZE"
+)
+
+// frozenRPCs are the methods of runtime service which are
+// rejected while a project is frozen
+var frozenRPCs = map[string]bool{
+	"DeployJobSpecification":      true,
+	"CreateJobSpecification":      true,
+	"DeleteJobSpecification":      true,
+	"DeployResourceSpecification": true,
+	"CreateResource":              true,
+	"UpdateResource":              true,
+	"Replay":                      true,
+}
+
+// FreezeChecker rejects changes to frozen projects made over http, which
+// aren't guarded by the grpc interceptors
+type FreezeChecker interface {
+	Check(projectName string) error
+}
+
+// freezeStatusCode is the http status code of an error returned by Check
+func freezeStatusCode(err error) int {
+	if status.Code(err) == codes.FailedPrecondition {
+		return http.StatusConflict
+	}
+	return http.StatusServiceUnavailable
+}
+
+// FreezeGuard rejects deploys and replays of frozen projects
+type FreezeGuard struct {
+	repo               store.ProjectFreezeRepository
+	projectRepoFactory ProjectRepoFactory
+	Now                func() time.Time
+}
+
+// UnaryServerInterceptor rejects unary calls before they are handled
+func (g *FreezeGuard) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if frozenRPCs[path.Base(info.FullMethod)] {
+			if err := g.Check(auditProjectName(req)); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streaming calls once the request
+// is received from client
+func (g *FreezeGuard) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !frozenRPCs[path.Base(info.FullMethod)] {
+			return handler(srv, ss)
+		}
+		return handler(srv, &freezeServerStream{ServerStream: ss, guard: g})
+	}
+}
+
+// Check returns a failed precondition status if the project is frozen,
+// unknown projects are left for handlers to deal with. Calls are rejected
+// as unavailable if freeze of the project can't be read
+func (g *FreezeGuard) Check(projectName string) error {
+	if projectName == "" {
+		return nil
+	}
+	projSpec, err := g.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil
+		}
+		logger.E("failed to get project ", projectName, " to check its freeze: ", err)
+		return status.Errorf(codes.Unavailable, "failed to check freeze of project %s", projectName)
+	}
+	freeze, err := g.repo.GetByProject(projSpec)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil
+		}
+		logger.E("failed to get freeze of project ", projectName, ": ", err)
+		return status.Errorf(codes.Unavailable, "failed to check freeze of project %s", projectName)
+	}
+	if !freeze.IsActive(g.Now()) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("project %s is frozen", projectName)
+	if !freeze.ExpiresAt.IsZero() {
+		msg += " until " + freeze.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	msg += ": " + freeze.Reason
+	st, err := status.New(codes.FailedPrecondition, msg).WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{
+			{
+				Type:        FreezeViolationType,
+				Subject:     projectName,
+				Description: freeze.Reason,
+			},
+		},
+	})
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, msg)
+	}
+	return st.Err()
+}
+
+// freezeServerStream checks the first message received on a stream
+type freezeServerStream struct {
+	grpc.ServerStream
+	guard   *FreezeGuard
+	checked bool
+}
+
+func (s *freezeServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.checked {
+		s.checked = true
+		return s.guard.Check(auditProjectName(m))
+	}
+	return nil
+}
+
+// FreezeRequest freezes a project, freeze is lifted automatically
+// after ExpiresAt if it is set. Freeze is recorded as made by the caller
+type FreezeRequest struct {
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// FreezeResponse is the freeze status of a project served over http
+type FreezeResponse struct {
+	ProjectName string     `json:"project_name"`
+	Frozen      bool       `json:"frozen"`
+	Reason      string     `json:"reason,omitempty"`
+	Actor       string     `json:"actor,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+// ServeHTTP lets admins manage freeze of a project identified by project
+// query param, GET returns the freeze status, PUT freezes the project
+// with request body as the caller and DELETE lifts the freeze
+func (g *FreezeGuard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := g.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req FreezeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, errors.Wrap(err, "invalid freeze").Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Reason == "" {
+			http.Error(w, "reason is required", http.StatusBadRequest)
+			return
+		}
+		freeze := models.ProjectFreeze{
+			Reason:    req.Reason,
+			Actor:     auditHTTPActor(r),
+			CreatedAt: g.Now(),
+		}
+		if req.ExpiresAt != nil {
+			if !req.ExpiresAt.After(g.Now()) {
+				http.Error(w, "expires_at should be in future", http.StatusBadRequest)
+				return
+			}
+			freeze.ExpiresAt = *req.ExpiresAt
+		}
+		if err := g.repo.Save(projSpec, freeze); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	case http.MethodDelete:
+		if err := g.repo.Delete(projSpec); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := FreezeResponse{
+		ProjectName: projSpec.Name,
+	}
+	freeze, err := g.repo.GetByProject(projSpec)
+	if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err == nil && freeze.IsActive(g.Now()) {
+		resp.Frozen = true
+		resp.Reason = freeze.Reason
+		resp.Actor = freeze.Actor
+		resp.CreatedAt = &freeze.CreatedAt
+		if !freeze.ExpiresAt.IsZero() {
+			resp.ExpiresAt = &freeze.ExpiresAt
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewFreezeGuard(repo store.ProjectFreezeRepository, projectRepoFactory ProjectRepoFactory) *FreezeGuard {
+	return &FreezeGuard{
+		repo:               repo,
+		projectRepoFactory: projectRepoFactory,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
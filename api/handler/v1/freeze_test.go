@@ -0,0 +1,197 @@
+package v1_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFreezeGuard(t *testing.T) {
+	logger.InitWithWriter("INFO", ioutil.Discard)
+	now := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	replayInfo := &grpc.UnaryServerInfo{
+		FullMethod: "/odpf.optimus.RuntimeService/Replay",
+	}
+	replayHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.ReplayResponse{Id: "replay-id"}, nil
+	}
+
+	t.Run("UnaryServerInterceptor", func(t *testing.T) {
+		t.Run("should reject calls to frozen projects with the reason", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			freezeRepo := new(mock.ProjectFreezeRepository)
+			freezeRepo.On("GetByProject", projectSpec).Return(models.ProjectFreeze{
+				Reason:    "incident INC-42",
+				ExpiresAt: now.Add(time.Hour),
+			}, nil)
+			defer freezeRepo.AssertExpectations(t)
+
+			guard := v1.NewFreezeGuard(freezeRepo, projectRepoFactory)
+			guard.Now = func() time.Time { return now }
+
+			_, err := guard.UnaryServerInterceptor()(context.Background(), &pb.ReplayRequest{ProjectName: projectSpec.Name},
+				replayInfo, replayHandler)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			assert.Equal(t, "project a-data-project is frozen until 2021-01-15T01:00:00Z: incident INC-42", status.Convert(err).Message())
+		})
+		t.Run("should allow calls once freeze is expired", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			freezeRepo := new(mock.ProjectFreezeRepository)
+			freezeRepo.On("GetByProject", projectSpec).Return(models.ProjectFreeze{
+				Reason:    "incident INC-42",
+				ExpiresAt: now.Add(-time.Minute),
+			}, nil)
+			defer freezeRepo.AssertExpectations(t)
+
+			guard := v1.NewFreezeGuard(freezeRepo, projectRepoFactory)
+			guard.Now = func() time.Time { return now }
+
+			resp, err := guard.UnaryServerInterceptor()(context.Background(), &pb.ReplayRequest{ProjectName: projectSpec.Name},
+				replayInfo, replayHandler)
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.ReplayResponse{Id: "replay-id"}, resp)
+		})
+		t.Run("should reject calls if freeze of the project can't be read", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			freezeRepo := new(mock.ProjectFreezeRepository)
+			freezeRepo.On("GetByProject", projectSpec).Return(models.ProjectFreeze{}, errors.New("connection refused"))
+			defer freezeRepo.AssertExpectations(t)
+
+			_, err := v1.NewFreezeGuard(freezeRepo, projectRepoFactory).UnaryServerInterceptor()(context.Background(),
+				&pb.ReplayRequest{ProjectName: projectSpec.Name}, replayInfo, replayHandler)
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+		})
+		t.Run("should reject calls if project can't be read and leave unknown projects to handlers", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(models.ProjectSpec{}, errors.New("connection refused"))
+			projectRepository.On("GetByName", "unknown-project").Return(models.ProjectSpec{}, store.ErrResourceNotFound)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			guard := v1.NewFreezeGuard(new(mock.ProjectFreezeRepository), projectRepoFactory)
+			_, err := guard.UnaryServerInterceptor()(context.Background(),
+				&pb.ReplayRequest{ProjectName: projectSpec.Name}, replayInfo, replayHandler)
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+
+			_, err = guard.UnaryServerInterceptor()(context.Background(),
+				&pb.ReplayRequest{ProjectName: "unknown-project"}, replayInfo, replayHandler)
+			assert.Nil(t, err)
+		})
+		t.Run("should allow reads of frozen projects", func(t *testing.T) {
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			defer projectRepoFactory.AssertExpectations(t)
+			freezeRepo := new(mock.ProjectFreezeRepository)
+			defer freezeRepo.AssertExpectations(t)
+
+			guard := v1.NewFreezeGuard(freezeRepo, projectRepoFactory)
+			resp, err := guard.UnaryServerInterceptor()(context.Background(), &pb.ReplayRequest{ProjectName: projectSpec.Name},
+				&grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/ReplayDryRun"}, replayHandler)
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.ReplayResponse{Id: "replay-id"}, resp)
+		})
+	})
+	t.Run("ServeHTTP", func(t *testing.T) {
+		t.Run("should freeze the project", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			freeze := models.ProjectFreeze{
+				Reason:    "release",
+				Actor:     "token/admin",
+				CreatedAt: now,
+			}
+			freezeRepo := new(mock.ProjectFreezeRepository)
+			freezeRepo.On("Save", projectSpec, freeze).Return(nil)
+			freezeRepo.On("GetByProject", projectSpec).Return(freeze, nil)
+			defer freezeRepo.AssertExpectations(t)
+
+			adminToken := models.APIToken{
+				Name:   "admin",
+				Scopes: []models.TokenScope{{Action: models.TokenActionAdmin, Project: models.TokenScopeAllProjects}},
+			}
+			tokenRepo := new(mock.APITokenRepository)
+			tokenRepo.On("GetByHash", mock2.AnythingOfType("string")).Return(adminToken, nil)
+			defer tokenRepo.AssertExpectations(t)
+
+			guard := v1.NewFreezeGuard(freezeRepo, projectRepoFactory)
+			guard.Now = func() time.Time { return now }
+			authenticator := v1.NewTokenAuthenticator(tokenRepo, false)
+			authenticator.Now = func() time.Time { return now }
+
+			// actor is the token freeze is made with, not the one sent in body
+			req := httptest.NewRequest(http.MethodPut, "/admin/freeze?project=a-data-project",
+				strings.NewReader(`{"reason": "release", "actor": "alice"}`))
+			req.Header.Set("Authorization", "Bearer opt_admin")
+			rec := httptest.NewRecorder()
+			authenticator.AdminHandler(guard).ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"project_name": "a-data-project", "frozen": true, "reason": "release",
+				"actor": "token/admin", "created_at": "2021-01-15T00:00:00Z"}`, rec.Body.String())
+		})
+		t.Run("should return not found for unknown projects", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", "unknown").Return(models.ProjectSpec{}, store.ErrResourceNotFound)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			guard := v1.NewFreezeGuard(nil, projectRepoFactory)
+			rec := httptest.NewRecorder()
+			guard.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/freeze?project=unknown", nil))
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		})
+	})
+}
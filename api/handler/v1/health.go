@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	healthCheckTimeout = time.Second * 5
+
+	HealthStatusOK     = "ok"
+	HealthStatusFailed = "failed"
+)
+
+// HealthCheck is a dependency server needs to serve requests
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthResponse is the status of server along with each of its checks
+type HealthResponse struct {
+	Status string              `json:"status"`
+	Checks []HealthCheckResult `json:"checks,omitempty"`
+}
+
+type HealthCheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessHandler serves ready status of server for load balancers and
+// orchestrators to route calls only to servers ready to serve them. All
+// checks run on every call, failing any of them responds with 503
+type ReadinessHandler struct {
+	checks []HealthCheck
+}
+
+func (h *ReadinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	resp := HealthResponse{Status: HealthStatusOK, Checks: make([]HealthCheckResult, len(h.checks))}
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			result := HealthCheckResult{Name: check.Name, Status: HealthStatusOK}
+			if err := check.Check(ctx); err != nil {
+				result.Status = HealthStatusFailed
+				result.Error = err.Error()
+			}
+			resp.Checks[i] = result
+		}(i, check)
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	for _, result := range resp.Checks {
+		if result.Status != HealthStatusOK {
+			resp.Status = HealthStatusFailed
+			status = http.StatusServiceUnavailable
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// LivenessHandler tells server process is alive, it doesn't check
+// dependencies so their outage doesn't get the server restarted
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(HealthResponse{Status: HealthStatusOK})
+	})
+}
+
+// DBHealthCheck checks database can be reached
+func DBHealthCheck(db interface {
+	PingContext(ctx context.Context) error
+}) HealthCheck {
+	return HealthCheck{
+		Name:  "db",
+		Check: db.PingContext,
+	}
+}
+
+// PluginHealthCheck checks task plugins are loaded, server without them
+// can't compile any job
+func PluginHealthCheck(pluginRepo models.PluginRepository) HealthCheck {
+	return HealthCheck{
+		Name: "plugins",
+		Check: func(ctx context.Context) error {
+			if len(pluginRepo.GetTasks()) == 0 {
+				return errors.New("no task plugins loaded")
+			}
+			return nil
+		},
+	}
+}
+
+// SchedulerHealthCheck checks schedulers of projects can be reached. As
+// projects may use schedulers of their own, it fails only if none of them
+// is reachable, one unreachable scheduler is an outage of its projects and
+// not of the server. Hosts shared by projects are checked once
+func SchedulerHealthCheck(scheduler models.SchedulerUnit, projectRepoFactory ProjectRepoFactory) HealthCheck {
+	return HealthCheck{
+		Name: "scheduler",
+		Check: func(ctx context.Context) error {
+			checker, ok := scheduler.(models.SchedulerHealthChecker)
+			if !ok {
+				return nil
+			}
+			projects, err := projectRepoFactory.New().GetAll()
+			if err != nil {
+				return err
+			}
+			hostProjects := map[string]models.ProjectSpec{}
+			for _, proj := range projects {
+				if host := proj.Config[models.ProjectSchedulerHost]; host != "" {
+					hostProjects[strings.Trim(host, "/")] = proj
+				}
+			}
+			if len(hostProjects) == 0 {
+				return nil
+			}
+
+			var failures []string
+			for _, proj := range hostProjects {
+				if err := checker.HealthCheck(ctx, proj); err != nil {
+					failures = append(failures, err.Error())
+					continue
+				}
+				return nil
+			}
+			sort.Strings(failures)
+			return errors.Errorf("no scheduler reachable: %s", strings.Join(failures, "; "))
+		},
+	}
+}
+
+func NewReadinessHandler(checks ...HealthCheck) *ReadinessHandler {
+	return &ReadinessHandler{
+		checks: checks,
+	}
+}
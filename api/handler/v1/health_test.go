@@ -0,0 +1,81 @@
+package v1_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	passing := v1.HealthCheck{Name: "db", Check: func(ctx context.Context) error { return nil }}
+	failing := v1.HealthCheck{Name: "plugins", Check: func(ctx context.Context) error { return errors.New("no task plugins loaded") }}
+
+	t.Run("should be ready when all checks pass", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v1.NewReadinessHandler(passing).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status": "ok", "checks": [{"name": "db", "status": "ok"}]}`, rec.Body.String())
+	})
+	t.Run("should not be ready when a check fails", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v1.NewReadinessHandler(passing, failing).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+		var resp v1.HealthResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.HealthResponse{
+			Status: v1.HealthStatusFailed,
+			Checks: []v1.HealthCheckResult{
+				{Name: "db", Status: v1.HealthStatusOK},
+				{Name: "plugins", Status: v1.HealthStatusFailed, Error: "no task plugins loaded"},
+			},
+		}, resp)
+	})
+	t.Run("PluginHealthCheck", func(t *testing.T) {
+		t.Run("should fail without task plugins", func(t *testing.T) {
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetTasks").Return([]*models.Plugin{})
+			defer pluginRepo.AssertExpectations(t)
+
+			assert.NotNil(t, v1.PluginHealthCheck(pluginRepo).Check(context.Background()))
+		})
+	})
+	t.Run("SchedulerHealthCheck", func(t *testing.T) {
+		projA := models.ProjectSpec{Name: "proj-a", Config: map[string]string{models.ProjectSchedulerHost: "http://airflow-a.io"}}
+		projB := models.ProjectSpec{Name: "proj-b", Config: map[string]string{models.ProjectSchedulerHost: "http://airflow-b.io/"}}
+		projC := models.ProjectSpec{Name: "proj-c", Config: map[string]string{models.ProjectSchedulerHost: "http://airflow-b.io"}}
+		newProjectRepoFactory := func() *mock.ProjectRepoFactory {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetAll").Return([]models.ProjectSpec{projA, projB, projC}, nil)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			return projectRepoFactory
+		}
+
+		t.Run("should pass when a scheduler is reachable", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("HealthCheck", mock2.Anything, projA).Return(errors.New("airflow-a is down")).Maybe()
+			scheduler.On("HealthCheck", mock2.Anything, mock2.Anything).Return(nil).Maybe()
+
+			err := v1.SchedulerHealthCheck(scheduler, newProjectRepoFactory()).Check(context.Background())
+			assert.Nil(t, err)
+		})
+		t.Run("should fail when no scheduler is reachable checking each host once", func(t *testing.T) {
+			scheduler := new(mock.Scheduler)
+			scheduler.On("HealthCheck", mock2.Anything, mock2.Anything).Return(errors.New("unreachable")).Twice()
+			defer scheduler.AssertExpectations(t)
+
+			err := v1.SchedulerHealthCheck(scheduler, newProjectRepoFactory()).Check(context.Background())
+			assert.Equal(t, "no scheduler reachable: unreachable; unreachable", err.Error())
+		})
+	})
+}
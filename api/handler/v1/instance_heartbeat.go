@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// InstanceHeartbeatRequest is a heartbeat of a task or hook of a job run
+// reported over http. State is running while the instance is alive, the
+// task reports success or failed once it exits
+type InstanceHeartbeatRequest struct {
+	ScheduledAt  time.Time `json:"scheduled_at"`
+	InstanceType string    `json:"instance_type"`
+	InstanceName string    `json:"instance_name"`
+	State        string    `json:"state"`
+}
+
+// InstanceHeartbeatHandler records heartbeats and terminal state of runs of
+// a job identified by project and job query params, reported by optimus exec
+// wrapping the task and hooks of the run. Terminal state reported by hooks is
+// not an outcome of the run and only counts as a heartbeat
+type InstanceHeartbeatHandler struct {
+	instSvc            models.InstanceService
+	jobSvc             models.JobService
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *InstanceHeartbeatHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	jobName := r.URL.Query().Get("job")
+	if projectName == "" || jobName == "" {
+		http.Error(w, "project and job are required", http.StatusBadRequest)
+		return
+	}
+	var req InstanceHeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid heartbeat: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.ScheduledAt.IsZero() {
+		http.Error(w, "scheduled_at is required", http.StatusBadRequest)
+		return
+	}
+	instanceType, err := models.InstanceType("").New(req.InstanceType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch req.State {
+	case models.InstanceStateRunning, models.InstanceStateSuccess, models.InstanceStateFailed:
+	default:
+		http.Error(w, "invalid state "+req.State+", expected running, success or failed", http.StatusBadRequest)
+		return
+	}
+
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobSpec, _, err := h.jobSvc.GetByNameForProject(jobName, projSpec)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "job "+jobName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if req.State != models.InstanceStateRunning && instanceType == models.InstanceTypeTask {
+		err = h.instSvc.UpdateState(jobSpec, req.ScheduledAt, req.State)
+	} else {
+		err = h.instSvc.Heartbeat(jobSpec, req.ScheduledAt)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func NewInstanceHeartbeatHandler(instSvc models.InstanceService, jobSvc models.JobService,
+	projectRepoFactory ProjectRepoFactory) *InstanceHeartbeatHandler {
+	return &InstanceHeartbeatHandler{
+		instSvc:            instSvc,
+		jobSvc:             jobSvc,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
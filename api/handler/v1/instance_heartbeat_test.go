@@ -0,0 +1,83 @@
+package v1_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceHeartbeatHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-job",
+	}
+	scheduledAt := time.Date(2021, 5, 13, 2, 0, 0, 0, time.UTC)
+	setup := func() (*mock.ProjectRepoFactory, *mock.JobService) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+		return projectRepoFactory, jobService
+	}
+	post := func(handler http.Handler, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/instance-heartbeats?project=a-data-project&job=a-job", strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("should record heartbeat of running instance", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		instanceService := new(mock.InstanceService)
+		instanceService.On("Heartbeat", jobSpec, scheduledAt).Return(nil)
+		defer instanceService.AssertExpectations(t)
+
+		rec := post(v1.NewInstanceHeartbeatHandler(instanceService, jobService, projectRepoFactory),
+			`{"scheduled_at": "2021-05-13T02:00:00Z", "instance_type": "task", "instance_name": "bq2bq", "state": "running"}`)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("should record terminal state reported by task as outcome of run", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		instanceService := new(mock.InstanceService)
+		instanceService.On("UpdateState", jobSpec, scheduledAt, models.InstanceStateFailed).Return(nil)
+		defer instanceService.AssertExpectations(t)
+
+		rec := post(v1.NewInstanceHeartbeatHandler(instanceService, jobService, projectRepoFactory),
+			`{"scheduled_at": "2021-05-13T02:00:00Z", "instance_type": "task", "instance_name": "bq2bq", "state": "failed"}`)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("should only record heartbeat for terminal state reported by hook", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		instanceService := new(mock.InstanceService)
+		instanceService.On("Heartbeat", jobSpec, scheduledAt).Return(nil)
+		defer instanceService.AssertExpectations(t)
+
+		rec := post(v1.NewInstanceHeartbeatHandler(instanceService, jobService, projectRepoFactory),
+			`{"scheduled_at": "2021-05-13T02:00:00Z", "instance_type": "hook", "instance_name": "transporter", "state": "success"}`)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+	t.Run("should reject heartbeat with invalid state", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		instanceService := new(mock.InstanceService)
+
+		rec := post(v1.NewInstanceHeartbeatHandler(instanceService, jobService, projectRepoFactory),
+			`{"scheduled_at": "2021-05-13T02:00:00Z", "instance_type": "task", "instance_name": "bq2bq", "state": "hung"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		instanceService.AssertNotCalled(t, "UpdateState")
+	})
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"runtime/debug"
+	"time"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpctags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	"github.com/odpf/optimus/core/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// tags added to logs of api calls with size of their payloads,
+	// streaming calls are tagged with the count of messages as well
+	tagRequestBytes  = "grpc.request.bytes"
+	tagResponseBytes = "grpc.response.bytes"
+	tagRequestMsgs   = "grpc.request.msgs"
+	tagResponseMsgs  = "grpc.response.msgs"
+)
+
+// RecoveryUnaryServerInterceptor fails the call with an internal error when
+// its handler panics instead of taking down the server, stack of the panic
+// is logged
+func RecoveryUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				resp, err = nil, recoverPanic(info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamServerInterceptor fails the streaming call with an internal
+// error when its handler panics
+func RecoveryStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recoverPanic(info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recoverPanic(fullMethod string, p interface{}) error {
+	logger.E(fmt.Sprintf("panic handling %s: %v\n%s", fullMethod, p, debug.Stack()))
+	return status.Errorf(codes.Internal, "internal error handling %s", path.Base(fullMethod))
+}
+
+// DeadlineUnaryServerInterceptor ends calls running longer than timeout,
+// clients setting a shorter deadline keep theirs. Zero timeout leaves calls
+// without a deadline
+func DeadlineUnaryServerInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// DeadlineStreamServerInterceptor ends streaming calls running longer than
+// timeout, it is kept separate from the one of unary calls as deployments
+// stream their progress for as long as they run
+func DeadlineStreamServerInterceptor(timeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if timeout <= 0 {
+			return handler(srv, ss)
+		}
+		ctx, cancel := context.WithTimeout(ss.Context(), timeout)
+		defer cancel()
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
+
+// PayloadUnaryServerInterceptor tags logs of calls with size of request
+// and response in bytes, limits on the size are enforced by grpc server
+// options
+func PayloadUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		tags := grpctags.Extract(ctx)
+		tags.Set(tagRequestBytes, payloadSize(req))
+		if err == nil {
+			tags.Set(tagResponseBytes, payloadSize(resp))
+		}
+		return resp, err
+	}
+}
+
+// PayloadStreamServerInterceptor tags logs of streaming calls with count
+// and total size of messages received and sent
+func PayloadStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		stream := &payloadServerStream{ServerStream: ss}
+		err := handler(srv, stream)
+		tags := grpctags.Extract(ss.Context())
+		tags.Set(tagRequestMsgs, stream.recvMsgs)
+		tags.Set(tagRequestBytes, stream.recvBytes)
+		tags.Set(tagResponseMsgs, stream.sentMsgs)
+		tags.Set(tagResponseBytes, stream.sentBytes)
+		return err
+	}
+}
+
+// payloadServerStream counts messages of a stream along with their size
+type payloadServerStream struct {
+	grpc.ServerStream
+	recvMsgs, sentMsgs   int
+	recvBytes, sentBytes int
+}
+
+func (s *payloadServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.recvMsgs++
+	s.recvBytes += payloadSize(m)
+	return nil
+}
+
+func (s *payloadServerStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.sentMsgs++
+	s.sentBytes += payloadSize(m)
+	return nil
+}
+
+func payloadSize(m interface{}) int {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return 0
+	}
+	return proto.Size(msg)
+}
@@ -0,0 +1,160 @@
+package v1_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	grpctags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// testServerStream is a server stream of a call which doesn't send
+// or receive messages
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) SendMsg(m interface{}) error {
+	return nil
+}
+
+func TestInterceptors(t *testing.T) {
+	logger.InitWithWriter("INFO", ioutil.Discard)
+	unaryInfo := &grpc.UnaryServerInfo{
+		FullMethod: "/odpf.optimus.RuntimeService/ListJobSpecification",
+	}
+	streamInfo := &grpc.StreamServerInfo{
+		FullMethod: "/odpf.optimus.RuntimeService/DeployJobSpecification",
+	}
+
+	t.Run("RecoveryUnaryServerInterceptor", func(t *testing.T) {
+		t.Run("should fail calls with internal error when handler panics", func(t *testing.T) {
+			resp, err := v1.RecoveryUnaryServerInterceptor()(context.Background(), &pb.ListJobSpecificationRequest{}, unaryInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					panic("nil map")
+				})
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.Internal, status.Code(err))
+			assert.Equal(t, "internal error handling ListJobSpecification", status.Convert(err).Message())
+		})
+		t.Run("should return response of handler", func(t *testing.T) {
+			resp, err := v1.RecoveryUnaryServerInterceptor()(context.Background(), &pb.ListJobSpecificationRequest{}, unaryInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return &pb.ListJobSpecificationResponse{}, nil
+				})
+			assert.Nil(t, err)
+			assert.Equal(t, &pb.ListJobSpecificationResponse{}, resp)
+		})
+	})
+	t.Run("RecoveryStreamServerInterceptor", func(t *testing.T) {
+		t.Run("should fail calls with internal error when handler panics", func(t *testing.T) {
+			err := v1.RecoveryStreamServerInterceptor()(nil, &testServerStream{ctx: context.Background()}, streamInfo,
+				func(srv interface{}, stream grpc.ServerStream) error {
+					panic("nil map")
+				})
+			assert.Equal(t, codes.Internal, status.Code(err))
+		})
+	})
+	t.Run("DeadlineUnaryServerInterceptor", func(t *testing.T) {
+		t.Run("should set deadline of calls without one", func(t *testing.T) {
+			_, err := v1.DeadlineUnaryServerInterceptor(time.Minute)(context.Background(), nil, unaryInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					deadline, ok := ctx.Deadline()
+					assert.True(t, ok)
+					assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+					return nil, nil
+				})
+			assert.Nil(t, err)
+		})
+		t.Run("should keep shorter deadline set by client", func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			clientDeadline, _ := ctx.Deadline()
+			_, err := v1.DeadlineUnaryServerInterceptor(time.Minute)(ctx, nil, unaryInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					deadline, _ := ctx.Deadline()
+					assert.Equal(t, clientDeadline, deadline)
+					return nil, nil
+				})
+			assert.Nil(t, err)
+		})
+		t.Run("should leave calls without deadline for zero timeout", func(t *testing.T) {
+			_, err := v1.DeadlineUnaryServerInterceptor(0)(context.Background(), nil, unaryInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					_, ok := ctx.Deadline()
+					assert.False(t, ok)
+					return nil, nil
+				})
+			assert.Nil(t, err)
+		})
+	})
+	t.Run("DeadlineStreamServerInterceptor", func(t *testing.T) {
+		t.Run("should set deadline of streaming calls", func(t *testing.T) {
+			err := v1.DeadlineStreamServerInterceptor(time.Hour)(nil, &testServerStream{ctx: context.Background()}, streamInfo,
+				func(srv interface{}, stream grpc.ServerStream) error {
+					deadline, ok := stream.Context().Deadline()
+					assert.True(t, ok)
+					assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Second)
+					return nil
+				})
+			assert.Nil(t, err)
+		})
+	})
+	t.Run("PayloadUnaryServerInterceptor", func(t *testing.T) {
+		t.Run("should tag calls with size of request and response", func(t *testing.T) {
+			req := &pb.ListJobSpecificationRequest{ProjectName: "a-data-project", Namespace: "game_jam"}
+			resp := &pb.ListJobSpecificationResponse{Jobs: []*pb.JobSpecification{{Name: "job-a"}}}
+			var tags grpctags.Tags
+			_, err := grpctags.UnaryServerInterceptor()(context.Background(), req, unaryInfo,
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					tags = grpctags.Extract(ctx)
+					return v1.PayloadUnaryServerInterceptor()(ctx, req, unaryInfo,
+						func(ctx context.Context, req interface{}) (interface{}, error) {
+							return resp, nil
+						})
+				})
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]interface{}{
+				"grpc.request.bytes":  proto.Size(req),
+				"grpc.response.bytes": proto.Size(resp),
+			}, tags.Values())
+		})
+	})
+	t.Run("PayloadStreamServerInterceptor", func(t *testing.T) {
+		t.Run("should tag calls with count and size of messages", func(t *testing.T) {
+			msg := &pb.DeployJobSpecificationResponse{Success: true, Message: "deployed job-a"}
+			var tags grpctags.Tags
+			err := grpctags.StreamServerInterceptor()(nil, &testServerStream{ctx: context.Background()}, streamInfo,
+				func(srv interface{}, stream grpc.ServerStream) error {
+					tags = grpctags.Extract(stream.Context())
+					return v1.PayloadStreamServerInterceptor()(srv, stream, streamInfo,
+						func(srv interface{}, stream grpc.ServerStream) error {
+							assert.Nil(t, stream.SendMsg(msg))
+							assert.Nil(t, stream.SendMsg(msg))
+							return nil
+						})
+				})
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]interface{}{
+				"grpc.request.msgs":   0,
+				"grpc.request.bytes":  0,
+				"grpc.response.msgs":  2,
+				"grpc.response.bytes": 2 * proto.Size(msg),
+			}, tags.Values())
+		})
+	})
+}
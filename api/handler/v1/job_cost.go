@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// JobCostResponse is cost of runs of a job served over http, summed over
+// the runs which reported datastore jobs
+type JobCostResponse struct {
+	JobName     string    `json:"job_name"`
+	Namespace   string    `json:"namespace"`
+	Since       time.Time `json:"since"`
+	Runs        int       `json:"runs"`
+	BytesBilled int64     `json:"bytes_billed"`
+	SlotMillis  int64     `json:"slot_millis"`
+}
+
+// ProjectCostResponse is cost of runs of all jobs of a project served over
+// http, jobs are sorted by bytes billed, most expensive first
+type ProjectCostResponse struct {
+	ProjectName string            `json:"project_name"`
+	Since       time.Time         `json:"since"`
+	Runs        int               `json:"runs"`
+	BytesBilled int64             `json:"bytes_billed"`
+	SlotMillis  int64             `json:"slot_millis"`
+	Jobs        []JobCostResponse `json:"jobs"`
+}
+
+// JobCostHandler serves cost of runs scheduled within the duration in since
+// query param e.g. 30d or 12h, of the job in job query param or of every
+// job of the project in project query param when job is left out
+type JobCostHandler struct {
+	instSvc              models.InstanceService
+	jobSvc               models.JobService
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+}
+
+func (h *JobCostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	since := defaultJobStatsSince
+	if sinceParam := r.URL.Query().Get("since"); sinceParam != "" {
+		var err error
+		if since, err = parseJobStatsSince(sinceParam); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	sinceTime := time.Now().UTC().Add(-since)
+
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var resp interface{}
+	if jobName := r.URL.Query().Get("job"); jobName != "" {
+		jobSpec, namespaceSpec, err := h.jobSvc.GetByNameForProject(jobName, projSpec)
+		if err != nil {
+			if errors.Is(err, store.ErrResourceNotFound) {
+				http.Error(w, "job "+jobName+" not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if resp, err = h.jobCost(jobSpec, namespaceSpec, sinceTime); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else if resp, err = h.projectCost(projSpec, sinceTime); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *JobCostHandler) jobCost(jobSpec models.JobSpec, namespaceSpec models.NamespaceSpec, since time.Time) (JobCostResponse, error) {
+	stats, err := h.instSvc.GetCostStats(jobSpec, since)
+	if err != nil {
+		return JobCostResponse{}, err
+	}
+	return JobCostResponse{
+		JobName:     jobSpec.Name,
+		Namespace:   namespaceSpec.Name,
+		Since:       stats.Since,
+		Runs:        stats.Runs,
+		BytesBilled: stats.BytesBilled,
+		SlotMillis:  stats.SlotMillis,
+	}, nil
+}
+
+func (h *JobCostHandler) projectCost(projSpec models.ProjectSpec, since time.Time) (ProjectCostResponse, error) {
+	namespaces, err := h.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return ProjectCostResponse{}, err
+	}
+	resp := ProjectCostResponse{
+		ProjectName: projSpec.Name,
+		Since:       since,
+		Jobs:        []JobCostResponse{},
+	}
+	for _, namespace := range namespaces {
+		jobSpecs, err := h.jobSvc.GetAll(namespace)
+		if err != nil {
+			return ProjectCostResponse{}, errors.Wrapf(err, "failed to read jobs of %s", namespace.Name)
+		}
+		for _, jobSpec := range jobSpecs {
+			jobCost, err := h.jobCost(jobSpec, namespace, since)
+			if err != nil {
+				return ProjectCostResponse{}, err
+			}
+			resp.Runs += jobCost.Runs
+			resp.BytesBilled += jobCost.BytesBilled
+			resp.SlotMillis += jobCost.SlotMillis
+			resp.Jobs = append(resp.Jobs, jobCost)
+		}
+	}
+	sort.SliceStable(resp.Jobs, func(i, j int) bool {
+		if resp.Jobs[i].BytesBilled != resp.Jobs[j].BytesBilled {
+			return resp.Jobs[i].BytesBilled > resp.Jobs[j].BytesBilled
+		}
+		return resp.Jobs[i].JobName < resp.Jobs[j].JobName
+	})
+	return resp, nil
+}
+
+func NewJobCostHandler(instSvc models.InstanceService, jobSvc models.JobService,
+	projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory) *JobCostHandler {
+	return &JobCostHandler{
+		instSvc:              instSvc,
+		jobSvc:               jobSvc,
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+	}
+}
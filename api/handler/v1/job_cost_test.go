@@ -0,0 +1,103 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestJobCostHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	cheapJob := models.JobSpec{ID: uuid.Must(uuid.NewRandom()), Name: "cheap-job"}
+	costlyJob := models.JobSpec{ID: uuid.Must(uuid.NewRandom()), Name: "costly-job"}
+	since := time.Date(2021, 5, 13, 2, 0, 0, 0, time.UTC)
+	sinceWeekAgo := mock2.MatchedBy(func(t time.Time) bool {
+		return time.Since(t).Round(time.Hour) == time.Hour*24*7
+	})
+	setup := func() (*mock.ProjectRepoFactory, *mock.NamespaceRepoFactory, *mock.InstanceService) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		namespaceRepository := new(mock.NamespaceRepository)
+		namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+		namespaceRepoFactory.On("New", projectSpec).Return(namespaceRepository)
+
+		instanceService := new(mock.InstanceService)
+		instanceService.On("GetCostStats", cheapJob, sinceWeekAgo).Return(models.JobCostStats{
+			Since: since, Runs: 7, BytesBilled: 1000, SlotMillis: 300}, nil)
+		instanceService.On("GetCostStats", costlyJob, sinceWeekAgo).Return(models.JobCostStats{
+			Since: since, Runs: 6, BytesBilled: 5000, SlotMillis: 200}, nil)
+		return projectRepoFactory, namespaceRepoFactory, instanceService
+	}
+
+	t.Run("should serve cost of runs of the job", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, instanceService := setup()
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", costlyJob.Name, projectSpec).Return(costlyJob, namespaceSpec, nil)
+		defer jobService.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewJobCostHandler(instanceService, jobService, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/job-costs?project=a-data-project&job=costly-job&since=7d", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.JobCostResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.JobCostResponse{
+			JobName:     "costly-job",
+			Namespace:   "a-namespace",
+			Since:       since,
+			Runs:        6,
+			BytesBilled: 5000,
+			SlotMillis:  200,
+		}, resp)
+	})
+	t.Run("should serve cost of all jobs of the project most expensive first", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, instanceService := setup()
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{cheapJob, costlyJob}, nil)
+		defer jobService.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewJobCostHandler(instanceService, jobService, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/job-costs?project=a-data-project&since=7d", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ProjectCostResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, "a-data-project", resp.ProjectName)
+		assert.Equal(t, 13, resp.Runs)
+		assert.Equal(t, int64(6000), resp.BytesBilled)
+		assert.Equal(t, int64(500), resp.SlotMillis)
+		assert.Len(t, resp.Jobs, 2)
+		assert.Equal(t, "costly-job", resp.Jobs[0].JobName)
+		assert.Equal(t, "cheap-job", resp.Jobs[1].JobName)
+	})
+	t.Run("should not serve cost without project", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, instanceService := setup()
+
+		rec := httptest.NewRecorder()
+		v1.NewJobCostHandler(instanceService, new(mock.JobService), projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/job-costs?job=costly-job", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// JobDependencyResponse is a dependency of a job along with why it's resolved
+type JobDependencyResponse struct {
+	Job         string   `json:"job,omitempty"`
+	Project     string   `json:"project,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Reason      string   `json:"reason"`
+	Destination string   `json:"destination,omitempty"`
+	Assets      []string `json:"assets,omitempty"`
+	Explanation string   `json:"explanation"`
+}
+
+// JobDependenciesResponse lists dependencies of a job served over http
+type JobDependenciesResponse struct {
+	JobName      string                  `json:"job_name"`
+	Namespace    string                  `json:"namespace"`
+	Dependencies []JobDependencyResponse `json:"dependencies"`
+}
+
+// JobDependenciesHandler serves dependencies of a job identified by project
+// and job query params, each with the reason it's resolved, to debug why a
+// sensor of the job exists or is missing
+type JobDependenciesHandler struct {
+	jobSvc             models.JobService
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *JobDependenciesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	jobName := r.URL.Query().Get("job")
+	if projectName == "" || jobName == "" {
+		http.Error(w, "project and job are required", http.StatusBadRequest)
+		return
+	}
+
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobSpec, namespaceSpec, err := h.jobSvc.GetByNameForProject(jobName, projSpec)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "job "+jobName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	namespaceSpec.ProjectSpec = projSpec
+
+	explanations, err := h.jobSvc.ExplainDependencies(namespaceSpec, jobSpec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := JobDependenciesResponse{
+		JobName:      jobSpec.Name,
+		Namespace:    namespaceSpec.Name,
+		Dependencies: []JobDependencyResponse{},
+	}
+	for _, explanation := range explanations {
+		resp.Dependencies = append(resp.Dependencies, JobDependencyResponse{
+			Job:         explanation.Job,
+			Project:     explanation.Project,
+			Type:        explanation.Type.String(),
+			Reason:      string(explanation.Reason),
+			Destination: explanation.Destination,
+			Assets:      explanation.Assets,
+			Explanation: explanation.String(),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// NewJobDependenciesHandler creates a handler explaining dependencies of jobs
+func NewJobDependenciesHandler(jobSvc models.JobService, projectRepoFactory ProjectRepoFactory) *JobDependenciesHandler {
+	return &JobDependenciesHandler{
+		jobSvc:             jobSvc,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,112 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobDependenciesHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "namespace-a",
+		ProjectSpec: projectSpec,
+	}
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-job",
+	}
+	setup := func() (*mock.ProjectRepoFactory, *mock.JobService) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+		jobService.On("GetByNameForProject", "unknown-job", projectSpec).Return(models.JobSpec{}, models.NamespaceSpec{},
+			errors.Wrap(store.ErrResourceNotFound, "failed to retrieve job"))
+		return projectRepoFactory, jobService
+	}
+
+	t.Run("should serve dependencies of job with why they are resolved", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		jobService.On("ExplainDependencies", namespaceSpec, jobSpec).Return([]models.JobSpecDependencyExplanation{
+			{
+				Job:     "declared-job",
+				Project: projectSpec.Name,
+				Type:    models.JobSpecDependencyTypeIntra,
+				Reason:  models.JobSpecDependencyReasonDeclared,
+			},
+			{
+				Job:         "upstream-job",
+				Project:     "b-data-project",
+				Type:        models.JobSpecDependencyTypeInter,
+				Reason:      models.JobSpecDependencyReasonInferred,
+				Destination: "b-data-project:dataset.table",
+				Assets:      []string{"query.sql"},
+			},
+			{
+				Reason:      models.JobSpecDependencyReasonUnresolved,
+				Destination: "external:dataset.table",
+			},
+		}, nil)
+		defer jobService.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewJobDependenciesHandler(jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/job-dependencies?project=a-data-project&job=a-job", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.JobDependenciesResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.JobDependenciesResponse{
+			JobName:   "a-job",
+			Namespace: "namespace-a",
+			Dependencies: []v1.JobDependencyResponse{
+				{
+					Job:         "declared-job",
+					Project:     "a-data-project",
+					Type:        "intra",
+					Reason:      "declared",
+					Explanation: "declared in job spec",
+				},
+				{
+					Job:         "upstream-job",
+					Project:     "b-data-project",
+					Type:        "inter",
+					Reason:      "inferred",
+					Destination: "b-data-project:dataset.table",
+					Assets:      []string{"query.sql"},
+					Explanation: "inferred from destination b-data-project:dataset.table read by query.sql, written by job of project b-data-project",
+				},
+				{
+					Reason:      "unresolved",
+					Destination: "external:dataset.table",
+					Explanation: "source external:dataset.table is not written by any job, no sensor waits for it",
+				},
+			},
+		}, resp)
+	})
+	t.Run("should not serve dependencies of unknown job", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+
+		rec := httptest.NewRecorder()
+		v1.NewJobDependenciesHandler(jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/job-dependencies?project=a-data-project&job=unknown-job", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
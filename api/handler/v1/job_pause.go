@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// JobPauseResponse lists jobs whose scheduling was paused or resumed
+type JobPauseResponse struct {
+	Paused bool             `json:"paused"`
+	Jobs   []JobPauseResult `json:"jobs"`
+}
+
+type JobPauseResult struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// JobPauseHandler pauses or resumes scheduling of jobs of a namespace on POST.
+// project and namespace query params are required along with jobs to pause,
+// either by repeated job params or a label selector, see models.ParseLabelSelector.
+// paused defaults to true, false resumes the jobs. Failing to pause a job
+// doesn't stop others from being paused, it's reported in its result
+type JobPauseHandler struct {
+	jobSvc               models.JobService
+	scheduler            models.SchedulerUnit
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+}
+
+func (h *JobPauseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	projectName, namespace := query.Get("project"), query.Get("namespace")
+	if projectName == "" || namespace == "" {
+		http.Error(w, "project and namespace are required", http.StatusBadRequest)
+		return
+	}
+	selector, err := models.ParseLabelSelector(query.Get("selector"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	jobNames := query["job"]
+	if selector.Empty() && len(jobNames) == 0 {
+		http.Error(w, "jobs to pause are required, either by job or selector", http.StatusBadRequest)
+		return
+	}
+	paused := true
+	if val := query.Get("paused"); val != "" {
+		if paused, err = strconv.ParseBool(val); err != nil {
+			http.Error(w, "invalid paused, expected true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	namespaceSpec, err := h.namespaceRepoFactory.New(projSpec).GetByName(namespace)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "namespace "+namespace+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobSpecs, err := h.jobSvc.GetAll(namespaceSpec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	requested := map[string]bool{}
+	for _, name := range jobNames {
+		requested[name] = true
+	}
+	var selected []string
+	for _, jobSpec := range jobSpecs {
+		if len(requested) > 0 && !requested[jobSpec.Name] {
+			continue
+		}
+		if selector.Matches(jobSpec.Labels) {
+			selected = append(selected, jobSpec.Name)
+		}
+		delete(requested, jobSpec.Name)
+	}
+	for name := range requested {
+		http.Error(w, "job "+name+" not found in namespace "+namespace, http.StatusNotFound)
+		return
+	}
+	sort.Strings(selected)
+
+	resp := JobPauseResponse{Paused: paused, Jobs: []JobPauseResult{}}
+	for _, name := range selected {
+		result := JobPauseResult{Name: name}
+		if err := h.scheduler.SetJobPaused(r.Context(), projSpec, name, paused); err != nil {
+			result.Error = err.Error()
+		}
+		resp.Jobs = append(resp.Jobs, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewJobPauseHandler(jobSvc models.JobService, scheduler models.SchedulerUnit, projectRepoFactory ProjectRepoFactory,
+	namespaceRepoFactory NamespaceRepoFactory) *JobPauseHandler {
+	return &JobPauseHandler{
+		jobSvc:               jobSvc,
+		scheduler:            scheduler,
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+	}
+}
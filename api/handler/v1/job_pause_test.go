@@ -0,0 +1,92 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestJobPauseHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "game_jam",
+		ProjectSpec: projectSpec,
+	}
+	jobSpecs := []models.JobSpec{
+		{Name: "job-b", Labels: map[string]string{"tier": "critical"}},
+		{Name: "job-a", Labels: map[string]string{"tier": "critical"}},
+		{Name: "job-c", Labels: map[string]string{"tier": "low"}},
+	}
+	newHandler := func(scheduler *mock.Scheduler) *v1.JobPauseHandler {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		namespaceRepository := new(mock.NamespaceRepository)
+		namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+		namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+		namespaceRepoFactory.On("New", projectSpec).Return(namespaceRepository)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return(jobSpecs, nil)
+		return v1.NewJobPauseHandler(jobService, scheduler, projectRepoFactory, namespaceRepoFactory)
+	}
+
+	t.Run("should pause jobs matching label selector", func(t *testing.T) {
+		scheduler := new(mock.Scheduler)
+		scheduler.On("SetJobPaused", mock2.Anything, projectSpec, "job-a", true).Return(nil)
+		scheduler.On("SetJobPaused", mock2.Anything, projectSpec, "job-b", true).Return(errors.New("dag not found"))
+		defer scheduler.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		newHandler(scheduler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-pause?project=a-data-project&namespace=game_jam&selector=tier%3Dcritical", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.JobPauseResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.JobPauseResponse{
+			Paused: true,
+			Jobs: []v1.JobPauseResult{
+				{Name: "job-a"},
+				{Name: "job-b", Error: "dag not found"},
+			},
+		}, resp)
+	})
+	t.Run("should resume requested jobs", func(t *testing.T) {
+		scheduler := new(mock.Scheduler)
+		scheduler.On("SetJobPaused", mock2.Anything, projectSpec, "job-c", false).Return(nil)
+		defer scheduler.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		newHandler(scheduler).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-pause?project=a-data-project&namespace=game_jam&job=job-c&paused=false", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("should fail for jobs not in namespace", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newHandler(new(mock.Scheduler)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-pause?project=a-data-project&namespace=game_jam&job=unknown", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("should require jobs to pause", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newHandler(new(mock.Scheduler)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-pause?project=a-data-project&namespace=game_jam", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/status"
+)
+
+// JobRenameResponse lists jobs whose dependencies on the renamed job were
+// rewritten
+type JobRenameResponse struct {
+	Job        string   `json:"job"`
+	Name       string   `json:"name"`
+	Dependents []string `json:"dependents"`
+}
+
+// JobRenameHandler renames a job of a namespace on POST. project, namespace,
+// job and the new name query params are required, the new name should follow
+// naming policy of the project. Static dependencies of jobs on the job are
+// rewritten to the new name and the affected namespaces are synced with the
+// scheduler. Jobs of frozen projects are not renamed
+type JobRenameHandler struct {
+	jobSvc               models.JobService
+	freezeChecker        FreezeChecker
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+}
+
+func (h *JobRenameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	projectName, namespace := query.Get("project"), query.Get("namespace")
+	oldName, newName := query.Get("job"), query.Get("name")
+	if projectName == "" || namespace == "" || oldName == "" || newName == "" {
+		http.Error(w, "project, namespace, job and name are required", http.StatusBadRequest)
+		return
+	}
+	if oldName == newName {
+		http.Error(w, "name should be different from the job name", http.StatusBadRequest)
+		return
+	}
+
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if h.freezeChecker != nil {
+		if err := h.freezeChecker.Check(projSpec.Name); err != nil {
+			http.Error(w, status.Convert(err).Message(), freezeStatusCode(err))
+			return
+		}
+	}
+	namespaceSpec, err := h.namespaceRepoFactory.New(projSpec).GetByName(namespace)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "namespace "+namespace+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	dependents, err := h.jobSvc.Rename(r.Context(), namespaceSpec, oldName, newName, nil)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrResourceNotFound):
+			http.Error(w, "job "+oldName+" not found in namespace "+namespace, http.StatusNotFound)
+		case errors.Is(err, job.ErrJobExists), errors.Is(err, job.ErrJobLocked):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, models.ErrNamingViolation):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if dependents == nil {
+		dependents = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(JobRenameResponse{
+		Job:        oldName,
+		Name:       newName,
+		Dependents: dependents,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewJobRenameHandler(jobSvc models.JobService, freezeChecker FreezeChecker, projectRepoFactory ProjectRepoFactory,
+	namespaceRepoFactory NamespaceRepoFactory) *JobRenameHandler {
+	return &JobRenameHandler{
+		jobSvc:               jobSvc,
+		freezeChecker:        freezeChecker,
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+	}
+}
@@ -0,0 +1,117 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestJobRenameHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "game_jam",
+		ProjectSpec: projectSpec,
+	}
+	newHandler := func(jobService *mock.JobService, freezeRepo *mock.ProjectFreezeRepository) *v1.JobRenameHandler {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		namespaceRepository := new(mock.NamespaceRepository)
+		namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+		namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+		namespaceRepoFactory.On("New", projectSpec).Return(namespaceRepository)
+
+		var freezeChecker v1.FreezeChecker
+		if freezeRepo != nil {
+			freezeChecker = v1.NewFreezeGuard(freezeRepo, projectRepoFactory)
+		}
+		return v1.NewJobRenameHandler(jobService, freezeChecker, projectRepoFactory, namespaceRepoFactory)
+	}
+
+	t.Run("should rename job and list jobs depending on it", func(t *testing.T) {
+		jobService := new(mock.JobService)
+		jobService.On("Rename", mock2.Anything, namespaceSpec, "job-a", "job-b", nil).
+			Return([]string{"job-c"}, nil)
+		defer jobService.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		newHandler(jobService, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-rename?project=a-data-project&namespace=game_jam&job=job-a&name=job-b", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.JobRenameResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.JobRenameResponse{
+			Job:        "job-a",
+			Name:       "job-b",
+			Dependents: []string{"job-c"},
+		}, resp)
+	})
+	t.Run("should fail with conflict if a job has the new name", func(t *testing.T) {
+		jobService := new(mock.JobService)
+		jobService.On("Rename", mock2.Anything, namespaceSpec, "job-a", "job-b", nil).
+			Return([]string(nil), errors.Wrap(job.ErrJobExists, "job job-b already exists"))
+
+		rec := httptest.NewRecorder()
+		newHandler(jobService, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-rename?project=a-data-project&namespace=game_jam&job=job-a&name=job-b", nil))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+	t.Run("should fail for jobs not in namespace", func(t *testing.T) {
+		jobService := new(mock.JobService)
+		jobService.On("Rename", mock2.Anything, namespaceSpec, "unknown", "job-b", nil).
+			Return([]string(nil), store.ErrResourceNotFound)
+
+		rec := httptest.NewRecorder()
+		newHandler(jobService, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-rename?project=a-data-project&namespace=game_jam&job=unknown&name=job-b", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("should fail with bad request if the new name violates naming policy", func(t *testing.T) {
+		jobService := new(mock.JobService)
+		jobService.On("Rename", mock2.Anything, namespaceSpec, "job-a", "JobB", nil).
+			Return([]string(nil), errors.Wrap(models.ErrNamingViolation, "job JobB doesn't match NAMING_JOB [a-z-]+"))
+
+		rec := httptest.NewRecorder()
+		newHandler(jobService, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-rename?project=a-data-project&namespace=game_jam&job=job-a&name=JobB", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("should not rename jobs of frozen projects", func(t *testing.T) {
+		freezeRepo := new(mock.ProjectFreezeRepository)
+		freezeRepo.On("GetByProject", projectSpec).Return(models.ProjectFreeze{Reason: "release"}, nil)
+		defer freezeRepo.AssertExpectations(t)
+
+		jobService := new(mock.JobService)
+		defer jobService.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		newHandler(jobService, freezeRepo).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-rename?project=a-data-project&namespace=game_jam&job=job-a&name=job-b", nil))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "project a-data-project is frozen: release\n", rec.Body.String())
+	})
+	t.Run("should require the new name", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		newHandler(new(mock.JobService), nil).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/job-rename?project=a-data-project&namespace=game_jam&job=job-a", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// runs of the last 30 days are summarized when since is not provided
+	defaultJobStatsSince = time.Hour * 24 * 30
+)
+
+// JobStatsResponse is a summary of runs of a job served over http, durations
+// are of successful runs
+type JobStatsResponse struct {
+	JobName              string    `json:"job_name"`
+	Since                time.Time `json:"since"`
+	Runs                 int       `json:"runs"`
+	Succeeded            int       `json:"succeeded"`
+	Failed               int       `json:"failed"`
+	Hung                 int       `json:"hung"`
+	SuccessRate          float64   `json:"success_rate"`
+	AverageDuration      string    `json:"average_duration"`
+	P50Duration          string    `json:"p50_duration"`
+	P95Duration          string    `json:"p95_duration"`
+	LongestFailureStreak int       `json:"longest_failure_streak"`
+	CurrentFailureStreak int       `json:"current_failure_streak"`
+	// MostFailingHour is the hour of day(UTC) most runs failed at, nil if
+	// no run failed
+	MostFailingHour *int `json:"most_failing_hour"`
+}
+
+// JobStatsHandler serves a summary of runs of a job identified by project and
+// job query params, scheduled within the duration in since query param
+// e.g. 30d or 12h
+type JobStatsHandler struct {
+	instSvc            models.InstanceService
+	jobSvc             models.JobService
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *JobStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	jobName := r.URL.Query().Get("job")
+	if projectName == "" || jobName == "" {
+		http.Error(w, "project and job are required", http.StatusBadRequest)
+		return
+	}
+	since := defaultJobStatsSince
+	if sinceParam := r.URL.Query().Get("since"); sinceParam != "" {
+		var err error
+		if since, err = parseJobStatsSince(sinceParam); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobSpec, _, err := h.jobSvc.GetByNameForProject(jobName, projSpec)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "job "+jobName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stats, err := h.instSvc.GetRunStats(jobSpec, time.Now().UTC().Add(-since))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp := JobStatsResponse{
+		JobName:              jobSpec.Name,
+		Since:                stats.Since,
+		Runs:                 stats.Runs,
+		Succeeded:            stats.Succeeded,
+		Failed:               stats.Failed,
+		Hung:                 stats.Hung,
+		SuccessRate:          stats.SuccessRate(),
+		AverageDuration:      stats.AverageDuration.String(),
+		P50Duration:          stats.P50Duration.String(),
+		P95Duration:          stats.P95Duration.String(),
+		LongestFailureStreak: stats.LongestFailureStreak,
+		CurrentFailureStreak: stats.CurrentFailureStreak,
+	}
+	if hour, ok := stats.MostFailingHour(); ok {
+		resp.MostFailingHour = &hour
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// parseJobStatsSince parses a positive duration, in days when suffixed
+// with d
+func parseJobStatsSince(since string) (time.Duration, error) {
+	var (
+		duration time.Duration
+		err      error
+	)
+	if strings.HasSuffix(since, "d") {
+		var days int
+		days, err = strconv.Atoi(strings.TrimSuffix(since, "d"))
+		duration = time.Hour * 24 * time.Duration(days)
+	} else {
+		duration, err = time.ParseDuration(since)
+	}
+	if err != nil || duration <= 0 {
+		return 0, errors.Errorf("invalid since %s, expected a duration like 30d or 12h", since)
+	}
+	return duration, nil
+}
+
+func NewJobStatsHandler(instSvc models.InstanceService, jobSvc models.JobService,
+	projectRepoFactory ProjectRepoFactory) *JobStatsHandler {
+	return &JobStatsHandler{
+		instSvc:            instSvc,
+		jobSvc:             jobSvc,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
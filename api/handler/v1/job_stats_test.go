@@ -0,0 +1,90 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestJobStatsHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-job",
+	}
+	setup := func() (*mock.ProjectRepoFactory, *mock.JobService) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+		return projectRepoFactory, jobService
+	}
+
+	t.Run("should summarize runs of job scheduled since the duration", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		defer jobService.AssertExpectations(t)
+
+		since := time.Date(2021, 5, 13, 2, 0, 0, 0, time.UTC)
+		instanceService := new(mock.InstanceService)
+		instanceService.On("GetRunStats", jobSpec, mock2.MatchedBy(func(t time.Time) bool {
+			return time.Since(t).Round(time.Hour) == time.Hour*24*7
+		})).Return(models.JobRunStats{
+			Since:                since,
+			Runs:                 8,
+			Succeeded:            6,
+			Failed:               2,
+			AverageDuration:      time.Minute * 10,
+			P50Duration:          time.Minute * 9,
+			P95Duration:          time.Minute * 15,
+			LongestFailureStreak: 2,
+			FailuresByHour:       map[int]int{2: 2},
+		}, nil)
+		defer instanceService.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewJobStatsHandler(instanceService, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/job-stats?project=a-data-project&job=a-job&since=7d", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.JobStatsResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		mostFailingHour := 2
+		assert.Equal(t, v1.JobStatsResponse{
+			JobName:              "a-job",
+			Since:                since,
+			Runs:                 8,
+			Succeeded:            6,
+			Failed:               2,
+			SuccessRate:          0.75,
+			AverageDuration:      "10m0s",
+			P50Duration:          "9m0s",
+			P95Duration:          "15m0s",
+			LongestFailureStreak: 2,
+			MostFailingHour:      &mostFailingHour,
+		}, resp)
+	})
+	t.Run("should not summarize runs for invalid since", func(t *testing.T) {
+		projectRepoFactory, jobService := setup()
+		instanceService := new(mock.InstanceService)
+
+		rec := httptest.NewRecorder()
+		v1.NewJobStatsHandler(instanceService, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/job-stats?project=a-data-project&job=a-job&since=-2d", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
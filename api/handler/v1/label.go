@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataLabelSelector set in request metadata of job listing returns only
+// jobs matching the selector, see models.ParseLabelSelector. In job deployment
+// it limits the deployment to jobs matching the selector, jobs in request
+// should all match it and jobs of namespace not matching it are left as they
+// are instead of being deleted. In replay only dependents of job matching it
+// are replayed
+const MetadataLabelSelector = "x-label-selector"
+
+func labelSelector(ctx context.Context) (models.LabelSelector, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	vals := md.Get(MetadataLabelSelector)
+	if len(vals) == 0 {
+		return nil, nil
+	}
+	selector, err := models.ParseLabelSelector(vals[0])
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return selector, nil
+}
+
+// unselectedJobs returns jobs not matching the selector, the ones a
+// deployment limited to selector should keep as is
+func unselectedJobs(selector models.LabelSelector, jobSpecs []models.JobSpec) []models.JobSpec {
+	if selector.Empty() {
+		return nil
+	}
+	var unselected []models.JobSpec
+	for _, jobSpec := range jobSpecs {
+		if !selector.Matches(jobSpec.Labels) {
+			unselected = append(unselected, jobSpec)
+		}
+	}
+	return unselected
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// fetching logs should finish within write timeout of http server
+	instanceLogTimeout = time.Second * 8
+	logRunsBatchSize   = 100
+)
+
+// InstanceLogResponse is the log of a task or hook of a job run served over http
+type InstanceLogResponse struct {
+	JobName      string    `json:"job_name"`
+	ScheduledAt  time.Time `json:"scheduled_at"`
+	InstanceType string    `json:"instance_type"`
+	InstanceName string    `json:"instance_name"`
+	Attempt      int       `json:"attempt"`
+	Content      string    `json:"content"`
+}
+
+// LogHandler serves logs of job runs proxied from the scheduler. project, job and
+// scheduled_at query params are required, scheduled_at is either the RFC3339 time
+// of run or a date to pick the latest run of the day(UTC). instance_type defaults
+// to task, instance defaults to the task of job and attempt to the latest one
+type LogHandler struct {
+	jobSvc             models.JobService
+	scheduler          models.SchedulerUnit
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *LogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	projectName, jobName := query.Get("project"), query.Get("job")
+	if projectName == "" || jobName == "" {
+		http.Error(w, "project and job are required", http.StatusBadRequest)
+		return
+	}
+	scheduledAt, err := time.Parse(time.RFC3339, query.Get("scheduled_at"))
+	runDate, dateErr := time.Parse(models.JobDatetimeLayout, query.Get("scheduled_at"))
+	if err != nil && dateErr != nil {
+		http.Error(w, "invalid scheduled_at, expected RFC3339 time or YYYY-MM-DD date", http.StatusBadRequest)
+		return
+	}
+	instanceType := models.InstanceTypeTask
+	if val := query.Get("instance_type"); val != "" {
+		if instanceType, err = models.InstanceType("").New(val); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	attempt := 0
+	if val := query.Get("attempt"); val != "" {
+		if attempt, err = strconv.Atoi(val); err != nil || attempt < 0 {
+			http.Error(w, "invalid attempt", http.StatusBadRequest)
+			return
+		}
+	}
+
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobSpec, _, err := h.jobSvc.GetByNameForProject(jobName, projSpec)
+	if err != nil {
+		http.Error(w, errors.Wrapf(err, "job %s not found", jobName).Error(), http.StatusNotFound)
+		return
+	}
+
+	instanceName := query.Get("instance")
+	switch instanceType {
+	case models.InstanceTypeTask:
+		if instanceName == "" {
+			instanceName = jobSpec.Task.Unit.Info().Name
+		}
+	case models.InstanceTypeHook:
+		if _, err := jobSpec.GetHookByName(instanceName); err != nil {
+			http.Error(w, errors.Wrapf(err, "hook %s of job %s", instanceName, jobName).Error(), http.StatusNotFound)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), instanceLogTimeout)
+	defer cancel()
+	if dateErr == nil {
+		if scheduledAt, err = h.latestRunOfDay(ctx, projSpec, jobSpec.Name, runDate); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	}
+	instanceLog, err := h.scheduler.GetInstanceLog(ctx, projSpec, jobSpec.Name, scheduledAt, instanceType, instanceName, attempt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(InstanceLogResponse{
+		JobName:      instanceLog.JobName,
+		ScheduledAt:  instanceLog.ScheduledAt,
+		InstanceType: string(instanceLog.InstanceType),
+		InstanceName: instanceLog.InstanceName,
+		Attempt:      instanceLog.Attempt,
+		Content:      instanceLog.Content,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *LogHandler) latestRunOfDay(ctx context.Context, projSpec models.ProjectSpec, jobName string,
+	day time.Time) (time.Time, error) {
+	runs, err := h.scheduler.GetDagRunStatus(ctx, projSpec, jobName, day, day.Add(time.Hour*24-time.Second), logRunsBatchSize)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var latest time.Time
+	for _, run := range runs {
+		if run.ScheduledAt.After(latest) {
+			latest = run.ScheduledAt
+		}
+	}
+	if latest.IsZero() {
+		return time.Time{}, errors.Errorf("no run of job %s on %s", jobName, day.Format(models.JobDatetimeLayout))
+	}
+	return latest, nil
+}
+
+func NewLogHandler(jobSvc models.JobService, scheduler models.SchedulerUnit, projectRepoFactory ProjectRepoFactory) *LogHandler {
+	return &LogHandler{
+		jobSvc:             jobSvc,
+		scheduler:          scheduler,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,129 @@
+package v1_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestLogHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	scheduledAt := time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC)
+
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name: "bq2bq",
+	}, nil)
+	jobSpec := models.JobSpec{
+		Name: "a-job",
+		Task: models.JobSpecTask{
+			Unit: &models.Plugin{Base: execUnit},
+		},
+	}
+
+	t.Run("should serve log of the task of a job run", func(t *testing.T) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		defer projectRepository.AssertExpectations(t)
+
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		defer projectRepoFactory.AssertExpectations(t)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+		defer jobService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		scheduler.On("GetInstanceLog", mock2.Anything, projectSpec, jobSpec.Name, scheduledAt, models.InstanceTypeTask, "bq2bq", 0).
+			Return(models.InstanceLog{
+				JobName:      jobSpec.Name,
+				ScheduledAt:  scheduledAt,
+				InstanceType: models.InstanceTypeTask,
+				InstanceName: "bq2bq",
+				Attempt:      1,
+				Content:      "query executed",
+			}, nil)
+		defer scheduler.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewLogHandler(jobService, scheduler, projectRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/logs?project=a-data-project&job=a-job&scheduled_at=2021-05-20T02:00:00Z", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"job_name": "a-job", "scheduled_at": "2021-05-20T02:00:00Z", "instance_type": "task",
+			"instance_name": "bq2bq", "attempt": 1, "content": "query executed"}`, rec.Body.String())
+	})
+	t.Run("should serve log of the latest run of a date", func(t *testing.T) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		defer projectRepository.AssertExpectations(t)
+
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		defer projectRepoFactory.AssertExpectations(t)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+		defer jobService.AssertExpectations(t)
+
+		day := time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC)
+		laterRun := time.Date(2021, 5, 20, 14, 0, 0, 0, time.UTC)
+		scheduler := new(mock.Scheduler)
+		scheduler.On("GetDagRunStatus", mock2.Anything, projectSpec, jobSpec.Name, day, day.Add(time.Hour*24-time.Second), 100).
+			Return([]models.JobStatus{
+				{ScheduledAt: scheduledAt, State: models.JobStatusStateSuccess},
+				{ScheduledAt: laterRun, State: models.JobStatusStateFailed},
+			}, nil)
+		scheduler.On("GetInstanceLog", mock2.Anything, projectSpec, jobSpec.Name, laterRun, models.InstanceTypeTask, "bq2bq", 2).
+			Return(models.InstanceLog{
+				JobName:      jobSpec.Name,
+				ScheduledAt:  laterRun,
+				InstanceType: models.InstanceTypeTask,
+				InstanceName: "bq2bq",
+				Attempt:      2,
+				Content:      "query failed",
+			}, nil)
+		defer scheduler.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewLogHandler(jobService, scheduler, projectRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/logs?project=a-data-project&job=a-job&scheduled_at=2021-05-20&attempt=2", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "query failed")
+	})
+	t.Run("should return not found for unknown hooks of job", func(t *testing.T) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		defer projectRepository.AssertExpectations(t)
+
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		defer projectRepoFactory.AssertExpectations(t)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+		defer jobService.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewLogHandler(jobService, nil, projectRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/logs?project=a-data-project&job=a-job&scheduled_at=2021-05-20T02:00:00Z&instance_type=hook&instance=transporter", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("should reject invalid run time", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v1.NewLogHandler(nil, nil, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/logs?project=a-data-project&job=a-job&scheduled_at=20-05-2021", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,313 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	grpctags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// queryDurationBuckets are upper bounds in seconds of buckets of the
+// histogram of queries
+var queryDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+const (
+	// tagProject and tagNamespace tag logs of api calls with the same
+	// labels their metrics are recorded with
+	tagProject   = "project"
+	tagNamespace = "namespace"
+)
+
+// Metrics counts api calls, runs and events of jobs by project and namespace.
+// Counts are kept in memory of the server since it started, they are served
+// by a prometheus registry and back the project stats. Events are counted by
+// job too, labelled with labels of job known from its runs registered
+type Metrics struct {
+	registry *prometheus.Registry
+	handler  http.Handler
+
+	requests  *prometheus.SummaryVec
+	reclaimed *prometheus.CounterVec
+	dropped   *prometheus.CounterVec
+	queries   *prometheus.HistogramVec
+	lookups   *prometheus.CounterVec
+
+	mu        sync.Mutex
+	runs      map[string]int64
+	jobEvents map[jobEventMetricKey]int64
+	jobLabels map[jobMetricKey]map[string]string
+
+	Since time.Time
+}
+
+type jobEventMetricKey struct {
+	project   string
+	namespace string
+	job       string
+	eventType string
+}
+
+type jobMetricKey struct {
+	project   string
+	namespace string
+	job       string
+}
+
+var (
+	jobRunsDesc = prometheus.NewDesc("optimus_job_runs_total",
+		"Runs of jobs started.", []string{"project"}, nil)
+	jobEventsDesc = prometheus.NewDesc("optimus_job_events_total",
+		"Events raised by runs of jobs, e.g. failure or sla_miss.", []string{"project", "namespace", "type"}, nil)
+)
+
+const (
+	jobEventsByJobName = "optimus_job_events_by_job_total"
+	jobEventsByJobHelp = "Events raised by runs of jobs by job, labelled with labels of job prefixed by label_."
+)
+
+// UnaryServerInterceptor records unary calls after they are handled and
+// tags logs of the call with project and namespace of the request
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.record(info.FullMethod, req, resp, err, time.Since(start))
+		tagRequest(grpctags.Extract(ctx), req)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records streaming calls once the stream is closed,
+// labels are taken from the first message received from client
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		wrapped := &auditServerStream{ServerStream: ss}
+		err := handler(srv, wrapped)
+		m.record(info.FullMethod, wrapped.req, nil, err, time.Since(start))
+		tagRequest(grpctags.Extract(ss.Context()), wrapped.req)
+		return err
+	}
+}
+
+// tagRequest tags logs of a call with project and namespace of the request,
+// they are set after the call is handled as streams only know them from the
+// first message received
+func tagRequest(tags grpctags.Tags, req interface{}) {
+	if project := auditProjectName(req); project != "" {
+		tags.Set(tagProject, project)
+	}
+	if namespace := requestNamespace(req); namespace != "" {
+		tags.Set(tagNamespace, namespace)
+	}
+}
+
+func (m *Metrics) record(fullMethod string, req, resp interface{}, err error, elapsed time.Duration) {
+	project := auditProjectName(req)
+	namespace := requestNamespace(req)
+	m.requests.WithLabelValues(path.Base(fullMethod), project, namespace, status.Code(err).String()).
+		Observe(elapsed.Seconds())
+
+	if err != nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch r := req.(type) {
+	case *pb.RegisterInstanceRequest:
+		// every run of a job registers its task instance
+		if r.GetInstanceType() == pb.InstanceSpec_TASK {
+			m.runs[project]++
+		}
+		if instance, ok := resp.(*pb.RegisterInstanceResponse); ok && instance.GetJob() != nil {
+			m.jobLabels[jobMetricKey{
+				project:   project,
+				namespace: instance.GetNamespace().GetName(),
+				job:       instance.GetJob().GetName(),
+			}] = instance.GetJob().GetLabels()
+		}
+	case *pb.RegisterJobEventRequest:
+		m.jobEvents[jobEventMetricKey{
+			project:   project,
+			namespace: namespace,
+			job:       r.GetJobName(),
+			eventType: strings.ToLower(r.GetEvent().GetType().String()),
+		}]++
+	}
+}
+
+// RecordReclaimed counts expired rows of a project deleted from a table
+func (m *Metrics) RecordReclaimed(projectName, table string, rows int64) {
+	m.reclaimed.WithLabelValues(projectName, table).Add(float64(rows))
+}
+
+// RecordPartitionsDropped counts expired partitions dropped from destination
+// of a job as per its retention
+func (m *Metrics) RecordPartitionsDropped(projectName, jobName string, partitions int) {
+	m.dropped.WithLabelValues(projectName, jobName).Add(float64(partitions))
+}
+
+// RecordQuery adds time taken by a query of a repository to the histogram
+// of queries, see postgres.Instrument
+func (m *Metrics) RecordQuery(repository, operation string, elapsed time.Duration) {
+	m.queries.WithLabelValues(repository, operation).Observe(elapsed.Seconds())
+}
+
+// RecordCacheLookup counts lookups of a cache of specifications by whether
+// they were hits, see postgres.SpecCache
+func (m *Metrics) RecordCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.lookups.WithLabelValues(cache, result).Inc()
+}
+
+// JobStats returns runs of jobs of project and events of its jobs by
+// namespace and event type
+func (m *Metrics) JobStats(projectName string) (int64, map[string]map[string]int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := map[string]map[string]int64{}
+	for key, count := range m.jobEvents {
+		if key.project != projectName {
+			continue
+		}
+		if _, ok := events[key.namespace]; !ok {
+			events[key.namespace] = map[string]int64{}
+		}
+		events[key.namespace][key.eventType] += count
+	}
+	return m.runs[projectName], events
+}
+
+// ServeHTTP writes metrics gathered from the registry in prometheus text
+// format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m.handler.ServeHTTP(w, r)
+}
+
+// jobCollector collects runs and events of jobs kept for the project stats.
+// Events by job carry labels of the job which differ between jobs, so the
+// collector describes nothing and is registered as unchecked
+type jobCollector struct {
+	metrics *Metrics
+}
+
+func (c jobCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (c jobCollector) Collect(ch chan<- prometheus.Metric) {
+	m := c.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for project, count := range m.runs {
+		ch <- prometheus.MustNewConstMetric(jobRunsDesc, prometheus.CounterValue, float64(count), project)
+	}
+
+	namespaceEvents := map[jobEventMetricKey]int64{}
+	for key, count := range m.jobEvents {
+		namespaceEvents[jobEventMetricKey{project: key.project, namespace: key.namespace, eventType: key.eventType}] += count
+	}
+	for key, count := range namespaceEvents {
+		ch <- prometheus.MustNewConstMetric(jobEventsDesc, prometheus.CounterValue, float64(count),
+			key.project, key.namespace, key.eventType)
+	}
+
+	for key, count := range m.jobEvents {
+		names := []string{"project", "namespace", "job", "type"}
+		values := []string{key.project, key.namespace, key.job, key.eventType}
+		labelNames, labelValues := jobLabelPairs(m.jobLabels[jobMetricKey{project: key.project, namespace: key.namespace, job: key.job}])
+		desc := prometheus.NewDesc(jobEventsByJobName, jobEventsByJobHelp, append(names, labelNames...), nil)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(count), append(values, labelValues...)...)
+	}
+}
+
+// jobLabelPairs returns names and values of labels of job as prometheus
+// labels, names are prefixed with label_ and characters not allowed in them
+// replaced, the first of labels sharing a name after replacing wins
+func jobLabelPairs(labels map[string]string) ([]string, []string) {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	names := make([]string, 0, len(labels))
+	values := make([]string, 0, len(labels))
+	seen := map[string]bool{}
+	for _, k := range keys {
+		name := "label_" + strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+				return r
+			}
+			return '_'
+		}, k)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+		values = append(values, labels[k])
+	}
+	return names, values
+}
+
+func requestNamespace(req interface{}) string {
+	switch r := req.(type) {
+	case interface{ GetNamespace() string }:
+		return r.GetNamespace()
+	case interface {
+		GetNamespace() *pb.NamespaceSpecification
+	}:
+		return r.GetNamespace().GetName()
+	}
+	return ""
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Name:       "optimus_grpc_request_duration_seconds",
+			Help:       "Time taken by api calls.",
+			Objectives: map[float64]float64{},
+		}, []string{"method", "project", "namespace", "code"}),
+		reclaimed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "optimus_retention_deleted_rows_total",
+			Help: "Expired rows deleted as per retention of projects.",
+		}, []string{"project", "table"}),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "optimus_retention_dropped_partitions_total",
+			Help: "Expired partitions dropped from destinations as per retention of jobs.",
+		}, []string{"project", "job"}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "optimus_db_query_duration_seconds",
+			Help:    "Time taken by queries of repositories.",
+			Buckets: queryDurationBuckets,
+		}, []string{"repository", "operation"}),
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "optimus_spec_cache_lookups_total",
+			Help: "Lookups of cached projects and job specifications by result, hit or miss.",
+		}, []string{"cache", "result"}),
+		runs:      map[string]int64{},
+		jobEvents: map[jobEventMetricKey]int64{},
+		jobLabels: map[jobMetricKey]map[string]string{},
+		Since:     time.Now().UTC(),
+	}
+	m.registry.MustRegister(m.requests, m.reclaimed, m.dropped, m.queries, m.lookups, jobCollector{metrics: m})
+	m.handler = promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	return m
+}
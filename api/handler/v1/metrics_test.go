@@ -0,0 +1,273 @@
+package v1_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	grpctags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetrics(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "game_jam",
+		ProjectSpec: projectSpec,
+	}
+	okHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	failHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "job not found")
+	}
+	registerRun := func(metrics *v1.Metrics) {
+		_, _ = metrics.UnaryServerInterceptor()(context.Background(), &pb.RegisterInstanceRequest{
+			ProjectName:  projectSpec.Name,
+			JobName:      "job-1",
+			InstanceType: pb.InstanceSpec_TASK,
+		}, &grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/RegisterInstance"}, okHandler)
+	}
+	registerFailure := func(metrics *v1.Metrics) {
+		_, _ = metrics.UnaryServerInterceptor()(context.Background(), &pb.RegisterJobEventRequest{
+			ProjectName: projectSpec.Name,
+			Namespace:   namespaceSpec.Name,
+			JobName:     "job-1",
+			Event:       &pb.JobEvent{Type: pb.JobEvent_FAILURE},
+		}, &grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/RegisterJobEvent"}, okHandler)
+	}
+
+	t.Run("UnaryServerInterceptor", func(t *testing.T) {
+		tagged := func(metrics *v1.Metrics, req interface{}) map[string]interface{} {
+			var tags grpctags.Tags
+			_, _ = grpctags.UnaryServerInterceptor()(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/Call"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					tags = grpctags.Extract(ctx)
+					return metrics.UnaryServerInterceptor()(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/Call"}, okHandler)
+				})
+			return tags.Values()
+		}
+		t.Run("should tag logs of calls with project and namespace labels of metrics", func(t *testing.T) {
+			tags := tagged(v1.NewMetrics(), &pb.DeployJobSpecificationRequest{ProjectName: projectSpec.Name, Namespace: namespaceSpec.Name})
+			assert.Equal(t, projectSpec.Name, tags["project"])
+			assert.Equal(t, namespaceSpec.Name, tags["namespace"])
+		})
+		t.Run("should tag namespace of registered namespace", func(t *testing.T) {
+			tags := tagged(v1.NewMetrics(), &pb.RegisterProjectNamespaceRequest{
+				ProjectName: projectSpec.Name,
+				Namespace:   &pb.NamespaceSpecification{Name: namespaceSpec.Name},
+			})
+			assert.Equal(t, namespaceSpec.Name, tags["namespace"])
+		})
+		t.Run("should not tag calls without project", func(t *testing.T) {
+			tags := tagged(v1.NewMetrics(), &pb.VersionRequest{})
+			assert.NotContains(t, tags, "project")
+			assert.NotContains(t, tags, "namespace")
+		})
+	})
+	t.Run("ServeHTTP", func(t *testing.T) {
+		t.Run("should serve calls, runs and events labelled by project", func(t *testing.T) {
+			metrics := v1.NewMetrics()
+			registerRun(metrics)
+			registerFailure(metrics)
+			_, _ = metrics.UnaryServerInterceptor()(context.Background(), &pb.ReadJobSpecificationRequest{
+				ProjectName: projectSpec.Name,
+				Namespace:   namespaceSpec.Name,
+				JobName:     "job-2",
+			}, &grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/ReadJobSpecification"}, failHandler)
+
+			rec := httptest.NewRecorder()
+			metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			body := rec.Body.String()
+			assert.Contains(t, body, `optimus_grpc_request_duration_seconds_count{code="NotFound",method="ReadJobSpecification",namespace="game_jam",project="a-data-project"} 1`)
+			assert.Contains(t, body, `optimus_grpc_request_duration_seconds_count{code="OK",method="RegisterInstance",namespace="",project="a-data-project"} 1`)
+			assert.Contains(t, body, `optimus_job_runs_total{project="a-data-project"} 1`)
+			assert.Contains(t, body, `optimus_job_events_total{namespace="game_jam",project="a-data-project",type="failure"} 1`)
+		})
+		t.Run("should serve events of jobs labelled with labels of job", func(t *testing.T) {
+			metrics := v1.NewMetrics()
+			_, _ = metrics.UnaryServerInterceptor()(context.Background(), &pb.RegisterInstanceRequest{
+				ProjectName:  projectSpec.Name,
+				JobName:      "job-1",
+				InstanceType: pb.InstanceSpec_TASK,
+			}, &grpc.UnaryServerInfo{FullMethod: "/odpf.optimus.RuntimeService/RegisterInstance"},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					return &pb.RegisterInstanceResponse{
+						Namespace: &pb.NamespaceSpecification{Name: namespaceSpec.Name},
+						Job: &pb.JobSpecification{
+							Name:   "job-1",
+							Labels: map[string]string{"tier": "critical", "cost-center": "ads"},
+						},
+					}, nil
+				})
+			registerFailure(metrics)
+			registerFailure(metrics)
+
+			rec := httptest.NewRecorder()
+			metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			body := rec.Body.String()
+			assert.Contains(t, body, `optimus_job_events_total{namespace="game_jam",project="a-data-project",type="failure"} 2`)
+			assert.Contains(t, body, `optimus_job_events_by_job_total{job="job-1",label_cost_center="ads",label_tier="critical",namespace="game_jam",project="a-data-project",type="failure"} 2`)
+		})
+		t.Run("should serve expired rows deleted by project and table", func(t *testing.T) {
+			metrics := v1.NewMetrics()
+			metrics.RecordReclaimed(projectSpec.Name, "instance", 1000)
+			metrics.RecordReclaimed(projectSpec.Name, "instance", 20)
+
+			rec := httptest.NewRecorder()
+			metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			assert.Contains(t, rec.Body.String(), `optimus_retention_deleted_rows_total{project="a-data-project",table="instance"} 1020`)
+		})
+		t.Run("should serve expired partitions dropped by project and job", func(t *testing.T) {
+			metrics := v1.NewMetrics()
+			metrics.RecordPartitionsDropped(projectSpec.Name, "job-1", 3)
+			metrics.RecordPartitionsDropped(projectSpec.Name, "job-1", 1)
+
+			rec := httptest.NewRecorder()
+			metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			assert.Contains(t, rec.Body.String(), `optimus_retention_dropped_partitions_total{job="job-1",project="a-data-project"} 4`)
+		})
+		t.Run("should serve histogram of queries by repository and operation", func(t *testing.T) {
+			metrics := v1.NewMetrics()
+			metrics.RecordQuery("job_spec", "query", time.Millisecond*30)
+			metrics.RecordQuery("job_spec", "query", time.Millisecond*300)
+			metrics.RecordQuery("job_spec", "update", time.Second*20)
+
+			rec := httptest.NewRecorder()
+			metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			body := rec.Body.String()
+			assert.Contains(t, body, "# TYPE optimus_db_query_duration_seconds histogram")
+			assert.Contains(t, body, `optimus_db_query_duration_seconds_bucket{operation="query",repository="job_spec",le="0.025"} 0`)
+			assert.Contains(t, body, `optimus_db_query_duration_seconds_bucket{operation="query",repository="job_spec",le="0.05"} 1`)
+			assert.Contains(t, body, `optimus_db_query_duration_seconds_bucket{operation="query",repository="job_spec",le="0.5"} 2`)
+			assert.Contains(t, body, `optimus_db_query_duration_seconds_count{operation="query",repository="job_spec"} 2`)
+			assert.Contains(t, body, `optimus_db_query_duration_seconds_bucket{operation="update",repository="job_spec",le="10"} 0`)
+			assert.Contains(t, body, `optimus_db_query_duration_seconds_bucket{operation="update",repository="job_spec",le="+Inf"} 1`)
+		})
+		t.Run("should serve lookups of spec caches by result", func(t *testing.T) {
+			metrics := v1.NewMetrics()
+			metrics.RecordCacheLookup("project", true)
+			metrics.RecordCacheLookup("project", true)
+			metrics.RecordCacheLookup("project", false)
+
+			rec := httptest.NewRecorder()
+			metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			body := rec.Body.String()
+			assert.Contains(t, body, `optimus_spec_cache_lookups_total{cache="project",result="hit"} 2`)
+			assert.Contains(t, body, `optimus_spec_cache_lookups_total{cache="project",result="miss"} 1`)
+		})
+	})
+	t.Run("StatsHandler", func(t *testing.T) {
+		t.Run("should serve job counts, failure rate and replay runs of project", func(t *testing.T) {
+			metrics := v1.NewMetrics()
+			registerRun(metrics)
+			registerRun(metrics)
+			registerFailure(metrics)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+			defer namespaceRepository.AssertExpectations(t)
+			namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+			namespaceRepoFactory.On("New", projectSpec).Return(namespaceRepository)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{{Name: "job-1"}, {Name: "job-2"}}, nil)
+			defer jobService.AssertExpectations(t)
+
+			quotaRepo := new(mock.ProjectQuotaRepository)
+			quotaRepo.On("GetReplayRuns", projectSpec, mock2.Anything).Return(4, nil)
+			defer quotaRepo.AssertExpectations(t)
+
+			handler := v1.NewStatsHandler(metrics, jobService, quotaRepo, nil, projectRepoFactory, namespaceRepoFactory)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?project=a-data-project", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var resp v1.StatsResponse
+			assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, 2, resp.Jobs)
+			assert.Equal(t, int64(2), resp.Runs)
+			assert.Equal(t, int64(1), resp.FailedRuns)
+			assert.Equal(t, 0.5, resp.FailureRate)
+			assert.Equal(t, 4, resp.ReplayRunsToday)
+			assert.Equal(t, []v1.NamespaceStats{{Name: "game_jam", Jobs: 2, FailedRuns: 1}}, resp.Namespaces)
+		})
+		t.Run("should serve spend of project against its monthly budget", func(t *testing.T) {
+			budgetedProject := models.ProjectSpec{
+				ID:     projectSpec.ID,
+				Name:   projectSpec.Name,
+				Config: map[string]string{models.ProjectCostBudgetKey: "1KiB"},
+			}
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(budgetedProject, nil)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{}, nil)
+			namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+			namespaceRepoFactory.On("New", budgetedProject).Return(namespaceRepository)
+			quotaRepo := new(mock.ProjectQuotaRepository)
+			quotaRepo.On("GetReplayRuns", budgetedProject, mock2.Anything).Return(0, nil)
+
+			costRepo := new(mock.ProjectCostRepository)
+			costRepo.On("GetBytesBilled", budgetedProject, mock2.Anything).Return(int64(870), nil)
+			defer costRepo.AssertExpectations(t)
+			budgetMonitor := job.NewCostBudgetMonitor(costRepo, nil)
+			budgetMonitor.Now = func() time.Time {
+				return time.Date(2021, 6, 20, 10, 0, 0, 0, time.UTC)
+			}
+
+			handler := v1.NewStatsHandler(v1.NewMetrics(), new(mock.JobService), quotaRepo, budgetMonitor,
+				projectRepoFactory, namespaceRepoFactory)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?project=a-data-project", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var resp v1.StatsResponse
+			assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, &v1.CostBudgetResponse{
+				Month:             "2021-06",
+				BudgetBytes:       1024,
+				SpentBytes:        870,
+				Percent:           84.9609375,
+				ThresholdsReached: []int{50, 80},
+			}, resp.Budget)
+		})
+		t.Run("should fail for unknown project", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", "unknown").Return(models.ProjectSpec{}, store.ErrResourceNotFound)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+
+			handler := v1.NewStatsHandler(v1.NewMetrics(), nil, nil, nil, projectRepoFactory, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats?project=unknown", nil))
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		})
+	})
+}
@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	operationDefaultLimit = 20
+)
+
+// OperationCanceller stops running operations
+type OperationCanceller interface {
+	Cancel(models.ProjectSpec, uuid.UUID) error
+}
+
+// OperationResponse is a long running operation served over http
+type OperationResponse struct {
+	ID       string                   `json:"id"`
+	Kind     string                   `json:"kind"`
+	Actor    string                   `json:"actor,omitempty"`
+	Params   json.RawMessage          `json:"params,omitempty"`
+	Result   json.RawMessage          `json:"result,omitempty"`
+	Progress models.OperationProgress `json:"progress"`
+	Status   string                   `json:"status"`
+	Message  string                   `json:"message,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsDone checks if operation is over, whatever the outcome
+func (o OperationResponse) IsDone() bool {
+	return models.Operation{Status: o.Status}.IsDone()
+}
+
+func toOperationResponse(op models.Operation) OperationResponse {
+	return OperationResponse{
+		ID:        op.ID.String(),
+		Kind:      op.Kind,
+		Actor:     op.Actor,
+		Params:    op.Params,
+		Result:    op.Result,
+		Progress:  op.Progress,
+		Status:    op.Status,
+		Message:   op.Message,
+		CreatedAt: op.CreatedAt,
+		UpdatedAt: op.UpdatedAt,
+	}
+}
+
+// writeOperationAccepted responds to requests which started an operation
+// with the operation and where its status can be followed
+func writeOperationAccepted(w http.ResponseWriter, projSpec models.ProjectSpec, op models.Operation) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/operations?project=%s&id=%s", projSpec.Name, op.ID))
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(toOperationResponse(op)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// OperationHandler serves long running operations of a project identified
+// by project query param. GET with id query param returns the operation,
+// without it latest operations are listed filtered by optional kind and
+// limit query params. DELETE with id query param cancels the operation
+type OperationHandler struct {
+	canceller          OperationCanceller
+	repo               store.OperationRepository
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *OperationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	projectName := query.Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	var id uuid.UUID
+	if val := query.Get("id"); val != "" || r.Method == http.MethodDelete {
+		var err error
+		if id, err = uuid.Parse(val); err != nil {
+			http.Error(w, "valid operation id is required", http.StatusBadRequest)
+			return
+		}
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var resp interface{}
+	switch r.Method {
+	case http.MethodGet:
+		if id == uuid.Nil {
+			limit := operationDefaultLimit
+			if val := query.Get("limit"); val != "" {
+				if limit, err = strconv.Atoi(val); err != nil || limit <= 0 {
+					http.Error(w, "invalid limit", http.StatusBadRequest)
+					return
+				}
+			}
+			ops, err := h.repo.GetLatest(projSpec, query.Get("kind"), limit)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			opsResp := []OperationResponse{}
+			for _, op := range ops {
+				opsResp = append(opsResp, toOperationResponse(op))
+			}
+			resp = opsResp
+			break
+		}
+		op, err := h.repo.GetByID(projSpec, id)
+		if err != nil {
+			if errors.Is(err, store.ErrResourceNotFound) {
+				http.Error(w, "operation "+id.String()+" not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp = toOperationResponse(op)
+	case http.MethodDelete:
+		if err := h.canceller.Cancel(projSpec, id); err != nil {
+			switch {
+			case errors.Is(err, store.ErrResourceNotFound):
+				http.Error(w, "operation "+id.String()+" not found", http.StatusNotFound)
+			case errors.Is(err, operation.ErrNotRunning):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewOperationHandler(canceller OperationCanceller, repo store.OperationRepository,
+	projectRepoFactory ProjectRepoFactory) *OperationHandler {
+	return &OperationHandler{
+		canceller:          canceller,
+		repo:               repo,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,97 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	projectRepository := new(mock.ProjectRepository)
+	projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+	projectRepoFactory := new(mock.ProjectRepoFactory)
+	projectRepoFactory.On("New").Return(projectRepository)
+	operationID := uuid.Must(uuid.NewRandom())
+	op := models.Operation{
+		ID:       operationID,
+		Kind:     models.OperationKindPromotion,
+		Params:   []byte(`{"datastore":"bigquery"}`),
+		Result:   []byte(`[{"source":"prod-project.playground"}]`),
+		Progress: models.OperationProgress{Done: 1, Total: 2},
+		Status:   models.OperationStatusFailed,
+		Message:  "permission denied",
+	}
+
+	t.Run("should serve operation by id", func(t *testing.T) {
+		repo := new(mock.OperationRepository)
+		repo.On("GetByID", projectSpec, operationID).Return(op, nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewOperationHandler(new(mock.OperationCanceller), repo, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/operations?project=a-data-project&id="+operationID.String(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.OperationResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.True(t, resp.IsDone())
+		assert.Equal(t, models.OperationProgress{Done: 1, Total: 2}, resp.Progress)
+		assert.Equal(t, "permission denied", resp.Message)
+		assert.JSONEq(t, `[{"source":"prod-project.playground"}]`, string(resp.Result))
+	})
+	t.Run("should return not found for operation of another project", func(t *testing.T) {
+		repo := new(mock.OperationRepository)
+		repo.On("GetByID", projectSpec, operationID).Return(models.Operation{}, store.ErrResourceNotFound)
+
+		rec := httptest.NewRecorder()
+		v1.NewOperationHandler(new(mock.OperationCanceller), repo, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/operations?project=a-data-project&id="+operationID.String(), nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("should list latest operations of kind", func(t *testing.T) {
+		repo := new(mock.OperationRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("GetLatest", projectSpec, models.OperationKindPromotion, 5).Return([]models.Operation{op}, nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewOperationHandler(new(mock.OperationCanceller), repo, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/operations?project=a-data-project&kind=promotion&limit=5", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp []v1.OperationResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, 1, len(resp))
+		assert.Equal(t, operationID.String(), resp[0].ID)
+	})
+	t.Run("should cancel running operation", func(t *testing.T) {
+		canceller := new(mock.OperationCanceller)
+		defer canceller.AssertExpectations(t)
+		canceller.On("Cancel", projectSpec, operationID).Return(nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewOperationHandler(canceller, new(mock.OperationRepository), projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodDelete, "/operations?project=a-data-project&id="+operationID.String(), nil))
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	})
+	t.Run("should conflict cancelling operation which is not running", func(t *testing.T) {
+		canceller := new(mock.OperationCanceller)
+		canceller.On("Cancel", projectSpec, operationID).Return(operation.ErrNotRunning)
+
+		rec := httptest.NewRecorder()
+		v1.NewOperationHandler(canceller, new(mock.OperationRepository), projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodDelete, "/operations?project=a-data-project&id="+operationID.String(), nil))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
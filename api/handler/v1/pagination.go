@@ -0,0 +1,217 @@
+package v1
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	// list requests can be paginated by passing these keys as grpc metadata,
+	// http clients can send them as headers prefixed with Grpc-Metadata-
+	MetadataPageSize  = "x-page-size"
+	MetadataPageToken = "x-page-token"
+	MetadataFieldMask = "x-field-mask"
+
+	// MetadataNextPageToken is sent back as response header if more items
+	// are available after the current page
+	MetadataNextPageToken = "x-next-page-token"
+
+	// http handlers listing items take pagination as these query params and
+	// send the token of the next page back in HeaderNextPageToken
+	QueryPageSize       = "page_size"
+	QueryPageToken      = "page_token"
+	HeaderNextPageToken = "X-Next-Page-Token"
+)
+
+var (
+	// MaxPageSize is the upper limit of items returned in a single page,
+	// requested page size is capped to this value
+	MaxPageSize = 500
+
+	ErrInvalidPageSize  = errors.New("invalid page size")
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrInvalidFieldMask = errors.New("invalid field mask")
+)
+
+// pageOptions is a client preference on how a list response should be sliced
+// and which fields of each item should be populated
+type pageOptions struct {
+	// Size of 0 means pagination is not requested and all items are returned
+	Size   int
+	Offset int
+	Fields []string
+}
+
+// parsePageOptions reads pagination preferences from incoming grpc metadata
+func parsePageOptions(ctx context.Context) (pageOptions, error) {
+	opts := pageOptions{}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return opts, nil
+	}
+
+	var err error
+	if vals := md.Get(MetadataPageSize); len(vals) > 0 {
+		if opts.Size, err = parsePageSize(vals[0]); err != nil {
+			return opts, err
+		}
+	}
+	if vals := md.Get(MetadataPageToken); len(vals) > 0 {
+		if opts.Offset, err = parsePageToken(vals[0]); err != nil {
+			return opts, err
+		}
+	}
+
+	if vals := md.Get(MetadataFieldMask); len(vals) > 0 {
+		for _, val := range vals {
+			for _, path := range strings.Split(val, ",") {
+				if path = strings.TrimSpace(path); path != "" {
+					opts.Fields = append(opts.Fields, path)
+				}
+			}
+		}
+	}
+	return opts, nil
+}
+
+// parseHTTPPageOptions reads pagination preferences from query params of
+// http list handlers, field masks are not supported over plain http
+func parseHTTPPageOptions(r *http.Request) (pageOptions, error) {
+	opts := pageOptions{}
+	var err error
+	if opts.Size, err = parsePageSize(r.URL.Query().Get(QueryPageSize)); err != nil {
+		return opts, err
+	}
+	if opts.Offset, err = parsePageToken(r.URL.Query().Get(QueryPageToken)); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// parsePageSize caps requested size to MaxPageSize, empty size means
+// pagination is not requested
+func parsePageSize(val string) (int, error) {
+	if val == "" {
+		return 0, nil
+	}
+	size, err := strconv.Atoi(val)
+	if err != nil || size < 0 {
+		return 0, errors.Wrap(ErrInvalidPageSize, val)
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	return size, nil
+}
+
+func parsePageToken(val string) (int, error) {
+	if val == "" {
+		return 0, nil
+	}
+	return decodePageToken(val)
+}
+
+// bounds returns the slice range of current page for a list of total items
+// and the token to request the next page, empty if no items are left
+func (p pageOptions) bounds(total int) (start, end int, nextToken string) {
+	start = p.Offset
+	if start > total {
+		start = total
+	}
+	if p.Size == 0 {
+		return start, total, ""
+	}
+
+	end = start + p.Size
+	if end >= total {
+		return start, total, ""
+	}
+	return start, end, encodePageToken(end)
+}
+
+// mask clears all the fields of msg which are not requested in field mask
+func (p pageOptions) mask(msg proto.Message) error {
+	if len(p.Fields) == 0 {
+		return nil
+	}
+	if _, err := fieldmaskpb.New(msg, p.Fields...); err != nil {
+		return errors.Wrap(ErrInvalidFieldMask, err.Error())
+	}
+
+	pruneMessage(msg.ProtoReflect(), p.Fields)
+	return nil
+}
+
+// pruneMessage clears populated fields of msg which are not part of paths,
+// nested paths like behavior.retry are applied recursively on singular messages
+func pruneMessage(msg protoreflect.Message, paths []string) {
+	fields := map[string][]string{}
+	for _, path := range paths {
+		parts := strings.SplitN(path, ".", 2)
+		if len(parts) == 1 {
+			// complete field is requested
+			fields[parts[0]] = nil
+			continue
+		}
+		if sub, ok := fields[parts[0]]; ok && sub == nil {
+			continue
+		}
+		fields[parts[0]] = append(fields[parts[0]], parts[1])
+	}
+
+	msg.Range(func(fd protoreflect.FieldDescriptor, val protoreflect.Value) bool {
+		subPaths, ok := fields[string(fd.Name())]
+		if !ok {
+			msg.Clear(fd)
+			return true
+		}
+		if len(subPaths) > 0 && fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+			pruneMessage(val.Message(), subPaths)
+		}
+		return true
+	})
+}
+
+// sendNextPageToken attaches next page token in response header, it is a no-op
+// if there is no grpc stream attached to the context
+func sendNextPageToken(ctx context.Context, token string) {
+	if token == "" {
+		return
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataNextPageToken, token))
+}
+
+// setNextPageToken attaches next page token to the response of an http
+// list handler
+func setNextPageToken(w http.ResponseWriter, token string) {
+	if token == "" {
+		return
+	}
+	w.Header().Set(HeaderNextPageToken, token)
+}
+
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errors.Wrap(ErrInvalidPageToken, token)
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, errors.Wrap(ErrInvalidPageToken, token)
+	}
+	return offset, nil
+}
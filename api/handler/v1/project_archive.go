@@ -0,0 +1,389 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// projectArchiveFreezeReasonPrefix starts the reason of the freeze
+	// which blocks deploys to archived projects
+	projectArchiveFreezeReasonPrefix = "archived: "
+)
+
+// ProjectArchiveRequest archives a project, its jobs are purged after
+// RetainDays, server default is used if it is not set and 0 retains them
+// till the project is restored
+type ProjectArchiveRequest struct {
+	Reason     string `json:"reason"`
+	Actor      string `json:"actor,omitempty"`
+	RetainDays *int   `json:"retain_days,omitempty"`
+}
+
+// ProjectArchiveResponse is the archive status of a project served over http
+type ProjectArchiveResponse struct {
+	ProjectName string     `json:"project_name"`
+	Archived    bool       `json:"archived"`
+	Reason      string     `json:"reason,omitempty"`
+	Actor       string     `json:"actor,omitempty"`
+	PurgeAt     *time.Time `json:"purge_at,omitempty"`
+	PurgedAt    *time.Time `json:"purged_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+// ProjectArchiveResult is the result of archive and restore operations,
+// Failed has jobs which couldn't be paused or resumed with the reason
+type ProjectArchiveResult struct {
+	Namespaces int               `json:"namespaces"`
+	Jobs       int               `json:"jobs"`
+	Failed     map[string]string `json:"failed,omitempty"`
+}
+
+// projectArchiveSnapshot is what is kept of a project when it is archived
+type projectArchiveSnapshot struct {
+	Config     map[string]string         `json:"config,omitempty"`
+	Namespaces []projectArchiveNamespace `json:"namespaces"`
+}
+
+type projectArchiveNamespace struct {
+	Name   string            `json:"name"`
+	Config map[string]string `json:"config,omitempty"`
+	// Jobs are serialized the way deployments are, see jobSnapshot
+	Jobs []byte `json:"jobs"`
+}
+
+// ProjectArchiveHandler lets admins decommission a project identified by
+// project query param without losing its data right away. GET returns the
+// archive status, PUT starts an operation archiving the project with request
+// body and DELETE starts one restoring it.
+// Archiving freezes the project, snapshots its job specs, pauses its jobs and
+// removes them from scheduler. Jobs are purged by retention janitor once the
+// archive is due, till then restoring redeploys them from the snapshot and
+// resumes them
+type ProjectArchiveHandler struct {
+	jobSvc               models.JobService
+	scheduler            models.SchedulerUnit
+	adapter              ProtoAdapter
+	repo                 store.ProjectArchiveRepository
+	freezeRepo           store.ProjectFreezeRepository
+	operations           models.OperationRunner
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+	defaultRetention     time.Duration
+	Now                  func() time.Time
+}
+
+func (h *ProjectArchiveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	archive, err := h.repo.GetByProject(projSpec)
+	if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	archived := err == nil
+
+	var op models.Operation
+	switch r.Method {
+	case http.MethodGet:
+		resp := ProjectArchiveResponse{
+			ProjectName: projSpec.Name,
+		}
+		if archived {
+			resp.Archived = true
+			resp.Reason = archive.Reason
+			resp.Actor = archive.Actor
+			resp.CreatedAt = &archive.CreatedAt
+			if !archive.PurgeAt.IsZero() {
+				resp.PurgeAt = &archive.PurgeAt
+			}
+			if archive.IsPurged() {
+				resp.PurgedAt = &archive.PurgedAt
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	case http.MethodPut:
+		var req ProjectArchiveRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, errors.Wrap(err, "invalid archive").Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Reason == "" {
+			http.Error(w, "reason is required", http.StatusBadRequest)
+			return
+		}
+		if req.RetainDays != nil && *req.RetainDays < 0 {
+			http.Error(w, "retain_days should not be negative", http.StatusBadRequest)
+			return
+		}
+		if archived {
+			http.Error(w, "project "+projSpec.Name+" is already archived", http.StatusConflict)
+			return
+		}
+		if req.Actor == "" {
+			req.Actor = r.Header.Get(MetadataActor)
+		}
+		op, err = h.operations.Start(projSpec, models.OperationKindProjectArchive, req.Actor, req,
+			func(ctx context.Context, report func(models.OperationProgress)) (interface{}, error) {
+				return h.archive(ctx, projSpec, req, report)
+			})
+	case http.MethodDelete:
+		if !archived {
+			http.Error(w, "project "+projSpec.Name+" is not archived", http.StatusNotFound)
+			return
+		}
+		if archive.IsPurged() {
+			http.Error(w, fmt.Sprintf("project %s was purged at %s and can't be restored", projSpec.Name,
+				archive.PurgedAt.UTC().Format(time.RFC3339)), http.StatusGone)
+			return
+		}
+		op, err = h.operations.Start(projSpec, models.OperationKindProjectRestore, r.Header.Get(MetadataActor), nil,
+			func(ctx context.Context, report func(models.OperationProgress)) (interface{}, error) {
+				return h.restore(ctx, projSpec, archive, report)
+			})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		if errors.Is(err, operation.ErrManagerClosed) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeOperationAccepted(w, projSpec, op)
+}
+
+// archive freezes the project before anything else so no job is deployed
+// while it is snapshotted, jobs failing to pause are reported in result as
+// removing them from scheduler stops them anyway
+func (h *ProjectArchiveHandler) archive(ctx context.Context, projSpec models.ProjectSpec, req ProjectArchiveRequest,
+	report func(models.OperationProgress)) (ProjectArchiveResult, error) {
+	result := ProjectArchiveResult{Failed: map[string]string{}}
+	now := h.Now()
+	if err := h.freezeRepo.Save(projSpec, models.ProjectFreeze{
+		Reason:    projectArchiveFreezeReasonPrefix + req.Reason,
+		Actor:     req.Actor,
+		CreatedAt: now,
+	}); err != nil {
+		return result, errors.Wrap(err, "failed to freeze project")
+	}
+
+	namespaces, err := h.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return result, err
+	}
+	snapshot := projectArchiveSnapshot{Config: projSpec.Config}
+	jobsByNamespace := map[string][]models.JobSpec{}
+	for _, namespace := range namespaces {
+		jobSpecs, err := h.jobSvc.GetAll(namespace)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to get jobs of namespace %s", namespace.Name)
+		}
+		var jobProtos []*pb.JobSpecification
+		for _, jobSpec := range jobSpecs {
+			jobProto, err := h.adapter.ToJobProto(jobSpec)
+			if err != nil {
+				return result, errors.Wrapf(err, "failed to snapshot job %s", jobSpec.Name)
+			}
+			jobProtos = append(jobProtos, jobProto)
+		}
+		jobs, _, err := jobSnapshot(jobProtos)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to snapshot jobs of namespace %s", namespace.Name)
+		}
+		snapshot.Namespaces = append(snapshot.Namespaces, projectArchiveNamespace{
+			Name:   namespace.Name,
+			Config: namespace.Config,
+			Jobs:   jobs,
+		})
+		jobsByNamespace[namespace.Name] = jobSpecs
+		result.Jobs += len(jobSpecs)
+	}
+	result.Namespaces = len(namespaces)
+
+	snapshotBytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return result, err
+	}
+	archive := models.ProjectArchive{
+		Reason:    req.Reason,
+		Actor:     req.Actor,
+		Snapshot:  snapshotBytes,
+		CreatedAt: now,
+	}
+	retention := h.defaultRetention
+	if req.RetainDays != nil {
+		retention = time.Duration(*req.RetainDays) * 24 * time.Hour
+	}
+	if retention > 0 {
+		archive.PurgeAt = now.Add(retention)
+	}
+	if err := h.repo.Save(projSpec, archive); err != nil {
+		return result, errors.Wrap(err, "failed to save archive")
+	}
+
+	done, total := 0, result.Jobs+len(namespaces)
+	report(models.OperationProgress{Done: done, Total: total})
+	for _, namespace := range namespaces {
+		for _, jobSpec := range jobsByNamespace[namespace.Name] {
+			if err := h.scheduler.SetJobPaused(ctx, projSpec, jobSpec.Name, true); err != nil {
+				result.Failed[jobSpec.Name] = err.Error()
+			}
+			done++
+			report(models.OperationProgress{Done: done, Total: total})
+		}
+		if err := h.jobSvc.Unschedule(ctx, namespace, nil); err != nil {
+			return result, errors.Wrapf(err, "failed to remove jobs of namespace %s from scheduler", namespace.Name)
+		}
+		done++
+		report(models.OperationProgress{Done: done, Total: total})
+	}
+	return result, nil
+}
+
+// restore redeploys jobs of the archive snapshot and resumes them once
+// scheduler loads them, the project is unfrozen only if all of them are
+// deployed
+func (h *ProjectArchiveHandler) restore(ctx context.Context, projSpec models.ProjectSpec,
+	archive models.ProjectArchive, report func(models.OperationProgress)) (ProjectArchiveResult, error) {
+	result := ProjectArchiveResult{Failed: map[string]string{}}
+	var snapshot projectArchiveSnapshot
+	if err := json.Unmarshal(archive.Snapshot, &snapshot); err != nil {
+		return result, errors.Wrap(err, "failed to read snapshot of archive")
+	}
+
+	namespaceRepo := h.namespaceRepoFactory.New(projSpec)
+	jobsByNamespace := map[string][]*pb.JobSpecification{}
+	for _, archived := range snapshot.Namespaces {
+		var jobs pb.DeployJobSpecificationRequest
+		if err := proto.Unmarshal(archived.Jobs, &jobs); err != nil {
+			return result, errors.Wrapf(err, "failed to read snapshot of namespace %s", archived.Name)
+		}
+		jobsByNamespace[archived.Name] = jobs.GetJobs()
+		result.Jobs += len(jobs.GetJobs())
+	}
+	result.Namespaces = len(snapshot.Namespaces)
+
+	done, total := 0, result.Jobs+result.Namespaces
+	report(models.OperationProgress{Done: done, Total: total})
+	for _, archived := range snapshot.Namespaces {
+		namespace, err := namespaceRepo.GetByName(archived.Name)
+		if errors.Is(err, store.ErrResourceNotFound) {
+			namespace = models.NamespaceSpec{Name: archived.Name, Config: archived.Config, ProjectSpec: projSpec}
+			if err = namespaceRepo.Save(namespace); err == nil {
+				namespace, err = namespaceRepo.GetByName(archived.Name)
+			}
+		}
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to get namespace %s", archived.Name)
+		}
+
+		var jobNames []string
+		for _, jobProto := range jobsByNamespace[archived.Name] {
+			jobSpec, err := h.adapter.FromJobProto(jobProto)
+			if err != nil {
+				return result, errors.Wrapf(err, "failed to read job %s of snapshot", jobProto.GetName())
+			}
+			if err := h.jobSvc.Create(namespace, jobSpec); err != nil {
+				return result, errors.Wrapf(err, "failed to restore job %s", jobSpec.Name)
+			}
+			jobNames = append(jobNames, jobSpec.Name)
+		}
+		if err := h.jobSvc.Sync(ctx, namespace, nil); err != nil {
+			return result, errors.Wrapf(err, "failed to deploy jobs of namespace %s", namespace.Name)
+		}
+		done++
+		report(models.OperationProgress{Done: done, Total: total})
+
+		for _, jobName := range jobNames {
+			if err := h.resumeWhenLoaded(ctx, projSpec, jobName); err != nil {
+				result.Failed[jobName] = err.Error()
+			}
+			done++
+			report(models.OperationProgress{Done: done, Total: total})
+		}
+	}
+
+	if err := h.repo.Delete(projSpec); err != nil {
+		return result, errors.Wrap(err, "failed to delete archive")
+	}
+	if err := h.freezeRepo.Delete(projSpec); err != nil {
+		return result, errors.Wrap(err, "failed to unfreeze project")
+	}
+	return result, nil
+}
+
+// resumeWhenLoaded waits for scheduler to load a redeployed job, jobs come
+// back paused as they were archived, before resuming it
+func (h *ProjectArchiveHandler) resumeWhenLoaded(ctx context.Context, projSpec models.ProjectSpec, jobName string) error {
+	ctx, cancel := context.WithTimeout(ctx, job.UpstreamLoadTimeout)
+	defer cancel()
+	ticker := time.NewTicker(job.UpstreamLoadPollInterval)
+	defer ticker.Stop()
+	for {
+		loaded, err := h.scheduler.IsJobLoaded(ctx, projSpec, jobName)
+		if err != nil {
+			logger.W(errors.Wrapf(err, "failed to check if job %s is loaded by scheduler", jobName))
+		}
+		if loaded {
+			return h.scheduler.SetJobPaused(ctx, projSpec, jobName, false)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Errorf("job %s is not loaded by scheduler in %s, resume it once it is", jobName, job.UpstreamLoadTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+func NewProjectArchiveHandler(jobSvc models.JobService, scheduler models.SchedulerUnit, adapter ProtoAdapter,
+	repo store.ProjectArchiveRepository, freezeRepo store.ProjectFreezeRepository, operations models.OperationRunner,
+	projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	defaultRetention time.Duration) *ProjectArchiveHandler {
+	return &ProjectArchiveHandler{
+		jobSvc:               jobSvc,
+		scheduler:            scheduler,
+		adapter:              adapter,
+		repo:                 repo,
+		freezeRepo:           freezeRepo,
+		operations:           operations,
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+		defaultRetention:     defaultRetention,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
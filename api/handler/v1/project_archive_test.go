@@ -0,0 +1,217 @@
+package v1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestProjectArchiveHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	operationID := uuid.Must(uuid.NewRandom())
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	taskName := "bq2bq"
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name:  taskName,
+		Image: "random-image",
+	}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+		Base: execUnit,
+	}, nil)
+	adapter := v1.NewAdapter(pluginRepo, nil)
+	jobSpecs := []models.JobSpec{
+		{
+			Name:  "a-job",
+			Owner: "jane@example.com",
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{Base: execUnit},
+				Window: models.JobSpecTaskWindow{Size: time.Hour * 24, TruncateTo: "d"},
+			},
+			Schedule:     models.JobSpecSchedule{StartDate: now, Interval: "@daily"},
+			Dependencies: map[string]models.JobSpecDependency{},
+		},
+	}
+
+	setup := func(archive models.ProjectArchive, archiveErr error) (*mock.ProjectRepoFactory,
+		*mock.NamespaceRepoFactory, *mock.ProjectArchiveRepository) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		namespaceRepository := new(mock.NamespaceRepository)
+		namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+		namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+		namespaceRepoFactory.On("New", projectSpec).Return(namespaceRepository)
+
+		archiveRepo := new(mock.ProjectArchiveRepository)
+		archiveRepo.On("GetByProject", projectSpec).Return(archive, archiveErr)
+		return projectRepoFactory, namespaceRepoFactory, archiveRepo
+	}
+	newHandler := func(jobService *mock.JobService, scheduler *mock.Scheduler, archiveRepo *mock.ProjectArchiveRepository,
+		freezeRepo *mock.ProjectFreezeRepository, operations *mock.OperationRunner, projectRepoFactory *mock.ProjectRepoFactory,
+		namespaceRepoFactory *mock.NamespaceRepoFactory) *v1.ProjectArchiveHandler {
+		handler := v1.NewProjectArchiveHandler(jobService, scheduler, adapter, archiveRepo, freezeRepo, operations,
+			projectRepoFactory, namespaceRepoFactory, time.Hour*24*30)
+		handler.Now = func() time.Time { return now }
+		return handler
+	}
+
+	var archived models.ProjectArchive
+	t.Run("should freeze, snapshot, pause and unschedule jobs of project when archived", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, archiveRepo := setup(models.ProjectArchive{}, store.ErrResourceNotFound)
+		archiveRepo.On("Save", projectSpec, mock2.AnythingOfType("models.ProjectArchive")).Run(func(args mock2.Arguments) {
+			archived = args.Get(1).(models.ProjectArchive)
+		}).Return(nil)
+		defer archiveRepo.AssertExpectations(t)
+
+		freezeRepo := new(mock.ProjectFreezeRepository)
+		freezeRepo.On("Save", projectSpec, models.ProjectFreeze{
+			Reason:    "archived: team moved to b-data-project",
+			Actor:     "jane@example.com",
+			CreatedAt: now,
+		}).Return(nil)
+		defer freezeRepo.AssertExpectations(t)
+
+		req := v1.ProjectArchiveRequest{Reason: "team moved to b-data-project", Actor: "jane@example.com"}
+		operations := new(mock.OperationRunner)
+		operations.On("Start", projectSpec, models.OperationKindProjectArchive, "jane@example.com", req).Return(
+			models.Operation{ID: operationID, Kind: models.OperationKindProjectArchive}, nil)
+		defer operations.AssertExpectations(t)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return(jobSpecs, nil)
+		jobService.On("Unschedule", mock2.Anything, namespaceSpec, nil).Return(nil)
+		defer jobService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		scheduler.On("SetJobPaused", mock2.Anything, projectSpec, "a-job", true).Return(nil)
+		defer scheduler.AssertExpectations(t)
+
+		body, _ := json.Marshal(req)
+		rec := httptest.NewRecorder()
+		newHandler(jobService, scheduler, archiveRepo, freezeRepo, operations, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPut, "/admin/archive?project=a-data-project", bytes.NewReader(body)))
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "/operations?project=a-data-project&id="+operationID.String(), rec.Header().Get("Location"))
+
+		assert.Nil(t, operations.Err)
+		assert.Equal(t, v1.ProjectArchiveResult{Namespaces: 1, Jobs: 1, Failed: map[string]string{}}, operations.Result)
+		assert.Equal(t, []models.OperationProgress{{Done: 0, Total: 2}, {Done: 1, Total: 2}, {Done: 2, Total: 2}},
+			operations.Progress)
+		assert.Equal(t, "team moved to b-data-project", archived.Reason)
+		assert.Equal(t, now.Add(time.Hour*24*30), archived.PurgeAt)
+		assert.NotEmpty(t, archived.Snapshot)
+	})
+	t.Run("should redeploy jobs from snapshot and resume them when restored", func(t *testing.T) {
+		defer func(interval time.Duration) { job.UpstreamLoadPollInterval = interval }(job.UpstreamLoadPollInterval)
+		job.UpstreamLoadPollInterval = time.Millisecond
+
+		projectRepoFactory, namespaceRepoFactory, archiveRepo := setup(archived, nil)
+		archiveRepo.On("Delete", projectSpec).Return(nil)
+		defer archiveRepo.AssertExpectations(t)
+
+		freezeRepo := new(mock.ProjectFreezeRepository)
+		freezeRepo.On("Delete", projectSpec).Return(nil)
+		defer freezeRepo.AssertExpectations(t)
+
+		operations := new(mock.OperationRunner)
+		operations.On("Start", projectSpec, models.OperationKindProjectRestore, "jane@example.com", nil).Return(
+			models.Operation{ID: operationID, Kind: models.OperationKindProjectRestore}, nil)
+		defer operations.AssertExpectations(t)
+
+		jobService := new(mock.JobService)
+		jobService.On("Create", mock2.MatchedBy(func(spec models.JobSpec) bool {
+			return spec.Name == "a-job" && spec.Owner == "jane@example.com"
+		}), namespaceSpec).Return(nil)
+		jobService.On("Sync", mock2.Anything, namespaceSpec, nil).Return(nil)
+		defer jobService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		scheduler.On("IsJobLoaded", mock2.Anything, projectSpec, "a-job").Return(false, nil).Once()
+		scheduler.On("IsJobLoaded", mock2.Anything, projectSpec, "a-job").Return(true, nil)
+		scheduler.On("SetJobPaused", mock2.Anything, projectSpec, "a-job", false).Return(nil)
+		defer scheduler.AssertExpectations(t)
+
+		req := httptest.NewRequest(http.MethodDelete, "/admin/archive?project=a-data-project", nil)
+		req.Header.Set(v1.MetadataActor, "jane@example.com")
+		rec := httptest.NewRecorder()
+		newHandler(jobService, scheduler, archiveRepo, freezeRepo, operations, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+
+		assert.Nil(t, operations.Err)
+		assert.Equal(t, v1.ProjectArchiveResult{Namespaces: 1, Jobs: 1, Failed: map[string]string{}}, operations.Result)
+	})
+	t.Run("should return archive status of project", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, archiveRepo := setup(archived, nil)
+
+		rec := httptest.NewRecorder()
+		newHandler(nil, nil, archiveRepo, nil, nil, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/admin/archive?project=a-data-project", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ProjectArchiveResponse
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.True(t, resp.Archived)
+		assert.Equal(t, "team moved to b-data-project", resp.Reason)
+		assert.Equal(t, now.Add(time.Hour*24*30), resp.PurgeAt.UTC())
+		assert.Nil(t, resp.PurgedAt)
+	})
+	t.Run("should reject archiving an archived project", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, archiveRepo := setup(archived, nil)
+		operations := new(mock.OperationRunner)
+
+		rec := httptest.NewRecorder()
+		newHandler(nil, nil, archiveRepo, nil, operations, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPut, "/admin/archive?project=a-data-project",
+				bytes.NewReader([]byte(`{"reason": "again"}`))))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		operations.AssertNotCalled(t, "Start")
+	})
+	t.Run("should reject restoring a purged project", func(t *testing.T) {
+		purged := archived
+		purged.Snapshot, purged.PurgedAt = nil, now.Add(time.Hour*24*31)
+		projectRepoFactory, namespaceRepoFactory, archiveRepo := setup(purged, nil)
+		operations := new(mock.OperationRunner)
+
+		rec := httptest.NewRecorder()
+		newHandler(nil, nil, archiveRepo, nil, operations, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodDelete, "/admin/archive?project=a-data-project", nil))
+		assert.Equal(t, http.StatusGone, rec.Code)
+		operations.AssertNotCalled(t, "Start")
+	})
+	t.Run("should reject restoring a project which isn't archived", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, archiveRepo := setup(models.ProjectArchive{}, store.ErrResourceNotFound)
+		operations := new(mock.OperationRunner)
+
+		rec := httptest.NewRecorder()
+		newHandler(nil, nil, archiveRepo, nil, operations, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodDelete, "/admin/archive?project=a-data-project", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		operations.AssertNotCalled(t, "Start")
+	})
+}
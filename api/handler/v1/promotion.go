@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/datastore"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// PromotionResultResponse is the outcome of promoting a dataset, a list of
+// them is the result of promotion operations
+type PromotionResultResponse struct {
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Copied      []string `json:"copied"`
+	Skipped     []string `json:"skipped"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// PromotionHandler lets admins clone datasets of a project identified by
+// project query param into another environment, POST takes the manifest
+// of datasets as request body and starts the promotion as an operation
+type PromotionHandler struct {
+	datastoreSvc       models.DatastoreService
+	operations         models.OperationRunner
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *PromotionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	var manifest models.PromotionManifest
+	if err := json.NewDecoder(r.Body).Decode(&manifest); err != nil {
+		http.Error(w, errors.Wrap(err, "invalid manifest").Error(), http.StatusBadRequest)
+		return
+	}
+	if err := manifest.Validate(); err != nil {
+		http.Error(w, errors.Wrap(err, "invalid manifest").Error(), http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	op, err := h.operations.Start(projSpec, models.OperationKindPromotion, r.Header.Get(MetadataActor), manifest,
+		func(ctx context.Context, report func(models.OperationProgress)) (interface{}, error) {
+			results, err := h.promote(ctx, projSpec, manifest, report)
+			if results == nil {
+				return nil, err
+			}
+			return results, err
+		})
+	if err != nil {
+		if errors.Is(err, operation.ErrManagerClosed) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeOperationAccepted(w, projSpec, op)
+}
+
+func (h *PromotionHandler) promote(ctx context.Context, projSpec models.ProjectSpec, manifest models.PromotionManifest,
+	report func(models.OperationProgress)) ([]PromotionResultResponse, error) {
+	results, err := h.datastoreSvc.Promote(ctx, projSpec, manifest, &promotionProgress{
+		total:  len(manifest.Datasets),
+		report: report,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := []PromotionResultResponse{}
+	for _, result := range results {
+		res := PromotionResultResponse{
+			Source:      result.Source,
+			Destination: result.Destination,
+			Copied:      result.Copied,
+			Skipped:     result.Skipped,
+		}
+		if result.Err != nil {
+			res.Error = result.Err.Error()
+			return append(resp, res), errors.Wrapf(result.Err, "failed to promote %s", result.Source)
+		}
+		resp = append(resp, res)
+	}
+	return resp, nil
+}
+
+// promotionProgress reports datasets promoted so far as progress of the
+// promotion operation
+type promotionProgress struct {
+	done   int
+	total  int
+	report func(models.OperationProgress)
+}
+
+func (p *promotionProgress) Notify(evt progress.Event) {
+	if _, ok := evt.(*datastore.EventDatasetPromoted); ok {
+		p.done++
+		p.report(models.OperationProgress{Done: p.done, Total: p.total})
+	}
+}
+
+func NewPromotionHandler(datastoreSvc models.DatastoreService, operations models.OperationRunner,
+	projectRepoFactory ProjectRepoFactory) *PromotionHandler {
+	return &PromotionHandler{
+		datastoreSvc:       datastoreSvc,
+		operations:         operations,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,102 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/datastore"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestPromotionHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	manifest := models.PromotionManifest{
+		Datastore: "bigquery",
+		Datasets: []models.PromotionDataset{
+			{Source: "prod-project.playground", Destination: "staging-project.playground", WithData: true},
+			{Source: "prod-project.reports", Destination: "staging-project.reports"},
+		},
+	}
+	operationID := uuid.Must(uuid.NewRandom())
+
+	t.Run("should promote datasets of manifest as an operation", func(t *testing.T) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		operations := new(mock.OperationRunner)
+		defer operations.AssertExpectations(t)
+		operations.On("Start", projectSpec, models.OperationKindPromotion, "jane@example.com", manifest).Return(
+			models.Operation{ID: operationID, Kind: models.OperationKindPromotion, Status: models.OperationStatusPending}, nil)
+
+		datastoreService := new(mock.DatastoreService)
+		datastoreService.On("Promote", mock2.Anything, projectSpec, manifest, mock2.Anything).Run(func(args mock2.Arguments) {
+			args.Get(3).(progress.Observer).Notify(&datastore.EventDatasetPromoted{
+				Source:      "prod-project.playground",
+				Destination: "staging-project.playground",
+			})
+		}).Return([]models.PromotionResult{
+			{
+				Source:              "prod-project.playground",
+				Destination:         "staging-project.playground",
+				CopyDatasetResponse: models.CopyDatasetResponse{Copied: []string{"events"}},
+			},
+			{
+				Source:      "prod-project.reports",
+				Destination: "staging-project.reports",
+				Err:         errors.New("permission denied"),
+			},
+		}, nil)
+		defer datastoreService.AssertExpectations(t)
+
+		body, err := json.Marshal(manifest)
+		assert.Nil(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/admin/promote?project=a-data-project", strings.NewReader(string(body)))
+		req.Header.Set(v1.MetadataActor, "jane@example.com")
+		rec := httptest.NewRecorder()
+		v1.NewPromotionHandler(datastoreService, operations, projectRepoFactory).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "/operations?project=a-data-project&id="+operationID.String(), rec.Header().Get("Location"))
+
+		var resp v1.OperationResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, operationID.String(), resp.ID)
+		assert.Equal(t, models.OperationStatusPending, resp.Status)
+
+		assert.Equal(t, []models.OperationProgress{{Done: 1, Total: 2}}, operations.Progress)
+		assert.Equal(t, "failed to promote prod-project.reports: permission denied", operations.Err.Error())
+		assert.Equal(t, []v1.PromotionResultResponse{
+			{
+				Source:      "prod-project.playground",
+				Destination: "staging-project.playground",
+				Copied:      []string{"events"},
+			},
+			{
+				Source:      "prod-project.reports",
+				Destination: "staging-project.reports",
+				Error:       "permission denied",
+			},
+		}, operations.Result)
+	})
+	t.Run("should reject invalid manifests", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v1.NewPromotionHandler(new(mock.DatastoreService), new(mock.OperationRunner), new(mock.ProjectRepoFactory)).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPost, "/admin/promote?project=a-data-project",
+				strings.NewReader(`{"datastore": "bigquery"}`)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// QuotaResponse is the quota of a project served over http
+type QuotaResponse struct {
+	ProjectName         string `json:"project_name"`
+	MaxJobs             int    `json:"max_jobs"`
+	MaxResources        int    `json:"max_resources"`
+	MaxReplayRunsPerDay int    `json:"max_replay_runs_per_day"`
+}
+
+// QuotaHandler lets admins view and adjust quota of a project identified by
+// project query param, GET returns the quota and PUT replaces it with request body
+type QuotaHandler struct {
+	quotaSvc           models.QuotaService
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *QuotaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req QuotaResponse
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, errors.Wrap(err, "invalid quota").Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.quotaSvc.Update(projSpec, models.ProjectQuota{
+			MaxJobs:             req.MaxJobs,
+			MaxResources:        req.MaxResources,
+			MaxReplayRunsPerDay: req.MaxReplayRunsPerDay,
+		}); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	quota, err := h.quotaSvc.Get(projSpec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(QuotaResponse{
+		ProjectName:         projSpec.Name,
+		MaxJobs:             quota.MaxJobs,
+		MaxResources:        quota.MaxResources,
+		MaxReplayRunsPerDay: quota.MaxReplayRunsPerDay,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewQuotaHandler(quotaSvc models.QuotaService, projectRepoFactory ProjectRepoFactory) *QuotaHandler {
+	return &QuotaHandler{
+		quotaSvc:           quotaSvc,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/odpf/optimus/core/tree"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// replay dry run and replay responses carry the estimated impact of
+	// replay in these response headers, duration is in seconds
+	MetadataReplayRuns               = "x-replay-runs"
+	MetadataReplayEstimatedDuration  = "x-replay-estimated-duration"
+	MetadataReplayRunsWithoutHistory = "x-replay-runs-without-history"
+)
+
+// estimateReplay projects the number of runs to clear and how long clearing them
+// would take based on durations of previous runs of each job in the tree
+func estimateReplay(instSvc models.InstanceService, rootNode *tree.TreeNode) (models.ReplayEstimate, error) {
+	estimate := models.ReplayEstimate{}
+	visited := map[string]bool{}
+	for _, node := range rootNode.GetAllNodes() {
+		if visited[node.GetName()] {
+			continue
+		}
+		visited[node.GetName()] = true
+
+		runs := node.Runs.Size()
+		estimate.Runs += runs
+		if runs == 0 {
+			continue
+		}
+		jobSpec, ok := node.Data.(models.JobSpec)
+		if !ok || instSvc == nil {
+			estimate.RunsWithoutHistory += runs
+			continue
+		}
+		took, err := instSvc.GetAverageDuration(jobSpec)
+		if err != nil {
+			return models.ReplayEstimate{}, errors.Wrapf(err, "failed to estimate runs of job %s", jobSpec.Name)
+		}
+		if took == 0 {
+			estimate.RunsWithoutHistory += runs
+			continue
+		}
+		estimate.Duration += took * time.Duration(runs)
+	}
+	return estimate, nil
+}
+
+// sendReplayEstimate attaches replay estimate in response header, it is a no-op
+// if there is no grpc stream attached to the context
+func sendReplayEstimate(ctx context.Context, estimate models.ReplayEstimate) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		MetadataReplayRuns, strconv.Itoa(estimate.Runs),
+		MetadataReplayEstimatedDuration, strconv.Itoa(int(estimate.Duration.Seconds())),
+		MetadataReplayRunsWithoutHistory, strconv.Itoa(estimate.RunsWithoutHistory),
+	))
+}
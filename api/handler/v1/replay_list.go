@@ -0,0 +1,144 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// ReplayListHandler serves status of active replays, the ones accepted or in
+// progress, of jobs of the project in project query param, optionally only
+// of the namespace in namespace query param. Oldest replays come first,
+// replays of jobs of a replay or a plan are listed under it. Replays are
+// paginated with page_size and page_token query params
+type ReplayListHandler struct {
+	replayQueue          ReplayQueue
+	replaySpecRepoFac    job.ReplaySpecRepoFactory
+	jobSvc               models.JobService
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+}
+
+func (h *ReplayListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	pageOpts, err := parseHTTPPageOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	namespaceRepo := h.namespaceRepoFactory.New(projSpec)
+	var namespaces []models.NamespaceSpec
+	if namespaceName := r.URL.Query().Get("namespace"); namespaceName != "" {
+		namespaceSpec, err := namespaceRepo.GetByName(namespaceName)
+		if err != nil {
+			if errors.Is(err, store.ErrResourceNotFound) {
+				http.Error(w, "namespace "+namespaceName+" not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		namespaces = append(namespaces, namespaceSpec)
+	} else if namespaces, err = namespaceRepo.GetAll(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobIDs := map[uuid.UUID]bool{}
+	for _, namespace := range namespaces {
+		jobSpecs, err := h.jobSvc.GetAll(namespace)
+		if err != nil {
+			http.Error(w, errors.Wrapf(err, "failed to read jobs of %s", namespace.Name).Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, jobSpec := range jobSpecs {
+			jobIDs[jobSpec.ID] = true
+		}
+	}
+
+	// replays of all projects are active in the same queue, the ones of
+	// other projects are left out
+	replays, err := h.replaySpecRepoFac.New(models.JobSpec{}).GetByStatus(job.ReplayStatusToValidate)
+	if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sort.SliceStable(replays, func(i, j int) bool {
+		return replays[i].CreatedAt.Before(replays[j].CreatedAt)
+	})
+	active := map[uuid.UUID]bool{}
+	hasActiveJobs := map[uuid.UUID]bool{}
+	for _, replay := range replays {
+		active[replay.ID] = true
+		hasActiveJobs[replay.ParentID] = true
+	}
+	resp := []ReplayStatusResponse{}
+	for _, replay := range replays {
+		if active[replay.ParentID] {
+			continue
+		}
+		var jobReplays []models.ReplaySpec
+		if hasActiveJobs[replay.ID] {
+			if jobReplays, err = h.replaySpecRepoFac.New(replay.Job).GetByParentID(replay.ID); err != nil &&
+				!errors.Is(err, store.ErrResourceNotFound) {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		if !jobIDs[replay.Job.ID] && !anyReplayOf(jobReplays, jobIDs) {
+			continue
+		}
+		resp = append(resp, compositeReplayStatus(replay, jobReplays, h.replayQueue))
+	}
+	pageStart, pageEnd, nextPageToken := pageOpts.bounds(len(resp))
+	setNextPageToken(w, nextPageToken)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp[pageStart:pageEnd]); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// anyReplayOf returns true if any of the replays is of one of the jobs,
+// plans have no job of their own and belong to projects of their replays
+func anyReplayOf(replays []models.ReplaySpec, jobIDs map[uuid.UUID]bool) bool {
+	for _, replay := range replays {
+		if jobIDs[replay.Job.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+func NewReplayListHandler(replayQueue ReplayQueue, replaySpecRepoFac job.ReplaySpecRepoFactory, jobSvc models.JobService,
+	projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory) *ReplayListHandler {
+	return &ReplayListHandler{
+		replayQueue:          replayQueue,
+		replaySpecRepoFac:    replaySpecRepoFac,
+		jobSvc:               jobSvc,
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+	}
+}
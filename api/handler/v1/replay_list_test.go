@@ -0,0 +1,188 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayListHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	jobSpec := models.JobSpec{ID: uuid.Must(uuid.NewRandom()), Name: "a-job"}
+	otherProjectJobSpec := models.JobSpec{ID: uuid.Must(uuid.NewRandom()), Name: "other-job"}
+	queued := models.ReplaySpec{
+		ID:        uuid.Must(uuid.NewRandom()),
+		Job:       jobSpec,
+		StartDate: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, 5, 22, 0, 0, 0, 0, time.UTC),
+		Status:    models.ReplayStatusAccepted,
+		CreatedAt: time.Date(2021, 5, 23, 11, 0, 0, 0, time.UTC),
+	}
+	running := models.ReplaySpec{
+		ID:        uuid.Must(uuid.NewRandom()),
+		Job:       jobSpec,
+		StartDate: time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, 5, 12, 0, 0, 0, 0, time.UTC),
+		Status:    models.ReplayStatusInProgress,
+		CreatedAt: time.Date(2021, 5, 23, 10, 0, 0, 0, time.UTC),
+	}
+	otherProjectReplay := models.ReplaySpec{
+		ID:        uuid.Must(uuid.NewRandom()),
+		Job:       otherProjectJobSpec,
+		Status:    models.ReplayStatusInProgress,
+		CreatedAt: time.Date(2021, 5, 23, 9, 0, 0, 0, time.UTC),
+	}
+	planID := uuid.Must(uuid.NewRandom())
+	plan := models.ReplaySpec{
+		ID:        planID,
+		Status:    models.ReplayStatusInProgress,
+		CreatedAt: time.Date(2021, 5, 23, 8, 0, 0, 0, time.UTC),
+	}
+	planReplays := []models.ReplaySpec{
+		{ID: uuid.Must(uuid.NewRandom()), Job: jobSpec, Status: models.ReplayStatusSuccess, ParentID: planID},
+		{ID: uuid.Must(uuid.NewRandom()), Job: jobSpec, Status: models.ReplayStatusInProgress, ParentID: planID},
+	}
+	setup := func(replays []models.ReplaySpec, err error) (*mock.ProjectRepoFactory, *mock.NamespaceRepoFactory,
+		*mock.JobService, *mock.ReplaySpecRepoFactory) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		namespaceRepository := new(mock.NamespaceRepository)
+		namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+		namespaceRepository.On("GetByName", "unknown").Return(models.NamespaceSpec{}, store.ErrResourceNotFound)
+		namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+		namespaceRepoFactory.On("New", projectSpec).Return(namespaceRepository)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{jobSpec}, nil)
+
+		replayRepository := new(mock.ReplayRepository)
+		replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return(replays, err)
+		replayRepository.On("GetByParentID", planID).Return(planReplays, nil)
+		replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+		replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+		return projectRepoFactory, namespaceRepoFactory, jobService, replaySpecRepoFac
+	}
+
+	t.Run("should serve active replays of jobs of the project oldest first", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, jobService, replaySpecRepoFac := setup(
+			[]models.ReplaySpec{queued, otherProjectReplay, running}, nil)
+		replayManager := new(mock.ReplayManager)
+		replayManager.On("QueuePosition", queued.ID).Return(models.ReplayQueuePosition{Position: 1}, true)
+		defer replayManager.AssertExpectations(t)
+
+		for _, url := range []string{"/replays?project=a-data-project", "/replays?project=a-data-project&namespace=a-namespace"} {
+			rec := httptest.NewRecorder()
+			v1.NewReplayListHandler(replayManager, replaySpecRepoFac, jobService, projectRepoFactory, namespaceRepoFactory).
+				ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var resp []v1.ReplayStatusResponse
+			assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, []v1.ReplayStatusResponse{
+				{
+					ID:        running.ID.String(),
+					JobName:   "a-job",
+					StartDate: running.StartDate,
+					EndDate:   running.EndDate,
+					Status:    models.ReplayStatusInProgress,
+					CreatedAt: running.CreatedAt,
+				},
+				{
+					ID:            queued.ID.String(),
+					JobName:       "a-job",
+					StartDate:     queued.StartDate,
+					EndDate:       queued.EndDate,
+					Status:        models.ReplayStatusAccepted,
+					CreatedAt:     queued.CreatedAt,
+					QueuePosition: 1,
+				},
+			}, resp)
+		}
+	})
+	t.Run("should serve requested page of active replays", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, jobService, replaySpecRepoFac := setup(
+			[]models.ReplaySpec{queued, otherProjectReplay, running}, nil)
+		replayManager := new(mock.ReplayManager)
+		replayManager.On("QueuePosition", queued.ID).Return(models.ReplayQueuePosition{Position: 1}, true)
+		handler := v1.NewReplayListHandler(replayManager, replaySpecRepoFac, jobService, projectRepoFactory, namespaceRepoFactory)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/replays?project=a-data-project&page_size=1", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var resp []v1.ReplayStatusResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Len(t, resp, 1)
+		assert.Equal(t, running.ID.String(), resp[0].ID)
+		nextPageToken := rec.Header().Get(v1.HeaderNextPageToken)
+		assert.NotEmpty(t, nextPageToken)
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/replays?project=a-data-project&page_size=1&page_token="+nextPageToken, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Len(t, resp, 1)
+		assert.Equal(t, queued.ID.String(), resp[0].ID)
+		assert.Empty(t, rec.Header().Get(v1.HeaderNextPageToken))
+	})
+	t.Run("should reject invalid page size", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v1.NewReplayListHandler(new(mock.ReplayManager), nil, nil, nil, nil).
+			ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/replays?project=a-data-project&page_size=-1", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("should list replays of a plan under the plan", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, jobService, replaySpecRepoFac := setup(
+			[]models.ReplaySpec{planReplays[1], plan}, nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayListHandler(new(mock.ReplayManager), replaySpecRepoFac, jobService, projectRepoFactory, namespaceRepoFactory).
+			ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/replays?project=a-data-project", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp []v1.ReplayStatusResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Len(t, resp, 1)
+		assert.Equal(t, planID.String(), resp[0].ID)
+		assert.Equal(t, 1, resp[0].JobsEnded)
+		assert.Len(t, resp[0].Jobs, 2)
+	})
+	t.Run("should serve empty list if no replay is active", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, jobService, replaySpecRepoFac := setup(nil, store.ErrResourceNotFound)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayListHandler(new(mock.ReplayManager), replaySpecRepoFac, jobService, projectRepoFactory, namespaceRepoFactory).
+			ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/replays?project=a-data-project", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "[]\n", rec.Body.String())
+	})
+	t.Run("should return not found for unknown namespace", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory, jobService, replaySpecRepoFac := setup(nil, nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayListHandler(new(mock.ReplayManager), replaySpecRepoFac, jobService, projectRepoFactory, namespaceRepoFactory).
+			ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/replays?project=a-data-project&namespace=unknown", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,284 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/set"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/status"
+)
+
+// ReplayPlanJob is the replay of a job in a replay plan, id is empty for a
+// dry run
+type ReplayPlanJob struct {
+	ID      string      `json:"id,omitempty"`
+	JobName string      `json:"job_name"`
+	Runs    []time.Time `json:"runs"`
+}
+
+// ReplayPlanResponse lists replays of a replay plan in the order they are
+// processed, id is empty for a dry run
+type ReplayPlanResponse struct {
+	ID      string          `json:"id,omitempty"`
+	Replays []ReplayPlanJob `json:"replays"`
+}
+
+// ReplayPlanHandler replays all jobs of the project in project query param
+// with labels matching selector query param on POST, between start and end
+// query params days. Jobs are replayed one after another, upstream jobs
+// first, tracked under id of the plan. force, ignore_upstream and dry_run
+// query params are booleans. Status of the plan identified by id query param
+// along with statuses of its replays is served on GET, the plan is cancelled
+// along with its replays on DELETE. Plans of frozen projects are rejected
+type ReplayPlanHandler struct {
+	jobSvc               models.JobService
+	quotaSvc             models.QuotaService
+	freezeChecker        FreezeChecker
+	replayQueue          ReplayQueue
+	replayCanceller      ReplayCanceller
+	replaySpecRepoFac    job.ReplaySpecRepoFactory
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+}
+
+func (h *ReplayPlanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var resp interface{}
+	var statusCode int
+	switch r.Method {
+	case http.MethodGet:
+		resp, statusCode, err = h.status(r, projSpec)
+	case http.MethodDelete:
+		resp, statusCode, err = h.cancel(r, projSpec)
+	default:
+		resp, statusCode, err = h.replay(r, projSpec)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *ReplayPlanHandler) replay(r *http.Request, projSpec models.ProjectSpec) (*ReplayPlanResponse, int, error) {
+	query := r.URL.Query()
+	if query.Get("selector") == "" || query.Get("start") == "" || query.Get("end") == "" {
+		return nil, http.StatusBadRequest, errors.New("selector, start and end are required")
+	}
+	selector, err := models.ParseLabelSelector(query.Get("selector"))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	start, err := time.Parse(job.ReplayDateFormat, query.Get("start"))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "unable to parse replay start date")
+	}
+	end, err := time.Parse(job.ReplayDateFormat, query.Get("end"))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "unable to parse replay end date")
+	}
+	if end.Before(start) {
+		return nil, http.StatusBadRequest, errors.New("replay end date cannot be before start date")
+	}
+	planRequest := models.ReplayPlanRequest{
+		Project:  projSpec,
+		Selector: selector,
+		Start:    start,
+		End:      end,
+	}
+	var dryRun bool
+	for param, value := range map[string]*bool{
+		"force":           &planRequest.Force,
+		"ignore_upstream": &planRequest.IgnoreUpstream,
+		"dry_run":         &dryRun,
+	} {
+		if query.Get(param) == "" {
+			continue
+		}
+		if *value, err = strconv.ParseBool(query.Get(param)); err != nil {
+			return nil, http.StatusBadRequest, errors.Wrapf(err, "invalid %s", param)
+		}
+	}
+
+	nodes, err := h.jobSvc.ReplayPlanDryRun(planRequest)
+	if err != nil {
+		if errors.Is(err, job.ErrReplayWindowMisaligned) {
+			return nil, http.StatusBadRequest, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	resp := &ReplayPlanResponse{Replays: []ReplayPlanJob{}}
+	runs := map[string][]time.Time{}
+	replayRuns := 0
+	for _, node := range nodes {
+		runs[node.GetName()] = set.Times(node.Runs)
+		replayRuns += node.Runs.Size()
+		resp.Replays = append(resp.Replays, ReplayPlanJob{JobName: node.GetName(), Runs: runs[node.GetName()]})
+	}
+	if dryRun {
+		return resp, http.StatusOK, nil
+	}
+
+	if h.freezeChecker != nil {
+		if err := h.freezeChecker.Check(projSpec.Name); err != nil {
+			return nil, freezeStatusCode(err), errors.New(status.Convert(err).Message())
+		}
+	}
+	if h.quotaSvc != nil {
+		if err := h.quotaSvc.ReserveReplayRuns(projSpec, replayRuns); err != nil {
+			if errors.Is(err, models.ErrQuotaExceeded) {
+				return nil, http.StatusTooManyRequests, err
+			}
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "failed to check project quota")
+		}
+	}
+	plan, err := h.jobSvc.ReplayPlan(r.Context(), planRequest)
+	if err != nil {
+		if h.quotaSvc != nil {
+			if err := h.quotaSvc.ReleaseReplayRuns(projSpec, replayRuns); err != nil {
+				logger.E(err)
+			}
+		}
+		switch {
+		case errors.Is(err, job.ErrRequestQueueFull):
+			return nil, http.StatusServiceUnavailable, err
+		case errors.Is(err, job.ErrConflictedJobRun), errors.Is(err, job.ErrJobLocked):
+			return nil, http.StatusConflict, err
+		case errors.Is(err, job.ErrReplayWindowMisaligned):
+			return nil, http.StatusBadRequest, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	resp = &ReplayPlanResponse{ID: plan.ID.String(), Replays: []ReplayPlanJob{}}
+	for _, replayRequest := range plan.Replays {
+		resp.Replays = append(resp.Replays, ReplayPlanJob{
+			ID:      replayRequest.ID.String(),
+			JobName: replayRequest.Job.Name,
+			Runs:    runs[replayRequest.Job.Name],
+		})
+	}
+	return resp, http.StatusOK, nil
+}
+
+func (h *ReplayPlanHandler) status(r *http.Request, projSpec models.ProjectSpec) (*ReplayStatusResponse, int, error) {
+	plan, replays, statusCode, err := h.plan(r, projSpec)
+	if err != nil {
+		return nil, statusCode, err
+	}
+	resp := compositeReplayStatus(plan, replays, h.replayQueue)
+	return &resp, http.StatusOK, nil
+}
+
+func (h *ReplayPlanHandler) cancel(r *http.Request, projSpec models.ProjectSpec) (*ReplayStatusResponse, int, error) {
+	plan, _, statusCode, err := h.plan(r, projSpec)
+	if err != nil {
+		return nil, statusCode, err
+	}
+	if err := h.replayCanceller.Cancel(r.Context(), projSpec, plan); err != nil {
+		if errors.Is(err, models.ErrInvalidReplayTransition) {
+			return nil, http.StatusConflict, errors.Errorf("replay plan %s has already ended", plan.ID)
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	return h.status(r, projSpec)
+}
+
+// plan returns the plan identified by id query param along with its
+// replays. Plans are saved as replays without a job, status of plans saved
+// before that is made of statuses of their replays
+func (h *ReplayPlanHandler) plan(r *http.Request, projSpec models.ProjectSpec) (models.ReplaySpec, []models.ReplaySpec, int, error) {
+	planID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		return models.ReplaySpec{}, nil, http.StatusBadRequest, errors.Wrap(err, "invalid replay plan id")
+	}
+	replaySpecRepo := h.replaySpecRepoFac.New(models.JobSpec{})
+	replays, err := replaySpecRepo.GetByParentID(planID)
+	if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+		return models.ReplaySpec{}, nil, http.StatusInternalServerError, err
+	}
+
+	// replays of all projects are in the same table, plans of other
+	// projects are not found
+	namespaces, err := h.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return models.ReplaySpec{}, nil, http.StatusInternalServerError, err
+	}
+	jobIDs := map[uuid.UUID]bool{}
+	for _, namespace := range namespaces {
+		jobSpecs, err := h.jobSvc.GetAll(namespace)
+		if err != nil {
+			return models.ReplaySpec{}, nil, http.StatusInternalServerError, errors.Wrapf(err, "failed to read jobs of %s", namespace.Name)
+		}
+		for _, jobSpec := range jobSpecs {
+			jobIDs[jobSpec.ID] = true
+		}
+	}
+	var planReplays []models.ReplaySpec
+	for _, replay := range replays {
+		if jobIDs[replay.Job.ID] {
+			planReplays = append(planReplays, replay)
+		}
+	}
+	if len(planReplays) == 0 {
+		return models.ReplaySpec{}, nil, http.StatusNotFound, errors.Errorf("replay plan %s not found", planID)
+	}
+
+	plan, err := replaySpecRepo.GetByID(planID)
+	if err != nil {
+		if !errors.Is(err, store.ErrResourceNotFound) {
+			return models.ReplaySpec{}, nil, http.StatusInternalServerError, err
+		}
+		plan = models.ReplaySpec{
+			ID:        planID,
+			StartDate: planReplays[0].StartDate,
+			EndDate:   planReplays[0].EndDate,
+			Status:    models.ReplayPlanStatus(planReplays),
+			CreatedAt: planReplays[0].CreatedAt,
+		}
+	}
+	return plan, planReplays, http.StatusOK, nil
+}
+
+func NewReplayPlanHandler(jobSvc models.JobService, quotaSvc models.QuotaService, freezeChecker FreezeChecker,
+	replayQueue ReplayQueue, replayCanceller ReplayCanceller, replaySpecRepoFac job.ReplaySpecRepoFactory,
+	projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory) *ReplayPlanHandler {
+	return &ReplayPlanHandler{
+		jobSvc:               jobSvc,
+		quotaSvc:             quotaSvc,
+		freezeChecker:        freezeChecker,
+		replayQueue:          replayQueue,
+		replayCanceller:      replayCanceller,
+		replaySpecRepoFac:    replaySpecRepoFac,
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+	}
+}
@@ -0,0 +1,270 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/core/tree"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestReplayPlanHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	upstreamJob := models.JobSpec{ID: uuid.Must(uuid.NewRandom()), Name: "upstream-job"}
+	downstreamJob := models.JobSpec{ID: uuid.Must(uuid.NewRandom()), Name: "downstream-job"}
+	selector, _ := models.ParseLabelSelector("pipeline=revenue")
+	planRequest := models.ReplayPlanRequest{
+		Project:  projectSpec,
+		Selector: selector,
+		Start:    time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2021, 5, 21, 0, 0, 0, 0, time.UTC),
+	}
+	upstreamRuns := []time.Time{time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC), time.Date(2021, 5, 21, 2, 0, 0, 0, time.UTC)}
+	downstreamRuns := []time.Time{time.Date(2021, 5, 21, 0, 0, 0, 0, time.UTC)}
+	nodes := func() []*tree.TreeNode {
+		upstreamNode := tree.NewTreeNode(upstreamJob)
+		for _, run := range upstreamRuns {
+			upstreamNode.Runs.Add(run)
+		}
+		downstreamNode := tree.NewTreeNode(downstreamJob)
+		for _, run := range downstreamRuns {
+			downstreamNode.Runs.Add(run)
+		}
+		return []*tree.TreeNode{upstreamNode, downstreamNode}
+	}
+	setup := func() (*mock.ProjectRepoFactory, *mock.NamespaceRepoFactory) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+		namespaceRepository := new(mock.NamespaceRepository)
+		namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+		namespaceRepoFactory.On("New", projectSpec).Return(namespaceRepository)
+		return projectRepoFactory, namespaceRepoFactory
+	}
+	replayURL := "/replay-plan?project=a-data-project&selector=pipeline%3Drevenue&start=2021-05-20&end=2021-05-21"
+
+	t.Run("should serve runs of jobs in the plan on dry run", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup()
+		jobService := new(mock.JobService)
+		defer jobService.AssertExpectations(t)
+		jobService.On("ReplayPlanDryRun", planRequest).Return(nodes(), nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayPlanHandler(jobService, nil, nil, new(mock.ReplayManager), new(mock.ReplayManager), new(mock.ReplaySpecRepoFactory),
+			projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, replayURL+"&dry_run=true", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ReplayPlanResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.ReplayPlanResponse{
+			Replays: []v1.ReplayPlanJob{
+				{JobName: upstreamJob.Name, Runs: upstreamRuns},
+				{JobName: downstreamJob.Name, Runs: downstreamRuns},
+			},
+		}, resp)
+	})
+	t.Run("should replay jobs in the plan within replay quota", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup()
+		planRequest := planRequest
+		planRequest.Force = true
+
+		plan := &models.ReplayPlan{
+			ID:      uuid.Must(uuid.NewRandom()),
+			Project: projectSpec,
+			Replays: []*models.ReplayWorkerRequest{
+				{ID: uuid.Must(uuid.NewRandom()), Job: upstreamJob},
+				{ID: uuid.Must(uuid.NewRandom()), Job: downstreamJob},
+			},
+		}
+		jobService := new(mock.JobService)
+		defer jobService.AssertExpectations(t)
+		jobService.On("ReplayPlanDryRun", planRequest).Return(nodes(), nil)
+		jobService.On("ReplayPlan", mock2.Anything, planRequest).Return(plan, nil)
+
+		quotaService := new(mock.QuotaService)
+		defer quotaService.AssertExpectations(t)
+		quotaService.On("ReserveReplayRuns", projectSpec, 3).Return(nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayPlanHandler(jobService, quotaService, nil, new(mock.ReplayManager), new(mock.ReplayManager), new(mock.ReplaySpecRepoFactory),
+			projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, replayURL+"&force=true", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ReplayPlanResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.ReplayPlanResponse{
+			ID: plan.ID.String(),
+			Replays: []v1.ReplayPlanJob{
+				{ID: plan.Replays[0].ID.String(), JobName: upstreamJob.Name, Runs: upstreamRuns},
+				{ID: plan.Replays[1].ID.String(), JobName: downstreamJob.Name, Runs: downstreamRuns},
+			},
+		}, resp)
+	})
+	t.Run("should reject plans conflicting with active replays", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup()
+		jobService := new(mock.JobService)
+		defer jobService.AssertExpectations(t)
+		jobService.On("ReplayPlanDryRun", planRequest).Return(nodes(), nil)
+		jobService.On("ReplayPlan", mock2.Anything, planRequest).Return(nil, job.ErrConflictedJobRun)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayPlanHandler(jobService, nil, nil, new(mock.ReplayManager), new(mock.ReplayManager), new(mock.ReplaySpecRepoFactory),
+			projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, replayURL, nil))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+	t.Run("should reject plans of frozen projects and audit them as the caller", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup()
+		jobService := new(mock.JobService)
+		defer jobService.AssertExpectations(t)
+		jobService.On("ReplayPlanDryRun", planRequest).Return(nodes(), nil)
+
+		freezeRepo := new(mock.ProjectFreezeRepository)
+		freezeRepo.On("GetByProject", projectSpec).Return(models.ProjectFreeze{Reason: "release"}, nil)
+		defer freezeRepo.AssertExpectations(t)
+
+		tokenRepo := new(mock.APITokenRepository)
+		tokenRepo.On("GetByHash", mock2.AnythingOfType("string")).Return(models.APIToken{
+			Name:   "ci",
+			Scopes: []models.TokenScope{{Action: models.TokenActionDeploy, Project: models.TokenScopeAllProjects}},
+		}, nil)
+		defer tokenRepo.AssertExpectations(t)
+
+		var recorded *models.AuditEntry
+		auditRepo := new(mock.AuditLogRepository)
+		auditRepo.On("Insert", mock2.AnythingOfType("*models.AuditEntry")).Run(func(args mock2.Arguments) {
+			recorded = args.Get(0).(*models.AuditEntry)
+		}).Return(nil)
+		defer auditRepo.AssertExpectations(t)
+
+		// quota is not reserved and no replay is started
+		handler := v1.NewReplayPlanHandler(jobService, new(mock.QuotaService), v1.NewFreezeGuard(freezeRepo, projectRepoFactory),
+			new(mock.ReplayManager), new(mock.ReplayManager), new(mock.ReplaySpecRepoFactory), projectRepoFactory, namespaceRepoFactory)
+		req := httptest.NewRequest(http.MethodPost, replayURL, nil)
+		req.Header.Set("Authorization", "Bearer opt_secret")
+		rec := httptest.NewRecorder()
+		v1.NewTokenAuthenticator(tokenRepo, false).HTTPHandler(v1.NewAuditLogger(auditRepo).HTTPHandler(handler)).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "project a-data-project is frozen: release\n", rec.Body.String())
+
+		assert.Equal(t, "token/ci", recorded.Actor)
+		assert.Equal(t, "POST /replay-plan", recorded.RPC)
+		assert.Equal(t, "a-data-project", recorded.ProjectName)
+		assert.Equal(t, models.AuditOutcomeFailure, recorded.Outcome)
+		assert.Equal(t, "project a-data-project is frozen: release", recorded.Message)
+	})
+	t.Run("should require a selector", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup()
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayPlanHandler(new(mock.JobService), nil, nil, new(mock.ReplayManager), new(mock.ReplayManager), new(mock.ReplaySpecRepoFactory),
+			projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+			"/replay-plan?project=a-data-project&start=2021-05-20&end=2021-05-21", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("should serve status of a plan along with statuses of its replays", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup()
+		planID := uuid.Must(uuid.NewRandom())
+		succeeded := models.ReplaySpec{
+			ID:        uuid.Must(uuid.NewRandom()),
+			Job:       upstreamJob,
+			StartDate: planRequest.Start,
+			EndDate:   planRequest.End,
+			Status:    models.ReplayStatusSuccess,
+			CreatedAt: time.Date(2021, 5, 23, 10, 0, 0, 0, time.UTC),
+			ParentID:  planID,
+		}
+		running := succeeded
+		running.ID = uuid.Must(uuid.NewRandom())
+		running.Job = downstreamJob
+		running.Status = models.ReplayStatusInProgress
+
+		jobService := new(mock.JobService)
+		defer jobService.AssertExpectations(t)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{upstreamJob, downstreamJob}, nil)
+		replayRepository := new(mock.ReplayRepository)
+		replayRepository.On("GetByParentID", planID).Return([]models.ReplaySpec{succeeded, running}, nil)
+		replayRepository.On("GetByID", planID).Return(models.ReplaySpec{
+			ID:        planID,
+			StartDate: planRequest.Start,
+			EndDate:   planRequest.End,
+			Status:    models.ReplayStatusInProgress,
+		}, nil)
+		replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+		replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayPlanHandler(jobService, nil, nil, new(mock.ReplayManager), new(mock.ReplayManager), replaySpecRepoFac, projectRepoFactory,
+			namespaceRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/replay-plan?project=a-data-project&id="+planID.String(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ReplayStatusResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, planID.String(), resp.ID)
+		assert.Equal(t, models.ReplayStatusInProgress, resp.Status)
+		assert.Equal(t, 1, resp.JobsEnded)
+		assert.Len(t, resp.Jobs, 2)
+		assert.Equal(t, models.ReplayStatusSuccess, resp.Jobs[0].Status)
+	})
+	t.Run("should cancel a plan along with its replays", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup()
+		planID := uuid.Must(uuid.NewRandom())
+		plan := models.ReplaySpec{ID: planID, Status: models.ReplayStatusAccepted}
+		replay := models.ReplaySpec{ID: uuid.Must(uuid.NewRandom()), Job: upstreamJob, Status: models.ReplayStatusAccepted, ParentID: planID}
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{upstreamJob}, nil)
+		replayRepository := new(mock.ReplayRepository)
+		replayRepository.On("GetByParentID", planID).Return([]models.ReplaySpec{replay}, nil)
+		replayRepository.On("GetByID", planID).Return(plan, nil)
+		replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+		replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+		replayManager := new(mock.ReplayManager)
+		defer replayManager.AssertExpectations(t)
+		replayManager.On("Cancel", mock2.Anything, projectSpec, plan).Return(nil)
+		replayManager.On("QueuePosition", mock2.Anything).Return(models.ReplayQueuePosition{}, false)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayPlanHandler(jobService, nil, nil, replayManager, replayManager, replaySpecRepoFac, projectRepoFactory,
+			namespaceRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodDelete,
+			"/replay-plan?project=a-data-project&id="+planID.String(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("should not find plans of other projects", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup()
+		planID := uuid.Must(uuid.NewRandom())
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{upstreamJob}, nil)
+		replayRepository := new(mock.ReplayRepository)
+		replayRepository.On("GetByParentID", planID).Return([]models.ReplaySpec{
+			{ID: uuid.Must(uuid.NewRandom()), Job: models.JobSpec{ID: uuid.Must(uuid.NewRandom())}, ParentID: planID},
+		}, nil)
+		replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+		replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayPlanHandler(jobService, nil, nil, new(mock.ReplayManager), new(mock.ReplayManager), replaySpecRepoFac, projectRepoFactory,
+			namespaceRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/replay-plan?project=a-data-project&id="+planID.String(), nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
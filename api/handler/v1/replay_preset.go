@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"context"
+
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// MetadataReplayPreset set in request metadata of replay and replay dry
+	// run names the replay preset of job to use. Its window is replayed if
+	// the request has no start date, its selector if the request has none
+	// and it forces the replay if set in preset
+	MetadataReplayPreset = "x-replay-preset"
+
+	// MetadataReplayChunks are sent in response header of replay dry run of
+	// a preset replayed in chunks, each one as start/end of a replay request.
+	// Clients replay chunks one after another with the preset
+	MetadataReplayChunks = "x-replay-chunks"
+)
+
+// replayPreset looks up the replay preset of job named in request metadata,
+// nil if none is named
+func replayPreset(ctx context.Context, jobSpec models.JobSpec) (*models.JobSpecReplayPreset, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	vals := md.Get(MetadataReplayPreset)
+	if len(vals) == 0 || vals[0] == "" {
+		return nil, nil
+	}
+	preset, ok := jobSpec.Behavior.ReplayPresets[vals[0]]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "replay preset %s not found for job %s", vals[0], jobSpec.Name)
+	}
+	return &preset, nil
+}
+
+// sendReplayChunks attaches chunks of a preset replay in response header if
+// the preset splits its window into more than one, it is a no-op if there is
+// no grpc stream attached to the context
+func sendReplayChunks(ctx context.Context, req *pb.ReplayRequest, replayRequest *models.ReplayWorkerRequest) error {
+	preset, err := replayPreset(ctx, replayRequest.Job)
+	if err != nil || preset == nil || req.StartDate != "" {
+		return err
+	}
+	chunks := job.SplitReplayWindow(replayRequest.Start, replayRequest.End, preset.ChunkDays)
+	if len(chunks) < 2 {
+		return nil
+	}
+	md := metadata.MD{}
+	for _, chunk := range chunks {
+		md.Append(MetadataReplayChunks, chunk.String())
+	}
+	_ = grpc.SetHeader(ctx, md)
+	return nil
+}
@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// ReplayQueue tells where an accepted replay waits in the request queue
+type ReplayQueue interface {
+	QueuePosition(replayID uuid.UUID) (models.ReplayQueuePosition, bool)
+}
+
+// ReplayCanceller cancels an active replay along with its children
+type ReplayCanceller interface {
+	Cancel(context.Context, models.ProjectSpec, models.ReplaySpec) error
+}
+
+// ReplayStatusResponse is the status of a replay served over http, queue
+// position and eta are only set while the replay waits for a worker. Replays
+// of more than one job and plans list the status of each of their jobs
+type ReplayStatusResponse struct {
+	ID            string    `json:"id"`
+	JobName       string    `json:"job_name"`
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	Status        string    `json:"status"`
+	Message       string    `json:"message,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	QueuePosition int       `json:"queue_position,omitempty"`
+	// QueueETA is the estimated time till a worker picks up the replay,
+	// empty if it can't be estimated yet
+	QueueETA string `json:"queue_eta,omitempty"`
+	// Jobs are replays of each job of the replay, JobsEnded is the number
+	// of them which have ended
+	Jobs      []ReplayStatusResponse `json:"jobs,omitempty"`
+	JobsEnded int                    `json:"jobs_ended,omitempty"`
+}
+
+// ReplayStatusHandler serves status of a replay identified by id query param
+// of the job identified by project and job query params on GET, the replay is
+// cancelled along with its jobs on DELETE
+type ReplayStatusHandler struct {
+	replayQueue        ReplayQueue
+	replayCanceller    ReplayCanceller
+	replaySpecRepoFac  job.ReplaySpecRepoFactory
+	jobSvc             models.JobService
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *ReplayStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	jobName := r.URL.Query().Get("job")
+	if projectName == "" || jobName == "" {
+		http.Error(w, "project and job are required", http.StatusBadRequest)
+		return
+	}
+	replayID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "invalid replay id: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	jobSpec, _, err := h.jobSvc.GetByNameForProject(jobName, projSpec)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "job "+jobName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	replaySpecRepo := h.replaySpecRepoFac.New(jobSpec)
+	replay, err := replaySpecRepo.GetByID(replayID)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "replay "+replayID.String()+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if r.Method == http.MethodDelete {
+		if err := h.replayCanceller.Cancel(r.Context(), projSpec, replay); err != nil {
+			if errors.Is(err, models.ErrInvalidReplayTransition) {
+				http.Error(w, "replay "+replayID.String()+" has already ended", http.StatusConflict)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if replay, err = replaySpecRepo.GetByID(replayID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	jobReplays, err := replaySpecRepo.GetByParentID(replayID)
+	if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := compositeReplayStatus(replay, jobReplays, h.replayQueue)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// replayStatus is the status of replay served over http, with its place in
+// queue while it waits for a worker
+func replayStatus(replay models.ReplaySpec, replayQueue ReplayQueue) ReplayStatusResponse {
+	resp := ReplayStatusResponse{
+		ID:        replay.ID.String(),
+		JobName:   replay.Job.Name,
+		StartDate: replay.StartDate,
+		EndDate:   replay.EndDate,
+		Status:    replay.Status,
+		Message:   replay.Message.Message,
+		CreatedAt: replay.CreatedAt,
+	}
+	if replay.Status == models.ReplayStatusAccepted {
+		if position, ok := replayQueue.QueuePosition(replay.ID); ok {
+			resp.QueuePosition = position.Position
+			if position.ETA > 0 {
+				resp.QueueETA = position.ETA.Round(time.Second).String()
+			}
+		}
+	}
+	return resp
+}
+
+// compositeReplayStatus is the status of a replay along with the status of
+// replays of each of its jobs
+func compositeReplayStatus(replay models.ReplaySpec, jobReplays []models.ReplaySpec, replayQueue ReplayQueue) ReplayStatusResponse {
+	resp := replayStatus(replay, replayQueue)
+	for _, jobReplay := range jobReplays {
+		resp.Jobs = append(resp.Jobs, replayStatus(jobReplay, replayQueue))
+		if models.IsReplayEnded(jobReplay.Status) {
+			resp.JobsEnded++
+		}
+	}
+	return resp
+}
+
+func NewReplayStatusHandler(replayQueue ReplayQueue, replayCanceller ReplayCanceller, replaySpecRepoFac job.ReplaySpecRepoFactory,
+	jobSvc models.JobService, projectRepoFactory ProjectRepoFactory) *ReplayStatusHandler {
+	return &ReplayStatusHandler{
+		replayQueue:        replayQueue,
+		replayCanceller:    replayCanceller,
+		replaySpecRepoFac:  replaySpecRepoFac,
+		jobSvc:             jobSvc,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,164 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestReplayStatusHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-job",
+	}
+	replayID := uuid.Must(uuid.NewRandom())
+	replaySpec := models.ReplaySpec{
+		ID:        replayID,
+		Job:       jobSpec,
+		StartDate: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2021, 5, 22, 0, 0, 0, 0, time.UTC),
+		Status:    models.ReplayStatusAccepted,
+		CreatedAt: time.Date(2021, 5, 23, 10, 0, 0, 0, time.UTC),
+	}
+	setup := func(replay models.ReplaySpec, err error, jobReplays ...models.ReplaySpec) (*mock.ProjectRepoFactory, *mock.JobService,
+		*mock.ReplaySpecRepoFactory) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+
+		replayRepository := new(mock.ReplayRepository)
+		replayRepository.On("GetByID", replayID).Return(replay, err)
+		replayRepository.On("GetByParentID", replayID).Return(jobReplays, nil)
+		replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+		replaySpecRepoFac.On("New", jobSpec).Return(replayRepository)
+		return projectRepoFactory, jobService, replaySpecRepoFac
+	}
+	url := "/replay-status?project=a-data-project&job=a-job&id=" + replayID.String()
+
+	t.Run("should serve position and eta of replay waiting in queue", func(t *testing.T) {
+		projectRepoFactory, jobService, replaySpecRepoFac := setup(replaySpec, nil)
+		replayManager := new(mock.ReplayManager)
+		replayManager.On("QueuePosition", replayID).Return(models.ReplayQueuePosition{
+			Position: 3,
+			ETA:      time.Minute * 4,
+		}, true)
+		defer replayManager.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayStatusHandler(replayManager, replayManager, replaySpecRepoFac, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ReplayStatusResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.ReplayStatusResponse{
+			ID:            replayID.String(),
+			JobName:       "a-job",
+			StartDate:     replaySpec.StartDate,
+			EndDate:       replaySpec.EndDate,
+			Status:        models.ReplayStatusAccepted,
+			CreatedAt:     replaySpec.CreatedAt,
+			QueuePosition: 3,
+			QueueETA:      "4m0s",
+		}, resp)
+	})
+	t.Run("should serve status of replay picked up by a worker", func(t *testing.T) {
+		inProgress := replaySpec
+		inProgress.Status = models.ReplayStatusInProgress
+		projectRepoFactory, jobService, replaySpecRepoFac := setup(inProgress, nil)
+		replayManager := new(mock.ReplayManager)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayStatusHandler(replayManager, replayManager, replaySpecRepoFac, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ReplayStatusResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, models.ReplayStatusInProgress, resp.Status)
+		assert.Equal(t, 0, resp.QueuePosition)
+	})
+	t.Run("should return not found for replay of another job", func(t *testing.T) {
+		projectRepoFactory, jobService, replaySpecRepoFac := setup(models.ReplaySpec{}, store.ErrResourceNotFound)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayStatusHandler(new(mock.ReplayManager), new(mock.ReplayManager), replaySpecRepoFac, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+	t.Run("should serve status of each job of a replay of more than one job", func(t *testing.T) {
+		inProgress := replaySpec
+		inProgress.Status = models.ReplayStatusInProgress
+		succeeded := models.ReplaySpec{
+			ID:       uuid.Must(uuid.NewRandom()),
+			Job:      jobSpec,
+			Status:   models.ReplayStatusSuccess,
+			ParentID: replayID,
+		}
+		accepted := models.ReplaySpec{
+			ID:       uuid.Must(uuid.NewRandom()),
+			Job:      models.JobSpec{Name: "a-downstream-job"},
+			Status:   models.ReplayStatusAccepted,
+			ParentID: replayID,
+		}
+		projectRepoFactory, jobService, replaySpecRepoFac := setup(inProgress, nil, succeeded, accepted)
+		replayManager := new(mock.ReplayManager)
+		replayManager.On("QueuePosition", accepted.ID).Return(models.ReplayQueuePosition{}, false)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayStatusHandler(replayManager, replayManager, replaySpecRepoFac, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ReplayStatusResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, models.ReplayStatusInProgress, resp.Status)
+		assert.Equal(t, 1, resp.JobsEnded)
+		assert.Len(t, resp.Jobs, 2)
+		assert.Equal(t, "a-downstream-job", resp.Jobs[1].JobName)
+		assert.Equal(t, models.ReplayStatusAccepted, resp.Jobs[1].Status)
+	})
+	t.Run("should cancel a replay", func(t *testing.T) {
+		inProgress := replaySpec
+		inProgress.Status = models.ReplayStatusInProgress
+		projectRepoFactory, jobService, replaySpecRepoFac := setup(inProgress, nil)
+		replayManager := new(mock.ReplayManager)
+		replayManager.On("Cancel", mock2.Anything, projectSpec, inProgress).Return(nil)
+		defer replayManager.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayStatusHandler(replayManager, replayManager, replaySpecRepoFac, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodDelete, url, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("should not cancel an ended replay", func(t *testing.T) {
+		succeeded := replaySpec
+		succeeded.Status = models.ReplayStatusSuccess
+		projectRepoFactory, jobService, replaySpecRepoFac := setup(succeeded, nil)
+		replayManager := new(mock.ReplayManager)
+		replayManager.On("Cancel", mock2.Anything, projectSpec, succeeded).Return(models.ErrInvalidReplayTransition)
+
+		rec := httptest.NewRecorder()
+		v1.NewReplayStatusHandler(replayManager, replayManager, replaySpecRepoFac, jobService, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodDelete, url, nil))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
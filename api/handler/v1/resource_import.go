@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// ImportedResourceResponse is a resource spec as it's written in a
+// repository, spec in yaml along with its assets
+type ImportedResourceResponse struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Spec   string            `json:"spec"`
+	Assets map[string]string `json:"assets,omitempty"`
+}
+
+type ResourceImportResponse struct {
+	Resources []ImportedResourceResponse `json:"resources"`
+	Skipped   []string                   `json:"skipped,omitempty"`
+}
+
+// ResourceImportHandler describes existing resources of a datastore, ones not
+// created by optimus, as resource specs so they can be added to a repository.
+// GET with project, datastore and dataset query params imports the dataset
+// along with the resources it holds
+type ResourceImportHandler struct {
+	dsRepo             models.DatastoreRepo
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *ResourceImportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	dataset := r.URL.Query().Get("dataset")
+	if dataset == "" {
+		http.Error(w, "dataset is required", http.StatusBadRequest)
+		return
+	}
+	datastoreName := r.URL.Query().Get("datastore")
+	if datastoreName == "" {
+		datastoreName = "bigquery"
+	}
+
+	ds, err := h.dsRepo.GetByName(datastoreName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	importer, ok := ds.(models.DatastoreImporter)
+	if !ok {
+		http.Error(w, "datastore "+datastoreName+" does not support importing resources", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	imported, err := importer.ImportDataset(r.Context(), models.ImportDatasetRequest{
+		Dataset: dataset,
+		Project: projSpec,
+	})
+	if err != nil {
+		http.Error(w, errors.Wrapf(err, "failed to import %s", dataset).Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := ResourceImportResponse{
+		Resources: []ImportedResourceResponse{},
+		Skipped:   imported.Skipped,
+	}
+	for _, resourceSpec := range imported.Resources {
+		typeController, ok := ds.Types()[resourceSpec.Type]
+		if !ok {
+			resp.Skipped = append(resp.Skipped, resourceSpec.Name)
+			continue
+		}
+		specBytes, err := typeController.Adapter().ToYaml(resourceSpec)
+		if err != nil {
+			http.Error(w, errors.Wrapf(err, "failed to serialize %s", resourceSpec.Name).Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Resources = append(resp.Resources, ImportedResourceResponse{
+			Name:   resourceSpec.Name,
+			Type:   resourceSpec.Type.String(),
+			Spec:   string(specBytes),
+			Assets: resourceSpec.Assets,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func NewResourceImportHandler(dsRepo models.DatastoreRepo, projectRepoFactory ProjectRepoFactory) *ResourceImportHandler {
+	return &ResourceImportHandler{
+		dsRepo:             dsRepo,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
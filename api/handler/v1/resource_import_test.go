@@ -0,0 +1,94 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestResourceImportHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	projectRepository := new(mock.ProjectRepository)
+	projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+	projectRepoFactory := new(mock.ProjectRepoFactory)
+	projectRepoFactory.On("New").Return(projectRepository)
+
+	t.Run("should return specs of imported resources in yaml", func(t *testing.T) {
+		datasetSpec := models.ResourceSpec{Version: 1, Name: "proj.playground", Type: models.ResourceTypeDataset}
+		viewSpec := models.ResourceSpec{
+			Version: 1,
+			Name:    "proj.playground.daily_events",
+			Type:    models.ResourceTypeView,
+			Assets:  map[string]string{"view.sql": "select 1"},
+		}
+		adapter := new(mock.DatastoreTypeAdapter)
+		adapter.On("ToYaml", datasetSpec).Return([]byte("name: proj.playground\n"), nil)
+		adapter.On("ToYaml", viewSpec).Return([]byte("name: proj.playground.daily_events\n"), nil)
+		typeController := new(mock.DatastoreTypeController)
+		typeController.On("Adapter").Return(adapter)
+
+		importer := new(mock.DatastoreImporter)
+		defer importer.AssertExpectations(t)
+		importer.On("Types").Return(map[models.ResourceType]models.DatastoreTypeController{
+			models.ResourceTypeDataset: typeController,
+			models.ResourceTypeView:    typeController,
+		})
+		importer.On("ImportDataset", mock2.Anything, models.ImportDatasetRequest{
+			Dataset: "proj.playground",
+			Project: projectSpec,
+		}).Return(models.ImportDatasetResponse{
+			Resources: []models.ResourceSpec{datasetSpec, viewSpec},
+			Skipped:   []string{"proj.playground.events_lake"},
+		}, nil)
+		dsRepo := new(mock.SupportedDatastoreRepo)
+		dsRepo.On("GetByName", "bigquery").Return(importer, nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewResourceImportHandler(dsRepo, projectRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/resource-import?project=a-data-project&datastore=bigquery&dataset=proj.playground", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.ResourceImportResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.ResourceImportResponse{
+			Resources: []v1.ImportedResourceResponse{
+				{Name: "proj.playground", Type: "dataset", Spec: "name: proj.playground\n"},
+				{
+					Name:   "proj.playground.daily_events",
+					Type:   "view",
+					Spec:   "name: proj.playground.daily_events\n",
+					Assets: map[string]string{"view.sql": "select 1"},
+				},
+			},
+			Skipped: []string{"proj.playground.events_lake"},
+		}, resp)
+	})
+	t.Run("should reject datastores which can't import", func(t *testing.T) {
+		datastorer := new(mock.Datastorer)
+		dsRepo := new(mock.SupportedDatastoreRepo)
+		dsRepo.On("GetByName", "gcs").Return(datastorer, nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewResourceImportHandler(dsRepo, projectRepoFactory).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/resource-import?project=a-data-project&datastore=gcs&dataset=bucket", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "datastore gcs does not support importing resources\n", rec.Body.String())
+	})
+	t.Run("should require dataset", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v1.NewResourceImportHandler(new(mock.SupportedDatastoreRepo), projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodGet, "/resource-import?project=a-data-project", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// ResourceRestorer starts restores of resources as operations
+type ResourceRestorer interface {
+	Restore(project models.ProjectSpec, actor string, restore models.ResourceRestore) (models.Operation, error)
+}
+
+// ResourceRestoreRequest restores a resource as it was at PointInTime into
+// Destination, or in place if Destination is empty
+type ResourceRestoreRequest struct {
+	Datastore   string    `json:"datastore"`
+	Resource    string    `json:"resource"`
+	Destination string    `json:"destination,omitempty"`
+	PointInTime time.Time `json:"point_in_time"`
+}
+
+// ResourceRestoreHandler restores resources of a project identified by
+// project query param. POST with a ResourceRestoreRequest starts a restore
+// as an operation and returns it, progress of the restore is served by
+// operations endpoint
+type ResourceRestoreHandler struct {
+	restorer           ResourceRestorer
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *ResourceRestoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	var req ResourceRestoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, errors.Wrap(err, "invalid restore").Error(), http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	op, err := h.restorer.Restore(projSpec, r.Header.Get(MetadataActor), models.ResourceRestore{
+		Datastore:    req.Datastore,
+		ResourceName: req.Resource,
+		Destination:  req.Destination,
+		PointInTime:  req.PointInTime,
+	})
+	if err != nil {
+		if errors.Is(err, operation.ErrManagerClosed) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeOperationAccepted(w, projSpec, op)
+}
+
+func NewResourceRestoreHandler(restorer ResourceRestorer, projectRepoFactory ProjectRepoFactory) *ResourceRestoreHandler {
+	return &ResourceRestoreHandler{
+		restorer:           restorer,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,67 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceRestoreHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	projectRepository := new(mock.ProjectRepository)
+	projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+	projectRepoFactory := new(mock.ProjectRepoFactory)
+	projectRepoFactory.On("New").Return(projectRepository)
+	restore := models.ResourceRestore{
+		Datastore:    "bigquery",
+		ResourceName: "proj.datas.events",
+		PointInTime:  time.Date(2021, 6, 2, 8, 0, 0, 0, time.UTC),
+	}
+	body := `{"datastore": "bigquery", "resource": "proj.datas.events", "point_in_time": "2021-06-02T08:00:00Z"}`
+
+	t.Run("should start restore as an operation and return it", func(t *testing.T) {
+		operationID := uuid.Must(uuid.NewRandom())
+		restorer := new(mock.ResourceRestorer)
+		defer restorer.AssertExpectations(t)
+		restorer.On("Restore", projectSpec, "jane@example.com", restore).Return(models.Operation{
+			ID:     operationID,
+			Kind:   models.OperationKindResourceRestore,
+			Status: models.OperationStatusPending,
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/resource-restore?project=a-data-project", strings.NewReader(body))
+		req.Header.Set(v1.MetadataActor, "jane@example.com")
+		rec := httptest.NewRecorder()
+		v1.NewResourceRestoreHandler(restorer, projectRepoFactory).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "/operations?project=a-data-project&id="+operationID.String(), rec.Header().Get("Location"))
+
+		var resp v1.OperationResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, operationID.String(), resp.ID)
+		assert.Equal(t, models.OperationKindResourceRestore, resp.Kind)
+		assert.Equal(t, models.OperationStatusPending, resp.Status)
+	})
+	t.Run("should be unavailable while server is shutting down", func(t *testing.T) {
+		restorer := new(mock.ResourceRestorer)
+		restorer.On("Restore", projectSpec, "", restore).Return(models.Operation{}, operation.ErrManagerClosed)
+
+		rec := httptest.NewRecorder()
+		v1.NewResourceRestoreHandler(restorer, projectRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPost, "/resource-restore?project=a-data-project", strings.NewReader(body)))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
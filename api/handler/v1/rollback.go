@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// MetadataRollbackTo set in request metadata of job deployment redeploys
+	// jobs of a previous deployment of the namespace instead of the jobs in
+	// request. It is either the id of deployment or RollbackPrevious
+	MetadataRollbackTo = "x-rollback-to"
+
+	// RollbackPrevious rolls back to the latest successful deployment with
+	// jobs different from the ones deployed last
+	RollbackPrevious = "previous"
+
+	// deployments looked up while searching for the previous one
+	rollbackSearchLimit = 50
+)
+
+func rollbackTarget(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if vals := md.Get(MetadataRollbackTo); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// rollbackJobs returns jobs of the deployment target points to, along with
+// the id of deployment
+func (sv *RuntimeServiceServer) rollbackJobs(projSpec models.ProjectSpec, namespaceSpec models.NamespaceSpec,
+	target string) ([]*pb.JobSpecification, uuid.UUID, error) {
+	if sv.changelogRepo == nil {
+		return nil, uuid.Nil, status.Error(codes.FailedPrecondition, "deployment history is not recorded by server")
+	}
+
+	var targetID uuid.UUID
+	if target == RollbackPrevious {
+		changelogs, err := sv.changelogRepo.GetLatest(projSpec, namespaceSpec.Name, rollbackSearchLimit)
+		if err != nil {
+			return nil, uuid.Nil, status.Errorf(codes.Internal, "%s: failed to fetch deployments of namespace %s", err.Error(), namespaceSpec.Name)
+		}
+		for i, changelog := range changelogs {
+			if i == 0 || changelog.Failed || changelog.SnapshotHash == "" || changelog.SnapshotHash == changelogs[0].SnapshotHash {
+				continue
+			}
+			targetID = changelog.ID
+			break
+		}
+		if targetID == uuid.Nil {
+			return nil, uuid.Nil, status.Errorf(codes.NotFound, "no previous deployment of namespace %s to roll back to", namespaceSpec.Name)
+		}
+	} else {
+		var err error
+		if targetID, err = uuid.Parse(target); err != nil {
+			return nil, uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid deployment id %s", target)
+		}
+	}
+
+	changelog, err := sv.changelogRepo.GetByID(projSpec, targetID)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, uuid.Nil, status.Errorf(codes.NotFound, "deployment %s not found", targetID)
+		}
+		return nil, uuid.Nil, status.Errorf(codes.Internal, "%s: failed to fetch deployment %s", err.Error(), targetID)
+	}
+	if changelog.Namespace != namespaceSpec.Name {
+		return nil, uuid.Nil, status.Errorf(codes.InvalidArgument, "deployment %s is of namespace %s", targetID, changelog.Namespace)
+	}
+	if !changelog.CanRollbackTo() {
+		return nil, uuid.Nil, status.Errorf(codes.FailedPrecondition, "deployment %s failed or has no snapshot to roll back to", targetID)
+	}
+	var snapshot pb.DeployJobSpecificationRequest
+	if err := proto.Unmarshal(changelog.Snapshot, &snapshot); err != nil {
+		return nil, uuid.Nil, status.Errorf(codes.Internal, "%s: failed to read snapshot of deployment %s", err.Error(), targetID)
+	}
+	return snapshot.GetJobs(), targetID, nil
+}
+
+// jobSnapshot serializes jobs of a deployment, hash of snapshot is same
+// for same jobs irrespective of their order
+func jobSnapshot(jobs []*pb.JobSpecification) ([]byte, string, error) {
+	sorted := make([]*pb.JobSpecification, len(jobs))
+	copy(sorted, jobs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+	snapshot, err := proto.MarshalOptions{Deterministic: true}.Marshal(&pb.DeployJobSpecificationRequest{
+		Jobs: sorted,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	hash := sha256.Sum256(snapshot)
+	return snapshot, hex.EncodeToString(hash[:]), nil
+}
+
+// jobResultObserver records the outcome of deploying each job
+type jobResultObserver struct {
+	mu      sync.Mutex
+	results []models.JobDeployResult
+}
+
+func (obs *jobResultObserver) Notify(e progress.Event) {
+	evt, ok := e.(*job.EventJobUpload)
+	if !ok {
+		return
+	}
+	result := models.JobDeployResult{
+		Name:    evt.Job.Name,
+		Success: evt.Err == nil,
+	}
+	if evt.Err != nil {
+		result.Message = evt.Err.Error()
+	}
+	obs.mu.Lock()
+	obs.results = append(obs.results, result)
+	obs.mu.Unlock()
+}
+
+func (obs *jobResultObserver) failed() bool {
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	for _, result := range obs.results {
+		if !result.Success {
+			return true
+		}
+	}
+	return false
+}
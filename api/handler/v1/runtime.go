@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,7 +17,9 @@ import (
 	"github.com/odpf/optimus/datastore"
 
 	"github.com/golang/protobuf/ptypes"
+	"github.com/google/uuid"
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/cron"
 	"github.com/odpf/optimus/core/logger"
 	log "github.com/odpf/optimus/core/logger"
 	"github.com/odpf/optimus/core/progress"
@@ -26,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -45,6 +49,13 @@ type JobEventService interface {
 	Register(context.Context, models.NamespaceSpec, models.JobSpec, models.JobEvent) error
 }
 
+// RunCostCollector records cost of the datastore jobs a run of a job
+// reported, see job.CostCollector
+type RunCostCollector interface {
+	Collect(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec,
+		scheduledAt time.Time, datastoreJobs []string) error
+}
+
 type ProtoAdapter interface {
 	FromJobProto(*pb.JobSpecification) (models.JobSpec, error)
 	ToJobProto(models.JobSpec) (*pb.JobSpecification, error)
@@ -76,6 +87,10 @@ type RuntimeServiceServer struct {
 	secretRepoFactory    SecretRepoFactory
 	instSvc              models.InstanceService
 	scheduler            models.SchedulerUnit
+	quotaSvc             models.QuotaService
+	changelogRepo        store.DeployChangelogRepository
+	costCollector        RunCostCollector
+	commitStatusSvc      models.CommitStatusPublisher
 
 	progressObserver progress.Observer
 	Now              func() time.Time
@@ -91,7 +106,7 @@ func (sv *RuntimeServiceServer) Version(ctx context.Context, version *pb.Version
 	return response, nil
 }
 
-func (sv *RuntimeServiceServer) DeployJobSpecification(req *pb.DeployJobSpecificationRequest, respStream pb.RuntimeService_DeployJobSpecificationServer) error {
+func (sv *RuntimeServiceServer) DeployJobSpecification(req *pb.DeployJobSpecificationRequest, respStream pb.RuntimeService_DeployJobSpecificationServer) (err error) {
 	startTime := time.Now()
 
 	projectRepo := sv.projectRepoFactory.New()
@@ -106,40 +121,162 @@ func (sv *RuntimeServiceServer) DeployJobSpecification(req *pb.DeployJobSpecific
 		return status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
 	}
 
+	results := new(jobResultObserver)
+	if ref, ok := commitRef(respStream.Context()); ok {
+		defer func() {
+			sv.publishDeployStatus(projSpec, namespaceSpec, ref, results, err)
+		}()
+	}
+
+	reqJobs := req.GetJobs()
+	var rollbackOf uuid.UUID
+	if target := rollbackTarget(respStream.Context()); target != "" {
+		if reqJobs, rollbackOf, err = sv.rollbackJobs(projSpec, namespaceSpec, target); err != nil {
+			return err
+		}
+	}
+
+	canary, err := canaryMode(respStream.Context())
+	if err != nil {
+		return err
+	}
+	selector, err := labelSelector(respStream.Context())
+	if err != nil {
+		return err
+	}
+	if !selector.Empty() && rollbackTarget(respStream.Context()) != "" {
+		return status.Error(codes.InvalidArgument, "rollback redeploys all jobs of a deployment, it can't be limited by label selector")
+	}
+
+	// jobs before deployment are needed to analyze schedule changes, compute
+	// the changelog, canary and the jobs left out of a deployment limited by
+	// label selector
+	previousJobs, err := sv.jobSvc.GetAll(namespaceSpec)
+	if err != nil {
+		return status.Errorf(codes.Internal, "%s: failed to retrieve jobs for namespace %s", err.Error(), req.GetNamespace())
+	}
+
 	var jobsToKeep []models.JobSpec
-	for _, reqJob := range req.GetJobs() {
+	requestedJobs := map[string]bool{}
+	for _, reqJob := range reqJobs {
 		adaptJob, err := sv.adapter.FromJobProto(reqJob)
 		if err != nil {
 			return status.Errorf(codes.Internal, "%s: cannot adapt job %s", err.Error(), reqJob.GetName())
 		}
+		if !selector.Matches(adaptJob.Labels) {
+			return status.Errorf(codes.InvalidArgument, "job %s doesn't match label selector %s", adaptJob.Name, selector.String())
+		}
+		jobsToKeep = append(jobsToKeep, adaptJob)
+		requestedJobs[adaptJob.Name] = true
+	}
 
-		err = sv.jobSvc.Create(namespaceSpec, adaptJob)
+	// jobs not matching the selector stay as they are, the rest not sent
+	// for deployment will be deleted
+	namespaceJobs := jobsToKeep
+	snapshotJobs := reqJobs
+	for _, unselected := range unselectedJobs(selector, previousJobs) {
+		if requestedJobs[unselected.Name] {
+			continue
+		}
+		unselectedProto, err := sv.adapter.ToJobProto(unselected)
 		if err != nil {
+			return status.Errorf(codes.Internal, "%s: cannot adapt job %s", err.Error(), unselected.Name)
+		}
+		namespaceJobs = append(namespaceJobs, unselected)
+		snapshotJobs = append(snapshotJobs, unselectedProto)
+	}
+	if err := checkJobNaming(projSpec, jobsToKeep); err != nil {
+		return err
+	}
+	if err := sv.checkJobQuota(projSpec, namespaceSpec, len(namespaceJobs)); err != nil {
+		return err
+	}
+	if err := sv.jobSvc.CheckDestinations(namespaceSpec, jobsToKeep); err != nil {
+		if errors.Is(err, job.ErrDestinationConflict) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return status.Errorf(codes.Internal, "%s: failed to check destinations of jobs", err.Error())
+	}
+	if err := checkScheduleImpact(previousJobs, namespaceJobs, sv.Now(), respStream); err != nil {
+		return err
+	}
+
+	syncObserver := &jobSyncObserver{
+		stream: respStream,
+		log:    logrus.New(),
+	}
+	if canary != "" {
+		canaryObservers := new(progress.ObserverChain)
+		canaryObservers.Join(sv.progressObserver)
+		canaryObservers.Join(syncObserver)
+		if err := sv.deployCanary(namespaceSpec, changedJobs(previousJobs, jobsToKeep), canary == CanaryRun,
+			respStream, canaryObservers); err != nil {
+			return err
+		}
+	}
+
+	for _, adaptJob := range jobsToKeep {
+		if err := sv.jobSvc.Create(namespaceSpec, adaptJob); err != nil {
+			if errors.Is(err, job.ErrJobLocked) {
+				return status.Error(codes.FailedPrecondition, err.Error())
+			}
 			return status.Errorf(codes.Internal, "%s: failed to save %s", err.Error(), adaptJob.Name)
 		}
-		jobsToKeep = append(jobsToKeep, adaptJob)
 	}
 
 	observers := new(progress.ObserverChain)
 	observers.Join(sv.progressObserver)
-	observers.Join(&jobSyncObserver{
-		stream: respStream,
-		log:    logrus.New(),
-	})
+	observers.Join(results)
+	observers.Join(syncObserver)
 
 	// delete specs not sent for deployment from internal repository
-	if err := sv.jobSvc.KeepOnly(namespaceSpec, jobsToKeep, observers); err != nil {
+	if err := sv.jobSvc.KeepOnly(namespaceSpec, namespaceJobs, observers); err != nil {
+		if errors.Is(err, job.ErrJobLocked) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return status.Errorf(codes.Internal, "%s: failed to delete jobs", err.Error())
 	}
 
-	if err := sv.jobSvc.Sync(respStream.Context(), namespaceSpec, observers); err != nil {
-		return status.Errorf(codes.Internal, "%s\nfailed to sync jobs", err.Error())
+	syncErr := sv.jobSvc.Sync(respStream.Context(), namespaceSpec, observers)
+	if sv.changelogRepo != nil {
+		// deployment is already done, failing to record changelog should not fail it,
+		// failed deployments are recorded too as jobs are saved anyway
+		if err := sv.recordDeployment(projSpec, namespaceSpec, auditActor(respStream.Context()), previousJobs,
+			namespaceJobs, snapshotJobs, results, syncErr != nil, rollbackOf); err != nil {
+			logger.E("failed to record deploy changelog: ", err)
+		}
+	}
+	if syncErr != nil {
+		return status.Errorf(codes.Internal, "%s\nfailed to sync jobs", syncErr.Error())
 	}
 
 	logger.I("finished job deployment in", time.Since(startTime))
 	return nil
 }
 
+func (sv *RuntimeServiceServer) recordDeployment(projSpec models.ProjectSpec, namespaceSpec models.NamespaceSpec, actor string,
+	previousJobs, currentJobs []models.JobSpec, jobProtos []*pb.JobSpecification, results *jobResultObserver,
+	syncFailed bool, rollbackOf uuid.UUID) error {
+	snapshot, snapshotHash, err := jobSnapshot(jobProtos)
+	if err != nil {
+		return errors.Wrap(err, "failed to snapshot jobs")
+	}
+	sort.Slice(results.results, func(i, j int) bool {
+		return results.results[i].Name < results.results[j].Name
+	})
+	return sv.changelogRepo.Insert(projSpec, &models.DeployChangelog{
+		Namespace:    namespaceSpec.Name,
+		Actor:        actor,
+		Changes:      job.DiffJobSpecs(previousJobs, currentJobs),
+		Snapshot:     snapshot,
+		SnapshotHash: snapshotHash,
+		Results:      results.results,
+		Failed:       syncFailed || results.failed(),
+		RollbackOf:   rollbackOf,
+		CreatedAt:    sv.Now(),
+	})
+}
+
 func (sv *RuntimeServiceServer) ListJobSpecification(ctx context.Context, req *pb.ListJobSpecificationRequest) (*pb.ListJobSpecificationResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
@@ -153,19 +290,37 @@ func (sv *RuntimeServiceServer) ListJobSpecification(ctx context.Context, req *p
 		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
 	}
 
+	pageOpts, err := parsePageOptions(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	selector, err := labelSelector(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	jobSpecs, err := sv.jobSvc.GetAll(namespaceSpec)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve jobs for project %s", err.Error(), req.GetProjectName())
 	}
+	jobSpecs = selector.SelectJobSpecs(jobSpecs)
+	sort.Slice(jobSpecs, func(i, j int) bool {
+		return jobSpecs[i].Name < jobSpecs[j].Name
+	})
+	pageStart, pageEnd, nextPageToken := pageOpts.bounds(len(jobSpecs))
 
 	jobProtos := []*pb.JobSpecification{}
-	for _, jobSpec := range jobSpecs {
+	for _, jobSpec := range jobSpecs[pageStart:pageEnd] {
 		jobProto, err := sv.adapter.ToJobProto(jobSpec)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "%s: failed to parse job spec %s", err.Error(), jobSpec.Name)
 		}
+		if err := pageOpts.mask(jobProto); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		jobProtos = append(jobProtos, jobProto)
 	}
+	sendNextPageToken(ctx, nextPageToken)
 	return &pb.ListJobSpecificationResponse{
 		Jobs: jobProtos,
 	}, nil
@@ -322,6 +477,26 @@ func (sv *RuntimeServiceServer) CreateJobSpecification(ctx context.Context, req
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: cannot deserialize job", err.Error())
 	}
+	if err := checkJobNaming(projSpec, []models.JobSpec{jobSpec}); err != nil {
+		return nil, err
+	}
+
+	if sv.quotaSvc != nil {
+		namespaceJobs, err := sv.jobSvc.GetAll(namespaceSpec)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "%s: failed to retrieve jobs for namespace %s", err.Error(), req.GetNamespace())
+		}
+		jobCount := len(namespaceJobs) + 1
+		for _, existing := range namespaceJobs {
+			if existing.Name == jobSpec.Name {
+				jobCount--
+				break
+			}
+		}
+		if err := sv.checkJobQuota(projSpec, namespaceSpec, jobCount); err != nil {
+			return nil, err
+		}
+	}
 
 	// validate job spec
 	if err = sv.jobSvc.Check(namespaceSpec, []models.JobSpec{jobSpec}, sv.progressObserver); err != nil {
@@ -330,6 +505,9 @@ func (sv *RuntimeServiceServer) CreateJobSpecification(ctx context.Context, req
 
 	err = sv.jobSvc.Create(namespaceSpec, jobSpec)
 	if err != nil {
+		if errors.Is(err, job.ErrJobLocked) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "%s: failed to save job %s", err.Error(), jobSpec.Name)
 	}
 
@@ -390,6 +568,9 @@ func (sv *RuntimeServiceServer) DeleteJobSpecification(ctx context.Context, req
 	}
 
 	if err := sv.jobSvc.Delete(ctx, namespaceSpec, jobSpecToDelete); err != nil {
+		if errors.Is(err, job.ErrJobLocked) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "%s: failed to delete job %s", err.Error(), req.GetJobName())
 	}
 
@@ -400,16 +581,30 @@ func (sv *RuntimeServiceServer) DeleteJobSpecification(ctx context.Context, req
 }
 
 func (sv *RuntimeServiceServer) ListProjects(ctx context.Context, req *pb.ListProjectsRequest) (*pb.ListProjectsResponse, error) {
+	pageOpts, err := parsePageOptions(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	projectRepo := sv.projectRepoFactory.New()
 	projects, err := projectRepo.GetAll()
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: failed to retrieve saved projects", err.Error())
 	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+	pageStart, pageEnd, nextPageToken := pageOpts.bounds(len(projects))
 
 	projSpecsProto := []*pb.ProjectSpecification{}
-	for _, project := range projects {
-		projSpecsProto = append(projSpecsProto, sv.adapter.ToProjectProto(project))
+	for _, project := range projects[pageStart:pageEnd] {
+		projectProto := sv.adapter.ToProjectProto(project)
+		if err := pageOpts.mask(projectProto); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		projSpecsProto = append(projSpecsProto, projectProto)
 	}
+	sendNextPageToken(ctx, nextPageToken)
 
 	return &pb.ListProjectsResponse{
 		Projects: projSpecsProto,
@@ -423,16 +618,30 @@ func (sv *RuntimeServiceServer) ListProjectNamespaces(ctx context.Context, req *
 		return nil, status.Errorf(codes.NotFound, "%s: project %s not found", err.Error(), req.GetProjectName())
 	}
 
+	pageOpts, err := parsePageOptions(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	namespaceRepo := sv.namespaceRepoFactory.New(projSpec)
 	namespaceSpecs, err := namespaceRepo.GetAll()
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: error while fetching namespaces", err.Error())
 	}
+	sort.Slice(namespaceSpecs, func(i, j int) bool {
+		return namespaceSpecs[i].Name < namespaceSpecs[j].Name
+	})
+	pageStart, pageEnd, nextPageToken := pageOpts.bounds(len(namespaceSpecs))
 
 	namespaceSpecsProto := []*pb.NamespaceSpecification{}
-	for _, namespace := range namespaceSpecs {
-		namespaceSpecsProto = append(namespaceSpecsProto, sv.adapter.ToNamespaceProto(namespace))
+	for _, namespace := range namespaceSpecs[pageStart:pageEnd] {
+		namespaceProto := sv.adapter.ToNamespaceProto(namespace)
+		if err := pageOpts.mask(namespaceProto); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		namespaceSpecsProto = append(namespaceSpecsProto, namespaceProto)
 	}
+	sendNextPageToken(ctx, nextPageToken)
 
 	return &pb.ListProjectNamespacesResponse{
 		Namespaces: namespaceSpecsProto,
@@ -502,25 +711,50 @@ func (sv *RuntimeServiceServer) JobStatus(ctx context.Context, req *pb.JobStatus
 			req.GetJobName(), req.GetProjectName())
 	}
 
+	pageOpts, err := parsePageOptions(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	jobStatuses, err := sv.scheduler.GetJobStatus(ctx, projSpec, req.GetJobName())
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "%s: failed to fetch jobStatus %s", err.Error(),
 			req.GetJobName())
 	}
+	// latest runs first so the first page has the runs most asked about
+	sort.SliceStable(jobStatuses, func(i, j int) bool {
+		return jobStatuses[i].ScheduledAt.After(jobStatuses[j].ScheduledAt)
+	})
+	pageStart, pageEnd, nextPageToken := pageOpts.bounds(len(jobStatuses))
 
 	var adaptedJobStatus []*pb.JobStatus
-	for _, jobStatus := range jobStatuses {
+	for _, jobStatus := range jobStatuses[pageStart:pageEnd] {
 		ts := timestamppb.New(jobStatus.ScheduledAt)
-		adaptedJobStatus = append(adaptedJobStatus, &pb.JobStatus{
+		statusProto := &pb.JobStatus{
 			State:       jobStatus.State.String(),
 			ScheduledAt: ts,
-		})
+		}
+		if err := pageOpts.mask(statusProto); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		adaptedJobStatus = append(adaptedJobStatus, statusProto)
 	}
+	sendNextPageToken(ctx, nextPageToken)
 	return &pb.JobStatusResponse{
 		Statuses: adaptedJobStatus,
 	}, nil
 }
 
+// EventValueDatastoreJobIDs in value of an event of the outcome of a run
+// lists ids of the datastore jobs its tasks ran, their cost is recorded with
+// the run
+const EventValueDatastoreJobIDs = "datastore_job_ids"
+
+// EventValueSensorTimeout in value of a sla miss event marks it raised by a
+// sensor which timed out waiting on a dependency, the scheduler can't raise
+// sensor timeouts as an event type of their own
+const EventValueSensorTimeout = "sensor_timeout"
+
 func (sv *RuntimeServiceServer) RegisterJobEvent(ctx context.Context, req *pb.RegisterJobEventRequest) (*pb.RegisterJobEventResponse, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
@@ -548,8 +782,16 @@ func (sv *RuntimeServiceServer) RegisterJobEvent(ctx context.Context, req *pb.Re
 	if req.GetEvent().Value != nil {
 		eventValues = req.GetEvent().Value.GetFields()
 	}
+	eventType := models.JobEventType(strings.ToLower(req.GetEvent().Type.String()))
+	if eventType == models.JobEventTypeSLAMiss && eventValues[EventValueSensorTimeout].GetBoolValue() {
+		eventType = models.JobEventTypeSensorTimeout
+	}
+	if err := sv.recordRunState(jobSpec, eventType, eventValues); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to record state of run: %s", err)
+	}
+	sv.collectRunCost(ctx, namespaceSpec, jobSpec, eventType, eventValues)
 	if err := sv.jobEventSvc.Register(ctx, namespaceSpec, jobSpec, models.JobEvent{
-		Type:  models.JobEventType(strings.ToLower(req.GetEvent().Type.String())),
+		Type:  eventType,
 		Value: eventValues,
 	}); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to register event: %s", err)
@@ -558,6 +800,77 @@ func (sv *RuntimeServiceServer) RegisterJobEvent(ctx context.Context, req *pb.Re
 	return &pb.RegisterJobEventResponse{}, nil
 }
 
+// recordRunState marks the run an event of its outcome is raised for as
+// succeeded or failed, events without scheduled_at of the run are left out
+func (sv *RuntimeServiceServer) recordRunState(jobSpec models.JobSpec, eventType models.JobEventType,
+	eventValues map[string]*structpb.Value) error {
+	var state string
+	switch eventType {
+	case models.JobEventTypeSuccess:
+		state = models.InstanceStateSuccess
+	case models.JobEventTypeFailure:
+		state = models.InstanceStateFailed
+	default:
+		return nil
+	}
+	if sv.instSvc == nil {
+		return nil
+	}
+	scheduledAt, ok, err := runScheduledAt(jobSpec, eventValues)
+	if err != nil || !ok {
+		return err
+	}
+	return sv.instSvc.UpdateState(jobSpec, scheduledAt, state)
+}
+
+// collectRunCost records cost of the datastore jobs an event of the outcome
+// of a run lists in datastore_job_ids. Cost is best effort, failing to look
+// it up doesn't fail the event
+func (sv *RuntimeServiceServer) collectRunCost(ctx context.Context, namespaceSpec models.NamespaceSpec,
+	jobSpec models.JobSpec, eventType models.JobEventType, eventValues map[string]*structpb.Value) {
+	if eventType != models.JobEventTypeSuccess && eventType != models.JobEventTypeFailure {
+		return
+	}
+	if sv.costCollector == nil || eventValues[EventValueDatastoreJobIDs] == nil {
+		return
+	}
+	var datastoreJobs []string
+	for _, val := range eventValues[EventValueDatastoreJobIDs].GetListValue().GetValues() {
+		if jobID := val.GetStringValue(); jobID != "" {
+			datastoreJobs = append(datastoreJobs, jobID)
+		}
+	}
+	if len(datastoreJobs) == 0 {
+		return
+	}
+	scheduledAt, ok, err := runScheduledAt(jobSpec, eventValues)
+	if err != nil || !ok {
+		return
+	}
+	if err := sv.costCollector.Collect(ctx, namespaceSpec, jobSpec, scheduledAt, datastoreJobs); err != nil {
+		logger.E(errors.Wrapf(err, "failed to collect cost of run of %s at %s", jobSpec.Name, scheduledAt))
+	}
+}
+
+// runScheduledAt returns the time the run an event is raised for is
+// registered at, false if event has no valid scheduled_at. Events carry the
+// execution date of the scheduler, which is the start of the interval,
+// whereas runs are registered at the end of it
+func runScheduledAt(jobSpec models.JobSpec, eventValues map[string]*structpb.Value) (time.Time, bool, error) {
+	if eventValues["scheduled_at"] == nil {
+		return time.Time{}, false, nil
+	}
+	scheduledAt, err := time.Parse(models.InstanceScheduledAtTimeLayout, eventValues["scheduled_at"].GetStringValue())
+	if err != nil {
+		return time.Time{}, false, nil
+	}
+	schd, err := cron.ParseCronSchedule(jobSpec.Schedule.Interval)
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "failed to parse schedule interval %s", jobSpec.Schedule.Interval)
+	}
+	return schd.Next(scheduledAt), true, nil
+}
+
 func (sv *RuntimeServiceServer) GetWindow(ctx context.Context, req *pb.GetWindowRequest) (*pb.GetWindowResponse, error) {
 	scheduledTime, err := ptypes.Timestamp(req.GetScheduledAt())
 	if err != nil {
@@ -629,6 +942,13 @@ func (sv *RuntimeServiceServer) CreateResource(ctx context.Context, req *pb.Crea
 		return nil, status.Errorf(codes.Internal, "%s: failed to parse resource %s", err.Error(), req.Resource.GetName())
 	}
 
+	if err := checkResourceNaming(projSpec, []models.ResourceSpec{optResource}); err != nil {
+		return nil, err
+	}
+	if err := sv.checkResourceQuota(projSpec, namespaceSpec, req.DatastoreName, []models.ResourceSpec{optResource}); err != nil {
+		return nil, err
+	}
+
 	if err := sv.resourceSvc.CreateResource(ctx, namespaceSpec, []models.ResourceSpec{optResource}, sv.progressObserver); err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to create resource %s", err.Error(), req.Resource.GetName())
 	}
@@ -654,6 +974,9 @@ func (sv *RuntimeServiceServer) UpdateResource(ctx context.Context, req *pb.Upda
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to parse resource %s", err.Error(), req.Resource.GetName())
 	}
+	if err := checkResourceNaming(projSpec, []models.ResourceSpec{optResource}); err != nil {
+		return nil, err
+	}
 
 	if err := sv.resourceSvc.UpdateResource(ctx, namespaceSpec, []models.ResourceSpec{optResource}, sv.progressObserver); err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to create resource %s", err.Error(), req.Resource.GetName())
@@ -692,6 +1015,34 @@ func (sv *RuntimeServiceServer) ReadResource(ctx context.Context, req *pb.ReadRe
 	}, nil
 }
 
+// MetadataDryRun set to true in request metadata of resource deployment only
+// plans the changes, each resource ack carries its changes in the message
+const MetadataDryRun = "x-dry-run"
+
+func isDryRun(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	vals := md.Get(MetadataDryRun)
+	return len(vals) > 0 && vals[0] == "true"
+}
+
+// MetadataReplayIgnoreUpstream set to true in request metadata of replay
+// marks sensors of the cleared runs waiting for jobs not being replayed as
+// succeeded, for upstream data known to be good whose runs were fixed
+// outside optimus
+const MetadataReplayIgnoreUpstream = "x-replay-ignore-upstream"
+
+func ignoresUpstream(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	vals := md.Get(MetadataReplayIgnoreUpstream)
+	return len(vals) > 0 && vals[0] == "true"
+}
+
 func (sv *RuntimeServiceServer) DeployResourceSpecification(req *pb.DeployResourceSpecificationRequest, respStream pb.RuntimeService_DeployResourceSpecificationServer) error {
 	startTime := time.Now()
 
@@ -715,6 +1066,12 @@ func (sv *RuntimeServiceServer) DeployResourceSpecification(req *pb.DeployResour
 		}
 		resourceSpecs = append(resourceSpecs, adapted)
 	}
+	if err := checkResourceNaming(projSpec, resourceSpecs); err != nil {
+		return err
+	}
+	if err := sv.checkResourceQuota(projSpec, namespaceSpec, req.DatastoreName, resourceSpecs); err != nil {
+		return err
+	}
 
 	observers := new(progress.ObserverChain)
 	observers.Join(sv.progressObserver)
@@ -723,6 +1080,12 @@ func (sv *RuntimeServiceServer) DeployResourceSpecification(req *pb.DeployResour
 		log:    logrus.New(),
 	})
 
+	if isDryRun(respStream.Context()) {
+		if err := sv.resourceSvc.PlanResource(respStream.Context(), namespaceSpec, resourceSpecs, observers); err != nil {
+			return status.Errorf(codes.Internal, "failed to plan resources:\n%s", err.Error())
+		}
+		return nil
+	}
 	if err := sv.resourceSvc.UpdateResource(respStream.Context(), namespaceSpec, resourceSpecs, observers); err != nil {
 		return status.Errorf(codes.Internal, "failed to update resources:\n%s", err.Error())
 	}
@@ -743,32 +1106,48 @@ func (sv *RuntimeServiceServer) ListResourceSpecification(ctx context.Context, r
 		return nil, status.Errorf(codes.NotFound, "%s: namespace %s not found", err.Error(), req.GetNamespace())
 	}
 
+	pageOpts, err := parsePageOptions(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	resourceSpecs, err := sv.resourceSvc.GetAll(namespaceSpec, req.DatastoreName)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "%s: failed to retrieve jobs for project %s", err.Error(), req.GetProjectName())
 	}
+	sort.Slice(resourceSpecs, func(i, j int) bool {
+		return resourceSpecs[i].Name < resourceSpecs[j].Name
+	})
+	pageStart, pageEnd, nextPageToken := pageOpts.bounds(len(resourceSpecs))
 
 	resourceProtos := []*pb.ResourceSpecification{}
-	for _, resourceSpec := range resourceSpecs {
+	for _, resourceSpec := range resourceSpecs[pageStart:pageEnd] {
 		resourceProto, err := sv.adapter.ToResourceProto(resourceSpec)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "%s: failed to parse job spec %s", err.Error(), resourceSpec.Name)
 		}
+		if err := pageOpts.mask(resourceProto); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		resourceProtos = append(resourceProtos, resourceProto)
 	}
+	sendNextPageToken(ctx, nextPageToken)
 	return &pb.ListResourceSpecificationResponse{
 		Resources: resourceProtos,
 	}, nil
 }
 
 func (sv *RuntimeServiceServer) ReplayDryRun(ctx context.Context, req *pb.ReplayRequest) (*pb.ReplayDryRunResponse, error) {
-	replayWorkerRequest, err := sv.parseReplayRequest(req)
+	replayWorkerRequest, err := sv.parseReplayRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
 	rootNode, err := sv.jobSvc.ReplayDryRun(replayWorkerRequest)
 	if err != nil {
+		if errors.Is(err, job.ErrReplayWindowMisaligned) {
+			return nil, status.Errorf(codes.InvalidArgument, "error while processing replay dry run: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "error while processing replay dry run: %v", err)
 	}
 
@@ -776,6 +1155,14 @@ func (sv *RuntimeServiceServer) ReplayDryRun(ctx context.Context, req *pb.Replay
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "error while preparing replay dry run response: %v", err)
 	}
+	estimate, err := estimateReplay(sv.instSvc, rootNode)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error while estimating replay: %v", err)
+	}
+	sendReplayEstimate(ctx, estimate)
+	if err := sendReplayChunks(ctx, req, replayWorkerRequest); err != nil {
+		return nil, err
+	}
 	return &pb.ReplayDryRunResponse{
 		Success:  true,
 		Response: node,
@@ -783,27 +1170,164 @@ func (sv *RuntimeServiceServer) ReplayDryRun(ctx context.Context, req *pb.Replay
 }
 
 func (sv *RuntimeServiceServer) Replay(ctx context.Context, req *pb.ReplayRequest) (*pb.ReplayResponse, error) {
-	replayWorkerRequest, err := sv.parseReplayRequest(req)
+	replayWorkerRequest, err := sv.parseReplayRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	replayRuns := 0
+	var estimate *models.ReplayEstimate
+	if sv.quotaSvc != nil || sv.instSvc != nil {
+		// job specs resolved for the tree are kept in request and reused
+		// by the replay
+		rootNode, err := sv.jobSvc.ReplayDryRun(replayWorkerRequest)
+		if err != nil {
+			if errors.Is(err, job.ErrReplayWindowMisaligned) {
+				return nil, status.Errorf(codes.InvalidArgument, "error while processing replay: %v", err)
+			}
+			return nil, status.Errorf(codes.Internal, "error while processing replay: %v", err)
+		}
+		for _, node := range rootNode.GetAllNodes() {
+			replayRuns += node.Runs.Size()
+		}
+		if sv.instSvc != nil {
+			replayEstimate, err := estimateReplay(sv.instSvc, rootNode)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "error while estimating replay: %v", err)
+			}
+			estimate = &replayEstimate
+		}
+		if sv.quotaSvc != nil {
+			if err := sv.quotaSvc.ReserveReplayRuns(replayWorkerRequest.Project, replayRuns); err != nil {
+				return nil, quotaStatus(err)
+			}
+		}
+	}
+
 	replayUUID, err := sv.jobSvc.Replay(ctx, replayWorkerRequest)
 	if err != nil {
+		if sv.quotaSvc != nil {
+			if err := sv.quotaSvc.ReleaseReplayRuns(replayWorkerRequest.Project, replayRuns); err != nil {
+				logger.E(err)
+			}
+		}
 		if errors.Is(err, job.ErrRequestQueueFull) {
 			return nil, status.Errorf(codes.Unavailable, "error while processing replay: %v", err)
 		} else if errors.Is(err, job.ErrConflictedJobRun) {
 			return nil, status.Errorf(codes.FailedPrecondition, "error while validating replay: %v", err)
+		} else if errors.Is(err, job.ErrJobLocked) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		} else if errors.Is(err, job.ErrReplayWindowMisaligned) {
+			return nil, status.Errorf(codes.InvalidArgument, "error while validating replay: %v", err)
 		}
 		return nil, status.Errorf(codes.Internal, "error while processing replay: %v", err)
 	}
 
+	if estimate != nil {
+		sendReplayEstimate(ctx, *estimate)
+	}
 	return &pb.ReplayResponse{
 		Id: replayUUID,
 	}, nil
 }
 
-func (sv *RuntimeServiceServer) parseReplayRequest(req *pb.ReplayRequest) (*models.ReplayWorkerRequest, error) {
+// checkJobQuota verifies the project can have the jobs of other namespaces
+// along with the given number of jobs in namespaceSpec
+func (sv *RuntimeServiceServer) checkJobQuota(projSpec models.ProjectSpec, namespaceSpec models.NamespaceSpec, namespaceJobs int) error {
+	if sv.quotaSvc == nil {
+		return nil
+	}
+	namespaces, err := sv.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return status.Errorf(codes.Internal, "%s: failed to retrieve namespaces of project %s", err.Error(), projSpec.Name)
+	}
+	jobCount := namespaceJobs
+	for _, ns := range namespaces {
+		if ns.Name == namespaceSpec.Name {
+			continue
+		}
+		jobs, err := sv.jobSvc.GetAll(ns)
+		if err != nil {
+			return status.Errorf(codes.Internal, "%s: failed to retrieve jobs for namespace %s", err.Error(), ns.Name)
+		}
+		jobCount += len(jobs)
+	}
+	if err := sv.quotaSvc.CheckJobs(projSpec, jobCount); err != nil {
+		return quotaStatus(err)
+	}
+	return nil
+}
+
+// checkResourceQuota verifies the project can have existing resources of the datastore
+// along with the new ones, resources are never deleted on deployment
+func (sv *RuntimeServiceServer) checkResourceQuota(projSpec models.ProjectSpec, namespaceSpec models.NamespaceSpec,
+	datastoreName string, resourceSpecs []models.ResourceSpec) error {
+	if sv.quotaSvc == nil {
+		return nil
+	}
+	namespaces, err := sv.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return status.Errorf(codes.Internal, "%s: failed to retrieve namespaces of project %s", err.Error(), projSpec.Name)
+	}
+	resourceCount := 0
+	existing := map[string]bool{}
+	for _, ns := range namespaces {
+		resources, err := sv.resourceSvc.GetAll(ns, datastoreName)
+		if err != nil {
+			return status.Errorf(codes.Internal, "%s: failed to retrieve resources for namespace %s", err.Error(), ns.Name)
+		}
+		resourceCount += len(resources)
+		if ns.Name == namespaceSpec.Name {
+			for _, res := range resources {
+				existing[res.Name] = true
+			}
+		}
+	}
+	for _, res := range resourceSpecs {
+		if !existing[res.Name] {
+			existing[res.Name] = true
+			resourceCount++
+		}
+	}
+	if err := sv.quotaSvc.CheckResources(projSpec, resourceCount); err != nil {
+		return quotaStatus(err)
+	}
+	return nil
+}
+
+func quotaStatus(err error) error {
+	if errors.Is(err, models.ErrQuotaExceeded) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Errorf(codes.Internal, "%s: failed to check project quota", err.Error())
+}
+
+// checkJobNaming verifies names of jobs follow naming policy of the project
+func checkJobNaming(projSpec models.ProjectSpec, jobSpecs []models.JobSpec) error {
+	policy, err := projSpec.NamingPolicy()
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err := policy.CheckJobs(jobSpecs); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+// checkResourceNaming verifies names of resources follow naming policy of
+// the project for their types
+func checkResourceNaming(projSpec models.ProjectSpec, resourceSpecs []models.ResourceSpec) error {
+	policy, err := projSpec.NamingPolicy()
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err := policy.CheckResources(resourceSpecs); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return nil
+}
+
+func (sv *RuntimeServiceServer) parseReplayRequest(ctx context.Context, req *pb.ReplayRequest) (*models.ReplayWorkerRequest, error) {
 	projectRepo := sv.projectRepoFactory.New()
 	projSpec, err := projectRepo.GetByName(req.GetProjectName())
 	if err != nil {
@@ -822,28 +1346,94 @@ func (sv *RuntimeServiceServer) parseReplayRequest(req *pb.ReplayRequest) (*mode
 			req.GetJobName(), req.GetNamespace())
 	}
 
-	startDate, err := time.Parse(job.ReplayDateFormat, req.StartDate)
+	preset, err := replayPreset(ctx, jobSpec)
+	if err != nil {
+		return nil, err
+	}
+	var startDate, endDate time.Time
+	switch {
+	case preset != nil && req.StartDate == "":
+		startDate, endDate, err = sv.resolveReplayWindow(*preset, jobSpec, projSpec)
+	case job.IsReplayRuns(req.StartDate):
+		startDate, endDate, err = sv.resolveReplayWindow(models.JobSpecReplayPreset{
+			Start: req.StartDate,
+			End:   req.EndDate,
+		}, jobSpec, projSpec)
+	default:
+		startDate, endDate, err = parseReplayDates(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	selector, err := labelSelector(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ignoreUpstream := ignoresUpstream(ctx)
+	if _, ok := sv.scheduler.(models.SchedulerSensorMarker); ignoreUpstream && !ok {
+		return nil, status.Error(codes.FailedPrecondition, "scheduler can't ignore upstream of replayed runs")
+	}
+	force := req.Force
+	if preset != nil {
+		if selector == nil {
+			if selector, err = models.ParseLabelSelector(preset.Selector); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid selector of replay preset: %v", err)
+			}
+		}
+		force = force || preset.Force
+	}
+	replayRequest := models.ReplayWorkerRequest{
+		Job:            jobSpec,
+		Start:          startDate,
+		End:            endDate,
+		Project:        projSpec,
+		Force:          force,
+		Selector:       selector,
+		IgnoreUpstream: ignoreUpstream,
+	}
+	return &replayRequest, nil
+}
+
+// resolveReplayWindow resolves a replay window expressed in runs of the job
+// or by its replay preset against its schedule and run history
+func (sv *RuntimeServiceServer) resolveReplayWindow(window models.JobSpecReplayPreset, jobSpec models.JobSpec,
+	projSpec models.ProjectSpec) (time.Time, time.Time, error) {
+	if window.Start == job.ReplaySinceLastSuccess && sv.instSvc == nil {
+		return time.Time{}, time.Time{}, status.Errorf(codes.FailedPrecondition, "run history is not kept, %s can't be resolved",
+			job.ReplaySinceLastSuccess)
+	}
+	start, end, err := job.ResolveReplayPreset(window, jobSpec, projSpec, sv.instSvc, sv.Now())
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "unable to parse replay start date(e.g. %s): %v", job.ReplayDateFormat, err)
+		if errors.Is(err, job.ErrInvalidReplayRuns) || errors.Is(err, job.ErrInvalidReplayPreset) {
+			return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "unable to parse replay window: %v", err)
+		} else if errors.Is(err, job.ErrNoRunsToReplay) {
+			return time.Time{}, time.Time{}, status.Errorf(codes.FailedPrecondition, "unable to resolve replay window: %v", err)
+		}
+		return time.Time{}, time.Time{}, status.Errorf(codes.Internal, "unable to resolve replay window: %v", err)
 	}
+	return start, end, nil
+}
 
+// parseReplayDates parses start and end of a replay request, end is the same
+// as start if empty
+func parseReplayDates(req *pb.ReplayRequest) (time.Time, time.Time, error) {
+	startDate, err := job.ParseReplayDate(req.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "unable to parse replay start date(e.g. %s, %s, %sN or %s): %v",
+			job.ReplayDateFormat, time.RFC3339, job.ReplayLastRunsPrefix, job.ReplaySinceLastSuccess, err)
+	}
 	endDate := startDate
 	if req.EndDate != "" {
-		if endDate, err = time.Parse(job.ReplayDateFormat, req.EndDate); err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "unable to parse replay end date(e.g. %s): %v", job.ReplayDateFormat, err)
+		if endDate, err = job.ParseReplayDate(req.EndDate); err != nil {
+			return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "unable to parse replay end date(e.g. %s or %s): %v",
+				job.ReplayDateFormat, time.RFC3339, err)
 		}
 	}
 	if endDate.Before(startDate) {
-		return nil, status.Errorf(codes.InvalidArgument, "replay end date cannot be before start date")
+		return time.Time{}, time.Time{}, status.Errorf(codes.InvalidArgument, "replay end date cannot be before start date")
 	}
-	replayRequest := models.ReplayWorkerRequest{
-		Job:     jobSpec,
-		Start:   startDate,
-		End:     endDate,
-		Project: projSpec,
-		Force:   req.Force,
-	}
-	return &replayRequest, nil
+	return startDate, endDate, nil
 }
 
 func NewRuntimeServiceServer(
@@ -858,6 +1448,10 @@ func NewRuntimeServiceServer(
 	progressObserver progress.Observer,
 	instSvc models.InstanceService,
 	scheduler models.SchedulerUnit,
+	quotaSvc models.QuotaService,
+	changelogRepo store.DeployChangelogRepository,
+	costCollector RunCostCollector,
+	commitStatusPublisher models.CommitStatusPublisher,
 ) *RuntimeServiceServer {
 	return &RuntimeServiceServer{
 		version:              version,
@@ -871,6 +1465,13 @@ func NewRuntimeServiceServer(
 		instSvc:              instSvc,
 		scheduler:            scheduler,
 		secretRepoFactory:    secretRepoFactory,
+		quotaSvc:             quotaSvc,
+		changelogRepo:        changelogRepo,
+		costCollector:        costCollector,
+		commitStatusSvc:      commitStatusPublisher,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
 	}
 }
 
@@ -890,6 +1491,8 @@ func (obs *jobSyncObserver) Notify(e progress.Event) {
 		if evt.Err != nil {
 			resp.Success = false
 			resp.Message = evt.Err.Error()
+		} else if evt.Unchanged {
+			resp.Message = evt.String()
 		}
 
 		if err := obs.stream.Send(resp); err != nil {
@@ -935,6 +1538,21 @@ func (obs *resourceObserver) Notify(e progress.Event) {
 		if err := obs.stream.Send(resp); err != nil {
 			obs.log.Error(errors.Wrapf(err, "failed to send deploy spec ack for: %s", evt.Spec.Name))
 		}
+	case *datastore.EventResourcePlanned:
+		resp := &pb.DeployResourceSpecificationResponse{
+			Success:      true,
+			Ack:          true,
+			ResourceName: evt.Spec.Name,
+			Message:      strings.Join(evt.Changes, "\n"),
+		}
+		if evt.Err != nil {
+			resp.Success = false
+			resp.Message = evt.Err.Error()
+		}
+
+		if err := obs.stream.Send(resp); err != nil {
+			obs.log.Error(errors.Wrapf(err, "failed to send plan ack for: %s", evt.Spec.Name))
+		}
 	}
 }
 
@@ -9,8 +9,11 @@ import (
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -50,6 +53,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			versionRequest := pb.VersionRequest{Client: Version}
 			resp, err := runtimeServiceServer.Version(context.Background(), &versionRequest)
@@ -175,6 +182,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				instanceService,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			versionRequest := pb.RegisterInstanceRequest{ProjectName: projectName, JobName: jobName,
@@ -243,6 +254,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			projectRequest := pb.RegisterProjectRequest{Project: adapter.ToProjectProto(projectSpec)}
@@ -283,6 +298,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			projectRequest := pb.RegisterProjectRequest{Project: adapter.ToProjectProto(projectSpec)}
@@ -341,6 +360,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			projectRequest := pb.RegisterProjectRequest{
@@ -404,6 +427,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			namespaceRequest := pb.RegisterProjectNamespaceRequest{
@@ -461,6 +488,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			namespaceRequest := pb.RegisterProjectNamespaceRequest{
@@ -565,6 +596,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			jobProto, _ := adapter.ToJobProto(jobSpec)
@@ -628,6 +663,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			secretRequest := pb.RegisterSecretRequest{
@@ -686,6 +725,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			secretRequest := pb.RegisterSecretRequest{
@@ -789,6 +832,8 @@ func TestRuntimeServiceServer(t *testing.T) {
 			defer projectJobSpecRepoFactory.AssertExpectations(t)
 
 			jobService := new(mock.JobService)
+			jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{}, nil)
+			jobService.On("CheckDestinations", namespaceSpec, mock2.Anything).Return(nil)
 			jobService.On("Create", mock2.Anything, namespaceSpec).Return(nil)
 			jobService.On("KeepOnly", namespaceSpec, mock2.Anything, mock2.Anything).Return(nil)
 			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
@@ -809,6 +854,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			jobSpecsAdapted := []*pb.JobSpecification{}
@@ -820,10 +869,8 @@ func TestRuntimeServiceServer(t *testing.T) {
 			err := runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
 			assert.Nil(t, err)
 		})
-	})
 
-	t.Run("ReadJobSpecification", func(t *testing.T) {
-		t.Run("should read a job spec", func(t *testing.T) {
+		t.Run("should keep jobs not matching label selector of deployment", func(t *testing.T) {
 			Version := "1.0.1"
 
 			projectName := "a-data-project"
@@ -874,8 +921,12 @@ func TestRuntimeServiceServer(t *testing.T) {
 								Value: "select * from 1",
 							},
 						}),
+					Labels: map[string]string{"tier": "critical"},
 				},
 			}
+			unselectedJob := jobSpecs[0]
+			unselectedJob.Name = "other-job"
+			unselectedJob.Labels = map[string]string{"tier": "low"}
 
 			projectRepository := new(mock.ProjectRepository)
 			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
@@ -885,9 +936,17 @@ func TestRuntimeServiceServer(t *testing.T) {
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			allTasksRepo := new(mock.SupportedPluginRepo)
-			allTasksRepo.On("GetByName", taskName).Return(execUnit1, nil)
-			adapter := v1.NewAdapter(allTasksRepo, nil)
+			jobSpecRepository := new(mock.JobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			jobSpecRepoFactory := new(mock.JobSpecRepoFactory)
+			defer jobSpecRepoFactory.AssertExpectations(t)
+
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
 
 			namespaceRepository := new(mock.NamespaceRepository)
 			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
@@ -897,10 +956,28 @@ func TestRuntimeServiceServer(t *testing.T) {
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
 
+			projectJobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer projectJobSpecRepository.AssertExpectations(t)
+
+			projectJobSpecRepoFactory := new(mock.ProjectJobSpecRepoFactory)
+			defer projectJobSpecRepoFactory.AssertExpectations(t)
+
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
+			jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{}, nil)
+			jobService.On("CheckDestinations", namespaceSpec, mock2.Anything).Return(nil)
+			jobService.On("Create", mock2.Anything, namespaceSpec).Return(nil)
+			jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{jobSpecs[0], unselectedJob}, nil)
+			jobService.On("KeepOnly", namespaceSpec, mock2.MatchedBy(func(jobs []models.JobSpec) bool {
+				return len(jobs) == 2 && jobs[0].Name == jobName1 && jobs[1].Name == unselectedJob.Name
+			}), mock2.Anything).Return(nil)
+			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
 			defer jobService.AssertExpectations(t)
 
+			grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+			grpcRespStream.On("Context").Return(metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs(v1.MetadataLabelSelector, "tier=critical")))
+			defer grpcRespStream.AssertExpectations(t)
+
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
 				Version,
 				jobService,
@@ -912,21 +989,28 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
-			jobSpecAdapted, _ := adapter.ToJobProto(jobSpecs[0])
-			deployRequest := pb.ReadJobSpecificationRequest{ProjectName: projectName, JobName: jobSpecs[0].Name, Namespace: namespaceSpec.Name}
-			jobSpecResp, err := runtimeServiceServer.ReadJobSpecification(context.Background(), &deployRequest)
+			jobSpecsAdapted := []*pb.JobSpecification{}
+			for _, jobSpec := range jobSpecs {
+				jobSpecAdapted, _ := adapter.ToJobProto(jobSpec)
+				jobSpecsAdapted = append(jobSpecsAdapted, jobSpecAdapted)
+			}
+			deployRequest := pb.DeployJobSpecificationRequest{ProjectName: projectName, Jobs: jobSpecsAdapted, Namespace: namespaceSpec.Name}
+			err := runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
 			assert.Nil(t, err)
-			assert.Equal(t, jobSpecAdapted, jobSpecResp.Spec)
 		})
-	})
 
-	t.Run("ListProjectNamespaces", func(t *testing.T) {
-		t.Run("should read namespaces of a project", func(t *testing.T) {
+		t.Run("should record changelog of the deployment", func(t *testing.T) {
 			Version := "1.0.1"
 
 			projectName := "a-data-project"
+			jobName1 := "a-data-job"
+			taskName := "a-data-task"
 
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
@@ -945,6 +1029,36 @@ func TestRuntimeServiceServer(t *testing.T) {
 				ProjectSpec: projectSpec,
 			}
 
+			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: taskName,
+			}, nil)
+			defer execUnit1.AssertExpectations(t)
+
+			jobSpecs := []models.JobSpec{
+				{
+					Name: jobName1,
+					Task: models.JobSpecTask{
+						Unit: &models.Plugin{
+							Base: execUnit1,
+						},
+						Config: models.JobSpecConfigs{
+							{
+								Name:  "do",
+								Value: "this",
+							},
+						},
+					},
+					Assets: *models.JobAssets{}.New(
+						[]models.JobSpecAsset{
+							{
+								Name:  "query.sql",
+								Value: "select * from 1",
+							},
+						}),
+				},
+			}
+
 			projectRepository := new(mock.ProjectRepository)
 			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
@@ -953,19 +1067,51 @@ func TestRuntimeServiceServer(t *testing.T) {
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
-			adapter := v1.NewAdapter(nil, nil)
+			jobSpecRepository := new(mock.JobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			jobSpecRepoFactory := new(mock.JobSpecRepoFactory)
+			defer jobSpecRepoFactory.AssertExpectations(t)
+
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
 
 			namespaceRepository := new(mock.NamespaceRepository)
-			namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
 			defer namespaceRepository.AssertExpectations(t)
 
 			namespaceRepoFact := new(mock.NamespaceRepoFactory)
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
 
+			projectJobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer projectJobSpecRepository.AssertExpectations(t)
+
+			projectJobSpecRepoFactory := new(mock.ProjectJobSpecRepoFactory)
+			defer projectJobSpecRepoFactory.AssertExpectations(t)
+
 			jobService := new(mock.JobService)
+			jobService.On("CheckDestinations", namespaceSpec, mock2.Anything).Return(nil)
+			jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{{Name: "removed-job"}}, nil)
+			jobService.On("Create", mock2.Anything, namespaceSpec).Return(nil)
+			jobService.On("KeepOnly", namespaceSpec, mock2.Anything, mock2.Anything).Return(nil)
+			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
 			defer jobService.AssertExpectations(t)
 
+			changelogRepo := new(mock.DeployChangelogRepository)
+			var recorded *models.DeployChangelog
+			changelogRepo.On("Insert", projectSpec, mock2.AnythingOfType("*models.DeployChangelog")).Run(func(args mock2.Arguments) {
+				recorded = args.Get(1).(*models.DeployChangelog)
+			}).Return(nil)
+			defer changelogRepo.AssertExpectations(t)
+
+			grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+			grpcRespStream.On("Context").Return(context.Background())
+			defer grpcRespStream.AssertExpectations(t)
+
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
 				Version,
 				jobService,
@@ -977,18 +1123,33 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				changelogRepo,
+				nil,
+				nil,
 			)
+			deployedAt := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+			runtimeServiceServer.Now = func() time.Time { return deployedAt }
 
-			namespaceAdapted := adapter.ToNamespaceProto(namespaceSpec)
-			request := pb.ListProjectNamespacesRequest{ProjectName: projectName}
-			resp, err := runtimeServiceServer.ListProjectNamespaces(context.Background(), &request)
+			jobSpecsAdapted := []*pb.JobSpecification{}
+			for _, jobSpec := range jobSpecs {
+				jobSpecAdapted, _ := adapter.ToJobProto(jobSpec)
+				jobSpecsAdapted = append(jobSpecsAdapted, jobSpecAdapted)
+			}
+			deployRequest := pb.DeployJobSpecificationRequest{ProjectName: projectName, Jobs: jobSpecsAdapted, Namespace: namespaceSpec.Name}
+			err := runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
 			assert.Nil(t, err)
-			assert.Equal(t, []*pb.NamespaceSpecification{namespaceAdapted}, resp.GetNamespaces())
+			assert.Equal(t, namespaceSpec.Name, recorded.Namespace)
+			assert.Equal(t, deployedAt, recorded.CreatedAt)
+			assert.Equal(t, []models.JobChange{
+				{Name: jobName1, Type: models.JobChangeTypeAdded},
+				{Name: "removed-job", Type: models.JobChangeTypeRemoved},
+			}, recorded.Changes)
+			assert.NotEmpty(t, recorded.Snapshot)
+			assert.Len(t, recorded.SnapshotHash, 64)
+			assert.False(t, recorded.Failed)
 		})
-	})
-
-	t.Run("DeleteJobSpecification", func(t *testing.T) {
-		t.Run("should delete the job", func(t *testing.T) {
+		t.Run("should redeploy jobs of previous deployment on rollback", func(t *testing.T) {
 			Version := "1.0.1"
 
 			projectName := "a-data-project"
@@ -1013,6 +1174,9 @@ func TestRuntimeServiceServer(t *testing.T) {
 			}
 
 			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: taskName,
+			}, nil)
 			defer execUnit1.AssertExpectations(t)
 
 			jobSpecs := []models.JobSpec{
@@ -1047,6 +1211,12 @@ func TestRuntimeServiceServer(t *testing.T) {
 			projectRepoFactory.On("New").Return(projectRepository)
 			defer projectRepoFactory.AssertExpectations(t)
 
+			jobSpecRepository := new(mock.JobSpecRepository)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			jobSpecRepoFactory := new(mock.JobSpecRepoFactory)
+			defer jobSpecRepoFactory.AssertExpectations(t)
+
 			pluginRepo := new(mock.SupportedPluginRepo)
 			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
 				Base: execUnit1,
@@ -1061,13 +1231,55 @@ func TestRuntimeServiceServer(t *testing.T) {
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
 
-			jobSpec := jobSpecs[0]
+			projectJobSpecRepository := new(mock.ProjectJobSpecRepository)
+			defer projectJobSpecRepository.AssertExpectations(t)
+
+			projectJobSpecRepoFactory := new(mock.ProjectJobSpecRepoFactory)
+			defer projectJobSpecRepoFactory.AssertExpectations(t)
 
 			jobService := new(mock.JobService)
-			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
-			jobService.On("Delete", mock2.Anything, namespaceSpec, jobSpec).Return(nil)
+			jobService.On("CheckDestinations", namespaceSpec, mock2.Anything).Return(nil)
+			jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{{Name: "removed-job"}}, nil)
+			jobService.On("Create", namespaceSpec, mock2.MatchedBy(func(spec models.JobSpec) bool {
+				return spec.Name == jobName1
+			})).Return(nil)
+			jobService.On("KeepOnly", namespaceSpec, mock2.Anything, mock2.Anything).Return(nil)
+			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
 			defer jobService.AssertExpectations(t)
 
+			jobSpecsAdapted := []*pb.JobSpecification{}
+			for _, jobSpec := range jobSpecs {
+				jobSpecAdapted, _ := adapter.ToJobProto(jobSpec)
+				jobSpecsAdapted = append(jobSpecsAdapted, jobSpecAdapted)
+			}
+			snapshot, err := proto.Marshal(&pb.DeployJobSpecificationRequest{Jobs: jobSpecsAdapted})
+			assert.Nil(t, err)
+			previous := models.DeployChangelog{
+				ID:           uuid.Must(uuid.NewRandom()),
+				Namespace:    namespaceSpec.Name,
+				Snapshot:     snapshot,
+				SnapshotHash: "hash-1",
+			}
+
+			changelogRepo := new(mock.DeployChangelogRepository)
+			changelogRepo.On("GetLatest", projectSpec, namespaceSpec.Name, 50).Return([]models.DeployChangelog{
+				{ID: uuid.Must(uuid.NewRandom()), SnapshotHash: "hash-2"},
+				{ID: uuid.Must(uuid.NewRandom()), SnapshotHash: "hash-3", Failed: true},
+				{ID: uuid.Must(uuid.NewRandom()), SnapshotHash: "hash-2"},
+				{ID: previous.ID, SnapshotHash: previous.SnapshotHash},
+			}, nil)
+			changelogRepo.On("GetByID", projectSpec, previous.ID).Return(previous, nil)
+			var recorded *models.DeployChangelog
+			changelogRepo.On("Insert", projectSpec, mock2.AnythingOfType("*models.DeployChangelog")).Run(func(args mock2.Arguments) {
+				recorded = args.Get(1).(*models.DeployChangelog)
+			}).Return(nil)
+			defer changelogRepo.AssertExpectations(t)
+
+			grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+			grpcRespStream.On("Context").Return(metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs(v1.MetadataRollbackTo, v1.RollbackPrevious)))
+			defer grpcRespStream.AssertExpectations(t)
+
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
 				Version,
 				jobService,
@@ -1079,55 +1291,692 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				changelogRepo,
+				nil,
+				nil,
 			)
 
-			deployRequest := pb.DeleteJobSpecificationRequest{ProjectName: projectName, JobName: jobSpec.Name, Namespace: namespaceSpec.Name}
-			resp, err := runtimeServiceServer.DeleteJobSpecification(context.Background(), &deployRequest)
+			deployRequest := pb.DeployJobSpecificationRequest{ProjectName: projectName, Namespace: namespaceSpec.Name}
+			err = runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
 			assert.Nil(t, err)
-			assert.Equal(t, "job a-data-job has been deleted", resp.GetMessage())
+			assert.Equal(t, previous.ID, recorded.RollbackOf)
+			assert.Equal(t, namespaceSpec.Name, recorded.Namespace)
+			assert.Equal(t, []models.JobChange{
+				{Name: jobName1, Type: models.JobChangeTypeAdded},
+				{Name: "removed-job", Type: models.JobChangeTypeRemoved},
+			}, recorded.Changes)
 		})
-	})
-
-	t.Run("JobStatus", func(t *testing.T) {
-		t.Run("should return all job status via scheduler if valid inputs", func(t *testing.T) {
-			Version := "1.0.0"
+		t.Run("should not deploy jobs when their canary fails", func(t *testing.T) {
+			defer func(interval time.Duration) { v1.CanaryPollInterval = interval }(v1.CanaryPollInterval)
+			v1.CanaryPollInterval = time.Millisecond
+			projectName := "a-data-project"
+			jobName1 := "a-data-job"
+			taskName := "a-data-task"
 
 			projectSpec := models.ProjectSpec{
 				ID:   uuid.Must(uuid.NewRandom()),
-				Name: "a-data-project",
+				Name: projectName,
 			}
-
 			namespaceSpec := models.NamespaceSpec{
 				ID:          uuid.Must(uuid.NewRandom()),
-				Name:        "game_jam",
+				Name:        "dev-test-namespace-1",
 				ProjectSpec: projectSpec,
 			}
 
-			jobSpec := models.JobSpec{
-				Name: "transform-tables",
-			}
+			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: taskName,
+			}, nil)
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
+			jobSpecAdapted, err := adapter.ToJobProto(models.JobSpec{
+				Name: jobName1,
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{
+						Base: execUnit1,
+					},
+				},
+			})
+			assert.Nil(t, err)
 
 			projectRepository := new(mock.ProjectRepository)
-			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
-			defer projectRepository.AssertExpectations(t)
-
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
 			projectRepoFactory := new(mock.ProjectRepoFactory)
 			projectRepoFactory.On("New").Return(projectRepository)
-			defer projectRepoFactory.AssertExpectations(t)
 
-			adapter := v1.NewAdapter(nil, nil)
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 
+			isCanary := mock2.MatchedBy(func(specs []models.JobSpec) bool {
+				return len(specs) == 1 && specs[0].Name == jobName1
+			})
 			jobService := new(mock.JobService)
-			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			jobService.On("CheckDestinations", namespaceSpec, mock2.Anything).Return(nil)
+			jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{}, nil)
+			jobService.On("DeployCanary", mock2.Anything, namespaceSpec, isCanary, mock2.Anything).Return(nil)
+			jobService.On("DeleteCanary", mock2.Anything, namespaceSpec, isCanary).Return(nil)
 			defer jobService.AssertExpectations(t)
 
-			jobStatuses := []models.JobStatus{
-				{
-					ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
-					State:       "failed",
-				},
-				{
-					ScheduledAt: time.Date(2020, 11, 10, 0, 0, 0, 0, time.UTC),
+			scheduler := new(mock.Scheduler)
+			scheduler.On("IsJobLoaded", mock2.Anything, projectSpec, "a-data-job_canary").Return(true, nil)
+			scheduler.On("RunJob", mock2.Anything, projectSpec, "a-data-job_canary", mock2.Anything).Return(nil)
+			scheduler.On("GetDagRunStatus", mock2.Anything, projectSpec, "a-data-job_canary", mock2.Anything, mock2.Anything, 1).
+				Return([]models.JobStatus{{State: models.JobStatusStateRunning}}, nil).Once()
+			scheduler.On("GetDagRunStatus", mock2.Anything, projectSpec, "a-data-job_canary", mock2.Anything, mock2.Anything, 1).
+				Return([]models.JobStatus{{State: models.JobStatusStateFailed}}, nil).Once()
+			defer scheduler.AssertExpectations(t)
+
+			grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+			grpcRespStream.On("Context").Return(metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs(v1.MetadataCanary, v1.CanaryRun)))
+			grpcRespStream.On("Send", mock2.MatchedBy(func(resp *pb.DeployJobSpecificationResponse) bool {
+				return resp.JobName == "a-data-job_canary" && strings.HasPrefix(resp.Message, "canary failed")
+			})).Return(nil).Once()
+			defer grpcRespStream.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.1",
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				scheduler,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			deployRequest := pb.DeployJobSpecificationRequest{ProjectName: projectName, Jobs: []*pb.JobSpecification{jobSpecAdapted},
+				Namespace: namespaceSpec.Name}
+			err = runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "canary deployment failed")
+			jobService.AssertNotCalled(t, "Create", mock2.Anything, mock2.Anything)
+			jobService.AssertNotCalled(t, "Sync", mock2.Anything, mock2.Anything, mock2.Anything)
+		})
+		t.Run("should deploy jobs with breaking schedule changes only if accepted", func(t *testing.T) {
+			projectName := "a-data-project"
+			jobName1 := "a-data-job"
+			taskName := "a-data-task"
+
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+			}
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-test-namespace-1",
+				ProjectSpec: projectSpec,
+			}
+
+			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: taskName,
+			}, nil)
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
+			jobSpec := func(interval string, windowSize time.Duration) models.JobSpec {
+				return models.JobSpec{
+					Name: jobName1,
+					Schedule: models.JobSpecSchedule{
+						StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+						Interval:  interval,
+					},
+					Task: models.JobSpecTask{
+						Unit: &models.Plugin{
+							Base: execUnit1,
+						},
+						Window: models.JobSpecTaskWindow{
+							Size: windowSize,
+						},
+					},
+				}
+			}
+			jobSpecAdapted, err := adapter.ToJobProto(jobSpec("@hourly", time.Hour))
+			assert.Nil(t, err)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{jobSpec("@daily", 24*time.Hour)}, nil)
+			jobService.On("CheckDestinations", namespaceSpec, mock2.Anything).Return(nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.1",
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			runtimeServiceServer.Now = func() time.Time {
+				return time.Date(2021, 5, 20, 10, 30, 0, 0, time.UTC)
+			}
+			deployRequest := pb.DeployJobSpecificationRequest{ProjectName: projectName, Jobs: []*pb.JobSpecification{jobSpecAdapted},
+				Namespace: namespaceSpec.Name}
+			isImpact := mock2.MatchedBy(func(resp *pb.DeployJobSpecificationResponse) bool {
+				return resp.JobName == jobName1 && strings.Contains(resp.Message, "data from 2021-05-20T00:00:00Z to 2021-05-20T10:00:00Z is skipped")
+			})
+
+			grpcRespStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+			grpcRespStream.On("Context").Return(context.Background())
+			grpcRespStream.On("Send", isImpact).Return(nil).Once()
+			defer grpcRespStream.AssertExpectations(t)
+
+			err = runtimeServiceServer.DeployJobSpecification(&deployRequest, grpcRespStream)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			jobService.AssertNotCalled(t, "Create", mock2.Anything, mock2.Anything)
+
+			jobService.On("Create", mock2.Anything, namespaceSpec).Return(nil)
+			jobService.On("KeepOnly", namespaceSpec, mock2.Anything, mock2.Anything).Return(nil)
+			jobService.On("Sync", mock2.Anything, namespaceSpec, mock2.Anything).Return(nil)
+			acceptedStream := new(mock.RuntimeService_DeployJobSpecificationServer)
+			acceptedStream.On("Context").Return(metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs(v1.MetadataAcceptScheduleImpact, "true")))
+			acceptedStream.On("Send", isImpact).Return(nil).Once()
+			defer acceptedStream.AssertExpectations(t)
+
+			err = runtimeServiceServer.DeployJobSpecification(&deployRequest, acceptedStream)
+			assert.Nil(t, err)
+		})
+	})
+
+	t.Run("ReadJobSpecification", func(t *testing.T) {
+		t.Run("should read a job spec", func(t *testing.T) {
+			Version := "1.0.1"
+
+			projectName := "a-data-project"
+			jobName1 := "a-data-job"
+			taskName := "a-data-task"
+
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+				ProjectSpec: projectSpec,
+			}
+
+			execUnit1 := new(mock.BasePlugin)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: taskName,
+			}, nil)
+			defer execUnit1.AssertExpectations(t)
+
+			jobSpecs := []models.JobSpec{
+				{
+					Name: jobName1,
+					Task: models.JobSpecTask{
+						Unit: &models.Plugin{
+							Base: execUnit1,
+						},
+						Config: models.JobSpecConfigs{
+							{
+								Name:  "do",
+								Value: "this",
+							},
+						},
+					},
+					Assets: *models.JobAssets{}.New(
+						[]models.JobSpecAsset{
+							{
+								Name:  "query.sql",
+								Value: "select * from 1",
+							},
+						}),
+				},
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			allTasksRepo := new(mock.SupportedPluginRepo)
+			allTasksRepo.On("GetByName", taskName).Return(execUnit1, nil)
+			adapter := v1.NewAdapter(allTasksRepo, nil)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				Version,
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			jobSpecAdapted, _ := adapter.ToJobProto(jobSpecs[0])
+			deployRequest := pb.ReadJobSpecificationRequest{ProjectName: projectName, JobName: jobSpecs[0].Name, Namespace: namespaceSpec.Name}
+			jobSpecResp, err := runtimeServiceServer.ReadJobSpecification(context.Background(), &deployRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, jobSpecAdapted, jobSpecResp.Spec)
+		})
+	})
+
+	t.Run("ListProjectNamespaces", func(t *testing.T) {
+		t.Run("should read namespaces of a project", func(t *testing.T) {
+			Version := "1.0.1"
+
+			projectName := "a-data-project"
+
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+				ProjectSpec: projectSpec,
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			adapter := v1.NewAdapter(nil, nil)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				Version,
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			namespaceAdapted := adapter.ToNamespaceProto(namespaceSpec)
+			request := pb.ListProjectNamespacesRequest{ProjectName: projectName}
+			resp, err := runtimeServiceServer.ListProjectNamespaces(context.Background(), &request)
+			assert.Nil(t, err)
+			assert.Equal(t, []*pb.NamespaceSpecification{namespaceAdapted}, resp.GetNamespaces())
+		})
+	})
+
+	t.Run("ListProjects", func(t *testing.T) {
+		projectSpecs := []models.ProjectSpec{
+			{
+				ID:     uuid.Must(uuid.NewRandom()),
+				Name:   "c-data-project",
+				Config: map[string]string{"bucket": "gs://some_folder"},
+			},
+			{
+				ID:     uuid.Must(uuid.NewRandom()),
+				Name:   "a-data-project",
+				Config: map[string]string{"bucket": "gs://some_folder"},
+			},
+			{
+				ID:     uuid.Must(uuid.NewRandom()),
+				Name:   "b-data-project",
+				Config: map[string]string{"bucket": "gs://some_folder"},
+			},
+		}
+		newServer := func(projectRepoFactory *mock.ProjectRepoFactory) *v1.RuntimeServiceServer {
+			return v1.NewRuntimeServiceServer(
+				"1.0.1",
+				nil,
+				nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+		}
+		t.Run("should return all projects sorted by name if page size is not requested", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetAll").Return(projectSpecs, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			resp, err := newServer(projectRepoFactory).ListProjects(context.Background(), &pb.ListProjectsRequest{})
+			assert.Nil(t, err)
+			assert.Equal(t, 3, len(resp.GetProjects()))
+			assert.Equal(t, "a-data-project", resp.GetProjects()[0].Name)
+			assert.Equal(t, "c-data-project", resp.GetProjects()[2].Name)
+		})
+		t.Run("should return requested page of projects with only masked fields", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetAll").Return(projectSpecs, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			// token of second item
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				v1.MetadataPageSize, "1",
+				v1.MetadataPageToken, "MQ",
+				v1.MetadataFieldMask, "name",
+			))
+			resp, err := newServer(projectRepoFactory).ListProjects(ctx, &pb.ListProjectsRequest{})
+			assert.Nil(t, err)
+			assert.Equal(t, []*pb.ProjectSpecification{{Name: "b-data-project"}}, resp.GetProjects())
+		})
+		t.Run("should return error if page token is invalid", func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				v1.MetadataPageToken, "invalid-token",
+			))
+			_, err := newServer(nil).ListProjects(ctx, &pb.ListProjectsRequest{})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+		t.Run("should return error if field mask has unknown fields", func(t *testing.T) {
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetAll").Return(projectSpecs, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				v1.MetadataFieldMask, "name,unknown",
+			))
+			_, err := newServer(projectRepoFactory).ListProjects(ctx, &pb.ListProjectsRequest{})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	})
+
+	t.Run("DeleteJobSpecification", func(t *testing.T) {
+		t.Run("should delete the job", func(t *testing.T) {
+			Version := "1.0.1"
+
+			projectName := "a-data-project"
+			jobName1 := "a-data-job"
+			taskName := "a-data-task"
+
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+				ProjectSpec: projectSpec,
+			}
+
+			execUnit1 := new(mock.BasePlugin)
+			defer execUnit1.AssertExpectations(t)
+
+			jobSpecs := []models.JobSpec{
+				{
+					Name: jobName1,
+					Task: models.JobSpecTask{
+						Unit: &models.Plugin{
+							Base: execUnit1,
+						},
+						Config: models.JobSpecConfigs{
+							{
+								Name:  "do",
+								Value: "this",
+							},
+						},
+					},
+					Assets: *models.JobAssets{}.New(
+						[]models.JobSpecAsset{
+							{
+								Name:  "query.sql",
+								Value: "select * from 1",
+							},
+						}),
+				},
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			pluginRepo := new(mock.SupportedPluginRepo)
+			pluginRepo.On("GetByName", taskName).Return(&models.Plugin{
+				Base: execUnit1,
+			}, nil)
+			adapter := v1.NewAdapter(pluginRepo, nil)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobSpec := jobSpecs[0]
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobSpecs[0].Name, namespaceSpec).Return(jobSpecs[0], nil)
+			jobService.On("Delete", mock2.Anything, namespaceSpec, jobSpec).Return(nil)
+			defer jobService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				Version,
+				jobService,
+				nil, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			deployRequest := pb.DeleteJobSpecificationRequest{ProjectName: projectName, JobName: jobSpec.Name, Namespace: namespaceSpec.Name}
+			resp, err := runtimeServiceServer.DeleteJobSpecification(context.Background(), &deployRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, "job a-data-job has been deleted", resp.GetMessage())
+		})
+	})
+
+	t.Run("JobStatus", func(t *testing.T) {
+		t.Run("should return requested page of job status latest first", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			}
+			jobSpec := models.JobSpec{
+				Name: "transform-tables",
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, models.NamespaceSpec{}, nil)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("GetJobStatus", mock2.Anything, projectSpec, jobSpec.Name).Return([]models.JobStatus{
+				{ScheduledAt: time.Date(2020, 11, 10, 0, 0, 0, 0, time.UTC), State: "success"},
+				{ScheduledAt: time.Date(2020, 11, 12, 0, 0, 0, 0, time.UTC), State: "running"},
+				{ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC), State: "failed"},
+			}, nil)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, nil, nil,
+				projectRepoFactory,
+				nil,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				scheduler,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			// token of second item
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				v1.MetadataPageSize, "1",
+				v1.MetadataPageToken, "MQ",
+			))
+			resp, err := runtimeServiceServer.JobStatus(ctx, &pb.JobStatusRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+			})
+			assert.Nil(t, err)
+			assert.Len(t, resp.Statuses, 1)
+			assert.Equal(t, "failed", resp.Statuses[0].State)
+		})
+		t.Run("should return all job status via scheduler if valid inputs", func(t *testing.T) {
+			Version := "1.0.0"
+
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "game_jam",
+				ProjectSpec: projectSpec,
+			}
+
+			jobSpec := models.JobSpec{
+				Name: "transform-tables",
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			adapter := v1.NewAdapter(nil, nil)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByNameForProject", jobSpec.Name, projectSpec).Return(jobSpec, namespaceSpec, nil)
+			defer jobService.AssertExpectations(t)
+
+			jobStatuses := []models.JobStatus{
+				{
+					ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+					State:       "failed",
+				},
+				{
+					ScheduledAt: time.Date(2020, 11, 10, 0, 0, 0, 0, time.UTC),
 					State:       "success",
 				},
 			}
@@ -1145,6 +1994,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				scheduler,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			req := &pb.JobStatusRequest{
@@ -1215,37 +2068,264 @@ func TestRuntimeServiceServer(t *testing.T) {
 
 			eventValues, _ := structpb.NewStruct(
 				map[string]interface{}{
-					"url": "http://example.io",
+					"url": "http://example.io",
+				},
+			)
+			eventSvc := new(mock.EventService)
+			eventSvc.On("Register", context.Background(), namespaceSpec, jobSpecs[0], models.JobEvent{
+				Type:  models.JobEventTypeFailure,
+				Value: eventValues.GetFields(),
+			}).Return(nil)
+			defer eventSvc.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				Version,
+				jobService, eventSvc, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			req := &pb.RegisterJobEventRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpecs[0].Name,
+				Namespace:   namespaceSpec.Name,
+				Event: &pb.JobEvent{
+					Type:  pb.JobEvent_FAILURE,
+					Value: eventValues,
+				},
+			}
+			_, err := runtimeServiceServer.RegisterJobEvent(context.Background(), req)
+			assert.Nil(t, err)
+		})
+		t.Run("should register sla miss events of sensors as sensor timeouts", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			}
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "game_jam",
+				ProjectSpec: projectSpec,
+			}
+			jobSpec := models.JobSpec{
+				Name: "transform-tables",
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobSpec.Name, namespaceSpec).Return(jobSpec, nil)
+			defer jobService.AssertExpectations(t)
+
+			eventValues, _ := structpb.NewStruct(
+				map[string]interface{}{
+					"sensor_timeout": true,
+					"task_id":        "wait_upstream-job-bq2bq",
+					"timeout":        "6:00:00",
+				},
+			)
+			eventSvc := new(mock.EventService)
+			eventSvc.On("Register", context.Background(), namespaceSpec, jobSpec, models.JobEvent{
+				Type:  models.JobEventTypeSensorTimeout,
+				Value: eventValues.GetFields(),
+			}).Return(nil)
+			defer eventSvc.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, eventSvc, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			_, err := runtimeServiceServer.RegisterJobEvent(context.Background(), &pb.RegisterJobEventRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				Namespace:   namespaceSpec.Name,
+				Event: &pb.JobEvent{
+					Type:  pb.JobEvent_SLA_MISS,
+					Value: eventValues,
+				},
+			})
+			assert.Nil(t, err)
+		})
+		t.Run("should record state of the run an event of its outcome is raised for", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			}
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "game_jam",
+				ProjectSpec: projectSpec,
+			}
+			jobSpec := models.JobSpec{
+				Name: "transform-tables",
+				Schedule: models.JobSpecSchedule{
+					Interval: "0 2 * * *",
+				},
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobSpec.Name, namespaceSpec).Return(jobSpec, nil)
+
+			eventValues, _ := structpb.NewStruct(
+				map[string]interface{}{
+					"scheduled_at": "2021-05-19T02:00:00Z",
+				},
+			)
+			eventSvc := new(mock.EventService)
+			eventSvc.On("Register", context.Background(), namespaceSpec, jobSpec, models.JobEvent{
+				Type:  models.JobEventTypeSuccess,
+				Value: eventValues.GetFields(),
+			}).Return(nil)
+			defer eventSvc.AssertExpectations(t)
+
+			instanceService := new(mock.InstanceService)
+			instanceService.On("UpdateState", jobSpec, time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC),
+				models.InstanceStateSuccess).Return(nil)
+			defer instanceService.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"1.0.0",
+				jobService, eventSvc, nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			_, err := runtimeServiceServer.RegisterJobEvent(context.Background(), &pb.RegisterJobEventRequest{
+				ProjectName: projectSpec.Name,
+				JobName:     jobSpec.Name,
+				Namespace:   namespaceSpec.Name,
+				Event: &pb.JobEvent{
+					Type:  pb.JobEvent_SUCCESS,
+					Value: eventValues,
+				},
+			})
+			assert.Nil(t, err)
+		})
+		t.Run("should collect cost of datastore jobs the run reported without failing on errors", func(t *testing.T) {
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			}
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "game_jam",
+				ProjectSpec: projectSpec,
+			}
+			jobSpec := models.JobSpec{
+				Name: "transform-tables",
+				Schedule: models.JobSpecSchedule{
+					Interval: "0 2 * * *",
+				},
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectSpec.Name).Return(projectSpec, nil)
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobSpec.Name, namespaceSpec).Return(jobSpec, nil)
+
+			eventValues, _ := structpb.NewStruct(
+				map[string]interface{}{
+					"scheduled_at":               "2021-05-19T02:00:00Z",
+					v1.EventValueDatastoreJobIDs: []interface{}{"proj:US.job-1", "proj:US.job-2"},
 				},
 			)
 			eventSvc := new(mock.EventService)
-			eventSvc.On("Register", context.Background(), namespaceSpec, jobSpecs[0], models.JobEvent{
+			eventSvc.On("Register", context.Background(), namespaceSpec, jobSpec, models.JobEvent{
 				Type:  models.JobEventTypeFailure,
 				Value: eventValues.GetFields(),
 			}).Return(nil)
 			defer eventSvc.AssertExpectations(t)
 
+			instanceService := new(mock.InstanceService)
+			instanceService.On("UpdateState", jobSpec, time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC),
+				models.InstanceStateFailed).Return(nil)
+			defer instanceService.AssertExpectations(t)
+
+			costCollector := new(mock.CostCollector)
+			costCollector.On("Collect", context.Background(), namespaceSpec, jobSpec,
+				time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC), []string{"proj:US.job-1", "proj:US.job-2"}).
+				Return(errors.New("access denied"))
+			defer costCollector.AssertExpectations(t)
+
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
-				Version,
+				"1.0.0",
 				jobService, eventSvc, nil,
 				projectRepoFactory,
 				namespaceRepoFact,
 				nil,
-				adapter,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
+				nil,
 				nil,
 				nil,
+				costCollector,
 				nil,
 			)
-			req := &pb.RegisterJobEventRequest{
+			_, err := runtimeServiceServer.RegisterJobEvent(context.Background(), &pb.RegisterJobEventRequest{
 				ProjectName: projectSpec.Name,
-				JobName:     jobSpecs[0].Name,
+				JobName:     jobSpec.Name,
 				Namespace:   namespaceSpec.Name,
 				Event: &pb.JobEvent{
 					Type:  pb.JobEvent_FAILURE,
 					Value: eventValues,
 				},
-			}
-			_, err := runtimeServiceServer.RegisterJobEvent(context.Background(), req)
+			})
 			assert.Nil(t, err)
 		})
 	})
@@ -1264,6 +2344,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
 			scheduledAtTimestamp := timestamppb.New(scheduledAt)
@@ -1292,6 +2376,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
 			scheduledAtTimestamp := timestamppb.New(scheduledAt)
@@ -1415,6 +2503,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			req := pb.DumpJobSpecificationRequest{
@@ -1519,12 +2611,210 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			resp, err := runtimeServiceServer.CreateResource(context.Background(), &req)
 			assert.Nil(t, err)
 			assert.Equal(t, true, resp.GetSuccess())
 		})
+
+		t.Run("should fail if project resource quota is exceeded", func(t *testing.T) {
+			projectName := "a-data-project"
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-test-namespace-1",
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+				},
+				ProjectSpec: projectSpec,
+			}
+
+			// prepare mocked datastore
+			dsTypeTableAdapter := new(mock.DatastoreTypeAdapter)
+
+			dsTypeTableController := new(mock.DatastoreTypeController)
+			dsTypeTableController.On("Adapter").Return(dsTypeTableAdapter)
+
+			dsTypeDatasetController := new(mock.DatastoreTypeController)
+			dsTypeDatasetController.On("Adapter").Return(dsTypeTableAdapter)
+
+			dsController := map[models.ResourceType]models.DatastoreTypeController{
+				models.ResourceTypeDataset: dsTypeTableController,
+			}
+			datastorer := new(mock.Datastorer)
+			datastorer.On("Types").Return(dsController)
+			datastorer.On("Name").Return("bq")
+
+			dsRepo := new(mock.SupportedDatastoreRepo)
+			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
+
+			resourceSpec := models.ResourceSpec{
+				Version:   1,
+				Name:      "proj.datas",
+				Type:      models.ResourceTypeDataset,
+				Datastore: datastorer,
+			}
+
+			dsTypeTableAdapter.On("FromProtobuf", mock2.Anything).Return(resourceSpec, nil)
+
+			req := pb.CreateResourceRequest{
+				ProjectName:   projectName,
+				DatastoreName: "bq",
+				Resource: &pb.ResourceSpecification{
+					Version: 1,
+					Name:    "proj.datas",
+					Type:    models.ResourceTypeDataset.String(),
+				},
+				Namespace: namespaceSpec.Name,
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			resourceSvc := new(mock.DatastoreService)
+			resourceSvc.On("GetAll", namespaceSpec, "bq").Return([]models.ResourceSpec{{Name: "proj.other"}}, nil)
+			defer resourceSvc.AssertExpectations(t)
+
+			quotaSvc := new(mock.QuotaService)
+			quotaSvc.On("CheckResources", projectSpec, 2).Return(errors.Wrap(models.ErrQuotaExceeded, "project a-data-project can have at most 1 resources, requested 2"))
+			defer quotaSvc.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				nil, nil,
+				resourceSvc,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, dsRepo),
+				nil,
+				nil,
+				nil,
+				quotaSvc,
+				nil,
+				nil,
+				nil,
+			)
+
+			_, err := runtimeServiceServer.CreateResource(context.Background(), &req)
+			assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		})
+
+		t.Run("should fail if resource name violates naming policy of project", func(t *testing.T) {
+			projectName := "a-data-project"
+			projectSpec := models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: projectName,
+				Config: map[string]string{
+					"bucket": "gs://some_folder",
+					models.ProjectNamingKeyPrefix + "DATASET": `proj\.[a-z_]+_dataset`,
+				},
+			}
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-test-namespace-1",
+				ProjectSpec: projectSpec,
+			}
+
+			dsTypeTableAdapter := new(mock.DatastoreTypeAdapter)
+			dsTypeTableController := new(mock.DatastoreTypeController)
+			dsTypeTableController.On("Adapter").Return(dsTypeTableAdapter)
+			dsController := map[models.ResourceType]models.DatastoreTypeController{
+				models.ResourceTypeDataset: dsTypeTableController,
+			}
+			datastorer := new(mock.Datastorer)
+			datastorer.On("Types").Return(dsController)
+
+			dsRepo := new(mock.SupportedDatastoreRepo)
+			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
+
+			resourceSpec := models.ResourceSpec{
+				Version:   1,
+				Name:      "proj.datas",
+				Type:      models.ResourceTypeDataset,
+				Datastore: datastorer,
+			}
+			dsTypeTableAdapter.On("FromProtobuf", mock2.Anything).Return(resourceSpec, nil)
+
+			req := pb.CreateResourceRequest{
+				ProjectName:   projectName,
+				DatastoreName: "bq",
+				Resource: &pb.ResourceSpecification{
+					Version: 1,
+					Name:    "proj.datas",
+					Type:    models.ResourceTypeDataset.String(),
+				},
+				Namespace: namespaceSpec.Name,
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			resourceSvc := new(mock.DatastoreService)
+			defer resourceSvc.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				nil, nil,
+				resourceSvc,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, dsRepo),
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+
+			_, err := runtimeServiceServer.CreateResource(context.Background(), &req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Equal(t, `dataset proj.datas doesn't match NAMING_DATASET proj\.[a-z_]+_dataset: names violate naming policy of project`,
+				status.Convert(err).Message())
+		})
 	})
 
 	t.Run("UpdateResource", func(t *testing.T) {
@@ -1617,6 +2907,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 
 			resp, err := runtimeServiceServer.UpdateResource(context.Background(), &req)
@@ -1675,14 +2969,89 @@ func TestRuntimeServiceServer(t *testing.T) {
 			dagNode := tree.NewTreeNode(jobSpec)
 			dagNode.Runs.Add(time.Date(2020, 11, 25, 2, 0, 0, 0, time.UTC))
 			dagNode.Runs.Add(time.Date(2020, 11, 26, 2, 0, 0, 0, time.UTC))
-			dagNode.Runs.Add(time.Date(2020, 11, 27, 2, 0, 0, 0, time.UTC))
-			dagNode.Runs.Add(time.Date(2020, 11, 28, 2, 0, 0, 0, time.UTC))
+			dagNode.Runs.Add(time.Date(2020, 11, 27, 2, 0, 0, 0, time.UTC))
+			dagNode.Runs.Add(time.Date(2020, 11, 28, 2, 0, 0, 0, time.UTC))
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			jobService.On("ReplayDryRun", replayWorkerRequest).Return(dagNode, nil)
+			defer jobService.AssertExpectations(t)
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService, nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.ReplayDryRun(context.Background(), &replayRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, true, replayResponse.Success)
+			expectedReplayResponse, err := adapter.ToReplayExecutionTreeNode(dagNode)
+			assert.Nil(t, err)
+			assert.Equal(t, expectedReplayResponse.JobName, replayResponse.Response.JobName)
+			assert.Equal(t, expectedReplayResponse.Dependents, replayResponse.Response.Dependents)
+			assert.Equal(t, expectedReplayResponse.Runs, replayResponse.Response.Runs)
+		})
+		t.Run("should send estimated impact of replay in response header", func(t *testing.T) {
+			startDate, _ := time.Parse(timeLayout, "2020-11-25")
+			endDate, _ := time.Parse(timeLayout, "2020-11-28")
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projectSpec,
+			}
+			downstreamSpec := models.JobSpec{Name: "downstream-job"}
+			downstreamNode := tree.NewTreeNode(downstreamSpec)
+			downstreamNode.Runs.Add(time.Date(2020, 11, 25, 2, 0, 0, 0, time.UTC))
+			dagNode := tree.NewTreeNode(jobSpec)
+			dagNode.Runs.Add(time.Date(2020, 11, 25, 2, 0, 0, 0, time.UTC))
+			dagNode.Runs.Add(time.Date(2020, 11, 26, 2, 0, 0, 0, time.UTC))
+			dagNode.AddDependent(downstreamNode)
 
 			jobService := new(mock.JobService)
 			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
 			jobService.On("ReplayDryRun", replayWorkerRequest).Return(dagNode, nil)
 			defer jobService.AssertExpectations(t)
 
+			instanceService := new(mock.InstanceService)
+			instanceService.On("GetAverageDuration", jobSpec).Return(time.Minute*30, nil)
+			instanceService.On("GetAverageDuration", downstreamSpec).Return(time.Duration(0), nil)
+			defer instanceService.AssertExpectations(t)
+
 			projectRepository := new(mock.ProjectRepository)
 			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
 			defer projectRepository.AssertExpectations(t)
@@ -1698,7 +3067,6 @@ func TestRuntimeServiceServer(t *testing.T) {
 			namespaceRepoFact := new(mock.NamespaceRepoFactory)
 			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
 			defer namespaceRepoFact.AssertExpectations(t)
-			adapter := v1.NewAdapter(nil, nil)
 			runtimeServiceServer := v1.NewRuntimeServiceServer(
 				"Version",
 				jobService, nil,
@@ -1706,7 +3074,11 @@ func TestRuntimeServiceServer(t *testing.T) {
 				projectRepoFactory,
 				namespaceRepoFact,
 				nil,
-				adapter,
+				v1.NewAdapter(nil, nil),
+				nil,
+				instanceService,
+				nil,
+				nil,
 				nil,
 				nil,
 				nil,
@@ -1718,14 +3090,13 @@ func TestRuntimeServiceServer(t *testing.T) {
 				StartDate:   startDate.Format(timeLayout),
 				EndDate:     endDate.Format(timeLayout),
 			}
-			replayResponse, err := runtimeServiceServer.ReplayDryRun(context.Background(), &replayRequest)
-			assert.Nil(t, err)
-			assert.Equal(t, true, replayResponse.Success)
-			expectedReplayResponse, err := adapter.ToReplayExecutionTreeNode(dagNode)
+			stream := &headerStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+			_, err := runtimeServiceServer.ReplayDryRun(ctx, &replayRequest)
 			assert.Nil(t, err)
-			assert.Equal(t, expectedReplayResponse.JobName, replayResponse.Response.JobName)
-			assert.Equal(t, expectedReplayResponse.Dependents, replayResponse.Response.Dependents)
-			assert.Equal(t, expectedReplayResponse.Runs, replayResponse.Response.Runs)
+			assert.Equal(t, []string{"3"}, stream.header.Get(v1.MetadataReplayRuns))
+			assert.Equal(t, []string{"3600"}, stream.header.Get(v1.MetadataReplayEstimatedDuration))
+			assert.Equal(t, []string{"1"}, stream.header.Get(v1.MetadataReplayRunsWithoutHistory))
 		})
 		t.Run("should failed when replay request is invalid", func(t *testing.T) {
 			startDate, _ := time.Parse(timeLayout, "2020-11-25")
@@ -1763,6 +3134,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -1819,6 +3194,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -1915,6 +3294,75 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   startDate.Format(timeLayout),
+				EndDate:     endDate.Format(timeLayout),
+			}
+			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, randomUUID, replayResponse.Id)
+		})
+		t.Run("should release replay runs reserved in quota if replay is not accepted", func(t *testing.T) {
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projectSpec,
+			}
+			dagNode := tree.NewTreeNode(jobSpec)
+			dagNode.Runs.Add(time.Date(2020, 11, 25, 2, 0, 0, 0, time.UTC))
+			dagNode.Runs.Add(time.Date(2020, 11, 26, 2, 0, 0, 0, time.UTC))
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(jobSpec, nil)
+			jobService.On("ReplayDryRun", replayWorkerRequest).Return(dagNode, nil).Once()
+			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return("", job.ErrRequestQueueFull)
+			defer jobService.AssertExpectations(t)
+
+			quotaSvc := new(mock.QuotaService)
+			quotaSvc.On("ReserveReplayRuns", projectSpec, 2).Return(nil)
+			quotaSvc.On("ReleaseReplayRuns", projectSpec, 2).Return(nil)
+			defer quotaSvc.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				v1.NewAdapter(nil, nil),
+				nil,
+				nil,
+				nil,
+				quotaSvc,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -1923,9 +3371,157 @@ func TestRuntimeServiceServer(t *testing.T) {
 				StartDate:   startDate.Format(timeLayout),
 				EndDate:     endDate.Format(timeLayout),
 			}
+			_, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.Equal(t, codes.Unavailable, status.Code(err))
+		})
+		t.Run("should replay the last runs of job resolved against its schedule", func(t *testing.T) {
+			scheduledJobSpec := jobSpec
+			scheduledJobSpec.Schedule = models.JobSpecSchedule{
+				StartDate: time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC),
+				Interval:  "0 2 * * *",
+			}
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     scheduledJobSpec,
+				Start:   time.Date(2020, 11, 25, 2, 0, 0, 0, time.UTC),
+				End:     time.Date(2020, 11, 27, 2, 0, 0, 0, time.UTC),
+				Project: projectSpec,
+			}
+			randomUUID := "random-uuid"
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(scheduledJobSpec, nil)
+			jobService.On("Replay", context.TODO(), replayWorkerRequest).Return(randomUUID, nil)
+			defer jobService.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			runtimeServiceServer.Now = func() time.Time {
+				return time.Date(2020, 11, 28, 10, 0, 0, 0, time.UTC)
+			}
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+				StartDate:   "last:3",
+			}
 			replayResponse, err := runtimeServiceServer.Replay(context.TODO(), &replayRequest)
 			assert.Nil(t, err)
 			assert.Equal(t, randomUUID, replayResponse.Id)
+
+			replayRequest.EndDate = "2020-11-28"
+			_, err = runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+			replayRequest.StartDate, replayRequest.EndDate = job.ReplaySinceLastSuccess, ""
+			_, err = runtimeServiceServer.Replay(context.TODO(), &replayRequest)
+			assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		})
+		t.Run("should replay preset of job named in request metadata", func(t *testing.T) {
+			presetJobSpec := jobSpec
+			presetJobSpec.Schedule = models.JobSpecSchedule{
+				StartDate: time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC),
+				Interval:  "0 2 * * *",
+			}
+			presetJobSpec.Behavior.ReplayPresets = map[string]models.JobSpecReplayPreset{
+				"last_week": {Start: "last:7", Selector: "tier=critical", Force: true},
+			}
+			replayWorkerRequest := &models.ReplayWorkerRequest{
+				Job:     presetJobSpec,
+				Start:   time.Date(2020, 11, 21, 2, 0, 0, 0, time.UTC),
+				End:     time.Date(2020, 11, 27, 2, 0, 0, 0, time.UTC),
+				Project: projectSpec,
+				Force:   true,
+				Selector: models.LabelSelector{
+					{Key: "tier", Operator: models.LabelOperatorEquals, Value: "critical"},
+				},
+			}
+			randomUUID := "random-uuid"
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetByName", projectName).Return(projectSpec, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobService := new(mock.JobService)
+			jobService.On("GetByName", jobName, namespaceSpec).Return(presetJobSpec, nil)
+			jobService.On("Replay", mock2.Anything, replayWorkerRequest).Return(randomUUID, nil)
+			defer jobService.AssertExpectations(t)
+
+			namespaceRepository := new(mock.NamespaceRepository)
+			namespaceRepository.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+			defer namespaceRepository.AssertExpectations(t)
+
+			namespaceRepoFact := new(mock.NamespaceRepoFactory)
+			namespaceRepoFact.On("New", projectSpec).Return(namespaceRepository)
+			defer namespaceRepoFact.AssertExpectations(t)
+			adapter := v1.NewAdapter(nil, nil)
+			runtimeServiceServer := v1.NewRuntimeServiceServer(
+				"Version",
+				jobService,
+				nil,
+				nil,
+				projectRepoFactory,
+				namespaceRepoFact,
+				nil,
+				adapter,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+			)
+			runtimeServiceServer.Now = func() time.Time {
+				return time.Date(2020, 11, 28, 10, 0, 0, 0, time.UTC)
+			}
+			replayRequest := pb.ReplayRequest{
+				ProjectName: projectName,
+				Namespace:   namespaceSpec.Name,
+				JobName:     jobName,
+			}
+			ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs(v1.MetadataReplayPreset, "last_week"))
+			replayResponse, err := runtimeServiceServer.Replay(ctx, &replayRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, randomUUID, replayResponse.Id)
+
+			ctx = metadata.NewIncomingContext(context.TODO(), metadata.Pairs(v1.MetadataReplayPreset, "full_history"))
+			_, err = runtimeServiceServer.Replay(ctx, &replayRequest)
+			assert.Equal(t, codes.NotFound, status.Code(err))
 		})
 		t.Run("should failed when replay request is invalid", func(t *testing.T) {
 			projectRepository := new(mock.ProjectRepository)
@@ -1956,6 +3552,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -2011,6 +3611,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -2048,6 +3652,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -2096,6 +3704,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -2151,6 +3763,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -2207,6 +3823,10 @@ func TestRuntimeServiceServer(t *testing.T) {
 				nil,
 				nil,
 				nil,
+				nil,
+				nil,
+				nil,
+				nil,
 			)
 			replayRequest := pb.ReplayRequest{
 				ProjectName: projectName,
@@ -2223,3 +3843,21 @@ func TestRuntimeServiceServer(t *testing.T) {
 		})
 	})
 }
+
+// headerStream records headers sent by a grpc handler
+type headerStream struct {
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return "" }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *headerStream) SetTrailer(md metadata.MD) error { return nil }
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// MetadataAcceptScheduleImpact set to true in request metadata of job
+	// deployment acknowledges breaking impact of changed schedules and
+	// windows, see job.ScheduleImpact. Deployments with breaking impact are
+	// rejected without it
+	MetadataAcceptScheduleImpact = "x-accept-schedule-impact"
+
+	// ScheduleImpactViolationType identifies the precondition failure detail
+	// sent for each job whose breaking schedule change rejected a deployment
+	ScheduleImpactViolationType = "SCHEDULE_IMPACT"
+)
+
+func isScheduleImpactAccepted(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	vals := md.Get(MetadataAcceptScheduleImpact)
+	return len(vals) > 0 && vals[0] == "true"
+}
+
+// checkScheduleImpact reports impact of changed schedules over the stream
+// and fails if any is breaking, unless accepted
+func checkScheduleImpact(previousJobs, currentJobs []models.JobSpec, now time.Time,
+	respStream pb.RuntimeService_DeployJobSpecificationServer) error {
+	impacts, err := job.AnalyzeScheduleChanges(previousJobs, currentJobs, now)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "%s: failed to analyze schedule changes", err.Error())
+	}
+
+	var violations []*errdetails.PreconditionFailure_Violation
+	for _, impact := range impacts {
+		if err := respStream.Send(&pb.DeployJobSpecificationResponse{
+			JobName: impact.JobName,
+			Message: impact.String(),
+		}); err != nil {
+			logger.E(errors.Wrapf(err, "failed to send schedule impact of: %s", impact.JobName))
+		}
+		if impact.Breaking() {
+			violations = append(violations, &errdetails.PreconditionFailure_Violation{
+				Type:        ScheduleImpactViolationType,
+				Subject:     impact.JobName,
+				Description: impact.String(),
+			})
+		}
+	}
+	if len(violations) == 0 || isScheduleImpactAccepted(respStream.Context()) {
+		return nil
+	}
+
+	var jobNames []string
+	for _, violation := range violations {
+		jobNames = append(jobNames, violation.Subject)
+	}
+	msg := fmt.Sprintf("schedule changes of %s have breaking impact, deploy with %s set to true to accept it",
+		strings.Join(jobNames, ", "), MetadataAcceptScheduleImpact)
+	st, err := status.New(codes.FailedPrecondition, msg).WithDetails(&errdetails.PreconditionFailure{
+		Violations: violations,
+	})
+	if err != nil {
+		return status.Error(codes.FailedPrecondition, msg)
+	}
+	return st.Err()
+}
@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	SearchKindJob      = "job"
+	SearchKindResource = "resource"
+)
+
+// JobSearchResponse is a job found by a search served over http
+type JobSearchResponse struct {
+	Name        string            `json:"name"`
+	Project     string            `json:"project"`
+	Namespace   string            `json:"namespace"`
+	Owner       string            `json:"owner,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Destination string            `json:"destination,omitempty"`
+	Task        string            `json:"task"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// ResourceSearchResponse is a resource found by a search served over http
+type ResourceSearchResponse struct {
+	Name      string            `json:"name"`
+	Project   string            `json:"project"`
+	Namespace string            `json:"namespace"`
+	Datastore string            `json:"datastore"`
+	Type      string            `json:"type"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type SearchResponse struct {
+	Jobs      []JobSearchResponse      `json:"jobs"`
+	Resources []ResourceSearchResponse `json:"resources"`
+}
+
+// SearchHandler finds jobs and resources across projects. Terms of q query
+// param are matched against names, owners, destinations and tasks of jobs and
+// names, datastores and types of resources. label params as key=value, project,
+// owner, task, datastore, type and limit narrow the results, kind limits them
+// to jobs or resources
+type SearchHandler struct {
+	repo store.SearchRepository
+}
+
+func (h *SearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	params := r.URL.Query()
+	query, err := parseSearchQuery(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	kind := params.Get("kind")
+	if kind != "" && kind != SearchKindJob && kind != SearchKindResource {
+		http.Error(w, "kind should be job or resource", http.StatusBadRequest)
+		return
+	}
+
+	// filters of jobs don't apply to resources and the other way round, so
+	// setting them limits results to their kind
+	jobFilters := query.Owner != "" || query.Task != ""
+	resourceFilters := query.Datastore != "" || query.Type != ""
+	searchJobs := kind == SearchKindJob || (kind == "" && !resourceFilters)
+	searchResources := kind == SearchKindResource || (kind == "" && !jobFilters)
+
+	resp := SearchResponse{
+		Jobs:      []JobSearchResponse{},
+		Resources: []ResourceSearchResponse{},
+	}
+	if searchJobs {
+		jobs, err := h.repo.SearchJobs(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, job := range jobs {
+			resp.Jobs = append(resp.Jobs, JobSearchResponse{
+				Name:        job.Name,
+				Project:     job.Project,
+				Namespace:   job.Namespace,
+				Owner:       job.Owner,
+				Description: job.Description,
+				Destination: job.Destination,
+				Task:        job.Task,
+				Labels:      job.Labels,
+				UpdatedAt:   job.UpdatedAt,
+			})
+		}
+	}
+	if searchResources {
+		resources, err := h.repo.SearchResources(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, resource := range resources {
+			resp.Resources = append(resp.Resources, ResourceSearchResponse{
+				Name:      resource.Name,
+				Project:   resource.Project,
+				Namespace: resource.Namespace,
+				Datastore: resource.Datastore,
+				Type:      resource.Type,
+				Labels:    resource.Labels,
+				UpdatedAt: resource.UpdatedAt,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func parseSearchQuery(params map[string][]string) (models.SearchQuery, error) {
+	get := func(key string) string {
+		if vals := params[key]; len(vals) > 0 {
+			return strings.TrimSpace(vals[0])
+		}
+		return ""
+	}
+	query := models.SearchQuery{
+		Text:      get("q"),
+		Project:   get("project"),
+		Owner:     get("owner"),
+		Task:      get("task"),
+		Datastore: get("datastore"),
+		Type:      get("type"),
+	}
+	for _, label := range params["label"] {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return query, errors.Errorf("invalid label %s, expected key=value", label)
+		}
+		if query.Labels == nil {
+			query.Labels = map[string]string{}
+		}
+		query.Labels[parts[0]] = parts[1]
+	}
+	if query.Text == "" && len(query.Labels) == 0 && query.Owner == "" && query.Task == "" &&
+		query.Datastore == "" && query.Type == "" {
+		return query, errors.New("q or a filter is required")
+	}
+	if val := get("limit"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil || limit <= 0 {
+			return query, errors.New("invalid limit")
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+func NewSearchHandler(repo store.SearchRepository) *SearchHandler {
+	return &SearchHandler{
+		repo: repo,
+	}
+}
@@ -0,0 +1,78 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchHandler(t *testing.T) {
+	updatedAt := time.Date(2021, 6, 1, 2, 0, 0, 0, time.UTC)
+
+	t.Run("should search jobs and resources", func(t *testing.T) {
+		query := models.SearchQuery{Text: "finance.daily", Labels: map[string]string{"team": "finance"}, Limit: 5}
+		repo := new(mock.SearchRepository)
+		repo.On("SearchJobs", query).Return([]models.JobSearchResult{{
+			Name: "daily_revenue", Project: "shop", Namespace: "finance", Owner: "finance@example.com",
+			Destination: "shop-project:finance.daily_revenue", Task: "bq2bq", UpdatedAt: updatedAt,
+		}}, nil)
+		repo.On("SearchResources", query).Return([]models.ResourceSearchResult{{
+			Name: "shop-project.finance.daily_revenue", Project: "shop", Namespace: "finance",
+			Datastore: "bigquery", Type: "table", UpdatedAt: updatedAt,
+		}}, nil)
+		defer repo.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewSearchHandler(repo).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			"/search?q=finance.daily&label=team%3Dfinance&limit=5", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp v1.SearchResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, v1.SearchResponse{
+			Jobs: []v1.JobSearchResponse{{
+				Name: "daily_revenue", Project: "shop", Namespace: "finance", Owner: "finance@example.com",
+				Destination: "shop-project:finance.daily_revenue", Task: "bq2bq", UpdatedAt: updatedAt,
+			}},
+			Resources: []v1.ResourceSearchResponse{{
+				Name: "shop-project.finance.daily_revenue", Project: "shop", Namespace: "finance",
+				Datastore: "bigquery", Type: "table", UpdatedAt: updatedAt,
+			}},
+		}, resp)
+	})
+	t.Run("should only search jobs when filtered by filters of jobs", func(t *testing.T) {
+		query := models.SearchQuery{Text: "revenue", Task: "bq2bq"}
+		repo := new(mock.SearchRepository)
+		repo.On("SearchJobs", query).Return([]models.JobSearchResult{}, nil)
+		defer repo.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewSearchHandler(repo).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?q=revenue&task=bq2bq", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"jobs": [], "resources": []}`, rec.Body.String())
+	})
+	t.Run("should only search the kind requested", func(t *testing.T) {
+		query := models.SearchQuery{Text: "revenue"}
+		repo := new(mock.SearchRepository)
+		repo.On("SearchResources", query).Return([]models.ResourceSearchResult{}, nil)
+		defer repo.AssertExpectations(t)
+
+		rec := httptest.NewRecorder()
+		v1.NewSearchHandler(repo).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?q=revenue&kind=resource", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("should reject searches without terms or filters", func(t *testing.T) {
+		for _, target := range []string{"/search", "/search?q=revenue&label=team", "/search?q=revenue&kind=view", "/search?q=a&limit=0"} {
+			rec := httptest.NewRecorder()
+			v1.NewSearchHandler(new(mock.SearchRepository)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// StaleJobReportGetter returns jobs of a project which look unused as found
+// in the latest analysis and when it ran
+type StaleJobReportGetter interface {
+	GetReport(projectName string) ([]models.StaleJob, time.Time)
+}
+
+// StaleJobResponse is a stale job served over http
+type StaleJobResponse struct {
+	Namespace   string     `json:"namespace"`
+	JobName     string     `json:"job_name"`
+	Owner       string     `json:"owner"`
+	Destination string     `json:"destination,omitempty"`
+	Reasons     []string   `json:"reasons"`
+	Runs        int        `json:"runs"`
+	LastReadAt  *time.Time `json:"last_read_at,omitempty"`
+}
+
+type StaleJobsResponse struct {
+	AnalyzedAt time.Time          `json:"analyzed_at"`
+	Jobs       []StaleJobResponse `json:"jobs"`
+}
+
+// StaleJobHandler serves the cleanup report of a project identified by
+// project query param, jobs without successful runs or reads of their
+// destination within the window of analysis
+type StaleJobHandler struct {
+	report             StaleJobReportGetter
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *StaleJobHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	staleJobs, analyzedAt := h.report.GetReport(projSpec.Name)
+	resp := StaleJobsResponse{AnalyzedAt: analyzedAt, Jobs: []StaleJobResponse{}}
+	for _, staleJob := range staleJobs {
+		jobResp := StaleJobResponse{
+			Namespace:   staleJob.NamespaceName,
+			JobName:     staleJob.JobName,
+			Owner:       staleJob.Owner,
+			Destination: staleJob.Destination,
+			Reasons:     []string{},
+			Runs:        staleJob.Runs,
+		}
+		for _, reason := range staleJob.Reasons {
+			jobResp.Reasons = append(jobResp.Reasons, string(reason))
+		}
+		if !staleJob.LastReadAt.IsZero() {
+			lastReadAt := staleJob.LastReadAt
+			jobResp.LastReadAt = &lastReadAt
+		}
+		resp.Jobs = append(resp.Jobs, jobResp)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewStaleJobHandler(report StaleJobReportGetter, projectRepoFactory ProjectRepoFactory) *StaleJobHandler {
+	return &StaleJobHandler{
+		report:             report,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// NamespaceStats are the operational stats of a namespace served over http
+type NamespaceStats struct {
+	Name       string `json:"name"`
+	Jobs       int    `json:"jobs"`
+	FailedRuns int64  `json:"failed_runs"`
+	SLAMisses  int64  `json:"sla_misses"`
+}
+
+// CostBudgetReader returns spend of a project in the current month against
+// its budget
+type CostBudgetReader interface {
+	Status(proj models.ProjectSpec) (models.CostBudgetStatus, error)
+}
+
+// CostBudgetResponse is spend of a project in a month against its monthly
+// budget served over http, bytes are billed ones
+type CostBudgetResponse struct {
+	Month             string  `json:"month"`
+	BudgetBytes       int64   `json:"budget_bytes"`
+	SpentBytes        int64   `json:"spent_bytes"`
+	Percent           float64 `json:"percent"`
+	ThresholdsReached []int   `json:"thresholds_reached"`
+}
+
+// StatsResponse are the operational stats of a project served over http,
+// runs and their events are counted since the server started. Budget is
+// left out for projects without one
+type StatsResponse struct {
+	ProjectName     string              `json:"project_name"`
+	Since           time.Time           `json:"since"`
+	Jobs            int                 `json:"jobs"`
+	Runs            int64               `json:"runs"`
+	FailedRuns      int64               `json:"failed_runs"`
+	FailureRate     float64             `json:"failure_rate"`
+	SLAMisses       int64               `json:"sla_misses"`
+	ReplayRunsToday int                 `json:"replay_runs_today"`
+	Namespaces      []NamespaceStats    `json:"namespaces"`
+	Budget          *CostBudgetResponse `json:"budget,omitempty"`
+}
+
+// StatsHandler serves stats of a project identified by project query param,
+// meant for tenant dashboards
+type StatsHandler struct {
+	metrics              *Metrics
+	jobSvc               models.JobService
+	quotaRepo            store.ProjectQuotaRepository
+	budgetReader         CostBudgetReader
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+}
+
+func (h *StatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	namespaces, err := h.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	replayRuns, err := h.quotaRepo.GetReplayRuns(projSpec, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	runs, events := h.metrics.JobStats(projSpec.Name)
+	resp := StatsResponse{
+		ProjectName:     projSpec.Name,
+		Since:           h.metrics.Since,
+		Runs:            runs,
+		ReplayRunsToday: replayRuns,
+		Namespaces:      []NamespaceStats{},
+	}
+	for _, namespace := range namespaces {
+		jobSpecs, err := h.jobSvc.GetAll(namespace)
+		if err != nil {
+			http.Error(w, errors.Wrapf(err, "failed to read jobs of %s", namespace.Name).Error(), http.StatusInternalServerError)
+			return
+		}
+		nsEvents := events[namespace.Name]
+		resp.Namespaces = append(resp.Namespaces, NamespaceStats{
+			Name:       namespace.Name,
+			Jobs:       len(jobSpecs),
+			FailedRuns: nsEvents[string(models.JobEventTypeFailure)],
+			SLAMisses:  nsEvents[string(models.JobEventTypeSLAMiss)],
+		})
+		resp.Jobs += len(jobSpecs)
+	}
+	sort.Slice(resp.Namespaces, func(i, j int) bool {
+		return resp.Namespaces[i].Name < resp.Namespaces[j].Name
+	})
+	for _, nsEvents := range events {
+		resp.FailedRuns += nsEvents[string(models.JobEventTypeFailure)]
+		resp.SLAMisses += nsEvents[string(models.JobEventTypeSLAMiss)]
+	}
+	if resp.Runs > 0 {
+		resp.FailureRate = float64(resp.FailedRuns) / float64(resp.Runs)
+	}
+	if h.budgetReader != nil {
+		budget, err := h.budgetReader.Status(projSpec)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if budget.BudgetBytes > 0 {
+			resp.Budget = &CostBudgetResponse{
+				Month:             budget.Month.Format("2006-01"),
+				BudgetBytes:       budget.BudgetBytes,
+				SpentBytes:        budget.SpentBytes,
+				Percent:           budget.Percent(),
+				ThresholdsReached: budget.ThresholdsReached(),
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewStatsHandler(metrics *Metrics, jobSvc models.JobService, quotaRepo store.ProjectQuotaRepository,
+	budgetReader CostBudgetReader, projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory) *StatsHandler {
+	return &StatsHandler{
+		metrics:              metrics,
+		jobSvc:               jobSvc,
+		quotaRepo:            quotaRepo,
+		budgetReader:         budgetReader,
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+	}
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+// TemplateMigrationRequest is what template migration operations are
+// started with
+type TemplateMigrationRequest struct {
+	Swap bool `json:"swap"`
+}
+
+// TemplateMigrationResponse is the result of template migration operations
+type TemplateMigrationResponse struct {
+	Jobs     int               `json:"jobs"`
+	Invalid  map[string]string `json:"invalid,omitempty"`
+	Swapped  bool              `json:"swapped"`
+	Uploaded int               `json:"uploaded"`
+}
+
+// TemplateMigrationHandler lets admins move jobs of a project identified by
+// project query param to the scheduler template of the server. POST starts
+// an operation recompiling all jobs of the project into its template staging
+// scheduler to validate them, and with swap query param set replaces the
+// deployed jobs with them if all of them are valid
+type TemplateMigrationHandler struct {
+	jobSvc               models.JobService
+	operations           models.OperationRunner
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+}
+
+func (h *TemplateMigrationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	var req TemplateMigrationRequest
+	if swap := r.URL.Query().Get("swap"); swap != "" {
+		var err error
+		if req.Swap, err = strconv.ParseBool(swap); err != nil {
+			http.Error(w, "invalid swap "+swap, http.StatusBadRequest)
+			return
+		}
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := job.TemplateStagingProject(projSpec); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	namespaces, err := h.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	op, err := h.operations.Start(projSpec, models.OperationKindTemplateMigration, r.Header.Get(MetadataActor), req,
+		func(ctx context.Context, report func(models.OperationProgress)) (interface{}, error) {
+			migration, err := h.jobSvc.MigrateTemplate(ctx, projSpec, namespaces, req.Swap,
+				&templateMigrationProgress{report: report})
+			resp := TemplateMigrationResponse{
+				Jobs:     migration.Jobs,
+				Invalid:  migration.Invalid,
+				Swapped:  migration.Swapped,
+				Uploaded: migration.Uploaded,
+			}
+			if err == nil && len(migration.Invalid) > 0 {
+				err = errors.Errorf("%d of %d jobs are invalid with the new template, deployed jobs are left as they are",
+					len(migration.Invalid), migration.Jobs)
+			}
+			return resp, err
+		})
+	if err != nil {
+		if errors.Is(err, operation.ErrManagerClosed) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeOperationAccepted(w, projSpec, op)
+}
+
+// templateMigrationProgress reports staged jobs validated so far as progress
+// of the template migration operation
+type templateMigrationProgress struct {
+	done   int
+	total  int
+	report func(models.OperationProgress)
+}
+
+func (p *templateMigrationProgress) Notify(evt progress.Event) {
+	switch e := evt.(type) {
+	case *job.EventTemplateMigrationStaged:
+		p.total = e.Jobs
+		p.report(models.OperationProgress{Done: p.done, Total: p.total})
+	case *job.EventTemplateMigrationJobValidated:
+		p.done++
+		p.report(models.OperationProgress{Done: p.done, Total: p.total})
+	}
+}
+
+func NewTemplateMigrationHandler(jobSvc models.JobService, operations models.OperationRunner,
+	projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory) *TemplateMigrationHandler {
+	return &TemplateMigrationHandler{
+		jobSvc:               jobSvc,
+		operations:           operations,
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+	}
+}
@@ -0,0 +1,133 @@
+package v1_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestTemplateMigrationHandler(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+		Config: map[string]string{
+			models.ProjectTemplateStagingStoragePathKey:   "gs://staging-bucket/optimus",
+			models.ProjectTemplateStagingSchedulerHostKey: "http://airflow.staging",
+		},
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	operationID := uuid.Must(uuid.NewRandom())
+	setup := func(projSpec models.ProjectSpec) (*mock.ProjectRepoFactory, *mock.NamespaceRepoFactory) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", projectSpec.Name).Return(projSpec, nil)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		namespaceRepository := new(mock.NamespaceRepository)
+		namespaceRepository.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFactory := new(mock.NamespaceRepoFactory)
+		namespaceRepoFactory.On("New", projSpec).Return(namespaceRepository)
+		return projectRepoFactory, namespaceRepoFactory
+	}
+	notify := func(args mock2.Arguments) {
+		observer := args.Get(4).(progress.Observer)
+		observer.Notify(&job.EventTemplateMigrationStaged{Jobs: 2})
+		observer.Notify(&job.EventTemplateMigrationJobValidated{Name: "a-job"})
+		observer.Notify(&job.EventTemplateMigrationJobValidated{Name: "b-job"})
+	}
+
+	t.Run("should migrate jobs of project as an operation", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup(projectSpec)
+		operations := new(mock.OperationRunner)
+		operations.On("Start", projectSpec, models.OperationKindTemplateMigration, "jane@example.com",
+			v1.TemplateMigrationRequest{Swap: true}).Return(models.Operation{
+			ID: operationID, Kind: models.OperationKindTemplateMigration, Status: models.OperationStatusPending}, nil)
+		defer operations.AssertExpectations(t)
+
+		jobService := new(mock.JobService)
+		jobService.On("MigrateTemplate", mock2.Anything, projectSpec, []models.NamespaceSpec{namespaceSpec}, true,
+			mock2.Anything).Run(notify).Return(models.TemplateMigration{
+			Jobs:     2,
+			Invalid:  map[string]string{},
+			Swapped:  true,
+			Uploaded: 2,
+		}, nil)
+		defer jobService.AssertExpectations(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/template-migration?project=a-data-project&swap=true", nil)
+		req.Header.Set(v1.MetadataActor, "jane@example.com")
+		rec := httptest.NewRecorder()
+		v1.NewTemplateMigrationHandler(jobService, operations, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "/operations?project=a-data-project&id="+operationID.String(), rec.Header().Get("Location"))
+
+		assert.Nil(t, operations.Err)
+		assert.Equal(t, []models.OperationProgress{{Done: 0, Total: 2}, {Done: 1, Total: 2}, {Done: 2, Total: 2}},
+			operations.Progress)
+		assert.Equal(t, v1.TemplateMigrationResponse{
+			Jobs:     2,
+			Invalid:  map[string]string{},
+			Swapped:  true,
+			Uploaded: 2,
+		}, operations.Result)
+	})
+	t.Run("should fail operation if any job is invalid", func(t *testing.T) {
+		projectRepoFactory, namespaceRepoFactory := setup(projectSpec)
+		operations := new(mock.OperationRunner)
+		operations.On("Start", projectSpec, models.OperationKindTemplateMigration, "",
+			v1.TemplateMigrationRequest{}).Return(models.Operation{ID: operationID}, nil)
+
+		jobService := new(mock.JobService)
+		jobService.On("MigrateTemplate", mock2.Anything, projectSpec, []models.NamespaceSpec{namespaceSpec}, false,
+			mock2.Anything).Return(models.TemplateMigration{
+			Jobs:    2,
+			Invalid: map[string]string{"b-job": "failed to load in staging scheduler: SyntaxError"},
+		}, nil)
+
+		rec := httptest.NewRecorder()
+		v1.NewTemplateMigrationHandler(jobService, operations, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPost, "/admin/template-migration?project=a-data-project", nil))
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "1 of 2 jobs are invalid with the new template, deployed jobs are left as they are",
+			operations.Err.Error())
+		assert.Equal(t, map[string]string{"b-job": "failed to load in staging scheduler: SyntaxError"},
+			operations.Result.(v1.TemplateMigrationResponse).Invalid)
+	})
+	t.Run("should reject projects without a template staging scheduler", func(t *testing.T) {
+		projSpec := models.ProjectSpec{ID: projectSpec.ID, Name: projectSpec.Name}
+		projectRepoFactory, namespaceRepoFactory := setup(projSpec)
+		operations := new(mock.OperationRunner)
+
+		rec := httptest.NewRecorder()
+		v1.NewTemplateMigrationHandler(new(mock.JobService), operations, projectRepoFactory, namespaceRepoFactory).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPost, "/admin/template-migration?project=a-data-project&swap=true", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		operations.AssertNotCalled(t, "Start")
+	})
+	t.Run("should respond not found for unknown project", func(t *testing.T) {
+		projectRepository := new(mock.ProjectRepository)
+		projectRepository.On("GetByName", "unknown").Return(models.ProjectSpec{}, store.ErrResourceNotFound)
+		projectRepoFactory := new(mock.ProjectRepoFactory)
+		projectRepoFactory.On("New").Return(projectRepository)
+
+		rec := httptest.NewRecorder()
+		v1.NewTemplateMigrationHandler(new(mock.JobService), new(mock.OperationRunner), projectRepoFactory,
+			new(mock.NamespaceRepoFactory)).ServeHTTP(rec,
+			httptest.NewRequest(http.MethodPost, "/admin/template-migration?project=unknown", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
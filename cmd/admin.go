@@ -12,7 +12,16 @@ func adminCommand(l logger, pluginRepo models.PluginRepository) *cli.Command {
 		Short: "administration commands, should not be used by user",
 	}
 	cmd.AddCommand(adminBuildCommand(l))
+	cmd.AddCommand(adminExecCommand(l))
 	cmd.AddCommand(adminGetCommand(l, pluginRepo))
+	cmd.AddCommand(adminAuditCommand(l))
+	cmd.AddCommand(adminQuotaCommand(l))
+	cmd.AddCommand(adminFreezeCommand(l))
+	cmd.AddCommand(adminTokenCommand(l))
+	cmd.AddCommand(adminPromoteCommand(l))
+	cmd.AddCommand(adminMigrateTemplateCommand(l))
+	cmd.AddCommand(adminArchiveCommand(l))
+	cmd.AddCommand(adminStaleJobsCommand(l))
 	return cmd
 }
 
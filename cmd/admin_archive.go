@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+func adminArchiveCommand(l logger) *cli.Command {
+	var (
+		optimusHost string
+		projectName string
+		reason      string
+		retainDays  int
+		restore     bool
+		detach      bool
+	)
+	cmd := &cli.Command{
+		Use:   "archive",
+		Short: "Decommission a project without losing its jobs right away",
+		Long: "Decommission a project without losing its jobs right away.\n" +
+			"Archiving freezes the project, snapshots its job specs, pauses its jobs and removes them from scheduler.\n" +
+			"Jobs are purged once retained for retain-days, server default is used if it is not set and 0 retains\n" +
+			"them till the project is restored. Restoring redeploys the jobs and resumes them.\n" +
+			"Archive and restore run on optimus service and are followed till they are over unless detached.",
+		Example: "optimus admin archive --host localhost:9100 --project \"project-id\"\n" +
+			"optimus admin archive --host localhost:9100 --project \"project-id\" --reason \"team moved to project-x\" --retain-days 60\n" +
+			"optimus admin archive --host localhost:9100 --project \"project-id\" --restore",
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&reason, "reason", "", "why the project is archived")
+	cmd.Flags().IntVar(&retainDays, "retain-days", -1, "days to retain jobs before they are purged, 0 retains them till restored")
+	cmd.Flags().BoolVar(&restore, "restore", false, "restore the archived project")
+	cmd.Flags().BoolVar(&detach, "detach", false, "return once archive or restore is started without following it")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", projectName)
+
+		var op v1handler.OperationResponse
+		switch {
+		case restore:
+			if err := operationRequest(optimusHost, http.MethodDelete, "/admin/archive", params, nil, &op); err != nil {
+				return err
+			}
+			l.Printf("restoring project %s, operation id: %s\n", projectName, op.ID)
+		case reason != "":
+			req := v1handler.ProjectArchiveRequest{
+				Reason: reason,
+				Actor:  auditActor(),
+			}
+			if retainDays >= 0 {
+				req.RetainDays = &retainDays
+			}
+			body, err := json.Marshal(req)
+			if err != nil {
+				return err
+			}
+			if err := operationRequest(optimusHost, http.MethodPut, "/admin/archive", params, bytes.NewReader(body), &op); err != nil {
+				return err
+			}
+			l.Printf("archiving project %s, operation id: %s\n", projectName, op.ID)
+		case c.Flags().Changed("retain-days"):
+			return errors.New("reason is required to archive a project")
+		default:
+			var resp v1handler.ProjectArchiveResponse
+			if err := operationRequest(optimusHost, http.MethodGet, "/admin/archive", params, nil, &resp); err != nil {
+				return err
+			}
+			printProjectArchive(l, resp)
+			return nil
+		}
+		if detach {
+			return nil
+		}
+		op, err := followOperation(l, optimusHost, projectName, op)
+		if err != nil {
+			return err
+		}
+
+		if len(op.Result) > 0 {
+			var result v1handler.ProjectArchiveResult
+			if err := json.Unmarshal(op.Result, &result); err != nil {
+				return errors.Wrap(err, "failed to decode archive result")
+			}
+			printProjectArchiveResult(l, result, restore)
+		}
+		return printOperationOutcome(l, op)
+	}
+	return cmd
+}
+
+func printProjectArchive(l logger, archive v1handler.ProjectArchiveResponse) {
+	if !archive.Archived {
+		l.Println(coloredSuccess(fmt.Sprintf("project %s is not archived", archive.ProjectName)))
+		return
+	}
+	l.Println(coloredNotice(fmt.Sprintf("project %s is archived", archive.ProjectName)))
+	l.Printf("reason: %s\n", archive.Reason)
+	if archive.Actor != "" {
+		l.Printf("by: %s\n", archive.Actor)
+	}
+	if archive.CreatedAt != nil {
+		l.Printf("since: %s\n", archive.CreatedAt.Format(time.RFC3339))
+	}
+	switch {
+	case archive.PurgedAt != nil:
+		l.Println(coloredError(fmt.Sprintf("purged at: %s", archive.PurgedAt.Format(time.RFC3339))))
+	case archive.PurgeAt != nil:
+		l.Printf("purge at: %s\n", archive.PurgeAt.Format(time.RFC3339))
+	default:
+		l.Println("retained till restored")
+	}
+}
+
+func printProjectArchiveResult(l logger, result v1handler.ProjectArchiveResult, restore bool) {
+	action, failedAction := "archived", "pause"
+	if restore {
+		action, failedAction = "restored", "resume"
+	}
+	l.Printf("%d jobs of %d namespaces %s\n", result.Jobs, result.Namespaces, action)
+	if len(result.Failed) == 0 {
+		return
+	}
+
+	l.Println(coloredError(fmt.Sprintf("failed to %s %d jobs", failedAction, len(result.Failed))))
+	var names []string
+	for name := range result.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	table := tablewriter.NewWriter(l.Writer())
+	table.SetBorder(false)
+	table.SetHeader([]string{
+		"Job",
+		"Reason",
+	})
+	for _, name := range names {
+		table.Append([]string{name, result.Failed[name]})
+	}
+	table.Render()
+}
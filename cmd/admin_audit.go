@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	adminAuditTimeout = time.Second * 10
+)
+
+func adminAuditCommand(l logger) *cli.Command {
+	var (
+		optimusHost string
+		projectName string
+		actor       string
+		rpc         string
+		outcome     string
+		since       time.Duration
+		limit       int
+	)
+	cmd := &cli.Command{
+		Use:   "audit",
+		Short: "List audit log of mutating api calls",
+		Example: "optimus admin audit --host localhost:9100 --project \"project-id\" --since 24h\n" +
+			"optimus admin audit --host localhost:9100 --actor alice --rpc DeployJobSpecification",
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringVar(&projectName, "project", "", "filter by name of the tenant")
+	cmd.Flags().StringVar(&actor, "actor", "", "filter by user who made the call")
+	cmd.Flags().StringVar(&rpc, "rpc", "", "filter by api method, e.g. RegisterSecret")
+	cmd.Flags().StringVar(&outcome, "outcome", "", "filter by outcome, success or failure")
+	cmd.Flags().DurationVar(&since, "since", 0, "only show calls made within this duration, e.g. 24h")
+	cmd.Flags().IntVar(&limit, "limit", 100, "max number of latest entries to show")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(limit))
+		for key, val := range map[string]string{
+			"project": projectName,
+			"actor":   actor,
+			"rpc":     rpc,
+			"outcome": outcome,
+		} {
+			if val != "" {
+				query.Set(key, val)
+			}
+		}
+		if since > 0 {
+			query.Set("since", time.Now().Add(-since).UTC().Format(time.RFC3339))
+		}
+
+		entries, err := getAuditLogRequest(optimusHost, query)
+		if err != nil {
+			return err
+		}
+		printAuditLog(l, entries)
+		return nil
+	}
+	return cmd
+}
+
+func getAuditLogRequest(host string, query url.Values) ([]v1handler.AuditLogResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(adminAuditTimeout))
+	defer cancel()
+
+	reqURL := fmt.Sprintf("%s://%s/admin/audit?%s", httpScheme(), host, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch audit log")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch audit log, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var entries []v1handler.AuditLogResponse
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, errors.Wrap(err, "failed to decode audit log")
+	}
+	return entries, nil
+}
+
+func printAuditLog(l logger, entries []v1handler.AuditLogResponse) {
+	table := tablewriter.NewWriter(l.Writer())
+	table.SetBorder(false)
+	table.SetHeader([]string{
+		"Time",
+		"Actor",
+		"RPC",
+		"Project",
+		"Outcome",
+		"Message",
+	})
+	for _, entry := range entries {
+		table.Append([]string{
+			entry.CreatedAt.Format(time.RFC3339),
+			entry.Actor,
+			entry.RPC,
+			entry.ProjectName,
+			entry.Outcome,
+			entry.Message,
+		})
+	}
+	table.Render()
+}
@@ -61,21 +61,20 @@ func adminBuildInstanceCommand(l logger) *cli.Command {
 		// append base path to input file directory
 		inputDirectory := filepath.Join(assetOutputDir, taskInputDirectory)
 
-		if err := getInstanceBuildRequest(l, jobName, inputDirectory, optimusHost, projectName, scheduledAt, runType, runName); err != nil {
-			return err
-		}
-		return nil
+		_, err := getInstanceBuildRequest(l, jobName, inputDirectory, optimusHost, projectName, scheduledAt, runType, runName)
+		return err
 	}
 	return cmd
 }
 
 // getInstanceBuildRequest fetches a JobRun from the store (eg, postgres)
 // Based on the response, it builds assets like query, env and config
-// for the Job Run which is saved into output files.
-func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectName, scheduledAt, runType, runName string) (err error) {
+// for the Job Run which is saved into output files. The env of the
+// Job Run is returned as well
+func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectName, scheduledAt, runType, runName string) (envs map[string]string, err error) {
 	jobScheduledTime, err := time.Parse(models.InstanceScheduledAtTimeLayout, scheduledAt)
 	if err != nil {
-		return errors.Wrapf(err, "invalid time format, please use %s", models.InstanceScheduledAtTimeLayout)
+		return nil, errors.Wrapf(err, "invalid time format, please use %s", models.InstanceScheduledAtTimeLayout)
 	}
 	jobScheduledTimeProto := timestamppb.New(jobScheduledTime)
 
@@ -87,11 +86,11 @@ func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectNam
 		if errors.Is(err, context.DeadlineExceeded) {
 			l.Println("can't reach optimus service, timing out")
 		}
-		return err
+		return nil, err
 	}
 	defer conn.Close()
 
-	timeoutCtx, cancel := context.WithTimeout(context.Background(), adminBuildInstanceTimeout)
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), callTimeout(adminBuildInstanceTimeout))
 	defer cancel()
 
 	// fetch Instance by calling the optimus API
@@ -104,12 +103,12 @@ func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectNam
 		InstanceName: runName,
 	})
 	if err != nil {
-		return errors.Wrapf(err, "request failed for job %s", jobName)
+		return nil, errors.Wrapf(err, "request failed for job %s", jobName)
 	}
 
 	// make sure output dir exists
 	if err := os.MkdirAll(inputDirectory, 0777); err != nil {
-		return errors.Wrapf(err, "failed to create directory at %s", inputDirectory)
+		return nil, errors.Wrapf(err, "failed to create directory at %s", inputDirectory)
 	}
 	writeToFileFn := utils.WriteStringToFileIndexed()
 
@@ -117,7 +116,7 @@ func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectNam
 	for fileName, fileContent := range jobResponse.Context.Files {
 		filePath := filepath.Join(inputDirectory, fileName)
 		if err := writeToFileFn(filePath, fileContent, l.Writer()); err != nil {
-			return errors.Wrapf(err, "failed to write asset file at %s", filePath)
+			return nil, errors.Wrapf(err, "failed to write asset file at %s", filePath)
 		}
 	}
 
@@ -128,8 +127,8 @@ func getInstanceBuildRequest(l logger, jobName, inputDirectory, host, projectNam
 	}
 	filePath := filepath.Join(inputDirectory, models.InstanceDataTypeEnvFileName)
 	if err := writeToFileFn(filePath, envFileBlob, l.Writer()); err != nil {
-		return errors.Wrapf(err, "failed to write asset file at %s", filePath)
+		return nil, errors.Wrapf(err, "failed to write asset file at %s", filePath)
 	}
 
-	return nil
+	return jobResponse.Context.Envs, nil
 }
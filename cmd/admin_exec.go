@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	adminExecHeartbeatTimeout = time.Second * 10
+)
+
+// adminExecCommand runs the unit of a task or hook container on behalf of the
+// executor. It registers the instance, fetches its assets and env, runs the
+// unit with them streaming its logs, and reports heartbeats while the unit
+// runs and its terminal state once it exits, so the server can tell hung runs
+// apart regardless of the executor
+func adminExecCommand(l logger) *cli.Command {
+	var (
+		optimusHost       string
+		projectName       string
+		assetOutputDir    string
+		scheduledAt       string
+		runType           string
+		runName           string
+		heartbeatInterval time.Duration
+	)
+	cmd := &cli.Command{
+		Use:   "exec",
+		Short: "Run the unit of a job instance reporting heartbeats and its state",
+		Example: "optimus admin exec sample_replace --project \"project-id\" --output-dir /data --type task --name bq2bq " +
+			"--scheduled-at 2021-05-13T02:00:00Z --host localhost:9100 -- python3 /opt/main.py",
+		Args: cli.MinimumNArgs(2),
+	}
+
+	cmd.Flags().StringVar(&assetOutputDir, "output-dir", "", "output directory for assets")
+	cmd.MarkFlagRequired("output-dir")
+	cmd.Flags().StringVar(&scheduledAt, "scheduled-at", "", "time at which the job was scheduled for execution")
+	cmd.MarkFlagRequired("scheduled-at")
+	cmd.Flags().StringVar(&runType, "type", "", "type of instance, could be task/hook")
+	cmd.MarkFlagRequired("type")
+	cmd.Flags().StringVar(&runName, "name", "", "name of instance, could be bq2bq/transporter/predator")
+	cmd.MarkFlagRequired("name")
+	cmd.Flags().DurationVar(&heartbeatInterval, "heartbeat-interval", models.InstanceHeartbeatInterval,
+		"how often to report the instance is alive")
+
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		jobName := args[0]
+		instanceType, err := models.InstanceType("").New(strings.ToLower(runType))
+		if err != nil {
+			return err
+		}
+		runScheduledAt, err := time.Parse(models.InstanceScheduledAtTimeLayout, scheduledAt)
+		if err != nil {
+			return errors.Wrapf(err, "invalid time format, please use %s", models.InstanceScheduledAtTimeLayout)
+		}
+		if heartbeatInterval <= 0 {
+			return errors.New("heartbeat interval should be positive")
+		}
+
+		l.Println("-- initializing optimus assets")
+		inputDirectory := filepath.Join(assetOutputDir, taskInputDirectory)
+		envs, err := getInstanceBuildRequest(l, jobName, inputDirectory, optimusHost, projectName, scheduledAt, runType, runName)
+		if err != nil {
+			return err
+		}
+
+		heartbeat := v1handler.InstanceHeartbeatRequest{
+			ScheduledAt:  runScheduledAt,
+			InstanceType: instanceType.String(),
+			InstanceName: runName,
+			State:        models.InstanceStateRunning,
+		}
+		report := func(state string) {
+			heartbeat.State = state
+			if err := sendInstanceHeartbeat(optimusHost, projectName, jobName, heartbeat); err != nil {
+				// heartbeats are best effort, failing to report doesn't fail the unit
+				l.Printf("failed to report %s state of instance: %s\n", state, err)
+			}
+		}
+
+		l.Println("-- running unit")
+		unit, err := buildInstanceUnit(args[1:], envs)
+		if err != nil {
+			return err
+		}
+		if err := unit.Start(); err != nil {
+			report(models.InstanceStateFailed)
+			return errors.Wrap(err, "failed to start unit")
+		}
+		report(models.InstanceStateRunning)
+
+		// containers are stopped with a signal, let the unit handle it
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(signals)
+
+		exited := make(chan error, 1)
+		go func() {
+			exited <- unit.Wait()
+		}()
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case sig := <-signals:
+				unit.Process.Signal(sig)
+			case <-ticker.C:
+				report(models.InstanceStateRunning)
+			case err := <-exited:
+				if err != nil {
+					report(models.InstanceStateFailed)
+					return errors.Wrapf(err, "%s %s of job %s failed", instanceType, runName, jobName)
+				}
+				report(models.InstanceStateSuccess)
+				return nil
+			}
+		}
+	}
+	return cmd
+}
+
+// buildInstanceUnit prepares the command of the unit with env of the instance
+// on top of the env of the container. Like the shell entrypoint did with
+// eval echo, env in args is expanded and args are split on whitespace, so
+// the command can be passed as a single arg
+func buildInstanceUnit(args []string, envs map[string]string) (*exec.Cmd, error) {
+	lookup := func(key string) string {
+		if val, ok := envs[key]; ok {
+			return val
+		}
+		return os.Getenv(key)
+	}
+	var command []string
+	for _, arg := range args {
+		command = append(command, strings.Fields(os.Expand(arg, lookup))...)
+	}
+	if len(command) == 0 {
+		return nil, errors.New("unit to run is empty")
+	}
+
+	unit := exec.Command(command[0], command[1:]...)
+	unit.Env = os.Environ()
+	for key, val := range envs {
+		unit.Env = append(unit.Env, fmt.Sprintf("%s=%s", key, val))
+	}
+	// logs of the unit are streamed as is for the executor to collect
+	unit.Stdin = os.Stdin
+	unit.Stdout = os.Stdout
+	unit.Stderr = os.Stderr
+	return unit, nil
+}
+
+func sendInstanceHeartbeat(host, projectName, jobName string, heartbeat v1handler.InstanceHeartbeatRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(adminExecHeartbeatTimeout))
+	defer cancel()
+
+	payload, err := json.Marshal(heartbeat)
+	if err != nil {
+		return err
+	}
+	params := url.Values{}
+	params.Set("project", projectName)
+	params.Set("job", jobName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s://%s/instance-heartbeats?%s", httpScheme(), host, params.Encode()), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	adminFreezeTimeout = time.Second * 10
+)
+
+func adminFreezeCommand(l logger) *cli.Command {
+	var (
+		optimusHost string
+		projectName string
+		reason      string
+		duration    time.Duration
+		lift        bool
+	)
+	cmd := &cli.Command{
+		Use:   "freeze",
+		Short: "Block deploys and replays of a project, e.g. during an incident",
+		Example: "optimus admin freeze --host localhost:9100 --project \"project-id\"\n" +
+			"optimus admin freeze --host localhost:9100 --project \"project-id\" --reason \"incident INC-42\" --for 2h\n" +
+			"optimus admin freeze --host localhost:9100 --project \"project-id\" --lift",
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&reason, "reason", "", "why the project is frozen, shown when a change is rejected")
+	cmd.Flags().DurationVar(&duration, "for", 0, "lift the freeze automatically after this duration, e.g. 2h")
+	cmd.Flags().BoolVar(&lift, "lift", false, "lift the freeze")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		var (
+			resp v1handler.FreezeResponse
+			err  error
+		)
+		switch {
+		case lift:
+			resp, err = freezeRequest(optimusHost, projectName, http.MethodDelete, nil)
+		case reason != "":
+			req := v1handler.FreezeRequest{
+				Reason: reason,
+			}
+			if duration > 0 {
+				expiresAt := time.Now().Add(duration).UTC()
+				req.ExpiresAt = &expiresAt
+			}
+			body, err := json.Marshal(req)
+			if err != nil {
+				return err
+			}
+			resp, err = freezeRequest(optimusHost, projectName, http.MethodPut, bytes.NewReader(body))
+			if err != nil {
+				return err
+			}
+		case duration > 0:
+			return errors.New("reason is required to freeze a project")
+		default:
+			resp, err = freezeRequest(optimusHost, projectName, http.MethodGet, nil)
+		}
+		if err != nil {
+			return err
+		}
+		printFreeze(l, resp)
+		return nil
+	}
+	return cmd
+}
+
+func freezeRequest(host, projectName, method string, body io.Reader) (v1handler.FreezeResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(adminFreezeTimeout))
+	defer cancel()
+
+	freeze := v1handler.FreezeResponse{}
+	reqURL := fmt.Sprintf("%s://%s/admin/freeze?project=%s", httpScheme(), host, url.QueryEscape(projectName))
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return freeze, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return freeze, errors.Wrap(err, "failed to request freeze")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return freeze, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return freeze, errors.Errorf("failed to request freeze, status: %d, response: %s", resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, &freeze); err != nil {
+		return freeze, errors.Wrap(err, "failed to decode freeze")
+	}
+	return freeze, nil
+}
+
+func printFreeze(l logger, freeze v1handler.FreezeResponse) {
+	if !freeze.Frozen {
+		l.Println(coloredSuccess(fmt.Sprintf("project %s is not frozen", freeze.ProjectName)))
+		return
+	}
+	l.Println(coloredError(fmt.Sprintf("project %s is frozen", freeze.ProjectName)))
+	l.Printf("reason: %s\n", freeze.Reason)
+	if freeze.Actor != "" {
+		l.Printf("by: %s\n", freeze.Actor)
+	}
+	if freeze.CreatedAt != nil {
+		l.Printf("since: %s\n", freeze.CreatedAt.Format(time.RFC3339))
+	}
+	if freeze.ExpiresAt != nil {
+		l.Printf("until: %s\n", freeze.ExpiresAt.Format(time.RFC3339))
+	}
+}
+
+// printFreezeReason tells user why the call was rejected if the project is frozen
+func printFreezeReason(l logger, err error) {
+	st, ok := status.FromError(errors.Cause(err))
+	if !ok {
+		return
+	}
+	for _, detail := range st.Details() {
+		failure, ok := detail.(*errdetails.PreconditionFailure)
+		if !ok {
+			continue
+		}
+		for _, violation := range failure.GetViolations() {
+			if violation.GetType() == v1handler.FreezeViolationType {
+				l.Println(coloredError(fmt.Sprintf("project %s is frozen, changes are not allowed: %s",
+					violation.GetSubject(), violation.GetDescription())))
+			}
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	cli "github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
@@ -59,20 +60,29 @@ func getJobStatusRequest(l logger, jobName, host, projectName string) error {
 	}
 	defer conn.Close()
 
-	timeoutCtx, cancel := context.WithTimeout(context.Background(), adminStatusTimeout)
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), callTimeout(adminStatusTimeout))
 	defer cancel()
 
 	runtime := pb.NewRuntimeServiceClient(conn)
-	jobStatusResponse, err := runtime.JobStatus(timeoutCtx, &pb.JobStatusRequest{
-		ProjectName: projectName,
-		JobName:     jobName,
-	})
-	if err != nil {
-		return errors.Wrapf(err, "request failed for job %s", jobName)
+	var (
+		jobStatuses []*pb.JobStatus
+		pageToken   string
+	)
+	for {
+		var header metadata.MD
+		jobStatusResponse, err := runtime.JobStatus(withPage(timeoutCtx, pageToken), &pb.JobStatusRequest{
+			ProjectName: projectName,
+			JobName:     jobName,
+		}, grpc.Header(&header))
+		if err != nil {
+			return errors.Wrapf(err, "request failed for job %s", jobName)
+		}
+		jobStatuses = append(jobStatuses, jobStatusResponse.GetStatuses()...)
+		if pageToken = nextPageToken(header); pageToken == "" {
+			break
+		}
 	}
 
-	jobStatuses := jobStatusResponse.GetStatuses()
-
 	sort.Slice(jobStatuses, func(i, j int) bool {
 		return jobStatuses[i].ScheduledAt.Seconds < jobStatuses[j].ScheduledAt.Seconds
 	})
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+func adminMigrateTemplateCommand(l logger) *cli.Command {
+	var (
+		optimusHost string
+		projectName string
+		swap        bool
+		detach      bool
+	)
+	cmd := &cli.Command{
+		Use:   "migrate-template",
+		Short: "Move jobs of a project to the scheduler template of optimus service",
+		Long: "Move jobs of a project to the scheduler template of optimus service.\n" +
+			"All jobs are recompiled into the template staging scheduler configured with TEMPLATE_STAGING_STORAGE_PATH\n" +
+			"and TEMPLATE_STAGING_SCHEDULER_HOST of the project, and are valid once it loads them. With swap, deployed\n" +
+			"jobs are replaced with them only if all of them are valid, otherwise the jobs are only validated.\n" +
+			"Migration runs on optimus service and is followed till it is over unless detached.",
+		Example: "optimus admin migrate-template --host localhost:9100 --project \"project-id\" --swap",
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().BoolVar(&swap, "swap", false, "replace deployed jobs once all of them are valid")
+	cmd.Flags().BoolVar(&detach, "detach", false, "return once migration is started without following it")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("swap", strconv.FormatBool(swap))
+		var op v1handler.OperationResponse
+		if err := operationRequest(optimusHost, http.MethodPost, "/admin/template-migration", params, nil, &op); err != nil {
+			return err
+		}
+		l.Printf("migrating jobs of project %s, operation id: %s\n", projectName, op.ID)
+		if detach {
+			return nil
+		}
+		op, err := followOperation(l, optimusHost, projectName, op)
+		if err != nil {
+			return err
+		}
+
+		if len(op.Result) > 0 {
+			var result v1handler.TemplateMigrationResponse
+			if err := json.Unmarshal(op.Result, &result); err != nil {
+				return errors.Wrap(err, "failed to decode migration result")
+			}
+			printTemplateMigration(l, result)
+		}
+		return printOperationOutcome(l, op)
+	}
+	return cmd
+}
+
+func printTemplateMigration(l logger, result v1handler.TemplateMigrationResponse) {
+	l.Printf("%d jobs recompiled, %d invalid\n", result.Jobs, len(result.Invalid))
+	if result.Swapped {
+		l.Printf("deployed jobs replaced, %d of them changed\n", result.Uploaded)
+	}
+	if len(result.Invalid) == 0 {
+		return
+	}
+
+	var names []string
+	for name := range result.Invalid {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	table := tablewriter.NewWriter(l.Writer())
+	table.SetBorder(false)
+	table.SetHeader([]string{
+		"Job",
+		"Reason",
+	})
+	for _, name := range names {
+		table.Append([]string{name, result.Invalid[name]})
+	}
+	table.Render()
+}
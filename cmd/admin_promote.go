@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func adminPromoteCommand(l logger) *cli.Command {
+	var (
+		optimusHost  string
+		projectName  string
+		manifestPath string
+		detach       bool
+	)
+	cmd := &cli.Command{
+		Use:   "promote",
+		Short: "Clone datasets of a project into another environment as listed in a manifest",
+		Long: "Clone datasets of a project into another environment as listed in a manifest.\n" +
+			"Tables missing in destination are created with the schema of source tables, with their data too\n" +
+			"if with_data is set, and views are created reading from destination datasets instead of source ones.\n" +
+			"Promotion runs on optimus service and is followed till it is over unless detached.",
+		Example: "optimus admin promote --host localhost:9100 --project \"project-id\" --manifest promotion.yaml",
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&manifestPath, "manifest", "", "path of yaml manifest listing datasets to promote")
+	cmd.MarkFlagRequired("manifest")
+	cmd.Flags().BoolVar(&detach, "detach", false, "return once promotion is started without following it")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		raw, err := ioutil.ReadFile(manifestPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to read manifest")
+		}
+		var manifest models.PromotionManifest
+		if err := yaml.Unmarshal(raw, &manifest); err != nil {
+			return errors.Wrap(err, "failed to parse manifest")
+		}
+		if err := manifest.Validate(); err != nil {
+			return err
+		}
+
+		body, err := json.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		params := url.Values{}
+		params.Set("project", projectName)
+		var op v1handler.OperationResponse
+		if err := operationRequest(optimusHost, http.MethodPost, "/admin/promote", params, bytes.NewReader(body), &op); err != nil {
+			return err
+		}
+		l.Printf("promoting %d datasets, operation id: %s\n", len(manifest.Datasets), op.ID)
+		if detach {
+			return nil
+		}
+		if op, err = followOperation(l, optimusHost, projectName, op); err != nil {
+			return err
+		}
+
+		var results []v1handler.PromotionResultResponse
+		if len(op.Result) > 0 {
+			if err := json.Unmarshal(op.Result, &results); err != nil {
+				return errors.Wrap(err, "failed to decode promotion results")
+			}
+			printPromotionResults(l, results)
+		}
+		if err := printOperationOutcome(l, op); err != nil {
+			return err
+		}
+		if len(results) < len(manifest.Datasets) {
+			return errors.New("promotion stopped before all datasets were promoted")
+		}
+		return nil
+	}
+	return cmd
+}
+
+func printPromotionResults(l logger, results []v1handler.PromotionResultResponse) {
+	table := tablewriter.NewWriter(l.Writer())
+	table.SetBorder(false)
+	table.SetHeader([]string{
+		"Source",
+		"Destination",
+		"Copied",
+		"Skipped",
+		"Error",
+	})
+	for _, result := range results {
+		table.Append([]string{
+			result.Source,
+			result.Destination,
+			strings.Join(result.Copied, ", "),
+			strings.Join(result.Skipped, ", "),
+			result.Error,
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	adminQuotaTimeout = time.Second * 10
+)
+
+func adminQuotaCommand(l logger) *cli.Command {
+	var (
+		optimusHost string
+		projectName string
+		quota       v1handler.QuotaResponse
+	)
+	cmd := &cli.Command{
+		Use:   "quota",
+		Short: "View or adjust quota of a project, zero means unlimited",
+		Example: "optimus admin quota --host localhost:9100 --project \"project-id\"\n" +
+			"optimus admin quota --host localhost:9100 --project \"project-id\" --max-jobs 500 --max-replay-runs-per-day 1000",
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().IntVar(&quota.MaxJobs, "max-jobs", 0, "max jobs across all namespaces")
+	cmd.Flags().IntVar(&quota.MaxResources, "max-resources", 0, "max resources of a datastore across all namespaces")
+	cmd.Flags().IntVar(&quota.MaxReplayRunsPerDay, "max-replay-runs-per-day", 0, "max job runs replayed in a day")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		current, err := quotaRequest(optimusHost, projectName, http.MethodGet, nil)
+		if err != nil {
+			return err
+		}
+
+		// only the limits passed as flags are changed
+		flags := c.Flags()
+		if flags.Changed("max-jobs") || flags.Changed("max-resources") || flags.Changed("max-replay-runs-per-day") {
+			if !flags.Changed("max-jobs") {
+				quota.MaxJobs = current.MaxJobs
+			}
+			if !flags.Changed("max-resources") {
+				quota.MaxResources = current.MaxResources
+			}
+			if !flags.Changed("max-replay-runs-per-day") {
+				quota.MaxReplayRunsPerDay = current.MaxReplayRunsPerDay
+			}
+			body, err := json.Marshal(quota)
+			if err != nil {
+				return err
+			}
+			if current, err = quotaRequest(optimusHost, projectName, http.MethodPut, bytes.NewReader(body)); err != nil {
+				return err
+			}
+			l.Println(coloredSuccess("quota updated"))
+		}
+		printQuota(l, current)
+		return nil
+	}
+	return cmd
+}
+
+func quotaRequest(host, projectName, method string, body io.Reader) (v1handler.QuotaResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(adminQuotaTimeout))
+	defer cancel()
+
+	quota := v1handler.QuotaResponse{}
+	reqURL := fmt.Sprintf("%s://%s/admin/quota?project=%s", httpScheme(), host, url.QueryEscape(projectName))
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return quota, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return quota, errors.Wrap(err, "failed to request quota")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return quota, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return quota, errors.Errorf("failed to request quota, status: %d, response: %s", resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, &quota); err != nil {
+		return quota, errors.Wrap(err, "failed to decode quota")
+	}
+	return quota, nil
+}
+
+func printQuota(l logger, quota v1handler.QuotaResponse) {
+	limit := func(val int) string {
+		if val == 0 {
+			return "unlimited"
+		}
+		return strconv.Itoa(val)
+	}
+	table := tablewriter.NewWriter(l.Writer())
+	table.SetBorder(false)
+	table.SetHeader([]string{
+		"Project",
+		"Max jobs",
+		"Max resources",
+		"Max replay runs per day",
+	})
+	table.Append([]string{
+		quota.ProjectName,
+		limit(quota.MaxJobs),
+		limit(quota.MaxResources),
+		limit(quota.MaxReplayRunsPerDay),
+	})
+	table.Render()
+}
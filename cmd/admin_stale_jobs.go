@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	adminStaleJobsTimeout = time.Second * 10
+)
+
+// adminStaleJobsCommand prints the cleanup report of a project, jobs without
+// successful runs or reads of their destination
+func adminStaleJobsCommand(l logger) *cli.Command {
+	var (
+		optimusHost string
+		projectName string
+	)
+	cmd := &cli.Command{
+		Use:     "stale-jobs",
+		Short:   "List jobs of a project which look unused",
+		Example: "optimus admin stale-jobs --host localhost:9100 --project \"project-id\"",
+		Args:    cli.NoArgs,
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		report, err := getStaleJobs(optimusHost, projectName)
+		if err != nil {
+			return err
+		}
+		if report.AnalyzedAt.IsZero() {
+			l.Println(coloredNotice("jobs are not analyzed yet"))
+			return nil
+		}
+		if len(report.Jobs) == 0 {
+			l.Println(coloredSuccess(fmt.Sprintf("no stale jobs as of %s", report.AnalyzedAt.Format(time.RFC3339))))
+			return nil
+		}
+		l.Println(coloredNotice(fmt.Sprintf("%d stale jobs as of %s", len(report.Jobs), report.AnalyzedAt.Format(time.RFC3339))))
+		table := tablewriter.NewWriter(l.Writer())
+		table.SetBorder(false)
+		table.SetHeader([]string{"Namespace", "Job", "Owner", "Destination", "Reasons", "Runs", "Last read"})
+		for _, job := range report.Jobs {
+			lastRead := "-"
+			if job.LastReadAt != nil {
+				lastRead = job.LastReadAt.Format(time.RFC3339)
+			}
+			table.Append([]string{job.Namespace, job.JobName, job.Owner, job.Destination,
+				strings.Join(job.Reasons, ","), strconv.Itoa(job.Runs), lastRead})
+		}
+		table.Render()
+		return nil
+	}
+	return cmd
+}
+
+func getStaleJobs(host, projectName string) (v1handler.StaleJobsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(adminStaleJobsTimeout))
+	defer cancel()
+
+	reqURL := fmt.Sprintf("%s://%s/admin/stale-jobs?project=%s", httpScheme(), host, url.QueryEscape(projectName))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return v1handler.StaleJobsResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.StaleJobsResponse{}, errors.Wrap(err, "failed to request stale jobs")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.StaleJobsResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.StaleJobsResponse{}, errors.Errorf("failed to request stale jobs, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var report v1handler.StaleJobsResponse
+	if err := json.Unmarshal(body, &report); err != nil {
+		return v1handler.StaleJobsResponse{}, errors.Wrap(err, "failed to decode stale jobs")
+	}
+	return report, nil
+}
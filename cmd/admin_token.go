@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	adminTokenTimeout = time.Second * 10
+)
+
+func adminTokenCommand(l logger) *cli.Command {
+	cmd := &cli.Command{
+		Use:   "token",
+		Short: "Manage api tokens used by automation, e.g. CI, in place of personal credentials",
+	}
+	cmd.AddCommand(adminTokenCreateCommand(l))
+	cmd.AddCommand(adminTokenListCommand(l))
+	cmd.AddCommand(adminTokenRevokeCommand(l))
+	return cmd
+}
+
+func adminTokenCreateCommand(l logger) *cli.Command {
+	var (
+		optimusHost string
+		scopes      []string
+		expiresIn   time.Duration
+	)
+	cmd := &cli.Command{
+		Use:   "create",
+		Short: "Create an api token with scopes written as action:project",
+		Long: "Create an api token with scopes written as action:project, actions being read, deploy, replay\n" +
+			"and admin, and * matching all projects. The token is only printed once, store it safely",
+		Example: "optimus admin token create ci --host localhost:9100 --scope deploy:project-id --scope read:*\n" +
+			"optimus admin token create replayer --host localhost:9100 --scope replay:project-id --expires-in 720h",
+		Args: cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+	cmd.Flags().StringArrayVar(&scopes, "scope", nil, "scope of the token as action:project, can be repeated")
+	cmd.MarkFlagRequired("scope")
+	cmd.Flags().DurationVar(&expiresIn, "expires-in", 0, "expire the token after this duration, e.g. 720h")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		req := v1handler.APITokenRequest{
+			Name:   args[0],
+			Scopes: scopes,
+			Actor:  auditActor(),
+		}
+		if expiresIn > 0 {
+			expiresAt := time.Now().Add(expiresIn).UTC()
+			req.ExpiresAt = &expiresAt
+		}
+		body, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		var token v1handler.APITokenResponse
+		if err := apiTokenRequest(optimusHost, http.MethodPost, "", bytes.NewReader(body), &token); err != nil {
+			return err
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("created api token %s with id %s", token.Name, token.ID)))
+		l.Println("token, shown only once, set it as OPTIMUS_TOKEN env to use it:")
+		l.Println(token.Token)
+		return nil
+	}
+	return cmd
+}
+
+func adminTokenListCommand(l logger) *cli.Command {
+	var optimusHost string
+	cmd := &cli.Command{
+		Use:     "list",
+		Short:   "List api tokens, latest first",
+		Example: "optimus admin token list --host localhost:9100",
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		var tokens []v1handler.APITokenResponse
+		if err := apiTokenRequest(optimusHost, http.MethodGet, "", nil, &tokens); err != nil {
+			return err
+		}
+		if len(tokens) == 0 {
+			l.Println("no api tokens found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(l.Writer())
+		table.SetBorder(false)
+		table.SetHeader([]string{"id", "name", "scopes", "created by", "created at", "status"})
+		for _, token := range tokens {
+			tokenStatus := "active"
+			switch {
+			case token.RevokedAt != nil:
+				tokenStatus = "revoked at " + token.RevokedAt.Format(time.RFC3339)
+			case token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()):
+				tokenStatus = "expired at " + token.ExpiresAt.Format(time.RFC3339)
+			case token.ExpiresAt != nil:
+				tokenStatus = "expires at " + token.ExpiresAt.Format(time.RFC3339)
+			}
+			table.Append([]string{
+				token.ID,
+				token.Name,
+				strings.Join(token.Scopes, ", "),
+				token.Actor,
+				token.CreatedAt.Format(time.RFC3339),
+				tokenStatus,
+			})
+		}
+		table.Render()
+		return nil
+	}
+	return cmd
+}
+
+func adminTokenRevokeCommand(l logger) *cli.Command {
+	var optimusHost string
+	cmd := &cli.Command{
+		Use:     "revoke",
+		Short:   "Revoke an api token, calls made with it are rejected from then on",
+		Example: "optimus admin token revoke 8d7e3c6a-2f4b-4a7e-9a1d-3b5c9e0f1a2b --host localhost:9100",
+		Args:    cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&optimusHost, "host", "", "optimus service endpoint url")
+	cmd.MarkFlagRequired("host")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		if err := apiTokenRequest(optimusHost, http.MethodDelete, args[0], nil, nil); err != nil {
+			return err
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("revoked api token %s", args[0])))
+		return nil
+	}
+	return cmd
+}
+
+// apiTokenRequest calls api tokens admin endpoint decoding response in out
+// if it's not nil
+func apiTokenRequest(host, method, id string, body io.Reader, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(adminTokenTimeout))
+	defer cancel()
+
+	reqURL := fmt.Sprintf("%s://%s/admin/tokens", httpScheme(), host)
+	if id != "" {
+		reqURL += "?id=" + url.QueryEscape(id)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to request api tokens")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return errors.Errorf("failed to request api tokens, status: %d, response: %s", resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return errors.Wrap(err, "failed to decode api tokens")
+	}
+	return nil
+}
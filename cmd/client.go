@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+// listPageSize is the number of items requested per call when commands
+// page through list responses of server
+const listPageSize = 200
+
+// clientConf is how cli connects to optimus server, it is loaded
+// once the root command is constructed
+var clientConf config.ClientConfig
+
+// idempotentRPCs are the methods of runtime service safe to call again
+// when server was unavailable, deployments converge to the same specs
+// however many times they are sent
+var idempotentRPCs = map[string]bool{
+	"Version":                     true,
+	"ReadJobSpecification":        true,
+	"ListJobSpecification":        true,
+	"DumpJobSpecification":        true,
+	"CheckJobSpecification":       true,
+	"CheckJobSpecifications":      true,
+	"RegisterProject":             true,
+	"RegisterProjectNamespace":    true,
+	"ListProjects":                true,
+	"ListProjectNamespaces":       true,
+	"JobStatus":                   true,
+	"GetWindow":                   true,
+	"ListResourceSpecification":   true,
+	"ReadResource":                true,
+	"ReplayDryRun":                true,
+	"DeployJobSpecification":      true,
+	"DeployResourceSpecification": true,
+}
+
+// clientDialOptions are the options of connection to server as configured
+func clientDialOptions(conf config.ClientConfig) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if conf.TLS.Enabled {
+		tlsConf, err := clientTLSConfig(conf.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConf)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if conf.Keepalive.TimeSecs > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    conf.Keepalive.TimeSecs,
+			Timeout: conf.Keepalive.TimeoutSecs,
+		}))
+	}
+	if conf.Retry.MaxAttempts > 1 {
+		retryOpts := []grpc_retry.CallOption{
+			grpc_retry.WithMax(uint(conf.Retry.MaxAttempts)),
+			grpc_retry.WithBackoff(grpc_retry.BackoffExponentialWithJitter(conf.Retry.BackoffMillis, 0.1)),
+			grpc_retry.WithCodes(codes.Unavailable),
+		}
+		opts = append(opts,
+			grpc.WithChainUnaryInterceptor(retryUnaryClientInterceptor(retryOpts...)),
+			grpc.WithChainStreamInterceptor(retryStreamClientInterceptor(retryOpts...)),
+		)
+	}
+	return opts, nil
+}
+
+// retryUnaryClientInterceptor retries idempotent calls, others are
+// called once
+func retryUnaryClientInterceptor(opts ...grpc_retry.CallOption) grpc.UnaryClientInterceptor {
+	retry := grpc_retry.UnaryClientInterceptor(opts...)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if !idempotentRPCs[path.Base(method)] {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+		return retry(ctx, method, req, reply, cc, invoker, callOpts...)
+	}
+}
+
+// retryStreamClientInterceptor retries idempotent streaming calls till
+// the first message is received, streams failing later aren't retried
+func retryStreamClientInterceptor(opts ...grpc_retry.CallOption) grpc.StreamClientInterceptor {
+	retry := grpc_retry.StreamClientInterceptor(opts...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !idempotentRPCs[path.Base(method)] || desc.ClientStreams {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+		return retry(ctx, desc, cc, method, streamer, callOpts...)
+	}
+}
+
+// clientTLSConfig verifies server with configured certificate authority
+// and presents client certificate if configured
+func clientTLSConfig(conf config.ClientTLSConfig) (*tls.Config, error) {
+	tlsConf := &tls.Config{
+		ServerName: conf.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if conf.CAFile != "" {
+		caCert, err := ioutil.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read certificate authority")
+		}
+		tlsConf.RootCAs = x509.NewCertPool()
+		if !tlsConf.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("no certificates found in %s", conf.CAFile)
+		}
+	}
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		if conf.CertFile == "" || conf.KeyFile == "" {
+			return nil, errors.New("both cert_file and key_file are required for client certificate")
+		}
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConf, nil
+}
+
+// callTimeout is the configured timeout of calls to server, commands
+// fall back to their own timeout if it isn't configured
+func callTimeout(defaultTimeout time.Duration) time.Duration {
+	if clientConf.CallTimeoutSecs > 0 {
+		return clientConf.CallTimeoutSecs
+	}
+	return defaultTimeout
+}
+
+// httpScheme is the scheme of http api of server, https once tls is enabled
+func httpScheme() string {
+	if clientConf.TLS.Enabled {
+		return "https"
+	}
+	return "http"
+}
+
+// doHTTPRequest calls http api of server over the same tls as grpc calls
+func doHTTPRequest(req *http.Request) (*http.Response, error) {
+	if token := apiToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if !clientConf.TLS.Enabled {
+		return http.DefaultClient.Do(req)
+	}
+	tlsConf, err := clientTLSConfig(clientConf.TLS)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConf
+	return (&http.Client{Transport: transport}).Do(req)
+}
+
+// withPage asks server for a page of list response starting at pageToken
+func withPage(ctx context.Context, pageToken string) context.Context {
+	ctx = metadata.AppendToOutgoingContext(ctx, v1handler.MetadataPageSize, strconv.Itoa(listPageSize))
+	if pageToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, v1handler.MetadataPageToken, pageToken)
+	}
+	return ctx
+}
+
+// nextPageToken reads the token of next page from response header of a list
+// call, empty once all items are fetched
+func nextPageToken(header metadata.MD) string {
+	if vals := header.Get(v1handler.MetadataNextPageToken); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
@@ -3,13 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/user"
 	"time"
 
 	"github.com/spf13/afero"
 
 	"github.com/odpf/optimus/store/local"
 
+	v1handler "github.com/odpf/optimus/api/handler/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/fatih/color"
 	"github.com/odpf/optimus/config"
@@ -46,6 +50,9 @@ type JobSpecRepository interface {
 	Save(models.JobSpec) error
 	GetByName(string) (models.JobSpec, error)
 	GetAll() ([]models.JobSpec, error)
+	// Rename renames a job and rewrites dependencies on it, returning names
+	// of the jobs depending on it
+	Rename(oldName, newName string) ([]string, error)
 }
 
 // New constructs the 'root' command.
@@ -71,6 +78,11 @@ func New(
 	}
 	cmd.PersistentFlags().BoolVar(&disableColoredOut, "no-color", disableColoredOut, "disable colored output")
 
+	clientConf = conf.GetClient()
+	if clientConf.DialTimeoutSecs > 0 {
+		OptimusDialTimeout = clientConf.DialTimeoutSecs
+	}
+
 	//init local specs
 	var jobSpecRepo JobSpecRepository
 	jobSpecFs := afero.NewBasePathFs(afero.NewOsFs(), conf.GetJob().Path)
@@ -89,23 +101,37 @@ func New(
 	cmd.AddCommand(configCommand(l, dsRepo))
 	cmd.AddCommand(createCommand(l, jobSpecFs, datastoreSpecsFs, pluginRepo, dsRepo))
 	cmd.AddCommand(deployCommand(l, conf, jobSpecRepo, pluginRepo, dsRepo, datastoreSpecsFs))
-	cmd.AddCommand(renderCommand(l, conf.GetHost(), jobSpecRepo))
-	cmd.AddCommand(validateCommand(l, conf.GetHost(), pluginRepo, jobSpecRepo))
+	cmd.AddCommand(renderCommand(l, conf, jobSpecRepo))
+	cmd.AddCommand(validateCommand(l, conf.GetHost(), pluginRepo, jobSpecRepo, jobSpecFs))
+	cmd.AddCommand(schemaCommand(l))
 	cmd.AddCommand(optimusServeCommand(l, conf))
+	cmd.AddCommand(migrateCommand(l, conf))
 	cmd.AddCommand(replayCommand(l, conf))
+	cmd.AddCommand(dashboardCommand(l, conf))
+	cmd.AddCommand(jobCommand(l, conf, jobSpecRepo, jobSpecFs))
+	cmd.AddCommand(resourceCommand(l, conf, dsRepo, datastoreSpecsFs))
+	cmd.AddCommand(operationCommand(l, conf))
+	cmd.AddCommand(searchCommand(l, conf))
+	cmd.AddCommand(destinationCommand(l, conf))
+	cmd.AddCommand(upgradeCommand(l))
 
 	// admin specific commands
 	if conf.GetAdmin().Enabled {
 		cmd.AddCommand(adminCommand(l, pluginRepo))
 	}
 
+	// extensions are added last so they can't shadow built-in commands
+	addExtensionCommands(cmd, l, conf)
+
 	return cmd
 }
 
 func createConnection(ctx context.Context, host string) (*grpc.ClientConn, error) {
-	var opts []grpc.DialOption
+	opts, err := clientDialOptions(clientConf)
+	if err != nil {
+		return nil, err
+	}
 	opts = append(opts,
-		grpc.WithInsecure(),
 		grpc.WithBlock(),
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallSendMsgSize(GRPCMaxClientSendSize),
@@ -113,6 +139,30 @@ func createConnection(ctx context.Context, host string) (*grpc.ClientConn, error
 		),
 	)
 
+	// identify the user making calls for audit log, and authenticate them
+	// with an api token if one is set
+	var callMetadata []string
+	if actor := auditActor(); actor != "" {
+		callMetadata = append(callMetadata, v1handler.MetadataActor, actor)
+	}
+	if token := apiToken(); token != "" {
+		callMetadata = append(callMetadata, v1handler.MetadataAuthorization, "Bearer "+token)
+	}
+	if len(callMetadata) > 0 {
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{},
+				cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				ctx = metadata.AppendToOutgoingContext(ctx, callMetadata...)
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}),
+			grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+				method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				ctx = metadata.AppendToOutgoingContext(ctx, callMetadata...)
+				return streamer(ctx, desc, cc, method, opts...)
+			}),
+		)
+	}
+
 	conn, err := grpc.DialContext(ctx, host, opts...)
 	if err != nil {
 		return nil, err
@@ -120,3 +170,21 @@ func createConnection(ctx context.Context, host string) (*grpc.ClientConn, error
 
 	return conn, nil
 }
+
+// apiToken is the api token calls are made with, set in OPTIMUS_TOKEN env
+// e.g. by CI instead of personal credentials
+func apiToken() string {
+	return os.Getenv("OPTIMUS_TOKEN")
+}
+
+// auditActor is the current user of the client, OPTIMUS_ACTOR env
+// takes precedence over os user for shared machines and CI
+func auditActor() string {
+	if actor := os.Getenv("OPTIMUS_ACTOR"); actor != "" {
+		return actor
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
@@ -238,9 +238,9 @@ this effects runtime dependencies and template macros`,
 
 	// define defaults
 	jobInput := local.Job{
-		Version: local.JobConfigVersion,
-		Name:    baseInputs["name"],
-		Owner:   baseInputs["owner"],
+		APIVersion: local.JobConfigVersion,
+		Name:       baseInputs["name"],
+		Owner:      baseInputs["owner"],
 		Schedule: local.JobSchedule{
 			StartDate: baseInputs["start_date"],
 			Interval:  baseInputs["interval"],
@@ -0,0 +1,530 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	dashboardTimeout = time.Second * 30
+
+	// deployments shown on dashboard, latest first
+	dashboardDeployments = 5
+)
+
+// sections of dashboard, in the order they are shown and moved through
+const (
+	dashboardJobsSection = iota
+	dashboardReplaysSection
+	dashboardDeploymentsSection
+	dashboardSections
+)
+
+// dashboard is a snapshot of a namespace shown by dashboard command, a
+// section that failed to load carries its error instead
+type dashboard struct {
+	fetchedAt time.Time
+
+	jobs    []dashboardJob
+	jobsErr error
+
+	replays    []v1handler.ReplayStatusResponse
+	replaysErr error
+
+	deployments    []v1handler.ChangelogResponse
+	deploymentsErr error
+}
+
+// dashboardJob is a deployed job with its latest run known to scheduler
+type dashboardJob struct {
+	name     string
+	interval string
+	lastRun  *pb.JobStatus
+	err      error
+}
+
+// dashboardCommand shows jobs of a namespace with state of their latest run,
+// active replays and recent deployments in an interactive terminal ui,
+// refreshed till it is quit
+func dashboardCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		namespace   string
+		interval    time.Duration
+		once        bool
+	)
+	cmd := &cli.Command{
+		Use:   "dashboard",
+		Short: "Watch jobs, active replays and deployments of a namespace",
+		Long: "Shows deployed jobs of a namespace with the state of their latest run, active replays in the\n" +
+			"namespace with their progress and the latest deployments of the namespace, refreshed every interval\n" +
+			"till quit. Tab moves between sections, arrow keys between items of a section and enter shows\n" +
+			"details of the selected item, r refreshes right away and q quits.",
+		Example: "optimus dashboard --project \"project-id\" --namespace kafka\n" +
+			"optimus dashboard --project \"project-id\" --namespace kafka --interval 30s",
+		Args: cli.NoArgs,
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace of jobs")
+	cmd.MarkFlagRequired("namespace")
+	cmd.Flags().DurationVar(&interval, "interval", time.Second*10, "refresh dashboard every interval")
+	cmd.Flags().BoolVar(&once, "once", false, "print dashboard once and exit, e.g. when output is not a terminal")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		if interval < time.Second {
+			return errors.New("interval should be at least a second")
+		}
+		if once {
+			board := fetchDashboard(l, conf.GetHost(), projectName, namespace)
+			var out bytes.Buffer
+			printDashboard(&out, projectName, namespace, board, nil)
+			l.Print(out.String())
+			return nil
+		}
+		// the ui owns the terminal, fetches don't log over it
+		model := &dashboardModel{
+			l:           log.New(ioutil.Discard, "", 0),
+			host:        conf.GetHost(),
+			projectName: projectName,
+			namespace:   namespace,
+			interval:    interval,
+			loading:     true,
+		}
+		return tea.NewProgram(model, tea.WithAltScreen()).Start()
+	}
+	return cmd
+}
+
+// dashboardFetchedMsg carries a dashboard fetched in background
+type dashboardFetchedMsg dashboard
+
+// dashboardRefreshMsg asks for the dashboard to be fetched again
+type dashboardRefreshMsg struct{}
+
+// dashboardModel is the interactive dashboard, an item of each section can
+// be selected and the details of the selected one are shown on request
+type dashboardModel struct {
+	l           logger
+	host        string
+	projectName string
+	namespace   string
+	interval    time.Duration
+
+	board   dashboard
+	loading bool
+
+	section  int
+	cursor   [dashboardSections]int
+	expanded bool
+}
+
+func (m *dashboardModel) Init() tea.Cmd {
+	return m.fetch
+}
+
+func (m *dashboardModel) fetch() tea.Msg {
+	return dashboardFetchedMsg(fetchDashboard(m.l, m.host, m.projectName, m.namespace))
+}
+
+func (m *dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case dashboardFetchedMsg:
+		m.board = dashboard(msg)
+		m.loading = false
+		// items may have gone away since the last refresh
+		for section := range m.cursor {
+			if count := m.board.items(section); m.cursor[section] >= count && count > 0 {
+				m.cursor[section] = count - 1
+			}
+		}
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg {
+			return dashboardRefreshMsg{}
+		})
+	case dashboardRefreshMsg:
+		if m.loading {
+			return m, nil
+		}
+		m.loading = true
+		return m, m.fetch
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "tab", "right", "l":
+			m.section = (m.section + 1) % dashboardSections
+			m.expanded = false
+		case "shift+tab", "left", "h":
+			m.section = (m.section + dashboardSections - 1) % dashboardSections
+			m.expanded = false
+		case "up", "k":
+			if m.cursor[m.section] > 0 {
+				m.cursor[m.section]--
+			}
+		case "down", "j":
+			if m.cursor[m.section] < m.board.items(m.section)-1 {
+				m.cursor[m.section]++
+			}
+		case "enter", " ":
+			m.expanded = !m.expanded
+		case "esc":
+			m.expanded = false
+		case "r":
+			return m.Update(dashboardRefreshMsg{})
+		}
+	}
+	return m, nil
+}
+
+func (m *dashboardModel) View() string {
+	var out bytes.Buffer
+	printDashboard(&out, m.projectName, m.namespace, m.board, &dashboardSelection{
+		section: m.section,
+		item:    m.cursor[m.section],
+	})
+	if m.expanded {
+		fmt.Fprintf(&out, "\n%s\n", coloredNotice("Details"))
+		printDashboardDetails(&out, m.board, m.section, m.cursor[m.section])
+	}
+	status := fmt.Sprintf("refreshing every %s", m.interval)
+	if m.loading {
+		status = "refreshing..."
+	}
+	fmt.Fprintf(&out, "\n%s, tab: next section, up/down: select, enter: details, r: refresh, q: quit\n", status)
+	return out.String()
+}
+
+// items is the number of items listed in a section of dashboard
+func (board dashboard) items(section int) int {
+	switch section {
+	case dashboardJobsSection:
+		return len(board.jobs)
+	case dashboardReplaysSection:
+		return len(board.replays)
+	case dashboardDeploymentsSection:
+		return len(board.deployments)
+	}
+	return 0
+}
+
+func fetchDashboard(l logger, host, projectName, namespace string) dashboard {
+	board := dashboard{fetchedAt: time.Now()}
+	board.jobs, board.jobsErr = getDashboardJobs(l, host, projectName, namespace)
+	board.replays, board.replaysErr = getActiveReplays(host, projectName, namespace)
+	board.deployments, board.deploymentsErr = getDeployChangelogs(host, projectName, namespace, dashboardDeployments)
+	return board
+}
+
+// getDashboardJobs lists deployed jobs of namespace with their latest run,
+// failing to fetch runs of a job is kept with the job
+func getDashboardJobs(l logger, host, projectName, namespace string) ([]dashboardJob, error) {
+	jobSpecs, err := listJobSpecifications(l, host, projectName, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
+	defer dialCancel()
+	conn, err := createConnection(dialTimeoutCtx, host)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), callTimeout(dashboardTimeout))
+	defer cancel()
+	runtime := pb.NewRuntimeServiceClient(conn)
+	jobs := make([]dashboardJob, 0, len(jobSpecs))
+	for _, jobSpec := range jobSpecs {
+		job := dashboardJob{
+			name:     jobSpec.GetName(),
+			interval: jobSpec.GetInterval(),
+		}
+		// statuses are served latest first, the first page has the latest run
+		resp, err := runtime.JobStatus(withPage(timeoutCtx, ""), &pb.JobStatusRequest{
+			ProjectName: projectName,
+			JobName:     jobSpec.GetName(),
+		})
+		if err != nil {
+			job.err = err
+		}
+		if statuses := resp.GetStatuses(); len(statuses) > 0 {
+			job.lastRun = statuses[0]
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].name < jobs[j].name
+	})
+	return jobs, nil
+}
+
+// getActiveReplays lists active replays of namespace from all pages
+func getActiveReplays(host, projectName, namespace string) ([]v1handler.ReplayStatusResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(replayTimeout))
+	defer cancel()
+
+	params := url.Values{}
+	params.Set("project", projectName)
+	if namespace != "" {
+		params.Set("namespace", namespace)
+	}
+	params.Set(v1handler.QueryPageSize, strconv.Itoa(listPageSize))
+	var replays []v1handler.ReplayStatusResponse
+	for {
+		page, pageToken, err := getReplaysPage(ctx, host, params)
+		if err != nil {
+			return nil, err
+		}
+		replays = append(replays, page...)
+		if pageToken == "" {
+			return replays, nil
+		}
+		params.Set(v1handler.QueryPageToken, pageToken)
+	}
+}
+
+// getReplaysPage fetches a page of replays along with the token of the
+// next page, empty on the last page
+func getReplaysPage(ctx context.Context, host string, params url.Values) ([]v1handler.ReplayStatusResponse, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/replays?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to fetch replays")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", errors.Errorf("failed to fetch replays, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var replays []v1handler.ReplayStatusResponse
+	if err := json.Unmarshal(body, &replays); err != nil {
+		return nil, "", errors.Wrap(err, "failed to decode replays")
+	}
+	return replays, resp.Header.Get(v1handler.HeaderNextPageToken), nil
+}
+
+// dashboardSelection is the item selected in a section of the interactive
+// dashboard
+type dashboardSelection struct {
+	section int
+	item    int
+}
+
+// marker prefixes an item of section, pointing at the selected one
+func (sel *dashboardSelection) marker(section, item int) string {
+	if sel == nil {
+		return ""
+	}
+	if sel.section == section && sel.item == item {
+		return "> "
+	}
+	return "  "
+}
+
+// heading of section, marked when the section is selected
+func (sel *dashboardSelection) heading(section int, title string) string {
+	if sel != nil && sel.section == section {
+		return coloredNotice("[" + title + "]")
+	}
+	return coloredNotice(title)
+}
+
+// printDashboard writes the dashboard, selection is nil unless it is shown
+// interactively
+func printDashboard(out *bytes.Buffer, projectName, namespace string, board dashboard, sel *dashboardSelection) {
+	fmt.Fprintf(out, "%s\n\n", coloredNotice(fmt.Sprintf("project %s, namespace %s at %s", projectName, namespace,
+		board.fetchedAt.Format(time.RFC3339))))
+
+	fmt.Fprintln(out, sel.heading(dashboardJobsSection, "Jobs"))
+	switch {
+	case board.jobsErr != nil:
+		fmt.Fprintln(out, coloredError(board.jobsErr.Error()))
+	case len(board.jobs) == 0:
+		fmt.Fprintln(out, "no jobs deployed")
+	default:
+		table := newDashboardTable(out, []string{"Job", "Interval", "Latest run", "State"})
+		for i, job := range board.jobs {
+			lastRun, state := "-", "-"
+			switch {
+			case job.err != nil:
+				state = coloredError("unknown: " + job.err.Error())
+			case job.lastRun != nil:
+				lastRun = job.lastRun.GetScheduledAt().AsTime().Format(time.RFC3339)
+				state = coloredRunState(job.lastRun.GetState())
+			}
+			table.Append([]string{sel.marker(dashboardJobsSection, i) + job.name, job.interval, lastRun, state})
+		}
+		table.Render()
+	}
+
+	fmt.Fprintf(out, "\n%s\n", sel.heading(dashboardReplaysSection, "Active replays"))
+	switch {
+	case board.replaysErr != nil:
+		fmt.Fprintln(out, coloredError(board.replaysErr.Error()))
+	case len(board.replays) == 0:
+		fmt.Fprintln(out, "no active replays")
+	default:
+		table := newDashboardTable(out, []string{"ID", "Job", "Window", "Status", "Progress"})
+		for i, replay := range board.replays {
+			jobName := replay.JobName
+			if jobName == "" {
+				jobName = fmt.Sprintf("plan of %d jobs", len(replay.Jobs))
+			}
+			table.Append([]string{sel.marker(dashboardReplaysSection, i) + replay.ID, jobName,
+				fmt.Sprintf("%s - %s", replay.StartDate.Format(time.RFC3339), replay.EndDate.Format(time.RFC3339)),
+				replay.Status, replayProgress(replay, board.fetchedAt)})
+		}
+		table.Render()
+	}
+
+	fmt.Fprintf(out, "\n%s\n", sel.heading(dashboardDeploymentsSection, "Recent deployments"))
+	switch {
+	case board.deploymentsErr != nil:
+		fmt.Fprintln(out, coloredError(board.deploymentsErr.Error()))
+	case len(board.deployments) == 0:
+		fmt.Fprintln(out, "no deployments recorded")
+	default:
+		table := newDashboardTable(out, []string{"ID", "Deployed at", "Actor", "Changes", "Result"})
+		for i, changelog := range board.deployments {
+			result := coloredSuccess("deployed")
+			if changelog.Failed {
+				var failed []string
+				for _, jobResult := range changelog.Results {
+					if !jobResult.Success {
+						failed = append(failed, jobResult.Name)
+					}
+				}
+				result = coloredError("failed")
+				if len(failed) > 0 {
+					result += ": " + strings.Join(failed, ", ")
+				}
+			}
+			table.Append([]string{sel.marker(dashboardDeploymentsSection, i) + changelog.ID, changelog.CreatedAt.Format(time.RFC3339), changelog.Actor,
+				fmt.Sprintf("%d", len(changelog.Changes)), result})
+		}
+		table.Render()
+	}
+}
+
+// printDashboardDetails writes details of an item of a section of dashboard
+func printDashboardDetails(out *bytes.Buffer, board dashboard, section, item int) {
+	if item >= board.items(section) {
+		fmt.Fprintln(out, "nothing selected")
+		return
+	}
+	switch section {
+	case dashboardJobsSection:
+		job := board.jobs[item]
+		fmt.Fprintf(out, "job: %s\ninterval: %s\n", job.name, job.interval)
+		switch {
+		case job.err != nil:
+			fmt.Fprintln(out, coloredError("runs unknown: "+job.err.Error()))
+		case job.lastRun == nil:
+			fmt.Fprintln(out, "no runs yet")
+		default:
+			fmt.Fprintf(out, "latest run scheduled at: %s\nstate: %s\n",
+				job.lastRun.GetScheduledAt().AsTime().Format(time.RFC3339), coloredRunState(job.lastRun.GetState()))
+		}
+	case dashboardReplaysSection:
+		replay := board.replays[item]
+		fmt.Fprintf(out, "replay: %s\nsubmitted at: %s\nstatus: %s\nprogress: %s\n", replay.ID,
+			replay.CreatedAt.Format(time.RFC3339), replay.Status, replayProgress(replay, board.fetchedAt))
+		if replay.Message != "" {
+			fmt.Fprintf(out, "message: %s\n", replay.Message)
+		}
+		if len(replay.Jobs) > 0 {
+			table := newDashboardTable(out, []string{"Job", "Status"})
+			for _, jobReplay := range replay.Jobs {
+				table.Append([]string{jobReplay.JobName, jobReplay.Status})
+			}
+			table.Render()
+		}
+	case dashboardDeploymentsSection:
+		changelog := board.deployments[item]
+		fmt.Fprintf(out, "deployment: %s\ndeployed at: %s\nactor: %s\n", changelog.ID,
+			changelog.CreatedAt.Format(time.RFC3339), changelog.Actor)
+		if changelog.RollbackOf != "" {
+			fmt.Fprintf(out, "rollback of: %s\n", changelog.RollbackOf)
+		}
+		results := map[string]v1handler.ChangelogResultResponse{}
+		for _, jobResult := range changelog.Results {
+			results[jobResult.Name] = jobResult
+		}
+		table := newDashboardTable(out, []string{"Job", "Change", "Result"})
+		for _, change := range changelog.Changes {
+			result := "-"
+			if jobResult, ok := results[change.Name]; ok {
+				result = coloredSuccess("deployed")
+				if !jobResult.Success {
+					result = coloredError("failed: " + jobResult.Message)
+				}
+			}
+			table.Append([]string{change.Name, change.Type, result})
+		}
+		table.Render()
+	}
+}
+
+func newDashboardTable(out *bytes.Buffer, header []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	table.SetBorder(false)
+	table.SetAutoWrapText(false)
+	table.SetHeader(header)
+	return table
+}
+
+// replayProgress tells how far an active replay has come, its place in
+// queue till a worker picks it up and the time since it was submitted after
+func replayProgress(replay v1handler.ReplayStatusResponse, now time.Time) string {
+	if replay.Status == models.ReplayStatusAccepted {
+		if replay.QueuePosition == 0 {
+			return "waiting for a worker"
+		}
+		progress := fmt.Sprintf("queued at %d", replay.QueuePosition)
+		if replay.QueueETA != "" {
+			progress += fmt.Sprintf(", starts in %s", replay.QueueETA)
+		}
+		return progress
+	}
+	progress := "in progress"
+	if len(replay.Jobs) > 0 {
+		progress += fmt.Sprintf(", %d of %d jobs ended", replay.JobsEnded, len(replay.Jobs))
+	}
+	return fmt.Sprintf("%s, submitted %s ago", progress, now.Sub(replay.CreatedAt).Round(time.Second))
+}
+
+func coloredRunState(state string) string {
+	switch models.JobStatusState(state) {
+	case models.JobStatusStateSuccess:
+		return coloredSuccess(state)
+	case models.JobStatusStateFailed:
+		return coloredError(state)
+	}
+	return state
+}
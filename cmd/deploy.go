@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/afero"
@@ -14,14 +16,21 @@ import (
 
 	v1handler "github.com/odpf/optimus/api/handler/v1"
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/job"
 	"github.com/odpf/optimus/models"
 	"github.com/pkg/errors"
 	cli "github.com/spf13/cobra"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 var (
 	deploymentTimeout = time.Minute * 10
+
+	// canary jobs are waited on by server before jobs are deployed
+	canaryDeploymentTimeout = time.Minute * 30
 )
 
 // deployCommand pushes current repo to optimus service
@@ -31,6 +40,11 @@ func deployCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository
 	var namespace string
 	var ignoreJobs bool
 	var ignoreResources bool
+	var dryRun bool
+	var canary string
+	var selector string
+	var acceptScheduleImpact bool
+	var commit models.CommitRef
 
 	cmd := &cli.Command{
 		Use:   "deploy",
@@ -42,8 +56,27 @@ func deployCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository
 	cmd.MarkFlagRequired("namespace")
 	cmd.Flags().BoolVar(&ignoreJobs, "ignore-jobs", false, "ignore deployment of jobs")
 	cmd.Flags().BoolVar(&ignoreResources, "ignore-resources", false, "ignore deployment of resources")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only show changes deployment of resources would make")
+	cmd.Flags().StringVar(&canary, "canary", "", fmt.Sprintf("deploy changed jobs only if their canary is loaded by scheduler, "+
+		"with %s a run of each canary should succeed too", v1handler.CanaryRun))
+	cmd.Flags().Lookup("canary").NoOptDefVal = v1handler.CanaryDeploy
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "deploy only jobs with labels matching the selector e.g. tier=critical, "+
+		"other jobs of namespace are left as they are")
+	cmd.Flags().BoolVar(&acceptScheduleImpact, "accept-schedule-impact", false, "deploy jobs even if changes of their schedule "+
+		"skip or duplicate data, trigger catchup runs or affect sensors of dependent jobs")
+	cmd.Flags().StringVar(&commit.SHA, "commit", "", "commit of the specifications deployed, result of deploying jobs is "+
+		"posted back to it as a check run or commit status")
+	cmd.Flags().StringVar(&commit.Repository, "repository", "", "repository of the commit e.g. github.com/odpf/optimus")
 
 	cmd.RunE = func(c *cli.Command, args []string) error {
+		if dryRun {
+			l.Printf("planning resources of project %s for namespace %s at %s\nplease wait...\n", projectName, namespace, conf.GetHost())
+			return postResourcePlanRequest(l, projectName, namespace, conf, pluginRepo, datastoreRepo, datastoreSpecFs)
+		}
+		labelSelector, err := models.ParseLabelSelector(selector)
+		if err != nil {
+			return err
+		}
 		l.Printf("deploying project %s for namespace %s at %s\nplease wait...\n", projectName, namespace, conf.GetHost())
 		start := time.Now()
 		if jobSpecRepo == nil {
@@ -51,8 +84,15 @@ func deployCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository
 			ignoreJobs = true
 		}
 
+		if (commit.SHA == "") != (commit.Repository == "") {
+			return errors.New("commit and repository should be set together")
+		}
+		if jobPath := conf.GetJob().Path; jobPath != "" && !filepath.IsAbs(jobPath) {
+			// relative to root of repository, where deployments from CI usually run
+			commit.SpecPath = filepath.ToSlash(filepath.Clean(jobPath))
+		}
 		if err := postDeploymentRequest(l, projectName, namespace, jobSpecRepo, conf, pluginRepo, datastoreRepo,
-			datastoreSpecFs, ignoreJobs, ignoreResources); err != nil {
+			datastoreSpecFs, ignoreJobs, ignoreResources, canary, labelSelector, acceptScheduleImpact, commit); err != nil {
 			return err
 		}
 
@@ -60,13 +100,95 @@ func deployCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository
 		return nil
 	}
 
+	cmd.AddCommand(deployChangelogCommand(l, conf))
+	cmd.AddCommand(deployRollbackCommand(l, conf))
 	return cmd
 }
 
+// postResourcePlanRequest sends resources as a dry run deployment and prints
+// the changes deploying them would make
+func postResourcePlanRequest(l logger, projectName string, namespace string, conf config.Provider,
+	pluginRepo models.PluginRepository, datastoreRepo models.DatastoreRepo, datastoreSpecFs map[string]afero.Fs) (err error) {
+	dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
+	defer dialCancel()
+
+	var conn *grpc.ClientConn
+	if conn, err = createConnection(dialTimeoutCtx, conf.GetHost()); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			l.Println("can't reach optimus service")
+		}
+		return err
+	}
+	defer conn.Close()
+
+	deployTimeoutCtx, deployCancel := context.WithTimeout(context.Background(), callTimeout(deploymentTimeout))
+	defer deployCancel()
+	deployTimeoutCtx = metadata.AppendToOutgoingContext(deployTimeoutCtx, v1handler.MetadataDryRun, "true")
+
+	runtime := pb.NewRuntimeServiceClient(conn)
+	adapt := v1handler.NewAdapter(pluginRepo, datastoreRepo)
+	for storeName, repoFS := range datastoreSpecFs {
+		ds, err := datastoreRepo.GetByName(storeName)
+		if err != nil {
+			return fmt.Errorf("unsupported datastore: %s\n", storeName)
+		}
+		resourceSpecs, err := local.NewResourceSpecRepository(repoFS, ds).GetAll()
+		if err == models.ErrNoResources {
+			l.Println(coloredNotice("no resource specifications found"))
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "resourceSpecRepo.GetAll()")
+		}
+
+		adaptedSpecs := []*pb.ResourceSpecification{}
+		for _, spec := range resourceSpecs {
+			adapted, err := adapt.ToResourceProto(spec)
+			if err != nil {
+				return errors.Wrapf(err, "failed to serialize: %s", spec.Name)
+			}
+			adaptedSpecs = append(adaptedSpecs, adapted)
+		}
+
+		respStream, err := runtime.DeployResourceSpecification(deployTimeoutCtx, &pb.DeployResourceSpecificationRequest{
+			Resources:     adaptedSpecs,
+			ProjectName:   projectName,
+			DatastoreName: storeName,
+			Namespace:     namespace,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "dry run failed")
+		}
+		for {
+			resp, err := respStream.Recv()
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+				return errors.Wrapf(err, "failed to receive dry run ack")
+			}
+			if !resp.Ack {
+				continue
+			}
+			if !resp.GetSuccess() {
+				l.Printf("%s: %s\n", coloredError(resp.GetResourceName()), resp.GetMessage())
+				continue
+			}
+			l.Println(coloredNotice(resp.GetResourceName()))
+			if resp.GetMessage() != "" {
+				l.Println(resp.GetMessage())
+			}
+		}
+	}
+	l.Println(coloredSuccess("dry run finished, nothing was deployed"))
+	return nil
+}
+
 // postDeploymentRequest send a deployment request to service
 func postDeploymentRequest(l logger, projectName string, namespace string, jobSpecRepo JobSpecRepository,
 	conf config.Provider, pluginRepo models.PluginRepository, datastoreRepo models.DatastoreRepo, datastoreSpecFs map[string]afero.Fs,
-	ignoreJobDeployment, ignoreResources bool) (err error) {
+	ignoreJobDeployment, ignoreResources bool, canary string, selector models.LabelSelector, acceptScheduleImpact bool,
+	commit models.CommitRef) (err error) {
 	dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
 	defer dialCancel()
 
@@ -79,7 +201,11 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 	}
 	defer conn.Close()
 
-	deployTimeoutCtx, deployCancel := context.WithTimeout(context.Background(), deploymentTimeout)
+	timeout := deploymentTimeout
+	if canary != "" {
+		timeout = canaryDeploymentTimeout
+	}
+	deployTimeoutCtx, deployCancel := context.WithTimeout(context.Background(), callTimeout(timeout))
 	defer deployCancel()
 
 	runtime := pb.NewRuntimeServiceClient(conn)
@@ -153,6 +279,7 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 					if err == io.EOF {
 						break
 					}
+					printFreezeReason(l, err)
 					return errors.Wrapf(err, "failed to receive deployment ack")
 				}
 				if resp.Ack {
@@ -180,6 +307,7 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 		if err != nil {
 			return err
 		}
+		jobSpecs = selector.SelectJobSpecs(jobSpecs)
 
 		var adaptedJobSpecs []*pb.JobSpecification
 		for _, spec := range jobSpecs {
@@ -189,7 +317,26 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 			}
 			adaptedJobSpecs = append(adaptedJobSpecs, adaptJob)
 		}
-		respStream, err := runtime.DeployJobSpecification(deployTimeoutCtx, &pb.DeployJobSpecificationRequest{
+		jobDeployCtx := deployTimeoutCtx
+		if canary != "" {
+			l.Println("deploying canary of changed jobs first, jobs are deployed once canary succeed")
+			jobDeployCtx = metadata.AppendToOutgoingContext(jobDeployCtx, v1handler.MetadataCanary, canary)
+		}
+		if !selector.Empty() {
+			l.Printf("deploying %d jobs matching %s, other jobs of namespace are left as they are\n", len(jobSpecs), selector.String())
+			jobDeployCtx = metadata.AppendToOutgoingContext(jobDeployCtx, v1handler.MetadataLabelSelector, selector.String())
+		}
+		if acceptScheduleImpact {
+			jobDeployCtx = metadata.AppendToOutgoingContext(jobDeployCtx, v1handler.MetadataAcceptScheduleImpact, "true")
+		}
+		if commit.SHA != "" {
+			jobDeployCtx = metadata.AppendToOutgoingContext(jobDeployCtx,
+				v1handler.MetadataCommitRepository, commit.Repository,
+				v1handler.MetadataCommitSHA, commit.SHA,
+				v1handler.MetadataCommitSpecPath, commit.SpecPath,
+			)
+		}
+		respStream, err := runtime.DeployJobSpecification(jobDeployCtx, &pb.DeployJobSpecificationRequest{
 			Jobs:        adaptedJobSpecs,
 			ProjectName: projectName,
 			Namespace:   namespace,
@@ -209,6 +356,8 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 				if err == io.EOF {
 					break
 				}
+				printFreezeReason(l, err)
+				printScheduleImpact(l, err)
 				return errors.Wrapf(err, "failed to receive deployment ack")
 			}
 			if resp.Ack {
@@ -216,6 +365,10 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 				if !resp.GetSuccess() {
 					return errors.Errorf("unable to deploy: %s %s", resp.GetJobName(), resp.GetMessage())
 				}
+				if canary != "" && strings.HasSuffix(resp.GetJobName(), job.CanaryJobSuffix) {
+					l.Printf("canary %s deployed\n", resp.GetJobName())
+					continue
+				}
 				jobCounter++
 				l.Printf("%d/%d. %s successfully deployed\n", jobCounter, totalJobs, resp.GetJobName())
 			} else {
@@ -231,3 +384,28 @@ func postDeploymentRequest(l logger, projectName string, namespace string, jobSp
 	l.Println("deployment completed successfully")
 	return nil
 }
+
+// printScheduleImpact tells user which schedule changes rejected the
+// deployment, and how to deploy them anyway
+func printScheduleImpact(l logger, err error) {
+	st, ok := status.FromError(errors.Cause(err))
+	if !ok {
+		return
+	}
+	var rejected bool
+	for _, detail := range st.Details() {
+		failure, ok := detail.(*errdetails.PreconditionFailure)
+		if !ok {
+			continue
+		}
+		for _, violation := range failure.GetViolations() {
+			if violation.GetType() == v1handler.ScheduleImpactViolationType {
+				l.Println(coloredError(violation.GetDescription()))
+				rejected = true
+			}
+		}
+	}
+	if rejected {
+		l.Println("deploy with --accept-schedule-impact to accept impact of the schedule changes")
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	deployChangelogTimeout = time.Second * 10
+
+	// length of snapshot hash shown, enough to tell snapshots apart
+	snapshotHashLength = 12
+)
+
+// deployChangelogCommand prints what changed in the jobs of previous deployments
+func deployChangelogCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		namespace   string
+		last        bool
+		limit       int
+	)
+	cmd := &cli.Command{
+		Use:   "changelog",
+		Short: "Show jobs added, modified and removed by previous deployments",
+		Example: "optimus deploy changelog --project \"project-id\" --last\n" +
+			"optimus deploy changelog --project \"project-id\" --namespace \"namespace-id\" --limit 5",
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "only show deployments of this namespace")
+	cmd.Flags().BoolVar(&last, "last", false, "only show the latest deployment")
+	cmd.Flags().IntVar(&limit, "limit", 10, "number of deployments to show")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		if last {
+			limit = 1
+		}
+		changelogs, err := getDeployChangelogs(conf.GetHost(), projectName, namespace, limit)
+		if err != nil {
+			return err
+		}
+		if len(changelogs) == 0 {
+			l.Println(coloredNotice(fmt.Sprintf("no deployments recorded for project %s", projectName)))
+			return nil
+		}
+		for _, changelog := range changelogs {
+			printDeployChangelog(l, changelog)
+		}
+		return nil
+	}
+	return cmd
+}
+
+func getDeployChangelogs(host, projectName, namespace string, limit int) ([]v1handler.ChangelogResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(deployChangelogTimeout))
+	defer cancel()
+
+	params := url.Values{}
+	params.Set("project", projectName)
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	if namespace != "" {
+		params.Set("namespace", namespace)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/admin/changelog?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch changelog")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch changelog, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var changelogs []v1handler.ChangelogResponse
+	if err := json.Unmarshal(body, &changelogs); err != nil {
+		return nil, errors.Wrap(err, "failed to decode changelog")
+	}
+	return changelogs, nil
+}
+
+func printDeployChangelog(l logger, changelog v1handler.ChangelogResponse) {
+	header := fmt.Sprintf("deployment %s of namespace %s at %s", changelog.ID, changelog.Namespace,
+		changelog.CreatedAt.Format(time.RFC3339))
+	if changelog.Actor != "" {
+		header += fmt.Sprintf(" by %s", changelog.Actor)
+	}
+	if changelog.RollbackOf != "" {
+		header += fmt.Sprintf(", rollback to %s", changelog.RollbackOf)
+	}
+	if changelog.Failed {
+		header += coloredError(" failed")
+	}
+	l.Println(header)
+	if len(changelog.SnapshotHash) >= snapshotHashLength {
+		l.Printf("  snapshot %s\n", changelog.SnapshotHash[:snapshotHashLength])
+	}
+	if len(changelog.Changes) == 0 {
+		l.Println("  no changes in jobs")
+	}
+	for _, change := range changelog.Changes {
+		switch models.JobChangeType(change.Type) {
+		case models.JobChangeTypeAdded:
+			l.Println(coloredSuccess(fmt.Sprintf("  + %s", change.Name)))
+		case models.JobChangeTypeRemoved:
+			l.Println(coloredError(fmt.Sprintf("  - %s", change.Name)))
+		default:
+			l.Println(coloredNotice(fmt.Sprintf("  ~ %s", change.Name)))
+		}
+		for _, field := range change.Fields {
+			l.Printf("      %s: %q -> %q\n", field.Field, field.Old, field.New)
+		}
+	}
+	for _, result := range changelog.Results {
+		if !result.Success {
+			l.Println(coloredError(fmt.Sprintf("  ! %s failed to deploy: %s", result.Name, result.Message)))
+		}
+	}
+	l.Println()
+}
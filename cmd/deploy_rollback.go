@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/config"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// deployRollbackCommand redeploys jobs of a previous deployment of namespace
+func deployRollbackCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		namespace   string
+		to          string
+
+		acceptScheduleImpact bool
+	)
+	cmd := &cli.Command{
+		Use:   "rollback",
+		Short: "Redeploy jobs of a previous deployment of namespace",
+		Long: "Redeploy jobs of a previous deployment of namespace, by default the latest successful one with jobs\n" +
+			"different from the ones deployed last. Deployments can be listed with `optimus deploy changelog`",
+		Example: "optimus deploy rollback --project \"project-id\" --namespace \"namespace-id\"\n" +
+			"optimus deploy rollback --project \"project-id\" --namespace \"namespace-id\" --to \"deployment-id\"",
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace to roll back")
+	cmd.MarkFlagRequired("namespace")
+	cmd.Flags().StringVar(&to, "to", v1handler.RollbackPrevious, "id of deployment to roll back to")
+	cmd.Flags().BoolVar(&acceptScheduleImpact, "accept-schedule-impact", false, "roll back even if changes of schedule "+
+		"skip or duplicate data, trigger catchup runs or affect sensors of dependent jobs")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		l.Printf("rolling back namespace %s of project %s to %s deployment\nplease wait...\n", namespace, projectName, to)
+		start := time.Now()
+		if err := postRollbackRequest(l, conf.GetHost(), projectName, namespace, to, acceptScheduleImpact); err != nil {
+			return err
+		}
+		l.Printf("rollback took %v\n", time.Since(start))
+		return nil
+	}
+	return cmd
+}
+
+func postRollbackRequest(l logger, host, projectName, namespace, to string, acceptScheduleImpact bool) (err error) {
+	dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
+	defer dialCancel()
+
+	var conn *grpc.ClientConn
+	if conn, err = createConnection(dialTimeoutCtx, host); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			l.Println("can't reach optimus service")
+		}
+		return err
+	}
+	defer conn.Close()
+
+	deployTimeoutCtx, deployCancel := context.WithTimeout(context.Background(), callTimeout(deploymentTimeout))
+	defer deployCancel()
+	deployTimeoutCtx = metadata.AppendToOutgoingContext(deployTimeoutCtx, v1handler.MetadataRollbackTo, to)
+	if acceptScheduleImpact {
+		deployTimeoutCtx = metadata.AppendToOutgoingContext(deployTimeoutCtx, v1handler.MetadataAcceptScheduleImpact, "true")
+	}
+
+	// jobs are picked from the deployment rolled back to by server
+	runtime := pb.NewRuntimeServiceClient(conn)
+	respStream, err := runtime.DeployJobSpecification(deployTimeoutCtx, &pb.DeployJobSpecificationRequest{
+		ProjectName: projectName,
+		Namespace:   namespace,
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			l.Println("rollback took too long, timing out")
+		}
+		return errors.Wrap(err, "rollback failed")
+	}
+
+	jobCounter := 0
+	for {
+		resp, err := respStream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			printFreezeReason(l, err)
+			printScheduleImpact(l, err)
+			return errors.Wrap(err, "failed to receive rollback ack")
+		}
+		if resp.Ack {
+			if !resp.GetSuccess() {
+				return errors.Errorf("unable to deploy: %s %s", resp.GetJobName(), resp.GetMessage())
+			}
+			jobCounter++
+			l.Printf("%d. %s successfully deployed\n", jobCounter, resp.GetJobName())
+		} else {
+			l.Printf("info '%s': %s\n", resp.GetJobName(), resp.GetMessage())
+		}
+	}
+	l.Println(coloredSuccess(fmt.Sprintf("rolled back namespace %s", namespace)))
+	return nil
+}
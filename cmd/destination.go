@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	destinationTimeout = time.Second * 15
+)
+
+// destinationCommand finds jobs producing destinations across projects
+func destinationCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		prefix string
+		limit  int
+	)
+	cmd := &cli.Command{
+		Use:   "destination [destination]",
+		Short: "Find jobs producing a destination across projects",
+		Example: "optimus destination shop-project:finance.daily_revenue\n" +
+			"optimus destination --prefix shop-project:finance.",
+		Args: cli.MaximumNArgs(1),
+	}
+	cmd.Flags().StringVar(&prefix, "prefix", "", "list destinations starting with prefix")
+	cmd.Flags().IntVar(&limit, "limit", 0, "max destinations listed by prefix")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		switch {
+		case len(args) == 1:
+			params.Set("destination", args[0])
+		case prefix != "":
+			params.Set("prefix", prefix)
+		default:
+			return errors.New("destination or --prefix is required")
+		}
+		if limit > 0 {
+			params.Set("limit", strconv.Itoa(limit))
+		}
+
+		result, err := getDestinations(conf.GetHost(), params)
+		if err != nil {
+			return err
+		}
+		if len(result.Producers) == 0 {
+			l.Println(coloredNotice("no destinations found"))
+			return nil
+		}
+		table := tablewriter.NewWriter(l.Writer())
+		table.SetBorder(false)
+		table.SetHeader([]string{"Destination", "Project", "Namespace", "Job", "Task", "Owner"})
+		for _, producer := range result.Producers {
+			table.Append([]string{producer.Destination, producer.Project, producer.Namespace, producer.Job,
+				producer.Task, producer.Owner})
+		}
+		table.Render()
+		return nil
+	}
+	return cmd
+}
+
+func getDestinations(host string, params url.Values) (v1handler.DestinationsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(destinationTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/destinations?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.DestinationsResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.DestinationsResponse{}, errors.Wrap(err, "failed to get destinations")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.DestinationsResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.DestinationsResponse{}, errors.Errorf("failed to get destinations, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var result v1handler.DestinationsResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return v1handler.DestinationsResponse{}, errors.Wrap(err, "failed to decode destinations")
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/extension"
+	cli "github.com/spf13/cobra"
+)
+
+// addExtensionCommands exposes extensions found on PATH or registered in config
+// as subcommands, built-in commands can not be overridden by extensions
+func addExtensionCommands(cmd *cli.Command, l logger, conf config.Provider) {
+	builtIn := map[string]bool{}
+	for _, c := range cmd.Commands() {
+		builtIn[c.Name()] = true
+	}
+	for name, binPath := range extension.Discover(os.Getenv("PATH"), conf.GetExtensions()) {
+		if builtIn[name] {
+			l.Println(coloredError("ignoring extension " + binPath + ", it conflicts with command " + name))
+			continue
+		}
+		cmd.AddCommand(extensionCommand(name, binPath, conf))
+	}
+}
+
+func extensionCommand(name, binPath string, conf config.Provider) *cli.Command {
+	return &cli.Command{
+		Use:                name,
+		Short:              "Extension at " + binPath,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cli.Command, args []string) error {
+			extCmd := exec.Command(binPath, args...)
+			extCmd.Stdin = os.Stdin
+			extCmd.Stdout = os.Stdout
+			extCmd.Stderr = os.Stderr
+			extCmd.Env = append(os.Environ(),
+				extension.EnvHost+"="+conf.GetHost(),
+				extension.EnvActor+"="+auditActor(),
+			)
+			return extCmd.Run()
+		},
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	jobLogsTimeout = time.Second * 10
+)
+
+func jobCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository, jobSpecFs afero.Fs) *cli.Command {
+	cmd := &cli.Command{
+		Use:   "job",
+		Short: "Inspect and pause deployed jobs, run and test jobs locally",
+	}
+	cmd.AddCommand(jobLogsCommand(l, conf))
+	cmd.AddCommand(jobStatsCommand(l, conf))
+	cmd.AddCommand(jobCostCommand(l, conf))
+	cmd.AddCommand(jobDependenciesCommand(l, conf))
+	cmd.AddCommand(jobListCommand(l, conf))
+	cmd.AddCommand(jobPauseCommand(l, conf, true))
+	cmd.AddCommand(jobPauseCommand(l, conf, false))
+	if jobSpecRepo != nil {
+		cmd.AddCommand(jobRunLocalCommand(l, conf, jobSpecRepo))
+		cmd.AddCommand(jobSimulateCommand(l, conf, jobSpecRepo))
+		cmd.AddCommand(jobTestCommand(l, conf, jobSpecRepo))
+		cmd.AddCommand(jobImportCommand(l, jobSpecRepo, jobSpecFs))
+		cmd.AddCommand(jobRenameCommand(l, conf, jobSpecRepo))
+	}
+	return cmd
+}
+
+// jobLogsCommand prints logs of task or hook of a job run fetched via the scheduler
+func jobLogsCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		date        string
+		hookName    string
+		attempt     int
+	)
+	cmd := &cli.Command{
+		Use:   "logs",
+		Short: "Show logs of the task or a hook of a job run",
+		Example: "optimus job logs <job_name> --project \"project-id\" --date 2021-05-20\n" +
+			"optimus job logs <job_name> --project \"project-id\" --date 2021-05-20T02:00:00Z --hook transporter --attempt 2",
+		Args: cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&date, "date", "", "scheduled time of run in RFC3339, or a date(YYYY-MM-DD) for the latest run of that day")
+	cmd.MarkFlagRequired("date")
+	cmd.Flags().StringVar(&hookName, "hook", "", "show logs of this hook instead of the task")
+	cmd.Flags().IntVar(&attempt, "attempt", 0, "attempt of the run, defaults to the latest one")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		if _, err := time.Parse(time.RFC3339, date); err != nil {
+			if _, err := time.Parse(models.JobDatetimeLayout, date); err != nil {
+				return errors.Errorf("invalid date %s, expected YYYY-MM-DD or RFC3339 time", date)
+			}
+		}
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("job", args[0])
+		params.Set("scheduled_at", date)
+		if hookName != "" {
+			params.Set("instance_type", string(models.InstanceTypeHook))
+			params.Set("instance", hookName)
+		}
+		if attempt > 0 {
+			params.Set("attempt", fmt.Sprintf("%d", attempt))
+		}
+
+		instanceLog, err := getInstanceLog(conf.GetHost(), params)
+		if err != nil {
+			return err
+		}
+		l.Println(coloredNotice(fmt.Sprintf("%s %s of job %s scheduled at %s, attempt %d", instanceLog.InstanceType,
+			instanceLog.InstanceName, instanceLog.JobName, instanceLog.ScheduledAt.Format(time.RFC3339), instanceLog.Attempt)))
+		l.Println(instanceLog.Content)
+		return nil
+	}
+	return cmd
+}
+
+// jobStatsCommand prints a summary of outcomes and durations of recent runs of a job
+func jobStatsCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		since       string
+	)
+	cmd := &cli.Command{
+		Use:   "stats",
+		Short: "Summarize outcomes and durations of recent runs of a job",
+		Example: "optimus job stats <job_name> --project \"project-id\"\n" +
+			"optimus job stats <job_name> --project \"project-id\" --since 7d",
+		Args: cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&since, "since", "30d", "summarize runs scheduled within this duration e.g. 30d or 12h")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("job", args[0])
+		params.Set("since", since)
+
+		stats, err := getJobStats(conf.GetHost(), params)
+		if err != nil {
+			return err
+		}
+		l.Println(coloredNotice(fmt.Sprintf("runs of job %s scheduled since %s", stats.JobName,
+			stats.Since.Format(time.RFC3339))))
+		l.Printf("runs: %d, succeeded: %d, failed: %d\n", stats.Runs, stats.Succeeded, stats.Failed)
+		if stats.Hung > 0 {
+			l.Printf("hung: %d, still running but missed heartbeats\n", stats.Hung)
+		}
+		if stats.Succeeded+stats.Failed == 0 {
+			return nil
+		}
+		l.Printf("success rate: %.1f%%\n", stats.SuccessRate*100)
+		if stats.Succeeded > 0 {
+			l.Printf("duration of successful runs, average: %s, p50: %s, p95: %s\n", stats.AverageDuration,
+				stats.P50Duration, stats.P95Duration)
+		}
+		l.Printf("failure streak, longest: %d, current: %d\n", stats.LongestFailureStreak, stats.CurrentFailureStreak)
+		if stats.MostFailingHour != nil {
+			l.Printf("most failures at: %02d:00-%02d:00 UTC\n", *stats.MostFailingHour, (*stats.MostFailingHour+1)%24)
+		}
+		return nil
+	}
+	return cmd
+}
+
+func getJobStats(host string, params url.Values) (v1handler.JobStatsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(jobLogsTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/job-stats?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.JobStatsResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.JobStatsResponse{}, errors.Wrap(err, "failed to fetch stats")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.JobStatsResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.JobStatsResponse{}, errors.Errorf("failed to fetch stats, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var stats v1handler.JobStatsResponse
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return v1handler.JobStatsResponse{}, errors.Wrap(err, "failed to decode stats")
+	}
+	return stats, nil
+}
+
+func getInstanceLog(host string, params url.Values) (v1handler.InstanceLogResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(jobLogsTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/logs?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.InstanceLogResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.InstanceLogResponse{}, errors.Wrap(err, "failed to fetch logs")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.InstanceLogResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.InstanceLogResponse{}, errors.Errorf("failed to fetch logs, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var instanceLog v1handler.InstanceLogResponse
+	if err := json.Unmarshal(body, &instanceLog); err != nil {
+		return v1handler.InstanceLogResponse{}, errors.Wrap(err, "failed to decode logs")
+	}
+	return instanceLog, nil
+}
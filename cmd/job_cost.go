@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	// cost of a project is summed over runs of all its jobs
+	jobCostTimeout = time.Minute
+)
+
+// jobCostCommand prints what recent runs of a job, or of every job of a
+// project, cost in the datastores their tasks ran jobs in
+func jobCostCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		since       string
+	)
+	cmd := &cli.Command{
+		Use:   "cost",
+		Short: "Show bytes billed and slot time of recent runs of a job or all jobs of a project",
+		Example: "optimus job cost <job_name> --project \"project-id\"\n" +
+			"optimus job cost --project \"project-id\" --since 7d",
+		Args: cli.MaximumNArgs(1),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&since, "since", "30d", "sum cost of runs scheduled within this duration e.g. 30d or 12h")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("since", since)
+		if len(args) > 0 {
+			params.Set("job", args[0])
+			var jobCost v1handler.JobCostResponse
+			if err := getJobCost(conf.GetHost(), params, &jobCost); err != nil {
+				return err
+			}
+			l.Println(coloredNotice(fmt.Sprintf("cost of runs of job %s scheduled since %s", jobCost.JobName,
+				jobCost.Since.Format(time.RFC3339))))
+			l.Printf("runs: %d, bytes billed: %s, slot time: %s\n", jobCost.Runs,
+				models.FormatBytes(jobCost.BytesBilled), time.Duration(jobCost.SlotMillis)*time.Millisecond)
+			return nil
+		}
+
+		var projectCost v1handler.ProjectCostResponse
+		if err := getJobCost(conf.GetHost(), params, &projectCost); err != nil {
+			return err
+		}
+		l.Println(coloredNotice(fmt.Sprintf("cost of runs of project %s scheduled since %s", projectCost.ProjectName,
+			projectCost.Since.Format(time.RFC3339))))
+		table := tablewriter.NewWriter(l.Writer())
+		table.SetBorder(false)
+		table.SetHeader([]string{"Job", "Namespace", "Runs", "Bytes billed", "Slot time"})
+		for _, jobCost := range projectCost.Jobs {
+			table.Append([]string{jobCost.JobName, jobCost.Namespace, fmt.Sprintf("%d", jobCost.Runs),
+				models.FormatBytes(jobCost.BytesBilled), (time.Duration(jobCost.SlotMillis) * time.Millisecond).String()})
+		}
+		table.SetFooter([]string{"Total", "", fmt.Sprintf("%d", projectCost.Runs),
+			models.FormatBytes(projectCost.BytesBilled), (time.Duration(projectCost.SlotMillis) * time.Millisecond).String()})
+		table.Render()
+		return nil
+	}
+	return cmd
+}
+
+func getJobCost(host string, params url.Values, cost interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(jobCostTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/job-costs?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch cost")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to fetch cost, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, cost); err != nil {
+		return errors.Wrap(err, "failed to decode cost")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	// assets of the job are compiled and its task plugin generates the
+	// sources it reads
+	jobDependenciesTimeout = time.Minute
+)
+
+// jobDependenciesCommand prints dependencies of a deployed job along with
+// why each of them is resolved
+func jobDependenciesCommand(l logger, conf config.Provider) *cli.Command {
+	var projectName string
+	cmd := &cli.Command{
+		Use:     "dependencies",
+		Short:   "Explain why a job depends on each of its upstream jobs, and sources no job writes",
+		Example: "optimus job dependencies <job_name> --project \"project-id\"",
+		Args:    cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("job", args[0])
+
+		deps, err := getJobDependencies(conf.GetHost(), params)
+		if err != nil {
+			return err
+		}
+		if len(deps.Dependencies) == 0 {
+			l.Printf("job %s has no dependencies\n", deps.JobName)
+			return nil
+		}
+		l.Println(coloredNotice(fmt.Sprintf("dependencies of job %s of namespace %s", deps.JobName, deps.Namespace)))
+		table := tablewriter.NewWriter(l.Writer())
+		table.SetBorder(false)
+		table.SetAutoWrapText(false)
+		table.SetHeader([]string{"Job", "Project", "Type", "Reason", "Explanation"})
+		for _, dep := range deps.Dependencies {
+			table.Append([]string{dep.Job, dep.Project, dep.Type, dep.Reason, dep.Explanation})
+		}
+		table.Render()
+		return nil
+	}
+	return cmd
+}
+
+func getJobDependencies(host string, params url.Values) (v1handler.JobDependenciesResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(jobDependenciesTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/job-dependencies?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.JobDependenciesResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.JobDependenciesResponse{}, errors.Wrap(err, "failed to fetch dependencies")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.JobDependenciesResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.JobDependenciesResponse{}, errors.Errorf("failed to fetch dependencies, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var deps v1handler.JobDependenciesResponse
+	if err := json.Unmarshal(body, &deps); err != nil {
+		return v1handler.JobDependenciesResponse{}, errors.Wrap(err, "failed to decode dependencies")
+	}
+	return deps, nil
+}
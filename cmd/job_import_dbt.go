@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/odpf/optimus/ext/dbt"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/local"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	cli "github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func jobImportCommand(l logger, jobSpecRepo JobSpecRepository, jobSpecFs afero.Fs) *cli.Command {
+	cmd := &cli.Command{
+		Use:   "import",
+		Short: "Import jobs from other tools",
+	}
+	cmd.AddCommand(jobImportDbtCommand(l, jobSpecRepo, jobSpecFs))
+	return cmd
+}
+
+// jobImportDbtCommand converts models of a dbt project into job specs of
+// bq2bq task written into the job path
+func jobImportDbtCommand(l logger, jobSpecRepo JobSpecRepository, jobSpecFs afero.Fs) *cli.Command {
+	var mappingFile string
+	cmd := &cli.Command{
+		Use:   "dbt",
+		Short: "Convert models of a dbt project into jobs",
+		Long: "Convert models of a dbt project materialized as tables or incrementally into jobs of bq2bq task,\n" +
+			"with refs and sources in queries replaced by the tables they resolve to and dependencies on jobs of\n" +
+			"the models they ref. Jobs are written into the job path in the directories models are in. dbt doesn't\n" +
+			"schedule models, so schedules along with the project and dataset models are written to are read from\n" +
+			"a mapping file. Jobs whose name is already used in the job path are left as is.",
+		Example: "optimus job import dbt ./shop-dbt --mapping dbt-mapping.yaml",
+		Args:    cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&mappingFile, "mapping", "", "file mapping models to schedules and destination")
+	cmd.MarkFlagRequired("mapping")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		rawMapping, err := ioutil.ReadFile(mappingFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read mapping %s", mappingFile)
+		}
+		mapping, err := dbt.ParseMapping(rawMapping)
+		if err != nil {
+			return err
+		}
+		project, err := dbt.LoadProject(afero.NewBasePathFs(afero.NewOsFs(), args[0]))
+		if err != nil {
+			return err
+		}
+		conversion, err := dbt.Convert(project, mapping)
+		if err != nil {
+			return err
+		}
+
+		var imported, existing int
+		for _, job := range conversion.Jobs {
+			_, err := jobSpecRepo.GetByName(job.Spec.Name)
+			if err == nil {
+				existing++
+				l.Printf("> job %s already exists, skipping\n", job.Spec.Name)
+				continue
+			}
+			if !errors.Is(err, models.ErrNoSuchSpec) {
+				return err
+			}
+			if err := writeImportedJob(jobSpecFs, job); err != nil {
+				return errors.Wrapf(err, "failed to write job %s", job.Spec.Name)
+			}
+			imported++
+			l.Printf("> imported job %s in %s\n", job.Spec.Name, job.Dir)
+		}
+		for _, warning := range conversion.Warnings {
+			l.Println(coloredNotice(fmt.Sprintf("> %s", warning)))
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("imported %d jobs from dbt project %s, %d already existed",
+			imported, project.Name, existing)))
+		return nil
+	}
+	return cmd
+}
+
+// writeImportedJob writes spec of job and its assets the same way job specs
+// are saved in a repository
+func writeImportedJob(fs afero.Fs, job dbt.Job) error {
+	assetDir := filepath.Join(job.Dir, local.AssetFolderName)
+	if err := fs.MkdirAll(assetDir, os.FileMode(0765)|os.ModeDir); err != nil {
+		return err
+	}
+	for name, asset := range job.Spec.Asset {
+		if err := afero.WriteFile(fs, filepath.Join(assetDir, name), []byte(asset), os.FileMode(0755)); err != nil {
+			return err
+		}
+	}
+	spec := job.Spec
+	spec.Asset = nil
+	specBytes, err := yaml.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, filepath.Join(job.Dir, local.JobSpecFileName), specBytes, os.FileMode(0755))
+}
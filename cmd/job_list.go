@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	jobListTimeout = time.Second * 30
+
+	// pausing calls the scheduler for every job
+	jobPauseTimeout = time.Minute * 2
+)
+
+// jobListCommand prints deployed jobs of a namespace, optionally only the
+// ones matching a label selector
+func jobListCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		namespace   string
+		selector    string
+	)
+	cmd := &cli.Command{
+		Use:   "list",
+		Short: "List deployed jobs of a namespace",
+		Example: "optimus job list --project \"project-id\" --namespace kafka\n" +
+			"optimus job list --project \"project-id\" --namespace kafka --selector tier=critical,team!=growth",
+		Args: cli.NoArgs,
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace of jobs")
+	cmd.MarkFlagRequired("namespace")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "list only jobs with labels matching the selector e.g. tier=critical")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		if _, err := models.ParseLabelSelector(selector); err != nil {
+			return err
+		}
+		jobs, err := listJobSpecifications(l, conf.GetHost(), projectName, namespace, selector)
+		if err != nil {
+			return err
+		}
+		if len(jobs) == 0 {
+			l.Println(coloredNotice("no jobs found"))
+			return nil
+		}
+		table := tablewriter.NewWriter(l.Writer())
+		table.SetBorder(false)
+		table.SetHeader([]string{"Job", "Task", "Owner", "Interval", "Labels"})
+		for _, job := range jobs {
+			table.Append([]string{job.GetName(), job.GetTaskName(), job.GetOwner(), job.GetInterval(),
+				formatLabels(job.GetLabels())})
+		}
+		table.Render()
+		return nil
+	}
+	return cmd
+}
+
+// jobPauseCommand pauses or resumes scheduling of deployed jobs, jobs are
+// either named as args or selected by their labels
+func jobPauseCommand(l logger, conf config.Provider, paused bool) *cli.Command {
+	var (
+		projectName string
+		namespace   string
+		selector    string
+	)
+	use, short, action := "pause", "Pause scheduling of deployed jobs", "paused"
+	if !paused {
+		use, short, action = "resume", "Resume scheduling of paused jobs", "resumed"
+	}
+	cmd := &cli.Command{
+		Use:   use,
+		Short: short,
+		Example: fmt.Sprintf("optimus job %s <job_name>... --project \"project-id\" --namespace kafka\n"+
+			"optimus job %s --project \"project-id\" --namespace kafka --selector tier=low", use, use),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace of jobs")
+	cmd.MarkFlagRequired("namespace")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", fmt.Sprintf("%s jobs with labels matching the selector e.g. tier=low", use))
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		if _, err := models.ParseLabelSelector(selector); err != nil {
+			return err
+		}
+		if len(args) == 0 && selector == "" {
+			return errors.New("jobs are required, either as args or by --selector")
+		}
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("namespace", namespace)
+		params.Set("paused", fmt.Sprintf("%t", paused))
+		if selector != "" {
+			params.Set("selector", selector)
+		}
+		for _, jobName := range args {
+			params.Add("job", jobName)
+		}
+
+		resp, err := postJobPause(conf.GetHost(), params)
+		if err != nil {
+			return err
+		}
+		if len(resp.Jobs) == 0 {
+			l.Println(coloredNotice("no jobs matched"))
+			return nil
+		}
+		var failed int
+		for _, job := range resp.Jobs {
+			if job.Error != "" {
+				failed++
+				l.Printf("%s: %s\n", coloredError(job.Name), job.Error)
+				continue
+			}
+			l.Printf("%s %s\n", job.Name, action)
+		}
+		if failed > 0 {
+			return errors.Errorf("failed to %s %d of %d jobs", use, failed, len(resp.Jobs))
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("%s %d jobs", action, len(resp.Jobs))))
+		return nil
+	}
+	return cmd
+}
+
+// listJobSpecifications fetches deployed jobs of namespace matching selector
+func listJobSpecifications(l logger, host, projectName, namespace, selector string) ([]*pb.JobSpecification, error) {
+	dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
+	defer dialCancel()
+
+	conn, err := createConnection(dialTimeoutCtx, host)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			l.Println("can't reach optimus service, timing out")
+		}
+		return nil, err
+	}
+	defer conn.Close()
+
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), callTimeout(jobListTimeout))
+	defer cancel()
+	if selector != "" {
+		timeoutCtx = metadata.AppendToOutgoingContext(timeoutCtx, v1handler.MetadataLabelSelector, selector)
+	}
+
+	runtime := pb.NewRuntimeServiceClient(conn)
+	var (
+		jobs      []*pb.JobSpecification
+		pageToken string
+	)
+	for {
+		var header metadata.MD
+		resp, err := runtime.ListJobSpecification(withPage(timeoutCtx, pageToken), &pb.ListJobSpecificationRequest{
+			ProjectName: projectName,
+			Namespace:   namespace,
+		}, grpc.Header(&header))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list jobs of namespace %s", namespace)
+		}
+		jobs = append(jobs, resp.GetJobs()...)
+		if pageToken = nextPageToken(header); pageToken == "" {
+			return jobs, nil
+		}
+	}
+}
+
+func postJobPause(host string, params url.Values) (v1handler.JobPauseResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(jobPauseTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s://%s/job-pause?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.JobPauseResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.JobPauseResponse{}, errors.Wrap(err, "failed to pause jobs")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.JobPauseResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.JobPauseResponse{}, errors.Errorf("failed to pause jobs, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var pauseResp v1handler.JobPauseResponse
+	if err := json.Unmarshal(body, &pauseResp); err != nil {
+		return v1handler.JobPauseResponse{}, errors.Wrap(err, "failed to decode pause response")
+	}
+	return pauseResp, nil
+}
+
+// formatLabels formats labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	// renaming syncs namespaces of the job and jobs depending on it
+	jobRenameTimeout = time.Minute * 5
+)
+
+// jobRenameCommand renames a job in local specs, rewriting dependencies of
+// other jobs on it, and on the server keeping its runs and checkpoints
+func jobRenameCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository) *cli.Command {
+	var (
+		projectName string
+		namespace   string
+		localOnly   bool
+	)
+	cmd := &cli.Command{
+		Use:   "rename",
+		Short: "Rename a job, rewriting dependencies of other jobs on it",
+		Long: "Rename a job in local specifications, moving its directory along if it is named after the job, " +
+			"and rewrite dependencies of other local jobs on it. The job is renamed on the server too, " +
+			"keeping its runs and checkpoints, and dependencies of deployed jobs on it are rewritten.",
+		Example: "optimus job rename <old_name> <new_name> --project \"project-id\" --namespace kafka\n" +
+			"optimus job rename <old_name> <new_name> --project \"project-id\" --namespace kafka --local-only",
+		Args: cli.ExactArgs(2),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace of the job")
+	cmd.MarkFlagRequired("namespace")
+	cmd.Flags().BoolVar(&localOnly, "local-only", false, "rename the job only in local specifications, "+
+		"e.g. after it was renamed on the server")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		oldName, newName := args[0], strings.TrimSpace(args[1])
+		if newName == "" || newName == oldName {
+			return errors.Errorf("invalid name %s to rename job %s to", newName, oldName)
+		}
+		// make sure local specs can be renamed before renaming the job on the server
+		if _, err := jobSpecRepo.GetByName(oldName); err != nil {
+			return errors.Wrapf(err, "failed to find job %s", oldName)
+		}
+		if _, err := jobSpecRepo.GetByName(newName); err == nil {
+			return errors.Errorf("job %s already exists", newName)
+		} else if !errors.Is(err, models.ErrNoSuchSpec) {
+			return err
+		}
+
+		if !localOnly {
+			params := url.Values{}
+			params.Set("project", projectName)
+			params.Set("namespace", namespace)
+			params.Set("job", oldName)
+			params.Set("name", newName)
+			resp, err := postJobRename(conf.GetHost(), params)
+			if err != nil {
+				return err
+			}
+			l.Println(coloredNotice(fmt.Sprintf("renamed job %s to %s on server", resp.Job, resp.Name)))
+			for _, dependent := range resp.Dependents {
+				l.Printf("rewrote dependencies of deployed job %s\n", dependent)
+			}
+		}
+
+		dependents, err := jobSpecRepo.Rename(oldName, newName)
+		if err != nil {
+			if !localOnly {
+				return errors.Wrap(err, "job is renamed on server but failed to rename local specifications, "+
+					"retry with --local-only")
+			}
+			return err
+		}
+		for _, dependent := range dependents {
+			l.Printf("rewrote dependencies of job %s\n", dependent)
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("renamed job %s to %s", oldName, newName)))
+		return nil
+	}
+	return cmd
+}
+
+func postJobRename(host string, params url.Values) (v1handler.JobRenameResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(jobRenameTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s://%s/job-rename?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.JobRenameResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.JobRenameResponse{}, errors.Wrap(err, "failed to rename job")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.JobRenameResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.JobRenameResponse{}, errors.Errorf("failed to rename job, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var renameResp v1handler.JobRenameResponse
+	if err := json.Unmarshal(body, &renameResp); err != nil {
+		return v1handler.JobRenameResponse{}, errors.Wrap(err, "failed to decode rename response")
+	}
+	return renameResp, nil
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/instance"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/utils"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	// directory of the container the instance context is mounted at,
+	// same as the one tasks get on the scheduler
+	runLocalJobDir = "/data"
+)
+
+// jobRunLocalCommand compiles context of a job run from local specifications
+// and runs the task image of the job with it in docker, without deploying
+func jobRunLocalCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository) *cli.Command {
+	var (
+		projectName string
+		namespace   string
+		date        string
+		outputDir   string
+		secretFile  string
+		skipPull    bool
+	)
+	cmd := &cli.Command{
+		Use:   "run-local",
+		Short: "Run the task of a job locally in docker for a scheduled time",
+		Example: "optimus job run-local <job_name> --project \"project-id\" --namespace kids --date 2021-05-20\n" +
+			"optimus job run-local <job_name> --project \"project-id\" --namespace kids --date 2021-05-20T02:00:00Z --secret-file ./auth.json",
+		Args: cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace of the job")
+	cmd.MarkFlagRequired("namespace")
+	cmd.Flags().StringVar(&date, "date", "", "scheduled time of run in RFC3339, or a date(YYYY-MM-DD) for the first run of that day")
+	cmd.MarkFlagRequired("date")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "directory compiled assets are written to, defaults to ./run/<job_name>")
+	cmd.Flags().StringVar(&secretFile, "secret-file", "", "file mounted at the secret path of the task plugin")
+	cmd.Flags().BoolVar(&skipPull, "skip-pull", false, "use the task image available locally instead of pulling it")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		jobSpec, err := jobSpecRepo.GetByName(args[0])
+		if err != nil {
+			return err
+		}
+		scheduledAt, err := parseRunLocalDate(date, jobSpec.Schedule.Interval)
+		if err != nil {
+			return err
+		}
+		namespaceSpec := models.NamespaceSpec{
+			Name:   namespace,
+			Config: conf.GetProjectConfig().Local,
+			ProjectSpec: models.ProjectSpec{
+				Name:   projectName,
+				Config: conf.GetProjectConfig().Global,
+			},
+		}
+		if outputDir == "" {
+			outputDir = filepath.Join(".", "run", jobSpec.Name)
+		}
+		if outputDir, err = filepath.Abs(outputDir); err != nil {
+			return err
+		}
+		l.Println("compiling context of", jobSpec.Name, "scheduled at", scheduledAt.Format(models.InstanceScheduledAtTimeLayout))
+
+		envs, err := writeRunLocalContext(l, namespaceSpec, jobSpec, scheduledAt, filepath.Join(outputDir, taskInputDirectory))
+		if err != nil {
+			return err
+		}
+
+		taskInfo := jobSpec.Task.Unit.Info()
+		if !skipPull {
+			l.Println("pulling", taskInfo.Image)
+			if err := runDocker("pull", taskInfo.Image); err != nil {
+				return errors.Wrapf(err, "failed to pull image %s", taskInfo.Image)
+			}
+		}
+
+		runArgs := []string{"run", "--rm", "-v", fmt.Sprintf("%s:%s", outputDir, runLocalJobDir)}
+		if secretFile != "" {
+			if taskInfo.SecretPath == "" {
+				return errors.Errorf("task %s does not use a secret", taskInfo.Name)
+			}
+			if secretFile, err = filepath.Abs(secretFile); err != nil {
+				return err
+			}
+			runArgs = append(runArgs, "-v", fmt.Sprintf("%s:%s:ro", secretFile, taskInfo.SecretPath))
+		}
+		for _, env := range runLocalEnvs(namespaceSpec, jobSpec, scheduledAt, envs) {
+			runArgs = append(runArgs, "-e", env)
+		}
+		runArgs = append(runArgs, taskInfo.Image)
+
+		l.Println(coloredNotice(fmt.Sprintf("running %s of job %s", taskInfo.Name, jobSpec.Name)))
+		if err := runDocker(runArgs...); err != nil {
+			return errors.Wrapf(err, "run of job %s failed", jobSpec.Name)
+		}
+		l.Println(coloredSuccess("run complete"))
+		return nil
+	}
+	return cmd
+}
+
+// parseRunLocalDate returns the scheduled time of the run, a date resolves
+// to the first run of the job on that day
+func parseRunLocalDate(date, interval string) (time.Time, error) {
+	if scheduledAt, err := time.Parse(time.RFC3339, date); err == nil {
+		return scheduledAt.UTC(), nil
+	}
+	day, err := time.Parse(models.JobDatetimeLayout, date)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid date %s, expected YYYY-MM-DD or RFC3339 time", date)
+	}
+	schedule, err := cron.ParseCronSchedule(interval)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse schedule interval %s", interval)
+	}
+	scheduledAt := schedule.Next(day.Add(-time.Second))
+	if !scheduledAt.Before(day.AddDate(0, 0, 1)) {
+		return time.Time{}, errors.Errorf("job has no run scheduled on %s", date)
+	}
+	return scheduledAt, nil
+}
+
+// writeRunLocalContext compiles the assets of a job run into the input
+// directory, the way the scheduler prepares them, and returns its envs.
+// Secrets are not resolved, templates referring to them fail to compile
+func writeRunLocalContext(l logger, namespace models.NamespaceSpec, jobSpec models.JobSpec, scheduledAt time.Time,
+	inputDirectory string) (map[string]string, error) {
+	envs, files, err := compileRunLocalContext(namespace, jobSpec, scheduledAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(inputDirectory, 0777); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory at %s", inputDirectory)
+	}
+	writeToFileFn := utils.WriteStringToFileIndexed()
+	for fileName, fileContent := range files {
+		filePath := filepath.Join(inputDirectory, fileName)
+		if err := writeToFileFn(filePath, fileContent, l.Writer()); err != nil {
+			return nil, errors.Wrapf(err, "failed to write asset file at %s", filePath)
+		}
+	}
+	envFileBlob := ""
+	for key, val := range envs {
+		envFileBlob += fmt.Sprintf("%s='%s'\n", key, val)
+	}
+	filePath := filepath.Join(inputDirectory, models.InstanceDataTypeEnvFileName)
+	if err := writeToFileFn(filePath, envFileBlob, l.Writer()); err != nil {
+		return nil, errors.Wrapf(err, "failed to write asset file at %s", filePath)
+	}
+	return envs, nil
+}
+
+// compileRunLocalContext compiles envs and assets of the task of a job run
+// the way the scheduler prepares them
+func compileRunLocalContext(namespace models.NamespaceSpec, jobSpec models.JobSpec, scheduledAt time.Time) (map[string]string, map[string]string, error) {
+	instanceSpec, err := instance.NewService(nil, func() time.Time {
+		return time.Now().UTC()
+	}, templateEngine, nil, nil).PrepInstance(jobSpec, scheduledAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	envs, files, err := instance.NewContextManager(namespace, jobSpec, templateEngine, nil).
+		Generate(instanceSpec, models.InstanceTypeTask, jobSpec.Task.Unit.Info().Name)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to compile context")
+	}
+	return envs, files, nil
+}
+
+// runLocalEnvs are the envs task container gets on the scheduler along with
+// the compiled ones, sorted for a stable command line
+func runLocalEnvs(namespace models.NamespaceSpec, jobSpec models.JobSpec, scheduledAt time.Time,
+	compiled map[string]string) []string {
+	envs := map[string]string{
+		"JOB_NAME":      jobSpec.Name,
+		"JOB_LABELS":    jobSpec.GetLabelsAsString(),
+		"JOB_DIR":       runLocalJobDir,
+		"PROJECT":       namespace.ProjectSpec.Name,
+		"NAMESPACE":     namespace.Name,
+		"INSTANCE_TYPE": string(models.InstanceTypeTask),
+		"INSTANCE_NAME": jobSpec.Task.Unit.Info().Name,
+		"SCHEDULED_AT":  scheduledAt.Format(models.InstanceScheduledAtTimeLayout),
+	}
+	for key, val := range compiled {
+		envs[key] = val
+	}
+	pairs := make([]string, 0, len(envs))
+	for key, val := range envs {
+		pairs = append(pairs, key+"="+val)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+func runDocker(args ...string) error {
+	dockerCmd := exec.Command("docker", args...)
+	dockerCmd.Stdin = os.Stdin
+	dockerCmd.Stdout = os.Stdout
+	dockerCmd.Stderr = os.Stderr
+	return dockerCmd.Run()
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+// jobSimulateCommand lists runs the schedule of a local job produces in a
+// range along with the window of each run, without deploying it
+func jobSimulateCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository) *cli.Command {
+	var (
+		projectName string
+		from        string
+		to          string
+	)
+	cmd := &cli.Command{
+		Use:   "simulate",
+		Short: "List runs the schedule of a job produces in a range with the window of each run",
+		Long: "List runs the schedule of a job produces in a range with the window of each run.\n" +
+			"Dates of the range are whole days, the day of --to included, or RFC3339 times.\n" +
+			"Runs skipped or added by schedule exceptions are marked, calendars are read from project config.",
+		Example: "optimus job simulate <job_name> --from 2021-05-01 --to 2021-05-31\n" +
+			"optimus job simulate <job_name> --from 2021-05-01T00:00:00Z --to 2021-05-02T12:00:00Z",
+		Args: cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.Flags().StringVar(&from, "from", "", "start of range, a date(YYYY-MM-DD) or RFC3339 time")
+	cmd.MarkFlagRequired("from")
+	cmd.Flags().StringVar(&to, "to", "", "end of range, a date(YYYY-MM-DD) or RFC3339 time")
+	cmd.MarkFlagRequired("to")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		jobSpec, err := jobSpecRepo.GetByName(args[0])
+		if err != nil {
+			return err
+		}
+		start, _, err := parseSimulateDate(from)
+		if err != nil {
+			return err
+		}
+		end, isDate, err := parseSimulateDate(to)
+		if err != nil {
+			return err
+		}
+		// end of range is inclusive
+		if isDate {
+			end = end.AddDate(0, 0, 1)
+		} else {
+			end = end.Add(time.Second)
+		}
+		if !start.Before(end) {
+			return errors.New("start of range should be before its end")
+		}
+		projSpec := models.ProjectSpec{
+			Name:   projectName,
+			Config: conf.GetProjectConfig().Global,
+		}
+
+		runs, err := job.SimulateRuns(jobSpec, projSpec, start, end)
+		if err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			l.Println(coloredNotice("no runs scheduled in range"))
+			return nil
+		}
+		table := tablewriter.NewWriter(l.Writer())
+		table.SetBorder(false)
+		table.SetHeader([]string{"Scheduled At", "DSTART", "DEND", "Note"})
+		scheduled := 0
+		for _, run := range runs {
+			note := ""
+			switch {
+			case run.Skipped:
+				note = "skipped by exception"
+			case run.Extra:
+				note = "extra run"
+			}
+			if !run.Skipped {
+				scheduled++
+			}
+			table.Append([]string{
+				run.ScheduledAt.Format(models.InstanceScheduledAtTimeLayout),
+				run.WindowStart.Format(models.InstanceScheduledAtTimeLayout),
+				run.WindowEnd.Format(models.InstanceScheduledAtTimeLayout),
+				note,
+			})
+		}
+		table.Render()
+		l.Printf("%d runs of %s with interval %s and window of size %s, offset %s, truncated to %s\n", scheduled,
+			jobSpec.Name, jobSpec.Schedule.Interval, jobSpec.Task.Window.SizeString(), jobSpec.Task.Window.OffsetString(),
+			jobSpec.Task.Window.TruncateTo)
+		return nil
+	}
+	return cmd
+}
+
+// parseSimulateDate parses a boundary of simulated range, telling if it is a
+// date rather than a time
+func parseSimulateDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), false, nil
+	}
+	day, err := time.Parse(models.JobDatetimeLayout, value)
+	if err != nil {
+		return time.Time{}, false, errors.Errorf("invalid date %s, expected YYYY-MM-DD or RFC3339 time", value)
+	}
+	return day, true, nil
+}
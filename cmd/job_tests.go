@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/ext/sqltest"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+	"google.golang.org/api/option"
+)
+
+// jobTestCommand runs test cases declared in the spec of a job, the query
+// compiled for the run of each test is run in bigquery against its fixtures
+func jobTestCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository) *cli.Command {
+	var (
+		projectName string
+		namespace   string
+		gcpProject  string
+		secretFile  string
+		testName    string
+	)
+	cmd := &cli.Command{
+		Use:   "test",
+		Short: "Run test cases of the query of a job against fixtures in bigquery",
+		Long: "Run test cases declared in tests of a job spec. For each test, assets are compiled for its run and\n" +
+			"the query is run with the tables it reads replaced by fixtures loaded into a temporary dataset,\n" +
+			"its output is compared to the rows expected. Datasets are deleted once tests are done.",
+		Example: "optimus job test <job_name> --project \"project-id\" --namespace kids --gcp-project shop-sandbox\n" +
+			"optimus job test <job_name> --project \"project-id\" --namespace kids --gcp-project shop-sandbox --test daily_revenue",
+		Args: cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVar(&projectName, "project", "", "name of the tenant")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace of the job")
+	cmd.MarkFlagRequired("namespace")
+	cmd.Flags().StringVar(&gcpProject, "gcp-project", "", "gcp project temporary datasets are created and queries run in")
+	cmd.MarkFlagRequired("gcp-project")
+	cmd.Flags().StringVar(&secretFile, "secret-file", "", "service account key used with bigquery, application default credentials if empty")
+	cmd.Flags().StringVar(&testName, "test", "", "run only the test of this name")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		jobSpec, err := jobSpecRepo.GetByName(args[0])
+		if err != nil {
+			return err
+		}
+		var tests []models.JobSpecTest
+		for _, test := range jobSpec.Tests {
+			if testName == "" || test.Name == testName {
+				tests = append(tests, test)
+			}
+		}
+		if len(tests) == 0 {
+			if testName != "" {
+				return errors.Errorf("job %s has no test %s", jobSpec.Name, testName)
+			}
+			return errors.Errorf("job %s declares no tests", jobSpec.Name)
+		}
+		namespaceSpec := models.NamespaceSpec{
+			Name:   namespace,
+			Config: conf.GetProjectConfig().Local,
+			ProjectSpec: models.ProjectSpec{
+				Name:   projectName,
+				Config: conf.GetProjectConfig().Global,
+			},
+		}
+
+		ctx := context.Background()
+		var opts []option.ClientOption
+		if secretFile != "" {
+			opts = append(opts, option.WithCredentialsFile(secretFile))
+		}
+		client, err := bigquery.NewClient(ctx, gcpProject, opts...)
+		if err != nil {
+			return errors.Wrap(err, "failed to create bigquery client")
+		}
+		defer client.Close()
+		runner := &sqltest.Runner{Client: bqiface.AdaptClient(client), Project: gcpProject}
+
+		var failed int
+		for _, test := range tests {
+			scheduledAt, err := jobTestScheduledAt(test, jobSpec)
+			if err != nil {
+				return errors.Wrapf(err, "invalid run of test %s", test.Name)
+			}
+			_, files, err := compileRunLocalContext(namespaceSpec, jobSpec, scheduledAt)
+			if err != nil {
+				return err
+			}
+			query, ok := files[sqltest.QueryAssetName]
+			if !ok {
+				return errors.Errorf("job %s has no %s asset to test", jobSpec.Name, sqltest.QueryAssetName)
+			}
+
+			l.Println(coloredNotice(fmt.Sprintf("running test %s for run at %s", test.Name,
+				scheduledAt.Format(models.InstanceScheduledAtTimeLayout))))
+			result, err := runner.Run(ctx, query, test)
+			if err != nil {
+				return errors.Wrapf(err, "failed to run test %s", test.Name)
+			}
+			if result.Passed() {
+				l.Println(coloredSuccess(fmt.Sprintf("> %s passed", test.Name)))
+				continue
+			}
+			failed++
+			l.Println(coloredError(fmt.Sprintf("> %s failed", test.Name)))
+			for _, failure := range result.Failures {
+				l.Printf("  - %s\n", failure)
+			}
+		}
+		if failed > 0 {
+			return errors.Errorf("%d of %d tests of job %s failed", failed, len(tests), jobSpec.Name)
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("all %d tests of job %s passed", len(tests), jobSpec.Name)))
+		return nil
+	}
+	return cmd
+}
+
+// jobTestScheduledAt is the run assets of a test are compiled for, the first
+// run of job unless the test sets one
+func jobTestScheduledAt(test models.JobSpecTest, jobSpec models.JobSpec) (time.Time, error) {
+	if test.ScheduledAt != "" {
+		return parseRunLocalDate(test.ScheduledAt, jobSpec.Schedule.Interval)
+	}
+	schedule, err := cron.ParseCronSchedule(jobSpec.Schedule.Interval)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse schedule interval %s", jobSpec.Schedule.Interval)
+	}
+	return schedule.Next(jobSpec.Schedule.StartDate.Add(-time.Second)), nil
+}
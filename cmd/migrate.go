@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/odpf/optimus/cmd/server"
+	"github.com/odpf/optimus/config"
+
+	cli "github.com/spf13/cobra"
+)
+
+func migrateCommand(l logger, conf config.Provider) *cli.Command {
+	c := &cli.Command{
+		Use:   "migrate",
+		Short: "Migrate database of optimus service",
+		Long: "Runs migrations of the database in serve config, meant for init containers of servers " +
+			"started with serve.db.auto_migrate disabled. Migrations run one at a time and are skipped " +
+			"if the database is already migrated",
+		RunE: func(c *cli.Command, args []string) error {
+			if err := server.Migrate(conf); err != nil {
+				return err
+			}
+			l.Println("database migrated")
+			return nil
+		},
+	}
+	return c
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	operationRequestTimeout = time.Second * 30
+	operationPollInterval   = time.Second * 10
+)
+
+// operationCommand follows long running operations, like restores and
+// promotions, which optimus service runs in background
+func operationCommand(l logger, conf config.Provider) *cli.Command {
+	cmd := &cli.Command{
+		Use:   "operation",
+		Short: "Follow and cancel long running operations of a project",
+	}
+	cmd.AddCommand(operationStatusCommand(l, conf))
+	cmd.AddCommand(operationListCommand(l, conf))
+	cmd.AddCommand(operationCancelCommand(l, conf))
+	return cmd
+}
+
+func operationStatusCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		follow      bool
+	)
+	cmd := &cli.Command{
+		Use:     "status",
+		Short:   "Get status of an operation",
+		Example: "optimus operation status <operation_id> --project \"project-id\" --follow",
+		Args:    cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&projectName, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "follow operation till it is over")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("id", args[0])
+		var op v1handler.OperationResponse
+		if err := operationRequest(conf.GetHost(), http.MethodGet, "/operations", params, nil, &op); err != nil {
+			return err
+		}
+		l.Printf("%s operation %s requested by %s at %s\n", op.Kind, op.ID, op.Actor, op.CreatedAt.Format(time.RFC3339))
+		if follow {
+			var err error
+			if op, err = followOperation(l, conf.GetHost(), projectName, op); err != nil {
+				return err
+			}
+		}
+		if !op.IsDone() {
+			l.Println("status:", coloredNotice(op.Status), operationProgress(op))
+			return nil
+		}
+		if len(op.Result) > 0 {
+			l.Println("result:", string(op.Result))
+		}
+		return printOperationOutcome(l, op)
+	}
+	return cmd
+}
+
+func operationListCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName string
+		kind        string
+		limit       int
+	)
+	cmd := &cli.Command{
+		Use:     "list",
+		Short:   "List latest operations",
+		Example: "optimus operation list --project \"project-id\" --kind promotion",
+	}
+	cmd.Flags().StringVarP(&projectName, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&kind, "kind", "", "only list operations of this kind, e.g. promotion, resource_restore")
+	cmd.Flags().IntVar(&limit, "limit", 20, "number of operations to list")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("limit", strconv.Itoa(limit))
+		if kind != "" {
+			params.Set("kind", kind)
+		}
+		var ops []v1handler.OperationResponse
+		if err := operationRequest(conf.GetHost(), http.MethodGet, "/operations", params, nil, &ops); err != nil {
+			return err
+		}
+		if len(ops) == 0 {
+			l.Println(coloredNotice(fmt.Sprintf("no operations found for project %s", projectName)))
+			return nil
+		}
+		table := tablewriter.NewWriter(l.Writer())
+		table.SetBorder(false)
+		table.SetHeader([]string{
+			"ID",
+			"Kind",
+			"Actor",
+			"Status",
+			"Progress",
+			"Created At",
+		})
+		for _, op := range ops {
+			table.Append([]string{
+				op.ID,
+				op.Kind,
+				op.Actor,
+				op.Status,
+				operationProgress(op),
+				op.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		table.Render()
+		return nil
+	}
+	return cmd
+}
+
+func operationCancelCommand(l logger, conf config.Provider) *cli.Command {
+	var projectName string
+	cmd := &cli.Command{
+		Use:     "cancel",
+		Short:   "Cancel a running operation",
+		Example: "optimus operation cancel <operation_id> --project \"project-id\"",
+		Args:    cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&projectName, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", projectName)
+		params.Set("id", args[0])
+		if err := operationRequest(conf.GetHost(), http.MethodDelete, "/operations", params, nil, nil); err != nil {
+			return err
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("operation %s is being cancelled", args[0])))
+		return nil
+	}
+	return cmd
+}
+
+// followOperation polls op till it is over, printing its status whenever
+// it changes
+func followOperation(l logger, host, projectName string, op v1handler.OperationResponse) (v1handler.OperationResponse, error) {
+	params := url.Values{}
+	params.Set("project", projectName)
+	params.Set("id", op.ID)
+	last := ""
+	for !op.IsDone() {
+		time.Sleep(operationPollInterval)
+		if err := operationRequest(host, http.MethodGet, "/operations", params, nil, &op); err != nil {
+			return op, err
+		}
+		if status := fmt.Sprintf("%s %s", op.Status, operationProgress(op)); status != last {
+			l.Println("status:", coloredNotice(op.Status), operationProgress(op))
+			last = status
+		}
+	}
+	return op, nil
+}
+
+func operationProgress(op v1handler.OperationResponse) string {
+	if op.Progress.Total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", op.Progress.Done, op.Progress.Total)
+}
+
+func printOperationOutcome(l logger, op v1handler.OperationResponse) error {
+	if op.Status == models.OperationStatusSucceeded {
+		l.Println(coloredSuccess(op.Kind + " succeeded"))
+		return nil
+	}
+	l.Println(coloredError(fmt.Sprintf("%s %s: %s", op.Kind, op.Status, op.Message)))
+	return errors.Errorf("operation %s %s", op.ID, op.Status)
+}
+
+// operationRequest calls path of optimus service which starts or serves
+// operations, decoding the response into out if it is not nil
+func operationRequest(host, method, path string, params url.Values, body io.Reader, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(operationRequestTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s://%s%s?%s", httpScheme(), host, path, params.Encode()), body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if actor := auditActor(); actor != "" {
+		req.Header.Set(v1handler.MetadataActor, actor)
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to request %s", path)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return errors.Errorf("failed to request %s, status: %d, response: %s", path, resp.StatusCode, string(respBody))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return errors.Wrapf(err, "failed to decode response of %s", path)
+	}
+	return nil
+}
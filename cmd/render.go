@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/odpf/optimus/config"
 	"github.com/odpf/optimus/instance"
 	"github.com/odpf/optimus/models"
 
@@ -22,19 +23,19 @@ var (
 	templateEngine = instance.NewGoEngine()
 )
 
-func renderCommand(l logger, host string, jobSpecRepo JobSpecRepository) *cli.Command {
+func renderCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository) *cli.Command {
 	cmd := &cli.Command{
 		Use:   "render",
 		Short: "convert raw representation of specification to consumables",
 	}
 	if jobSpecRepo != nil {
-		cmd.AddCommand(renderTemplateCommand(l, jobSpecRepo))
+		cmd.AddCommand(renderTemplateCommand(l, conf, jobSpecRepo))
 	}
-	cmd.AddCommand(renderJobCommand(l, host))
+	cmd.AddCommand(renderJobCommand(l, conf.GetHost()))
 	return cmd
 }
 
-func renderTemplateCommand(l logger, jobSpecRepo JobSpecRepository) *cli.Command {
+func renderTemplateCommand(l logger, conf config.Provider, jobSpecRepo JobSpecRepository) *cli.Command {
 	cmd := &cli.Command{
 		Use:     "template",
 		Short:   "render templates for a job to current 'render' directory",
@@ -64,7 +65,10 @@ func renderTemplateCommand(l logger, jobSpecRepo JobSpecRepository) *cli.Command
 		now := time.Now()
 		l.Println("assuming execution time as current time of", now.Format(models.InstanceScheduledAtTimeLayout))
 
-		templates, err := instance.DumpAssets(jobSpec, now, templateEngine, true)
+		projectSpec := models.ProjectSpec{
+			Config: conf.GetProjectConfig().Global,
+		}
+		templates, err := instance.DumpAssets(projectSpec, jobSpec, now, templateEngine, true)
 		if err != nil {
 			return err
 		}
@@ -118,7 +122,7 @@ func renderJobSpecificationBuildRequest(l logger, projectName, namespace, jobNam
 	}
 	defer conn.Close()
 
-	dumpTimeoutCtx, dumpCancel := context.WithTimeout(context.Background(), renderTimeout)
+	dumpTimeoutCtx, dumpCancel := context.WithTimeout(context.Background(), callTimeout(renderTimeout))
 	defer dumpCancel()
 
 	l.Println("please wait...")
@@ -2,7 +2,12 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,17 +15,27 @@ import (
 
 	"github.com/odpf/optimus/core/set"
 
+	v1handler "github.com/odpf/optimus/api/handler/v1"
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
 	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
 	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
 	cli "github.com/spf13/cobra"
 	"github.com/xlab/treeprint"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 var (
 	replayTimeout = time.Minute * 1
+
+	// backoff between attempts of submitting a replay while waiting
+	replayWaitInitialBackoff = time.Second * 5
+	replayWaitMaxBackoff     = time.Minute * 1
 )
 
 type taskRunBlock struct {
@@ -41,7 +56,7 @@ func taskRunBlockComperator(a, b interface{}) int {
 	return strings.Compare(aAsserted.name, bAsserted.name)
 }
 
-//formatRunsPerJobInstance returns a hashmap with Job -> Runs[] mapping
+// formatRunsPerJobInstance returns a hashmap with Job -> Runs[] mapping
 func formatRunsPerJobInstance(instance *pb.ReplayExecutionTreeNode, taskReruns map[string]taskRunBlock, height int) {
 	if _, ok := taskReruns[instance.JobName]; !ok {
 		taskReruns[instance.JobName] = taskRunBlock{
@@ -66,31 +81,73 @@ func replayCommand(l logger, conf config.Provider) *cli.Command {
 		Long:  `Backfill etl job and all of its downstream dependencies`,
 	}
 	cmd.AddCommand(replayRunSubCommand(l, conf))
+	cmd.AddCommand(replayStatusSubCommand(l, conf))
+	cmd.AddCommand(replayPlanSubCommand(l, conf))
+	cmd.AddCommand(replayPlanStatusSubCommand(l, conf))
+	cmd.AddCommand(replayCancelSubCommand(l, conf))
 	return cmd
 }
 
 func replayRunSubCommand(l logger, conf config.Provider) *cli.Command {
 	dryRun := false
 	forceRun := false
+	ignoreUpstream := false
 	var (
 		replayProject string
 		namespace     string
+		wait          bool
+		waitTimeout   time.Duration
+		selector      string
+		lastRuns      int
+		sinceSuccess  bool
+		preset        string
 	)
 
 	reCmd := &cli.Command{
-		Use:     "run",
-		Short:   "run replay operation on a dag based on provided date range",
-		Example: "optimus replay run optimus.dag.name 2020-02-03 2020-02-05",
+		Use:   "run",
+		Short: "run replay operation on a dag based on provided date range",
+		Example: "optimus replay run optimus.dag.name 2020-02-03 2020-02-05\n" +
+			"optimus replay run optimus.dag.name 2020-02-03T05:00:00Z 2020-02-03T09:00:00Z\n" +
+			"optimus replay run optimus.dag.name 2020-02-03 2020-02-05 --wait --wait-timeout 1h\n" +
+			"optimus replay run optimus.dag.name --last-runs 3\n" +
+			"optimus replay run optimus.dag.name --since-last-success\n" +
+			"optimus replay run optimus.dag.name --preset last_week\n" +
+			"optimus replay run optimus.dag.name 2020-02-03 2020-02-05 --ignore-upstream",
 		Long: `
 This operation takes three arguments, first is DAG name[required]
 used in optimus specification, second is start date[required] of
 replay, third is end date[optional] of replay. 
+Dates are either YYYY-MM-DD or RFC3339 times of scheduled runs.
 ReplayDryRun date ranges are inclusive.
+Instead of dates, --last-runs replays the latest runs of the DAG
+and --since-last-success the runs after its last successful one,
+the server resolves them against run history and schedule.
+With --preset, a replay preset of the DAG defined in its spec is
+replayed, presets in chunks are replayed one chunk after another.
+With --wait, submission is retried with backoff while the replay
+queue of the server is full or runs of the jobs are active.
+With --selector, only dependents with labels matching the selector
+are replayed, others are left out along with their downstream.
+With --ignore-upstream, sensors of the cleared runs waiting for jobs
+not being replayed are marked as succeeded, for upstream data known
+to be good whose runs were fixed outside optimus.
 		`,
 		Args: func(cmd *cli.Command, args []string) error {
 			if len(args) < 1 {
 				return errors.New("dag name is required")
 			}
+			windows := 0
+			for _, given := range []bool{len(args) > 1, lastRuns > 0, sinceSuccess, preset != ""} {
+				if given {
+					windows++
+				}
+			}
+			if windows > 1 {
+				return errors.New("replay dates, --last-runs, --since-last-success and --preset are exclusive")
+			}
+			if lastRuns > 0 || sinceSuccess || preset != "" {
+				return nil
+			}
 			if len(args) < 2 {
 				return errors.New("replay start date is required")
 			}
@@ -103,13 +160,34 @@ ReplayDryRun date ranges are inclusive.
 	reCmd.Flags().StringVarP(&namespace, "namespace", "n", "", "namespace of deployee")
 	reCmd.MarkFlagRequired("namespace")
 	reCmd.Flags().BoolVarP(&forceRun, "force", "f", forceRun, "run replay even if a previous run is in progress")
+	reCmd.Flags().BoolVar(&ignoreUpstream, "ignore-upstream", ignoreUpstream, "mark sensors of cleared runs waiting for jobs not being replayed as succeeded")
+	reCmd.Flags().BoolVar(&wait, "wait", false, "keep retrying with backoff while the replay queue is full or conflicting runs are active")
+	reCmd.Flags().DurationVar(&waitTimeout, "wait-timeout", time.Minute*30, "give up waiting after this duration")
+	reCmd.Flags().StringVarP(&selector, "selector", "l", "", "replay only dependents with labels matching the selector e.g. tier=critical")
+	reCmd.Flags().IntVar(&lastRuns, "last-runs", 0, "replay this many latest runs of the dag instead of dates")
+	reCmd.Flags().BoolVar(&sinceSuccess, "since-last-success", false, "replay runs of the dag after its last successful one instead of dates")
+	reCmd.Flags().StringVar(&preset, "preset", "", "replay a preset defined in spec of the dag instead of dates")
 
 	reCmd.RunE = func(cmd *cli.Command, args []string) error {
-		endDate := args[1]
-		if len(args) >= 3 {
-			endDate = args[2]
+		var startDate, endDate string
+		switch {
+		case lastRuns > 0:
+			startDate = fmt.Sprintf("%s%d", job.ReplayLastRunsPrefix, lastRuns)
+		case sinceSuccess:
+			startDate = job.ReplaySinceLastSuccess
+		case preset != "":
+			// window of preset is resolved by the server
+		default:
+			startDate, endDate = args[1], args[1]
+			if len(args) >= 3 {
+				endDate = args[2]
+			}
 		}
-		if err := printReplayExecutionTree(l, replayProject, namespace, args[0], args[1], endDate, conf); err != nil {
+		if _, err := models.ParseLabelSelector(selector); err != nil {
+			return err
+		}
+		chunks, err := printReplayExecutionTree(l, replayProject, namespace, args[0], startDate, endDate, selector, preset, conf)
+		if err != nil {
 			return err
 		}
 		if dryRun {
@@ -131,17 +209,135 @@ ReplayDryRun date ranges are inclusive.
 			return nil
 		}
 
-		replayId, err := runReplayRequest(l, replayProject, namespace, args[0], args[1], endDate, conf, forceRun)
+		if len(chunks) > 0 {
+			return runReplayChunks(l, replayProject, namespace, args[0], chunks, selector, preset, conf, forceRun,
+				ignoreUpstream, waitTimeout)
+		}
+
+		var waitUntil time.Time
+		if wait {
+			waitUntil = time.Now().Add(waitTimeout)
+		}
+		replayId, err := runReplayRequest(l, replayProject, namespace, args[0], startDate, endDate, selector, preset, conf,
+			forceRun, ignoreUpstream, waitUntil)
 		if err != nil {
 			return err
 		}
 		l.Printf("🚀 replay request created with id %s\n", replayId)
+		l.Printf("check its status with: optimus replay status %s --project %s --job %s\n", replayId, replayProject, args[0])
 		return nil
 	}
 	return reCmd
 }
 
-func printReplayExecutionTree(l logger, projectName, namespace, jobName, startDate, endDate string, conf config.Provider) (err error) {
+// replayStatusSubCommand prints status of a replay, along with its position
+// in the queue while it waits for a worker
+func replayStatusSubCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		replayProject string
+		jobName       string
+	)
+	cmd := &cli.Command{
+		Use:     "status",
+		Short:   "get status of a replay, and its position in queue if it is yet to start",
+		Example: "optimus replay status <replay_id> --project \"project-id\" --job optimus.dag.name",
+		Args:    cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&replayProject, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVarP(&jobName, "job", "j", "", "name of the job replayed")
+	cmd.MarkFlagRequired("job")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", replayProject)
+		params.Set("job", jobName)
+		params.Set("id", args[0])
+		replay, err := getReplayStatus(conf.GetHost(), params)
+		if err != nil {
+			return err
+		}
+		l.Printf("replay %s of job %s from %s to %s, created at %s\n", replay.ID, replay.JobName,
+			replay.StartDate.Format(time.RFC3339), replay.EndDate.Format(time.RFC3339), replay.CreatedAt.Format(time.RFC3339))
+		l.Println("status:", coloredNotice(replay.Status))
+		if replay.Message != "" {
+			l.Println("message:", replay.Message)
+		}
+		if replay.QueuePosition > 0 {
+			waiting := fmt.Sprintf("waiting for a worker at position %d in queue", replay.QueuePosition)
+			if replay.QueueETA != "" {
+				waiting += fmt.Sprintf(", expected to start in %s", replay.QueueETA)
+			}
+			l.Println(waiting)
+		}
+		printReplayJobs(l, replay)
+		return nil
+	}
+	return cmd
+}
+
+func getReplayStatus(host string, params url.Values) (v1handler.ReplayStatusResponse, error) {
+	return requestReplayStatus(host, http.MethodGet, "/replay-status", params)
+}
+
+// requestReplayStatus requests path serving status of a replay or a plan,
+// replays are cancelled with a DELETE
+func requestReplayStatus(host, method, path string, params url.Values) (v1handler.ReplayStatusResponse, error) {
+	action := "fetch replay status"
+	if method == http.MethodDelete {
+		action = "cancel replay"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(replayTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s://%s%s?%s", httpScheme(), host, path, params.Encode()), nil)
+	if err != nil {
+		return v1handler.ReplayStatusResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.ReplayStatusResponse{}, errors.Wrap(err, "failed to "+action)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.ReplayStatusResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.ReplayStatusResponse{}, errors.Errorf("failed to %s, status: %d, response: %s", action, resp.StatusCode, string(body))
+	}
+	var replay v1handler.ReplayStatusResponse
+	if err := json.Unmarshal(body, &replay); err != nil {
+		return v1handler.ReplayStatusResponse{}, errors.Wrap(err, "failed to decode replay status")
+	}
+	return replay, nil
+}
+
+// printReplayJobs prints status of each job of a replay of more than one
+// job or a plan
+func printReplayJobs(l logger, replay v1handler.ReplayStatusResponse) {
+	if len(replay.Jobs) == 0 {
+		return
+	}
+	l.Printf("%d of %d jobs ended\n", replay.JobsEnded, len(replay.Jobs))
+	table := tablewriter.NewWriter(l.Writer())
+	table.SetBorder(false)
+	table.SetHeader([]string{"Index", "Job", "Replay", "Status", "Message"})
+	for idx, jobReplay := range replay.Jobs {
+		status := jobReplay.Status
+		if jobReplay.QueuePosition > 0 {
+			status += fmt.Sprintf(" (queued at %d)", jobReplay.QueuePosition)
+		}
+		table.Append([]string{fmt.Sprintf("%d", idx+1), jobReplay.JobName, jobReplay.ID, status, jobReplay.Message})
+	}
+	table.Render()
+}
+
+// printReplayExecutionTree prints the runs a replay would clear, returning
+// the chunks a preset replayed in chunks is split into by the server
+func printReplayExecutionTree(l logger, projectName, namespace, jobName, startDate, endDate, selector, preset string,
+	conf config.Provider) (chunks []string, err error) {
 	dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
 	defer dialCancel()
 
@@ -150,12 +346,13 @@ func printReplayExecutionTree(l logger, projectName, namespace, jobName, startDa
 		if errors.Is(err, context.DeadlineExceeded) {
 			l.Println("can't reach optimus service")
 		}
-		return err
+		return nil, err
 	}
 	defer conn.Close()
 
-	replayRequestTimeout, replayRequestCancel := context.WithTimeout(context.Background(), replayTimeout)
+	replayRequestTimeout, replayRequestCancel := context.WithTimeout(context.Background(), callTimeout(replayTimeout))
 	defer replayRequestCancel()
+	replayRequestTimeout = replayRequestContext(replayRequestTimeout, selector, preset)
 
 	l.Println("please wait...")
 	runtime := pb.NewRuntimeServiceClient(conn)
@@ -166,16 +363,55 @@ func printReplayExecutionTree(l logger, projectName, namespace, jobName, startDa
 		StartDate:   startDate,
 		EndDate:     endDate,
 	}
-	replayDryRunResponse, err := runtime.ReplayDryRun(replayRequestTimeout, replayRequest)
+	var header metadata.MD
+	replayDryRunResponse, err := runtime.ReplayDryRun(replayRequestTimeout, replayRequest, grpc.Header(&header))
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			l.Println("replay dry run took too long, timing out")
 		}
-		return errors.Wrapf(err, "request failed for job %s", jobName)
+		return nil, errors.Wrapf(err, "request failed for job %s", jobName)
 	}
 
 	printReplayDryRunResponse(l, replayRequest, replayDryRunResponse)
-	return nil
+	printReplayEstimate(l, header)
+	chunks = header.Get(v1handler.MetadataReplayChunks)
+	if len(chunks) > 0 {
+		l.Println(coloredNotice("\nCHUNKS"))
+		l.Printf("replayed in %d chunks one after another: %s\n", len(chunks), strings.Join(chunks, ", "))
+	}
+	return chunks, nil
+}
+
+// replayRequestContext adds selector and preset of a replay to outgoing
+// metadata of ctx
+func replayRequestContext(ctx context.Context, selector, preset string) context.Context {
+	if selector != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, v1handler.MetadataLabelSelector, selector)
+	}
+	if preset != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, v1handler.MetadataReplayPreset, preset)
+	}
+	return ctx
+}
+
+// printReplayEstimate prints the impact of replay estimated by the server
+// from durations of previous runs
+func printReplayEstimate(l logger, header metadata.MD) {
+	runs := header.Get(v1handler.MetadataReplayRuns)
+	if len(runs) == 0 {
+		return
+	}
+	estimate := fmt.Sprintf("%s runs to clear", runs[0])
+	if vals := header.Get(v1handler.MetadataReplayEstimatedDuration); len(vals) > 0 {
+		if secs, err := strconv.Atoi(vals[0]); err == nil && secs > 0 {
+			estimate += fmt.Sprintf(", estimated to take %s if run one after another", time.Duration(secs)*time.Second)
+		}
+	}
+	if vals := header.Get(v1handler.MetadataReplayRunsWithoutHistory); len(vals) > 0 && vals[0] != "0" {
+		estimate += fmt.Sprintf(", %s runs without previous run durations are not estimated", vals[0])
+	}
+	l.Println(coloredNotice("\nESTIMATE"))
+	l.Println(estimate)
 }
 
 func printReplayDryRunResponse(l logger, replayRequest *pb.ReplayRequest, replayDryRunResponse *pb.ReplayDryRunResponse) {
@@ -229,7 +465,10 @@ func printExecutionTree(instance *pb.ReplayExecutionTreeNode, tree treeprint.Tre
 	return tree
 }
 
-func runReplayRequest(l logger, projectName, namespace, jobName, startDate, endDate string, conf config.Provider, forceRun bool) (string, error) {
+// runReplayRequest submits the replay, while waitUntil is ahead it retries
+// with backoff if the server can't accept the replay at the moment
+func runReplayRequest(l logger, projectName, namespace, jobName, startDate, endDate, selector, preset string,
+	conf config.Provider, forceRun, ignoreUpstream bool, waitUntil time.Time) (string, error) {
 	dialTimeoutCtx, dialCancel := context.WithTimeout(context.Background(), OptimusDialTimeout)
 	defer dialCancel()
 
@@ -242,13 +481,13 @@ func runReplayRequest(l logger, projectName, namespace, jobName, startDate, endD
 	}
 	defer conn.Close()
 
-	replayRequestTimeout, replayRequestCancel := context.WithTimeout(context.Background(), replayTimeout)
-	defer replayRequestCancel()
-
 	l.Println("firing the replay request...")
 	if forceRun {
 		l.Println("force running replay even if its already in progress")
 	}
+	if ignoreUpstream {
+		l.Println("sensors of cleared runs waiting for jobs not being replayed will be marked as succeeded")
+	}
 	runtime := pb.NewRuntimeServiceClient(conn)
 	replayRequest := &pb.ReplayRequest{
 		ProjectName: projectName,
@@ -258,12 +497,114 @@ func runReplayRequest(l logger, projectName, namespace, jobName, startDate, endD
 		EndDate:     endDate,
 		Force:       forceRun,
 	}
-	replayResponse, err := runtime.Replay(replayRequestTimeout, replayRequest)
+	backoff := replayWaitInitialBackoff
+	for {
+		replayId, err := submitReplayRequest(l, runtime, replayRequest, selector, preset, ignoreUpstream)
+		if err == nil {
+			return replayId, nil
+		}
+		reason, retryable := replayRetryReason(err)
+		if !retryable || time.Now().Add(backoff).After(waitUntil) {
+			if retryable && !waitUntil.IsZero() {
+				l.Println("replay could not be submitted before wait timeout")
+			}
+			return "", errors.Wrapf(err, "request failed for job %s", jobName)
+		}
+		l.Printf("%s, retrying in %s...\n", reason, backoff)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > replayWaitMaxBackoff {
+			backoff = replayWaitMaxBackoff
+		}
+	}
+}
+
+func submitReplayRequest(l logger, runtime pb.RuntimeServiceClient, replayRequest *pb.ReplayRequest,
+	selector, preset string, ignoreUpstream bool) (string, error) {
+	replayRequestTimeout, replayRequestCancel := context.WithTimeout(context.Background(), callTimeout(replayTimeout))
+	defer replayRequestCancel()
+	replayRequestTimeout = replayRequestContext(replayRequestTimeout, selector, preset)
+	if ignoreUpstream {
+		replayRequestTimeout = metadata.AppendToOutgoingContext(replayRequestTimeout, v1handler.MetadataReplayIgnoreUpstream, "true")
+	}
+
+	var header metadata.MD
+	replayResponse, err := runtime.Replay(replayRequestTimeout, replayRequest, grpc.Header(&header))
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			l.Println("replay request took too long, timing out")
 		}
-		return "", errors.Wrapf(err, "request failed for job %s", jobName)
+		printFreezeReason(l, err)
+		return "", err
 	}
+	printReplayEstimate(l, header)
 	return replayResponse.Id, nil
 }
+
+// runReplayChunks replays chunks of a preset one after another, each chunk
+// is submitted once the replay of previous one succeeded. Submission of a
+// chunk is retried till waitTimeout like --wait
+func runReplayChunks(l logger, projectName, namespace, jobName string, chunks []string, selector, preset string,
+	conf config.Provider, forceRun, ignoreUpstream bool, waitTimeout time.Duration) error {
+	for idx, chunk := range chunks {
+		bounds := strings.SplitN(chunk, "/", 2)
+		if len(bounds) != 2 {
+			return errors.Errorf("invalid replay chunk %s", chunk)
+		}
+		l.Printf("replaying chunk %d of %d from %s to %s\n", idx+1, len(chunks), bounds[0], bounds[1])
+		replayId, err := runReplayRequest(l, projectName, namespace, jobName, bounds[0], bounds[1], selector, preset,
+			conf, forceRun, ignoreUpstream, time.Now().Add(waitTimeout))
+		if err != nil {
+			return errors.Wrapf(err, "failed to replay chunk %d of %d", idx+1, len(chunks))
+		}
+		l.Printf("🚀 replay request created with id %s\n", replayId)
+		if idx == len(chunks)-1 {
+			l.Printf("check its status with: optimus replay status %s --project %s --job %s\n", replayId, projectName, jobName)
+			break
+		}
+		replayStatus, err := waitForReplay(projectName, jobName, replayId, conf)
+		if err != nil {
+			return err
+		}
+		if replayStatus != models.ReplayStatusSuccess {
+			return errors.Errorf("replay %s of chunk %d ended %s, %d chunks left are not replayed", replayId, idx+1,
+				replayStatus, len(chunks)-idx-1)
+		}
+	}
+	return nil
+}
+
+// waitForReplay polls status of the replay till it ends, returning its end state
+func waitForReplay(projectName, jobName, replayID string, conf config.Provider) (string, error) {
+	params := url.Values{}
+	params.Set("project", projectName)
+	params.Set("job", jobName)
+	params.Set("id", replayID)
+	for {
+		replay, err := getReplayStatus(conf.GetHost(), params)
+		if err != nil {
+			return "", err
+		}
+		switch replay.Status {
+		case models.ReplayStatusSuccess, models.ReplayStatusFailed, models.ReplayStatusCancelled:
+			return replay.Status, nil
+		}
+		time.Sleep(replayWaitMaxBackoff)
+	}
+}
+
+// replayRetryReason tells if a failed replay request can succeed when
+// submitted again later, i.e. the replay queue of the server is full or runs
+// of the jobs are active, and why it failed
+func replayRetryReason(err error) (string, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "", false
+	}
+	switch {
+	case st.Code() == codes.Unavailable && strings.Contains(st.Message(), job.ErrRequestQueueFull.Error()):
+		return "replay queue of the server is full", true
+	case st.Code() == codes.FailedPrecondition && strings.Contains(st.Message(), job.ErrConflictedJobRun.Error()):
+		return "runs of the jobs are active: " + st.Message(), true
+	}
+	return "", false
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/odpf/optimus/config"
+	cli "github.com/spf13/cobra"
+)
+
+// replayCancelSubCommand cancels an active replay of a job or a replay plan
+// along with replays of its jobs
+func replayCancelSubCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		replayProject string
+		jobName       string
+	)
+	cmd := &cli.Command{
+		Use:   "cancel",
+		Short: "cancel an active replay or replay plan along with replays of its jobs",
+		Example: "optimus replay cancel <replay_id> --project \"project-id\" --job optimus.dag.name\n" +
+			"optimus replay cancel <plan_id> --project \"project-id\"",
+		Long: `
+Cancels a replay yet to end. Replays waiting in queue are dropped from it,
+ones in progress stop before clearing runs of their next job. Runs cleared
+already are left to finish in scheduler. Without a job the id is of a
+replay plan, all replays of the plan yet to end are cancelled.
+		`,
+		Args: cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&replayProject, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVarP(&jobName, "job", "j", "", "name of the job replayed, empty for a replay plan")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", replayProject)
+		params.Set("id", args[0])
+		path := "/replay-plan"
+		if jobName != "" {
+			params.Set("job", jobName)
+			path = "/replay-status"
+		}
+		replay, err := requestReplayStatus(conf.GetHost(), http.MethodDelete, path, params)
+		if err != nil {
+			return err
+		}
+		l.Printf("replay %s cancelled\n", replay.ID)
+		l.Println("status:", coloredNotice(replay.Status))
+		printReplayJobs(l, replay)
+		return nil
+	}
+	return cmd
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+// replayPlanSubCommand replays all jobs of a project matching a label
+// selector as a single plan, upstream jobs first
+func replayPlanSubCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		replayProject  string
+		dryRun         bool
+		forceRun       bool
+		ignoreUpstream bool
+	)
+	cmd := &cli.Command{
+		Use:   "plan",
+		Short: "replay all jobs with labels matching a selector based on provided date range",
+		Example: "optimus replay plan pipeline=revenue 2020-02-03 2020-02-05 --project \"project-id\"\n" +
+			"optimus replay plan pipeline=revenue,tier=critical 2020-02-03 --project \"project-id\" --dry-run",
+		Long: `
+This operation takes three arguments, first is a label selector[required]
+matching jobs of the project to replay, second is start date[required] of
+replay, third is end date[optional] of replay. Dates are YYYY-MM-DD and
+inclusive. Each job is replayed without its dependents, one after another
+in order of their dependencies so upstream jobs are replayed first, all
+tracked under a single plan id. Replays after a failed one are cancelled.
+		`,
+		Args: func(cmd *cli.Command, args []string) error {
+			if len(args) < 1 {
+				return errors.New("label selector is required")
+			}
+			if len(args) < 2 {
+				return errors.New("replay start date is required")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&replayProject, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "", dryRun, "do a trial run with no permanent changes")
+	cmd.Flags().BoolVarP(&forceRun, "force", "f", forceRun, "run replay even if a previous run is in progress")
+	cmd.Flags().BoolVar(&ignoreUpstream, "ignore-upstream", ignoreUpstream, "mark sensors of cleared runs waiting for jobs not being replayed as succeeded")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		if _, err := models.ParseLabelSelector(args[0]); err != nil {
+			return err
+		}
+		params := url.Values{}
+		params.Set("project", replayProject)
+		params.Set("selector", args[0])
+		params.Set("start", args[1])
+		params.Set("end", args[1])
+		if len(args) >= 3 {
+			params.Set("end", args[2])
+		}
+		params.Set("force", strconv.FormatBool(forceRun))
+		params.Set("ignore_upstream", strconv.FormatBool(ignoreUpstream))
+
+		dryRunParams := url.Values{}
+		for key, values := range params {
+			dryRunParams[key] = values
+		}
+		dryRunParams.Set("dry_run", "true")
+		plan, err := postReplayPlan(conf.GetHost(), dryRunParams)
+		if err != nil {
+			return err
+		}
+		l.Printf("For %s project, jobs matching %s\n\n", coloredNotice(replayProject), coloredNotice(args[0]))
+		printReplayPlan(l, plan)
+		if dryRun {
+			return nil
+		}
+
+		proceedWithReplay := "Yes"
+		if err := survey.AskOne(&survey.Select{
+			Message: "Proceed with replay?",
+			Options: []string{"Yes", "No"},
+			Default: "Yes",
+		}, &proceedWithReplay); err != nil {
+			return err
+		}
+		if proceedWithReplay == "No" {
+			l.Println("aborting...")
+			return nil
+		}
+
+		plan, err = postReplayPlan(conf.GetHost(), params)
+		if err != nil {
+			return err
+		}
+		l.Printf("🚀 replay plan created with id %s\n", plan.ID)
+		l.Printf("check its status with: optimus replay plan-status %s --project %s\n", plan.ID, replayProject)
+		return nil
+	}
+	return cmd
+}
+
+// replayPlanStatusSubCommand prints status of a replay plan along with
+// statuses of its replays
+func replayPlanStatusSubCommand(l logger, conf config.Provider) *cli.Command {
+	var replayProject string
+	cmd := &cli.Command{
+		Use:     "plan-status",
+		Short:   "get status of a replay plan and each of its replays",
+		Example: "optimus replay plan-status <plan_id> --project \"project-id\"",
+		Args:    cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&replayProject, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("project", replayProject)
+		params.Set("id", args[0])
+		plan, err := requestReplayStatus(conf.GetHost(), http.MethodGet, "/replay-plan", params)
+		if err != nil {
+			return err
+		}
+		l.Printf("replay plan %s from %s to %s, created at %s\n", plan.ID, plan.StartDate.Format(time.RFC3339),
+			plan.EndDate.Format(time.RFC3339), plan.CreatedAt.Format(time.RFC3339))
+		l.Println("status:", coloredNotice(plan.Status))
+		if plan.Message != "" {
+			l.Println("message:", plan.Message)
+		}
+		printReplayJobs(l, plan)
+		return nil
+	}
+	return cmd
+}
+
+func printReplayPlan(l logger, plan v1handler.ReplayPlanResponse) {
+	l.Println(coloredNotice("REPLAY RUNS"))
+	table := tablewriter.NewWriter(l.Writer())
+	table.SetBorder(false)
+	table.SetHeader([]string{
+		"Index",
+		"Job",
+		"Run",
+	})
+	for idx, replay := range plan.Replays {
+		runTimes := []string{}
+		for _, run := range replay.Runs {
+			runTimes = append(runTimes, run.String())
+		}
+		table.Append([]string{fmt.Sprintf("%d", idx+1), replay.JobName, strings.Join(runTimes, "\n")})
+	}
+	table.Render()
+}
+
+func postReplayPlan(host string, params url.Values) (v1handler.ReplayPlanResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(replayTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s://%s/replay-plan?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.ReplayPlanResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.ReplayPlanResponse{}, errors.Wrap(err, "failed to request replay plan")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.ReplayPlanResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.ReplayPlanResponse{}, errors.Errorf("failed to request replay plan, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var plan v1handler.ReplayPlanResponse
+	if err := json.Unmarshal(body, &plan); err != nil {
+		return v1handler.ReplayPlanResponse{}, errors.Wrap(err, "failed to decode replay plan")
+	}
+	return plan, nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	cli "github.com/spf13/cobra"
+)
+
+// resourceCommand manages resources of datastores deployed by optimus
+func resourceCommand(l logger, conf config.Provider, dsRepo models.DatastoreRepo, datastoreSpecFs map[string]afero.Fs) *cli.Command {
+	cmd := &cli.Command{
+		Use:   "resource",
+		Short: "Manage resources of datastores",
+	}
+	cmd.AddCommand(resourceRestoreCommand(l, conf))
+	cmd.AddCommand(resourceImportCommand(l, conf, dsRepo, datastoreSpecFs))
+	return cmd
+}
+
+func resourceRestoreCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		projectName   string
+		datastoreName string
+		destination   string
+		at            string
+		detach        bool
+	)
+	cmd := &cli.Command{
+		Use:   "restore",
+		Short: "Restore a table as it was at a point in time",
+		Long: "Restore a table as it was at a point in time within the time travel window of datastore,\n" +
+			"overwriting the table or into a new table. Restore runs on optimus service and is followed\n" +
+			"till it is over unless detached, a detached restore can be followed with operation status.",
+		Example: "optimus resource restore project.dataset.table --project \"project-id\" --at 2021-06-02T08:00:00Z\n" +
+			"optimus resource restore project.dataset.table --project \"project-id\" --at 2h --to project.dataset.table_restored",
+		Args: cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&projectName, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&datastoreName, "datastore", "bigquery", "datastore of the resource")
+	cmd.Flags().StringVar(&at, "at", "", "point in time to restore to, RFC3339 timestamp or duration before now, e.g. 2h")
+	cmd.MarkFlagRequired("at")
+	cmd.Flags().StringVar(&destination, "to", "", "name of a new table to restore into, table is overwritten if not set")
+	cmd.Flags().BoolVar(&detach, "detach", false, "return once restore is started without following it")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		pointInTime, err := parseRestorePointInTime(at, time.Now())
+		if err != nil {
+			return err
+		}
+		body, err := json.Marshal(v1handler.ResourceRestoreRequest{
+			Datastore:   datastoreName,
+			Resource:    args[0],
+			Destination: destination,
+			PointInTime: pointInTime,
+		})
+		if err != nil {
+			return err
+		}
+		params := url.Values{}
+		params.Set("project", projectName)
+		var op v1handler.OperationResponse
+		if err := operationRequest(conf.GetHost(), http.MethodPost, "/resource-restore", params, bytes.NewReader(body), &op); err != nil {
+			return err
+		}
+		target := "in place"
+		if destination != "" && destination != args[0] {
+			target = "into " + destination
+		}
+		l.Printf("restoring %s to %s %s, operation id: %s\n", args[0], pointInTime.Format(time.RFC3339), target, op.ID)
+		if detach {
+			return nil
+		}
+		if op, err = followOperation(l, conf.GetHost(), projectName, op); err != nil {
+			return err
+		}
+		return printOperationOutcome(l, op)
+	}
+	return cmd
+}
+
+// parseRestorePointInTime accepts a RFC3339 timestamp or a duration before now
+func parseRestorePointInTime(at string, now time.Time) (time.Time, error) {
+	if pointInTime, err := time.Parse(time.RFC3339, at); err == nil {
+		return pointInTime, nil
+	}
+	ago, err := time.ParseDuration(at)
+	if err != nil || ago <= 0 {
+		return time.Time{}, errors.Errorf("invalid point in time %s, should be a RFC3339 timestamp or a duration like 2h", at)
+	}
+	return now.Add(-ago).UTC().Truncate(time.Second), nil
+}
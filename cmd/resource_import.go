@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/local"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	// datasets with many tables take a while to be read
+	resourceImportTimeout = time.Minute * 5
+)
+
+func resourceImportCommand(l logger, conf config.Provider, dsRepo models.DatastoreRepo,
+	datastoreSpecFs map[string]afero.Fs) *cli.Command {
+	var (
+		projectName string
+		dataset     string
+		overwrite   bool
+	)
+	cmd := &cli.Command{
+		Use:   "import",
+		Short: "Import existing resources of a datastore as resource specs",
+		Long: "Read existing resources of a datastore, ones not created by optimus, and write them as resource\n" +
+			"specs into the configured datastore path. Specs of a dataset are written in a directory named after\n" +
+			"the dataset, with a directory for each of its tables and views. Specs already in the repository\n" +
+			"are left as is unless overwritten.",
+		Example: "optimus resource import bigquery --project \"project-id\" --dataset project.dataset",
+		Args:    cli.ExactArgs(1),
+	}
+	cmd.Flags().StringVarP(&projectName, "project", "p", "", "project name of optimus managed ocean repository")
+	cmd.MarkFlagRequired("project")
+	cmd.Flags().StringVar(&dataset, "dataset", "", "fully qualified name of dataset to import, e.g. project.dataset")
+	cmd.MarkFlagRequired("dataset")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "overwrite specs already in the repository")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		datastoreName := args[0]
+		repoFS, ok := datastoreSpecFs[datastoreName]
+		if !ok {
+			return fmt.Errorf("unregistered datastore, please use configuration file to set datastore path")
+		}
+		ds, err := dsRepo.GetByName(datastoreName)
+		if err != nil {
+			return err
+		}
+		resourceSpecRepo := local.NewResourceSpecRepository(repoFS, ds)
+
+		imported, err := getImportedResources(conf.GetHost(), projectName, datastoreName, dataset)
+		if err != nil {
+			return err
+		}
+		var written, existing int
+		for _, resource := range imported.Resources {
+			typeController, ok := ds.Types()[models.ResourceType(resource.Type)]
+			if !ok {
+				return errors.Errorf("unsupported type %s for datastore %s", resource.Type, datastoreName)
+			}
+			resourceSpec, err := typeController.Adapter().FromYaml([]byte(resource.Spec))
+			if err != nil {
+				return errors.Wrapf(err, "failed to read imported spec of %s", resource.Name)
+			}
+			resourceSpec.Datastore = ds
+			resourceSpec.Assets = resource.Assets
+
+			_, err = resourceSpecRepo.GetByName(resource.Name)
+			switch {
+			case err == nil && !overwrite:
+				existing++
+				l.Printf("> %s already exists, skipping\n", resource.Name)
+				continue
+			case err == nil:
+				// keep the spec where it is in the repository
+				err = resourceSpecRepo.Save(resourceSpec)
+			case errors.Is(err, models.ErrNoSuchSpec):
+				err = resourceSpecRepo.SaveAt(resourceSpec, importedResourceDir(resource.Name))
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to write spec of %s", resource.Name)
+			}
+			written++
+			l.Printf("> imported %s %s\n", resource.Type, resource.Name)
+		}
+		for _, name := range imported.Skipped {
+			l.Printf("> skipped %s, it can't be described as a resource spec\n", name)
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("imported %d resources of %s, %d already existed, %d skipped",
+			written, dataset, existing, len(imported.Skipped))))
+		return nil
+	}
+	return cmd
+}
+
+// importedResourceDir is the directory a resource is written to, relative to
+// datastore path, e.g. dataset/table for project.dataset.table
+func importedResourceDir(name string) string {
+	parts := strings.Split(name, ".")
+	if len(parts) > 1 {
+		parts = parts[1:]
+	}
+	return filepath.Join(parts...)
+}
+
+func getImportedResources(host, projectName, datastoreName, dataset string) (v1handler.ResourceImportResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(resourceImportTimeout))
+	defer cancel()
+
+	params := url.Values{}
+	params.Set("project", projectName)
+	params.Set("datastore", datastoreName)
+	params.Set("dataset", dataset)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/resource-import?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.ResourceImportResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.ResourceImportResponse{}, errors.Wrap(err, "failed to import resources")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.ResourceImportResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.ResourceImportResponse{}, errors.Errorf("failed to import resources, status: %d, response: %s",
+			resp.StatusCode, string(body))
+	}
+	var imported v1handler.ResourceImportResponse
+	if err := json.Unmarshal(body, &imported); err != nil {
+		return v1handler.ResourceImportResponse{}, errors.Wrap(err, "failed to decode imported resources")
+	}
+	return imported, nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/odpf/optimus/core/jsonschema"
+	"github.com/odpf/optimus/store/local"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	cli "github.com/spf13/cobra"
+)
+
+var (
+	// specSchemas are json schemas of supported specification kinds
+	specSchemas = map[string]func() *jsonschema.Schema{
+		"job":      local.JobSpecSchema,
+		"resource": local.ResourceSpecSchema,
+	}
+)
+
+// schemaCommand provides json schema of specification files
+func schemaCommand(l logger) *cli.Command {
+	cmd := &cli.Command{
+		Use:   "schema",
+		Short: "json schema of specification files",
+	}
+	cmd.AddCommand(schemaExportCommand(l))
+	return cmd
+}
+
+func schemaExportCommand(l logger) *cli.Command {
+	var outputDir string
+	cmd := &cli.Command{
+		Use:   "export [job|resource]",
+		Short: "export json schema of specification files for editors and CI checks",
+		Example: "optimus schema export job\n" +
+			"optimus schema export --output ./schemas",
+		Args: cli.MaximumNArgs(1),
+	}
+	cmd.Flags().StringVarP(&outputDir, "output", "o", "", "directory to write <kind>.schema.json files into, prints to stdout if empty")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		kinds := []string{"job", "resource"}
+		if len(args) == 1 {
+			if _, ok := specSchemas[args[0]]; !ok {
+				return fmt.Errorf("unknown specification kind %s, supported: %v", args[0], kinds)
+			}
+			kinds = []string{args[0]}
+		}
+
+		for _, kind := range kinds {
+			raw, err := json.MarshalIndent(specSchemas[kind](), "", "  ")
+			if err != nil {
+				return err
+			}
+			if outputDir == "" {
+				l.Println(string(raw))
+				continue
+			}
+
+			if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+				return err
+			}
+			filePath := filepath.Join(outputDir, fmt.Sprintf("%s.schema.json", kind))
+			if err := ioutil.WriteFile(filePath, append(raw, '\n'), 0644); err != nil {
+				return errors.Wrapf(err, "failed to write %s", filePath)
+			}
+			l.Printf("schema exported to %s\n", filePath)
+		}
+		return nil
+	}
+	return cmd
+}
+
+// validateJobSpecFiles checks all job specification files available in fs
+// against the json schema without reaching optimus service
+func validateJobSpecFiles(fs afero.Fs) error {
+	schema := local.JobSpecSchema()
+
+	var errs error
+	err := afero.Walk(fs, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || (info.Name() != local.JobSpecFileName && info.Name() != local.JobSpecParentName) {
+			return nil
+		}
+
+		content, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		if err := local.ValidateSpecFile(schema, content); err != nil {
+			errs = multierror.Append(errs, errors.Wrap(err, path))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return errs
+}
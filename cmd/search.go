@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	searchTimeout = time.Second * 15
+)
+
+// searchCommand finds deployed jobs and resources across projects
+func searchCommand(l logger, conf config.Provider) *cli.Command {
+	var (
+		kind        string
+		projectName string
+		labels      []string
+		owner       string
+		task        string
+		datastore   string
+		resType     string
+		limit       int
+	)
+	cmd := &cli.Command{
+		Use:   "search [terms]",
+		Short: "Find deployed jobs and resources across projects",
+		Long: "Find deployed jobs and resources across projects. Every term has to be part of the name, owner,\n" +
+			"destination or task of a job, or the name, datastore or type of a resource.",
+		Example: "optimus search finance.daily_revenue\n" +
+			"optimus search revenue --kind job --label team=finance --task bq2bq",
+	}
+	cmd.Flags().StringVar(&kind, "kind", "", "search only jobs or resources, job or resource")
+	cmd.Flags().StringVar(&projectName, "project", "", "search only this project")
+	cmd.Flags().StringArrayVar(&labels, "label", nil, "label as key=value jobs or resources should have, can be repeated")
+	cmd.Flags().StringVar(&owner, "owner", "", "owner of jobs")
+	cmd.Flags().StringVar(&task, "task", "", "task of jobs")
+	cmd.Flags().StringVar(&datastore, "datastore", "", "datastore of resources")
+	cmd.Flags().StringVar(&resType, "type", "", "type of resources")
+	cmd.Flags().IntVar(&limit, "limit", 0, "max results of each kind")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		params := url.Values{}
+		params.Set("q", strings.Join(args, " "))
+		for key, val := range map[string]string{
+			"kind": kind, "project": projectName, "owner": owner, "task": task,
+			"datastore": datastore, "type": resType,
+		} {
+			if val != "" {
+				params.Set(key, val)
+			}
+		}
+		for _, label := range labels {
+			params.Add("label", label)
+		}
+		if limit > 0 {
+			params.Set("limit", strconv.Itoa(limit))
+		}
+
+		result, err := getSearch(conf.GetHost(), params)
+		if err != nil {
+			return err
+		}
+		if len(result.Jobs) == 0 && len(result.Resources) == 0 {
+			l.Println(coloredNotice("nothing found"))
+			return nil
+		}
+		if len(result.Jobs) > 0 {
+			l.Println(coloredNotice(fmt.Sprintf("%d jobs", len(result.Jobs))))
+			table := tablewriter.NewWriter(l.Writer())
+			table.SetBorder(false)
+			table.SetHeader([]string{"Project", "Namespace", "Job", "Task", "Owner", "Destination"})
+			for _, job := range result.Jobs {
+				table.Append([]string{job.Project, job.Namespace, job.Name, job.Task, job.Owner, job.Destination})
+			}
+			table.Render()
+		}
+		if len(result.Resources) > 0 {
+			l.Println(coloredNotice(fmt.Sprintf("%d resources", len(result.Resources))))
+			table := tablewriter.NewWriter(l.Writer())
+			table.SetBorder(false)
+			table.SetHeader([]string{"Project", "Namespace", "Resource", "Datastore", "Type"})
+			for _, resource := range result.Resources {
+				table.Append([]string{resource.Project, resource.Namespace, resource.Name, resource.Datastore, resource.Type})
+			}
+			table.Render()
+		}
+		return nil
+	}
+	return cmd
+}
+
+func getSearch(host string, params url.Values) (v1handler.SearchResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(searchTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s/search?%s", httpScheme(), host, params.Encode()), nil)
+	if err != nil {
+		return v1handler.SearchResponse{}, err
+	}
+	resp, err := doHTTPRequest(req)
+	if err != nil {
+		return v1handler.SearchResponse{}, errors.Wrap(err, "failed to search")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return v1handler.SearchResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return v1handler.SearchResponse{}, errors.Errorf("failed to search, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+	var result v1handler.SearchResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return v1handler.SearchResponse{}, errors.Wrap(err, "failed to decode search results")
+	}
+	return result, nil
+}
@@ -2,7 +2,10 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -14,9 +17,12 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 
+	"github.com/odpf/optimus/ext/commitstatus"
+	"github.com/odpf/optimus/ext/notify/email"
 	"github.com/odpf/optimus/ext/notify/slack"
 
 	"github.com/odpf/optimus/utils"
+	"github.com/odpf/optimus/web"
 
 	"github.com/odpf/optimus/ext/scheduler/airflow"
 
@@ -37,25 +43,41 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	slackapi "github.com/slack-go/slack"
+	"github.com/spf13/afero"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
 
 	v1 "github.com/odpf/optimus/api/handler/v1"
 	v1handler "github.com/odpf/optimus/api/handler/v1"
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/core/certs"
+	"github.com/odpf/optimus/core/jsonschema"
 	"github.com/odpf/optimus/core/logger"
 	"github.com/odpf/optimus/core/progress"
 	_ "github.com/odpf/optimus/ext/datastore"
 	"github.com/odpf/optimus/ext/scheduler/airflow2"
+	"github.com/odpf/optimus/ext/secret/gsm"
+	"github.com/odpf/optimus/ext/secret/vault"
+	"github.com/odpf/optimus/gitsync"
 	"github.com/odpf/optimus/instance"
 	"github.com/odpf/optimus/job"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
 	_ "github.com/odpf/optimus/plugin"
+	"github.com/odpf/optimus/quota"
+	"github.com/odpf/optimus/replication"
+	"github.com/odpf/optimus/retention"
+	"github.com/odpf/optimus/secret"
 	"github.com/odpf/optimus/store"
+	"github.com/odpf/optimus/store/filesystem"
 	"github.com/odpf/optimus/store/gcs"
+	"github.com/odpf/optimus/store/local"
+	"github.com/odpf/optimus/store/object"
 	"github.com/odpf/optimus/store/postgres"
+	"github.com/odpf/optimus/store/s3"
 )
 
 var (
@@ -64,7 +86,8 @@ var (
 
 	shutdownWait = 30 * time.Second
 
-	GRPCMaxRecvMsgSize = 45 << 20 // 45MB
+	// buffer of in process connection of http proxy to grpc server
+	inProcessConnBufferSize = 1 << 20
 )
 
 // projectJobSpecRepoFactory stores raw specifications
@@ -111,26 +134,55 @@ func (fac *jobRepoFactory) New(ctx context.Context, proj models.ProjectSpec) (st
 	if !ok {
 		return nil, errors.Errorf("%s not configured for project %s", models.ProjectStoragePathKey, proj.Name)
 	}
+	p, err := url.Parse(storagePath)
+	if err != nil {
+		return nil, err
+	}
 	storageSecret, ok := proj.Secret.GetByName(models.ProjectSecretStorageKey)
-	if !ok {
+	if !ok && p.Scheme != "file" {
 		return nil, errors.Errorf("%s secret not configured for project %s", models.ProjectSecretStorageKey, proj.Name)
 	}
 
-	p, err := url.Parse(storagePath)
+	artifact, err := proj.JobArtifact()
 	if err != nil {
 		return nil, err
 	}
+	suffix := job.JobArtifactSuffix(artifact, fac.schd.GetJobsExtension())
+
+	jobsPath := filepath.Join(p.Path, fac.schd.GetJobsDir())
 	switch p.Scheme {
 	case "gs":
 		storageClient, err := storage.NewClient(ctx, option.WithCredentialsJSON([]byte(storageSecret)))
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating google storage client")
 		}
-		return gcs.NewJobRepository(p.Hostname(), filepath.Join(p.Path, fac.schd.GetJobsDir()), fac.schd.GetJobsExtension(), storageClient), nil
+		return gcs.NewJobRepository(p.Hostname(), jobsPath, suffix, storageClient), nil
+	case "s3", "file":
+		objStore, err := newObjectStore(p, storageSecret)
+		if err != nil {
+			return nil, err
+		}
+		return object.NewJobRepository(objStore, p.Hostname(), jobsPath, suffix), nil
 	}
 	return nil, errors.Errorf("unsupported storage config %s in %s of project %s", storagePath, models.ProjectStoragePathKey, proj.Name)
 }
 
+// newObjectStore is the store of storage path other than gcs, s3 buckets
+// or directories of filesystem like dag folders mounted over nfs
+func newObjectStore(p *url.URL, storageSecret string) (store.ObjectStore, error) {
+	switch p.Scheme {
+	case "s3":
+		creds, err := s3.ParseCredentials(storageSecret)
+		if err != nil {
+			return nil, err
+		}
+		return s3.NewObjectStore(creds, &http.Client{}), nil
+	case "file":
+		return filesystem.NewObjectStore(afero.NewOsFs()), nil
+	}
+	return nil, errors.Errorf("unsupported storage scheme %s", p.Scheme)
+}
+
 type projectRepoFactory struct {
 	db   *gorm.DB
 	hash models.ApplicationKey
@@ -203,6 +255,8 @@ func (o *objectWriterFactory) New(ctx context.Context, writerPath, writerSecret
 		return &gcs.GcsObjectWriter{
 			Client: gcsClient,
 		}, nil
+	case "s3", "file":
+		return newObjectStore(p, writerSecret)
 	}
 	return nil, errors.Errorf("unsupported storage config %s", writerPath)
 }
@@ -226,10 +280,10 @@ func (obs *pipelineLogObserver) Notify(evt progress.Event) {
 	obs.log.Info(evt)
 }
 
-func jobSpecAssetDump() func(jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
+func jobSpecAssetDump() func(proj models.ProjectSpec, jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
 	engine := instance.NewGoEngine()
-	return func(jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
-		aMap, err := instance.DumpAssets(jobSpec, scheduledAt, engine, false)
+	return func(proj models.ProjectSpec, jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
+		aMap, err := instance.DumpAssets(proj, jobSpec, scheduledAt, engine, false)
 		if err != nil {
 			return models.JobAssets{}, err
 		}
@@ -245,8 +299,12 @@ func checkRequiredConfigs(conf config.Provider) error {
 	if conf.GetServe().ReplayNumWorkers < 1 {
 		return errors.New(fmt.Sprintf("%s should be greater than 0", config.KeyServeReplayNumWorkers))
 	}
+	return checkDBConfigs(conf)
+}
+
+func checkDBConfigs(conf config.Provider) error {
 	if conf.GetServe().DB.DSN == "" {
-		return errors.Wrap(errRequiredMissing, "serve.db.dsn")
+		return errors.Wrap(errors.New("required config missing"), "serve.db.dsn")
 	}
 	if parsed, err := url.Parse(conf.GetServe().DB.DSN); err != nil {
 		return errors.Wrap(err, "failed to parse serve.db.dsn")
@@ -258,6 +316,18 @@ func checkRequiredConfigs(conf config.Provider) error {
 	return nil
 }
 
+// Migrate runs migrations of the database of server, replicas of server
+// starting with serve.db.auto_migrate disabled expect it to be done already
+func Migrate(conf config.Provider) error {
+	if err := checkDBConfigs(conf); err != nil {
+		return err
+	}
+	if err := postgres.Migrate(conf.GetServe().DB.DSN); err != nil {
+		return errors.Wrap(err, "postgres.Migrate")
+	}
+	return nil
+}
+
 func Initialize(conf config.Provider) error {
 	if err := checkRequiredConfigs(conf); err != nil {
 		return err
@@ -278,13 +348,26 @@ func Initialize(conf config.Provider) error {
 	}
 
 	// setup db
-	if err := postgres.Migrate(conf.GetServe().DB.DSN); err != nil {
-		return errors.Wrap(err, "postgres.Migrate")
+	if conf.GetServe().DB.AutoMigrate {
+		if err := postgres.Migrate(conf.GetServe().DB.DSN); err != nil {
+			return errors.Wrap(err, "postgres.Migrate")
+		}
+	} else {
+		mainLog.Info("skipping migration of database, serve.db.auto_migrate is disabled")
 	}
 	dbConn, err := postgres.Connect(conf.GetServe().DB.DSN, conf.GetServe().DB.MaxIdleConnection, conf.GetServe().DB.MaxOpenConnection)
 	if err != nil {
 		return errors.Wrap(err, "postgres.Connect")
 	}
+	// counts calls, runs and events of jobs by project and namespace, and
+	// times queries of repositories
+	metrics := v1handler.NewMetrics()
+	postgres.Instrument(dbConn, metrics.RecordQuery, conf.GetServe().DB.SlowQueryMillis)
+	if conf.GetServe().SpecCacheTTLSecs > 0 {
+		// projects and job specs are read on every dependency resolution and
+		// instance compilation
+		dbConn = postgres.WithSpecCache(dbConn, postgres.NewSpecCache(conf.GetServe().SpecCacheTTLSecs, metrics.RecordCacheLookup))
+	}
 
 	// init default scheduler
 	switch conf.GetScheduler().Name {
@@ -364,13 +447,47 @@ func Initialize(conf config.Provider) error {
 	// Make sure that log statements internal to gRPC library are logged using the logrus Logger as well.
 	grpc_logrus.ReplaceGrpcLogger(logrusEntry)
 
+	// records every mutating call
+	auditLogRepo := postgres.NewAuditLogRepository(dbConn)
+	auditLogger := v1handler.NewAuditLogger(auditLogRepo)
+	// lets calls with api tokens act only within scopes of their token
+	tokenAuthenticator := v1handler.NewTokenAuthenticator(postgres.NewAPITokenRepository(dbConn),
+		conf.GetServe().Auth.RequireToken)
+	// rejects changes to frozen projects
+	freezeRepo := postgres.NewProjectFreezeRepository(dbConn)
+	freezeGuard := v1handler.NewFreezeGuard(freezeRepo, projectRepoFac)
+	// archives of decommissioned projects, purged by retention janitor
+	projectArchiveRepo := postgres.NewProjectArchiveRepository(dbConn)
+
 	grpcAddr := fmt.Sprintf("%s:%d", conf.GetServe().Host, conf.GetServe().Port)
+	// recovery follows logging so a panicking call is logged with its
+	// internal error, interceptors after it are covered by recovery too
+	grpcConf := conf.GetServe().GRPC
 	grpcOpts := []grpc.ServerOption{
 		grpc_middleware.WithUnaryServerChain(
-			grpctags.UnaryServerInterceptor(grpctags.WithFieldExtractor(grpctags.CodeGenRequestFieldExtractor)),
+			grpctags.UnaryServerInterceptor(),
 			grpc_logrus.UnaryServerInterceptor(logrusEntry, opts...),
+			v1handler.RecoveryUnaryServerInterceptor(),
+			v1handler.PayloadUnaryServerInterceptor(),
+			v1handler.DeadlineUnaryServerInterceptor(grpcConf.UnaryTimeoutSecs),
+			metrics.UnaryServerInterceptor(),
+			tokenAuthenticator.UnaryServerInterceptor(),
+			auditLogger.UnaryServerInterceptor(),
+			freezeGuard.UnaryServerInterceptor(),
 		),
-		grpc.MaxRecvMsgSize(GRPCMaxRecvMsgSize),
+		grpc_middleware.WithStreamServerChain(
+			grpctags.StreamServerInterceptor(),
+			grpc_logrus.StreamServerInterceptor(logrusEntry, opts...),
+			v1handler.RecoveryStreamServerInterceptor(),
+			v1handler.PayloadStreamServerInterceptor(),
+			v1handler.DeadlineStreamServerInterceptor(grpcConf.StreamTimeoutSecs),
+			metrics.StreamServerInterceptor(),
+			tokenAuthenticator.StreamServerInterceptor(),
+			auditLogger.StreamServerInterceptor(),
+			freezeGuard.StreamServerInterceptor(),
+		),
+		grpc.MaxRecvMsgSize(grpcConf.MaxRecvMsgSizeMB << 20),
+		grpc.MaxSendMsgSize(grpcConf.MaxSendMsgSizeMB << 20),
 	}
 	grpcServer := grpc.NewServer(grpcOpts...)
 	reflection.Register(grpcServer)
@@ -405,59 +522,185 @@ func Initialize(conf config.Provider) error {
 		db:             dbConn,
 		jobSpecRepoFac: jobSpecRepoFac,
 	}
-	replayWorker := job.NewReplayWorker(replaySpecRepoFac, models.Scheduler)
-	replayManager := job.NewManager(replayWorker, replaySpecRepoFac, utils.NewUUIDProvider(), job.ReplayManagerConfig{
-		NumWorkers:    conf.GetServe().ReplayNumWorkers,
-		WorkerTimeout: conf.GetServe().ReplayWorkerTimeoutSecs,
-		RunTimeout:    conf.GetServe().ReplayRunTimeoutSecs,
-	}, models.Scheduler)
 
 	notificationContext, cancelNotifiers := context.WithCancel(context.Background())
 	defer cancelNotifiers()
-	eventService := job.NewEventService(map[string]models.Notifier{
+	notifiers := map[string]models.Notifier{
 		"slack": slack.NewNotifier(notificationContext, slackapi.APIURL,
 			slack.DefaultEventBatchInterval,
 			func(err error) {
 				logger.E(err)
 			},
 		),
+	}
+	if smtpConf := conf.GetServe().SMTP; smtpConf.Host != "" {
+		mainLog.Infof("email notifications are enabled through %s:%d", smtpConf.Host, smtpConf.Port)
+		notifiers["email"] = email.NewNotifier(notificationContext, email.Config{
+			Host:     smtpConf.Host,
+			Port:     smtpConf.Port,
+			Username: smtpConf.Username,
+			Password: smtpConf.Password,
+			From:     smtpConf.From,
+		}, email.DefaultQueueSize, func(err error) {
+			logger.E(err)
+		})
+	}
+	eventService := job.NewEventService(notifiers)
+
+	// deploys of a job and replays of it take its lock, so they don't run
+	// at the same time on any of the servers sharing the database
+	jobLocker := job.NewJobLocker(postgres.NewJobLockRepository(dbConn))
+	jobLocker.Start()
+	replayWorker := job.NewReplayWorker(replaySpecRepoFac, models.Scheduler, eventService)
+	replayManager := job.NewManager(replayWorker, replaySpecRepoFac, utils.NewUUIDProvider(), job.ReplayManagerConfig{
+		NumWorkers:    conf.GetServe().ReplayNumWorkers,
+		WorkerTimeout: conf.GetServe().ReplayWorkerTimeoutSecs,
+		RunTimeout:    conf.GetServe().ReplayRunTimeoutSecs,
+		QueueSize:     conf.GetServe().ReplayQueueSize,
+	}, models.Scheduler, eventService, jobLocker)
+
+	changelogRepo := postgres.NewDeployChangelogRepository(dbConn)
+	quotaConf := conf.GetServe().Quota
+	quotaService := quota.NewService(postgres.NewProjectQuotaRepository(dbConn), models.ProjectQuota{
+		MaxJobs:             quotaConf.MaxJobs,
+		MaxResources:        quotaConf.MaxResources,
+		MaxReplayRunsPerDay: quotaConf.MaxReplayRunsPerDay,
 	})
 
+	jobService := job.NewService(
+		&jobSpecRepoFac,
+		&jobRepoFactory{
+			schd: models.Scheduler,
+		},
+		jobCompiler,
+		jobSpecAssetDump(),
+		dependencyResolver,
+		priorityResolver,
+		metaSvcFactory,
+		&projectJobSpecRepoFac,
+		replayManager,
+		models.Scheduler,
+	)
+	jobService.SetLocker(jobLocker)
+	jobService.SetProjectRepoFactory(projectRepoFac)
+
+	checkpointRepo := postgres.NewJobCheckpointRepository(dbConn)
+	instanceService := instance.NewService(
+		&instanceRepoFactory{
+			db: dbConn,
+		},
+		func() time.Time {
+			return time.Now().UTC()
+		},
+		instance.NewGoEngine(),
+		secret.NewResolver([]models.SecretBackend{
+			vault.NewBackend(http.DefaultClient),
+			gsm.NewBackend(),
+		}, auditLogRepo, conf.GetServe().SecretCacheTTLSecs),
+		checkpointRepo,
+	)
+
+	var autoHealer *job.AutoHealer
+	if interval := conf.GetServe().AutoHealIntervalSecs; interval > 0 {
+		autoHealer = job.NewAutoHealer(projectRepoFac, namespaceSpecRepoFac, jobService, replaySpecRepoFac,
+			models.Scheduler, eventService, interval)
+		autoHealer.Start()
+	}
+
+	// cron can't express extra runs in schedule exceptions of jobs
+	var extraRunTrigger *job.ExtraRunTrigger
+	if interval := conf.GetServe().ExtraRunsIntervalSecs; interval > 0 {
+		extraRunTrigger = job.NewExtraRunTrigger(projectRepoFac, namespaceSpecRepoFac, jobService,
+			models.Scheduler, interval)
+		extraRunTrigger.Start()
+	}
+
+	// warns of runs taking much longer than recent runs of the job
+	var durationAnalyzer *job.DurationAnalyzer
+	if interval := conf.GetServe().DurationAnomaly.IntervalSecs; interval > 0 {
+		durationAnalyzer = job.NewDurationAnalyzer(projectRepoFac, namespaceSpecRepoFac, jobService, instanceService,
+			eventService, interval, conf.GetServe().DurationAnomaly.Factor)
+		durationAnalyzer.Start()
+	}
+
+	// reports jobs which look unused for owners to clean up
+	var staleJobAnalyzer *job.StaleJobAnalyzer
+	if interval := conf.GetServe().StaleJobs.IntervalSecs; interval > 0 {
+		staleJobAnalyzer = job.NewStaleJobAnalyzer(projectRepoFac, namespaceSpecRepoFac, &projectJobSpecRepoFac,
+			jobService, instanceService, models.DatastoreRegistry, interval,
+			time.Hour*24*time.Duration(conf.GetServe().StaleJobs.WindowDays))
+		staleJobAnalyzer.Start()
+	}
+
+	// fails runs which stopped reporting heartbeats so dead workers are noticed
+	var hungRunMonitor *job.HungRunMonitor
+	if hungRunsConf := conf.GetServe().HungRuns; hungRunsConf.IntervalSecs > 0 {
+		hungRunMonitor = job.NewHungRunMonitor(projectRepoFac, namespaceSpecRepoFac, jobService, instanceService,
+			models.Scheduler, eventService, hungRunsConf.IntervalSecs, hungRunsConf.SilenceSecs, hungRunsConf.Retry)
+		hungRunMonitor.Start()
+	}
+
+	// tables of runs, replays and audit logs would grow unbounded otherwise
+	var janitor *retention.Janitor
+	if retentionConf := conf.GetServe().Retention; retentionConf.IntervalSecs > 0 {
+		janitor = retention.NewJanitor(projectRepoFac, postgres.NewRetentionRepository(dbConn), projectArchiveRepo, metrics,
+			models.RetentionPolicy{
+				Instances: time.Hour * 24 * time.Duration(retentionConf.InstancesDays),
+				Replays:   time.Hour * 24 * time.Duration(retentionConf.ReplaysDays),
+				AuditLogs: time.Hour * 24 * time.Duration(retentionConf.AuditLogsDays),
+			}, retentionConf.IntervalSecs, retentionConf.BatchSize)
+		janitor.Start()
+	}
+
+	// partitions of destinations of jobs with a retention in their spec
+	var partitionJanitor *retention.PartitionJanitor
+	if interval := conf.GetServe().Retention.PartitionsIntervalSecs; interval > 0 {
+		partitionJanitor = retention.NewPartitionJanitor(projectRepoFac, namespaceSpecRepoFac, &projectJobSpecRepoFac,
+			models.DatastoreRegistry, metrics, interval)
+		partitionJanitor.Start()
+	}
+
+	for kind, limit := range conf.GetServe().ResourceConcurrency {
+		datastore.KindConcurrency[models.ResourceType(kind)] = limit
+	}
+	datastoreService := datastore.NewService(&resourceSpecRepoFac, models.DatastoreRegistry)
+	operationRepo := postgres.NewOperationRepository(dbConn)
+	operationManager := operation.NewManager(operationRepo, utils.NewUUIDProvider())
+
+	costBudgetMonitor := job.NewCostBudgetMonitor(postgres.NewProjectCostRepository(dbConn), eventService)
+
 	// runtime service instance over grpc
 	pb.RegisterRuntimeServiceServer(grpcServer, v1handler.NewRuntimeServiceServer(
 		config.Version,
-		job.NewService(
-			&jobSpecRepoFac,
-			&jobRepoFactory{
-				schd: models.Scheduler,
-			},
-			jobCompiler,
-			jobSpecAssetDump(),
-			dependencyResolver,
-			priorityResolver,
-			metaSvcFactory,
-			&projectJobSpecRepoFac,
-			replayManager,
-		),
+		jobService,
 		eventService,
-		datastore.NewService(&resourceSpecRepoFac, models.DatastoreRegistry),
+		datastoreService,
 		projectRepoFac,
 		namespaceSpecRepoFac,
 		projectSecretRepoFac,
 		v1.NewAdapter(models.PluginRegistry, models.DatastoreRegistry),
 		progressObs,
-		instance.NewService(
-			&instanceRepoFactory{
-				db: dbConn,
-			},
-			func() time.Time {
-				return time.Now().UTC()
-			},
-			instance.NewGoEngine(),
-		),
+		instanceService,
 		models.Scheduler,
+		quotaService,
+		changelogRepo,
+		job.NewCostCollector(instanceService, models.DatastoreRegistry, costBudgetMonitor),
+		commitstatus.NewPublisher(nil),
 	))
 
+	// tls is terminated by the listener serving both grpc and http, renewed
+	// certificates are picked up without restarting
+	var certReloader *certs.Reloader
+	if tlsConf := conf.GetServe().TLS; tlsConf.CertFile != "" || tlsConf.KeyFile != "" {
+		if certReloader, err = certs.NewReloader(tlsConf.CertFile, tlsConf.KeyFile, tlsConf.ClientCAFile,
+			tlsConf.RequireClientCert, tlsConf.ReloadIntervalSecs); err != nil {
+			return errors.Wrap(err, "certs.NewReloader")
+		}
+		if tlsConf.ReloadIntervalSecs > 0 {
+			certReloader.Start()
+		}
+	}
+
 	timeoutGrpcDialCtx, grpcDialCancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer grpcDialCancel()
 
@@ -465,10 +708,25 @@ func Initialize(conf config.Provider) error {
 	gwmux := runtime.NewServeMux(
 		runtime.WithErrorHandler(runtime.DefaultHTTPErrorHandler),
 	)
-	// gRPC dialup options to proxy http connections
-	grpcConn, err := grpc.DialContext(timeoutGrpcDialCtx, grpcAddr, []grpc.DialOption{
+	// gRPC dialup options to proxy http connections, with tls served the proxy
+	// calls grpc server in process as it has no client certificate to present
+	grpcDialAddr := grpcAddr
+	grpcDialOpts := []grpc.DialOption{
 		grpc.WithInsecure(),
-	}...)
+	}
+	if certReloader != nil {
+		inProcessLis := bufconn.Listen(inProcessConnBufferSize)
+		go func() {
+			if err := grpcServer.Serve(inProcessLis); err != nil {
+				mainLog.Errorf("in process grpc server error: %v\n", err)
+			}
+		}()
+		grpcDialAddr = "in-process"
+		grpcDialOpts = append(grpcDialOpts, grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return inProcessLis.Dial()
+		}))
+	}
+	grpcConn, err := grpc.DialContext(timeoutGrpcDialCtx, grpcDialAddr, grpcDialOpts...)
 	if err != nil {
 		return errors.Wrap(err, "grpc.DialContext")
 	}
@@ -478,25 +736,129 @@ func Initialize(conf config.Provider) error {
 		return errors.Wrap(err, "RegisterRuntimeServiceHandler")
 	}
 
+	// deploys projects from their git repository through the runtime service
+	// so they go through the same checks as deployments from cli, on its own
+	// connection as the gateway one carries tokens of http clients
+	gitSyncConn, err := grpc.DialContext(timeoutGrpcDialCtx, grpcDialAddr,
+		append(grpcDialOpts, tokenAuthenticator.InternalDialOptions()...)...)
+	if err != nil {
+		return errors.Wrap(err, "grpc.DialContext")
+	}
+	gitSyncer := gitsync.NewSyncer(projectRepoFac, gitsync.NewGit(),
+		gitsync.NewDeployer(pb.NewRuntimeServiceClient(gitSyncConn), v1.NewAdapter(models.PluginRegistry, models.DatastoreRegistry)),
+		models.PluginRegistry, models.DatastoreRegistry, conf.GetServe().GitSync.Dir, conf.GetServe().GitSync.IntervalSecs)
+	if conf.GetServe().GitSync.IntervalSecs > 0 {
+		gitSyncer.Start()
+	}
+
+	// metadata of projects is served to instances replicating this one,
+	// and replicated from the primary instance if this one has one
+	replicationStore := replication.NewStore(projectRepoFac, namespaceSpecRepoFac, &jobSpecRepoFac, &resourceSpecRepoFac,
+		models.DatastoreRegistry, v1.NewAdapter(models.PluginRegistry, models.DatastoreRegistry))
+	replicationStateRepo := postgres.NewReplicationStateRepository(dbConn)
+	var replicator *replication.Replicator
+	if replicationConf := conf.GetServe().Replication; replicationConf.PrimaryURL != "" && replicationConf.IntervalSecs > 0 {
+		source, err := replication.NewHTTPSource(replicationConf.PrimaryURL, replicationConf.Token,
+			replicationConf.TimeoutSecs)
+		if err != nil {
+			return err
+		}
+		replicator = replication.NewReplicator(source, replicationStore, replicationStateRepo, replicationConf.IntervalSecs,
+			replicationConf.KeepProjectConfig)
+		replicator.Start()
+	}
+
 	// base router
 	baseMux := http.NewServeMux()
 	baseMux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "pong")
 	})
+	baseMux.Handle("/livez", v1handler.LivenessHandler())
+	baseMux.Handle("/readyz", v1handler.NewReadinessHandler(
+		v1handler.DBHealthCheck(dbConn.DB()),
+		v1handler.PluginHealthCheck(models.PluginRegistry),
+		v1handler.SchedulerHealthCheck(models.Scheduler, projectRepoFac),
+	))
 	baseMux.Handle("/api/", http.StripPrefix("/api", gwmux))
+	baseMux.Handle("/logs", v1handler.NewLogHandler(jobService, models.Scheduler, projectRepoFac))
+	baseMux.Handle("/job-pause", v1handler.NewJobPauseHandler(jobService, models.Scheduler, projectRepoFac, namespaceSpecRepoFac))
+	baseMux.Handle("/job-rename", v1handler.NewJobRenameHandler(jobService, freezeGuard, projectRepoFac, namespaceSpecRepoFac))
+	baseMux.Handle("/checkpoints", v1handler.NewCheckpointHandler(checkpointRepo, jobService, projectRepoFac))
+	baseMux.Handle("/search", v1handler.NewSearchHandler(postgres.NewSearchRepository(dbConn)))
+	baseMux.Handle("/destinations", v1handler.NewDestinationHandler(postgres.NewDestinationRegistry(dbConn)))
+	baseMux.Handle("/job-stats", v1handler.NewJobStatsHandler(instanceService, jobService, projectRepoFac))
+	baseMux.Handle("/instance-heartbeats", v1handler.NewInstanceHeartbeatHandler(instanceService, jobService, projectRepoFac))
+	baseMux.Handle("/job-dependencies", v1handler.NewJobDependenciesHandler(jobService, projectRepoFac))
+	baseMux.Handle("/job-costs", v1handler.NewJobCostHandler(instanceService, jobService, projectRepoFac, namespaceSpecRepoFac))
+	baseMux.Handle("/dependency-freshness", v1handler.NewDependencyFreshnessHandler(job.NewDependencyFreshnessChecker(
+		projectRepoFac, namespaceSpecRepoFac, &projectJobSpecRepoFac, models.DatastoreRegistry), projectRepoFac))
+	baseMux.Handle("/replay-status", v1handler.NewReplayStatusHandler(replayManager, replayManager, replaySpecRepoFac, jobService, projectRepoFac))
+	baseMux.Handle("/replays", v1handler.NewReplayListHandler(replayManager, replaySpecRepoFac, jobService, projectRepoFac,
+		namespaceSpecRepoFac))
+	baseMux.Handle("/replay-plan", v1handler.NewReplayPlanHandler(jobService, quotaService, freezeGuard, replayManager, replayManager,
+		replaySpecRepoFac, projectRepoFac, namespaceSpecRepoFac))
+	baseMux.Handle("/operations", v1handler.NewOperationHandler(operationManager, operationRepo, projectRepoFac))
+	baseMux.Handle("/resource-restore", v1handler.NewResourceRestoreHandler(
+		datastore.NewRestorer(models.DatastoreRegistry, operationManager), projectRepoFac))
+	baseMux.Handle("/resource-import", v1handler.NewResourceImportHandler(models.DatastoreRegistry, projectRepoFac))
+	baseMux.Handle("/metrics", metrics)
+	baseMux.Handle("/stats", v1handler.NewStatsHandler(metrics, jobService, postgres.NewProjectQuotaRepository(dbConn),
+		costBudgetMonitor, projectRepoFac, namespaceSpecRepoFac))
+	if durationAnalyzer != nil {
+		baseMux.Handle("/anomalies", v1handler.NewDurationAnomalyHandler(durationAnalyzer, projectRepoFac))
+	}
+	baseMux.Handle("/webhook/git-sync", gitsync.NewWebhookHandler(gitSyncer, projectRepoFac))
+	baseMux.Handle("/deployments", gitsync.NewDeploymentHandler(gitSyncer, postgres.NewDeploymentRepository(dbConn),
+		projectRepoFac, utils.NewUUIDProvider()))
+	baseMux.Handle("/schema/job.json", schemaHandler(local.JobSpecSchema()))
+	baseMux.Handle("/schema/resource.json", schemaHandler(local.ResourceSpecSchema()))
+	if conf.GetServe().UI.Enabled {
+		baseMux.Handle("/ui/", http.StripPrefix("/ui", web.Handler()))
+	}
+
+	// admin endpoints need a token allowed to admin all projects
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/admin/audit", auditLogger.ListHandler())
+	adminMux.Handle("/admin/freeze", freezeGuard)
+	adminMux.Handle("/admin/tokens", tokenAuthenticator)
+	adminMux.Handle("/admin/changelog", v1handler.NewChangelogHandler(changelogRepo, projectRepoFac))
+	adminMux.Handle("/admin/quota", v1handler.NewQuotaHandler(quotaService, projectRepoFac))
+	adminMux.Handle("/admin/promote", v1handler.NewPromotionHandler(datastoreService, operationManager, projectRepoFac))
+	adminMux.Handle("/admin/template-migration", v1handler.NewTemplateMigrationHandler(jobService, operationManager,
+		projectRepoFac, namespaceSpecRepoFac))
+	adminMux.Handle("/admin/archive", v1handler.NewProjectArchiveHandler(jobService, models.Scheduler,
+		v1handler.NewAdapter(models.PluginRegistry, models.DatastoreRegistry), projectArchiveRepo, freezeRepo, operationManager,
+		projectRepoFac, namespaceSpecRepoFac, time.Hour*24*time.Duration(conf.GetServe().Retention.ArchivesDays)))
+	if staleJobAnalyzer != nil {
+		adminMux.Handle("/admin/stale-jobs", v1handler.NewStaleJobHandler(staleJobAnalyzer, projectRepoFac))
+	}
+	adminMux.Handle(replication.ExportPath, replication.NewExportHandler(replicationStore, replicationStateRepo))
+	if replicator != nil {
+		adminMux.Handle("/admin/replication", replicator)
+	}
+	baseMux.Handle("/admin/", tokenAuthenticator.AdminHandler(adminMux))
 
 	srv := &http.Server{
-		Handler:      grpcHandlerFunc(grpcServer, baseMux),
+		Handler:      grpcHandlerFunc(grpcServer, tokenAuthenticator.HTTPHandler(auditLogger.HTTPHandler(baseMux))),
 		Addr:         grpcAddr,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
 	}
 
+	if certReloader != nil {
+		srv.TLSConfig = certReloader.TLSConfig()
+	}
+
 	// run our server in a goroutine so that it doesn't block to wait for termination requests
 	go func() {
 		mainLog.Infoln("starting listening at ", grpcAddr)
-		if err := srv.ListenAndServe(); err != nil {
+		serve := srv.ListenAndServe
+		if certReloader != nil {
+			mainLog.Infoln("serving tls, client certificates required: ", conf.GetServe().TLS.RequireClientCert)
+			serve = func() error { return srv.ListenAndServeTLS("", "") }
+		}
+		if err := serve(); err != nil {
 			if err != http.ErrServerClosed {
 				mainLog.Fatalf("server error: %v\n", err)
 			}
@@ -513,9 +875,63 @@ func Initialize(conf config.Provider) error {
 	mainLog.Info("termination request received")
 	var terminalError error
 
+	if autoHealer != nil {
+		if err = autoHealer.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "autoHealer.Close"))
+		}
+	}
+	if extraRunTrigger != nil {
+		if err = extraRunTrigger.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "extraRunTrigger.Close"))
+		}
+	}
+	if durationAnalyzer != nil {
+		if err = durationAnalyzer.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "durationAnalyzer.Close"))
+		}
+	}
+	if staleJobAnalyzer != nil {
+		if err = staleJobAnalyzer.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "staleJobAnalyzer.Close"))
+		}
+	}
+	if hungRunMonitor != nil {
+		if err = hungRunMonitor.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "hungRunMonitor.Close"))
+		}
+	}
+	if janitor != nil {
+		if err = janitor.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "janitor.Close"))
+		}
+	}
+	if partitionJanitor != nil {
+		if err = partitionJanitor.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "partitionJanitor.Close"))
+		}
+	}
+	if replicator != nil {
+		if err = replicator.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "replicator.Close"))
+		}
+	}
+	if certReloader != nil {
+		if err = certReloader.Close(); err != nil {
+			terminalError = multierror.Append(terminalError, errors.Wrap(err, "certReloader.Close"))
+		}
+	}
+	if err = gitSyncer.Close(); err != nil {
+		terminalError = multierror.Append(terminalError, errors.Wrap(err, "gitSyncer.Close"))
+	}
 	if err = replayManager.Close(); err != nil {
 		terminalError = multierror.Append(terminalError, errors.Wrap(err, "replayManager.Close"))
 	}
+	if err = operationManager.Close(); err != nil {
+		terminalError = multierror.Append(terminalError, errors.Wrap(err, "operationManager.Close"))
+	}
+	if err = jobLocker.Close(); err != nil {
+		terminalError = multierror.Append(terminalError, errors.Wrap(err, "jobLocker.Close"))
+	}
 
 	// Create a deadline to wait for server
 	ctxProxy, cancelProxy := context.WithTimeout(context.Background(), shutdownWait)
@@ -528,6 +944,15 @@ func Initialize(conf config.Provider) error {
 	}
 	grpcServer.GracefulStop()
 
+	// release clients datastores pool across requests once no request is left
+	for _, ds := range models.DatastoreRegistry.GetAll() {
+		if closer, ok := ds.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				terminalError = multierror.Append(terminalError, errors.Wrapf(err, "%s.Close", ds.Name()))
+			}
+		}
+	}
+
 	// gracefully shutdown event service, e.g. slack notifiers flush in memory batches
 	cancelNotifiers()
 	if err := eventService.Close(); err != nil && len(err.Error()) != 0 {
@@ -555,6 +980,17 @@ func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Ha
 	}), &http2.Server{})
 }
 
+// schemaHandler serves json schema of specification files, clients
+// and editors can use it to validate specs before deploying them
+func schemaHandler(schema *jsonschema.Schema) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/schema+json")
+		if err := json.NewEncoder(w).Encode(schema); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
 // NewKafkaWriter creates a new kafka client that will be used for meta publishing
 func NewKafkaWriter(topic string, brokers []string, batchSize int) *kafka.Writer {
 	// check if metadata publisher is disabled
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/odpf/optimus/config"
+	"github.com/pkg/errors"
+	cli "github.com/spf13/cobra"
+)
+
+const (
+	githubReleaseByTagURL = "https://api.github.com/repos/odpf/optimus/releases/tags/%s"
+	releaseChecksumsFile  = "checksums.txt"
+
+	upgradeTimeout = time.Minute * 5
+)
+
+var (
+	// releaseOSNames and releaseArchNames map go platform names to the
+	// ones used in release archives, see .goreleaser.yml
+	releaseOSNames = map[string]string{
+		"darwin": "macos",
+	}
+	releaseArchNames = map[string]string{
+		"amd64": "x86_64",
+		"386":   "i386",
+		"arm":   "armv6",
+	}
+)
+
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name        string `json:"name"`
+		DownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (r githubRelease) assetURL(name string) (string, error) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset.DownloadURL, nil
+		}
+	}
+	return "", errors.Errorf("release %s has no asset %s", r.TagName, name)
+}
+
+func upgradeCommand(l logger) *cli.Command {
+	var (
+		targetVersion string
+		checkOnly     bool
+		force         bool
+	)
+	cmd := &cli.Command{
+		Use:   "upgrade",
+		Short: "Upgrade optimus client to the latest or a specific version",
+		Long: "Upgrade optimus client to the latest or a specific version.\n" +
+			"Release archive of the current platform is downloaded from github and its sha256 is matched with\n" +
+			"the release checksums.txt. Checksums are not signed and come from the same release, so this only\n" +
+			"guards against corrupt or truncated downloads, not against a tampered release.",
+		Example: "optimus upgrade\n" +
+			"optimus upgrade --version v0.0.5",
+	}
+	cmd.Flags().StringVar(&targetVersion, "version", "", "version to install, latest release if empty")
+	cmd.Flags().BoolVar(&checkOnly, "check", false, "only check if a new version is available")
+	cmd.Flags().BoolVar(&force, "force", false, "install even if the version is not newer than current")
+
+	cmd.RunE = func(c *cli.Command, args []string) error {
+		httpClient := &http.Client{Timeout: upgradeTimeout}
+		release, err := getRelease(httpClient, targetVersion)
+		if err != nil {
+			return err
+		}
+
+		latestV, err := version.NewVersion(release.TagName)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse release version")
+		}
+		if currentV, err := version.NewVersion(config.Version); err == nil && !force && !currentV.LessThan(latestV) {
+			l.Printf("optimus %s is already up to date\n", config.Version)
+			return nil
+		}
+		if checkOnly {
+			l.Printf("new version is available: %s\n", coloredNotice(latestV))
+			return nil
+		}
+
+		l.Printf("upgrading optimus %s to %s for %s/%s\n", config.Version, release.TagName, runtime.GOOS, runtime.GOARCH)
+		binary, err := downloadReleaseBinary(httpClient, release, runtime.GOOS, runtime.GOARCH)
+		if err != nil {
+			return err
+		}
+
+		exePath, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		if exePath, err = filepath.EvalSymlinks(exePath); err != nil {
+			return err
+		}
+		if err := replaceExecutable(exePath, binary); err != nil {
+			return errors.Wrapf(err, "failed to replace %s", exePath)
+		}
+		l.Println(coloredSuccess(fmt.Sprintf("optimus upgraded to %s", release.TagName)))
+		return nil
+	}
+	return cmd
+}
+
+func getRelease(client *http.Client, tag string) (githubRelease, error) {
+	release := githubRelease{}
+	reqURL := githubReleaseURL
+	if tag != "" {
+		reqURL = fmt.Sprintf(githubReleaseByTagURL, tag)
+	}
+	body, err := httpGet(client, reqURL)
+	if err != nil {
+		return release, errors.Wrap(err, "failed to get release from github")
+	}
+	if err := json.Unmarshal(body, &release); err != nil {
+		return release, errors.Wrap(err, "failed to parse release")
+	}
+	return release, nil
+}
+
+// downloadReleaseBinary fetches release archive of the platform, verifies
+// its checksum and returns the optimus binary packed inside it. checksums.txt
+// is unsigned and served by the same release, the check is only for integrity
+func downloadReleaseBinary(client *http.Client, release githubRelease, goos, goarch string) ([]byte, error) {
+	archiveName := releaseArchiveName(release.TagName, goos, goarch)
+	archiveURL, err := release.assetURL(archiveName)
+	if err != nil {
+		return nil, err
+	}
+	checksumsURL, err := release.assetURL(releaseChecksumsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums, err := httpGet(client, checksumsURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download checksums")
+	}
+	archive, err := httpGet(client, archiveURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %s", archiveName)
+	}
+	if err := verifyChecksum(archive, archiveName, checksums); err != nil {
+		return nil, err
+	}
+
+	binaryName := "optimus"
+	if goos == "windows" {
+		binaryName += ".exe"
+		return extractFromZip(archive, binaryName)
+	}
+	return extractFromTarGz(archive, binaryName)
+}
+
+// releaseArchiveName follows the default goreleaser archive name template
+func releaseArchiveName(tag, goos, goarch string) string {
+	osName, ok := releaseOSNames[goos]
+	if !ok {
+		osName = goos
+	}
+	archName, ok := releaseArchNames[goarch]
+	if !ok {
+		archName = goarch
+	}
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("optimus_%s_%s_%s.%s", strings.TrimPrefix(tag, "v"), osName, archName, ext)
+}
+
+// verifyChecksum matches sha256 of content with the one listed in checksums
+// file, each line of which is in "<sha256>  <file name>" format
+func verifyChecksum(content []byte, name string, checksums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[1] != name {
+			continue
+		}
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != fields[0] {
+			return errors.Errorf("checksum mismatch for %s", name)
+		}
+		return nil
+	}
+	return errors.Errorf("checksum not found for %s", name)
+}
+
+func extractFromTarGz(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == name {
+			return ioutil.ReadAll(tr)
+		}
+	}
+	return nil, errors.Errorf("%s not found in archive", name)
+}
+
+func extractFromZip(archive []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range zr.File {
+		if filepath.Base(file.Name) != name {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+	return nil, errors.Errorf("%s not found in archive", name)
+}
+
+// replaceExecutable writes the new binary next to the current one and swaps
+// them, running executable can't be overwritten on windows but can be renamed
+func replaceExecutable(exePath string, binary []byte) error {
+	newPath := exePath + ".new"
+	oldPath := exePath + ".old"
+	if err := ioutil.WriteFile(newPath, binary, 0755); err != nil {
+		return err
+	}
+
+	_ = os.Remove(oldPath)
+	if err := os.Rename(exePath, oldPath); err != nil {
+		os.Remove(newPath)
+		return err
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		// restore the previous binary
+		_ = os.Rename(oldPath, exePath)
+		return err
+	}
+	if runtime.GOOS != "windows" {
+		_ = os.Remove(oldPath)
+	}
+	return nil
+}
+
+func httpGet(client *http.Client, reqURL string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "optimus")
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d from %s", res.StatusCode, reqURL)
+	}
+	return ioutil.ReadAll(res.Body)
+}
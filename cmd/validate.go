@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"time"
 
 	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/core/cron"
 
 	"github.com/odpf/optimus/models"
 
 	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	cli "github.com/spf13/cobra"
 	"google.golang.org/grpc"
 )
@@ -20,36 +23,50 @@ const (
 	validateTimeout = time.Minute * 3
 )
 
-func validateCommand(l logger, host string, pluginRepo models.PluginRepository, jobSpecRepo JobSpecRepository) *cli.Command {
+func validateCommand(l logger, host string, pluginRepo models.PluginRepository, jobSpecRepo JobSpecRepository,
+	jobSpecFs afero.Fs) *cli.Command {
 	cmd := &cli.Command{
 		Use:   "validate",
 		Short: "check if specifications are valid for deployment",
 	}
 	if jobSpecRepo != nil {
-		cmd.AddCommand(validateJobCommand(l, host, pluginRepo, jobSpecRepo))
+		cmd.AddCommand(validateJobCommand(l, host, pluginRepo, jobSpecRepo, jobSpecFs))
 	}
 	return cmd
 }
 
-func validateJobCommand(l logger, host string, pluginRepo models.PluginRepository, jobSpecRepo JobSpecRepository) *cli.Command {
+func validateJobCommand(l logger, host string, pluginRepo models.PluginRepository, jobSpecRepo JobSpecRepository,
+	jobSpecFs afero.Fs) *cli.Command {
 	var projectName string
 	var namespace string
+	var nextRuns int
 	cmd := &cli.Command{
-		Use:     "job",
-		Short:   "run basic checks on all jobs",
-		Example: "optimus validate job",
+		Use:   "job",
+		Short: "run basic checks on all jobs",
+		Example: "optimus validate job\n" +
+			"optimus validate job --next-runs 3",
 	}
 	cmd.Flags().StringVar(&projectName, "project", "", "name of the project")
 	cmd.MarkFlagRequired("project")
 	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace")
 	cmd.MarkFlagRequired("namespace")
+	cmd.Flags().IntVar(&nextRuns, "next-runs", 0, "print the schedule of every job in words along with its next runs")
 
 	cmd.RunE = func(c *cli.Command, args []string) error {
 		start := time.Now()
+		if err := validateJobSpecFiles(jobSpecFs); err != nil {
+			l.Println(coloredError("job specifications do not match the schema"))
+			return err
+		}
 		jobSpecs, err := jobSpecRepo.GetAll()
 		if err != nil {
 			return err
 		}
+		if nextRuns > 0 {
+			if err := printJobSchedules(l, jobSpecs, nextRuns, time.Now()); err != nil {
+				return err
+			}
+		}
 		if err := validateJobSpecificationRequest(l, projectName, namespace, pluginRepo, jobSpecs, host); err != nil {
 			return err
 		}
@@ -62,6 +79,37 @@ func validateJobCommand(l logger, host string, pluginRepo models.PluginRepositor
 	return cmd
 }
 
+// printJobSchedules prints the schedule of jobs in words along with their
+// next n runs after now, within start and end dates of their schedule
+func printJobSchedules(l logger, jobSpecs []models.JobSpec, n int, now time.Time) error {
+	sorted := append([]models.JobSpec{}, jobSpecs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	for _, spec := range sorted {
+		if spec.Schedule.Interval == "" {
+			l.Printf("%s: not scheduled, runs only when triggered\n", spec.Name)
+			continue
+		}
+		schd, err := cron.ParseCronSchedule(spec.Schedule.Interval)
+		if err != nil {
+			return errors.Wrapf(err, "invalid schedule interval of %s", spec.Name)
+		}
+		l.Printf("%s: %s\n", spec.Name, schd.Describe())
+
+		from := now
+		if spec.Schedule.StartDate.After(from) {
+			from = spec.Schedule.StartDate.Add(-time.Second)
+		}
+		for _, run := range schd.NextN(from, n) {
+			if end := spec.Schedule.EndDate; end != nil && run.After(*end) {
+				l.Println("\tschedule ends at " + end.Format(models.JobDatetimeLayout))
+				break
+			}
+			l.Println("\t" + run.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
 func validateJobSpecificationRequest(l logger, projectName string, namespace string,
 	pluginRepo models.PluginRepository, jobSpecs []models.JobSpec, host string) (err error) {
 	adapt := v1handler.NewAdapter(pluginRepo, models.DatastoreRegistry)
@@ -78,7 +126,7 @@ func validateJobSpecificationRequest(l logger, projectName string, namespace str
 	}
 	defer conn.Close()
 
-	dumpTimeoutCtx, dumpCancel := context.WithTimeout(context.Background(), validateTimeout)
+	dumpTimeoutCtx, dumpCancel := context.WithTimeout(context.Background(), callTimeout(validateTimeout))
 	defer dumpCancel()
 
 	adaptedJobSpecs := []*pb.JobSpecification{}
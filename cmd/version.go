@@ -116,7 +116,7 @@ func checkLatestVersion(l logger) {
 	}
 
 	if currentV.LessThan(latestV) {
-		l.Printf("new version is available: %s, consider updating the client", coloredNotice(latestV))
+		l.Printf("new version is available: %s, consider updating the client with 'optimus upgrade'", coloredNotice(latestV))
 	}
 }
 
@@ -131,7 +131,7 @@ func getVersionRequest(clientVer string, host string) (ver string, err error) {
 	}
 	defer conn.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout(versionTimeout))
 	defer cancel()
 
 	runtime := pb.NewRuntimeServiceClient(conn)
@@ -29,6 +29,8 @@ var (
 	KeyServeDBDSN                   = "serve.db.dsn"
 	KeyServeDBMaxIdleConnection     = "serve.db.max_idle_connection"
 	KeyServeDBMaxOpenConnection     = "serve.db.max_open_connection"
+	KeyServeDBAutoMigrate           = "serve.db.auto_migrate"
+	KeyServeDBSlowQueryMillis       = "serve.db.slow_query_ms"
 	KeyServeMetadataWriterBatchSize = "serve.metadata.writer_batch_size"
 	KeyServeMetadataKafkaBrokers    = "serve.metadata.kafka_brokers"
 	KeyServeMetadataKafkaJobTopic   = "serve.metadata.kafka_job_topic"
@@ -36,10 +38,70 @@ var (
 	KeyServeReplayNumWorkers        = "serve.replay_num_workers"
 	KeyServeReplayWorkerTimeoutSecs = "serve.replay_worker_timeout_secs"
 	KeyServeReplayRunTimeoutSecs    = "serve.replay_run_timeout_secs"
+	KeyServeReplayQueueSize         = "serve.replay_queue_size"
+	KeyServeQuotaMaxJobs            = "serve.quota.max_jobs"
+	KeyServeQuotaMaxResources       = "serve.quota.max_resources"
+	KeyServeQuotaMaxReplayRuns      = "serve.quota.max_replay_runs_per_day"
+	KeyServeSecretCacheTTLSecs      = "serve.secret_cache_ttl_secs"
+	KeyServeSpecCacheTTLSecs        = "serve.spec_cache_ttl_secs"
+	KeyServeAutoHealIntervalSecs    = "serve.auto_heal_interval_secs"
+	KeyServeExtraRunsIntervalSecs   = "serve.extra_runs_interval_secs"
+	KeyServeGitSyncIntervalSecs     = "serve.git_sync.interval_secs"
+	KeyServeGitSyncDir              = "serve.git_sync.dir"
+	KeyServeDurationAnomalyInterval = "serve.duration_anomaly.interval_secs"
+	KeyServeDurationAnomalyFactor   = "serve.duration_anomaly.factor"
+	KeyServeStaleJobsIntervalSecs   = "serve.stale_jobs.interval_secs"
+	KeyServeStaleJobsWindowDays     = "serve.stale_jobs.window_days"
+	KeyServeHungRunsIntervalSecs    = "serve.hung_runs.interval_secs"
+	KeyServeHungRunsSilenceSecs     = "serve.hung_runs.silence_secs"
+	KeyServeHungRunsRetry           = "serve.hung_runs.retry"
+	KeyServeRetentionIntervalSecs   = "serve.retention.interval_secs"
+	KeyServeRetentionBatchSize      = "serve.retention.batch_size"
+	KeyServeRetentionInstancesDays  = "serve.retention.instances_days"
+	KeyServeRetentionReplaysDays    = "serve.retention.replays_days"
+	KeyServeRetentionAuditLogsDays  = "serve.retention.audit_logs_days"
+	KeyServeRetentionArchivesDays   = "serve.retention.archives_days"
+	KeyServeRetentionPartitionsSecs = "serve.retention.partitions_interval_secs"
+	KeyServeReplicationPrimaryURL   = "serve.replication.primary_url"
+	KeyServeReplicationIntervalSecs = "serve.replication.interval_secs"
+	KeyServeReplicationToken        = "serve.replication.token"
+	KeyServeReplicationTimeoutSecs  = "serve.replication.timeout_secs"
+	KeyServeReplicationKeepConfig   = "serve.replication.keep_project_config"
+	KeyServeGRPCMaxRecvMsgSizeMB    = "serve.grpc.max_recv_msg_size_mb"
+	KeyServeGRPCMaxSendMsgSizeMB    = "serve.grpc.max_send_msg_size_mb"
+	KeyServeGRPCUnaryTimeoutSecs    = "serve.grpc.unary_timeout_secs"
+	KeyServeGRPCStreamTimeoutSecs   = "serve.grpc.stream_timeout_secs"
+	KeyServeTLSCertFile             = "serve.tls.cert_file"
+	KeyServeTLSKeyFile              = "serve.tls.key_file"
+	KeyServeTLSClientCAFile         = "serve.tls.client_ca_file"
+	KeyServeTLSRequireClientCert    = "serve.tls.require_client_cert"
+	KeyServeTLSReloadIntervalSecs   = "serve.tls.reload_interval_secs"
+	KeyServeResourceConcurrency     = "serve.resource_concurrency"
+	KeyServeUIEnabled               = "serve.ui.enabled"
+	KeyServeAuthRequireToken        = "serve.auth.require_token"
+	KeyServeSMTPHost                = "serve.smtp.host"
+	KeyServeSMTPPort                = "serve.smtp.port"
+	KeyServeSMTPUsername            = "serve.smtp.username"
+	KeyServeSMTPPassword            = "serve.smtp.password"
+	KeyServeSMTPFrom                = "serve.smtp.from"
+
+	KeyClientDialTimeoutSecs      = "client.dial_timeout_secs"
+	KeyClientCallTimeoutSecs      = "client.call_timeout_secs"
+	KeyClientRetryMaxAttempts     = "client.retry.max_attempts"
+	KeyClientRetryBackoffMillis   = "client.retry.backoff_ms"
+	KeyClientKeepaliveTimeSecs    = "client.keepalive.time_secs"
+	KeyClientKeepaliveTimeoutSecs = "client.keepalive.timeout_secs"
+	KeyClientTLSEnabled           = "client.tls.enabled"
+	KeyClientTLSCAFile            = "client.tls.ca_file"
+	KeyClientTLSCertFile          = "client.tls.cert_file"
+	KeyClientTLSKeyFile           = "client.tls.key_file"
+	KeyClientTLSServerName        = "client.tls.server_name"
 
 	KeySchedulerName = "scheduler.name"
 
 	KeyAdminEnabled = "admin.enabled"
+
+	KeyExtensions = "extensions"
 )
 
 type Optimus struct {
@@ -55,6 +117,9 @@ type Optimus struct {
 	Datastore []Datastore   `yaml:"datastore"`
 	Config    ProjectConfig `yaml:"config"`
 
+	// cli extensions by name, path to the binary
+	Extensions map[string]string `yaml:"extensions"`
+
 	k      *koanf.Koanf
 	parser koanf.Parser
 }
@@ -80,6 +145,55 @@ type ProjectConfig struct {
 	Local map[string]string `yaml:"local"`
 }
 
+// ClientConfig configures how cli calls optimus server
+type ClientConfig struct {
+	// time to wait for connection to server
+	DialTimeoutSecs time.Duration `yaml:"dial_timeout_secs"`
+
+	// calls running longer are cancelled, zero keeps the default
+	// timeout of each command
+	CallTimeoutSecs time.Duration `yaml:"call_timeout_secs"`
+
+	Retry     ClientRetryConfig     `yaml:"retry"`
+	Keepalive ClientKeepaliveConfig `yaml:"keepalive"`
+	TLS       ClientTLSConfig       `yaml:"tls"`
+}
+
+// ClientRetryConfig configures retry of idempotent calls failed
+// because server was unavailable
+type ClientRetryConfig struct {
+	// attempts of a call including the first one, one disables retry
+	MaxAttempts int `yaml:"max_attempts"`
+
+	// wait before the first retry, doubled for every next one
+	BackoffMillis time.Duration `yaml:"backoff_ms"`
+}
+
+// ClientKeepaliveConfig configures pings to detect broken connections
+type ClientKeepaliveConfig struct {
+	// idle time after which connection is pinged, zero disables pings
+	TimeSecs time.Duration `yaml:"time_secs"`
+
+	// time to wait for ping to be acknowledged before closing connection
+	TimeoutSecs time.Duration `yaml:"timeout_secs"`
+}
+
+// ClientTLSConfig configures tls of connection to server, client
+// certificate is sent to servers requiring mutual tls
+type ClientTLSConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// certificate authority to verify server with, system roots if empty
+	CAFile string `yaml:"ca_file"`
+
+	// client certificate and its key, both are required for mutual tls
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+
+	// name to verify server certificate against, host if empty
+	ServerName string `yaml:"server_name"`
+}
+
 type LogConfig struct {
 	// log level - debug, info, warning, error, fatal
 	Level string `yaml:"level"`
@@ -105,6 +219,218 @@ type ServerConfig struct {
 	ReplayNumWorkers        int            `yaml:"replay_num_workers"`
 	ReplayWorkerTimeoutSecs time.Duration  `yaml:"replay_worker_timeout_secs"`
 	ReplayRunTimeoutSecs    time.Duration  `yaml:"replay_run_timeout_secs"`
+	ReplayQueueSize         int            `yaml:"replay_queue_size"`
+	Quota                   QuotaConfig    `yaml:"quota"`
+	SecretCacheTTLSecs      time.Duration  `yaml:"secret_cache_ttl_secs"`
+	SpecCacheTTLSecs        time.Duration  `yaml:"spec_cache_ttl_secs"`
+
+	// interval to look for failed runs of jobs opted in to auto-heal,
+	// zero disables auto-heal
+	AutoHealIntervalSecs time.Duration `yaml:"auto_heal_interval_secs"`
+
+	// interval to look for due extra runs in schedule exceptions of jobs,
+	// zero disables triggering them
+	ExtraRunsIntervalSecs time.Duration `yaml:"extra_runs_interval_secs"`
+
+	GitSync GitSyncConfig `yaml:"git_sync"`
+
+	DurationAnomaly DurationAnomalyConfig `yaml:"duration_anomaly"`
+
+	StaleJobs StaleJobsConfig `yaml:"stale_jobs"`
+
+	HungRuns HungRunsConfig `yaml:"hung_runs"`
+
+	Retention RetentionConfig `yaml:"retention"`
+
+	Replication ReplicationConfig `yaml:"replication"`
+
+	GRPC GRPCConfig `yaml:"grpc"`
+
+	TLS TLSConfig `yaml:"tls"`
+
+	// number of resources of each kind deployed at once, e.g. table: 20,
+	// overrides the defaults of kinds set
+	ResourceConcurrency map[string]int `yaml:"resource_concurrency"`
+
+	UI UIConfig `yaml:"ui"`
+
+	Auth AuthConfig `yaml:"auth"`
+
+	SMTP SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig configures the smtp server notifications are emailed through
+type SMTPConfig struct {
+	// host of the smtp server, email notifications are disabled if empty
+	Host string `yaml:"host"`
+
+	Port int `yaml:"port"`
+
+	// credentials of the smtp server, emails are sent without
+	// authentication if username is empty
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+
+	// address emails are sent from
+	From string `yaml:"from"`
+}
+
+// AuthConfig configures authentication of calls with api tokens, tokens
+// sent are always validated
+type AuthConfig struct {
+	// reject calls without a token, except health checks, metrics and
+	// the web UI
+	RequireToken bool `yaml:"require_token"`
+}
+
+// UIConfig configures the web UI served at /ui/, a read-only browser of
+// projects
+type UIConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// TLSConfig configures tls of the listener serving grpc and http api
+type TLSConfig struct {
+	// certificate served along with its key, tls is served once
+	// both are set
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+
+	// certificate authority client certificates are verified against
+	ClientCAFile string `yaml:"client_ca_file"`
+
+	// reject clients without a certificate signed by client_ca_file
+	RequireClientCert bool `yaml:"require_client_cert"`
+
+	// interval to check files for renewed certificates, zero disables
+	// reloading them
+	ReloadIntervalSecs time.Duration `yaml:"reload_interval_secs"`
+}
+
+// GRPCConfig limits calls to the grpc api
+type GRPCConfig struct {
+	// max size of a message received, larger ones are rejected
+	MaxRecvMsgSizeMB int `yaml:"max_recv_msg_size_mb"`
+
+	// max size of a message sent in response
+	MaxSendMsgSizeMB int `yaml:"max_send_msg_size_mb"`
+
+	// unary calls running longer are cancelled, clients can set a
+	// shorter deadline, zero doesn't limit them
+	UnaryTimeoutSecs time.Duration `yaml:"unary_timeout_secs"`
+
+	// streaming calls, e.g. deployments, running longer are cancelled,
+	// zero doesn't limit them
+	StreamTimeoutSecs time.Duration `yaml:"stream_timeout_secs"`
+}
+
+// GitSyncConfig configures deployment of projects from the git repository
+// set in their project config
+type GitSyncConfig struct {
+	// interval to check repositories for new commits, zero disables
+	// periodic sync leaving only webhook triggered ones
+	IntervalSecs time.Duration `yaml:"interval_secs"`
+
+	// directory where repositories are checked out, temporary
+	// directory of host is used if empty
+	Dir string `yaml:"dir"`
+}
+
+// DurationAnomalyConfig configures detection of runs of jobs taking much
+// longer than their recent runs
+type DurationAnomalyConfig struct {
+	// interval to check latest runs of jobs, zero disables detection
+	IntervalSecs time.Duration `yaml:"interval_secs"`
+
+	// runs taking longer than factor times the p95 duration of recent
+	// runs of the job are anomalies
+	Factor float64 `yaml:"factor"`
+}
+
+// StaleJobsConfig configures detection of jobs which look unused, without
+// successful runs or reads of their destination
+type StaleJobsConfig struct {
+	// interval to look for stale jobs, zero disables detection
+	IntervalSecs time.Duration `yaml:"interval_secs"`
+
+	// jobs are judged by their runs and reads within these last days
+	WindowDays int `yaml:"window_days"`
+}
+
+// HungRunsConfig configures detection of runs which stopped reporting
+// heartbeats while running, e.g. as their worker died
+type HungRunsConfig struct {
+	// interval to look for hung runs, zero disables detection
+	IntervalSecs time.Duration `yaml:"interval_secs"`
+
+	// runs which reported no heartbeat for longer are marked failed
+	SilenceSecs time.Duration `yaml:"silence_secs"`
+
+	// clear hung runs in scheduler so they run again, a run is retried
+	// once
+	Retry bool `yaml:"retry"`
+}
+
+// RetentionConfig configures deletion of expired data of projects, days are
+// the default retention of projects which don't configure their own and
+// zero keeps data forever
+type RetentionConfig struct {
+	// interval to delete expired rows, zero disables deletion
+	IntervalSecs time.Duration `yaml:"interval_secs"`
+
+	// rows deleted in a statement
+	BatchSize int `yaml:"batch_size"`
+
+	// days runs of jobs are kept for
+	InstancesDays int `yaml:"instances_days"`
+
+	// days replays are kept for once they end
+	ReplaysDays int `yaml:"replays_days"`
+
+	// days audit logs of api calls are kept for
+	AuditLogsDays int `yaml:"audit_logs_days"`
+
+	// days jobs of archived projects are kept for before they are purged,
+	// unless set while archiving
+	ArchivesDays int `yaml:"archives_days"`
+
+	// interval to drop partitions of destinations of jobs older than the
+	// retention in their spec, zero disables dropping
+	PartitionsIntervalSecs time.Duration `yaml:"partitions_interval_secs"`
+}
+
+// ReplicationConfig configures replication of metadata of projects from
+// another instance, e.g. to keep a disaster recovery instance current
+type ReplicationConfig struct {
+	// url of the instance replicated from, replication is disabled if empty
+	PrimaryURL string `yaml:"primary_url"`
+
+	// interval to fetch metadata of projects from the primary instance,
+	// zero disables replication
+	IntervalSecs time.Duration `yaml:"interval_secs"`
+
+	// api token of the primary instance with admin access
+	Token string `yaml:"token"`
+
+	// time allowed to fetch metadata of projects from the primary instance
+	TimeoutSecs time.Duration `yaml:"timeout_secs"`
+
+	// project config keys whose value of this instance is kept, e.g. the
+	// scheduler of projects in this region
+	KeepProjectConfig []string `yaml:"keep_project_config"`
+}
+
+// QuotaConfig is the default quota of projects which don't have one
+// configured explicitly, zero means unlimited
+type QuotaConfig struct {
+	// max jobs across all namespaces of a project
+	MaxJobs int `yaml:"max_jobs"`
+
+	// max resources of a datastore across all namespaces of a project
+	MaxResources int `yaml:"max_resources"`
+
+	// max job runs a project can replay in a day(UTC)
+	MaxReplayRunsPerDay int `yaml:"max_replay_runs_per_day"`
 }
 
 type DBConfig struct {
@@ -117,6 +443,13 @@ type DBConfig struct {
 
 	// maximum allowed open DB connections
 	MaxOpenConnection int `yaml:"max_open_connection"`
+
+	// run migrations of the database on start of server, disabled when
+	// they are run with migrate command, e.g. in an init container
+	AutoMigrate bool `yaml:"auto_migrate"`
+
+	// queries of repositories taking longer are logged, zero logs none
+	SlowQueryMillis time.Duration `yaml:"slow_query_ms"`
 }
 
 type MetadataConfig struct {
@@ -156,6 +489,28 @@ func (o Optimus) GetHost() string {
 	return o.k.String(KeyHost)
 }
 
+func (o Optimus) GetClient() ClientConfig {
+	return ClientConfig{
+		DialTimeoutSecs: time.Second * time.Duration(o.k.Int(KeyClientDialTimeoutSecs)),
+		CallTimeoutSecs: time.Second * time.Duration(o.k.Int(KeyClientCallTimeoutSecs)),
+		Retry: ClientRetryConfig{
+			MaxAttempts:   o.k.Int(KeyClientRetryMaxAttempts),
+			BackoffMillis: time.Millisecond * time.Duration(o.k.Int(KeyClientRetryBackoffMillis)),
+		},
+		Keepalive: ClientKeepaliveConfig{
+			TimeSecs:    time.Second * time.Duration(o.k.Int(KeyClientKeepaliveTimeSecs)),
+			TimeoutSecs: time.Second * time.Duration(o.k.Int(KeyClientKeepaliveTimeoutSecs)),
+		},
+		TLS: ClientTLSConfig{
+			Enabled:    o.k.Bool(KeyClientTLSEnabled),
+			CAFile:     o.k.String(KeyClientTLSCAFile),
+			CertFile:   o.k.String(KeyClientTLSCertFile),
+			KeyFile:    o.k.String(KeyClientTLSKeyFile),
+			ServerName: o.k.String(KeyClientTLSServerName),
+		},
+	}
+}
+
 func (o Optimus) GetJob() Job {
 	return Job{
 		Path: o.k.String(KeyJobPath),
@@ -185,6 +540,8 @@ func (o Optimus) GetServe() ServerConfig {
 			DSN:               o.k.String(KeyServeDBDSN),
 			MaxIdleConnection: o.eKi(KeyServeDBMaxIdleConnection),
 			MaxOpenConnection: o.eKi(KeyServeDBMaxOpenConnection),
+			AutoMigrate:       o.k.Bool(KeyServeDBAutoMigrate),
+			SlowQueryMillis:   time.Millisecond * time.Duration(o.k.Int(KeyServeDBSlowQueryMillis)),
 		},
 		Metadata: MetadataConfig{
 			WriterBatchSize: o.eKi(KeyServeMetadataWriterBatchSize),
@@ -195,6 +552,77 @@ func (o Optimus) GetServe() ServerConfig {
 		ReplayNumWorkers:        o.k.Int(KeyServeReplayNumWorkers),
 		ReplayWorkerTimeoutSecs: time.Second * time.Duration(o.k.Int(KeyServeReplayWorkerTimeoutSecs)),
 		ReplayRunTimeoutSecs:    time.Second * time.Duration(o.k.Int(KeyServeReplayRunTimeoutSecs)),
+		ReplayQueueSize:         o.k.Int(KeyServeReplayQueueSize),
+		Quota: QuotaConfig{
+			MaxJobs:             o.k.Int(KeyServeQuotaMaxJobs),
+			MaxResources:        o.k.Int(KeyServeQuotaMaxResources),
+			MaxReplayRunsPerDay: o.k.Int(KeyServeQuotaMaxReplayRuns),
+		},
+		SecretCacheTTLSecs:    time.Second * time.Duration(o.k.Int(KeyServeSecretCacheTTLSecs)),
+		SpecCacheTTLSecs:      time.Second * time.Duration(o.k.Int(KeyServeSpecCacheTTLSecs)),
+		AutoHealIntervalSecs:  time.Second * time.Duration(o.k.Int(KeyServeAutoHealIntervalSecs)),
+		ExtraRunsIntervalSecs: time.Second * time.Duration(o.k.Int(KeyServeExtraRunsIntervalSecs)),
+		GitSync: GitSyncConfig{
+			IntervalSecs: time.Second * time.Duration(o.k.Int(KeyServeGitSyncIntervalSecs)),
+			Dir:          o.k.String(KeyServeGitSyncDir),
+		},
+		DurationAnomaly: DurationAnomalyConfig{
+			IntervalSecs: time.Second * time.Duration(o.k.Int(KeyServeDurationAnomalyInterval)),
+			Factor:       o.k.Float64(KeyServeDurationAnomalyFactor),
+		},
+		StaleJobs: StaleJobsConfig{
+			IntervalSecs: time.Second * time.Duration(o.k.Int(KeyServeStaleJobsIntervalSecs)),
+			WindowDays:   o.k.Int(KeyServeStaleJobsWindowDays),
+		},
+		HungRuns: HungRunsConfig{
+			IntervalSecs: time.Second * time.Duration(o.k.Int(KeyServeHungRunsIntervalSecs)),
+			SilenceSecs:  time.Second * time.Duration(o.k.Int(KeyServeHungRunsSilenceSecs)),
+			Retry:        o.k.Bool(KeyServeHungRunsRetry),
+		},
+		Retention: RetentionConfig{
+			IntervalSecs:  time.Second * time.Duration(o.k.Int(KeyServeRetentionIntervalSecs)),
+			BatchSize:     o.k.Int(KeyServeRetentionBatchSize),
+			InstancesDays: o.k.Int(KeyServeRetentionInstancesDays),
+			ReplaysDays:   o.k.Int(KeyServeRetentionReplaysDays),
+			AuditLogsDays: o.k.Int(KeyServeRetentionAuditLogsDays),
+			ArchivesDays:  o.k.Int(KeyServeRetentionArchivesDays),
+
+			PartitionsIntervalSecs: time.Second * time.Duration(o.k.Int(KeyServeRetentionPartitionsSecs)),
+		},
+		Replication: ReplicationConfig{
+			PrimaryURL:        o.eKs(KeyServeReplicationPrimaryURL),
+			IntervalSecs:      time.Second * time.Duration(o.k.Int(KeyServeReplicationIntervalSecs)),
+			Token:             o.k.String(KeyServeReplicationToken),
+			TimeoutSecs:       time.Second * time.Duration(o.k.Int(KeyServeReplicationTimeoutSecs)),
+			KeepProjectConfig: o.k.Strings(KeyServeReplicationKeepConfig),
+		},
+		GRPC: GRPCConfig{
+			MaxRecvMsgSizeMB:  o.k.Int(KeyServeGRPCMaxRecvMsgSizeMB),
+			MaxSendMsgSizeMB:  o.k.Int(KeyServeGRPCMaxSendMsgSizeMB),
+			UnaryTimeoutSecs:  time.Second * time.Duration(o.k.Int(KeyServeGRPCUnaryTimeoutSecs)),
+			StreamTimeoutSecs: time.Second * time.Duration(o.k.Int(KeyServeGRPCStreamTimeoutSecs)),
+		},
+		TLS: TLSConfig{
+			CertFile:           o.k.String(KeyServeTLSCertFile),
+			KeyFile:            o.k.String(KeyServeTLSKeyFile),
+			ClientCAFile:       o.k.String(KeyServeTLSClientCAFile),
+			RequireClientCert:  o.k.Bool(KeyServeTLSRequireClientCert),
+			ReloadIntervalSecs: time.Second * time.Duration(o.k.Int(KeyServeTLSReloadIntervalSecs)),
+		},
+		ResourceConcurrency: o.k.IntMap(KeyServeResourceConcurrency),
+		UI: UIConfig{
+			Enabled: o.k.Bool(KeyServeUIEnabled),
+		},
+		Auth: AuthConfig{
+			RequireToken: o.k.Bool(KeyServeAuthRequireToken),
+		},
+		SMTP: SMTPConfig{
+			Host:     o.k.String(KeyServeSMTPHost),
+			Port:     o.k.Int(KeyServeSMTPPort),
+			Username: o.k.String(KeyServeSMTPUsername),
+			Password: o.k.String(KeyServeSMTPPassword),
+			From:     o.k.String(KeyServeSMTPFrom),
+		},
 	}
 }
 
@@ -210,6 +638,10 @@ func (o Optimus) GetAdmin() AdminConfig {
 	}
 }
 
+func (o Optimus) GetExtensions() map[string]string {
+	return o.k.StringMap(KeyExtensions)
+}
+
 // eKs replaces . with _ to support buggy koanf config loader from ENV
 // this should be used in all keys where underscore is used
 func (o Optimus) eKs(e string) string {
@@ -3,6 +3,7 @@ package config
 type Provider interface {
 	GetVersion() string
 	GetHost() string
+	GetClient() ClientConfig
 	GetJob() Job
 	GetDatastore() []Datastore
 	GetProjectConfig() ProjectConfig
@@ -11,4 +12,5 @@ type Provider interface {
 	GetServe() ServerConfig
 	GetScheduler() SchedulerConfig
 	GetAdmin() AdminConfig
+	GetExtensions() map[string]string
 }
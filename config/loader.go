@@ -55,12 +55,43 @@ func InitOptimus() (*Optimus, error) {
 		KeyServeHost:                    "0.0.0.0",
 		KeyServeDBMaxOpenConnection:     10,
 		KeyServeDBMaxIdleConnection:     5,
+		KeyServeDBAutoMigrate:           true,
+		KeyServeDBSlowQueryMillis:       1000,
 		KeyServeMetadataKafkaJobTopic:   "resource_optimus_job_log",
 		KeyServeMetadataKafkaBatchSize:  50,
 		KeyServeMetadataWriterBatchSize: 50,
 		KeySchedulerName:                "airflow2",
+		KeyClientDialTimeoutSecs:        2,
+		KeyClientRetryMaxAttempts:       3,
+		KeyClientRetryBackoffMillis:     500,
+		KeyClientKeepaliveTimeSecs:      30,
+		KeyClientKeepaliveTimeoutSecs:   10,
 		KeyServeReplayNumWorkers:        1,
 		KeyServeReplayWorkerTimeoutSecs: 120,
+		KeyServeReplayQueueSize:         10,
+		KeyServeSecretCacheTTLSecs:      300,
+		KeyServeSpecCacheTTLSecs:        30,
+		KeyServeAutoHealIntervalSecs:    600,
+		KeyServeExtraRunsIntervalSecs:   300,
+		KeyServeGitSyncIntervalSecs:     300,
+		KeyServeDurationAnomalyInterval: 900,
+		KeyServeDurationAnomalyFactor:   2.0,
+		KeyServeStaleJobsIntervalSecs:   86400,
+		KeyServeStaleJobsWindowDays:     30,
+		KeyServeHungRunsIntervalSecs:    300,
+		KeyServeHungRunsSilenceSecs:     180,
+		KeyServeRetentionIntervalSecs:   3600,
+		KeyServeRetentionBatchSize:      1000,
+		KeyServeRetentionArchivesDays:   30,
+		KeyServeRetentionPartitionsSecs: 86400,
+		KeyServeReplicationIntervalSecs: 60,
+		KeyServeReplicationTimeoutSecs:  60,
+		KeyServeGRPCMaxRecvMsgSizeMB:    45,
+		KeyServeGRPCMaxSendMsgSizeMB:    45,
+		KeyServeGRPCUnaryTimeoutSecs:    300,
+		KeyServeGRPCStreamTimeoutSecs:   3600,
+		KeyServeTLSReloadIntervalSecs:   60,
+		KeyServeSMTPPort:                587,
 	}, "."), nil); err != nil {
 		return nil, errors.Wrap(err, "k.Load: error loading config defaults")
 	}
@@ -0,0 +1,174 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/pkg/errors"
+)
+
+// Reloader serves a certificate loaded from files, certificates renewed in
+// place are picked up on the next check without restarting the server.
+// Clients are verified against the certificate authority if it is set
+type Reloader struct {
+	certFile          string
+	keyFile           string
+	clientCAFile      string
+	requireClientCert bool
+	interval          time.Duration
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	loadedAt  time.Time
+
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+// TLSConfig is the config of listener serving the certificate, every
+// handshake gets the latest certificate loaded
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.config(), nil
+		},
+	}
+}
+
+func (r *Reloader) config() *tls.Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conf := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2", "http/1.1"},
+		Certificates: []tls.Certificate{*r.cert},
+	}
+	if r.clientCAs != nil {
+		conf.ClientCAs = r.clientCAs
+		conf.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.requireClientCert {
+			conf.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return conf
+}
+
+// Reload loads files again if any of them changed since they were loaded,
+// a broken certificate is reported leaving the current one in use
+func (r *Reloader) Reload() error {
+	r.mu.RLock()
+	loadedAt := r.loadedAt
+	r.mu.RUnlock()
+
+	changed := false
+	for _, file := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(loadedAt) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.load()
+}
+
+func (r *Reloader) load() error {
+	loadedAt := time.Now()
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to load certificate")
+	}
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		caCert, err := ioutil.ReadFile(r.clientCAFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read client certificate authority")
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCert) {
+			return errors.Errorf("no certificates found in %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.loadedAt = loadedAt
+	return nil
+}
+
+// Start checks files for changes every interval till closed
+func (r *Reloader) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Reload(); err != nil {
+					logger.E("failed to reload certificate, serving the current one: ", err)
+				}
+			}
+		}
+	}()
+}
+
+func (r *Reloader) Close() error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	return nil
+}
+
+// NewReloader loads the certificate, its key and the certificate authority
+// of clients if set, clients must present a certificate if required
+func NewReloader(certFile, keyFile, clientCAFile string, requireClientCert bool, interval time.Duration) (*Reloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("both certificate and key are required")
+	}
+	if requireClientCert && clientCAFile == "" {
+		return nil, errors.New("certificate authority of clients is required to verify their certificates")
+	}
+	r := &Reloader{
+		certFile:          certFile,
+		keyFile:           keyFile,
+		clientCAFile:      clientCAFile,
+		requireClientCert: requireClientCert,
+		interval:          interval,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
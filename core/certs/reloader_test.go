@@ -0,0 +1,107 @@
+package certs_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/core/certs"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCert writes a self signed certificate for name along with its key
+func writeCert(t *testing.T, certFile, keyFile, name string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	assert.Nil(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+}
+
+func servedName(t *testing.T, reloader *certs.Reloader) string {
+	conf, err := reloader.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	assert.Nil(t, err)
+	leaf, err := x509.ParseCertificate(conf.Certificates[0].Certificate[0])
+	assert.Nil(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "optimus-certs")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	caFile := filepath.Join(dir, "ca.pem")
+	writeCert(t, certFile, keyFile, "optimus-1")
+	writeCert(t, caFile, filepath.Join(dir, "ca-key.pem"), "clients-ca")
+
+	t.Run("should serve the certificate loaded", func(t *testing.T) {
+		reloader, err := certs.NewReloader(certFile, keyFile, "", false, time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, "optimus-1", servedName(t, reloader))
+
+		conf, err := reloader.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+		assert.Nil(t, err)
+		assert.Equal(t, tls.NoClientCert, conf.ClientAuth)
+	})
+	t.Run("should require client certificates if configured", func(t *testing.T) {
+		reloader, err := certs.NewReloader(certFile, keyFile, caFile, true, time.Minute)
+		assert.Nil(t, err)
+
+		conf, err := reloader.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+		assert.Nil(t, err)
+		assert.Equal(t, tls.RequireAndVerifyClientCert, conf.ClientAuth)
+		assert.NotNil(t, conf.ClientCAs)
+	})
+	t.Run("should serve renewed certificate once reloaded", func(t *testing.T) {
+		reloader, err := certs.NewReloader(certFile, keyFile, "", false, time.Minute)
+		assert.Nil(t, err)
+
+		assert.Nil(t, reloader.Reload())
+		assert.Equal(t, "optimus-1", servedName(t, reloader))
+
+		writeCert(t, certFile, keyFile, "optimus-2")
+		renewedAt := time.Now().Add(time.Minute)
+		assert.Nil(t, os.Chtimes(certFile, renewedAt, renewedAt))
+		assert.Nil(t, os.Chtimes(keyFile, renewedAt, renewedAt))
+		assert.Nil(t, reloader.Reload())
+		assert.Equal(t, "optimus-2", servedName(t, reloader))
+	})
+	t.Run("should keep serving current certificate if renewed one is broken", func(t *testing.T) {
+		writeCert(t, certFile, keyFile, "optimus-3")
+		reloader, err := certs.NewReloader(certFile, keyFile, "", false, time.Minute)
+		assert.Nil(t, err)
+
+		assert.Nil(t, ioutil.WriteFile(keyFile, []byte("not a key"), 0600))
+		brokenAt := time.Now().Add(time.Minute)
+		assert.Nil(t, os.Chtimes(keyFile, brokenAt, brokenAt))
+		assert.NotNil(t, reloader.Reload())
+		assert.Equal(t, "optimus-3", servedName(t, reloader))
+	})
+	t.Run("should fail without certificate authority to verify required client certificates", func(t *testing.T) {
+		_, err := certs.NewReloader(certFile, keyFile, "", true, time.Minute)
+		assert.NotNil(t, err)
+	})
+}
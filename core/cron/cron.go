@@ -1,19 +1,49 @@
 package cron
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	roboCron "github.com/robfig/cron/v3"
 )
 
+var (
+	// descriptors are the ones the scheduler accepts, in order of frequency
+	descriptors = []string{"@hourly", "@daily", "@weekly", "@monthly", "@quarterly", "@yearly"}
+
+	// descriptorNotations are cron notations of descriptors, runs are
+	// computed with them as not all descriptors can be parsed
+	descriptorNotations = map[string]string{
+		"@hourly":    "0 * * * *",
+		"@daily":     "0 0 * * *",
+		"@weekly":    "0 0 * * 0",
+		"@monthly":   "0 0 1 * *",
+		"@quarterly": "0 0 1 */3 *",
+		"@yearly":    "0 0 1 1 *",
+	}
+)
+
 type ScheduleSpec struct {
-	schd roboCron.Schedule
+	schd     roboCron.Schedule
+	interval string
 }
 
 // Next accepts the time and returns the next run time that should
-// be used for execution
+// be used for execution. Schedules are in UTC, the same as the scheduler,
+// so times in other locations don't skip or repeat runs around DST changes
 func (s *ScheduleSpec) Next(t time.Time) time.Time {
-	return s.schd.Next(t)
+	return s.schd.Next(t.UTC())
+}
+
+// NextN returns the next n run times after t
+func (s *ScheduleSpec) NextN(t time.Time, n int) []time.Time {
+	runs := make([]time.Time, 0, n)
+	for run := s.Next(t); len(runs) < n; run = s.Next(run) {
+		runs = append(runs, run)
+	}
+	return runs
 }
 
 // ParseCronSchedule can parse standard cron notation
@@ -24,14 +54,71 @@ func (s *ScheduleSpec) Next(t time.Time) time.Time {
 //
 // It accepts
 //   - Standard crontab specs, e.g. "* * * * ?"
-//   - Descriptors, e.g. "@midnight", "@every 1h30m"
+//   - Descriptors the scheduler accepts, e.g. "@daily", "@quarterly"
+//
+// Sunday can be 0 or 7 in day of week, as the scheduler accepts both.
+// Schedules are in UTC, so timezones can't be set with a TZ= prefix.
+// Descriptors the scheduler doesn't accept like @midnight, @annually and
+// intervals of @every are rejected.
 func ParseCronSchedule(interval string) (*ScheduleSpec, error) {
-	roboCronSchedule, err := roboCron.ParseStandard(interval)
+	interval = strings.TrimSpace(interval)
+	if strings.HasPrefix(interval, "TZ=") || strings.HasPrefix(interval, "CRON_TZ=") {
+		return nil, errors.New("timezone of a schedule can't be set, schedules are in UTC")
+	}
+	notation := interval
+	if strings.HasPrefix(interval, "@") {
+		descriptorNotation, ok := descriptorNotations[interval]
+		if !ok {
+			return nil, errors.Errorf("%s is not supported by the scheduler, use cron notation or one of %s",
+				interval, strings.Join(descriptors, ", "))
+		}
+		notation = descriptorNotation
+	}
+	if fields := strings.Fields(notation); len(fields) == 5 {
+		fields[4] = sundayAsZero(fields[4])
+		notation = strings.Join(fields, " ")
+	}
+	roboCronSchedule, err := roboCron.ParseStandard(notation)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ScheduleSpec{
-		schd: roboCronSchedule,
+		schd:     roboCronSchedule,
+		interval: interval,
 	}, nil
 }
+
+// sundayAsZero rewrites 7 in day of week field as 0, the only value of Sunday
+// runs are computed with, ranges up to 7 are split at Saturday
+func sundayAsZero(dayOfWeek string) string {
+	var values []string
+	for _, value := range strings.Split(dayOfWeek, ",") {
+		base, step := value, ""
+		if idx := strings.Index(value, "/"); idx >= 0 {
+			base, step = value[:idx], value[idx:]
+		}
+		switch {
+		case base == "7":
+			values = append(values, "0"+step)
+		case strings.HasSuffix(base, "-7"):
+			from := strings.TrimSuffix(base, "-7")
+			if from == "7" {
+				values = append(values, "0")
+				continue
+			}
+			values = append(values, from+"-6"+step)
+			start, err := strconv.Atoi(from)
+			interval := 1
+			if step != "" {
+				interval, _ = strconv.Atoi(step[1:])
+			}
+			if err == nil && interval > 0 && (7-start)%interval == 0 {
+				values = append(values, "0")
+			}
+		default:
+			values = append(values, value)
+		}
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,166 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // locations with DST for tests
+
+	"github.com/odpf/optimus/core/cron"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleSpec(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	assert.Nil(t, err)
+
+	t.Run("should return runs of sub hourly schedules", func(t *testing.T) {
+		schd, err := cron.ParseCronSchedule("*/15 * * * *")
+		assert.Nil(t, err)
+
+		var runs []time.Time
+		for run := schd.Next(time.Date(2021, 5, 20, 9, 50, 0, 0, time.UTC)); len(runs) < 3; run = schd.Next(run) {
+			runs = append(runs, run)
+		}
+		assert.Equal(t, []time.Time{
+			time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC),
+			time.Date(2021, 5, 20, 10, 15, 0, 0, time.UTC),
+			time.Date(2021, 5, 20, 10, 30, 0, 0, time.UTC),
+		}, runs)
+	})
+	t.Run("should return runs in UTC for times in other locations", func(t *testing.T) {
+		schd, err := cron.ParseCronSchedule("0 2 * * *")
+		assert.Nil(t, err)
+
+		// 2021-05-20 01:00 UTC
+		run := schd.Next(time.Date(2021, 5, 20, 3, 0, 0, 0, amsterdam))
+		assert.Equal(t, time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC), run)
+	})
+	t.Run("should neither skip nor repeat runs around DST changes", func(t *testing.T) {
+		schd, err := cron.ParseCronSchedule("@hourly")
+		assert.Nil(t, err)
+
+		cases := []struct {
+			From time.Time
+			To   time.Time
+		}{
+			// clocks go forward an hour at 02:00 local time
+			{From: time.Date(2021, 3, 28, 0, 0, 0, 0, amsterdam), To: time.Date(2021, 3, 29, 0, 0, 0, 0, amsterdam)},
+			// clocks go back an hour at 03:00 local time
+			{From: time.Date(2021, 10, 31, 0, 0, 0, 0, amsterdam), To: time.Date(2021, 11, 1, 0, 0, 0, 0, amsterdam)},
+		}
+		for _, tcase := range cases {
+			var runs []time.Time
+			for run := schd.Next(tcase.From.Add(-time.Second)); run.Before(tcase.To); run = schd.Next(run) {
+				runs = append(runs, run)
+			}
+			assert.Equal(t, int(tcase.To.Sub(tcase.From).Hours()), len(runs))
+			for i := 1; i < len(runs); i++ {
+				assert.Equal(t, time.Hour, runs[i].Sub(runs[i-1]))
+			}
+		}
+	})
+	t.Run("should return next n runs", func(t *testing.T) {
+		schd, err := cron.ParseCronSchedule("0 2 * * 1-5")
+		assert.Nil(t, err)
+
+		// 2021-05-21 is a friday
+		runs := schd.NextN(time.Date(2021, 5, 21, 0, 0, 0, 0, time.UTC), 3)
+		assert.Equal(t, []time.Time{
+			time.Date(2021, 5, 21, 2, 0, 0, 0, time.UTC),
+			time.Date(2021, 5, 24, 2, 0, 0, 0, time.UTC),
+			time.Date(2021, 5, 25, 2, 0, 0, 0, time.UTC),
+		}, runs)
+	})
+}
+
+func TestParseCronSchedule(t *testing.T) {
+	t.Run("should reject schedules in other timezones", func(t *testing.T) {
+		_, err := cron.ParseCronSchedule("CRON_TZ=Asia/Jakarta 0 2 * * *")
+		assert.Equal(t, "timezone of a schedule can't be set, schedules are in UTC", err.Error())
+	})
+	t.Run("should reject descriptors not supported by the scheduler", func(t *testing.T) {
+		_, err := cron.ParseCronSchedule("@every 2h")
+		assert.Equal(t, "@every 2h is not supported by the scheduler, use cron notation or one of "+
+			"@hourly, @daily, @weekly, @monthly, @quarterly, @yearly", err.Error())
+
+		for _, interval := range []string{"@midnight", "@annually", "@every 1h30m"} {
+			_, err := cron.ParseCronSchedule(interval)
+			assert.NotNil(t, err, interval)
+		}
+	})
+	t.Run("should return runs of quarterly schedules", func(t *testing.T) {
+		schd, err := cron.ParseCronSchedule("@quarterly")
+		assert.Nil(t, err)
+		runs := schd.NextN(time.Date(2021, 2, 10, 0, 0, 0, 0, time.UTC), 2)
+		assert.Equal(t, []time.Time{
+			time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+		}, runs)
+	})
+	t.Run("should run on Sunday for day of week 7", func(t *testing.T) {
+		// 2021-05-20 is a Thursday
+		from := time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC)
+		cases := map[string][]time.Time{
+			"0 2 * * 7": {
+				time.Date(2021, 5, 23, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, 5, 30, 2, 0, 0, 0, time.UTC),
+			},
+			"0 2 * * 5-7": {
+				time.Date(2021, 5, 21, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, 5, 22, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, 5, 23, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, 5, 28, 2, 0, 0, 0, time.UTC),
+			},
+			"0 2 * * 1-7/2": {
+				time.Date(2021, 5, 21, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, 5, 23, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, 5, 24, 2, 0, 0, 0, time.UTC),
+				time.Date(2021, 5, 26, 2, 0, 0, 0, time.UTC),
+			},
+		}
+		for interval, expected := range cases {
+			schd, err := cron.ParseCronSchedule(interval)
+			assert.Nil(t, err, interval)
+			assert.Equal(t, expected, schd.NextN(from, len(expected)), interval)
+		}
+	})
+	t.Run("should reject invalid notations", func(t *testing.T) {
+		for _, interval := range []string{"", "@hello", "* * z", "60 * * * *"} {
+			_, err := cron.ParseCronSchedule(interval)
+			assert.NotNil(t, err, interval)
+		}
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	cases := []struct {
+		Interval    string
+		Description string
+	}{
+		{Interval: "@daily", Description: "every day at 00:00 UTC"},
+		{Interval: "@hourly", Description: "every hour at minute 0"},
+		{Interval: "@weekly", Description: "every week on Sunday at 00:00 UTC"},
+		{Interval: "@quarterly", Description: "every quarter on January 1, April 1, July 1 and October 1 at 00:00 UTC"},
+		{Interval: "* * * * *", Description: "every minute"},
+		{Interval: "*/15 * * * *", Description: "every 15 minutes"},
+		{Interval: "30 * * * *", Description: "at minute 30 of every hour"},
+		{Interval: "0 2 * * *", Description: "at 02:00 UTC every day"},
+		{Interval: "0,30 9 * * *", Description: "at 09:00 and 09:30 UTC every day"},
+		{Interval: "0 2/3 * * *", Description: "at minute 0 every 3 hours from 2 UTC"},
+		{Interval: "30 3-6,20-23 * * *", Description: "at minute 30 past hours 3 through 6 and 20 through 23 UTC"},
+		{Interval: "0 2 * * 1-5", Description: "at 02:00 UTC, on Monday through Friday"},
+		{Interval: "0 2 * * 7", Description: "at 02:00 UTC, on Sunday"},
+		{Interval: "0 2 * * 5-7", Description: "at 02:00 UTC, on Friday through Sunday"},
+		{Interval: "0 0 1,15 * *", Description: "at 00:00 UTC, on days 1 and 15 of the month"},
+		{Interval: "0 0 1 * MON", Description: "at 00:00 UTC, on day 1 of the month or on Monday"},
+		{Interval: "0 9 * JAN,JUL *", Description: "at 09:00 UTC, in January and July"},
+		{Interval: "0 0 1 */3 *", Description: "at 00:00 UTC, on day 1 of the month, every 3 months"},
+	}
+	for _, tcase := range cases {
+		t.Run(tcase.Interval, func(t *testing.T) {
+			schd, err := cron.ParseCronSchedule(tcase.Interval)
+			assert.Nil(t, err)
+			assert.Equal(t, tcase.Description, schd.Describe())
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	descriptorDescriptions = map[string]string{
+		"@yearly":    "every year on January 1 at 00:00 UTC",
+		"@quarterly": "every quarter on January 1, April 1, July 1 and October 1 at 00:00 UTC",
+		"@monthly":   "every month on day 1 at 00:00 UTC",
+		"@weekly":    "every week on Sunday at 00:00 UTC",
+		"@daily":     "every day at 00:00 UTC",
+		"@hourly":    "every hour at minute 0",
+	}
+
+	monthNames = []string{"", "January", "February", "March", "April", "May", "June", "July",
+		"August", "September", "October", "November", "December"}
+	// Sunday is both 0 and 7 in cron notation
+	weekdayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+)
+
+// cronField describes how values of a field of cron notation are read out
+type cronField struct {
+	singular string
+	plural   string
+	// names of values, if values are read out by their names like months
+	names []string
+}
+
+var (
+	minuteField     = cronField{singular: "minute", plural: "minutes"}
+	hourField       = cronField{singular: "hour", plural: "hours"}
+	dayOfMonthField = cronField{singular: "day", plural: "days"}
+	monthField      = cronField{singular: "month", plural: "months", names: monthNames}
+	dayOfWeekField  = cronField{singular: "day of the week", plural: "days of the week", names: weekdayNames}
+)
+
+// Describe returns the schedule in words, e.g. "at 02:00 UTC, on Monday
+// through Friday" for 0 2 * * 1-5
+func (s *ScheduleSpec) Describe() string {
+	if desc, ok := descriptorDescriptions[s.interval]; ok {
+		return desc
+	}
+	fields := strings.Fields(s.interval)
+	if len(fields) != 5 {
+		return s.interval
+	}
+	minute, hour, dayOfMonth, month, dayOfWeek := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+	desc, isClock := describeTime(minute, hour)
+	var days []string
+	if !isWildcard(dayOfMonth) {
+		days = append(days, withPreposition("on", dayOfMonthField.describe(dayOfMonth)+" of the month"))
+	}
+	if !isWildcard(dayOfWeek) {
+		days = append(days, withPreposition("on", dayOfWeekField.describe(dayOfWeek)))
+	}
+	if len(days) == 0 && isWildcard(month) {
+		if isClock {
+			desc += " every day"
+		}
+		return desc
+	}
+	// a day is run on if either of day of month or day of week matches
+	if len(days) > 0 {
+		desc += ", " + strings.Join(days, " or ")
+	}
+	if !isWildcard(month) {
+		desc += ", " + withPreposition("in", monthField.describe(month))
+	}
+	return desc
+}
+
+// describeTime describes the minute and hour fields, as clock times if
+// they are a few plain values
+func describeTime(minute, hour string) (desc string, isClock bool) {
+	if isWildcard(hour) {
+		switch {
+		case isWildcard(minute):
+			return "every minute", false
+		case isPlainValues(minute):
+			return "at " + minuteField.describe(minute) + " of every hour", false
+		default:
+			return minuteField.describe(minute), false
+		}
+	}
+	if isPlainValues(minute) && isPlainValues(hour) {
+		minutes, hours := strings.Split(minute, ","), strings.Split(hour, ",")
+		if len(minutes)*len(hours) <= 6 {
+			var clocks []string
+			for _, h := range hours {
+				for _, m := range minutes {
+					hh, _ := strconv.Atoi(h)
+					mm, _ := strconv.Atoi(m)
+					clocks = append(clocks, fmt.Sprintf("%02d:%02d", hh, mm))
+				}
+			}
+			return "at " + joinWords(clocks) + " UTC", true
+		}
+	}
+
+	switch {
+	case isWildcard(minute):
+		desc = "every minute"
+	case isPlainValues(minute):
+		desc = "at " + minuteField.describe(minute)
+	default:
+		desc = minuteField.describe(minute)
+	}
+	return desc + " " + withPreposition("past", hourField.describe(hour)) + " UTC", false
+}
+
+// describe reads out values of the field, e.g. "days 1 and 15",
+// "Monday through Friday" or "every 15 minutes from 5"
+func (f cronField) describe(expr string) string {
+	parts := strings.Split(expr, ",")
+	var (
+		descs  []string
+		values int
+	)
+	for _, part := range parts {
+		base, step := part, ""
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			base, step = part[:idx], part[idx+1:]
+		}
+		var from, to string
+		if idx := strings.Index(base, "-"); idx >= 0 {
+			from, to = f.valueName(base[:idx]), f.valueName(base[idx+1:])
+		} else if !isWildcard(base) {
+			from = f.valueName(base)
+		}
+
+		if step == "" {
+			values++
+			if to != "" {
+				values++
+				descs = append(descs, from+" through "+to)
+				continue
+			}
+			descs = append(descs, from)
+			continue
+		}
+		desc := "every " + step + " " + f.plural
+		if step == "1" {
+			desc = "every " + f.singular
+		}
+		switch {
+		case to != "":
+			desc += " from " + from + " through " + to
+		case from != "":
+			desc += " from " + from
+		}
+		descs = append(descs, desc)
+	}
+
+	desc := joinWords(descs)
+	if values == 0 || f.names != nil {
+		return desc
+	}
+	if values == 1 {
+		return f.singular + " " + desc
+	}
+	return f.plural + " " + desc
+}
+
+// valueName returns the name of a value of the field, names in notation
+// like MON are read as they are
+func (f cronField) valueName(value string) string {
+	if f.names == nil {
+		return value
+	}
+	idx, err := strconv.Atoi(value)
+	if err != nil {
+		for _, name := range f.names {
+			if name != "" && strings.EqualFold(name[:3], value) {
+				return name
+			}
+		}
+		return value
+	}
+	if idx < 0 || idx >= len(f.names) {
+		return value
+	}
+	return f.names[idx]
+}
+
+func isWildcard(expr string) bool {
+	return expr == "*" || expr == "?"
+}
+
+// isPlainValues returns true if expr is a list of numbers
+func isPlainValues(expr string) bool {
+	for _, value := range strings.Split(expr, ",") {
+		if _, err := strconv.Atoi(value); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// withPreposition puts the preposition before desc unless it reads as
+// a frequency, e.g. "every 2 hours"
+func withPreposition(preposition, desc string) string {
+	if strings.HasPrefix(desc, "every ") {
+		return desc
+	}
+	return preposition + " " + desc
+}
+
+// joinWords joins words as a list in a sentence, e.g. "a, b and c"
+func joinWords(words []string) string {
+	if len(words) <= 1 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
@@ -0,0 +1,294 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	Draft07 = "http://json-schema.org/draft-07/schema#"
+
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// Schema is a subset of json schema draft-07 enough to describe
+// specification files and validate them
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalProperties is either a bool or *Schema
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+	Items                *Schema     `json:"items,omitempty"`
+
+	Pattern   string `json:"pattern,omitempty"`
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Minimum   *int   `json:"minimum,omitempty"`
+	Maximum   *int   `json:"maximum,omitempty"`
+}
+
+// Reflector generates Schema of go structs using the tags of its fields
+// - KeyTag is used to find the property name, e.g. yaml or json,
+// if not provided lower cased field name is used
+// - validate tag is read for min, max and regexp constraints
+// - jsonschema tag supports required and description=<text>
+type Reflector struct {
+	KeyTag string
+
+	// Overrides are used as is for matching types instead of reflecting them
+	Overrides map[reflect.Type]*Schema
+}
+
+// Reflect creates schema of the provided value
+func (r Reflector) Reflect(v interface{}, title string) *Schema {
+	s := r.reflectType(reflect.TypeOf(v))
+	s.Schema = Draft07
+	s.Title = title
+	return s
+}
+
+func (r Reflector) reflectType(t reflect.Type) *Schema {
+	if s, ok := r.Overrides[t]; ok {
+		copied := *s
+		return &copied
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return r.reflectType(t.Elem())
+	case reflect.String:
+		return &Schema{Type: TypeString}
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TypeInteger}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: TypeArray, Items: r.reflectType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: TypeObject, AdditionalProperties: r.reflectType(t.Elem())}
+	case reflect.Struct:
+		return r.reflectStruct(t)
+	}
+	// interface or unsupported kind, allow anything
+	return &Schema{}
+}
+
+func (r Reflector) reflectStruct(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 TypeObject,
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// unexported
+			continue
+		}
+		name := r.propertyName(field)
+		if name == "-" {
+			continue
+		}
+
+		prop := r.reflectType(field.Type)
+		applyValidateTag(prop, field.Tag.Get("validate"))
+		for _, opt := range strings.Split(field.Tag.Get("jsonschema"), ",") {
+			switch {
+			case opt == "required":
+				s.Required = append(s.Required, name)
+			case strings.HasPrefix(opt, "description="):
+				prop.Description = strings.TrimPrefix(opt, "description=")
+			}
+		}
+		s.Properties[name] = prop
+	}
+	return s
+}
+
+func (r Reflector) propertyName(field reflect.StructField) string {
+	if r.KeyTag != "" {
+		if name := strings.Split(field.Tag.Get(r.KeyTag), ",")[0]; name != "" {
+			return name
+		}
+	}
+	return strings.ToLower(field.Name)
+}
+
+// applyValidateTag translates constraints used by gopkg.in/validator.v2
+func applyValidateTag(s *Schema, tag string) {
+	for _, rule := range strings.Split(tag, ",") {
+		parts := strings.SplitN(rule, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "regexp":
+			s.Pattern = parts[1]
+		case "min", "max":
+			val, err := strconv.Atoi(parts[1])
+			if err != nil {
+				continue
+			}
+			switch {
+			case s.Type == TypeString && parts[0] == "min":
+				s.MinLength = &val
+			case s.Type == TypeString && parts[0] == "max":
+				s.MaxLength = &val
+			case s.Type == TypeInteger && parts[0] == "min":
+				s.Minimum = &val
+			case s.Type == TypeInteger && parts[0] == "max":
+				s.Maximum = &val
+			}
+		}
+	}
+}
+
+// Validate checks if a decoded yaml/json document satisfies the schema,
+// it returns all the violations found, empty if document is valid
+func (s *Schema) Validate(doc interface{}) []error {
+	return s.validate("", doc)
+}
+
+func (s *Schema) validate(path string, doc interface{}) (errs []error) {
+	if doc == nil {
+		return nil
+	}
+	field := path
+	if field == "" {
+		field = "<root>"
+	}
+
+	switch s.Type {
+	case TypeObject:
+		obj, ok := toObject(doc)
+		if !ok {
+			return []error{fmt.Errorf("%s: expected an object", field)}
+		}
+		for _, req := range s.Required {
+			if _, ok := obj[req]; !ok {
+				errs = append(errs, fmt.Errorf("%s: missing required field %s", field, req))
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if prop, ok := s.Properties[key]; ok {
+				errs = append(errs, prop.validate(joinPath(path, key), obj[key])...)
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case *Schema:
+				errs = append(errs, additional.validate(joinPath(path, key), obj[key])...)
+			case bool:
+				if !additional {
+					errs = append(errs, fmt.Errorf("%s: unknown field %s", field, key))
+				}
+			}
+		}
+	case TypeArray:
+		arr, ok := doc.([]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: expected an array", field)}
+		}
+		if s.Items != nil {
+			for idx, item := range arr {
+				errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, idx), item)...)
+			}
+		}
+	case TypeString:
+		str, ok := doc.(string)
+		if !ok {
+			return []error{fmt.Errorf("%s: expected a string", field)}
+		}
+		if s.MinLength != nil && len(str) < *s.MinLength {
+			errs = append(errs, fmt.Errorf("%s: should be at least %d characters long", field, *s.MinLength))
+		}
+		if s.MaxLength != nil && len(str) > *s.MaxLength {
+			errs = append(errs, fmt.Errorf("%s: should be at most %d characters long", field, *s.MaxLength))
+		}
+		if s.Pattern != "" {
+			if matched, err := regexp.MatchString(s.Pattern, str); err == nil && !matched {
+				errs = append(errs, fmt.Errorf("%s: %q does not match pattern %s", field, str, s.Pattern))
+			}
+		}
+	case TypeInteger:
+		num, ok := toInt(doc)
+		if !ok {
+			return []error{fmt.Errorf("%s: expected an integer", field)}
+		}
+		if s.Minimum != nil && num < *s.Minimum {
+			errs = append(errs, fmt.Errorf("%s: should be greater than or equal to %d", field, *s.Minimum))
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			errs = append(errs, fmt.Errorf("%s: should be less than or equal to %d", field, *s.Maximum))
+		}
+	case TypeNumber:
+		switch doc.(type) {
+		case int, int64, uint64, float32, float64:
+		default:
+			return []error{fmt.Errorf("%s: expected a number", field)}
+		}
+	case TypeBoolean:
+		if _, ok := doc.(bool); !ok {
+			return []error{fmt.Errorf("%s: expected a boolean", field)}
+		}
+	}
+	return errs
+}
+
+// toObject supports maps decoded by both yaml(v2) and json decoders
+func toObject(doc interface{}) (map[string]interface{}, bool) {
+	switch obj := doc.(type) {
+	case map[string]interface{}:
+		return obj, true
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for k, v := range obj {
+			converted[fmt.Sprintf("%v", k)] = v
+		}
+		return converted, true
+	}
+	return nil, false
+}
+
+func toInt(doc interface{}) (int, bool) {
+	switch num := doc.(type) {
+	case int:
+		return num, true
+	case int64:
+		return int(num), true
+	case uint64:
+		return int(num), true
+	case float64:
+		if num == float64(int(num)) {
+			return int(num), true
+		}
+	}
+	return 0, false
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
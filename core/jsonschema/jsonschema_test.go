@@ -0,0 +1,122 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/odpf/optimus/core/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+type sampleRetry struct {
+	Count int `yaml:"count,omitempty" validate:"min=0,max=10"`
+}
+
+type sampleSpec struct {
+	Version int    `yaml:"version" validate:"min=1,max=100" jsonschema:"required"`
+	Name    string `validate:"min=3,max=10" jsonschema:"required,description=unique name"`
+	Window  string `yaml:"window" validate:"regexp=^(h|d)$"`
+	Retry   sampleRetry
+	Labels  map[string]string `yaml:"labels,omitempty"`
+	Tags    []string
+	Config  yaml.MapSlice `yaml:"config,omitempty"`
+	Spec    interface{}
+	ignored string
+}
+
+func TestReflector(t *testing.T) {
+	reflector := jsonschema.Reflector{
+		KeyTag: "yaml",
+		Overrides: map[reflect.Type]*jsonschema.Schema{
+			reflect.TypeOf(yaml.MapSlice{}): {Type: jsonschema.TypeObject},
+		},
+	}
+	schema := reflector.Reflect(sampleSpec{}, "sample")
+
+	t.Run("Reflect", func(t *testing.T) {
+		t.Run("should generate properties using key tag and field names", func(t *testing.T) {
+			assert.Equal(t, jsonschema.Draft07, schema.Schema)
+			assert.Equal(t, "sample", schema.Title)
+			assert.Equal(t, []string{"version", "name"}, schema.Required)
+			assert.Equal(t, false, schema.AdditionalProperties)
+
+			assert.Len(t, schema.Properties, 8)
+			assert.Equal(t, jsonschema.TypeInteger, schema.Properties["version"].Type)
+			assert.Equal(t, 1, *schema.Properties["version"].Minimum)
+			assert.Equal(t, 100, *schema.Properties["version"].Maximum)
+			assert.Equal(t, "unique name", schema.Properties["name"].Description)
+			assert.Equal(t, 3, *schema.Properties["name"].MinLength)
+			assert.Equal(t, "^(h|d)$", schema.Properties["window"].Pattern)
+			assert.Equal(t, jsonschema.TypeObject, schema.Properties["retry"].Type)
+			assert.Equal(t, jsonschema.TypeInteger, schema.Properties["retry"].Properties["count"].Type)
+			assert.Equal(t, jsonschema.TypeArray, schema.Properties["tags"].Type)
+			assert.Equal(t, jsonschema.TypeString, schema.Properties["tags"].Items.Type)
+			assert.Equal(t, &jsonschema.Schema{Type: jsonschema.TypeString}, schema.Properties["labels"].AdditionalProperties)
+			assert.Equal(t, &jsonschema.Schema{Type: jsonschema.TypeObject}, schema.Properties["config"])
+			assert.Equal(t, &jsonschema.Schema{}, schema.Properties["spec"])
+		})
+		t.Run("should be serializable as json", func(t *testing.T) {
+			raw, err := json.Marshal(schema.Properties["window"])
+			assert.Nil(t, err)
+			assert.JSONEq(t, `{"type":"string","pattern":"^(h|d)$"}`, string(raw))
+		})
+	})
+	t.Run("Validate", func(t *testing.T) {
+		t.Run("should pass for a valid yaml document", func(t *testing.T) {
+			var doc interface{}
+			err := yaml.Unmarshal([]byte(`
+version: 1
+name: foo
+window: d
+retry:
+  count: 2
+labels:
+  owner: bar
+tags: [a, b]
+config:
+  KEY: value
+spec:
+  anything: [1, 2]
+`), &doc)
+			assert.Nil(t, err)
+			assert.Empty(t, schema.Validate(doc))
+		})
+		t.Run("should pass for a valid json document", func(t *testing.T) {
+			var doc interface{}
+			err := json.Unmarshal([]byte(`{"version": 1, "name": "foo", "retry": {"count": 0}}`), &doc)
+			assert.Nil(t, err)
+			assert.Empty(t, schema.Validate(doc))
+		})
+		t.Run("should return all violations of an invalid document", func(t *testing.T) {
+			var doc interface{}
+			err := yaml.Unmarshal([]byte(`
+name: a-very-long-name
+window: x
+retry:
+  count: 20
+labels:
+  owner: 1
+tags: a
+unknown: true
+`), &doc)
+			assert.Nil(t, err)
+
+			errs := schema.Validate(doc)
+			var msgs []string
+			for _, e := range errs {
+				msgs = append(msgs, e.Error())
+			}
+			assert.Equal(t, []string{
+				"<root>: missing required field version",
+				"labels.owner: expected a string",
+				"name: should be at most 10 characters long",
+				"retry.count: should be less than or equal to 10",
+				"tags: expected an array",
+				"<root>: unknown field unknown",
+				`window: "x" does not match pattern ^(h|d)$`,
+			}, msgs)
+		})
+	})
+}
@@ -44,6 +44,57 @@ func NewTreeSetWith(comp func(a, b interface{}) int) *treeset.Set {
 	return treeset.NewWith(comp)
 }
 
+// TimeIntersection returns times present in both the sets ordered by time
+func TimeIntersection(a, b Set) Set {
+	result := NewTreeSetWithTimeComparator()
+	for _, item := range a.Values() {
+		if b.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// TimeDifference returns times of a which are not present in b ordered by time
+func TimeDifference(a, b Set) Set {
+	result := NewTreeSetWithTimeComparator()
+	for _, item := range a.Values() {
+		if !b.Contains(item) {
+			result.Add(item)
+		}
+	}
+	return result
+}
+
+// TimeUnion returns times present in any of the sets ordered by time
+func TimeUnion(a, b Set) Set {
+	result := NewTreeSetWithTimeComparator()
+	result.Add(a.Values()...)
+	result.Add(b.Values()...)
+	return result
+}
+
+// TimeRange returns times of s which lie within [start, end] ordered by time
+func TimeRange(s Set, start, end time.Time) Set {
+	result := NewTreeSetWithTimeComparator()
+	for _, item := range s.Values() {
+		t := item.(time.Time)
+		if !t.Before(start) && !t.After(end) {
+			result.Add(t)
+		}
+	}
+	return result
+}
+
+// Times returns values of a time set
+func Times(s Set) []time.Time {
+	times := make([]time.Time, 0, s.Size())
+	for _, item := range s.Values() {
+		times = append(times, item.(time.Time))
+	}
+	return times
+}
+
 func timeComparator(a, b interface{}) int {
 	aAsserted := a.(time.Time)
 	bAsserted := b.(time.Time)
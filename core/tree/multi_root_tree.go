@@ -1,10 +1,17 @@
 package tree
 
-import "github.com/pkg/errors"
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
 
 var (
 	// ErrCyclicDependencyEncountered is triggered a tree has a cyclic dependency
 	ErrCyclicDependencyEncountered = errors.New("a cycle dependency encountered in the tree")
+
+	// ErrNodeNotFound is returned when a node is not part of the tree
+	ErrNodeNotFound = errors.New("node not found in the tree")
 )
 
 // MultiRootTree - represents a data type which has multiple independent root nodes
@@ -90,6 +97,171 @@ func (t *MultiRootTree) hasCycle(root *TreeNode, visited, pathMap map[string]boo
 	return nil
 }
 
+// GetNodes returns all the nodes of tree sorted by name
+func (t *MultiRootTree) GetNodes() []*TreeNode {
+	nodes := make([]*TreeNode, 0, len(t.dataMap))
+	for _, node := range t.dataMap {
+		nodes = append(nodes, node)
+	}
+	sortNodesByName(nodes)
+	return nodes
+}
+
+// GetLevels groups nodes in waves, a node is placed in the level after the
+// last of its dependencies. Nodes of a level don't depend on each other and
+// are sorted by name, first level contains nodes without any dependency.
+func (t *MultiRootTree) GetLevels() ([][]*TreeNode, error) {
+	nodes := t.reachableNodes()
+	inDegree := map[string]int{}
+	for name, node := range nodes {
+		if _, ok := inDegree[name]; !ok {
+			inDegree[name] = 0
+		}
+		for _, dependent := range uniqueNames(node.Dependents) {
+			inDegree[dependent]++
+		}
+	}
+
+	var current []*TreeNode
+	for name, degree := range inDegree {
+		if degree == 0 {
+			current = append(current, nodes[name])
+		}
+	}
+
+	var levels [][]*TreeNode
+	visited := 0
+	for len(current) > 0 {
+		sortNodesByName(current)
+		levels = append(levels, current)
+		visited += len(current)
+
+		var next []*TreeNode
+		for _, node := range current {
+			for _, dependent := range uniqueNames(node.Dependents) {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, nodes[dependent])
+				}
+			}
+		}
+		current = next
+	}
+
+	if visited != len(nodes) {
+		// nodes left with dependencies are part of a cycle
+		var pending []string
+		for name, degree := range inDegree {
+			if degree > 0 {
+				pending = append(pending, name)
+			}
+		}
+		sort.Strings(pending)
+		return nil, errors.Wrap(ErrCyclicDependencyEncountered, pending[0])
+	}
+	return levels, nil
+}
+
+// TopologicalSort returns nodes in an order where every node appears after
+// all of its dependencies, ties are broken by name so the order is stable
+func (t *MultiRootTree) TopologicalSort() ([]*TreeNode, error) {
+	levels, err := t.GetLevels()
+	if err != nil {
+		return nil, err
+	}
+	var sorted []*TreeNode
+	for _, level := range levels {
+		sorted = append(sorted, level...)
+	}
+	return sorted, nil
+}
+
+// GetSubTree extracts a new tree with the given node as its only root along
+// with all of its transitive dependents, nodes share data and runs with the
+// current tree but have their own dependents
+func (t *MultiRootTree) GetSubTree(rootName string) (*MultiRootTree, error) {
+	nodes := t.reachableNodes()
+	root, ok := nodes[rootName]
+	if !ok {
+		return nil, errors.Wrap(ErrNodeNotFound, rootName)
+	}
+
+	subTree := NewMultiRootTree()
+	copies := map[string]*TreeNode{}
+	copyOf := func(node *TreeNode) *TreeNode {
+		if c, ok := copies[node.GetName()]; ok {
+			return c
+		}
+		c := &TreeNode{
+			Data:       node.Data,
+			Dependents: []*TreeNode{},
+			Runs:       node.Runs,
+		}
+		copies[node.GetName()] = c
+		subTree.AddNode(c)
+		return c
+	}
+
+	queue := []*TreeNode{root}
+	visited := map[string]bool{rootName: true}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		nodeCopy := copyOf(node)
+		for _, name := range uniqueNames(node.Dependents) {
+			dependent := nodes[name]
+			nodeCopy.AddDependent(copyOf(dependent))
+			if !visited[name] {
+				visited[name] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	subTree.MarkRoot(copies[rootName])
+	return subTree, nil
+}
+
+// reachableNodes returns all nodes of tree including dependents which are
+// not explicitly added, nodes added in tree take precedence for a name
+func (t *MultiRootTree) reachableNodes() map[string]*TreeNode {
+	nodes := map[string]*TreeNode{}
+	var queue []*TreeNode
+	for name, node := range t.dataMap {
+		nodes[name] = node
+		queue = append(queue, node)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, dependent := range node.Dependents {
+			if _, ok := nodes[dependent.GetName()]; !ok {
+				nodes[dependent.GetName()] = dependent
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	return nodes
+}
+
+// uniqueNames returns names of nodes ignoring duplicate edges
+func uniqueNames(nodes []*TreeNode) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, node := range nodes {
+		if !seen[node.GetName()] {
+			seen[node.GetName()] = true
+			names = append(names, node.GetName())
+		}
+	}
+	return names
+}
+
+func sortNodesByName(nodes []*TreeNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetName() < nodes[j].GetName()
+	})
+}
+
 // NewMultiRootTree returns an instance of multi root dag tree
 func NewMultiRootTree() *MultiRootTree {
 	return &MultiRootTree{
@@ -69,4 +69,85 @@ func TestMultiRootDagTree(t *testing.T) {
 			assert.Nil(t, err)
 		})
 	})
+	t.Run("GetLevels", func(t *testing.T) {
+		// job1 -> job3 -> job4
+		// job2 -> job4
+		// job2 -> job5
+		newTree := func() *tree.MultiRootTree {
+			nodes := map[string]*tree.TreeNode{}
+			for _, name := range []string{"job1", "job2", "job3", "job4", "job5"} {
+				nodes[name] = tree.NewTreeNode(models.JobSpec{Name: name})
+			}
+			nodes["job1"].AddDependent(nodes["job3"])
+			nodes["job3"].AddDependent(nodes["job4"])
+			nodes["job2"].AddDependent(nodes["job4"])
+			nodes["job2"].AddDependent(nodes["job5"])
+
+			multiRootTree := tree.NewMultiRootTree()
+			for _, name := range []string{"job5", "job4", "job3", "job2", "job1"} {
+				multiRootTree.AddNode(nodes[name])
+			}
+			multiRootTree.MarkRoot(nodes["job1"])
+			multiRootTree.MarkRoot(nodes["job2"])
+			return multiRootTree
+		}
+		names := func(nodes []*tree.TreeNode) []string {
+			var n []string
+			for _, node := range nodes {
+				n = append(n, node.GetName())
+			}
+			return n
+		}
+
+		t.Run("should group nodes in waves after their dependencies", func(t *testing.T) {
+			levels, err := newTree().GetLevels()
+			assert.Nil(t, err)
+			assert.Equal(t, 3, len(levels))
+			assert.Equal(t, []string{"job1", "job2"}, names(levels[0]))
+			assert.Equal(t, []string{"job3", "job5"}, names(levels[1]))
+			assert.Equal(t, []string{"job4"}, names(levels[2]))
+		})
+		t.Run("should return stable topological order", func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				sorted, err := newTree().TopologicalSort()
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"job1", "job2", "job3", "job5", "job4"}, names(sorted))
+			}
+		})
+		t.Run("should return error if tree is cyclic", func(t *testing.T) {
+			treeNode1 := tree.NewTreeNode(models.JobSpec{Name: "job1"})
+			treeNode2 := tree.NewTreeNode(models.JobSpec{Name: "job2"})
+			treeNode3 := tree.NewTreeNode(models.JobSpec{Name: "job3"})
+			treeNode1.AddDependent(treeNode2)
+			treeNode2.AddDependent(treeNode3)
+			treeNode3.AddDependent(treeNode2)
+			multiRootTree := tree.NewMultiRootTree()
+			multiRootTree.AddNode(treeNode1)
+			multiRootTree.AddNode(treeNode2)
+			multiRootTree.AddNode(treeNode3)
+
+			_, err := multiRootTree.TopologicalSort()
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tree.ErrCyclicDependencyEncountered.Error())
+		})
+		t.Run("should extract sub tree of a root", func(t *testing.T) {
+			multiRootTree := newTree()
+			subTree, err := multiRootTree.GetSubTree("job2")
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"job2"}, names(subTree.GetRootNodes()))
+			assert.Equal(t, []string{"job2", "job4", "job5"}, names(subTree.GetNodes()))
+
+			job4, _ := subTree.GetNodeByName("job4")
+			assert.Equal(t, 0, len(job4.Dependents))
+
+			// original tree is not modified
+			assert.Equal(t, 5, len(multiRootTree.GetNodes()))
+			job2, _ := multiRootTree.GetNodeByName("job2")
+			assert.Equal(t, 2, len(job2.Dependents))
+
+			_, err = multiRootTree.GetSubTree("job9")
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tree.ErrNodeNotFound.Error())
+		})
+	})
 }
@@ -1,6 +1,8 @@
 package tree
 
 import (
+	"time"
+
 	"github.com/odpf/optimus/core/set"
 )
 
@@ -38,6 +40,26 @@ func (t *TreeNode) AddDependent(depNode *TreeNode) *TreeNode {
 	return t
 }
 
+// IntersectRuns returns runs scheduled in both the nodes
+func (t *TreeNode) IntersectRuns(other *TreeNode) set.Set {
+	return set.TimeIntersection(t.Runs, other.Runs)
+}
+
+// DifferenceRuns returns runs of current node which are not in other
+func (t *TreeNode) DifferenceRuns(other *TreeNode) set.Set {
+	return set.TimeDifference(t.Runs, other.Runs)
+}
+
+// UnionRuns returns runs scheduled in any of the nodes
+func (t *TreeNode) UnionRuns(other *TreeNode) set.Set {
+	return set.TimeUnion(t.Runs, other.Runs)
+}
+
+// RunsBetween returns runs of the node scheduled within [start, end]
+func (t *TreeNode) RunsBetween(start, end time.Time) set.Set {
+	return set.TimeRange(t.Runs, start, end)
+}
+
 // NewTreeNode creates an instance of TreeNode
 func NewTreeNode(data TreeData) *TreeNode {
 	return &TreeNode{
@@ -2,7 +2,9 @@ package tree_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/odpf/optimus/core/set"
 	"github.com/odpf/optimus/core/tree"
 
 	"github.com/odpf/optimus/models"
@@ -50,4 +52,27 @@ func TestDagNode(t *testing.T) {
 		assert.Equal(t, "job-level-1", allNodes[1].Data.GetName())
 		assert.Equal(t, "job-level-2", allNodes[2].Data.GetName())
 	})
+	t.Run("RunSetOperations", func(t *testing.T) {
+		day := func(d int) time.Time {
+			return time.Date(2021, 1, d, 2, 0, 0, 0, time.UTC)
+		}
+		times := func(runs set.Set) []time.Time {
+			return set.Times(runs)
+		}
+		activeNode := tree.NewTreeNode(models.JobSpec{Name: "job"})
+		activeNode.Runs.Add(day(3), day(1), day(2))
+		reqNode := tree.NewTreeNode(models.JobSpec{Name: "job"})
+		reqNode.Runs.Add(day(4), day(2), day(3))
+
+		assert.Equal(t, []time.Time{day(2), day(3)}, times(reqNode.IntersectRuns(activeNode)))
+		assert.Equal(t, []time.Time{day(4)}, times(reqNode.DifferenceRuns(activeNode)))
+		assert.Equal(t, []time.Time{day(1)}, times(activeNode.DifferenceRuns(reqNode)))
+		assert.Equal(t, []time.Time{day(1), day(2), day(3), day(4)}, times(reqNode.UnionRuns(activeNode)))
+		assert.Equal(t, []time.Time{day(2), day(3)}, times(reqNode.RunsBetween(day(1), day(3))))
+		assert.True(t, reqNode.RunsBetween(day(5), day(9)).Empty())
+
+		// source sets are not modified
+		assert.Equal(t, 3, activeNode.Runs.Size())
+		assert.Equal(t, 3, reqNode.Runs.Size())
+	})
 }
@@ -0,0 +1,162 @@
+package datastore
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/core/tree"
+	"github.com/odpf/optimus/models"
+)
+
+var (
+	// KindConcurrency is the number of resources of each kind deployed at
+	// once, datasets are deployed one at a time as datastores rate limit
+	// their updates. Kinds missing from it are deployed ConcurrentLimit at
+	// a time
+	KindConcurrency = map[models.ResourceType]int{
+		models.ResourceTypeDataset:       1,
+		models.ResourceTypeTable:         10,
+		models.ResourceTypeView:          10,
+		models.ResourceTypeExternalTable: 10,
+	}
+)
+
+// resourceLevels groups resources in waves where a resource is placed after all
+// the resources it depends on, dependencies outside the requested resources are
+// assumed to exist already. Dependencies of each resource are returned by name
+func resourceLevels(resourceSpecs []models.ResourceSpec) ([][]models.ResourceSpec, map[string][]string, error) {
+	dependencyTree := tree.NewMultiRootTree()
+	for _, resourceSpec := range resourceSpecs {
+		dependencyTree.AddNode(tree.NewTreeNode(resourceSpec))
+	}
+
+	dependencies := map[string][]string{}
+	for _, resourceSpec := range resourceSpecs {
+		resolver, ok := resourceSpec.Datastore.(models.DatastoreDependencyResolver)
+		if !ok {
+			continue
+		}
+		node, _ := dependencyTree.GetNodeByName(resourceSpec.Name)
+		for _, dependency := range resolver.ResourceDependencies(resourceSpec) {
+			dependencyNode, ok := dependencyTree.GetNodeByName(dependency)
+			if !ok || dependency == resourceSpec.Name {
+				continue
+			}
+			dependencyNode.AddDependent(node)
+			dependencies[resourceSpec.Name] = append(dependencies[resourceSpec.Name], dependency)
+		}
+	}
+
+	levels, err := dependencyTree.GetLevels()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to order resources")
+	}
+	var resourceWaves [][]models.ResourceSpec
+	for _, level := range levels {
+		var wave []models.ResourceSpec
+		for _, node := range level {
+			wave = append(wave, node.Data.(models.ResourceSpec))
+		}
+		resourceWaves = append(resourceWaves, wave)
+	}
+	return resourceWaves, dependencies, nil
+}
+
+// kindLimiter bounds the number of resources of each kind deployed at once
+type kindLimiter struct {
+	mu    sync.Mutex
+	slots map[models.ResourceType]chan struct{}
+}
+
+// acquire waits for a slot of the kind, the returned func releases it
+func (l *kindLimiter) acquire(kind models.ResourceType) func() {
+	l.mu.Lock()
+	slot, ok := l.slots[kind]
+	if !ok {
+		limit := KindConcurrency[kind]
+		if limit <= 0 {
+			limit = ConcurrentLimit
+		}
+		slot = make(chan struct{}, limit)
+		l.slots[kind] = slot
+	}
+	l.mu.Unlock()
+
+	slot <- struct{}{}
+	return func() { <-slot }
+}
+
+// runInDependencyOrder runs fn for each resource as soon as the resources it
+// depends on are done, independent resources run concurrently within limits
+// of their kind. Resources whose dependency failed still reach fn with the
+// failure so they can be reported. Failures are aggregated by resource
+func runInDependencyOrder(resourceSpecs []models.ResourceSpec, fn func(models.ResourceSpec, error) error) error {
+	levels, dependencies, err := resourceLevels(resourceSpecs)
+	if err != nil {
+		return err
+	}
+
+	type outcome struct {
+		done chan struct{}
+		err  error
+	}
+	outcomes := map[string]*outcome{}
+	for _, level := range levels {
+		for _, resourceSpec := range level {
+			outcomes[resourceSpec.Name] = &outcome{done: make(chan struct{})}
+		}
+	}
+
+	limiter := &kindLimiter{slots: map[models.ResourceType]chan struct{}{}}
+	var wg sync.WaitGroup
+	for _, level := range levels {
+		for _, resourceSpec := range level {
+			wg.Add(1)
+			go func(currentSpec models.ResourceSpec) {
+				defer wg.Done()
+				result := outcomes[currentSpec.Name]
+				defer close(result.done)
+
+				var dependencyErr error
+				for _, dependency := range dependencies[currentSpec.Name] {
+					dependencyResult := outcomes[dependency]
+					<-dependencyResult.done
+					if dependencyResult.err != nil && dependencyErr == nil {
+						dependencyErr = fmt.Errorf("dependency %s of %s failed", dependency, currentSpec.Name)
+					}
+				}
+				if dependencyErr != nil {
+					result.err = fn(currentSpec, dependencyErr)
+					return
+				}
+
+				release := limiter.acquire(currentSpec.Type)
+				defer release()
+				result.err = fn(currentSpec, nil)
+			}(resourceSpec)
+		}
+	}
+	wg.Wait()
+
+	errorSet := &multierror.Error{}
+	for _, resourceSpec := range resourceSpecs {
+		if result, ok := outcomes[resourceSpec.Name]; ok && result.err != nil {
+			errorSet = multierror.Append(errorSet, errors.Wrap(result.err, resourceSpec.Name))
+		}
+	}
+	if errorSet.Len() == 0 {
+		return nil
+	}
+	errorSet.ErrorFormat = func(errs []error) string {
+		failures := make([]string, len(errs))
+		for i, err := range errs {
+			failures[i] = "\t* " + err.Error()
+		}
+		return fmt.Sprintf("%d of %d resources failed:\n%s", len(errs), len(resourceSpecs), strings.Join(failures, "\n"))
+	}
+	return errorSet
+}
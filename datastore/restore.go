@@ -0,0 +1,61 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/models"
+)
+
+// Restorer restores resources of datastores to how they were at a point in
+// time. Restores can take long for large resources, so they run in
+// background as operations
+type Restorer struct {
+	dsRepo     models.DatastoreRepo
+	operations models.OperationRunner
+
+	Now func() time.Time
+}
+
+// Restore validates the restore requested by actor and starts it as an
+// operation, which is returned
+func (r *Restorer) Restore(project models.ProjectSpec, actor string, restore models.ResourceRestore) (models.Operation, error) {
+	if restore.ResourceName == "" {
+		return models.Operation{}, errors.New("name of resource to restore is required")
+	}
+	if restore.Destination == restore.ResourceName {
+		// same as restoring in place
+		restore.Destination = ""
+	}
+	if restore.PointInTime.IsZero() || restore.PointInTime.After(r.Now()) {
+		return models.Operation{}, errors.New("point in time to restore to should be in the past")
+	}
+	ds, err := r.dsRepo.GetByName(restore.Datastore)
+	if err != nil {
+		return models.Operation{}, err
+	}
+	restorer, ok := ds.(models.DatastoreRestorer)
+	if !ok {
+		return models.Operation{}, errors.Errorf("datastore %s does not support restoring resources", ds.Name())
+	}
+
+	return r.operations.Start(project, models.OperationKindResourceRestore, actor, restore,
+		func(ctx context.Context, progress func(models.OperationProgress)) (interface{}, error) {
+			return nil, restorer.RestoreResource(ctx, models.RestoreResourceRequest{
+				Name:        restore.ResourceName,
+				Destination: restore.Destination,
+				PointInTime: restore.PointInTime,
+				Project:     project,
+			})
+		})
+}
+
+func NewRestorer(dsRepo models.DatastoreRepo, operations models.OperationRunner) *Restorer {
+	return &Restorer{
+		dsRepo:     dsRepo,
+		operations: operations,
+		Now:        time.Now,
+	}
+}
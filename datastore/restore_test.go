@@ -0,0 +1,81 @@
+package datastore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+
+	"github.com/odpf/optimus/datastore"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+)
+
+func TestRestorer(t *testing.T) {
+	now := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	operationID := uuid.Must(uuid.NewRandom())
+
+	t.Run("should start restore as an operation", func(t *testing.T) {
+		restorer := new(mock.DatastoreRestorer)
+		defer restorer.AssertExpectations(t)
+		dsRepo := new(mock.SupportedDatastoreRepo)
+		dsRepo.On("GetByName", "bigquery").Return(restorer, nil)
+
+		operations := new(mock.OperationRunner)
+		defer operations.AssertExpectations(t)
+		operations.On("Start", projectSpec, models.OperationKindResourceRestore, "jane@example.com", models.ResourceRestore{
+			Datastore:    "bigquery",
+			ResourceName: "proj.datas.events",
+			PointInTime:  now.Add(-time.Hour),
+		}).Return(models.Operation{ID: operationID, Status: models.OperationStatusPending}, nil)
+		restorer.On("RestoreResource", mock2.Anything, models.RestoreResourceRequest{
+			Name:        "proj.datas.events",
+			PointInTime: now.Add(-time.Hour),
+			Project:     projectSpec,
+		}).Return(errors.New("table expired"))
+
+		svc := datastore.NewRestorer(dsRepo, operations)
+		svc.Now = func() time.Time { return now }
+		op, err := svc.Restore(projectSpec, "jane@example.com", models.ResourceRestore{
+			Datastore:    "bigquery",
+			ResourceName: "proj.datas.events",
+			Destination:  "proj.datas.events",
+			PointInTime:  now.Add(-time.Hour),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, operationID, op.ID)
+		assert.Equal(t, "table expired", operations.Err.Error())
+	})
+	t.Run("should reject restores to a point in future", func(t *testing.T) {
+		svc := datastore.NewRestorer(new(mock.SupportedDatastoreRepo), new(mock.OperationRunner))
+		svc.Now = func() time.Time { return now }
+		_, err := svc.Restore(projectSpec, "", models.ResourceRestore{
+			Datastore:    "bigquery",
+			ResourceName: "proj.datas.events",
+			PointInTime:  now.Add(time.Hour),
+		})
+		assert.Equal(t, "point in time to restore to should be in the past", err.Error())
+	})
+	t.Run("should reject restores of datastores which can't restore", func(t *testing.T) {
+		datastorer := new(mock.Datastorer)
+		datastorer.On("Name").Return("gcs")
+		dsRepo := new(mock.SupportedDatastoreRepo)
+		dsRepo.On("GetByName", "gcs").Return(datastorer, nil)
+
+		svc := datastore.NewRestorer(dsRepo, new(mock.OperationRunner))
+		svc.Now = func() time.Time { return now }
+		_, err := svc.Restore(projectSpec, "", models.ResourceRestore{
+			Datastore:    "gcs",
+			ResourceName: "bucket/events",
+			PointInTime:  now.Add(-time.Hour),
+		})
+		assert.Equal(t, "datastore gcs does not support restoring resources", err.Error())
+	})
+}
@@ -3,11 +3,13 @@ package datastore
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/odpf/optimus/core/progress"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/kushsharma/parallel"
+	"github.com/pkg/errors"
 
 	"github.com/odpf/optimus/store"
 
@@ -41,53 +43,86 @@ func (srv Service) GetAll(namespace models.NamespaceSpec, datastoreName string)
 }
 
 func (srv Service) CreateResource(ctx context.Context, namespace models.NamespaceSpec, resourceSpecs []models.ResourceSpec, obs progress.Observer) error {
-	runner := parallel.NewRunner(parallel.WithLimit(ConcurrentLimit), parallel.WithTicket(ConcurrentTicketPerSec))
-	for _, resourceSpec := range resourceSpecs {
-		currentSpec := resourceSpec
-		repo := srv.resourceRepoFactory.New(namespace, currentSpec.Datastore)
-		runner.Add(func() (interface{}, error) {
-			if err := repo.Save(currentSpec); err != nil {
-				return nil, err
-			}
-
-			err := currentSpec.Datastore.CreateResource(ctx, models.CreateResourceRequest{
-				Resource: currentSpec,
-				Project:  namespace.ProjectSpec,
-			})
+	return runInDependencyOrder(resourceSpecs, func(currentSpec models.ResourceSpec, dependencyErr error) error {
+		if dependencyErr != nil {
 			srv.notifyProgress(obs, &EventResourceCreated{
 				Spec: currentSpec,
-				Err:  err,
+				Err:  dependencyErr,
 			})
-			return nil, err
+			return dependencyErr
+		}
+		repo := srv.resourceRepoFactory.New(namespace, currentSpec.Datastore)
+		if err := repo.Save(currentSpec); err != nil {
+			return err
+		}
+
+		err := currentSpec.Datastore.CreateResource(ctx, models.CreateResourceRequest{
+			Resource:  currentSpec,
+			Project:   namespace.ProjectSpec,
+			Namespace: namespace,
 		})
-	}
+		srv.notifyProgress(obs, &EventResourceCreated{
+			Spec: currentSpec,
+			Err:  err,
+		})
+		return err
+	})
+}
 
-	var errorSet error
-	for _, result := range runner.Run() {
-		if result.Err != nil {
-			errorSet = multierror.Append(errorSet, result.Err)
+// UpdateResource saves and updates resources in the order of their dependencies
+// within datastore, e.g. datasets before their tables and tables before views
+// selecting from them. Resources depending on a failed resource are not updated
+func (srv Service) UpdateResource(ctx context.Context, namespace models.NamespaceSpec, resourceSpecs []models.ResourceSpec, obs progress.Observer) error {
+	return runInDependencyOrder(resourceSpecs, func(currentSpec models.ResourceSpec, dependencyErr error) error {
+		if dependencyErr != nil {
+			srv.notifyProgress(obs, &EventResourceUpdated{
+				Spec: currentSpec,
+				Err:  dependencyErr,
+			})
+			return dependencyErr
 		}
-	}
-	return errorSet
+		repo := srv.resourceRepoFactory.New(namespace, currentSpec.Datastore)
+		if err := repo.Save(currentSpec); err != nil {
+			return err
+		}
+
+		err := currentSpec.Datastore.UpdateResource(ctx, models.UpdateResourceRequest{
+			Resource:  currentSpec,
+			Project:   namespace.ProjectSpec,
+			Namespace: namespace,
+		})
+		srv.notifyProgress(obs, &EventResourceUpdated{
+			Spec: currentSpec,
+			Err:  err,
+		})
+		return err
+	})
 }
 
-func (srv Service) UpdateResource(ctx context.Context, namespace models.NamespaceSpec, resourceSpecs []models.ResourceSpec, obs progress.Observer) error {
+func (srv Service) PlanResource(ctx context.Context, namespace models.NamespaceSpec, resourceSpecs []models.ResourceSpec, obs progress.Observer) error {
 	runner := parallel.NewRunner(parallel.WithLimit(ConcurrentLimit), parallel.WithTicket(ConcurrentTicketPerSec))
 	for _, resourceSpec := range resourceSpecs {
 		currentSpec := resourceSpec
-		repo := srv.resourceRepoFactory.New(namespace, currentSpec.Datastore)
 		runner.Add(func() (interface{}, error) {
-			if err := repo.Save(currentSpec); err != nil {
+			planner, ok := currentSpec.Datastore.(models.DatastorePlanner)
+			if !ok {
+				err := fmt.Errorf("dry run is not supported by datastore %s", currentSpec.Datastore.Name())
+				srv.notifyProgress(obs, &EventResourcePlanned{
+					Spec: currentSpec,
+					Err:  err,
+				})
 				return nil, err
 			}
 
-			err := currentSpec.Datastore.UpdateResource(ctx, models.UpdateResourceRequest{
-				Resource: currentSpec,
-				Project:  namespace.ProjectSpec,
+			changes, err := planner.PlanResource(ctx, models.UpdateResourceRequest{
+				Resource:  currentSpec,
+				Project:   namespace.ProjectSpec,
+				Namespace: namespace,
 			})
-			srv.notifyProgress(obs, &EventResourceUpdated{
-				Spec: currentSpec,
-				Err:  err,
+			srv.notifyProgress(obs, &EventResourcePlanned{
+				Spec:    currentSpec,
+				Changes: changes,
+				Err:     err,
 			})
 			return nil, err
 		})
@@ -107,6 +142,10 @@ func (srv Service) ReadResource(ctx context.Context, namespace models.NamespaceS
 	if err != nil {
 		return models.ResourceSpec{}, err
 	}
+	name, err = resourceName(ds, name)
+	if err != nil {
+		return models.ResourceSpec{}, err
+	}
 	repo := srv.resourceRepoFactory.New(namespace, ds)
 	dbSpec, err := repo.GetByName(name)
 	if err != nil {
@@ -114,8 +153,9 @@ func (srv Service) ReadResource(ctx context.Context, namespace models.NamespaceS
 	}
 
 	infoResponse, err := dbSpec.Datastore.ReadResource(ctx, models.ReadResourceRequest{
-		Resource: dbSpec,
-		Project:  namespace.ProjectSpec,
+		Resource:  dbSpec,
+		Project:   namespace.ProjectSpec,
+		Namespace: namespace,
 	})
 	if err != nil {
 		return models.ResourceSpec{}, err
@@ -128,6 +168,10 @@ func (srv Service) DeleteResource(ctx context.Context, namespace models.Namespac
 	if err != nil {
 		return err
 	}
+	name, err = resourceName(ds, name)
+	if err != nil {
+		return err
+	}
 	repo := srv.resourceRepoFactory.New(namespace, ds)
 	resourceSpec, err := repo.GetByName(name)
 	if err != nil {
@@ -136,8 +180,9 @@ func (srv Service) DeleteResource(ctx context.Context, namespace models.Namespac
 
 	// migrate the deleted resource
 	if err := resourceSpec.Datastore.DeleteResource(ctx, models.DeleteResourceRequest{
-		Resource: resourceSpec,
-		Project:  namespace.ProjectSpec,
+		Resource:  resourceSpec,
+		Project:   namespace.ProjectSpec,
+		Namespace: namespace,
 	}); err != nil {
 		return err
 	}
@@ -145,6 +190,66 @@ func (srv Service) DeleteResource(ctx context.Context, namespace models.Namespac
 	return repo.Delete(name)
 }
 
+// resourceName resolves a urn of the datastore, e.g. bigquery://project:dataset.table,
+// to the name of the resource it refers, names other than urns are returned
+// as they are
+func resourceName(ds models.Datastorer, name string) (string, error) {
+	if !strings.Contains(name, "://") {
+		return name, nil
+	}
+	resolver, ok := ds.(models.DatastoreURNResolver)
+	if !ok {
+		return "", errors.Errorf("resources of datastore %s can't be looked up by urn", ds.Name())
+	}
+	urn, err := models.ParseURN(name)
+	if err != nil {
+		return "", err
+	}
+	return resolver.ResourceName(urn)
+}
+
+func (srv Service) Promote(ctx context.Context, project models.ProjectSpec, manifest models.PromotionManifest,
+	obs progress.Observer) ([]models.PromotionResult, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	ds, err := srv.dsRepo.GetByName(manifest.Datastore)
+	if err != nil {
+		return nil, err
+	}
+	copier, ok := ds.(models.DatastoreCopier)
+	if !ok {
+		return nil, errors.Errorf("datastore %s does not support copying datasets", ds.Name())
+	}
+
+	var results []models.PromotionResult
+	for _, dataset := range manifest.Datasets {
+		resp, err := copier.CopyDataset(ctx, models.CopyDatasetRequest{
+			Source:      dataset.Source,
+			Destination: dataset.Destination,
+			WithData:    dataset.WithData,
+			Tables:      dataset.Tables,
+			Project:     project,
+		})
+		results = append(results, models.PromotionResult{
+			Source:              dataset.Source,
+			Destination:         dataset.Destination,
+			CopyDatasetResponse: resp,
+			Err:                 err,
+		})
+		srv.notifyProgress(obs, &EventDatasetPromoted{
+			Source:      dataset.Source,
+			Destination: dataset.Destination,
+			Err:         err,
+		})
+		if err != nil {
+			// later datasets might hold views reading from this one
+			break
+		}
+	}
+	return results, nil
+}
+
 func (srv *Service) notifyProgress(po progress.Observer, event progress.Event) {
 	if po == nil {
 		return
@@ -171,8 +276,36 @@ type (
 		Spec models.ResourceSpec
 		Err  error
 	}
+
+	// EventResourcePlanned represents changes updating the resource would make
+	EventResourcePlanned struct {
+		Spec    models.ResourceSpec
+		Changes []string
+		Err     error
+	}
+
+	// EventDatasetPromoted represents a dataset of promotion being cloned
+	EventDatasetPromoted struct {
+		Source      string
+		Destination string
+		Err         error
+	}
 )
 
+func (e *EventDatasetPromoted) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("promoting: %s to %s, failed with error): %s", e.Source, e.Destination, e.Err.Error())
+	}
+	return fmt.Sprintf("promoted: %s to %s", e.Source, e.Destination)
+}
+
+func (e *EventResourcePlanned) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("planning: %s, failed with error): %s", e.Spec.Name, e.Err.Error())
+	}
+	return fmt.Sprintf("planned: %s\n%s", e.Spec.Name, strings.Join(e.Changes, "\n"))
+}
+
 func (e *EventResourceUpdated) String() string {
 	if e.Err != nil {
 		return fmt.Sprintf("updating: %s, failed with error): %s", e.Spec.Name, e.Err.Error())
@@ -2,12 +2,15 @@ package datastore_test
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
 
 	"github.com/odpf/optimus/datastore"
 	"github.com/odpf/optimus/mock"
@@ -85,12 +88,14 @@ func TestService(t *testing.T) {
 				Datastore: datastorer,
 			}
 			datastorer.On("CreateResource", context.TODO(), models.CreateResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec1,
+				Project:   projectSpec,
+				Resource:  resourceSpec1,
+				Namespace: namespaceSpec,
 			}).Return(nil)
 			datastorer.On("CreateResource", context.TODO(), models.CreateResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec2,
+				Project:   projectSpec,
+				Resource:  resourceSpec2,
+				Namespace: namespaceSpec,
 			}).Return(nil)
 
 			resourceRepo := new(mock.ResourceSpecRepository)
@@ -129,8 +134,9 @@ func TestService(t *testing.T) {
 				Datastore: datastorer,
 			}
 			datastorer.On("CreateResource", context.TODO(), models.CreateResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec2,
+				Project:   projectSpec,
+				Resource:  resourceSpec2,
+				Namespace: namespaceSpec,
 			}).Return(nil)
 
 			resourceRepo := new(mock.ResourceSpecRepository)
@@ -171,12 +177,14 @@ func TestService(t *testing.T) {
 				Datastore: datastorer,
 			}
 			datastorer.On("UpdateResource", context.TODO(), models.UpdateResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec1,
+				Project:   projectSpec,
+				Resource:  resourceSpec1,
+				Namespace: namespaceSpec,
 			}).Return(nil)
 			datastorer.On("UpdateResource", context.TODO(), models.UpdateResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec2,
+				Project:   projectSpec,
+				Resource:  resourceSpec2,
+				Namespace: namespaceSpec,
 			}).Return(nil)
 
 			resourceRepo := new(mock.ResourceSpecRepository)
@@ -215,8 +223,9 @@ func TestService(t *testing.T) {
 				Datastore: datastorer,
 			}
 			datastorer.On("UpdateResource", context.TODO(), models.UpdateResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec2,
+				Project:   projectSpec,
+				Resource:  resourceSpec2,
+				Namespace: namespaceSpec,
 			}).Return(nil)
 
 			resourceRepo := new(mock.ResourceSpecRepository)
@@ -236,6 +245,219 @@ func TestService(t *testing.T) {
 			assert.NotNil(t, err)
 		})
 	})
+	t.Run("UpdateResource in dependency order", func(t *testing.T) {
+		t.Run("should update resources after their dependencies and skip dependents of failed ones", func(t *testing.T) {
+			datastorer := new(mock.DatastoreDependencyResolver)
+			defer datastorer.AssertExpectations(t)
+
+			dataset := models.ResourceSpec{Name: "proj.datas", Type: models.ResourceTypeDataset, Datastore: datastorer}
+			table := models.ResourceSpec{Name: "proj.datas.tab", Type: models.ResourceTypeTable, Datastore: datastorer}
+			failingTable := models.ResourceSpec{Name: "proj.datas.bad", Type: models.ResourceTypeTable, Datastore: datastorer}
+			view := models.ResourceSpec{Name: "proj.datas.vw", Type: models.ResourceTypeView, Datastore: datastorer}
+			brokenView := models.ResourceSpec{Name: "proj.datas.bad_vw", Type: models.ResourceTypeView, Datastore: datastorer}
+			datastorer.On("ResourceDependencies", dataset).Return([]string{})
+			datastorer.On("ResourceDependencies", table).Return([]string{"proj.datas"})
+			datastorer.On("ResourceDependencies", failingTable).Return([]string{"proj.datas"})
+			datastorer.On("ResourceDependencies", view).Return([]string{"proj.datas", "proj.datas.tab", "other.datas.tab"})
+			datastorer.On("ResourceDependencies", brokenView).Return([]string{"proj.datas", "proj.datas.bad"})
+
+			var updated []string
+			for _, spec := range []models.ResourceSpec{dataset, table, view} {
+				name := spec.Name
+				datastorer.On("UpdateResource", context.TODO(), models.UpdateResourceRequest{
+					Project:   projectSpec,
+					Resource:  spec,
+					Namespace: namespaceSpec,
+				}).Run(func(args mock2.Arguments) {
+					updated = append(updated, name)
+				}).Return(nil)
+			}
+			datastorer.On("UpdateResource", context.TODO(), models.UpdateResourceRequest{
+				Project:   projectSpec,
+				Resource:  failingTable,
+				Namespace: namespaceSpec,
+			}).Return(errors.New("invalid schema"))
+
+			resourceRepo := new(mock.ResourceSpecRepository)
+			resourceRepo.On("Save", dataset).Return(nil)
+			resourceRepo.On("Save", table).Return(nil)
+			resourceRepo.On("Save", failingTable).Return(nil)
+			resourceRepo.On("Save", view).Return(nil)
+			defer resourceRepo.AssertExpectations(t)
+
+			resourceRepoFac := new(mock.ResourceSpecRepoFactory)
+			resourceRepoFac.On("New", namespaceSpec, datastorer).Return(resourceRepo)
+			defer resourceRepoFac.AssertExpectations(t)
+
+			obs := new(mock.PipelineLogObserver)
+			obs.On("Notify", mock2.Anything).Return()
+			defer obs.AssertExpectations(t)
+
+			service := datastore.NewService(resourceRepoFac, nil)
+			err := service.UpdateResource(context.TODO(), namespaceSpec,
+				[]models.ResourceSpec{brokenView, view, failingTable, table, dataset}, obs)
+			assert.NotNil(t, err)
+			assert.Equal(t, []string{"proj.datas", "proj.datas.tab", "proj.datas.vw"}, updated)
+			obs.AssertCalled(t, "Notify", mock2.MatchedBy(func(evt *datastore.EventResourceUpdated) bool {
+				return evt.Spec.Name == brokenView.Name && evt.Err != nil &&
+					evt.Err.Error() == "dependency proj.datas.bad of proj.datas.bad_vw failed"
+			}))
+		})
+	})
+	t.Run("UpdateResource with concurrency of kinds", func(t *testing.T) {
+		t.Run("should update datasets one at a time and report failures of each resource", func(t *testing.T) {
+			datastorer := new(mock.Datastorer)
+			defer datastorer.AssertExpectations(t)
+
+			var (
+				mu                sync.Mutex
+				running, maxTotal int
+			)
+			var specs []models.ResourceSpec
+			for _, name := range []string{"proj.a", "proj.b", "proj.c", "proj.d"} {
+				spec := models.ResourceSpec{Name: name, Type: models.ResourceTypeDataset, Datastore: datastorer}
+				specs = append(specs, spec)
+
+				var err error
+				if name == "proj.b" || name == "proj.d" {
+					err = errors.New("access denied")
+				}
+				datastorer.On("UpdateResource", context.TODO(), models.UpdateResourceRequest{
+					Project:   projectSpec,
+					Resource:  spec,
+					Namespace: namespaceSpec,
+				}).Run(func(args mock2.Arguments) {
+					mu.Lock()
+					running++
+					if running > maxTotal {
+						maxTotal = running
+					}
+					mu.Unlock()
+					time.Sleep(time.Millisecond * 10)
+					mu.Lock()
+					running--
+					mu.Unlock()
+				}).Return(err)
+			}
+
+			resourceRepo := new(mock.ResourceSpecRepository)
+			for _, spec := range specs {
+				resourceRepo.On("Save", spec).Return(nil)
+			}
+			resourceRepoFac := new(mock.ResourceSpecRepoFactory)
+			resourceRepoFac.On("New", namespaceSpec, datastorer).Return(resourceRepo)
+
+			service := datastore.NewService(resourceRepoFac, nil)
+			err := service.UpdateResource(context.TODO(), namespaceSpec, specs, nil)
+			assert.Equal(t, 1, maxTotal)
+			assert.Equal(t, "2 of 4 resources failed:\n\t* proj.b: access denied\n\t* proj.d: access denied", err.Error())
+		})
+	})
+	t.Run("PlanResource", func(t *testing.T) {
+		t.Run("should notify changes planned by datastore without saving resources", func(t *testing.T) {
+			planner := new(mock.DatastorePlanner)
+			defer planner.AssertExpectations(t)
+
+			resourceSpec := models.ResourceSpec{
+				Version:   1,
+				Name:      "proj.datas.tab",
+				Type:      models.ResourceTypeTable,
+				Datastore: planner,
+			}
+			planner.On("PlanResource", context.TODO(), models.UpdateResourceRequest{
+				Project:   projectSpec,
+				Resource:  resourceSpec,
+				Namespace: namespaceSpec,
+			}).Return([]string{"update table proj:datas.tab"}, nil)
+
+			resourceRepoFac := new(mock.ResourceSpecRepoFactory)
+			defer resourceRepoFac.AssertExpectations(t)
+
+			obs := new(mock.PipelineLogObserver)
+			obs.On("Notify", &datastore.EventResourcePlanned{
+				Spec:    resourceSpec,
+				Changes: []string{"update table proj:datas.tab"},
+			}).Return()
+			defer obs.AssertExpectations(t)
+
+			service := datastore.NewService(resourceRepoFac, nil)
+			err := service.PlanResource(context.TODO(), namespaceSpec, []models.ResourceSpec{resourceSpec}, obs)
+			assert.Nil(t, err)
+		})
+		t.Run("should fail for datastores which can't plan", func(t *testing.T) {
+			datastorer := new(mock.Datastorer)
+			datastorer.On("Name").Return("bq")
+			defer datastorer.AssertExpectations(t)
+
+			resourceSpec := models.ResourceSpec{
+				Version:   1,
+				Name:      "proj.datas",
+				Type:      models.ResourceTypeDataset,
+				Datastore: datastorer,
+			}
+			service := datastore.NewService(new(mock.ResourceSpecRepoFactory), nil)
+			err := service.PlanResource(context.TODO(), namespaceSpec, []models.ResourceSpec{resourceSpec}, nil)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("Promote", func(t *testing.T) {
+		manifest := models.PromotionManifest{
+			Datastore: "bq",
+			Datasets: []models.PromotionDataset{
+				{Source: "prod.events", Destination: "staging.events", WithData: true},
+				{Source: "prod.reports", Destination: "staging.reports"},
+				{Source: "prod.exports", Destination: "staging.exports"},
+			},
+		}
+		t.Run("should copy datasets in order till one fails", func(t *testing.T) {
+			copier := new(mock.DatastoreCopier)
+			defer copier.AssertExpectations(t)
+			copier.On("CopyDataset", context.TODO(), models.CopyDatasetRequest{
+				Source:      "prod.events",
+				Destination: "staging.events",
+				WithData:    true,
+				Project:     projectSpec,
+			}).Return(models.CopyDatasetResponse{Copied: []string{"clicks"}}, nil)
+			copyErr := errors.New("access denied")
+			copier.On("CopyDataset", context.TODO(), models.CopyDatasetRequest{
+				Source:      "prod.reports",
+				Destination: "staging.reports",
+				Project:     projectSpec,
+			}).Return(models.CopyDatasetResponse{}, copyErr)
+
+			dsRepo := new(mock.SupportedDatastoreRepo)
+			dsRepo.On("GetByName", "bq").Return(copier, nil)
+			defer dsRepo.AssertExpectations(t)
+
+			obs := new(mock.PipelineLogObserver)
+			obs.On("Notify", mock2.Anything).Return()
+			defer obs.AssertExpectations(t)
+
+			service := datastore.NewService(nil, dsRepo)
+			results, err := service.Promote(context.TODO(), projectSpec, manifest, obs)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.PromotionResult{
+				{
+					Source:              "prod.events",
+					Destination:         "staging.events",
+					CopyDatasetResponse: models.CopyDatasetResponse{Copied: []string{"clicks"}},
+				},
+				{Source: "prod.reports", Destination: "staging.reports", Err: copyErr},
+			}, results)
+			obs.AssertNumberOfCalls(t, "Notify", 2)
+		})
+		t.Run("should fail for datastores which can't copy datasets", func(t *testing.T) {
+			datastorer := new(mock.Datastorer)
+			datastorer.On("Name").Return("bq")
+
+			dsRepo := new(mock.SupportedDatastoreRepo)
+			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
+
+			service := datastore.NewService(nil, dsRepo)
+			_, err := service.Promote(context.TODO(), projectSpec, manifest, nil)
+			assert.NotNil(t, err)
+		})
+	})
 	t.Run("ReadResource", func(t *testing.T) {
 		t.Run("should successfully call datastore read operation by reading from persistent repository", func(t *testing.T) {
 			datastorer := new(mock.Datastorer)
@@ -252,8 +474,9 @@ func TestService(t *testing.T) {
 				Datastore: datastorer,
 			}
 			datastorer.On("ReadResource", context.TODO(), models.ReadResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec1,
+				Project:   projectSpec,
+				Resource:  resourceSpec1,
+				Namespace: namespaceSpec,
 			}).Return(models.ReadResourceResponse{Resource: resourceSpec1}, nil)
 
 			resourceRepo := new(mock.ResourceSpecRepository)
@@ -302,6 +525,51 @@ func TestService(t *testing.T) {
 			_, err := service.ReadResource(context.TODO(), namespaceSpec, "bq", resourceSpec1.Name)
 			assert.NotNil(t, err)
 		})
+		t.Run("should look up resource by urn of the datastore", func(t *testing.T) {
+			datastorer := new(mock.DatastoreURNResolver)
+			defer datastorer.AssertExpectations(t)
+
+			dsRepo := new(mock.SupportedDatastoreRepo)
+			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
+			defer dsRepo.AssertExpectations(t)
+
+			resourceSpec1 := models.ResourceSpec{
+				Version:   1,
+				Name:      "proj.datas.tab",
+				Type:      models.ResourceTypeTable,
+				Datastore: datastorer,
+			}
+			datastorer.On("ResourceName", models.URN{Scheme: models.URNSchemeBigQuery, Name: "proj:datas.tab"}).
+				Return(resourceSpec1.Name, nil)
+			datastorer.On("ReadResource", context.TODO(), models.ReadResourceRequest{
+				Project:   projectSpec,
+				Resource:  resourceSpec1,
+				Namespace: namespaceSpec,
+			}).Return(models.ReadResourceResponse{Resource: resourceSpec1}, nil)
+
+			resourceRepo := new(mock.ResourceSpecRepository)
+			resourceRepo.On("GetByName", resourceSpec1.Name).Return(resourceSpec1, nil)
+			defer resourceRepo.AssertExpectations(t)
+
+			resourceRepoFac := new(mock.ResourceSpecRepoFactory)
+			resourceRepoFac.On("New", namespaceSpec, datastorer).Return(resourceRepo)
+
+			service := datastore.NewService(resourceRepoFac, dsRepo)
+			resp, err := service.ReadResource(context.TODO(), namespaceSpec, "bq", "bigquery://proj:datas.tab")
+			assert.Nil(t, err)
+			assert.Equal(t, resourceSpec1, resp)
+		})
+		t.Run("should fail to look up resource by urn if datastore doesn't support them", func(t *testing.T) {
+			datastorer := new(mock.Datastorer)
+			datastorer.On("Name").Return("bq")
+
+			dsRepo := new(mock.SupportedDatastoreRepo)
+			dsRepo.On("GetByName", "bq").Return(datastorer, nil)
+
+			service := datastore.NewService(nil, dsRepo)
+			_, err := service.ReadResource(context.TODO(), namespaceSpec, "bq", "bigquery://proj:datas.tab")
+			assert.NotNil(t, err)
+		})
 	})
 	t.Run("DeleteResource", func(t *testing.T) {
 		t.Run("should successfully call datastore delete operation and then from persistent repository", func(t *testing.T) {
@@ -319,8 +587,9 @@ func TestService(t *testing.T) {
 				Datastore: datastorer,
 			}
 			datastorer.On("DeleteResource", context.TODO(), models.DeleteResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec1,
+				Project:   projectSpec,
+				Resource:  resourceSpec1,
+				Namespace: namespaceSpec,
 			}).Return(nil)
 
 			resourceRepo := new(mock.ResourceSpecRepository)
@@ -354,8 +623,9 @@ func TestService(t *testing.T) {
 				Datastore: datastorer,
 			}
 			datastorer.On("DeleteResource", context.TODO(), models.DeleteResourceRequest{
-				Project:  projectSpec,
-				Resource: resourceSpec1,
+				Project:   projectSpec,
+				Resource:  resourceSpec1,
+				Namespace: namespaceSpec,
 			}).Return(errors.New("failed to delete"))
 
 			resourceRepo := new(mock.ResourceSpecRepository)
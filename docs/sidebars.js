@@ -32,7 +32,12 @@ module.exports = {
         "guides/create-bigquery-view",
         "guides/organising-specifications",
         "guides/optimus-serve",
-        "guides/task-bq2bq"
+        "guides/task-bq2bq",
+        "guides/task-bq2gcs",
+        "guides/task-http2bq",
+        "guides/task-pyspark-python",
+        "guides/testing-job",
+        "guides/importing-from-dbt"
       ],
     },
     {
@@ -0,0 +1,273 @@
+package commitstatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	ProviderGithub = "github"
+	ProviderGitlab = "gitlab"
+
+	// DefaultTimeout of calls to api of git hosts
+	DefaultTimeout = time.Second * 30
+
+	// github accepts up to 50 annotations in a request of a check run, and
+	// descriptions of commit statuses up to 140 characters
+	maxGithubAnnotations       = 50
+	maxGithubStatusDescription = 140
+	maxGitlabDescription       = 255
+
+	maxResponseSize = 64 * 1024
+)
+
+// Publisher posts results of deployments as check runs of github, or commit
+// statuses of github and gitlab, using ProjectSecretCommitStatusToken secret
+// of project. Projects without the secret are skipped
+type Publisher struct {
+	client *http.Client
+}
+
+func (p *Publisher) Publish(ctx context.Context, projSpec models.ProjectSpec, status models.CommitStatus) error {
+	token, ok := projSpec.Secret.GetByName(models.ProjectSecretCommitStatusToken)
+	if !ok || token == "" {
+		return nil
+	}
+	repo, err := parseRepository(status.Ref.Repository)
+	if err != nil {
+		return err
+	}
+	provider := strings.ToLower(strings.TrimSpace(projSpec.Config[models.ProjectCommitStatusProviderKey]))
+	if provider == "" {
+		provider = repo.provider()
+	}
+	apiURL := strings.TrimSuffix(strings.TrimSpace(projSpec.Config[models.ProjectCommitStatusAPIURLKey]), "/")
+	if apiURL == "" {
+		apiURL = repo.apiURL(provider)
+	}
+
+	switch provider {
+	case ProviderGithub:
+		return p.publishGithub(ctx, apiURL, token, repo, status)
+	case ProviderGitlab:
+		return p.publishGitlab(ctx, apiURL, token, repo, status)
+	}
+	return errors.Errorf("unknown %s %s, should be %s or %s", models.ProjectCommitStatusProviderKey, provider,
+		ProviderGithub, ProviderGitlab)
+}
+
+// publishGithub creates a check run with annotations, tokens which can't
+// create check runs, e.g. personal access tokens, post a commit status
+// without annotations instead
+func (p *Publisher) publishGithub(ctx context.Context, apiURL, token string, repo repository, status models.CommitStatus) error {
+	headers := map[string]string{
+		"Authorization": "token " + token,
+		"Accept":        "application/vnd.github.v3+json",
+	}
+	conclusion := "success"
+	if status.State != models.CommitStateSuccess {
+		conclusion = "failure"
+	}
+	type annotation struct {
+		Path            string `json:"path"`
+		StartLine       int    `json:"start_line"`
+		EndLine         int    `json:"end_line"`
+		AnnotationLevel string `json:"annotation_level"`
+		Title           string `json:"title,omitempty"`
+		Message         string `json:"message"`
+	}
+	annotations := []annotation{}
+	for _, a := range status.Annotations {
+		if a.Path == "" || len(annotations) == maxGithubAnnotations {
+			continue
+		}
+		annotations = append(annotations, annotation{
+			Path:            a.Path,
+			StartLine:       1,
+			EndLine:         1,
+			AnnotationLevel: "failure",
+			Title:           a.Title,
+			Message:         a.Message,
+		})
+	}
+	checkRun := map[string]interface{}{
+		"name":       status.Name,
+		"head_sha":   status.Ref.SHA,
+		"status":     "completed",
+		"conclusion": conclusion,
+		"output": map[string]interface{}{
+			"title":       status.Description,
+			"summary":     summary(status),
+			"annotations": annotations,
+		},
+	}
+	statusCode, err := p.post(ctx, fmt.Sprintf("%s/repos/%s/check-runs", apiURL, repo.path), headers, checkRun)
+	if statusCode != http.StatusForbidden {
+		return err
+	}
+
+	commitStatus := map[string]string{
+		"state":       conclusion,
+		"description": truncate(status.Description, maxGithubStatusDescription),
+		"context":     status.Name,
+	}
+	_, err = p.post(ctx, fmt.Sprintf("%s/repos/%s/statuses/%s", apiURL, repo.path, status.Ref.SHA), headers, commitStatus)
+	return err
+}
+
+// publishGitlab posts a commit status, failures of jobs are commented on the
+// commit as gitlab has no annotations
+func (p *Publisher) publishGitlab(ctx context.Context, apiURL, token string, repo repository, status models.CommitStatus) error {
+	headers := map[string]string{
+		"PRIVATE-TOKEN": token,
+	}
+	state := "success"
+	if status.State != models.CommitStateSuccess {
+		state = "failed"
+	}
+	projectURL := fmt.Sprintf("%s/projects/%s", apiURL, url.PathEscape(repo.path))
+	if _, err := p.post(ctx, fmt.Sprintf("%s/statuses/%s", projectURL, status.Ref.SHA), headers, map[string]string{
+		"state":       state,
+		"name":        status.Name,
+		"description": truncate(status.Description, maxGitlabDescription),
+	}); err != nil {
+		return err
+	}
+	if len(status.Annotations) == 0 {
+		return nil
+	}
+	_, err := p.post(ctx, fmt.Sprintf("%s/repository/commits/%s/comments", projectURL, status.Ref.SHA), headers, map[string]string{
+		"note": summary(status),
+	})
+	return err
+}
+
+// summary lists failures of jobs in markdown
+func summary(status models.CommitStatus) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "**%s**: %s\n", status.Name, status.Description)
+	for _, a := range status.Annotations {
+		fmt.Fprintf(&buf, "\n- `%s`", a.Title)
+		if a.Path != "" {
+			fmt.Fprintf(&buf, " (%s)", a.Path)
+		}
+		fmt.Fprintf(&buf, ": %s", a.Message)
+	}
+	return buf.String()
+}
+
+// post sends body as json and returns status code of the response, calls
+// not succeeded are errors
+func (p *Publisher) post(ctx context.Context, url string, headers map[string]string, body interface{}) (int, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to call %s", url)
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.Errorf("%s responded with status %d: %s", url, resp.StatusCode, string(respBody))
+	}
+	return resp.StatusCode, nil
+}
+
+func truncate(text string, size int) string {
+	if len(text) <= size {
+		return text
+	}
+	return text[:size-3] + "..."
+}
+
+// repository is a repository at a git host, path is its full name, e.g.
+// odpf/optimus or a group path of gitlab
+type repository struct {
+	host string
+	path string
+}
+
+func (r repository) provider() string {
+	if strings.Contains(r.host, ProviderGitlab) {
+		return ProviderGitlab
+	}
+	return ProviderGithub
+}
+
+func (r repository) apiURL(provider string) string {
+	switch {
+	case provider == ProviderGitlab:
+		return "https://" + r.host + "/api/v4"
+	case r.host == "github.com":
+		return "https://api.github.com"
+	}
+	// github enterprise
+	return "https://" + r.host + "/api/v3"
+}
+
+// parseRepository accepts urls of repositories, e.g.
+// https://github.com/odpf/optimus.git, git@gitlab.com:group/repo.git or
+// github.com/odpf/optimus. Names without a host like odpf/optimus are of
+// github.com
+func parseRepository(name string) (repository, error) {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".git")
+	var repo repository
+	switch {
+	case strings.Contains(name, "://"):
+		u, err := url.Parse(name)
+		if err != nil {
+			return repository{}, errors.Wrapf(err, "invalid repository %s", name)
+		}
+		repo = repository{host: u.Host, path: u.Path}
+	case strings.Contains(name, "@") && strings.Contains(name, ":"):
+		// scp like syntax of ssh urls
+		hostPath := name[strings.Index(name, "@")+1:]
+		parts := strings.SplitN(hostPath, ":", 2)
+		repo = repository{host: parts[0], path: parts[1]}
+	default:
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) == 2 && strings.Contains(parts[0], ".") {
+			repo = repository{host: parts[0], path: parts[1]}
+		} else {
+			repo = repository{host: "github.com", path: name}
+		}
+	}
+	repo.path = strings.Trim(repo.path, "/")
+	if repo.host == "" || !strings.Contains(repo.path, "/") {
+		return repository{}, errors.Errorf("invalid repository %s, should be like github.com/owner/name", name)
+	}
+	return repo, nil
+}
+
+// NewPublisher uses client to call git hosts, a client with DefaultTimeout
+// is used if it is nil
+func NewPublisher(client *http.Client) *Publisher {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	return &Publisher{
+		client: client,
+	}
+}
@@ -0,0 +1,159 @@
+package commitstatus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/models"
+)
+
+type request struct {
+	path    string
+	headers http.Header
+	body    map[string]interface{}
+}
+
+type fakeHost struct {
+	mu       sync.Mutex
+	requests []request
+	status   map[string]int
+}
+
+func (f *fakeHost) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	body := map[string]interface{}{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	f.requests = append(f.requests, request{path: r.URL.EscapedPath(), headers: r.Header, body: body})
+	if code, ok := f.status[r.URL.EscapedPath()]; ok {
+		w.WriteHeader(code)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestParseRepository(t *testing.T) {
+	cases := map[string]repository{
+		"https://github.com/odpf/optimus.git":   {host: "github.com", path: "odpf/optimus"},
+		"git@gitlab.com:data/team/specs.git":    {host: "gitlab.com", path: "data/team/specs"},
+		"github.example.io/odpf/optimus":        {host: "github.example.io", path: "odpf/optimus"},
+		"odpf/optimus":                          {host: "github.com", path: "odpf/optimus"},
+		"ssh://git@gitlab.example.io/data/spec": {host: "gitlab.example.io", path: "data/spec"},
+	}
+	for name, expected := range cases {
+		repo, err := parseRepository(name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, expected, repo, name)
+	}
+
+	_, err := parseRepository("optimus")
+	assert.Equal(t, "invalid repository optimus, should be like github.com/owner/name", err.Error())
+}
+
+func TestPublisher(t *testing.T) {
+	failedStatus := models.CommitStatus{
+		Ref: models.CommitRef{
+			Repository: "github.com/odpf/specs",
+			SHA:        "abc123",
+			SpecPath:   "specs/jobs",
+		},
+		Name:        "optimus/deploy/team-a",
+		State:       models.CommitStateFailure,
+		Description: "1 of 2 jobs failed to deploy to team-a",
+		Annotations: []models.CommitAnnotation{
+			{
+				Path:    "specs/jobs/team/a-job/job.yaml",
+				Title:   "team.a-job",
+				Message: "failed to compile",
+			},
+		},
+	}
+	projectSpec := func(apiURL, provider string) models.ProjectSpec {
+		return models.ProjectSpec{
+			Name: "a-data-project",
+			Config: map[string]string{
+				models.ProjectCommitStatusAPIURLKey:   apiURL,
+				models.ProjectCommitStatusProviderKey: provider,
+			},
+			Secret: models.ProjectSecrets{
+				{Name: models.ProjectSecretCommitStatusToken, Value: "secret-token"},
+			},
+		}
+	}
+
+	t.Run("should create a github check run with annotations", func(t *testing.T) {
+		host := &fakeHost{}
+		server := httptest.NewServer(host)
+		defer server.Close()
+
+		err := NewPublisher(nil).Publish(context.Background(), projectSpec(server.URL, ""), failedStatus)
+		assert.Nil(t, err)
+
+		assert.Len(t, host.requests, 1)
+		req := host.requests[0]
+		assert.Equal(t, "/repos/odpf/specs/check-runs", req.path)
+		assert.Equal(t, "token secret-token", req.headers.Get("Authorization"))
+		assert.Equal(t, "abc123", req.body["head_sha"])
+		assert.Equal(t, "failure", req.body["conclusion"])
+		output := req.body["output"].(map[string]interface{})
+		annotations := output["annotations"].([]interface{})
+		assert.Len(t, annotations, 1)
+		assert.Equal(t, "specs/jobs/team/a-job/job.yaml", annotations[0].(map[string]interface{})["path"])
+	})
+	t.Run("should fall back to a github commit status if check runs are forbidden", func(t *testing.T) {
+		host := &fakeHost{status: map[string]int{"/repos/odpf/specs/check-runs": http.StatusForbidden}}
+		server := httptest.NewServer(host)
+		defer server.Close()
+
+		err := NewPublisher(nil).Publish(context.Background(), projectSpec(server.URL, ""), failedStatus)
+		assert.Nil(t, err)
+
+		assert.Len(t, host.requests, 2)
+		req := host.requests[1]
+		assert.Equal(t, "/repos/odpf/specs/statuses/abc123", req.path)
+		assert.Equal(t, "failure", req.body["state"])
+		assert.Equal(t, "optimus/deploy/team-a", req.body["context"])
+	})
+	t.Run("should post a gitlab commit status and comment failures", func(t *testing.T) {
+		host := &fakeHost{}
+		server := httptest.NewServer(host)
+		defer server.Close()
+
+		status := failedStatus
+		status.Ref.Repository = "git@gitlab.example.io:data/specs.git"
+		err := NewPublisher(nil).Publish(context.Background(), projectSpec(server.URL, ProviderGitlab), status)
+		assert.Nil(t, err)
+
+		assert.Len(t, host.requests, 2)
+		assert.Equal(t, "/projects/data%2Fspecs/statuses/abc123", host.requests[0].path)
+		assert.Equal(t, "secret-token", host.requests[0].headers.Get("PRIVATE-TOKEN"))
+		assert.Equal(t, "failed", host.requests[0].body["state"])
+		assert.Equal(t, "/projects/data%2Fspecs/repository/commits/abc123/comments", host.requests[1].path)
+		assert.Contains(t, host.requests[1].body["note"], "`team.a-job` (specs/jobs/team/a-job/job.yaml): failed to compile")
+	})
+	t.Run("should return error of git host", func(t *testing.T) {
+		host := &fakeHost{status: map[string]int{"/repos/odpf/specs/check-runs": http.StatusUnprocessableEntity}}
+		server := httptest.NewServer(host)
+		defer server.Close()
+
+		err := NewPublisher(nil).Publish(context.Background(), projectSpec(server.URL, ""), failedStatus)
+		assert.Contains(t, err.Error(), "responded with status 422")
+	})
+	t.Run("should skip projects without a token", func(t *testing.T) {
+		host := &fakeHost{}
+		server := httptest.NewServer(host)
+		defer server.Close()
+
+		projSpec := projectSpec(server.URL, "")
+		projSpec.Secret = nil
+		err := NewPublisher(nil).Publish(context.Background(), projSpec, failedStatus)
+		assert.Nil(t, err)
+		assert.Len(t, host.requests, 0)
+	})
+}
@@ -176,6 +176,11 @@ func bqSchemaTo(schema BQSchema) (bqapi.Schema, error) {
 			Required:    fm.required,
 			Repeated:    fm.repeated,
 		}
+		if len(field.PolicyTags) > 0 {
+			s.PolicyTags = &bqapi.PolicyTagList{
+				Names: field.PolicyTags,
+			}
+		}
 		s.Schema, err = bqSchemaTo(field.Schema)
 		if err != nil {
 			return nil, err
@@ -201,6 +206,9 @@ func bqSchemaFrom(schema bqapi.Schema) (BQSchema, error) {
 				required: field.Required,
 			}),
 		}
+		if field.PolicyTags != nil {
+			s.PolicyTags = field.PolicyTags.Names
+		}
 		s.Schema, err = bqSchemaFrom(field.Schema)
 		if err != nil {
 			return nil, err
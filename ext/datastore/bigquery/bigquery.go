@@ -3,6 +3,7 @@ package bigquery
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -13,12 +14,14 @@ import (
 const (
 	// Required secret
 	SecretName = "DATASTORE_BIGQUERY"
+
+	// DefaultOperationTimeout bounds operations on a resource whose caller
+	// has not set a deadline of its own
+	DefaultOperationTimeout = time.Minute * 5
 )
 
 var (
-	This = &BigQuery{
-		ClientFac: &defaultBQClientFactory{},
-	}
+	This = newBigQuery(newClientPool())
 
 	errSecretNotFoundStr = "secret %s required to migrate datastore not found for %s"
 )
@@ -28,7 +31,40 @@ type ClientFactory interface {
 }
 
 type BigQuery struct {
-	ClientFac ClientFactory
+	ClientFac    ClientFactory
+	IAMClientFac IAMClientFactory
+
+	// OperationTimeout is the deadline of operations on a resource if their
+	// context has none, operations are not bounded if it is 0
+	OperationTimeout time.Duration
+
+	pool *clientPool
+}
+
+func newBigQuery(pool *clientPool) *BigQuery {
+	return &BigQuery{
+		ClientFac:        &defaultBQClientFactory{pool: pool},
+		IAMClientFac:     &defaultIAMClientFactory{pool: pool},
+		OperationTimeout: DefaultOperationTimeout,
+		pool:             pool,
+	}
+}
+
+// Close releases clients pooled across requests
+func (b *BigQuery) Close() error {
+	if b.pool == nil {
+		return nil
+	}
+	return b.pool.Close()
+}
+
+// withTimeout bounds ctx of an operation on a resource by OperationTimeout
+// unless the caller already set a deadline
+func (b *BigQuery) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || b.OperationTimeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, b.OperationTimeout)
 }
 
 func (b BigQuery) Name() string {
@@ -45,13 +81,18 @@ func (b BigQuery) Types() map[models.ResourceType]models.DatastoreTypeController
 		models.ResourceTypeView:          &standardViewSpec{},
 		models.ResourceTypeDataset:       &datasetSpec{},
 		models.ResourceTypeExternalTable: &externalTableSpec{},
+
+		models.ResourceTypeRowAccessPolicy: &rowAccessPolicySpec{},
 	}
 }
 
 func (b *BigQuery) CreateResource(ctx context.Context, request models.CreateResourceRequest) error {
-	svcAcc, ok := request.Project.Secret.GetByName(SecretName)
-	if !ok || len(svcAcc) == 0 {
-		return errors.New(fmt.Sprintf(errSecretNotFoundStr, SecretName, b.Name()))
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return err
 	}
 
 	client, err := b.ClientFac.New(ctx, svcAcc)
@@ -61,21 +102,33 @@ func (b *BigQuery) CreateResource(ctx context.Context, request models.CreateReso
 
 	switch request.Resource.Type {
 	case models.ResourceTypeTable:
-		return createTable(ctx, request.Resource, client, false)
+		if err := createTable(ctx, request.Resource, client, false); err != nil {
+			return err
+		}
+		return b.reconcileTableIAM(ctx, svcAcc, request.Resource)
 	case models.ResourceTypeView:
 		return createStandardView(ctx, request.Resource, client, false)
 	case models.ResourceTypeDataset:
 		return createDataset(ctx, request.Resource, client, false)
 	case models.ResourceTypeExternalTable:
 		return createExternalTable(ctx, request.Resource, client, false)
+	case models.ResourceTypeRowAccessPolicy:
+		iamClient, err := b.IAMClientFac.New(ctx, svcAcc)
+		if err != nil {
+			return err
+		}
+		return createRowAccessPolicy(ctx, request.Resource, client, iamClient, false)
 	}
 	return fmt.Errorf("unsupported resource type %s", request.Resource.Type)
 }
 
 func (b *BigQuery) UpdateResource(ctx context.Context, request models.UpdateResourceRequest) error {
-	svcAcc, ok := request.Project.Secret.GetByName(SecretName)
-	if !ok || len(svcAcc) == 0 {
-		return errors.New(fmt.Sprintf(errSecretNotFoundStr, SecretName, b.Name()))
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return err
 	}
 
 	client, err := b.ClientFac.New(ctx, svcAcc)
@@ -85,21 +138,33 @@ func (b *BigQuery) UpdateResource(ctx context.Context, request models.UpdateReso
 
 	switch request.Resource.Type {
 	case models.ResourceTypeTable:
-		return createTable(ctx, request.Resource, client, true)
+		if err := createTable(ctx, request.Resource, client, true); err != nil {
+			return err
+		}
+		return b.reconcileTableIAM(ctx, svcAcc, request.Resource)
 	case models.ResourceTypeView:
 		return createStandardView(ctx, request.Resource, client, true)
 	case models.ResourceTypeDataset:
 		return createDataset(ctx, request.Resource, client, true)
 	case models.ResourceTypeExternalTable:
 		return createExternalTable(ctx, request.Resource, client, true)
+	case models.ResourceTypeRowAccessPolicy:
+		iamClient, err := b.IAMClientFac.New(ctx, svcAcc)
+		if err != nil {
+			return err
+		}
+		return createRowAccessPolicy(ctx, request.Resource, client, iamClient, true)
 	}
 	return fmt.Errorf("unsupported resource type %s", request.Resource.Type)
 }
 
 func (b *BigQuery) ReadResource(ctx context.Context, request models.ReadResourceRequest) (models.ReadResourceResponse, error) {
-	svcAcc, ok := request.Project.Secret.GetByName(SecretName)
-	if !ok || len(svcAcc) == 0 {
-		return models.ReadResourceResponse{}, errors.New(fmt.Sprintf(errSecretNotFoundStr, SecretName, b.Name()))
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return models.ReadResourceResponse{}, err
 	}
 
 	client, err := b.ClientFac.New(ctx, svcAcc)
@@ -132,14 +197,29 @@ func (b *BigQuery) ReadResource(ctx context.Context, request models.ReadResource
 		return models.ReadResourceResponse{
 			Resource: info,
 		}, nil
+	case models.ResourceTypeRowAccessPolicy:
+		iamClient, err := b.IAMClientFac.New(ctx, svcAcc)
+		if err != nil {
+			return models.ReadResourceResponse{}, err
+		}
+		info, err := getRowAccessPolicy(ctx, request.Resource, iamClient)
+		if err != nil {
+			return models.ReadResourceResponse{}, err
+		}
+		return models.ReadResourceResponse{
+			Resource: info,
+		}, nil
 	}
 	return models.ReadResourceResponse{}, fmt.Errorf("unsupported resource type %s", request.Resource.Type)
 }
 
 func (b *BigQuery) DeleteResource(ctx context.Context, request models.DeleteResourceRequest) error {
-	svcAcc, ok := request.Project.Secret.GetByName(SecretName)
-	if !ok || len(svcAcc) == 0 {
-		return errors.New(fmt.Sprintf(errSecretNotFoundStr, SecretName, b.Name()))
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return err
 	}
 
 	client, err := b.ClientFac.New(ctx, svcAcc)
@@ -154,10 +234,158 @@ func (b *BigQuery) DeleteResource(ctx context.Context, request models.DeleteReso
 		return deleteTable(ctx, request.Resource, client)
 	case models.ResourceTypeDataset:
 		return deleteDataset(ctx, request.Resource, client)
+	case models.ResourceTypeRowAccessPolicy:
+		return deleteRowAccessPolicy(ctx, request.Resource, client)
 	}
 	return fmt.Errorf("unsupported resource type %s", request.Resource.Type)
 }
 
+// CopyDataset clones a dataset into another, e.g. of another environment,
+// both have to be accessible to the service account of the project
+func (b *BigQuery) CopyDataset(ctx context.Context, request models.CopyDatasetRequest) (models.CopyDatasetResponse, error) {
+	svcAcc, err := b.credentials(request.Project, models.NamespaceSpec{})
+	if err != nil {
+		return models.CopyDatasetResponse{}, err
+	}
+	source, err := parseDatasetName(request.Source)
+	if err != nil {
+		return models.CopyDatasetResponse{}, err
+	}
+	destination, err := parseDatasetName(request.Destination)
+	if err != nil {
+		return models.CopyDatasetResponse{}, err
+	}
+
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return models.CopyDatasetResponse{}, err
+	}
+	return copyDataset(ctx, client, source, destination, request.WithData, request.Tables)
+}
+
+// RestoreResource restores a table as it was at a point in time within the
+// time travel window, in place or into a new table
+func (b *BigQuery) RestoreResource(ctx context.Context, request models.RestoreResourceRequest) error {
+	svcAcc, err := b.credentials(request.Project, models.NamespaceSpec{})
+	if err != nil {
+		return err
+	}
+	source, err := parseTableName(request.Name)
+	if err != nil {
+		return err
+	}
+	destination := source
+	if request.Destination != "" {
+		if destination, err = parseTableName(request.Destination); err != nil {
+			return err
+		}
+	}
+
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return err
+	}
+	return restoreTable(ctx, client, source, destination, request.PointInTime, time.Now())
+}
+
+// ImportDataset describes an existing dataset, with tables and views in it,
+// as resource specs
+func (b *BigQuery) ImportDataset(ctx context.Context, request models.ImportDatasetRequest) (models.ImportDatasetResponse, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	svcAcc, err := b.credentials(request.Project, models.NamespaceSpec{})
+	if err != nil {
+		return models.ImportDatasetResponse{}, err
+	}
+	dataset, err := parseDatasetName(request.Dataset)
+	if err != nil {
+		return models.ImportDatasetResponse{}, err
+	}
+
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return models.ImportDatasetResponse{}, err
+	}
+	resp, err := importDataset(ctx, client, dataset)
+	if err != nil {
+		return models.ImportDatasetResponse{}, err
+	}
+	for i := range resp.Resources {
+		resp.Resources[i].Datastore = b
+	}
+	return resp, nil
+}
+
+// PlanResource describes changes updating the resource would make without
+// applying them
+func (b *BigQuery) PlanResource(ctx context.Context, request models.UpdateResourceRequest) ([]string, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch request.Resource.Type {
+	case models.ResourceTypeTable:
+		bqTable, ok := request.Resource.Spec.(BQTable)
+		if !ok {
+			return nil, errors.New("failed to read table spec for bigquery")
+		}
+		var iamClient IAMClient
+		if len(bqTable.Metadata.IAM) > 0 {
+			if iamClient, err = b.IAMClientFac.New(ctx, svcAcc); err != nil {
+				return nil, err
+			}
+		}
+		return planTable(ctx, client, iamClient, bqTable)
+	case models.ResourceTypeRowAccessPolicy:
+		policy, ok := request.Resource.Spec.(BQRowAccessPolicy)
+		if !ok {
+			return nil, errors.New("failed to read row access policy spec for bigquery")
+		}
+		iamClient, err := b.IAMClientFac.New(ctx, svcAcc)
+		if err != nil {
+			return nil, err
+		}
+		return planRowAccessPolicy(ctx, iamClient, policy)
+	}
+	// changes of other resource types are not planned yet, they are only reported
+	// so dry run of a whole datastore doesn't fail
+	return []string{fmt.Sprintf("changes of resource type %s can't be planned yet", request.Resource.Type)}, nil
+}
+
+// credentials returns the service account key used for resources of
+// namespace, namespaces can be configured to use a project secret of their
+// own instead of the one shared across the project
+func (b *BigQuery) credentials(project models.ProjectSpec, namespace models.NamespaceSpec) (string, error) {
+	secretName := namespace.DatastoreSecretName(b.Name(), SecretName)
+	svcAcc, ok := project.Secret.GetByName(secretName)
+	if !ok || len(svcAcc) == 0 {
+		return "", errors.New(fmt.Sprintf(errSecretNotFoundStr, secretName, b.Name()))
+	}
+	return svcAcc, nil
+}
+
+func (b *BigQuery) reconcileTableIAM(ctx context.Context, svcAcc string, resourceSpec models.ResourceSpec) error {
+	bqTable, ok := resourceSpec.Spec.(BQTable)
+	if !ok || len(bqTable.Metadata.IAM) == 0 {
+		return nil
+	}
+	iamClient, err := b.IAMClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return err
+	}
+	return ensureTableIAM(ctx, iamClient, bqTable)
+}
+
 func init() {
 	if err := models.DatastoreRegistry.Add(This); err != nil {
 		panic(err)
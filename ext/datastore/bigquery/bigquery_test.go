@@ -126,6 +126,56 @@ func TestBigquery(t *testing.T) {
 
 			assert.NotNil(t, err)
 		})
+		t.Run("should use secret of namespace when namespace has its own", func(t *testing.T) {
+			resourceSpec := models.ResourceSpec{
+				Spec: BQDataset{
+					Project: testingProject,
+					Dataset: testingDataset,
+				},
+			}
+			namespaceSecret := "namespace_secret"
+			resourceRequest := models.CreateResourceRequest{
+				Resource: resourceSpec,
+				Project: models.ProjectSpec{
+					Secret: models.ProjectSecrets{
+						{Name: SecretName, Value: secret},
+						{Name: "DATASTORE_BIGQUERY_MARKETING", Value: namespaceSecret},
+					},
+				},
+				Namespace: models.NamespaceSpec{
+					Config: map[string]string{
+						models.NamespaceDatastoreSecretKeyPrefix + "BIGQUERY": "DATASTORE_BIGQUERY_MARKETING",
+					},
+				},
+			}
+
+			bQClientFactory := new(BQClientFactoryMock)
+			defer bQClientFactory.AssertExpectations(t)
+			bQClientFactory.On("New", testingContext, namespaceSecret).Return(new(BqClientMock), errors.New("some error"))
+
+			bq := BigQuery{
+				ClientFac: bQClientFactory,
+			}
+			err := bq.CreateResource(testingContext, resourceRequest)
+
+			assert.Equal(t, "some error", err.Error())
+		})
+		t.Run("should return error when secret of namespace is not found", func(t *testing.T) {
+			resourceRequest := models.CreateResourceRequest{
+				Resource: models.ResourceSpec{Spec: BQDataset{Project: testingProject, Dataset: testingDataset}},
+				Project:  projectSpec,
+				Namespace: models.NamespaceSpec{
+					Config: map[string]string{
+						models.NamespaceDatastoreSecretKeyPrefix + "BIGQUERY": "DATASTORE_BIGQUERY_MARKETING",
+					},
+				},
+			}
+
+			bq := BigQuery{}
+			err := bq.CreateResource(testingContext, resourceRequest)
+
+			assert.Equal(t, "secret DATASTORE_BIGQUERY_MARKETING required to migrate datastore not found for bigquery", err.Error())
+		})
 	})
 	t.Run("UpdateResource", func(t *testing.T) {
 		t.Run("should return error when secret not found", func(t *testing.T) {
@@ -0,0 +1,83 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	bqapi "cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+)
+
+var (
+	// full ids of jobs, e.g. project:location.job_id, projects of a domain
+	// are prefixed with it e.g. example.com:project
+	jobIDRegex = regexp.MustCompile(`^([\w.:-]+):([\w-]+)\.([\w-]+)$`)
+)
+
+// BQJob is a job run in a project and location of bigquery
+type BQJob struct {
+	Project  string
+	Location string
+	ID       string
+}
+
+// JobCost looks up bytes billed and slot time of a job run by a task. Jobs
+// are read from INFORMATION_SCHEMA.JOBS_BY_PROJECT which needs
+// bigquery.jobs.listAll permission and may take a few seconds to list a
+// finished job
+func (b *BigQuery) JobCost(ctx context.Context, request models.JobCostRequest) (models.JobCostResponse, error) {
+	parts := jobIDRegex.FindStringSubmatch(request.JobID)
+	if parts == nil {
+		return models.JobCostResponse{}, nil
+	}
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return models.JobCostResponse{}, err
+	}
+
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return models.JobCostResponse{}, err
+	}
+	resp, err := jobCost(ctx, client, BQJob{Project: parts[1], Location: parts[2], ID: parts[3]}, request.Since)
+	if err != nil {
+		return models.JobCostResponse{}, err
+	}
+	resp.Supported = true
+	return resp, nil
+}
+
+func jobCost(ctx context.Context, client bqiface.Client, job BQJob, since time.Time) (models.JobCostResponse, error) {
+	sql := fmt.Sprintf("SELECT total_bytes_billed, total_slot_ms "+
+		"FROM `%s`.`region-%s`.INFORMATION_SCHEMA.JOBS_BY_PROJECT "+
+		"WHERE creation_time >= TIMESTAMP_MILLIS(%d) AND job_id = '%s'",
+		job.Project, strings.ToLower(job.Location), since.UnixNano()/int64(time.Millisecond), job.ID)
+
+	it, err := client.Query(sql).Read(ctx)
+	if err != nil {
+		return models.JobCostResponse{}, errors.Wrapf(err, "failed to look up job %s", job.ID)
+	}
+	var row struct {
+		BytesBilled bqapi.NullInt64 `bigquery:"total_bytes_billed"`
+		SlotMillis  bqapi.NullInt64 `bigquery:"total_slot_ms"`
+	}
+	if err := it.Next(&row); err != nil {
+		if err == iterator.Done {
+			return models.JobCostResponse{}, errors.Errorf("job %s not found in project %s", job.ID, job.Project)
+		}
+		return models.JobCostResponse{}, errors.Wrapf(err, "failed to look up job %s", job.ID)
+	}
+	// jobs served from cache are billed nothing
+	return models.JobCostResponse{
+		BytesBilled: row.BytesBilled.Int64,
+		SlotMillis:  row.SlotMillis.Int64,
+	}, nil
+}
@@ -0,0 +1,63 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/api/iterator"
+)
+
+func TestJobCost(t *testing.T) {
+	ctx := context.Background()
+	since := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	sql := "SELECT total_bytes_billed, total_slot_ms " +
+		"FROM `example.com:proj`.`region-us`.INFORMATION_SCHEMA.JOBS_BY_PROJECT " +
+		"WHERE creation_time >= TIMESTAMP_MILLIS(1622505600000) AND job_id = 'bquxjob_1a2b'"
+	type costRow = struct {
+		BytesBilled bigquery.NullInt64 `bigquery:"total_bytes_billed"`
+		SlotMillis  bigquery.NullInt64 `bigquery:"total_slot_ms"`
+	}
+
+	t.Run("should return bytes billed and slot time of job", func(t *testing.T) {
+		it := new(BqRowIteratorMock)
+		it.On("Next", mock.Anything).Run(func(args mock.Arguments) {
+			row := args.Get(0).(*costRow)
+			row.BytesBilled = bigquery.NullInt64{Int64: 10485760, Valid: true}
+			row.SlotMillis = bigquery.NullInt64{Int64: 4200, Valid: true}
+		}).Return(nil)
+		query := new(BqQueryMock)
+		defer query.AssertExpectations(t)
+		query.On("Read", ctx).Return(it, nil)
+		client := new(BqClientMock)
+		client.On("Query", sql).Return(query)
+
+		resp, err := jobCost(ctx, client, BQJob{Project: "example.com:proj", Location: "US", ID: "bquxjob_1a2b"}, since)
+		assert.Nil(t, err)
+		assert.Equal(t, models.JobCostResponse{BytesBilled: 10485760, SlotMillis: 4200}, resp)
+	})
+	t.Run("should fail if job is not listed", func(t *testing.T) {
+		it := new(BqRowIteratorMock)
+		it.On("Next", mock.Anything).Return(iterator.Done)
+		query := new(BqQueryMock)
+		query.On("Read", ctx).Return(it, nil)
+		client := new(BqClientMock)
+		client.On("Query", sql).Return(query)
+
+		_, err := jobCost(ctx, client, BQJob{Project: "example.com:proj", Location: "US", ID: "bquxjob_1a2b"}, since)
+		assert.NotNil(t, err)
+	})
+	t.Run("should parse full ids of jobs", func(t *testing.T) {
+		parts := jobIDRegex.FindStringSubmatch("example.com:proj:asia-southeast2.bquxjob_1a2b")
+		assert.Equal(t, []string{"example.com:proj", "asia-southeast2", "bquxjob_1a2b"}, parts[1:])
+	})
+	t.Run("should not support ids of jobs of other datastores", func(t *testing.T) {
+		resp, err := (&BigQuery{}).JobCost(ctx, models.JobCostRequest{JobID: "spark-application-1"})
+		assert.Nil(t, err)
+		assert.False(t, resp.Supported)
+	})
+}
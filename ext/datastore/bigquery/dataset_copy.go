@@ -0,0 +1,183 @@
+package bigquery
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	bqapi "cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+)
+
+func parseDatasetName(name string) (BQDataset, error) {
+	parts := datasetNameParseRegex.FindStringSubmatch(name)
+	if len(parts) < 3 {
+		return BQDataset{}, errors.Errorf("invalid dataset name %s, expected project.dataset", name)
+	}
+	return BQDataset{Project: parts[1], Dataset: parts[2]}, nil
+}
+
+// copyDataset clones tables of source dataset into destination, views read
+// from destination wherever they read from source. Tables are copied with
+// their data using copy jobs if withData is set, tables existing in
+// destination are then overwritten, otherwise they are left as is
+func copyDataset(ctx context.Context, client bqiface.Client, source, destination BQDataset, withData bool,
+	tableNames []string) (models.CopyDatasetResponse, error) {
+	srcDataset := client.DatasetInProject(source.Project, source.Dataset)
+	srcMeta, err := srcDataset.Metadata(ctx)
+	if err != nil {
+		return models.CopyDatasetResponse{}, errors.Wrapf(err, "failed to read dataset %s.%s", source.Project, source.Dataset)
+	}
+	dstDataset := client.DatasetInProject(destination.Project, destination.Dataset)
+	if err := ensureDatasetCopy(ctx, dstDataset, srcMeta); err != nil {
+		return models.CopyDatasetResponse{}, errors.Wrapf(err, "failed to create dataset %s.%s", destination.Project, destination.Dataset)
+	}
+
+	if len(tableNames) == 0 {
+		if tableNames, err = listTables(ctx, srcDataset); err != nil {
+			return models.CopyDatasetResponse{}, errors.Wrapf(err, "failed to list tables of %s.%s", source.Project, source.Dataset)
+		}
+	}
+	var (
+		resp  models.CopyDatasetResponse
+		views []string
+	)
+	metas := map[string]*bqapi.TableMetadata{}
+	for _, name := range tableNames {
+		meta, err := srcDataset.Table(name).Metadata(ctx)
+		if err != nil {
+			return resp, errors.Wrapf(err, "failed to read table %s.%s.%s", source.Project, source.Dataset, name)
+		}
+		metas[name] = meta
+		// views are created after the tables they might read from
+		if meta.Type == bqapi.ViewTable {
+			views = append(views, name)
+			continue
+		}
+		copied, err := copyTable(ctx, srcDataset.Table(name), dstDataset.Table(name), meta, withData)
+		if err != nil {
+			return resp, errors.Wrapf(err, "failed to copy table %s.%s.%s", source.Project, source.Dataset, name)
+		}
+		if copied {
+			resp.Copied = append(resp.Copied, name)
+		} else {
+			resp.Skipped = append(resp.Skipped, name)
+		}
+	}
+
+	rewriter := strings.NewReplacer(source.Project+"."+source.Dataset+".", destination.Project+"."+destination.Dataset+".")
+	for _, name := range views {
+		meta := metas[name]
+		copied, err := createIfNotExists(ctx, dstDataset.Table(name), &bqapi.TableMetadata{
+			Description:  meta.Description,
+			Labels:       meta.Labels,
+			ViewQuery:    rewriter.Replace(meta.ViewQuery),
+			UseLegacySQL: meta.UseLegacySQL,
+		})
+		if err != nil {
+			return resp, errors.Wrapf(err, "failed to copy view %s.%s.%s", source.Project, source.Dataset, name)
+		}
+		if copied {
+			resp.Copied = append(resp.Copied, name)
+		} else {
+			resp.Skipped = append(resp.Skipped, name)
+		}
+	}
+	return resp, nil
+}
+
+// ensureDatasetCopy creates destination dataset like the source if it doesn't
+// exist, in the same location as copy jobs can't cross locations
+func ensureDatasetCopy(ctx context.Context, datasetHandle bqiface.Dataset, srcMeta *bqiface.DatasetMetadata) error {
+	datasetMutex.Lock()
+	defer datasetMutex.Unlock()
+
+	if _, err := datasetHandle.Metadata(ctx); err != nil {
+		if metaErr, ok := err.(*googleapi.Error); !ok || metaErr.Code != http.StatusNotFound {
+			return err
+		}
+		return datasetHandle.Create(ctx, &bqiface.DatasetMetadata{
+			DatasetMetadata: bqapi.DatasetMetadata{
+				Description:            srcMeta.Description,
+				Labels:                 srcMeta.Labels,
+				Location:               srcMeta.Location,
+				DefaultTableExpiration: srcMeta.DefaultTableExpiration,
+			},
+		})
+	}
+	return nil
+}
+
+func listTables(ctx context.Context, datasetHandle bqiface.Dataset) ([]string, error) {
+	var names []string
+	tables := datasetHandle.Tables(ctx)
+	for {
+		table, err := tables.Next()
+		if err == iterator.Done {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, table.TableID())
+	}
+}
+
+// copyTable clones a table, or an external table, returns false if it was
+// left out as it already exists or can't be cloned
+func copyTable(ctx context.Context, src, dst bqiface.Table, meta *bqapi.TableMetadata, withData bool) (bool, error) {
+	switch meta.Type {
+	case bqapi.RegularTable:
+		if !withData {
+			return createIfNotExists(ctx, dst, &bqapi.TableMetadata{
+				Description:            meta.Description,
+				Labels:                 meta.Labels,
+				Schema:                 meta.Schema,
+				TimePartitioning:       meta.TimePartitioning,
+				RangePartitioning:      meta.RangePartitioning,
+				RequirePartitionFilter: meta.RequirePartitionFilter,
+				Clustering:             meta.Clustering,
+			})
+		}
+		copier := dst.CopierFrom(src)
+		copier.SetCopyConfig(bqiface.CopyConfig{
+			CopyConfig: bqapi.CopyConfig{
+				CreateDisposition: bqapi.CreateIfNeeded,
+				WriteDisposition:  bqapi.WriteTruncate,
+			},
+			Srcs: []bqiface.Table{src},
+			Dst:  dst,
+		})
+		job, err := copier.Run(ctx)
+		if err != nil {
+			return false, err
+		}
+		status, err := job.Wait(ctx)
+		if err != nil {
+			return false, err
+		}
+		return true, status.Err()
+	case bqapi.ExternalTable:
+		return createIfNotExists(ctx, dst, &bqapi.TableMetadata{
+			Description:        meta.Description,
+			Labels:             meta.Labels,
+			Schema:             meta.Schema,
+			ExternalDataConfig: meta.ExternalDataConfig,
+		})
+	}
+	// materialized views, snapshots and such
+	return false, nil
+}
+
+func createIfNotExists(ctx context.Context, tableHandle bqiface.Table, meta *bqapi.TableMetadata) (bool, error) {
+	if _, err := tableHandle.Metadata(ctx); err == nil {
+		return false, nil
+	} else if metaErr, ok := err.(*googleapi.Error); !ok || metaErr.Code != http.StatusNotFound {
+		return false, err
+	}
+	return true, tableHandle.Create(ctx, meta)
+}
@@ -0,0 +1,128 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+)
+
+func TestCopyDataset(t *testing.T) {
+	ctx := context.Background()
+	source := BQDataset{Project: "prod-project", Dataset: "playground"}
+	destination := BQDataset{Project: "staging-project", Dataset: "playground"}
+	errNotFound := &googleapi.Error{
+		Code: 404,
+	}
+	sourceMeta := &bqiface.DatasetMetadata{
+		DatasetMetadata: bigquery.DatasetMetadata{
+			Description: "events of the game",
+			Location:    "EU",
+		},
+	}
+	schema := bigquery.Schema{
+		&bigquery.FieldSchema{Name: "event_id", Type: bigquery.StringFieldType},
+		&bigquery.FieldSchema{Name: "event_timestamp", Type: bigquery.TimestampFieldType},
+	}
+
+	t.Run("should clone schema of tables into a new dataset and views reading from it", func(t *testing.T) {
+		srcDataset := new(BqDatasetMock)
+		defer srcDataset.AssertExpectations(t)
+		dstDataset := new(BqDatasetMock)
+		defer dstDataset.AssertExpectations(t)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", source.Project, source.Dataset).Return(srcDataset)
+		client.On("DatasetInProject", destination.Project, destination.Dataset).Return(dstDataset)
+
+		srcDataset.On("Metadata", ctx).Return(sourceMeta, nil)
+		dstDataset.On("Metadata", ctx).Return((*bqiface.DatasetMetadata)(nil), errNotFound)
+		dstDataset.On("Create", ctx, &bqiface.DatasetMetadata{
+			DatasetMetadata: bigquery.DatasetMetadata{
+				Description: "events of the game",
+				Location:    "EU",
+			},
+		}).Return(nil)
+
+		srcEvents, srcDailyEvents := new(BqTableMock), new(BqTableMock)
+		srcEvents.On("TableID").Return("events")
+		srcDailyEvents.On("TableID").Return("daily_events")
+		tables := new(BqTableIteratorMock)
+		tables.On("Next").Return(srcDailyEvents, nil).Once()
+		tables.On("Next").Return(srcEvents, nil).Once()
+		tables.On("Next").Return((*BqTableMock)(nil), iterator.Done).Once()
+		srcDataset.On("Tables", ctx).Return(tables)
+
+		srcDataset.On("Table", "events").Return(srcEvents)
+		srcEvents.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+			Type:   bigquery.RegularTable,
+			Schema: schema,
+		}, nil)
+		srcDataset.On("Table", "daily_events").Return(srcDailyEvents)
+		srcDailyEvents.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+			Type:      bigquery.ViewTable,
+			ViewQuery: "select * from `prod-project.playground.events` join `prod-project.lookups.games`",
+		}, nil)
+
+		dstEvents, dstDailyEvents := new(BqTableMock), new(BqTableMock)
+		defer dstEvents.AssertExpectations(t)
+		defer dstDailyEvents.AssertExpectations(t)
+		dstDataset.On("Table", "events").Return(dstEvents)
+		dstEvents.On("Metadata", ctx).Return((*bigquery.TableMetadata)(nil), errNotFound)
+		dstEvents.On("Create", ctx, &bigquery.TableMetadata{Schema: schema}).Return(nil)
+		dstDataset.On("Table", "daily_events").Return(dstDailyEvents)
+		dstDailyEvents.On("Metadata", ctx).Return((*bigquery.TableMetadata)(nil), errNotFound)
+		dstDailyEvents.On("Create", ctx, &bigquery.TableMetadata{
+			ViewQuery: "select * from `staging-project.playground.events` join `prod-project.lookups.games`",
+		}).Return(nil)
+
+		resp, err := copyDataset(ctx, client, source, destination, false, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, models.CopyDatasetResponse{Copied: []string{"events", "daily_events"}}, resp)
+	})
+	t.Run("should copy data of tables with copy jobs", func(t *testing.T) {
+		srcDataset := new(BqDatasetMock)
+		defer srcDataset.AssertExpectations(t)
+		dstDataset := new(BqDatasetMock)
+		defer dstDataset.AssertExpectations(t)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", source.Project, source.Dataset).Return(srcDataset)
+		client.On("DatasetInProject", destination.Project, destination.Dataset).Return(dstDataset)
+
+		srcDataset.On("Metadata", ctx).Return(sourceMeta, nil)
+		dstDataset.On("Metadata", ctx).Return(sourceMeta, nil)
+
+		srcEvents, dstEvents := new(BqTableMock), new(BqTableMock)
+		defer dstEvents.AssertExpectations(t)
+		srcDataset.On("Table", "events").Return(srcEvents)
+		srcEvents.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+			Type:   bigquery.RegularTable,
+			Schema: schema,
+		}, nil)
+		dstDataset.On("Table", "events").Return(dstEvents)
+
+		job := new(BqJobMock)
+		defer job.AssertExpectations(t)
+		job.On("Wait", ctx).Return(&bigquery.JobStatus{State: bigquery.Done}, nil)
+		copier := new(BqCopierMock)
+		defer copier.AssertExpectations(t)
+		copier.On("SetCopyConfig", bqiface.CopyConfig{
+			CopyConfig: bigquery.CopyConfig{
+				CreateDisposition: bigquery.CreateIfNeeded,
+				WriteDisposition:  bigquery.WriteTruncate,
+			},
+			Srcs: []bqiface.Table{srcEvents},
+			Dst:  dstEvents,
+		}).Return()
+		copier.On("Run", ctx).Return(job, nil)
+		dstEvents.On("CopierFrom", []bqiface.Table{srcEvents}).Return(copier)
+
+		resp, err := copyDataset(ctx, client, source, destination, true, []string{"events"})
+		assert.Nil(t, err)
+		assert.Equal(t, models.CopyDatasetResponse{Copied: []string{"events"}}, resp)
+	})
+}
@@ -0,0 +1,73 @@
+package bigquery
+
+import (
+	"context"
+
+	bqapi "cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// importedSpecVersion is the version of resource specs created by import
+	importedSpecVersion = 1
+)
+
+// importDataset describes an existing dataset along with its tables and views
+// as resource specs. Queries of views are kept as their view.sql asset,
+// external tables, materialized views and snapshots are skipped
+func importDataset(ctx context.Context, client bqiface.Client, dataset BQDataset) (models.ImportDatasetResponse, error) {
+	datasetSpec, err := getDataset(ctx, models.ResourceSpec{Spec: dataset}, client)
+	if err != nil {
+		return models.ImportDatasetResponse{}, errors.Wrapf(err, "failed to read dataset %s.%s", dataset.Project, dataset.Dataset)
+	}
+	datasetSpec.Version = importedSpecVersion
+	datasetSpec.Name = dataset.Project + "." + dataset.Dataset
+	datasetSpec.Type = models.ResourceTypeDataset
+
+	datasetHandle := client.DatasetInProject(dataset.Project, dataset.Dataset)
+	tableNames, err := listTables(ctx, datasetHandle)
+	if err != nil {
+		return models.ImportDatasetResponse{}, errors.Wrapf(err, "failed to list tables of %s.%s", dataset.Project, dataset.Dataset)
+	}
+
+	resp := models.ImportDatasetResponse{
+		Resources: []models.ResourceSpec{datasetSpec},
+	}
+	for _, name := range tableNames {
+		table := BQTable{Project: dataset.Project, Dataset: dataset.Dataset, Table: name}
+		meta, err := datasetHandle.Table(name).Metadata(ctx)
+		if err != nil {
+			return resp, errors.Wrapf(err, "failed to read table %s", table.FullyQualifiedName())
+		}
+
+		resourceSpec := models.ResourceSpec{
+			Version: importedSpecVersion,
+			Name:    dataset.Project + "." + dataset.Dataset + "." + name,
+		}
+		switch meta.Type {
+		case bqapi.RegularTable:
+			if table.Metadata, err = bqTableMetadataFrom(meta); err != nil {
+				return resp, errors.Wrapf(err, "failed to read table %s", table.FullyQualifiedName())
+			}
+			resourceSpec.Type = models.ResourceTypeTable
+		case bqapi.ViewTable:
+			// schema of views is derived from their query
+			table.Metadata = BQTableMetadata{
+				Description: meta.Description,
+				Labels:      meta.Labels,
+			}
+			resourceSpec.Type = models.ResourceTypeView
+			resourceSpec.Assets = map[string]string{
+				ViewQueryFile: meta.ViewQuery,
+			}
+		default:
+			resp.Skipped = append(resp.Skipped, resourceSpec.Name)
+			continue
+		}
+		resourceSpec.Spec = table
+		resp.Resources = append(resp.Resources, resourceSpec)
+	}
+	return resp, nil
+}
@@ -0,0 +1,130 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+)
+
+func TestImportDataset(t *testing.T) {
+	ctx := context.Background()
+	dataset := BQDataset{Project: "proj", Dataset: "playground"}
+	datasetMeta := &bqiface.DatasetMetadata{
+		DatasetMetadata: bigquery.DatasetMetadata{
+			Description: "events of the game",
+			Location:    "EU",
+		},
+	}
+
+	t.Run("should describe dataset with its tables and views as resource specs", func(t *testing.T) {
+		events, dailyEvents, eventsLake := new(BqTableMock), new(BqTableMock), new(BqTableMock)
+		events.On("TableID").Return("events")
+		dailyEvents.On("TableID").Return("daily_events")
+		eventsLake.On("TableID").Return("events_lake")
+		tables := new(BqTableIteratorMock)
+		tables.On("Next").Return(events, nil).Once()
+		tables.On("Next").Return(dailyEvents, nil).Once()
+		tables.On("Next").Return(eventsLake, nil).Once()
+		tables.On("Next").Return((*BqTableMock)(nil), iterator.Done).Once()
+
+		datasetHandle := new(BqDatasetMock)
+		datasetHandle.On("Metadata", ctx).Return(datasetMeta, nil)
+		datasetHandle.On("Tables", ctx).Return(tables)
+		datasetHandle.On("Table", "events").Return(events)
+		datasetHandle.On("Table", "daily_events").Return(dailyEvents)
+		datasetHandle.On("Table", "events_lake").Return(eventsLake)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "playground").Return(datasetHandle)
+
+		events.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+			Type:        bigquery.RegularTable,
+			Description: "raw events",
+			Schema: bigquery.Schema{
+				&bigquery.FieldSchema{Name: "event_id", Type: bigquery.StringFieldType, Required: true},
+				&bigquery.FieldSchema{Name: "event_timestamp", Type: bigquery.TimestampFieldType},
+			},
+			TimePartitioning: &bigquery.TimePartitioning{Field: "event_timestamp"},
+			Clustering:       &bigquery.Clustering{Fields: []string{"event_id"}},
+		}, nil)
+		dailyEvents.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+			Type:      bigquery.ViewTable,
+			Labels:    map[string]string{"owner": "game"},
+			ViewQuery: "select * from `proj.playground.events`",
+			Schema: bigquery.Schema{
+				&bigquery.FieldSchema{Name: "event_id", Type: bigquery.StringFieldType},
+			},
+		}, nil)
+		eventsLake.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+			Type: bigquery.ExternalTable,
+		}, nil)
+
+		resp, err := importDataset(ctx, client, dataset)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"proj.playground.events_lake"}, resp.Skipped)
+		assert.Equal(t, []models.ResourceSpec{
+			{
+				Version: 1,
+				Name:    "proj.playground",
+				Type:    models.ResourceTypeDataset,
+				Spec: BQDataset{
+					Project: "proj",
+					Dataset: "playground",
+					Metadata: BQDatasetMetadata{
+						Description: "events of the game",
+						Location:    "EU",
+					},
+				},
+			},
+			{
+				Version: 1,
+				Name:    "proj.playground.events",
+				Type:    models.ResourceTypeTable,
+				Spec: BQTable{
+					Project: "proj",
+					Dataset: "playground",
+					Table:   "events",
+					Metadata: BQTableMetadata{
+						Description: "raw events",
+						Schema: BQSchema{
+							{Name: "event_id", Type: "STRING", Mode: "required", Schema: BQSchema{}},
+							{Name: "event_timestamp", Type: "TIMESTAMP", Mode: "nullable", Schema: BQSchema{}},
+						},
+						Cluster:   &BQClusteringInfo{Using: []string{"event_id"}},
+						Partition: bqPartitioningFrom(&bigquery.TimePartitioning{Field: "event_timestamp"}),
+					},
+				},
+			},
+			{
+				Version: 1,
+				Name:    "proj.playground.daily_events",
+				Type:    models.ResourceTypeView,
+				Spec: BQTable{
+					Project: "proj",
+					Dataset: "playground",
+					Table:   "daily_events",
+					Metadata: BQTableMetadata{
+						Labels: map[string]string{"owner": "game"},
+					},
+				},
+				Assets: map[string]string{
+					ViewQueryFile: "select * from `proj.playground.events`",
+				},
+			},
+		}, resp.Resources)
+	})
+	t.Run("should fail if dataset doesn't exist", func(t *testing.T) {
+		datasetHandle := new(BqDatasetMock)
+		datasetHandle.On("Metadata", ctx).Return((*bqiface.DatasetMetadata)(nil), &googleapi.Error{Code: 404, Message: "not found"})
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "playground").Return(datasetHandle)
+
+		_, err := importDataset(ctx, client, dataset)
+		assert.Contains(t, err.Error(), "failed to read dataset proj.playground")
+	})
+}
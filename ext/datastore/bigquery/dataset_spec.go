@@ -20,7 +20,9 @@ var (
 
 // DatasetResourceSpec is how dataset should be represented in yaml
 type DatasetResourceSpec struct {
-	Version int
+	APIVersion int `yaml:"apiVersion,omitempty"`
+	// Version is what apiVersion was written as before it
+	Version int `yaml:"version,omitempty"`
 	Name    string
 	Type    models.ResourceType
 	Spec    BQDatasetMetadata
@@ -58,11 +60,11 @@ func (s datasetSpecHandler) ToYaml(optResource models.ResourceSpec) ([]byte, err
 	}
 
 	yamlResource := DatasetResourceSpec{
-		Version: optResource.Version,
-		Name:    optResource.Name,
-		Type:    optResource.Type,
-		Spec:    bqResource.Metadata,
-		Labels:  optResource.Labels,
+		APIVersion: optResource.Version,
+		Name:       optResource.Name,
+		Type:       optResource.Type,
+		Spec:       bqResource.Metadata,
+		Labels:     optResource.Labels,
 	}
 	return yaml.Marshal(yamlResource)
 }
@@ -79,7 +81,7 @@ func (s datasetSpecHandler) FromYaml(b []byte) (models.ResourceSpec, error) {
 	}
 
 	optResource := models.ResourceSpec{
-		Version:   yamlResource.Version,
+		Version:   yamlSpecVersion(yamlResource.APIVersion, yamlResource.Version),
 		Name:      yamlResource.Name,
 		Type:      yamlResource.Type,
 		Datastore: This,
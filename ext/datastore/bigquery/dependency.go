@@ -0,0 +1,45 @@
+package bigquery
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+)
+
+var (
+	// tables referred in a view query, e.g. `project.dataset.table`
+	viewQueryTableRegex = regexp.MustCompile("`?([\\w-]+)\\.(\\w+)\\.([\\w-]+)`?")
+)
+
+// ResourceDependencies returns the dataset of tables and views, views also
+// depend on tables selected in their query and row access policies on the
+// table they filter
+func (b *BigQuery) ResourceDependencies(resourceSpec models.ResourceSpec) []string {
+	if policy, ok := resourceSpec.Spec.(BQRowAccessPolicy); ok {
+		return []string{fmt.Sprintf("%s.%s.%s", policy.Project, policy.Dataset, policy.Table)}
+	}
+	bqResource, ok := resourceSpec.Spec.(BQTable)
+	if !ok {
+		return nil
+	}
+	dependencies := []string{fmt.Sprintf("%s.%s", bqResource.Project, bqResource.Dataset)}
+	if resourceSpec.Type != models.ResourceTypeView {
+		return dependencies
+	}
+
+	viewQuery := bqResource.Metadata.ViewQuery
+	if query, ok := resourceSpec.Assets.GetByName(ViewQueryFile); ok && len(strings.TrimSpace(viewQuery)) == 0 {
+		viewQuery = query
+	}
+	seen := map[string]bool{}
+	for _, match := range viewQueryTableRegex.FindAllStringSubmatch(viewQuery, -1) {
+		name := fmt.Sprintf("%s.%s.%s", match[1], match[2], match[3])
+		if !seen[name] && name != resourceSpec.Name {
+			seen[name] = true
+			dependencies = append(dependencies, name)
+		}
+	}
+	return dependencies
+}
@@ -0,0 +1,44 @@
+package bigquery
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceDependencies(t *testing.T) {
+	bq := &BigQuery{}
+	t.Run("should not return dependencies of dataset", func(t *testing.T) {
+		assert.Nil(t, bq.ResourceDependencies(models.ResourceSpec{
+			Name: "proj.datas",
+			Type: models.ResourceTypeDataset,
+			Spec: BQDataset{Project: "proj", Dataset: "datas"},
+		}))
+	})
+	t.Run("should return dataset of table", func(t *testing.T) {
+		assert.Equal(t, []string{"proj.datas"}, bq.ResourceDependencies(models.ResourceSpec{
+			Name: "proj.datas.tab",
+			Type: models.ResourceTypeTable,
+			Spec: BQTable{Project: "proj", Dataset: "datas", Table: "tab"},
+		}))
+	})
+	t.Run("should return dataset and tables selected in query of view", func(t *testing.T) {
+		assert.Equal(t, []string{"proj.datas", "proj.datas.tab", "other-proj.datas.events"}, bq.ResourceDependencies(models.ResourceSpec{
+			Name: "proj.datas.vw",
+			Type: models.ResourceTypeView,
+			Spec: BQTable{Project: "proj", Dataset: "datas", Table: "vw"},
+			Assets: map[string]string{
+				ViewQueryFile: "select t.id from `proj.datas.tab` t join `other-proj.datas.events` e on t.id = e.id " +
+					"join `proj.datas.tab` again using (id)",
+			},
+		}))
+	})
+	t.Run("should return table of row access policy", func(t *testing.T) {
+		assert.Equal(t, []string{"proj.datas.tab"}, bq.ResourceDependencies(models.ResourceSpec{
+			Name: "proj.datas.tab.eu_only",
+			Type: models.ResourceTypeRowAccessPolicy,
+			Spec: BQRowAccessPolicy{Project: "proj", Dataset: "datas", Table: "tab", Policy: "eu_only"},
+		}))
+	})
+}
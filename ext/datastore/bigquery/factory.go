@@ -1,52 +1,182 @@
 package bigquery
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"sync"
-
-	"google.golang.org/api/option"
+	"time"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2/google"
+	bqv2 "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
 )
 
 const (
-	MaxBQClientReuse = 10
+	// pooled clients not used for this long are closed
+	clientIdleTimeout = time.Minute * 30
+
+	// upper limit of clients kept in pool, least recently used one is
+	// closed to make room for a new one
+	maxPooledClients = 32
 )
 
-type defaultBQClientFactory struct {
-	cachedClient bqiface.Client
-	cachedCred   *google.Credentials
-	timesUsed    int
-	mu           sync.Mutex
+// pooledClient holds the clients of a credential, each created the first
+// time it is asked for
+type pooledClient struct {
+	bq       bqiface.Client
+	iam      IAMClient
+	lastUsed time.Time
 }
 
-func (fac *defaultBQClientFactory) New(ctx context.Context, svcAccount string) (bqiface.Client, error) {
-	fac.mu.Lock()
-	defer fac.mu.Unlock()
+// clientPool creates authenticated clients once per project and credential
+// and reuses them across requests, as creating one per request slows down
+// deploys of many resources. Clients outlive the requests creating them so
+// they are created with a background context, cancellation and deadlines
+// of each request are applied by passing its context to client calls
+type clientPool struct {
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+
+	newBQClient  func(ctx context.Context, cred *google.Credentials) (bqiface.Client, error)
+	newIAMClient func(ctx context.Context, cred *google.Credentials) (IAMClient, error)
+	now          func() time.Time
+}
 
-	cred, err := google.CredentialsFromJSON(ctx, []byte(svcAccount), bigquery.Scope)
+func (p *clientPool) bigquery(ctx context.Context, svcAccount string) (bqiface.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, cred, err := p.get(ctx, svcAccount)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read secret")
+		return nil, err
+	}
+	if entry.bq == nil {
+		if entry.bq, err = p.newBQClient(context.Background(), cred); err != nil {
+			return nil, errors.Wrap(err, "failed to create BQ client")
+		}
 	}
+	return entry.bq, nil
+}
+
+func (p *clientPool) iam(ctx context.Context, svcAccount string) (IAMClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	// check if cached client can be reused
-	if fac.cachedCred != nil && fac.cachedClient != nil && fac.timesUsed == MaxBQClientReuse &&
-		bytes.Equal(cred.JSON, fac.cachedCred.JSON) {
-		fac.timesUsed++
-		return fac.cachedClient, nil
+	entry, cred, err := p.get(ctx, svcAccount)
+	if err != nil {
+		return nil, err
 	}
+	if entry.iam == nil {
+		if entry.iam, err = p.newIAMClient(context.Background(), cred); err != nil {
+			return nil, errors.Wrap(err, "failed to create BQ IAM client")
+		}
+	}
+	return entry.iam, nil
+}
 
-	client, err := bigquery.NewClient(ctx, cred.ProjectID, option.WithCredentials(cred))
+// get returns the pool entry of credential, adding it if needed, after
+// evicting clients idle for too long. mu should be held by the caller
+func (p *clientPool) get(ctx context.Context, svcAccount string) (*pooledClient, *google.Credentials, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	cred, err := google.CredentialsFromJSON(context.Background(), []byte(svcAccount), bigquery.Scope)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create BQ client")
+		return nil, nil, errors.Wrap(err, "failed to read secret")
+	}
+	hash := sha256.Sum256(cred.JSON)
+	key := cred.ProjectID + ":" + hex.EncodeToString(hash[:])
+
+	now := p.now()
+	p.evict(now)
+	entry, ok := p.clients[key]
+	if !ok {
+		if len(p.clients) >= maxPooledClients {
+			p.evictLeastRecentlyUsed()
+		}
+		entry = &pooledClient{}
+		p.clients[key] = entry
+	}
+	entry.lastUsed = now
+	return entry, cred, nil
+}
+
+func (p *clientPool) evict(now time.Time) {
+	for key, entry := range p.clients {
+		if now.Sub(entry.lastUsed) > clientIdleTimeout {
+			entry.close()
+			delete(p.clients, key)
+		}
+	}
+}
+
+func (p *clientPool) evictLeastRecentlyUsed() {
+	var oldestKey string
+	var oldest *pooledClient
+	for key, entry := range p.clients {
+		if oldest == nil || entry.lastUsed.Before(oldest.lastUsed) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	if oldest != nil {
+		oldest.close()
+		delete(p.clients, oldestKey)
+	}
+}
+
+// Close closes all the pooled clients
+func (p *clientPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs error
+	for key, entry := range p.clients {
+		if err := entry.close(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		delete(p.clients, key)
+	}
+	return errs
+}
+
+func (c *pooledClient) close() error {
+	// iam client holds no resources of its own to release
+	if c.bq == nil {
+		return nil
+	}
+	return c.bq.Close()
+}
+
+func newClientPool() *clientPool {
+	return &clientPool{
+		clients: map[string]*pooledClient{},
+		newBQClient: func(ctx context.Context, cred *google.Credentials) (bqiface.Client, error) {
+			client, err := bigquery.NewClient(ctx, cred.ProjectID, option.WithCredentials(cred))
+			if err != nil {
+				return nil, err
+			}
+			return bqiface.AdaptClient(client), nil
+		},
+		newIAMClient: func(ctx context.Context, cred *google.Credentials) (IAMClient, error) {
+			svc, err := bqv2.NewService(ctx, option.WithCredentials(cred))
+			if err != nil {
+				return nil, err
+			}
+			return &iamClient{svc: svc}, nil
+		},
+		now: time.Now,
 	}
+}
 
-	fac.cachedCred = cred
-	fac.cachedClient = bqiface.AdaptClient(client)
-	fac.timesUsed = 1
-	return fac.cachedClient, nil
+type defaultBQClientFactory struct {
+	pool *clientPool
+}
+
+func (fac *defaultBQClientFactory) New(ctx context.Context, svcAccount string) (bqiface.Client, error) {
+	return fac.pool.bigquery(ctx, svcAccount)
 }
@@ -0,0 +1,128 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2/google"
+)
+
+type pooledClientStub struct {
+	bqiface.Client
+	project string
+	closed  int
+}
+
+func (c *pooledClientStub) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestClientPool(t *testing.T) {
+	ctx := context.Background()
+	svcAccount := func(project string) string {
+		return fmt.Sprintf(`{"type": "service_account", "project_id": "%s", "client_email": "optimus@%s.iam.gserviceaccount.com", "private_key": "key"}`,
+			project, project)
+	}
+	newPool := func(now *time.Time) (*clientPool, *[]*pooledClientStub) {
+		created := &[]*pooledClientStub{}
+		pool := newClientPool()
+		pool.newBQClient = func(ctx context.Context, cred *google.Credentials) (bqiface.Client, error) {
+			client := &pooledClientStub{project: cred.ProjectID}
+			*created = append(*created, client)
+			return client, nil
+		}
+		pool.now = func() time.Time { return *now }
+		return pool, created
+	}
+
+	t.Run("should reuse client of the same credential", func(t *testing.T) {
+		now := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+		pool, created := newPool(&now)
+
+		first, err := pool.bigquery(ctx, svcAccount("proj-a"))
+		assert.Nil(t, err)
+		second, err := pool.bigquery(ctx, svcAccount("proj-a"))
+		assert.Nil(t, err)
+		other, err := pool.bigquery(ctx, svcAccount("proj-b"))
+		assert.Nil(t, err)
+
+		assert.Same(t, first, second)
+		assert.NotSame(t, first, other)
+		assert.Equal(t, 2, len(*created))
+		assert.Equal(t, "proj-b", other.(*pooledClientStub).project)
+	})
+	t.Run("should close clients idle for too long", func(t *testing.T) {
+		now := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+		pool, created := newPool(&now)
+
+		idle, err := pool.bigquery(ctx, svcAccount("proj-a"))
+		assert.Nil(t, err)
+		now = now.Add(clientIdleTimeout + time.Minute)
+		fresh, err := pool.bigquery(ctx, svcAccount("proj-a"))
+		assert.Nil(t, err)
+
+		assert.NotSame(t, idle, fresh)
+		assert.Equal(t, 1, idle.(*pooledClientStub).closed)
+		assert.Equal(t, 2, len(*created))
+	})
+	t.Run("should close least recently used client when pool is full", func(t *testing.T) {
+		now := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+		pool, created := newPool(&now)
+
+		for i := 0; i < maxPooledClients; i++ {
+			now = now.Add(time.Second)
+			_, err := pool.bigquery(ctx, svcAccount(fmt.Sprintf("proj-%d", i)))
+			assert.Nil(t, err)
+		}
+		_, err := pool.bigquery(ctx, svcAccount("proj-new"))
+		assert.Nil(t, err)
+
+		assert.Equal(t, maxPooledClients, len(pool.clients))
+		assert.Equal(t, 1, (*created)[0].closed)
+		assert.Equal(t, 0, (*created)[1].closed)
+	})
+	t.Run("should close all clients on close", func(t *testing.T) {
+		now := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+		pool, created := newPool(&now)
+
+		_, err := pool.bigquery(ctx, svcAccount("proj-a"))
+		assert.Nil(t, err)
+		assert.Nil(t, pool.Close())
+		assert.Equal(t, 1, (*created)[0].closed)
+		assert.Equal(t, 0, len(pool.clients))
+	})
+	t.Run("should not create clients for cancelled requests", func(t *testing.T) {
+		now := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+		pool, created := newPool(&now)
+
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := pool.bigquery(cancelledCtx, svcAccount("proj-a"))
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 0, len(*created))
+	})
+}
+
+func TestBigQueryWithTimeout(t *testing.T) {
+	t.Run("should bound operations without deadline", func(t *testing.T) {
+		bq := BigQuery{OperationTimeout: time.Minute}
+		ctx, cancel := bq.withTimeout(context.Background())
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.True(t, time.Until(deadline) <= time.Minute)
+	})
+	t.Run("should keep deadline set by caller", func(t *testing.T) {
+		bq := BigQuery{OperationTimeout: time.Minute}
+		callerCtx, callerCancel := context.WithTimeout(context.Background(), time.Hour)
+		defer callerCancel()
+		ctx, cancel := bq.withTimeout(callerCtx)
+		defer cancel()
+		assert.Equal(t, callerCtx, ctx)
+	})
+}
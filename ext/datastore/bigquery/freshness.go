@@ -0,0 +1,43 @@
+package bigquery
+
+import (
+	"context"
+	"time"
+
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// LastModifiedAt reads when a destination table was last modified from its
+// metadata, streaming inserts and changes of schema count as modifications
+func (b *BigQuery) LastModifiedAt(ctx context.Context, request models.ReadFreshnessRequest) (models.ReadFreshnessResponse, error) {
+	table, ok := parseTableURN(request.Destination)
+	if !ok {
+		return models.ReadFreshnessResponse{}, nil
+	}
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return models.ReadFreshnessResponse{}, err
+	}
+
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return models.ReadFreshnessResponse{}, err
+	}
+	lastModifiedAt, err := tableLastModifiedAt(ctx, client, table)
+	if err != nil {
+		return models.ReadFreshnessResponse{}, err
+	}
+	return models.ReadFreshnessResponse{Supported: true, LastModifiedAt: lastModifiedAt}, nil
+}
+
+func tableLastModifiedAt(ctx context.Context, client bqiface.Client, table BQTable) (time.Time, error) {
+	meta, err := client.DatasetInProject(table.Project, table.Dataset).Table(table.Table).Metadata(ctx)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to read table %s", table.FullyQualifiedName())
+	}
+	return meta.LastModifiedTime.UTC(), nil
+}
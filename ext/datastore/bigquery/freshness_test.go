@@ -0,0 +1,47 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastModifiedAt(t *testing.T) {
+	ctx := context.Background()
+	bqTable := BQTable{Project: "proj", Dataset: "datas", Table: "events"}
+
+	t.Run("should read last modified time of table from its metadata", func(t *testing.T) {
+		modifiedAt := time.Date(2021, 6, 1, 5, 30, 0, 0, time.UTC)
+		table := new(BqTableMock)
+		table.On("Metadata", ctx).Return(&bigquery.TableMetadata{LastModifiedTime: modifiedAt}, nil)
+		dataset := new(BqDatasetMock)
+		dataset.On("Table", "events").Return(table)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		lastModifiedAt, err := tableLastModifiedAt(ctx, client, bqTable)
+		assert.Nil(t, err)
+		assert.Equal(t, modifiedAt, lastModifiedAt)
+	})
+	t.Run("should fail if metadata of table can't be read", func(t *testing.T) {
+		table := new(BqTableMock)
+		table.On("Metadata", ctx).Return((*bigquery.TableMetadata)(nil), errors.New("not found"))
+		dataset := new(BqDatasetMock)
+		dataset.On("Table", "events").Return(table)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		_, err := tableLastModifiedAt(ctx, client, bqTable)
+		assert.NotNil(t, err)
+	})
+	t.Run("should not support destinations other than tables", func(t *testing.T) {
+		resp, err := (&BigQuery{}).LastModifiedAt(ctx, models.ReadFreshnessRequest{Destination: "gs://bucket/path"})
+		assert.Nil(t, err)
+		assert.False(t, resp.Supported)
+	})
+}
@@ -0,0 +1,163 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	bqv2 "google.golang.org/api/bigquery/v2"
+)
+
+// IAMClient reads and writes IAM policy of bigquery tables, and reads row
+// access policies of tables which the bigquery client doesn't expose
+type IAMClient interface {
+	GetTablePolicy(ctx context.Context, t BQTable) (*bqv2.Policy, error)
+	SetTablePolicy(ctx context.Context, t BQTable, policy *bqv2.Policy) error
+
+	// GetRowAccessPolicy returns nil if the table has no such policy
+	GetRowAccessPolicy(ctx context.Context, p BQRowAccessPolicy) (*bqv2.RowAccessPolicy, error)
+	// GetRowAccessPolicyIAM returns IAM policy of row access policy, its
+	// grantees are the members of filtered data viewer role
+	GetRowAccessPolicyIAM(ctx context.Context, p BQRowAccessPolicy) (*bqv2.Policy, error)
+}
+
+type IAMClientFactory interface {
+	New(ctx context.Context, svcAccount string) (IAMClient, error)
+}
+
+type defaultIAMClientFactory struct {
+	pool *clientPool
+}
+
+func (fac *defaultIAMClientFactory) New(ctx context.Context, svcAccount string) (IAMClient, error) {
+	return fac.pool.iam(ctx, svcAccount)
+}
+
+type iamClient struct {
+	svc *bqv2.Service
+}
+
+func (c *iamClient) GetTablePolicy(ctx context.Context, t BQTable) (*bqv2.Policy, error) {
+	return c.svc.Tables.GetIamPolicy(tableIAMResource(t), &bqv2.GetIamPolicyRequest{}).Context(ctx).Do()
+}
+
+func (c *iamClient) SetTablePolicy(ctx context.Context, t BQTable, policy *bqv2.Policy) error {
+	_, err := c.svc.Tables.SetIamPolicy(tableIAMResource(t), &bqv2.SetIamPolicyRequest{
+		Policy: policy,
+	}).Context(ctx).Do()
+	return err
+}
+
+func (c *iamClient) GetRowAccessPolicy(ctx context.Context, p BQRowAccessPolicy) (*bqv2.RowAccessPolicy, error) {
+	var found *bqv2.RowAccessPolicy
+	err := c.svc.RowAccessPolicies.List(p.Project, p.Dataset, p.Table).Pages(ctx,
+		func(page *bqv2.ListRowAccessPoliciesResponse) error {
+			for _, policy := range page.RowAccessPolicies {
+				if policy.RowAccessPolicyReference != nil && policy.RowAccessPolicyReference.PolicyId == p.Policy {
+					found = policy
+				}
+			}
+			return nil
+		})
+	return found, err
+}
+
+func (c *iamClient) GetRowAccessPolicyIAM(ctx context.Context, p BQRowAccessPolicy) (*bqv2.Policy, error) {
+	return c.svc.RowAccessPolicies.GetIamPolicy(rowAccessPolicyIAMResource(p), &bqv2.GetIamPolicyRequest{}).Context(ctx).Do()
+}
+
+func tableIAMResource(t BQTable) string {
+	return fmt.Sprintf("projects/%s/datasets/%s/tables/%s", t.Project, t.Dataset, t.Table)
+}
+
+func rowAccessPolicyIAMResource(p BQRowAccessPolicy) string {
+	return fmt.Sprintf("projects/%s/datasets/%s/tables/%s/rowAccessPolicies/%s", p.Project, p.Dataset, p.Table, p.Policy)
+}
+
+// ensureTableIAM reconciles IAM bindings of table with the ones in spec, a
+// table without IAM bindings in spec is left untouched
+func ensureTableIAM(ctx context.Context, client IAMClient, t BQTable) error {
+	if len(t.Metadata.IAM) == 0 {
+		return nil
+	}
+	policy, err := client.GetTablePolicy(ctx, t)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read IAM policy of table %s", t.FullyQualifiedName())
+	}
+	changes, err := planTableIAM(policy, t.Metadata.IAM)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := client.SetTablePolicy(ctx, t, policy); err != nil {
+		return errors.Wrapf(err, "failed to update IAM policy of table %s", t.FullyQualifiedName())
+	}
+	return nil
+}
+
+// planTableIAM updates the policy in place to match the bindings and returns the
+// member changes it made. Bindings are authoritative only for the roles they
+// declare, a role declared without members revokes all of its members. Roles not
+// in bindings and conditional role bindings are never touched
+func planTableIAM(policy *bqv2.Policy, bindings []BQIAMBinding) ([]string, error) {
+	declared := map[string]bool{}
+	for _, binding := range bindings {
+		if binding.Role == "" {
+			return nil, errors.New("role of IAM binding can't be empty")
+		}
+		if declared[binding.Role] {
+			return nil, errors.Errorf("role %s has more than one IAM binding", binding.Role)
+		}
+		declared[binding.Role] = true
+	}
+
+	var changes []string
+	for _, binding := range bindings {
+		current := map[string]bool{}
+		var kept []*bqv2.Binding
+		for _, b := range policy.Bindings {
+			if b.Role == binding.Role && b.Condition == nil {
+				for _, member := range b.Members {
+					current[member] = true
+				}
+				continue
+			}
+			kept = append(kept, b)
+		}
+
+		desired := map[string]bool{}
+		for _, member := range binding.Members {
+			if !current[member] && !desired[member] {
+				changes = append(changes, fmt.Sprintf("iam %s: +%s", binding.Role, member))
+			}
+			desired[member] = true
+		}
+		var revoked []string
+		for member := range current {
+			if !desired[member] {
+				revoked = append(revoked, member)
+			}
+		}
+		sort.Strings(revoked)
+		for _, member := range revoked {
+			changes = append(changes, fmt.Sprintf("iam %s: -%s", binding.Role, member))
+		}
+
+		if len(desired) > 0 {
+			members := make([]string, 0, len(desired))
+			for member := range desired {
+				members = append(members, member)
+			}
+			sort.Strings(members)
+			kept = append(kept, &bqv2.Binding{
+				Role:    binding.Role,
+				Members: members,
+			})
+		}
+		policy.Bindings = kept
+	}
+	return changes, nil
+}
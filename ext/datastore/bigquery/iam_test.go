@@ -0,0 +1,122 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	bqv2 "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+func TestTableIAM(t *testing.T) {
+	testingContext := context.Background()
+	viewer := "roles/bigquery.dataViewer"
+	editor := "roles/bigquery.dataEditor"
+	bQResource := BQTable{
+		Project: "project",
+		Dataset: "dataset",
+		Table:   "table",
+		Metadata: BQTableMetadata{
+			IAM: []BQIAMBinding{
+				{Role: viewer, Members: []string{"group:analysts@example.com", "user:jane@example.com"}},
+			},
+		},
+	}
+
+	t.Run("planTableIAM", func(t *testing.T) {
+		t.Run("should grant and revoke members of declared roles only", func(t *testing.T) {
+			condition := &bqv2.Expr{Expression: "request.time < timestamp('2021-12-31T00:00:00Z')"}
+			policy := &bqv2.Policy{
+				Etag: "etag",
+				Bindings: []*bqv2.Binding{
+					{Role: viewer, Members: []string{"user:jane@example.com", "user:john@example.com"}},
+					{Role: viewer, Members: []string{"user:temp@example.com"}, Condition: condition},
+					{Role: editor, Members: []string{"serviceAccount:etl@example.com"}},
+				},
+			}
+			changes, err := planTableIAM(policy, bQResource.Metadata.IAM)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				"iam roles/bigquery.dataViewer: +group:analysts@example.com",
+				"iam roles/bigquery.dataViewer: -user:john@example.com",
+			}, changes)
+			assert.Equal(t, &bqv2.Policy{
+				Etag: "etag",
+				Bindings: []*bqv2.Binding{
+					{Role: viewer, Members: []string{"user:temp@example.com"}, Condition: condition},
+					{Role: editor, Members: []string{"serviceAccount:etl@example.com"}},
+					{Role: viewer, Members: []string{"group:analysts@example.com", "user:jane@example.com"}},
+				},
+			}, policy)
+		})
+		t.Run("should revoke all members of a role declared without members", func(t *testing.T) {
+			policy := &bqv2.Policy{
+				Bindings: []*bqv2.Binding{
+					{Role: editor, Members: []string{"serviceAccount:etl@example.com"}},
+				},
+			}
+			changes, err := planTableIAM(policy, []BQIAMBinding{{Role: editor}})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"iam roles/bigquery.dataEditor: -serviceAccount:etl@example.com"}, changes)
+			assert.Empty(t, policy.Bindings)
+		})
+		t.Run("should fail if a role is declared more than once", func(t *testing.T) {
+			_, err := planTableIAM(&bqv2.Policy{}, []BQIAMBinding{{Role: viewer}, {Role: viewer}})
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("ensureTableIAM", func(t *testing.T) {
+		t.Run("should update policy if bindings differ", func(t *testing.T) {
+			iamClient := new(IAMClientMock)
+			defer iamClient.AssertExpectations(t)
+
+			iamClient.On("GetTablePolicy", testingContext, bQResource).Return(&bqv2.Policy{Etag: "etag"}, nil)
+			iamClient.On("SetTablePolicy", testingContext, bQResource, &bqv2.Policy{
+				Etag: "etag",
+				Bindings: []*bqv2.Binding{
+					{Role: viewer, Members: []string{"group:analysts@example.com", "user:jane@example.com"}},
+				},
+			}).Return(nil)
+
+			err := ensureTableIAM(testingContext, iamClient, bQResource)
+			assert.Nil(t, err)
+		})
+		t.Run("should not update policy if bindings are in sync", func(t *testing.T) {
+			iamClient := new(IAMClientMock)
+			defer iamClient.AssertExpectations(t)
+
+			iamClient.On("GetTablePolicy", testingContext, bQResource).Return(&bqv2.Policy{
+				Bindings: []*bqv2.Binding{
+					{Role: viewer, Members: []string{"user:jane@example.com", "group:analysts@example.com"}},
+				},
+			}, nil)
+
+			err := ensureTableIAM(testingContext, iamClient, bQResource)
+			assert.Nil(t, err)
+		})
+	})
+	t.Run("planTable", func(t *testing.T) {
+		t.Run("should plan all bindings of a table yet to be created", func(t *testing.T) {
+			bQClient := new(BqClientMock)
+			defer bQClient.AssertExpectations(t)
+			bQDataset := new(BqDatasetMock)
+			defer bQDataset.AssertExpectations(t)
+			bQTable := new(BqTableMock)
+			defer bQTable.AssertExpectations(t)
+
+			bQClient.On("DatasetInProject", bQResource.Project, bQResource.Dataset).Return(bQDataset)
+			bQDataset.On("Table", bQResource.Table).Return(bQTable)
+			bQTable.On("Metadata", testingContext).Return((*bigquery.TableMetadata)(nil), &googleapi.Error{Code: 404})
+
+			changes, err := planTable(testingContext, bQClient, nil, bQResource)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				"create table project:dataset.table",
+				"iam roles/bigquery.dataViewer: +group:analysts@example.com",
+				"iam roles/bigquery.dataViewer: +user:jane@example.com",
+			}, changes)
+		})
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
 	"github.com/odpf/optimus/models"
 	"github.com/stretchr/testify/mock"
+	bqv2 "google.golang.org/api/bigquery/v2"
 )
 
 type BqClientMock struct {
@@ -43,7 +44,7 @@ func (cli *BqClientMock) DatasetsInProject(context.Context, string) bqiface.Data
 }
 
 func (cli *BqClientMock) Query(q string) bqiface.Query {
-	panic("not implemented")
+	return cli.Called(q).Get(0).(bqiface.Query)
 }
 
 func (cli *BqClientMock) JobFromID(context.Context, string) (bqiface.Job, error) {
@@ -97,8 +98,8 @@ func (ds *BqDatasetMock) Table(name string) bqiface.Table {
 	return ds.Called(name).Get(0).(bqiface.Table)
 }
 
-func (ds *BqDatasetMock) Tables(context.Context) bqiface.TableIterator {
-	panic("not implemented")
+func (ds *BqDatasetMock) Tables(ctx context.Context) bqiface.TableIterator {
+	return ds.Called(ctx).Get(0).(bqiface.TableIterator)
 }
 
 type BqTableMock struct {
@@ -149,7 +150,7 @@ func (table *BqTableMock) Read(ctx context.Context) bqiface.RowIterator {
 }
 
 func (table *BqTableMock) TableID() string {
-	panic("not implemented")
+	return table.Called().String(0)
 }
 
 func (table *BqTableMock) Update(ctx context.Context, meta bigquery.TableMetadataToUpdate, etag string) (*bigquery.TableMetadata, error) {
@@ -161,6 +162,68 @@ func (table *BqTableMock) Uploader() bqiface.Uploader {
 	panic("not implemented")
 }
 
+type BqTableIteratorMock struct {
+	mock.Mock
+	bqiface.TableIterator
+}
+
+func (it *BqTableIteratorMock) Next() (bqiface.Table, error) {
+	args := it.Called()
+	return args.Get(0).(bqiface.Table), args.Error(1)
+}
+
+type BqCopierMock struct {
+	mock.Mock
+	bqiface.Copier
+}
+
+func (copier *BqCopierMock) SetCopyConfig(config bqiface.CopyConfig) {
+	copier.Called(config)
+}
+
+func (copier *BqCopierMock) Run(ctx context.Context) (bqiface.Job, error) {
+	args := copier.Called(ctx)
+	return args.Get(0).(bqiface.Job), args.Error(1)
+}
+
+type BqQueryMock struct {
+	mock.Mock
+	bqiface.Query
+}
+
+func (query *BqQueryMock) SetQueryConfig(config bqiface.QueryConfig) {
+	query.Called(config)
+}
+
+func (query *BqQueryMock) Run(ctx context.Context) (bqiface.Job, error) {
+	args := query.Called(ctx)
+	return args.Get(0).(bqiface.Job), args.Error(1)
+}
+
+func (query *BqQueryMock) Read(ctx context.Context) (bqiface.RowIterator, error) {
+	args := query.Called(ctx)
+	return args.Get(0).(bqiface.RowIterator), args.Error(1)
+}
+
+type BqRowIteratorMock struct {
+	mock.Mock
+	bqiface.RowIterator
+}
+
+func (it *BqRowIteratorMock) Next(dst interface{}) error {
+	return it.Called(dst).Error(0)
+}
+
+type BqJobMock struct {
+	mock.Mock
+	bqiface.Job
+}
+
+func (job *BqJobMock) Wait(ctx context.Context) (*bigquery.JobStatus, error) {
+	args := job.Called(ctx)
+	return args.Get(0).(*bigquery.JobStatus), args.Error(1)
+}
+
 type BQClientFactoryMock struct {
 	mock.Mock
 }
@@ -170,6 +233,38 @@ func (fac *BQClientFactoryMock) New(ctx context.Context, svcAcc string) (bqiface
 	return args.Get(0).(bqiface.Client), args.Error(1)
 }
 
+type IAMClientMock struct {
+	mock.Mock
+}
+
+func (cli *IAMClientMock) GetTablePolicy(ctx context.Context, t BQTable) (*bqv2.Policy, error) {
+	args := cli.Called(ctx, t)
+	return args.Get(0).(*bqv2.Policy), args.Error(1)
+}
+
+func (cli *IAMClientMock) SetTablePolicy(ctx context.Context, t BQTable, policy *bqv2.Policy) error {
+	return cli.Called(ctx, t, policy).Error(0)
+}
+
+func (cli *IAMClientMock) GetRowAccessPolicy(ctx context.Context, p BQRowAccessPolicy) (*bqv2.RowAccessPolicy, error) {
+	args := cli.Called(ctx, p)
+	return args.Get(0).(*bqv2.RowAccessPolicy), args.Error(1)
+}
+
+func (cli *IAMClientMock) GetRowAccessPolicyIAM(ctx context.Context, p BQRowAccessPolicy) (*bqv2.Policy, error) {
+	args := cli.Called(ctx, p)
+	return args.Get(0).(*bqv2.Policy), args.Error(1)
+}
+
+type IAMClientFactoryMock struct {
+	mock.Mock
+}
+
+func (fac *IAMClientFactoryMock) New(ctx context.Context, svcAcc string) (IAMClient, error) {
+	args := fac.Called(ctx, svcAcc)
+	return args.Get(0).(IAMClient), args.Error(1)
+}
+
 type BigQueryMock struct {
 	mock.Mock
 }
@@ -0,0 +1,102 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	bqapi "cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+)
+
+var (
+	// layouts of ids of partitions by their type, ids of partitions
+	// sort the same as the periods they hold
+	partitionIDLayouts = map[bqapi.TimePartitioningType]string{
+		bqapi.DayPartitioningType:  "20060102",
+		bqapi.HourPartitioningType: "2006010215",
+	}
+)
+
+// DropPartitions deletes partitions of a destination table partitioned by
+// time whose period ended by the time, partitions are looked up in
+// INFORMATION_SCHEMA.PARTITIONS of the dataset and deleted one by one with
+// their partition decorator, e.g. table$20210601
+func (b *BigQuery) DropPartitions(ctx context.Context, request models.DropPartitionsRequest) (models.DropPartitionsResponse, error) {
+	table, ok := parseTableURN(request.Destination)
+	if !ok {
+		return models.DropPartitionsResponse{}, nil
+	}
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return models.DropPartitionsResponse{}, err
+	}
+
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return models.DropPartitionsResponse{}, err
+	}
+	dropped, skipped, err := dropTablePartitions(ctx, client, table, request.Before)
+	return models.DropPartitionsResponse{Supported: true, Dropped: dropped, Skipped: skipped}, err
+}
+
+// dropTablePartitions returns ids of the partitions dropped, including the
+// ones dropped before failing to drop a partition. Tables partitioned by
+// month or year are skipped, telling why, as their partitions hold periods
+// longer than retention of most jobs
+func dropTablePartitions(ctx context.Context, client bqiface.Client, table BQTable, before time.Time) ([]string, string, error) {
+	dataset := client.DatasetInProject(table.Project, table.Dataset)
+	meta, err := dataset.Table(table.Table).Metadata(ctx)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to read table %s", table.FullyQualifiedName())
+	}
+	if meta.TimePartitioning == nil {
+		return nil, "", errors.Errorf("table %s is not partitioned by time", table.FullyQualifiedName())
+	}
+	partitionType := meta.TimePartitioning.Type
+	if partitionType == "" {
+		partitionType = bqapi.DayPartitioningType
+	}
+	layout, ok := partitionIDLayouts[partitionType]
+	if !ok {
+		return nil, fmt.Sprintf("table %s is partitioned by %s, only partitions by %s and %s are dropped",
+			table.FullyQualifiedName(), partitionType, bqapi.DayPartitioningType, bqapi.HourPartitioningType), nil
+	}
+
+	// partitions before the one holding the time ended by then
+	sql := fmt.Sprintf("SELECT partition_id FROM `%s`.`%s`.INFORMATION_SCHEMA.PARTITIONS "+
+		"WHERE table_name = '%s' AND partition_id < '%s' "+
+		"AND partition_id NOT IN ('__NULL__', '__UNPARTITIONED__') ORDER BY partition_id",
+		table.Project, table.Dataset, table.Table, before.UTC().Format(layout))
+	it, err := client.Query(sql).Read(ctx)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to list partitions of table %s", table.FullyQualifiedName())
+	}
+	var partitionIDs []string
+	for {
+		var row struct {
+			PartitionID string `bigquery:"partition_id"`
+		}
+		if err := it.Next(&row); err != nil {
+			if err == iterator.Done {
+				break
+			}
+			return nil, "", errors.Wrapf(err, "failed to list partitions of table %s", table.FullyQualifiedName())
+		}
+		partitionIDs = append(partitionIDs, row.PartitionID)
+	}
+
+	var dropped []string
+	for _, partitionID := range partitionIDs {
+		if err := dataset.Table(table.Table + "$" + partitionID).Delete(ctx); err != nil {
+			return dropped, "", errors.Wrapf(err, "failed to drop partition %s of table %s", partitionID, table.FullyQualifiedName())
+		}
+		dropped = append(dropped, partitionID)
+	}
+	return dropped, "", nil
+}
@@ -0,0 +1,129 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/api/iterator"
+)
+
+func TestDropPartitions(t *testing.T) {
+	ctx := context.Background()
+	before := time.Date(2021, 6, 1, 5, 0, 0, 0, time.UTC)
+	bqTable := BQTable{Project: "proj", Dataset: "datas", Table: "events"}
+	sql := "SELECT partition_id FROM `proj`.`datas`.INFORMATION_SCHEMA.PARTITIONS " +
+		"WHERE table_name = 'events' AND partition_id < '20210601' " +
+		"AND partition_id NOT IN ('__NULL__', '__UNPARTITIONED__') ORDER BY partition_id"
+
+	partitionRows := func(ids ...string) *BqRowIteratorMock {
+		it := new(BqRowIteratorMock)
+		for _, id := range ids {
+			partitionID := id
+			it.On("Next", mock.Anything).Run(func(args mock.Arguments) {
+				row := args.Get(0).(*struct {
+					PartitionID string `bigquery:"partition_id"`
+				})
+				row.PartitionID = partitionID
+			}).Return(nil).Once()
+		}
+		it.On("Next", mock.Anything).Return(iterator.Done).Once()
+		return it
+	}
+
+	t.Run("should drop partitions of days before the time", func(t *testing.T) {
+		table := new(BqTableMock)
+		table.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+			TimePartitioning: &bigquery.TimePartitioning{Type: bigquery.DayPartitioningType},
+		}, nil)
+		firstPartition := new(BqTableMock)
+		defer firstPartition.AssertExpectations(t)
+		firstPartition.On("Delete", ctx).Return(nil)
+		secondPartition := new(BqTableMock)
+		defer secondPartition.AssertExpectations(t)
+		secondPartition.On("Delete", ctx).Return(nil)
+
+		dataset := new(BqDatasetMock)
+		dataset.On("Table", "events").Return(table)
+		dataset.On("Table", "events$20210530").Return(firstPartition)
+		dataset.On("Table", "events$20210531").Return(secondPartition)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		query := new(BqQueryMock)
+		defer query.AssertExpectations(t)
+		query.On("Read", ctx).Return(partitionRows("20210530", "20210531"), nil)
+		client.On("Query", sql).Return(query)
+
+		dropped, skipped, err := dropTablePartitions(ctx, client, bqTable, before)
+		assert.Nil(t, err)
+		assert.Empty(t, skipped)
+		assert.Equal(t, []string{"20210530", "20210531"}, dropped)
+	})
+	t.Run("should return partitions dropped before failing to drop one", func(t *testing.T) {
+		table := new(BqTableMock)
+		table.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+			TimePartitioning: &bigquery.TimePartitioning{},
+		}, nil)
+		firstPartition := new(BqTableMock)
+		firstPartition.On("Delete", ctx).Return(nil)
+		secondPartition := new(BqTableMock)
+		secondPartition.On("Delete", ctx).Return(errors.New("access denied"))
+
+		dataset := new(BqDatasetMock)
+		dataset.On("Table", "events").Return(table)
+		dataset.On("Table", "events$20210530").Return(firstPartition)
+		dataset.On("Table", "events$20210531").Return(secondPartition)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		query := new(BqQueryMock)
+		query.On("Read", ctx).Return(partitionRows("20210530", "20210531"), nil)
+		client.On("Query", sql).Return(query)
+
+		dropped, _, err := dropTablePartitions(ctx, client, bqTable, before)
+		assert.NotNil(t, err)
+		assert.Equal(t, []string{"20210530"}, dropped)
+	})
+	t.Run("should fail for tables not partitioned by time", func(t *testing.T) {
+		table := new(BqTableMock)
+		table.On("Metadata", ctx).Return(&bigquery.TableMetadata{}, nil)
+		dataset := new(BqDatasetMock)
+		dataset.On("Table", "events").Return(table)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		dropped, _, err := dropTablePartitions(ctx, client, bqTable, before)
+		assert.NotNil(t, err)
+		assert.Empty(t, dropped)
+	})
+	t.Run("should skip tables partitioned by month or year", func(t *testing.T) {
+		for _, partitionType := range []bigquery.TimePartitioningType{"MONTH", "YEAR"} {
+			table := new(BqTableMock)
+			table.On("Metadata", ctx).Return(&bigquery.TableMetadata{
+				TimePartitioning: &bigquery.TimePartitioning{Type: partitionType},
+			}, nil)
+			dataset := new(BqDatasetMock)
+			dataset.On("Table", "events").Return(table)
+			client := new(BqClientMock)
+			client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+			dropped, skipped, err := dropTablePartitions(ctx, client, bqTable, before)
+			assert.Nil(t, err)
+			assert.Empty(t, dropped)
+			assert.Equal(t, "table proj:datas.events is partitioned by "+string(partitionType)+
+				", only partitions by DAY and HOUR are dropped", skipped)
+			client.AssertNotCalled(t, "Query", mock.Anything)
+		}
+	})
+	t.Run("should not support destinations other than tables", func(t *testing.T) {
+		resp, err := (&BigQuery{}).DropPartitions(ctx, models.DropPartitionsRequest{Destination: "gs://bucket/path"})
+		assert.Nil(t, err)
+		assert.False(t, resp.Supported)
+	})
+}
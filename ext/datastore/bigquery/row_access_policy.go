@@ -0,0 +1,171 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// grantees of a row access policy are the members of this role in
+	// IAM policy of the row access policy
+	rowAccessPolicyGranteeRole = "roles/bigquery.filteredDataViewer"
+)
+
+// createRowAccessPolicy creates the policy on its table with DDL, or replaces
+// it if upsert is set and filter or grantees of policy differ from spec
+func createRowAccessPolicy(ctx context.Context, spec models.ResourceSpec, client bqiface.Client, iamClient IAMClient, upsert bool) error {
+	policy, ok := spec.Spec.(BQRowAccessPolicy)
+	if !ok {
+		return errors.New("failed to read row access policy spec for bigquery")
+	}
+	if strings.TrimSpace(policy.Metadata.Filter) == "" {
+		return errors.Errorf("filter of row access policy %s can't be empty", policy.FullyQualifiedName())
+	}
+
+	exists, changes, err := diffRowAccessPolicy(ctx, iamClient, policy)
+	if err != nil {
+		return err
+	}
+	if exists && (!upsert || len(changes) == 0) {
+		return nil
+	}
+	if err := runDDL(ctx, client, rowAccessPolicyDDL(policy)); err != nil {
+		return errors.Wrapf(err, "failed to create row access policy %s", policy.FullyQualifiedName())
+	}
+	return nil
+}
+
+func getRowAccessPolicy(ctx context.Context, spec models.ResourceSpec, iamClient IAMClient) (models.ResourceSpec, error) {
+	policy, ok := spec.Spec.(BQRowAccessPolicy)
+	if !ok {
+		return models.ResourceSpec{}, errors.New("failed to read row access policy spec for bigquery")
+	}
+	current, err := iamClient.GetRowAccessPolicy(ctx, policy)
+	if err != nil {
+		return models.ResourceSpec{}, err
+	}
+	if current == nil {
+		return models.ResourceSpec{}, errors.Errorf("row access policy %s not found", policy.FullyQualifiedName())
+	}
+	grantees, err := rowAccessPolicyGrantees(ctx, iamClient, policy)
+	if err != nil {
+		return models.ResourceSpec{}, err
+	}
+	policy.Metadata = BQRowAccessPolicyMetadata{
+		Filter:   current.FilterPredicate,
+		Grantees: grantees,
+	}
+	spec.Spec = policy
+	return spec, nil
+}
+
+func deleteRowAccessPolicy(ctx context.Context, spec models.ResourceSpec, client bqiface.Client) error {
+	policy, ok := spec.Spec.(BQRowAccessPolicy)
+	if !ok {
+		return errors.New("failed to read row access policy spec for bigquery")
+	}
+	return runDDL(ctx, client, fmt.Sprintf("DROP ROW ACCESS POLICY IF EXISTS `%s` ON `%s.%s.%s`",
+		policy.Policy, policy.Project, policy.Dataset, policy.Table))
+}
+
+// planRowAccessPolicy describes whether the policy will be created or
+// replaced along with changes to its filter and grantees
+func planRowAccessPolicy(ctx context.Context, iamClient IAMClient, policy BQRowAccessPolicy) ([]string, error) {
+	exists, changes, err := diffRowAccessPolicy(ctx, iamClient, policy)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return []string{"create row access policy " + policy.FullyQualifiedName()}, nil
+	}
+	return append([]string{"update row access policy " + policy.FullyQualifiedName()}, changes...), nil
+}
+
+// diffRowAccessPolicy compares filter and grantees of existing policy with
+// the spec, grantees being added are prefixed with + and removed with -
+func diffRowAccessPolicy(ctx context.Context, iamClient IAMClient, policy BQRowAccessPolicy) (bool, []string, error) {
+	current, err := iamClient.GetRowAccessPolicy(ctx, policy)
+	if err != nil {
+		return false, nil, errors.Wrapf(err, "failed to read row access policy %s", policy.FullyQualifiedName())
+	}
+	if current == nil {
+		return false, nil, nil
+	}
+
+	var changes []string
+	if strings.TrimSpace(current.FilterPredicate) != strings.TrimSpace(policy.Metadata.Filter) {
+		changes = append(changes, fmt.Sprintf("filter: %q -> %q", current.FilterPredicate, policy.Metadata.Filter))
+	}
+	grantees, err := rowAccessPolicyGrantees(ctx, iamClient, policy)
+	if err != nil {
+		return false, nil, err
+	}
+	currentGrantees := map[string]bool{}
+	for _, grantee := range grantees {
+		currentGrantees[grantee] = true
+	}
+	for _, grantee := range policy.Metadata.Grantees {
+		if !currentGrantees[grantee] {
+			changes = append(changes, fmt.Sprintf("grantee +%s", grantee))
+		}
+		delete(currentGrantees, grantee)
+	}
+	var revoked []string
+	for grantee := range currentGrantees {
+		revoked = append(revoked, grantee)
+	}
+	sort.Strings(revoked)
+	for _, grantee := range revoked {
+		changes = append(changes, fmt.Sprintf("grantee -%s", grantee))
+	}
+	return true, changes, nil
+}
+
+func rowAccessPolicyGrantees(ctx context.Context, iamClient IAMClient, policy BQRowAccessPolicy) ([]string, error) {
+	iamPolicy, err := iamClient.GetRowAccessPolicyIAM(ctx, policy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read grantees of row access policy %s", policy.FullyQualifiedName())
+	}
+	var grantees []string
+	for _, binding := range iamPolicy.Bindings {
+		if binding.Role == rowAccessPolicyGranteeRole && binding.Condition == nil {
+			grantees = append(grantees, binding.Members...)
+		}
+	}
+	sort.Strings(grantees)
+	return grantees, nil
+}
+
+// rowAccessPolicyDDL replaces the policy as a whole, bigquery doesn't alter
+// filter or grantees of an existing policy
+func rowAccessPolicyDDL(policy BQRowAccessPolicy) string {
+	ddl := fmt.Sprintf("CREATE OR REPLACE ROW ACCESS POLICY `%s` ON `%s.%s.%s`",
+		policy.Policy, policy.Project, policy.Dataset, policy.Table)
+	if len(policy.Metadata.Grantees) > 0 {
+		grantees := make([]string, 0, len(policy.Metadata.Grantees))
+		for _, grantee := range policy.Metadata.Grantees {
+			grantees = append(grantees, strconv.Quote(grantee))
+		}
+		ddl += fmt.Sprintf(" GRANT TO (%s)", strings.Join(grantees, ", "))
+	}
+	return ddl + fmt.Sprintf(" FILTER USING (%s)", policy.Metadata.Filter)
+}
+
+func runDDL(ctx context.Context, client bqiface.Client, ddl string) error {
+	job, err := client.Query(ddl).Run(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return status.Err()
+}
@@ -0,0 +1,192 @@
+package bigquery
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kushsharma/structs"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1 "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// row access policies are named after the table they filter, e.g.
+	// project.dataset.table.policy
+	rowAccessPolicyNameParseRegex = regexp.MustCompile(`^([\w-]+)\.(\w+)\.([\w-]+)\.(\w+)$`)
+)
+
+// RowAccessPolicyResourceSpec is how row access policy will be represented in yaml
+type RowAccessPolicyResourceSpec struct {
+	APIVersion int `yaml:"apiVersion,omitempty"`
+	// Version is what apiVersion was written as before it
+	Version int `yaml:"version,omitempty"`
+	Name    string
+	Type    models.ResourceType
+	Spec    BQRowAccessPolicyMetadata
+	Labels  map[string]string
+}
+
+// BQRowAccessPolicy is a specification for a BigQuery row access policy
+// of a table, the policy may or may not exist
+type BQRowAccessPolicy struct {
+	Project string
+	Dataset string
+	Table   string
+	Policy  string
+
+	Metadata BQRowAccessPolicyMetadata
+}
+
+// FullyQualifiedName returns the "full name" for a row access policy
+func (p BQRowAccessPolicy) FullyQualifiedName() string {
+	return fmt.Sprintf("%s:%s.%s.%s", p.Project, p.Dataset, p.Table, p.Policy)
+}
+
+// BQRowAccessPolicyMetadata holds configuration for a row access policy,
+// grantees can read only the rows of table matching the filter
+type BQRowAccessPolicyMetadata struct {
+	// filter expression of rows, e.g. region = "EU"
+	Filter string `yaml:"filter" structs:"filter"`
+
+	// members granted access to filtered rows, e.g. group:analysts@example.com
+	Grantees []string `yaml:"grantees,omitempty" structs:"grantees,omitempty"`
+}
+
+// rowAccessPolicySpecHandler helps serializing/deserializing datastore resource
+// for row access policy
+type rowAccessPolicySpecHandler struct {
+}
+
+func (s rowAccessPolicySpecHandler) ToYaml(optResource models.ResourceSpec) ([]byte, error) {
+	if optResource.Spec == nil {
+		// usually happens when resource is requested to be created for the first time via optimus cli
+		optResource.Spec = BQRowAccessPolicy{}
+	}
+	bqResource, ok := optResource.Spec.(BQRowAccessPolicy)
+	if !ok {
+		return nil, errors.New("failed to convert resource, malformed spec")
+	}
+
+	yamlResource := RowAccessPolicyResourceSpec{
+		APIVersion: optResource.Version,
+		Name:       optResource.Name,
+		Type:       optResource.Type,
+		Spec:       bqResource.Metadata,
+		Labels:     optResource.Labels,
+	}
+	return yaml.Marshal(yamlResource)
+}
+
+func (s rowAccessPolicySpecHandler) FromYaml(b []byte) (models.ResourceSpec, error) {
+	var yamlResource RowAccessPolicyResourceSpec
+	if err := yaml.Unmarshal(b, &yamlResource); err != nil {
+		return models.ResourceSpec{}, err
+	}
+
+	parsedNames := rowAccessPolicyNameParseRegex.FindStringSubmatch(yamlResource.Name)
+	if len(parsedNames) < 5 {
+		return models.ResourceSpec{}, fmt.Errorf("invalid resource name %s", yamlResource.Name)
+	}
+
+	optResource := models.ResourceSpec{
+		Version:   yamlSpecVersion(yamlResource.APIVersion, yamlResource.Version),
+		Name:      yamlResource.Name,
+		Type:      yamlResource.Type,
+		Datastore: This,
+		Spec: BQRowAccessPolicy{
+			Project:  parsedNames[1],
+			Dataset:  parsedNames[2],
+			Table:    parsedNames[3],
+			Policy:   parsedNames[4],
+			Metadata: yamlResource.Spec,
+		},
+		Labels: yamlResource.Labels,
+	}
+	return optResource, nil
+}
+
+func (s rowAccessPolicySpecHandler) ToProtobuf(optResource models.ResourceSpec) ([]byte, error) {
+	bqResource, ok := optResource.Spec.(BQRowAccessPolicy)
+	if !ok {
+		return nil, errors.New("failed to convert resource, malformed spec")
+	}
+	bqResourceProtoSpec, err := structpb.NewStruct(structs.Map(bqResource.Metadata))
+	if err != nil {
+		return nil, err
+	}
+	resSpec := &v1.ResourceSpecification{
+		Version: int32(optResource.Version),
+		Name:    optResource.Name,
+		Type:    optResource.Type.String(),
+		Spec:    bqResourceProtoSpec,
+		Assets:  optResource.Assets,
+		Labels:  optResource.Labels,
+	}
+	return proto.Marshal(resSpec)
+}
+
+func (s rowAccessPolicySpecHandler) FromProtobuf(b []byte) (models.ResourceSpec, error) {
+	baseSpec := &v1.ResourceSpecification{}
+	if err := proto.Unmarshal(b, baseSpec); err != nil {
+		return models.ResourceSpec{}, err
+	}
+
+	parsedNames := rowAccessPolicyNameParseRegex.FindStringSubmatch(baseSpec.Name)
+	if len(parsedNames) < 5 {
+		return models.ResourceSpec{}, fmt.Errorf("invalid resource name %s", baseSpec.Name)
+	}
+
+	bqMeta := BQRowAccessPolicyMetadata{}
+	if baseSpec.Spec != nil {
+		if protoSpecField, ok := baseSpec.Spec.Fields["filter"]; ok {
+			bqMeta.Filter = protoSpecField.GetStringValue()
+		}
+
+		if protoSpecField, ok := baseSpec.Spec.Fields["grantees"]; ok {
+			for _, granteeVal := range protoSpecField.GetListValue().GetValues() {
+				bqMeta.Grantees = append(bqMeta.Grantees, granteeVal.GetStringValue())
+			}
+		}
+	}
+
+	optResource := models.ResourceSpec{
+		Version:   int(baseSpec.Version),
+		Name:      baseSpec.Name,
+		Type:      models.ResourceType(baseSpec.Type),
+		Assets:    baseSpec.Assets,
+		Datastore: This,
+		Spec: BQRowAccessPolicy{
+			Project:  parsedNames[1],
+			Dataset:  parsedNames[2],
+			Table:    parsedNames[3],
+			Policy:   parsedNames[4],
+			Metadata: bqMeta,
+		},
+		Labels: baseSpec.Labels,
+	}
+	return optResource, nil
+}
+
+type rowAccessPolicySpec struct{}
+
+func (s rowAccessPolicySpec) Adapter() models.DatastoreSpecAdapter {
+	return &rowAccessPolicySpecHandler{}
+}
+
+func (s rowAccessPolicySpec) Validator() models.DatastoreSpecValidator {
+	return func(spec models.ResourceSpec) error {
+		if !rowAccessPolicyNameParseRegex.MatchString(spec.Name) {
+			return fmt.Errorf("for example 'project_name.dataset_name.table_name.policy_name'")
+		}
+		return nil
+	}
+}
+
+func (s rowAccessPolicySpec) DefaultAssets() map[string]string {
+	return map[string]string{}
+}
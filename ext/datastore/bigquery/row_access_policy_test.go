@@ -0,0 +1,203 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	bqv2 "google.golang.org/api/bigquery/v2"
+)
+
+func TestRowAccessPolicy(t *testing.T) {
+	testingContext := context.Background()
+	policy := BQRowAccessPolicy{
+		Project: "project",
+		Dataset: "dataset",
+		Table:   "table",
+		Policy:  "eu_only",
+		Metadata: BQRowAccessPolicyMetadata{
+			Filter:   `region = "EU"`,
+			Grantees: []string{"group:analysts@example.com", "user:jane@example.com"},
+		},
+	}
+	resourceSpec := models.ResourceSpec{
+		Name: "project.dataset.table.eu_only",
+		Type: models.ResourceTypeRowAccessPolicy,
+		Spec: policy,
+	}
+	ddl := "CREATE OR REPLACE ROW ACCESS POLICY `eu_only` ON `project.dataset.table` " +
+		`GRANT TO ("group:analysts@example.com", "user:jane@example.com") FILTER USING (region = "EU")`
+
+	t.Run("createRowAccessPolicy", func(t *testing.T) {
+		t.Run("should create policy missing on table", func(t *testing.T) {
+			iamClient := new(IAMClientMock)
+			defer iamClient.AssertExpectations(t)
+			iamClient.On("GetRowAccessPolicy", testingContext, policy).Return((*bqv2.RowAccessPolicy)(nil), nil)
+
+			job := new(BqJobMock)
+			defer job.AssertExpectations(t)
+			job.On("Wait", testingContext).Return(&bigquery.JobStatus{State: bigquery.Done}, nil)
+			query := new(BqQueryMock)
+			defer query.AssertExpectations(t)
+			query.On("Run", testingContext).Return(job, nil)
+			client := new(BqClientMock)
+			defer client.AssertExpectations(t)
+			client.On("Query", ddl).Return(query)
+
+			err := createRowAccessPolicy(testingContext, resourceSpec, client, iamClient, false)
+			assert.Nil(t, err)
+		})
+		t.Run("should replace policy if its grantees differ from spec", func(t *testing.T) {
+			iamClient := new(IAMClientMock)
+			defer iamClient.AssertExpectations(t)
+			iamClient.On("GetRowAccessPolicy", testingContext, policy).Return(&bqv2.RowAccessPolicy{
+				FilterPredicate: `region = "EU"`,
+			}, nil)
+			iamClient.On("GetRowAccessPolicyIAM", testingContext, policy).Return(&bqv2.Policy{
+				Bindings: []*bqv2.Binding{
+					{Role: rowAccessPolicyGranteeRole, Members: []string{"user:jane@example.com"}},
+				},
+			}, nil)
+
+			job := new(BqJobMock)
+			job.On("Wait", testingContext).Return(&bigquery.JobStatus{State: bigquery.Done}, nil)
+			query := new(BqQueryMock)
+			query.On("Run", testingContext).Return(job, nil)
+			client := new(BqClientMock)
+			defer client.AssertExpectations(t)
+			client.On("Query", ddl).Return(query)
+
+			err := createRowAccessPolicy(testingContext, resourceSpec, client, iamClient, true)
+			assert.Nil(t, err)
+		})
+		t.Run("should leave policy matching spec untouched", func(t *testing.T) {
+			iamClient := new(IAMClientMock)
+			defer iamClient.AssertExpectations(t)
+			iamClient.On("GetRowAccessPolicy", testingContext, policy).Return(&bqv2.RowAccessPolicy{
+				FilterPredicate: `region = "EU"`,
+			}, nil)
+			iamClient.On("GetRowAccessPolicyIAM", testingContext, policy).Return(&bqv2.Policy{
+				Bindings: []*bqv2.Binding{
+					{Role: rowAccessPolicyGranteeRole, Members: []string{"user:jane@example.com", "group:analysts@example.com"}},
+				},
+			}, nil)
+			client := new(BqClientMock)
+			defer client.AssertExpectations(t)
+
+			err := createRowAccessPolicy(testingContext, resourceSpec, client, iamClient, true)
+			assert.Nil(t, err)
+		})
+		t.Run("should fail for policy without filter", func(t *testing.T) {
+			noFilter := policy
+			noFilter.Metadata.Filter = " "
+			err := createRowAccessPolicy(testingContext, models.ResourceSpec{Spec: noFilter},
+				new(BqClientMock), new(IAMClientMock), true)
+			assert.Equal(t, "filter of row access policy project:dataset.table.eu_only can't be empty", err.Error())
+		})
+	})
+	t.Run("planRowAccessPolicy", func(t *testing.T) {
+		t.Run("should describe changes to filter and grantees", func(t *testing.T) {
+			iamClient := new(IAMClientMock)
+			defer iamClient.AssertExpectations(t)
+			iamClient.On("GetRowAccessPolicy", testingContext, policy).Return(&bqv2.RowAccessPolicy{
+				FilterPredicate: `region = "US"`,
+			}, nil)
+			iamClient.On("GetRowAccessPolicyIAM", testingContext, policy).Return(&bqv2.Policy{
+				Bindings: []*bqv2.Binding{
+					{Role: rowAccessPolicyGranteeRole, Members: []string{"user:jane@example.com", "user:john@example.com"}},
+				},
+			}, nil)
+
+			changes, err := planRowAccessPolicy(testingContext, iamClient, policy)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				"update row access policy project:dataset.table.eu_only",
+				`filter: "region = \"US\"" -> "region = \"EU\""`,
+				"grantee +group:analysts@example.com",
+				"grantee -user:john@example.com",
+			}, changes)
+		})
+		t.Run("should describe creation of missing policy", func(t *testing.T) {
+			iamClient := new(IAMClientMock)
+			defer iamClient.AssertExpectations(t)
+			iamClient.On("GetRowAccessPolicy", testingContext, policy).Return((*bqv2.RowAccessPolicy)(nil), nil)
+
+			changes, err := planRowAccessPolicy(testingContext, iamClient, policy)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"create row access policy project:dataset.table.eu_only"}, changes)
+		})
+	})
+	t.Run("deleteRowAccessPolicy", func(t *testing.T) {
+		t.Run("should drop policy from table", func(t *testing.T) {
+			job := new(BqJobMock)
+			job.On("Wait", testingContext).Return(&bigquery.JobStatus{State: bigquery.Done}, nil)
+			query := new(BqQueryMock)
+			query.On("Run", testingContext).Return(job, nil)
+			client := new(BqClientMock)
+			defer client.AssertExpectations(t)
+			client.On("Query", "DROP ROW ACCESS POLICY IF EXISTS `eu_only` ON `project.dataset.table`").Return(query)
+
+			err := deleteRowAccessPolicy(testingContext, resourceSpec, client)
+			assert.Nil(t, err)
+		})
+	})
+}
+
+func TestRowAccessPolicySpecHandler(t *testing.T) {
+	t.Run("should convert from and to yaml successfully", func(t *testing.T) {
+		fl := `
+version: 1
+name: proj.datas.tab.eu_only
+type: row_access_policy
+spec:
+  filter: region = "EU"
+  grantees: ["group:analysts@example.com"]
+labels:
+  owner: security
+`
+		handler := rowAccessPolicySpecHandler{}
+		res, err := handler.FromYaml([]byte(fl))
+		assert.Nil(t, err)
+		assert.Equal(t, BQRowAccessPolicy{
+			Project: "proj",
+			Dataset: "datas",
+			Table:   "tab",
+			Policy:  "eu_only",
+			Metadata: BQRowAccessPolicyMetadata{
+				Filter:   `region = "EU"`,
+				Grantees: []string{"group:analysts@example.com"},
+			},
+		}, res.Spec)
+		converted, err := handler.ToYaml(res)
+		assert.Nil(t, err)
+		resBack, err := handler.FromYaml(converted)
+		assert.Nil(t, err)
+		assert.Equal(t, res, resBack)
+	})
+	t.Run("should convert from and to proto successfully", func(t *testing.T) {
+		originalRes := models.ResourceSpec{
+			Version:   1,
+			Name:      "proj.datas.tab.eu_only",
+			Type:      models.ResourceTypeRowAccessPolicy,
+			Datastore: This,
+			Spec: BQRowAccessPolicy{
+				Project: "proj",
+				Dataset: "datas",
+				Table:   "tab",
+				Policy:  "eu_only",
+				Metadata: BQRowAccessPolicyMetadata{
+					Filter:   `region = "EU"`,
+					Grantees: []string{"group:analysts@example.com", "user:jane@example.com"},
+				},
+			},
+		}
+		handler := rowAccessPolicySpecHandler{}
+		protoInBytes, err := handler.ToProtobuf(originalRes)
+		assert.Nil(t, err)
+		resBack, err := handler.FromProtobuf(protoInBytes)
+		assert.Nil(t, err)
+		assert.Equal(t, originalRes, resBack)
+	})
+}
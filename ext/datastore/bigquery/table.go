@@ -2,11 +2,17 @@ package bigquery
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"regexp"
+	"sort"
+	"strings"
+
+	bqapi "cloud.google.com/go/bigquery"
 
 	"github.com/pkg/errors"
 
+	bqv2 "google.golang.org/api/bigquery/v2"
 	"google.golang.org/api/googleapi"
 
 	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
@@ -60,10 +66,107 @@ func ensureTable(ctx context.Context, tableHandle bqiface.Table, t BQTable, upse
 	if err != nil {
 		return err
 	}
+	clearRemovedPolicyTags(m.Schema, meta.Schema)
 	_, err = tableHandle.Update(ctx, m, meta.ETag)
 	return err
 }
 
+// planTable describes whether the table will be created or updated along with
+// changes to its IAM bindings
+func planTable(ctx context.Context, client bqiface.Client, iamClient IAMClient, t BQTable) ([]string, error) {
+	policy := &bqv2.Policy{}
+	changes := []string{"update table " + t.FullyQualifiedName()}
+	meta, err := client.DatasetInProject(t.Project, t.Dataset).Table(t.Table).Metadata(ctx)
+	if err != nil {
+		if metaErr, ok := err.(*googleapi.Error); !ok || metaErr.Code != http.StatusNotFound {
+			return nil, err
+		}
+		changes = []string{"create table " + t.FullyQualifiedName()}
+	} else {
+		changes = append(changes, planColumnMetadata(meta.Schema, t.Metadata.Schema, "")...)
+		if len(t.Metadata.IAM) > 0 {
+			if policy, err = iamClient.GetTablePolicy(ctx, t); err != nil {
+				return nil, errors.Wrapf(err, "failed to read IAM policy of table %s", t.FullyQualifiedName())
+			}
+		}
+	}
+
+	iamChanges, err := planTableIAM(policy, t.Metadata.IAM)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, iamChanges...), nil
+}
+
+// planColumnMetadata describes columns being added and changes to description
+// and policy tags of existing columns, nested columns are prefixed with parent
+func planColumnMetadata(current bqapi.Schema, desired BQSchema, prefix string) []string {
+	var changes []string
+	for _, field := range desired {
+		name := prefix + field.Name
+		currentField := findField(current, field.Name)
+		if currentField == nil {
+			changes = append(changes, fmt.Sprintf("column %s: added", name))
+			continue
+		}
+		if currentField.Description != field.Description {
+			changes = append(changes, fmt.Sprintf("column %s: description %q -> %q", name,
+				currentField.Description, field.Description))
+		}
+
+		currentTags := map[string]bool{}
+		if currentField.PolicyTags != nil {
+			for _, tag := range currentField.PolicyTags.Names {
+				currentTags[tag] = true
+			}
+		}
+		for _, tag := range field.PolicyTags {
+			if !currentTags[tag] {
+				changes = append(changes, fmt.Sprintf("column %s: policy tag +%s", name, tag))
+			}
+			delete(currentTags, tag)
+		}
+		var removedTags []string
+		for tag := range currentTags {
+			removedTags = append(removedTags, tag)
+		}
+		sort.Strings(removedTags)
+		for _, tag := range removedTags {
+			changes = append(changes, fmt.Sprintf("column %s: policy tag -%s", name, tag))
+		}
+
+		changes = append(changes, planColumnMetadata(currentField.Schema, field.Schema, name+".")...)
+	}
+	return changes
+}
+
+// clearRemovedPolicyTags marks policy tags of columns to be removed if they are
+// no more in spec, bigquery leaves the tags as is when they are omitted in update
+func clearRemovedPolicyTags(desired, current bqapi.Schema) {
+	for _, field := range desired {
+		currentField := findField(current, field.Name)
+		if currentField == nil {
+			continue
+		}
+		if field.PolicyTags == nil && currentField.PolicyTags != nil && len(currentField.PolicyTags.Names) > 0 {
+			field.PolicyTags = &bqapi.PolicyTagList{
+				Names: []string{},
+			}
+		}
+		clearRemovedPolicyTags(field.Schema, currentField.Schema)
+	}
+}
+
+// findField looks up a column by name, column names are case insensitive
+func findField(schema bqapi.Schema, name string) *bqapi.FieldSchema {
+	for _, field := range schema {
+		if field != nil && strings.EqualFold(field.Name, name) {
+			return field
+		}
+	}
+	return nil
+}
+
 // getTable retrieves bq table information
 func getTable(ctx context.Context, resourceSpec models.ResourceSpec, client bqiface.Client) (models.ResourceSpec, error) {
 	var bqResource BQTable
@@ -83,14 +186,24 @@ func getTable(ctx context.Context, resourceSpec models.ResourceSpec, client bqif
 		return models.ResourceSpec{}, err
 	}
 
+	if bqResource.Metadata, err = bqTableMetadataFrom(tableMeta); err != nil {
+		return models.ResourceSpec{}, err
+	}
+	resourceSpec.Spec = bqResource
+	return resourceSpec, nil
+}
+
+// bqTableMetadataFrom converts metadata of a table read from bigquery to
+// the one of its resource spec
+func bqTableMetadataFrom(tableMeta *bqapi.TableMetadata) (BQTableMetadata, error) {
 	// generate schema
 	tableSchema, err := bqSchemaFrom(tableMeta.Schema)
 	if err != nil {
-		return models.ResourceSpec{}, err
+		return BQTableMetadata{}, err
 	}
 
 	// update metadata
-	bqResource.Metadata = BQTableMetadata{
+	metadata := BQTableMetadata{
 		Description: tableMeta.Description,
 		Labels:      tableMeta.Labels,
 		Schema:      tableSchema,
@@ -101,16 +214,15 @@ func getTable(ctx context.Context, resourceSpec models.ResourceSpec, client bqif
 
 	// if table is partitioned
 	if tableMeta.TimePartitioning != nil {
-		bqResource.Metadata.Partition = bqPartitioningFrom(tableMeta.TimePartitioning)
+		metadata.Partition = bqPartitioningFrom(tableMeta.TimePartitioning)
 	} else if tableMeta.RangePartitioning != nil {
-		bqResource.Metadata.Partition = &BQPartitionInfo{
+		metadata.Partition = &BQPartitionInfo{
 			Field: tableMeta.RangePartitioning.Field,
 			Range: bqPartitioningRangeFrom(tableMeta.RangePartitioning.Range),
 		}
 	}
 
-	resourceSpec.Spec = bqResource
-	return resourceSpec, nil
+	return metadata, nil
 }
 
 func deleteTable(ctx context.Context, resourceSpec models.ResourceSpec, client bqiface.Client) error {
@@ -0,0 +1,94 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	bqapi "cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/pkg/errors"
+)
+
+const (
+	// tables can be read as they were at any point within the last 7 days
+	timeTravelWindow = time.Hour * 24 * 7
+)
+
+func parseTableName(name string) (BQTable, error) {
+	parts := tableNameParseRegex.FindStringSubmatch(name)
+	if len(parts) < 4 {
+		return BQTable{}, errors.Errorf("invalid table name %s, expected project.dataset.table", name)
+	}
+	return BQTable{Project: parts[1], Dataset: parts[2], Table: parts[3]}, nil
+}
+
+// restoreTable writes rows of source as they were at pointInTime into
+// destination with a query reading source FOR SYSTEM_TIME AS OF pointInTime.
+// Source is overwritten if it is the destination, otherwise destination is
+// created with the schema, partitioning and clustering of source and must
+// not exist already
+func restoreTable(ctx context.Context, client bqiface.Client, source, destination BQTable, pointInTime, now time.Time) error {
+	if now.Sub(pointInTime) > timeTravelWindow {
+		return errors.Errorf("%s is out of the time travel window, tables can be restored up to %s back",
+			pointInTime.Format(time.RFC3339), timeTravelWindow)
+	}
+	srcTable := client.DatasetInProject(source.Project, source.Dataset).Table(source.Table)
+	meta, err := srcTable.Metadata(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read table %s", source.FullyQualifiedName())
+	}
+	if meta.Type != bqapi.RegularTable {
+		return errors.Errorf("%s is not a table, only tables can be restored", source.FullyQualifiedName())
+	}
+	if pointInTime.Before(meta.CreationTime) {
+		return errors.Errorf("table %s was created after %s", source.FullyQualifiedName(), pointInTime.Format(time.RFC3339))
+	}
+
+	writeDisposition := bqapi.WriteTruncate
+	dstTable := srcTable
+	if destination.FullyQualifiedName() != source.FullyQualifiedName() {
+		dstTable = client.DatasetInProject(destination.Project, destination.Dataset).Table(destination.Table)
+		created, err := createIfNotExists(ctx, dstTable, &bqapi.TableMetadata{
+			Description:            meta.Description,
+			Labels:                 meta.Labels,
+			Schema:                 meta.Schema,
+			TimePartitioning:       meta.TimePartitioning,
+			RangePartitioning:      meta.RangePartitioning,
+			RequirePartitionFilter: meta.RequirePartitionFilter,
+			Clustering:             meta.Clustering,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to create table %s", destination.FullyQualifiedName())
+		}
+		if !created {
+			return errors.Errorf("table %s already exists", destination.FullyQualifiedName())
+		}
+		writeDisposition = bqapi.WriteEmpty
+	}
+
+	sql := fmt.Sprintf("SELECT * FROM `%s.%s.%s` FOR SYSTEM_TIME AS OF TIMESTAMP_MILLIS(%d)",
+		source.Project, source.Dataset, source.Table, pointInTime.UnixNano()/int64(time.Millisecond))
+	query := client.Query(sql)
+	query.SetQueryConfig(bqiface.QueryConfig{
+		QueryConfig: bqapi.QueryConfig{
+			Q:                 sql,
+			CreateDisposition: bqapi.CreateNever,
+			WriteDisposition:  writeDisposition,
+			// partitioning of destination has to match the one of source
+			TimePartitioning:  meta.TimePartitioning,
+			RangePartitioning: meta.RangePartitioning,
+			Clustering:        meta.Clustering,
+		},
+		Dst: dstTable,
+	})
+	job, err := query.Run(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to restore table %s", source.FullyQualifiedName())
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to restore table %s", source.FullyQualifiedName())
+	}
+	return status.Err()
+}
@@ -0,0 +1,109 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+func TestRestoreTable(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+	pointInTime := time.Date(2021, 6, 2, 8, 0, 0, 0, time.UTC)
+	source := BQTable{Project: "proj", Dataset: "datas", Table: "events"}
+	partitioning := &bigquery.TimePartitioning{Field: "event_timestamp"}
+	sourceMeta := &bigquery.TableMetadata{
+		Type:             bigquery.RegularTable,
+		Schema:           bigquery.Schema{&bigquery.FieldSchema{Name: "event_timestamp", Type: bigquery.TimestampFieldType}},
+		TimePartitioning: partitioning,
+		CreationTime:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	sql := "SELECT * FROM `proj.datas.events` FOR SYSTEM_TIME AS OF TIMESTAMP_MILLIS(1622620800000)"
+
+	t.Run("should overwrite table in place with its rows at point in time", func(t *testing.T) {
+		srcTable := new(BqTableMock)
+		srcTable.On("Metadata", ctx).Return(sourceMeta, nil)
+		dataset := new(BqDatasetMock)
+		dataset.On("Table", "events").Return(srcTable)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		job := new(BqJobMock)
+		defer job.AssertExpectations(t)
+		job.On("Wait", ctx).Return(&bigquery.JobStatus{State: bigquery.Done}, nil)
+		query := new(BqQueryMock)
+		defer query.AssertExpectations(t)
+		query.On("SetQueryConfig", bqiface.QueryConfig{
+			QueryConfig: bigquery.QueryConfig{
+				Q:                 sql,
+				CreateDisposition: bigquery.CreateNever,
+				WriteDisposition:  bigquery.WriteTruncate,
+				TimePartitioning:  partitioning,
+			},
+			Dst: srcTable,
+		}).Return()
+		query.On("Run", ctx).Return(job, nil)
+		client.On("Query", sql).Return(query)
+
+		err := restoreTable(ctx, client, source, source, pointInTime, now)
+		assert.Nil(t, err)
+	})
+	t.Run("should restore into a new table like the source", func(t *testing.T) {
+		destination := BQTable{Project: "proj", Dataset: "datas", Table: "events_restored"}
+		srcTable, dstTable := new(BqTableMock), new(BqTableMock)
+		defer dstTable.AssertExpectations(t)
+		srcTable.On("Metadata", ctx).Return(sourceMeta, nil)
+		dstTable.On("Metadata", ctx).Return((*bigquery.TableMetadata)(nil), &googleapi.Error{Code: 404})
+		dstTable.On("Create", ctx, &bigquery.TableMetadata{
+			Schema:           sourceMeta.Schema,
+			TimePartitioning: partitioning,
+		}).Return(nil)
+		dataset := new(BqDatasetMock)
+		dataset.On("Table", "events").Return(srcTable)
+		dataset.On("Table", "events_restored").Return(dstTable)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		job := new(BqJobMock)
+		job.On("Wait", ctx).Return(&bigquery.JobStatus{State: bigquery.Done}, nil)
+		query := new(BqQueryMock)
+		defer query.AssertExpectations(t)
+		query.On("SetQueryConfig", bqiface.QueryConfig{
+			QueryConfig: bigquery.QueryConfig{
+				Q:                 sql,
+				CreateDisposition: bigquery.CreateNever,
+				WriteDisposition:  bigquery.WriteEmpty,
+				TimePartitioning:  partitioning,
+			},
+			Dst: dstTable,
+		}).Return()
+		query.On("Run", ctx).Return(job, nil)
+		client.On("Query", sql).Return(query)
+
+		err := restoreTable(ctx, client, source, destination, pointInTime, now)
+		assert.Nil(t, err)
+	})
+	t.Run("should not overwrite an existing table other than the source", func(t *testing.T) {
+		destination := BQTable{Project: "proj", Dataset: "datas", Table: "events_restored"}
+		srcTable, dstTable := new(BqTableMock), new(BqTableMock)
+		srcTable.On("Metadata", ctx).Return(sourceMeta, nil)
+		dstTable.On("Metadata", ctx).Return(&bigquery.TableMetadata{}, nil)
+		dataset := new(BqDatasetMock)
+		dataset.On("Table", "events").Return(srcTable)
+		dataset.On("Table", "events_restored").Return(dstTable)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		err := restoreTable(ctx, client, source, destination, pointInTime, now)
+		assert.Equal(t, "table proj:datas.events_restored already exists", err.Error())
+	})
+	t.Run("should fail for point in time out of time travel window", func(t *testing.T) {
+		err := restoreTable(ctx, new(BqClientMock), source, source, now.Add(-time.Hour*24*8), now)
+		assert.Equal(t, "2021-05-25T10:00:00Z is out of the time travel window, tables can be restored up to 168h0m0s back", err.Error())
+	})
+}
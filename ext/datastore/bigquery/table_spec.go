@@ -24,13 +24,24 @@ var (
 
 // TableResourceSpec is how resource will be represented in yaml
 type TableResourceSpec struct {
-	Version int
+	APIVersion int `yaml:"apiVersion,omitempty"`
+	// Version is what apiVersion was written as before it
+	Version int `yaml:"version,omitempty"`
 	Name    string
 	Type    models.ResourceType
 	Spec    BQTableMetadata
 	Labels  map[string]string
 }
 
+// yamlSpecVersion is the version of a resource yaml, version is read only
+// for resources written before apiVersion
+func yamlSpecVersion(apiVersion, version int) int {
+	if apiVersion != 0 {
+		return apiVersion
+	}
+	return version
+}
+
 // BQTable is a specification for a BigQuery Table
 // The table may or may not exist
 //
@@ -76,6 +87,15 @@ type BQTableMetadata struct {
 
 	Location string            `yaml:",omitempty" structs:"location,omitempty"`
 	Labels   map[string]string `yaml:"-" structs:"-"` // inherited
+
+	// table level access, reconciled on every deployment
+	IAM []BQIAMBinding `yaml:"iam,omitempty" structs:"iam,omitempty"`
+}
+
+// BQIAMBinding grants a role on the table to members, e.g. group:analysts@example.com
+type BQIAMBinding struct {
+	Role    string   `yaml:"role" structs:"role"`
+	Members []string `yaml:"members" structs:"members"`
 }
 
 // BQField describes an individual field/column in a bigquery schema
@@ -85,6 +105,10 @@ type BQField struct {
 	Description string `yaml:",omitempty" structs:"description,omitempty"`
 	Mode        string `yaml:",omitempty" structs:"mode,omitempty"`
 
+	// data catalog policy tags of the column for column level security, e.g.:
+	// projects/<project>/locations/<location>/taxonomies/<taxonomy>/policyTags/<tag>
+	PolicyTags []string `yaml:"policy_tags,omitempty" structs:"policy_tags,omitempty"`
+
 	// optional sub-schema, if Type is set to Record
 	Schema BQSchema `yaml:",omitempty" structs:"schema,omitempty"`
 }
@@ -134,10 +158,10 @@ func (s tableSpecHandler) ToYaml(optResource models.ResourceSpec) ([]byte, error
 	}
 
 	yamlResource := TableResourceSpec{
-		Version: optResource.Version,
-		Name:    optResource.Name,
-		Type:    optResource.Type,
-		Spec:    spec.Metadata,
+		APIVersion: optResource.Version,
+		Name:       optResource.Name,
+		Type:       optResource.Type,
+		Spec:       spec.Metadata,
 	}
 	if len(yamlResource.Labels) > 0 {
 		yamlResource.Labels = optResource.Labels
@@ -157,7 +181,7 @@ func (s tableSpecHandler) FromYaml(b []byte) (models.ResourceSpec, error) {
 	}
 
 	optResource := models.ResourceSpec{
-		Version:   yamlResource.Version,
+		Version:   yamlSpecVersion(yamlResource.APIVersion, yamlResource.Version),
 		Name:      yamlResource.Name,
 		Type:      yamlResource.Type,
 		Datastore: This,
@@ -269,6 +293,10 @@ func (s tableSpecHandler) FromProtobuf(b []byte) (models.ResourceSpec, error) {
 		if protoSpecField, ok := protoSpec.Spec.Fields["partition"]; ok {
 			bqTable.Metadata.Partition = extractTablePartitionFromProtoStruct(protoSpecField)
 		}
+
+		if protoSpecField, ok := protoSpec.Spec.Fields["iam"]; ok {
+			bqTable.Metadata.IAM = extractTableIAMFromProtoStruct(protoSpecField)
+		}
 	}
 	return models.ResourceSpec{
 		Version:   int(protoSpec.Version),
@@ -304,6 +332,10 @@ func extractTableSchemaFieldFromProto(schemaListValues *structpb.Value) BQField
 			bqField.Description = schemaAttrVal.GetStringValue()
 		case "mode":
 			bqField.Mode = schemaAttrVal.GetStringValue()
+		case "policy_tags":
+			for _, tagVal := range schemaAttrVal.GetListValue().GetValues() {
+				bqField.PolicyTags = append(bqField.PolicyTags, tagVal.GetStringValue())
+			}
 		case "schema":
 			bqField.Schema = extractTableSchemaFromProtoStruct(schemaAttrVal)
 		}
@@ -341,6 +373,23 @@ func extractTablePartitionFromProtoStruct(protoVal *structpb.Value) *BQPartition
 	return pInfo
 }
 
+func extractTableIAMFromProtoStruct(protoVal *structpb.Value) []BQIAMBinding {
+	var bindings []BQIAMBinding
+	for _, bindingVal := range protoVal.GetListValue().GetValues() {
+		binding := BQIAMBinding{}
+		if f, ok := bindingVal.GetStructValue().GetFields()["role"]; ok {
+			binding.Role = f.GetStringValue()
+		}
+		if f, ok := bindingVal.GetStructValue().GetFields()["members"]; ok {
+			for _, memberVal := range f.GetListValue().GetValues() {
+				binding.Members = append(binding.Members, memberVal.GetStringValue())
+			}
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings
+}
+
 type tableSpec struct{}
 
 func (s tableSpec) Adapter() models.DatastoreSpecAdapter {
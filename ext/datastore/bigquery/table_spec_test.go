@@ -18,9 +18,15 @@ spec:
   schema:
   - name: aa
     type: INT
+    policy_tags:
+    - projects/prj/locations/us/taxonomies/1/policyTags/2
   partition:
     field: aa
     expiration: 24
+  iam:
+  - role: roles/bigquery.dataViewer
+    members:
+    - group:analysts@example.com
 `
 		tabHandler := tableSpecHandler{}
 		res, err := tabHandler.FromYaml([]byte(fl))
@@ -31,6 +37,21 @@ spec:
 		assert.Nil(t, err)
 		assert.Equal(t, res, resBack)
 	})
+	t.Run("should read version of yaml written before apiVersion and write apiVersion", func(t *testing.T) {
+		tabHandler := tableSpecHandler{}
+		res, err := tabHandler.FromYaml([]byte("version: 1\nname: prj.datas.t1\ntype: table\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, res.Version)
+
+		converted, err := tabHandler.ToYaml(res)
+		assert.Nil(t, err)
+		assert.Contains(t, string(converted), "apiVersion: 1\n")
+		assert.NotContains(t, string(converted), "\nversion:")
+
+		res, err = tabHandler.FromYaml([]byte("apiVersion: 2\nversion: 1\nname: prj.datas.t1\ntype: table\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, res.Version)
+	})
 
 	t.Run("should convert from and to proto successfully", func(t *testing.T) {
 		originalRes := models.ResourceSpec{
@@ -49,6 +70,7 @@ spec:
 							Name:        "col1",
 							Type:        "INT",
 							Description: "desc",
+							PolicyTags:  []string{"projects/proj/locations/us/taxonomies/1/policyTags/2"},
 							Schema:      nil,
 						},
 					},
@@ -60,6 +82,9 @@ spec:
 						SourceURIs: []string{"http://googlesheets.com/1234"},
 						Config:     map[string]interface{}{"skip_leading_rows": 1.0, "range": "A!:A1:B1"},
 					},
+					IAM: []BQIAMBinding{
+						{Role: "roles/bigquery.dataViewer", Members: []string{"group:analysts@example.com"}},
+					},
 				},
 			},
 			Assets: map[string]string{
@@ -142,6 +142,40 @@ func TestTable(t *testing.T) {
 			err := ensureTable(testingContext, bQTable, bQResource, upsert)
 			assert.Nil(t, err)
 		})
+		t.Run("should clear policy tags of columns which are no more tagged in spec on update", func(t *testing.T) {
+			upsert := true
+			piiTag := "projects/p/locations/us/taxonomies/1/policyTags/2"
+
+			bQTable := new(BqTableMock)
+			defer bQTable.AssertExpectations(t)
+
+			tableMeta := &bigquery.TableMetadata{
+				ETag: "etag-0000",
+				Schema: bigquery.Schema{
+					{Name: "message", Type: "STRING"},
+					{Name: "recipient", Type: "STRING", Repeated: true, PolicyTags: &bigquery.PolicyTagList{
+						Names: []string{piiTag},
+					}},
+				},
+			}
+			updateTableMeta := bigquery.TableMetadataToUpdate{
+				Name: bQResource.Table,
+				Schema: bigquery.Schema{
+					{Name: "message", Type: "STRING"},
+					{Name: "message_type", Type: "STRING"},
+					{Name: "recipient", Type: "STRING", Repeated: true, PolicyTags: &bigquery.PolicyTagList{
+						Names: []string{},
+					}},
+					{Name: "time", Type: "TIME"},
+				},
+			}
+
+			bQTable.On("Metadata", testingContext).Return(tableMeta, nil)
+			bQTable.On("Update", testingContext, updateTableMeta, tableMeta.ETag).Return(tableMeta, nil)
+
+			err := ensureTable(testingContext, bQTable, bQResource, upsert)
+			assert.Nil(t, err)
+		})
 		t.Run("should return an error if bigquery field specification is invalid (on create)", func(t *testing.T) {
 			upsert := false
 			invalidTable := BQTable{
@@ -194,6 +228,32 @@ func TestTable(t *testing.T) {
 		})
 	})
 
+	t.Run("planColumnMetadata", func(t *testing.T) {
+		t.Run("should describe changes to columns and their policy tags", func(t *testing.T) {
+			current := bigquery.Schema{
+				{Name: "email", Type: "STRING", Description: "email", PolicyTags: &bigquery.PolicyTagList{
+					Names: []string{"tags/low"},
+				}},
+				{Name: "address", Type: "RECORD", Schema: bigquery.Schema{
+					{Name: "city", Type: "STRING"},
+				}},
+			}
+			desired := BQSchema{
+				{Name: "email", Type: "STRING", Description: "email of user", PolicyTags: []string{"tags/pii"}},
+				{Name: "address", Type: "RECORD", Schema: BQSchema{
+					{Name: "city", Type: "STRING", PolicyTags: []string{"tags/pii"}},
+					{Name: "zip", Type: "STRING"},
+				}},
+			}
+			assert.Equal(t, []string{
+				`column email: description "email" -> "email of user"`,
+				"column email: policy tag +tags/pii",
+				"column email: policy tag -tags/low",
+				"column address.city: policy tag +tags/pii",
+				"column address.zip: added",
+			}, planColumnMetadata(current, desired, ""))
+		})
+	})
 	t.Run("createTable", func(t *testing.T) {
 		t.Run("should create table if given valid input", func(t *testing.T) {
 			upsert := false
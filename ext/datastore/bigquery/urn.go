@@ -0,0 +1,71 @@
+package bigquery
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	// names of bigquery urns, project:dataset for datasets and
+	// project:dataset.table for tables and views
+	urnNameRegex = regexp.MustCompile(`^([\w-]+):(\w+)(?:\.([\w-]+))?$`)
+)
+
+// ResourceURN returns the urn of a dataset, table or view, e.g.
+// bigquery://project:dataset.table, row access policies have none
+func (b *BigQuery) ResourceURN(resourceSpec models.ResourceSpec) (models.URN, error) {
+	switch resourceSpec.Type {
+	case models.ResourceTypeDataset:
+		dataset, err := parseDatasetName(resourceSpec.Name)
+		if err != nil {
+			return models.URN{}, err
+		}
+		return models.URN{
+			Scheme: models.URNSchemeBigQuery,
+			Name:   fmt.Sprintf("%s:%s", dataset.Project, dataset.Dataset),
+		}, nil
+	case models.ResourceTypeTable, models.ResourceTypeView, models.ResourceTypeExternalTable:
+		table, err := parseTableName(resourceSpec.Name)
+		if err != nil {
+			return models.URN{}, err
+		}
+		return models.URN{
+			Scheme: models.URNSchemeBigQuery,
+			Name:   fmt.Sprintf("%s:%s.%s", table.Project, table.Dataset, table.Table),
+		}, nil
+	}
+	return models.URN{}, errors.Errorf("%s %s can't be named with a urn", resourceSpec.Type, resourceSpec.Name)
+}
+
+// ResourceName returns the name of dataset or table of a bigquery urn, i.e.
+// project.dataset or project.dataset.table
+func (b *BigQuery) ResourceName(urn models.URN) (string, error) {
+	if urn.Scheme != models.URNSchemeBigQuery {
+		return "", errors.Errorf("urn %s is not of bigquery", urn)
+	}
+	parts := urnNameRegex.FindStringSubmatch(urn.Name)
+	if parts == nil {
+		return "", errors.Errorf("invalid bigquery urn %s, expected bigquery://project:dataset.table or bigquery://project:dataset", urn)
+	}
+	if parts[3] == "" {
+		return fmt.Sprintf("%s.%s", parts[1], parts[2]), nil
+	}
+	return fmt.Sprintf("%s.%s.%s", parts[1], parts[2], parts[3]), nil
+}
+
+// parseTableURN returns the table a urn of a job destination or source
+// refers, false for urns which aren't of bigquery tables
+func parseTableURN(value string) (BQTable, bool) {
+	urn, err := models.ParseURN(value)
+	if err != nil || urn.Scheme != models.URNSchemeBigQuery {
+		return BQTable{}, false
+	}
+	parts := urnNameRegex.FindStringSubmatch(urn.Name)
+	if parts == nil || parts[3] == "" {
+		return BQTable{}, false
+	}
+	return BQTable{Project: parts[1], Dataset: parts[2], Table: parts[3]}, true
+}
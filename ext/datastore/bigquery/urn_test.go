@@ -0,0 +1,59 @@
+package bigquery
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURN(t *testing.T) {
+	bq := &BigQuery{}
+	t.Run("ResourceURN", func(t *testing.T) {
+		t.Run("should name datasets, tables and views with urns", func(t *testing.T) {
+			urn, err := bq.ResourceURN(models.ResourceSpec{Name: "proj.datas", Type: models.ResourceTypeDataset})
+			assert.Nil(t, err)
+			assert.Equal(t, "bigquery://proj:datas", urn.String())
+
+			urn, err = bq.ResourceURN(models.ResourceSpec{Name: "proj.datas.events", Type: models.ResourceTypeView})
+			assert.Nil(t, err)
+			assert.Equal(t, "bigquery://proj:datas.events", urn.String())
+		})
+		t.Run("should fail for row access policies", func(t *testing.T) {
+			_, err := bq.ResourceURN(models.ResourceSpec{Name: "proj.datas.events.policy", Type: models.ResourceTypeRowAccessPolicy})
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("ResourceName", func(t *testing.T) {
+		t.Run("should return name of resource of urn", func(t *testing.T) {
+			name, err := bq.ResourceName(models.URN{Scheme: models.URNSchemeBigQuery, Name: "proj:datas.events"})
+			assert.Nil(t, err)
+			assert.Equal(t, "proj.datas.events", name)
+
+			name, err = bq.ResourceName(models.URN{Scheme: models.URNSchemeBigQuery, Name: "proj:datas"})
+			assert.Nil(t, err)
+			assert.Equal(t, "proj.datas", name)
+		})
+		t.Run("should fail for urns not naming a resource of bigquery", func(t *testing.T) {
+			for _, urn := range []models.URN{
+				{Scheme: models.URNSchemeGCS, Name: "bucket/path"},
+				{Scheme: models.URNSchemeBigQuery, Name: "proj.datas.events"},
+				{Scheme: models.URNSchemeBigQuery, Name: "proj:datas.events.column"},
+			} {
+				_, err := bq.ResourceName(urn)
+				assert.NotNil(t, err, urn.String())
+			}
+		})
+	})
+	t.Run("parseTableURN", func(t *testing.T) {
+		t.Run("should parse urns of tables in canonical and legacy form", func(t *testing.T) {
+			for _, value := range []string{"bigquery://proj:datas.events", "proj:datas.events"} {
+				table, ok := parseTableURN(value)
+				assert.True(t, ok)
+				assert.Equal(t, BQTable{Project: "proj", Dataset: "datas", Table: "events"}, table)
+			}
+			_, ok := parseTableURN("bigquery://proj:datas")
+			assert.False(t, ok)
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	bqapi "cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+)
+
+// LastReadAt looks up the latest query job of the project of a destination
+// table referencing it since a time, jobs writing to the table itself are
+// ignored. Jobs are read from INFORMATION_SCHEMA.JOBS_BY_PROJECT which needs
+// bigquery.jobs.listAll permission and only holds the last 180 days, reads
+// by jobs running in other projects aren't seen
+func (b *BigQuery) LastReadAt(ctx context.Context, request models.ReadUsageRequest) (models.ReadUsageResponse, error) {
+	table, ok := parseTableURN(request.Destination)
+	if !ok {
+		return models.ReadUsageResponse{}, nil
+	}
+	svcAcc, err := b.credentials(request.Project, request.Namespace)
+	if err != nil {
+		return models.ReadUsageResponse{}, err
+	}
+
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	client, err := b.ClientFac.New(ctx, svcAcc)
+	if err != nil {
+		return models.ReadUsageResponse{}, err
+	}
+	lastReadAt, err := lastTableReadAt(ctx, client, table, request.Since)
+	if err != nil {
+		return models.ReadUsageResponse{}, err
+	}
+	return models.ReadUsageResponse{Supported: true, LastReadAt: lastReadAt}, nil
+}
+
+func lastTableReadAt(ctx context.Context, client bqiface.Client, table BQTable, since time.Time) (time.Time, error) {
+	// jobs are kept in INFORMATION_SCHEMA of the region of their dataset
+	meta, err := client.DatasetInProject(table.Project, table.Dataset).Metadata(ctx)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to read dataset %s.%s", table.Project, table.Dataset)
+	}
+	sql := fmt.Sprintf("SELECT MAX(creation_time) AS last_read_at "+
+		"FROM `%s`.`region-%s`.INFORMATION_SCHEMA.JOBS_BY_PROJECT, UNNEST(referenced_tables) AS ref "+
+		"WHERE creation_time >= TIMESTAMP_MILLIS(%d) AND job_type = 'QUERY' "+
+		"AND ref.project_id = '%s' AND ref.dataset_id = '%s' AND ref.table_id = '%s' "+
+		"AND (destination_table.table_id IS NULL OR destination_table.project_id != ref.project_id "+
+		"OR destination_table.dataset_id != ref.dataset_id OR destination_table.table_id != ref.table_id)",
+		table.Project, strings.ToLower(meta.Location), since.UnixNano()/int64(time.Millisecond),
+		table.Project, table.Dataset, table.Table)
+
+	it, err := client.Query(sql).Read(ctx)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to look up reads of table %s", table.FullyQualifiedName())
+	}
+	var row struct {
+		LastReadAt bqapi.NullTimestamp `bigquery:"last_read_at"`
+	}
+	if err := it.Next(&row); err != nil {
+		if err == iterator.Done {
+			return time.Time{}, nil
+		}
+		return time.Time{}, errors.Wrapf(err, "failed to look up reads of table %s", table.FullyQualifiedName())
+	}
+	if !row.LastReadAt.Valid {
+		return time.Time{}, nil
+	}
+	return row.LastReadAt.Timestamp.UTC(), nil
+}
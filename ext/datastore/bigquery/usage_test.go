@@ -0,0 +1,55 @@
+package bigquery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLastReadAt(t *testing.T) {
+	ctx := context.Background()
+	since := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	sql := "SELECT MAX(creation_time) AS last_read_at " +
+		"FROM `proj`.`region-asia-southeast2`.INFORMATION_SCHEMA.JOBS_BY_PROJECT, UNNEST(referenced_tables) AS ref " +
+		"WHERE creation_time >= TIMESTAMP_MILLIS(1622505600000) AND job_type = 'QUERY' " +
+		"AND ref.project_id = 'proj' AND ref.dataset_id = 'datas' AND ref.table_id = 'events' " +
+		"AND (destination_table.table_id IS NULL OR destination_table.project_id != ref.project_id " +
+		"OR destination_table.dataset_id != ref.dataset_id OR destination_table.table_id != ref.table_id)"
+
+	t.Run("should return latest query job referencing table", func(t *testing.T) {
+		lastReadAt := time.Date(2021, 6, 10, 5, 0, 0, 0, time.UTC)
+		dataset := new(BqDatasetMock)
+		dataset.On("Metadata", ctx).Return(&bqiface.DatasetMetadata{
+			DatasetMetadata: bigquery.DatasetMetadata{Location: "asia-southeast2"},
+		}, nil)
+		client := new(BqClientMock)
+		client.On("DatasetInProject", "proj", "datas").Return(dataset)
+
+		it := new(BqRowIteratorMock)
+		it.On("Next", mock.Anything).Run(func(args mock.Arguments) {
+			row := args.Get(0).(*struct {
+				LastReadAt bigquery.NullTimestamp `bigquery:"last_read_at"`
+			})
+			row.LastReadAt = bigquery.NullTimestamp{Timestamp: lastReadAt, Valid: true}
+		}).Return(nil)
+		query := new(BqQueryMock)
+		defer query.AssertExpectations(t)
+		query.On("Read", ctx).Return(it, nil)
+		client.On("Query", sql).Return(query)
+
+		readAt, err := lastTableReadAt(ctx, client, BQTable{Project: "proj", Dataset: "datas", Table: "events"}, since)
+		assert.Nil(t, err)
+		assert.Equal(t, lastReadAt, readAt)
+	})
+	t.Run("should not support destinations other than tables", func(t *testing.T) {
+		resp, err := (&BigQuery{}).LastReadAt(ctx, models.ReadUsageRequest{Destination: "gs://bucket/path"})
+		assert.Nil(t, err)
+		assert.False(t, resp.Supported)
+	})
+}
@@ -0,0 +1,296 @@
+package dbt
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/local"
+	"github.com/pkg/errors"
+	"gopkg.in/validator.v2"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// TaskName is the task jobs converted from models run with
+	TaskName = "bq2bq"
+
+	QueryAssetName = "query.sql"
+
+	loadMethodAppend  = "APPEND"
+	loadMethodReplace = "REPLACE"
+)
+
+var (
+	thisRegex          = regexp.MustCompile(`\{\{-?\s*this\s*-?\}\}`)
+	isIncrementalRegex = regexp.MustCompile(`\{%-?\s*(?:if\s+is_incremental\(\)|endif)\s*-?%\}`)
+	jinjaRegex         = regexp.MustCompile(`\{\{|\{%`)
+)
+
+// Mapping configures how models of a dbt project are converted to jobs, dbt
+// projects don't schedule their models so schedules are set here
+type Mapping struct {
+	// Project and Dataset are the bigquery project and dataset models are
+	// written to, the database and schema of dbt target
+	Project string `yaml:"project"`
+	Dataset string `yaml:"dataset"`
+
+	Owner    string              `yaml:"owner"`
+	Schedule local.JobSchedule   `yaml:"schedule"`
+	Window   local.JobTaskWindow `yaml:"window"`
+
+	// Models overrides the defaults above for a model, keyed by model name
+	Models map[string]ModelMapping `yaml:"models"`
+}
+
+type ModelMapping struct {
+	// Job is name of the job of model, model name if empty
+	Job        string              `yaml:"job"`
+	Owner      string              `yaml:"owner"`
+	Schedule   local.JobSchedule   `yaml:"schedule"`
+	Window     local.JobTaskWindow `yaml:"window"`
+	LoadMethod string              `yaml:"load_method"`
+	// Skip leaves the model out of conversion
+	Skip bool `yaml:"skip"`
+}
+
+// ParseMapping reads a mapping from yaml
+func ParseMapping(raw []byte) (Mapping, error) {
+	var mapping Mapping
+	if err := yaml.Unmarshal(raw, &mapping); err != nil {
+		return Mapping{}, errors.Wrap(err, "failed to parse mapping")
+	}
+	if mapping.Project == "" || mapping.Dataset == "" {
+		return Mapping{}, errors.New("project and dataset models are written to are required in mapping")
+	}
+	return mapping, nil
+}
+
+// Job is a job spec converted from a model
+type Job struct {
+	// Dir is directory of job spec relative to job path, models are
+	// converted in the same directories they are in the dbt project
+	Dir  string
+	Spec local.Job
+}
+
+type Conversion struct {
+	Jobs []Job
+	// Warnings are about models left out of conversion, or ones converted
+	// with parts which need to be looked at
+	Warnings []string
+}
+
+type modelTarget struct {
+	job   string
+	table string
+	// converted is set for models run as jobs
+	converted bool
+}
+
+// Convert converts models materialized as tables into jobs of bq2bq task
+// with the query of model, refs and sources in queries are replaced by tables
+// they resolve to and jobs depend on jobs of the models they ref. Views and
+// ephemeral models are not converted, as views are resources in optimus
+// and there is nothing to run for ephemeral models
+func Convert(project Project, mapping Mapping) (Conversion, error) {
+	var conversion Conversion
+	targets := map[string]modelTarget{}
+	for _, model := range project.Models {
+		modelMapping := mapping.Models[model.Name]
+		dataset := mapping.Dataset
+		if model.Schema != "" {
+			// custom schemas are suffixed to target schema by dbt
+			dataset = mapping.Dataset + "_" + model.Schema
+		}
+		table := model.Name
+		if model.Alias != "" {
+			table = model.Alias
+		}
+		job := modelMapping.Job
+		if job == "" {
+			job = model.Name
+		}
+		target := modelTarget{
+			job:   job,
+			table: fmt.Sprintf("%s.%s.%s", mapping.Project, dataset, table),
+		}
+		switch {
+		case modelMapping.Skip:
+		case model.Materialized == MaterializedTable || model.Materialized == MaterializedIncremental:
+			target.converted = true
+		case model.Materialized == MaterializedView:
+			conversion.Warnings = append(conversion.Warnings, fmt.Sprintf("%s is a view, views should be created as resources", model.Name))
+		default:
+			conversion.Warnings = append(conversion.Warnings, fmt.Sprintf("%s is materialized as %s which can't be run as a job", model.Name, model.Materialized))
+		}
+		targets[model.Name] = target
+	}
+
+	jobNames := map[string]string{}
+	for _, model := range project.Models {
+		target := targets[model.Name]
+		if !target.converted {
+			continue
+		}
+		if other, ok := jobNames[target.job]; ok {
+			return Conversion{}, errors.Errorf("models %s and %s are converted to the same job %s", other, model.Name, target.job)
+		}
+		jobNames[target.job] = model.Name
+
+		query, warnings := convertQuery(model, project, mapping, targets)
+		conversion.Warnings = append(conversion.Warnings, warnings...)
+		job, err := convertModel(model, mapping, target, query, targets)
+		if err != nil {
+			return Conversion{}, err
+		}
+		conversion.Jobs = append(conversion.Jobs, Job{
+			Dir:  filepath.Join(model.Dir, model.Name),
+			Spec: job,
+		})
+	}
+	return conversion, nil
+}
+
+// convertQuery replaces jinja of dbt in query of model with the tables it
+// resolves to, jinja which can't be replaced is left as is with a warning
+func convertQuery(model Model, project Project, mapping Mapping, targets map[string]modelTarget) (string, []string) {
+	var warnings []string
+	query := refRegex.ReplaceAllStringFunc(model.SQL, func(ref string) string {
+		parts := refRegex.FindStringSubmatch(ref)
+		name := parts[1]
+		if parts[2] != "" {
+			name = parts[2]
+		}
+		target, ok := targets[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s refs %s which is not a model of the project", model.Name, name))
+			return ref
+		}
+		return "`" + target.table + "`"
+	})
+	query = sourceRegex.ReplaceAllStringFunc(query, func(src string) string {
+		parts := sourceRegex.FindStringSubmatch(src)
+		table, ok := project.Sources[parts[1]][parts[2]]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s reads source %s.%s which is not declared", model.Name, parts[1], parts[2]))
+			return src
+		}
+		database := table.Database
+		if database == "" {
+			database = mapping.Project
+		}
+		return fmt.Sprintf("`%s.%s.%s`", database, table.Schema, table.Identifier)
+	})
+	query = thisRegex.ReplaceAllString(query, "`"+targets[model.Name].table+"`")
+	// jobs of incremental models append to tables created already, so their
+	// incremental filters always apply
+	query = isIncrementalRegex.ReplaceAllString(query, "")
+
+	if jinjaRegex.MatchString(query) {
+		warnings = append(warnings, fmt.Sprintf("%s uses jinja which can't be converted, edit its %s", model.Name, QueryAssetName))
+	}
+	return query, warnings
+}
+
+func convertModel(model Model, mapping Mapping, target modelTarget, query string, targets map[string]modelTarget) (local.Job, error) {
+	modelMapping := mapping.Models[model.Name]
+	owner := modelMapping.Owner
+	if owner == "" {
+		owner = mapping.Owner
+	}
+	schedule := mergeSchedule(modelMapping.Schedule, mapping.Schedule)
+	window := mergeWindow(modelMapping.Window, mapping.Window)
+
+	loadMethod := modelMapping.LoadMethod
+	if loadMethod == "" {
+		loadMethod = loadMethodReplace
+		if model.Materialized == MaterializedIncremental {
+			loadMethod = loadMethodAppend
+		}
+	}
+	parts := strings.SplitN(target.table, ".", 3)
+
+	var dependencies []local.JobDependency
+	for _, ref := range model.Refs {
+		if refTarget, ok := targets[ref]; ok && refTarget.converted {
+			dependencies = append(dependencies, local.JobDependency{
+				JobName: refTarget.job,
+				Type:    string(models.JobSpecDependencyTypeIntra),
+			})
+		}
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].JobName < dependencies[j].JobName
+	})
+
+	description := model.Description
+	if description == "" {
+		description = fmt.Sprintf("converted from dbt model %s", model.Name)
+	}
+	job := local.Job{
+		APIVersion:  local.JobConfigVersion,
+		Name:        target.job,
+		Owner:       owner,
+		Description: description,
+		Schedule:    schedule,
+		Task: local.JobTask{
+			Name: TaskName,
+			Config: yaml.MapSlice{
+				{Key: "PROJECT", Value: parts[0]},
+				{Key: "DATASET", Value: parts[1]},
+				{Key: "TABLE", Value: parts[2]},
+				{Key: "LOAD_METHOD", Value: loadMethod},
+				{Key: "SQL_TYPE", Value: "STANDARD"},
+			},
+			Window: window,
+		},
+		Asset: map[string]string{
+			QueryAssetName: query,
+		},
+		Dependencies: dependencies,
+	}
+	if err := validator.Validate(job); err != nil {
+		return local.Job{}, errors.Wrapf(err, "invalid job of model %s", model.Name)
+	}
+	return job, nil
+}
+
+func mergeSchedule(schedule, defaults local.JobSchedule) local.JobSchedule {
+	if schedule.StartDate == "" {
+		schedule.StartDate = defaults.StartDate
+	}
+	if schedule.EndDate == "" {
+		schedule.EndDate = defaults.EndDate
+	}
+	if schedule.Interval == "" {
+		schedule.Interval = defaults.Interval
+	}
+	return schedule
+}
+
+func mergeWindow(window, defaults local.JobTaskWindow) local.JobTaskWindow {
+	if window.Size == "" {
+		window.Size = defaults.Size
+	}
+	if window.Offset == "" {
+		window.Offset = defaults.Offset
+	}
+	if window.TruncateTo == "" {
+		window.TruncateTo = defaults.TruncateTo
+	}
+	// same window as jobs created with optimus create job
+	if window.Size == "" {
+		window.Size = "24h"
+	}
+	if window.Offset == "" {
+		window.Offset = "0"
+	}
+	if window.TruncateTo == "" {
+		window.TruncateTo = "d"
+	}
+	return window
+}
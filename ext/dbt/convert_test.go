@@ -0,0 +1,142 @@
+package dbt_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/ext/dbt"
+	"github.com/odpf/optimus/store/local"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConvert(t *testing.T) {
+	project := dbt.Project{
+		Name: "shop",
+		Sources: map[string]map[string]dbt.SourceTable{
+			"raw": {"orders": {Database: "raw-project", Schema: "raw", Identifier: "orders_v2"}},
+		},
+		Models: []dbt.Model{
+			{
+				Name:         "orders",
+				Description:  "orders placed in the shop",
+				Dir:          "marts",
+				SQL:          "select * from {{ ref('stg_orders') }}",
+				Materialized: "table",
+				Refs:         []string{"stg_orders"},
+			},
+			{
+				Name:         "revenue",
+				Dir:          "marts/finance",
+				SQL:          "select * from {{ ref('orders') }} {% if is_incremental() %}where ts > (select max(ts) from {{ this }}){% endif %}",
+				Materialized: "incremental",
+				Schema:       "finance",
+				Alias:        "daily_revenue",
+				Refs:         []string{"orders"},
+			},
+			{
+				Name:         "stg_orders",
+				Dir:          "staging",
+				SQL:          "select * from {{ source('raw', 'orders') }}",
+				Materialized: "view",
+			},
+		},
+	}
+	mapping, err := dbt.ParseMapping([]byte(`
+project: shop-project
+dataset: analytics
+owner: data@example.com
+schedule:
+  start_date: "2021-06-01"
+  interval: 0 2 * * *
+models:
+  revenue:
+    job: shop_revenue
+    schedule:
+      interval: 0 4 * * *
+`))
+	assert.Nil(t, err)
+
+	t.Run("should convert models materialized as tables to jobs depending on jobs of their refs", func(t *testing.T) {
+		conversion, err := dbt.Convert(project, mapping)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"stg_orders is a view, views should be created as resources"}, conversion.Warnings)
+		assert.Equal(t, []dbt.Job{
+			{
+				Dir: "marts/orders",
+				Spec: local.Job{
+					APIVersion:  1,
+					Name:        "orders",
+					Owner:       "data@example.com",
+					Description: "orders placed in the shop",
+					Schedule:    local.JobSchedule{StartDate: "2021-06-01", Interval: "0 2 * * *"},
+					Task: local.JobTask{
+						Name: "bq2bq",
+						Config: yaml.MapSlice{
+							{Key: "PROJECT", Value: "shop-project"},
+							{Key: "DATASET", Value: "analytics"},
+							{Key: "TABLE", Value: "orders"},
+							{Key: "LOAD_METHOD", Value: "REPLACE"},
+							{Key: "SQL_TYPE", Value: "STANDARD"},
+						},
+						Window: local.JobTaskWindow{Size: "24h", Offset: "0", TruncateTo: "d"},
+					},
+					Asset: map[string]string{
+						"query.sql": "select * from `shop-project.analytics.stg_orders`",
+					},
+				},
+			},
+			{
+				Dir: "marts/finance/revenue",
+				Spec: local.Job{
+					APIVersion:  1,
+					Name:        "shop_revenue",
+					Owner:       "data@example.com",
+					Description: "converted from dbt model revenue",
+					Schedule:    local.JobSchedule{StartDate: "2021-06-01", Interval: "0 4 * * *"},
+					Task: local.JobTask{
+						Name: "bq2bq",
+						Config: yaml.MapSlice{
+							{Key: "PROJECT", Value: "shop-project"},
+							{Key: "DATASET", Value: "analytics_finance"},
+							{Key: "TABLE", Value: "daily_revenue"},
+							{Key: "LOAD_METHOD", Value: "APPEND"},
+							{Key: "SQL_TYPE", Value: "STANDARD"},
+						},
+						Window: local.JobTaskWindow{Size: "24h", Offset: "0", TruncateTo: "d"},
+					},
+					Asset: map[string]string{
+						"query.sql": "select * from `shop-project.analytics.orders` " +
+							"where ts > (select max(ts) from `shop-project.analytics_finance.daily_revenue`)",
+					},
+					Dependencies: []local.JobDependency{{JobName: "orders", Type: "intra"}},
+				},
+			},
+		}, conversion.Jobs)
+	})
+	t.Run("should warn of jinja which can't be converted", func(t *testing.T) {
+		conversion, err := dbt.Convert(dbt.Project{
+			Models: []dbt.Model{{
+				Name:         "events",
+				SQL:          "select {{ dbt_utils.star(ref('raw_events')) }} from {{ source('raw', 'events') }}",
+				Materialized: "table",
+			}},
+		}, mapping)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"events reads source raw.events which is not declared",
+			"events uses jinja which can't be converted, edit its query.sql",
+		}, conversion.Warnings)
+		assert.Len(t, conversion.Jobs, 1)
+	})
+	t.Run("should fail for invalid schedule", func(t *testing.T) {
+		invalid := mapping
+		invalid.Schedule = local.JobSchedule{StartDate: "2021-06-01", Interval: "daily"}
+		invalid.Models = nil
+		_, err := dbt.Convert(project, invalid)
+		assert.NotNil(t, err)
+	})
+	t.Run("should require destination of models in mapping", func(t *testing.T) {
+		_, err := dbt.ParseMapping([]byte(`owner: data@example.com`))
+		assert.Equal(t, "project and dataset models are written to are required in mapping", err.Error())
+	})
+}
@@ -0,0 +1,286 @@
+package dbt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ProjectFileName = "dbt_project.yml"
+
+	MaterializedTable       = "table"
+	MaterializedIncremental = "incremental"
+	MaterializedView        = "view"
+	MaterializedEphemeral   = "ephemeral"
+
+	// dbt materializes models as views unless configured otherwise
+	defaultMaterialized = MaterializedView
+	defaultModelPath    = "models"
+)
+
+var (
+	configBlockRegex = regexp.MustCompile(`(?s)\{\{-?\s*config\((.*?)\)\s*-?\}\}`)
+	configArgRegex   = regexp.MustCompile(`(\w+)\s*=\s*['"]([^'"]*)['"]`)
+	refRegex         = regexp.MustCompile(`\{\{-?\s*ref\(\s*['"]([^'"]+)['"]\s*(?:,\s*['"]([^'"]+)['"]\s*)?\)\s*-?\}\}`)
+	sourceRegex      = regexp.MustCompile(`\{\{-?\s*source\(\s*['"]([^'"]+)['"]\s*,\s*['"]([^'"]+)['"]\s*\)\s*-?\}\}`)
+)
+
+// Project is a dbt project as read from its directory
+type Project struct {
+	Name   string
+	Models []Model
+
+	// Sources are tables read by models which are not built by the project,
+	// keyed by source name and table name
+	Sources map[string]map[string]SourceTable
+}
+
+// Model is a select statement of a dbt project, materialized as a table
+// named after the model
+type Model struct {
+	Name        string
+	Description string
+	// Dir is directory of model relative to the model path it is in
+	Dir string
+	SQL string
+
+	// Materialized, Schema and Alias are configs of model, set in the model
+	// or inherited from the directories it is in
+	Materialized string
+	Schema       string
+	Alias        string
+
+	// Refs are names of models the model selects from
+	Refs []string
+}
+
+type SourceTable struct {
+	Database   string
+	Schema     string
+	Identifier string
+}
+
+type projectFile struct {
+	Name        string                 `yaml:"name"`
+	ModelPaths  []string               `yaml:"model-paths"`
+	SourcePaths []string               `yaml:"source-paths"`
+	Models      map[string]interface{} `yaml:"models"`
+}
+
+type propertiesFile struct {
+	Models []struct {
+		Name        string `yaml:"name"`
+		Description string `yaml:"description"`
+	} `yaml:"models"`
+	Sources []struct {
+		Name     string `yaml:"name"`
+		Database string `yaml:"database"`
+		Schema   string `yaml:"schema"`
+		Tables   []struct {
+			Name       string `yaml:"name"`
+			Identifier string `yaml:"identifier"`
+		} `yaml:"tables"`
+	} `yaml:"sources"`
+}
+
+// LoadProject reads models of the dbt project at root of fs, along with their
+// configs and sources declared in property files of model paths
+func LoadProject(fs afero.Fs) (Project, error) {
+	raw, err := afero.ReadFile(fs, ProjectFileName)
+	if err != nil {
+		return Project{}, errors.Wrapf(err, "failed to read %s", ProjectFileName)
+	}
+	var projFile projectFile
+	if err := yaml.Unmarshal(raw, &projFile); err != nil {
+		return Project{}, errors.Wrapf(err, "failed to parse %s", ProjectFileName)
+	}
+	if projFile.Name == "" {
+		return Project{}, errors.Errorf("name of project is missing in %s", ProjectFileName)
+	}
+	modelPaths := projFile.ModelPaths
+	if len(modelPaths) == 0 {
+		// dbt versions before 1.0 named model paths as source paths
+		modelPaths = projFile.SourcePaths
+	}
+	if len(modelPaths) == 0 {
+		modelPaths = []string{defaultModelPath}
+	}
+
+	project := Project{
+		Name:    projFile.Name,
+		Sources: map[string]map[string]SourceTable{},
+	}
+	descriptions := map[string]string{}
+	for _, modelPath := range modelPaths {
+		err := afero.Walk(fs, modelPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(modelPath, path)
+			if err != nil {
+				return err
+			}
+			switch filepath.Ext(path) {
+			case ".sql":
+				model, err := readModel(fs, path, filepath.Dir(rel))
+				if err != nil {
+					return err
+				}
+				model.Materialized, model.Schema = configOfDir(projFile.Models, projFile.Name, model.Dir, model.Materialized, model.Schema)
+				project.Models = append(project.Models, model)
+			case ".yml", ".yaml":
+				if err := readProperties(fs, path, &project, descriptions); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return Project{}, errors.Wrapf(err, "failed to read models in %s", modelPath)
+		}
+	}
+	for i := range project.Models {
+		project.Models[i].Description = descriptions[project.Models[i].Name]
+	}
+	sort.Slice(project.Models, func(i, j int) bool {
+		return project.Models[i].Name < project.Models[j].Name
+	})
+	return project, nil
+}
+
+func readModel(fs afero.Fs, path, dir string) (Model, error) {
+	fd, err := fs.Open(path)
+	if err != nil {
+		return Model{}, err
+	}
+	defer fd.Close()
+	raw, err := ioutil.ReadAll(fd)
+	if err != nil {
+		return Model{}, err
+	}
+
+	if dir == "." {
+		dir = ""
+	}
+	model := Model{
+		Name: strings.TrimSuffix(filepath.Base(path), ".sql"),
+		Dir:  dir,
+		SQL:  string(raw),
+	}
+	if block := configBlockRegex.FindStringSubmatch(model.SQL); block != nil {
+		for _, arg := range configArgRegex.FindAllStringSubmatch(block[1], -1) {
+			switch arg[1] {
+			case "materialized":
+				model.Materialized = arg[2]
+			case "schema":
+				model.Schema = arg[2]
+			case "alias":
+				model.Alias = arg[2]
+			}
+		}
+		model.SQL = strings.TrimLeft(configBlockRegex.ReplaceAllString(model.SQL, ""), "\n")
+	}
+
+	seen := map[string]bool{}
+	for _, ref := range refRegex.FindAllStringSubmatch(model.SQL, -1) {
+		name := ref[1]
+		if ref[2] != "" {
+			// ref of a model of another package
+			name = ref[2]
+		}
+		if !seen[name] {
+			seen[name] = true
+			model.Refs = append(model.Refs, name)
+		}
+	}
+	sort.Strings(model.Refs)
+	return model, nil
+}
+
+func readProperties(fs afero.Fs, path string, project *Project, descriptions map[string]string) error {
+	raw, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return err
+	}
+	var props propertiesFile
+	if err := yaml.Unmarshal(raw, &props); err != nil {
+		return errors.Wrapf(err, "failed to parse %s", path)
+	}
+	for _, model := range props.Models {
+		descriptions[model.Name] = model.Description
+	}
+	for _, source := range props.Sources {
+		tables, ok := project.Sources[source.Name]
+		if !ok {
+			tables = map[string]SourceTable{}
+			project.Sources[source.Name] = tables
+		}
+		schema := source.Schema
+		if schema == "" {
+			// schema of a source defaults to its name
+			schema = source.Name
+		}
+		for _, table := range source.Tables {
+			identifier := table.Identifier
+			if identifier == "" {
+				identifier = table.Name
+			}
+			tables[table.Name] = SourceTable{
+				Database:   source.Database,
+				Schema:     schema,
+				Identifier: identifier,
+			}
+		}
+	}
+	return nil
+}
+
+// configOfDir resolves materialization and schema of a model from configs of
+// directories in models of dbt_project.yml, configs of model itself take
+// precedence over ones of its directories and ones of inner directories
+// over outer
+func configOfDir(models map[string]interface{}, projectName, dir, materialized, schema string) (string, string) {
+	var dirMaterialized, dirSchema string
+	current, _ := models[projectName].(map[interface{}]interface{})
+	segments := []string{}
+	if dir != "" {
+		segments = strings.Split(filepath.ToSlash(dir), "/")
+	}
+	for i := 0; current != nil; i++ {
+		for key, value := range current {
+			str, _ := value.(string)
+			switch key {
+			case "materialized", "+materialized":
+				dirMaterialized = str
+			case "schema", "+schema":
+				dirSchema = str
+			}
+		}
+		if i >= len(segments) {
+			break
+		}
+		current, _ = current[segments[i]].(map[interface{}]interface{})
+	}
+
+	if materialized == "" {
+		materialized = dirMaterialized
+	}
+	if materialized == "" {
+		materialized = defaultMaterialized
+	}
+	if schema == "" {
+		schema = dirSchema
+	}
+	return materialized, schema
+}
@@ -0,0 +1,78 @@
+package dbt_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/ext/dbt"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadProject(t *testing.T) {
+	t.Run("should read models with their configs, refs and sources", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "dbt_project.yml", []byte(`
+name: shop
+model-paths: ["models"]
+models:
+  shop:
+    +materialized: view
+    marts:
+      +materialized: table
+      finance:
+        +schema: finance
+`), 0644)
+		afero.WriteFile(fs, "models/staging/stg_orders.sql", []byte(`select * from {{ source('raw', 'orders') }}`), 0644)
+		afero.WriteFile(fs, "models/marts/finance/revenue.sql", []byte(`{{ config(materialized='incremental', alias="daily_revenue") }}
+select * from {{ ref('orders') }} join {{ ref("shop", "stg_orders") }} using (order_id)`), 0644)
+		afero.WriteFile(fs, "models/marts/orders.sql", []byte(`select * from {{ ref('stg_orders') }}`), 0644)
+		afero.WriteFile(fs, "models/sources.yml", []byte(`
+version: 2
+sources:
+  - name: raw
+    database: raw-project
+    tables:
+      - name: orders
+        identifier: orders_v2
+models:
+  - name: orders
+    description: orders placed in the shop
+`), 0644)
+
+		project, err := dbt.LoadProject(fs)
+		assert.Nil(t, err)
+		assert.Equal(t, "shop", project.Name)
+		assert.Equal(t, map[string]map[string]dbt.SourceTable{
+			"raw": {"orders": {Database: "raw-project", Schema: "raw", Identifier: "orders_v2"}},
+		}, project.Sources)
+		assert.Equal(t, []dbt.Model{
+			{
+				Name:         "orders",
+				Description:  "orders placed in the shop",
+				Dir:          "marts",
+				SQL:          "select * from {{ ref('stg_orders') }}",
+				Materialized: "table",
+				Refs:         []string{"stg_orders"},
+			},
+			{
+				Name:         "revenue",
+				Dir:          "marts/finance",
+				SQL:          "select * from {{ ref('orders') }} join {{ ref(\"shop\", \"stg_orders\") }} using (order_id)",
+				Materialized: "incremental",
+				Schema:       "finance",
+				Alias:        "daily_revenue",
+				Refs:         []string{"orders", "stg_orders"},
+			},
+			{
+				Name:         "stg_orders",
+				Dir:          "staging",
+				SQL:          "select * from {{ source('raw', 'orders') }}",
+				Materialized: "view",
+			},
+		}, project.Models)
+	})
+	t.Run("should fail without a dbt project file", func(t *testing.T) {
+		_, err := dbt.LoadProject(afero.NewMemMapFs())
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,195 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/ext/notify/payload"
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// Scheme of channels notified by email, e.g. email://oncall@example.io
+	Scheme = "email"
+
+	// OwnerRoute routes notifications to the email of owners of the job,
+	// e.g. email://owner
+	OwnerRoute = "owner"
+
+	// DefaultQueueSize is the number of emails which can wait to be sent,
+	// notifications are rejected once it is full
+	DefaultQueueSize = 100
+
+	DefaultSubjectTemplate = `[{{.Title}}] {{if .Job}}{{.Job}} | {{end}}{{.Project}}{{if .Namespace}}/{{.Namespace}}{{end}}`
+	DefaultBodyTemplate    = `{{.Title}} in project {{.Project}}{{if .Namespace}}, namespace {{.Namespace}}{{end}}
+{{if .Job}}
+Job: {{.Job}}
+Owner: {{.Owner}}
+{{end}}{{range .Keys}}
+{{.}}: {{index $.Values .}}{{end}}
+`
+)
+
+// Config is the smtp server emails are sent through
+type Config struct {
+	Host string
+	Port int
+	// Username and Password authenticate with the server, emails are sent
+	// without authentication if Username is empty
+	Username string
+	Password string
+	// From is the address emails are sent from
+	From string
+}
+
+type message struct {
+	to      []string
+	subject string
+	body    string
+}
+
+// Notifier sends notifications of events as emails through a smtp server,
+// routes are either an email address or OwnerRoute. Emails are sent in the
+// background in the order they are queued
+type Notifier struct {
+	config   Config
+	queue    chan message
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+	wg         sync.WaitGroup
+	errHandler func(error)
+}
+
+func (n *Notifier) Notify(ctx context.Context, attr models.NotifyAttrs) error {
+	to, err := recipients(attr)
+	if err != nil {
+		return err
+	}
+	data := payload.New(attr)
+	projConfig := attr.Namespace.ProjectSpec.Config
+	subject, err := render(projConfig[models.ProjectNotifyEmailSubjectKey], DefaultSubjectTemplate, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render %s", models.ProjectNotifyEmailSubjectKey)
+	}
+	bodyKey := models.ProjectNotifyBodyKey(Scheme)
+	body, err := render(projConfig[bodyKey], DefaultBodyTemplate, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to render %s", bodyKey)
+	}
+
+	select {
+	case n.queue <- message{to: to, subject: strings.TrimSpace(subject), body: body}:
+		return nil
+	default:
+		return errors.Errorf("email queue is full, dropping notification to %s", strings.Join(to, ", "))
+	}
+}
+
+// recipients resolves the route of a notification to addresses, the owner
+// route uses email of owners of the job falling back to its owner if that
+// is an address
+func recipients(attr models.NotifyAttrs) ([]string, error) {
+	route := strings.TrimSpace(attr.Route)
+	if route != OwnerRoute {
+		if !strings.Contains(route, "@") {
+			return nil, errors.Errorf("invalid email route %s, expected an address or %s", route, OwnerRoute)
+		}
+		return []string{route}, nil
+	}
+	var to []string
+	for _, address := range strings.Split(attr.JobSpec.Ownership.Email, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			to = append(to, address)
+		}
+	}
+	if len(to) == 0 && strings.Contains(attr.JobSpec.Owner, "@") {
+		to = append(to, strings.TrimSpace(attr.JobSpec.Owner))
+	}
+	if len(to) == 0 {
+		return nil, errors.Errorf("job %s has no owner email to notify", attr.JobSpec.Name)
+	}
+	return to, nil
+}
+
+// render executes the template set in project config, or the default one if
+// not set
+func render(text, defaultText string, data payload.Data) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		text = defaultText
+	}
+	return payload.Render("email", text, data)
+}
+
+func (n *Notifier) send(msg message) error {
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	if err := n.sendMail(addr, auth, n.config.From, msg.to, buf.Bytes()); err != nil {
+		return errors.Wrapf(err, "failed to send email to %s", strings.Join(msg.to, ", "))
+	}
+	return nil
+}
+
+func (n *Notifier) Worker(ctx context.Context) {
+	defer n.wg.Done()
+	for {
+		select {
+		case msg := <-n.queue:
+			if err := n.send(msg); err != nil {
+				n.errHandler(err)
+			}
+		case <-ctx.Done():
+			// queued emails are not dropped when shutting down
+			for {
+				select {
+				case msg := <-n.queue:
+					if err := n.send(msg); err != nil {
+						n.errHandler(err)
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (n *Notifier) Close() error {
+	// drain queue
+	n.wg.Wait()
+	return nil
+}
+
+func NewNotifier(ctx context.Context, config Config, queueSize int, errHandler func(error)) *Notifier {
+	this := &Notifier{
+		config:     config,
+		queue:      make(chan message, queueSize),
+		sendMail:   smtp.SendMail,
+		errHandler: errHandler,
+	}
+
+	this.wg.Add(1)
+	go this.Worker(ctx)
+	return this
+}
@@ -0,0 +1,200 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type sentMail struct {
+	addr string
+	from string
+	to   []string
+	msg  string
+}
+
+type fakeSMTP struct {
+	mu   sync.Mutex
+	sent []sentMail
+	err  error
+}
+
+func (f *fakeSMTP) SendMail(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, sentMail{addr: addr, from: from, to: to, msg: string(msg)})
+	return f.err
+}
+
+func newTestNotifier(ctx context.Context, server *fakeSMTP, queueSize int, errHandler func(error)) *Notifier {
+	notifier := &Notifier{
+		config: Config{
+			Host: "smtp.example.io",
+			Port: 587,
+			From: "optimus@example.io",
+		},
+		queue:      make(chan message, queueSize),
+		sendMail:   server.SendMail,
+		errHandler: errHandler,
+	}
+	notifier.wg.Add(1)
+	go notifier.Worker(ctx)
+	return notifier
+}
+
+func TestEmail(t *testing.T) {
+	eventValues, _ := structpb.NewStruct(
+		map[string]interface{}{
+			"task_id":   "some_task_name",
+			"duration":  "2s",
+			"log_url":   "http://localhost:8081/tree?dag_id=hello_1",
+			"exception": "this much data failed",
+		},
+	)
+	namespaceSpec := models.NamespaceSpec{
+		Name: "game_jam",
+		ProjectSpec: models.ProjectSpec{
+			Name: "foo",
+		},
+	}
+	jobSpec := models.JobSpec{
+		Name:  "foo-job-spec",
+		Owner: "optimus",
+		Ownership: models.JobSpecOwnership{
+			Email: "devs@example.io, leads@example.io",
+		},
+	}
+
+	t.Run("should send email to job owners using default templates", func(t *testing.T) {
+		server := &fakeSMTP{}
+		ctx, cancel := context.WithCancel(context.Background())
+		var sendErrors []error
+		client := newTestNotifier(ctx, server, DefaultQueueSize, func(err error) {
+			sendErrors = append(sendErrors, err)
+		})
+		err := client.Notify(context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobSpec:   jobSpec,
+			JobEvent: models.JobEvent{
+				Type:  models.JobEventTypeFailure,
+				Value: eventValues.GetFields(),
+			},
+			Route: OwnerRoute,
+		})
+		assert.Nil(t, err)
+		cancel()
+		assert.Nil(t, client.Close())
+		assert.Nil(t, sendErrors)
+
+		assert.Len(t, server.sent, 1)
+		sent := server.sent[0]
+		assert.Equal(t, "smtp.example.io:587", sent.addr)
+		assert.Equal(t, "optimus@example.io", sent.from)
+		assert.Equal(t, []string{"devs@example.io", "leads@example.io"}, sent.to)
+		assert.Contains(t, sent.msg, "To: devs@example.io, leads@example.io\r\n")
+		assert.Contains(t, sent.msg, "Subject: [Job Failure] foo-job-spec | foo/game_jam\r\n")
+		assert.Contains(t, sent.msg, "Job: foo-job-spec\r\n")
+		assert.Contains(t, sent.msg, "exception: this much data failed\r\n")
+	})
+	t.Run("should fall back to owner of the job if it is an email", func(t *testing.T) {
+		to, err := recipients(models.NotifyAttrs{
+			JobSpec: models.JobSpec{Name: "foo-job-spec", Owner: "dev@example.io"},
+			Route:   OwnerRoute,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"dev@example.io"}, to)
+
+		_, err = recipients(models.NotifyAttrs{
+			JobSpec: models.JobSpec{Name: "foo-job-spec", Owner: "optimus"},
+			Route:   OwnerRoute,
+		})
+		assert.Equal(t, "job foo-job-spec has no owner email to notify", err.Error())
+	})
+	t.Run("should send email to address in route using templates of the project", func(t *testing.T) {
+		server := &fakeSMTP{}
+		ctx, cancel := context.WithCancel(context.Background())
+		client := newTestNotifier(ctx, server, DefaultQueueSize, func(err error) {})
+		projectNamespace := namespaceSpec
+		projectNamespace.ProjectSpec.Config = map[string]string{
+			models.ProjectNotifyEmailSubjectKey: "{{.Event}} of {{.Job}}",
+			models.ProjectNotifyBodyKey(Scheme): "see {{index .Values \"log_url\"}}",
+		}
+		err := client.Notify(context.Background(), models.NotifyAttrs{
+			Namespace: projectNamespace,
+			JobSpec:   jobSpec,
+			JobEvent: models.JobEvent{
+				Type:  models.JobEventTypeSLAMiss,
+				Value: eventValues.GetFields(),
+			},
+			Route: "oncall@example.io",
+		})
+		assert.Nil(t, err)
+		cancel()
+		client.Close()
+
+		assert.Len(t, server.sent, 1)
+		assert.Equal(t, []string{"oncall@example.io"}, server.sent[0].to)
+		assert.Contains(t, server.sent[0].msg, "Subject: sla_miss of foo-job-spec\r\n")
+		assert.True(t, strings.HasSuffix(server.sent[0].msg, "\r\n\r\nsee http://localhost:8081/tree?dag_id=hello_1"))
+	})
+	t.Run("should fail to notify if template of the project is invalid", func(t *testing.T) {
+		server := &fakeSMTP{}
+		ctx, cancel := context.WithCancel(context.Background())
+		client := newTestNotifier(ctx, server, DefaultQueueSize, func(err error) {})
+		projectNamespace := namespaceSpec
+		projectNamespace.ProjectSpec.Config = map[string]string{
+			models.ProjectNotifyEmailSubjectKey: "{{.Job",
+		}
+		err := client.Notify(context.Background(), models.NotifyAttrs{
+			Namespace: projectNamespace,
+			JobSpec:   jobSpec,
+			JobEvent:  models.JobEvent{Type: models.JobEventTypeFailure},
+			Route:     OwnerRoute,
+		})
+		assert.Contains(t, err.Error(), "failed to render NOTIFY_EMAIL_SUBJECT")
+		cancel()
+		client.Close()
+		assert.Len(t, server.sent, 0)
+	})
+	t.Run("should report errors of sending emails to error handler", func(t *testing.T) {
+		server := &fakeSMTP{err: errors.New("connection refused")}
+		ctx, cancel := context.WithCancel(context.Background())
+		var sendErrors []error
+		client := newTestNotifier(ctx, server, DefaultQueueSize, func(err error) {
+			sendErrors = append(sendErrors, err)
+		})
+		err := client.Notify(context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobSpec:   jobSpec,
+			JobEvent:  models.JobEvent{Type: models.JobEventTypeFailure},
+			Route:     "oncall@example.io",
+		})
+		assert.Nil(t, err)
+		cancel()
+		client.Close()
+		assert.Len(t, sendErrors, 1)
+		assert.Equal(t, "failed to send email to oncall@example.io: connection refused", sendErrors[0].Error())
+	})
+	t.Run("should fail to notify an invalid route", func(t *testing.T) {
+		server := &fakeSMTP{}
+		ctx, cancel := context.WithCancel(context.Background())
+		client := newTestNotifier(ctx, server, DefaultQueueSize, func(err error) {})
+		err := client.Notify(context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobSpec:   jobSpec,
+			JobEvent:  models.JobEvent{Type: models.JobEventTypeFailure},
+			Route:     "#data-alerts",
+		})
+		assert.Equal(t, "invalid email route #data-alerts, expected an address or owner", err.Error())
+		cancel()
+		client.Close()
+	})
+}
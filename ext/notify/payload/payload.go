@@ -0,0 +1,123 @@
+// Package payload renders notifications of job events with go templates
+// projects configure per channel, e.g. to match formats of incident tooling
+// the channel is watched by
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/odpf/optimus/models"
+)
+
+// Titles of events as they are headed in notifications
+var Titles = map[models.JobEventType]string{
+	models.JobEventTypeSLAMiss:         "SLA Breached",
+	models.JobEventTypeFailure:         "Job Failure",
+	models.JobEventTypeSuccess:         "Job Success",
+	models.JobEventTypeAutoHeal:        "Job Auto Heal",
+	models.JobEventTypeDurationAnomaly: "Job Duration Anomaly",
+	models.JobEventTypeHungRun:         "Job Hung Run",
+	models.JobEventTypeReplay:          "Replay",
+	models.JobEventTypeCostBudget:      "Budget",
+	models.JobEventTypeSensorTimeout:   "Job Sensor Timeout",
+}
+
+// Instance is the run of the job an event is of, fields not sent with the
+// event are empty
+type Instance struct {
+	ScheduledAt string
+	TaskID      string
+	Duration    string
+	LogURL      string
+	JobURL      string
+	Exception   string
+	Message     string
+}
+
+// Data is what templates of notifications are executed with. Values are
+// values of the event by their name with Keys being their sorted names
+type Data struct {
+	Project   string
+	Namespace string
+	Job       string
+	Owner     string
+	Ownership models.JobSpecOwnership
+	Labels    map[string]string
+
+	Event    string
+	Title    string
+	Route    string
+	Instance Instance
+
+	Keys   []string
+	Values map[string]interface{}
+}
+
+// New returns template data of a notification
+func New(attr models.NotifyAttrs) Data {
+	data := Data{
+		Project:   attr.Namespace.ProjectSpec.Name,
+		Namespace: attr.Namespace.Name,
+		Job:       attr.JobSpec.Name,
+		Owner:     attr.JobSpec.Owner,
+		Ownership: attr.JobSpec.Ownership,
+		Labels:    attr.JobSpec.Labels,
+		Event:     string(attr.JobEvent.Type),
+		Title:     Titles[attr.JobEvent.Type],
+		Route:     attr.Route,
+		Values:    map[string]interface{}{},
+	}
+	if data.Title == "" {
+		data.Title = data.Event
+	}
+	if status := attr.JobEvent.Value["status"].GetStringValue(); attr.JobEvent.Type == models.JobEventTypeReplay && status != "" {
+		data.Title += " " + strings.Title(status)
+	}
+	for key, value := range attr.JobEvent.Value {
+		data.Keys = append(data.Keys, key)
+		data.Values[key] = value.AsInterface()
+	}
+	sort.Strings(data.Keys)
+
+	value := func(key string) string {
+		return attr.JobEvent.Value[key].GetStringValue()
+	}
+	data.Instance = Instance{
+		ScheduledAt: value("scheduled_at"),
+		TaskID:      value("task_id"),
+		Duration:    value("duration"),
+		LogURL:      value("log_url"),
+		JobURL:      value("job_url"),
+		Exception:   value("exception"),
+		Message:     value("message"),
+	}
+	return data
+}
+
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// json quotes values to embed them in json payloads
+	"json": func(v interface{}) (string, error) {
+		raw, err := json.Marshal(v)
+		return string(raw), err
+	},
+}
+
+// Render executes the template text with data, missing keys of maps are
+// rendered as their zero value
+func Render(name, text string, data Data) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,70 @@
+package payload_test
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/odpf/optimus/ext/notify/payload"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayload(t *testing.T) {
+	eventValues, _ := structpb.NewStruct(
+		map[string]interface{}{
+			"task_id":      "some_task_name",
+			"scheduled_at": "2021-07-12T07:40:00Z",
+			"log_url":      "http://localhost:8081/tree?dag_id=hello_1",
+			"exception":    "this much \"data\" failed",
+			"attempt":      2,
+		},
+	)
+	attr := models.NotifyAttrs{
+		Namespace: models.NamespaceSpec{
+			Name: "game_jam",
+			ProjectSpec: models.ProjectSpec{
+				Name: "foo",
+			},
+		},
+		JobSpec: models.JobSpec{
+			Name:   "foo-job-spec",
+			Owner:  "optimus",
+			Labels: map[string]string{"tier": "critical"},
+			Ownership: models.JobSpecOwnership{
+				Team: "data",
+			},
+		},
+		JobEvent: models.JobEvent{
+			Type:  models.JobEventTypeFailure,
+			Value: eventValues.GetFields(),
+		},
+		Route: "#incidents",
+	}
+
+	t.Run("should render job, instance and event fields", func(t *testing.T) {
+		text, err := payload.Render("test", `{{upper .Labels.tier}} {{.Title}} of {{.Job}} by {{.Ownership.Team}} at {{.Instance.ScheduledAt}} on {{.Instance.TaskID}}, attempt {{.Values.attempt}} to {{.Route}}`,
+			payload.New(attr))
+		assert.Nil(t, err)
+		assert.Equal(t, "CRITICAL Job Failure of foo-job-spec by data at 2021-07-12T07:40:00Z on some_task_name, attempt 2 to #incidents", text)
+	})
+	t.Run("should quote values embedded in json payloads", func(t *testing.T) {
+		text, err := payload.Render("test", `{"summary": {{json .Instance.Exception}}, "source": {{json .Project}}}`, payload.New(attr))
+		assert.Nil(t, err)
+		assert.Equal(t, `{"summary": "this much \"data\" failed", "source": "foo"}`, text)
+	})
+	t.Run("should title replay events with their status", func(t *testing.T) {
+		replayAttr := attr
+		replayAttr.JobEvent = models.JobEvent{
+			Type: models.JobEventTypeReplay,
+			Value: map[string]*structpb.Value{
+				"status": structpb.NewStringValue(models.ReplayStatusSuccess),
+			},
+		}
+		assert.Equal(t, "Replay Success", payload.New(replayAttr).Title)
+	})
+	t.Run("should fail to render an invalid template", func(t *testing.T) {
+		_, err := payload.Render("test", "{{.Job", payload.New(attr))
+		assert.NotNil(t, err)
+	})
+}
@@ -11,15 +11,23 @@ import (
 	"github.com/pkg/errors"
 	api "github.com/slack-go/slack"
 
+	"github.com/odpf/optimus/ext/notify/payload"
 	"github.com/odpf/optimus/models"
 )
 
 const (
 	OAuthTokenSecretName = "NOTIFY_SLACK"
 
+	// Scheme of channels notified on slack, e.g. slack://#data-alerts
+	Scheme = "slack"
+
 	DefaultEventBatchInterval = time.Second * 10
 
 	MaxSLAEventsToProcess = 6
+
+	// MaxReplaysInDigest is the number of replays listed in a digest,
+	// the rest are only counted
+	MaxReplaysInDigest = 20
 )
 
 type Notifier struct {
@@ -27,6 +35,7 @@ type Notifier struct {
 
 	slackUrl      string
 	routeMsgBatch map[route][]event // channelID -> [][][][][]
+	replayDigests map[digestKey]*digest
 	wg            sync.WaitGroup
 	mu            sync.Mutex
 	workerErrChan chan error
@@ -39,6 +48,17 @@ type route struct {
 	authToken  string
 }
 
+// digestKey batches replay events of a project sent to a route
+type digestKey struct {
+	route       route
+	projectName string
+}
+
+type digest struct {
+	dueAt  time.Time
+	events []event
+}
+
 type event struct {
 	authToken     string
 	projectName   string
@@ -46,6 +66,10 @@ type event struct {
 	jobName       string
 	owner         string
 	meta          models.JobEvent
+
+	// text is the event rendered with template of the project, the event
+	// is formatted as blocks if empty
+	text string
 }
 
 func (s *Notifier) Notify(ctx context.Context, attr models.NotifyAttrs) error {
@@ -53,6 +77,21 @@ func (s *Notifier) Notify(ctx context.Context, attr models.NotifyAttrs) error {
 	if !ok {
 		return errors.Errorf("failed to find authentication token of bot required for sending notifications, please register %s secret", OAuthTokenSecretName)
 	}
+	var digestInterval time.Duration
+	if attr.JobEvent.Type == models.JobEventTypeReplay {
+		var err error
+		if digestInterval, err = attr.Namespace.ProjectSpec.ReplayNotifyDigest(); err != nil {
+			return err
+		}
+	}
+	var text string
+	bodyKey := models.ProjectNotifyBodyKey(Scheme)
+	if tmpl := attr.Namespace.ProjectSpec.Config[bodyKey]; strings.TrimSpace(tmpl) != "" {
+		var err error
+		if text, err = payload.Render("slack", tmpl, payload.New(attr)); err != nil {
+			return errors.Wrapf(err, "failed to render %s", bodyKey)
+		}
+	}
 	client := api.New(oauthSecret, api.OptionAPIURL(s.slackUrl))
 
 	var receiverIDs []string
@@ -97,11 +136,14 @@ func (s *Notifier) Notify(ctx context.Context, attr models.NotifyAttrs) error {
 		return errors.Errorf("failed to find notification route %s", attr.Route)
 	}
 
-	s.queueNotification(receiverIDs, oauthSecret, attr)
+	s.queueNotification(receiverIDs, oauthSecret, attr, text, digestInterval)
 	return nil
 }
 
-func (s *Notifier) queueNotification(receiverIDs []string, oauthSecret string, attr models.NotifyAttrs) {
+// queueNotification batches event for receivers till the next batch is sent,
+// or till the digest of project is due if a digest interval is set
+func (s *Notifier) queueNotification(receiverIDs []string, oauthSecret string, attr models.NotifyAttrs, text string,
+	digestInterval time.Duration) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	for _, receiverID := range receiverIDs {
@@ -120,6 +162,15 @@ func (s *Notifier) queueNotification(receiverIDs []string, oauthSecret string, a
 			jobName:       attr.JobSpec.Name,
 			owner:         attr.JobSpec.Owner,
 			meta:          attr.JobEvent,
+			text:          text,
+		}
+		if digestInterval > 0 {
+			key := digestKey{route: rt, projectName: evt.projectName}
+			if _, ok := s.replayDigests[key]; !ok {
+				s.replayDigests[key] = &digest{dueAt: time.Now().Add(digestInterval)}
+			}
+			s.replayDigests[key].events = append(s.replayDigests[key].events, evt)
+			continue
 		}
 		s.routeMsgBatch[rt] = append(s.routeMsgBatch[rt], evt)
 	}
@@ -131,9 +182,19 @@ func buildMessageBlocks(events []event) []api.Block {
 
 	// core details related to event
 	for evtIdx, evt := range events {
+		if evt.text != "" {
+			blocks = append(blocks, api.NewSectionBlock(api.NewTextBlockObject("mrkdwn", evt.text, false, false), nil, nil))
+			if len(events) != evtIdx+1 {
+				blocks = append(blocks, api.NewDividerBlock())
+			}
+			continue
+		}
+
 		fieldSlice := make([]*api.TextBlockObject, 0)
-		fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Job:*\n%s", evt.jobName), false, false))
-		fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Owner:*\n%s", evt.owner), false, false))
+		if evt.jobName != "" {
+			fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Job:*\n%s", evt.jobName), false, false))
+			fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Owner:*\n%s", evt.owner), false, false))
+		}
 
 		switch evt.meta.Type {
 		case models.JobEventTypeSLAMiss:
@@ -178,6 +239,90 @@ func buildMessageBlocks(events []event) []api.Block {
 			if taskID, ok := evt.meta.Value["task_id"]; ok && taskID.GetStringValue() != "" {
 				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Task ID:*\n%s", taskID.GetStringValue()), false, false))
 			}
+		case models.JobEventTypeAutoHeal:
+			heading := api.NewTextBlockObject("plain_text",
+				fmt.Sprintf("[Job] Auto Heal | %s/%s", evt.projectName, evt.namespaceName), true, false)
+			blocks = append(blocks, api.NewHeaderBlock(heading))
+
+			if replayID, ok := evt.meta.Value["replay_id"]; ok && replayID.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Replay ID:*\n%s", replayID.GetStringValue()), false, false))
+			}
+			if startDate, ok := evt.meta.Value["start_date"]; ok && startDate.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Replayed From:*\n%s", startDate.GetStringValue()), false, false))
+			}
+			if endDate, ok := evt.meta.Value["end_date"]; ok && endDate.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Replayed To:*\n%s", endDate.GetStringValue()), false, false))
+			}
+		case models.JobEventTypeReplay:
+			heading := api.NewTextBlockObject("plain_text",
+				fmt.Sprintf("[Replay] %s | %s", strings.Title(evt.meta.Value["status"].GetStringValue()), evt.projectName), true, false)
+			blocks = append(blocks, api.NewHeaderBlock(heading))
+
+			if replayID, ok := evt.meta.Value["replay_id"]; ok && replayID.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Replay ID:*\n%s", replayID.GetStringValue()), false, false))
+			}
+			if startDate, ok := evt.meta.Value["start_date"]; ok && startDate.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Replayed From:*\n%s", startDate.GetStringValue()), false, false))
+			}
+			if endDate, ok := evt.meta.Value["end_date"]; ok && endDate.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Replayed To:*\n%s", endDate.GetStringValue()), false, false))
+			}
+		case models.JobEventTypeCostBudget:
+			heading := api.NewTextBlockObject("plain_text",
+				fmt.Sprintf("[Budget] %d%% Reached | %s", int(evt.meta.Value["threshold"].GetNumberValue()), evt.projectName), true, false)
+			blocks = append(blocks, api.NewHeaderBlock(heading))
+
+			if month, ok := evt.meta.Value["month"]; ok && month.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Month:*\n%s", month.GetStringValue()), false, false))
+			}
+			if spent, ok := evt.meta.Value["spent"]; ok && spent.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Billed:*\n%s", spent.GetStringValue()), false, false))
+			}
+			if budget, ok := evt.meta.Value["budget"]; ok && budget.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Budget:*\n%s", budget.GetStringValue()), false, false))
+			}
+		case models.JobEventTypeDurationAnomaly:
+			heading := api.NewTextBlockObject("plain_text",
+				fmt.Sprintf("[Job] Duration Anomaly | %s/%s", evt.projectName, evt.namespaceName), true, false)
+			blocks = append(blocks, api.NewHeaderBlock(heading))
+
+			if scheduledAt, ok := evt.meta.Value["scheduled_at"]; ok && scheduledAt.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Scheduled At:*\n%s", scheduledAt.GetStringValue()), false, false))
+			}
+			if duration, ok := evt.meta.Value["duration"]; ok && duration.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Duration:*\n%s", duration.GetStringValue()), false, false))
+			}
+			if baseline, ok := evt.meta.Value["baseline"]; ok && baseline.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Usually Within(p95):*\n%s", baseline.GetStringValue()), false, false))
+			}
+		case models.JobEventTypeHungRun:
+			heading := api.NewTextBlockObject("plain_text",
+				fmt.Sprintf("[Job] Hung Run | %s/%s", evt.projectName, evt.namespaceName), true, false)
+			blocks = append(blocks, api.NewHeaderBlock(heading))
+
+			if scheduledAt, ok := evt.meta.Value["scheduled_at"]; ok && scheduledAt.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Scheduled At:*\n%s", scheduledAt.GetStringValue()), false, false))
+			}
+			if heartbeatAt, ok := evt.meta.Value["heartbeat_at"]; ok && heartbeatAt.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Last Heartbeat At:*\n%s", heartbeatAt.GetStringValue()), false, false))
+			}
+			if retried, ok := evt.meta.Value["retried"]; ok {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Retried:*\n%t", retried.GetBoolValue()), false, false))
+			}
+		case models.JobEventTypeSensorTimeout:
+			heading := api.NewTextBlockObject("plain_text",
+				fmt.Sprintf("[Job] Sensor Timeout | %s/%s", evt.projectName, evt.namespaceName), true, false)
+			blocks = append(blocks, api.NewHeaderBlock(heading))
+
+			if scheduledAt, ok := evt.meta.Value["scheduled_at"]; ok && scheduledAt.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Scheduled At:*\n%s", scheduledAt.GetStringValue()), false, false))
+			}
+			if taskID, ok := evt.meta.Value["task_id"]; ok && taskID.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Sensor:*\n%s", taskID.GetStringValue()), false, false))
+			}
+			if timeout, ok := evt.meta.Value["timeout"]; ok && timeout.GetStringValue() != "" {
+				fieldSlice = append(fieldSlice, api.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Waited For:*\n%s", timeout.GetStringValue()), false, false))
+			}
 		default:
 			// unknown event
 			continue
@@ -224,6 +369,71 @@ func buildMessageBlocks(events []event) []api.Block {
 	return blocks
 }
 
+// buildDigestBlocks summarises replay events of a project, a replay is listed
+// once with the latest status it moved to
+func buildDigestBlocks(projectName string, events []event) []api.Block {
+	var replayIDs []string
+	latest := map[string]event{}
+	for _, evt := range events {
+		replayID := evt.meta.Value["replay_id"].GetStringValue()
+		if _, ok := latest[replayID]; !ok {
+			replayIDs = append(replayIDs, replayID)
+		}
+		latest[replayID] = evt
+	}
+
+	counts := map[string]int{}
+	for _, evt := range latest {
+		counts[evt.meta.Value["status"].GetStringValue()]++
+	}
+	var summary []string
+	for _, status := range []string{models.ReplayStatusSuccess, models.ReplayStatusFailed, models.ReplayStatusCancelled,
+		models.ReplayStatusInProgress, models.ReplayStatusAccepted} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("*%d* %s", counts[status], status))
+		}
+	}
+
+	heading := api.NewTextBlockObject("plain_text", fmt.Sprintf("[Replay] Digest | %s", projectName), true, false)
+	blocks := []api.Block{
+		api.NewHeaderBlock(heading),
+		api.NewSectionBlock(api.NewTextBlockObject("mrkdwn",
+			fmt.Sprintf("%d replays changed status: %s", len(replayIDs), strings.Join(summary, ", ")), false, false), nil, nil),
+		api.NewDividerBlock(),
+	}
+	for idx, replayID := range replayIDs {
+		if idx == MaxReplaysInDigest {
+			blocks = append(blocks, api.NewContextBlock("", api.NewTextBlockObject("plain_text",
+				fmt.Sprintf("%d more replays not listed", len(replayIDs)-MaxReplaysInDigest), true, false)))
+			break
+		}
+		evt := latest[replayID]
+		text := fmt.Sprintf("*%s* %s to %s: *%s*\nReplay ID: %s", evt.jobName,
+			evt.meta.Value["start_date"].GetStringValue(), evt.meta.Value["end_date"].GetStringValue(),
+			evt.meta.Value["status"].GetStringValue(), replayID)
+		if message := evt.meta.Value["message"].GetStringValue(); message != "" {
+			text += "\n" + message
+		}
+		blocks = append(blocks, api.NewSectionBlock(api.NewTextBlockObject("mrkdwn", text, false, false), nil, nil))
+	}
+	return blocks
+}
+
+func (s *Notifier) send(rt route, blocks []api.Block, events []event) {
+	client := api.New(rt.authToken, api.OptionAPIURL(s.slackUrl))
+	if _, _, _, err := client.SendMessage(rt.receiverID,
+		api.MsgOptionBlocks(blocks...),
+		api.MsgOptionAsUser(true),
+	); err != nil {
+		cleanedEvents := []event{}
+		for _, ev := range events {
+			ev.authToken = "*redacted*"
+			cleanedEvents = append(cleanedEvents, ev)
+		}
+		s.workerErrChan <- errors.Wrapf(err, "Worker_SendMessageContext: %v", cleanedEvents)
+	}
+}
+
 func (s *Notifier) Worker(ctx context.Context) {
 	defer s.wg.Done()
 	for {
@@ -233,29 +443,18 @@ func (s *Notifier) Worker(ctx context.Context) {
 			if len(events) == 0 {
 				continue
 			}
-			var messageOptions []api.MsgOption
-			messageOptions = append(messageOptions, api.MsgOptionBlocks(buildMessageBlocks(events)...))
-			messageOptions = append(messageOptions, api.MsgOptionAsUser(true))
-
-			client := api.New(route.authToken, api.OptionAPIURL(s.slackUrl))
-			if _, _, _, err := client.SendMessage(route.receiverID,
-				messageOptions...,
-			); err != nil {
-				cleanedEvents := []event{}
-				for _, ev := range events {
-					ev.authToken = "*redacted*"
-					cleanedEvents = append(cleanedEvents, ev)
-				}
-				s.workerErrChan <- errors.Wrapf(err, "Worker_SendMessageContext: %v", cleanedEvents)
-			}
+			s.send(route, buildMessageBlocks(events), events)
 
 			// clear events from map as they are processed
 			s.routeMsgBatch[route] = []event{}
 		}
 		s.mu.Unlock()
+		s.sendDigests(false)
 
 		select {
 		case <-ctx.Done():
+			// digests are not held back when shutting down
+			s.sendDigests(true)
 			close(s.workerErrChan)
 			return
 		default:
@@ -265,6 +464,20 @@ func (s *Notifier) Worker(ctx context.Context) {
 	}
 }
 
+// sendDigests sends replay digests which are due, or all of them if forced
+func (s *Notifier) sendDigests(force bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for key, dg := range s.replayDigests {
+		if !force && now.Before(dg.dueAt) {
+			continue
+		}
+		s.send(key.route, buildDigestBlocks(key.projectName, dg.events), dg.events)
+		delete(s.replayDigests, key)
+	}
+}
+
 func (s *Notifier) Close() error {
 	// drain batches
 	s.wg.Wait()
@@ -275,6 +488,7 @@ func NewNotifier(ctx context.Context, slackUrl string, eventBatchInterval time.D
 	this := &Notifier{
 		slackUrl:           slackUrl,
 		routeMsgBatch:      map[route][]event{},
+		replayDigests:      map[digestKey]*digest{},
 		workerErrChan:      make(chan error, 0),
 		eventBatchInterval: eventBatchInterval,
 	}
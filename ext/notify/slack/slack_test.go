@@ -197,6 +197,136 @@ func TestSlack(t *testing.T) {
 		assert.Nil(t, client.Close())
 		assert.Nil(t, sendErrors)
 	})
+	t.Run("should batch replay notifications of project into a digest", func(t *testing.T) {
+		muxRouter := mux.NewRouter()
+		server := httptest.NewServer(muxRouter)
+		var sent int
+		muxRouter.HandleFunc("/chat.postMessage", func(rw http.ResponseWriter, r *http.Request) {
+			sent++
+			rw.Header().Set("Content-Type", "application/json")
+			response, _ := json.Marshal(struct {
+				SlackResponse api.SlackResponse
+			}{
+				SlackResponse: api.SlackResponse{
+					Ok: true,
+				},
+			})
+			rw.Write(response)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var sendErrors []error
+		client := NewNotifier(
+			ctx,
+			"http://"+server.Listener.Addr().String()+"/",
+			time.Millisecond*50,
+			func(err error) {
+				sendErrors = append(sendErrors, err)
+			},
+		)
+		namespace := models.NamespaceSpec{
+			ProjectSpec: models.ProjectSpec{
+				Name: "foo",
+				Config: map[string]string{
+					models.ProjectReplayNotifyDigestKey: "1h",
+				},
+				Secret: []models.ProjectSecretItem{
+					{
+						Name:  OAuthTokenSecretName,
+						Value: "test-token",
+					},
+				},
+			},
+		}
+		for _, status := range []string{models.ReplayStatusAccepted, models.ReplayStatusSuccess} {
+			eventValues, _ := structpb.NewStruct(map[string]interface{}{
+				"replay_id": "a-replay-id",
+				"status":    status,
+			})
+			err := client.Notify(context.Background(), models.NotifyAttrs{
+				Namespace: namespace,
+				JobSpec: models.JobSpec{
+					Name: "foo-job-spec",
+				},
+				JobEvent: models.JobEvent{
+					Type:  models.JobEventTypeReplay,
+					Value: eventValues.GetFields(),
+				},
+				Route: "#data-replays",
+			})
+			assert.Nil(t, err)
+			// past the batch interval, replay notifications are held till digest is due
+			time.Sleep(time.Millisecond * 100)
+		}
+		assert.Equal(t, 0, sent)
+
+		cancel()
+		assert.Nil(t, client.Close())
+		assert.Nil(t, sendErrors)
+		assert.Equal(t, 1, sent)
+	})
+}
+
+func TestBuildDigestBlocks(t *testing.T) {
+	newEvent := func(jobName, replayID, status, message string) event {
+		eventValues, _ := structpb.NewStruct(map[string]interface{}{
+			"replay_id":  replayID,
+			"status":     status,
+			"start_date": "2021-08-01",
+			"end_date":   "2021-08-02",
+			"message":    message,
+		})
+		return event{
+			projectName: "foo",
+			jobName:     jobName,
+			meta: models.JobEvent{
+				Type:  models.JobEventTypeReplay,
+				Value: eventValues.GetFields(),
+			},
+		}
+	}
+	blocks := buildDigestBlocks("foo", []event{
+		newEvent("orders", "id-1", models.ReplayStatusAccepted, ""),
+		newEvent("revenue", "id-2", models.ReplayStatusAccepted, ""),
+		newEvent("orders", "id-1", models.ReplayStatusSuccess, ""),
+		newEvent("revenue", "id-2", models.ReplayStatusFailed, "failed to clear airflow dag run"),
+	})
+	b, err := json.MarshalIndent(blocks, "", "    ")
+	assert.Nil(t, err)
+	assert.Equal(t, `[
+    {
+        "type": "header",
+        "text": {
+            "type": "plain_text",
+            "text": "[Replay] Digest | foo",
+            "emoji": true
+        }
+    },
+    {
+        "type": "section",
+        "text": {
+            "type": "mrkdwn",
+            "text": "2 replays changed status: *1* success, *1* failed"
+        }
+    },
+    {
+        "type": "divider"
+    },
+    {
+        "type": "section",
+        "text": {
+            "type": "mrkdwn",
+            "text": "*orders* 2021-08-01 to 2021-08-02: *success*\nReplay ID: id-1"
+        }
+    },
+    {
+        "type": "section",
+        "text": {
+            "type": "mrkdwn",
+            "text": "*revenue* 2021-08-01 to 2021-08-02: *failed*\nReplay ID: id-2\nfailed to clear airflow dag run"
+        }
+    }
+]`, string(b))
 }
 
 func TestBuildMessages(t *testing.T) {
@@ -270,6 +400,76 @@ func TestBuildMessages(t *testing.T) {
             }
         ]
     }
+]`,
+		},
+		{
+			name: "should list spend of project without a job for cost_budget",
+			args: args{events: []event{
+				{
+					authToken:   "xx",
+					projectName: "ss",
+					meta: models.JobEvent{
+						Type: models.JobEventTypeCostBudget,
+						Value: map[string]*structpb.Value{
+							"threshold": structpb.NewNumberValue(80),
+							"month":     structpb.NewStringValue("2021-06"),
+							"spent":     structpb.NewStringValue("8.2 TiB"),
+							"budget":    structpb.NewStringValue("10.0 TiB"),
+						},
+					},
+				},
+			}},
+			want: `[
+    {
+        "type": "header",
+        "text": {
+            "type": "plain_text",
+            "text": "[Budget] 80% Reached | ss",
+            "emoji": true
+        }
+    },
+    {
+        "type": "section",
+        "fields": [
+            {
+                "type": "mrkdwn",
+                "text": "*Month:*\n2021-06"
+            },
+            {
+                "type": "mrkdwn",
+                "text": "*Billed:*\n8.2 TiB"
+            },
+            {
+                "type": "mrkdwn",
+                "text": "*Budget:*\n10.0 TiB"
+            }
+        ]
+    }
+]`,
+		},
+		{
+			name: "should send event rendered with template of project as text",
+			args: args{events: []event{
+				{
+					authToken:     "xx",
+					projectName:   "ss",
+					namespaceName: "bb",
+					jobName:       "cc",
+					owner:         "rr",
+					meta: models.JobEvent{
+						Type: models.JobEventTypeFailure,
+					},
+					text: "INCIDENT sev2 cc failed",
+				},
+			}},
+			want: `[
+    {
+        "type": "section",
+        "text": {
+            "type": "mrkdwn",
+            "text": "INCIDENT sev2 cc failed"
+        }
+    }
 ]`,
 		},
 	}
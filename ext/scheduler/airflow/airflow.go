@@ -30,6 +30,7 @@ const (
 	baseLibFileName = "__lib.py"
 	dagStatusURL    = "api/experimental/dags/%s/dag_runs"
 	dagRunClearURL  = "clear&dag_id=%s&start_date=%s&end_date=%s"
+	dagPausedURL    = "api/experimental/dags/%s/paused/%t"
 )
 
 type HTTPClient interface {
@@ -73,15 +74,15 @@ func (a *scheduler) Bootstrap(ctx context.Context, proj models.ProjectSpec) erro
 	if !ok {
 		return errors.Errorf("%s config not configured for project %s", models.ProjectStoragePathKey, proj.Name)
 	}
-	storageSecret, ok := proj.Secret.GetByName(models.ProjectSecretStorageKey)
-	if !ok {
-		return errors.Errorf("%s secret not configured for project %s", models.ProjectSecretStorageKey, proj.Name)
-	}
-
 	p, err := url.Parse(storagePath)
 	if err != nil {
 		return err
 	}
+	// dag folders on filesystem are written without credentials
+	storageSecret, ok := proj.Secret.GetByName(models.ProjectSecretStorageKey)
+	if !ok && p.Scheme != "file" {
+		return errors.Errorf("%s secret not configured for project %s", models.ProjectSecretStorageKey, proj.Name)
+	}
 	objectWriter, err := a.objWriterFac.New(ctx, storagePath, storageSecret)
 	if err != nil {
 		return errors.Errorf("object writer failed for %s", proj.Name)
@@ -239,3 +240,92 @@ func (a *scheduler) GetDagRunStatus(ctx context.Context, projSpec models.Project
 
 	return requestedJobStatus, nil
 }
+
+func (a *scheduler) GetInstanceLog(ctx context.Context, projSpec models.ProjectSpec, jobName string, scheduledAt time.Time,
+	instanceType models.InstanceType, instanceName string, attempt int) (models.InstanceLog, error) {
+	return models.InstanceLog{}, errors.Errorf("logs of job runs are not exposed by %s api, use airflow2 scheduler", a.GetName())
+}
+
+func (a *scheduler) IsJobLoaded(ctx context.Context, projSpec models.ProjectSpec, jobName string) (bool, error) {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return false, errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	fetchURL := fmt.Sprintf(fmt.Sprintf("%s/%s", schdHost, dagStatusURL), jobName)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL, nil)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to build http request for %s", fetchURL)
+	}
+	resp, err := a.httpClient.Do(request)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to fetch airflow dag runs from %s", fetchURL)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, errors.Errorf("failed to fetch airflow dag runs from %s: %d", fetchURL, resp.StatusCode)
+}
+
+func (a *scheduler) RunJob(ctx context.Context, projSpec models.ProjectSpec, jobName string, scheduledAt time.Time) error {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	unpauseURL := fmt.Sprintf(fmt.Sprintf("%s/%s", schdHost, dagPausedURL), jobName, false)
+	if err := a.call(ctx, http.MethodGet, unpauseURL, nil); err != nil {
+		return errors.Wrapf(err, "failed to unpause %s", jobName)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"execution_date": scheduledAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	runURL := fmt.Sprintf(fmt.Sprintf("%s/%s", schdHost, dagStatusURL), jobName)
+	if err := a.call(ctx, http.MethodPost, runURL, body); err != nil {
+		return errors.Wrapf(err, "failed to run %s", jobName)
+	}
+	return nil
+}
+
+func (a *scheduler) SetJobPaused(ctx context.Context, projSpec models.ProjectSpec, jobName string, paused bool) error {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	pausedURL := fmt.Sprintf(fmt.Sprintf("%s/%s", schdHost, dagPausedURL), jobName, paused)
+	if err := a.call(ctx, http.MethodGet, pausedURL, nil); err != nil {
+		return errors.Wrapf(err, "failed to set paused of %s to %t", jobName, paused)
+	}
+	return nil
+}
+
+func (a *scheduler) call(ctx context.Context, method, callURL string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, method, callURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to build http request for %s", callURL)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := a.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to call airflow %s", callURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to call airflow %s: %d", callURL, resp.StatusCode)
+	}
+	return nil
+}
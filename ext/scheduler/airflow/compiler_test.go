@@ -17,9 +17,10 @@ var CompiledTemplate []byte
 func TestCompiler(t *testing.T) {
 	execUnit := new(mock.BasePlugin)
 	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
-		Name:       "bq",
-		Image:      "example.io/namespace/image:latest",
-		SecretPath: "/opt/optimus/secrets/auth.json",
+		Name:          "bq",
+		PluginVersion: "1.0.0",
+		Image:         "example.io/namespace/image:latest",
+		SecretPath:    "/opt/optimus/secrets/auth.json",
 	}, nil)
 
 	transporterHook := "transporter"
@@ -135,9 +136,10 @@ func TestCompiler(t *testing.T) {
 			DependsOnPast: false,
 		},
 		Schedule: models.JobSpecSchedule{
-			StartDate: time.Date(2000, 11, 11, 0, 0, 0, 0, time.UTC),
-			EndDate:   &scheduleEndDate,
-			Interval:  "* * * * *",
+			StartDate:     time.Date(2000, 11, 11, 0, 0, 0, 0, time.UTC),
+			EndDate:       &scheduleEndDate,
+			Interval:      "* * * * *",
+			MaxActiveRuns: 2,
 		},
 		Task: models.JobSpecTask{
 			Unit:     &models.Plugin{Base: execUnit},
@@ -151,7 +153,9 @@ func TestCompiler(t *testing.T) {
 		Dependencies: map[string]models.JobSpecDependency{
 			// we'll add resolved dependencies
 			"destination1": {Job: &depSpecIntra, Project: &projSpec, Type: models.JobSpecDependencyTypeIntra},
-			"destination2": {Job: &depSpecInter, Project: &externalProjSpec, Type: models.JobSpecDependencyTypeInter},
+			"destination2": {Job: &depSpecInter, Project: &externalProjSpec, Type: models.JobSpecDependencyTypeInter,
+				Sensor:    models.JobSpecDependencySensor{Timeout: 6 * time.Hour, PokeInterval: 10 * time.Minute},
+				Freshness: models.JobSpecDependencyFreshness{Enabled: true, Tolerance: time.Hour}},
 		},
 		Assets: *models.JobAssets{}.New(
 			[]models.JobSpecAsset{
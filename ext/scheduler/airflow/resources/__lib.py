@@ -12,7 +12,7 @@ import requests
 from airflow.configuration import conf
 from airflow.contrib.operators.kubernetes_pod_operator import \
     KubernetesPodOperator
-from airflow.exceptions import AirflowException
+from airflow.exceptions import AirflowException, AirflowSensorTimeout
 from airflow.hooks.base_hook import BaseHook
 from airflow.hooks.http_hook import HttpHook
 from airflow.kubernetes import kube_client, pod_launcher
@@ -288,6 +288,19 @@ class OptimusAPIClient:
         self._raise_error_if_request_failed(response)
         return response.json()
 
+    def get_dependency_freshness(self, optimus_project: str, optimus_job: str, dependency_project: str,
+                                 dependency_job: str, scheduled_at: str) -> dict:
+        url = '{optimus_host}/dependency-freshness'.format(optimus_host=self.host)
+        response = requests.get(url, params={
+            "project": optimus_project,
+            "job": optimus_job,
+            "dependency_project": dependency_project,
+            "dependency": dependency_job,
+            "scheduled_at": scheduled_at,
+        })
+        self._raise_error_if_request_failed(response)
+        return response.json()
+
     def _raise_error_if_request_failed(self, response):
         if response.status_code != 200:
             log.error("Request to optimus returned non-200 status code. Server response:\n")
@@ -389,6 +402,63 @@ class CrossTenantDependencySensor(BaseSensorOperator):
             return datetime.strptime(timestamp, self.TIMESTAMP_MS_FORMAT)
 
 
+class DependencyFreshnessCheck(BaseOperator):
+    """fails the run before its task starts if destination of a dependency
+    wasn't modified after the window of the run started, as far as optimus
+    can tell from metadata of the datastore of destination"""
+    TIMESTAMP_FORMAT = "%Y-%m-%dT%H:%M:%SZ"
+
+    @apply_defaults
+    def __init__(
+            self,
+            optimus_hostname: str,
+            optimus_project: str,
+            optimus_job: str,
+            dependency_project: str,
+            dependency_job: str,
+            **kwargs) -> None:
+        super().__init__(**kwargs)
+        self.optimus_project = optimus_project
+        self.optimus_job = optimus_job
+        self.dependency_project = dependency_project
+        self.dependency_job = dependency_job
+        self._optimus_client = OptimusAPIClient(optimus_hostname)
+
+    def execute(self, context):
+        # runs are scheduled at the end of their interval
+        scheduled_at = context['next_execution_date'].strftime(self.TIMESTAMP_FORMAT)
+        freshness = self._optimus_client.get_dependency_freshness(self.optimus_project, self.optimus_job,
+                                                                  self.dependency_project, self.dependency_job,
+                                                                  scheduled_at)
+        if not freshness['supported']:
+            self.log.warning("freshness of dependency '{}' of '{}' can't be asserted, "
+                             "its destination isn't tracked by any datastore".format(
+                self.dependency_job, self.dependency_project))
+            return
+        if not freshness['fresh']:
+            raise AirflowException("destination {} of dependency '{}' of '{}' is stale, last modified at {} "
+                                   "before window of the run started at {}".format(
+                freshness.get('destination'), self.dependency_job, self.dependency_project,
+                freshness.get('last_modified_at'), freshness['window_start']))
+        self.log.info("destination {} of dependency '{}' was last modified at {}".format(
+            freshness.get('destination'), self.dependency_job, freshness.get('last_modified_at')))
+
+
+def _datastore_job_ids(dag_id, execution_date) -> List[str]:
+    """ids of datastore jobs like bigquery jobs, tasks of the run push them
+    as datastore_job_ids of their return value for optimus to record cost"""
+    job_ids = []
+    for xcom in XCom.get_many(
+            execution_date,
+            key=XCOM_RETURN_KEY,
+            task_ids=None,
+            dag_ids=dag_id,
+            include_prior_dates=False):
+        if isinstance(xcom.value, dict) and isinstance(xcom.value.get('datastore_job_ids'), list):
+            job_ids.extend(str(job_id) for job_id in xcom.value['datastore_job_ids'])
+    return job_ids
+
+
 def optimus_failure_notify(context):
     params = context.get("params")
     optimus_client = OptimusAPIClient(params["optimus_hostname"])
@@ -428,7 +498,8 @@ def optimus_failure_notify(context):
         "duration": str(context.get('task_instance').duration),
         "message": failure_message,
         "exception": str(context.get('exception')),
-        "scheduled_at": current_execution_date.strftime("%Y-%m-%dT%H:%M:%SZ")
+        "scheduled_at": current_execution_date.strftime("%Y-%m-%dT%H:%M:%SZ"),
+        "datastore_job_ids": _datastore_job_ids(current_dag_id, current_execution_date),
     }
     event = {
         "type": "FAILURE",
@@ -440,6 +511,55 @@ def optimus_failure_notify(context):
     return
 
 
+
+def optimus_success_notify(context):
+    params = context.get("params")
+    optimus_client = OptimusAPIClient(params["optimus_hostname"])
+
+    current_dag_id = context.get('task_instance').dag_id
+    current_execution_date = context.get('execution_date')
+    message = {
+        "run_id": context.get('run_id'),
+        "scheduled_at": current_execution_date.strftime("%Y-%m-%dT%H:%M:%SZ"),
+        "datastore_job_ids": _datastore_job_ids(current_dag_id, current_execution_date),
+    }
+    event = {
+        "type": "SUCCESS",
+        "value": message,
+    }
+    # post event, optimus records the outcome of the run
+    resp = optimus_client.notify_event(params["project_name"], params["namespace"], params["job_name"], event)
+    print("posted event ", params, event, resp)
+    return
+
+def optimus_sensor_failure_notify(context):
+    """a sensor which timed out waiting on its dependency is notified to
+    whoever watches sla misses before the failure of run is"""
+    if isinstance(context.get('exception'), AirflowSensorTimeout) and \
+            int(Variable.get("slamiss_alert", default_var=1)) == 1:
+        params = context.get("params")
+        optimus_client = OptimusAPIClient(params["optimus_hostname"])
+
+        task_instance = context.get('task_instance')
+        current_execution_date = context.get('execution_date')
+        message = {
+            "sensor_timeout": True,
+            "log_url": task_instance.log_url,
+            "task_id": task_instance.task_id,
+            "run_id": context.get('run_id'),
+            "timeout": str(timedelta(seconds=task_instance.task.timeout)),
+            "scheduled_at": current_execution_date.strftime("%Y-%m-%dT%H:%M:%SZ"),
+        }
+        # sensor timeouts are raised as sla misses, optimus tells them apart
+        # with sensor_timeout of value
+        event = {
+            "type": "SLA_MISS",
+            "value": message,
+        }
+        resp = optimus_client.notify_event(params["project_name"], params["namespace"], params["job_name"], event)
+        print("posted event ", params, event, resp)
+    return optimus_failure_notify(context)
+
 def optimus_sla_miss_notify(dag, task_list, blocking_task_list, slas, blocking_tis):
     params = dag.params
     optimus_client = OptimusAPIClient(params["optimus_hostname"])
@@ -1,4 +1,5 @@
 # Code generated by optimus {{.Version}}. DO NOT EDIT.
+# Compiled with task plugin {{.Job.Task.Unit.Info.Name}} version {{.Job.Task.Unit.Info.PluginVersion}}
 
 from typing import Any, Callable, Dict, Optional
 from datetime import datetime, timedelta, timezone
@@ -8,8 +9,10 @@ from airflow.kubernetes.secret import Secret
 from airflow.configuration import conf
 from airflow.utils.weight_rule import WeightRule
 
-from __lib import optimus_failure_notify, optimus_sla_miss_notify, SuperKubernetesPodOperator, \
-    SuperExternalTaskSensor, CrossTenantDependencySensor
+from __lib import optimus_failure_notify, optimus_success_notify, optimus_sla_miss_notify, \
+    optimus_sensor_failure_notify, \
+    SuperKubernetesPodOperator, \
+    SuperExternalTaskSensor, CrossTenantDependencySensor, DependencyFreshnessCheck
 
 SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS = int(Variable.get("sensor_poke_interval_in_secs", default_var=15 * 60))
 SENSOR_DEFAULT_TIMEOUT_IN_SECS = int(Variable.get("sensor_timeout_in_secs", default_var=15 * 60 * 60))
@@ -38,10 +41,39 @@ default_args = {
 dag = DAG(
     dag_id={{.Job.Name | quote}},
     default_args=default_args,
-    schedule_interval={{.Job.Schedule.Interval | quote}},
+    schedule_interval={{ if .Job.Schedule.Interval -}} {{.Job.Schedule.Interval | quote}} {{- else -}} None {{- end }},
     sla_miss_callback=optimus_sla_miss_notify,
+    on_success_callback=optimus_success_notify,
     catchup ={{ if .Job.Behavior.CatchUp }} True{{ else }} False{{ end }}
+{{- if gt .Job.Schedule.MaxActiveRuns 0 }},
+    max_active_runs={{ .Job.Schedule.MaxActiveRuns }}
+{{- end }}
+{{- if .Job.Labels }},
+    tags=[{{ range $i, $tag := .Job.GetLabelsAsTags }}{{ if $i }}, {{ end }}{{ $tag | quote }}{{ end }}]
+{{- end }}
 )
+{{- if .SkipDates }}
+
+from airflow.operators.python_operator import ShortCircuitOperator
+
+# runs of the interval scheduled on these days(UTC) are skipped
+SKIP_DATES = [{{ range $i, $day := .SkipDates }}{{ if $i }}, {{ end }}{{ $day | quote }}{{ end }}]
+
+
+def skip_on_exception_dates(**context):
+    # runs triggered manually, including extra runs of the job, are never skipped
+    if context.get("dag_run") is not None and context["dag_run"].external_trigger:
+        return True
+    return context["next_execution_date"].strftime("%Y-%m-%d") not in SKIP_DATES
+
+
+skip_exception_dates = ShortCircuitOperator(
+    task_id="skip_exception_dates",
+    python_callable=skip_on_exception_dates,
+    provide_context=True,
+    dag=dag
+)
+{{- end }}
 
 {{$baseTaskSchema := .Job.Task.Unit.Info -}}
 {{ if ne $baseTaskSchema.SecretPath "" -}}
@@ -133,8 +165,9 @@ wait_{{$dependency.Job.Name | replace "-" "__dash__" | replace "." "__dot__"}} =
     window_truncate_to = {{$baseWindow.TruncateTo | quote}},
     optimus_hostname = "{{$.Hostname}}",
     task_id = "wait_{{$dependency.Job.Name | trunc 200}}-{{$dependencySchema.Name}}",
-    poke_interval = SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS,
-    timeout = SENSOR_DEFAULT_TIMEOUT_IN_SECS,
+    poke_interval = {{ if gt $dependency.Sensor.PokeInterval.Nanoseconds 0 -}} {{$dependency.Sensor.PokeInterval.Seconds}} {{- else -}} SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS {{- end}},
+    timeout = {{ if gt $dependency.Sensor.Timeout.Nanoseconds 0 -}} {{$dependency.Sensor.Timeout.Seconds}} {{- else -}} SENSOR_DEFAULT_TIMEOUT_IN_SECS {{- end}},
+    on_failure_callback = optimus_sensor_failure_notify,
     dag=dag
 )
 {{- end -}}
@@ -144,12 +177,25 @@ wait_{{$dependency.Job.Name | replace "-" "__dash__" | replace "." "__dot__"}} =
     optimus_hostname="{{$.Hostname}}",
     optimus_project="{{$dependency.Project.Name}}",
     optimus_job="{{$dependency.Job.Name}}",
-    poke_interval=SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS,
-    timeout=SENSOR_DEFAULT_TIMEOUT_IN_SECS,
+    poke_interval={{ if gt $dependency.Sensor.PokeInterval.Nanoseconds 0 -}} {{$dependency.Sensor.PokeInterval.Seconds}} {{- else -}} SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS {{- end}},
+    timeout={{ if gt $dependency.Sensor.Timeout.Nanoseconds 0 -}} {{$dependency.Sensor.Timeout.Seconds}} {{- else -}} SENSOR_DEFAULT_TIMEOUT_IN_SECS {{- end}},
+    on_failure_callback=optimus_sensor_failure_notify,
     task_id="wait_{{$dependency.Job.Name | trunc 200}}-{{$dependencySchema.Name}}",
     dag=dag
 )
 {{- end -}}
+
+{{- if $dependency.Freshness.Enabled }}
+check_freshness_{{$dependency.Job.Name | replace "-" "__dash__" | replace "." "__dot__"}} = DependencyFreshnessCheck(
+    optimus_hostname="{{$.Hostname}}",
+    optimus_project="{{$.Namespace.ProjectSpec.Name}}",
+    optimus_job="{{$.Job.Name}}",
+    dependency_project="{{$dependency.Project.Name}}",
+    dependency_job="{{$dependency.Job.Name}}",
+    task_id="check_freshness_{{$dependency.Job.Name | trunc 200}}",
+    dag=dag
+)
+{{- end -}}
 {{- end}}
 
 # arrange inter task dependencies
@@ -157,7 +203,11 @@ wait_{{$dependency.Job.Name | replace "-" "__dash__" | replace "." "__dot__"}} =
 
 # upstream sensors -> base transformation task
 {{- range $i, $t := $.Job.Dependencies }}
+{{- if $t.Freshness.Enabled }}
+wait_{{ $t.Job.Name | replace "-" "__dash__" | replace "." "__dot__" }} >> check_freshness_{{ $t.Job.Name | replace "-" "__dash__" | replace "." "__dot__" }} >> transformation_{{$baseTaskSchema.Name | replace "-" "__dash__" | replace "." "__dot__"}}
+{{- else }}
 wait_{{ $t.Job.Name | replace "-" "__dash__" | replace "." "__dot__" }} >> transformation_{{$baseTaskSchema.Name | replace "-" "__dash__" | replace "." "__dot__"}}
+{{- end }}
 {{- end}}
 
 # set inter-dependencies between task and hooks
@@ -198,4 +248,18 @@ hook_{{$hookSchema.Name | replace "-" "__dash__"}} >> [
 
 {{- end -}}
 
-{{- end -}}
\ No newline at end of file
+{{- end -}}
+{{- if .SkipDates }}
+
+# skip runs on exception dates before any task of the job runs
+skip_exception_dates >> transformation_{{$baseTaskSchema.Name | replace "-" "__dash__" | replace "." "__dot__"}}
+{{- range $_, $t := $.Job.Dependencies }}
+skip_exception_dates >> wait_{{ $t.Job.Name | replace "-" "__dash__" | replace "." "__dot__" }}
+{{- end }}
+{{- range $_, $task := .Job.Hooks }}
+{{- $hookSchema := $task.Unit.Info }}
+{{- if eq $hookSchema.HookType $.HookTypePre }}
+skip_exception_dates >> hook_{{$hookSchema.Name | replace "-" "__dash__"}}
+{{- end }}
+{{- end }}
+{{ end -}}
\ No newline at end of file
@@ -32,7 +32,24 @@ const (
 	dagStatusUrl      = "api/v1/dags/%s/dagRuns?limit=99999"
 	dagStatusBatchUrl = "api/v1/dags/~/dagRuns/list"
 	dagRunClearURL    = "api/v1/dags/%s/clearTaskInstances"
+	dagRunListURL     = "api/v1/dags/%s/dagRuns?execution_date_gte=%s&execution_date_lte=%s"
+	taskInstanceURL   = "api/v1/dags/%s/dagRuns/%s/taskInstances/%s"
+	taskLogURL        = "api/v1/dags/%s/dagRuns/%s/taskInstances/%s/logs/%d?full_content=true"
+	dagURL            = "api/v1/dags/%s"
+	dagPauseURL       = "api/v1/dags/%s?update_mask=is_paused"
+	dagRunCreateURL   = "api/v1/dags/%s/dagRuns"
+	dagTasksURL       = "api/v1/dags/%s/tasks"
+	taskStateURL      = "api/v1/dags/%s/updateTaskInstancesState"
+	healthURL         = "health"
+	importErrorsURL   = "api/v1/importErrors?limit=%d&offset=%d"
 	airflowDateFormat = "2006-01-02T15:04:05+00:00"
+
+	// sensors of upstream jobs are compiled as tasks named with the prefix
+	// followed by name of the job, truncated to the limit
+	sensorTaskPrefix   = "wait_"
+	sensorJobNameLimit = 200
+	// most dag runs airflow lists in a page by default
+	dagRunPageLimit = 100
 )
 
 type HttpClient interface {
@@ -76,15 +93,15 @@ func (a *scheduler) Bootstrap(ctx context.Context, proj models.ProjectSpec) erro
 	if !ok {
 		return errors.Errorf("%s config not configured for project %s", models.ProjectStoragePathKey, proj.Name)
 	}
-	storageSecret, ok := proj.Secret.GetByName(models.ProjectSecretStorageKey)
-	if !ok {
-		return errors.Errorf("%s secret not configured for project %s", models.ProjectSecretStorageKey, proj.Name)
-	}
-
 	p, err := url.Parse(storagePath)
 	if err != nil {
 		return err
 	}
+	// dag folders on filesystem are written without credentials
+	storageSecret, ok := proj.Secret.GetByName(models.ProjectSecretStorageKey)
+	if !ok && p.Scheme != "file" {
+		return errors.Errorf("%s secret not configured for project %s", models.ProjectSecretStorageKey, proj.Name)
+	}
 	objectWriter, err := a.objWriterFac.New(ctx, storagePath, storageSecret)
 	if err != nil {
 		return errors.Errorf("object writer failed for %s", proj.Name)
@@ -276,6 +293,342 @@ func (a *scheduler) GetDagRunStatus(ctx context.Context, projSpec models.Project
 	return jobStatus, nil
 }
 
+func (a *scheduler) GetInstanceLog(ctx context.Context, projSpec models.ProjectSpec, jobName string, scheduledAt time.Time,
+	instanceType models.InstanceType, instanceName string, attempt int) (models.InstanceLog, error) {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return models.InstanceLog{}, errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	authToken, ok := projSpec.Secret.GetByName(models.ProjectSchedulerAuth)
+	if !ok {
+		return models.InstanceLog{}, errors.Errorf("%s secret not configured for project %s", models.ProjectSchedulerAuth, projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	// hooks are scheduled as separate tasks prefixed with hook_
+	taskID := instanceName
+	if instanceType == models.InstanceTypeHook {
+		taskID = fmt.Sprintf("hook_%s", instanceName)
+	}
+
+	executionDate := url.QueryEscape(scheduledAt.UTC().Format(airflowDateFormat))
+	var dagRuns struct {
+		DagRuns []struct {
+			DagRunID string `json:"dag_run_id"`
+		} `json:"dag_runs"`
+	}
+	if err := a.getJSON(ctx, fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(dagRunListURL, jobName, executionDate, executionDate)),
+		authToken, &dagRuns); err != nil {
+		return models.InstanceLog{}, err
+	}
+	if len(dagRuns.DagRuns) == 0 {
+		return models.InstanceLog{}, errors.Errorf("no run of %s scheduled at %s", jobName, scheduledAt.UTC().Format(airflowDateFormat))
+	}
+	dagRunID := url.PathEscape(dagRuns.DagRuns[0].DagRunID)
+
+	if attempt <= 0 {
+		var taskInstance struct {
+			TryNumber int `json:"try_number"`
+		}
+		if err := a.getJSON(ctx, fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(taskInstanceURL, jobName, dagRunID, taskID)),
+			authToken, &taskInstance); err != nil {
+			return models.InstanceLog{}, err
+		}
+		attempt = taskInstance.TryNumber
+		if attempt <= 0 {
+			attempt = 1
+		}
+	}
+
+	fetchURL := fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(taskLogURL, jobName, dagRunID, taskID, attempt))
+	body, err := a.get(ctx, fetchURL, authToken, "text/plain")
+	if err != nil {
+		return models.InstanceLog{}, err
+	}
+	return models.InstanceLog{
+		JobName:      jobName,
+		ScheduledAt:  scheduledAt,
+		InstanceType: instanceType,
+		InstanceName: instanceName,
+		Attempt:      attempt,
+		Content:      string(body),
+	}, nil
+}
+
+func (a *scheduler) IsJobLoaded(ctx context.Context, projSpec models.ProjectSpec, jobName string) (bool, error) {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return false, errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	authToken, ok := projSpec.Secret.GetByName(models.ProjectSchedulerAuth)
+	if !ok {
+		return false, errors.Errorf("%s secret not configured for project %s", models.ProjectSchedulerAuth, projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	fetchURL := fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(dagURL, jobName))
+	status, err := a.send(ctx, http.MethodGet, fetchURL, authToken, nil)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, errors.Errorf("failed to fetch from airflow %s: %d", fetchURL, status)
+}
+
+func (a *scheduler) RunJob(ctx context.Context, projSpec models.ProjectSpec, jobName string, scheduledAt time.Time) error {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	authToken, ok := projSpec.Secret.GetByName(models.ProjectSchedulerAuth)
+	if !ok {
+		return errors.Errorf("%s secret not configured for project %s", models.ProjectSchedulerAuth, projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	unpauseURL := fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(dagPauseURL, jobName))
+	status, err := a.send(ctx, http.MethodPatch, unpauseURL, authToken, []byte(`{"is_paused": false}`))
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errors.Errorf("failed to unpause %s at %s: %d", jobName, unpauseURL, status)
+	}
+
+	runURL := fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(dagRunCreateURL, jobName))
+	status, err = a.send(ctx, http.MethodPost, runURL, authToken,
+		[]byte(fmt.Sprintf(`{"execution_date": "%s"}`, scheduledAt.UTC().Format(airflowDateFormat))))
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errors.Errorf("failed to run %s at %s: %d", jobName, runURL, status)
+	}
+	return nil
+}
+
+func (a *scheduler) SetJobPaused(ctx context.Context, projSpec models.ProjectSpec, jobName string, paused bool) error {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	authToken, ok := projSpec.Secret.GetByName(models.ProjectSchedulerAuth)
+	if !ok {
+		return errors.Errorf("%s secret not configured for project %s", models.ProjectSchedulerAuth, projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	pauseURL := fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(dagPauseURL, jobName))
+	status, err := a.send(ctx, http.MethodPatch, pauseURL, authToken, []byte(fmt.Sprintf(`{"is_paused": %t}`, paused)))
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return errors.Errorf("failed to set paused of %s to %t at %s: %d", jobName, paused, pauseURL, status)
+	}
+	return nil
+}
+
+// MarkSensorsSuccess sets state of sensor tasks of runs between start and end
+// dates to success, a running sensor is stopped by airflow. Sensors are
+// compiled as wait_<job>-<task of job> tasks, the ones waiting for jobs in
+// except are left as they are
+func (a *scheduler) MarkSensorsSuccess(ctx context.Context, projSpec models.ProjectSpec, jobName string, startDate,
+	endDate time.Time, except []string) error {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	authToken, ok := projSpec.Secret.GetByName(models.ProjectSchedulerAuth)
+	if !ok {
+		return errors.Errorf("%s secret not configured for project %s", models.ProjectSchedulerAuth, projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	var dagTasks struct {
+		Tasks []struct {
+			TaskID string `json:"task_id"`
+		} `json:"tasks"`
+	}
+	if err := a.getJSON(ctx, fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(dagTasksURL, jobName)), authToken, &dagTasks); err != nil {
+		return err
+	}
+	keep := map[string]bool{}
+	for _, name := range except {
+		if len(name) > sensorJobNameLimit {
+			name = name[:sensorJobNameLimit]
+		}
+		keep[name] = true
+	}
+	var sensors []string
+	for _, task := range dagTasks.Tasks {
+		if !strings.HasPrefix(task.TaskID, sensorTaskPrefix) {
+			continue
+		}
+		waitsFor := strings.TrimPrefix(task.TaskID, sensorTaskPrefix)
+		if idx := strings.LastIndex(waitsFor, "-"); idx >= 0 {
+			waitsFor = waitsFor[:idx]
+		}
+		if !keep[waitsFor] {
+			sensors = append(sensors, task.TaskID)
+		}
+	}
+	if len(sensors) == 0 {
+		return nil
+	}
+
+	runs, err := a.GetDagRunStatus(ctx, projSpec, jobName, startDate, endDate, dagRunPageLimit)
+	if err != nil {
+		return err
+	}
+	postURL := fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(taskStateURL, jobName))
+	for _, run := range runs {
+		for _, sensor := range sensors {
+			body, err := json.Marshal(map[string]interface{}{
+				"dry_run":            false,
+				"task_id":            sensor,
+				"execution_date":     run.ScheduledAt.UTC().Format(airflowDateFormat),
+				"include_upstream":   false,
+				"include_downstream": false,
+				"include_future":     false,
+				"include_past":       false,
+				"new_state":          "success",
+			})
+			if err != nil {
+				return err
+			}
+			status, err := a.send(ctx, http.MethodPost, postURL, authToken, body)
+			if err != nil {
+				return err
+			}
+			if status != http.StatusOK {
+				return errors.Errorf("failed to mark %s of run at %s as succeeded at %s: %d", sensor,
+					run.ScheduledAt.UTC().Format(airflowDateFormat), postURL, status)
+			}
+		}
+	}
+	return nil
+}
+
+// HealthCheck calls health endpoint of airflow, it doesn't require
+// authentication so projects without scheduler secret can be checked too
+func (a *scheduler) HealthCheck(ctx context.Context, projSpec models.ProjectSpec) error {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	fetchURL := fmt.Sprintf("%s/%s", schdHost, healthURL)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to build http request for %s", fetchURL)
+	}
+	resp, err := a.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrapf(err, "failed to reach airflow %s", fetchURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("airflow %s is unhealthy: %d", fetchURL, resp.StatusCode)
+	}
+	return nil
+}
+
+// GetJobLoadErrors lists import errors of dag files airflow failed to parse,
+// dag files are named after their jobs
+func (a *scheduler) GetJobLoadErrors(ctx context.Context, projSpec models.ProjectSpec) (map[string]string, error) {
+	schdHost, ok := projSpec.Config[models.ProjectSchedulerHost]
+	if !ok {
+		return nil, errors.Errorf("scheduler host not set for %s", projSpec.Name)
+	}
+	authToken, ok := projSpec.Secret.GetByName(models.ProjectSchedulerAuth)
+	if !ok {
+		return nil, errors.Errorf("%s secret not configured for project %s", models.ProjectSchedulerAuth, projSpec.Name)
+	}
+	schdHost = strings.Trim(schdHost, "/")
+
+	loadErrors := map[string]string{}
+	for offset := 0; ; offset += dagRunPageLimit {
+		var page struct {
+			ImportErrors []struct {
+				Filename   string `json:"filename"`
+				StackTrace string `json:"stack_trace"`
+			} `json:"import_errors"`
+			TotalEntries int `json:"total_entries"`
+		}
+		fetchURL := fmt.Sprintf("%s/%s", schdHost, fmt.Sprintf(importErrorsURL, dagRunPageLimit, offset))
+		if err := a.getJSON(ctx, fetchURL, authToken, &page); err != nil {
+			return nil, err
+		}
+		for _, importError := range page.ImportErrors {
+			fileName := filepath.Base(importError.Filename)
+			jobName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+			loadErrors[jobName] = importError.StackTrace
+		}
+		if len(page.ImportErrors) == 0 || offset+len(page.ImportErrors) >= page.TotalEntries {
+			return loadErrors, nil
+		}
+	}
+}
+
+// send makes a json request to airflow and returns the status of response
+func (a *scheduler) send(ctx context.Context, method, callURL, authToken string, body []byte) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, method, callURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to build http request for %s", callURL)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(authToken))))
+
+	resp, err := a.httpClient.Do(request)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to call airflow %s", callURL)
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (a *scheduler) getJSON(ctx context.Context, fetchURL, authToken string, v interface{}) error {
+	body, err := a.get(ctx, fetchURL, authToken, "application/json")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return errors.Wrapf(err, "json error: %s", string(body))
+	}
+	return nil
+}
+
+func (a *scheduler) get(ctx context.Context, fetchURL, authToken, accept string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build http request for %s", fetchURL)
+	}
+	request.Header.Set("Accept", accept)
+	request.Header.Set("Authorization", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(authToken))))
+
+	resp, err := a.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch from airflow %s", fetchURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch from airflow %s: %d", fetchURL, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read airflow response")
+	}
+	return body, nil
+}
+
 func toJobStatus(dagRuns []map[string]interface{}, jobName string) ([]models.JobStatus, error) {
 	var jobStatus []models.JobStatus
 	for _, status := range dagRuns {
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -78,6 +79,31 @@ func TestAirflow2(t *testing.T) {
 			})
 			assert.Nil(t, err)
 		})
+		t.Run("should bootstrap dag folders on filesystem without storage secret", func(t *testing.T) {
+			var out bytes.Buffer
+			wc := new(mocked.WriteCloser)
+			defer wc.AssertExpectations(t)
+			wc.On("Write").Return(&out, nil)
+			wc.On("Close").Return(nil)
+
+			ow := new(mocked.ObjectWriter)
+			defer ow.AssertExpectations(t)
+
+			owf := new(MockedObjectWriterFactory)
+			owf.On("New", ctx, "file:///mnt/airflow", "").Return(ow, nil)
+			defer owf.AssertExpectations(t)
+
+			ow.On("NewWriter", ctx, "", "mnt/airflow/dags/__lib.py").Return(wc, nil)
+
+			air := airflow2.NewScheduler(owf, nil)
+			err := air.Bootstrap(context.Background(), models.ProjectSpec{
+				Name: "proj-name",
+				Config: map[string]string{
+					models.ProjectStoragePathKey: "file:///mnt/airflow",
+				},
+			})
+			assert.Nil(t, err)
+		})
 		t.Run("should fail if no storage config is set", func(t *testing.T) {
 			air := airflow2.NewScheduler(nil, nil)
 			err := air.Bootstrap(ctx, models.ProjectSpec{
@@ -446,4 +472,338 @@ func TestAirflow2(t *testing.T) {
 			assert.Len(t, status, 0)
 		})
 	})
+	t.Run("GetInstanceLog", func(t *testing.T) {
+		host := "http://airflow.example.io"
+		jobName := "sample_select"
+		scheduledAt := time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC)
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: host,
+			},
+			Secret: []models.ProjectSecretItem{
+				{
+					Name:  models.ProjectSchedulerAuth,
+					Value: "admin:admin",
+				},
+			},
+		}
+		respond := func(body string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil
+		}
+
+		t.Run("should return log of the latest attempt of a hook", func(t *testing.T) {
+			var requested []string
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					requested = append(requested, req.URL.String())
+					switch req.URL.Path {
+					case "/api/v1/dags/sample_select/dagRuns":
+						return respond(`{"dag_runs": [{"dag_run_id": "scheduled__2021-05-20T02:00:00+00:00"}], "total_entries": 1}`)
+					case "/api/v1/dags/sample_select/dagRuns/scheduled__2021-05-20T02:00:00+00:00/taskInstances/hook_transporter":
+						return respond(`{"task_id": "hook_transporter", "try_number": 2, "state": "failed"}`)
+					case "/api/v1/dags/sample_select/dagRuns/scheduled__2021-05-20T02:00:00+00:00/taskInstances/hook_transporter/logs/2":
+						assert.Equal(t, "text/plain", req.Header.Get("Accept"))
+						return respond("transporter failed")
+					}
+					return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+				},
+			}
+
+			air := airflow2.NewScheduler(nil, client)
+			log, err := air.GetInstanceLog(ctx, projectSpec, jobName, scheduledAt, models.InstanceTypeHook, "transporter", 0)
+			assert.Nil(t, err)
+			assert.Equal(t, models.InstanceLog{
+				JobName:      jobName,
+				ScheduledAt:  scheduledAt,
+				InstanceType: models.InstanceTypeHook,
+				InstanceName: "transporter",
+				Attempt:      2,
+				Content:      "transporter failed",
+			}, log)
+			assert.Len(t, requested, 3)
+		})
+		t.Run("should fail if job has no run at the time", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return respond(`{"dag_runs": [], "total_entries": 0}`)
+				},
+			}
+
+			air := airflow2.NewScheduler(nil, client)
+			_, err := air.GetInstanceLog(ctx, projectSpec, jobName, scheduledAt, models.InstanceTypeTask, "bq2bq", 1)
+			assert.Equal(t, "no run of sample_select scheduled at 2021-05-20T02:00:00+00:00", err.Error())
+		})
+	})
+	t.Run("IsJobLoaded", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: "http://airflow.example.io",
+			},
+			Secret: []models.ProjectSecretItem{
+				{
+					Name:  models.ProjectSchedulerAuth,
+					Value: "admin:admin",
+				},
+			},
+		}
+		respond := func(status int) *MockHttpClient {
+			return &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "/api/v1/dags/sample_select_canary", req.URL.Path)
+					return &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+				},
+			}
+		}
+
+		t.Run("should tell if dag is loaded", func(t *testing.T) {
+			loaded, err := airflow2.NewScheduler(nil, respond(http.StatusOK)).IsJobLoaded(ctx, projectSpec, "sample_select_canary")
+			assert.Nil(t, err)
+			assert.True(t, loaded)
+
+			loaded, err = airflow2.NewScheduler(nil, respond(http.StatusNotFound)).IsJobLoaded(ctx, projectSpec, "sample_select_canary")
+			assert.Nil(t, err)
+			assert.False(t, loaded)
+		})
+		t.Run("should fail if host fails to return OK", func(t *testing.T) {
+			_, err := airflow2.NewScheduler(nil, respond(http.StatusInternalServerError)).IsJobLoaded(ctx, projectSpec, "sample_select_canary")
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("RunJob", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: "http://airflow.example.io",
+			},
+			Secret: []models.ProjectSecretItem{
+				{
+					Name:  models.ProjectSchedulerAuth,
+					Value: "admin:admin",
+				},
+			},
+		}
+		scheduledAt := time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC)
+
+		t.Run("should unpause and run dag", func(t *testing.T) {
+			var requested []string
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					body, err := ioutil.ReadAll(req.Body)
+					assert.Nil(t, err)
+					requested = append(requested, req.Method+" "+req.URL.Path+" "+string(body))
+					return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+				},
+			}
+
+			err := airflow2.NewScheduler(nil, client).RunJob(ctx, projectSpec, "sample_select_canary", scheduledAt)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				`PATCH /api/v1/dags/sample_select_canary {"is_paused": false}`,
+				`POST /api/v1/dags/sample_select_canary/dagRuns {"execution_date": "2021-05-20T02:00:00+00:00"}`,
+			}, requested)
+		})
+		t.Run("should fail if dag can't be unpaused", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+				},
+			}
+
+			err := airflow2.NewScheduler(nil, client).RunJob(ctx, projectSpec, "sample_select_canary", scheduledAt)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("SetJobPaused", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: "http://airflow.example.io",
+			},
+			Secret: []models.ProjectSecretItem{
+				{
+					Name:  models.ProjectSchedulerAuth,
+					Value: "admin:admin",
+				},
+			},
+		}
+		t.Run("should patch paused state of dag", func(t *testing.T) {
+			var requested []string
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					body, err := ioutil.ReadAll(req.Body)
+					assert.Nil(t, err)
+					requested = append(requested, req.Method+" "+req.URL.String()+" "+string(body))
+					return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+				},
+			}
+
+			err := airflow2.NewScheduler(nil, client).SetJobPaused(ctx, projectSpec, "sample_select", true)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				`PATCH http://airflow.example.io/api/v1/dags/sample_select?update_mask=is_paused {"is_paused": true}`,
+			}, requested)
+		})
+		t.Run("should fail if dag is not found", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+				},
+			}
+
+			err := airflow2.NewScheduler(nil, client).SetJobPaused(ctx, projectSpec, "sample_select", false)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("MarkSensorsSuccess", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: "http://airflow.example.io",
+			},
+			Secret: []models.ProjectSecretItem{
+				{
+					Name:  models.ProjectSchedulerAuth,
+					Value: "admin:admin",
+				},
+			},
+		}
+		startDate := time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC)
+		endDate := time.Date(2021, 5, 21, 2, 0, 0, 0, time.UTC)
+
+		t.Run("should mark sensors of runs as succeeded except the ones waiting for excepted jobs", func(t *testing.T) {
+			var marked []string
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					var respBody string
+					switch req.URL.Path {
+					case "/api/v1/dags/sample_select/tasks":
+						respBody = `{"tasks": [{"task_id": "wait_upstream-job-bq2bq"}, {"task_id": "wait_replayed-job-bq2bq"},
+							{"task_id": "bq2bq"}, {"task_id": "hook_transporter"}]}`
+					case "/api/v1/dags/~/dagRuns/list":
+						respBody = `{"dag_runs": [{"execution_date": "2021-05-20T02:00:00+00:00", "state": "queued"},
+							{"execution_date": "2021-05-21T02:00:00+00:00", "state": "queued"}], "total_entries": 2}`
+					case "/api/v1/dags/sample_select/updateTaskInstancesState":
+						body, err := ioutil.ReadAll(req.Body)
+						assert.Nil(t, err)
+						marked = append(marked, string(body))
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader([]byte(respBody)))}, nil
+				},
+			}
+
+			err := airflow2.NewScheduler(nil, client).MarkSensorsSuccess(ctx, projectSpec, "sample_select", startDate, endDate,
+				[]string{"sample_select", "replayed-job"})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				`{"dry_run":false,"execution_date":"2021-05-20T02:00:00+00:00","include_downstream":false,"include_future":false,"include_past":false,"include_upstream":false,"new_state":"success","task_id":"wait_upstream-job-bq2bq"}`,
+				`{"dry_run":false,"execution_date":"2021-05-21T02:00:00+00:00","include_downstream":false,"include_future":false,"include_past":false,"include_upstream":false,"new_state":"success","task_id":"wait_upstream-job-bq2bq"}`,
+			}, marked)
+		})
+		t.Run("should fail if sensor can't be marked", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					var respBody string
+					statusCode := http.StatusOK
+					switch req.URL.Path {
+					case "/api/v1/dags/sample_select/tasks":
+						respBody = `{"tasks": [{"task_id": "wait_upstream-job-bq2bq"}]}`
+					case "/api/v1/dags/~/dagRuns/list":
+						respBody = `{"dag_runs": [{"execution_date": "2021-05-20T02:00:00+00:00", "state": "queued"}], "total_entries": 1}`
+					default:
+						statusCode = http.StatusForbidden
+					}
+					return &http.Response{StatusCode: statusCode, Body: ioutil.NopCloser(bytes.NewReader([]byte(respBody)))}, nil
+				},
+			}
+
+			err := airflow2.NewScheduler(nil, client).MarkSensorsSuccess(ctx, projectSpec, "sample_select", startDate, endDate, nil)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("HealthCheck", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: "http://airflow.example.io/",
+			},
+		}
+		respond := func(status int) *MockHttpClient {
+			return &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, "http://airflow.example.io/health", req.URL.String())
+					return &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+				},
+			}
+		}
+
+		t.Run("should pass if airflow is healthy", func(t *testing.T) {
+			err := airflow2.NewScheduler(nil, respond(http.StatusOK)).HealthCheck(ctx, projectSpec)
+			assert.Nil(t, err)
+		})
+		t.Run("should fail if airflow is unhealthy", func(t *testing.T) {
+			err := airflow2.NewScheduler(nil, respond(http.StatusServiceUnavailable)).HealthCheck(ctx, projectSpec)
+			assert.NotNil(t, err)
+		})
+		t.Run("should fail if scheduler host is not set", func(t *testing.T) {
+			err := airflow2.NewScheduler(nil, respond(http.StatusOK)).HealthCheck(ctx, models.ProjectSpec{Name: "test-proj"})
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("GetJobLoadErrors", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "test-proj",
+			Config: map[string]string{
+				models.ProjectSchedulerHost: "http://airflow.example.io/",
+			},
+			Secret: models.ProjectSecrets{
+				{
+					Name:  models.ProjectSchedulerAuth,
+					Value: "admin:admin",
+				},
+			},
+		}
+
+		t.Run("should list import errors of all pages by job name", func(t *testing.T) {
+			var fetched []string
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					fetched = append(fetched, req.URL.String())
+					body := `{"import_errors": [], "total_entries": 101}`
+					switch req.URL.Query().Get("offset") {
+					case "0":
+						errs := make([]string, 100)
+						for idx := range errs {
+							errs[idx] = fmt.Sprintf(`{"filename": "/opt/dags/ns/job_%d.py", "stack_trace": "bad"}`, idx)
+						}
+						body = fmt.Sprintf(`{"import_errors": [%s], "total_entries": 101}`, strings.Join(errs, ","))
+					case "100":
+						body = `{"import_errors": [{"filename": "/opt/dags/ns/broken_job.py", "stack_trace": "NameError: name 'Operator' is not defined"}], "total_entries": 101}`
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+				},
+			}
+			loadErrors, err := airflow2.NewScheduler(nil, client).GetJobLoadErrors(ctx, projectSpec)
+			assert.Nil(t, err)
+			assert.Len(t, loadErrors, 101)
+			assert.Equal(t, "NameError: name 'Operator' is not defined", loadErrors["broken_job"])
+			assert.Equal(t, []string{
+				"http://airflow.example.io/api/v1/importErrors?limit=100&offset=0",
+				"http://airflow.example.io/api/v1/importErrors?limit=100&offset=100",
+			}, fetched)
+		})
+		t.Run("should fail if import errors can't be fetched", func(t *testing.T) {
+			client := &MockHttpClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusForbidden, Body: ioutil.NopCloser(bytes.NewReader(nil))}, nil
+				},
+			}
+			_, err := airflow2.NewScheduler(nil, client).GetJobLoadErrors(ctx, projectSpec)
+			assert.NotNil(t, err)
+		})
+	})
 }
@@ -1,4 +1,5 @@
 # Code generated by optimus dev. DO NOT EDIT.
+# Compiled with task plugin bq version 1.0.0
 
 from typing import Any, Callable, Dict, Optional
 from datetime import datetime, timedelta, timezone
@@ -9,8 +10,10 @@ from airflow.configuration import conf
 from airflow.utils.weight_rule import WeightRule
 from kubernetes.client import models as k8s
 
-from __lib import optimus_failure_notify, optimus_sla_miss_notify, SuperKubernetesPodOperator, \
-    SuperExternalTaskSensor, CrossTenantDependencySensor
+from __lib import optimus_failure_notify, optimus_success_notify, optimus_sla_miss_notify, \
+    optimus_sensor_failure_notify, \
+    SuperKubernetesPodOperator, \
+    SuperExternalTaskSensor, CrossTenantDependencySensor, DependencyFreshnessCheck
 
 SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS = int(Variable.get("sensor_poke_interval_in_secs", default_var=15 * 60))
 SENSOR_DEFAULT_TIMEOUT_IN_SECS = int(Variable.get("sensor_timeout_in_secs", default_var=15 * 60 * 60))
@@ -41,7 +44,10 @@ dag = DAG(
     default_args=default_args,
     schedule_interval="* * * * *",
     sla_miss_callback=optimus_sla_miss_notify,
-    catchup = True
+    on_success_callback=optimus_success_notify,
+    catchup = True,
+    max_active_runs=2,
+    tags=["orchestrator:optimus"]
 )
 
 transformation_secret = Secret(
@@ -187,24 +193,35 @@ wait_foo__dash__intra__dash__dep__dash__job = SuperExternalTaskSensor(
     task_id = "wait_foo-intra-dep-job-bq",
     poke_interval = SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS,
     timeout = SENSOR_DEFAULT_TIMEOUT_IN_SECS,
+    on_failure_callback = optimus_sensor_failure_notify,
     dag=dag
 )
 wait_foo__dash__inter__dash__dep__dash__job = CrossTenantDependencySensor(
     optimus_hostname="http://airflow.example.io",
     optimus_project="foo-external-project",
     optimus_job="foo-inter-dep-job",
-    poke_interval=SENSOR_DEFAULT_POKE_INTERVAL_IN_SECS,
-    timeout=SENSOR_DEFAULT_TIMEOUT_IN_SECS,
+    poke_interval=600,
+    timeout=21600,
+    on_failure_callback=optimus_sensor_failure_notify,
     task_id="wait_foo-inter-dep-job-bq",
     dag=dag
 )
+check_freshness_foo__dash__inter__dash__dep__dash__job = DependencyFreshnessCheck(
+    optimus_hostname="http://airflow.example.io",
+    optimus_project="foo-project",
+    optimus_job="foo",
+    dependency_project="foo-external-project",
+    dependency_job="foo-inter-dep-job",
+    task_id="check_freshness_foo-inter-dep-job",
+    dag=dag
+)
 
 # arrange inter task dependencies
 ####################################
 
 # upstream sensors -> base transformation task
 wait_foo__dash__intra__dash__dep__dash__job >> transformation_bq
-wait_foo__dash__inter__dash__dep__dash__job >> transformation_bq
+wait_foo__dash__inter__dash__dep__dash__job >> check_freshness_foo__dash__inter__dash__dep__dash__job >> transformation_bq
 
 # set inter-dependencies between task and hooks
 hook_transporter >> transformation_bq
@@ -0,0 +1,48 @@
+import unittest
+import sys
+sys.path.insert(1, '../resources')
+
+from datetime import datetime
+from airflow.exceptions import AirflowException
+from __lib import DependencyFreshnessCheck
+
+from unittest.mock import Mock
+
+
+class TestDependencyFreshnessCheck(unittest.TestCase):
+    context = {"next_execution_date": datetime(2021, 1, 26, 2, 0, 0)}
+
+    def _check(self, freshness_response):
+        optimus_client_mock = Mock()
+        optimus_client_mock.get_dependency_freshness.return_value = freshness_response
+
+        check = DependencyFreshnessCheck(
+            task_id='task',
+            optimus_hostname="dummy-since-we-are-mocking",
+            optimus_project="g-pilotdata-gl",
+            optimus_job="pilotdata-integration.playground.revenue",
+            dependency_project="g-pilotdata-gl",
+            dependency_job="pilotdata-integration.playground.characters",
+        )
+        check._optimus_client = optimus_client_mock # inject
+        return check, optimus_client_mock
+
+    def test_should_pass_if_destination_is_fresh(self):
+        check, optimus_client_mock = self._check({'destination': 'bigquery://pilotdata-integration:playground.characters',
+                                                  'window_start': '2021-01-25T00:00:00Z', 'supported': True,
+                                                  'last_modified_at': '2021-01-26T01:00:00Z', 'fresh': True})
+        check.execute(self.context)
+        optimus_client_mock.get_dependency_freshness.assert_called_once_with(
+            "g-pilotdata-gl", "pilotdata-integration.playground.revenue",
+            "g-pilotdata-gl", "pilotdata-integration.playground.characters", "2021-01-26T02:00:00Z")
+
+    def test_should_fail_if_destination_is_stale(self):
+        check, _ = self._check({'destination': 'bigquery://pilotdata-integration:playground.characters',
+                                'window_start': '2021-01-25T00:00:00Z', 'supported': True,
+                                'last_modified_at': '2021-01-20T01:00:00Z', 'fresh': False})
+        with self.assertRaises(AirflowException):
+            check.execute(self.context)
+
+    def test_should_pass_if_freshness_is_not_supported(self):
+        check, _ = self._check({'window_start': '2021-01-25T00:00:00Z', 'supported': False, 'fresh': False})
+        check.execute(self.context)
@@ -0,0 +1,119 @@
+package gsm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"github.com/pkg/errors"
+	"google.golang.org/api/option"
+	secretmanagerpb "google.golang.org/genproto/googleapis/cloud/secretmanager/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// project config, defaults to the project of service account
+	ConfigProject = "SECRET_GSM_PROJECT"
+
+	// project secret holding the service account used to access secrets
+	SecretAuth = "SECRET_GSM_AUTH"
+)
+
+// Client accesses the latest version of secrets
+type Client interface {
+	// Access returns value of the latest version of secret, ErrNoSuchSecret if
+	// it doesn't exist
+	Access(ctx context.Context, gcpProject, name string) (string, error)
+	Close() error
+}
+
+type ClientFactory interface {
+	New(ctx context.Context, svcAccount string) (Client, error)
+}
+
+// Backend resolves project secrets from GCP Secret Manager, a secret of
+// the project maps to the secret with same name in configured gcp project
+type Backend struct {
+	ClientFac ClientFactory
+}
+
+func (b *Backend) Name() string {
+	return "gsm"
+}
+
+func (b *Backend) Resolve(ctx context.Context, proj models.ProjectSpec, names []string) (map[string]string, error) {
+	svcAccount, ok := proj.Secret.GetByName(SecretAuth)
+	if !ok || svcAccount == "" {
+		return nil, errors.Errorf("secret %s is required to resolve secrets from %s", SecretAuth, b.Name())
+	}
+	gcpProject := proj.Config[ConfigProject]
+	if gcpProject == "" {
+		var cred struct {
+			ProjectID string `json:"project_id"`
+		}
+		if err := json.Unmarshal([]byte(svcAccount), &cred); err != nil || cred.ProjectID == "" {
+			return nil, errors.Errorf("config %s is required to resolve secrets from %s", ConfigProject, b.Name())
+		}
+		gcpProject = cred.ProjectID
+	}
+
+	client, err := b.ClientFac.New(ctx, svcAccount)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	resolved := map[string]string{}
+	for _, name := range names {
+		value, err := client.Access(ctx, gcpProject, name)
+		if err != nil {
+			if errors.Is(err, models.ErrNoSuchSecret) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to access secret %s", name)
+		}
+		resolved[name] = value
+	}
+	return resolved, nil
+}
+
+type defaultClientFactory struct{}
+
+func (fac *defaultClientFactory) New(ctx context.Context, svcAccount string) (Client, error) {
+	client, err := secretmanager.NewClient(ctx, option.WithCredentialsJSON([]byte(svcAccount)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create secret manager client")
+	}
+	return &secretManagerClient{client: client}, nil
+}
+
+type secretManagerClient struct {
+	client *secretmanager.Client
+}
+
+func (c *secretManagerClient) Access(ctx context.Context, gcpProject, name string) (string, error) {
+	resp, err := c.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/latest", gcpProject, name),
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", models.ErrNoSuchSecret
+		}
+		return "", err
+	}
+	return string(resp.GetPayload().GetData()), nil
+}
+
+func (c *secretManagerClient) Close() error {
+	return c.client.Close()
+}
+
+func NewBackend() *Backend {
+	return &Backend{
+		ClientFac: &defaultClientFactory{},
+	}
+}
@@ -0,0 +1,98 @@
+package gsm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/ext/secret/gsm"
+	"github.com/odpf/optimus/models"
+)
+
+type fakeClient struct {
+	gcpProject string
+	secrets    map[string]string
+	closed     bool
+}
+
+func (c *fakeClient) Access(_ context.Context, gcpProject, name string) (string, error) {
+	c.gcpProject = gcpProject
+	if name == "BROKEN" {
+		return "", errors.New("permission denied")
+	}
+	value, ok := c.secrets[name]
+	if !ok {
+		return "", models.ErrNoSuchSecret
+	}
+	return value, nil
+}
+
+func (c *fakeClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+type fakeClientFactory struct {
+	client *fakeClient
+}
+
+func (fac *fakeClientFactory) New(_ context.Context, _ string) (gsm.Client, error) {
+	return fac.client, nil
+}
+
+func TestBackend(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should resolve latest version of secrets from project of service account", func(t *testing.T) {
+		client := &fakeClient{secrets: map[string]string{"API_KEY": "secret-key"}}
+		backend := &gsm.Backend{ClientFac: &fakeClientFactory{client: client}}
+
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+			Secret: models.ProjectSecrets{
+				{Name: gsm.SecretAuth, Value: `{"type": "service_account", "project_id": "gcp-project"}`},
+			},
+		}
+		resolved, err := backend.Resolve(ctx, projectSpec, []string{"API_KEY", "MISSING"})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"API_KEY": "secret-key"}, resolved)
+		assert.Equal(t, "gcp-project", client.gcpProject)
+		assert.True(t, client.closed)
+	})
+	t.Run("should resolve from configured gcp project", func(t *testing.T) {
+		client := &fakeClient{secrets: map[string]string{"API_KEY": "secret-key"}}
+		backend := &gsm.Backend{ClientFac: &fakeClientFactory{client: client}}
+
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+			Config: map[string]string{
+				gsm.ConfigProject: "secrets-project",
+			},
+			Secret: models.ProjectSecrets{
+				{Name: gsm.SecretAuth, Value: `{"type": "service_account", "project_id": "gcp-project"}`},
+			},
+		}
+		_, err := backend.Resolve(ctx, projectSpec, []string{"API_KEY"})
+		assert.Nil(t, err)
+		assert.Equal(t, "secrets-project", client.gcpProject)
+	})
+	t.Run("should fail if secret can't be accessed", func(t *testing.T) {
+		backend := &gsm.Backend{ClientFac: &fakeClientFactory{client: &fakeClient{}}}
+
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+			Secret: models.ProjectSecrets{
+				{Name: gsm.SecretAuth, Value: `{"type": "service_account", "project_id": "gcp-project"}`},
+			},
+		}
+		_, err := backend.Resolve(ctx, projectSpec, []string{"BROKEN"})
+		assert.Equal(t, "failed to access secret BROKEN: permission denied", err.Error())
+	})
+	t.Run("should fail if service account is not registered", func(t *testing.T) {
+		backend := gsm.NewBackend()
+		_, err := backend.Resolve(ctx, models.ProjectSpec{Name: "a-data-project"}, []string{"API_KEY"})
+		assert.Equal(t, "secret SECRET_GSM_AUTH is required to resolve secrets from gsm", err.Error())
+	})
+}
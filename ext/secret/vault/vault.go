@@ -0,0 +1,102 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// project config
+	ConfigAddress = "SECRET_VAULT_ADDRESS"
+	ConfigMount   = "SECRET_VAULT_MOUNT"
+	ConfigPath    = "SECRET_VAULT_PATH"
+
+	// project secret holding the token used to read from vault
+	SecretToken = "SECRET_VAULT_TOKEN"
+
+	defaultMount = "secret"
+)
+
+// Backend resolves project secrets from a HashiCorp Vault KV v2 secret,
+// every key of the secret at configured path is a secret of the project.
+// Path defaults to optimus/<project name> under the "secret" mount.
+type Backend struct {
+	client *http.Client
+}
+
+func (b *Backend) Name() string {
+	return "vault"
+}
+
+func (b *Backend) Resolve(ctx context.Context, proj models.ProjectSpec, names []string) (map[string]string, error) {
+	address, ok := proj.Config[ConfigAddress]
+	if !ok || address == "" {
+		return nil, errors.Errorf("config %s is required to resolve secrets from %s", ConfigAddress, b.Name())
+	}
+	token, ok := proj.Secret.GetByName(SecretToken)
+	if !ok || token == "" {
+		return nil, errors.Errorf("secret %s is required to resolve secrets from %s", SecretToken, b.Name())
+	}
+	mount := defaultMount
+	if val, ok := proj.Config[ConfigMount]; ok && val != "" {
+		mount = val
+	}
+	path := fmt.Sprintf("optimus/%s", proj.Name)
+	if val, ok := proj.Config[ConfigPath]; ok && val != "" {
+		path = val
+	}
+
+	secretURL := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(address, "/"),
+		strings.Trim(mount, "/"), strings.Trim(path, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read secret from %s", b.Name())
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return map[string]string{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to read secret from %s, status: %d", b.Name(), resp.StatusCode)
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode secret from %s", b.Name())
+	}
+	resolved := map[string]string{}
+	for _, name := range names {
+		if value, ok := secret.Data.Data[name]; ok {
+			resolved[name] = fmt.Sprintf("%v", value)
+		}
+	}
+	return resolved, nil
+}
+
+func NewBackend(client *http.Client) *Backend {
+	return &Backend{
+		client: client,
+	}
+}
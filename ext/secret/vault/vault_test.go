@@ -0,0 +1,89 @@
+package vault_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/ext/secret/vault"
+	"github.com/odpf/optimus/models"
+)
+
+func TestBackend(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should resolve secrets from kv secret of the project", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/secret/data/optimus/a-data-project", r.URL.Path)
+			assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+			w.Write([]byte(`{"data": {"data": {"API_KEY": "secret-key", "PORT": 5432}, "metadata": {"version": 2}}}`))
+		}))
+		defer server.Close()
+
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+			Config: map[string]string{
+				vault.ConfigAddress: server.URL,
+			},
+			Secret: models.ProjectSecrets{
+				{Name: vault.SecretToken, Value: "vault-token"},
+			},
+		}
+		resolved, err := vault.NewBackend(server.Client()).Resolve(ctx, projectSpec, []string{"API_KEY", "PORT", "MISSING"})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"API_KEY": "secret-key", "PORT": "5432"}, resolved)
+	})
+	t.Run("should read from configured mount and path", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/kv/data/teams/data", r.URL.Path)
+			w.Write([]byte(`{"data": {"data": {"API_KEY": "secret-key"}}}`))
+		}))
+		defer server.Close()
+
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+			Config: map[string]string{
+				vault.ConfigAddress: server.URL + "/",
+				vault.ConfigMount:   "kv",
+				vault.ConfigPath:    "/teams/data",
+			},
+			Secret: models.ProjectSecrets{
+				{Name: vault.SecretToken, Value: "vault-token"},
+			},
+		}
+		resolved, err := vault.NewBackend(server.Client()).Resolve(ctx, projectSpec, []string{"API_KEY"})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"API_KEY": "secret-key"}, resolved)
+	})
+	t.Run("should fail if vault denies the request", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+			Config: map[string]string{
+				vault.ConfigAddress: server.URL,
+			},
+			Secret: models.ProjectSecrets{
+				{Name: vault.SecretToken, Value: "vault-token"},
+			},
+		}
+		_, err := vault.NewBackend(server.Client()).Resolve(ctx, projectSpec, []string{"API_KEY"})
+		assert.Equal(t, "failed to read secret from vault, status: 403", err.Error())
+	})
+	t.Run("should fail if token is not registered", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+			Config: map[string]string{
+				vault.ConfigAddress: "http://vault.example.io",
+			},
+		}
+		_, err := vault.NewBackend(http.DefaultClient).Resolve(ctx, projectSpec, []string{"API_KEY"})
+		assert.Equal(t, "secret SECRET_VAULT_TOKEN is required to resolve secrets from vault", err.Error())
+	})
+}
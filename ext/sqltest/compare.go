@@ -0,0 +1,149 @@
+package sqltest
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"github.com/odpf/optimus/models"
+)
+
+// Compare returns the differences of output of query from the rows expected,
+// rows are compared by the columns expected rows declare. Values are compared
+// by their text, so 10 matches 10.0 and dates match their ISO 8601 form
+func Compare(expected models.JobSpecTestExpectation, output []map[string]bigquery.Value) []string {
+	var columns []string
+	declared := map[string]bool{}
+	for _, row := range expected.Rows {
+		for column := range row {
+			if !declared[column] {
+				declared[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	sort.Strings(columns)
+
+	var failures []string
+	if len(output) > 0 {
+		for _, column := range columns {
+			if _, ok := output[0][column]; !ok {
+				failures = append(failures, fmt.Sprintf("column %s is not in the output", column))
+			}
+		}
+		if len(failures) > 0 {
+			return failures
+		}
+	}
+
+	want := make([]string, len(expected.Rows))
+	for i, row := range expected.Rows {
+		want[i] = formatRow(columns, func(column string) interface{} { return row[column] })
+	}
+	got := make([]string, len(output))
+	for i, row := range output {
+		got[i] = formatRow(columns, func(column string) interface{} { return row[column] })
+	}
+
+	if expected.Ordered {
+		for i := 0; i < len(want) || i < len(got); i++ {
+			switch {
+			case i >= len(got):
+				failures = append(failures, fmt.Sprintf("row %d: expected %s, got no row", i+1, want[i]))
+			case i >= len(want):
+				failures = append(failures, fmt.Sprintf("row %d: unexpected %s", i+1, got[i]))
+			case want[i] != got[i]:
+				failures = append(failures, fmt.Sprintf("row %d: expected %s, got %s", i+1, want[i], got[i]))
+			}
+		}
+		return failures
+	}
+
+	// rows are compared as multisets, duplicates have to match in count
+	remaining := map[string]int{}
+	for _, row := range got {
+		remaining[row]++
+	}
+	for _, row := range want {
+		if remaining[row] > 0 {
+			remaining[row]--
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("missing row %s", row))
+	}
+	for _, row := range got {
+		if remaining[row] > 0 {
+			remaining[row]--
+			failures = append(failures, fmt.Sprintf("unexpected row %s", row))
+		}
+	}
+	return failures
+}
+
+func formatRow(columns []string, value func(column string) interface{}) string {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i] = column + ": " + formatValue(value(column))
+	}
+	return "{" + strings.Join(cells, ", ") + "}"
+}
+
+// formatValue returns the text of values of bigquery and yaml in the same
+// form, so they can be compared
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case []byte:
+		return strconv.Quote(string(v))
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case *big.Rat:
+		return strings.TrimRight(strings.TrimRight(v.FloatString(9), "0"), ".")
+	case time.Time:
+		return strconv.Quote(v.UTC().Format(time.RFC3339Nano))
+	case civil.Date, civil.DateTime, civil.Time:
+		return strconv.Quote(fmt.Sprintf("%s", v))
+	case []bigquery.Value:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]bigquery.Value:
+		record := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			record[key] = val
+		}
+		return formatValue(record)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return formatRow(keys, func(key string) interface{} { return v[key] })
+	}
+	return strconv.Quote(fmt.Sprintf("%v", value))
+}
@@ -0,0 +1,72 @@
+package sqltest_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"github.com/odpf/optimus/ext/sqltest"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	output := []map[string]bigquery.Value{
+		{
+			"day":        civil.Date{Year: 2021, Month: 6, Day: 1},
+			"revenue":    big.NewRat(21, 2),
+			"orders":     int64(2),
+			"updated_at": time.Date(2021, 6, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			"day":        civil.Date{Year: 2021, Month: 6, Day: 2},
+			"revenue":    float64(4),
+			"orders":     int64(1),
+			"updated_at": time.Date(2021, 6, 2, 2, 0, 0, 0, time.UTC),
+		},
+	}
+	t.Run("should match rows in any order by the columns expected", func(t *testing.T) {
+		failures := sqltest.Compare(models.JobSpecTestExpectation{
+			Rows: []map[string]interface{}{
+				{"day": "2021-06-02", "revenue": 4, "orders": 1},
+				{"day": "2021-06-01", "revenue": 10.5, "orders": 2},
+			},
+		}, output)
+		assert.Empty(t, failures)
+	})
+	t.Run("should report missing and unexpected rows", func(t *testing.T) {
+		failures := sqltest.Compare(models.JobSpecTestExpectation{
+			Rows: []map[string]interface{}{
+				{"day": "2021-06-01", "orders": 2},
+				{"day": "2021-06-02", "orders": 3},
+			},
+		}, output)
+		assert.Equal(t, []string{
+			`missing row {day: "2021-06-02", orders: 3}`,
+			`unexpected row {day: "2021-06-02", orders: 1}`,
+		}, failures)
+	})
+	t.Run("should compare rows in order when ordered", func(t *testing.T) {
+		failures := sqltest.Compare(models.JobSpecTestExpectation{
+			Rows: []map[string]interface{}{
+				{"day": "2021-06-02"},
+				{"day": "2021-06-01"},
+				{"day": "2021-06-03"},
+			},
+			Ordered: true,
+		}, output)
+		assert.Equal(t, []string{
+			`row 1: expected {day: "2021-06-02"}, got {day: "2021-06-01"}`,
+			`row 2: expected {day: "2021-06-01"}, got {day: "2021-06-02"}`,
+			`row 3: expected {day: "2021-06-03"}, got no row`,
+		}, failures)
+	})
+	t.Run("should report columns expected which are not in the output", func(t *testing.T) {
+		failures := sqltest.Compare(models.JobSpecTestExpectation{
+			Rows: []map[string]interface{}{{"day": "2021-06-01", "refunds": 0}},
+		}, output)
+		assert.Equal(t, []string{"column refunds is not in the output"}, failures)
+	})
+}
@@ -0,0 +1,169 @@
+// Package sqltest runs test cases declared in job specs, the query of a job
+// is run in bigquery against fixtures loaded into a temporary dataset in place
+// of the tables it reads, and its output compared to the rows expected
+package sqltest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/google/uuid"
+	"github.com/googleapis/google-cloud-go-testing/bigquery/bqiface"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	// QueryAssetName is the asset holding the query of a job
+	QueryAssetName = "query.sql"
+
+	datasetPrefix = "optimus_test_"
+	// datasetExpiration cleans up fixtures of runs which failed to delete
+	// their dataset
+	datasetExpiration = time.Hour
+)
+
+// tableRegex matches tables as project.dataset.table or project:dataset.table,
+// optionally quoted with backticks
+var tableRegex = regexp.MustCompile("^`?([a-z][a-z0-9-]{4,28}[a-z0-9])[:.]([A-Za-z0-9_]+)\\.([A-Za-z0-9_-]+)`?$")
+
+type Result struct {
+	Name string
+	// Failures are the differences of output from the rows expected
+	Failures []string
+}
+
+func (r Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+type Runner struct {
+	Client bqiface.Client
+	// Project is the gcp project temporary datasets are created in
+	Project string
+}
+
+// Run runs the compiled query of a job with the inputs of test loaded as
+// fixtures, the dataset of fixtures is deleted once done
+func (r *Runner) Run(ctx context.Context, query string, test models.JobSpecTest) (Result, error) {
+	if err := Validate(test); err != nil {
+		return Result{}, err
+	}
+	datasetID := datasetPrefix + strings.ReplaceAll(uuid.New().String(), "-", "")[:12]
+	dataset := r.Client.DatasetInProject(r.Project, datasetID)
+	if err := dataset.Create(ctx, &bqiface.DatasetMetadata{
+		DatasetMetadata: bigquery.DatasetMetadata{
+			Description:            fmt.Sprintf("fixtures of optimus test %s", test.Name),
+			DefaultTableExpiration: datasetExpiration,
+		},
+	}); err != nil {
+		return Result{}, errors.Wrapf(err, "failed to create dataset %s", datasetID)
+	}
+	defer dataset.DeleteWithContents(context.Background())
+
+	fixtures := map[string]string{}
+	for _, input := range test.Inputs {
+		fixtureID := FixtureTableID(input.Table)
+		if err := loadFixture(ctx, dataset.Table(fixtureID), input.Rows); err != nil {
+			return Result{}, errors.Wrapf(err, "failed to load fixture of %s", input.Table)
+		}
+		fixtures[input.Table] = fmt.Sprintf("%s.%s.%s", r.Project, datasetID, fixtureID)
+	}
+
+	it, err := r.Client.Query(ReplaceTables(query, fixtures)).Read(ctx)
+	if err != nil {
+		return Result{}, errors.Wrap(err, "failed to run query")
+	}
+	var output []map[string]bigquery.Value
+	for {
+		row := map[string]bigquery.Value{}
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return Result{}, errors.Wrap(err, "failed to read output of query")
+		}
+		output = append(output, row)
+	}
+	return Result{
+		Name:     test.Name,
+		Failures: Compare(test.Expected, output),
+	}, nil
+}
+
+// Validate checks inputs of a test can be loaded as fixtures
+func Validate(test models.JobSpecTest) error {
+	seen := map[string]bool{}
+	for _, input := range test.Inputs {
+		if !tableRegex.MatchString(input.Table) {
+			return errors.Errorf("input %q of test %s should be a table like shop-project:raw.orders", input.Table, test.Name)
+		}
+		if seen[FixtureTableID(input.Table)] {
+			return errors.Errorf("table %s has more than one input in test %s", input.Table, test.Name)
+		}
+		seen[FixtureTableID(input.Table)] = true
+		// schema of fixtures is detected from their rows
+		if len(input.Rows) == 0 {
+			return errors.Errorf("input %s of test %s has no rows, fixtures need rows to detect their schema", input.Table, test.Name)
+		}
+	}
+	return nil
+}
+
+// FixtureTableID is the table of fixture standing in for a table, tables of
+// all projects and datasets are loaded in the same dataset
+func FixtureTableID(table string) string {
+	parts := tableRegex.FindStringSubmatch(table)
+	if parts == nil {
+		return ""
+	}
+	return strings.ReplaceAll(fmt.Sprintf("%s__%s__%s", parts[1], parts[2], parts[3]), "-", "_")
+}
+
+// ReplaceTables replaces references to tables in query with their fixtures,
+// tables are matched in any of the forms bigquery accepts
+func ReplaceTables(query string, fixtures map[string]string) string {
+	for table, fixture := range fixtures {
+		parts := tableRegex.FindStringSubmatch(table)
+		if parts == nil {
+			continue
+		}
+		project, dataset, name := regexp.QuoteMeta(parts[1]), regexp.QuoteMeta(parts[2]), regexp.QuoteMeta(parts[3])
+		ref := regexp.MustCompile(fmt.Sprintf(
+			"`%[1]s[:.]%[2]s\\.%[3]s`|`%[1]s`\\.`%[2]s`\\.`%[3]s`|\\b%[1]s[:.]%[2]s\\.%[3]s\\b",
+			project, dataset, name))
+		query = ref.ReplaceAllLiteralString(query, "`"+fixture+"`")
+	}
+	return query
+}
+
+func loadFixture(ctx context.Context, table bqiface.Table, rows []map[string]interface{}) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return err
+		}
+	}
+	source := bigquery.NewReaderSource(&buf)
+	source.SourceFormat = bigquery.JSON
+	source.AutoDetect = true
+
+	job, err := table.LoaderFrom(source).Run(ctx)
+	if err != nil {
+		return err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return status.Err()
+}
@@ -0,0 +1,60 @@
+package sqltest_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/ext/sqltest"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceTables(t *testing.T) {
+	fixtures := map[string]string{
+		"shop-project:raw.orders": "test-project.optimus_test_1.shop_project__raw__orders",
+	}
+	t.Run("should replace tables in any form bigquery accepts", func(t *testing.T) {
+		query := "select * from `shop-project.raw.orders` o join `shop-project`.`raw`.`orders` p using (id) " +
+			"join `shop-project:raw.orders` q using (id) join `shop-project.raw.orders_v2` r using (id)"
+		assert.Equal(t, "select * from `test-project.optimus_test_1.shop_project__raw__orders` o "+
+			"join `test-project.optimus_test_1.shop_project__raw__orders` p using (id) "+
+			"join `test-project.optimus_test_1.shop_project__raw__orders` q using (id) "+
+			"join `shop-project.raw.orders_v2` r using (id)", sqltest.ReplaceTables(query, fixtures))
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should accept inputs with rows", func(t *testing.T) {
+		err := sqltest.Validate(models.JobSpecTest{
+			Name: "daily_revenue",
+			Inputs: []models.JobSpecTestInput{
+				{Table: "shop-project.raw.orders", Rows: []map[string]interface{}{{"id": 1}}},
+				{Table: "shop-project:raw.refunds", Rows: []map[string]interface{}{{"id": 1}}},
+			},
+		})
+		assert.Nil(t, err)
+	})
+	t.Run("should reject inputs without rows", func(t *testing.T) {
+		err := sqltest.Validate(models.JobSpecTest{
+			Name:   "daily_revenue",
+			Inputs: []models.JobSpecTestInput{{Table: "shop-project.raw.orders"}},
+		})
+		assert.Equal(t, "input shop-project.raw.orders of test daily_revenue has no rows, fixtures need rows to detect their schema", err.Error())
+	})
+	t.Run("should reject the same table twice", func(t *testing.T) {
+		err := sqltest.Validate(models.JobSpecTest{
+			Name: "daily_revenue",
+			Inputs: []models.JobSpecTestInput{
+				{Table: "shop-project.raw.orders", Rows: []map[string]interface{}{{"id": 1}}},
+				{Table: "shop-project:raw.orders", Rows: []map[string]interface{}{{"id": 2}}},
+			},
+		})
+		assert.Equal(t, "table shop-project:raw.orders has more than one input in test daily_revenue", err.Error())
+	})
+	t.Run("should reject tables not fully qualified", func(t *testing.T) {
+		err := sqltest.Validate(models.JobSpecTest{
+			Name:   "daily_revenue",
+			Inputs: []models.JobSpecTestInput{{Table: "raw.orders", Rows: []map[string]interface{}{{"id": 1}}}},
+		})
+		assert.Equal(t, `input "raw.orders" of test daily_revenue should be a table like shop-project:raw.orders`, err.Error())
+	})
+}
@@ -0,0 +1,240 @@
+package bq2gcs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	Name    = "bq2gcs"
+	Version = "0.1.0"
+	Image   = "docker.io/odpf/optimus-task-bq2gcs:" + Version
+
+	// SecretPath is where the service account key tables are exported with
+	// is mounted
+	SecretPath = "/opt/optimus/secrets/auth.json"
+
+	ConfigSourceTable    = "SOURCE_TABLE"
+	ConfigPartition      = "PARTITION"
+	ConfigDestinationURI = "DESTINATION_URI"
+	ConfigFormat         = "FORMAT"
+	ConfigCompression    = "COMPRESSION"
+	ConfigPrintHeader    = "PRINT_HEADER"
+	ConfigDelimiter      = "FIELD_DELIMITER"
+
+	FormatCSV     = "CSV"
+	FormatJSON    = "NEWLINE_DELIMITED_JSON"
+	FormatAvro    = "AVRO"
+	FormatParquet = "PARQUET"
+
+	CompressionNone = "NONE"
+)
+
+var (
+	This = &BQ2GCS{}
+
+	// Formats are the formats bigquery exports to, along with compressions
+	// supported for them
+	Formats = map[string][]string{
+		FormatCSV:     {CompressionNone, "GZIP"},
+		FormatJSON:    {CompressionNone, "GZIP"},
+		FormatAvro:    {CompressionNone, "DEFLATE", "SNAPPY"},
+		FormatParquet: {CompressionNone, "SNAPPY", "GZIP"},
+	}
+	formatOrder = []string{FormatCSV, FormatJSON, FormatAvro, FormatParquet}
+
+	// tables are accepted as project:dataset.table or project.dataset.table
+	tableRegex     = regexp.MustCompile(`^([a-z][a-z0-9-]{4,28}[a-z0-9])[:.]([A-Za-z0-9_]+)\.([A-Za-z0-9_-]+)$`)
+	partitionRegex = regexp.MustCompile(`^\d{4}-?\d{2}-?\d{2}(\d{2})?$`)
+)
+
+// BQ2GCS exports a bigquery table, or a partition of it, to gcs
+type BQ2GCS struct{}
+
+func (b *BQ2GCS) PluginInfo() (*models.PluginInfoResponse, error) {
+	return &models.PluginInfoResponse{
+		Name:          Name,
+		Description:   "Export a BigQuery table or partition to GCS",
+		PluginType:    models.PluginTypeTask,
+		PluginMods:    []models.PluginMod{models.ModTypeCLI, models.ModTypeDependencyResolver, models.ModTypeConfigValidator},
+		PluginVersion: Version,
+		Image:         Image,
+		SecretPath:    SecretPath,
+	}, nil
+}
+
+func (b *BQ2GCS) GetQuestions(ctx context.Context, req models.GetQuestionsRequest) (*models.GetQuestionsResponse, error) {
+	var formatQuestions []models.PluginSubQuestion
+	for _, format := range formatOrder {
+		formatQuestions = append(formatQuestions, models.PluginSubQuestion{
+			IfValue: format,
+			Questions: models.PluginQuestions{
+				{
+					Name:        ConfigCompression,
+					Prompt:      "Compression",
+					Multiselect: Formats[format],
+				},
+			},
+		})
+	}
+	return &models.GetQuestionsResponse{
+		Questions: models.PluginQuestions{
+			{
+				Name:   ConfigSourceTable,
+				Prompt: "Table to export",
+				Help:   "e.g. shop-project:analytics.orders",
+			},
+			{
+				Name:   ConfigDestinationURI,
+				Prompt: "GCS path files are exported to",
+				Help: "macros are compiled for each run and * is replaced with file numbers, " +
+					"e.g. gs://shop-exports/orders/{{ .DSTART | Date }}/part-*.csv",
+			},
+			{
+				Name:         ConfigFormat,
+				Prompt:       "Format",
+				Multiselect:  formatOrder,
+				SubQuestions: formatQuestions,
+			},
+		},
+	}, nil
+}
+
+func (b *BQ2GCS) ValidateQuestion(ctx context.Context, req models.ValidateQuestionRequest) (*models.ValidateQuestionResponse, error) {
+	value := strings.TrimSpace(req.Answer.Value)
+	var problem string
+	switch req.Answer.Question.Name {
+	case ConfigSourceTable:
+		problem = validateTable(value)
+	case ConfigDestinationURI:
+		problem = validateURI(value)
+	}
+	if problem != "" {
+		return &models.ValidateQuestionResponse{Error: problem}, nil
+	}
+	return &models.ValidateQuestionResponse{Success: true}, nil
+}
+
+func (b *BQ2GCS) DefaultConfig(ctx context.Context, req models.DefaultConfigRequest) (*models.DefaultConfigResponse, error) {
+	var config models.PluginConfigs
+	for _, name := range []string{ConfigSourceTable, ConfigDestinationURI, ConfigFormat, ConfigCompression} {
+		if answer, ok := req.Answers.Get(name); ok && answer.Value != "" {
+			config = append(config, models.PluginConfig{Name: name, Value: answer.Value})
+		}
+	}
+	return &models.DefaultConfigResponse{Config: config}, nil
+}
+
+func (b *BQ2GCS) DefaultAssets(ctx context.Context, req models.DefaultAssetsRequest) (*models.DefaultAssetsResponse, error) {
+	return &models.DefaultAssetsResponse{}, nil
+}
+
+func (b *BQ2GCS) CompileAssets(ctx context.Context, req models.CompileAssetsRequest) (*models.CompileAssetsResponse, error) {
+	return &models.CompileAssetsResponse{Assets: req.Assets}, nil
+}
+
+// GenerateDestination returns the urn of the part of destination uri same for
+// all runs, up to the directory where macros or wildcards start
+func (b *BQ2GCS) GenerateDestination(ctx context.Context, req models.GenerateDestinationRequest) (*models.GenerateDestinationResponse, error) {
+	uri, _ := req.Config.Get(ConfigDestinationURI)
+	return &models.GenerateDestinationResponse{Destination: models.CanonicalURN(DestinationPrefix(uri.Value))}, nil
+}
+
+// GenerateDependencies returns the exported table, so the job runs after the
+// job writing it
+func (b *BQ2GCS) GenerateDependencies(ctx context.Context, req models.GenerateDependenciesRequest) (*models.GenerateDependenciesResponse, error) {
+	table, _ := req.Config.Get(ConfigSourceTable)
+	parts := tableRegex.FindStringSubmatch(strings.TrimSpace(table.Value))
+	if parts == nil {
+		return &models.GenerateDependenciesResponse{}, nil
+	}
+	return &models.GenerateDependenciesResponse{
+		Dependencies: []string{models.URN{
+			Scheme: models.URNSchemeBigQuery,
+			Name:   fmt.Sprintf("%s:%s.%s", parts[1], parts[2], parts[3]),
+		}.String()},
+	}, nil
+}
+
+func (b *BQ2GCS) ValidateConfig(ctx context.Context, req models.ValidateConfigRequest) (*models.ValidateConfigResponse, error) {
+	var errs []string
+	value := func(name string) string {
+		conf, _ := req.Config.Get(name)
+		return strings.TrimSpace(conf.Value)
+	}
+	if problem := validateTable(value(ConfigSourceTable)); problem != "" {
+		errs = append(errs, problem)
+	}
+	if problem := validateURI(value(ConfigDestinationURI)); problem != "" {
+		errs = append(errs, problem)
+	}
+	if partition := value(ConfigPartition); partition != "" && !isTemplate(partition) && !partitionRegex.MatchString(partition) {
+		errs = append(errs, fmt.Sprintf("%s %s should be a date like 2021-06-01, or an hour like 2021060113 for hourly partitions",
+			ConfigPartition, partition))
+	}
+
+	// bigquery exports to csv unless told otherwise
+	format := strings.ToUpper(value(ConfigFormat))
+	if format == "" {
+		format = FormatCSV
+	}
+	compressions, ok := Formats[format]
+	if !ok {
+		errs = append(errs, fmt.Sprintf("%s %q is not supported, use one of %s", ConfigFormat, format, strings.Join(formatOrder, ", ")))
+	} else if compression := strings.ToUpper(value(ConfigCompression)); compression != "" && !contains(compressions, compression) {
+		errs = append(errs, fmt.Sprintf("%s %s can't be used with %s, use one of %s", ConfigCompression, compression, format,
+			strings.Join(compressions, ", ")))
+	}
+	if format != FormatCSV {
+		for _, name := range []string{ConfigPrintHeader, ConfigDelimiter} {
+			if value(name) != "" {
+				errs = append(errs, fmt.Sprintf("%s only applies to %s format", name, FormatCSV))
+			}
+		}
+	}
+	return &models.ValidateConfigResponse{Errors: errs}, nil
+}
+
+// DestinationPrefix returns the part of uri before the directory macros or
+// wildcards are used in
+func DestinationPrefix(uri string) string {
+	uri = strings.TrimSpace(uri)
+	if idx := strings.IndexAny(uri, "{*"); idx >= 0 {
+		uri = uri[:idx]
+		if slash := strings.LastIndex(uri, "/"); slash >= 0 {
+			uri = uri[:slash]
+		}
+	}
+	return strings.TrimRight(uri, "/")
+}
+
+func validateTable(table string) string {
+	if !tableRegex.MatchString(table) {
+		return fmt.Sprintf("%s %q should be a table like shop-project:analytics.orders", ConfigSourceTable, table)
+	}
+	return ""
+}
+
+func validateURI(uri string) string {
+	if !strings.HasPrefix(uri, "gs://") || len(strings.TrimPrefix(uri, "gs://")) == 0 {
+		return fmt.Sprintf("%s %q should be a gcs path like gs://shop-exports/orders/part-*.csv", ConfigDestinationURI, uri)
+	}
+	return ""
+}
+
+func isTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+func contains(list []string, item string) bool {
+	for _, v := range list {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
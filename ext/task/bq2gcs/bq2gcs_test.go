@@ -0,0 +1,79 @@
+package bq2gcs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/optimus/ext/task/bq2gcs"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBQ2GCS(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ValidateConfig", func(t *testing.T) {
+		t.Run("should accept export of a partition with macros in path", func(t *testing.T) {
+			resp, err := bq2gcs.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "SOURCE_TABLE", Value: "shop-project:analytics.orders"},
+					{Name: "PARTITION", Value: "{{ .DSTART | Date }}"},
+					{Name: "DESTINATION_URI", Value: "gs://shop-exports/orders/{{ .DSTART | Date }}/part-*.avro"},
+					{Name: "FORMAT", Value: "avro"},
+					{Name: "COMPRESSION", Value: "SNAPPY"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Empty(t, resp.Errors)
+		})
+		t.Run("should reject compression not supported by format and csv options of other formats", func(t *testing.T) {
+			resp, err := bq2gcs.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "SOURCE_TABLE", Value: "shop-project.analytics.orders"},
+					{Name: "PARTITION", Value: "June"},
+					{Name: "DESTINATION_URI", Value: "shop-exports/orders.json"},
+					{Name: "FORMAT", Value: "NEWLINE_DELIMITED_JSON"},
+					{Name: "COMPRESSION", Value: "SNAPPY"},
+					{Name: "PRINT_HEADER", Value: "false"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				`DESTINATION_URI "shop-exports/orders.json" should be a gcs path like gs://shop-exports/orders/part-*.csv`,
+				"PARTITION June should be a date like 2021-06-01, or an hour like 2021060113 for hourly partitions",
+				"COMPRESSION SNAPPY can't be used with NEWLINE_DELIMITED_JSON, use one of NONE, GZIP",
+				"PRINT_HEADER only applies to CSV format",
+			}, resp.Errors)
+		})
+		t.Run("should require table and supported format", func(t *testing.T) {
+			resp, err := bq2gcs.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "DESTINATION_URI", Value: "gs://shop-exports/orders.orc"},
+					{Name: "FORMAT", Value: "ORC"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				`SOURCE_TABLE "" should be a table like shop-project:analytics.orders`,
+				`FORMAT "ORC" is not supported, use one of CSV, NEWLINE_DELIMITED_JSON, AVRO, PARQUET`,
+			}, resp.Errors)
+		})
+	})
+	t.Run("should declare gcs path as destination and exported table as dependency", func(t *testing.T) {
+		config := models.PluginConfigs{
+			{Name: "SOURCE_TABLE", Value: "shop-project.analytics.orders"},
+			{Name: "DESTINATION_URI", Value: "gs://shop-exports/orders/{{ .DSTART | Date }}/part-*.csv"},
+		}
+		dest, err := bq2gcs.This.GenerateDestination(ctx, models.GenerateDestinationRequest{Config: config})
+		assert.Nil(t, err)
+		assert.Equal(t, "gcs://shop-exports/orders", dest.Destination)
+		deps, err := bq2gcs.This.GenerateDependencies(ctx, models.GenerateDependenciesRequest{Config: config})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"bigquery://shop-project:analytics.orders"}, deps.Dependencies)
+	})
+	t.Run("DestinationPrefix", func(t *testing.T) {
+		assert.Equal(t, "gs://shop-exports/orders/orders.csv", bq2gcs.DestinationPrefix("gs://shop-exports/orders/orders.csv"))
+		assert.Equal(t, "gs://shop-exports/orders", bq2gcs.DestinationPrefix("gs://shop-exports/orders/part-*.csv"))
+		assert.Equal(t, "gs://shop-exports", bq2gcs.DestinationPrefix("gs://shop-exports/{{.DSTART}}/orders.csv"))
+	})
+}
@@ -0,0 +1,41 @@
+#!/bin/bash
+set -euo pipefail
+
+# wait for few seconds to prepare scheduler for the run
+sleep 5
+
+echo "-- initializing optimus assets"
+OPTIMUS_ADMIN_ENABLED=1 /opt/optimus admin build instance "$JOB_NAME" --project "$PROJECT" --output-dir "$JOB_DIR" \
+  --type "$INSTANCE_TYPE" --name "$INSTANCE_NAME" --scheduled-at "$SCHEDULED_AT" --host "$OPTIMUS_HOSTNAME"
+
+echo "-- exporting env"
+set -o allexport
+source "$JOB_DIR/in/.env"
+set +o allexport
+
+gcloud auth activate-service-account --key-file /opt/optimus/secrets/auth.json
+
+# bq accepts project:dataset.table with partition decorator of the form $YYYYMMDD
+table="$SOURCE_TABLE"
+if [[ "$table" != *:* ]]; then
+  table="${table/./:}"
+fi
+if [ -n "${PARTITION:-}" ]; then
+  table="${table}\$${PARTITION//-/}"
+fi
+
+format="${FORMAT:-CSV}"
+compression="${COMPRESSION:-NONE}"
+extract_args=(--destination_format="${format^^}" --compression="${compression^^}")
+if [ -n "${FIELD_DELIMITER:-}" ]; then
+  extract_args+=(--field_delimiter="$FIELD_DELIMITER")
+fi
+if [ -n "${PRINT_HEADER:-}" ]; then
+  extract_args+=(--print_header="$PRINT_HEADER")
+fi
+if [ "${format^^}" = "AVRO" ]; then
+  extract_args+=(--use_avro_logical_types)
+fi
+
+echo "-- exporting $table to $DESTINATION_URI"
+exec bq --project_id="${table%%:*}" extract "${extract_args[@]}" "$table" "$DESTINATION_URI"
@@ -0,0 +1,45 @@
+package builtin
+
+import (
+	"github.com/odpf/optimus/ext/task/bq2gcs"
+	"github.com/odpf/optimus/ext/task/http2bq"
+	"github.com/odpf/optimus/ext/task/pyspark"
+	"github.com/odpf/optimus/ext/task/python"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// Task is implemented by task plugins built into optimus, they run in process
+// so they provide all the mods without a plugin binary
+type Task interface {
+	models.CommandLineMod
+	models.DependencyResolverMod
+	models.ConfigValidatorMod
+}
+
+// Tasks are the task plugins built into optimus
+var Tasks = []Task{
+	bq2gcs.This,
+	http2bq.This,
+	pyspark.This,
+	python.This,
+}
+
+// Register adds task plugins built into optimus to the registry, plugins
+// installed with the same name take precedence over the built in ones so
+// it should be called after installed plugins are loaded
+func Register(registry models.PluginRepository) error {
+	for _, t := range Tasks {
+		info, err := t.PluginInfo()
+		if err != nil {
+			return err
+		}
+		if _, err := registry.GetByName(info.Name); err == nil {
+			continue
+		}
+		if err := registry.Add(t, t, t, t); err != nil {
+			return errors.Wrapf(err, "failed to register built in task %s", info.Name)
+		}
+	}
+	return nil
+}
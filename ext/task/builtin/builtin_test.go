@@ -0,0 +1,42 @@
+package builtin_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/ext/task/builtin"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	t.Run("should register built in tasks with all their mods", func(t *testing.T) {
+		registry := models.NewPluginRepository()
+		assert.Nil(t, builtin.Register(registry))
+
+		for _, name := range []string{"bq2gcs", "http2bq", "pyspark", "python"} {
+			p, err := registry.GetByName(name)
+			assert.Nil(t, err)
+			assert.NotNil(t, p.CLIMod)
+			assert.NotNil(t, p.DependencyMod)
+			assert.NotNil(t, p.ValidatorMod)
+		}
+	})
+	t.Run("should keep installed plugin with the same name as a built in task", func(t *testing.T) {
+		installed := new(mock.BasePlugin)
+		installed.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name:          "python",
+			PluginType:    models.PluginTypeTask,
+			PluginVersion: "2.0.0",
+			Image:         "example.io/python:2.0.0",
+		}, nil)
+		registry := models.NewPluginRepository()
+		assert.Nil(t, registry.Add(installed, nil, nil, nil))
+
+		assert.Nil(t, builtin.Register(registry))
+		p, err := registry.GetByName("python")
+		assert.Nil(t, err)
+		assert.Equal(t, "2.0.0", p.Info().PluginVersion)
+		assert.Nil(t, p.ValidatorMod)
+	})
+}
@@ -0,0 +1,314 @@
+package http2bq
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	Name    = "http2bq"
+	Version = "0.1.0"
+	Image   = "docker.io/odpf/optimus-task-http2bq:" + Version
+
+	// SecretPath is where the service account key records are loaded to
+	// bigquery with is mounted
+	SecretPath = "/opt/optimus/secrets/auth.json"
+
+	ConfigURL           = "URL"
+	ConfigMethod        = "METHOD"
+	ConfigHeaders       = "HEADERS"
+	ConfigBody          = "BODY"
+	ConfigAuth          = "AUTH"
+	ConfigAuthToken     = "AUTH_TOKEN"
+	ConfigAuthUsername  = "AUTH_USERNAME"
+	ConfigAuthPassword  = "AUTH_PASSWORD"
+	ConfigAuthHeader    = "AUTH_HEADER"
+	ConfigPagination    = "PAGINATION"
+	ConfigPageParam     = "PAGE_PARAM"
+	ConfigPageSize      = "PAGE_SIZE"
+	ConfigPageSizeParam = "PAGE_SIZE_PARAM"
+	ConfigCursorPath    = "CURSOR_PATH"
+	ConfigRecordsPath   = "RECORDS_PATH"
+	ConfigMaxPages      = "MAX_PAGES"
+	ConfigTargetTable   = "TARGET_TABLE"
+	ConfigLoadMethod    = "LOAD_METHOD"
+
+	AuthNone   = "none"
+	AuthBearer = "bearer"
+	AuthBasic  = "basic"
+	AuthAPIKey = "api_key"
+
+	// PaginationPage requests pages by number in PAGE_PARAM starting at 1
+	PaginationPage = "page"
+	// PaginationOffset requests pages by offset of the first record in
+	// PAGE_PARAM, PAGE_SIZE apart
+	PaginationOffset = "offset"
+	// PaginationCursor passes the cursor found at CURSOR_PATH of a response
+	// in PAGE_PARAM of the next request
+	PaginationCursor = "cursor"
+	// PaginationLink follows the next link in the Link header of responses
+	PaginationLink = "link"
+
+	LoadMethodAppend  = "APPEND"
+	LoadMethodReplace = "REPLACE"
+)
+
+var (
+	This = &HTTP2BQ{}
+
+	methods     = []string{"GET", "POST"}
+	auths       = []string{AuthNone, AuthBearer, AuthBasic, AuthAPIKey}
+	paginations = []string{"none", PaginationPage, PaginationOffset, PaginationCursor, PaginationLink}
+	loadMethods = []string{LoadMethodAppend, LoadMethodReplace}
+
+	tableRegex = regexp.MustCompile(`^([a-z][a-z0-9-]{4,28}[a-z0-9])[:.]([A-Za-z0-9_]+)\.([A-Za-z0-9_-]+)$`)
+	// secretRegex matches configs made of a secret macro, e.g. {{ .secret.API_TOKEN }}
+	secretRegex = regexp.MustCompile(`^{{-?\s*\.?secret\.[A-Za-z_][A-Za-z0-9_]*\s*-?}}$`)
+	// pathRegex matches dot separated keys into a json response, e.g. meta.next_cursor
+	pathRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+)
+
+// HTTP2BQ lands records returned by an http api into a bigquery table, following
+// pages of the api
+type HTTP2BQ struct{}
+
+func (h *HTTP2BQ) PluginInfo() (*models.PluginInfoResponse, error) {
+	return &models.PluginInfoResponse{
+		Name:          Name,
+		Description:   "Load records of an HTTP API into BigQuery",
+		PluginType:    models.PluginTypeTask,
+		PluginMods:    []models.PluginMod{models.ModTypeCLI, models.ModTypeDependencyResolver, models.ModTypeConfigValidator},
+		PluginVersion: Version,
+		Image:         Image,
+		SecretPath:    SecretPath,
+	}, nil
+}
+
+func (h *HTTP2BQ) GetQuestions(ctx context.Context, req models.GetQuestionsRequest) (*models.GetQuestionsResponse, error) {
+	return &models.GetQuestionsResponse{
+		Questions: models.PluginQuestions{
+			{
+				Name:   ConfigURL,
+				Prompt: "URL of the api",
+				Help:   "macros are compiled for each run, e.g. https://api.example.com/orders?since={{ .DSTART }}",
+			},
+			{
+				Name:        ConfigAuth,
+				Prompt:      "Authentication",
+				Multiselect: auths,
+				SubQuestions: []models.PluginSubQuestion{
+					{IfValue: AuthBearer, Questions: models.PluginQuestions{secretQuestion(ConfigAuthToken)}},
+					{IfValue: AuthAPIKey, Questions: models.PluginQuestions{secretQuestion(ConfigAuthToken)}},
+					{IfValue: AuthBasic, Questions: models.PluginQuestions{
+						{Name: ConfigAuthUsername, Prompt: "Username"},
+						secretQuestion(ConfigAuthPassword),
+					}},
+				},
+			},
+			{
+				Name:        ConfigPagination,
+				Prompt:      "Pagination",
+				Multiselect: paginations,
+				SubQuestions: []models.PluginSubQuestion{
+					{IfValue: PaginationCursor, Questions: models.PluginQuestions{
+						{Name: ConfigCursorPath, Prompt: "Path to the next cursor in responses", Help: "e.g. meta.next_cursor"},
+					}},
+				},
+			},
+			{
+				Name:   ConfigRecordsPath,
+				Prompt: "Path to records in responses",
+				Help:   "e.g. data.orders, empty if responses are lists of records",
+			},
+			{
+				Name:   ConfigTargetTable,
+				Prompt: "Table records are loaded to",
+				Help:   "e.g. shop-project:raw.orders",
+			},
+			{
+				Name:        ConfigLoadMethod,
+				Prompt:      "Load method",
+				Multiselect: loadMethods,
+			},
+		},
+	}, nil
+}
+
+func secretQuestion(name string) models.PluginQuestion {
+	return models.PluginQuestion{
+		Name:   name,
+		Prompt: "Secret holding " + strings.ToLower(strings.ReplaceAll(name, "_", " ")),
+		Help:   "name of a secret registered for the project, e.g. SHOP_API_TOKEN",
+	}
+}
+
+func (h *HTTP2BQ) ValidateQuestion(ctx context.Context, req models.ValidateQuestionRequest) (*models.ValidateQuestionResponse, error) {
+	value := strings.TrimSpace(req.Answer.Value)
+	var problem string
+	switch req.Answer.Question.Name {
+	case ConfigURL:
+		problem = validateURL(value)
+	case ConfigAuthToken, ConfigAuthPassword, ConfigAuthUsername, ConfigCursorPath:
+		if value == "" {
+			problem = req.Answer.Question.Name + " is required"
+		}
+	case ConfigTargetTable:
+		problem = validateTable(value)
+	}
+	if problem != "" {
+		return &models.ValidateQuestionResponse{Error: problem}, nil
+	}
+	return &models.ValidateQuestionResponse{Success: true}, nil
+}
+
+// DefaultConfig writes secrets answered as macros referring them, so they are
+// resolved for each run instead of being kept in the spec
+func (h *HTTP2BQ) DefaultConfig(ctx context.Context, req models.DefaultConfigRequest) (*models.DefaultConfigResponse, error) {
+	var config models.PluginConfigs
+	for _, name := range []string{ConfigURL, ConfigAuth, ConfigAuthUsername, ConfigAuthToken, ConfigAuthPassword,
+		ConfigPagination, ConfigCursorPath, ConfigRecordsPath, ConfigTargetTable, ConfigLoadMethod} {
+		answer, ok := req.Answers.Get(name)
+		if !ok || answer.Value == "" {
+			continue
+		}
+		value := answer.Value
+		if name == ConfigAuthToken || name == ConfigAuthPassword {
+			value = fmt.Sprintf("{{ .secret.%s }}", answer.Value)
+		}
+		config = append(config, models.PluginConfig{Name: name, Value: value})
+	}
+	return &models.DefaultConfigResponse{Config: config}, nil
+}
+
+func (h *HTTP2BQ) DefaultAssets(ctx context.Context, req models.DefaultAssetsRequest) (*models.DefaultAssetsResponse, error) {
+	return &models.DefaultAssetsResponse{}, nil
+}
+
+func (h *HTTP2BQ) CompileAssets(ctx context.Context, req models.CompileAssetsRequest) (*models.CompileAssetsResponse, error) {
+	return &models.CompileAssetsResponse{Assets: req.Assets}, nil
+}
+
+func (h *HTTP2BQ) GenerateDestination(ctx context.Context, req models.GenerateDestinationRequest) (*models.GenerateDestinationResponse, error) {
+	table, _ := req.Config.Get(ConfigTargetTable)
+	parts := tableRegex.FindStringSubmatch(strings.TrimSpace(table.Value))
+	if parts == nil {
+		return &models.GenerateDestinationResponse{}, nil
+	}
+	return &models.GenerateDestinationResponse{Destination: models.URN{
+		Scheme: models.URNSchemeBigQuery,
+		Name:   fmt.Sprintf("%s:%s.%s", parts[1], parts[2], parts[3]),
+	}.String()}, nil
+}
+
+// GenerateDependencies returns no dependencies, apis are outside of optimus
+func (h *HTTP2BQ) GenerateDependencies(ctx context.Context, req models.GenerateDependenciesRequest) (*models.GenerateDependenciesResponse, error) {
+	return &models.GenerateDependenciesResponse{}, nil
+}
+
+func (h *HTTP2BQ) ValidateConfig(ctx context.Context, req models.ValidateConfigRequest) (*models.ValidateConfigResponse, error) {
+	var errs []string
+	value := func(name string) string {
+		conf, _ := req.Config.Get(name)
+		return strings.TrimSpace(conf.Value)
+	}
+	oneOf := func(name string, options []string, fallback string) string {
+		v := value(name)
+		if v == "" {
+			return fallback
+		}
+		for _, option := range options {
+			if strings.EqualFold(v, option) {
+				return option
+			}
+		}
+		errs = append(errs, fmt.Sprintf("%s %q is not supported, use one of %s", name, v, strings.Join(options, ", ")))
+		return ""
+	}
+	requireSecret := func(name string) {
+		switch v := value(name); {
+		case v == "":
+			errs = append(errs, fmt.Sprintf("%s is required", name))
+		case !secretRegex.MatchString(v):
+			errs = append(errs, fmt.Sprintf("%s should refer a secret like {{ .secret.API_TOKEN }}, "+
+				"credentials in job specs are visible to everyone reading them", name))
+		}
+	}
+
+	if problem := validateURL(value(ConfigURL)); problem != "" {
+		errs = append(errs, problem)
+	}
+	oneOf(ConfigMethod, methods, "GET")
+	for _, header := range splitList(value(ConfigHeaders)) {
+		if !strings.Contains(header, ":") {
+			errs = append(errs, fmt.Sprintf("%s should be comma separated Name: value pairs, %q has no value", ConfigHeaders, header))
+		}
+	}
+
+	switch oneOf(ConfigAuth, auths, AuthNone) {
+	case AuthBearer, AuthAPIKey:
+		requireSecret(ConfigAuthToken)
+	case AuthBasic:
+		if value(ConfigAuthUsername) == "" {
+			errs = append(errs, fmt.Sprintf("%s is required for %s auth", ConfigAuthUsername, AuthBasic))
+		}
+		requireSecret(ConfigAuthPassword)
+	}
+
+	switch oneOf(ConfigPagination, paginations, "none") {
+	case PaginationCursor:
+		if !pathRegex.MatchString(value(ConfigCursorPath)) {
+			errs = append(errs, fmt.Sprintf("%s is required for %s pagination, set it to the path of next cursor in responses like meta.next_cursor",
+				ConfigCursorPath, PaginationCursor))
+		}
+	case PaginationOffset:
+		if value(ConfigPageSize) == "" {
+			errs = append(errs, fmt.Sprintf("%s is required for %s pagination, offsets of pages are that many records apart",
+				ConfigPageSize, PaginationOffset))
+		}
+	}
+	for _, name := range []string{ConfigPageSize, ConfigMaxPages} {
+		if v := value(name); v != "" {
+			if n, err := strconv.Atoi(v); err != nil || n < 1 {
+				errs = append(errs, fmt.Sprintf("%s should be a positive number, got %s", name, v))
+			}
+		}
+	}
+	if path := value(ConfigRecordsPath); path != "" && !pathRegex.MatchString(path) {
+		errs = append(errs, fmt.Sprintf("%s %q should be dot separated keys like data.orders", ConfigRecordsPath, path))
+	}
+
+	if problem := validateTable(value(ConfigTargetTable)); problem != "" {
+		errs = append(errs, problem)
+	}
+	oneOf(ConfigLoadMethod, loadMethods, LoadMethodAppend)
+	return &models.ValidateConfigResponse{Errors: errs}, nil
+}
+
+func validateURL(url string) string {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return fmt.Sprintf("%s %q should be an http or https url", ConfigURL, url)
+	}
+	return ""
+}
+
+func validateTable(table string) string {
+	if !tableRegex.MatchString(table) {
+		return fmt.Sprintf("%s %q should be a table like shop-project:raw.orders", ConfigTargetTable, table)
+	}
+	return ""
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,88 @@
+package http2bq_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/optimus/ext/task/http2bq"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTP2BQ(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ValidateConfig", func(t *testing.T) {
+		t.Run("should accept cursor paginated api with token from secrets", func(t *testing.T) {
+			resp, err := http2bq.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "URL", Value: "https://api.example.com/orders?since={{ .DSTART }}"},
+					{Name: "HEADERS", Value: "Accept: application/json"},
+					{Name: "AUTH", Value: "bearer"},
+					{Name: "AUTH_TOKEN", Value: "{{ .secret.SHOP_API_TOKEN }}"},
+					{Name: "PAGINATION", Value: "cursor"},
+					{Name: "CURSOR_PATH", Value: "meta.next_cursor"},
+					{Name: "RECORDS_PATH", Value: "data.orders"},
+					{Name: "TARGET_TABLE", Value: "shop-project:raw.orders"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Empty(t, resp.Errors)
+		})
+		t.Run("should reject credentials written in the spec", func(t *testing.T) {
+			resp, err := http2bq.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "URL", Value: "https://api.example.com/orders"},
+					{Name: "AUTH", Value: "basic"},
+					{Name: "AUTH_PASSWORD", Value: "hunter2"},
+					{Name: "TARGET_TABLE", Value: "shop-project.raw.orders"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				"AUTH_USERNAME is required for basic auth",
+				"AUTH_PASSWORD should refer a secret like {{ .secret.API_TOKEN }}, credentials in job specs are visible to everyone reading them",
+			}, resp.Errors)
+		})
+		t.Run("should require options of pagination and a target table", func(t *testing.T) {
+			resp, err := http2bq.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "URL", Value: "api.example.com/orders"},
+					{Name: "METHOD", Value: "PUT"},
+					{Name: "PAGINATION", Value: "offset"},
+					{Name: "MAX_PAGES", Value: "0"},
+					{Name: "LOAD_METHOD", Value: "merge"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				`URL "api.example.com/orders" should be an http or https url`,
+				`METHOD "PUT" is not supported, use one of GET, POST`,
+				"PAGE_SIZE is required for offset pagination, offsets of pages are that many records apart",
+				"MAX_PAGES should be a positive number, got 0",
+				`TARGET_TABLE "" should be a table like shop-project:raw.orders`,
+				`LOAD_METHOD "merge" is not supported, use one of APPEND, REPLACE`,
+			}, resp.Errors)
+		})
+	})
+	t.Run("should declare target table as destination", func(t *testing.T) {
+		dest, err := http2bq.This.GenerateDestination(ctx, models.GenerateDestinationRequest{Config: models.PluginConfigs{
+			{Name: "TARGET_TABLE", Value: "shop-project.raw.orders"},
+		}})
+		assert.Nil(t, err)
+		assert.Equal(t, "bigquery://shop-project:raw.orders", dest.Destination)
+	})
+	t.Run("should write secrets answered as macros in default config", func(t *testing.T) {
+		resp, err := http2bq.This.DefaultConfig(ctx, models.DefaultConfigRequest{Answers: models.PluginAnswers{
+			{Question: models.PluginQuestion{Name: "URL"}, Value: "https://api.example.com/orders"},
+			{Question: models.PluginQuestion{Name: "AUTH"}, Value: "bearer"},
+			{Question: models.PluginQuestion{Name: "AUTH_TOKEN"}, Value: "SHOP_API_TOKEN"},
+		}})
+		assert.Nil(t, err)
+		assert.Equal(t, models.PluginConfigs{
+			{Name: "URL", Value: "https://api.example.com/orders"},
+			{Name: "AUTH", Value: "bearer"},
+			{Name: "AUTH_TOKEN", Value: "{{ .secret.SHOP_API_TOKEN }}"},
+		}, resp.Config)
+	})
+}
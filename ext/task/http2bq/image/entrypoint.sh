@@ -0,0 +1,19 @@
+#!/bin/bash
+set -euo pipefail
+
+# wait for few seconds to prepare scheduler for the run
+sleep 5
+
+echo "-- initializing optimus assets"
+OPTIMUS_ADMIN_ENABLED=1 /opt/optimus admin build instance "$JOB_NAME" --project "$PROJECT" --output-dir "$JOB_DIR" \
+  --type "$INSTANCE_TYPE" --name "$INSTANCE_NAME" --scheduled-at "$SCHEDULED_AT" --host "$OPTIMUS_HOSTNAME"
+
+echo "-- exporting env"
+set -o allexport
+source "$JOB_DIR/in/.env"
+set +o allexport
+
+export GOOGLE_APPLICATION_CREDENTIALS=/opt/optimus/secrets/auth.json
+
+echo "-- ingesting $URL into $TARGET_TABLE"
+exec python /opt/ingest.py
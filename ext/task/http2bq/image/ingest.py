@@ -0,0 +1,143 @@
+"""Loads records returned by an http api into a bigquery table, following pages
+of the api as configured in the job spec. Configs are read from environment."""
+import base64
+import json
+import os
+import sys
+import time
+
+import requests
+from google.cloud import bigquery
+
+RETRIES = 3
+TIMEOUT_SECONDS = 60
+
+
+def config(name, default=""):
+    return os.environ.get(name, default).strip()
+
+
+def headers():
+    result = {}
+    for header in config("HEADERS").split(","):
+        if ":" in header:
+            key, value = header.split(":", 1)
+            result[key.strip()] = value.strip()
+
+    auth = config("AUTH", "none").lower()
+    if auth == "bearer":
+        result["Authorization"] = "Bearer " + config("AUTH_TOKEN")
+    elif auth == "api_key":
+        result[config("AUTH_HEADER", "X-API-Key")] = config("AUTH_TOKEN")
+    elif auth == "basic":
+        creds = "{}:{}".format(config("AUTH_USERNAME"), config("AUTH_PASSWORD"))
+        result["Authorization"] = "Basic " + base64.b64encode(creds.encode()).decode()
+    return result
+
+
+def lookup(body, path):
+    for key in filter(None, path.split(".")):
+        if not isinstance(body, dict):
+            return None
+        body = body.get(key)
+    return body
+
+
+def fetch(session, url, params):
+    for attempt in range(RETRIES + 1):
+        resp = session.request(config("METHOD", "GET").upper(), url, params=params,
+                               data=config("BODY") or None, timeout=TIMEOUT_SECONDS)
+        # rate limited or failing server, retry with backoff
+        if resp.status_code == 429 or resp.status_code >= 500:
+            if attempt < RETRIES:
+                time.sleep(2 ** attempt * 5)
+                continue
+        resp.raise_for_status()
+        return resp
+
+
+def pages(session):
+    """Yields records of every page, pages are requested until the api runs out
+    of records or says there is no next page"""
+    pagination = config("PAGINATION", "none").lower()
+    default_param = {"page": "page", "offset": "offset", "cursor": "cursor"}.get(pagination, "")
+    param = config("PAGE_PARAM", default_param)
+    page_size = int(config("PAGE_SIZE", "0"))
+    max_pages = int(config("MAX_PAGES", "1000"))
+
+    url = config("URL")
+    params = {}
+    if page_size:
+        params[config("PAGE_SIZE_PARAM", "limit")] = page_size
+    if pagination == "page":
+        params[param] = 1
+    elif pagination == "offset":
+        params[param] = 0
+
+    for count in range(1, max_pages + 1):
+        resp = fetch(session, url, params)
+        body = resp.json()
+        records = lookup(body, config("RECORDS_PATH"))
+        if records is None:
+            records = []
+        if not isinstance(records, list):
+            raise ValueError("records at {!r} of response are not a list".format(config("RECORDS_PATH")))
+        print("-- page {}: {} records".format(count, len(records)))
+        yield records
+
+        if pagination == "page" or pagination == "offset":
+            if not records or (page_size and len(records) < page_size):
+                return
+            params[param] += 1 if pagination == "page" else len(records)
+        elif pagination == "cursor":
+            cursor = lookup(body, config("CURSOR_PATH"))
+            if not cursor:
+                return
+            params[param] = cursor
+        elif pagination == "link":
+            next_link = resp.links.get("next", {}).get("url")
+            if not next_link:
+                return
+            # next links carry all the query params needed
+            url, params = next_link, {}
+        else:
+            return
+    raise RuntimeError("api had more than MAX_PAGES {} pages, raise MAX_PAGES if that is expected".format(max_pages))
+
+
+def main():
+    session = requests.Session()
+    session.headers.update(headers())
+    if config("BODY"):
+        session.headers.setdefault("Content-Type", "application/json")
+
+    # records are loaded once all pages are read, so a failing run loads nothing
+    records = [record for page in pages(session) for record in page]
+
+    table = config("TARGET_TABLE")
+    if ":" in table:
+        table = table.replace(":", ".", 1)
+    disposition = bigquery.WriteDisposition.WRITE_APPEND
+    if config("LOAD_METHOD", "APPEND").upper() == "REPLACE":
+        disposition = bigquery.WriteDisposition.WRITE_TRUNCATE
+    if not records and disposition == bigquery.WriteDisposition.WRITE_APPEND:
+        print("-- no records to load")
+        return
+
+    client = bigquery.Client(project=table.split(".")[0])
+    job = client.load_table_from_json(records, table, job_config=bigquery.LoadJobConfig(
+        autodetect=True,
+        write_disposition=disposition,
+        schema_update_options=[bigquery.SchemaUpdateOption.ALLOW_FIELD_ADDITION]
+        if disposition == bigquery.WriteDisposition.WRITE_APPEND else None,
+    ))
+    job.result()
+    print("-- loaded {} records into {}".format(len(records), table))
+
+
+if __name__ == "__main__":
+    try:
+        main()
+    except Exception as e:
+        print("-- ingestion failed: {}".format(e), file=sys.stderr)
+        sys.exit(1)
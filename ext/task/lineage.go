@@ -0,0 +1,49 @@
+package task
+
+import (
+	"strings"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// ConfigDestination declares the urn of what a script task writes to,
+	// e.g. bigquery://project:dataset.table for bigquery tables
+	ConfigDestination = "DESTINATION"
+	// ConfigSources declares comma separated urns of destinations of other
+	// jobs a script task reads from, jobs writing to them become its
+	// dependencies
+	ConfigSources = "SOURCES"
+)
+
+// Destination returns the destination declared in config, scripts can't be
+// parsed for what they write to so tasks running them rely on declarations.
+// Destinations declared the way they were before urns are converted to urns
+func Destination(config models.PluginConfigs) string {
+	destination, _ := config.Get(ConfigDestination)
+	return models.CanonicalURN(destination.Value)
+}
+
+// Sources returns the sources declared in config
+func Sources(config models.PluginConfigs) []string {
+	sources, _ := config.Get(ConfigSources)
+	var list []string
+	for _, source := range strings.Split(sources.Value, ",") {
+		if source = models.CanonicalURN(source); source != "" {
+			list = append(list, source)
+		}
+	}
+	return list
+}
+
+// ValidateLineage returns problems with the declared destination and sources
+func ValidateLineage(config models.PluginConfigs) []string {
+	var errs []string
+	destination := Destination(config)
+	for _, source := range Sources(config) {
+		if source == destination {
+			errs = append(errs, ConfigSources+" contains the destination "+destination+", a job can't depend on itself")
+		}
+	}
+	return errs
+}
@@ -0,0 +1,45 @@
+#!/bin/bash
+set -euo pipefail
+
+# wait for few seconds to prepare scheduler for the run
+sleep 5
+
+echo "-- initializing optimus assets"
+OPTIMUS_ADMIN_ENABLED=1 /opt/optimus admin build instance "$JOB_NAME" --project "$PROJECT" --output-dir "$JOB_DIR" \
+  --type "$INSTANCE_TYPE" --name "$INSTANCE_NAME" --scheduled-at "$SCHEDULED_AT" --host "$OPTIMUS_HOSTNAME"
+
+echo "-- exporting env"
+set -o allexport
+source "$JOB_DIR/in/.env"
+set +o allexport
+
+gcloud auth activate-service-account --key-file /opt/optimus/secrets/auth.json
+
+# package python assets other than the driver as py files of the job and the
+# rest as its files
+cd "$JOB_DIR/in"
+submit_args=()
+py_files=$(find . -maxdepth 1 -type f -name "*.py" ! -name main.py)
+if [ -n "$py_files" ]; then
+  zip -q /tmp/deps.zip $py_files
+  submit_args+=(--py-files=/tmp/deps.zip)
+fi
+files=$(find . -maxdepth 1 -type f ! -name "*.py" ! -name ".env" -printf "%f," | sed "s/,$//")
+if [ -n "$files" ]; then
+  submit_args+=(--files="$files")
+fi
+if [ -n "${PROPERTIES:-}" ]; then
+  submit_args+=(--properties="$PROPERTIES")
+fi
+labels="optimus-project=${PROJECT},optimus-job=${JOB_NAME//_/-}"
+
+echo "-- submitting main.py in ${MODE} mode"
+if [ "$MODE" = "serverless" ]; then
+  if [ -n "${RUNTIME_VERSION:-}" ]; then
+    submit_args+=(--version="$RUNTIME_VERSION")
+  fi
+  exec gcloud dataproc batches submit pyspark main.py --project="$GCP_PROJECT" --region="$REGION" \
+    --deps-bucket="$DEPS_BUCKET" --labels="$labels" "${submit_args[@]}"
+fi
+exec gcloud dataproc jobs submit pyspark main.py --project="$GCP_PROJECT" --region="$REGION" \
+  --cluster="$CLUSTER" --labels="$labels" "${submit_args[@]}"
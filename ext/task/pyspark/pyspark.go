@@ -0,0 +1,202 @@
+package pyspark
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/odpf/optimus/ext/task"
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	Name    = "pyspark"
+	Version = "0.1.0"
+	Image   = "docker.io/odpf/optimus-task-pyspark:" + Version
+
+	// SecretPath is where the service account key dataproc jobs are submitted
+	// with is mounted
+	SecretPath = "/opt/optimus/secrets/auth.json"
+
+	// MainFile is the asset run as the driver of spark job, other python
+	// assets are packaged as py files of the job and rest as its files
+	MainFile = "main.py"
+
+	ConfigProject        = "GCP_PROJECT"
+	ConfigRegion         = "REGION"
+	ConfigMode           = "MODE"
+	ConfigCluster        = "CLUSTER"
+	ConfigDepsBucket     = "DEPS_BUCKET"
+	ConfigRuntimeVersion = "RUNTIME_VERSION"
+	ConfigProperties     = "PROPERTIES"
+
+	// ModeServerless submits scripts as dataproc serverless batches
+	ModeServerless = "serverless"
+	// ModeCluster submits scripts as jobs to an existing dataproc cluster
+	ModeCluster = "cluster"
+
+	defaultRegion = "us-central1"
+)
+
+var This = &PySpark{}
+
+const defaultMain = `import os
+
+from pyspark.sql import SparkSession
+
+# start and end of the window of the run, e.g. 2021-06-01T00:00:00Z
+DSTART = os.environ["DSTART"]
+DEND = os.environ["DEND"]
+
+spark = SparkSession.builder.appName(os.environ["JOB_NAME"]).getOrCreate()
+`
+
+// PySpark runs python scripts of jobs as spark jobs on dataproc, either as
+// serverless batches or jobs submitted to a cluster
+type PySpark struct{}
+
+func (p *PySpark) PluginInfo() (*models.PluginInfoResponse, error) {
+	return &models.PluginInfoResponse{
+		Name:          Name,
+		Description:   "Run PySpark scripts on Dataproc serverless or a Dataproc cluster",
+		PluginType:    models.PluginTypeTask,
+		PluginMods:    []models.PluginMod{models.ModTypeCLI, models.ModTypeDependencyResolver, models.ModTypeConfigValidator},
+		PluginVersion: Version,
+		Image:         Image,
+		SecretPath:    SecretPath,
+	}, nil
+}
+
+func (p *PySpark) GetQuestions(ctx context.Context, req models.GetQuestionsRequest) (*models.GetQuestionsResponse, error) {
+	return &models.GetQuestionsResponse{
+		Questions: models.PluginQuestions{
+			{
+				Name:   ConfigProject,
+				Prompt: "GCP project to run spark jobs in",
+			},
+			{
+				Name:    ConfigRegion,
+				Prompt:  "Dataproc region",
+				Default: defaultRegion,
+			},
+			{
+				Name:        ConfigMode,
+				Prompt:      "Run as",
+				Help:        "serverless runs the script as a Dataproc serverless batch, cluster submits it to an existing cluster",
+				Multiselect: []string{ModeServerless, ModeCluster},
+				SubQuestions: []models.PluginSubQuestion{
+					{
+						IfValue: ModeServerless,
+						Questions: models.PluginQuestions{
+							{
+								Name:   ConfigDepsBucket,
+								Prompt: "Bucket scripts are staged in",
+								Help:   "e.g. gs://spark-deps",
+							},
+						},
+					},
+					{
+						IfValue: ModeCluster,
+						Questions: models.PluginQuestions{
+							{
+								Name:   ConfigCluster,
+								Prompt: "Dataproc cluster",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:   task.ConfigDestination,
+				Prompt: "Destination the script writes to",
+				Help:   "urn used to infer dependencies of jobs reading it, e.g. bigquery://project:dataset.table, can be left empty",
+			},
+		},
+	}, nil
+}
+
+func (p *PySpark) ValidateQuestion(ctx context.Context, req models.ValidateQuestionRequest) (*models.ValidateQuestionResponse, error) {
+	value := strings.TrimSpace(req.Answer.Value)
+	switch req.Answer.Question.Name {
+	case ConfigProject, ConfigRegion, ConfigCluster:
+		if value == "" {
+			return &models.ValidateQuestionResponse{Error: fmt.Sprintf("%s is required", req.Answer.Question.Name)}, nil
+		}
+	case ConfigDepsBucket:
+		if !strings.HasPrefix(value, "gs://") {
+			return &models.ValidateQuestionResponse{Error: "bucket should be a gcs path like gs://spark-deps"}, nil
+		}
+	}
+	return &models.ValidateQuestionResponse{Success: true}, nil
+}
+
+func (p *PySpark) DefaultConfig(ctx context.Context, req models.DefaultConfigRequest) (*models.DefaultConfigResponse, error) {
+	var config models.PluginConfigs
+	for _, name := range []string{ConfigProject, ConfigRegion, ConfigMode, ConfigDepsBucket, ConfigCluster, task.ConfigDestination} {
+		if answer, ok := req.Answers.Get(name); ok && answer.Value != "" {
+			config = append(config, models.PluginConfig{Name: name, Value: answer.Value})
+		}
+	}
+	return &models.DefaultConfigResponse{Config: config}, nil
+}
+
+func (p *PySpark) DefaultAssets(ctx context.Context, req models.DefaultAssetsRequest) (*models.DefaultAssetsResponse, error) {
+	return &models.DefaultAssetsResponse{
+		Assets: models.PluginAssets{
+			{Name: MainFile, Value: defaultMain},
+		},
+	}, nil
+}
+
+func (p *PySpark) CompileAssets(ctx context.Context, req models.CompileAssetsRequest) (*models.CompileAssetsResponse, error) {
+	return &models.CompileAssetsResponse{Assets: req.Assets}, nil
+}
+
+func (p *PySpark) GenerateDestination(ctx context.Context, req models.GenerateDestinationRequest) (*models.GenerateDestinationResponse, error) {
+	return &models.GenerateDestinationResponse{Destination: task.Destination(req.Config)}, nil
+}
+
+func (p *PySpark) GenerateDependencies(ctx context.Context, req models.GenerateDependenciesRequest) (*models.GenerateDependenciesResponse, error) {
+	return &models.GenerateDependenciesResponse{Dependencies: task.Sources(req.Config)}, nil
+}
+
+func (p *PySpark) ValidateConfig(ctx context.Context, req models.ValidateConfigRequest) (*models.ValidateConfigResponse, error) {
+	var errs []string
+	value := func(name string) string {
+		conf, _ := req.Config.Get(name)
+		return strings.TrimSpace(conf.Value)
+	}
+	for _, name := range []string{ConfigProject, ConfigRegion} {
+		if value(name) == "" {
+			errs = append(errs, fmt.Sprintf("%s is required", name))
+		}
+	}
+	switch mode := value(ConfigMode); mode {
+	case ModeServerless:
+		if !strings.HasPrefix(value(ConfigDepsBucket), "gs://") {
+			errs = append(errs, fmt.Sprintf("%s is required in %s mode, set it to a bucket scripts are staged in like gs://spark-deps",
+				ConfigDepsBucket, ModeServerless))
+		}
+	case ModeCluster:
+		if value(ConfigCluster) == "" {
+			errs = append(errs, fmt.Sprintf("%s is required in %s mode, set it to the dataproc cluster jobs are submitted to",
+				ConfigCluster, ModeCluster))
+		}
+		if value(ConfigRuntimeVersion) != "" {
+			errs = append(errs, fmt.Sprintf("%s only applies to %s mode, runtime of cluster is the image it was created with",
+				ConfigRuntimeVersion, ModeServerless))
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("%s %q is not supported, use %s or %s", ConfigMode, mode, ModeServerless, ModeCluster))
+	}
+	for _, property := range strings.Split(value(ConfigProperties), ",") {
+		if property = strings.TrimSpace(property); property != "" && !strings.Contains(property, "=") {
+			errs = append(errs, fmt.Sprintf("%s should be comma separated key=value pairs, %q has no value", ConfigProperties, property))
+		}
+	}
+	if _, ok := req.Assets.Get(MainFile); !ok {
+		errs = append(errs, fmt.Sprintf("asset %s is required, it is run as the driver of spark job", MainFile))
+	}
+	errs = append(errs, task.ValidateLineage(req.Config)...)
+	return &models.ValidateConfigResponse{Errors: errs}, nil
+}
@@ -0,0 +1,76 @@
+package pyspark_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/optimus/ext/task/pyspark"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPySpark(t *testing.T) {
+	ctx := context.Background()
+	assets := models.PluginAssets{{Name: "main.py", Value: "print('hello')"}}
+
+	t.Run("ValidateConfig", func(t *testing.T) {
+		t.Run("should accept serverless config with staging bucket", func(t *testing.T) {
+			resp, err := pyspark.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "GCP_PROJECT", Value: "shop-project"},
+					{Name: "REGION", Value: "us-central1"},
+					{Name: "MODE", Value: "serverless"},
+					{Name: "DEPS_BUCKET", Value: "gs://spark-deps"},
+					{Name: "PROPERTIES", Value: "spark.executor.instances=4, spark.executor.memory=8g"},
+				},
+				Assets: assets,
+			})
+			assert.Nil(t, err)
+			assert.Empty(t, resp.Errors)
+		})
+		t.Run("should reject cluster config without cluster", func(t *testing.T) {
+			resp, err := pyspark.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "GCP_PROJECT", Value: "shop-project"},
+					{Name: "REGION", Value: "us-central1"},
+					{Name: "MODE", Value: "cluster"},
+					{Name: "RUNTIME_VERSION", Value: "2.0"},
+				},
+				Assets: assets,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				"CLUSTER is required in cluster mode, set it to the dataproc cluster jobs are submitted to",
+				"RUNTIME_VERSION only applies to serverless mode, runtime of cluster is the image it was created with",
+			}, resp.Errors)
+		})
+		t.Run("should reject unknown mode, invalid properties and missing driver", func(t *testing.T) {
+			resp, err := pyspark.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "MODE", Value: "local"},
+					{Name: "PROPERTIES", Value: "spark.executor.instances"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				"GCP_PROJECT is required",
+				"REGION is required",
+				`MODE "local" is not supported, use serverless or cluster`,
+				`PROPERTIES should be comma separated key=value pairs, "spark.executor.instances" has no value`,
+				"asset main.py is required, it is run as the driver of spark job",
+			}, resp.Errors)
+		})
+	})
+	t.Run("should declare destination and dependencies from config", func(t *testing.T) {
+		config := models.PluginConfigs{
+			{Name: "DESTINATION", Value: "shop-project:analytics.revenue"},
+			{Name: "SOURCES", Value: "shop-project:analytics.orders, shop-project:raw.payments"},
+		}
+		dest, err := pyspark.This.GenerateDestination(ctx, models.GenerateDestinationRequest{Config: config})
+		assert.Nil(t, err)
+		assert.Equal(t, "bigquery://shop-project:analytics.revenue", dest.Destination)
+		deps, err := pyspark.This.GenerateDependencies(ctx, models.GenerateDependenciesRequest{Config: config})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"bigquery://shop-project:analytics.orders", "bigquery://shop-project:raw.payments"}, deps.Dependencies)
+	})
+}
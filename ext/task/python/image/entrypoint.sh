@@ -0,0 +1,23 @@
+#!/bin/bash
+set -euo pipefail
+
+# wait for few seconds to prepare scheduler for the run
+sleep 5
+
+echo "-- initializing optimus assets"
+OPTIMUS_ADMIN_ENABLED=1 /opt/optimus admin build instance "$JOB_NAME" --project "$PROJECT" --output-dir "$JOB_DIR" \
+  --type "$INSTANCE_TYPE" --name "$INSTANCE_NAME" --scheduled-at "$SCHEDULED_AT" --host "$OPTIMUS_HOSTNAME"
+
+echo "-- exporting env"
+set -o allexport
+source "$JOB_DIR/in/.env"
+set +o allexport
+
+cd "$JOB_DIR/in"
+if [ -s requirements.txt ]; then
+  echo "-- installing requirements"
+  pip install --no-cache-dir -r requirements.txt
+fi
+
+echo "-- running main.py"
+exec python main.py ${ARGS:-}
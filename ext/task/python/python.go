@@ -0,0 +1,115 @@
+package python
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/odpf/optimus/ext/task"
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	Name    = "python"
+	Version = "0.1.0"
+	Image   = "docker.io/odpf/optimus-task-python:" + Version
+
+	// MainFile is the asset run with python, along with it all the assets
+	// are in the working directory so it can import the other scripts
+	MainFile = "main.py"
+	// RequirementsFile is the asset listing packages installed before the
+	// script runs, in the pip requirements format
+	RequirementsFile = "requirements.txt"
+
+	// ConfigArgs are passed to the script as command line arguments
+	ConfigArgs = "ARGS"
+)
+
+var This = &Python{}
+
+const defaultMain = `import os
+
+# start and end of the window of the run, e.g. 2021-06-01T00:00:00Z
+DSTART = os.environ["DSTART"]
+DEND = os.environ["DEND"]
+`
+
+// Python runs python scripts of jobs in a container, with packages listed in
+// requirements of the job installed
+type Python struct{}
+
+func (p *Python) PluginInfo() (*models.PluginInfoResponse, error) {
+	return &models.PluginInfoResponse{
+		Name:          Name,
+		Description:   "Run python scripts in a container",
+		PluginType:    models.PluginTypeTask,
+		PluginMods:    []models.PluginMod{models.ModTypeCLI, models.ModTypeDependencyResolver, models.ModTypeConfigValidator},
+		PluginVersion: Version,
+		Image:         Image,
+	}, nil
+}
+
+func (p *Python) GetQuestions(ctx context.Context, req models.GetQuestionsRequest) (*models.GetQuestionsResponse, error) {
+	return &models.GetQuestionsResponse{
+		Questions: models.PluginQuestions{
+			{
+				Name:   task.ConfigDestination,
+				Prompt: "Destination the script writes to",
+				Help:   "urn used to infer dependencies of jobs reading it, e.g. bigquery://project:dataset.table, can be left empty",
+			},
+		},
+	}, nil
+}
+
+func (p *Python) ValidateQuestion(ctx context.Context, req models.ValidateQuestionRequest) (*models.ValidateQuestionResponse, error) {
+	return &models.ValidateQuestionResponse{Success: true}, nil
+}
+
+func (p *Python) DefaultConfig(ctx context.Context, req models.DefaultConfigRequest) (*models.DefaultConfigResponse, error) {
+	var config models.PluginConfigs
+	if answer, ok := req.Answers.Get(task.ConfigDestination); ok && answer.Value != "" {
+		config = append(config, models.PluginConfig{Name: task.ConfigDestination, Value: answer.Value})
+	}
+	return &models.DefaultConfigResponse{Config: config}, nil
+}
+
+func (p *Python) DefaultAssets(ctx context.Context, req models.DefaultAssetsRequest) (*models.DefaultAssetsResponse, error) {
+	return &models.DefaultAssetsResponse{
+		Assets: models.PluginAssets{
+			{Name: MainFile, Value: defaultMain},
+			{Name: RequirementsFile, Value: ""},
+		},
+	}, nil
+}
+
+func (p *Python) CompileAssets(ctx context.Context, req models.CompileAssetsRequest) (*models.CompileAssetsResponse, error) {
+	return &models.CompileAssetsResponse{Assets: req.Assets}, nil
+}
+
+func (p *Python) GenerateDestination(ctx context.Context, req models.GenerateDestinationRequest) (*models.GenerateDestinationResponse, error) {
+	return &models.GenerateDestinationResponse{Destination: task.Destination(req.Config)}, nil
+}
+
+func (p *Python) GenerateDependencies(ctx context.Context, req models.GenerateDependenciesRequest) (*models.GenerateDependenciesResponse, error) {
+	return &models.GenerateDependenciesResponse{Dependencies: task.Sources(req.Config)}, nil
+}
+
+func (p *Python) ValidateConfig(ctx context.Context, req models.ValidateConfigRequest) (*models.ValidateConfigResponse, error) {
+	var errs []string
+	if main, ok := req.Assets.Get(MainFile); !ok || strings.TrimSpace(main.Value) == "" {
+		errs = append(errs, fmt.Sprintf("asset %s is required, it is the script run by the job", MainFile))
+	}
+	if requirements, ok := req.Assets.Get(RequirementsFile); ok {
+		for _, line := range strings.Split(requirements.Value, "\n") {
+			line = strings.TrimSpace(line)
+			// only assets are shipped with the job, files on the machine
+			// of the author can't be installed
+			if strings.HasPrefix(line, "-e") || strings.HasPrefix(line, ".") || strings.HasPrefix(line, "/") {
+				errs = append(errs, fmt.Sprintf("%s can't install local packages, %q should be a package from an index",
+					RequirementsFile, line))
+			}
+		}
+	}
+	errs = append(errs, task.ValidateLineage(req.Config)...)
+	return &models.ValidateConfigResponse{Errors: errs}, nil
+}
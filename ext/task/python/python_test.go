@@ -0,0 +1,44 @@
+package python_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/optimus/ext/task/python"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPython(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ValidateConfig", func(t *testing.T) {
+		t.Run("should accept script with requirements from an index", func(t *testing.T) {
+			resp, err := python.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Assets: models.PluginAssets{
+					{Name: "main.py", Value: "import requests"},
+					{Name: "requirements.txt", Value: "# http\nrequests==2.26.0\n"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Empty(t, resp.Errors)
+		})
+		t.Run("should reject missing script, local packages and job depending on itself", func(t *testing.T) {
+			resp, err := python.This.ValidateConfig(ctx, models.ValidateConfigRequest{
+				Config: models.PluginConfigs{
+					{Name: "DESTINATION", Value: "shop-project:analytics.revenue"},
+					{Name: "SOURCES", Value: "shop-project:analytics.revenue"},
+				},
+				Assets: models.PluginAssets{
+					{Name: "requirements.txt", Value: "-e ./shared"},
+				},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, []string{
+				"asset main.py is required, it is the script run by the job",
+				`requirements.txt can't install local packages, "-e ./shared" should be a package from an index`,
+				"SOURCES contains the destination bigquery://shop-project:analytics.revenue, a job can't depend on itself",
+			}, resp.Errors)
+		})
+	})
+}
@@ -0,0 +1,134 @@
+// Package extension helps building binaries which extend optimus cli with
+// custom subcommands. Executables named optimus-<name> on PATH, or registered
+// under extensions in optimus config, are exposed as `optimus <name>` and get
+// the host of optimus server and the actor of cli through env
+package extension
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/config"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// Prefix of extension binaries, optimus-<name> is exposed as `optimus <name>`
+	Prefix = "optimus-"
+
+	// EnvHost carries the optimus server host cli is configured with
+	EnvHost = "OPTIMUS_HOST"
+
+	// EnvActor carries the user of cli, calls made by extensions are audited
+	// against this actor
+	EnvActor = "OPTIMUS_ACTOR"
+
+	grpcMaxClientSendSize = 45 << 20 // 45MB
+	grpcMaxClientRecvSize = 45 << 20 // 45MB
+)
+
+// Discover finds extensions in directories of pathList(PATH env format) and
+// merges them with registered ones, registered extensions take precedence
+// over discovered ones. For duplicate binaries only the first found is used.
+// Task plugins share the prefix but carry _<os>_<arch> suffix and are skipped
+func Discover(pathList string, registered map[string]string) map[string]string {
+	var (
+		pluginSuffix = fmt.Sprintf("_%s_%s", runtime.GOOS, runtime.GOARCH)
+		extensions   = map[string]string{}
+	)
+	for _, dirPath := range filepath.SplitList(pathList) {
+		fileInfos, err := ioutil.ReadDir(dirPath)
+		if err != nil {
+			continue
+		}
+		for _, item := range fileInfos {
+			fullName := item.Name()
+			if !strings.HasPrefix(fullName, Prefix) || strings.HasSuffix(fullName, pluginSuffix) {
+				continue
+			}
+			name := strings.TrimSuffix(strings.TrimPrefix(fullName, Prefix), filepath.Ext(fullName))
+			if name == "" {
+				continue
+			}
+			if _, ok := extensions[name]; ok {
+				continue
+			}
+
+			absPath, err := filepath.Abs(filepath.Join(dirPath, fullName))
+			if err != nil {
+				continue
+			}
+			// item could be a symlink, check the target
+			info, err := os.Stat(absPath)
+			if err != nil || info.IsDir() || !isExecutable(info) {
+				continue
+			}
+			extensions[name] = filepath.Clean(absPath)
+		}
+	}
+	for name, path := range registered {
+		extensions[name] = path
+	}
+	return extensions
+}
+
+func isExecutable(info os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0111 != 0
+}
+
+// Config loads optimus config the same way cli does, host passed by cli
+// through env takes precedence over config file
+func Config() (config.Provider, error) {
+	return config.InitOptimus()
+}
+
+// Dial connects to optimus server cli is configured with, calls are audited
+// against the actor of cli
+func Dial(ctx context.Context, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	host := os.Getenv(EnvHost)
+	if host == "" {
+		conf, err := Config()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load optimus config")
+		}
+		host = conf.GetHost()
+	}
+	if host == "" {
+		return nil, errors.New("optimus host is not configured")
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallSendMsgSize(grpcMaxClientSendSize),
+			grpc.MaxCallRecvMsgSize(grpcMaxClientRecvSize),
+		),
+	}
+	if actor := os.Getenv(EnvActor); actor != "" {
+		dialOpts = append(dialOpts,
+			grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{},
+				cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				ctx = metadata.AppendToOutgoingContext(ctx, v1handler.MetadataActor, actor)
+				return invoker(ctx, method, req, reply, cc, opts...)
+			}),
+			grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+				method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				ctx = metadata.AppendToOutgoingContext(ctx, v1handler.MetadataActor, actor)
+				return streamer(ctx, desc, cc, method, opts...)
+			}),
+		)
+	}
+	return grpc.DialContext(ctx, host, append(dialOpts, opts...)...)
+}
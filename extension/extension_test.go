@@ -0,0 +1,56 @@
+package extension_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/odpf/optimus/extension"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscover(t *testing.T) {
+	writeBinary := func(t *testing.T, dir, name string, perm os.FileMode) string {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"), perm))
+		return path
+	}
+
+	t.Run("should discover extensions from path and prefer the first found", func(t *testing.T) {
+		first, err := ioutil.TempDir("", "optimus-ext")
+		assert.Nil(t, err)
+		defer os.RemoveAll(first)
+		second, err := ioutil.TempDir("", "optimus-ext")
+		assert.Nil(t, err)
+		defer os.RemoveAll(second)
+
+		lintPath := writeBinary(t, first, "optimus-lint", 0755)
+		writeBinary(t, second, "optimus-lint", 0755)
+		costPath := writeBinary(t, second, "optimus-cost", 0755)
+		writeBinary(t, second, "optimus-notes", 0644)
+		writeBinary(t, second, fmt.Sprintf("optimus-bq2bq_%s_%s", runtime.GOOS, runtime.GOARCH), 0755)
+		writeBinary(t, second, "lint", 0755)
+
+		extensions := extension.Discover(first+string(os.PathListSeparator)+second, nil)
+		assert.Equal(t, map[string]string{
+			"lint": lintPath,
+			"cost": costPath,
+		}, extensions)
+	})
+	t.Run("should prefer registered extensions over discovered ones", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "optimus-ext")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+		writeBinary(t, dir, "optimus-lint", 0755)
+
+		extensions := extension.Discover(dir, map[string]string{
+			"lint": "/opt/tools/lint",
+		})
+		assert.Equal(t, map[string]string{
+			"lint": "/opt/tools/lint",
+		}, extensions)
+	})
+}
@@ -0,0 +1,145 @@
+package gitsync
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// Actor is recorded in audit log and deploy changelog for the
+	// deployments made by git sync, unless overridden with WithActor
+	Actor = "git-sync"
+)
+
+// Deployer deploys specifications of a namespace, specifications missing
+// in the request are deleted just like a deployment from cli
+type Deployer interface {
+	DeployResources(ctx context.Context, projectName, namespace, datastoreName string, specs []models.ResourceSpec) error
+	DeployJobs(ctx context.Context, projectName, namespace string, specs []models.JobSpec) error
+}
+
+// grpcDeployer deploys through runtime service so git sync is subject to the
+// same freeze, quota, audit and changelog as deployments from cli
+type grpcDeployer struct {
+	runtime pb.RuntimeServiceClient
+	adapter v1handler.ProtoAdapter
+}
+
+func (d *grpcDeployer) DeployResources(ctx context.Context, projectName, namespace, datastoreName string,
+	specs []models.ResourceSpec) error {
+	adaptedSpecs := []*pb.ResourceSpecification{}
+	for _, spec := range specs {
+		adapted, err := d.adapter.ToResourceProto(spec)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize: %s", spec.Name)
+		}
+		adaptedSpecs = append(adaptedSpecs, adapted)
+	}
+
+	respStream, err := d.runtime.DeployResourceSpecification(actorContext(ctx), &pb.DeployResourceSpecificationRequest{
+		Resources:     adaptedSpecs,
+		ProjectName:   projectName,
+		DatastoreName: datastoreName,
+		Namespace:     namespace,
+	})
+	if err != nil {
+		return errors.Wrap(err, "deployment failed")
+	}
+	for {
+		resp, err := respStream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "failed to receive deployment ack")
+		}
+		if resp.Ack && !resp.GetSuccess() {
+			return errors.Errorf("unable to deploy: %s %s", resp.GetResourceName(), resp.GetMessage())
+		}
+	}
+}
+
+func (d *grpcDeployer) DeployJobs(ctx context.Context, projectName, namespace string, specs []models.JobSpec) error {
+	var adaptedSpecs []*pb.JobSpecification
+	for _, spec := range specs {
+		adapted, err := d.adapter.ToJobProto(spec)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize: %s", spec.Name)
+		}
+		adaptedSpecs = append(adaptedSpecs, adapted)
+	}
+
+	respStream, err := d.runtime.DeployJobSpecification(commitContext(actorContext(ctx)), &pb.DeployJobSpecificationRequest{
+		Jobs:        adaptedSpecs,
+		ProjectName: projectName,
+		Namespace:   namespace,
+	})
+	if err != nil {
+		return errors.Wrap(err, "deployment failed")
+	}
+	for {
+		resp, err := respStream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "failed to receive deployment ack")
+		}
+		if resp.Ack && !resp.GetSuccess() {
+			return errors.Errorf("unable to deploy: %s %s", resp.GetJobName(), resp.GetMessage())
+		}
+	}
+}
+
+type actorKey struct{}
+
+// WithActor records actor instead of Actor for the deployments made
+// with returned context
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func actorContext(ctx context.Context) context.Context {
+	actor := Actor
+	if val, ok := ctx.Value(actorKey{}).(string); ok && val != "" {
+		actor = val
+	}
+	return metadata.AppendToOutgoingContext(ctx, v1handler.MetadataActor, actor)
+}
+
+type commitKey struct{}
+
+// WithCommit posts result of the job deployments made with returned context
+// back to the commit specifications are of, see
+// v1handler.MetadataCommitRepository
+func WithCommit(ctx context.Context, ref models.CommitRef) context.Context {
+	return context.WithValue(ctx, commitKey{}, ref)
+}
+
+func commitContext(ctx context.Context) context.Context {
+	ref, ok := ctx.Value(commitKey{}).(models.CommitRef)
+	if !ok || ref.SHA == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx,
+		v1handler.MetadataCommitRepository, ref.Repository,
+		v1handler.MetadataCommitSHA, ref.SHA,
+		v1handler.MetadataCommitSpecPath, ref.SpecPath,
+	)
+}
+
+// NewDeployer deploys specifications using runtime service client, usually
+// connected to the server running git sync itself
+func NewDeployer(runtime pb.RuntimeServiceClient, adapter v1handler.ProtoAdapter) Deployer {
+	return &grpcDeployer{
+		runtime: runtime,
+		adapter: adapter,
+	}
+}
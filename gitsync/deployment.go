@@ -0,0 +1,304 @@
+package gitsync
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/odpf/optimus/utils"
+)
+
+const (
+	// DeploymentActor is recorded for deployments requested without
+	// x-actor header
+	DeploymentActor = "ci"
+
+	// deployments are checked out in a directory which can't clash
+	// with checkouts of projects
+	deploymentsDirectory = ".deployments"
+
+	maxTarballSize = 100 * 1024 * 1024
+)
+
+// DeploymentRequest deploys specifications of a git ref of the project
+// repository, see ProjectGitSyncURLKey
+type DeploymentRequest struct {
+	Ref string `json:"ref"`
+}
+
+// DeploymentResponse is the status of a deployment served over http
+type DeploymentResponse struct {
+	ID          string    `json:"id"`
+	ProjectName string    `json:"project_name"`
+	Source      string    `json:"source"`
+	Actor       string    `json:"actor,omitempty"`
+	Status      string    `json:"status"`
+	Message     string    `json:"message,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DeploymentHandler lets CI systems without grpc support deploy a project
+// identified by project query param. POST with a gzipped tarball of
+// specifications, laid out the way git sync expects, or a DeploymentRequest
+// starts a deployment in background and returns its id, GET with id query
+// param returns status of the deployment. Calls should carry the
+// ProjectSecretDeployToken secret of project as bearer token
+type DeploymentHandler struct {
+	syncer             *Syncer
+	deploymentRepo     store.DeploymentRepository
+	projectRepoFactory ProjectRepoFactory
+	uuidProvider       utils.UUIDProvider
+}
+
+func (h *DeploymentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if status, err := authenticate(r, projSpec); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	var deployment models.Deployment
+	switch r.Method {
+	case http.MethodGet:
+		id, err := uuid.Parse(r.URL.Query().Get("id"))
+		if err != nil {
+			http.Error(w, "valid deployment id is required", http.StatusBadRequest)
+			return
+		}
+		if deployment, err = h.deploymentRepo.GetByID(projSpec, id); err != nil {
+			if errors.Is(err, store.ErrResourceNotFound) {
+				http.Error(w, "deployment "+id.String()+" not found", http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+	case http.MethodPost:
+		var status int
+		if deployment, status, err = h.create(w, r, projSpec); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("%s?project=%s&id=%s", r.URL.Path, projSpec.Name, deployment.ID))
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(DeploymentResponse{
+		ID:          deployment.ID.String(),
+		ProjectName: projSpec.Name,
+		Source:      deployment.Source,
+		Actor:       deployment.Actor,
+		Status:      deployment.Status,
+		Message:     deployment.Message,
+		CreatedAt:   deployment.CreatedAt,
+		UpdatedAt:   deployment.UpdatedAt,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// create prepares specifications of request in a directory of its own and
+// deploys them in background, returning the http status of failure if any
+func (h *DeploymentHandler) create(w http.ResponseWriter, r *http.Request, projSpec models.ProjectSpec) (models.Deployment, int, error) {
+	id, err := h.uuidProvider.NewUUID()
+	if err != nil {
+		return models.Deployment{}, http.StatusInternalServerError, err
+	}
+	deployment := models.Deployment{
+		ID:        id,
+		Actor:     r.Header.Get(v1handler.MetadataActor),
+		Status:    models.DeploymentStatusPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if deployment.Actor == "" {
+		deployment.Actor = DeploymentActor
+	}
+
+	var ref string
+	dir := filepath.Join(h.syncer.workDir, deploymentsDirectory, id.String())
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		var req DeploymentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return deployment, http.StatusBadRequest, errors.Wrap(err, "invalid deployment")
+		}
+		if strings.TrimSpace(req.Ref) == "" {
+			return deployment, http.StatusBadRequest, errors.New("ref is required")
+		}
+		if !IsEnabled(projSpec) {
+			return deployment, http.StatusBadRequest, errors.Errorf("git repository is not configured for project %s", projSpec.Name)
+		}
+		ref = req.Ref
+		deployment.Source = "ref " + ref
+	case "application/gzip", "application/x-gzip":
+		// body is read before responding so deployment doesn't depend on
+		// the connection to client
+		if err := extractTarball(http.MaxBytesReader(w, r.Body, maxTarballSize), dir); err != nil {
+			os.RemoveAll(dir)
+			return deployment, http.StatusBadRequest, errors.Wrap(err, "invalid tarball")
+		}
+		deployment.Source = "tarball"
+	default:
+		return deployment, http.StatusUnsupportedMediaType, errors.New("specifications should be sent as application/gzip tarball or application/json ref")
+	}
+
+	if err := h.deploymentRepo.Insert(projSpec, &deployment); err != nil {
+		os.RemoveAll(dir)
+		return deployment, http.StatusInternalServerError, err
+	}
+	if !h.syncer.background(func(ctx context.Context) {
+		h.run(ctx, projSpec, deployment, ref, dir)
+	}) {
+		os.RemoveAll(dir)
+		h.updateStatus(deployment.ID, models.DeploymentStatusFailed, "server is shutting down")
+		return deployment, http.StatusServiceUnavailable, errors.New("server is shutting down")
+	}
+	return deployment, 0, nil
+}
+
+func (h *DeploymentHandler) run(ctx context.Context, projSpec models.ProjectSpec, deployment models.Deployment, ref, dir string) {
+	defer os.RemoveAll(dir)
+	h.updateStatus(deployment.ID, models.DeploymentStatusInProgress, "")
+	if err := h.syncer.deploy(WithActor(ctx, deployment.Actor), projSpec, ref, dir); err != nil {
+		logger.E(errors.Wrapf(err, "deployment %s of project %s failed", deployment.ID, projSpec.Name))
+		h.updateStatus(deployment.ID, models.DeploymentStatusFailed, err.Error())
+		return
+	}
+	h.updateStatus(deployment.ID, models.DeploymentStatusSucceeded, "")
+}
+
+func (h *DeploymentHandler) updateStatus(id uuid.UUID, status, message string) {
+	if err := h.deploymentRepo.UpdateStatus(id, status, message); err != nil {
+		logger.E(errors.Wrapf(err, "failed to update status of deployment %s", id))
+	}
+}
+
+// deploy checks out ref of project repository in dir if set, otherwise
+// specifications are expected in dir already
+func (s *Syncer) deploy(ctx context.Context, projSpec models.ProjectSpec, ref, dir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	specDir := dir
+	var commit models.CommitRef
+	if ref != "" {
+		revision, err := s.git.Checkout(ctx, projSpec.Config[models.ProjectGitSyncURLKey], ref, dir)
+		if err != nil {
+			return errors.Wrap(err, "failed to checkout repository")
+		}
+		specDir = filepath.Join(dir, projSpec.Config[models.ProjectGitSyncPathKey])
+		commit = commitRef(projSpec, revision)
+	}
+	return s.deployDir(ctx, projSpec.Name, specDir, commit)
+}
+
+// authenticate checks the bearer token of request against the deploy token
+// of project, projects without one can't be deployed over http
+func authenticate(r *http.Request, projSpec models.ProjectSpec) (int, error) {
+	token, ok := projSpec.Secret.GetByName(models.ProjectSecretDeployToken)
+	if !ok || token == "" {
+		return http.StatusForbidden, errors.Errorf("secret %s is not registered for project %s", models.ProjectSecretDeployToken, projSpec.Name)
+	}
+	bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+		return http.StatusUnauthorized, errors.New("invalid token")
+	}
+	return 0, nil
+}
+
+// extractTarball writes directories and regular files of a gzipped tarball in
+// dir, entries pointing outside of dir are rejected
+func extractTarball(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, header.Name)
+		if target != dir && !strings.HasPrefix(target, dir+string(os.PathSeparator)) {
+			return errors.Errorf("%s is outside of tarball", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func NewDeploymentHandler(syncer *Syncer, deploymentRepo store.DeploymentRepository, projectRepoFactory ProjectRepoFactory,
+	uuidProvider utils.UUIDProvider) *DeploymentHandler {
+	return &DeploymentHandler{
+		syncer:             syncer,
+		deploymentRepo:     deploymentRepo,
+		projectRepoFactory: projectRepoFactory,
+		uuidProvider:       uuidProvider,
+	}
+}
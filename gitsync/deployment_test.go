@@ -0,0 +1,153 @@
+package gitsync_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/gitsync"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestDeploymentHandler(t *testing.T) {
+	logger.InitWithWriter(logger.DEBUG, ioutil.Discard)
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+		Secret: models.ProjectSecrets{
+			{Name: models.ProjectSecretDeployToken, Value: "deploy-token"},
+		},
+	}
+	projectRepo := new(mock.ProjectRepository)
+	projectRepo.On("GetByName", projSpec.Name).Return(projSpec, nil)
+	projectRepoFac := new(mock.ProjectRepoFactory)
+	projectRepoFac.On("New").Return(projectRepo)
+
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name: "foo",
+	}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", "foo").Return(&models.Plugin{Base: execUnit}, nil)
+	datastoreRepo := new(mock.SupportedDatastoreRepo)
+	datastoreRepo.On("GetAll").Return([]models.Datastorer{})
+
+	tarball := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, content := range files {
+			assert.Nil(t, tw.WriteHeader(&tar.Header{
+				Name:     name,
+				Typeflag: tar.TypeReg,
+				Mode:     0600,
+				Size:     int64(len(content)),
+			}))
+			_, err := tw.Write([]byte(content))
+			assert.Nil(t, err)
+		}
+		assert.Nil(t, tw.Close())
+		assert.Nil(t, gz.Close())
+		return buf.Bytes()
+	}
+
+	t.Run("should deploy tarball in background", func(t *testing.T) {
+		deploymentID := uuid.Must(uuid.NewRandom())
+		uuidProvider := new(mock.UUIDProvider)
+		uuidProvider.On("NewUUID").Return(deploymentID, nil)
+
+		deploymentRepo := new(mock.DeploymentRepository)
+		defer deploymentRepo.AssertExpectations(t)
+		deploymentRepo.On("Insert", projSpec, mock2.MatchedBy(func(d *models.Deployment) bool {
+			return d.ID == deploymentID && d.Source == "tarball" && d.Actor == "alice"
+		})).Return(nil)
+		deploymentRepo.On("UpdateStatus", deploymentID, models.DeploymentStatusInProgress, "").Return(nil)
+		deploymentRepo.On("UpdateStatus", deploymentID, models.DeploymentStatusSucceeded, "").Return(nil)
+
+		deployer := new(mock.GitSyncDeployer)
+		defer deployer.AssertExpectations(t)
+		deployer.On("DeployJobs", mock2.Anything, projSpec.Name, "team-a", mock2.MatchedBy(func(specs []models.JobSpec) bool {
+			return len(specs) == 1 && specs[0].Name == "test"
+		})).Return(nil)
+
+		syncer := gitsync.NewSyncer(projectRepoFac, new(mock.Git), deployer, pluginRepo, datastoreRepo, t.TempDir(), time.Minute)
+		req := httptest.NewRequest(http.MethodPost, "/deployments?project=proj", bytes.NewReader(tarball(map[string]string{
+			"team-a/jobs/test/job.yaml": testJobSpec,
+		})))
+		req.Header.Set("Content-Type", "application/gzip")
+		req.Header.Set("Authorization", "Bearer deploy-token")
+		req.Header.Set("X-Actor", "alice")
+		rec := httptest.NewRecorder()
+		gitsync.NewDeploymentHandler(syncer, deploymentRepo, projectRepoFac, uuidProvider).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		var resp gitsync.DeploymentResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, deploymentID.String(), resp.ID)
+		assert.Equal(t, models.DeploymentStatusPending, resp.Status)
+		// waits for the deployment in background
+		assert.Nil(t, syncer.Close())
+	})
+	t.Run("should reject tarball with files outside of it", func(t *testing.T) {
+		uuidProvider := new(mock.UUIDProvider)
+		uuidProvider.On("NewUUID").Return(uuid.Must(uuid.NewRandom()), nil)
+		deploymentRepo := new(mock.DeploymentRepository)
+		defer deploymentRepo.AssertExpectations(t)
+
+		syncer := gitsync.NewSyncer(projectRepoFac, new(mock.Git), new(mock.GitSyncDeployer), pluginRepo, datastoreRepo, t.TempDir(), time.Minute)
+		defer syncer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/deployments?project=proj", bytes.NewReader(tarball(map[string]string{
+			"../team-a/jobs/test/job.yaml": testJobSpec,
+		})))
+		req.Header.Set("Content-Type", "application/gzip")
+		req.Header.Set("Authorization", "Bearer deploy-token")
+		rec := httptest.NewRecorder()
+		gitsync.NewDeploymentHandler(syncer, deploymentRepo, projectRepoFac, uuidProvider).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+	t.Run("should return status of deployment", func(t *testing.T) {
+		deployment := models.Deployment{
+			ID:      uuid.Must(uuid.NewRandom()),
+			Source:  "ref v1.0.0",
+			Status:  models.DeploymentStatusFailed,
+			Message: "namespace team-a not found",
+		}
+		deploymentRepo := new(mock.DeploymentRepository)
+		defer deploymentRepo.AssertExpectations(t)
+		deploymentRepo.On("GetByID", projSpec, deployment.ID).Return(deployment, nil)
+
+		syncer := gitsync.NewSyncer(projectRepoFac, new(mock.Git), new(mock.GitSyncDeployer), pluginRepo, datastoreRepo, t.TempDir(), time.Minute)
+		defer syncer.Close()
+		req := httptest.NewRequest(http.MethodGet, "/deployments?project=proj&id="+deployment.ID.String(), nil)
+		req.Header.Set("Authorization", "Bearer deploy-token")
+		rec := httptest.NewRecorder()
+		gitsync.NewDeploymentHandler(syncer, deploymentRepo, projectRepoFac, new(mock.UUIDProvider)).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var resp gitsync.DeploymentResponse
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, models.DeploymentStatusFailed, resp.Status)
+		assert.Equal(t, "namespace team-a not found", resp.Message)
+	})
+	t.Run("should reject calls without deploy token", func(t *testing.T) {
+		syncer := gitsync.NewSyncer(projectRepoFac, new(mock.Git), new(mock.GitSyncDeployer), pluginRepo, datastoreRepo, t.TempDir(), time.Minute)
+		defer syncer.Close()
+		req := httptest.NewRequest(http.MethodGet, "/deployments?project=proj&id="+uuid.New().String(), nil)
+		req.Header.Set("Authorization", "Bearer guess")
+		rec := httptest.NewRecorder()
+		gitsync.NewDeploymentHandler(syncer, new(mock.DeploymentRepository), projectRepoFac, new(mock.UUIDProvider)).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
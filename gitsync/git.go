@@ -0,0 +1,72 @@
+package gitsync
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Git checks out a ref of a remote repository in a local directory
+type Git interface {
+	// Checkout makes dir a copy of the commit ref, e.g. a branch, tag or
+	// commit hash, points to at url, discarding local changes, and returns
+	// the commit checked out
+	Checkout(ctx context.Context, url, ref, dir string) (string, error)
+}
+
+// cliGit uses git binary available on the host, credentials for private
+// repositories are picked from the git configuration of the server, e.g.
+// ssh keys or credential helpers
+type cliGit struct{}
+
+func (g *cliGit) Checkout(ctx context.Context, url, ref, dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return "", err
+		}
+		if _, err := g.run(ctx, dir, "init", "--quiet"); err != nil {
+			return "", err
+		}
+		if _, err := g.run(ctx, dir, "remote", "add", "origin", url); err != nil {
+			return "", err
+		}
+	} else if _, err := g.run(ctx, dir, "remote", "set-url", "origin", url); err != nil {
+		return "", err
+	}
+	// fetching a ref instead of cloning a branch allows checking out
+	// tags and commits as well
+	if _, err := g.run(ctx, dir, "fetch", "--depth", "1", "origin", ref); err != nil {
+		return "", err
+	}
+	if _, err := g.run(ctx, dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	if _, err := g.run(ctx, dir, "clean", "-fdx"); err != nil {
+		return "", err
+	}
+	return g.run(ctx, dir, "rev-parse", "HEAD")
+}
+
+func (g *cliGit) run(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// never wait for credentials on a terminal the server doesn't have
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// NewGit returns a Git backed by the git binary
+func NewGit() Git {
+	return &cliGit{}
+}
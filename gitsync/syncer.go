@@ -0,0 +1,263 @@
+package gitsync
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/odpf/optimus/store/local"
+)
+
+const (
+	// DefaultBranch is synced when project has no branch configured
+	DefaultBranch = "main"
+
+	// JobsDirectory has job specifications of a namespace, other
+	// directories of a namespace are named after the datastore of
+	// the resource specifications they have
+	JobsDirectory = "jobs"
+)
+
+// ProjectRepoFactory is used to list registered projects
+type ProjectRepoFactory interface {
+	New() store.ProjectRepository
+}
+
+// Syncer deploys specifications of projects from the git repository set in
+// their project config, every directory at the configured path of repository
+// is a namespace. Projects are synced every interval and on demand through
+// webhook, a commit already deployed is skipped by periodic syncs
+type Syncer struct {
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	projectRepoFactory ProjectRepoFactory
+	git                Git
+	deployer           Deployer
+	pluginRepo         models.PluginRepository
+	datastoreRepo      models.DatastoreRepo
+	workDir            string
+	interval           time.Duration
+
+	// last commit deployed of each project
+	revisions map[string]string
+}
+
+// Start syncs projects in background every interval until closed
+func (s *Syncer) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.SyncAll(s.ctx); err != nil {
+					logger.E(errors.Wrap(err, "git sync failed"))
+				}
+			}
+		}
+	}()
+}
+
+// Trigger syncs a project in background even if its latest commit is
+// already deployed
+func (s *Syncer) Trigger(projSpec models.ProjectSpec) {
+	s.background(func(ctx context.Context) {
+		if err := s.Sync(ctx, projSpec, true); err != nil {
+			logger.E(errors.Wrapf(err, "git sync of project %s failed", projSpec.Name))
+		}
+	})
+}
+
+// background runs fn unless syncer is closed, close waits for it to return
+func (s *Syncer) background(fn func(ctx context.Context)) bool {
+	if s.ctx.Err() != nil {
+		return false
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		fn(s.ctx)
+	}()
+	return true
+}
+
+// Close stops syncing, waiting for the ongoing syncs to finish
+func (s *Syncer) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// SyncAll syncs all projects with a git repository configured, failing to
+// sync a project does not stop syncing of the rest
+func (s *Syncer) SyncAll(ctx context.Context) error {
+	projects, err := s.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch projects")
+	}
+	for _, projSpec := range projects {
+		if !IsEnabled(projSpec) {
+			continue
+		}
+		if err := s.Sync(ctx, projSpec, false); err != nil {
+			logger.E(errors.Wrapf(err, "git sync of project %s failed", projSpec.Name))
+		}
+	}
+	return nil
+}
+
+// Sync checks out the configured branch of project repository and deploys
+// specifications of every namespace in it, unless force is set nothing is
+// deployed if the commit checked out is already deployed
+func (s *Syncer) Sync(ctx context.Context, projSpec models.ProjectSpec, force bool) error {
+	if !IsEnabled(projSpec) {
+		return errors.Errorf("git sync is not configured for project %s", projSpec.Name)
+	}
+	// projects share the deployer, syncing them one at a time keeps the
+	// load on server same as a single deployment from cli
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repoDir := filepath.Join(s.workDir, projSpec.Name)
+	revision, err := s.git.Checkout(ctx, projSpec.Config[models.ProjectGitSyncURLKey], branch(projSpec), repoDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to checkout repository")
+	}
+	if !force && s.revisions[projSpec.Name] == revision {
+		return nil
+	}
+
+	if err := s.deployDir(ctx, projSpec.Name, filepath.Join(repoDir, projSpec.Config[models.ProjectGitSyncPathKey]),
+		commitRef(projSpec, revision)); err != nil {
+		return err
+	}
+
+	s.revisions[projSpec.Name] = revision
+	logger.I("git sync of project ", projSpec.Name, " deployed commit ", revision)
+	return nil
+}
+
+// deployDir deploys every directory in specDir as a namespace, results of
+// deploying jobs are posted to commit if it is set. Callers should hold the
+// lock of syncer
+func (s *Syncer) deployDir(ctx context.Context, projectName, specDir string, commit models.CommitRef) error {
+	specFs := afero.NewBasePathFs(afero.NewOsFs(), specDir)
+	entries, err := afero.ReadDir(specFs, ".")
+	if err != nil {
+		return errors.Wrap(err, "failed to read namespaces")
+	}
+	var errorSet error
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		namespaceFs := afero.NewBasePathFs(specFs, entry.Name())
+		if err := s.deployNamespace(ctx, projectName, entry.Name(), namespaceFs, commit); err != nil {
+			errorSet = multierror.Append(errorSet, errors.Wrapf(err, "namespace %s", entry.Name()))
+		}
+	}
+	return errorSet
+}
+
+// deployNamespace deploys resources before jobs so jobs can refer to them,
+// jobs of a namespace are left untouched if it has no jobs directory
+func (s *Syncer) deployNamespace(ctx context.Context, projectName, namespace string, namespaceFs afero.Fs,
+	commit models.CommitRef) error {
+	for _, ds := range s.datastoreRepo.GetAll() {
+		if ok, _ := afero.DirExists(namespaceFs, ds.Name()); !ok {
+			continue
+		}
+		resourceSpecs, err := local.NewResourceSpecRepository(afero.NewBasePathFs(namespaceFs, ds.Name()), ds).GetAll()
+		if err == models.ErrNoResources {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s resources", ds.Name())
+		}
+		if err := s.deployer.DeployResources(ctx, projectName, namespace, ds.Name(), resourceSpecs); err != nil {
+			return errors.Wrapf(err, "failed to deploy %s resources", ds.Name())
+		}
+	}
+
+	if ok, _ := afero.DirExists(namespaceFs, JobsDirectory); !ok {
+		return nil
+	}
+	jobSpecs, err := local.NewJobSpecRepository(
+		afero.NewBasePathFs(namespaceFs, JobsDirectory),
+		local.NewJobSpecAdapter(s.pluginRepo),
+	).GetAll()
+	if err != nil && err != models.ErrNoDAGSpecs {
+		return errors.Wrap(err, "failed to read jobs")
+	}
+	jobsCtx := ctx
+	if commit.SHA != "" {
+		commit.SpecPath = path.Join(commit.SpecPath, namespace, JobsDirectory)
+		jobsCtx = WithCommit(ctx, commit)
+	}
+	if err := s.deployer.DeployJobs(jobsCtx, projectName, namespace, jobSpecs); err != nil {
+		return errors.Wrap(err, "failed to deploy jobs")
+	}
+	return nil
+}
+
+// IsEnabled checks if a git repository is configured for project
+func IsEnabled(projSpec models.ProjectSpec) bool {
+	return strings.TrimSpace(projSpec.Config[models.ProjectGitSyncURLKey]) != ""
+}
+
+// commitRef is the commit of project repository specifications of project
+// are deployed from, SpecPath is the git sync path of project
+func commitRef(projSpec models.ProjectSpec, revision string) models.CommitRef {
+	return models.CommitRef{
+		Repository: projSpec.Config[models.ProjectGitSyncURLKey],
+		SHA:        revision,
+		SpecPath:   strings.Trim(path.Clean("/"+projSpec.Config[models.ProjectGitSyncPathKey]), "/"),
+	}
+}
+
+func branch(projSpec models.ProjectSpec) string {
+	if b := strings.TrimSpace(projSpec.Config[models.ProjectGitSyncBranchKey]); b != "" {
+		return b
+	}
+	return DefaultBranch
+}
+
+// NewSyncer keeps checked out repositories in workDir, a temporary
+// directory is used if it is empty
+func NewSyncer(projectRepoFactory ProjectRepoFactory, git Git, deployer Deployer, pluginRepo models.PluginRepository,
+	datastoreRepo models.DatastoreRepo, workDir string, interval time.Duration) *Syncer {
+	if workDir == "" {
+		workDir = filepath.Join(os.TempDir(), "optimus-git-sync")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Syncer{
+		ctx:                ctx,
+		cancel:             cancel,
+		projectRepoFactory: projectRepoFactory,
+		git:                git,
+		deployer:           deployer,
+		pluginRepo:         pluginRepo,
+		datastoreRepo:      datastoreRepo,
+		workDir:            workDir,
+		interval:           interval,
+		revisions:          map[string]string{},
+	}
+}
@@ -0,0 +1,153 @@
+package gitsync_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/gitsync"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+const testJobSpec = `version: 1
+name: test
+owner: optimus
+schedule:
+  start_date: "2020-12-02"
+  interval: '@daily'
+task:
+  name: foo
+  config:
+    table: tab1
+  window:
+    size: 24h
+    offset: "0"
+    truncate_to: d
+`
+
+func TestSyncer(t *testing.T) {
+	logger.InitWithWriter(logger.DEBUG, ioutil.Discard)
+	ctx := context.Background()
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+		Config: map[string]string{
+			models.ProjectGitSyncURLKey:  "git@example.io:data/specs.git",
+			models.ProjectGitSyncPathKey: "specs",
+		},
+	}
+
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name: "foo",
+	}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", "foo").Return(&models.Plugin{Base: execUnit}, nil)
+	datastoreRepo := new(mock.SupportedDatastoreRepo)
+	datastoreRepo.On("GetAll").Return([]models.Datastorer{})
+
+	prepareRepo := func(t *testing.T) string {
+		workDir := t.TempDir()
+		specDir := filepath.Join(workDir, projSpec.Name, "specs")
+		assert.Nil(t, os.MkdirAll(filepath.Join(specDir, "team-a", gitsync.JobsDirectory, "test"), os.ModePerm))
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(specDir, "team-a", gitsync.JobsDirectory, "test", "job.yaml"), []byte(testJobSpec), 0600))
+		assert.Nil(t, os.MkdirAll(filepath.Join(specDir, "team-b"), os.ModePerm))
+		assert.Nil(t, os.MkdirAll(filepath.Join(specDir, ".github"), os.ModePerm))
+		return workDir
+	}
+	// results of deploying jobs are posted to the commit deployed
+	commitCtx := gitsync.WithCommit(ctx, models.CommitRef{
+		Repository: "git@example.io:data/specs.git",
+		SHA:        "abc123",
+		SpecPath:   "specs/team-a/jobs",
+	})
+	jobNames := func(names ...string) interface{} {
+		return mock2.MatchedBy(func(specs []models.JobSpec) bool {
+			if len(specs) != len(names) {
+				return false
+			}
+			for i, spec := range specs {
+				if spec.Name != names[i] {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	t.Run("should deploy jobs of namespaces with jobs directory", func(t *testing.T) {
+		workDir := prepareRepo(t)
+		git := new(mock.Git)
+		defer git.AssertExpectations(t)
+		git.On("Checkout", ctx, "git@example.io:data/specs.git", gitsync.DefaultBranch,
+			filepath.Join(workDir, projSpec.Name)).Return("abc123", nil)
+
+		deployer := new(mock.GitSyncDeployer)
+		defer deployer.AssertExpectations(t)
+		deployer.On("DeployJobs", commitCtx, projSpec.Name, "team-a", jobNames("test")).Return(nil).Once()
+
+		syncer := gitsync.NewSyncer(nil, git, deployer, pluginRepo, datastoreRepo, workDir, time.Minute)
+		defer syncer.Close()
+		assert.Nil(t, syncer.Sync(ctx, projSpec, false))
+	})
+	t.Run("should skip deploying a commit already deployed unless forced", func(t *testing.T) {
+		workDir := prepareRepo(t)
+		git := new(mock.Git)
+		defer git.AssertExpectations(t)
+		git.On("Checkout", ctx, "git@example.io:data/specs.git", gitsync.DefaultBranch,
+			filepath.Join(workDir, projSpec.Name)).Return("abc123", nil).Times(3)
+
+		deployer := new(mock.GitSyncDeployer)
+		defer deployer.AssertExpectations(t)
+		deployer.On("DeployJobs", commitCtx, projSpec.Name, "team-a", jobNames("test")).Return(nil).Twice()
+
+		syncer := gitsync.NewSyncer(nil, git, deployer, pluginRepo, datastoreRepo, workDir, time.Minute)
+		defer syncer.Close()
+		assert.Nil(t, syncer.Sync(ctx, projSpec, false))
+		assert.Nil(t, syncer.Sync(ctx, projSpec, false))
+		assert.Nil(t, syncer.Sync(ctx, projSpec, true))
+	})
+	t.Run("should retry a commit which failed to deploy", func(t *testing.T) {
+		workDir := prepareRepo(t)
+		git := new(mock.Git)
+		defer git.AssertExpectations(t)
+		git.On("Checkout", ctx, "git@example.io:data/specs.git", gitsync.DefaultBranch,
+			filepath.Join(workDir, projSpec.Name)).Return("abc123", nil).Twice()
+
+		deployer := new(mock.GitSyncDeployer)
+		defer deployer.AssertExpectations(t)
+		deployer.On("DeployJobs", commitCtx, projSpec.Name, "team-a", jobNames("test")).Return(assert.AnError).Once()
+		deployer.On("DeployJobs", commitCtx, projSpec.Name, "team-a", jobNames("test")).Return(nil).Once()
+
+		syncer := gitsync.NewSyncer(nil, git, deployer, pluginRepo, datastoreRepo, workDir, time.Minute)
+		defer syncer.Close()
+		assert.NotNil(t, syncer.Sync(ctx, projSpec, false))
+		assert.Nil(t, syncer.Sync(ctx, projSpec, false))
+	})
+	t.Run("should sync only projects with a repository configured", func(t *testing.T) {
+		workDir := prepareRepo(t)
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{{Name: "other"}, projSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		git := new(mock.Git)
+		defer git.AssertExpectations(t)
+		git.On("Checkout", ctx, "git@example.io:data/specs.git", gitsync.DefaultBranch,
+			filepath.Join(workDir, projSpec.Name)).Return("abc123", nil)
+
+		deployer := new(mock.GitSyncDeployer)
+		defer deployer.AssertExpectations(t)
+		deployer.On("DeployJobs", commitCtx, projSpec.Name, "team-a", jobNames("test")).Return(nil)
+
+		syncer := gitsync.NewSyncer(projectRepoFac, git, deployer, pluginRepo, datastoreRepo, workDir, time.Minute)
+		defer syncer.Close()
+		assert.Nil(t, syncer.SyncAll(ctx))
+	})
+}
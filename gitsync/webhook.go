@@ -0,0 +1,110 @@
+package gitsync
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// headers used by git hosts to sign webhook calls with the secret
+	// configured on the webhook
+	headerGithubSignature = "X-Hub-Signature-256"
+	headerGitlabToken     = "X-Gitlab-Token"
+
+	maxWebhookPayloadSize = 25 * 1024 * 1024
+)
+
+// WebhookHandler syncs a project identified by project query param when
+// its repository receives a push, calls for branches other than the synced
+// one are ignored. Calls are accepted only if signed with
+// ProjectSecretGitSyncWebhook secret of the project, all calls are rejected
+// for projects without one
+type WebhookHandler struct {
+	syncer             *Syncer
+	projectRepoFactory ProjectRepoFactory
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := r.URL.Query().Get("project")
+	if projectName == "" {
+		http.Error(w, "project is required", http.StatusBadRequest)
+		return
+	}
+	projSpec, err := h.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			http.Error(w, "project "+projectName+" not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !IsEnabled(projSpec) {
+		http.Error(w, "git sync is not configured for project "+projectName, http.StatusBadRequest)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayloadSize))
+	if err != nil {
+		http.Error(w, errors.Wrap(err, "invalid payload").Error(), http.StatusBadRequest)
+		return
+	}
+	secret, ok := projSpec.Secret.GetByName(models.ProjectSecretGitSyncWebhook)
+	if !ok || secret == "" {
+		http.Error(w, "webhook secret is not registered for project "+projectName, http.StatusUnauthorized)
+		return
+	}
+	if !verify(r, payload, secret) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	// push events carry the ref pushed, anything else triggers a sync
+	var event struct {
+		Ref string `json:"ref"`
+	}
+	if err := json.Unmarshal(payload, &event); err == nil && event.Ref != "" &&
+		strings.TrimPrefix(event.Ref, "refs/heads/") != branch(projSpec) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// deployments outlive webhook timeouts of git hosts
+	h.syncer.Trigger(projSpec)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func verify(r *http.Request, payload []byte, secret string) bool {
+	if token := r.Header.Get(headerGitlabToken); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+	signature := strings.TrimPrefix(r.Header.Get(headerGithubSignature), "sha256=")
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func NewWebhookHandler(syncer *Syncer, projectRepoFactory ProjectRepoFactory) *WebhookHandler {
+	return &WebhookHandler{
+		syncer:             syncer,
+		projectRepoFactory: projectRepoFactory,
+	}
+}
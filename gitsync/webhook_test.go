@@ -0,0 +1,104 @@
+package gitsync_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/gitsync"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	logger.InitWithWriter(logger.DEBUG, ioutil.Discard)
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+		Config: map[string]string{
+			models.ProjectGitSyncURLKey: "git@example.io:data/specs.git",
+		},
+		Secret: models.ProjectSecrets{
+			{Name: models.ProjectSecretGitSyncWebhook, Value: "webhook-secret"},
+		},
+	}
+	projectRepo := new(mock.ProjectRepository)
+	projectRepo.On("GetByName", projSpec.Name).Return(projSpec, nil)
+	projectRepoFac := new(mock.ProjectRepoFactory)
+	projectRepoFac.On("New").Return(projectRepo)
+	datastoreRepo := new(mock.SupportedDatastoreRepo)
+	datastoreRepo.On("GetAll").Return([]models.Datastorer{})
+
+	sign := func(payload []byte) string {
+		mac := hmac.New(sha256.New, []byte("webhook-secret"))
+		mac.Write(payload)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("should sync project on push to its branch", func(t *testing.T) {
+		workDir := t.TempDir()
+		git := new(mock.Git)
+		defer git.AssertExpectations(t)
+		git.On("Checkout", mock2.Anything, "git@example.io:data/specs.git", gitsync.DefaultBranch, mock2.Anything).Return("abc123", nil)
+
+		syncer := gitsync.NewSyncer(projectRepoFac, git, new(mock.GitSyncDeployer), nil, datastoreRepo, workDir, time.Minute)
+		payload := []byte(`{"ref": "refs/heads/main"}`)
+		req := httptest.NewRequest(http.MethodPost, "/webhook/git-sync?project=proj", bytes.NewReader(payload))
+		req.Header.Set("X-Hub-Signature-256", sign(payload))
+		rec := httptest.NewRecorder()
+		gitsync.NewWebhookHandler(syncer, projectRepoFac).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		// waits for the triggered sync
+		assert.Nil(t, syncer.Close())
+	})
+	t.Run("should ignore push to other branches", func(t *testing.T) {
+		syncer := gitsync.NewSyncer(projectRepoFac, new(mock.Git), new(mock.GitSyncDeployer), nil, datastoreRepo, t.TempDir(), time.Minute)
+		defer syncer.Close()
+		payload := []byte(`{"ref": "refs/heads/feature"}`)
+		req := httptest.NewRequest(http.MethodPost, "/webhook/git-sync?project=proj", bytes.NewReader(payload))
+		req.Header.Set("X-Hub-Signature-256", sign(payload))
+		rec := httptest.NewRecorder()
+		gitsync.NewWebhookHandler(syncer, projectRepoFac).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+	t.Run("should reject calls not signed with webhook secret", func(t *testing.T) {
+		syncer := gitsync.NewSyncer(projectRepoFac, new(mock.Git), new(mock.GitSyncDeployer), nil, datastoreRepo, t.TempDir(), time.Minute)
+		defer syncer.Close()
+		payload := []byte(`{"ref": "refs/heads/main"}`)
+		req := httptest.NewRequest(http.MethodPost, "/webhook/git-sync?project=proj", bytes.NewReader(payload))
+		req.Header.Set("X-Gitlab-Token", "guess")
+		rec := httptest.NewRecorder()
+		gitsync.NewWebhookHandler(syncer, projectRepoFac).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("should reject calls for projects without webhook secret", func(t *testing.T) {
+		unsigned := projSpec
+		unsigned.Name = "unsigned"
+		unsigned.Secret = nil
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetByName", unsigned.Name).Return(unsigned, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		syncer := gitsync.NewSyncer(projectRepoFac, new(mock.Git), new(mock.GitSyncDeployer), nil, datastoreRepo, t.TempDir(), time.Minute)
+		defer syncer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/webhook/git-sync?project=unsigned",
+			bytes.NewReader([]byte(`{"ref": "refs/heads/main"}`)))
+		rec := httptest.NewRecorder()
+		gitsync.NewWebhookHandler(syncer, projectRepoFac).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "webhook secret is not registered for project unsigned\n", rec.Body.String())
+	})
+}
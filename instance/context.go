@@ -3,6 +3,8 @@ package instance
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/odpf/optimus/models"
@@ -23,6 +25,14 @@ var (
 	// IgnoreTemplateRenderExtension used as extension on a file will skip template
 	// rendering of it
 	IgnoreTemplateRenderExtension = []string{".gtpl", ".j2", ".tmpl", ".tpl"}
+
+	// templateActionRegex matches actions of a template, e.g. {{ .secret.API_KEY | quote }}
+	// or {{ secret.API_KEY }} in jinja
+	templateActionRegex = regexp.MustCompile(`{{.*?}}`)
+	// secretMacroRegex matches the secrets referred inside a template action
+	secretMacroRegex = regexp.MustCompile(`\bsecret\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+	secretResolveTimeout = time.Second * 30
 )
 
 // ContextManager fetches all config data for a given instanceSpec and compiles all
@@ -32,11 +42,12 @@ var (
 // transformed before they can work as inputs. Input could be through
 // environment variables or as a file.
 // It exposes .proj, .inst, .task variable names containing configs that can be
-// used in job specification
+// used in job specification and .secret containing the secrets referred by them
 type ContextManager struct {
-	namespace models.NamespaceSpec
-	jobSpec   models.JobSpec
-	engine    models.TemplateEngine
+	namespace      models.NamespaceSpec
+	jobSpec        models.JobSpec
+	engine         models.TemplateEngine
+	secretResolver models.SecretResolver
 }
 
 // Generate fetches and compiles all config data related to an instance and
@@ -57,6 +68,15 @@ func (fm *ContextManager) Generate(
 	projectInstanceContext["proj"] = projRawConfig
 	projectInstanceContext["inst"] = instanceEnvMap
 
+	// only the secrets referred in templates are resolved
+	if fm.secretResolver != nil {
+		secrets, err := fm.resolveSecrets(runType, runName, instanceFileMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		projectInstanceContext["secret"] = secrets
+	}
+
 	// prepare configs
 	envMap, err = fm.generateEnvs(runName, runType, projectInstanceContext)
 	if err != nil {
@@ -85,7 +105,11 @@ func (fm *ContextManager) Generate(
 
 	// append job spec assets to list of files need to write
 	fileMap = MergeStringMap(instanceFileMap, compiledAssetResponse.Assets.ToJobSpec().ToMap())
-	if fileMap, err = fm.engine.CompileFiles(fileMap, projectInstanceContext); err != nil {
+	assetEngine, err := AssetEngine(fm.namespace.ProjectSpec, fm.jobSpec, fm.engine)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fileMap, err = assetEngine.CompileFiles(fileMap, projectInstanceContext); err != nil {
 		return
 	}
 	return envMap, fileMap, nil
@@ -159,6 +183,52 @@ func (fm *ContextManager) compileTemplates(templateValueMap, templateContext map
 	return templateValueMap, nil
 }
 
+func (fm *ContextManager) resolveSecrets(runType models.InstanceType, runName string,
+	instanceFileMap map[string]string) (map[string]string, error) {
+	var templates []string
+	for _, conf := range fm.jobSpec.Task.Config {
+		templates = append(templates, conf.Value)
+	}
+	if runType == models.InstanceTypeHook {
+		if hook, err := fm.jobSpec.GetHookByName(runName); err == nil {
+			for _, conf := range hook.Config {
+				templates = append(templates, conf.Value)
+			}
+		}
+	}
+	for name, content := range MergeStringMap(instanceFileMap, fm.jobSpec.Assets.ToMap()) {
+		if !shouldIgnoreFile(name) {
+			templates = append(templates, content)
+		}
+	}
+
+	names := secretMacroNames(templates)
+	if len(names) == 0 {
+		return map[string]string{}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+	return fm.secretResolver.Resolve(ctx, fm.namespace, fm.jobSpec, names)
+}
+
+// secretMacroNames returns sorted names of secrets referred in templates
+func secretMacroNames(templates []string) []string {
+	found := map[string]bool{}
+	for _, tmpl := range templates {
+		for _, action := range templateActionRegex.FindAllString(tmpl, -1) {
+			for _, match := range secretMacroRegex.FindAllStringSubmatch(action, -1) {
+				found[match[1]] = true
+			}
+		}
+	}
+	var names []string
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (fm *ContextManager) getProjectConfigMap() map[string]string {
 	configMap := map[string]string{}
 	for key, val := range fm.namespace.ProjectSpec.Config {
@@ -213,11 +283,13 @@ func (fm *ContextManager) getConfigMaps(jobSpec models.JobSpec, runName string,
 	return transformationMap, hookMap, nil
 }
 
-func NewContextManager(namespace models.NamespaceSpec, jobSpec models.JobSpec, engine models.TemplateEngine) *ContextManager {
+func NewContextManager(namespace models.NamespaceSpec, jobSpec models.JobSpec, engine models.TemplateEngine,
+	secretResolver models.SecretResolver) *ContextManager {
 	return &ContextManager{
-		namespace: namespace,
-		jobSpec:   jobSpec,
-		engine:    engine,
+		namespace:      namespace,
+		jobSpec:        jobSpec,
+		engine:         engine,
+		secretResolver: secretResolver,
 	}
 }
 
@@ -261,7 +333,12 @@ func MergeInterfaceMapToString(mp1, mp2 map[string]interface{}) (mp3 map[string]
 }
 
 // DumpAssets used for dry run and does not effect actual execution of a job
-func DumpAssets(jobSpec models.JobSpec, scheduledAt time.Time, engine models.TemplateEngine, allowOverride bool) (map[string]string, error) {
+func DumpAssets(proj models.ProjectSpec, jobSpec models.JobSpec, scheduledAt time.Time, engine models.TemplateEngine, allowOverride bool) (map[string]string, error) {
+	assetEngine, err := AssetEngine(proj, jobSpec, engine)
+	if err != nil {
+		return nil, err
+	}
+
 	var jobDestination string
 	if jobSpec.Task.Unit.DependencyMod != nil {
 		jobDestinationResponse, err := jobSpec.Task.Unit.DependencyMod.GenerateDestination(context.TODO(), models.GenerateDestinationRequest{
@@ -304,7 +381,7 @@ func DumpAssets(jobSpec models.JobSpec, scheduledAt time.Time, engine models.Tem
 	}
 
 	// compile again if needed
-	templates, err := engine.CompileFiles(assetsToDump, map[string]interface{}{
+	templates, err := assetEngine.CompileFiles(assetsToDump, map[string]interface{}{
 		ConfigKeyDstart:        jobSpec.Task.Window.GetStart(scheduledAt).Format(models.InstanceScheduledAtTimeLayout),
 		ConfigKeyDend:          jobSpec.Task.Window.GetEnd(scheduledAt).Format(models.InstanceScheduledAtTimeLayout),
 		ConfigKeyExecutionTime: scheduledAt.Format(models.InstanceScheduledAtTimeLayout),
@@ -11,6 +11,7 @@ import (
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
 	"github.com/stretchr/testify/assert"
+	testMock "github.com/stretchr/testify/mock"
 )
 
 func TestContextManager(t *testing.T) {
@@ -130,7 +131,7 @@ func TestContextManager(t *testing.T) {
 			}}, nil)
 
 			envMap, fileMap, err := instance.NewContextManager(namespaceSpec, jobSpec,
-				instance.NewGoEngine()).Generate(instanceSpec, models.InstanceTypeTask, "bq")
+				instance.NewGoEngine(), nil).Generate(instanceSpec, models.InstanceTypeTask, "bq")
 			assert.Nil(t, err)
 
 			assert.Equal(t, "2020-11-11T00:00:00Z", envMap["DEND"])
@@ -283,7 +284,7 @@ func TestContextManager(t *testing.T) {
 				},
 			}}, nil)
 
-			envMap, fileMap, err := instance.NewContextManager(namespaceSpec, jobSpec, instance.NewGoEngine()).Generate(
+			envMap, fileMap, err := instance.NewContextManager(namespaceSpec, jobSpec, instance.NewGoEngine(), nil).Generate(
 				instanceSpec, models.InstanceTypeHook, transporterHook)
 			assert.Nil(t, err)
 
@@ -418,7 +419,7 @@ func TestContextManager(t *testing.T) {
 				},
 			}}, nil)
 
-			envMap, fileMap, err := instance.NewContextManager(namespaceSpec, jobSpec, instance.NewGoEngine()).Generate(instanceSpec, models.InstanceTypeTask, "bq")
+			envMap, fileMap, err := instance.NewContextManager(namespaceSpec, jobSpec, instance.NewGoEngine(), nil).Generate(instanceSpec, models.InstanceTypeTask, "bq")
 			assert.Nil(t, err)
 
 			assert.Equal(t, "2020-11-11T00:00:00Z", envMap["DEND"])
@@ -435,5 +436,160 @@ func TestContextManager(t *testing.T) {
 				fileMap["query.sql"],
 			)
 		})
+		t.Run("should resolve secrets referred in task config and assets", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "namespace-1",
+				ProjectSpec: models.ProjectSpec{
+					ID:   uuid.Must(uuid.NewRandom()),
+					Name: "humara-projectSpec",
+				},
+			}
+
+			execUnit := new(mock.BasePlugin)
+			execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: "bq",
+			}, nil)
+			cliMod := new(mock.CLIMod)
+
+			jobSpec := models.JobSpec{
+				Name: "foo",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{Base: execUnit, CLIMod: cliMod},
+					Window: models.JobSpecTaskWindow{
+						Size:       time.Hour,
+						TruncateTo: "d",
+					},
+					Config: models.JobSpecConfigs{
+						{
+							Name:  "API_KEY",
+							Value: "{{ .secret.API_KEY }}",
+						},
+					},
+				},
+				Assets: *models.JobAssets{}.New(
+					[]models.JobSpecAsset{
+						{
+							Name:  "query.sql",
+							Value: "select * from table where token = '{{.secret.TOKEN}}' and note = 'not a .secret.MACRO'",
+						},
+					},
+				),
+			}
+			instanceSpec := models.InstanceSpec{
+				Job:         jobSpec,
+				ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+			}
+
+			cliMod.On("CompileAssets", context.TODO(), models.CompileAssetsRequest{
+				Window:           jobSpec.Task.Window,
+				Config:           models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+				Assets:           models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+				InstanceSchedule: instanceSpec.ScheduledAt,
+			}).Return(&models.CompileAssetsResponse{Assets: models.PluginAssets{}.FromJobSpec(jobSpec.Assets)}, nil)
+
+			secretResolver := new(mock.SecretResolver)
+			secretResolver.On("Resolve", testMock.Anything, namespaceSpec, jobSpec, []string{"API_KEY", "TOKEN"}).Return(map[string]string{
+				"API_KEY": "secret-key",
+				"TOKEN":   "secret-token",
+			}, nil)
+			defer secretResolver.AssertExpectations(t)
+
+			envMap, fileMap, err := instance.NewContextManager(namespaceSpec, jobSpec, instance.NewGoEngine(), secretResolver).Generate(instanceSpec, models.InstanceTypeTask, "bq")
+			assert.Nil(t, err)
+			assert.Equal(t, "secret-key", envMap["API_KEY"])
+			assert.Equal(t, "select * from table where token = 'secret-token' and note = 'not a .secret.MACRO'", fileMap["query.sql"])
+		})
+		t.Run("should compile assets with jinja if set for the project", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "namespace-1",
+				ProjectSpec: models.ProjectSpec{
+					ID:   uuid.Must(uuid.NewRandom()),
+					Name: "humara-projectSpec",
+					Config: map[string]string{
+						models.ProjectTemplateEngineKey: "jinja",
+					},
+				},
+			}
+
+			execUnit := new(mock.BasePlugin)
+			execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: "bq",
+			}, nil)
+			cliMod := new(mock.CLIMod)
+
+			jobSpec := models.JobSpec{
+				Name: "foo",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{Base: execUnit, CLIMod: cliMod},
+					Window: models.JobSpecTaskWindow{
+						Size:       time.Hour,
+						TruncateTo: "d",
+					},
+				},
+				Assets: *models.JobAssets{}.New(
+					[]models.JobSpecAsset{
+						{
+							Name:  "query.sql",
+							Value: "select * from table where ts >= '{{ DSTART|ToDate }}'{% if secret.TOKEN %} and token = '{{ secret.TOKEN }}'{% endif %}",
+						},
+					},
+				),
+			}
+			instanceSpec := models.InstanceSpec{
+				Job:         jobSpec,
+				ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+				Data: []models.InstanceSpecData{
+					{
+						Name:  instance.ConfigKeyDstart,
+						Value: "2020-11-11T00:00:00Z",
+						Type:  models.InstanceDataTypeEnv,
+					},
+				},
+			}
+
+			cliMod.On("CompileAssets", context.TODO(), models.CompileAssetsRequest{
+				Window:           jobSpec.Task.Window,
+				Config:           models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+				Assets:           models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+				InstanceSchedule: instanceSpec.ScheduledAt,
+				InstanceData:     instanceSpec.Data,
+			}).Return(&models.CompileAssetsResponse{Assets: models.PluginAssets{}.FromJobSpec(jobSpec.Assets)}, nil)
+
+			secretResolver := new(mock.SecretResolver)
+			secretResolver.On("Resolve", testMock.Anything, namespaceSpec, jobSpec, []string{"TOKEN"}).Return(map[string]string{
+				"TOKEN": "secret-token",
+			}, nil)
+			defer secretResolver.AssertExpectations(t)
+
+			_, fileMap, err := instance.NewContextManager(namespaceSpec, jobSpec, instance.NewGoEngine(), secretResolver).Generate(instanceSpec, models.InstanceTypeTask, "bq")
+			assert.Nil(t, err)
+			assert.Equal(t, "select * from table where ts >= '2020-11-11' and token = 'secret-token'", fileMap["query.sql"])
+		})
+		t.Run("should fail for unknown template engine of the job", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				Name: "namespace-1",
+				ProjectSpec: models.ProjectSpec{
+					Name: "humara-projectSpec",
+				},
+			}
+			cliMod := new(mock.CLIMod)
+			jobSpec := models.JobSpec{
+				Name: "foo",
+				Task: models.JobSpecTask{
+					Unit:           &models.Plugin{CLIMod: cliMod},
+					TemplateEngine: "mustache",
+				},
+			}
+			instanceSpec := models.InstanceSpec{
+				Job:         jobSpec,
+				ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+			}
+			cliMod.On("CompileAssets", context.TODO(), testMock.Anything).Return(&models.CompileAssetsResponse{}, nil)
+
+			_, _, err := instance.NewContextManager(namespaceSpec, jobSpec, instance.NewGoEngine(), nil).Generate(instanceSpec, models.InstanceTypeTask, "bq")
+			assert.Equal(t, "unknown template engine mustache for assets of job foo", err.Error())
+		})
 	})
 }
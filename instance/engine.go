@@ -0,0 +1,20 @@
+package instance
+
+import (
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// AssetEngine returns the engine assets of the job are compiled with, as set
+// for the job or else for its project. Go templates are compiled with the
+// engine provided, so callers keep control over functions available to them
+func AssetEngine(proj models.ProjectSpec, jobSpec models.JobSpec, goEngine models.TemplateEngine) (models.TemplateEngine, error) {
+	switch name := jobSpec.AssetTemplateEngine(proj); name {
+	case models.TemplateEngineGo:
+		return goEngine, nil
+	case models.TemplateEngineJinja:
+		return NewJinjaEngine(), nil
+	default:
+		return nil, errors.Errorf("unknown template engine %s for assets of job %s", name, jobSpec.Name)
+	}
+}
@@ -16,6 +16,9 @@ const (
 	ConfigKeyDend          = "DEND"
 	ConfigKeyExecutionTime = "EXECUTION_TIME"
 	ConfigKeyDestination   = "JOB_DESTINATION"
+
+	// number of latest runs used to measure duration of a job
+	durationSampleSize = 10
 )
 
 type InstanceSpecRepoFactory interface {
@@ -26,12 +29,32 @@ type Service struct {
 	repoFac        InstanceSpecRepoFactory
 	Now            func() time.Time
 	templateEngine models.TemplateEngine
+	secretResolver models.SecretResolver
+	checkpointRepo store.JobCheckpointRepository
 }
 
+// Compile generates the context of an instance, checkpoints saved by
+// earlier runs of the job are part of it as env prefixed with
+// models.JobCheckpointEnvPrefix
 func (s *Service) Compile(namespace models.NamespaceSpec, jobSpec models.JobSpec, instanceSpec models.InstanceSpec,
 	runType models.InstanceType, runName string) (envMap map[string]string, fileMap map[string]string, err error) {
+	if s.checkpointRepo != nil {
+		checkpoints, err := s.checkpointRepo.GetAll(jobSpec)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to fetch checkpoints of %s", jobSpec.Name)
+		}
+		data := append([]models.InstanceSpecData{}, instanceSpec.Data...)
+		for _, checkpoint := range checkpoints {
+			data = append(data, models.InstanceSpecData{
+				Name:  models.JobCheckpointEnvPrefix + checkpoint.Name,
+				Value: checkpoint.Value,
+				Type:  models.InstanceDataTypeEnv,
+			})
+		}
+		instanceSpec.Data = data
+	}
 	return NewContextManager(
-		namespace, jobSpec, s.templateEngine).Generate(
+		namespace, jobSpec, s.templateEngine, s.secretResolver).Generate(
 		instanceSpec, runType, runName,
 	)
 }
@@ -63,6 +86,10 @@ func (s *Service) Register(jobSpec models.JobSpec, scheduledAt time.Time,
 			}
 		} else if err != nil {
 			return models.InstanceSpec{}, err
+		} else if err := jobRunRepo.Touch(scheduledAt); err != nil {
+			// hooks registered after the task mark the end of its run
+			return models.InstanceSpec{}, errors.Wrapf(err, "failed to update instance of job %s",
+				scheduledAt.String())
 		}
 
 	default:
@@ -77,7 +104,177 @@ func (s *Service) Register(jobSpec models.JobSpec, scheduledAt time.Time,
 	return instanceSpec, nil
 }
 
+// GetAverageDuration measures runs from registration of the task till the last
+// hook registered after it, runs of jobs without such hooks are not measurable
+func (s *Service) GetAverageDuration(jobSpec models.JobSpec) (time.Duration, error) {
+	runs, err := s.GetRunDurations(jobSpec, durationSampleSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(runs) == 0 {
+		return 0, nil
+	}
+	var total time.Duration
+	for _, run := range runs {
+		total += run.Duration
+	}
+	return total / time.Duration(len(runs)), nil
+}
+
+// GetRunDurations measures runs the same way as GetAverageDuration
+func (s *Service) GetRunDurations(jobSpec models.JobSpec, limit int) ([]models.RunDuration, error) {
+	instances, err := s.repoFac.New(jobSpec).GetLatest(limit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch instances of job %s", jobSpec.Name)
+	}
+	var runs []models.RunDuration
+	for _, inst := range instances {
+		if took, ok := runDuration(inst); ok {
+			runs = append(runs, models.RunDuration{
+				ScheduledAt: inst.ScheduledAt,
+				Duration:    took,
+			})
+		}
+	}
+	return runs, nil
+}
+
+// runDuration is the time from registration of the task of the run till the
+// last hook registered after it, false if no such hook was registered
+func runDuration(inst models.InstanceSpec) (time.Duration, bool) {
+	for _, data := range inst.Data {
+		if data.Name != ConfigKeyExecutionTime || data.Type != models.InstanceDataTypeEnv {
+			continue
+		}
+		executedAt, err := time.Parse(models.InstanceScheduledAtTimeLayout, data.Value)
+		if err != nil {
+			return 0, false
+		}
+		took := inst.UpdatedAt.Sub(executedAt)
+		return took, took >= time.Second
+	}
+	return 0, false
+}
+
+func (s *Service) UpdateState(jobSpec models.JobSpec, scheduledAt time.Time, state string) error {
+	if state != models.InstanceStateSuccess && state != models.InstanceStateFailed {
+		return errors.Errorf("invalid state of run: %s", state)
+	}
+	if err := s.repoFac.New(jobSpec).UpdateState(scheduledAt.UTC(), state); err != nil {
+		return errors.Wrapf(err, "failed to update state of job %s run scheduled at %s", jobSpec.Name,
+			scheduledAt.Format(models.InstanceScheduledAtTimeLayout))
+	}
+	return nil
+}
+
+// Heartbeat records the run is alive at current time, runs which stop
+// reporting while running are counted as hung by GetRunStats
+func (s *Service) Heartbeat(jobSpec models.JobSpec, scheduledAt time.Time) error {
+	if err := s.repoFac.New(jobSpec).Heartbeat(scheduledAt.UTC(), s.Now().UTC()); err != nil {
+		return errors.Wrapf(err, "failed to record heartbeat of job %s run scheduled at %s", jobSpec.Name,
+			scheduledAt.Format(models.InstanceScheduledAtTimeLayout))
+	}
+	return nil
+}
+
+func (s *Service) GetHungRuns(jobSpec models.JobSpec, since time.Time, silence time.Duration) ([]models.InstanceSpec, error) {
+	instances, err := s.repoFac.New(jobSpec).GetSince(since)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch instances of job %s", jobSpec.Name)
+	}
+	var hung []models.InstanceSpec
+	now := s.Now()
+	for _, inst := range instances {
+		if inst.IsHung(now, silence) {
+			hung = append(hung, inst)
+		}
+	}
+	return hung, nil
+}
+
+// GetRunStats measures durations of successful runs the same way as GetAverageDuration
+func (s *Service) GetRunStats(jobSpec models.JobSpec, since time.Time) (models.JobRunStats, error) {
+	instances, err := s.repoFac.New(jobSpec).GetSince(since)
+	if err != nil {
+		return models.JobRunStats{}, errors.Wrapf(err, "failed to fetch instances of job %s", jobSpec.Name)
+	}
+	stats := models.JobRunStats{
+		Since:          since,
+		Runs:           len(instances),
+		FailuresByHour: map[int]int{},
+	}
+	var (
+		durations []models.RunDuration
+		total     time.Duration
+	)
+	now := s.Now()
+	for _, inst := range instances {
+		if inst.IsHung(now, models.InstanceHeartbeatInterval*models.InstanceHeartbeatMissLimit) {
+			stats.Hung++
+		}
+		switch inst.State {
+		case models.InstanceStateSuccess:
+			stats.Succeeded++
+			stats.CurrentFailureStreak = 0
+			if took, ok := runDuration(inst); ok {
+				durations = append(durations, models.RunDuration{ScheduledAt: inst.ScheduledAt, Duration: took})
+				total += took
+			}
+		case models.InstanceStateFailed:
+			stats.Failed++
+			stats.CurrentFailureStreak++
+			if stats.CurrentFailureStreak > stats.LongestFailureStreak {
+				stats.LongestFailureStreak = stats.CurrentFailureStreak
+			}
+			stats.FailuresByHour[inst.ScheduledAt.UTC().Hour()]++
+		}
+	}
+	if len(durations) > 0 {
+		stats.AverageDuration = total / time.Duration(len(durations))
+		stats.P50Duration = models.RunDurationPercentile(durations, 0.5)
+		stats.P95Duration = models.RunDurationPercentile(durations, 0.95)
+	}
+	return stats, nil
+}
+
+func (s *Service) RecordCost(jobSpec models.JobSpec, scheduledAt time.Time, cost models.InstanceCost) error {
+	if err := s.repoFac.New(jobSpec).UpdateCost(scheduledAt.UTC(), cost); err != nil {
+		return errors.Wrapf(err, "failed to record cost of job %s run scheduled at %s", jobSpec.Name,
+			scheduledAt.Format(models.InstanceScheduledAtTimeLayout))
+	}
+	return nil
+}
+
+// GetCostStats sums cost of runs the datastore jobs of which are known
+func (s *Service) GetCostStats(jobSpec models.JobSpec, since time.Time) (models.JobCostStats, error) {
+	instances, err := s.repoFac.New(jobSpec).GetSince(since)
+	if err != nil {
+		return models.JobCostStats{}, errors.Wrapf(err, "failed to fetch instances of job %s", jobSpec.Name)
+	}
+	stats := models.JobCostStats{Since: since}
+	for _, inst := range instances {
+		if inst.Cost == nil {
+			continue
+		}
+		stats.Runs++
+		stats.BytesBilled += inst.Cost.BytesBilled
+		stats.SlotMillis += inst.Cost.SlotMillis
+	}
+	return stats, nil
+}
+
+func (s *Service) GetLastSuccess(jobSpec models.JobSpec) (time.Time, bool, error) {
+	inst, err := s.repoFac.New(jobSpec).GetLatestByState(models.InstanceStateSuccess)
+	if errors.Is(err, store.ErrResourceNotFound) {
+		return time.Time{}, false, nil
+	} else if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "failed to fetch last successful run of job %s", jobSpec.Name)
+	}
+	return inst.ScheduledAt, true, nil
+}
+
 func (s *Service) PrepInstance(jobSpec models.JobSpec, scheduledAt time.Time) (models.InstanceSpec, error) {
+	scheduledAt = scheduledAt.UTC()
 	var jobDestination string
 	if jobSpec.Task.Unit.DependencyMod != nil {
 		jobDestinationResponse, err := jobSpec.Task.Unit.DependencyMod.GenerateDestination(context.TODO(), models.GenerateDestinationRequest{
@@ -121,10 +318,13 @@ func (s *Service) PrepInstance(jobSpec models.JobSpec, scheduledAt time.Time) (m
 	}, nil
 }
 
-func NewService(repoFac InstanceSpecRepoFactory, timeFunc func() time.Time, te models.TemplateEngine) *Service {
+func NewService(repoFac InstanceSpecRepoFactory, timeFunc func() time.Time, te models.TemplateEngine,
+	secretResolver models.SecretResolver, checkpointRepo store.JobCheckpointRepository) *Service {
 	return &Service{
 		repoFac:        repoFac,
 		Now:            timeFunc,
 		templateEngine: te,
+		secretResolver: secretResolver,
+		checkpointRepo: checkpointRepo,
 	}
 }
@@ -89,7 +89,7 @@ func TestService(t *testing.T) {
 			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
 			defer jobRunSpecRep.AssertExpectations(t)
 
-			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil)
 
 			returnedInstanceSpec, err := instanceService.Register(jobSpec, scheduledAt, models.InstanceTypeTask)
 			assert.Nil(t, err)
@@ -135,13 +135,13 @@ func TestService(t *testing.T) {
 			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
 			defer jobRunSpecRep.AssertExpectations(t)
 
-			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil)
 
 			returnedInstanceSpec, err := instanceService.Register(jobSpec, scheduledAt, models.InstanceTypeHook)
 			assert.Nil(t, err)
 			assert.Equal(t, returnedInstanceSpec, instanceSpec)
 		})
-		t.Run("for hook, should not save specs if already present and return data", func(t *testing.T) {
+		t.Run("for hook, should only touch specs if already present and return data", func(t *testing.T) {
 			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
 			instanceSpecRepo := new(mock.InstanceSpecRepository)
 			instanceSpec := models.InstanceSpec{
@@ -173,6 +173,7 @@ func TestService(t *testing.T) {
 			}
 
 			instanceSpecRepo.On("GetByScheduledAt", scheduledAt).Return(instanceSpec, nil).Once()
+			instanceSpecRepo.On("Touch", scheduledAt).Return(nil)
 			instanceSpecRepo.On("GetByScheduledAt", scheduledAt).Return(instanceSpec, nil).Once()
 			defer instanceSpecRepo.AssertExpectations(t)
 
@@ -180,7 +181,7 @@ func TestService(t *testing.T) {
 			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
 			defer jobRunSpecRep.AssertExpectations(t)
 
-			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil)
 
 			returnedInstanceSpec, err := instanceService.Register(jobSpec, scheduledAt, models.InstanceTypeHook)
 			assert.Nil(t, err)
@@ -225,7 +226,7 @@ func TestService(t *testing.T) {
 			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
 			defer jobRunSpecRep.AssertExpectations(t)
 
-			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil)
 
 			returnedInstanceSpec, err := instanceService.Register(jobSpec, scheduledAt, models.InstanceTypeTask)
 			assert.Equal(t, "a random error", err.Error())
@@ -242,7 +243,7 @@ func TestService(t *testing.T) {
 			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
 			defer jobRunSpecRep.AssertExpectations(t)
 
-			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil)
+			instanceService := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil)
 
 			returnedInstanceSpec, err := instanceService.Register(jobSpec, scheduledAt,
 				models.InstanceTypeHook)
@@ -251,12 +252,282 @@ func TestService(t *testing.T) {
 		})
 	})
 
+	t.Run("GetAverageDuration", func(t *testing.T) {
+		executedAt := time.Date(2020, 11, 11, 2, 0, 0, 0, time.UTC)
+		executionData := func(at time.Time) []models.InstanceSpecData {
+			return []models.InstanceSpecData{
+				{
+					Name:  instance.ConfigKeyExecutionTime,
+					Value: at.Format(models.InstanceScheduledAtTimeLayout),
+					Type:  models.InstanceDataTypeEnv,
+				},
+			}
+		}
+		t.Run("should average duration of measurable runs", func(t *testing.T) {
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetLatest", 10).Return([]models.InstanceSpec{
+				{Data: executionData(executedAt), UpdatedAt: executedAt.Add(time.Minute * 10)},
+				{Data: executionData(executedAt), UpdatedAt: executedAt.Add(time.Minute * 20)},
+				{Data: executionData(executedAt), UpdatedAt: executedAt},
+				{UpdatedAt: executedAt.Add(time.Hour)},
+			}, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			took, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetAverageDuration(jobSpec)
+			assert.Nil(t, err)
+			assert.Equal(t, time.Minute*15, took)
+		})
+		t.Run("should return zero if no run is measurable", func(t *testing.T) {
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetLatest", 10).Return([]models.InstanceSpec{
+				{Data: executionData(executedAt), UpdatedAt: executedAt},
+			}, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			took, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetAverageDuration(jobSpec)
+			assert.Nil(t, err)
+			assert.Equal(t, time.Duration(0), took)
+		})
+	})
+	t.Run("GetRunDurations", func(t *testing.T) {
+		t.Run("should return duration of measurable runs", func(t *testing.T) {
+			executedAt := time.Date(2020, 11, 11, 2, 0, 0, 0, time.UTC)
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetLatest", 3).Return([]models.InstanceSpec{
+				{
+					ScheduledAt: executedAt.Add(-time.Hour * 2),
+					Data: []models.InstanceSpecData{
+						{
+							Name:  instance.ConfigKeyExecutionTime,
+							Value: executedAt.Format(models.InstanceScheduledAtTimeLayout),
+							Type:  models.InstanceDataTypeEnv,
+						},
+					},
+					UpdatedAt: executedAt.Add(time.Minute * 10),
+				},
+				{ScheduledAt: executedAt.Add(-time.Hour * 26), UpdatedAt: executedAt.Add(-time.Hour)},
+			}, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			runs, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetRunDurations(jobSpec, 3)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.RunDuration{
+				{ScheduledAt: executedAt.Add(-time.Hour * 2), Duration: time.Minute * 10},
+			}, runs)
+		})
+	})
+	t.Run("GetRunStats", func(t *testing.T) {
+		t.Run("should summarize outcomes and durations of runs", func(t *testing.T) {
+			since := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+			run := func(day int, state string, took time.Duration) models.InstanceSpec {
+				scheduledAt := since.AddDate(0, 0, day).Add(time.Hour * 2)
+				return models.InstanceSpec{
+					ScheduledAt: scheduledAt,
+					State:       state,
+					Data: []models.InstanceSpecData{
+						{
+							Name:  instance.ConfigKeyExecutionTime,
+							Value: scheduledAt.Format(models.InstanceScheduledAtTimeLayout),
+							Type:  models.InstanceDataTypeEnv,
+						},
+					},
+					UpdatedAt: scheduledAt.Add(took),
+				}
+			}
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetSince", since).Return([]models.InstanceSpec{
+				run(0, models.InstanceStateSuccess, time.Minute*10),
+				run(1, models.InstanceStateFailed, time.Minute),
+				run(2, models.InstanceStateFailed, time.Minute),
+				run(3, models.InstanceStateSuccess, time.Minute*20),
+				run(4, models.InstanceStateFailed, time.Minute),
+				run(5, models.InstanceStateRunning, 0),
+			}, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			stats, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetRunStats(jobSpec, since)
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobRunStats{
+				Since:                since,
+				Runs:                 6,
+				Succeeded:            2,
+				Failed:               3,
+				AverageDuration:      time.Minute * 15,
+				P50Duration:          time.Minute * 10,
+				P95Duration:          time.Minute * 20,
+				LongestFailureStreak: 2,
+				CurrentFailureStreak: 1,
+				FailuresByHour:       map[int]int{2: 3},
+			}, stats)
+			assert.Equal(t, 0.4, stats.SuccessRate())
+		})
+		t.Run("should count running runs which missed heartbeats as hung", func(t *testing.T) {
+			since := mockedTimeNow.Add(-time.Hour * 3)
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetSince", since).Return([]models.InstanceSpec{
+				{ScheduledAt: since, State: models.InstanceStateSuccess, HeartbeatAt: since},
+				{ScheduledAt: since.Add(time.Hour), State: models.InstanceStateRunning, HeartbeatAt: mockedTimeNow.Add(-time.Hour)},
+				{ScheduledAt: since.Add(time.Hour * 2), State: models.InstanceStateRunning, HeartbeatAt: mockedTimeNow.Add(-time.Minute)},
+				{ScheduledAt: since.Add(time.Hour * 2), State: models.InstanceStateRunning},
+			}, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			stats, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetRunStats(jobSpec, since)
+			assert.Nil(t, err)
+			assert.Equal(t, 4, stats.Runs)
+			assert.Equal(t, 1, stats.Hung)
+		})
+	})
+	t.Run("Heartbeat", func(t *testing.T) {
+		t.Run("should record heartbeat of run at current time", func(t *testing.T) {
+			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("Heartbeat", scheduledAt, mockedTimeNow.UTC()).Return(nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).Heartbeat(jobSpec, scheduledAt)
+			assert.Nil(t, err)
+		})
+	})
+	t.Run("GetHungRuns", func(t *testing.T) {
+		t.Run("should return running runs silent for longer than silence", func(t *testing.T) {
+			since := mockedTimeNow.Add(-time.Hour * 3)
+			hungRun := models.InstanceSpec{ScheduledAt: since.Add(time.Hour), State: models.InstanceStateRunning,
+				HeartbeatAt: mockedTimeNow.Add(-time.Minute * 10)}
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetSince", since).Return([]models.InstanceSpec{
+				{ScheduledAt: since, State: models.InstanceStateFailed, HeartbeatAt: since},
+				hungRun,
+				{ScheduledAt: since.Add(time.Hour * 2), State: models.InstanceStateRunning, HeartbeatAt: mockedTimeNow.Add(-time.Minute)},
+				{ScheduledAt: since.Add(time.Hour * 2), State: models.InstanceStateRunning},
+			}, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			runs, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetHungRuns(jobSpec, since, time.Minute*5)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.InstanceSpec{hungRun}, runs)
+		})
+	})
+	t.Run("GetCostStats", func(t *testing.T) {
+		t.Run("should sum cost of runs with known cost", func(t *testing.T) {
+			since := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetSince", since).Return([]models.InstanceSpec{
+				{ScheduledAt: since, Cost: &models.InstanceCost{BytesBilled: 100, SlotMillis: 20}},
+				{ScheduledAt: since.AddDate(0, 0, 1)},
+				{ScheduledAt: since.AddDate(0, 0, 2), Cost: &models.InstanceCost{BytesBilled: 50, SlotMillis: 10}},
+			}, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			stats, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetCostStats(jobSpec, since)
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobCostStats{Since: since, Runs: 2, BytesBilled: 150, SlotMillis: 30}, stats)
+		})
+	})
+	t.Run("GetLastSuccess", func(t *testing.T) {
+		t.Run("should return schedule of the latest successful run", func(t *testing.T) {
+			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetLatestByState", models.InstanceStateSuccess).Return(models.InstanceSpec{
+				ScheduledAt: scheduledAt,
+				State:       models.InstanceStateSuccess,
+			}, nil)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			lastSuccess, ok, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetLastSuccess(jobSpec)
+			assert.Nil(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, scheduledAt, lastSuccess)
+		})
+		t.Run("should report no run succeeded yet", func(t *testing.T) {
+			instanceSpecRepo := new(mock.InstanceSpecRepository)
+			instanceSpecRepo.On("GetLatestByState", models.InstanceStateSuccess).Return(models.InstanceSpec{}, store.ErrResourceNotFound)
+			defer instanceSpecRepo.AssertExpectations(t)
+
+			jobRunSpecRep := new(mock.InstanceSpecRepoFactory)
+			jobRunSpecRep.On("New", jobSpec).Return(instanceSpecRepo, nil)
+			defer jobRunSpecRep.AssertExpectations(t)
+
+			_, ok, err := instance.NewService(jobRunSpecRep, mockedTimeFunc, nil, nil, nil).GetLastSuccess(jobSpec)
+			assert.Nil(t, err)
+			assert.False(t, ok)
+		})
+	})
+	t.Run("Compile", func(t *testing.T) {
+		t.Run("should add checkpoints of job to the context", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				Name:        "namespace-1",
+				ProjectSpec: models.ProjectSpec{Name: "proj"},
+			}
+			cliMod := new(mock.CLIMod)
+			cliMod.On("CompileAssets", context.Background(), mock2.Anything).Return(&models.CompileAssetsResponse{
+				Assets: models.PluginAssets{
+					{Name: "query.sql", Value: "select * from table WHERE offset > {{.CHECKPOINT__LAST_OFFSET}}"},
+				},
+			}, nil)
+			defer cliMod.AssertExpectations(t)
+			checkpointJobSpec := jobSpec
+			checkpointJobSpec.Task.Unit = &models.Plugin{Base: execUnit, CLIMod: cliMod}
+
+			checkpointRepo := new(mock.JobCheckpointRepository)
+			checkpointRepo.On("GetAll", checkpointJobSpec).Return([]models.JobCheckpoint{
+				{Name: "LAST_OFFSET", Value: "250"},
+			}, nil)
+			defer checkpointRepo.AssertExpectations(t)
+
+			instanceSpec := models.InstanceSpec{
+				Job:         checkpointJobSpec,
+				ScheduledAt: time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC),
+			}
+			envMap, fileMap, err := instance.NewService(nil, mockedTimeFunc, instance.NewGoEngine(), nil, checkpointRepo).
+				Compile(namespaceSpec, checkpointJobSpec, instanceSpec, models.InstanceTypeTask, "bq")
+			assert.Nil(t, err)
+			assert.Equal(t, "250", envMap["CHECKPOINT__LAST_OFFSET"])
+			assert.Equal(t, "select * from table WHERE offset > 250", fileMap["query.sql"])
+		})
+	})
 	t.Run("PrepInstance", func(t *testing.T) {
 		t.Run("while preparing instance execution time should be correct", func(t *testing.T) {
 			scheduledAt := time.Date(2020, 11, 11, 0, 0, 0, 0, time.UTC)
 			srv := instance.NewService(nil, func() time.Time {
 				return time.Now().UTC()
-			}, nil)
+			}, nil, nil, nil)
 			prep1, err := srv.PrepInstance(jobSpec, scheduledAt)
 			assert.Nil(t, err)
 			time.Sleep(time.Second)
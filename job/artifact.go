@@ -0,0 +1,131 @@
+package job
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	// zipArtifactModTime is the modification time of sources bundled in
+	// zip artifacts, fixed so a job compiled to the same source has the same
+	// artifact and isn't uploaded again
+	zipArtifactModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// jsonArtifact is the document compiled jobs are uploaded as in json format
+type jsonArtifact struct {
+	Name        string `json:"name"`
+	NamespaceID string `json:"namespace_id"`
+	FileName    string `json:"file_name"`
+	Source      string `json:"source"`
+}
+
+// JobArtifactSuffix is the suffix of compiled jobs uploaded as artifact,
+// sourceExt is the extension of source files of the scheduler
+func JobArtifactSuffix(artifact models.JobArtifact, sourceExt string) string {
+	switch artifact.Format {
+	case models.JobArtifactFormatZip:
+		return ".zip"
+	case models.JobArtifactFormatJSON:
+		if artifact.Compression == models.JobArtifactCompressionGzip {
+			return ".json.gz"
+		}
+		return ".json"
+	}
+	return sourceExt
+}
+
+// EncodeJobArtifact converts a compiled job to the artifact uploaded for
+// it, sourceExt is the extension of source files of the scheduler. Artifacts
+// are deterministic, the same source always encodes to the same contents
+func EncodeJobArtifact(artifact models.JobArtifact, compiledJob models.Job, sourceExt string) (models.Job, error) {
+	var (
+		contents []byte
+		err      error
+	)
+	switch artifact.Format {
+	case models.JobArtifactFormatSource:
+		return compiledJob, nil
+	case models.JobArtifactFormatZip:
+		contents, err = zipArtifact(compiledJob.Name+sourceExt, compiledJob.Contents, artifact.Compression)
+	case models.JobArtifactFormatJSON:
+		contents, err = json.Marshal(jsonArtifact{
+			Name:        compiledJob.Name,
+			NamespaceID: compiledJob.NamespaceID,
+			FileName:    compiledJob.Name + sourceExt,
+			Source:      string(compiledJob.Contents),
+		})
+		if err == nil && artifact.Compression == models.JobArtifactCompressionGzip {
+			contents, err = gzipArtifact(contents)
+		}
+	default:
+		return models.Job{}, errors.Errorf("unknown job artifact format %s", artifact.Format)
+	}
+	if err != nil {
+		return models.Job{}, errors.Wrapf(err, "failed to encode %s as %s", compiledJob.Name, artifact.Format)
+	}
+	compiledJob.Contents = contents
+	return compiledJob, nil
+}
+
+// zipArtifact bundles source as the only file of a zip, deflated with gzip
+// compression and stored as is otherwise
+func zipArtifact(fileName string, source []byte, compression string) ([]byte, error) {
+	method := zip.Store
+	if compression == models.JobArtifactCompressionGzip {
+		method = zip.Deflate
+	}
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	file, err := writer.CreateHeader(&zip.FileHeader{
+		Name:     fileName,
+		Method:   method,
+		Modified: zipArtifactModTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(source); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gzipArtifact(contents []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(contents); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// compileArtifact compiles a job into the artifact uploaded for the project
+// of namespace
+func (srv *Service) compileArtifact(namespace models.NamespaceSpec, jobSpec models.JobSpec) (models.Job, error) {
+	artifact, err := namespace.ProjectSpec.JobArtifact()
+	if err != nil {
+		return models.Job{}, err
+	}
+	compiledJob, err := srv.compiler.Compile(namespace, jobSpec)
+	if err != nil || artifact.Format == models.JobArtifactFormatSource {
+		return compiledJob, err
+	}
+	sourceExt := ""
+	if srv.scheduler != nil {
+		sourceExt = srv.scheduler.GetJobsExtension()
+	}
+	return EncodeJobArtifact(artifact, compiledJob, sourceExt)
+}
@@ -0,0 +1,92 @@
+package job_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobArtifact(t *testing.T) {
+	compiledJob := models.Job{
+		Name:        "test",
+		NamespaceID: "dev-team-1",
+		Contents:    []byte(`dag = DAG("test")`),
+	}
+
+	t.Run("JobArtifactSuffix", func(t *testing.T) {
+		t.Run("should use extension of scheduler for source files", func(t *testing.T) {
+			assert.Equal(t, ".py", job.JobArtifactSuffix(models.JobArtifact{
+				Format: models.JobArtifactFormatSource, Compression: models.JobArtifactCompressionNone,
+			}, ".py"))
+		})
+		t.Run("should suffix artifacts by their format and compression", func(t *testing.T) {
+			assert.Equal(t, ".zip", job.JobArtifactSuffix(models.JobArtifact{
+				Format: models.JobArtifactFormatZip, Compression: models.JobArtifactCompressionGzip,
+			}, ".py"))
+			assert.Equal(t, ".json.gz", job.JobArtifactSuffix(models.JobArtifact{
+				Format: models.JobArtifactFormatJSON, Compression: models.JobArtifactCompressionGzip,
+			}, ".py"))
+		})
+	})
+	t.Run("EncodeJobArtifact", func(t *testing.T) {
+		t.Run("should leave source files as compiled", func(t *testing.T) {
+			artifact, err := job.EncodeJobArtifact(models.JobArtifact{
+				Format: models.JobArtifactFormatSource, Compression: models.JobArtifactCompressionNone,
+			}, compiledJob, ".py")
+			assert.Nil(t, err)
+			assert.Equal(t, compiledJob, artifact)
+		})
+		t.Run("should bundle source file of job in a zip", func(t *testing.T) {
+			artifact, err := job.EncodeJobArtifact(models.JobArtifact{
+				Format: models.JobArtifactFormatZip, Compression: models.JobArtifactCompressionGzip,
+			}, compiledJob, ".py")
+			assert.Nil(t, err)
+			assert.Equal(t, compiledJob.Name, artifact.Name)
+
+			reader, err := zip.NewReader(bytes.NewReader(artifact.Contents), int64(len(artifact.Contents)))
+			assert.Nil(t, err)
+			assert.Len(t, reader.File, 1)
+			assert.Equal(t, "test.py", reader.File[0].Name)
+			assert.Equal(t, zip.Deflate, reader.File[0].Method)
+			file, err := reader.File[0].Open()
+			assert.Nil(t, err)
+			source, err := ioutil.ReadAll(file)
+			assert.Nil(t, err)
+			assert.Equal(t, compiledJob.Contents, source)
+		})
+		t.Run("should wrap source of job in a gzipped json document", func(t *testing.T) {
+			artifact, err := job.EncodeJobArtifact(models.JobArtifact{
+				Format: models.JobArtifactFormatJSON, Compression: models.JobArtifactCompressionGzip,
+			}, compiledJob, ".py")
+			assert.Nil(t, err)
+
+			reader, err := gzip.NewReader(bytes.NewReader(artifact.Contents))
+			assert.Nil(t, err)
+			var document map[string]string
+			assert.Nil(t, json.NewDecoder(reader).Decode(&document))
+			assert.Equal(t, map[string]string{
+				"name":         "test",
+				"namespace_id": "dev-team-1",
+				"file_name":    "test.py",
+				"source":       `dag = DAG("test")`,
+			}, document)
+		})
+		t.Run("should encode the same source to the same artifact", func(t *testing.T) {
+			for _, format := range []string{models.JobArtifactFormatZip, models.JobArtifactFormatJSON} {
+				artifact := models.JobArtifact{Format: format, Compression: models.JobArtifactCompressionGzip}
+				first, err := job.EncodeJobArtifact(artifact, compiledJob, ".py")
+				assert.Nil(t, err)
+				second, err := job.EncodeJobArtifact(artifact, compiledJob, ".py")
+				assert.Nil(t, err)
+				assert.Equal(t, first.Contents, second.Contents)
+			}
+		})
+	})
+}
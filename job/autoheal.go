@@ -0,0 +1,204 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/set"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// AutoHealDefaultWindow is used when auto-heal policy of a job has no window
+	AutoHealDefaultWindow = time.Hour * 24
+
+	// max runs fetched from scheduler while looking for failed runs of a job
+	autoHealRunsBatchSize = 100
+)
+
+// ProjectRepoFactory is used to list registered projects
+type ProjectRepoFactory interface {
+	New() store.ProjectRepository
+}
+
+// EventRegistrar notifies channels of a job about an event
+type EventRegistrar interface {
+	Register(context.Context, models.NamespaceSpec, models.JobSpec, models.JobEvent) error
+}
+
+// AutoHealer periodically looks for failed runs of jobs opted in to auto-heal
+// and replays them through the replay manager. Replays of a job, including
+// the manual ones, are capped at max per day of its policy
+type AutoHealer struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+	jobSvc               models.JobService
+	replaySpecRepoFac    ReplaySpecRepoFactory
+	scheduler            models.SchedulerUnit
+	eventSvc             EventRegistrar
+	interval             time.Duration
+
+	Now func() time.Time
+}
+
+// Start runs auto-heal in background every interval until closed
+func (h *AutoHealer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := h.Heal(ctx); err != nil {
+					logger.E(errors.Wrap(err, "auto-heal failed"))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops auto-heal, waiting for the ongoing pass to finish
+func (h *AutoHealer) Close() error {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	h.wg.Wait()
+	return nil
+}
+
+// Heal replays failed runs of all jobs opted in to auto-heal, failing to
+// heal a job does not stop healing of the rest
+func (h *AutoHealer) Heal(ctx context.Context) error {
+	projects, err := h.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch projects")
+	}
+	for _, projSpec := range projects {
+		namespaces, err := h.namespaceRepoFactory.New(projSpec).GetAll()
+		if err != nil {
+			logger.E(errors.Wrapf(err, "failed to fetch namespaces of project %s", projSpec.Name))
+			continue
+		}
+		for _, namespace := range namespaces {
+			jobSpecs, err := h.jobSvc.GetAll(namespace)
+			if err != nil {
+				logger.E(errors.Wrapf(err, "failed to fetch jobs of namespace %s", namespace.Name))
+				continue
+			}
+			for _, jobSpec := range jobSpecs {
+				if !jobSpec.Behavior.AutoHeal.IsEnabled() {
+					continue
+				}
+				if err := h.healJob(ctx, projSpec, namespace, jobSpec); err != nil {
+					logger.E(errors.Wrapf(err, "failed to auto-heal job %s", jobSpec.Name))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (h *AutoHealer) healJob(ctx context.Context, projSpec models.ProjectSpec, namespace models.NamespaceSpec,
+	jobSpec models.JobSpec) error {
+	now := h.Now()
+	replays, err := h.replaySpecRepoFac.New(jobSpec).GetByJobIDAndStatus(jobSpec.ID, []string{
+		models.ReplayStatusAccepted, models.ReplayStatusInProgress, models.ReplayStatusFailed,
+		models.ReplayStatusSuccess, models.ReplayStatusCancelled,
+	})
+	if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+		return errors.Wrap(err, "failed to fetch replays")
+	}
+	startOfDay := now.Truncate(time.Hour * 24)
+	replaysToday := 0
+	for _, replay := range replays {
+		if replay.Status == models.ReplayStatusAccepted || replay.Status == models.ReplayStatusInProgress {
+			// failed runs will be checked again once the active replay is over
+			return nil
+		}
+		if !replay.CreatedAt.Before(startOfDay) {
+			replaysToday++
+		}
+	}
+	if replaysToday >= jobSpec.Behavior.AutoHeal.MaxPerDay {
+		return nil
+	}
+
+	window := jobSpec.Behavior.AutoHeal.Window
+	if window <= 0 {
+		window = AutoHealDefaultWindow
+	}
+	runs, err := h.scheduler.GetDagRunStatus(ctx, projSpec, jobSpec.Name, now.Add(-window), now, autoHealRunsBatchSize)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch runs")
+	}
+	failedRuns := set.NewTreeSetWithTimeComparator()
+	for _, run := range runs {
+		if run.State == models.JobStatusStateFailed {
+			failedRuns.Add(run.ScheduledAt)
+		}
+	}
+	if failedRuns.Empty() {
+		return nil
+	}
+
+	runTimes := set.Times(failedRuns)
+	replayRequest := &models.ReplayWorkerRequest{
+		Job:     jobSpec,
+		Start:   runTimes[0].Truncate(time.Hour * 24),
+		End:     runTimes[len(runTimes)-1].Truncate(time.Hour * 24),
+		Project: projSpec,
+	}
+	replayID, err := h.jobSvc.Replay(ctx, replayRequest)
+	if err != nil {
+		if errors.Is(err, ErrConflictedJobRun) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to replay failed runs")
+	}
+	logger.I(fmt.Sprintf("auto-heal replay %s of job %s created for runs %s", replayID, jobSpec.Name,
+		formatRuns(failedRuns)))
+
+	return h.eventSvc.Register(ctx, namespace, jobSpec, models.JobEvent{
+		Type: models.JobEventTypeAutoHeal,
+		Value: map[string]*structpb.Value{
+			"replay_id":  structpb.NewStringValue(replayID),
+			"start_date": structpb.NewStringValue(replayRequest.Start.Format(ReplayDateFormat)),
+			"end_date":   structpb.NewStringValue(replayRequest.End.Format(ReplayDateFormat)),
+			"message": structpb.NewStringValue(fmt.Sprintf("replaying failed runs %s, auto-heal %d of %d today",
+				formatRuns(failedRuns), replaysToday+1, jobSpec.Behavior.AutoHeal.MaxPerDay)),
+		},
+	})
+}
+
+// NewAutoHealer creates an auto-healer checking failed runs every interval
+func NewAutoHealer(projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	jobSvc models.JobService, replaySpecRepoFac ReplaySpecRepoFactory, scheduler models.SchedulerUnit,
+	eventSvc EventRegistrar, interval time.Duration) *AutoHealer {
+	return &AutoHealer{
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+		jobSvc:               jobSvc,
+		replaySpecRepoFac:    replaySpecRepoFac,
+		scheduler:            scheduler,
+		eventSvc:             eventSvc,
+		interval:             interval,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
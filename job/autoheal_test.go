@@ -0,0 +1,135 @@
+package job_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+)
+
+func TestAutoHealer(t *testing.T) {
+	logger.InitWithWriter("ERROR", ioutil.Discard)
+
+	ctx := context.Background()
+	now := time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	healedJob := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "healed-job",
+		Behavior: models.JobSpecBehavior{
+			AutoHeal: models.JobSpecBehaviorAutoHeal{
+				Window:    time.Hour * 48,
+				MaxPerDay: 2,
+			},
+		},
+	}
+	unhealedJob := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "unhealed-job",
+	}
+	replayStatuses := []string{
+		models.ReplayStatusAccepted, models.ReplayStatusInProgress, models.ReplayStatusFailed,
+		models.ReplayStatusSuccess, models.ReplayStatusCancelled,
+	}
+
+	setup := func(replays []models.ReplaySpec) (*mock.ProjectRepoFactory, *mock.NamespaceRepoFactory,
+		*mock.JobService, *mock.ReplaySpecRepoFactory) {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projectSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		namespaceRepo := new(mock.NamespaceRepository)
+		namespaceRepo.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFac := new(mock.NamespaceRepoFactory)
+		namespaceRepoFac.On("New", projectSpec).Return(namespaceRepo)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{healedJob, unhealedJob}, nil)
+
+		replayRepo := new(mock.ReplayRepository)
+		replayRepo.On("GetByJobIDAndStatus", healedJob.ID, replayStatuses).Return(replays, nil)
+		replayRepoFac := new(mock.ReplaySpecRepoFactory)
+		replayRepoFac.On("New", healedJob).Return(replayRepo)
+		return projectRepoFac, namespaceRepoFac, jobService, replayRepoFac
+	}
+
+	t.Run("should replay failed runs of opted in jobs and notify", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService, replayRepoFac := setup([]models.ReplaySpec{
+			{Status: models.ReplayStatusSuccess, CreatedAt: now.Add(-time.Hour * 24)},
+		})
+		defer jobService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		scheduler.On("GetDagRunStatus", ctx, projectSpec, healedJob.Name, now.Add(-time.Hour*48), now, 100).Return([]models.JobStatus{
+			{ScheduledAt: time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), State: models.JobStatusStateFailed},
+			{ScheduledAt: time.Date(2021, 5, 18, 2, 0, 0, 0, time.UTC), State: models.JobStatusStateFailed},
+			{ScheduledAt: time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC), State: models.JobStatusStateSuccess},
+		}, nil)
+		defer scheduler.AssertExpectations(t)
+
+		jobService.On("Replay", ctx, &models.ReplayWorkerRequest{
+			Job:     healedJob,
+			Start:   time.Date(2021, 5, 18, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2021, 5, 19, 0, 0, 0, 0, time.UTC),
+			Project: projectSpec,
+		}).Return("replay-id", nil)
+
+		eventService := new(mock.EventService)
+		eventService.On("Register", ctx, namespaceSpec, healedJob, mock2.MatchedBy(func(evt models.JobEvent) bool {
+			return evt.Type == models.JobEventTypeAutoHeal &&
+				evt.Value["replay_id"].GetStringValue() == "replay-id" &&
+				evt.Value["message"].GetStringValue() == "replaying failed runs 2021-05-18T02:00:00+00:00, "+
+					"2021-05-19T02:00:00+00:00, auto-heal 1 of 2 today"
+		})).Return(nil)
+		defer eventService.AssertExpectations(t)
+
+		healer := job.NewAutoHealer(projectRepoFac, namespaceRepoFac, jobService, replayRepoFac, scheduler, eventService, time.Minute)
+		healer.Now = func() time.Time { return now }
+		assert.Nil(t, healer.Heal(ctx))
+	})
+	t.Run("should not replay once max replays of the day are reached", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService, replayRepoFac := setup([]models.ReplaySpec{
+			{Status: models.ReplayStatusFailed, CreatedAt: now.Add(-time.Hour * 2)},
+			{Status: models.ReplayStatusSuccess, CreatedAt: now.Add(-time.Hour)},
+		})
+		defer jobService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		defer scheduler.AssertExpectations(t)
+
+		healer := job.NewAutoHealer(projectRepoFac, namespaceRepoFac, jobService, replayRepoFac, scheduler, nil, time.Minute)
+		healer.Now = func() time.Time { return now }
+		assert.Nil(t, healer.Heal(ctx))
+	})
+	t.Run("should wait for active replay of the job to finish", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService, replayRepoFac := setup([]models.ReplaySpec{
+			{Status: models.ReplayStatusInProgress, CreatedAt: now.Add(-time.Hour * 30)},
+		})
+		defer jobService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		defer scheduler.AssertExpectations(t)
+
+		healer := job.NewAutoHealer(projectRepoFac, namespaceRepoFac, jobService, replayRepoFac, scheduler, nil, time.Minute)
+		healer.Now = func() time.Time { return now }
+		assert.Nil(t, healer.Heal(ctx))
+	})
+}
@@ -0,0 +1,137 @@
+package job
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CanaryJobSuffix is appended to the name of jobs deployed as canary
+	CanaryJobSuffix = "_canary"
+)
+
+// CanaryJobName is the name of canary of a job
+func CanaryJobName(jobName string) string {
+	return jobName + CanaryJobSuffix
+}
+
+// CanaryJobSpec is the canary of a job, it is never scheduled, doesn't wait
+// for upstream jobs and doesn't notify anyone, so it runs only when triggered
+func CanaryJobSpec(spec models.JobSpec) models.JobSpec {
+	spec.Name = CanaryJobName(spec.Name)
+	spec.Schedule.Interval = ""
+	spec.Schedule.EndDate = nil
+	spec.Behavior.DependsOnPast = false
+	spec.Behavior.CatchUp = false
+	spec.Behavior.Notify = nil
+	spec.Dependencies = nil
+	return spec
+}
+
+// CanaryProject is the project canary jobs of projSpec are deployed to, the
+// staging scheduler if configured otherwise the project itself
+func CanaryProject(projSpec models.ProjectSpec) models.ProjectSpec {
+	storagePath, ok := projSpec.Config[models.ProjectCanaryStoragePathKey]
+	if !ok || storagePath == "" {
+		return projSpec
+	}
+	return stagingProject(projSpec, storagePath, projSpec.Config[models.ProjectCanarySchedulerHostKey],
+		map[string]string{
+			models.ProjectSecretStorageKey: models.ProjectSecretCanaryStorageKey,
+			models.ProjectSchedulerAuth:    models.ProjectSecretCanarySchedulerAuth,
+		})
+}
+
+// stagingProject is projSpec with its specification store and scheduler host
+// replaced, host is kept if empty. Secrets are replaced with the ones named
+// in stagingSecrets when the project has them
+func stagingProject(projSpec models.ProjectSpec, storagePath, host string,
+	stagingSecrets map[string]string) models.ProjectSpec {
+	config := map[string]string{}
+	for key, value := range projSpec.Config {
+		config[key] = value
+	}
+	config[models.ProjectStoragePathKey] = storagePath
+	if host != "" {
+		config[models.ProjectSchedulerHost] = host
+	}
+
+	var secrets models.ProjectSecrets
+	for _, item := range projSpec.Secret {
+		if stagingName, ok := stagingSecrets[item.Name]; ok {
+			if value, ok := projSpec.Secret.GetByName(stagingName); ok {
+				item.Value = value
+			}
+		}
+		secrets = append(secrets, item)
+	}
+
+	projSpec.Config = config
+	projSpec.Secret = secrets
+	return projSpec
+}
+
+// DeployCanary saves and uploads the canary of each job to the scheduler of
+// CanaryProject, jobs of the namespace are left untouched
+func (srv *Service) DeployCanary(ctx context.Context, namespace models.NamespaceSpec, jobSpecs []models.JobSpec,
+	progressObserver progress.Observer) error {
+	jobRepo, err := srv.jobRepoFactory.New(ctx, CanaryProject(namespace.ProjectSpec))
+	if err != nil {
+		return err
+	}
+
+	var deployErrors error
+	for _, jobSpec := range jobSpecs {
+		canarySpec := CanaryJobSpec(jobSpec)
+		err := srv.deployCanary(ctx, jobRepo, namespace, canarySpec)
+		srv.notifyProgress(progressObserver, &EventJobUpload{
+			Job: canarySpec,
+			Err: err,
+		})
+		if err != nil {
+			deployErrors = multierror.Append(deployErrors, errors.Wrapf(err, "failed to deploy canary of %s", jobSpec.Name))
+		}
+	}
+	return deployErrors
+}
+
+// canary specs are saved as well, so runs of canary can read their spec
+// from optimus like any other job
+func (srv *Service) deployCanary(ctx context.Context, jobRepo store.JobRepository, namespace models.NamespaceSpec,
+	canarySpec models.JobSpec) error {
+	if err := srv.Create(namespace, canarySpec); err != nil {
+		return err
+	}
+	compiledJob, err := srv.compileArtifact(namespace, canarySpec)
+	if err != nil {
+		return err
+	}
+	return jobRepo.Save(ctx, compiledJob)
+}
+
+// DeleteCanary deletes canary of each job from the specification store and
+// the scheduler they were deployed to
+func (srv *Service) DeleteCanary(ctx context.Context, namespace models.NamespaceSpec, jobSpecs []models.JobSpec) error {
+	jobRepo, err := srv.jobRepoFactory.New(ctx, CanaryProject(namespace.ProjectSpec))
+	if err != nil {
+		return err
+	}
+	jobSpecRepo := srv.jobSpecRepoFactory.New(namespace)
+
+	var deleteErrors error
+	for _, jobSpec := range jobSpecs {
+		canaryName := CanaryJobName(jobSpec.Name)
+		if err := jobRepo.Delete(ctx, namespace, canaryName); err != nil {
+			deleteErrors = multierror.Append(deleteErrors, errors.Wrapf(err, "failed to delete compiled canary %s", canaryName))
+		}
+		if err := jobSpecRepo.Delete(canaryName); err != nil {
+			deleteErrors = multierror.Append(deleteErrors, errors.Wrapf(err, "failed to delete canary spec %s", canaryName))
+		}
+	}
+	return deleteErrors
+}
@@ -0,0 +1,158 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type uploadObserver struct {
+	uploaded []string
+}
+
+func (obs *uploadObserver) Notify(e progress.Event) {
+	if evt, ok := e.(*job.EventJobUpload); ok && evt.Err == nil {
+		obs.uploaded = append(obs.uploaded, evt.Job.Name)
+	}
+}
+
+func TestCanary(t *testing.T) {
+	ctx := context.Background()
+	endDate := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	jobSpec := models.JobSpec{
+		Version: 1,
+		Name:    "test",
+		Owner:   "optimus",
+		Schedule: models.JobSpecSchedule{
+			StartDate: time.Date(2020, 12, 02, 0, 0, 0, 0, time.UTC),
+			EndDate:   &endDate,
+			Interval:  "@daily",
+		},
+		Behavior: models.JobSpecBehavior{
+			DependsOnPast: true,
+			Notify: []models.JobSpecNotifier{
+				{On: models.JobEventTypeFailure, Channels: []string{"slack://#alerts"}},
+			},
+		},
+		Dependencies: map[string]models.JobSpecDependency{
+			"upstream": {},
+		},
+	}
+
+	t.Run("CanaryJobSpec", func(t *testing.T) {
+		t.Run("should unschedule canary of job", func(t *testing.T) {
+			canarySpec := job.CanaryJobSpec(jobSpec)
+			assert.Equal(t, "test_canary", canarySpec.Name)
+			assert.Equal(t, "", canarySpec.Schedule.Interval)
+			assert.Nil(t, canarySpec.Schedule.EndDate)
+			assert.False(t, canarySpec.Behavior.DependsOnPast)
+			assert.Empty(t, canarySpec.Behavior.Notify)
+			assert.Empty(t, canarySpec.Dependencies)
+			// job itself is left untouched
+			assert.Equal(t, "test", jobSpec.Name)
+			assert.Equal(t, "@daily", jobSpec.Schedule.Interval)
+		})
+	})
+	t.Run("CanaryProject", func(t *testing.T) {
+		projSpec := models.ProjectSpec{
+			Name: "proj",
+			Config: map[string]string{
+				models.ProjectStoragePathKey: "gs://prod/dags",
+				models.ProjectSchedulerHost:  "http://airflow.prod",
+			},
+			Secret: models.ProjectSecrets{
+				{Name: models.ProjectSecretStorageKey, Value: "prod-storage"},
+				{Name: models.ProjectSchedulerAuth, Value: "prod-auth"},
+			},
+		}
+		t.Run("should use project when staging scheduler is not configured", func(t *testing.T) {
+			assert.Equal(t, projSpec, job.CanaryProject(projSpec))
+		})
+		t.Run("should use staging scheduler when configured", func(t *testing.T) {
+			stagingSpec := projSpec
+			stagingSpec.Config = map[string]string{
+				models.ProjectStoragePathKey:         "gs://prod/dags",
+				models.ProjectSchedulerHost:          "http://airflow.prod",
+				models.ProjectCanaryStoragePathKey:   "gs://staging/dags",
+				models.ProjectCanarySchedulerHostKey: "http://airflow.staging",
+			}
+			stagingSpec.Secret = append(models.ProjectSecrets{
+				{Name: models.ProjectSecretCanaryStorageKey, Value: "staging-storage"},
+			}, projSpec.Secret...)
+
+			canaryProject := job.CanaryProject(stagingSpec)
+			assert.Equal(t, "gs://staging/dags", canaryProject.Config[models.ProjectStoragePathKey])
+			assert.Equal(t, "http://airflow.staging", canaryProject.Config[models.ProjectSchedulerHost])
+			storageSecret, _ := canaryProject.Secret.GetByName(models.ProjectSecretStorageKey)
+			assert.Equal(t, "staging-storage", storageSecret)
+			authSecret, _ := canaryProject.Secret.GetByName(models.ProjectSchedulerAuth)
+			assert.Equal(t, "prod-auth", authSecret)
+			// project itself is left untouched
+			assert.Equal(t, "gs://prod/dags", stagingSpec.Config[models.ProjectStoragePathKey])
+		})
+	})
+	t.Run("DeployCanary", func(t *testing.T) {
+		projSpec := models.ProjectSpec{
+			Name: "proj",
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "dev-team-1",
+			ProjectSpec: projSpec,
+		}
+		canarySpec := job.CanaryJobSpec(jobSpec)
+		compiledCanary := models.Job{
+			Name:        canarySpec.Name,
+			Contents:    []byte("canary"),
+			NamespaceID: namespaceSpec.ID.String(),
+		}
+
+		t.Run("should save and upload canary of jobs", func(t *testing.T) {
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("Save", canarySpec).Return(nil)
+			defer jobSpecRepo.AssertExpectations(t)
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("Save", ctx, compiledCanary).Return(nil)
+			defer jobRepo.AssertExpectations(t)
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+
+			compiler := new(mock.Compiler)
+			compiler.On("Compile", namespaceSpec, canarySpec).Return(compiledCanary, nil)
+			defer compiler.AssertExpectations(t)
+
+			observer := new(uploadObserver)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, nil, nil, nil, nil, nil, nil, nil)
+			err := svc.DeployCanary(ctx, namespaceSpec, []models.JobSpec{jobSpec}, observer)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"test_canary"}, observer.uploaded)
+		})
+		t.Run("should delete canary of jobs", func(t *testing.T) {
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("Delete", "test_canary").Return(nil)
+			defer jobSpecRepo.AssertExpectations(t)
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("Delete", ctx, namespaceSpec, "test_canary").Return(nil)
+			defer jobRepo.AssertExpectations(t)
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, nil, nil, nil, nil, nil, nil, nil, nil)
+			err := svc.DeleteCanary(ctx, namespaceSpec, []models.JobSpec{jobSpec})
+			assert.Nil(t, err)
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package job
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// assets can be large, their changes are tracked by a short hash of content
+	changelogAssetHashLength = 12
+)
+
+// DiffJobSpecs compares job specifications of a namespace before and after
+// a deployment, changes are sorted by job name
+func DiffJobSpecs(previous, current []models.JobSpec) []models.JobChange {
+	previousByName := map[string]models.JobSpec{}
+	for _, spec := range previous {
+		previousByName[spec.Name] = spec
+	}
+	currentByName := map[string]models.JobSpec{}
+	for _, spec := range current {
+		currentByName[spec.Name] = spec
+	}
+
+	var changes []models.JobChange
+	for name, spec := range currentByName {
+		prevSpec, ok := previousByName[name]
+		if !ok {
+			changes = append(changes, models.JobChange{
+				Name: name,
+				Type: models.JobChangeTypeAdded,
+			})
+			continue
+		}
+		if fields := diffFields(flattenJobSpec(prevSpec), flattenJobSpec(spec)); len(fields) > 0 {
+			changes = append(changes, models.JobChange{
+				Name:   name,
+				Type:   models.JobChangeTypeModified,
+				Fields: fields,
+			})
+		}
+	}
+	for name := range previousByName {
+		if _, ok := currentByName[name]; !ok {
+			changes = append(changes, models.JobChange{
+				Name: name,
+				Type: models.JobChangeTypeRemoved,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+func diffFields(previous, current map[string]string) []models.JobFieldChange {
+	var fields []models.JobFieldChange
+	for field, val := range current {
+		if prevVal, ok := previous[field]; !ok || prevVal != val {
+			fields = append(fields, models.JobFieldChange{
+				Field: field,
+				Old:   prevVal,
+				New:   val,
+			})
+		}
+	}
+	for field, prevVal := range previous {
+		if _, ok := current[field]; !ok {
+			fields = append(fields, models.JobFieldChange{
+				Field: field,
+				Old:   prevVal,
+			})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Field < fields[j].Field
+	})
+	return fields
+}
+
+// flattenJobSpec converts user defined fields of a job spec to dot
+// separated paths and their values, empty values are skipped
+func flattenJobSpec(spec models.JobSpec) map[string]string {
+	flat := map[string]string{}
+	set := func(key, val string) {
+		if val != "" {
+			flat[key] = val
+		}
+	}
+
+	set("version", strconv.Itoa(spec.Version))
+	set("owner", spec.Owner)
+	set("ownership.email", spec.Ownership.Email)
+	set("ownership.team", spec.Ownership.Team)
+	set("ownership.slack_channel", spec.Ownership.SlackChannel)
+	set("description", spec.Description)
+	for k, v := range spec.Labels {
+		set("labels."+k, v)
+	}
+
+	set("schedule.start_date", spec.Schedule.StartDate.Format(models.JobDatetimeLayout))
+	if spec.Schedule.EndDate != nil {
+		set("schedule.end_date", spec.Schedule.EndDate.Format(models.JobDatetimeLayout))
+	}
+	set("schedule.interval", spec.Schedule.Interval)
+	var skipDates, extraRuns []string
+	for _, date := range spec.Schedule.Exceptions.SkipDates {
+		skipDates = append(skipDates, date.Format(models.JobDatetimeLayout))
+	}
+	for _, run := range spec.Schedule.Exceptions.ExtraRuns {
+		extraRuns = append(extraRuns, run.Format(time.RFC3339))
+	}
+	set("schedule.exceptions.skip_dates", strings.Join(skipDates, ","))
+	set("schedule.exceptions.extra_runs", strings.Join(extraRuns, ","))
+	set("schedule.exceptions.calendars", strings.Join(spec.Schedule.Exceptions.Calendars, ","))
+	if spec.Schedule.MaxActiveRuns > 0 {
+		set("schedule.max_active_runs", strconv.Itoa(spec.Schedule.MaxActiveRuns))
+	}
+
+	set("behavior.depends_on_past", strconv.FormatBool(spec.Behavior.DependsOnPast))
+	set("behavior.catch_up", strconv.FormatBool(spec.Behavior.CatchUp))
+	set("behavior.retry.count", strconv.Itoa(spec.Behavior.Retry.Count))
+	set("behavior.retry.delay", spec.Behavior.Retry.Delay.String())
+	set("behavior.retry.exponential_backoff", strconv.FormatBool(spec.Behavior.Retry.ExponentialBackoff))
+	if spec.Behavior.AutoHeal.IsEnabled() {
+		set("behavior.auto_heal.window", spec.Behavior.AutoHeal.Window.String())
+		set("behavior.auto_heal.max_per_day", strconv.Itoa(spec.Behavior.AutoHeal.MaxPerDay))
+	}
+	for _, notify := range spec.Behavior.Notify {
+		set(fmt.Sprintf("behavior.notify.%s.channels", notify.On), strings.Join(notify.Channels, ","))
+		for k, v := range notify.Config {
+			set(fmt.Sprintf("behavior.notify.%s.config.%s", notify.On, k), v)
+		}
+	}
+
+	set("task.name", pluginName(spec.Task.Unit))
+	set("task.version", spec.Task.Version)
+	set("task.template_engine", spec.Task.TemplateEngine)
+	for _, conf := range spec.Task.Config {
+		set("task.config."+conf.Name, conf.Value)
+	}
+	set("task.window.size", spec.Task.Window.SizeString())
+	set("task.window.offset", spec.Task.Window.OffsetString())
+	set("task.window.truncate_to", spec.Task.Window.TruncateTo)
+
+	for name, dep := range spec.Dependencies {
+		set("dependencies."+name, dep.Type.String())
+	}
+	for _, asset := range spec.Assets.GetAll() {
+		sum := sha256.Sum256([]byte(asset.Value))
+		set("assets."+asset.Name, "sha256:"+hex.EncodeToString(sum[:])[:changelogAssetHashLength])
+	}
+	for _, hook := range spec.Hooks {
+		hookName := pluginName(hook.Unit)
+		if hookName == "" {
+			continue
+		}
+		set("hooks."+hookName, "enabled")
+		for _, conf := range hook.Config {
+			set(fmt.Sprintf("hooks.%s.config.%s", hookName, conf.Name), conf.Value)
+		}
+	}
+	if spec.Lifecycle.Retention.PartitionDays != 0 {
+		set("lifecycle.retention.partition_days", strconv.Itoa(spec.Lifecycle.Retention.PartitionDays))
+	}
+	return flat
+}
+
+func pluginName(unit *models.Plugin) string {
+	if unit == nil || unit.Base == nil {
+		return ""
+	}
+	if info := unit.Info(); info != nil {
+		return info.Name
+	}
+	return ""
+}
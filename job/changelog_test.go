@@ -0,0 +1,83 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJobSpecs(t *testing.T) {
+	jobSpec := func(name, interval string, config models.JobSpecConfigs) models.JobSpec {
+		return models.JobSpec{
+			Version: 1,
+			Name:    name,
+			Owner:   "optimus",
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2020, 12, 02, 0, 0, 0, 0, time.UTC),
+				Interval:  interval,
+			},
+			Task: models.JobSpecTask{
+				Config: config,
+			},
+			Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+				{Name: "query.sql", Value: "select * from " + name},
+			}),
+		}
+	}
+
+	t.Run("should return added, modified and removed jobs sorted by name", func(t *testing.T) {
+		previous := []models.JobSpec{
+			jobSpec("job-b", "@daily", models.JobSpecConfigs{{Name: "TABLE", Value: "tab1"}, {Name: "DATASET", Value: "data"}}),
+			jobSpec("job-c", "@daily", nil),
+			jobSpec("job-d", "@daily", nil),
+		}
+		current := []models.JobSpec{
+			jobSpec("job-d", "@daily", nil),
+			jobSpec("job-b", "@hourly", models.JobSpecConfigs{{Name: "TABLE", Value: "tab2"}, {Name: "LOAD_METHOD", Value: "APPEND"}}),
+			jobSpec("job-a", "@daily", nil),
+		}
+
+		changes := job.DiffJobSpecs(previous, current)
+		assert.Equal(t, []models.JobChange{
+			{
+				Name: "job-a",
+				Type: models.JobChangeTypeAdded,
+			},
+			{
+				Name: "job-b",
+				Type: models.JobChangeTypeModified,
+				Fields: []models.JobFieldChange{
+					{Field: "schedule.interval", Old: "@daily", New: "@hourly"},
+					{Field: "task.config.DATASET", Old: "data", New: ""},
+					{Field: "task.config.LOAD_METHOD", Old: "", New: "APPEND"},
+					{Field: "task.config.TABLE", Old: "tab1", New: "tab2"},
+				},
+			},
+			{
+				Name: "job-c",
+				Type: models.JobChangeTypeRemoved,
+			},
+		}, changes)
+	})
+	t.Run("should track asset changes by hash of content", func(t *testing.T) {
+		previous := jobSpec("job-a", "@daily", nil)
+		current := jobSpec("job-a", "@daily", nil)
+		current.Assets = *models.JobAssets{}.New([]models.JobSpecAsset{
+			{Name: "query.sql", Value: "select 1"},
+		})
+
+		changes := job.DiffJobSpecs([]models.JobSpec{previous}, []models.JobSpec{current})
+		assert.Len(t, changes, 1)
+		assert.Len(t, changes[0].Fields, 1)
+		assert.Equal(t, "assets.query.sql", changes[0].Fields[0].Field)
+		assert.Contains(t, changes[0].Fields[0].Old, "sha256:")
+		assert.NotEqual(t, changes[0].Fields[0].Old, changes[0].Fields[0].New)
+	})
+	t.Run("should return no changes for same specs", func(t *testing.T) {
+		specs := []models.JobSpec{jobSpec("job-a", "@daily", nil)}
+		assert.Empty(t, job.DiffJobSpecs(specs, specs))
+	})
+}
@@ -50,6 +50,11 @@ func (com *Compiler) Compile(namespaceSpec models.NamespaceSpec, jobSpec models.
 		}
 	}
 
+	skipDates, err := jobSpec.Schedule.Exceptions.SkipDays(namespaceSpec.ProjectSpec)
+	if err != nil {
+		return models.Job{}, errors.Wrap(err, "failed to resolve schedule exceptions")
+	}
+
 	var buf bytes.Buffer
 	if err = tmpl.Execute(&buf, struct {
 		Namespace                  models.NamespaceSpec
@@ -64,6 +69,7 @@ func (com *Compiler) Compile(namespaceSpec models.NamespaceSpec, jobSpec models.
 		JobSpecDependencyTypeInter string
 		JobSpecDependencyTypeExtra string
 		SLAMissDurationInSec       int64
+		SkipDates                  []string
 		Version                    string
 	}{
 		Namespace:                  namespaceSpec,
@@ -78,6 +84,7 @@ func (com *Compiler) Compile(namespaceSpec models.NamespaceSpec, jobSpec models.
 		JobSpecDependencyTypeInter: string(models.JobSpecDependencyTypeInter),
 		JobSpecDependencyTypeExtra: string(models.JobSpecDependencyTypeExtra),
 		SLAMissDurationInSec:       slaMissDurationInSec,
+		SkipDates:                  skipDates,
 		Version:                    config.Version,
 	}); err != nil {
 		return models.Job{}, errors.Wrap(err, "failed to templatize job")
@@ -0,0 +1,65 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// CostCollector records what runs of jobs cost, from the datastore jobs
+// their tasks report to have run, and checks budget of their project
+type CostCollector struct {
+	instSvc       models.InstanceService
+	dsRepo        models.DatastoreRepo
+	budgetMonitor *CostBudgetMonitor
+}
+
+// Collect looks up cost of every datastore job in the datastore it belongs
+// to and records their sum with the run. Jobs of datastores which don't
+// bill them count as free
+func (c *CostCollector) Collect(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec,
+	scheduledAt time.Time, datastoreJobs []string) error {
+	cost := models.InstanceCost{DatastoreJobs: datastoreJobs}
+	for _, datastoreJob := range datastoreJobs {
+		for _, ds := range c.dsRepo.GetAll() {
+			reader, ok := ds.(models.DatastoreJobCostReader)
+			if !ok {
+				continue
+			}
+			resp, err := reader.JobCost(ctx, models.JobCostRequest{
+				JobID:     datastoreJob,
+				Since:     scheduledAt,
+				Project:   namespace.ProjectSpec,
+				Namespace: namespace,
+			})
+			if err != nil {
+				return errors.Wrapf(err, "failed to read cost of %s in %s", datastoreJob, ds.Name())
+			}
+			if resp.Supported {
+				cost.BytesBilled += resp.BytesBilled
+				cost.SlotMillis += resp.SlotMillis
+				break
+			}
+		}
+	}
+	if err := c.instSvc.RecordCost(jobSpec, scheduledAt, cost); err != nil {
+		return err
+	}
+	if c.budgetMonitor == nil || cost.BytesBilled == 0 {
+		return nil
+	}
+	return c.budgetMonitor.Check(ctx, namespace.ProjectSpec)
+}
+
+// NewCostCollector creates a collector of cost of runs, budgets are not
+// checked without a monitor
+func NewCostCollector(instSvc models.InstanceService, dsRepo models.DatastoreRepo,
+	budgetMonitor *CostBudgetMonitor) *CostCollector {
+	return &CostCollector{
+		instSvc:       instSvc,
+		dsRepo:        dsRepo,
+		budgetMonitor: budgetMonitor,
+	}
+}
@@ -0,0 +1,100 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	costBudgetMonthLayout = "2006-01"
+)
+
+// CostBudgetMonitor alerts projects whose runs billed more than thresholds
+// of their monthly budget, see models.ProjectCostBudgetKey. It is checked
+// as cost of runs is recorded, each threshold is alerted once a month
+type CostBudgetMonitor struct {
+	costRepo store.ProjectCostRepository
+	eventSvc EventRegistrar
+
+	Now func() time.Time
+}
+
+// Status returns what runs of jobs of the project billed in the current
+// month against its budget
+func (m *CostBudgetMonitor) Status(proj models.ProjectSpec) (models.CostBudgetStatus, error) {
+	budget, err := proj.CostBudget()
+	if err != nil {
+		return models.CostBudgetStatus{}, err
+	}
+	return m.status(proj, budget)
+}
+
+func (m *CostBudgetMonitor) status(proj models.ProjectSpec, budget int64) (models.CostBudgetStatus, error) {
+	month := models.MonthStart(m.Now())
+	spent, err := m.costRepo.GetBytesBilled(proj, month)
+	if err != nil {
+		return models.CostBudgetStatus{}, errors.Wrapf(err, "failed to sum cost of project %s", proj.Name)
+	}
+	return models.CostBudgetStatus{
+		Month:       month,
+		BudgetBytes: budget,
+		SpentBytes:  spent,
+	}, nil
+}
+
+// Check notifies the highest threshold of budget spend of the project
+// reached this month unless it was alerted already. Lower thresholds
+// crossed at the same time are recorded without being notified
+func (m *CostBudgetMonitor) Check(ctx context.Context, proj models.ProjectSpec) error {
+	budget, err := proj.CostBudget()
+	if err != nil || budget == 0 {
+		return err
+	}
+	status, err := m.status(proj, budget)
+	if err != nil {
+		return err
+	}
+
+	var alert int
+	for _, threshold := range status.ThresholdsReached() {
+		saved, err := m.costRepo.SaveBudgetAlert(proj, status.Month, threshold)
+		if err != nil {
+			return errors.Wrapf(err, "failed to record budget alert of project %s", proj.Name)
+		}
+		if saved {
+			alert = threshold
+		}
+	}
+	if alert == 0 {
+		return nil
+	}
+	return m.eventSvc.Register(ctx, models.NamespaceSpec{ProjectSpec: proj}, models.JobSpec{}, models.JobEvent{
+		Type: models.JobEventTypeCostBudget,
+		Value: map[string]*structpb.Value{
+			"threshold": structpb.NewNumberValue(float64(alert)),
+			"month":     structpb.NewStringValue(status.Month.Format(costBudgetMonthLayout)),
+			"spent":     structpb.NewStringValue(models.FormatBytes(status.SpentBytes)),
+			"budget":    structpb.NewStringValue(models.FormatBytes(status.BudgetBytes)),
+			"message": structpb.NewStringValue(fmt.Sprintf("runs of project %s billed %.1f%% of its budget in %s",
+				proj.Name, status.Percent(), status.Month.Format(costBudgetMonthLayout))),
+		},
+	})
+}
+
+// NewCostBudgetMonitor creates a monitor of budgets notifying through the
+// event service
+func NewCostBudgetMonitor(costRepo store.ProjectCostRepository, eventSvc EventRegistrar) *CostBudgetMonitor {
+	return &CostBudgetMonitor{
+		costRepo: costRepo,
+		eventSvc: eventSvc,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestCostBudgetMonitor(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 6, 20, 10, 0, 0, 0, time.UTC)
+	june := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		Name: "a-data-project",
+		Config: map[string]string{
+			models.ProjectCostBudgetKey:       "1KiB",
+			models.ProjectCostBudgetNotifyKey: "slack://#data-costs",
+		},
+	}
+
+	t.Run("should notify the highest threshold reached not alerted yet", func(t *testing.T) {
+		costRepo := new(mock.ProjectCostRepository)
+		defer costRepo.AssertExpectations(t)
+		costRepo.On("GetBytesBilled", projectSpec, june).Return(int64(870), nil)
+		costRepo.On("SaveBudgetAlert", projectSpec, june, 50).Return(false, nil)
+		costRepo.On("SaveBudgetAlert", projectSpec, june, 80).Return(true, nil)
+
+		eventSvc := new(mock.EventService)
+		defer eventSvc.AssertExpectations(t)
+		eventSvc.On("Register", ctx, models.NamespaceSpec{ProjectSpec: projectSpec}, models.JobSpec{},
+			mock2.MatchedBy(func(evt models.JobEvent) bool {
+				return evt.Type == models.JobEventTypeCostBudget &&
+					evt.Value["threshold"].GetNumberValue() == 80 &&
+					evt.Value["month"].GetStringValue() == "2021-06" &&
+					evt.Value["budget"].GetStringValue() == "1.0 KiB"
+			})).Return(nil)
+
+		monitor := job.NewCostBudgetMonitor(costRepo, eventSvc)
+		monitor.Now = func() time.Time { return now }
+		assert.Nil(t, monitor.Check(ctx, projectSpec))
+	})
+	t.Run("should not notify thresholds alerted this month", func(t *testing.T) {
+		costRepo := new(mock.ProjectCostRepository)
+		costRepo.On("GetBytesBilled", projectSpec, june).Return(int64(600), nil)
+		costRepo.On("SaveBudgetAlert", projectSpec, june, 50).Return(false, nil)
+		eventSvc := new(mock.EventService)
+
+		monitor := job.NewCostBudgetMonitor(costRepo, eventSvc)
+		monitor.Now = func() time.Time { return now }
+		assert.Nil(t, monitor.Check(ctx, projectSpec))
+		eventSvc.AssertNotCalled(t, "Register")
+	})
+	t.Run("should not check projects without a budget", func(t *testing.T) {
+		costRepo := new(mock.ProjectCostRepository)
+		monitor := job.NewCostBudgetMonitor(costRepo, new(mock.EventService))
+		assert.Nil(t, monitor.Check(ctx, models.ProjectSpec{Name: "a-data-project"}))
+		costRepo.AssertNotCalled(t, "GetBytesBilled")
+	})
+	t.Run("should return spend of the month against budget", func(t *testing.T) {
+		costRepo := new(mock.ProjectCostRepository)
+		costRepo.On("GetBytesBilled", projectSpec, june).Return(int64(2048), nil)
+
+		monitor := job.NewCostBudgetMonitor(costRepo, new(mock.EventService))
+		monitor.Now = func() time.Time { return now }
+		status, err := monitor.Status(projectSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, models.CostBudgetStatus{Month: june, BudgetBytes: 1024, SpentBytes: 2048}, status)
+		assert.Equal(t, []int{50, 80, 100}, status.ThresholdsReached())
+	})
+}
@@ -0,0 +1,61 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostCollector(t *testing.T) {
+	ctx := context.Background()
+	scheduledAt := time.Date(2021, 7, 1, 2, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{Name: "a-data-project"}
+	namespaceSpec := models.NamespaceSpec{Name: "a-namespace", ProjectSpec: projectSpec}
+	jobSpec := models.JobSpec{Name: "revenue"}
+	costRequest := func(jobID string) models.JobCostRequest {
+		return models.JobCostRequest{JobID: jobID, Since: scheduledAt, Project: projectSpec, Namespace: namespaceSpec}
+	}
+
+	t.Run("should record sum of cost of datastore jobs of the run", func(t *testing.T) {
+		reader := new(mock.DatastoreJobCostReader)
+		defer reader.AssertExpectations(t)
+		reader.On("JobCost", ctx, costRequest("proj:US.job-1")).Return(models.JobCostResponse{
+			Supported: true, BytesBilled: 100, SlotMillis: 20}, nil)
+		reader.On("JobCost", ctx, costRequest("proj:US.job-2")).Return(models.JobCostResponse{
+			Supported: true, BytesBilled: 50, SlotMillis: 10}, nil)
+		reader.On("JobCost", ctx, costRequest("spark-app-1")).Return(models.JobCostResponse{}, nil)
+		datastoreRepo := new(mock.SupportedDatastoreRepo)
+		datastoreRepo.On("GetAll").Return([]models.Datastorer{new(mock.Datastorer), reader})
+
+		instanceService := new(mock.InstanceService)
+		defer instanceService.AssertExpectations(t)
+		instanceService.On("RecordCost", jobSpec, scheduledAt, models.InstanceCost{
+			DatastoreJobs: []string{"proj:US.job-1", "spark-app-1", "proj:US.job-2"},
+			BytesBilled:   150,
+			SlotMillis:    30,
+		}).Return(nil)
+
+		collector := job.NewCostCollector(instanceService, datastoreRepo, nil)
+		assert.Nil(t, collector.Collect(ctx, namespaceSpec, jobSpec, scheduledAt,
+			[]string{"proj:US.job-1", "spark-app-1", "proj:US.job-2"}))
+	})
+	t.Run("should not record cost if a job can't be looked up", func(t *testing.T) {
+		reader := new(mock.DatastoreJobCostReader)
+		reader.On("Name").Return("bigquery")
+		reader.On("JobCost", ctx, costRequest("proj:US.job-1")).Return(models.JobCostResponse{}, errors.New("access denied"))
+		datastoreRepo := new(mock.SupportedDatastoreRepo)
+		datastoreRepo.On("GetAll").Return([]models.Datastorer{reader})
+
+		instanceService := new(mock.InstanceService)
+		collector := job.NewCostCollector(instanceService, datastoreRepo, nil)
+		err := collector.Collect(ctx, namespaceSpec, jobSpec, scheduledAt, []string{"proj:US.job-1"})
+		assert.Contains(t, err.Error(), "access denied")
+		instanceService.AssertNotCalled(t, "RecordCost")
+	})
+}
@@ -2,6 +2,8 @@ package job
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	"github.com/odpf/optimus/core/progress"
 	"github.com/odpf/optimus/models"
@@ -46,17 +48,9 @@ func (r *dependencyResolver) Resolve(projectSpec models.ProjectSpec, projectJobS
 func (r *dependencyResolver) resolveInferredDependencies(jobSpec models.JobSpec, projectSpec models.ProjectSpec,
 	projectJobSpecRepo store.ProjectJobSpecRepository, observer progress.Observer) (models.JobSpec, error) {
 	// get destinations of dependencies, assets should be dependent on
-	var jobDependencies []string
-	if jobSpec.Task.Unit.DependencyMod != nil {
-		resp, err := jobSpec.Task.Unit.DependencyMod.GenerateDependencies(context.TODO(), models.GenerateDependenciesRequest{
-			Config:  models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
-			Assets:  models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
-			Project: projectSpec,
-		})
-		if err != nil {
-			return models.JobSpec{}, err
-		}
-		jobDependencies = resp.Dependencies
+	jobDependencies, err := r.generateDependencies(jobSpec, projectSpec)
+	if err != nil {
+		return models.JobSpec{}, err
 	}
 
 	// get job spec of these destinations and append to current jobSpec
@@ -74,12 +68,113 @@ func (r *dependencyResolver) resolveInferredDependencies(jobSpec models.JobSpec,
 		// determine the type of dependency
 		dep := models.JobSpecDependency{Job: &depSpec, Project: &depProj}
 		dep.Type = r.getJobSpecDependencyType(dep, projectSpec.Name)
+		// sensors and freshness of inferred dependencies are configured by
+		// listing them in spec
+		dep.Sensor = jobSpec.Dependencies[depSpec.Name].Sensor
+		dep.Freshness = jobSpec.Dependencies[depSpec.Name].Freshness
 		jobSpec.Dependencies[depSpec.Name] = dep
 	}
 
 	return jobSpec, nil
 }
 
+// generateDependencies returns the destinations the task of the job reads
+func (r *dependencyResolver) generateDependencies(jobSpec models.JobSpec, projectSpec models.ProjectSpec) ([]string, error) {
+	if jobSpec.Task.Unit.DependencyMod == nil {
+		return nil, nil
+	}
+	resp, err := jobSpec.Task.Unit.DependencyMod.GenerateDependencies(context.TODO(), models.GenerateDependenciesRequest{
+		Config:  models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+		Assets:  models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+		Project: projectSpec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return models.CanonicalURNs(resp.Dependencies), nil
+}
+
+// Explain lists dependencies of a job, declared ones first, along with why
+// each of them is resolved. Unlike Resolve, sources no job writes and
+// declared dependencies which don't exist are listed as unresolved instead
+// of being skipped or failing, as those are why an expected sensor is missing
+func (r *dependencyResolver) Explain(projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
+	jobSpec models.JobSpec) ([]models.JobSpecDependencyExplanation, error) {
+	var explanations []models.JobSpecDependencyExplanation
+
+	var declared []string
+	for depName := range jobSpec.Dependencies {
+		declared = append(declared, depName)
+	}
+	sort.Strings(declared)
+	for _, depName := range declared {
+		dep := jobSpec.Dependencies[depName]
+		explanation := models.JobSpecDependencyExplanation{
+			Job:     depName,
+			Project: projectSpec.Name,
+			Type:    models.JobSpecDependencyTypeIntra,
+			Reason:  models.JobSpecDependencyReasonDeclared,
+		}
+		if dep.Project != nil {
+			explanation.Project = dep.Project.Name
+			explanation.Type = r.getJobSpecDependencyType(dep, projectSpec.Name)
+		}
+		if dep.Job == nil {
+			if _, _, err := projectJobSpecRepo.GetByName(depName); err != nil {
+				if !errors.Is(err, store.ErrResourceNotFound) {
+					return nil, errors.Wrapf(err, "failed to find dependency %s", depName)
+				}
+				explanation.Reason = models.JobSpecDependencyReasonUnresolved
+			}
+		}
+		explanations = append(explanations, explanation)
+	}
+
+	destinations, err := r.generateDependencies(jobSpec, projectSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate dependencies")
+	}
+	for _, destination := range destinations {
+		explanation := models.JobSpecDependencyExplanation{
+			Reason:      models.JobSpecDependencyReasonUnresolved,
+			Destination: destination,
+			Assets:      assetsMentioning(jobSpec.Assets, destination),
+		}
+		depSpec, depProj, err := projectJobSpecRepo.GetByDestination(destination)
+		if err != nil {
+			if !errors.Is(err, store.ErrResourceNotFound) {
+				return nil, errors.Wrap(err, "runtime dependency evaluation failed")
+			}
+			explanations = append(explanations, explanation)
+			continue
+		}
+		explanation.Job = depSpec.Name
+		explanation.Project = depProj.Name
+		explanation.Type = r.getJobSpecDependencyType(models.JobSpecDependency{Project: &depProj}, projectSpec.Name)
+		explanation.Reason = models.JobSpecDependencyReasonInferred
+		explanations = append(explanations, explanation)
+	}
+	return explanations, nil
+}
+
+// assetsMentioning returns names of assets containing the destination,
+// written either as it is or the way queries refer tables, e.g.
+// bigquery://project:dataset.table as project.dataset.table
+func assetsMentioning(assets models.JobAssets, destination string) []string {
+	if idx := strings.Index(destination, "://"); idx >= 0 {
+		destination = destination[idx+len("://"):]
+	}
+	var names []string
+	for _, asset := range assets.GetAll() {
+		if strings.Contains(asset.Value, destination) ||
+			strings.Contains(asset.Value, strings.ReplaceAll(destination, ":", ".")) {
+			names = append(names, asset.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (r *dependencyResolver) getJobSpecDependencyType(dependency models.JobSpecDependency, currentJobSpecProject string) models.JobSpecDependencyType {
 	if dependency.Project.Name == currentJobSpecProject {
 		return models.JobSpecDependencyTypeIntra
@@ -10,6 +10,7 @@ import (
 	"github.com/odpf/optimus/job"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -128,6 +129,44 @@ func TestDependencyResolver(t *testing.T) {
 			assert.Equal(t, map[string]models.JobSpecDependency{}, resolvedJobSpec2.Dependencies)
 			assert.Equal(t, []*models.JobSpecHook{&resolvedJobSpec1.Hooks[0]}, resolvedJobSpec1.Hooks[1].DependsOn)
 		})
+		t.Run("it should keep sensor and freshness of a runtime dependency listed in spec", func(t *testing.T) {
+			execUnit1 := new(mock.DependencyResolverMod)
+			defer execUnit1.AssertExpectations(t)
+
+			sensor := models.JobSpecDependencySensor{Timeout: 6 * time.Hour, PokeInterval: 10 * time.Minute}
+			freshness := models.JobSpecDependencyFreshness{Enabled: true, Tolerance: time.Hour}
+			jobSpec1 := models.JobSpec{
+				Version: 1,
+				Name:    "test1",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{DependencyMod: execUnit1},
+				},
+				Dependencies: map[string]models.JobSpecDependency{
+					"test2": {Sensor: sensor, Freshness: freshness},
+				},
+			}
+			jobSpec2 := models.JobSpec{
+				Version: 1,
+				Name:    "test2",
+			}
+
+			jobSpecRepository := new(mock.ProjectJobSpecRepository)
+			jobSpecRepository.On("GetByDestination", "project.dataset.table2_destination").Return(jobSpec2, projectSpec, nil)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			execUnit1.On("GenerateDependencies", context.TODO(), models.GenerateDependenciesRequest{
+				Config: models.PluginConfigs{}.FromJobSpec(jobSpec1.Task.Config), Assets: models.PluginAssets{}.FromJobSpec(jobSpec1.Assets),
+				Project: projectSpec,
+			}).Return(&models.GenerateDependenciesResponse{Dependencies: []string{"project.dataset.table2_destination"}}, nil)
+
+			resolver := job.NewDependencyResolver()
+			resolvedJobSpec1, err := resolver.Resolve(projectSpec, jobSpecRepository, jobSpec1, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]models.JobSpecDependency{
+				jobSpec2.Name: {Job: &jobSpec2, Project: &projectSpec, Type: models.JobSpecDependencyTypeIntra,
+					Sensor: sensor, Freshness: freshness},
+			}, resolvedJobSpec1.Dependencies)
+		})
 		t.Run("it should resolve all dependencies including static unresolved dependency", func(t *testing.T) {
 			execUnit := new(mock.DependencyResolverMod)
 			defer execUnit.AssertExpectations(t)
@@ -637,4 +676,100 @@ func TestDependencyResolver(t *testing.T) {
 			assert.Equal(t, map[string]models.JobSpecDependency{}, resolvedJobSpec2.Dependencies)
 		})
 	})
+	t.Run("Explain", func(t *testing.T) {
+		projectSpec := models.ProjectSpec{
+			Name: "a-data-project",
+		}
+		externalProjectSpec := models.ProjectSpec{
+			Name: "an-external-data-project",
+		}
+
+		t.Run("it should explain declared, inferred and unresolved dependencies", func(t *testing.T) {
+			execUnit := new(mock.DependencyResolverMod)
+			defer execUnit.AssertExpectations(t)
+
+			jobSpec := models.JobSpec{
+				Version: 1,
+				Name:    "test1",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{DependencyMod: execUnit},
+				},
+				Assets: *models.JobAssets{}.New([]models.JobSpecAsset{
+					{Name: "query.sql", Value: "select * from `an-external-data-project.dataset.table2`"},
+					{Name: "other.sql", Value: "select 1"},
+				}),
+				Dependencies: map[string]models.JobSpecDependency{
+					"test3":   {Type: models.JobSpecDependencyTypeIntra},
+					"removed": {Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+			upstreamSpec := models.JobSpec{Name: "test2"}
+
+			jobSpecRepository := new(mock.ProjectJobSpecRepository)
+			jobSpecRepository.On("GetByName", "test3").Return(models.JobSpec{Name: "test3"}, models.NamespaceSpec{}, nil)
+			jobSpecRepository.On("GetByName", "removed").Return(models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound)
+			jobSpecRepository.On("GetByDestination", "bigquery://an-external-data-project:dataset.table2").Return(upstreamSpec, externalProjectSpec, nil)
+			jobSpecRepository.On("GetByDestination", "bigquery://external:dataset.table").Return(models.JobSpec{}, models.ProjectSpec{}, store.ErrResourceNotFound)
+			defer jobSpecRepository.AssertExpectations(t)
+
+			execUnit.On("GenerateDependencies", context.TODO(), models.GenerateDependenciesRequest{
+				Config:  models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+				Assets:  models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+				Project: projectSpec,
+			}).Return(&models.GenerateDependenciesResponse{
+				Dependencies: []string{
+					"bigquery://an-external-data-project:dataset.table2",
+					"bigquery://external:dataset.table",
+				},
+			}, nil)
+
+			explanations, err := job.NewDependencyResolver().Explain(projectSpec, jobSpecRepository, jobSpec)
+			assert.Nil(t, err)
+			assert.Equal(t, []models.JobSpecDependencyExplanation{
+				{
+					Job:     "removed",
+					Project: "a-data-project",
+					Type:    models.JobSpecDependencyTypeIntra,
+					Reason:  models.JobSpecDependencyReasonUnresolved,
+				},
+				{
+					Job:     "test3",
+					Project: "a-data-project",
+					Type:    models.JobSpecDependencyTypeIntra,
+					Reason:  models.JobSpecDependencyReasonDeclared,
+				},
+				{
+					Job:         "test2",
+					Project:     "an-external-data-project",
+					Type:        models.JobSpecDependencyTypeInter,
+					Reason:      models.JobSpecDependencyReasonInferred,
+					Destination: "bigquery://an-external-data-project:dataset.table2",
+					Assets:      []string{"query.sql"},
+				},
+				{
+					Reason:      models.JobSpecDependencyReasonUnresolved,
+					Destination: "bigquery://external:dataset.table",
+				},
+			}, explanations)
+		})
+		t.Run("should fail if GenerateDependencies fails", func(t *testing.T) {
+			execUnit := new(mock.DependencyResolverMod)
+			defer execUnit.AssertExpectations(t)
+
+			jobSpec := models.JobSpec{
+				Name: "test1",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{DependencyMod: execUnit},
+				},
+			}
+			execUnit.On("GenerateDependencies", context.TODO(), models.GenerateDependenciesRequest{
+				Config:  models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+				Assets:  models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+				Project: projectSpec,
+			}).Return(&models.GenerateDependenciesResponse{}, errors.New("random error"))
+
+			_, err := job.NewDependencyResolver().Explain(projectSpec, new(mock.ProjectJobSpecRepository), jobSpec)
+			assert.Equal(t, "failed to generate dependencies: random error", err.Error())
+		})
+	})
 }
@@ -0,0 +1,83 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrDestinationConflict signifies that more than one job of a project
+	// writes to the same destination, which makes its data nondeterministic
+	ErrDestinationConflict = errors.New("jobs write to the same destination")
+)
+
+// CheckDestinations makes sure jobs to be deployed in a namespace don't write
+// to the destination of another job of the project, among themselves or in
+// other namespaces. Jobs of the namespace not being deployed are left out as
+// they are deleted by the deployment, and so are canaries of jobs. Shared
+// destinations listed in project config are allowed to have many writers
+func (srv *Service) CheckDestinations(namespace models.NamespaceSpec, jobSpecs []models.JobSpec) error {
+	projSpec := namespace.ProjectSpec
+	writers := map[string][]string{}
+	for _, jobSpec := range jobSpecs {
+		destination, err := jobDestination(jobSpec)
+		if err != nil {
+			return err
+		}
+		if destination == "" || projSpec.IsSharedDestination(destination) {
+			continue
+		}
+		writers[destination] = append(writers[destination], jobSpec.Name)
+	}
+	if len(writers) == 0 {
+		return nil
+	}
+
+	destinations, err := srv.projectJobSpecRepoFactory.New(projSpec).GetDestinations()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve destinations of jobs")
+	}
+	for _, existing := range destinations {
+		if existing.NamespaceName == namespace.Name || strings.HasSuffix(existing.JobName, CanaryJobSuffix) {
+			continue
+		}
+		if names, ok := writers[existing.Destination]; ok {
+			writers[existing.Destination] = append(names, existing.JobName)
+		}
+	}
+
+	var conflicts []string
+	for destination, names := range writers {
+		if len(names) > 1 {
+			sort.Strings(names)
+			conflicts = append(conflicts, fmt.Sprintf("%s by %s", destination, strings.Join(names, ", ")))
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return errors.Wrapf(ErrDestinationConflict, "%s, add destinations written intentionally by many jobs to %s in project config",
+			strings.Join(conflicts, "; "), models.ProjectSharedDestinationsKey)
+	}
+	return nil
+}
+
+// jobDestination is the destination the task of a job writes to, empty for
+// tasks without one
+func jobDestination(jobSpec models.JobSpec) (string, error) {
+	if jobSpec.Task.Unit == nil || jobSpec.Task.Unit.DependencyMod == nil {
+		return "", nil
+	}
+	resp, err := jobSpec.Task.Unit.DependencyMod.GenerateDestination(context.TODO(), models.GenerateDestinationRequest{
+		Config: models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+		Assets: models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate destination for job %s", jobSpec.Name)
+	}
+	return models.CanonicalURN(resp.Destination), nil
+}
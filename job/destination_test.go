@@ -0,0 +1,83 @@
+package job_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+)
+
+func TestCheckDestinations(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+		Config: map[string]string{
+			models.ProjectSharedDestinationsKey: "proj.dataset.shared_table, proj.dataset.events",
+		},
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	jobWritingTo := func(name, destination string) models.JobSpec {
+		depMod := new(mock.DependencyResolverMod)
+		depMod.On("GenerateDestination", mock2.Anything, mock2.Anything).
+			Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+		return models.JobSpec{
+			Name: name,
+			Task: models.JobSpecTask{
+				Unit: &models.Plugin{DependencyMod: depMod},
+			},
+		}
+	}
+	existing := []models.JobDestination{
+		// replaced or deleted by the deployment
+		{JobName: "old-job", NamespaceName: namespaceSpec.Name, Destination: "proj.dataset.table_a"},
+		{JobName: "other-job", NamespaceName: "another-namespace", Destination: "proj.dataset.table_b"},
+		{JobName: "other-job_canary", NamespaceName: "another-namespace", Destination: "proj.dataset.table_c"},
+		{JobName: "shared-job", NamespaceName: "another-namespace", Destination: "proj.dataset.shared_table"},
+	}
+	setup := func() *mock.ProjectJobSpecRepoFactory {
+		projJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projJobSpecRepo.On("GetDestinations").Return(existing, nil)
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projectSpec).Return(projJobSpecRepo)
+		return projJobSpecRepoFac
+	}
+
+	t.Run("should allow jobs writing to their own destinations", func(t *testing.T) {
+		jobSvc := job.NewService(nil, nil, nil, nil, nil, nil, nil, setup(), nil, nil)
+		err := jobSvc.CheckDestinations(namespaceSpec, []models.JobSpec{
+			jobWritingTo("new-job", "proj.dataset.table_a"),
+			jobWritingTo("another-new-job", "proj.dataset.table_c"),
+			jobWritingTo("shared-job-2", "proj.dataset.shared_table"),
+		})
+		assert.Nil(t, err)
+	})
+	t.Run("should fail listing jobs writing to the same destination", func(t *testing.T) {
+		jobSvc := job.NewService(nil, nil, nil, nil, nil, nil, nil, setup(), nil, nil)
+		err := jobSvc.CheckDestinations(namespaceSpec, []models.JobSpec{
+			jobWritingTo("job-1", "proj.dataset.table_a"),
+			jobWritingTo("job-2", "proj.dataset.table_a"),
+			jobWritingTo("job-3", "proj.dataset.table_b"),
+		})
+		assert.True(t, errors.Is(err, job.ErrDestinationConflict))
+		assert.Contains(t, err.Error(), "proj.dataset.table_a by job-1, job-2; proj.dataset.table_b by job-3, other-job")
+	})
+	t.Run("should compare destinations as urns", func(t *testing.T) {
+		jobSvc := job.NewService(nil, nil, nil, nil, nil, nil, nil, setup(), nil, nil)
+		err := jobSvc.CheckDestinations(namespaceSpec, []models.JobSpec{
+			jobWritingTo("job-1", "proj:dataset.table_d"),
+			jobWritingTo("job-2", "bigquery://proj:dataset.table_d"),
+		})
+		assert.True(t, errors.Is(err, job.ErrDestinationConflict))
+		assert.Contains(t, err.Error(), "bigquery://proj:dataset.table_d by job-1, job-2")
+	})
+}
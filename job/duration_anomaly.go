@@ -0,0 +1,217 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// DurationAnomalyDefaultFactor is used when analyzer is created without
+	// a factor, runs taking over twice the p95 of recent runs are anomalies
+	DurationAnomalyDefaultFactor = 2.0
+
+	// latest runs of a job its duration baseline is computed from
+	durationBaselineSampleSize = 30
+
+	// runs needed before a job has a baseline worth comparing against
+	durationBaselineMinRuns = 5
+
+	// anomalies kept in memory for each project
+	durationAnomaliesPerProject = 100
+)
+
+// DurationAnalyzer periodically compares how long the latest runs of every
+// job took against the p95 duration of its earlier runs, runs exceeding it
+// by factor are kept as anomalies and notified to the channels of the job
+type DurationAnalyzer struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+	jobSvc               models.JobService
+	instSvc              models.InstanceService
+	eventSvc             EventRegistrar
+	interval             time.Duration
+	factor               float64
+
+	mu sync.Mutex
+	// project name -> anomalies, latest detected first
+	anomalies map[string][]models.DurationAnomaly
+	// job id -> scheduled time of the latest run analyzed
+	analyzed map[string]time.Time
+
+	Now func() time.Time
+}
+
+// Start runs analysis in background every interval until closed
+func (a *DurationAnalyzer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := a.Analyze(ctx); err != nil {
+					logger.E(errors.Wrap(err, "duration analysis failed"))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops analysis, waiting for the ongoing pass to finish
+func (a *DurationAnalyzer) Close() error {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.wg.Wait()
+	return nil
+}
+
+// Analyze checks runs of all jobs finished since the last pass, failing to
+// analyze a job does not stop analysis of the rest
+func (a *DurationAnalyzer) Analyze(ctx context.Context) error {
+	projects, err := a.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch projects")
+	}
+	for _, projSpec := range projects {
+		namespaces, err := a.namespaceRepoFactory.New(projSpec).GetAll()
+		if err != nil {
+			logger.E(errors.Wrapf(err, "failed to fetch namespaces of project %s", projSpec.Name))
+			continue
+		}
+		for _, namespace := range namespaces {
+			jobSpecs, err := a.jobSvc.GetAll(namespace)
+			if err != nil {
+				logger.E(errors.Wrapf(err, "failed to fetch jobs of namespace %s", namespace.Name))
+				continue
+			}
+			for _, jobSpec := range jobSpecs {
+				if err := a.analyzeJob(ctx, namespace, jobSpec); err != nil {
+					logger.E(errors.Wrapf(err, "failed to analyze duration of job %s", jobSpec.Name))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (a *DurationAnalyzer) analyzeJob(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec) error {
+	runs, err := a.instSvc.GetRunDurations(jobSpec, durationBaselineSampleSize+1)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].ScheduledAt.After(runs[j].ScheduledAt)
+	})
+
+	a.mu.Lock()
+	lastAnalyzed, seen := a.analyzed[jobSpec.ID.String()]
+	a.analyzed[jobSpec.ID.String()] = runs[0].ScheduledAt
+	a.mu.Unlock()
+
+	for idx, run := range runs {
+		if seen && !run.ScheduledAt.After(lastAnalyzed) {
+			break
+		}
+		if !seen && idx > 0 {
+			// only the latest run is checked for jobs not seen before
+			break
+		}
+		if len(runs[idx+1:]) < durationBaselineMinRuns {
+			break
+		}
+		baseline := models.RunDurationPercentile(runs[idx+1:], 0.95)
+		if float64(run.Duration) <= float64(baseline)*a.factor {
+			continue
+		}
+
+		anomaly := models.DurationAnomaly{
+			ProjectName:   namespace.ProjectSpec.Name,
+			NamespaceName: namespace.Name,
+			JobName:       jobSpec.Name,
+			ScheduledAt:   run.ScheduledAt,
+			Duration:      run.Duration,
+			Baseline:      baseline,
+			DetectedAt:    a.Now(),
+		}
+		a.record(anomaly)
+		logger.W(fmt.Sprintf("run of job %s scheduled at %s took %s, p95 of its recent runs is %s",
+			jobSpec.Name, run.ScheduledAt.Format(time.RFC3339), run.Duration, baseline))
+
+		if err := a.eventSvc.Register(ctx, namespace, jobSpec, models.JobEvent{
+			Type: models.JobEventTypeDurationAnomaly,
+			Value: map[string]*structpb.Value{
+				"scheduled_at": structpb.NewStringValue(run.ScheduledAt.Format(time.RFC3339)),
+				"duration":     structpb.NewStringValue(run.Duration.String()),
+				"baseline":     structpb.NewStringValue(baseline.String()),
+				"message": structpb.NewStringValue(fmt.Sprintf("run took %s, over %.1fx the p95 %s of its recent runs",
+					run.Duration, a.factor, baseline)),
+			},
+		}); err != nil {
+			return errors.Wrap(err, "failed to notify duration anomaly")
+		}
+	}
+	return nil
+}
+
+func (a *DurationAnalyzer) record(anomaly models.DurationAnomaly) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	anomalies := append([]models.DurationAnomaly{anomaly}, a.anomalies[anomaly.ProjectName]...)
+	if len(anomalies) > durationAnomaliesPerProject {
+		anomalies = anomalies[:durationAnomaliesPerProject]
+	}
+	a.anomalies[anomaly.ProjectName] = anomalies
+}
+
+// GetAnomalies returns anomalies detected in runs of jobs of a project since
+// the server started, latest detected first
+func (a *DurationAnalyzer) GetAnomalies(projectName string) []models.DurationAnomaly {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]models.DurationAnomaly{}, a.anomalies[projectName]...)
+}
+
+// NewDurationAnalyzer creates an analyzer checking runs of jobs every interval,
+// runs taking over factor times the p95 of recent runs are anomalies
+func NewDurationAnalyzer(projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	jobSvc models.JobService, instSvc models.InstanceService, eventSvc EventRegistrar, interval time.Duration,
+	factor float64) *DurationAnalyzer {
+	if factor <= 0 {
+		factor = DurationAnomalyDefaultFactor
+	}
+	return &DurationAnalyzer{
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+		jobSvc:               jobSvc,
+		instSvc:              instSvc,
+		eventSvc:             eventSvc,
+		interval:             interval,
+		factor:               factor,
+		anomalies:            map[string][]models.DurationAnomaly{},
+		analyzed:             map[string]time.Time{},
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package job_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+)
+
+func TestDurationAnalyzer(t *testing.T) {
+	logger.InitWithWriter("ERROR", ioutil.Discard)
+
+	ctx := context.Background()
+	now := time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-job",
+	}
+	latestRun := time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC)
+	// runs of last week took 10 to 20 minutes
+	runs := func(latestTook time.Duration) []models.RunDuration {
+		runs := []models.RunDuration{{ScheduledAt: latestRun, Duration: latestTook}}
+		for day := 1; day <= 6; day++ {
+			runs = append(runs, models.RunDuration{
+				ScheduledAt: latestRun.AddDate(0, 0, -day),
+				Duration:    time.Minute * time.Duration(8+day*2),
+			})
+		}
+		return runs
+	}
+
+	setup := func() (*mock.ProjectRepoFactory, *mock.NamespaceRepoFactory, *mock.JobService) {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projectSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		namespaceRepo := new(mock.NamespaceRepository)
+		namespaceRepo.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFac := new(mock.NamespaceRepoFactory)
+		namespaceRepoFac.On("New", projectSpec).Return(namespaceRepo)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{jobSpec}, nil)
+		return projectRepoFac, namespaceRepoFac, jobService
+	}
+
+	t.Run("should record and notify runs exceeding p95 of recent runs by factor once", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService := setup()
+		defer jobService.AssertExpectations(t)
+
+		instanceService := new(mock.InstanceService)
+		instanceService.On("GetRunDurations", jobSpec, 31).Return(runs(time.Minute*50), nil)
+		defer instanceService.AssertExpectations(t)
+
+		eventService := new(mock.EventService)
+		eventService.On("Register", ctx, namespaceSpec, jobSpec, mock2.MatchedBy(func(evt models.JobEvent) bool {
+			return evt.Type == models.JobEventTypeDurationAnomaly &&
+				evt.Value["scheduled_at"].GetStringValue() == "2021-05-20T02:00:00Z" &&
+				evt.Value["duration"].GetStringValue() == "50m0s" &&
+				evt.Value["baseline"].GetStringValue() == "20m0s"
+		})).Return(nil).Once()
+		defer eventService.AssertExpectations(t)
+
+		analyzer := job.NewDurationAnalyzer(projectRepoFac, namespaceRepoFac, jobService, instanceService,
+			eventService, time.Minute, 2)
+		analyzer.Now = func() time.Time { return now }
+		assert.Nil(t, analyzer.Analyze(ctx))
+		// the same run is not reported again
+		assert.Nil(t, analyzer.Analyze(ctx))
+
+		assert.Equal(t, []models.DurationAnomaly{
+			{
+				ProjectName:   projectSpec.Name,
+				NamespaceName: namespaceSpec.Name,
+				JobName:       jobSpec.Name,
+				ScheduledAt:   latestRun,
+				Duration:      time.Minute * 50,
+				Baseline:      time.Minute * 20,
+				DetectedAt:    now,
+			},
+		}, analyzer.GetAnomalies(projectSpec.Name))
+		assert.Empty(t, analyzer.GetAnomalies("another-project"))
+	})
+	t.Run("should not report runs within factor of p95 of recent runs", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService := setup()
+		defer jobService.AssertExpectations(t)
+
+		instanceService := new(mock.InstanceService)
+		instanceService.On("GetRunDurations", jobSpec, 31).Return(runs(time.Minute*35), nil)
+		defer instanceService.AssertExpectations(t)
+
+		analyzer := job.NewDurationAnalyzer(projectRepoFac, namespaceRepoFac, jobService, instanceService,
+			nil, time.Minute, 0)
+		assert.Nil(t, analyzer.Analyze(ctx))
+		assert.Empty(t, analyzer.GetAnomalies(projectSpec.Name))
+	})
+	t.Run("should not report runs of jobs without enough runs for baseline", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService := setup()
+		defer jobService.AssertExpectations(t)
+
+		instanceService := new(mock.InstanceService)
+		instanceService.On("GetRunDurations", jobSpec, 31).Return(runs(time.Hour * 5)[:3], nil)
+		defer instanceService.AssertExpectations(t)
+
+		analyzer := job.NewDurationAnalyzer(projectRepoFac, namespaceRepoFac, jobService, instanceService,
+			nil, time.Minute, 0)
+		assert.Nil(t, analyzer.Analyze(ctx))
+		assert.Empty(t, analyzer.GetAnomalies(projectSpec.Name))
+	})
+}
@@ -18,34 +18,92 @@ type eventService struct {
 	notifyChannels map[string]models.Notifier
 }
 
+const (
+	// ownershipNotifyScheme is used to alert job owners when job
+	// has no notifier configured for an event
+	ownershipNotifyScheme = "slack"
+
+	// ownershipEmailScheme is used to alert email of job owners instead
+	// of their slack user when an email notifier is configured
+	ownershipEmailScheme = "email"
+	ownershipEmailRoute  = "owner"
+)
+
 func (e *eventService) Register(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec,
 	evt models.JobEvent) error {
-	var err error
+	routeOn := evt.Type
+	if evt.Type == models.JobEventTypeAutoHeal || evt.Type == models.JobEventTypeHungRun {
+		// auto-heal follows a failure and hung runs are failed, they reach
+		// whoever is notified of failures
+		routeOn = models.JobEventTypeFailure
+	}
+	if evt.Type == models.JobEventTypeDurationAnomaly || evt.Type == models.JobEventTypeSensorTimeout {
+		// a slow run or a run stuck on its upstream risks missing sla, it
+		// reaches whoever is notified of sla misses
+		routeOn = models.JobEventTypeSLAMiss
+	}
+	var channels []string
+	if evt.Type == models.JobEventTypeReplay {
+		// replays span jobs of a project, they reach whoever watches replays of the project
+		channels = namespace.ProjectSpec.ReplayNotifyChannels()
+	}
+	if evt.Type == models.JobEventTypeCostBudget {
+		// budgets are of a project, no job is notified of them
+		channels = namespace.ProjectSpec.CostBudgetNotifyChannels()
+	}
 	for _, notify := range jobSpec.Behavior.Notify {
-		if notify.On == evt.Type {
-			for _, channel := range notify.Channels {
-				chanParts := strings.Split(channel, "://")
-				scheme := chanParts[0]
-				route := chanParts[1]
-
-				log.Df("notification event for job %s: %v", jobSpec.Name, evt)
-				if notifyChannel, ok := e.notifyChannels[scheme]; ok {
-					if currErr := notifyChannel.Notify(ctx, models.NotifyAttrs{
-						Namespace: namespace,
-						JobSpec:   jobSpec,
-						JobEvent:  evt,
-						Route:     route,
-					}); currErr != nil {
-						log.E(currErr)
-						err = multierror.Append(err, errors.Wrapf(currErr, "notifyChannel.Notify: %s", channel))
-					}
-				}
+		if notify.On == routeOn {
+			channels = append(channels, notify.Channels...)
+		}
+	}
+	if len(channels) == 0 && isAlertEvent(evt.Type) {
+		channels = e.ownershipChannels(jobSpec.Ownership)
+	}
+
+	var err error
+	for _, channel := range channels {
+		chanParts := strings.Split(channel, "://")
+		scheme := chanParts[0]
+		route := chanParts[1]
+
+		log.Df("notification event for job %s: %v", jobSpec.Name, evt)
+		if notifyChannel, ok := e.notifyChannels[scheme]; ok {
+			if currErr := notifyChannel.Notify(ctx, models.NotifyAttrs{
+				Namespace: namespace,
+				JobSpec:   jobSpec,
+				JobEvent:  evt,
+				Route:     route,
+			}); currErr != nil {
+				log.E(currErr)
+				err = multierror.Append(err, errors.Wrapf(currErr, "notifyChannel.Notify: %s", channel))
 			}
 		}
 	}
 	return err
 }
 
+func isAlertEvent(evtType models.JobEventType) bool {
+	return evtType == models.JobEventTypeFailure || evtType == models.JobEventTypeSLAMiss ||
+		evtType == models.JobEventTypeAutoHeal || evtType == models.JobEventTypeDurationAnomaly ||
+		evtType == models.JobEventTypeHungRun || evtType == models.JobEventTypeSensorTimeout
+}
+
+// ownershipChannels routes alerts to slack channel of the owners,
+// falling back to their email if channel is not known. Email is mailed
+// if an email notifier is configured, else owners are messaged on slack
+func (e *eventService) ownershipChannels(ownership models.JobSpecOwnership) []string {
+	if ownership.SlackChannel != "" {
+		return []string{ownershipNotifyScheme + "://#" + strings.TrimPrefix(ownership.SlackChannel, "#")}
+	}
+	if _, ok := e.notifyChannels[ownershipEmailScheme]; ok && ownership.Email != "" {
+		return []string{ownershipEmailScheme + "://" + ownershipEmailRoute}
+	}
+	if ownership.Email != "" {
+		return []string{ownershipNotifyScheme + "://" + ownership.Email}
+	}
+	return nil
+}
+
 func (e *eventService) Close() error {
 	var err error
 	for _, notify := range e.notifyChannels {
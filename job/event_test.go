@@ -152,4 +152,232 @@ func TestEventService(t *testing.T) {
 		err := evtService.Register(context.Background(), namespaceSpec, jobSpec, je)
 		assert.Error(t, err, "failed to notify")
 	})
+	t.Run("should notify job owners if no notifier is configured for the event", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "game_jam",
+			ProjectSpec: models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			},
+		}
+		jobSpec := models.JobSpec{
+			Name: "transform-tables",
+			Ownership: models.JobSpecOwnership{
+				Email:        "devs@example.io",
+				Team:         "data",
+				SlackChannel: "data-alerts",
+			},
+		}
+		je := models.JobEvent{
+			Type:  models.JobEventTypeSLAMiss,
+			Value: eventValues.GetFields(),
+		}
+
+		notifier := new(mock.Notifier)
+		notifier.On("Notify", context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobSpec:   jobSpec,
+			JobEvent:  je,
+			Route:     "#data-alerts",
+		}).Return(nil)
+		defer notifier.AssertExpectations(t)
+
+		evtService := job.NewEventService(map[string]models.Notifier{
+			"slack": notifier,
+		})
+		err := evtService.Register(context.Background(), namespaceSpec, jobSpec, je)
+		assert.Nil(t, err)
+	})
+	t.Run("should email job owners without a slack channel if email notifier is configured", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "game_jam",
+			ProjectSpec: models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			},
+		}
+		jobSpec := models.JobSpec{
+			Name: "transform-tables",
+			Ownership: models.JobSpecOwnership{
+				Email: "devs@example.io",
+				Team:  "data",
+			},
+		}
+		je := models.JobEvent{
+			Type:  models.JobEventTypeFailure,
+			Value: eventValues.GetFields(),
+		}
+
+		slackNotifier := new(mock.Notifier)
+		defer slackNotifier.AssertExpectations(t)
+		emailNotifier := new(mock.Notifier)
+		emailNotifier.On("Notify", context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobSpec:   jobSpec,
+			JobEvent:  je,
+			Route:     "owner",
+		}).Return(nil)
+		defer emailNotifier.AssertExpectations(t)
+
+		evtService := job.NewEventService(map[string]models.Notifier{
+			"slack": slackNotifier,
+			"email": emailNotifier,
+		})
+		err := evtService.Register(context.Background(), namespaceSpec, jobSpec, je)
+		assert.Nil(t, err)
+	})
+	t.Run("should notify failure channels of job when its runs are auto-healed", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "game_jam",
+			ProjectSpec: models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			},
+		}
+		jobSpec := models.JobSpec{
+			Name: "transform-tables",
+			Behavior: models.JobSpecBehavior{
+				Notify: []models.JobSpecNotifier{
+					{
+						On:       models.JobEventTypeFailure,
+						Channels: []string{"slack://#data-failures"},
+					},
+				},
+			},
+			Ownership: models.JobSpecOwnership{
+				SlackChannel: "data-alerts",
+			},
+		}
+		je := models.JobEvent{
+			Type:  models.JobEventTypeAutoHeal,
+			Value: eventValues.GetFields(),
+		}
+
+		notifier := new(mock.Notifier)
+		notifier.On("Notify", context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobSpec:   jobSpec,
+			JobEvent:  je,
+			Route:     "#data-failures",
+		}).Return(nil)
+		defer notifier.AssertExpectations(t)
+
+		evtService := job.NewEventService(map[string]models.Notifier{
+			"slack": notifier,
+		})
+		err := evtService.Register(context.Background(), namespaceSpec, jobSpec, je)
+		assert.Nil(t, err)
+	})
+	t.Run("should notify sla miss channels of job when its sensors time out", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "game_jam",
+			ProjectSpec: models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+			},
+		}
+		jobSpec := models.JobSpec{
+			Name: "transform-tables",
+			Behavior: models.JobSpecBehavior{
+				Notify: []models.JobSpecNotifier{
+					{
+						On:       models.JobEventTypeFailure,
+						Channels: []string{"slack://#data-failures"},
+					},
+					{
+						On:       models.JobEventTypeSLAMiss,
+						Channels: []string{"slack://#data-slas"},
+					},
+				},
+			},
+		}
+		je := models.JobEvent{
+			Type:  models.JobEventTypeSensorTimeout,
+			Value: eventValues.GetFields(),
+		}
+
+		notifier := new(mock.Notifier)
+		notifier.On("Notify", context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobSpec:   jobSpec,
+			JobEvent:  je,
+			Route:     "#data-slas",
+		}).Return(nil)
+		defer notifier.AssertExpectations(t)
+
+		evtService := job.NewEventService(map[string]models.Notifier{
+			"slack": notifier,
+		})
+		err := evtService.Register(context.Background(), namespaceSpec, jobSpec, je)
+		assert.Nil(t, err)
+	})
+	t.Run("should notify replay channels of project when replays of job change status", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			ProjectSpec: models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+				Config: map[string]string{
+					models.ProjectReplayNotifyKey: "slack://#data-replays",
+				},
+			},
+		}
+		jobSpec := models.JobSpec{
+			Name: "transform-tables",
+			Ownership: models.JobSpecOwnership{
+				SlackChannel: "data-alerts",
+			},
+		}
+		je := models.JobEvent{
+			Type:  models.JobEventTypeReplay,
+			Value: eventValues.GetFields(),
+		}
+
+		notifier := new(mock.Notifier)
+		notifier.On("Notify", context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobSpec:   jobSpec,
+			JobEvent:  je,
+			Route:     "#data-replays",
+		}).Return(nil)
+		defer notifier.AssertExpectations(t)
+
+		evtService := job.NewEventService(map[string]models.Notifier{
+			"slack": notifier,
+		})
+		err := evtService.Register(context.Background(), namespaceSpec, jobSpec, je)
+		assert.Nil(t, err)
+	})
+	t.Run("should notify cost budget channels of project when its spend crosses a threshold", func(t *testing.T) {
+		namespaceSpec := models.NamespaceSpec{
+			ProjectSpec: models.ProjectSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "a-data-project",
+				Config: map[string]string{
+					models.ProjectCostBudgetNotifyKey: "slack://#data-costs",
+				},
+			},
+		}
+		je := models.JobEvent{
+			Type:  models.JobEventTypeCostBudget,
+			Value: eventValues.GetFields(),
+		}
+
+		notifier := new(mock.Notifier)
+		notifier.On("Notify", context.Background(), models.NotifyAttrs{
+			Namespace: namespaceSpec,
+			JobEvent:  je,
+			Route:     "#data-costs",
+		}).Return(nil)
+		defer notifier.AssertExpectations(t)
+
+		evtService := job.NewEventService(map[string]models.Notifier{
+			"slack": notifier,
+		})
+		err := evtService.Register(context.Background(), namespaceSpec, models.JobSpec{}, je)
+		assert.Nil(t, err)
+	})
 }
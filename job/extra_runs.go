@@ -0,0 +1,133 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// extra runs older than this are not triggered anymore, they can still
+	// be run through replay
+	extraRunsLookback = time.Hour * 24
+)
+
+// ExtraRunTrigger periodically triggers extra runs listed in schedule
+// exceptions of jobs once they are due, cron of the scheduler can't
+// express runs on arbitrary dates
+type ExtraRunTrigger struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+	jobSvc               models.JobService
+	scheduler            models.SchedulerUnit
+	interval             time.Duration
+
+	Now func() time.Time
+}
+
+// Start triggers due extra runs in background every interval until closed
+func (t *ExtraRunTrigger) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := t.Trigger(ctx); err != nil {
+					logger.E(errors.Wrap(err, "extra run trigger failed"))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops triggering, waiting for the ongoing pass to finish
+func (t *ExtraRunTrigger) Close() error {
+	if t.cancel != nil {
+		t.cancel()
+	}
+	t.wg.Wait()
+	return nil
+}
+
+// Trigger runs due extra runs of all jobs which are not known to the
+// scheduler yet, failing to trigger a job does not stop the rest
+func (t *ExtraRunTrigger) Trigger(ctx context.Context) error {
+	projects, err := t.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch projects")
+	}
+	for _, projSpec := range projects {
+		namespaces, err := t.namespaceRepoFactory.New(projSpec).GetAll()
+		if err != nil {
+			logger.E(errors.Wrapf(err, "failed to fetch namespaces of project %s", projSpec.Name))
+			continue
+		}
+		for _, namespace := range namespaces {
+			jobSpecs, err := t.jobSvc.GetAll(namespace)
+			if err != nil {
+				logger.E(errors.Wrapf(err, "failed to fetch jobs of namespace %s", namespace.Name))
+				continue
+			}
+			for _, jobSpec := range jobSpecs {
+				if len(jobSpec.Schedule.Exceptions.ExtraRuns) == 0 {
+					continue
+				}
+				if err := t.triggerJob(ctx, projSpec, jobSpec); err != nil {
+					logger.E(errors.Wrapf(err, "failed to trigger extra runs of job %s", jobSpec.Name))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (t *ExtraRunTrigger) triggerJob(ctx context.Context, projSpec models.ProjectSpec, jobSpec models.JobSpec) error {
+	now := t.Now()
+	for _, run := range jobSpec.Schedule.Exceptions.ExtraRuns {
+		if run.After(now) || run.Before(now.Add(-extraRunsLookback)) {
+			continue
+		}
+		runs, err := t.scheduler.GetDagRunStatus(ctx, projSpec, jobSpec.Name, run, run, 1)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch runs")
+		}
+		if len(runs) > 0 {
+			continue
+		}
+		if err := t.scheduler.RunJob(ctx, projSpec, jobSpec.Name, run); err != nil {
+			return errors.Wrapf(err, "failed to run %s", run.Format(time.RFC3339))
+		}
+		logger.I(fmt.Sprintf("extra run of job %s scheduled at %s triggered", jobSpec.Name, run.Format(time.RFC3339)))
+	}
+	return nil
+}
+
+// NewExtraRunTrigger creates a trigger checking extra runs of jobs every interval
+func NewExtraRunTrigger(projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	jobSvc models.JobService, scheduler models.SchedulerUnit, interval time.Duration) *ExtraRunTrigger {
+	return &ExtraRunTrigger{
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+		jobSvc:               jobSvc,
+		scheduler:            scheduler,
+		interval:             interval,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
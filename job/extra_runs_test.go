@@ -0,0 +1,83 @@
+package job_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+)
+
+func TestExtraRunTrigger(t *testing.T) {
+	logger.InitWithWriter("ERROR", ioutil.Discard)
+
+	ctx := context.Background()
+	now := time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	dueRun := time.Date(2021, 5, 20, 6, 0, 0, 0, time.UTC)
+	triggeredRun := time.Date(2021, 5, 19, 18, 0, 0, 0, time.UTC)
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-job",
+		Schedule: models.JobSpecSchedule{
+			Interval: "@daily",
+			Exceptions: models.JobSpecScheduleExceptions{
+				ExtraRuns: []time.Time{
+					// too old to be triggered
+					time.Date(2021, 5, 1, 6, 0, 0, 0, time.UTC),
+					triggeredRun,
+					dueRun,
+					// not due yet
+					time.Date(2021, 5, 21, 6, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+	regularJobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-regular-job",
+	}
+
+	t.Run("should run due extra runs of jobs not known to scheduler", func(t *testing.T) {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projectSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		namespaceRepo := new(mock.NamespaceRepository)
+		namespaceRepo.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFac := new(mock.NamespaceRepoFactory)
+		namespaceRepoFac.On("New", projectSpec).Return(namespaceRepo)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{jobSpec, regularJobSpec}, nil)
+		defer jobService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		scheduler.On("GetDagRunStatus", ctx, projectSpec, jobSpec.Name, triggeredRun, triggeredRun, 1).
+			Return([]models.JobStatus{{ScheduledAt: triggeredRun, State: models.JobStatusStateSuccess}}, nil)
+		scheduler.On("GetDagRunStatus", ctx, projectSpec, jobSpec.Name, dueRun, dueRun, 1).
+			Return([]models.JobStatus{}, nil)
+		scheduler.On("RunJob", ctx, projectSpec, jobSpec.Name, dueRun).Return(nil).Once()
+		defer scheduler.AssertExpectations(t)
+
+		trigger := job.NewExtraRunTrigger(projectRepoFac, namespaceRepoFac, jobService, scheduler, time.Minute)
+		trigger.Now = func() time.Time { return now }
+		assert.Nil(t, trigger.Trigger(ctx))
+	})
+}
@@ -0,0 +1,94 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+// DependencyFreshnessChecker asserts destinations of dependencies of jobs
+// were modified after the window of a run started, as far as datastores
+// implementing models.DatastoreFreshnessReader can tell
+type DependencyFreshnessChecker struct {
+	projectRepoFactory        ProjectRepoFactory
+	namespaceRepoFactory      NamespaceRepoFactory
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory
+	datastoreRepo             models.DatastoreRepo
+}
+
+// Check asserts freshness of destination of a dependency of a job for its
+// run scheduled at a time, tolerance comes from the freshness configured for
+// the dependency in spec of the job. Dependencies are looked up in project
+// of the job unless dependencyProjectName names another project
+func (c *DependencyFreshnessChecker) Check(ctx context.Context, projSpec models.ProjectSpec, jobName,
+	dependencyProjectName, dependencyName string, scheduledAt time.Time) (models.DependencyFreshness, error) {
+	jobSpec, _, err := c.projectJobSpecRepoFactory.New(projSpec).GetByName(jobName)
+	if err != nil {
+		return models.DependencyFreshness{}, errors.Wrapf(err, "failed to find job %s", jobName)
+	}
+	freshness := models.DependencyFreshness{
+		WindowStart: jobSpec.Task.Window.GetStart(scheduledAt),
+	}
+
+	depProj := projSpec
+	if dependencyProjectName != "" && dependencyProjectName != projSpec.Name {
+		if depProj, err = c.projectRepoFactory.New().GetByName(dependencyProjectName); err != nil {
+			return freshness, errors.Wrapf(err, "failed to find project %s", dependencyProjectName)
+		}
+	}
+	destinations, err := c.projectJobSpecRepoFactory.New(depProj).GetDestinations()
+	if err != nil {
+		return freshness, errors.Wrapf(err, "failed to find destination of %s", dependencyName)
+	}
+	var destination models.JobDestination
+	for _, d := range destinations {
+		if d.JobName == dependencyName {
+			destination = d
+			break
+		}
+	}
+	if destination.Destination == "" {
+		return freshness, nil
+	}
+	freshness.Destination = destination.Destination
+	namespace, err := c.namespaceRepoFactory.New(depProj).GetByName(destination.NamespaceName)
+	if err != nil {
+		return freshness, errors.Wrapf(err, "failed to find namespace of %s", dependencyName)
+	}
+
+	for _, ds := range c.datastoreRepo.GetAll() {
+		reader, ok := ds.(models.DatastoreFreshnessReader)
+		if !ok {
+			continue
+		}
+		resp, err := reader.LastModifiedAt(ctx, models.ReadFreshnessRequest{
+			Destination: destination.Destination,
+			Project:     depProj,
+			Namespace:   namespace,
+		})
+		if err != nil {
+			return freshness, errors.Wrapf(err, "failed to read modification of %s in %s", destination.Destination, ds.Name())
+		}
+		if resp.Supported {
+			freshness.Supported = true
+			freshness.LastModifiedAt = resp.LastModifiedAt
+			freshness.Fresh = jobSpec.Dependencies[dependencyName].Freshness.IsFresh(resp.LastModifiedAt, freshness.WindowStart)
+			break
+		}
+	}
+	return freshness, nil
+}
+
+// NewDependencyFreshnessChecker creates a checker of freshness of
+// destinations of dependencies
+func NewDependencyFreshnessChecker(projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory, datastoreRepo models.DatastoreRepo) *DependencyFreshnessChecker {
+	return &DependencyFreshnessChecker{
+		projectRepoFactory:        projectRepoFactory,
+		namespaceRepoFactory:      namespaceRepoFactory,
+		projectJobSpecRepoFactory: projectJobSpecRepoFactory,
+		datastoreRepo:             datastoreRepo,
+	}
+}
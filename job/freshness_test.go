@@ -0,0 +1,114 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyFreshnessChecker(t *testing.T) {
+	ctx := context.Background()
+	scheduledAt := time.Date(2021, 7, 1, 2, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{Name: "a-data-project"}
+	upstreamProjectSpec := models.ProjectSpec{Name: "b-data-project"}
+	namespaceSpec := models.NamespaceSpec{Name: "game_jam"}
+	jobSpec := models.JobSpec{
+		Name: "revenue",
+		Task: models.JobSpecTask{
+			Window: models.JobSpecTaskWindow{Size: 24 * time.Hour, TruncateTo: "d"},
+		},
+		Dependencies: map[string]models.JobSpecDependency{
+			"sessions": {Freshness: models.JobSpecDependencyFreshness{Enabled: true, Tolerance: time.Hour}},
+		},
+	}
+	windowStart := jobSpec.Task.Window.GetStart(scheduledAt)
+	freshnessRequest := func(proj models.ProjectSpec) models.ReadFreshnessRequest {
+		return models.ReadFreshnessRequest{
+			Destination: "bigquery://proj:raw.sessions", Project: proj, Namespace: namespaceSpec,
+		}
+	}
+	newChecker := func(upstreamProj models.ProjectSpec, datastores []models.Datastorer) *job.DependencyFreshnessChecker {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetByName", upstreamProjectSpec.Name).Return(upstreamProjectSpec, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetByName", jobSpec.Name).Return(jobSpec, namespaceSpec, nil)
+		upstreamJobSpecRepo := projectJobSpecRepo
+		if upstreamProj.Name != projectSpec.Name {
+			upstreamJobSpecRepo = new(mock.ProjectJobSpecRepository)
+		}
+		upstreamJobSpecRepo.On("GetDestinations").Return([]models.JobDestination{
+			{JobName: "sessions", NamespaceName: namespaceSpec.Name, Destination: "bigquery://proj:raw.sessions"},
+		}, nil)
+		projectJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projectJobSpecRepoFac.On("New", projectSpec).Return(projectJobSpecRepo)
+		projectJobSpecRepoFac.On("New", upstreamProjectSpec).Return(upstreamJobSpecRepo)
+
+		namespaceRepo := new(mock.NamespaceRepository)
+		namespaceRepo.On("GetByName", namespaceSpec.Name).Return(namespaceSpec, nil)
+		namespaceRepoFac := new(mock.NamespaceRepoFactory)
+		namespaceRepoFac.On("New", upstreamProj).Return(namespaceRepo)
+
+		datastoreRepo := new(mock.SupportedDatastoreRepo)
+		datastoreRepo.On("GetAll").Return(datastores)
+		return job.NewDependencyFreshnessChecker(projectRepoFac, namespaceRepoFac, projectJobSpecRepoFac, datastoreRepo)
+	}
+
+	t.Run("should be fresh if destination was modified within tolerance of window start", func(t *testing.T) {
+		lastModifiedAt := windowStart.Add(-30 * time.Minute)
+		reader := new(mock.DatastoreFreshnessReader)
+		defer reader.AssertExpectations(t)
+		reader.On("LastModifiedAt", ctx, freshnessRequest(projectSpec)).Return(
+			models.ReadFreshnessResponse{Supported: true, LastModifiedAt: lastModifiedAt}, nil)
+
+		checker := newChecker(projectSpec, []models.Datastorer{new(mock.Datastorer), reader})
+		freshness, err := checker.Check(ctx, projectSpec, jobSpec.Name, "", "sessions", scheduledAt)
+		assert.Nil(t, err)
+		assert.Equal(t, models.DependencyFreshness{
+			Destination:    "bigquery://proj:raw.sessions",
+			WindowStart:    windowStart,
+			Supported:      true,
+			LastModifiedAt: lastModifiedAt,
+			Fresh:          true,
+		}, freshness)
+	})
+	t.Run("should be stale if destination of dependency in another project was modified before tolerance", func(t *testing.T) {
+		reader := new(mock.DatastoreFreshnessReader)
+		reader.On("LastModifiedAt", ctx, freshnessRequest(upstreamProjectSpec)).Return(
+			models.ReadFreshnessResponse{Supported: true, LastModifiedAt: windowStart.Add(-2 * time.Hour)}, nil)
+
+		checker := newChecker(upstreamProjectSpec, []models.Datastorer{reader})
+		freshness, err := checker.Check(ctx, projectSpec, jobSpec.Name, upstreamProjectSpec.Name, "sessions", scheduledAt)
+		assert.Nil(t, err)
+		assert.True(t, freshness.Supported)
+		assert.False(t, freshness.Fresh)
+	})
+	t.Run("should not assert destinations no datastore tracks", func(t *testing.T) {
+		reader := new(mock.DatastoreFreshnessReader)
+		reader.On("LastModifiedAt", ctx, freshnessRequest(projectSpec)).Return(models.ReadFreshnessResponse{}, nil)
+
+		checker := newChecker(projectSpec, []models.Datastorer{reader})
+		freshness, err := checker.Check(ctx, projectSpec, jobSpec.Name, projectSpec.Name, "sessions", scheduledAt)
+		assert.Nil(t, err)
+		assert.False(t, freshness.Supported)
+		assert.False(t, freshness.Fresh)
+	})
+	t.Run("should fail if datastore fails to read modification", func(t *testing.T) {
+		reader := new(mock.DatastoreFreshnessReader)
+		reader.On("Name").Return("bigquery")
+		reader.On("LastModifiedAt", ctx, freshnessRequest(projectSpec)).Return(
+			models.ReadFreshnessResponse{}, errors.New("access denied"))
+
+		checker := newChecker(projectSpec, []models.Datastorer{reader})
+		_, err := checker.Check(ctx, projectSpec, jobSpec.Name, "", "sessions", scheduledAt)
+		assert.NotNil(t, err)
+	})
+}
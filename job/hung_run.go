@@ -0,0 +1,202 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// HungRunDefaultSilence is used when monitor is created without a
+	// silence period, runs which missed the heartbeats of 3 minutes are hung
+	HungRunDefaultSilence = models.InstanceHeartbeatInterval * models.InstanceHeartbeatMissLimit
+
+	// runs scheduled within this window are looked at, older ones would
+	// have been caught by earlier passes unless the server was down
+	hungRunsWindow = time.Hour * 24 * 3
+)
+
+// HungRunMonitor periodically looks for runs of jobs which stopped reporting
+// heartbeats while running, e.g. as their worker died, marks them failed and
+// notifies the channels of the job. With retry, hung runs are cleared in the
+// scheduler to run again, once for each run. Only runs wrapped by optimus exec
+// report heartbeats, others are never considered hung
+type HungRunMonitor struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	projectRepoFactory   ProjectRepoFactory
+	namespaceRepoFactory NamespaceRepoFactory
+	jobSvc               models.JobService
+	instSvc              models.InstanceService
+	scheduler            models.SchedulerUnit
+	eventSvc             EventRegistrar
+	interval             time.Duration
+	silence              time.Duration
+	retry                bool
+
+	mu sync.Mutex
+	// job id and scheduled time of runs retried -> scheduled time of the run
+	retried map[string]time.Time
+
+	Now func() time.Time
+}
+
+// Start runs the monitor in background every interval until closed
+func (m *HungRunMonitor) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := m.Check(ctx); err != nil {
+					logger.E(errors.Wrap(err, "hung run check failed"))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the monitor, waiting for the ongoing pass to finish
+func (m *HungRunMonitor) Close() error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+	return nil
+}
+
+// Check looks for hung runs of all jobs, failing to check a job does not
+// stop checks of the rest
+func (m *HungRunMonitor) Check(ctx context.Context) error {
+	projects, err := m.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch projects")
+	}
+	since := m.Now().Add(-hungRunsWindow)
+	m.forgetRetriedBefore(since)
+	for _, projSpec := range projects {
+		namespaces, err := m.namespaceRepoFactory.New(projSpec).GetAll()
+		if err != nil {
+			logger.E(errors.Wrapf(err, "failed to fetch namespaces of project %s", projSpec.Name))
+			continue
+		}
+		for _, namespace := range namespaces {
+			jobSpecs, err := m.jobSvc.GetAll(namespace)
+			if err != nil {
+				logger.E(errors.Wrapf(err, "failed to fetch jobs of namespace %s", namespace.Name))
+				continue
+			}
+			for _, jobSpec := range jobSpecs {
+				if err := m.checkJob(ctx, namespace, jobSpec, since); err != nil {
+					logger.E(errors.Wrapf(err, "failed to check hung runs of job %s", jobSpec.Name))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (m *HungRunMonitor) checkJob(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec,
+	since time.Time) error {
+	runs, err := m.instSvc.GetHungRuns(jobSpec, since, m.silence)
+	if err != nil {
+		return err
+	}
+	for _, run := range runs {
+		if err := m.instSvc.UpdateState(jobSpec, run.ScheduledAt, models.InstanceStateFailed); err != nil {
+			return err
+		}
+		silentFor := m.Now().Sub(run.HeartbeatAt).Round(time.Second)
+		logger.W(fmt.Sprintf("run of job %s scheduled at %s reported no heartbeat for %s, marked failed",
+			jobSpec.Name, run.ScheduledAt.Format(time.RFC3339), silentFor))
+
+		retried := false
+		if m.retry && m.markRetried(jobSpec, run.ScheduledAt) {
+			if err := m.scheduler.Clear(ctx, namespace.ProjectSpec, jobSpec.Name, run.ScheduledAt,
+				run.ScheduledAt); err != nil {
+				logger.E(errors.Wrapf(err, "failed to clear hung run of job %s scheduled at %s", jobSpec.Name,
+					run.ScheduledAt.Format(time.RFC3339)))
+			} else {
+				retried = true
+			}
+		}
+
+		message := fmt.Sprintf("run reported no heartbeat for %s, marked failed", silentFor)
+		if retried {
+			message += " and cleared to run again"
+		}
+		if err := m.eventSvc.Register(ctx, namespace, jobSpec, models.JobEvent{
+			Type: models.JobEventTypeHungRun,
+			Value: map[string]*structpb.Value{
+				"scheduled_at": structpb.NewStringValue(run.ScheduledAt.Format(time.RFC3339)),
+				"heartbeat_at": structpb.NewStringValue(run.HeartbeatAt.Format(time.RFC3339)),
+				"retried":      structpb.NewBoolValue(retried),
+				"message":      structpb.NewStringValue(message),
+			},
+		}); err != nil {
+			return errors.Wrap(err, "failed to notify hung run")
+		}
+	}
+	return nil
+}
+
+// markRetried tells if the run can be retried, marking it retried
+func (m *HungRunMonitor) markRetried(jobSpec models.JobSpec, scheduledAt time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := jobSpec.ID.String() + "/" + scheduledAt.UTC().Format(time.RFC3339)
+	if _, ok := m.retried[key]; ok {
+		return false
+	}
+	m.retried[key] = scheduledAt
+	return true
+}
+
+func (m *HungRunMonitor) forgetRetriedBefore(since time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, scheduledAt := range m.retried {
+		if scheduledAt.Before(since) {
+			delete(m.retried, key)
+		}
+	}
+}
+
+// NewHungRunMonitor creates a monitor looking for hung runs of jobs every
+// interval, runs which reported no heartbeat for longer than silence are hung
+func NewHungRunMonitor(projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	jobSvc models.JobService, instSvc models.InstanceService, scheduler models.SchedulerUnit,
+	eventSvc EventRegistrar, interval, silence time.Duration, retry bool) *HungRunMonitor {
+	if silence <= 0 {
+		silence = HungRunDefaultSilence
+	}
+	return &HungRunMonitor{
+		projectRepoFactory:   projectRepoFactory,
+		namespaceRepoFactory: namespaceRepoFactory,
+		jobSvc:               jobSvc,
+		instSvc:              instSvc,
+		scheduler:            scheduler,
+		eventSvc:             eventSvc,
+		interval:             interval,
+		silence:              silence,
+		retry:                retry,
+		retried:              map[string]time.Time{},
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package job_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+)
+
+func TestHungRunMonitor(t *testing.T) {
+	logger.InitWithWriter("ERROR", ioutil.Discard)
+
+	ctx := context.Background()
+	now := time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour * 24 * 3)
+	silence := time.Minute * 5
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-job",
+	}
+	hungRun := models.InstanceSpec{
+		ScheduledAt: time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC),
+		State:       models.InstanceStateRunning,
+		HeartbeatAt: now.Add(-time.Minute * 20),
+	}
+
+	setup := func() (*mock.ProjectRepoFactory, *mock.NamespaceRepoFactory, *mock.JobService) {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projectSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		namespaceRepo := new(mock.NamespaceRepository)
+		namespaceRepo.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFac := new(mock.NamespaceRepoFactory)
+		namespaceRepoFac.On("New", projectSpec).Return(namespaceRepo)
+
+		jobService := new(mock.JobService)
+		jobService.On("GetAll", namespaceSpec).Return([]models.JobSpec{jobSpec}, nil)
+		return projectRepoFac, namespaceRepoFac, jobService
+	}
+	hungRunEvent := func(retried bool) interface{} {
+		return mock2.MatchedBy(func(evt models.JobEvent) bool {
+			return evt.Type == models.JobEventTypeHungRun &&
+				evt.Value["scheduled_at"].GetStringValue() == "2021-05-20T02:00:00Z" &&
+				evt.Value["heartbeat_at"].GetStringValue() == "2021-05-20T09:40:00Z" &&
+				evt.Value["retried"].GetBoolValue() == retried
+		})
+	}
+
+	t.Run("should mark hung runs failed and notify them", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService := setup()
+		defer jobService.AssertExpectations(t)
+
+		instanceService := new(mock.InstanceService)
+		instanceService.On("GetHungRuns", jobSpec, since, silence).Return([]models.InstanceSpec{hungRun}, nil)
+		instanceService.On("UpdateState", jobSpec, hungRun.ScheduledAt, models.InstanceStateFailed).Return(nil)
+		defer instanceService.AssertExpectations(t)
+
+		eventService := new(mock.EventService)
+		eventService.On("Register", ctx, namespaceSpec, jobSpec, hungRunEvent(false)).Return(nil)
+		defer eventService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		defer scheduler.AssertExpectations(t)
+
+		monitor := job.NewHungRunMonitor(projectRepoFac, namespaceRepoFac, jobService, instanceService,
+			scheduler, eventService, time.Minute, silence, false)
+		monitor.Now = func() time.Time { return now }
+		assert.Nil(t, monitor.Check(ctx))
+	})
+	t.Run("should clear hung runs in scheduler to run again only once", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService := setup()
+		defer jobService.AssertExpectations(t)
+
+		instanceService := new(mock.InstanceService)
+		instanceService.On("GetHungRuns", jobSpec, since, silence).Return([]models.InstanceSpec{hungRun}, nil)
+		instanceService.On("UpdateState", jobSpec, hungRun.ScheduledAt, models.InstanceStateFailed).Return(nil)
+		defer instanceService.AssertExpectations(t)
+
+		eventService := new(mock.EventService)
+		eventService.On("Register", ctx, namespaceSpec, jobSpec, hungRunEvent(true)).Return(nil).Once()
+		eventService.On("Register", ctx, namespaceSpec, jobSpec, hungRunEvent(false)).Return(nil).Once()
+		defer eventService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		scheduler.On("Clear", ctx, projectSpec, jobSpec.Name, hungRun.ScheduledAt, hungRun.ScheduledAt).
+			Return(nil).Once()
+		defer scheduler.AssertExpectations(t)
+
+		monitor := job.NewHungRunMonitor(projectRepoFac, namespaceRepoFac, jobService, instanceService,
+			scheduler, eventService, time.Minute, silence, true)
+		monitor.Now = func() time.Time { return now }
+		assert.Nil(t, monitor.Check(ctx))
+		// the retried run hung again
+		assert.Nil(t, monitor.Check(ctx))
+	})
+	t.Run("should notify hung run as not retried if clearing it fails", func(t *testing.T) {
+		projectRepoFac, namespaceRepoFac, jobService := setup()
+
+		instanceService := new(mock.InstanceService)
+		instanceService.On("GetHungRuns", jobSpec, since, silence).Return([]models.InstanceSpec{hungRun}, nil)
+		instanceService.On("UpdateState", jobSpec, hungRun.ScheduledAt, models.InstanceStateFailed).Return(nil)
+		defer instanceService.AssertExpectations(t)
+
+		eventService := new(mock.EventService)
+		eventService.On("Register", ctx, namespaceSpec, jobSpec, hungRunEvent(false)).Return(nil)
+		defer eventService.AssertExpectations(t)
+
+		scheduler := new(mock.Scheduler)
+		scheduler.On("Clear", ctx, projectSpec, jobSpec.Name, hungRun.ScheduledAt, hungRun.ScheduledAt).
+			Return(errors.New("airflow is down"))
+		defer scheduler.AssertExpectations(t)
+
+		monitor := job.NewHungRunMonitor(projectRepoFac, namespaceRepoFac, jobService, instanceService,
+			scheduler, eventService, time.Minute, silence, true)
+		monitor.Now = func() time.Time { return now }
+		assert.Nil(t, monitor.Check(ctx))
+	})
+}
@@ -8,4 +8,8 @@ type SpecRepository interface {
 	GetByName(string) (models.JobSpec, error)
 	GetAll() ([]models.JobSpec, error)
 	Delete(string) error
+	// Rename renames a job of the namespace keeping its identity and saves
+	// dependents with their dependencies rewritten to the new name, all of
+	// them or none
+	Rename(oldName, newName string, dependents []models.JobSpec) error
 }
@@ -0,0 +1,196 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// JobOperationDeploy changes spec of a job or deletes it
+	JobOperationDeploy = "deploy"
+	// JobOperationReplay reruns past runs of a job
+	JobOperationReplay = "replay"
+
+	// JobLockTTL is how long locks of jobs last without being extended
+	JobLockTTL = 2 * time.Minute
+)
+
+var (
+	// ErrJobLocked signifies the job is locked by another operation, errors
+	// carrying it are of type *JobLockedError
+	ErrJobLocked = errors.New("job is locked")
+)
+
+// JobLockedError tells which operation holds lock of a job
+type JobLockedError struct {
+	Project   string
+	Job       string
+	Operation string
+	// ID of the operation holding the lock if it has one, e.g. replay id
+	ID string
+}
+
+func (e *JobLockedError) Error() string {
+	if e.ID != "" {
+		return fmt.Sprintf("job %s of project %s is locked by %s %s in progress, try again once it's done",
+			e.Job, e.Project, e.Operation, e.ID)
+	}
+	return fmt.Sprintf("job %s of project %s is locked by %s in progress, try again once it's done",
+		e.Job, e.Project, e.Operation)
+}
+
+func (e *JobLockedError) Unwrap() error {
+	return ErrJobLocked
+}
+
+// JobLocker holds advisory locks of jobs so deploys changing a job and
+// replays of it don't run at the same time. Operations of the same kind
+// share the lock, conflicts among them are handled by the operations
+// themselves. Locks are kept in the database so all servers sharing it see
+// them, locks held by the server are extended in background until released
+// and expire if the server goes away
+type JobLocker struct {
+	mu   sync.Mutex
+	held map[uuid.UUID]bool
+
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	repo store.JobLockRepository
+	ttl  time.Duration
+}
+
+// Lock locks jobs of project for operation, either all of them or none if
+// any is held by another operation. The returned func releases the locks.
+// A nil locker never locks
+func (l *JobLocker) Lock(projectName string, jobNames []string, operation, id string) (func(), error) {
+	if l == nil || len(jobNames) == 0 {
+		return func() {}, nil
+	}
+
+	lock := models.JobLock{
+		Token:     uuid.New(),
+		Project:   projectName,
+		Operation: operation,
+		HolderID:  id,
+	}
+	conflict, err := l.repo.Acquire(lock, uniqueJobNames(jobNames), l.ttl)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock jobs")
+	}
+	if conflict != nil {
+		return nil, &JobLockedError{
+			Project:   projectName,
+			Job:       conflict.Job,
+			Operation: conflict.Operation,
+			ID:        conflict.HolderID,
+		}
+	}
+
+	l.mu.Lock()
+	l.held[lock.Token] = true
+	l.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if err := l.release(lock.Token); err != nil {
+				logger.E(errors.Wrapf(err, "failed to release lock of jobs of %s", projectName))
+			}
+		})
+	}, nil
+}
+
+// release releases lock acquired under token unless it's already released
+// by closing the locker
+func (l *JobLocker) release(token uuid.UUID) error {
+	l.mu.Lock()
+	held := l.held[token]
+	delete(l.held, token)
+	l.mu.Unlock()
+	if !held {
+		return nil
+	}
+	return l.repo.Release(token)
+}
+
+// Start extends locks held by the server in background until closed, well
+// before they expire
+func (l *JobLocker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		ticker := time.NewTicker(l.ttl / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := l.repo.Extend(l.heldTokens(), l.ttl); err != nil {
+					logger.E(errors.Wrap(err, "failed to extend locks of jobs"))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops extending locks and releases the ones still held
+func (l *JobLocker) Close() error {
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.wg.Wait()
+
+	var errs error
+	for _, token := range l.heldTokens() {
+		if err := l.release(token); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (l *JobLocker) heldTokens() []uuid.UUID {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tokens := make([]uuid.UUID, 0, len(l.held))
+	for token := range l.held {
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// uniqueJobNames drops repeated names keeping the order of the first ones
+func uniqueJobNames(jobNames []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, jobName := range jobNames {
+		if seen[jobName] {
+			continue
+		}
+		seen[jobName] = true
+		unique = append(unique, jobName)
+	}
+	return unique
+}
+
+// NewJobLocker creates a locker keeping locks in repo without any lock held
+func NewJobLocker(repo store.JobLockRepository) *JobLocker {
+	return &JobLocker{
+		held: map[uuid.UUID]bool{},
+		repo: repo,
+		ttl:  JobLockTTL,
+	}
+}
@@ -0,0 +1,83 @@
+package job_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestJobLocker(t *testing.T) {
+	matchLock := func(operation, id string) interface{} {
+		return mock2.MatchedBy(func(lock models.JobLock) bool {
+			return lock.Token != uuid.Nil && lock.Project == "proj" && lock.Operation == operation && lock.HolderID == id
+		})
+	}
+	t.Run("should lock each job once and release the lock once", func(t *testing.T) {
+		var token uuid.UUID
+		repo := new(mock.JobLockRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("Acquire", matchLock(job.JobOperationReplay, "replay-1"), []string{"job-a", "job-b"}, job.JobLockTTL).
+			Run(func(args mock2.Arguments) {
+				token = args.Get(0).(models.JobLock).Token
+			}).Return((*models.JobLock)(nil), nil).Once()
+		repo.On("Release", mock2.AnythingOfType("uuid.UUID")).Return(nil).Once()
+
+		locker := job.NewJobLocker(repo)
+		unlock, err := locker.Lock("proj", []string{"job-a", "job-b", "job-a"}, job.JobOperationReplay, "replay-1")
+		assert.Nil(t, err)
+		unlock()
+		unlock()
+		repo.AssertCalled(t, "Release", token)
+		assert.Nil(t, locker.Close())
+	})
+	t.Run("should fail with the operation holding the lock", func(t *testing.T) {
+		repo := new(mock.JobLockRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("Acquire", matchLock(job.JobOperationDeploy, ""), []string{"job-a", "job-b"}, job.JobLockTTL).
+			Return(&models.JobLock{
+				Token:     uuid.New(),
+				Project:   "proj",
+				Job:       "job-b",
+				Operation: job.JobOperationReplay,
+				HolderID:  "replay-1",
+			}, nil)
+
+		locker := job.NewJobLocker(repo)
+		_, err := locker.Lock("proj", []string{"job-a", "job-b"}, job.JobOperationDeploy, "")
+		assert.True(t, errors.Is(err, job.ErrJobLocked))
+		var lockedErr *job.JobLockedError
+		assert.True(t, errors.As(err, &lockedErr))
+		assert.Equal(t, "replay-1", lockedErr.ID)
+		assert.Equal(t, "job job-b of project proj is locked by replay replay-1 in progress, try again once it's done", err.Error())
+	})
+	t.Run("should release locks still held on close", func(t *testing.T) {
+		var token uuid.UUID
+		repo := new(mock.JobLockRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("Acquire", matchLock(job.JobOperationDeploy, ""), []string{"job-a"}, job.JobLockTTL).
+			Run(func(args mock2.Arguments) {
+				token = args.Get(0).(models.JobLock).Token
+			}).Return((*models.JobLock)(nil), nil)
+		repo.On("Release", mock2.AnythingOfType("uuid.UUID")).Return(nil).Once()
+
+		locker := job.NewJobLocker(repo)
+		locker.Start()
+		unlock, err := locker.Lock("proj", []string{"job-a"}, job.JobOperationDeploy, "")
+		assert.Nil(t, err)
+		assert.Nil(t, locker.Close())
+		repo.AssertCalled(t, "Release", token)
+		unlock()
+	})
+	t.Run("should never lock with a nil locker", func(t *testing.T) {
+		var locker *job.JobLocker
+		unlock, err := locker.Lock("proj", []string{"job-a"}, job.JobOperationDeploy, "")
+		assert.Nil(t, err)
+		unlock()
+	})
+}
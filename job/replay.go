@@ -3,8 +3,10 @@ package job
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/odpf/optimus/core/cron"
 	"github.com/odpf/optimus/core/tree"
 	"github.com/odpf/optimus/models"
@@ -16,6 +18,12 @@ const (
 	ReplayDateFormat = "2006-01-02"
 )
 
+var (
+	// ErrReplayWindowMisaligned signifies that a replay boundary or the whole
+	// window doesn't line up with runs of the job
+	ErrReplayWindowMisaligned = errors.New("replay window is not aligned with schedule of job")
+)
+
 func (srv *Service) populateRequestWithJobSpecs(replayRequest *models.ReplayWorkerRequest) error {
 	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(replayRequest.Project)
 	jobSpecs, err := srv.GetDependencyResolvedSpecs(replayRequest.Project, projectJobSpecRepo, nil)
@@ -43,9 +51,13 @@ func (srv *Service) ReplayDryRun(replayRequest *models.ReplayWorkerRequest) (*tr
 	return rootInstance, nil
 }
 
+// Replay accepts a replay of the request, job specs already resolved by a
+// dry run of the same request are reused
 func (srv *Service) Replay(ctx context.Context, replayRequest *models.ReplayWorkerRequest) (string, error) {
-	if err := srv.populateRequestWithJobSpecs(replayRequest); err != nil {
-		return "", err
+	if replayRequest.JobSpecMap == nil {
+		if err := srv.populateRequestWithJobSpecs(replayRequest); err != nil {
+			return "", err
+		}
 	}
 
 	replayUUID, err := srv.replayManager.Replay(ctx, replayRequest)
@@ -55,22 +67,146 @@ func (srv *Service) Replay(ctx context.Context, replayRequest *models.ReplayWork
 	return replayUUID, nil
 }
 
+// ReplayPlanDryRun returns execution trees of replays of a plan replaying
+// jobs matching selector of the request, in the order they are replayed
+func (srv *Service) ReplayPlanDryRun(planRequest models.ReplayPlanRequest) ([]*tree.TreeNode, error) {
+	plan, err := srv.prepareReplayPlan(planRequest)
+	if err != nil {
+		return nil, err
+	}
+	var nodes []*tree.TreeNode
+	for _, replayRequest := range plan.Replays {
+		runs, err := replayRuns(replayRequest)
+		if err != nil {
+			return nil, err
+		}
+		node := tree.NewTreeNode(replayRequest.Job)
+		for _, run := range runs {
+			node.Runs.Add(run)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// ReplayPlan replays jobs matching selector of the request one after
+// another in order of their dependencies, tracked under id of the plan
+func (srv *Service) ReplayPlan(ctx context.Context, planRequest models.ReplayPlanRequest) (*models.ReplayPlan, error) {
+	plan, err := srv.prepareReplayPlan(planRequest)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := srv.replayManager.ReplayPlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// prepareReplayPlan makes a replay of each job matching selector of the
+// request, upstream jobs first. Jobs without runs in the window are left out
+func (srv *Service) prepareReplayPlan(planRequest models.ReplayPlanRequest) (*models.ReplayPlan, error) {
+	if planRequest.Selector.Empty() {
+		return nil, errors.New("label selector of replay plan is required")
+	}
+	// jobs of a plan have different schedules, only days line up with all
+	for _, boundary := range []time.Time{planRequest.Start, planRequest.End} {
+		if !isReplayDate(boundary) {
+			return nil, errors.Wrapf(ErrReplayWindowMisaligned, "replay plan boundary %s is not a day", boundary.Format(time.RFC3339))
+		}
+	}
+	baseRequest := &models.ReplayWorkerRequest{Project: planRequest.Project}
+	if err := srv.populateRequestWithJobSpecs(baseRequest); err != nil {
+		return nil, err
+	}
+	orderedJobs, err := orderByDependencies(baseRequest.JobSpecMap)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.ReplayPlan{Project: planRequest.Project}
+	for _, jobSpec := range orderedJobs {
+		if jobSpec.Schedule.Interval == "" || !planRequest.Selector.Matches(jobSpec.Labels) {
+			continue
+		}
+		replayRequest := &models.ReplayWorkerRequest{
+			Job:            jobSpec,
+			Start:          planRequest.Start,
+			End:            planRequest.End,
+			Project:        planRequest.Project,
+			JobSpecMap:     baseRequest.JobSpecMap,
+			Force:          planRequest.Force,
+			IgnoreUpstream: planRequest.IgnoreUpstream,
+		}
+		if _, err := replayRuns(replayRequest); err != nil {
+			if errors.Is(err, ErrReplayWindowMisaligned) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to prepare replay of %s", jobSpec.Name)
+		}
+		plan.Replays = append(plan.Replays, replayRequest)
+	}
+	if len(plan.Replays) == 0 {
+		return nil, errors.Wrapf(ErrReplayWindowMisaligned, "no jobs matching %s have runs scheduled between %s and %s",
+			planRequest.Selector.String(), planRequest.Start.Format(ReplayDateFormat), planRequest.End.Format(ReplayDateFormat))
+	}
+	return plan, nil
+}
+
+// orderByDependencies orders jobs so that each comes after the jobs of the
+// project it depends on, jobs without any order between them are by name
+func orderByDependencies(jobSpecMap map[string]models.JobSpec) ([]models.JobSpec, error) {
+	upstreams := map[string]int{}
+	dependents := map[string][]string{}
+	for name, jobSpec := range jobSpecMap {
+		upstreams[name] = 0
+		for _, dependency := range jobSpec.Dependencies {
+			if dependency.Job == nil {
+				continue
+			}
+			if _, ok := jobSpecMap[dependency.Job.Name]; !ok || dependency.Job.Name == name {
+				continue
+			}
+			upstreams[name]++
+			dependents[dependency.Job.Name] = append(dependents[dependency.Job.Name], name)
+		}
+	}
+
+	var ready []string
+	for name, count := range upstreams {
+		if count == 0 {
+			ready = append(ready, name)
+		}
+	}
+	var ordered []models.JobSpec
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, jobSpecMap[name])
+		for _, dependent := range dependents[name] {
+			upstreams[dependent]--
+			if upstreams[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(ordered) != len(jobSpecMap) {
+		return nil, errors.New("dependencies of jobs are cyclic")
+	}
+	return ordered, nil
+}
+
 // prepareTree creates a execution tree for replay operation
 func prepareTree(replayRequest *models.ReplayWorkerRequest) (*tree.TreeNode, error) {
-	replayJobSpec, found := replayRequest.JobSpecMap[replayRequest.Job.Name]
-	if !found {
-		return nil, fmt.Errorf("couldn't find any job with name %s", replayRequest.Job.Name)
+	runs, err := replayRuns(replayRequest)
+	if err != nil {
+		return nil, err
 	}
-
-	// compute runs that require replay
+	replayJobSpec := replayRequest.JobSpecMap[replayRequest.Job.Name]
 	dagTree := tree.NewMultiRootTree()
 	parentNode := tree.NewTreeNode(replayJobSpec)
-	if runs, err := getRunsBetweenDates(replayRequest.Start, replayRequest.End, replayJobSpec.Schedule.Interval); err == nil {
-		for _, run := range runs {
-			parentNode.Runs.Add(run)
-		}
-	} else {
-		return nil, err
+	for _, run := range runs {
+		parentNode.Runs.Add(run)
 	}
 	dagTree.AddNode(parentNode)
 
@@ -78,8 +214,14 @@ func prepareTree(replayRequest *models.ReplayWorkerRequest) (*tree.TreeNode, err
 	if err != nil {
 		return nil, err
 	}
+	if replayRequest.ParentID != uuid.Nil {
+		// dependents in a replay plan have replays of their own
+		rootInstance.Dependents = []*tree.TreeNode{}
+	} else if !replayRequest.Selector.Empty() {
+		pruneUnselectedDependents(rootInstance, replayRequest.Selector)
+	}
 
-	rootInstance, err = populateDownstreamRuns(rootInstance)
+	rootInstance, err = populateDownstreamRuns(rootInstance, replayRequest.Project)
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +229,28 @@ func prepareTree(replayRequest *models.ReplayWorkerRequest) (*tree.TreeNode, err
 	return rootInstance, nil
 }
 
+// replayRuns returns runs of the replayed job in the window of replay
+func replayRuns(replayRequest *models.ReplayWorkerRequest) ([]time.Time, error) {
+	replayJobSpec, found := replayRequest.JobSpecMap[replayRequest.Job.Name]
+	if !found {
+		return nil, fmt.Errorf("couldn't find any job with name %s", replayRequest.Job.Name)
+	}
+
+	windowStart, windowEnd, err := replayWindow(replayRequest.Start, replayRequest.End, replayJobSpec.Schedule.Interval)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := getJobRunsBetweenDates(windowStart, windowEnd, replayJobSpec, replayRequest.Project)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, errors.Wrapf(ErrReplayWindowMisaligned, "%s has no runs scheduled between %s and %s, it runs at '%s'",
+			replayJobSpec.Name, windowStart.Format(time.RFC3339), windowEnd.Format(time.RFC3339), replayJobSpec.Schedule.Interval)
+	}
+	return runs, nil
+}
+
 func findOrCreateDAGNode(dagTree *tree.MultiRootTree, dagSpec models.JobSpec) *tree.TreeNode {
 	node, ok := dagTree.GetNodeByName(dagSpec.Name)
 	if !ok {
@@ -138,7 +302,20 @@ func populateDownstreamDAGs(dagTree *tree.MultiRootTree, jobSpec models.JobSpec,
 	return rootNode, nil
 }
 
-func populateDownstreamRuns(parentNode *tree.TreeNode) (*tree.TreeNode, error) {
+// pruneUnselectedDependents removes dependents not matching selector along
+// with their downstream, the replayed job itself is always kept
+func pruneUnselectedDependents(node *tree.TreeNode, selector models.LabelSelector) {
+	selected := []*tree.TreeNode{}
+	for _, dependent := range node.Dependents {
+		if selector.Matches(dependent.Data.(models.JobSpec).Labels) {
+			pruneUnselectedDependents(dependent, selector)
+			selected = append(selected, dependent)
+		}
+	}
+	node.Dependents = selected
+}
+
+func populateDownstreamRuns(parentNode *tree.TreeNode, projSpec models.ProjectSpec) (*tree.TreeNode, error) {
 	for idx, childNode := range parentNode.Dependents {
 		childDag := childNode.Data.(models.JobSpec)
 		taskSchedule, err := cron.ParseCronSchedule(childDag.Schedule.Interval)
@@ -149,8 +326,8 @@ func populateDownstreamRuns(parentNode *tree.TreeNode) (*tree.TreeNode, error) {
 		for _, parentRunDateRaw := range parentNode.Runs.Values() { //
 			parentRunDate := parentRunDateRaw.(time.Time)
 
-			// subtract 1 day to make end inclusive
-			parentEndDate := parentRunDate.Add(time.Hour * -24).Add(childDag.Task.Window.Size)
+			// runs of child reading from the window of parent run
+			parentEndDate := parentRunDate.Add(childDag.Task.Window.Size)
 
 			// subtracting 1 sec to accommodate next call of cron
 			// where parent task and current task has same scheduled interval
@@ -161,7 +338,7 @@ func populateDownstreamRuns(parentNode *tree.TreeNode) (*tree.TreeNode, error) {
 				continue
 			}
 
-			runs, err := getRunsBetweenDates(parentRunDate, parentEndDate, childDag.Schedule.Interval)
+			runs, err := getJobRunsBetweenDates(parentRunDate, parentEndDate, childDag, projSpec)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to find runs with parent dag")
 			}
@@ -169,7 +346,7 @@ func populateDownstreamRuns(parentNode *tree.TreeNode) (*tree.TreeNode, error) {
 				childNode.Runs.Add(run)
 			}
 		}
-		updatedChildNode, err := populateDownstreamRuns(childNode)
+		updatedChildNode, err := populateDownstreamRuns(childNode, projSpec)
 		if err != nil {
 			return nil, err
 		}
@@ -178,8 +355,73 @@ func populateDownstreamRuns(parentNode *tree.TreeNode) (*tree.TreeNode, error) {
 	return parentNode, nil
 }
 
-// getRunsBetweenDates provides execution runs from start to end following a schedule interval
-// start and end both are inclusive
+// getJobRunsBetweenDates provides runs of job from start till end honoring its
+// schedule exceptions, runs on skip days are left out and extra runs are added
+func getJobRunsBetweenDates(start time.Time, end time.Time, jobSpec models.JobSpec, projSpec models.ProjectSpec) ([]time.Time, error) {
+	runs, err := getRunsBetweenDates(start, end, jobSpec.Schedule.Interval)
+	if err != nil {
+		return nil, err
+	}
+	exceptions := jobSpec.Schedule.Exceptions
+	if exceptions.IsEmpty() {
+		return runs, nil
+	}
+
+	skipDays, err := exceptions.SkipDays(projSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve schedule exceptions of %s", jobSpec.Name)
+	}
+	skipped := map[string]bool{}
+	for _, day := range skipDays {
+		skipped[day] = true
+	}
+	var jobRuns []time.Time
+	for _, run := range runs {
+		if !skipped[run.UTC().Format(models.JobDatetimeLayout)] {
+			jobRuns = append(jobRuns, run)
+		}
+	}
+	for _, run := range exceptions.ExtraRuns {
+		if !run.Before(start) && run.Before(end) {
+			jobRuns = append(jobRuns, run)
+		}
+	}
+	sort.Slice(jobRuns, func(i, j int) bool {
+		return jobRuns[i].Before(jobRuns[j])
+	})
+	return jobRuns, nil
+}
+
+// replayWindow aligns the requested start and end of a replay with the
+// schedule interval of job, returning the range [start, end) runs in it are
+// replayed. A boundary at midnight is a day and the end day is replayed
+// entirely, any other boundary has to be a scheduled run of the job
+func replayWindow(start, end time.Time, interval string) (time.Time, time.Time, error) {
+	schd, err := cron.ParseCronSchedule(interval)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	for _, boundary := range []time.Time{start, end} {
+		if isReplayDate(boundary) {
+			continue
+		}
+		if next := schd.Next(boundary.Add(-time.Second)); !next.Equal(boundary) {
+			return time.Time{}, time.Time{}, errors.Wrapf(ErrReplayWindowMisaligned, "%s is not a scheduled run, next run is at %s",
+				boundary.Format(time.RFC3339), next.Format(time.RFC3339))
+		}
+	}
+	if isReplayDate(end) {
+		return start, end.AddDate(0, 0, 1), nil
+	}
+	return start, end.Add(time.Second), nil
+}
+
+func isReplayDate(t time.Time) bool {
+	return t.Equal(t.Truncate(24 * time.Hour))
+}
+
+// getRunsBetweenDates provides execution runs from start till end following a schedule interval,
+// start is inclusive and end is exclusive
 func getRunsBetweenDates(start time.Time, end time.Time, schedule string) ([]time.Time, error) {
 	var runs []time.Time
 
@@ -189,13 +431,8 @@ func getRunsBetweenDates(start time.Time, end time.Time, schedule string) ([]tim
 		return nil, err
 	}
 
-	replayRunEnd := schd.Next(end)
-	for replayRunEnd.Before(end.AddDate(0, 0, 1)) {
-		replayRunEnd = schd.Next(replayRunEnd)
-	}
-
 	// loop until start date reaches end date
-	for run := schd.Next(start.Add(time.Second * -1)); run.Before(replayRunEnd); run = schd.Next(run) {
+	for run := schd.Next(start.Add(time.Second * -1)); run.Before(end); run = schd.Next(run) {
 		runs = append(runs, run)
 	}
 
@@ -3,11 +3,13 @@ package job
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/set"
 	"github.com/odpf/optimus/core/tree"
 	"github.com/odpf/optimus/models"
 	"github.com/odpf/optimus/store"
@@ -33,11 +35,21 @@ type ReplayManagerConfig struct {
 	NumWorkers    int
 	WorkerTimeout time.Duration
 	RunTimeout    time.Duration
+	// QueueSize is the number of requests which can wait for a free worker
+	QueueSize int
 }
 
 type ReplayManager interface {
 	Init()
 	Replay(context.Context, *models.ReplayWorkerRequest) (string, error)
+	// ReplayPlan replays jobs of a plan one after another under a single id,
+	// returns the id of the plan
+	ReplayPlan(context.Context, *models.ReplayPlan) (string, error)
+	// Cancel cancels an active replay or plan along with its children
+	Cancel(context.Context, models.ProjectSpec, models.ReplaySpec) error
+	// QueuePosition returns the place of an accepted replay in the request
+	// queue, false if the replay is not waiting for a worker
+	QueuePosition(replayID uuid.UUID) (models.ReplayQueuePosition, bool)
 }
 
 // Manager for replaying operation(s).
@@ -52,25 +64,38 @@ type Manager struct {
 	uuidProvider utils.UUIDProvider
 	config       ReplayManagerConfig
 
-	// request queue, used by workers
-	requestQ chan *models.ReplayWorkerRequest
-	// request map, used for verifying if a request is
-	// in queue without actually consuming it
-	requestMap map[uuid.UUID]bool
+	// request queue, used by workers. A replay is queued as a plan of its
+	// own, replays of a plan are processed by the same worker in order
+	requestQ chan *models.ReplayPlan
+	// ids of requests in queue in the order they are picked up, used for
+	// finding the position of a request without actually consuming it
+	queued []uuid.UUID
+	// plans replays of plans in queue are part of, by replay id
+	planOf map[uuid.UUID]uuid.UUID
+	// number of requests processed by workers and the time they took
+	processed   int
+	processTime time.Duration
 
 	//request worker
 	replayWorker ReplayWorker
 
 	replaySpecRepoFac ReplaySpecRepoFactory
 	scheduler         models.SchedulerUnit
+	eventSvc          EventRegistrar
+
+	// replays hold lock of their job from being accepted till a worker is
+	// done with them, so the job isn't deployed meanwhile
+	locker *JobLocker
+	unlock map[uuid.UUID]func()
 }
 
 // Replay a request asynchronously, returns a replay id that can
-// can be used to query its status
+// can be used to query its status. A replay of more than one job tracks
+// each of its jobs by a replay of its own with the replay as their parent
 func (m *Manager) Replay(ctx context.Context, reqInput *models.ReplayWorkerRequest) (string, error) {
 	replaySpecRepo := m.replaySpecRepoFac.New(reqInput.Job)
 
-	err := m.validate(ctx, replaySpecRepo, reqInput)
+	replayTree, err := m.validate(ctx, replaySpecRepo, reqInput)
 	if err != nil {
 		return "", err
 	}
@@ -81,6 +106,11 @@ func (m *Manager) Replay(ctx context.Context, reqInput *models.ReplayWorkerReque
 	}
 	reqInput.ID = uuidOb
 
+	unlock, err := m.locker.Lock(reqInput.Project.Name, replayJobNames(replayTree), JobOperationReplay, uuidOb.String())
+	if err != nil {
+		return "", err
+	}
+
 	// save replay request and mark status as accepted
 	replay := models.ReplaySpec{
 		ID:        uuidOb,
@@ -90,30 +120,249 @@ func (m *Manager) Replay(ctx context.Context, reqInput *models.ReplayWorkerReque
 		Status:    models.ReplayStatusAccepted,
 	}
 	if err = replaySpecRepo.Insert(&replay); err != nil {
+		unlock()
+		return "", err
+	}
+	var jobReplays []models.ReplaySpec
+	if replayNodes := replayTree.GetAllNodes(); len(replayNodes) > 1 {
+		tracked := map[string]bool{}
+		for _, node := range replayNodes {
+			// jobs downstream of more than one replayed job are in the
+			// tree once for each
+			if tracked[node.GetName()] {
+				continue
+			}
+			tracked[node.GetName()] = true
+			jobReplayID, err := m.uuidProvider.NewUUID()
+			if err != nil {
+				unlock()
+				m.cancelNotAccepted(ctx, reqInput.Project, []models.ReplaySpec{replay}, err)
+				return "", err
+			}
+			jobReplays = append(jobReplays, models.ReplaySpec{
+				ID:        jobReplayID,
+				Job:       node.Data.(models.JobSpec),
+				StartDate: reqInput.Start,
+				EndDate:   reqInput.End,
+				Status:    models.ReplayStatusAccepted,
+				ParentID:  uuidOb,
+			})
+		}
+	}
+	if err := m.insertReplays(ctx, reqInput.Project, jobReplays, []models.ReplaySpec{replay}); err != nil {
+		unlock()
 		return "", err
 	}
 
 	// try sending the job request down the request queue
 	// if full return error indicating that we don't have capacity
 	// to process this request at the moment
+	m.mu.Lock()
 	select {
-	case m.requestQ <- reqInput:
-		m.mu.Lock()
-		//request pushed to worker
-		m.requestMap[reqInput.ID] = true
+	case m.requestQ <- &models.ReplayPlan{ID: reqInput.ID, Project: reqInput.Project, Replays: []*models.ReplayWorkerRequest{reqInput}}:
+		// held lock keeps workers from picking up the request before it is
+		// recorded in queue
+		m.queued = append(m.queued, reqInput.ID)
+		m.unlock[reqInput.ID] = unlock
 		m.mu.Unlock()
 
+		notifyReplay(ctx, m.eventSvc, reqInput.Project, replay)
 		return reqInput.ID.String(), nil
 	default:
+		m.mu.Unlock()
+		unlock()
+		// cancel the request so that it doesn't conflict with the same
+		// replay submitted again once the queue has capacity
+		replay.Status = models.ReplayStatusCancelled
+		replay.Message = models.ReplayMessage{
+			Type:    ErrRequestQueueFull.Error(),
+			Message: "request could not be queued",
+		}
+		if err := replaySpecRepo.UpdateStatus(replay.ID, replay.Status, replay.Message); err != nil {
+			return "", err
+		}
+		cancelReplays(ctx, m.replaySpecRepoFac, m.eventSvc, reqInput.Project, jobReplays, replay.Message, false)
+		notifyReplay(ctx, m.eventSvc, reqInput.Project, replay)
 		return "", ErrRequestQueueFull
 	}
 }
 
-func (m *Manager) validate(ctx context.Context, replaySpecRepo store.ReplaySpecRepository, reqInput *models.ReplayWorkerRequest) error {
-	reqReplayTree, err := prepareTree(reqInput)
+// replayJobNames returns names of jobs rerun by replays of trees, which are
+// the replayed jobs and their downstream, so all of them are locked
+func replayJobNames(replayTrees ...*tree.TreeNode) []string {
+	var jobNames []string
+	for _, replayTree := range replayTrees {
+		for _, node := range replayTree.GetAllNodes() {
+			jobNames = append(jobNames, node.GetName())
+		}
+	}
+	return jobNames
+}
+
+// ReplayPlan replays jobs of a plan asynchronously, replays of the plan are
+// validated and accepted together and processed by a single worker in order,
+// replays after a failed one are cancelled. The plan is saved as a replay
+// without a job with its replays as children. Returns id of the plan that
+// can be used to query status of its replays
+func (m *Manager) ReplayPlan(ctx context.Context, plan *models.ReplayPlan) (string, error) {
+	if len(plan.Replays) == 0 {
+		return "", errors.New("replay plan has no replays")
+	}
+	planID, err := m.uuidProvider.NewUUID()
 	if err != nil {
+		return "", err
+	}
+	plan.ID = planID
+
+	var replayTrees []*tree.TreeNode
+	for _, reqInput := range plan.Replays {
+		replayID, err := m.uuidProvider.NewUUID()
+		if err != nil {
+			return "", err
+		}
+		reqInput.ID = replayID
+		reqInput.ParentID = planID
+		replayTree, err := m.validate(ctx, m.replaySpecRepoFac.New(reqInput.Job), reqInput)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to validate replay of %s", reqInput.Job.Name)
+		}
+		replayTrees = append(replayTrees, replayTree)
+	}
+
+	unlock, err := m.locker.Lock(plan.Project.Name, replayJobNames(replayTrees...), JobOperationReplay, planID.String())
+	if err != nil {
+		return "", err
+	}
+
+	// save the plan and its replays and mark them as accepted
+	planReplay := models.ReplaySpec{
+		ID:        planID,
+		StartDate: plan.Replays[0].Start,
+		EndDate:   plan.Replays[0].End,
+		Status:    models.ReplayStatusAccepted,
+	}
+	replays := []models.ReplaySpec{planReplay}
+	for _, reqInput := range plan.Replays {
+		replays = append(replays, models.ReplaySpec{
+			ID:        reqInput.ID,
+			Job:       reqInput.Job,
+			StartDate: reqInput.Start,
+			EndDate:   reqInput.End,
+			Status:    models.ReplayStatusAccepted,
+			ParentID:  planID,
+		})
+	}
+	if err := m.insertReplays(ctx, plan.Project, replays, nil); err != nil {
+		unlock()
+		return "", err
+	}
+
+	m.mu.Lock()
+	select {
+	case m.requestQ <- plan:
+		m.queued = append(m.queued, planID)
+		for _, reqInput := range plan.Replays {
+			m.planOf[reqInput.ID] = planID
+		}
+		m.unlock[planID] = unlock
+		m.mu.Unlock()
+
+		for _, replay := range replays[1:] {
+			notifyReplay(ctx, m.eventSvc, plan.Project, replay)
+		}
+		return planID.String(), nil
+	default:
+		m.mu.Unlock()
+		unlock()
+		cancelReplays(ctx, m.replaySpecRepoFac, m.eventSvc, plan.Project, replays, models.ReplayMessage{
+			Type:    ErrRequestQueueFull.Error(),
+			Message: "request could not be queued",
+		}, true)
+		return "", ErrRequestQueueFull
+	}
+}
+
+// insertReplays saves replays in order, replays saved before a failure are
+// cancelled along with accepted ones so they don't conflict with the same
+// replay submitted again
+func (m *Manager) insertReplays(ctx context.Context, project models.ProjectSpec, replays, accepted []models.ReplaySpec) error {
+	for idx := range replays {
+		if err := m.replaySpecRepoFac.New(replays[idx].Job).Insert(&replays[idx]); err != nil {
+			m.cancelNotAccepted(ctx, project, append(accepted, replays[:idx]...), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// cancelNotAccepted cancels replays saved for a request which failed to be
+// accepted
+func (m *Manager) cancelNotAccepted(ctx context.Context, project models.ProjectSpec, replays []models.ReplaySpec, err error) {
+	cancelReplays(ctx, m.replaySpecRepoFac, m.eventSvc, project, replays, models.ReplayMessage{
+		Type:    "replay not accepted",
+		Message: err.Error(),
+	}, false)
+}
+
+// Cancel cancels an active replay along with its children, the jobs of a
+// replay of more than one job or the replays of a plan. Replays waiting in
+// queue are dropped from it, ones in progress stop before their next job
+func (m *Manager) Cancel(ctx context.Context, project models.ProjectSpec, replay models.ReplaySpec) error {
+	message := models.ReplayMessage{
+		Type:    ReplayCancelled,
+		Message: "replay was cancelled",
+	}
+	replaySpecRepo := m.replaySpecRepoFac.New(replay.Job)
+	if err := replaySpecRepo.UpdateStatus(replay.ID, models.ReplayStatusCancelled, message); err != nil {
 		return err
 	}
+	m.dequeue(replay.ID)
+	if replay.Job.Name != "" {
+		replay.Status = models.ReplayStatusCancelled
+		replay.Message = message
+		notifyReplay(ctx, m.eventSvc, project, replay)
+	}
+
+	children, err := replaySpecRepo.GetByParentID(replay.ID)
+	if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+		return err
+	}
+	// replays of a plan are replays of their own, jobs of a replay only
+	// track it
+	cancelReplays(ctx, m.replaySpecRepoFac, m.eventSvc, project, children, models.ReplayMessage{
+		Type:    ReplayCancelled,
+		Message: fmt.Sprintf("parent replay %s was cancelled", replay.ID),
+	}, replay.Job.Name == "")
+	return nil
+}
+
+// cancelReplays cancels replays which are yet to end, failures are only
+// logged as they are cancelled on a failure already. Replays of jobs are
+// notified unless they only track a job of a replay of more than one job
+func cancelReplays(ctx context.Context, replaySpecRepoFac ReplaySpecRepoFactory, eventSvc EventRegistrar,
+	project models.ProjectSpec, replays []models.ReplaySpec, message models.ReplayMessage, notify bool) {
+	for _, replay := range replays {
+		if err := replaySpecRepoFac.New(replay.Job).UpdateStatus(replay.ID, models.ReplayStatusCancelled, message); err != nil {
+			if !errors.Is(err, models.ErrInvalidReplayTransition) {
+				logger.E(errors.Wrapf(err, "failed to cancel replay %s", replay.ID))
+			}
+			continue
+		}
+		if notify && replay.Job.Name != "" {
+			replay.Status = models.ReplayStatusCancelled
+			replay.Message = message
+			notifyReplay(ctx, eventSvc, project, replay)
+		}
+	}
+}
+
+// validate checks a replay request doesn't conflict with active replays or
+// running instances of its jobs, returns the tree of jobs to replay
+func (m *Manager) validate(ctx context.Context, replaySpecRepo store.ReplaySpecRepository, reqInput *models.ReplayWorkerRequest) (*tree.TreeNode, error) {
+	reqReplayTree, err := prepareTree(reqInput)
+	if err != nil {
+		return nil, err
+	}
 
 	if !reqInput.Force {
 		reqReplayNodes := reqReplayTree.GetAllNodes()
@@ -121,24 +370,33 @@ func (m *Manager) validate(ctx context.Context, replaySpecRepo store.ReplaySpecR
 		//check if this dag have running instance in the scheduler
 		err = m.validateRunningInstance(ctx, reqReplayNodes, reqInput)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		//check another replay active for this dag
 		activeReplaySpecs, err := replaySpecRepo.GetByStatus(ReplayStatusToValidate)
 		if err != nil {
 			if err == store.ErrResourceNotFound {
-				return nil
+				return reqReplayTree, nil
 			}
-			return err
+			return nil, err
+		}
+		if err := validateReplayJobsConflict(activeReplaySpecs, reqInput, reqReplayNodes); err != nil {
+			return nil, err
 		}
-		return validateReplayJobsConflict(activeReplaySpecs, reqInput, reqReplayNodes)
+		return reqReplayTree, nil
 	}
 	//check and cancel if found conflicted replays for same job ID
-	return cancelConflictedReplays(replaySpecRepo, reqInput)
+	if err := m.cancelConflictedReplays(ctx, replaySpecRepo, reqInput); err != nil {
+		return nil, err
+	}
+	return reqReplayTree, nil
 }
 
-func cancelConflictedReplays(replaySpecRepo store.ReplaySpecRepository, reqInput *models.ReplayWorkerRequest) error {
+// cancelConflictedReplays cancels active replays of the job, ones still
+// waiting in queue are dropped from it and skipped once a worker picks
+// them up as they can't move to in progress anymore
+func (m *Manager) cancelConflictedReplays(ctx context.Context, replaySpecRepo store.ReplaySpecRepository, reqInput *models.ReplayWorkerRequest) error {
 	duplicatedReplaySpecs, err := replaySpecRepo.GetByJobIDAndStatus(reqInput.Job.ID, ReplayStatusToValidate)
 	if err != nil {
 		if err == store.ErrResourceNotFound {
@@ -147,12 +405,32 @@ func cancelConflictedReplays(replaySpecRepo store.ReplaySpecRepository, reqInput
 		return err
 	}
 	for _, replaySpec := range duplicatedReplaySpecs {
-		if err := replaySpecRepo.UpdateStatus(replaySpec.ID, models.ReplayStatusCancelled, models.ReplayMessage{
+		if replaySpec.ParentID != uuid.Nil {
+			// plans are saved without a job, otherwise the replay tracks
+			// the job in a replay of more than one job which is cancelled
+			// only if it is of the same job
+			if _, err := m.replaySpecRepoFac.New(models.JobSpec{}).GetByID(replaySpec.ParentID); err != nil {
+				if errors.Is(err, store.ErrResourceNotFound) {
+					continue
+				}
+				return err
+			}
+		}
+		cancelledMessage := models.ReplayMessage{
 			Type:    ErrConflictedJobRun.Error(),
 			Message: fmt.Sprintf("force started replay with ID: %s", reqInput.ID),
-		}); err != nil {
+		}
+		if err := replaySpecRepo.UpdateStatus(replaySpec.ID, models.ReplayStatusCancelled, cancelledMessage); err != nil {
+			if errors.Is(err, models.ErrInvalidReplayTransition) {
+				// replay ended meanwhile
+				continue
+			}
 			return err
 		}
+		m.dequeue(replaySpec.ID)
+		replaySpec.Status = models.ReplayStatusCancelled
+		replaySpec.Message = cancelledMessage
+		notifyReplay(ctx, m.eventSvc, reqInput.Project, replaySpec)
 	}
 	return nil
 }
@@ -165,18 +443,33 @@ func (m *Manager) validateRunningInstance(ctx context.Context, reqReplayNodes []
 		if err != nil {
 			return err
 		}
+		runningRuns := set.NewTreeSetWithTimeComparator()
 		for _, jobStatus := range jobStatusAllRuns {
-			if reqReplayNode.Runs.Contains(jobStatus.ScheduledAt) && jobStatus.State == models.JobStatusStateRunning {
-				return ErrConflictedJobRun
+			if jobStatus.State == models.JobStatusStateRunning {
+				runningRuns.Add(jobStatus.ScheduledAt)
 			}
 		}
+		if overlap := set.TimeIntersection(reqReplayNode.Runs, runningRuns); !overlap.Empty() {
+			return errors.Wrapf(ErrConflictedJobRun, "%s is already running for %s", reqReplayNode.GetName(), formatRuns(overlap))
+		}
 	}
 	return nil
 }
 
 func validateReplayJobsConflict(activeReplaySpecs []models.ReplaySpec, reqInput *models.ReplayWorkerRequest,
 	reqReplayNodes []*tree.TreeNode) error {
+	replaysOfJobs := map[uuid.UUID]bool{}
 	for _, activeSpec := range activeReplaySpecs {
+		if activeSpec.Job.Name != "" {
+			replaysOfJobs[activeSpec.ID] = true
+		}
+	}
+	for _, activeSpec := range activeReplaySpecs {
+		// plans have no job of their own, their replays are checked. Jobs
+		// of a replay of more than one job are checked with their parent
+		if activeSpec.Job.Name == "" || replaysOfJobs[activeSpec.ParentID] {
+			continue
+		}
 		activeReplayWorkerRequest := &models.ReplayWorkerRequest{
 			ID:         activeSpec.ID,
 			Job:        activeSpec.Job,
@@ -184,13 +477,16 @@ func validateReplayJobsConflict(activeReplaySpecs []models.ReplaySpec, reqInput
 			End:        activeSpec.EndDate,
 			Project:    reqInput.Project,
 			JobSpecMap: reqInput.JobSpecMap,
+			ParentID:   activeSpec.ParentID,
 		}
 		activeTree, err := prepareTree(activeReplayWorkerRequest)
 		if err != nil {
 			return err
 		}
 		activeNodes := activeTree.GetAllNodes()
-		return checkAnyConflictedDags(activeNodes, reqReplayNodes)
+		if err := checkAnyConflictedDags(activeNodes, reqReplayNodes); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -212,28 +508,171 @@ func checkAnyConflictedDags(activeNodes []*tree.TreeNode, reqReplayNodes []*tree
 }
 
 func checkAnyConflictedRuns(activeNode *tree.TreeNode, reqNode *tree.TreeNode) error {
-	for _, reqNodeRun := range reqNode.Runs.Values() {
-		if activeNode.Runs.Contains(reqNodeRun.(time.Time)) {
-			return ErrConflictedJobRun
-		}
+	if overlap := reqNode.IntersectRuns(activeNode); !overlap.Empty() {
+		return errors.Wrapf(ErrConflictedJobRun, "%s is being replayed for %s", reqNode.GetName(), formatRuns(overlap))
 	}
 	return nil
 }
 
+// formatRuns lists run timestamps of a time set in log format
+func formatRuns(runs set.Set) string {
+	var formatted []string
+	for _, run := range set.Times(runs) {
+		formatted = append(formatted, run.Format(TimestampLogFormat))
+	}
+	return strings.Join(formatted, ", ")
+}
+
 // start a worker goroutine that runs the deployment pipeline in background
 func (m *Manager) spawnServiceWorker() {
 	defer m.wg.Done()
 
-	for reqInput := range m.requestQ {
+	for plan := range m.requestQ {
+		m.dequeue(plan.ID)
+
+		startedAt := time.Now()
+		m.processPlan(plan)
+
+		m.mu.Lock()
+		m.processed++
+		m.processTime += time.Since(startedAt)
+		unlock, ok := m.unlock[plan.ID]
+		delete(m.unlock, plan.ID)
+		for _, reqInput := range plan.Replays {
+			delete(m.planOf, reqInput.ID)
+		}
+		m.mu.Unlock()
+		if ok {
+			unlock()
+		}
+	}
+}
+
+// processPlan processes replays of a plan in order, replays after a failed
+// one are cancelled. Status of the plan is the overall status of its replays
+// once they are processed
+func (m *Manager) processPlan(plan *models.ReplayPlan) {
+	// a replay is queued as a plan of its own without a parent
+	isPlan := plan.Replays[0].ParentID == plan.ID
+	planRepo := m.replaySpecRepoFac.New(models.JobSpec{})
+	if isPlan {
+		if err := planRepo.UpdateStatus(plan.ID, models.ReplayStatusInProgress, models.ReplayMessage{}); err != nil {
+			if errors.Is(err, models.ErrInvalidReplayTransition) {
+				// cancelled while waiting in queue along with its replays
+				logger.I(fmt.Sprintf("skipping replay plan %s: %s", plan.ID, err))
+				return
+			}
+			logger.E(errors.Wrapf(err, "failed to mark replay plan %s in progress", plan.ID))
+		}
+	}
+
+	for idx, reqInput := range plan.Replays {
 		logger.I("worker picked up the request for ", reqInput.Job.Name)
 		ctx, cancelCtx := context.WithTimeout(context.Background(), m.config.WorkerTimeout)
-		if err := m.replayWorker.Process(ctx, reqInput); err != nil {
-			//do something about this error
-			logger.E(errors.Wrap(err, "worker failed to process"))
-			cancelCtx()
-		}
+		err := m.replayWorker.Process(ctx, reqInput)
 		cancelCtx()
+		if err == nil {
+			continue
+		}
+		logger.E(errors.Wrap(err, "worker failed to process"))
+		if remaining := plan.Replays[idx+1:]; len(remaining) > 0 {
+			// downstream of a failed replay would be replayed with
+			// upstream data which isn't fixed
+			var replays []models.ReplaySpec
+			for _, remainingReq := range remaining {
+				replays = append(replays, models.ReplaySpec{
+					ID:        remainingReq.ID,
+					Job:       remainingReq.Job,
+					StartDate: remainingReq.Start,
+					EndDate:   remainingReq.End,
+					ParentID:  plan.ID,
+				})
+			}
+			cancelReplays(context.Background(), m.replaySpecRepoFac, m.eventSvc, plan.Project, replays, models.ReplayMessage{
+				Type:    ReplayUpstreamFailed,
+				Message: fmt.Sprintf("replay %s of %s in the same plan failed", reqInput.ID, reqInput.Job.Name),
+			}, true)
+		}
+		break
+	}
+	if !isPlan {
+		return
+	}
+
+	replays, err := planRepo.GetByParentID(plan.ID)
+	if err != nil {
+		logger.E(errors.Wrapf(err, "failed to read replays of plan %s", plan.ID))
+		return
+	}
+	status := models.ReplayPlanStatus(replays)
+	if status == models.ReplayStatusAccepted || status == models.ReplayStatusInProgress {
+		// replays are done with by now, ones which are not ended failed
+		// to update their status
+		status = models.ReplayStatusFailed
+	}
+	var message models.ReplayMessage
+	if status != models.ReplayStatusSuccess {
+		message = models.ReplayMessage{
+			Type:    "replay plan " + status,
+			Message: fmt.Sprintf("%d of %d replays of the plan succeeded", countReplays(replays, models.ReplayStatusSuccess), len(replays)),
+		}
+	}
+	if err := planRepo.UpdateStatus(plan.ID, status, message); err != nil && !errors.Is(err, models.ErrInvalidReplayTransition) {
+		logger.E(errors.Wrapf(err, "failed to update status of replay plan %s", plan.ID))
+	}
+}
+
+// countReplays returns the number of replays with the status
+func countReplays(replays []models.ReplaySpec, status string) int {
+	count := 0
+	for _, replay := range replays {
+		if replay.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// dequeue drops a replay from requests waiting in queue, once it's picked up
+// by a worker or it has ended while waiting
+func (m *Manager) dequeue(replayID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for idx, queuedID := range m.queued {
+		if queuedID == replayID {
+			m.queued = append(m.queued[:idx], m.queued[idx+1:]...)
+			return
+		}
+	}
+}
+
+// QueuePosition returns the place of an accepted replay in the request queue
+// along with the time till a worker picks it up, estimated from the average
+// time workers took to process requests so far. Replays of a plan are in the
+// place of their plan
+func (m *Manager) QueuePosition(replayID uuid.UUID) (models.ReplayQueuePosition, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if planID, ok := m.planOf[replayID]; ok {
+		replayID = planID
+	}
+	for idx, queuedID := range m.queued {
+		if queuedID != replayID {
+			continue
+		}
+		position := models.ReplayQueuePosition{Position: idx + 1}
+		if m.processed > 0 {
+			workers := m.config.NumWorkers
+			if workers < 1 {
+				workers = 1
+			}
+			// requests ahead are picked up by busy workers in rounds
+			rounds := idx/workers + 1
+			position.ETA = time.Duration(rounds) * (m.processTime / time.Duration(m.processed))
+		}
+		return position, true
 	}
+	return models.ReplayQueuePosition{}, false
 }
 
 //Close stops consuming any new request
@@ -280,15 +719,18 @@ func (m *Manager) shuttingDownTimedOutReplays() {
 
 // NewManager constructs a new instance of Manager
 func NewManager(worker ReplayWorker, replaySpecRepoFac ReplaySpecRepoFactory, uuidProvider utils.UUIDProvider,
-	config ReplayManagerConfig, scheduler models.SchedulerUnit) *Manager {
+	config ReplayManagerConfig, scheduler models.SchedulerUnit, eventSvc EventRegistrar, locker *JobLocker) *Manager {
 	mgr := &Manager{
 		replayWorker:      worker,
-		requestMap:        make(map[uuid.UUID]bool),
 		config:            config,
-		requestQ:          make(chan *models.ReplayWorkerRequest, 0),
+		requestQ:          make(chan *models.ReplayPlan, config.QueueSize),
 		replaySpecRepoFac: replaySpecRepoFac,
 		uuidProvider:      uuidProvider,
 		scheduler:         scheduler,
+		eventSvc:          eventSvc,
+		locker:            locker,
+		unlock:            map[uuid.UUID]func(){},
+		planOf:            map[uuid.UUID]uuid.UUID{},
 	}
 	mgr.Init()
 	return mgr
@@ -15,6 +15,7 @@ import (
 	"github.com/odpf/optimus/store"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
 )
 
 func TestReplayManager(t *testing.T) {
@@ -34,7 +35,7 @@ func TestReplayManager(t *testing.T) {
 		defer replaySpecRepoFac.AssertExpectations(t)
 		replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
 
-		manager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, nil)
+		manager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, nil, nil, nil)
 		err := manager.Close()
 		assert.Nil(t, err)
 	})
@@ -82,7 +83,7 @@ func TestReplayManager(t *testing.T) {
 			defer replaySpecRepoFac.AssertExpectations(t)
 			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, nil)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, nil, nil, nil)
 			replayManager.Init()
 		})
 	})
@@ -143,7 +144,7 @@ func TestReplayManager(t *testing.T) {
 			defer scheduler.AssertExpectations(t)
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, scheduler, nil, nil)
 			_, err := replayManager.Replay(ctx, replayRequest)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), errMessage)
@@ -178,7 +179,7 @@ func TestReplayManager(t *testing.T) {
 			defer scheduler.AssertExpectations(t)
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, scheduler, nil, nil)
 			_, err := replayManager.Replay(ctx, replayRequest)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), errMessage)
@@ -199,7 +200,7 @@ func TestReplayManager(t *testing.T) {
 			defer scheduler.AssertExpectations(t)
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler, nil, nil)
 			_, err := replayManager.Replay(ctx, replayRequest)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), errMessage)
@@ -236,10 +237,10 @@ func TestReplayManager(t *testing.T) {
 			defer scheduler.AssertExpectations(t)
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler, nil, nil)
 
 			_, err := replayManager.Replay(ctx, replayRequest)
-			assert.Equal(t, err, job.ErrConflictedJobRun)
+			assert.True(t, errors.Is(err, job.ErrConflictedJobRun))
 		})
 		t.Run("should pass replay validation when no conflicting dag found", func(t *testing.T) {
 			activeReplayUUID := uuid.Must(uuid.NewRandom())
@@ -283,7 +284,7 @@ func TestReplayManager(t *testing.T) {
 			defer scheduler.AssertExpectations(t)
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, scheduler, nil, nil)
 			_, err := replayManager.Replay(ctx, replayRequest)
 			assert.Equal(t, errMessage, err.Error())
 		})
@@ -331,10 +332,156 @@ func TestReplayManager(t *testing.T) {
 			defer scheduler.AssertExpectations(t)
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, scheduler, nil, nil)
 			_, err := replayManager.Replay(ctx, replayRequest)
 			assert.Equal(t, errMessage, err.Error())
 		})
+		t.Run("should cancel replay if request queue is full", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, store.ErrResourceNotFound)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+			replaySpecRepoFac.On("New", replayRequest.Job).Return(replayRepository)
+
+			uuidProvider := new(mock.UUIDProvider)
+			defer uuidProvider.AssertExpectations(t)
+			objUUID := uuid.Must(uuid.NewRandom())
+			uuidProvider.On("NewUUID").Return(objUUID, nil)
+
+			replayRepository.On("Insert", &models.ReplaySpec{
+				ID:        objUUID,
+				Job:       jobSpec,
+				StartDate: startDate,
+				EndDate:   endDate,
+				Status:    models.ReplayStatusAccepted,
+			}).Return(nil)
+			replayRepository.On("UpdateStatus", objUUID, models.ReplayStatusCancelled, models.ReplayMessage{
+				Type:    job.ErrRequestQueueFull.Error(),
+				Message: "request could not be queued",
+			}).Return(nil)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
+
+			// no worker picks up requests
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, job.ReplayManagerConfig{}, scheduler, nil, nil)
+			_, err := replayManager.Replay(ctx, replayRequest)
+			assert.Equal(t, job.ErrRequestQueueFull, err)
+		})
+		t.Run("should queue replay till a worker is free", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, store.ErrResourceNotFound)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+			replaySpecRepoFac.On("New", replayRequest.Job).Return(replayRepository)
+
+			uuidProvider := new(mock.UUIDProvider)
+			defer uuidProvider.AssertExpectations(t)
+			objUUID := uuid.Must(uuid.NewRandom())
+			uuidProvider.On("NewUUID").Return(objUUID, nil)
+
+			replayRepository.On("Insert", &models.ReplaySpec{
+				ID:        objUUID,
+				Job:       jobSpec,
+				StartDate: startDate,
+				EndDate:   endDate,
+				Status:    models.ReplayStatusAccepted,
+			}).Return(nil)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
+
+			// no worker picks up requests
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, job.ReplayManagerConfig{QueueSize: 1}, scheduler, nil, nil)
+			replayID, err := replayManager.Replay(ctx, replayRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, objUUID.String(), replayID)
+
+			position, ok := replayManager.QueuePosition(objUUID)
+			assert.True(t, ok)
+			assert.Equal(t, models.ReplayQueuePosition{Position: 1}, position)
+
+			_, ok = replayManager.QueuePosition(uuid.Must(uuid.NewRandom()))
+			assert.False(t, ok)
+		})
+		t.Run("should hold lock of the job till the replay is processed", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, store.ErrResourceNotFound)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+			replaySpecRepoFac.On("New", replayRequest.Job).Return(replayRepository)
+
+			uuidProvider := new(mock.UUIDProvider)
+			defer uuidProvider.AssertExpectations(t)
+			objUUID := uuid.Must(uuid.NewRandom())
+			uuidProvider.On("NewUUID").Return(objUUID, nil)
+
+			replayRepository.On("Insert", &models.ReplaySpec{
+				ID:        objUUID,
+				Job:       jobSpec,
+				StartDate: startDate,
+				EndDate:   endDate,
+				Status:    models.ReplayStatusAccepted,
+			}).Return(nil)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
+
+			lockRepo := new(mock.JobLockRepository)
+			defer lockRepo.AssertExpectations(t)
+			lockRepo.On("Acquire", mock2.MatchedBy(func(lock models.JobLock) bool {
+				return lock.Project == replayRequest.Project.Name && lock.Operation == job.JobOperationReplay && lock.HolderID == objUUID.String()
+			}), []string{jobSpec.Name}, job.JobLockTTL).Return((*models.JobLock)(nil), nil)
+
+			// no worker picks up requests
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, job.ReplayManagerConfig{QueueSize: 1}, scheduler, nil, job.NewJobLocker(lockRepo))
+			_, err := replayManager.Replay(ctx, replayRequest)
+			assert.Nil(t, err)
+			lockRepo.AssertNotCalled(t, "Release", mock2.Anything)
+		})
+		t.Run("should reject replay of a job being deployed", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, store.ErrResourceNotFound)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+			replaySpecRepoFac.On("New", replayRequest.Job).Return(replayRepository)
+
+			uuidProvider := new(mock.UUIDProvider)
+			defer uuidProvider.AssertExpectations(t)
+			uuidProvider.On("NewUUID").Return(uuid.Must(uuid.NewRandom()), nil)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
+
+			lockRepo := new(mock.JobLockRepository)
+			defer lockRepo.AssertExpectations(t)
+			lockRepo.On("Acquire", mock2.AnythingOfType("models.JobLock"), []string{jobSpec.Name}, job.JobLockTTL).Return(&models.JobLock{
+				Project:   replayRequest.Project.Name,
+				Job:       jobSpec.Name,
+				Operation: job.JobOperationDeploy,
+			}, nil)
+
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, job.ReplayManagerConfig{QueueSize: 1}, scheduler, nil, job.NewJobLocker(lockRepo))
+			_, err := replayManager.Replay(ctx, replayRequest)
+			assert.True(t, errors.Is(err, job.ErrJobLocked))
+			assert.Equal(t, "job "+jobSpec.Name+" of project project-name is locked by deploy in progress, try again once it's done", err.Error())
+		})
 		t.Run("should return error when unable to get status from scheduler", func(t *testing.T) {
 			replayRepository := new(mock.ReplayRepository)
 			defer replayRepository.AssertExpectations(t)
@@ -350,7 +497,7 @@ func TestReplayManager(t *testing.T) {
 			errMessage := "unable to get status"
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, errors.New(errMessage))
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler, nil, nil)
 
 			_, err := replayManager.Replay(ctx, replayRequest)
 			assert.Equal(t, errMessage, err.Error())
@@ -379,9 +526,11 @@ func TestReplayManager(t *testing.T) {
 			}
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return(jobStatus, nil)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler, nil, nil)
 			_, err := replayManager.Replay(ctx, replayRequest)
-			assert.Equal(t, job.ErrConflictedJobRun, err)
+			assert.True(t, errors.Is(err, job.ErrConflictedJobRun))
+			assert.Contains(t, err.Error(), "2020-08-23T02:00:00+00:00")
+			assert.NotContains(t, err.Error(), "2020-08-22T02:00:00+00:00")
 		})
 		t.Run("should return error when no running instance found in scheduler but accepted in replay", func(t *testing.T) {
 			activeReplayUUID := uuid.Must(uuid.NewRandom())
@@ -421,9 +570,9 @@ func TestReplayManager(t *testing.T) {
 			}
 			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, jobSpec.Name, startDate, reqBatchEndDate, reqBatchSize).Return(jobStatus, nil)
 
-			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, replayManagerConfig, scheduler, nil, nil)
 			_, err := replayManager.Replay(ctx, replayRequest)
-			assert.Equal(t, job.ErrConflictedJobRun, err)
+			assert.True(t, errors.Is(err, job.ErrConflictedJobRun))
 		})
 		t.Run("should not validate conflicting dags but cancel conflicting replay when force enabled", func(t *testing.T) {
 			activeReplayUUID := uuid.Must(uuid.NewRandom())
@@ -469,9 +618,340 @@ func TestReplayManager(t *testing.T) {
 			replayRepository.On("Insert", toInsertReplaySpec).Return(errors.New(errMessage))
 
 			replayRequest.Force = true
-			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, nil)
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, replayManagerConfig, nil, nil, nil)
 			_, err := replayManager.Replay(ctx, replayRequest)
 			assert.Equal(t, errMessage, err.Error())
 		})
 	})
+	t.Run("ReplayPlan", func(t *testing.T) {
+		dagStartTime, _ := time.Parse(job.ReplayDateFormat, "2020-04-05")
+		startDate, _ := time.Parse(job.ReplayDateFormat, "2020-08-22")
+		endDate, _ := time.Parse(job.ReplayDateFormat, "2020-08-26")
+		reqBatchEndDate := endDate.AddDate(0, 0, 1)
+		reqBatchSize := 100
+		schedule := models.JobSpecSchedule{
+			StartDate: dagStartTime,
+			Interval:  "0 2 * * *",
+		}
+		jobSpec := models.JobSpec{
+			ID:       uuid.Must(uuid.NewRandom()),
+			Name:     "job-name",
+			Schedule: schedule,
+		}
+		jobSpec2 := models.JobSpec{
+			ID:       uuid.Must(uuid.NewRandom()),
+			Name:     "job-name-2",
+			Schedule: schedule,
+			Dependencies: map[string]models.JobSpecDependency{
+				jobSpec.Name: {Job: &jobSpec, Type: models.JobSpecDependencyTypeIntra},
+			},
+		}
+		projSpec := models.ProjectSpec{
+			Name: "project-name",
+		}
+		jobSpecMap := map[string]models.JobSpec{
+			jobSpec.Name:  jobSpec,
+			jobSpec2.Name: jobSpec2,
+		}
+		newPlan := func() *models.ReplayPlan {
+			plan := &models.ReplayPlan{Project: projSpec}
+			for _, spec := range []models.JobSpec{jobSpec, jobSpec2} {
+				plan.Replays = append(plan.Replays, &models.ReplayWorkerRequest{
+					Job:        spec,
+					Start:      startDate,
+					End:        endDate,
+					Project:    projSpec,
+					JobSpecMap: jobSpecMap,
+				})
+			}
+			return plan
+		}
+		planUUID := uuid.Must(uuid.NewRandom())
+		replayUUIDs := []uuid.UUID{uuid.Must(uuid.NewRandom()), uuid.Must(uuid.NewRandom())}
+		newUUIDProvider := func() *mock.UUIDProvider {
+			uuidProvider := new(mock.UUIDProvider)
+			uuidProvider.On("NewUUID").Return(planUUID, nil).Once()
+			uuidProvider.On("NewUUID").Return(replayUUIDs[0], nil).Once()
+			uuidProvider.On("NewUUID").Return(replayUUIDs[1], nil).Once()
+			return uuidProvider
+		}
+		acceptedReplay := func(idx int, spec models.JobSpec) *models.ReplaySpec {
+			return &models.ReplaySpec{
+				ID:        replayUUIDs[idx],
+				Job:       spec,
+				StartDate: startDate,
+				EndDate:   endDate,
+				Status:    models.ReplayStatusAccepted,
+				ParentID:  planUUID,
+			}
+		}
+		acceptedPlan := &models.ReplaySpec{
+			ID:        planUUID,
+			StartDate: startDate,
+			EndDate:   endDate,
+			Status:    models.ReplayStatusAccepted,
+		}
+		newScheduler := func() *mock.Scheduler {
+			scheduler := new(mock.Scheduler)
+			for _, spec := range []models.JobSpec{jobSpec, jobSpec2} {
+				scheduler.On("GetDagRunStatus", ctx, projSpec, spec.Name, startDate, reqBatchEndDate, reqBatchSize).Return([]models.JobStatus{}, nil)
+			}
+			return scheduler
+		}
+
+		t.Run("should accept replays of a plan under a single id holding locks of their jobs", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, store.ErrResourceNotFound)
+			replayRepository.On("Insert", acceptedPlan).Return(nil)
+			replayRepository.On("Insert", acceptedReplay(0, jobSpec)).Return(nil)
+			replayRepository.On("Insert", acceptedReplay(1, jobSpec2)).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec2).Return(replayRepository)
+
+			uuidProvider := newUUIDProvider()
+			defer uuidProvider.AssertExpectations(t)
+			scheduler := newScheduler()
+			defer scheduler.AssertExpectations(t)
+
+			// job-name-2 is downstream of job-name, jobs of both trees are locked once
+			lockRepo := new(mock.JobLockRepository)
+			defer lockRepo.AssertExpectations(t)
+			lockRepo.On("Acquire", mock2.MatchedBy(func(lock models.JobLock) bool {
+				return lock.Project == projSpec.Name && lock.Operation == job.JobOperationReplay && lock.HolderID == planUUID.String()
+			}), []string{jobSpec.Name, jobSpec2.Name}, job.JobLockTTL).Return((*models.JobLock)(nil), nil)
+
+			// no worker picks up requests
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, job.ReplayManagerConfig{QueueSize: 1}, scheduler, nil, job.NewJobLocker(lockRepo))
+			plan := newPlan()
+			planID, err := replayManager.ReplayPlan(ctx, plan)
+			assert.Nil(t, err)
+			assert.Equal(t, planUUID.String(), planID)
+			for idx, reqInput := range plan.Replays {
+				assert.Equal(t, replayUUIDs[idx], reqInput.ID)
+				assert.Equal(t, planUUID, reqInput.ParentID)
+			}
+
+			position, ok := replayManager.QueuePosition(replayUUIDs[1])
+			assert.True(t, ok)
+			assert.Equal(t, models.ReplayQueuePosition{Position: 1}, position)
+			lockRepo.AssertNotCalled(t, "Release", mock2.Anything)
+		})
+		t.Run("should cancel replays of a plan if request queue is full", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, store.ErrResourceNotFound)
+			replayRepository.On("Insert", acceptedPlan).Return(nil)
+			replayRepository.On("Insert", acceptedReplay(0, jobSpec)).Return(nil)
+			replayRepository.On("Insert", acceptedReplay(1, jobSpec2)).Return(nil)
+			queueFullMessage := models.ReplayMessage{
+				Type:    job.ErrRequestQueueFull.Error(),
+				Message: "request could not be queued",
+			}
+			replayRepository.On("UpdateStatus", planUUID, models.ReplayStatusCancelled, queueFullMessage).Return(nil)
+			replayRepository.On("UpdateStatus", replayUUIDs[0], models.ReplayStatusCancelled, queueFullMessage).Return(nil)
+			replayRepository.On("UpdateStatus", replayUUIDs[1], models.ReplayStatusCancelled, queueFullMessage).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec2).Return(replayRepository)
+
+			uuidProvider := newUUIDProvider()
+			defer uuidProvider.AssertExpectations(t)
+			scheduler := newScheduler()
+			defer scheduler.AssertExpectations(t)
+
+			// no worker picks up requests
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, job.ReplayManagerConfig{}, scheduler, nil, nil)
+			_, err := replayManager.ReplayPlan(ctx, newPlan())
+			assert.Equal(t, job.ErrRequestQueueFull, err)
+		})
+		t.Run("should cancel replays after a failed one in a plan", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, store.ErrResourceNotFound)
+			replayRepository.On("Insert", acceptedPlan).Return(nil)
+			replayRepository.On("Insert", acceptedReplay(0, jobSpec)).Return(nil)
+			replayRepository.On("Insert", acceptedReplay(1, jobSpec2)).Return(nil)
+			replayRepository.On("UpdateStatus", planUUID, models.ReplayStatusInProgress, models.ReplayMessage{}).Return(nil)
+			upstreamFailedMessage := models.ReplayMessage{
+				Type:    job.ReplayUpstreamFailed,
+				Message: fmt.Sprintf("replay %s of %s in the same plan failed", replayUUIDs[0], jobSpec.Name),
+			}
+			replayRepository.On("UpdateStatus", replayUUIDs[1], models.ReplayStatusCancelled, upstreamFailedMessage).Return(nil)
+			failed := *acceptedReplay(0, jobSpec)
+			failed.Status = models.ReplayStatusFailed
+			cancelled := *acceptedReplay(1, jobSpec2)
+			cancelled.Status = models.ReplayStatusCancelled
+			replayRepository.On("GetByParentID", planUUID).Return([]models.ReplaySpec{failed, cancelled}, nil)
+			replayRepository.On("UpdateStatus", planUUID, models.ReplayStatusFailed, models.ReplayMessage{
+				Type:    "replay plan failed",
+				Message: "0 of 2 replays of the plan succeeded",
+			}).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec2).Return(replayRepository)
+
+			uuidProvider := newUUIDProvider()
+			defer uuidProvider.AssertExpectations(t)
+			scheduler := newScheduler()
+			defer scheduler.AssertExpectations(t)
+
+			worker := new(mock.ReplayWorker)
+			defer worker.AssertExpectations(t)
+			worker.On("Process", mock2.Anything, mock2.MatchedBy(func(req *models.ReplayWorkerRequest) bool {
+				return req.ID == replayUUIDs[0]
+			})).Return(errors.New("failed to clear runs"))
+
+			replayManager := job.NewManager(worker, replaySpecRepoFac, uuidProvider,
+				job.ReplayManagerConfig{NumWorkers: 1, QueueSize: 1, WorkerTimeout: time.Minute}, scheduler, nil, nil)
+			_, err := replayManager.ReplayPlan(ctx, newPlan())
+			assert.Nil(t, err)
+			// waits for the plan to be processed
+			assert.Nil(t, replayManager.Close())
+		})
+	})
+	t.Run("ReplayOfMoreThanOneJob", func(t *testing.T) {
+		dagStartTime, _ := time.Parse(job.ReplayDateFormat, "2020-04-05")
+		startDate, _ := time.Parse(job.ReplayDateFormat, "2020-08-22")
+		endDate, _ := time.Parse(job.ReplayDateFormat, "2020-08-26")
+		reqBatchEndDate := endDate.AddDate(0, 0, 1)
+		schedule := models.JobSpecSchedule{
+			StartDate: dagStartTime,
+			Interval:  "0 2 * * *",
+		}
+		jobSpec := models.JobSpec{
+			ID:       uuid.Must(uuid.NewRandom()),
+			Name:     "job-name",
+			Schedule: schedule,
+		}
+		jobSpec2 := models.JobSpec{
+			ID:       uuid.Must(uuid.NewRandom()),
+			Name:     "job-name-2",
+			Schedule: schedule,
+			Dependencies: map[string]models.JobSpecDependency{
+				jobSpec.Name: {Job: &jobSpec, Type: models.JobSpecDependencyTypeIntra},
+			},
+		}
+		projSpec := models.ProjectSpec{
+			Name: "project-name",
+		}
+		replayUUID := uuid.Must(uuid.NewRandom())
+		jobReplayUUIDs := []uuid.UUID{uuid.Must(uuid.NewRandom()), uuid.Must(uuid.NewRandom())}
+		jobReplay := func(idx int, spec models.JobSpec) models.ReplaySpec {
+			return models.ReplaySpec{
+				ID:        jobReplayUUIDs[idx],
+				Job:       spec,
+				StartDate: startDate,
+				EndDate:   endDate,
+				Status:    models.ReplayStatusAccepted,
+				ParentID:  replayUUID,
+			}
+		}
+
+		t.Run("should track each job of the replay by a replay of its own", func(t *testing.T) {
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("GetByStatus", job.ReplayStatusToValidate).Return([]models.ReplaySpec{}, store.ErrResourceNotFound)
+			replayRepository.On("Insert", &models.ReplaySpec{
+				ID:        replayUUID,
+				Job:       jobSpec,
+				StartDate: startDate,
+				EndDate:   endDate,
+				Status:    models.ReplayStatusAccepted,
+			}).Return(nil)
+			upstreamReplay, downstreamReplay := jobReplay(0, jobSpec), jobReplay(1, jobSpec2)
+			replayRepository.On("Insert", &upstreamReplay).Return(nil)
+			replayRepository.On("Insert", &downstreamReplay).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", models.JobSpec{}).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec2).Return(replayRepository)
+
+			uuidProvider := new(mock.UUIDProvider)
+			defer uuidProvider.AssertExpectations(t)
+			uuidProvider.On("NewUUID").Return(replayUUID, nil).Once()
+			uuidProvider.On("NewUUID").Return(jobReplayUUIDs[0], nil).Once()
+			uuidProvider.On("NewUUID").Return(jobReplayUUIDs[1], nil).Once()
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("GetDagRunStatus", ctx, projSpec, jobSpec.Name, startDate, reqBatchEndDate, 100).Return([]models.JobStatus{}, nil)
+
+			// no worker picks up requests
+			replayManager := job.NewManager(nil, replaySpecRepoFac, uuidProvider, job.ReplayManagerConfig{QueueSize: 1}, scheduler, nil, nil)
+			replayID, err := replayManager.Replay(ctx, &models.ReplayWorkerRequest{
+				Job:     jobSpec,
+				Start:   startDate,
+				End:     endDate,
+				Project: projSpec,
+				JobSpecMap: map[string]models.JobSpec{
+					jobSpec.Name:  jobSpec,
+					jobSpec2.Name: jobSpec2,
+				},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, replayUUID.String(), replayID)
+		})
+		t.Run("should cancel a replay along with its jobs", func(t *testing.T) {
+			replay := models.ReplaySpec{
+				ID:        replayUUID,
+				Job:       jobSpec,
+				StartDate: startDate,
+				EndDate:   endDate,
+				Status:    models.ReplayStatusInProgress,
+			}
+			userCancelled := models.ReplayMessage{
+				Type:    job.ReplayCancelled,
+				Message: "replay was cancelled",
+			}
+			parentCancelled := models.ReplayMessage{
+				Type:    job.ReplayCancelled,
+				Message: fmt.Sprintf("parent replay %s was cancelled", replayUUID),
+			}
+			succeeded := jobReplay(0, jobSpec)
+			succeeded.Status = models.ReplayStatusSuccess
+
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("UpdateStatus", replayUUID, models.ReplayStatusCancelled, userCancelled).Return(nil)
+			replayRepository.On("GetByParentID", replayUUID).Return([]models.ReplaySpec{succeeded, jobReplay(1, jobSpec2)}, nil)
+			replayRepository.On("UpdateStatus", jobReplayUUIDs[0], models.ReplayStatusCancelled, parentCancelled).
+				Return(fmt.Errorf("%w: success to cancelled", models.ErrInvalidReplayTransition))
+			replayRepository.On("UpdateStatus", jobReplayUUIDs[1], models.ReplayStatusCancelled, parentCancelled).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", jobSpec).Return(replayRepository)
+			replaySpecRepoFac.On("New", jobSpec2).Return(replayRepository)
+
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, job.ReplayManagerConfig{}, nil, nil, nil)
+			assert.Nil(t, replayManager.Cancel(ctx, projSpec, replay))
+		})
+		t.Run("should not cancel an ended replay", func(t *testing.T) {
+			replay := models.ReplaySpec{ID: replayUUID, Job: jobSpec, Status: models.ReplayStatusSuccess}
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("UpdateStatus", replayUUID, models.ReplayStatusCancelled, mock2.Anything).
+				Return(fmt.Errorf("%w: success to cancelled", models.ErrInvalidReplayTransition))
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			replaySpecRepoFac.On("New", jobSpec).Return(replayRepository)
+
+			replayManager := job.NewManager(nil, replaySpecRepoFac, nil, job.ReplayManagerConfig{}, nil, nil, nil)
+			err := replayManager.Cancel(ctx, projSpec, replay)
+			assert.True(t, errors.Is(err, models.ErrInvalidReplayTransition))
+		})
+	})
 }
@@ -0,0 +1,34 @@
+package job
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// notifyReplay registers status change of a replay as an event of the replayed
+// job, replays are not tied to a namespace so only the project is known. Failing
+// to notify doesn't fail the replay, it's only logged
+func notifyReplay(ctx context.Context, eventSvc EventRegistrar, projSpec models.ProjectSpec, replay models.ReplaySpec) {
+	if eventSvc == nil {
+		return
+	}
+	value := map[string]*structpb.Value{
+		"replay_id":  structpb.NewStringValue(replay.ID.String()),
+		"status":     structpb.NewStringValue(replay.Status),
+		"start_date": structpb.NewStringValue(replay.StartDate.Format(ReplayDateFormat)),
+		"end_date":   structpb.NewStringValue(replay.EndDate.Format(ReplayDateFormat)),
+	}
+	if replay.Message.Message != "" {
+		value["message"] = structpb.NewStringValue(replay.Message.Message)
+	}
+	if err := eventSvc.Register(ctx, models.NamespaceSpec{ProjectSpec: projSpec}, replay.Job, models.JobEvent{
+		Type:  models.JobEventTypeReplay,
+		Value: value,
+	}); err != nil {
+		logger.E(errors.Wrapf(err, "failed to notify replay %s", replay.ID.String()))
+	}
+}
@@ -0,0 +1,103 @@
+package job
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidReplayPreset signifies a replay preset of job with a malformed window
+	ErrInvalidReplayPreset = errors.New("invalid replay preset")
+)
+
+// ReplayChunk is a part of a replay window replayed on its own, boundaries
+// are the same as of a replay request
+type ReplayChunk struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (c ReplayChunk) String() string {
+	return fmt.Sprintf("%s/%s", FormatReplayDate(c.Start), FormatReplayDate(c.End))
+}
+
+// ParseReplayDate parses a replay boundary, a date or the time of a run
+func ParseReplayDate(value string) (time.Time, error) {
+	if date, err := time.Parse(ReplayDateFormat, value); err == nil {
+		return date, nil
+	}
+	runTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return runTime.UTC(), nil
+}
+
+// FormatReplayDate formats a replay boundary the way ParseReplayDate reads
+// it back, a date at midnight and the time of a run otherwise
+func FormatReplayDate(t time.Time) string {
+	if isReplayDate(t) {
+		return t.Format(ReplayDateFormat)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ResolveReplayPreset resolves the window of a replay preset of job into the
+// start and the end of a replay. A start expressed in runs is resolved like
+// ResolveReplayRuns, empty start is the start of job and empty end its
+// latest run triggered by now
+func ResolveReplayPreset(preset models.JobSpecReplayPreset, jobSpec models.JobSpec, projSpec models.ProjectSpec,
+	instSvc models.InstanceService, now time.Time) (time.Time, time.Time, error) {
+	if IsReplayRuns(preset.Start) {
+		if preset.End != "" {
+			return time.Time{}, time.Time{}, errors.Wrapf(ErrInvalidReplayPreset, "end can't be set with start %s", preset.Start)
+		}
+		return ResolveReplayRuns(preset.Start, jobSpec, projSpec, instSvc, now)
+	}
+
+	var (
+		start = jobSpec.Schedule.StartDate
+		end   time.Time
+		err   error
+	)
+	if preset.Start != "" {
+		if start, err = ParseReplayDate(preset.Start); err != nil {
+			return time.Time{}, time.Time{}, errors.Wrapf(ErrInvalidReplayPreset, "start %s: %v", preset.Start, err)
+		}
+	}
+	if preset.End != "" {
+		if end, err = ParseReplayDate(preset.End); err != nil {
+			return time.Time{}, time.Time{}, errors.Wrapf(ErrInvalidReplayPreset, "end %s: %v", preset.End, err)
+		}
+	} else if _, end, err = ResolveReplayRuns(ReplayLastRunsPrefix+"1", jobSpec, projSpec, instSvc, now); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, errors.Wrapf(ErrNoRunsToReplay, "%s is before start %s", FormatReplayDate(end),
+			FormatReplayDate(start))
+	}
+	return start, end, nil
+}
+
+// SplitReplayWindow splits the window of a replay into chunks of at most
+// chunkDays days(UTC), earliest first. Chunks end with a whole day and the
+// next one starts with the following day, the first and the last keep the
+// boundaries of window. The window is a single chunk without chunkDays
+func SplitReplayWindow(start, end time.Time, chunkDays int) []ReplayChunk {
+	if chunkDays <= 0 {
+		return []ReplayChunk{{Start: start, End: end}}
+	}
+	lastDay := end.Truncate(24 * time.Hour)
+	var chunks []ReplayChunk
+	for chunkStart := start; ; {
+		chunkEnd := chunkStart.Truncate(24*time.Hour).AddDate(0, 0, chunkDays-1)
+		if !chunkEnd.Before(lastDay) {
+			return append(chunks, ReplayChunk{Start: chunkStart, End: end})
+		}
+		chunks = append(chunks, ReplayChunk{Start: chunkStart, End: chunkEnd})
+		chunkStart = chunkEnd.AddDate(0, 0, 1)
+	}
+}
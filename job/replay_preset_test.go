@@ -0,0 +1,76 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveReplayPreset(t *testing.T) {
+	now := time.Date(2021, 5, 20, 10, 30, 0, 0, time.UTC)
+	projSpec := models.ProjectSpec{Name: "proj"}
+	jobSpec := models.JobSpec{
+		Name: "job-a",
+		Schedule: models.JobSpecSchedule{
+			StartDate: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+			Interval:  "0 2 * * *",
+		},
+	}
+
+	t.Run("should replay from start of job till its latest run by default", func(t *testing.T) {
+		start, end, err := job.ResolveReplayPreset(models.JobSpecReplayPreset{}, jobSpec, projSpec, nil, now)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), end)
+
+		start, end, err = job.ResolveReplayPreset(models.JobSpecReplayPreset{Start: "2021-05-10"}, jobSpec, projSpec, nil, now)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), end)
+	})
+	t.Run("should resolve start expressed in runs", func(t *testing.T) {
+		start, end, err := job.ResolveReplayPreset(models.JobSpecReplayPreset{Start: "last:2"}, jobSpec, projSpec, nil, now)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 5, 18, 2, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), end)
+	})
+	t.Run("should fail on malformed windows", func(t *testing.T) {
+		for _, preset := range []models.JobSpecReplayPreset{
+			{Start: "last:2", End: "2021-05-19"},
+			{Start: "05/10"},
+			{End: "yesterday"},
+		} {
+			_, _, err := job.ResolveReplayPreset(preset, jobSpec, projSpec, nil, now)
+			assert.True(t, errors.Is(err, job.ErrInvalidReplayPreset), preset)
+		}
+		_, _, err := job.ResolveReplayPreset(models.JobSpecReplayPreset{Start: "2021-05-10", End: "2021-05-09"},
+			jobSpec, projSpec, nil, now)
+		assert.True(t, errors.Is(err, job.ErrNoRunsToReplay))
+	})
+}
+
+func TestSplitReplayWindow(t *testing.T) {
+	t.Run("should split window into chunks of whole days keeping its boundaries", func(t *testing.T) {
+		chunks := job.SplitReplayWindow(time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), 7)
+		var formatted []string
+		for _, chunk := range chunks {
+			formatted = append(formatted, chunk.String())
+		}
+		assert.Equal(t, []string{
+			"2021-05-01/2021-05-07",
+			"2021-05-08/2021-05-14",
+			"2021-05-15/2021-05-19T02:00:00Z",
+		}, formatted)
+	})
+	t.Run("should keep window shorter than a chunk whole", func(t *testing.T) {
+		start := time.Date(2021, 5, 3, 2, 0, 0, 0, time.UTC)
+		end := time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, []job.ReplayChunk{{Start: start, End: end}}, job.SplitReplayWindow(start, end, 7))
+		assert.Equal(t, []job.ReplayChunk{{Start: start, End: end}}, job.SplitReplayWindow(start, end, 0))
+	})
+}
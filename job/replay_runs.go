@@ -0,0 +1,123 @@
+package job
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ReplayLastRunsPrefix followed by a count as start of a replay replays
+	// that many latest runs of the job, e.g. last:3
+	ReplayLastRunsPrefix = "last:"
+
+	// ReplaySinceLastSuccess as start of a replay replays the runs of the job
+	// after its latest successful one
+	ReplaySinceLastSuccess = "since-last-success"
+
+	// runs replayed by count are capped at this, larger replays are better
+	// expressed in dates
+	maxReplayLastRuns = 1000
+)
+
+var (
+	// ErrInvalidReplayRuns signifies a malformed replay window expressed in runs
+	ErrInvalidReplayRuns = errors.New("invalid replay runs")
+
+	// ErrNoRunsToReplay signifies that a replay window expressed in runs
+	// resolved to no run of the job
+	ErrNoRunsToReplay = errors.New("no runs to replay")
+)
+
+// IsReplayRuns tells if start of a replay is expressed in runs of the job
+// instead of a date
+func IsReplayRuns(value string) bool {
+	return value == ReplaySinceLastSuccess || strings.HasPrefix(value, ReplayLastRunsPrefix)
+}
+
+// ResolveReplayRuns resolves start of a replay expressed in runs against the
+// schedule and run history of job, returning the first and the last run to
+// replay. Only runs scheduler triggered by now count, the ones whose interval
+// ended, schedule exceptions are honored. Like any replay boundary, a last run
+// at midnight replays its whole day
+func ResolveReplayRuns(value string, jobSpec models.JobSpec, projSpec models.ProjectSpec,
+	instSvc models.InstanceService, now time.Time) (time.Time, time.Time, error) {
+	schd, err := cron.ParseCronSchedule(jobSpec.Schedule.Interval)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrapf(err, "invalid interval of %s", jobSpec.Name)
+	}
+	until := now.UTC()
+	if end := jobSpec.Schedule.EndDate; end != nil && end.Before(until) {
+		until = *end
+	}
+	// a run is triggered once the next one is due, runs before the latest
+	// due one are the triggered ones
+	triggeredBefore := lastRunBefore(schd, jobSpec.Schedule.StartDate, until)
+	if triggeredBefore.IsZero() {
+		return time.Time{}, time.Time{}, errors.Wrapf(ErrNoRunsToReplay, "%s has not run yet", jobSpec.Name)
+	}
+
+	var runs []time.Time
+	switch {
+	case value == ReplaySinceLastSuccess:
+		lastSuccess, ok, err := instSvc.GetLastSuccess(jobSpec)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if !ok {
+			return time.Time{}, time.Time{}, errors.Wrapf(ErrNoRunsToReplay, "no successful run of %s is known, replay dates instead",
+				jobSpec.Name)
+		}
+		// instances are scheduled at the end of interval of their run, runs
+		// after the successful one start from there
+		if runs, err = getJobRunsBetweenDates(lastSuccess, triggeredBefore, jobSpec, projSpec); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if len(runs) == 0 {
+			return time.Time{}, time.Time{}, errors.Wrapf(ErrNoRunsToReplay, "latest run of %s succeeded", jobSpec.Name)
+		}
+	case strings.HasPrefix(value, ReplayLastRunsPrefix):
+		count, err := strconv.Atoi(strings.TrimPrefix(value, ReplayLastRunsPrefix))
+		if err != nil || count <= 0 || count > maxReplayLastRuns {
+			return time.Time{}, time.Time{}, errors.Wrapf(ErrInvalidReplayRuns, "%s needs a count of runs between 1 and %d",
+				value, maxReplayLastRuns)
+		}
+		if runs, err = lastJobRuns(jobSpec, projSpec, triggeredBefore, count); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if len(runs) == 0 {
+			return time.Time{}, time.Time{}, errors.Wrapf(ErrNoRunsToReplay, "%s has not run yet", jobSpec.Name)
+		}
+	default:
+		return time.Time{}, time.Time{}, errors.Wrapf(ErrInvalidReplayRuns, "%s is neither %sN nor %s", value,
+			ReplayLastRunsPrefix, ReplaySinceLastSuccess)
+	}
+	return runs[0], runs[len(runs)-1], nil
+}
+
+// lastJobRuns returns at most count latest runs of job before end, looked for
+// in a window growing back from end like lastRunBefore
+func lastJobRuns(jobSpec models.JobSpec, projSpec models.ProjectSpec, end time.Time, count int) ([]time.Time, error) {
+	start := jobSpec.Schedule.StartDate
+	for lookback := time.Hour; lookback <= maxRunLookback; lookback *= 2 {
+		from := end.Add(-lookback)
+		if from.Before(start) {
+			from = start
+		}
+		runs, err := getJobRunsBetweenDates(from, end, jobSpec, projSpec)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) >= count {
+			return runs[len(runs)-count:], nil
+		}
+		if !from.After(start) {
+			return runs, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,83 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveReplayRuns(t *testing.T) {
+	now := time.Date(2021, 5, 20, 10, 30, 0, 0, time.UTC)
+	projSpec := models.ProjectSpec{Name: "proj"}
+	jobSpec := models.JobSpec{
+		Name: "job-a",
+		Schedule: models.JobSpecSchedule{
+			StartDate: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+			Interval:  "0 2 * * *",
+		},
+	}
+
+	t.Run("should resolve the last runs triggered by now", func(t *testing.T) {
+		start, end, err := job.ResolveReplayRuns("last:3", jobSpec, projSpec, nil, now)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 5, 17, 2, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), end)
+	})
+	t.Run("should resolve no further back than start of job", func(t *testing.T) {
+		start, end, err := job.ResolveReplayRuns("last:100", jobSpec, projSpec, nil, now)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 5, 1, 2, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), end)
+	})
+	t.Run("should skip runs on skip days of job", func(t *testing.T) {
+		skippingJobSpec := jobSpec
+		skippingJobSpec.Schedule.Exceptions = models.JobSpecScheduleExceptions{
+			SkipDates: []time.Time{time.Date(2021, 5, 18, 0, 0, 0, 0, time.UTC)},
+		}
+		start, end, err := job.ResolveReplayRuns("last:2", skippingJobSpec, projSpec, nil, now)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 5, 17, 2, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), end)
+	})
+	t.Run("should resolve runs after the last successful one", func(t *testing.T) {
+		instSvc := new(mock.InstanceService)
+		// run of 2021-05-15 succeeded, its instance is scheduled at the end of its interval
+		instSvc.On("GetLastSuccess", jobSpec).Return(time.Date(2021, 5, 16, 2, 0, 0, 0, time.UTC), true, nil)
+		defer instSvc.AssertExpectations(t)
+
+		start, end, err := job.ResolveReplayRuns(job.ReplaySinceLastSuccess, jobSpec, projSpec, instSvc, now)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 5, 16, 2, 0, 0, 0, time.UTC), start)
+		assert.Equal(t, time.Date(2021, 5, 19, 2, 0, 0, 0, time.UTC), end)
+	})
+	t.Run("should fail if nothing is there to replay", func(t *testing.T) {
+		instSvc := new(mock.InstanceService)
+		instSvc.On("GetLastSuccess", jobSpec).Return(time.Date(2021, 5, 20, 2, 0, 0, 0, time.UTC), true, nil).Once()
+		instSvc.On("GetLastSuccess", jobSpec).Return(time.Time{}, false, nil).Once()
+		defer instSvc.AssertExpectations(t)
+
+		_, _, err := job.ResolveReplayRuns(job.ReplaySinceLastSuccess, jobSpec, projSpec, instSvc, now)
+		assert.True(t, errors.Is(err, job.ErrNoRunsToReplay))
+		_, _, err = job.ResolveReplayRuns(job.ReplaySinceLastSuccess, jobSpec, projSpec, instSvc, now)
+		assert.True(t, errors.Is(err, job.ErrNoRunsToReplay))
+
+		notStartedJobSpec := jobSpec
+		notStartedJobSpec.Schedule.StartDate = now.AddDate(0, 0, 1)
+		_, _, err = job.ResolveReplayRuns("last:1", notStartedJobSpec, projSpec, nil, now)
+		assert.True(t, errors.Is(err, job.ErrNoRunsToReplay))
+	})
+	t.Run("should fail on malformed runs", func(t *testing.T) {
+		for _, value := range []string{"last:", "last:0", "last:x", "last:5000", "since-last"} {
+			_, _, err := job.ResolveReplayRuns(value, jobSpec, projSpec, nil, now)
+			assert.True(t, errors.Is(err, job.ErrInvalidReplayRuns), value)
+		}
+		assert.True(t, job.IsReplayRuns("last:3"))
+		assert.True(t, job.IsReplayRuns(job.ReplaySinceLastSuccess))
+		assert.False(t, job.IsReplayRuns("2021-05-01"))
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
 	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
 )
 
 func getRuns(root *tree.TreeNode, countMap map[string][]time.Time) {
@@ -39,7 +40,7 @@ func getRuns(root *tree.TreeNode, countMap map[string][]time.Time) {
 func TestReplay(t *testing.T) {
 	ctx := context.TODO()
 	noDependency := map[string]models.JobSpecDependency{}
-	dumpAssets := func(jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
+	dumpAssets := func(_ models.ProjectSpec, jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error) {
 		return jobSpec.Assets, nil
 	}
 	var (
@@ -107,7 +108,7 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil)
 			replayRequest := &models.ReplayWorkerRequest{
 				Job:     specs[spec1],
 				Start:   replayStart,
@@ -141,7 +142,7 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
 			replayRequest := &models.ReplayWorkerRequest{
 				Job:     specs[spec1],
 				Start:   replayStart,
@@ -184,7 +185,7 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
 			replayRequest := &models.ReplayWorkerRequest{
 				Job:     cyclicDagSpec[0],
 				Start:   replayStart,
@@ -219,7 +220,7 @@ func TestReplay(t *testing.T) {
 			compiler := new(mock.Compiler)
 			defer compiler.AssertExpectations(t)
 
-			jobSvc := job.NewService(nil, nil, compiler, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, compiler, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 			replayRequest := &models.ReplayWorkerRequest{
@@ -271,7 +272,7 @@ func TestReplay(t *testing.T) {
 			compiler := new(mock.Compiler)
 			defer compiler.AssertExpectations(t)
 
-			jobSvc := job.NewService(nil, nil, compiler, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, compiler, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayRequest := &models.ReplayWorkerRequest{
@@ -302,6 +303,200 @@ func TestReplay(t *testing.T) {
 				assert.Equal(t, expectedRunMap[k], v)
 			}
 		})
+
+		t.Run("resolve create replay tree with only dependents matching label selector", func(t *testing.T) {
+			labelledSpecs := []models.JobSpec{specs[spec1], specs[spec2], specs[spec3]}
+			labelledSpecs[1].Labels = map[string]string{"tier": "critical"}
+			labelledSpecs[2].Labels = map[string]string{"tier": "low"}
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(labelledSpecs, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			for _, spec := range labelledSpecs {
+				depenResolver.On("Resolve", projSpec, projectJobSpecRepo, spec, nil).Return(spec, nil)
+			}
+			defer depenResolver.AssertExpectations(t)
+
+			selector, err := models.ParseLabelSelector("tier=critical")
+			assert.Nil(t, err)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
+			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
+			tree, err := jobSvc.ReplayDryRun(&models.ReplayWorkerRequest{
+				Job:      specs[spec1],
+				Start:    replayStart,
+				End:      replayStart,
+				Project:  projSpec,
+				Selector: selector,
+			})
+
+			assert.Nil(t, err)
+			countMap := make(map[string][]time.Time)
+			getRuns(tree, countMap)
+			assert.Len(t, countMap, 2)
+			assert.Contains(t, countMap, spec1)
+			assert.Contains(t, countMap, spec2)
+		})
+
+		t.Run("resolve create replay tree honoring schedule exceptions of dag", func(t *testing.T) {
+			calendarProjSpec := models.ProjectSpec{
+				Name: "proj",
+				Config: map[string]string{
+					models.ProjectCalendarKeyPrefix + "HOLIDAYS": "2020-08-07",
+				},
+			}
+			exceptionSchedule := twoAMSchedule
+			exceptionSchedule.Exceptions = models.JobSpecScheduleExceptions{
+				SkipDates: []time.Time{time.Date(2020, time.Month(8), 6, 0, 0, 0, 0, time.UTC)},
+				ExtraRuns: []time.Time{
+					time.Date(2020, time.Month(8), 6, 14, 0, 0, 0, time.UTC),
+					time.Date(2020, time.Month(8), 12, 14, 0, 0, 0, time.UTC),
+				},
+				Calendars: []string{"holidays"},
+			}
+			exceptionSpec := models.JobSpec{Name: "dag-with-exceptions", Dependencies: noDependency,
+				Schedule: exceptionSchedule, Task: oneDayTaskWindow}
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{exceptionSpec}, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", calendarProjSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", calendarProjSpec, projectJobSpecRepo, exceptionSpec, nil).Return(exceptionSpec, nil)
+			defer depenResolver.AssertExpectations(t)
+
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
+			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
+			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-08")
+			replayRequest := &models.ReplayWorkerRequest{
+				Job:     exceptionSpec,
+				Start:   replayStart,
+				End:     replayEnd,
+				Project: calendarProjSpec,
+			}
+
+			tree, err := jobSvc.ReplayDryRun(replayRequest)
+
+			assert.Nil(t, err)
+			countMap := make(map[string][]time.Time)
+			getRuns(tree, countMap)
+			assert.Equal(t, []time.Time{
+				time.Date(2020, time.Month(8), 5, 2, 0, 0, 0, time.UTC),
+				time.Date(2020, time.Month(8), 6, 14, 0, 0, 0, time.UTC),
+				time.Date(2020, time.Month(8), 8, 2, 0, 0, 0, time.UTC),
+			}, countMap[exceptionSpec.Name])
+		})
+
+		t.Run("resolve create replay tree for a sub hourly dag and its hourly dependent", func(t *testing.T) {
+			subHourlySpecs := map[string]models.JobSpec{}
+			subHourlySpecs["dag-every-15-min"] = models.JobSpec{Name: "dag-every-15-min", Dependencies: noDependency,
+				Schedule: models.JobSpecSchedule{StartDate: dagStartTime, Interval: "*/15 * * * *"},
+				Task:     models.JobSpecTask{Window: models.JobSpecTaskWindow{Size: 15 * time.Minute, TruncateTo: "m"}}}
+			subHourlySpecs["dag-hourly"] = models.JobSpec{Name: "dag-hourly", Dependencies: getDependencyObject(subHourlySpecs, "dag-every-15-min"),
+				Schedule: hourlySchedule,
+				Task:     models.JobSpecTask{Window: models.JobSpecTaskWindow{Size: time.Hour, TruncateTo: "h"}}}
+			subHourlyDagSpecs := []models.JobSpec{subHourlySpecs["dag-every-15-min"], subHourlySpecs["dag-hourly"]}
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(subHourlyDagSpecs, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, subHourlyDagSpecs[0], nil).Return(subHourlyDagSpecs[0], nil)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, subHourlyDagSpecs[1], nil).Return(subHourlyDagSpecs[1], nil)
+			defer depenResolver.AssertExpectations(t)
+
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
+			replayRequest := &models.ReplayWorkerRequest{
+				Job:     subHourlySpecs["dag-every-15-min"],
+				Start:   time.Date(2020, time.Month(8), 5, 10, 0, 0, 0, time.UTC),
+				End:     time.Date(2020, time.Month(8), 5, 11, 0, 0, 0, time.UTC),
+				Project: projSpec,
+			}
+
+			tree, err := jobSvc.ReplayDryRun(replayRequest)
+
+			assert.Nil(t, err)
+			countMap := make(map[string][]time.Time)
+			getRuns(tree, countMap)
+			assert.Equal(t, []time.Time{
+				time.Date(2020, time.Month(8), 5, 10, 0, 0, 0, time.UTC),
+				time.Date(2020, time.Month(8), 5, 10, 15, 0, 0, time.UTC),
+				time.Date(2020, time.Month(8), 5, 10, 30, 0, 0, time.UTC),
+				time.Date(2020, time.Month(8), 5, 10, 45, 0, 0, time.UTC),
+				time.Date(2020, time.Month(8), 5, 11, 0, 0, 0, time.UTC),
+			}, countMap["dag-every-15-min"])
+			assert.Equal(t, []time.Time{
+				time.Date(2020, time.Month(8), 5, 10, 0, 0, 0, time.UTC),
+				time.Date(2020, time.Month(8), 5, 11, 0, 0, 0, time.UTC),
+			}, countMap["dag-hourly"])
+		})
+
+		t.Run("resolve create replay tree aligning replay window with schedule interval of dag", func(t *testing.T) {
+			weeklySpec := models.JobSpec{Name: "dag-weekly", Dependencies: noDependency, Task: oneDayTaskWindow,
+				Schedule: models.JobSpecSchedule{StartDate: dagStartTime, Interval: "0 2 * * 1"}}
+			setup := func() *job.Service {
+				projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+				projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{weeklySpec}, nil)
+				projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+				projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+				depenResolver := new(mock.DependencyResolver)
+				depenResolver.On("Resolve", projSpec, projectJobSpecRepo, weeklySpec, nil).Return(weeklySpec, nil)
+				return job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
+			}
+
+			t.Run("should replay runs between scheduled times inclusively", func(t *testing.T) {
+				tree, err := setup().ReplayDryRun(&models.ReplayWorkerRequest{
+					Job:     weeklySpec,
+					Start:   time.Date(2020, time.Month(8), 3, 2, 0, 0, 0, time.UTC),
+					End:     time.Date(2020, time.Month(8), 10, 2, 0, 0, 0, time.UTC),
+					Project: projSpec,
+				})
+
+				assert.Nil(t, err)
+				countMap := make(map[string][]time.Time)
+				getRuns(tree, countMap)
+				assert.Equal(t, []time.Time{
+					time.Date(2020, time.Month(8), 3, 2, 0, 0, 0, time.UTC),
+					time.Date(2020, time.Month(8), 10, 2, 0, 0, 0, time.UTC),
+				}, countMap[weeklySpec.Name])
+			})
+			t.Run("should fail if a time boundary is not a scheduled run", func(t *testing.T) {
+				_, err := setup().ReplayDryRun(&models.ReplayWorkerRequest{
+					Job:     weeklySpec,
+					Start:   time.Date(2020, time.Month(8), 3, 3, 0, 0, 0, time.UTC),
+					End:     time.Date(2020, time.Month(8), 10, 2, 0, 0, 0, time.UTC),
+					Project: projSpec,
+				})
+
+				assert.True(t, errors.Is(err, job.ErrReplayWindowMisaligned))
+				assert.Contains(t, err.Error(), "2020-08-03T03:00:00Z is not a scheduled run, next run is at 2020-08-10T02:00:00Z")
+			})
+			t.Run("should fail if no run is scheduled between dates", func(t *testing.T) {
+				_, err := setup().ReplayDryRun(&models.ReplayWorkerRequest{
+					Job:     weeklySpec,
+					Start:   time.Date(2020, time.Month(8), 4, 0, 0, 0, 0, time.UTC),
+					End:     time.Date(2020, time.Month(8), 8, 0, 0, 0, 0, time.UTC),
+					Project: projSpec,
+				})
+
+				assert.True(t, errors.Is(err, job.ErrReplayWindowMisaligned))
+				assert.Contains(t, err.Error(), "dag-weekly has no runs scheduled between 2020-08-04T00:00:00Z and 2020-08-09T00:00:00Z")
+			})
+		})
 	})
 
 	t.Run("Replay", func(t *testing.T) {
@@ -317,7 +512,7 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil)
 			replayRequest := &models.ReplayWorkerRequest{
 				Job:     specs[spec1],
 				Start:   replayStart,
@@ -350,11 +545,10 @@ func TestReplay(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 			replayRequest := &models.ReplayWorkerRequest{
-				Job:        specs[spec1],
-				Start:      replayStart,
-				End:        replayEnd,
-				Project:    projSpec,
-				JobSpecMap: specs,
+				Job:     specs[spec1],
+				Start:   replayStart,
+				End:     replayEnd,
+				Project: projSpec,
 			}
 
 			errMessage := "error with replay manager"
@@ -362,7 +556,7 @@ func TestReplay(t *testing.T) {
 			replayManager.On("Replay", ctx, replayRequest).Return("", errors.New(errMessage))
 			defer replayManager.AssertExpectations(t)
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager, nil)
 
 			_, err := jobSvc.Replay(ctx, replayRequest)
 			assert.NotNil(t, err)
@@ -387,6 +581,29 @@ func TestReplay(t *testing.T) {
 			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, dagSpec[5], nil).Return(dagSpec[5], nil)
 			defer depenResolver.AssertExpectations(t)
 
+			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
+			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
+			replayRequest := &models.ReplayWorkerRequest{
+				Job:     specs[spec1],
+				Start:   replayStart,
+				End:     replayEnd,
+				Project: projSpec,
+			}
+
+			replayManager := new(mock.ReplayManager)
+			objUUID := uuid.Must(uuid.NewRandom())
+			replayManager.On("Replay", ctx, replayRequest).Return(objUUID.String(), nil)
+			defer replayManager.AssertExpectations(t)
+
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager, nil)
+
+			replayUUID, err := jobSvc.Replay(ctx, replayRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, objUUID.String(), replayUUID)
+			assert.Len(t, replayRequest.JobSpecMap, len(dagSpec))
+		})
+
+		t.Run("should reuse job specs resolved by dry run of the request", func(t *testing.T) {
 			replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
 			replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
 			replayRequest := &models.ReplayWorkerRequest{
@@ -402,11 +619,106 @@ func TestReplay(t *testing.T) {
 			replayManager.On("Replay", ctx, replayRequest).Return(objUUID.String(), nil)
 			defer replayManager.AssertExpectations(t)
 
-			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager)
+			// project job spec repo factory is nil, specs are not resolved again
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, replayManager, nil)
 
 			replayUUID, err := jobSvc.Replay(ctx, replayRequest)
 			assert.Nil(t, err)
 			assert.Equal(t, objUUID.String(), replayUUID)
 		})
 	})
+	t.Run("ReplayPlan", func(t *testing.T) {
+		revenueLabels := map[string]string{"pipeline": "revenue"}
+		var labeledSpecs []models.JobSpec
+		for _, spec := range dagSpec {
+			if spec.Name == spec1 || spec.Name == spec3 || spec.Name == spec5 {
+				spec.Labels = revenueLabels
+			}
+			labeledSpecs = append(labeledSpecs, spec)
+		}
+		selector, _ := models.ParseLabelSelector("pipeline=revenue")
+		replayStart, _ := time.Parse(job.ReplayDateFormat, "2020-08-05")
+		replayEnd, _ := time.Parse(job.ReplayDateFormat, "2020-08-07")
+		planRequest := models.ReplayPlanRequest{
+			Project:  projSpec,
+			Selector: selector,
+			Start:    replayStart,
+			End:      replayEnd,
+		}
+		newProjJobSpecRepoFac := func() (*mock.ProjectJobSpecRepoFactory, *mock.DependencyResolver) {
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(labeledSpecs, nil)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			depenResolver := new(mock.DependencyResolver)
+			for _, spec := range labeledSpecs {
+				depenResolver.On("Resolve", projSpec, projectJobSpecRepo, spec, nil).Return(spec, nil)
+			}
+			return projJobSpecRepoFac, depenResolver
+		}
+
+		t.Run("should return runs of jobs matching selector upstream jobs first", func(t *testing.T) {
+			projJobSpecRepoFac, depenResolver := newProjJobSpecRepoFac()
+			defer projJobSpecRepoFac.AssertExpectations(t)
+			defer depenResolver.AssertExpectations(t)
+
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
+			nodes, err := jobSvc.ReplayPlanDryRun(planRequest)
+			assert.Nil(t, err)
+
+			var names []string
+			for _, node := range nodes {
+				names = append(names, node.GetName())
+				assert.Equal(t, 3, node.Runs.Size())
+				assert.Empty(t, node.Dependents)
+			}
+			assert.Equal(t, []string{spec1, spec3, spec5}, names)
+		})
+		t.Run("should replay jobs matching selector under a single plan", func(t *testing.T) {
+			projJobSpecRepoFac, depenResolver := newProjJobSpecRepoFac()
+			defer projJobSpecRepoFac.AssertExpectations(t)
+			defer depenResolver.AssertExpectations(t)
+
+			planUUID := uuid.Must(uuid.NewRandom())
+			replayManager := new(mock.ReplayManager)
+			replayManager.On("ReplayPlan", ctx, mock2.Anything).Run(func(args mock2.Arguments) {
+				args.Get(1).(*models.ReplayPlan).ID = planUUID
+			}).Return(planUUID.String(), nil)
+			defer replayManager.AssertExpectations(t)
+
+			planRequest := planRequest
+			planRequest.Force = true
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, replayManager, nil)
+			plan, err := jobSvc.ReplayPlan(ctx, planRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, planUUID, plan.ID)
+
+			var names []string
+			for _, replayRequest := range plan.Replays {
+				names = append(names, replayRequest.Job.Name)
+				assert.Equal(t, replayStart, replayRequest.Start)
+				assert.Equal(t, replayEnd, replayRequest.End)
+				assert.True(t, replayRequest.Force)
+			}
+			assert.Equal(t, []string{spec1, spec3, spec5}, names)
+		})
+		t.Run("should fail if no job matching selector has runs in the window", func(t *testing.T) {
+			projJobSpecRepoFac, depenResolver := newProjJobSpecRepoFac()
+			defer projJobSpecRepoFac.AssertExpectations(t)
+			defer depenResolver.AssertExpectations(t)
+
+			planRequest := planRequest
+			planRequest.Selector, _ = models.ParseLabelSelector("pipeline=orders")
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
+			_, err := jobSvc.ReplayPlanDryRun(planRequest)
+			assert.True(t, errors.Is(err, job.ErrReplayWindowMisaligned))
+		})
+		t.Run("should fail if window of the plan is not in days", func(t *testing.T) {
+			planRequest := planRequest
+			planRequest.Start = replayStart.Add(time.Hour * 2)
+			jobSvc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
+			_, err := jobSvc.ReplayPlanDryRun(planRequest)
+			assert.True(t, errors.Is(err, job.ErrReplayWindowMisaligned))
+		})
+	})
 }
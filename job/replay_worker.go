@@ -6,13 +6,26 @@ import (
 	"time"
 
 	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/set"
+	"github.com/odpf/optimus/core/tree"
 
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/pkg/errors"
 )
 
 const (
 	AirflowClearDagRunFailed = "failed to clear airflow dag run"
+	// ReplayUpstreamFailed signifies type of cancellation of replays which
+	// were to run after a failed replay of their upstream
+	ReplayUpstreamFailed = "upstream replay failed"
+	// ReplayCancelled signifies type of cancellation of replays cancelled
+	// by users
+	ReplayCancelled = "replay cancelled"
+
+	// ReplaySequentialPollInterval is how often runs of sequential jobs
+	// cleared by replay are checked for completion
+	ReplaySequentialPollInterval = time.Second * 30
 )
 
 type ReplayWorker interface {
@@ -22,14 +35,23 @@ type ReplayWorker interface {
 type replayWorker struct {
 	replaySpecRepoFac ReplaySpecRepoFactory
 	scheduler         models.SchedulerUnit
+	eventSvc          EventRegistrar
+
+	PollInterval time.Duration
 }
 
 func (w *replayWorker) Process(ctx context.Context, input *models.ReplayWorkerRequest) (err error) {
 	replaySpecRepo := w.replaySpecRepoFac.New(input.Job)
 	// mark replay request in progress
 	if inProgressErr := replaySpecRepo.UpdateStatus(input.ID, models.ReplayStatusInProgress, models.ReplayMessage{}); inProgressErr != nil {
+		if errors.Is(inProgressErr, models.ErrInvalidReplayTransition) {
+			// cancelled or timed out while waiting in queue
+			logger.I(fmt.Sprintf("skipping replay %s: %s", input.ID.String(), inProgressErr.Error()))
+			return nil
+		}
 		return inProgressErr
 	}
+	w.notify(ctx, input, models.ReplayStatusInProgress, models.ReplayMessage{})
 
 	replayTree, err := prepareTree(input)
 	if err != nil {
@@ -37,30 +59,200 @@ func (w *replayWorker) Process(ctx context.Context, input *models.ReplayWorkerRe
 	}
 
 	replayDagsMap := replayTree.GetAllNodes()
+	var replayedJobs []string
+	for _, treeNode := range replayDagsMap {
+		replayedJobs = append(replayedJobs, treeNode.GetName())
+	}
+	jobReplays, err := w.jobReplays(input, replayDagsMap)
+	if err != nil {
+		return err
+	}
 	for _, treeNode := range replayDagsMap {
-		runTimes := treeNode.Runs.Values()
-		startTime := runTimes[0].(time.Time)
-		endTime := runTimes[treeNode.Runs.Size()-1].(time.Time)
-		if err = w.scheduler.Clear(ctx, input.Project, treeNode.GetName(), startTime, endTime); err != nil {
+		jobReplay, tracked := jobReplays[treeNode.GetName()]
+		if tracked {
+			if err := replaySpecRepo.UpdateStatus(jobReplay.ID, models.ReplayStatusInProgress, models.ReplayMessage{}); err != nil {
+				if errors.Is(err, models.ErrInvalidReplayTransition) {
+					// replay was cancelled along with its jobs
+					logger.I(fmt.Sprintf("replay %s ended before completing: %s", input.ID.String(), err.Error()))
+					return nil
+				}
+				return err
+			}
+			// a job is cleared once even if it is downstream of more
+			// than one replayed job
+			delete(jobReplays, treeNode.GetName())
+		}
+		if err = w.clearRuns(ctx, input, treeNode, replayedJobs); err != nil {
 			err = errors.Wrapf(err, "error while clearing dag runs for job %s", treeNode.GetName())
 			logger.W(fmt.Sprintf("error while running replay %s: %s", input.ID.String(), err.Error()))
-			if updateStatusErr := replaySpecRepo.UpdateStatus(input.ID, models.ReplayStatusFailed, models.ReplayMessage{
+			failedMessage := models.ReplayMessage{
 				Type:    AirflowClearDagRunFailed,
 				Message: err.Error(),
-			}); updateStatusErr != nil {
+			}
+			if tracked {
+				if updateStatusErr := replaySpecRepo.UpdateStatus(jobReplay.ID, models.ReplayStatusFailed, failedMessage); updateStatusErr != nil {
+					return updateStatusErr
+				}
+				var remaining []models.ReplaySpec
+				for _, remainingReplay := range jobReplays {
+					remaining = append(remaining, remainingReplay)
+				}
+				cancelReplays(ctx, w.replaySpecRepoFac, w.eventSvc, input.Project, remaining, models.ReplayMessage{
+					Type:    ReplayUpstreamFailed,
+					Message: fmt.Sprintf("replay of %s failed", treeNode.GetName()),
+				}, false)
+			}
+			if updateStatusErr := replaySpecRepo.UpdateStatus(input.ID, models.ReplayStatusFailed, failedMessage); updateStatusErr != nil {
 				return updateStatusErr
 			}
+			w.notify(ctx, input, models.ReplayStatusFailed, failedMessage)
 			return err
 		}
+		if tracked {
+			if err := replaySpecRepo.UpdateStatus(jobReplay.ID, models.ReplayStatusSuccess, models.ReplayMessage{}); err != nil &&
+				!errors.Is(err, models.ErrInvalidReplayTransition) {
+				return err
+			}
+		}
 	}
 
 	if err = replaySpecRepo.UpdateStatus(input.ID, models.ReplayStatusSuccess, models.ReplayMessage{}); err != nil {
+		if errors.Is(err, models.ErrInvalidReplayTransition) {
+			// cancelled by a forced replay while runs were being cleared
+			logger.I(fmt.Sprintf("replay %s ended before completing: %s", input.ID.String(), err.Error()))
+			return nil
+		}
 		return err
 	}
+	w.notify(ctx, input, models.ReplayStatusSuccess, models.ReplayMessage{})
 	logger.I(fmt.Sprintf("successfully completed replay id: %s", input.ID.String()))
 	return nil
 }
 
-func NewReplayWorker(replaySpecRepoFac ReplaySpecRepoFactory, scheduler models.SchedulerUnit) *replayWorker {
-	return &replayWorker{replaySpecRepoFac: replaySpecRepoFac, scheduler: scheduler}
+// clearRuns clears runs of a job being replayed. Runs of sequential jobs, ones
+// depending on past or with max active runs, are cleared oldest first in
+// batches of max active runs(one if depending on past), each once the previous
+// batch has finished, so that backfilled runs never run out of order or more
+// at once than allowed. A failed run stops clearing of the later ones
+func (w *replayWorker) clearRuns(ctx context.Context, input *models.ReplayWorkerRequest, treeNode *tree.TreeNode,
+	replayedJobs []string) error {
+	runTimes := set.Times(treeNode.Runs)
+	jobSpec, _ := treeNode.Data.(models.JobSpec)
+	batchSize := jobSpec.Schedule.MaxActiveRuns
+	if jobSpec.Behavior.DependsOnPast {
+		batchSize = 1
+	}
+	if batchSize == 0 {
+		return w.clear(ctx, input, treeNode.GetName(), runTimes[0], runTimes[len(runTimes)-1], replayedJobs)
+	}
+
+	for batchStart := 0; batchStart < len(runTimes); batchStart += batchSize {
+		batchEnd := batchStart + batchSize - 1
+		if batchEnd >= len(runTimes) {
+			batchEnd = len(runTimes) - 1
+		}
+		startTime, endTime := runTimes[batchStart], runTimes[batchEnd]
+		if err := w.clear(ctx, input, treeNode.GetName(), startTime, endTime, replayedJobs); err != nil {
+			return err
+		}
+		if batchEnd == len(runTimes)-1 {
+			// last batch is left to finish in scheduler like other replays
+			break
+		}
+		if err := w.waitForRuns(ctx, input.Project, treeNode.GetName(), startTime, endTime, batchEnd-batchStart+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clear clears runs of job between start and end, marking their sensors
+// waiting for jobs not being replayed as succeeded if upstream is ignored
+func (w *replayWorker) clear(ctx context.Context, input *models.ReplayWorkerRequest, jobName string,
+	startTime, endTime time.Time, replayedJobs []string) error {
+	if err := w.scheduler.Clear(ctx, input.Project, jobName, startTime, endTime); err != nil {
+		return err
+	}
+	if !input.IgnoreUpstream {
+		return nil
+	}
+	marker, ok := w.scheduler.(models.SchedulerSensorMarker)
+	if !ok {
+		return errors.Errorf("scheduler %s can't ignore upstream of runs", w.scheduler.GetName())
+	}
+	if err := marker.MarkSensorsSuccess(ctx, input.Project, jobName, startTime, endTime, replayedJobs); err != nil {
+		return errors.Wrap(err, "failed to mark sensors of cleared runs as succeeded")
+	}
+	return nil
+}
+
+// waitForRuns blocks till all runs of job between start and end are finished,
+// failing if any of them failed
+func (w *replayWorker) waitForRuns(ctx context.Context, projSpec models.ProjectSpec, jobName string,
+	startTime, endTime time.Time, runs int) error {
+	for {
+		statuses, err := w.scheduler.GetDagRunStatus(ctx, projSpec, jobName, startTime, endTime, runs)
+		if err != nil {
+			return err
+		}
+		finished := 0
+		for _, status := range statuses {
+			switch status.State {
+			case models.JobStatusStateFailed:
+				return errors.Errorf("run at %s failed, later runs are not cleared as job runs sequentially",
+					status.ScheduledAt.Format(TimestampLogFormat))
+			case models.JobStatusStateSuccess:
+				finished++
+			}
+		}
+		if finished >= runs {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "runs between %s and %s did not finish", startTime.Format(TimestampLogFormat),
+				endTime.Format(TimestampLogFormat))
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+// jobReplays returns replays tracking jobs of a replay of more than one job
+// by their job name
+func (w *replayWorker) jobReplays(input *models.ReplayWorkerRequest, replayNodes []*tree.TreeNode) (map[string]models.ReplaySpec, error) {
+	jobReplays := map[string]models.ReplaySpec{}
+	if len(replayNodes) <= 1 {
+		return jobReplays, nil
+	}
+	replays, err := w.replaySpecRepoFac.New(input.Job).GetByParentID(input.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return jobReplays, nil
+		}
+		return nil, err
+	}
+	for _, replay := range replays {
+		jobReplays[replay.Job.Name] = replay
+	}
+	return jobReplays, nil
+}
+
+func (w *replayWorker) notify(ctx context.Context, input *models.ReplayWorkerRequest, status string, message models.ReplayMessage) {
+	notifyReplay(ctx, w.eventSvc, input.Project, models.ReplaySpec{
+		ID:        input.ID,
+		Job:       input.Job,
+		StartDate: input.Start,
+		EndDate:   input.End,
+		Status:    status,
+		Message:   message,
+	})
+}
+
+func NewReplayWorker(replaySpecRepoFac ReplaySpecRepoFactory, scheduler models.SchedulerUnit, eventSvc EventRegistrar) *replayWorker {
+	return &replayWorker{
+		replaySpecRepoFac: replaySpecRepoFac,
+		scheduler:         scheduler,
+		eventSvc:          eventSvc,
+		PollInterval:      ReplaySequentialPollInterval,
+	}
 }
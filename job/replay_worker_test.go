@@ -14,6 +14,7 @@ import (
 	"github.com/odpf/optimus/models"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
 )
 
 func TestReplayWorker(t *testing.T) {
@@ -55,7 +56,7 @@ func TestReplayWorker(t *testing.T) {
 			defer replaySpecRepoFac.AssertExpectations(t)
 			replaySpecRepoFac.On("New", replayRequest.Job).Return(replayRepository)
 
-			worker := job.NewReplayWorker(replaySpecRepoFac, nil)
+			worker := job.NewReplayWorker(replaySpecRepoFac, nil, nil)
 			err := worker.Process(ctx, replayRequest)
 			assert.NotNil(t, err)
 			assert.Equal(t, errMessage, err.Error())
@@ -81,7 +82,7 @@ func TestReplayWorker(t *testing.T) {
 			errorMessage := "scheduler clear error"
 			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunEndTime).Return(errors.New(errorMessage))
 
-			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler)
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
 			err := worker.Process(ctx, replayRequest)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), errorMessage)
@@ -108,7 +109,7 @@ func TestReplayWorker(t *testing.T) {
 			errorMessage := "scheduler clear error"
 			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunEndTime).Return(errors.New(errorMessage))
 
-			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler)
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
 			err := worker.Process(ctx, replayRequest)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), updateStatusErr.Error())
@@ -129,7 +130,7 @@ func TestReplayWorker(t *testing.T) {
 			defer scheduler.AssertExpectations(t)
 			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunEndTime).Return(nil)
 
-			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler)
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
 			err := worker.Process(ctx, replayRequest)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), updateSuccessStatusErr.Error())
@@ -148,10 +149,201 @@ func TestReplayWorker(t *testing.T) {
 			defer scheduler.AssertExpectations(t)
 			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunEndTime).Return(nil)
 
-			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler)
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
 			err := worker.Process(ctx, replayRequest)
 			assert.Nil(t, err)
 		})
+		t.Run("should mark sensors of cleared runs as succeeded when upstream is ignored", func(t *testing.T) {
+			ctx := context.Background()
+			ignoreUpstreamRequest := *replayRequest
+			ignoreUpstreamRequest.IgnoreUpstream = true
+
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusInProgress, models.ReplayMessage{}).Return(nil)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusSuccess, models.ReplayMessage{}).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", replayRequest.Job).Return(replayRepository)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunEndTime).Return(nil)
+			scheduler.On("MarkSensorsSuccess", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunEndTime,
+				[]string{"job-name"}).Return(nil)
+
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
+			err := worker.Process(ctx, &ignoreUpstreamRequest)
+			assert.Nil(t, err)
+		})
+		t.Run("should clear runs of sequential jobs in batches of max active runs after previous batch finishes", func(t *testing.T) {
+			ctx := context.Background()
+			sequentialSpec := jobSpec
+			sequentialSpec.Schedule.MaxActiveRuns = 2
+			sequentialRequest := *replayRequest
+			sequentialRequest.Job = sequentialSpec
+			sequentialRequest.JobSpecMap = map[string]models.JobSpec{"job-name": sequentialSpec}
+
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusInProgress, models.ReplayMessage{}).Return(nil)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusSuccess, models.ReplayMessage{}).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", sequentialSpec).Return(replayRepository)
+
+			runAt := func(day int) time.Time {
+				return time.Date(2020, time.Month(8), day, 2, 0, 0, 0, time.UTC)
+			}
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", runAt(22), runAt(23)).Return(nil).Once()
+			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, "job-name", runAt(22), runAt(23), 2).Return([]models.JobStatus{
+				{ScheduledAt: runAt(22), State: models.JobStatusStateSuccess},
+				{ScheduledAt: runAt(23), State: models.JobStatusStateRunning},
+			}, nil).Once()
+			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, "job-name", runAt(22), runAt(23), 2).Return([]models.JobStatus{
+				{ScheduledAt: runAt(22), State: models.JobStatusStateSuccess},
+				{ScheduledAt: runAt(23), State: models.JobStatusStateSuccess},
+			}, nil).Once()
+			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", runAt(24), runAt(25)).Return(nil).Once()
+			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, "job-name", runAt(24), runAt(25), 2).Return([]models.JobStatus{
+				{ScheduledAt: runAt(24), State: models.JobStatusStateSuccess},
+				{ScheduledAt: runAt(25), State: models.JobStatusStateSuccess},
+			}, nil).Once()
+			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", runAt(26), runAt(26)).Return(nil).Once()
+
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
+			worker.PollInterval = time.Millisecond
+			err := worker.Process(ctx, &sequentialRequest)
+			assert.Nil(t, err)
+		})
+		t.Run("should stop clearing runs of job depending on past once a run fails", func(t *testing.T) {
+			ctx := context.Background()
+			sequentialSpec := jobSpec
+			sequentialSpec.Behavior.DependsOnPast = true
+			sequentialRequest := *replayRequest
+			sequentialRequest.Job = sequentialSpec
+			sequentialRequest.JobSpecMap = map[string]models.JobSpec{"job-name": sequentialSpec}
+
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusInProgress, models.ReplayMessage{}).Return(nil)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusFailed, mock2.AnythingOfType("models.ReplayMessage")).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", sequentialSpec).Return(replayRepository)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunStartTime).Return(nil).Once()
+			scheduler.On("GetDagRunStatus", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunStartTime, 1).Return([]models.JobStatus{
+				{ScheduledAt: dagRunStartTime, State: models.JobStatusStateFailed},
+			}, nil).Once()
+
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
+			worker.PollInterval = time.Millisecond
+			err := worker.Process(ctx, &sequentialRequest)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "later runs are not cleared")
+		})
+		t.Run("should notify status changes of replay", func(t *testing.T) {
+			ctx := context.Background()
+			replayRepository := new(mock.ReplayRepository)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusInProgress, models.ReplayMessage{}).Return(nil)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusSuccess, models.ReplayMessage{}).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", replayRequest.Job).Return(replayRepository)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("Clear", ctx, replayRequest.Project, "job-name", dagRunStartTime, dagRunEndTime).Return(nil)
+
+			var notified []string
+			eventSvc := new(mock.EventService)
+			defer eventSvc.AssertExpectations(t)
+			eventSvc.On("Register", ctx, models.NamespaceSpec{ProjectSpec: replayRequest.Project}, jobSpec,
+				mock2.AnythingOfType("models.JobEvent")).Run(func(args mock2.Arguments) {
+				evt := args.Get(3).(models.JobEvent)
+				assert.Equal(t, models.JobEventTypeReplay, evt.Type)
+				assert.Equal(t, currUUID.String(), evt.Value["replay_id"].GetStringValue())
+				assert.Equal(t, "2020-08-22", evt.Value["start_date"].GetStringValue())
+				notified = append(notified, evt.Value["status"].GetStringValue())
+			}).Return(nil)
+
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, eventSvc)
+			err := worker.Process(ctx, replayRequest)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{models.ReplayStatusInProgress, models.ReplayStatusSuccess}, notified)
+		})
+		t.Run("should skip replay which ended while waiting in queue", func(t *testing.T) {
+			ctx := context.Background()
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusInProgress, models.ReplayMessage{}).
+				Return(errors.Wrap(models.ErrInvalidReplayTransition, "cancelled to inprogress"))
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", replayRequest.Job).Return(replayRepository)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
+			err := worker.Process(ctx, replayRequest)
+			assert.Nil(t, err)
+		})
+		t.Run("should track status of each job of a replay of more than one job", func(t *testing.T) {
+			ctx := context.Background()
+			downstreamSpec := models.JobSpec{
+				Name:     "downstream-job-name",
+				Schedule: jobSpec.Schedule,
+				Dependencies: map[string]models.JobSpecDependency{
+					jobSpec.Name: {Job: &jobSpec, Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+			request := *replayRequest
+			request.JobSpecMap = map[string]models.JobSpec{
+				jobSpec.Name:        jobSpec,
+				downstreamSpec.Name: downstreamSpec,
+			}
+			upstreamReplay := models.ReplaySpec{ID: uuid.Must(uuid.NewRandom()), Job: jobSpec, ParentID: currUUID}
+			downstreamReplay := models.ReplaySpec{ID: uuid.Must(uuid.NewRandom()), Job: downstreamSpec, ParentID: currUUID}
+
+			replayRepository := new(mock.ReplayRepository)
+			defer replayRepository.AssertExpectations(t)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusInProgress, models.ReplayMessage{}).Return(nil)
+			replayRepository.On("GetByParentID", currUUID).Return([]models.ReplaySpec{upstreamReplay, downstreamReplay}, nil)
+			replayRepository.On("UpdateStatus", upstreamReplay.ID, models.ReplayStatusInProgress, models.ReplayMessage{}).Return(nil)
+			replayRepository.On("UpdateStatus", upstreamReplay.ID, models.ReplayStatusSuccess, models.ReplayMessage{}).Return(nil)
+			replayRepository.On("UpdateStatus", downstreamReplay.ID, models.ReplayStatusInProgress, models.ReplayMessage{}).Return(nil)
+			failedReplayMessage := models.ReplayMessage{
+				Type:    job.AirflowClearDagRunFailed,
+				Message: "error while clearing dag runs for job downstream-job-name: scheduler clear error",
+			}
+			replayRepository.On("UpdateStatus", downstreamReplay.ID, models.ReplayStatusFailed, failedReplayMessage).Return(nil)
+			replayRepository.On("UpdateStatus", currUUID, models.ReplayStatusFailed, failedReplayMessage).Return(nil)
+
+			replaySpecRepoFac := new(mock.ReplaySpecRepoFactory)
+			defer replaySpecRepoFac.AssertExpectations(t)
+			replaySpecRepoFac.On("New", request.Job).Return(replayRepository)
+
+			scheduler := new(mock.Scheduler)
+			defer scheduler.AssertExpectations(t)
+			scheduler.On("Clear", ctx, request.Project, jobSpec.Name, dagRunStartTime, dagRunEndTime).Return(nil)
+			scheduler.On("Clear", ctx, request.Project, downstreamSpec.Name, mock2.Anything, mock2.Anything).
+				Return(errors.New("scheduler clear error"))
+
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
+			err := worker.Process(ctx, &request)
+			assert.NotNil(t, err)
+		})
 		t.Run("should throw an error when prepareTree throws an error", func(t *testing.T) {
 			replayRequest.JobSpecMap = make(map[string]models.JobSpec)
 			ctx := context.Background()
@@ -166,7 +358,7 @@ func TestReplayWorker(t *testing.T) {
 			scheduler := new(mock.Scheduler)
 			defer scheduler.AssertExpectations(t)
 
-			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler)
+			worker := job.NewReplayWorker(replaySpecRepoFac, scheduler, nil)
 			err := worker.Process(ctx, replayRequest)
 			assert.NotNil(t, err)
 		})
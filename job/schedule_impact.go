@@ -0,0 +1,190 @@
+package job
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// catchup runs are counted up to this, a job with a frequent schedule
+	// starting long ago can have too many to enumerate
+	maxCatchUpRunsCounted = 1000
+
+	// runs are looked for at most this far back, schedules find no runs
+	// further than five years apart either
+	maxRunLookback = 5 * 366 * 24 * time.Hour
+)
+
+// ScheduleImpact is the effect on runs of a job of changing its schedule
+// or window in a deployment
+type ScheduleImpact struct {
+	JobName string
+
+	// SkippedFrom and SkippedTo bound the data no run covers anymore, between
+	// window of the last run of previous schedule and the first run of the
+	// new one. Both are zero if nothing is skipped
+	SkippedFrom time.Time
+	SkippedTo   time.Time
+
+	// DuplicatedFrom and DuplicatedTo bound the data covered again by the
+	// first run of the new schedule. Both are zero if nothing is duplicated
+	DuplicatedFrom time.Time
+	DuplicatedTo   time.Time
+
+	// CatchUpRuns are the runs scheduler triggers right after deployment for
+	// the times missed by new schedule, capped at maxCatchUpRunsCounted
+	CatchUpRuns int
+
+	// Dependents are jobs depending on the job, their sensors wait for runs
+	// of the new schedule
+	Dependents []string
+}
+
+// Breaking tells if the change skips or duplicates data, triggers catchup
+// runs or affects other jobs
+func (i ScheduleImpact) Breaking() bool {
+	return !i.SkippedFrom.IsZero() || !i.DuplicatedFrom.IsZero() || i.CatchUpRuns > 0 || len(i.Dependents) > 0
+}
+
+func (i ScheduleImpact) String() string {
+	var effects []string
+	if !i.SkippedFrom.IsZero() {
+		effects = append(effects, fmt.Sprintf("data from %s to %s is skipped",
+			i.SkippedFrom.Format(time.RFC3339), i.SkippedTo.Format(time.RFC3339)))
+	}
+	if !i.DuplicatedFrom.IsZero() {
+		effects = append(effects, fmt.Sprintf("data from %s to %s is processed again",
+			i.DuplicatedFrom.Format(time.RFC3339), i.DuplicatedTo.Format(time.RFC3339)))
+	}
+	if i.CatchUpRuns >= maxCatchUpRunsCounted {
+		effects = append(effects, fmt.Sprintf("at least %d catchup runs are triggered", i.CatchUpRuns))
+	} else if i.CatchUpRuns > 0 {
+		effects = append(effects, fmt.Sprintf("%d catchup runs are triggered", i.CatchUpRuns))
+	}
+	if len(i.Dependents) > 0 {
+		effects = append(effects, fmt.Sprintf("sensors of %s are affected", strings.Join(i.Dependents, ", ")))
+	}
+	if len(effects) == 0 {
+		return fmt.Sprintf("schedule of %s changed without impact on its runs", i.JobName)
+	}
+	return fmt.Sprintf("schedule of %s changed: %s", i.JobName, strings.Join(effects, "; "))
+}
+
+// AnalyzeScheduleChanges computes impact of the jobs of a deployment whose
+// start date, interval or window changed compared to previous specs, as if
+// deployed at now. Dependents are looked up in current specs, sorted by
+// job name
+func AnalyzeScheduleChanges(previous, current []models.JobSpec, now time.Time) ([]ScheduleImpact, error) {
+	previousByName := map[string]models.JobSpec{}
+	for _, spec := range previous {
+		previousByName[spec.Name] = spec
+	}
+	dependents := map[string][]string{}
+	for _, spec := range current {
+		for depName := range spec.Dependencies {
+			dependents[depName] = append(dependents[depName], spec.Name)
+		}
+	}
+
+	var impacts []ScheduleImpact
+	for _, spec := range current {
+		prevSpec, ok := previousByName[spec.Name]
+		if !ok || !scheduleChanged(prevSpec, spec) {
+			continue
+		}
+		impact, err := AnalyzeScheduleChange(prevSpec, spec, now)
+		if err != nil {
+			return nil, err
+		}
+		impact.Dependents = dependents[spec.Name]
+		sort.Strings(impact.Dependents)
+		impacts = append(impacts, impact)
+	}
+	sort.Slice(impacts, func(i, j int) bool {
+		return impacts[i].JobName < impacts[j].JobName
+	})
+	return impacts, nil
+}
+
+// AnalyzeScheduleChange compares the runs of previous schedule of a job up
+// to now with the runs of the current one after it. With catchup, scheduler
+// continues from the last run, triggering runs of the new schedule missed
+// since
+func AnalyzeScheduleChange(previous, current models.JobSpec, now time.Time) (ScheduleImpact, error) {
+	impact := ScheduleImpact{JobName: current.Name}
+	prevSchd, err := cron.ParseCronSchedule(previous.Schedule.Interval)
+	if err != nil {
+		return impact, errors.Wrapf(err, "invalid previous interval of %s", previous.Name)
+	}
+	curSchd, err := cron.ParseCronSchedule(current.Schedule.Interval)
+	if err != nil {
+		return impact, errors.Wrapf(err, "invalid interval of %s", current.Name)
+	}
+	now = now.UTC()
+
+	prevUntil := now
+	if end := previous.Schedule.EndDate; end != nil && end.Before(now) {
+		prevUntil = *end
+	}
+	lastRun := lastRunBefore(prevSchd, previous.Schedule.StartDate, prevUntil)
+
+	catchUpFrom := lastRun
+	if lastRun.IsZero() {
+		catchUpFrom = current.Schedule.StartDate.Add(-time.Second)
+	}
+	firstRun := curSchd.Next(now)
+	if current.Behavior.CatchUp {
+		firstRun = curSchd.Next(catchUpFrom)
+		for run := firstRun; !run.IsZero() && !run.After(now) && impact.CatchUpRuns < maxCatchUpRunsCounted; run = curSchd.Next(run) {
+			impact.CatchUpRuns++
+		}
+	}
+	if lastRun.IsZero() || firstRun.IsZero() {
+		// nothing ran yet or will run, no data to skip or duplicate
+		return impact, nil
+	}
+
+	coveredUntil := previous.Task.Window.GetEnd(lastRun)
+	coveredFrom := current.Task.Window.GetStart(firstRun)
+	if coveredFrom.After(coveredUntil) {
+		impact.SkippedFrom, impact.SkippedTo = coveredUntil, coveredFrom
+	} else if coveredFrom.Before(coveredUntil) {
+		impact.DuplicatedFrom, impact.DuplicatedTo = coveredFrom, coveredUntil
+	}
+	return impact, nil
+}
+
+func scheduleChanged(previous, current models.JobSpec) bool {
+	return previous.Schedule.Interval != current.Schedule.Interval ||
+		!previous.Schedule.StartDate.Equal(current.Schedule.StartDate) ||
+		previous.Task.Window != current.Task.Window
+}
+
+// lastRunBefore returns the last run of schedule starting at start which is
+// not after until, zero if there is none. Schedule can't be iterated
+// backwards, runs are looked for in a window growing back from until
+func lastRunBefore(schd *cron.ScheduleSpec, start, until time.Time) time.Time {
+	if until.Before(start) {
+		return time.Time{}
+	}
+	for lookback := time.Hour; lookback <= maxRunLookback; lookback *= 2 {
+		from := until.Add(-lookback)
+		if !from.After(start) {
+			from = start.Add(-time.Second)
+		}
+		var last time.Time
+		for run := schd.Next(from); !run.IsZero() && !run.After(until); run = schd.Next(run) {
+			last = run
+		}
+		if !last.IsZero() || !from.After(start) {
+			return last
+		}
+	}
+	return time.Time{}
+}
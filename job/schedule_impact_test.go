@@ -0,0 +1,101 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeScheduleChange(t *testing.T) {
+	now := time.Date(2021, 5, 20, 10, 30, 0, 0, time.UTC)
+	jobSpec := func(name, interval string, windowSize time.Duration, catchUp bool) models.JobSpec {
+		return models.JobSpec{
+			Name: name,
+			Schedule: models.JobSpecSchedule{
+				StartDate: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				Interval:  interval,
+			},
+			Behavior: models.JobSpecBehavior{
+				CatchUp: catchUp,
+			},
+			Task: models.JobSpecTask{
+				Window: models.JobSpecTaskWindow{
+					Size: windowSize,
+				},
+			},
+		}
+	}
+
+	t.Run("should report data skipped till first run of new schedule", func(t *testing.T) {
+		impact, err := job.AnalyzeScheduleChange(jobSpec("job-a", "@daily", 24*time.Hour, false),
+			jobSpec("job-a", "@hourly", time.Hour, false), now)
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC), impact.SkippedFrom)
+		assert.Equal(t, time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC), impact.SkippedTo)
+		assert.True(t, impact.DuplicatedFrom.IsZero())
+		assert.Equal(t, 0, impact.CatchUpRuns)
+		assert.True(t, impact.Breaking())
+		assert.Equal(t, "schedule of job-a changed: data from 2021-05-20T00:00:00Z to 2021-05-20T10:00:00Z is skipped", impact.String())
+	})
+	t.Run("should report data processed again by first run of new schedule", func(t *testing.T) {
+		impact, err := job.AnalyzeScheduleChange(jobSpec("job-a", "@hourly", time.Hour, false),
+			jobSpec("job-a", "@daily", 24*time.Hour, false), now)
+		assert.Nil(t, err)
+		assert.True(t, impact.SkippedFrom.IsZero())
+		assert.Equal(t, time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC), impact.DuplicatedFrom)
+		assert.Equal(t, time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC), impact.DuplicatedTo)
+		assert.True(t, impact.Breaking())
+	})
+	t.Run("should count runs triggered by catchup since the last run", func(t *testing.T) {
+		impact, err := job.AnalyzeScheduleChange(jobSpec("job-a", "@daily", 24*time.Hour, true),
+			jobSpec("job-a", "@hourly", time.Hour, true), now)
+		assert.Nil(t, err)
+		assert.True(t, impact.SkippedFrom.IsZero())
+		assert.True(t, impact.DuplicatedFrom.IsZero())
+		assert.Equal(t, 10, impact.CatchUpRuns)
+		assert.Equal(t, "schedule of job-a changed: 10 catchup runs are triggered", impact.String())
+	})
+	t.Run("should not be breaking if window keeps covering the same data", func(t *testing.T) {
+		impact, err := job.AnalyzeScheduleChange(jobSpec("job-a", "@daily", 24*time.Hour, false),
+			jobSpec("job-a", "0 2 * * *", 26*time.Hour, false), now)
+		assert.Nil(t, err)
+		assert.False(t, impact.Breaking())
+	})
+	t.Run("should not report data of jobs not run yet", func(t *testing.T) {
+		previous := jobSpec("job-a", "@daily", 24*time.Hour, false)
+		previous.Schedule.StartDate = now.Add(time.Hour * 48)
+		current := jobSpec("job-a", "@hourly", time.Hour, false)
+		current.Schedule.StartDate = previous.Schedule.StartDate
+
+		impact, err := job.AnalyzeScheduleChange(previous, current, now)
+		assert.Nil(t, err)
+		assert.False(t, impact.Breaking())
+	})
+	t.Run("should analyze jobs with changed schedules and their dependents", func(t *testing.T) {
+		dependent := jobSpec("job-c", "@daily", 24*time.Hour, false)
+		dependent.Dependencies = map[string]models.JobSpecDependency{"job-a": {}}
+		previous := []models.JobSpec{
+			jobSpec("job-a", "@daily", 24*time.Hour, false),
+			jobSpec("job-b", "@daily", 24*time.Hour, false),
+			dependent,
+		}
+		current := []models.JobSpec{
+			jobSpec("job-a", "@hourly", time.Hour, false),
+			jobSpec("job-b", "@daily", 24*time.Hour, false),
+			dependent,
+			jobSpec("job-d", "@hourly", time.Hour, false),
+		}
+
+		impacts, err := job.AnalyzeScheduleChanges(previous, current, now)
+		assert.Nil(t, err)
+		assert.Len(t, impacts, 1)
+		assert.Equal(t, "job-a", impacts[0].JobName)
+		assert.Equal(t, []string{"job-c"}, impacts[0].Dependents)
+
+		_, err = job.AnalyzeScheduleChanges(previous, []models.JobSpec{jobSpec("job-a", "every hour", time.Hour, false)}, now)
+		assert.NotNil(t, err)
+	})
+}
@@ -2,13 +2,20 @@ package job
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 
 	"github.com/kushsharma/parallel"
+	"github.com/odpf/optimus/core/cron"
+	"github.com/odpf/optimus/core/logger"
 	"github.com/odpf/optimus/core/progress"
 	"github.com/odpf/optimus/meta"
 	"github.com/odpf/optimus/models"
@@ -24,12 +31,31 @@ const (
 	ConcurrentLimit        = 600
 )
 
-type AssetCompiler func(jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error)
+var (
+	// DependencyResolverWorkers is the number of jobs compiled and resolved
+	// concurrently while resolving dependencies of a project
+	DependencyResolverWorkers = 50
+
+	// UpstreamLoadTimeout is how long a sync waits for scheduler to load
+	// new jobs before uploading the ones depending on them
+	UpstreamLoadTimeout = 5 * time.Minute
+	// UpstreamLoadPollInterval is how often scheduler is checked for new
+	// jobs being loaded
+	UpstreamLoadPollInterval = 10 * time.Second
+
+	// ErrJobExists is returned on renaming a job to the name of another job
+	// of the project
+	ErrJobExists = errors.New("job already exists")
+)
+
+type AssetCompiler func(proj models.ProjectSpec, jobSpec models.JobSpec, scheduledAt time.Time) (models.JobAssets, error)
 
 // DependencyResolver compiles static and runtime dependencies
 type DependencyResolver interface {
 	Resolve(projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
 		jobSpec models.JobSpec, observer progress.Observer) (models.JobSpec, error)
+	Explain(projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
+		jobSpec models.JobSpec) ([]models.JobSpecDependencyExplanation, error)
 }
 
 // SpecRepoFactory is used to manage job specs at namespace level
@@ -69,6 +95,9 @@ type Service struct {
 	metaSvcFactory            meta.MetaSvcFactory
 	projectJobSpecRepoFactory ProjectJobSpecRepoFactory
 	replayManager             ReplayManager
+	locker                    *JobLocker
+	projectRepoFactory        ProjectRepoFactory
+	scheduler                 models.SchedulerUnit
 
 	Now           func() time.Time
 	assetCompiler AssetCompiler
@@ -76,13 +105,100 @@ type Service struct {
 
 // Create constructs a Job for a namespace and commits it to the store
 func (srv *Service) Create(namespace models.NamespaceSpec, spec models.JobSpec) error {
+	if err := validateTaskVersion(spec); err != nil {
+		return err
+	}
+	if err := validateTaskConfig(context.TODO(), spec, false); err != nil {
+		return err
+	}
+	if namespace.ProjectSpec.IsProduction() {
+		if err := validateOwnership(spec); err != nil {
+			return err
+		}
+	}
 	jobRepo := srv.jobSpecRepoFactory.New(namespace)
+	unlock, err := srv.locker.Lock(namespace.ProjectSpec.Name, []string{spec.Name}, JobOperationDeploy, "")
+	if err != nil {
+		// saving the job unchanged doesn't affect the operation holding its lock
+		if !errors.Is(err, ErrJobLocked) || isJobChanged(jobRepo, spec) {
+			return err
+		}
+		unlock = func() {}
+	}
+	defer unlock()
+
 	if err := jobRepo.Save(spec); err != nil {
 		return errors.Wrapf(err, "failed to save job: %s", spec.Name)
 	}
 	return nil
 }
 
+// isJobChanged checks if spec differs from the one saved, jobs which can't
+// be read are taken as changed
+func isJobChanged(jobRepo SpecRepository, spec models.JobSpec) bool {
+	saved, err := jobRepo.GetByName(spec.Name)
+	if err != nil {
+		return true
+	}
+	return len(DiffJobSpecs([]models.JobSpec{saved}, []models.JobSpec{spec})) > 0
+}
+
+// validateOwnership makes sure production jobs can be traced back to
+// the people responsible for them
+func validateOwnership(spec models.JobSpec) error {
+	var missing []string
+	if spec.Ownership.Email == "" {
+		missing = append(missing, "email")
+	}
+	if spec.Ownership.Team == "" {
+		missing = append(missing, "team")
+	}
+	if spec.Ownership.SlackChannel == "" {
+		missing = append(missing, "slack_channel")
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("job %s of a production project is missing ownership fields: %s",
+			spec.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// validateTaskVersion makes sure a job pinned to a task plugin version is not
+// compiled with a different version installed on the server
+func validateTaskVersion(spec models.JobSpec) error {
+	if spec.Task.Version == "" || spec.Task.Unit == nil {
+		return nil
+	}
+	info := spec.Task.Unit.Info()
+	if info.PluginVersion != spec.Task.Version {
+		return errors.Wrapf(models.ErrUnsupportedPluginVersion, "job %s pins %s plugin version %s but server has %s",
+			spec.Name, info.Name, spec.Task.Version, info.PluginVersion)
+	}
+	return nil
+}
+
+// validateTaskConfig lets task plugins reject config and assets of a job
+// they can't run, before the job is scheduled
+func validateTaskConfig(ctx context.Context, spec models.JobSpec, dryRun bool) error {
+	if spec.Task.Unit == nil || spec.Task.Unit.ValidatorMod == nil {
+		return nil
+	}
+	resp, err := spec.Task.Unit.ValidatorMod.ValidateConfig(ctx, models.ValidateConfigRequest{
+		Config: models.PluginConfigs{}.FromJobSpec(spec.Task.Config),
+		Assets: models.PluginAssets{}.FromJobSpec(spec.Assets),
+		PluginOptions: models.PluginOptions{
+			DryRun: dryRun,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate config of job %s", spec.Name)
+	}
+	if len(resp.Errors) > 0 {
+		return errors.Errorf("invalid config of job %s: %s", spec.Name, strings.Join(resp.Errors, "; "))
+	}
+	return nil
+}
+
 // GetByName fetches a Job by name for a specific namespace
 func (srv *Service) GetByName(name string, namespace models.NamespaceSpec) (models.JobSpec, error) {
 	jobSpec, err := srv.jobSpecRepoFactory.New(namespace).GetByName(name)
@@ -146,7 +262,7 @@ func (srv *Service) Dump(namespace models.NamespaceSpec, jobSpec models.JobSpec)
 func (srv *Service) Check(namespace models.NamespaceSpec, jobSpecs []models.JobSpec, obs progress.Observer) (err error) {
 	for i, jSpec := range jobSpecs {
 		// compile assets
-		if jobSpecs[i].Assets, err = srv.assetCompiler(jSpec, srv.Now()); err != nil {
+		if jobSpecs[i].Assets, err = srv.assetCompiler(namespace.ProjectSpec, jSpec, srv.Now()); err != nil {
 			return errors.Wrap(err, "asset compilation")
 		}
 
@@ -158,6 +274,24 @@ func (srv *Service) Check(namespace models.NamespaceSpec, jobSpecs []models.JobS
 	for _, jSpec := range jobSpecs {
 		runner.Add(func(currentSpec models.JobSpec) func() (interface{}, error) {
 			return func() (interface{}, error) {
+				// check schedule, jobs without an interval are only run when triggered
+				if currentSpec.Schedule.Interval != "" {
+					if _, err := cron.ParseCronSchedule(currentSpec.Schedule.Interval); err != nil {
+						if obs != nil {
+							obs.Notify(&EventJobCheckFailed{Name: currentSpec.Name, Reason: fmt.Sprintf("schedule validation: %s\n", err.Error())})
+						}
+						return nil, errors.Wrapf(err, "invalid schedule interval of %s", currentSpec.Name)
+					}
+				}
+
+				// check config
+				if err := validateTaskConfig(context.TODO(), currentSpec, true); err != nil {
+					if obs != nil {
+						obs.Notify(&EventJobCheckFailed{Name: currentSpec.Name, Reason: fmt.Sprintf("config validation: %s\n", err.Error())})
+					}
+					return nil, err
+				}
+
 				// check dependencies
 				if currentSpec.Task.Unit.DependencyMod != nil {
 					if _, err := currentSpec.Task.Unit.DependencyMod.GenerateDependencies(context.TODO(), models.GenerateDependenciesRequest{
@@ -203,6 +337,11 @@ func (srv *Service) Delete(ctx context.Context, namespace models.NamespaceSpec,
 	if err := srv.isJobDeletable(namespace.ProjectSpec, jobSpec); err != nil {
 		return err
 	}
+	unlock, err := srv.locker.Lock(namespace.ProjectSpec.Name, []string{jobSpec.Name}, JobOperationDeploy, "")
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	jobSpecRepo := srv.jobSpecRepoFactory.New(namespace)
 	if err := jobSpecRepo.Delete(jobSpec.Name); err != nil {
@@ -216,6 +355,143 @@ func (srv *Service) Delete(ctx context.Context, namespace models.NamespaceSpec,
 	return nil
 }
 
+// Rename renames a job of the namespace keeping its identity, so its runs
+// and checkpoints stay with it, and rewrites static dependencies of jobs on
+// it, by its name in jobs of the project and by project/name in jobs of other
+// projects. Dependents are all looked up before anything is saved and are
+// saved along with the job or not at all. The namespace of the job and of
+// the jobs depending on it are synced after, replacing the job in scheduler
+// under its new name. Names of the jobs whose dependencies were rewritten are
+// returned, prefixed with their project if it is another one
+func (srv *Service) Rename(ctx context.Context, namespace models.NamespaceSpec, oldName, newName string,
+	progressObserver progress.Observer) ([]string, error) {
+	newName = strings.TrimSpace(newName)
+	if newName == "" || newName == oldName {
+		return nil, errors.Errorf("invalid name %s to rename job %s to", newName, oldName)
+	}
+	policy, err := namespace.ProjectSpec.NamingPolicy()
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.CheckJobs([]models.JobSpec{{Name: newName}}); err != nil {
+		return nil, err
+	}
+	unlock, err := srv.locker.Lock(namespace.ProjectSpec.Name, []string{oldName, newName}, JobOperationDeploy, "")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	jobSpecRepo := srv.jobSpecRepoFactory.New(namespace)
+	if _, err := jobSpecRepo.GetByName(oldName); err != nil {
+		return nil, errors.Wrapf(err, "failed to find job %s", oldName)
+	}
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(namespace.ProjectSpec)
+	if _, _, err := projectJobSpecRepo.GetByName(newName); err == nil {
+		return nil, errors.Wrapf(ErrJobExists, "job %s already exists in project %s", newName, namespace.ProjectSpec.Name)
+	} else if !errors.Is(err, store.ErrResourceNotFound) {
+		return nil, err
+	}
+
+	projects := []models.ProjectSpec{namespace.ProjectSpec}
+	if srv.projectRepoFactory != nil {
+		allProjects, err := srv.projectRepoFactory.New().GetAll()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list projects to rewrite dependencies in")
+		}
+		for _, proj := range allProjects {
+			if proj.Name != namespace.ProjectSpec.Name {
+				projects = append(projects, proj)
+			}
+		}
+	}
+	// jobs of other projects depend on the job by project/name
+	qualifiedOldName := namespace.ProjectSpec.Name + "/" + oldName
+	qualifiedNewName := namespace.ProjectSpec.Name + "/" + newName
+	projectRenames := map[string]string{oldName: newName, qualifiedOldName: qualifiedNewName}
+	crossProjectRenames := map[string]string{qualifiedOldName: qualifiedNewName}
+	var (
+		dependentSpecs []models.JobSpec
+		dependents     []string
+		namespaces     []models.NamespaceSpec
+		synced         = map[uuid.UUID]bool{namespace.ID: true}
+	)
+	for _, proj := range projects {
+		renames := crossProjectRenames
+		if proj.Name == namespace.ProjectSpec.Name {
+			renames = projectRenames
+		}
+		repo := srv.projectJobSpecRepoFactory.New(proj)
+		jobSpecs, err := repo.GetAll()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read jobs of project %s", proj.Name)
+		}
+		for _, jobSpec := range jobSpecs {
+			if proj.Name == namespace.ProjectSpec.Name && jobSpec.Name == oldName {
+				continue
+			}
+			rewritten, ok := renameDependencies(jobSpec.Dependencies, renames)
+			if !ok {
+				continue
+			}
+			_, jobNamespace, err := repo.GetByName(jobSpec.Name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to find namespace of job %s", jobSpec.Name)
+			}
+			jobSpec.Dependencies = rewritten
+			dependentSpecs = append(dependentSpecs, jobSpec)
+			if proj.Name == namespace.ProjectSpec.Name {
+				dependents = append(dependents, jobSpec.Name)
+			} else {
+				dependents = append(dependents, proj.Name+"/"+jobSpec.Name)
+			}
+			if !synced[jobNamespace.ID] {
+				synced[jobNamespace.ID] = true
+				namespaces = append(namespaces, jobNamespace)
+			}
+		}
+	}
+	sort.Strings(dependents)
+	sort.Slice(namespaces, func(i, j int) bool {
+		if namespaces[i].ProjectSpec.Name != namespaces[j].ProjectSpec.Name {
+			return namespaces[i].ProjectSpec.Name < namespaces[j].ProjectSpec.Name
+		}
+		return namespaces[i].Name < namespaces[j].Name
+	})
+
+	if err := jobSpecRepo.Rename(oldName, newName, dependentSpecs); err != nil {
+		return nil, errors.Wrapf(err, "failed to rename job %s", oldName)
+	}
+
+	// the namespace of the job is synced first, so the job is uploaded under
+	// its new name before the jobs depending on it
+	if err := srv.Sync(ctx, namespace, progressObserver); err != nil {
+		return dependents, err
+	}
+	for _, jobNamespace := range namespaces {
+		if err := srv.Sync(ctx, jobNamespace, progressObserver); err != nil {
+			return dependents, err
+		}
+	}
+	return dependents, nil
+}
+
+// renameDependencies returns dependencies with the ones named in renames
+// renamed, false if none of them are
+func renameDependencies(dependencies map[string]models.JobSpecDependency,
+	renames map[string]string) (map[string]models.JobSpecDependency, bool) {
+	renamed := false
+	rewritten := map[string]models.JobSpecDependency{}
+	for name, dep := range dependencies {
+		if newName, ok := renames[name]; ok {
+			name = newName
+			renamed = true
+		}
+		rewritten[name] = dep
+	}
+	return rewritten, renamed
+}
+
 // Sync fetches all the jobs that belong to a project, resolves its dependencies
 // assign proper priority weights, compiles it and uploads it to the destination
 // store
@@ -243,17 +519,17 @@ func (srv *Service) Sync(ctx context.Context, namespace models.NamespaceSpec, pr
 		return err
 	}
 
-	if err = srv.uploadSpecs(ctx, jobSpecs, jobRepo, namespace, progressObserver); err != nil {
+	// get all the stored job names
+	destJobNames, err := jobRepo.ListNames(ctx, namespace)
+	if err != nil {
 		return err
 	}
 
-	if err = srv.publishMetadata(namespace, jobSpecs, progressObserver); err != nil {
+	if err = srv.uploadSpecs(ctx, jobSpecs, jobRepo, namespace, destJobNames, progressObserver); err != nil {
 		return err
 	}
 
-	// get all the stored job names
-	destJobNames, err := jobRepo.ListNames(ctx, namespace)
-	if err != nil {
+	if err = srv.publishMetadata(namespace, jobSpecs, progressObserver); err != nil {
 		return err
 	}
 
@@ -274,6 +550,26 @@ func (srv *Service) Sync(ctx context.Context, namespace models.NamespaceSpec, pr
 	return nil
 }
 
+// Unschedule deletes compiled jobs of a namespace from the destination store,
+// specs of the jobs are kept
+func (srv *Service) Unschedule(ctx context.Context, namespace models.NamespaceSpec, progressObserver progress.Observer) error {
+	jobRepo, err := srv.jobRepoFactory.New(ctx, namespace.ProjectSpec)
+	if err != nil {
+		return err
+	}
+	destJobNames, err := jobRepo.ListNames(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	for _, dagName := range jobDeletionFilter(destJobNames) {
+		if err := jobRepo.Delete(ctx, namespace, dagName); err != nil {
+			return err
+		}
+		srv.notifyProgress(progressObserver, &EventJobRemoteDelete{dagName})
+	}
+	return nil
+}
+
 // KeepOnly only keeps the provided jobSpecs in argument and deletes rest from spec repository
 func (srv *Service) KeepOnly(namespace models.NamespaceSpec, specsToKeep []models.JobSpec, progressObserver progress.Observer) error {
 	jobSpecRepo := srv.jobSpecRepoFactory.New(namespace)
@@ -294,6 +590,11 @@ func (srv *Service) KeepOnly(namespace models.NamespaceSpec, specsToKeep []model
 	// filter what we need to keep/delete
 	jobsToDelete := setSubstract(specsPresentNames, specsToKeepNames)
 	jobsToDelete = jobDeletionFilter(jobsToDelete)
+	unlock, err := srv.locker.Lock(namespace.ProjectSpec.Name, jobsToDelete, JobOperationDeploy, "")
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	for _, jobName := range jobsToDelete {
 		// delete raw spec
@@ -335,46 +636,156 @@ func (srv *Service) GetDependencyResolvedSpecs(proj models.ProjectSpec, projectJ
 	}
 	srv.notifyProgress(progressObserver, &EventJobSpecFetch{})
 
-	// compile assets first
-	for i, jSpec := range jobSpecs {
-		if jobSpecs[i].Assets, err = srv.assetCompiler(jSpec, srv.Now()); err != nil {
-			return nil, errors.Wrap(err, "asset compilation")
-		}
+	// compile assets and resolve specs using a fixed pool of workers, each job
+	// is independent of others so only the order of results needs to be kept
+	type resolveResult struct {
+		spec models.JobSpec
+		err  error
+	}
+	results := make([]resolveResult, len(jobSpecs))
+	jobIndexes := make(chan int)
+	workers := DependencyResolverWorkers
+	if workers > len(jobSpecs) {
+		workers = len(jobSpecs)
 	}
 
-	// resolve specs in parallel
-	runner := parallel.NewRunner(parallel.WithTicket(ConcurrentTicketPerSec), parallel.WithLimit(ConcurrentLimit))
-	for _, jobSpec := range jobSpecs {
-		runner.Add(func(currentSpec models.JobSpec) func() (interface{}, error) {
-			return func() (interface{}, error) {
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobIndexes {
+				currentSpec := jobSpecs[idx]
+				assets, err := srv.assetCompiler(proj, currentSpec, srv.Now())
+				if err != nil {
+					results[idx].err = errors.Wrapf(err, "asset compilation for %s", currentSpec.Name)
+					continue
+				}
+				currentSpec.Assets = assets
 				resolvedSpec, err := srv.dependencyResolver.Resolve(proj, projectJobSpecRepo, currentSpec, progressObserver)
 				if err != nil {
-					return nil, errors.Wrapf(err, "failed to resolve dependency for %s", currentSpec.Name)
+					results[idx].err = errors.Wrapf(err, "failed to resolve dependency for %s", currentSpec.Name)
+					continue
 				}
-				return resolvedSpec, nil
+				results[idx].spec = resolvedSpec
 			}
-		}(jobSpec))
+		}()
 	}
+	for idx := range jobSpecs {
+		jobIndexes <- idx
+	}
+	close(jobIndexes)
+	wg.Wait()
 
-	for _, state := range runner.Run() {
-		if state.Err != nil {
-			resolvedErrors = multierror.Append(resolvedErrors, state.Err)
+	for _, result := range results {
+		if result.err != nil {
+			resolvedErrors = multierror.Append(resolvedErrors, result.err)
 		} else {
-			resolvedSpecs = append(resolvedSpecs, state.Val.(models.JobSpec))
+			resolvedSpecs = append(resolvedSpecs, result.spec)
 		}
 	}
 
 	return resolvedSpecs, resolvedErrors
 }
 
-// uploadSpecs compiles a Job and uploads it to the destination store
+// ExplainDependencies lists dependencies of a job along with why each of them
+// is resolved, with assets compiled as they are for deployment
+func (srv *Service) ExplainDependencies(namespace models.NamespaceSpec, jobSpec models.JobSpec) ([]models.JobSpecDependencyExplanation, error) {
+	assets, err := srv.assetCompiler(namespace.ProjectSpec, jobSpec, srv.Now())
+	if err != nil {
+		return nil, errors.Wrapf(err, "asset compilation for %s", jobSpec.Name)
+	}
+	jobSpec.Assets = assets
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(namespace.ProjectSpec)
+	explanations, err := srv.dependencyResolver.Explain(namespace.ProjectSpec, projectJobSpecRepo, jobSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to explain dependencies of %s", jobSpec.Name)
+	}
+	return explanations, nil
+}
+
+// uploadSpecs compiles a Job and uploads it to the destination store, jobs
+// compiled to the contents already stored are not uploaded again
+// uploadSpecs compiles and uploads jobs in order of their dependencies within
+// the namespace, upstream jobs first. New jobs are waited for till scheduler
+// loads them before jobs depending on them are uploaded, so sensors of the
+// downstream jobs don't look for jobs scheduler doesn't know yet. Downstream
+// jobs of new jobs failing to upload or load are not uploaded
 func (srv *Service) uploadSpecs(ctx context.Context, jobSpecs []models.JobSpec, jobRepo store.JobRepository,
-	namespace models.NamespaceSpec, progressObserver progress.Observer) error {
+	namespace models.NamespaceSpec, storedJobNames []string, progressObserver progress.Observer) error {
+	storedChecksums := map[string]string{}
+	if checksumRepo, ok := jobRepo.(store.JobChecksumRepository); ok {
+		checksums, err := checksumRepo.Checksums(ctx, namespace)
+		if err != nil {
+			logger.W(errors.Wrapf(err, "failed to fetch checksums of jobs of namespace %s, uploading all of them", namespace.Name))
+		} else {
+			storedChecksums = checksums
+		}
+	}
+	stored := map[string]bool{}
+	for _, name := range storedJobNames {
+		stored[name] = true
+	}
+
+	levels, upstreams := uploadLevels(jobSpecs)
+	hasDownstream := map[string]bool{}
+	for _, names := range upstreams {
+		for _, name := range names {
+			hasDownstream[name] = true
+		}
+	}
+
+	// new jobs downstream jobs can't depend on yet, with the reason
+	notReady := map[string]error{}
+	for _, level := range levels {
+		var toUpload []models.JobSpec
+		for _, jobSpec := range level {
+			if err := upstreamNotReady(upstreams[jobSpec.Name], notReady); err != nil {
+				srv.notifyProgress(progressObserver, &EventJobUpload{
+					Job: jobSpec,
+					Err: err,
+				})
+				if !stored[jobSpec.Name] {
+					notReady[jobSpec.Name] = err
+				}
+				continue
+			}
+			toUpload = append(toUpload, jobSpec)
+		}
+
+		var toLoad []string
+		for idx, err := range srv.uploadLevel(ctx, toUpload, jobRepo, namespace, storedChecksums, progressObserver) {
+			jobSpec := toUpload[idx]
+			if stored[jobSpec.Name] {
+				continue
+			}
+			if err != nil {
+				notReady[jobSpec.Name] = err
+			} else if hasDownstream[jobSpec.Name] {
+				toLoad = append(toLoad, jobSpec.Name)
+			}
+		}
+		for name, err := range srv.waitForJobsLoaded(ctx, namespace.ProjectSpec, toLoad) {
+			notReady[name] = err
+		}
+	}
+	return nil
+}
+
+// uploadLevel compiles and uploads jobs concurrently, notifying the result
+// of each, returns errors of jobs in their order
+func (srv *Service) uploadLevel(ctx context.Context, jobSpecs []models.JobSpec, jobRepo store.JobRepository,
+	namespace models.NamespaceSpec, storedChecksums map[string]string, progressObserver progress.Observer) []error {
+	uploadErrors := make([]error, len(jobSpecs))
+	if len(jobSpecs) == 0 {
+		return uploadErrors
+	}
+	unchanged := make([]bool, len(jobSpecs))
 	runner := parallel.NewRunner(parallel.WithTicket(ConcurrentTicketPerSec))
-	for _, jobSpec := range jobSpecs {
-		runner.Add(func(currentSpec models.JobSpec) func() (interface{}, error) {
+	for idx, jobSpec := range jobSpecs {
+		runner.Add(func(idx int, currentSpec models.JobSpec) func() (interface{}, error) {
 			return func() (interface{}, error) {
-				compiledJob, err := srv.compiler.Compile(namespace, currentSpec)
+				compiledJob, err := srv.compileArtifact(namespace, currentSpec)
 				if err != nil {
 					return nil, err
 				}
@@ -382,23 +793,141 @@ func (srv *Service) uploadSpecs(ctx context.Context, jobSpecs []models.JobSpec,
 					Name: currentSpec.Name,
 				})
 
+				if checksum, ok := storedChecksums[compiledJob.Name]; ok && checksum == contentChecksum(compiledJob.Contents) {
+					unchanged[idx] = true
+					return nil, nil
+				}
 				if err = jobRepo.Save(ctx, compiledJob); err != nil {
 					return nil, err
 				}
 				return nil, nil
 			}
-		}(jobSpec))
+		}(idx, jobSpec))
 	}
 
 	for runIdx, state := range runner.Run() {
 		srv.notifyProgress(progressObserver, &EventJobUpload{
-			Job: jobSpecs[runIdx],
-			Err: state.Err,
+			Job:       jobSpecs[runIdx],
+			Err:       state.Err,
+			Unchanged: unchanged[runIdx],
 		})
+		uploadErrors[runIdx] = state.Err
+	}
+	return uploadErrors
+}
+
+// uploadLevels groups jobs in levels, each job depending only on jobs of the
+// namespace in levels before it. It also returns the upstream jobs of each
+// job within the namespace
+func uploadLevels(jobSpecs []models.JobSpec) ([][]models.JobSpec, map[string][]string) {
+	inNamespace := map[string]bool{}
+	for _, jobSpec := range jobSpecs {
+		inNamespace[jobSpec.Name] = true
+	}
+	upstreams := map[string][]string{}
+	for _, jobSpec := range jobSpecs {
+		for _, dep := range jobSpec.Dependencies {
+			if dep.Type != models.JobSpecDependencyTypeIntra || dep.Job == nil {
+				continue
+			}
+			if inNamespace[dep.Job.Name] && dep.Job.Name != jobSpec.Name {
+				upstreams[jobSpec.Name] = append(upstreams[jobSpec.Name], dep.Job.Name)
+			}
+		}
+		sort.Strings(upstreams[jobSpec.Name])
+	}
+
+	var levels [][]models.JobSpec
+	placed := map[string]bool{}
+	remaining := jobSpecs
+	for len(remaining) > 0 {
+		var level, rest []models.JobSpec
+		for _, jobSpec := range remaining {
+			ready := true
+			for _, upstream := range upstreams[jobSpec.Name] {
+				if !placed[upstream] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				level = append(level, jobSpec)
+			} else {
+				rest = append(rest, jobSpec)
+			}
+		}
+		if len(level) == 0 {
+			// cyclic dependencies are rejected while resolving priorities,
+			// jobs left are uploaded together anyway
+			level, rest = rest, nil
+		}
+		for _, jobSpec := range level {
+			placed[jobSpec.Name] = true
+		}
+		levels = append(levels, level)
+		remaining = rest
+	}
+	return levels, upstreams
+}
+
+func upstreamNotReady(upstreams []string, notReady map[string]error) error {
+	for _, upstream := range upstreams {
+		if err, ok := notReady[upstream]; ok {
+			return errors.Wrapf(err, "not uploaded as upstream job %s is not ready", upstream)
+		}
 	}
 	return nil
 }
 
+// waitForJobsLoaded polls scheduler till it loads the jobs, returning the
+// ones not loaded in UpstreamLoadTimeout. Jobs which can't be checked, e.g.
+// scheduler of the project is not reachable, are not waited for
+func (srv *Service) waitForJobsLoaded(ctx context.Context, projSpec models.ProjectSpec, jobNames []string) map[string]error {
+	notLoaded := map[string]error{}
+	if srv.scheduler == nil || len(jobNames) == 0 {
+		return notLoaded
+	}
+	ctx, cancel := context.WithTimeout(ctx, UpstreamLoadTimeout)
+	defer cancel()
+	ticker := time.NewTicker(UpstreamLoadPollInterval)
+	defer ticker.Stop()
+
+	pending := jobNames
+	for {
+		var stillPending []string
+		for _, jobName := range pending {
+			loaded, err := srv.scheduler.IsJobLoaded(ctx, projSpec, jobName)
+			if err != nil {
+				logger.W(errors.Wrapf(err, "failed to check if job %s is loaded by scheduler, not waiting for it", jobName))
+				continue
+			}
+			if !loaded {
+				stillPending = append(stillPending, jobName)
+			}
+		}
+		pending = stillPending
+		if len(pending) == 0 {
+			return notLoaded
+		}
+
+		select {
+		case <-ctx.Done():
+			for _, jobName := range pending {
+				notLoaded[jobName] = errors.Errorf("job %s is not loaded by scheduler in %s", jobName, UpstreamLoadTimeout)
+			}
+			return notLoaded
+		case <-ticker.C:
+		}
+	}
+}
+
+// contentChecksum is hex encoded md5 of contents, the checksum object
+// stores keep of objects
+func contentChecksum(contents []byte) string {
+	sum := md5.Sum(contents)
+	return hex.EncodeToString(sum[:])
+}
+
 func (srv *Service) publishMetadata(namespace models.NamespaceSpec, jobSpecs []models.JobSpec,
 	progressObserver progress.Observer) error {
 	if srv.metaSvcFactory == nil {
@@ -485,7 +1014,7 @@ func NewService(jobSpecRepoFactory SpecRepoFactory, jobRepoFact JobRepoFactory,
 	compiler models.JobCompiler, assetCompiler AssetCompiler, dependencyResolver DependencyResolver,
 	priorityResolver PriorityResolver, metaSvcFactory meta.MetaSvcFactory,
 	projectJobSpecRepoFactory ProjectJobSpecRepoFactory,
-	replayManager ReplayManager,
+	replayManager ReplayManager, scheduler models.SchedulerUnit,
 ) *Service {
 	return &Service{
 		jobSpecRepoFactory:        jobSpecRepoFactory,
@@ -496,12 +1025,26 @@ func NewService(jobSpecRepoFactory SpecRepoFactory, jobRepoFact JobRepoFactory,
 		metaSvcFactory:            metaSvcFactory,
 		projectJobSpecRepoFactory: projectJobSpecRepoFactory,
 		replayManager:             replayManager,
+		scheduler:                 scheduler,
 
 		assetCompiler: assetCompiler,
 		Now:           time.Now,
 	}
 }
 
+// SetLocker makes service lock jobs it changes, so they don't change while
+// replays of them are in progress. Jobs aren't locked without a locker
+func (srv *Service) SetLocker(locker *JobLocker) {
+	srv.locker = locker
+}
+
+// SetProjectRepoFactory makes service rewrite dependencies of jobs of other
+// projects on the jobs it renames. Only jobs of the project of the renamed
+// job are rewritten without it
+func (srv *Service) SetProjectRepoFactory(projectRepoFactory ProjectRepoFactory) {
+	srv.projectRepoFactory = projectRepoFactory
+}
+
 type (
 	// EventJobSpecFetch represents a specification being
 	// read from the storage
@@ -527,6 +1070,10 @@ type (
 	EventJobUpload struct {
 		Job models.JobSpec
 		Err error
+
+		// Unchanged is set if upload was skipped as the job stored
+		// has the same contents
+		Unchanged bool
 	}
 
 	// EventJobRemoteDelete signifies that a
@@ -563,6 +1110,9 @@ func (e *EventJobUpload) String() string {
 	if e.Err != nil {
 		return fmt.Sprintf("uploading: %s, failed with error): %s", e.Job.Name, e.Err.Error())
 	}
+	if e.Unchanged {
+		return fmt.Sprintf("unchanged: %s", e.Job.Name)
+	}
 	return fmt.Sprintf("uploaded: %s", e.Job.Name)
 }
 
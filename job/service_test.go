@@ -8,17 +8,29 @@ import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
+	"github.com/odpf/optimus/core/progress"
 	"github.com/odpf/optimus/job"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 	testMock "github.com/stretchr/testify/mock"
 )
 
+type uploadErrorObserver struct {
+	errs map[string]error
+}
+
+func (obs *uploadErrorObserver) Notify(e progress.Event) {
+	if evt, ok := e.(*job.EventJobUpload); ok && evt.Err != nil {
+		obs.errs[evt.Job.Name] = evt.Err
+	}
+}
+
 func TestService(t *testing.T) {
 	ctx := context.Background()
 
-	dumpAssets := func(jobSpec models.JobSpec, _ time.Time) (models.JobAssets, error) {
+	dumpAssets := func(_ models.ProjectSpec, jobSpec models.JobSpec, _ time.Time) (models.JobAssets, error) {
 		return jobSpec.Assets, nil
 	}
 
@@ -53,7 +65,7 @@ func TestService(t *testing.T) {
 			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
 			defer projJobSpecRepoFac.AssertExpectations(t)
 
-			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil)
 			err := svc.Create(namespaceSpec, jobSpec)
 			assert.Nil(t, err)
 		})
@@ -85,10 +97,142 @@ func TestService(t *testing.T) {
 			repoFac.On("New", namespaceSpec).Return(repo)
 			defer repoFac.AssertExpectations(t)
 
-			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil)
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
 			err := svc.Create(namespaceSpec, jobSpec)
 			assert.NotNil(t, err)
 		})
+
+		t.Run("should reject changes to a job while it's replayed", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-team-1",
+				ProjectSpec: models.ProjectSpec{Name: "proj"},
+			}
+			savedSpec := models.JobSpec{
+				Version: 1,
+				Name:    "test",
+				Owner:   "optimus",
+				Schedule: models.JobSpecSchedule{
+					StartDate: time.Date(2020, 12, 02, 0, 0, 0, 0, time.UTC),
+					Interval:  "@daily",
+				},
+			}
+			jobSpec := savedSpec
+			jobSpec.Schedule.Interval = "@hourly"
+
+			repo := new(mock.JobSpecRepository)
+			repo.On("GetByName", "test").Return(savedSpec, nil)
+			defer repo.AssertExpectations(t)
+
+			repoFac := new(mock.JobSpecRepoFactory)
+			repoFac.On("New", namespaceSpec).Return(repo)
+			defer repoFac.AssertExpectations(t)
+
+			lockRepo := new(mock.JobLockRepository)
+			lockRepo.On("Acquire", testMock.AnythingOfType("models.JobLock"), []string{"test"}, job.JobLockTTL).Return(&models.JobLock{
+				Project:   "proj",
+				Job:       "test",
+				Operation: job.JobOperationReplay,
+				HolderID:  "replay-id",
+			}, nil)
+			defer lockRepo.AssertExpectations(t)
+
+			svc := job.NewService(repoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
+			svc.SetLocker(job.NewJobLocker(lockRepo))
+			err := svc.Create(namespaceSpec, jobSpec)
+			assert.True(t, errors.Is(err, job.ErrJobLocked))
+			assert.Equal(t, "job test of project proj is locked by replay replay-id in progress, try again once it's done", err.Error())
+
+			repo.On("Save", savedSpec).Return(nil)
+			assert.Nil(t, svc.Create(namespaceSpec, savedSpec))
+		})
+
+		t.Run("should fail if ownership is missing for a production project", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-team-1",
+				ProjectSpec: models.ProjectSpec{
+					Name: "proj",
+					Config: map[string]string{
+						models.ProjectEnvironmentKey: models.ProjectEnvironmentProduction,
+					},
+				},
+			}
+			jobSpec := models.JobSpec{
+				Version: 1,
+				Name:    "test",
+				Owner:   "optimus",
+				Ownership: models.JobSpecOwnership{
+					Email: "optimus@example.io",
+				},
+			}
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
+			err := svc.Create(namespaceSpec, jobSpec)
+			assert.Equal(t, "job test of a production project is missing ownership fields: team, slack_channel", err.Error())
+		})
+
+		t.Run("should fail if pinned task version is not installed on the server", func(t *testing.T) {
+			execUnit := new(mock.BasePlugin)
+			execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name:          "bq2bq",
+				PluginVersion: "1.1.0",
+			}, nil)
+			defer execUnit.AssertExpectations(t)
+
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-team-1",
+				ProjectSpec: models.ProjectSpec{
+					Name: "proj",
+				},
+			}
+			jobSpec := models.JobSpec{
+				Version: 1,
+				Name:    "test",
+				Task: models.JobSpecTask{
+					Unit:    &models.Plugin{Base: execUnit},
+					Version: "1.0.0",
+				},
+			}
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
+			err := svc.Create(namespaceSpec, jobSpec)
+			assert.True(t, errors.Is(err, models.ErrUnsupportedPluginVersion))
+			assert.Equal(t, "job test pins bq2bq plugin version 1.0.0 but server has 1.1.0: unsupported plugin version requested", err.Error())
+		})
+
+		t.Run("should fail if task plugin rejects config of the job", func(t *testing.T) {
+			namespaceSpec := models.NamespaceSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "dev-team-1",
+				ProjectSpec: models.ProjectSpec{
+					Name: "proj",
+				},
+			}
+			validatorMod := new(mock.ConfigValidatorMod)
+			defer validatorMod.AssertExpectations(t)
+			jobSpec := models.JobSpec{
+				Version: 1,
+				Name:    "test",
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{ValidatorMod: validatorMod},
+					Config: models.JobSpecConfigs{
+						{Name: "LOAD_METHOD", Value: "UPSERT"},
+					},
+				},
+			}
+			validatorMod.On("ValidateConfig", context.TODO(), models.ValidateConfigRequest{
+				Config: models.PluginConfigs{{Name: "LOAD_METHOD", Value: "UPSERT"}},
+				Assets: models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+			}).Return(&models.ValidateConfigResponse{
+				Errors: []string{"LOAD_METHOD should be one of APPEND, REPLACE, MERGE", "TABLE is required"},
+			}, nil)
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
+			err := svc.Create(namespaceSpec, jobSpec)
+			assert.Equal(t, "invalid config of job test: LOAD_METHOD should be one of APPEND, REPLACE, MERGE; TABLE is required", err.Error())
+		})
 	})
 
 	t.Run("Check", func(t *testing.T) {
@@ -118,7 +262,7 @@ func TestService(t *testing.T) {
 			compiler.On("Compile", namespaceSpec, currentSpec).Return(models.Job{}, nil)
 			defer compiler.AssertExpectations(t)
 
-			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil)
+			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil, nil)
 			err := service.Check(namespaceSpec, []models.JobSpec{currentSpec}, nil)
 			assert.Nil(t, err)
 		})
@@ -151,10 +295,64 @@ func TestService(t *testing.T) {
 			compiler.On("Compile", namespaceSpec, currentSpec).Return(models.Job{}, nil)
 			defer compiler.AssertExpectations(t)
 
-			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil)
+			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil, nil)
 			err := service.Check(namespaceSpec, []models.JobSpec{currentSpec}, nil)
 			assert.Nil(t, err)
 		})
+		t.Run("should fail check without compiling if schedule interval is invalid", func(t *testing.T) {
+			currentSpec := models.JobSpec{
+				Version: 1,
+				Name:    "test",
+				Owner:   "optimus",
+				Schedule: models.JobSpecSchedule{
+					StartDate: time.Date(2020, 12, 02, 0, 0, 0, 0, time.UTC),
+					Interval:  "@every 30s",
+				},
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{},
+				},
+				Dependencies: map[string]models.JobSpecDependency{},
+			}
+			compiler := new(mock.Compiler)
+
+			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil, nil)
+			err := service.Check(namespaceSpec, []models.JobSpec{currentSpec}, nil)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "invalid schedule interval of test")
+			compiler.AssertNotCalled(t, "Compile", namespaceSpec, currentSpec)
+		})
+		t.Run("should fail check without compiling if task plugin rejects config", func(t *testing.T) {
+			validatorMod := new(mock.ConfigValidatorMod)
+			defer validatorMod.AssertExpectations(t)
+			currentSpec := models.JobSpec{
+				Version: 1,
+				Name:    "test",
+				Owner:   "optimus",
+				Schedule: models.JobSpecSchedule{
+					StartDate: time.Date(2020, 12, 02, 0, 0, 0, 0, time.UTC),
+					Interval:  "@daily",
+				},
+				Task: models.JobSpecTask{
+					Unit: &models.Plugin{ValidatorMod: validatorMod},
+				},
+				Dependencies: map[string]models.JobSpecDependency{},
+			}
+			validatorMod.On("ValidateConfig", context.TODO(), models.ValidateConfigRequest{
+				Config: models.PluginConfigs{}.FromJobSpec(currentSpec.Task.Config),
+				Assets: models.PluginAssets{}.FromJobSpec(currentSpec.Assets),
+				PluginOptions: models.PluginOptions{
+					DryRun: true,
+				},
+			}).Return(&models.ValidateConfigResponse{Errors: []string{"TABLE is required"}}, nil)
+
+			compiler := new(mock.Compiler)
+			defer compiler.AssertExpectations(t)
+
+			service := job.NewService(nil, nil, compiler, dumpAssets, nil, nil, nil, nil, nil, nil)
+			err := service.Check(namespaceSpec, []models.JobSpec{currentSpec}, nil)
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "invalid config of job test: TABLE is required")
+		})
 	})
 
 	t.Run("Sync", func(t *testing.T) {
@@ -260,9 +458,164 @@ func TestService(t *testing.T) {
 				jobRepo.On("Save", ctx, compiledJob).Return(nil)
 			}
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil)
+			err := svc.Sync(ctx, namespaceSpec, nil)
+			assert.Nil(t, err)
+		})
+
+		t.Run("should skip uploading jobs compiled to the contents already stored", func(t *testing.T) {
+			jobSpecs := []models.JobSpec{
+				{Version: 1, Name: "test", Owner: "optimus"},
+				{Version: 1, Name: "test-2", Owner: "optimus"},
+			}
+			unchangedJob := models.Job{Name: "test", Contents: []byte(`some string`), NamespaceID: namespaceSpec.Name}
+			changedJob := models.Job{Name: "test-2", Contents: []byte(`changed string`), NamespaceID: namespaceSpec.Name}
+
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+			jobRepo := new(mock.ChecksumJobRepository)
+			jobRepo.On("Checksums", ctx, namespaceSpec).Return(map[string]string{
+				// md5 of contents of both jobs before test-2 changed
+				"test":   "5ac749fbeec93607fc28d666be85e73a",
+				"test-2": "5ac749fbeec93607fc28d666be85e73a",
+			}, nil)
+			jobRepo.On("Save", ctx, changedJob).Return(nil)
+			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"test", "test-2"}, nil)
+			defer jobRepo.AssertExpectations(t)
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+
+			depenResolver := new(mock.DependencyResolver)
+			priorityResolver := new(mock.PriorityResolver)
+			compiler := new(mock.Compiler)
+			for idx, compiledJob := range []models.Job{unchangedJob, changedJob} {
+				depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecs[idx], nil).Return(jobSpecs[idx], nil)
+				compiler.On("Compile", namespaceSpec, jobSpecs[idx]).Return(compiledJob, nil)
+			}
+			priorityResolver.On("Resolve", jobSpecs).Return(jobSpecs, nil)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil)
 			err := svc.Sync(ctx, namespaceSpec, nil)
 			assert.Nil(t, err)
+			jobRepo.AssertNotCalled(t, "Save", ctx, unchangedJob)
+		})
+
+		t.Run("should upload new upstream jobs and wait for scheduler to load them before their downstream", func(t *testing.T) {
+			defer func(interval time.Duration) { job.UpstreamLoadPollInterval = interval }(job.UpstreamLoadPollInterval)
+			job.UpstreamLoadPollInterval = time.Millisecond
+
+			upstreamSpec := models.JobSpec{Version: 1, Name: "upstream", Owner: "optimus"}
+			downstreamSpec := models.JobSpec{Version: 1, Name: "downstream", Owner: "optimus",
+				Dependencies: map[string]models.JobSpecDependency{
+					"upstream": {Job: &upstreamSpec, Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+			jobSpecs := []models.JobSpec{downstreamSpec, upstreamSpec}
+			upstreamJob := models.Job{Name: "upstream", Contents: []byte(`upstream`), NamespaceID: namespaceSpec.Name}
+			downstreamJob := models.Job{Name: "downstream", Contents: []byte(`downstream`), NamespaceID: namespaceSpec.Name}
+
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+			var uploaded []string
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"downstream"}, nil)
+			for _, compiledJob := range []models.Job{upstreamJob, downstreamJob} {
+				jobRepo.On("Save", ctx, compiledJob).Run(func(args testMock.Arguments) {
+					uploaded = append(uploaded, args.Get(1).(models.Job).Name)
+				}).Return(nil)
+			}
+			defer jobRepo.AssertExpectations(t)
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+
+			depenResolver := new(mock.DependencyResolver)
+			priorityResolver := new(mock.PriorityResolver)
+			compiler := new(mock.Compiler)
+			for idx, compiledJob := range []models.Job{downstreamJob, upstreamJob} {
+				depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecs[idx], nil).Return(jobSpecs[idx], nil)
+				compiler.On("Compile", namespaceSpec, jobSpecs[idx]).Return(compiledJob, nil)
+			}
+			priorityResolver.On("Resolve", jobSpecs).Return(jobSpecs, nil)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("IsJobLoaded", testMock.Anything, projSpec, "upstream").Return(false, nil).Once()
+			scheduler.On("IsJobLoaded", testMock.Anything, projSpec, "upstream").Return(true, nil).Once()
+			defer scheduler.AssertExpectations(t)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, scheduler)
+			err := svc.Sync(ctx, namespaceSpec, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"upstream", "downstream"}, uploaded)
+		})
+
+		t.Run("should not upload downstream jobs of new jobs scheduler doesn't load", func(t *testing.T) {
+			defer func(timeout, interval time.Duration) {
+				job.UpstreamLoadTimeout, job.UpstreamLoadPollInterval = timeout, interval
+			}(job.UpstreamLoadTimeout, job.UpstreamLoadPollInterval)
+			job.UpstreamLoadTimeout, job.UpstreamLoadPollInterval = 20*time.Millisecond, time.Millisecond
+
+			upstreamSpec := models.JobSpec{Version: 1, Name: "upstream", Owner: "optimus"}
+			downstreamSpec := models.JobSpec{Version: 1, Name: "downstream", Owner: "optimus",
+				Dependencies: map[string]models.JobSpecDependency{
+					"upstream": {Job: &upstreamSpec, Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+			jobSpecs := []models.JobSpec{upstreamSpec, downstreamSpec}
+			upstreamJob := models.Job{Name: "upstream", Contents: []byte(`upstream`), NamespaceID: namespaceSpec.Name}
+
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{}, nil)
+			jobRepo.On("Save", ctx, upstreamJob).Return(nil)
+			defer jobRepo.AssertExpectations(t)
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+
+			depenResolver := new(mock.DependencyResolver)
+			priorityResolver := new(mock.PriorityResolver)
+			compiler := new(mock.Compiler)
+			for _, jobSpec := range jobSpecs {
+				depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpec, nil).Return(jobSpec, nil)
+			}
+			compiler.On("Compile", namespaceSpec, upstreamSpec).Return(upstreamJob, nil)
+			priorityResolver.On("Resolve", jobSpecs).Return(jobSpecs, nil)
+
+			scheduler := new(mock.Scheduler)
+			scheduler.On("IsJobLoaded", testMock.Anything, projSpec, "upstream").Return(false, nil)
+
+			observer := &uploadErrorObserver{errs: map[string]error{}}
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, scheduler)
+			err := svc.Sync(ctx, namespaceSpec, observer)
+			assert.Nil(t, err)
+			assert.Equal(t, "not uploaded as upstream job upstream is not ready: job upstream is not loaded by scheduler in 20ms",
+				observer.errs["downstream"].Error())
+			compiler.AssertNotCalled(t, "Compile", namespaceSpec, downstreamSpec)
 		})
 
 		t.Run("should delete job specs from target store if there are existing specs that are no longer present in job specs", func(t *testing.T) {
@@ -376,7 +729,7 @@ func TestService(t *testing.T) {
 			// delete unwanted
 			jobRepo.On("Delete", ctx, namespaceSpec, jobs[1].Name).Return(nil)
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil)
 			err := svc.Sync(ctx, namespaceSpec, nil)
 			assert.Nil(t, err)
 		})
@@ -424,7 +777,7 @@ func TestService(t *testing.T) {
 				errors.New("error test-2"))
 			defer depenResolver.AssertExpectations(t)
 
-			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, depenResolver, nil, nil, projJobSpecRepoFac, nil, nil)
 			err := svc.Sync(ctx, namespaceSpec, nil)
 			assert.NotNil(t, err)
 			assert.Contains(t, err.Error(), "2 errors occurred")
@@ -532,7 +885,7 @@ func TestService(t *testing.T) {
 				jobRepo.On("Save", ctx, compiledJob).Return(nil)
 			}
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, metaSvcFact, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, metaSvcFact, projJobSpecRepoFac, nil, nil)
 			err := svc.Sync(ctx, namespaceSpec, nil)
 			assert.Nil(t, err)
 		})
@@ -606,7 +959,7 @@ func TestService(t *testing.T) {
 			// delete unwanted
 			jobSpecRepo.On("Delete", jobSpecsBase[0].Name).Return(nil)
 
-			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil)
 			err := svc.KeepOnly(namespaceSpec, toKeep, nil)
 			assert.Nil(t, err)
 		})
@@ -711,7 +1064,7 @@ func TestService(t *testing.T) {
 				compiler.On("Compile", namespaceSpec, jobSpecsAfterPriorityResolve[idx]).Return(compiledJob, nil)
 			}
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil)
 			compiledJob, err := svc.Dump(namespaceSpec, jobSpecsBase[0])
 			assert.Nil(t, err)
 			assert.Equal(t, "come string", string(compiledJob.Contents))
@@ -719,6 +1072,34 @@ func TestService(t *testing.T) {
 		})
 	})
 
+	t.Run("Unschedule", func(t *testing.T) {
+		projSpec := models.ProjectSpec{
+			Name: "proj",
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "dev-team-1",
+			ProjectSpec: projSpec,
+		}
+
+		t.Run("should delete compiled jobs of namespace leaving their specs and persisted jobs", func(t *testing.T) {
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"test", job.PersistJobPrefix + "keep"}, nil)
+			jobRepo.On("Delete", ctx, namespaceSpec, "test").Return(nil)
+			defer jobRepo.AssertExpectations(t)
+
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+			defer jobRepoFac.AssertExpectations(t)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			defer jobSpecRepoFac.AssertExpectations(t)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
+			err := svc.Unschedule(ctx, namespaceSpec, nil)
+			assert.Nil(t, err)
+		})
+	})
 	t.Run("Delete", func(t *testing.T) {
 		projSpec := models.ProjectSpec{
 			Name: "proj",
@@ -823,7 +1204,7 @@ func TestService(t *testing.T) {
 				jobRepo.On("Save", ctx, compiledJob).Return(nil)
 			}
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil)
 			err := svc.Delete(ctx, namespaceSpec, jobSpecsBase[0])
 			assert.Nil(t, err)
 		})
@@ -912,10 +1293,250 @@ func TestService(t *testing.T) {
 			compiler := new(mock.Compiler)
 			defer compiler.AssertExpectations(t)
 
-			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil)
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil, projJobSpecRepoFac, nil, nil)
 			err := svc.Delete(ctx, namespaceSpec, jobSpecsBase[0])
 			assert.NotNil(t, err)
 			assert.Equal(t, "cannot delete job test since it's dependency of job downstream-test", err.Error())
 		})
 	})
+	t.Run("Rename", func(t *testing.T) {
+		projSpec := models.ProjectSpec{
+			Name: "proj",
+		}
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "dev-team-1",
+			ProjectSpec: projSpec,
+		}
+		upstream := models.JobSpec{Version: 1, Name: "upstream", Owner: "optimus"}
+
+		t.Run("should rename job, rewrite dependencies on it and replace it in scheduler", func(t *testing.T) {
+			downstream := models.JobSpec{
+				Version: 1,
+				Name:    "downstream",
+				Owner:   "optimus",
+				Dependencies: map[string]models.JobSpecDependency{
+					"upstream": {Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+			renamed := upstream
+			renamed.Name = "source"
+			rewritten := downstream
+			rewritten.Dependencies = map[string]models.JobSpecDependency{
+				"source": {Type: models.JobSpecDependencyTypeIntra},
+			}
+
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetByName", "upstream").Return(upstream, nil)
+			jobSpecRepo.On("Rename", "upstream", "source", []models.JobSpec{rewritten}).Return(nil)
+			jobSpecRepo.On("GetAll").Return([]models.JobSpec{}, nil)
+			defer jobSpecRepo.AssertExpectations(t)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+			defer jobSpecRepoFac.AssertExpectations(t)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", "source").Return(nil, store.ErrResourceNotFound)
+			projectJobSpecRepo.On("GetByName", "downstream").Return(downstream, namespaceSpec, nil)
+			projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{renamed, downstream}, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, testMock.Anything, nil).Return(models.JobSpec{}, nil)
+			defer depenResolver.AssertExpectations(t)
+
+			priorityResolver := new(mock.PriorityResolver)
+			priorityResolver.On("Resolve", testMock.Anything).Return([]models.JobSpec{}, nil)
+			defer priorityResolver.AssertExpectations(t)
+
+			// the job is removed from scheduler under its old name
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"upstream"}, nil)
+			jobRepo.On("Delete", ctx, namespaceSpec, "upstream").Return(nil)
+			defer jobRepo.AssertExpectations(t)
+
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+			defer jobRepoFac.AssertExpectations(t)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, nil, dumpAssets, depenResolver, priorityResolver, nil,
+				projJobSpecRepoFac, nil, nil)
+			dependents, err := svc.Rename(ctx, namespaceSpec, "upstream", "source", nil)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"downstream"}, dependents)
+		})
+		t.Run("should fail if a job of the project has the new name", func(t *testing.T) {
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetByName", "upstream").Return(upstream, nil)
+			defer jobSpecRepo.AssertExpectations(t)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", "taken").Return(models.JobSpec{Name: "taken"}, namespaceSpec, nil)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil)
+			_, err := svc.Rename(ctx, namespaceSpec, "upstream", "taken", nil)
+			assert.True(t, errors.Is(err, job.ErrJobExists))
+			jobSpecRepo.AssertNotCalled(t, "Rename", testMock.Anything, testMock.Anything, testMock.Anything)
+		})
+		t.Run("should rewrite dependencies of jobs of other projects by project/name", func(t *testing.T) {
+			otherProjSpec := models.ProjectSpec{Name: "other-proj"}
+			otherNamespaceSpec := models.NamespaceSpec{
+				ID:          uuid.Must(uuid.NewRandom()),
+				Name:        "dev-team-2",
+				ProjectSpec: otherProjSpec,
+			}
+			// jobs of other projects depending on a job of their own
+			// project of the same name are left as is
+			consumer := models.JobSpec{
+				Version: 1,
+				Name:    "consumer",
+				Owner:   "optimus",
+				Dependencies: map[string]models.JobSpecDependency{
+					"proj/upstream": {Type: models.JobSpecDependencyTypeInter},
+					"upstream":      {Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+			rewritten := consumer
+			rewritten.Dependencies = map[string]models.JobSpecDependency{
+				"proj/source": {Type: models.JobSpecDependencyTypeInter},
+				"upstream":    {Type: models.JobSpecDependencyTypeIntra},
+			}
+
+			projectRepository := new(mock.ProjectRepository)
+			projectRepository.On("GetAll").Return([]models.ProjectSpec{projSpec, otherProjSpec}, nil)
+			defer projectRepository.AssertExpectations(t)
+
+			projectRepoFactory := new(mock.ProjectRepoFactory)
+			projectRepoFactory.On("New").Return(projectRepository)
+			defer projectRepoFactory.AssertExpectations(t)
+
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetByName", "upstream").Return(upstream, nil)
+			jobSpecRepo.On("Rename", "upstream", "source", []models.JobSpec{rewritten}).Return(nil)
+			jobSpecRepo.On("GetAll").Return([]models.JobSpec{}, nil)
+			defer jobSpecRepo.AssertExpectations(t)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+			jobSpecRepoFac.On("New", otherNamespaceSpec).Return(jobSpecRepo)
+			defer jobSpecRepoFac.AssertExpectations(t)
+
+			renamed := upstream
+			renamed.Name = "source"
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", "source").Return(nil, store.ErrResourceNotFound)
+			projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{renamed}, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			otherProjectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			otherProjectJobSpecRepo.On("GetByName", "consumer").Return(consumer, otherNamespaceSpec, nil)
+			otherProjectJobSpecRepo.On("GetAll").Return([]models.JobSpec{consumer}, nil)
+			defer otherProjectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+			projJobSpecRepoFac.On("New", otherProjSpec).Return(otherProjectJobSpecRepo)
+			defer projJobSpecRepoFac.AssertExpectations(t)
+
+			depenResolver := new(mock.DependencyResolver)
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, testMock.Anything, nil).Return(models.JobSpec{}, nil)
+			depenResolver.On("Resolve", otherProjSpec, otherProjectJobSpecRepo, testMock.Anything, nil).Return(models.JobSpec{}, nil)
+			defer depenResolver.AssertExpectations(t)
+
+			priorityResolver := new(mock.PriorityResolver)
+			priorityResolver.On("Resolve", testMock.Anything).Return([]models.JobSpec{}, nil)
+			defer priorityResolver.AssertExpectations(t)
+
+			// namespace of the consumer is synced after the one of the job
+			jobRepo := new(mock.JobRepository)
+			jobRepo.On("ListNames", ctx, namespaceSpec).Return([]string{"upstream"}, nil).Once()
+			jobRepo.On("Delete", ctx, namespaceSpec, "upstream").Return(nil)
+			jobRepo.On("ListNames", ctx, otherNamespaceSpec).Return([]string{}, nil).Once()
+			defer jobRepo.AssertExpectations(t)
+
+			jobRepoFac := new(mock.JobRepoFactory)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+			jobRepoFac.On("New", ctx, otherProjSpec).Return(jobRepo, nil)
+			defer jobRepoFac.AssertExpectations(t)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, nil, dumpAssets, depenResolver, priorityResolver, nil,
+				projJobSpecRepoFac, nil, nil)
+			svc.SetProjectRepoFactory(projectRepoFactory)
+			dependents, err := svc.Rename(ctx, namespaceSpec, "upstream", "source", nil)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"other-proj/consumer"}, dependents)
+		})
+		t.Run("should not sync anything if the job and its dependents fail to be saved", func(t *testing.T) {
+			downstream := models.JobSpec{
+				Version: 1,
+				Name:    "downstream",
+				Owner:   "optimus",
+				Dependencies: map[string]models.JobSpecDependency{
+					"upstream": {Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+			rewritten := downstream
+			rewritten.Dependencies = map[string]models.JobSpecDependency{
+				"source": {Type: models.JobSpecDependencyTypeIntra},
+			}
+
+			// repo saves the job along with its dependents or none of them
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetByName", "upstream").Return(upstream, nil)
+			jobSpecRepo.On("Rename", "upstream", "source", []models.JobSpec{rewritten}).
+				Return(errors.New("failed to rewrite dependencies of job downstream"))
+			defer jobSpecRepo.AssertExpectations(t)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", "source").Return(nil, store.ErrResourceNotFound)
+			projectJobSpecRepo.On("GetByName", "downstream").Return(downstream, namespaceSpec, nil)
+			projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{upstream, downstream}, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+			projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+			// jobs are neither saved one by one nor synced
+			jobRepoFac := new(mock.JobRepoFactory)
+			defer jobRepoFac.AssertExpectations(t)
+
+			svc := job.NewService(jobSpecRepoFac, jobRepoFac, nil, dumpAssets, nil, nil, nil, projJobSpecRepoFac, nil, nil)
+			_, err := svc.Rename(ctx, namespaceSpec, "upstream", "source", nil)
+			assert.Equal(t, "failed to rename job upstream: failed to rewrite dependencies of job downstream", err.Error())
+			jobSpecRepo.AssertNotCalled(t, "Save", testMock.Anything)
+		})
+		t.Run("should fail if the new name violates naming policy of the project", func(t *testing.T) {
+			namespaceSpec := namespaceSpec
+			namespaceSpec.ProjectSpec.Config = map[string]string{models.ProjectNamingKeyPrefix + "JOB": "[a-z-]+"}
+
+			svc := job.NewService(nil, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
+			_, err := svc.Rename(ctx, namespaceSpec, "upstream", "Source", nil)
+			assert.True(t, errors.Is(err, models.ErrNamingViolation))
+		})
+		t.Run("should fail if the job doesn't exist", func(t *testing.T) {
+			jobSpecRepo := new(mock.JobSpecRepository)
+			jobSpecRepo.On("GetByName", "missing").Return(models.JobSpec{}, store.ErrResourceNotFound)
+
+			jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+			jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+			svc := job.NewService(jobSpecRepoFac, nil, nil, dumpAssets, nil, nil, nil, nil, nil, nil)
+			_, err := svc.Rename(ctx, namespaceSpec, "missing", "source", nil)
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+		})
+	})
 }
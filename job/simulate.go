@@ -0,0 +1,84 @@
+package job
+
+import (
+	"sort"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// runs simulated at once are capped at this, a frequent schedule over
+	// a long range is better simulated in parts
+	maxSimulatedRuns = 10000
+)
+
+// SimulatedRun is a run the schedule of a job produces, with the window of
+// data the run processes, its DSTART and DEND
+type SimulatedRun struct {
+	ScheduledAt time.Time
+	WindowStart time.Time
+	WindowEnd   time.Time
+
+	// Extra is set for runs added by schedule exceptions, Skipped for runs
+	// of the interval left out by them
+	Extra   bool
+	Skipped bool
+}
+
+// SimulateRuns lists runs of a job scheduled from start till end, end
+// exclusive, within the start and end dates of its schedule. Runs skipped by
+// schedule exceptions are listed as well, marked skipped, calendars of
+// exceptions are read from project
+func SimulateRuns(jobSpec models.JobSpec, projSpec models.ProjectSpec, start, end time.Time) ([]SimulatedRun, error) {
+	start, end = start.UTC(), end.UTC()
+	if !jobSpec.Schedule.StartDate.IsZero() && start.Before(jobSpec.Schedule.StartDate) {
+		start = jobSpec.Schedule.StartDate
+	}
+	// scheduler runs a job at its end date as well
+	if scheduleEnd := jobSpec.Schedule.EndDate; scheduleEnd != nil && scheduleEnd.Add(time.Second).Before(end) {
+		end = scheduleEnd.Add(time.Second)
+	}
+	if !start.Before(end) {
+		return nil, nil
+	}
+
+	runs, err := getRunsBetweenDates(start, end, jobSpec.Schedule.Interval)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid interval of %s", jobSpec.Name)
+	}
+	skipDays, err := jobSpec.Schedule.Exceptions.SkipDays(projSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve schedule exceptions of %s", jobSpec.Name)
+	}
+	skipped := map[string]bool{}
+	for _, day := range skipDays {
+		skipped[day] = true
+	}
+
+	var simulated []SimulatedRun
+	for _, run := range runs {
+		simulated = append(simulated, SimulatedRun{
+			ScheduledAt: run,
+			Skipped:     skipped[run.UTC().Format(models.JobDatetimeLayout)],
+		})
+	}
+	for _, run := range jobSpec.Schedule.Exceptions.ExtraRuns {
+		if !run.Before(start) && run.Before(end) {
+			simulated = append(simulated, SimulatedRun{ScheduledAt: run.UTC(), Extra: true})
+		}
+	}
+	if len(simulated) > maxSimulatedRuns {
+		return nil, errors.Errorf("%s has %d runs in range, more than %d can be simulated at once", jobSpec.Name,
+			len(simulated), maxSimulatedRuns)
+	}
+	sort.SliceStable(simulated, func(i, j int) bool {
+		return simulated[i].ScheduledAt.Before(simulated[j].ScheduledAt)
+	})
+	for idx := range simulated {
+		simulated[idx].WindowStart = jobSpec.Task.Window.GetStart(simulated[idx].ScheduledAt)
+		simulated[idx].WindowEnd = jobSpec.Task.Window.GetEnd(simulated[idx].ScheduledAt)
+	}
+	return simulated, nil
+}
@@ -0,0 +1,74 @@
+package job_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateRuns(t *testing.T) {
+	endDate := time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC)
+	jobSpec := models.JobSpec{
+		Name: "test",
+		Schedule: models.JobSpecSchedule{
+			StartDate: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   &endDate,
+			Interval:  "0 2 * * *",
+		},
+		Task: models.JobSpecTask{
+			Window: models.JobSpecTaskWindow{
+				Size:       time.Hour * 24,
+				Offset:     0,
+				TruncateTo: "d",
+			},
+		},
+	}
+	day := func(d, hour int) time.Time {
+		return time.Date(2021, 3, d, hour, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("should list runs with their windows within the schedule of job", func(t *testing.T) {
+		runs, err := job.SimulateRuns(jobSpec, models.ProjectSpec{}, day(1, 0).AddDate(0, 0, -7), day(10, 0))
+		assert.Nil(t, err)
+		assert.Equal(t, []job.SimulatedRun{
+			{ScheduledAt: day(1, 2), WindowStart: day(1, 0).AddDate(0, 0, -1), WindowEnd: day(1, 0)},
+			{ScheduledAt: day(2, 2), WindowStart: day(1, 0), WindowEnd: day(2, 0)},
+			{ScheduledAt: day(3, 2), WindowStart: day(2, 0), WindowEnd: day(3, 0)},
+			{ScheduledAt: day(4, 2), WindowStart: day(3, 0), WindowEnd: day(4, 0)},
+		}, runs)
+	})
+	t.Run("should mark runs skipped and added by schedule exceptions", func(t *testing.T) {
+		spec := jobSpec
+		spec.Schedule.EndDate = nil
+		spec.Schedule.Exceptions = models.JobSpecScheduleExceptions{
+			ExtraRuns: []time.Time{day(2, 14)},
+			Calendars: []string{"holidays"},
+		}
+		projSpec := models.ProjectSpec{
+			Config: map[string]string{
+				models.ProjectCalendarKeyPrefix + "HOLIDAYS": "2021-03-03",
+			},
+		}
+		runs, err := job.SimulateRuns(spec, projSpec, day(2, 0), day(4, 0))
+		assert.Nil(t, err)
+		assert.Equal(t, []job.SimulatedRun{
+			{ScheduledAt: day(2, 2), WindowStart: day(1, 0), WindowEnd: day(2, 0)},
+			{ScheduledAt: day(2, 14), WindowStart: day(1, 0), WindowEnd: day(2, 0), Extra: true},
+			{ScheduledAt: day(3, 2), WindowStart: day(2, 0), WindowEnd: day(3, 0), Skipped: true},
+		}, runs)
+	})
+	t.Run("should list no runs out of the schedule of job", func(t *testing.T) {
+		runs, err := job.SimulateRuns(jobSpec, models.ProjectSpec{}, day(6, 0), day(10, 0))
+		assert.Nil(t, err)
+		assert.Empty(t, runs)
+	})
+	t.Run("should fail for invalid interval", func(t *testing.T) {
+		spec := jobSpec
+		spec.Schedule.Interval = "every day"
+		_, err := job.SimulateRuns(spec, models.ProjectSpec{}, day(1, 0), day(4, 0))
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,283 @@
+package job
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	// StaleJobDefaultWindow is used when analyzer is created without a
+	// window, jobs are judged by their last 30 days
+	StaleJobDefaultWindow = time.Hour * 24 * 30
+)
+
+// DependencyResolvedSpecGetter returns jobs of a project with their static
+// and inferred dependencies resolved
+type DependencyResolvedSpecGetter interface {
+	GetDependencyResolvedSpecs(proj models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
+		progressObserver progress.Observer) ([]models.JobSpec, error)
+}
+
+// StaleJobAnalyzer periodically looks for jobs which look unused, ones
+// without a successful run within window or whose destination no job of
+// any project depends on and no one read within window, as far as
+// datastores implementing models.DatastoreUsageReader can tell. Each pass
+// replaces the report of the previous one
+type StaleJobAnalyzer struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	projectRepoFactory        ProjectRepoFactory
+	namespaceRepoFactory      NamespaceRepoFactory
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory
+	specGetter                DependencyResolvedSpecGetter
+	instSvc                   models.InstanceService
+	datastoreRepo             models.DatastoreRepo
+	interval                  time.Duration
+	window                    time.Duration
+
+	mu sync.Mutex
+	// project name -> stale jobs found in the latest pass
+	report     map[string][]models.StaleJob
+	analyzedAt time.Time
+
+	Now func() time.Time
+}
+
+// projectJobs are jobs of a project gathered in the first phase of a pass
+type projectJobs struct {
+	project      models.ProjectSpec
+	namespaces   map[string]models.NamespaceSpec
+	jobs         []models.JobSpec
+	destinations map[string]models.JobDestination
+}
+
+// Start runs analysis right away and then in background every interval
+// until closed
+func (a *StaleJobAnalyzer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+		for {
+			if err := a.Analyze(ctx); err != nil {
+				logger.E(errors.Wrap(err, "stale job analysis failed"))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Close stops analysis, waiting for the ongoing pass to finish
+func (a *StaleJobAnalyzer) Close() error {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	a.wg.Wait()
+	return nil
+}
+
+// Analyze looks for stale jobs across all projects, dependencies are
+// gathered from all projects first as a destination can be read by jobs
+// of other projects. Failing to analyze a project or a job doesn't stop
+// analysis of the rest, they are left out of the report
+func (a *StaleJobAnalyzer) Analyze(ctx context.Context) error {
+	projects, err := a.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch projects")
+	}
+
+	// project name/job name of jobs some job depends on
+	dependedOn := map[string]bool{}
+	var gathered []projectJobs
+	for _, projSpec := range projects {
+		projJobs, err := a.gather(projSpec, dependedOn)
+		if err != nil {
+			logger.E(errors.Wrapf(err, "failed to fetch jobs of project %s", projSpec.Name))
+			continue
+		}
+		gathered = append(gathered, projJobs)
+	}
+
+	now := a.Now()
+	since := now.Add(-a.window)
+	report := map[string][]models.StaleJob{}
+	for _, projJobs := range gathered {
+		for _, jobSpec := range projJobs.jobs {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// jobs younger than window had no chance to be used yet
+			if jobSpec.Schedule.StartDate.After(since) {
+				continue
+			}
+			staleJob, err := a.analyzeJob(ctx, projJobs, jobSpec, since, dependedOn)
+			if err != nil {
+				logger.E(errors.Wrapf(err, "failed to analyze usage of job %s", jobSpec.Name))
+				continue
+			}
+			if len(staleJob.Reasons) > 0 {
+				report[projJobs.project.Name] = append(report[projJobs.project.Name], staleJob)
+			}
+		}
+	}
+
+	a.mu.Lock()
+	a.report = report
+	a.analyzedAt = now
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *StaleJobAnalyzer) gather(projSpec models.ProjectSpec, dependedOn map[string]bool) (projectJobs, error) {
+	projJobs := projectJobs{
+		project:      projSpec,
+		namespaces:   map[string]models.NamespaceSpec{},
+		destinations: map[string]models.JobDestination{},
+	}
+	namespaces, err := a.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return projJobs, err
+	}
+	for _, namespace := range namespaces {
+		projJobs.namespaces[namespace.Name] = namespace
+	}
+	projectJobSpecRepo := a.projectJobSpecRepoFactory.New(projSpec)
+	if projJobs.jobs, err = projectJobSpecRepo.GetAll(); err != nil {
+		return projJobs, err
+	}
+	destinations, err := projectJobSpecRepo.GetDestinations()
+	if err != nil {
+		return projJobs, err
+	}
+	for _, destination := range destinations {
+		projJobs.destinations[destination.JobName] = destination
+	}
+
+	// jobs failing to resolve are left out, their dependencies stay unknown
+	resolvedSpecs, err := a.specGetter.GetDependencyResolvedSpecs(projSpec, projectJobSpecRepo, nil)
+	if err != nil {
+		logger.W(errors.Wrapf(err, "failed to resolve dependencies of some jobs of project %s", projSpec.Name).Error())
+	}
+	for _, resolvedSpec := range resolvedSpecs {
+		for _, dep := range resolvedSpec.Dependencies {
+			if dep.Job == nil {
+				continue
+			}
+			depProject := projSpec.Name
+			if dep.Project != nil {
+				depProject = dep.Project.Name
+			}
+			dependedOn[depProject+"/"+dep.Job.Name] = true
+		}
+	}
+	return projJobs, nil
+}
+
+func (a *StaleJobAnalyzer) analyzeJob(ctx context.Context, projJobs projectJobs, jobSpec models.JobSpec,
+	since time.Time, dependedOn map[string]bool) (models.StaleJob, error) {
+	destination := projJobs.destinations[jobSpec.Name]
+	staleJob := models.StaleJob{
+		ProjectName:   projJobs.project.Name,
+		NamespaceName: destination.NamespaceName,
+		JobName:       jobSpec.Name,
+		Owner:         jobSpec.Owner,
+		Destination:   destination.Destination,
+	}
+
+	stats, err := a.instSvc.GetRunStats(jobSpec, since)
+	if err != nil {
+		return staleJob, err
+	}
+	staleJob.Runs = stats.Runs
+	if stats.Succeeded == 0 {
+		staleJob.Reasons = append(staleJob.Reasons, models.StaleJobReasonNoSuccessfulRun)
+	}
+
+	if staleJob.Destination == "" || dependedOn[projJobs.project.Name+"/"+jobSpec.Name] {
+		return staleJob, nil
+	}
+	audited := false
+	for _, ds := range a.datastoreRepo.GetAll() {
+		reader, ok := ds.(models.DatastoreUsageReader)
+		if !ok {
+			continue
+		}
+		resp, err := reader.LastReadAt(ctx, models.ReadUsageRequest{
+			Destination: staleJob.Destination,
+			Since:       since,
+			Project:     projJobs.project,
+			Namespace:   projJobs.namespaces[destination.NamespaceName],
+		})
+		if err != nil {
+			return staleJob, errors.Wrapf(err, "failed to look up reads of %s", staleJob.Destination)
+		}
+		if !resp.Supported {
+			continue
+		}
+		audited = true
+		if resp.LastReadAt.After(staleJob.LastReadAt) {
+			staleJob.LastReadAt = resp.LastReadAt
+		}
+	}
+	// reads of destinations no datastore audits are unknown, they can't be
+	// told unread
+	if audited && staleJob.LastReadAt.IsZero() {
+		staleJob.Reasons = append(staleJob.Reasons, models.StaleJobReasonUnreadDestination)
+	}
+	return staleJob, nil
+}
+
+// GetReport returns stale jobs of a project found in the latest pass, by
+// namespace and name, and when the pass ran, zero if none did yet
+func (a *StaleJobAnalyzer) GetReport(projectName string) ([]models.StaleJob, time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	staleJobs := append([]models.StaleJob{}, a.report[projectName]...)
+	sort.Slice(staleJobs, func(i, j int) bool {
+		if staleJobs[i].NamespaceName != staleJobs[j].NamespaceName {
+			return staleJobs[i].NamespaceName < staleJobs[j].NamespaceName
+		}
+		return staleJobs[i].JobName < staleJobs[j].JobName
+	})
+	return staleJobs, a.analyzedAt
+}
+
+// NewStaleJobAnalyzer creates an analyzer looking for stale jobs every
+// interval, judging jobs by their runs and reads within window
+func NewStaleJobAnalyzer(projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory, specGetter DependencyResolvedSpecGetter,
+	instSvc models.InstanceService, datastoreRepo models.DatastoreRepo, interval, window time.Duration) *StaleJobAnalyzer {
+	if window <= 0 {
+		window = StaleJobDefaultWindow
+	}
+	return &StaleJobAnalyzer{
+		projectRepoFactory:        projectRepoFactory,
+		namespaceRepoFactory:      namespaceRepoFactory,
+		projectJobSpecRepoFactory: projectJobSpecRepoFactory,
+		specGetter:                specGetter,
+		instSvc:                   instSvc,
+		datastoreRepo:             datastoreRepo,
+		interval:                  interval,
+		window:                    window,
+		report:                    map[string][]models.StaleJob{},
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
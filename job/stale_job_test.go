@@ -0,0 +1,126 @@
+package job_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+)
+
+func TestStaleJobAnalyzer(t *testing.T) {
+	logger.InitWithWriter("ERROR", ioutil.Discard)
+
+	ctx := context.Background()
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour * 24 * 30)
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	newJob := func(name string, startDate time.Time) models.JobSpec {
+		return models.JobSpec{
+			ID:           uuid.Must(uuid.NewRandom()),
+			Name:         name,
+			Owner:        name + "@example.com",
+			Schedule:     models.JobSpecSchedule{StartDate: startDate},
+			Dependencies: map[string]models.JobSpecDependency{},
+		}
+	}
+	startDate := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	orders := newJob("orders", startDate)
+	revenue := newJob("revenue", startDate)
+	legacy := newJob("legacy", startDate)
+	fresh := newJob("fresh", now.AddDate(0, 0, -2))
+	resolvedRevenue := newJob("revenue", startDate)
+	resolvedRevenue.Dependencies["orders"] = models.JobSpecDependency{Job: &orders, Project: &projectSpec}
+
+	t.Run("should report jobs without successful runs or reads of destination", func(t *testing.T) {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projectSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		namespaceRepo := new(mock.NamespaceRepository)
+		namespaceRepo.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFac := new(mock.NamespaceRepoFactory)
+		namespaceRepoFac.On("New", projectSpec).Return(namespaceRepo)
+
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{orders, revenue, legacy, fresh}, nil)
+		projectJobSpecRepo.On("GetDestinations").Return([]models.JobDestination{
+			{JobName: "orders", NamespaceName: namespaceSpec.Name, Destination: "proj:raw.orders"},
+			{JobName: "revenue", NamespaceName: namespaceSpec.Name, Destination: "proj:finance.revenue"},
+			{JobName: "legacy", NamespaceName: namespaceSpec.Name, Destination: "proj:finance.legacy"},
+			{JobName: "fresh", NamespaceName: namespaceSpec.Name, Destination: "proj:finance.fresh"},
+		}, nil)
+		projectJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projectJobSpecRepoFac.On("New", projectSpec).Return(projectJobSpecRepo)
+
+		specGetter := new(mock.DependencyResolvedSpecGetter)
+		specGetter.On("GetDependencyResolvedSpecs", projectSpec, projectJobSpecRepo, nil).
+			Return([]models.JobSpec{orders, resolvedRevenue, legacy, fresh}, nil)
+
+		instanceService := new(mock.InstanceService)
+		defer instanceService.AssertExpectations(t)
+		instanceService.On("GetRunStats", orders, since).Return(models.JobRunStats{Runs: 30, Succeeded: 30}, nil)
+		instanceService.On("GetRunStats", revenue, since).Return(models.JobRunStats{Runs: 30, Failed: 30}, nil)
+		instanceService.On("GetRunStats", legacy, since).Return(models.JobRunStats{Runs: 30, Succeeded: 29}, nil)
+
+		lastReadAt := now.AddDate(0, 0, -3)
+		reader := new(mock.DatastoreUsageReader)
+		defer reader.AssertExpectations(t)
+		reader.On("LastReadAt", ctx, models.ReadUsageRequest{
+			Destination: "proj:finance.revenue", Since: since, Project: projectSpec, Namespace: namespaceSpec,
+		}).Return(models.ReadUsageResponse{Supported: true, LastReadAt: lastReadAt}, nil)
+		reader.On("LastReadAt", ctx, models.ReadUsageRequest{
+			Destination: "proj:finance.legacy", Since: since, Project: projectSpec, Namespace: namespaceSpec,
+		}).Return(models.ReadUsageResponse{Supported: true}, nil)
+		datastoreRepo := new(mock.SupportedDatastoreRepo)
+		datastoreRepo.On("GetAll").Return([]models.Datastorer{new(mock.Datastorer), reader})
+
+		analyzer := job.NewStaleJobAnalyzer(projectRepoFac, namespaceRepoFac, projectJobSpecRepoFac, specGetter,
+			instanceService, datastoreRepo, time.Hour, 0)
+		analyzer.Now = func() time.Time { return now }
+		assert.Nil(t, analyzer.Analyze(ctx))
+
+		report, analyzedAt := analyzer.GetReport(projectSpec.Name)
+		assert.Equal(t, now, analyzedAt)
+		assert.Equal(t, []models.StaleJob{
+			{
+				ProjectName:   projectSpec.Name,
+				NamespaceName: namespaceSpec.Name,
+				JobName:       "legacy",
+				Owner:         "legacy@example.com",
+				Destination:   "proj:finance.legacy",
+				Reasons:       []models.StaleJobReason{models.StaleJobReasonUnreadDestination},
+				Runs:          30,
+			},
+			{
+				ProjectName:   projectSpec.Name,
+				NamespaceName: namespaceSpec.Name,
+				JobName:       "revenue",
+				Owner:         "revenue@example.com",
+				Destination:   "proj:finance.revenue",
+				Reasons:       []models.StaleJobReason{models.StaleJobReasonNoSuccessfulRun},
+				Runs:          30,
+				LastReadAt:    lastReadAt,
+			},
+		}, report)
+
+		report, _ = analyzer.GetReport("another-project")
+		assert.Empty(t, report)
+	})
+}
@@ -0,0 +1,278 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/core/progress"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	// TemplateMigrationLoadTimeout is how long a template migration waits
+	// for the staging scheduler to load the recompiled jobs, jobs not loaded
+	// by then are invalid
+	TemplateMigrationLoadTimeout = 10 * time.Minute
+)
+
+// TemplateStagingProject is the project jobs of projSpec are recompiled to
+// when migrating them to a new scheduler template. Both the specification
+// store and the host of the staging scheduler must be configured, as the
+// jobs would otherwise overwrite the deployed ones before being validated
+func TemplateStagingProject(projSpec models.ProjectSpec) (models.ProjectSpec, error) {
+	storagePath := projSpec.Config[models.ProjectTemplateStagingStoragePathKey]
+	host := projSpec.Config[models.ProjectTemplateStagingSchedulerHostKey]
+	if storagePath == "" || host == "" {
+		return models.ProjectSpec{}, errors.Errorf("%s and %s are required in config of project %s to migrate its template",
+			models.ProjectTemplateStagingStoragePathKey, models.ProjectTemplateStagingSchedulerHostKey, projSpec.Name)
+	}
+	return stagingProject(projSpec, storagePath, host, map[string]string{
+		models.ProjectSecretStorageKey: models.ProjectSecretTemplateStagingStorageKey,
+		models.ProjectSchedulerAuth:    models.ProjectSecretTemplateStagingSchedulerAuth,
+	}), nil
+}
+
+// stagedJob is a job recompiled into the staging scheduler
+type stagedJob struct {
+	namespace models.NamespaceSpec
+	job       models.Job
+}
+
+// MigrateTemplate recompiles jobs of namespaces against the scheduler
+// template of the server into the specification store of
+// TemplateStagingProject, and waits for the staging scheduler to load them,
+// jobs failing to compile, failing to load or not loaded in
+// TemplateMigrationLoadTimeout are invalid. With swap and no invalid job, the
+// same compiled jobs are uploaded over the deployed ones, skipping the ones
+// which didn't change. Staged jobs are deleted once validated
+func (srv *Service) MigrateTemplate(ctx context.Context, projSpec models.ProjectSpec, namespaces []models.NamespaceSpec,
+	swap bool, progressObserver progress.Observer) (models.TemplateMigration, error) {
+	result := models.TemplateMigration{
+		Invalid: map[string]string{},
+	}
+	stagingProj, err := TemplateStagingProject(projSpec)
+	if err != nil {
+		return result, err
+	}
+
+	projectJobSpecRepo := srv.projectJobSpecRepoFactory.New(projSpec)
+	jobSpecs, err := srv.GetDependencyResolvedSpecs(projSpec, projectJobSpecRepo, progressObserver)
+	if err != nil {
+		return result, err
+	}
+	srv.notifyProgress(progressObserver, &EventJobSpecDependencyResolve{})
+	jobSpecs, err = srv.priorityResolver.Resolve(jobSpecs)
+	if err != nil {
+		return result, err
+	}
+	srv.notifyProgress(progressObserver, &EventJobPriorityWeightAssign{})
+
+	stagingRepo, err := srv.jobRepoFactory.New(ctx, stagingProj)
+	if err != nil {
+		return result, errors.Wrap(err, "failed to open specification store of staging scheduler")
+	}
+	staged, err := srv.stageJobs(ctx, stagingRepo, jobSpecs, namespaces, &result, progressObserver)
+	// staged jobs are only needed to validate them, they are removed even if
+	// the migration is cancelled
+	defer srv.deleteStagedJobs(stagingRepo, staged)
+	if err != nil {
+		return result, err
+	}
+	srv.notifyProgress(progressObserver, &EventTemplateMigrationStaged{Jobs: len(staged)})
+
+	for name, reason := range srv.validateStagedJobs(ctx, stagingProj, staged) {
+		result.Invalid[name] = reason
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	for _, stagedJob := range staged {
+		var validateErr error
+		if reason, ok := result.Invalid[stagedJob.job.Name]; ok {
+			validateErr = errors.New(reason)
+		}
+		srv.notifyProgress(progressObserver, &EventTemplateMigrationJobValidated{
+			Name: stagedJob.job.Name,
+			Err:  validateErr,
+		})
+	}
+	if len(result.Invalid) > 0 || !swap {
+		return result, nil
+	}
+
+	if result.Uploaded, err = srv.swapStagedJobs(ctx, projSpec, staged); err != nil {
+		return result, errors.Wrap(err, "failed to replace deployed jobs, some of them may already be replaced")
+	}
+	result.Swapped = true
+	return result, nil
+}
+
+// stageJobs compiles jobs of namespaces and saves them in the staging repo,
+// jobs failing to do so are recorded as invalid in result
+func (srv *Service) stageJobs(ctx context.Context, stagingRepo store.JobRepository, jobSpecs []models.JobSpec,
+	namespaces []models.NamespaceSpec, result *models.TemplateMigration,
+	progressObserver progress.Observer) ([]stagedJob, error) {
+	var staged []stagedJob
+	for _, namespace := range namespaces {
+		namespaceJobSpecs, err := srv.filterJobSpecForNamespace(jobSpecs, namespace)
+		if err != nil {
+			return staged, errors.Wrapf(err, "failed to fetch jobs of namespace %s", namespace.Name)
+		}
+		for _, jobSpec := range namespaceJobSpecs {
+			if err := ctx.Err(); err != nil {
+				return staged, err
+			}
+			result.Jobs++
+			compiledJob, err := srv.compileArtifact(namespace, jobSpec)
+			if err != nil {
+				result.Invalid[jobSpec.Name] = errors.Wrap(err, "failed to compile").Error()
+				continue
+			}
+			srv.notifyProgress(progressObserver, &EventJobSpecCompile{Name: jobSpec.Name})
+			if err := stagingRepo.Save(ctx, compiledJob); err != nil {
+				result.Invalid[jobSpec.Name] = errors.Wrap(err, "failed to stage").Error()
+				continue
+			}
+			staged = append(staged, stagedJob{namespace: namespace, job: compiledJob})
+		}
+	}
+	return staged, nil
+}
+
+// validateStagedJobs polls the staging scheduler till it loads the staged
+// jobs, returning the reason of the ones which are invalid. Load errors are
+// checked too when scheduler can list them, so broken jobs don't have to
+// wait for the timeout
+func (srv *Service) validateStagedJobs(ctx context.Context, stagingProj models.ProjectSpec,
+	staged []stagedJob) map[string]string {
+	invalid := map[string]string{}
+	if len(staged) == 0 {
+		return invalid
+	}
+	if srv.scheduler == nil {
+		for _, stagedJob := range staged {
+			invalid[stagedJob.job.Name] = "scheduler is not available to validate the job"
+		}
+		return invalid
+	}
+	errorLister, canListErrors := srv.scheduler.(models.SchedulerJobLoadErrorLister)
+
+	ctx, cancel := context.WithTimeout(ctx, TemplateMigrationLoadTimeout)
+	defer cancel()
+	ticker := time.NewTicker(UpstreamLoadPollInterval)
+	defer ticker.Stop()
+
+	var pending []string
+	for _, stagedJob := range staged {
+		pending = append(pending, stagedJob.job.Name)
+	}
+	for {
+		loadErrors := map[string]string{}
+		if canListErrors {
+			var err error
+			if loadErrors, err = errorLister.GetJobLoadErrors(ctx, stagingProj); err != nil {
+				logger.W(errors.Wrap(err, "failed to list load errors of staged jobs"))
+			}
+		}
+
+		var stillPending []string
+		for _, jobName := range pending {
+			if loadErr, ok := loadErrors[jobName]; ok {
+				invalid[jobName] = "failed to load in staging scheduler: " + loadErr
+				continue
+			}
+			loaded, err := srv.scheduler.IsJobLoaded(ctx, stagingProj, jobName)
+			if err != nil {
+				// a job is only valid once the scheduler is seen loading it
+				if ctx.Err() == nil {
+					logger.W(errors.Wrapf(err, "failed to check if staged job %s is loaded", jobName))
+				}
+			}
+			if !loaded {
+				stillPending = append(stillPending, jobName)
+			}
+		}
+		pending = stillPending
+		if len(pending) == 0 {
+			return invalid
+		}
+
+		select {
+		case <-ctx.Done():
+			for _, jobName := range pending {
+				invalid[jobName] = fmt.Sprintf("not loaded by staging scheduler in %s", TemplateMigrationLoadTimeout)
+			}
+			return invalid
+		case <-ticker.C:
+		}
+	}
+}
+
+// swapStagedJobs uploads staged jobs over the deployed ones, returning the
+// number of jobs which differed from what was deployed
+func (srv *Service) swapStagedJobs(ctx context.Context, projSpec models.ProjectSpec, staged []stagedJob) (int, error) {
+	jobRepo, err := srv.jobRepoFactory.New(ctx, projSpec)
+	if err != nil {
+		return 0, err
+	}
+	checksumRepo, hasChecksums := jobRepo.(store.JobChecksumRepository)
+	storedChecksums := map[string]map[string]string{}
+
+	uploaded := 0
+	for _, stagedJob := range staged {
+		namespaceName := stagedJob.namespace.Name
+		if _, ok := storedChecksums[namespaceName]; !ok && hasChecksums {
+			checksums, err := checksumRepo.Checksums(ctx, stagedJob.namespace)
+			if err != nil {
+				logger.W(errors.Wrapf(err, "failed to fetch checksums of jobs of namespace %s, uploading all of them", namespaceName))
+				checksums = map[string]string{}
+			}
+			storedChecksums[namespaceName] = checksums
+		}
+		if checksum, ok := storedChecksums[namespaceName][stagedJob.job.Name]; ok &&
+			checksum == contentChecksum(stagedJob.job.Contents) {
+			continue
+		}
+		if err := jobRepo.Save(ctx, stagedJob.job); err != nil {
+			return uploaded, errors.Wrapf(err, "failed to upload %s", stagedJob.job.Name)
+		}
+		uploaded++
+	}
+	return uploaded, nil
+}
+
+func (srv *Service) deleteStagedJobs(stagingRepo store.JobRepository, staged []stagedJob) {
+	for _, stagedJob := range staged {
+		if err := stagingRepo.Delete(context.Background(), stagedJob.namespace, stagedJob.job.Name); err != nil {
+			logger.W(errors.Wrapf(err, "failed to delete staged job %s", stagedJob.job.Name))
+		}
+	}
+}
+
+type (
+	// EventTemplateMigrationStaged represents jobs being recompiled into
+	// the staging scheduler
+	EventTemplateMigrationStaged struct{ Jobs int }
+
+	// EventTemplateMigrationJobValidated represents a staged job being
+	// validated by the staging scheduler
+	EventTemplateMigrationJobValidated struct {
+		Name string
+		Err  error
+	}
+)
+
+func (e *EventTemplateMigrationStaged) String() string {
+	return fmt.Sprintf("staged %d jobs", e.Jobs)
+}
+
+func (e *EventTemplateMigrationJobValidated) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid: %s, %s", e.Name, e.Err.Error())
+	}
+	return fmt.Sprintf("valid: %s", e.Name)
+}
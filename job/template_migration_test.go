@@ -0,0 +1,185 @@
+package job_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestTemplateMigration(t *testing.T) {
+	ctx := context.Background()
+	dumpAssets := func(_ models.ProjectSpec, jobSpec models.JobSpec, _ time.Time) (models.JobAssets, error) {
+		return jobSpec.Assets, nil
+	}
+	projSpec := models.ProjectSpec{
+		Name: "proj",
+		Config: map[string]string{
+			models.ProjectStoragePathKey:                  "gs://prod/dags",
+			models.ProjectSchedulerHost:                   "http://airflow.prod",
+			models.ProjectTemplateStagingStoragePathKey:   "gs://staging/dags",
+			models.ProjectTemplateStagingSchedulerHostKey: "http://airflow.staging",
+		},
+		Secret: models.ProjectSecrets{
+			{Name: models.ProjectSecretStorageKey, Value: "prod-storage"},
+			{Name: models.ProjectSchedulerAuth, Value: "prod-auth"},
+			{Name: models.ProjectSecretTemplateStagingSchedulerAuth, Value: "staging-auth"},
+		},
+	}
+	stagingProj, err := job.TemplateStagingProject(projSpec)
+	assert.Nil(t, err)
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "dev-team-1",
+		ProjectSpec: projSpec,
+	}
+	jobSpecs := []models.JobSpec{
+		{Version: 1, Name: "test", Owner: "optimus"},
+		{Version: 1, Name: "test-2", Owner: "optimus"},
+	}
+	unchangedJob := models.Job{Name: "test", Contents: []byte(`some string`), NamespaceID: namespaceSpec.Name}
+	changedJob := models.Job{Name: "test-2", Contents: []byte(`changed string`), NamespaceID: namespaceSpec.Name}
+
+	// jobs of the namespace are resolved, compiled and staged
+	setup := func() (*mock.JobRepoFactory, *mock.JobRepository, *job.Service, *mock.Scheduler) {
+		jobSpecRepo := new(mock.JobSpecRepository)
+		jobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+		jobSpecRepoFac := new(mock.JobSpecRepoFactory)
+		jobSpecRepoFac.On("New", namespaceSpec).Return(jobSpecRepo)
+
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+		projJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projJobSpecRepoFac.On("New", projSpec).Return(projectJobSpecRepo)
+
+		stagingRepo := new(mock.JobRepository)
+		jobRepoFac := new(mock.JobRepoFactory)
+		jobRepoFac.On("New", ctx, stagingProj).Return(stagingRepo, nil)
+
+		depenResolver := new(mock.DependencyResolver)
+		priorityResolver := new(mock.PriorityResolver)
+		compiler := new(mock.Compiler)
+		for idx, compiledJob := range []models.Job{unchangedJob, changedJob} {
+			depenResolver.On("Resolve", projSpec, projectJobSpecRepo, jobSpecs[idx], nil).Return(jobSpecs[idx], nil)
+			compiler.On("Compile", namespaceSpec, jobSpecs[idx]).Return(compiledJob, nil)
+			stagingRepo.On("Save", ctx, compiledJob).Return(nil)
+			stagingRepo.On("Delete", ctx, namespaceSpec, compiledJob.Name).Return(nil)
+		}
+		priorityResolver.On("Resolve", jobSpecs).Return(jobSpecs, nil)
+
+		scheduler := new(mock.Scheduler)
+		svc := job.NewService(jobSpecRepoFac, jobRepoFac, compiler, dumpAssets, depenResolver, priorityResolver, nil,
+			projJobSpecRepoFac, nil, scheduler)
+		return jobRepoFac, stagingRepo, svc, scheduler
+	}
+
+	t.Run("TemplateStagingProject", func(t *testing.T) {
+		t.Run("should use staging scheduler with its secrets", func(t *testing.T) {
+			assert.Equal(t, "gs://staging/dags", stagingProj.Config[models.ProjectStoragePathKey])
+			assert.Equal(t, "http://airflow.staging", stagingProj.Config[models.ProjectSchedulerHost])
+			storageSecret, _ := stagingProj.Secret.GetByName(models.ProjectSecretStorageKey)
+			assert.Equal(t, "prod-storage", storageSecret)
+			authSecret, _ := stagingProj.Secret.GetByName(models.ProjectSchedulerAuth)
+			assert.Equal(t, "staging-auth", authSecret)
+			// project itself is left untouched
+			assert.Equal(t, "gs://prod/dags", projSpec.Config[models.ProjectStoragePathKey])
+		})
+		t.Run("should fail when staging scheduler is not configured", func(t *testing.T) {
+			_, err := job.TemplateStagingProject(models.ProjectSpec{
+				Name: "proj",
+				Config: map[string]string{
+					models.ProjectTemplateStagingStoragePathKey: "gs://staging/dags",
+				},
+			})
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("MigrateTemplate", func(t *testing.T) {
+		t.Run("should swap deployed jobs with staged ones once all of them are loaded", func(t *testing.T) {
+			jobRepoFac, stagingRepo, svc, scheduler := setup()
+			defer stagingRepo.AssertExpectations(t)
+
+			jobRepo := new(mock.ChecksumJobRepository)
+			jobRepo.On("Checksums", ctx, namespaceSpec).Return(map[string]string{
+				// md5 of contents of both jobs before test-2 changed
+				"test":   "5ac749fbeec93607fc28d666be85e73a",
+				"test-2": "5ac749fbeec93607fc28d666be85e73a",
+			}, nil)
+			jobRepo.On("Save", ctx, changedJob).Return(nil)
+			defer jobRepo.AssertExpectations(t)
+			jobRepoFac.On("New", ctx, projSpec).Return(jobRepo, nil)
+
+			scheduler.On("GetJobLoadErrors", mock2.Anything, stagingProj).Return(map[string]string{}, nil)
+			scheduler.On("IsJobLoaded", mock2.Anything, stagingProj, "test").Return(true, nil)
+			scheduler.On("IsJobLoaded", mock2.Anything, stagingProj, "test-2").Return(true, nil)
+			defer scheduler.AssertExpectations(t)
+
+			migration, err := svc.MigrateTemplate(ctx, projSpec, []models.NamespaceSpec{namespaceSpec}, true, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, models.TemplateMigration{
+				Jobs:     2,
+				Invalid:  map[string]string{},
+				Swapped:  true,
+				Uploaded: 1,
+			}, migration)
+			jobRepo.AssertNotCalled(t, "Save", ctx, unchangedJob)
+		})
+		t.Run("should leave deployed jobs untouched if any staged job fails to load", func(t *testing.T) {
+			defer func(timeout, interval time.Duration) {
+				job.TemplateMigrationLoadTimeout, job.UpstreamLoadPollInterval = timeout, interval
+			}(job.TemplateMigrationLoadTimeout, job.UpstreamLoadPollInterval)
+			job.TemplateMigrationLoadTimeout, job.UpstreamLoadPollInterval = time.Second, time.Millisecond
+
+			jobRepoFac, stagingRepo, svc, scheduler := setup()
+			defer stagingRepo.AssertExpectations(t)
+
+			scheduler.On("GetJobLoadErrors", mock2.Anything, stagingProj).Return(map[string]string{}, nil).Once()
+			scheduler.On("GetJobLoadErrors", mock2.Anything, stagingProj).Return(map[string]string{
+				"test-2": "ImportError: cannot import name 'KubernetesPodOperator'",
+			}, nil)
+			scheduler.On("IsJobLoaded", mock2.Anything, stagingProj, "test").Return(true, nil)
+			scheduler.On("IsJobLoaded", mock2.Anything, stagingProj, "test-2").Return(false, nil)
+
+			migration, err := svc.MigrateTemplate(ctx, projSpec, []models.NamespaceSpec{namespaceSpec}, true, nil)
+			assert.Nil(t, err)
+			assert.False(t, migration.Swapped)
+			assert.Equal(t, map[string]string{
+				"test-2": "failed to load in staging scheduler: ImportError: cannot import name 'KubernetesPodOperator'",
+			}, migration.Invalid)
+			jobRepoFac.AssertNotCalled(t, "New", ctx, projSpec)
+		})
+		t.Run("should mark staged jobs not loaded in time invalid", func(t *testing.T) {
+			defer func(timeout, interval time.Duration) {
+				job.TemplateMigrationLoadTimeout, job.UpstreamLoadPollInterval = timeout, interval
+			}(job.TemplateMigrationLoadTimeout, job.UpstreamLoadPollInterval)
+			job.TemplateMigrationLoadTimeout, job.UpstreamLoadPollInterval = 20*time.Millisecond, time.Millisecond
+
+			_, stagingRepo, svc, scheduler := setup()
+			defer stagingRepo.AssertExpectations(t)
+
+			scheduler.On("GetJobLoadErrors", mock2.Anything, stagingProj).Return(map[string]string{}, nil)
+			scheduler.On("IsJobLoaded", mock2.Anything, stagingProj, "test").Return(true, nil)
+			scheduler.On("IsJobLoaded", mock2.Anything, stagingProj, "test-2").Return(false, nil)
+
+			migration, err := svc.MigrateTemplate(ctx, projSpec, []models.NamespaceSpec{namespaceSpec}, false, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, 2, migration.Jobs)
+			assert.Equal(t, []string{"test-2"}, invalidJobNames(migration.Invalid))
+			assert.False(t, migration.Swapped)
+		})
+	})
+}
+
+func invalidJobNames(invalid map[string]string) []string {
+	var names []string
+	for name := range invalid {
+		names = append(names, name)
+	}
+	return names
+}
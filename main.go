@@ -19,6 +19,7 @@ import (
 	"github.com/odpf/optimus/config"
 	lg "github.com/odpf/optimus/core/logger"
 	_ "github.com/odpf/optimus/ext/datastore"
+	"github.com/odpf/optimus/ext/task/builtin"
 	"github.com/odpf/optimus/models"
 	_ "github.com/odpf/optimus/plugin"
 )
@@ -59,6 +60,13 @@ func main() {
 	// Make sure we clean up any managed plugins at the end of this
 	defer hPlugin.CleanupClients()
 
+	// built in tasks are added after installed plugins which can replace them
+	if err := builtin.Register(models.PluginRegistry); err != nil {
+		hPlugin.CleanupClients()
+		fmt.Printf("ERROR: %s\n", err.Error())
+		os.Exit(1)
+	}
+
 	command := cmd.New(
 		log.New(os.Stderr, "", 0),
 		configuration,
@@ -0,0 +1,31 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type APITokenRepository struct {
+	mock.Mock
+}
+
+func (repo *APITokenRepository) Insert(token *models.APIToken) error {
+	return repo.Called(token).Error(0)
+}
+
+func (repo *APITokenRepository) GetByHash(hash string) (models.APIToken, error) {
+	args := repo.Called(hash)
+	return args.Get(0).(models.APIToken), args.Error(1)
+}
+
+func (repo *APITokenRepository) GetAll() ([]models.APIToken, error) {
+	args := repo.Called()
+	return args.Get(0).([]models.APIToken), args.Error(1)
+}
+
+func (repo *APITokenRepository) Revoke(id uuid.UUID, at time.Time) error {
+	return repo.Called(id, at).Error(0)
+}
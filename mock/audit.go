@@ -0,0 +1,19 @@
+package mock
+
+import (
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuditLogRepository struct {
+	mock.Mock
+}
+
+func (repo *AuditLogRepository) Insert(entry *models.AuditEntry) error {
+	return repo.Called(entry).Error(0)
+}
+
+func (repo *AuditLogRepository) List(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	args := repo.Called(filter)
+	return args.Get(0).([]models.AuditEntry), args.Error(1)
+}
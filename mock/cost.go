@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type ProjectCostRepository struct {
+	mock.Mock
+}
+
+func (repo *ProjectCostRepository) GetBytesBilled(proj models.ProjectSpec, since time.Time) (int64, error) {
+	args := repo.Called(proj, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (repo *ProjectCostRepository) SaveBudgetAlert(proj models.ProjectSpec, month time.Time, threshold int) (bool, error) {
+	args := repo.Called(proj, month, threshold)
+	return args.Bool(0), args.Error(1)
+}
@@ -78,6 +78,109 @@ func (d *DatastoreTypeAdapter) FromProtobuf(bytes []byte) (models.ResourceSpec,
 	return args.Get(0).(models.ResourceSpec), args.Error(1)
 }
 
+// DatastorePlanner is a datastore which can plan resource updates
+type DatastorePlanner struct {
+	Datastorer
+}
+
+func (d *DatastorePlanner) PlanResource(ctx context.Context, inp models.UpdateResourceRequest) ([]string, error) {
+	args := d.Called(ctx, inp)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// DatastoreCopier is a datastore which can copy datasets
+type DatastoreCopier struct {
+	Datastorer
+}
+
+func (d *DatastoreCopier) CopyDataset(ctx context.Context, inp models.CopyDatasetRequest) (models.CopyDatasetResponse, error) {
+	args := d.Called(ctx, inp)
+	return args.Get(0).(models.CopyDatasetResponse), args.Error(1)
+}
+
+// DatastoreRestorer is a datastore which can restore resources to a point in time
+type DatastoreRestorer struct {
+	Datastorer
+}
+
+func (d *DatastoreRestorer) RestoreResource(ctx context.Context, inp models.RestoreResourceRequest) error {
+	return d.Called(ctx, inp).Error(0)
+}
+
+// DatastoreImporter is a datastore which can import its existing resources as specs
+type DatastoreImporter struct {
+	Datastorer
+}
+
+func (d *DatastoreImporter) ImportDataset(ctx context.Context, inp models.ImportDatasetRequest) (models.ImportDatasetResponse, error) {
+	args := d.Called(ctx, inp)
+	return args.Get(0).(models.ImportDatasetResponse), args.Error(1)
+}
+
+// DatastoreUsageReader is a datastore which audits reads of its resources
+type DatastoreUsageReader struct {
+	Datastorer
+}
+
+func (d *DatastoreUsageReader) LastReadAt(ctx context.Context, inp models.ReadUsageRequest) (models.ReadUsageResponse, error) {
+	args := d.Called(ctx, inp)
+	return args.Get(0).(models.ReadUsageResponse), args.Error(1)
+}
+
+// DatastoreJobCostReader is a datastore which bills jobs run in it
+type DatastoreJobCostReader struct {
+	Datastorer
+}
+
+func (d *DatastoreJobCostReader) JobCost(ctx context.Context, inp models.JobCostRequest) (models.JobCostResponse, error) {
+	args := d.Called(ctx, inp)
+	return args.Get(0).(models.JobCostResponse), args.Error(1)
+}
+
+// DatastorePartitionDropper is a datastore whose resources are partitioned by time
+type DatastorePartitionDropper struct {
+	Datastorer
+}
+
+func (d *DatastorePartitionDropper) DropPartitions(ctx context.Context, inp models.DropPartitionsRequest) (models.DropPartitionsResponse, error) {
+	args := d.Called(ctx, inp)
+	return args.Get(0).(models.DropPartitionsResponse), args.Error(1)
+}
+
+// DatastoreFreshnessReader is a datastore tracking modification of its resources
+type DatastoreFreshnessReader struct {
+	Datastorer
+}
+
+func (d *DatastoreFreshnessReader) LastModifiedAt(ctx context.Context, inp models.ReadFreshnessRequest) (models.ReadFreshnessResponse, error) {
+	args := d.Called(ctx, inp)
+	return args.Get(0).(models.ReadFreshnessResponse), args.Error(1)
+}
+
+// DatastoreDependencyResolver is a datastore whose resources depend on each other
+type DatastoreDependencyResolver struct {
+	Datastorer
+}
+
+func (d *DatastoreDependencyResolver) ResourceDependencies(spec models.ResourceSpec) []string {
+	return d.Called(spec).Get(0).([]string)
+}
+
+// DatastoreURNResolver is a datastore naming its resources with urns
+type DatastoreURNResolver struct {
+	Datastorer
+}
+
+func (d *DatastoreURNResolver) ResourceURN(spec models.ResourceSpec) (models.URN, error) {
+	args := d.Called(spec)
+	return args.Get(0).(models.URN), args.Error(1)
+}
+
+func (d *DatastoreURNResolver) ResourceName(urn models.URN) (string, error) {
+	args := d.Called(urn)
+	return args.Get(0).(string), args.Error(1)
+}
+
 type DatastoreService struct {
 	mock.Mock
 }
@@ -95,11 +198,21 @@ func (d *DatastoreService) UpdateResource(ctx context.Context, namespace models.
 	return d.Called(ctx, namespace, resourceSpecs, obs).Error(0)
 }
 
+func (d *DatastoreService) PlanResource(ctx context.Context, namespace models.NamespaceSpec, resourceSpecs []models.ResourceSpec, obs progress.Observer) error {
+	return d.Called(ctx, namespace, resourceSpecs, obs).Error(0)
+}
+
 func (d *DatastoreService) ReadResource(ctx context.Context, namespace models.NamespaceSpec, datastoreName, name string) (models.ResourceSpec, error) {
 	args := d.Called(ctx, namespace, datastoreName, name)
 	return args.Get(0).(models.ResourceSpec), args.Error(1)
 }
 
+func (d *DatastoreService) Promote(ctx context.Context, project models.ProjectSpec, manifest models.PromotionManifest,
+	obs progress.Observer) ([]models.PromotionResult, error) {
+	args := d.Called(ctx, project, manifest, obs)
+	return args.Get(0).([]models.PromotionResult), args.Error(1)
+}
+
 func (d *DatastoreService) DeleteResource(ctx context.Context, namespace models.NamespaceSpec, datastoreName, name string) error {
 	return d.Called(ctx, namespace, datastoreName, name).Error(1)
 }
@@ -173,3 +286,12 @@ func (r *ProjectResourceSpecRepository) GetAll() ([]models.ResourceSpec, error)
 	args := r.Called()
 	return args.Get(0).([]models.ResourceSpec), args.Error(1)
 }
+
+type ResourceRestorer struct {
+	mock.Mock
+}
+
+func (r *ResourceRestorer) Restore(proj models.ProjectSpec, actor string, restore models.ResourceRestore) (models.Operation, error) {
+	args := r.Called(proj, actor, restore)
+	return args.Get(0).(models.Operation), args.Error(1)
+}
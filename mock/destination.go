@@ -0,0 +1,20 @@
+package mock
+
+import (
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type DestinationRegistry struct {
+	mock.Mock
+}
+
+func (repo *DestinationRegistry) GetProducers(destination string) ([]models.DestinationProducer, error) {
+	args := repo.Called(destination)
+	return args.Get(0).([]models.DestinationProducer), args.Error(1)
+}
+
+func (repo *DestinationRegistry) List(prefix string, limit int) ([]models.DestinationProducer, error) {
+	args := repo.Called(prefix, limit)
+	return args.Get(0).([]models.DestinationProducer), args.Error(1)
+}
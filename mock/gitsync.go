@@ -0,0 +1,55 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type Git struct {
+	mock.Mock
+}
+
+func (g *Git) Checkout(ctx context.Context, url, branch, dir string) (string, error) {
+	args := g.Called(ctx, url, branch, dir)
+	return args.String(0), args.Error(1)
+}
+
+type GitSyncDeployer struct {
+	mock.Mock
+}
+
+func (d *GitSyncDeployer) DeployResources(ctx context.Context, projectName, namespace, datastoreName string, specs []models.ResourceSpec) error {
+	return d.Called(ctx, projectName, namespace, datastoreName, specs).Error(0)
+}
+
+func (d *GitSyncDeployer) DeployJobs(ctx context.Context, projectName, namespace string, specs []models.JobSpec) error {
+	return d.Called(ctx, projectName, namespace, specs).Error(0)
+}
+
+type DeploymentRepository struct {
+	mock.Mock
+}
+
+func (repo *DeploymentRepository) Insert(proj models.ProjectSpec, deployment *models.Deployment) error {
+	return repo.Called(proj, deployment).Error(0)
+}
+
+func (repo *DeploymentRepository) UpdateStatus(id uuid.UUID, status, message string) error {
+	return repo.Called(id, status, message).Error(0)
+}
+
+func (repo *DeploymentRepository) GetByID(proj models.ProjectSpec, id uuid.UUID) (models.Deployment, error) {
+	args := repo.Called(proj, id)
+	return args.Get(0).(models.Deployment), args.Error(1)
+}
+
+type CommitStatusPublisher struct {
+	mock.Mock
+}
+
+func (p *CommitStatusPublisher) Publish(ctx context.Context, projSpec models.ProjectSpec, status models.CommitStatus) error {
+	return p.Called(ctx, projSpec, status).Error(0)
+}
@@ -38,6 +38,37 @@ func (repo *InstanceSpecRepository) Clear(st time.Time) error {
 	return repo.Called(st).Error(0)
 }
 
+func (repo *InstanceSpecRepository) GetLatest(limit int) ([]models.InstanceSpec, error) {
+	args := repo.Called(limit)
+	return args.Get(0).([]models.InstanceSpec), args.Error(1)
+}
+
+func (repo *InstanceSpecRepository) Touch(st time.Time) error {
+	return repo.Called(st).Error(0)
+}
+
+func (repo *InstanceSpecRepository) UpdateState(st time.Time, state string) error {
+	return repo.Called(st, state).Error(0)
+}
+
+func (repo *InstanceSpecRepository) Heartbeat(st time.Time, at time.Time) error {
+	return repo.Called(st, at).Error(0)
+}
+
+func (repo *InstanceSpecRepository) UpdateCost(st time.Time, cost models.InstanceCost) error {
+	return repo.Called(st, cost).Error(0)
+}
+
+func (repo *InstanceSpecRepository) GetSince(since time.Time) ([]models.InstanceSpec, error) {
+	args := repo.Called(since)
+	return args.Get(0).([]models.InstanceSpec), args.Error(1)
+}
+
+func (repo *InstanceSpecRepository) GetLatestByState(state string) (models.InstanceSpec, error) {
+	args := repo.Called(state)
+	return args.Get(0).(models.InstanceSpec), args.Error(1)
+}
+
 type InstanceService struct {
 	mock.Mock
 }
@@ -52,3 +83,62 @@ func (s *InstanceService) Register(jobSpec models.JobSpec, scheduledAt time.Time
 	args := s.Called(jobSpec, scheduledAt, taskType)
 	return args.Get(0).(models.InstanceSpec), args.Error(1)
 }
+
+func (s *InstanceService) GetAverageDuration(jobSpec models.JobSpec) (time.Duration, error) {
+	args := s.Called(jobSpec)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (s *InstanceService) GetRunDurations(jobSpec models.JobSpec, limit int) ([]models.RunDuration, error) {
+	args := s.Called(jobSpec, limit)
+	return args.Get(0).([]models.RunDuration), args.Error(1)
+}
+
+func (s *InstanceService) UpdateState(jobSpec models.JobSpec, scheduledAt time.Time, state string) error {
+	return s.Called(jobSpec, scheduledAt, state).Error(0)
+}
+
+func (s *InstanceService) Heartbeat(jobSpec models.JobSpec, scheduledAt time.Time) error {
+	return s.Called(jobSpec, scheduledAt).Error(0)
+}
+
+func (s *InstanceService) GetHungRuns(jobSpec models.JobSpec, since time.Time, silence time.Duration) ([]models.InstanceSpec, error) {
+	args := s.Called(jobSpec, since, silence)
+	return args.Get(0).([]models.InstanceSpec), args.Error(1)
+}
+
+func (s *InstanceService) GetRunStats(jobSpec models.JobSpec, since time.Time) (models.JobRunStats, error) {
+	args := s.Called(jobSpec, since)
+	return args.Get(0).(models.JobRunStats), args.Error(1)
+}
+
+func (s *InstanceService) RecordCost(jobSpec models.JobSpec, scheduledAt time.Time, cost models.InstanceCost) error {
+	return s.Called(jobSpec, scheduledAt, cost).Error(0)
+}
+
+func (s *InstanceService) GetCostStats(jobSpec models.JobSpec, since time.Time) (models.JobCostStats, error) {
+	args := s.Called(jobSpec, since)
+	return args.Get(0).(models.JobCostStats), args.Error(1)
+}
+
+func (s *InstanceService) GetLastSuccess(jobSpec models.JobSpec) (time.Time, bool, error) {
+	args := s.Called(jobSpec)
+	return args.Get(0).(time.Time), args.Bool(1), args.Error(2)
+}
+
+type JobCheckpointRepository struct {
+	mock.Mock
+}
+
+func (repo *JobCheckpointRepository) Save(jobSpec models.JobSpec, checkpoint models.JobCheckpoint) error {
+	return repo.Called(jobSpec, checkpoint).Error(0)
+}
+
+func (repo *JobCheckpointRepository) GetAll(jobSpec models.JobSpec) ([]models.JobCheckpoint, error) {
+	args := repo.Called(jobSpec)
+	return args.Get(0).([]models.JobCheckpoint), args.Error(1)
+}
+
+func (repo *JobCheckpointRepository) Delete(jobSpec models.JobSpec, name string) error {
+	return repo.Called(jobSpec, name).Error(0)
+}
@@ -2,6 +2,9 @@ package mock
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/odpf/optimus/job"
 
@@ -52,6 +55,14 @@ func (repo *ProjectJobSpecRepository) GetByDestination(dest string) (models.JobS
 	return models.JobSpec{}, models.ProjectSpec{}, args.Error(2)
 }
 
+func (repo *ProjectJobSpecRepository) GetDestinations() ([]models.JobDestination, error) {
+	args := repo.Called()
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.JobDestination), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // JobSpecRepoFactory to store raw specs at namespace level
 type JobSpecRepoFactory struct {
 	mock.Mock
@@ -82,6 +93,10 @@ func (repo *JobSpecRepository) Delete(name string) error {
 	return repo.Called(name).Error(0)
 }
 
+func (repo *JobSpecRepository) Rename(oldName, newName string, dependents []models.JobSpec) error {
+	return repo.Called(oldName, newName, dependents).Error(0)
+}
+
 func (repo *JobSpecRepository) GetAll() ([]models.JobSpec, error) {
 	args := repo.Called()
 	if args.Get(0) != nil {
@@ -138,6 +153,16 @@ func (repo *JobRepository) Delete(ctx context.Context, namespace models.Namespac
 	return args.Error(0)
 }
 
+// ChecksumJobRepository is a JobRepository telling checksums of stored jobs
+type ChecksumJobRepository struct {
+	JobRepository
+}
+
+func (repo *ChecksumJobRepository) Checksums(ctx context.Context, namespace models.NamespaceSpec) (map[string]string, error) {
+	args := repo.Called(ctx, namespace)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
 type JobConfigLocalFactory struct {
 	mock.Mock
 }
@@ -186,6 +211,11 @@ func (srv *JobService) Sync(ctx context.Context, spec models.NamespaceSpec, obse
 	return args.Error(0)
 }
 
+func (srv *JobService) Unschedule(ctx context.Context, spec models.NamespaceSpec, observer progress.Observer) error {
+	args := srv.Called(ctx, spec, observer)
+	return args.Error(0)
+}
+
 func (j *JobService) Check(namespaceSpec models.NamespaceSpec, specs []models.JobSpec, observer progress.Observer) error {
 	args := j.Called(namespaceSpec, specs, observer)
 	return args.Error(0)
@@ -196,6 +226,38 @@ func (j *JobService) Delete(ctx context.Context, c models.NamespaceSpec, job mod
 	return args.Error(0)
 }
 
+func (j *JobService) Rename(ctx context.Context, namespaceSpec models.NamespaceSpec, oldName, newName string,
+	observer progress.Observer) ([]string, error) {
+	args := j.Called(ctx, namespaceSpec, oldName, newName, observer)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (j *JobService) DeployCanary(ctx context.Context, namespaceSpec models.NamespaceSpec, specs []models.JobSpec, observer progress.Observer) error {
+	args := j.Called(ctx, namespaceSpec, specs, observer)
+	return args.Error(0)
+}
+
+func (j *JobService) DeleteCanary(ctx context.Context, namespaceSpec models.NamespaceSpec, specs []models.JobSpec) error {
+	args := j.Called(ctx, namespaceSpec, specs)
+	return args.Error(0)
+}
+
+func (j *JobService) CheckDestinations(namespaceSpec models.NamespaceSpec, specs []models.JobSpec) error {
+	args := j.Called(namespaceSpec, specs)
+	return args.Error(0)
+}
+
+func (j *JobService) ExplainDependencies(namespaceSpec models.NamespaceSpec, spec models.JobSpec) ([]models.JobSpecDependencyExplanation, error) {
+	args := j.Called(namespaceSpec, spec)
+	return args.Get(0).([]models.JobSpecDependencyExplanation), args.Error(1)
+}
+
+func (j *JobService) MigrateTemplate(ctx context.Context, projSpec models.ProjectSpec, namespaces []models.NamespaceSpec,
+	swap bool, observer progress.Observer) (models.TemplateMigration, error) {
+	args := j.Called(ctx, projSpec, namespaces, swap, observer)
+	return args.Get(0).(models.TemplateMigration), args.Error(1)
+}
+
 func (j *JobService) ReplayDryRun(replayRequest *models.ReplayWorkerRequest) (*tree.TreeNode, error) {
 	args := j.Called(replayRequest)
 	return args.Get(0).(*tree.TreeNode), args.Error(1)
@@ -206,6 +268,22 @@ func (j *JobService) Replay(ctx context.Context, replayRequest *models.ReplayWor
 	return args.Get(0).(string), args.Error(1)
 }
 
+func (j *JobService) ReplayPlanDryRun(planRequest models.ReplayPlanRequest) ([]*tree.TreeNode, error) {
+	args := j.Called(planRequest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*tree.TreeNode), args.Error(1)
+}
+
+func (j *JobService) ReplayPlan(ctx context.Context, planRequest models.ReplayPlanRequest) (*models.ReplayPlan, error) {
+	args := j.Called(ctx, planRequest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReplayPlan), args.Error(1)
+}
+
 type Compiler struct {
 	mock.Mock
 }
@@ -225,6 +303,22 @@ func (srv *DependencyResolver) Resolve(projectSpec models.ProjectSpec, projectJo
 	return args.Get(0).(models.JobSpec), args.Error(1)
 }
 
+func (srv *DependencyResolver) Explain(projectSpec models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
+	jobSpec models.JobSpec) ([]models.JobSpecDependencyExplanation, error) {
+	args := srv.Called(projectSpec, projectJobSpecRepo, jobSpec)
+	return args.Get(0).([]models.JobSpecDependencyExplanation), args.Error(1)
+}
+
+type DependencyResolvedSpecGetter struct {
+	mock.Mock
+}
+
+func (srv *DependencyResolvedSpecGetter) GetDependencyResolvedSpecs(proj models.ProjectSpec, projectJobSpecRepo store.ProjectJobSpecRepository,
+	obs progress.Observer) ([]models.JobSpec, error) {
+	args := srv.Called(proj, projectJobSpecRepo, obs)
+	return args.Get(0).([]models.JobSpec), args.Error(1)
+}
+
 type PriorityResolver struct {
 	mock.Mock
 }
@@ -253,3 +347,57 @@ func (n *Notifier) Close() error {
 func (n *Notifier) Notify(ctx context.Context, attr models.NotifyAttrs) error {
 	return n.Called(ctx, attr).Error(0)
 }
+
+type DeployChangelogRepository struct {
+	mock.Mock
+}
+
+func (repo *DeployChangelogRepository) Insert(proj models.ProjectSpec, changelog *models.DeployChangelog) error {
+	return repo.Called(proj, changelog).Error(0)
+}
+
+func (repo *DeployChangelogRepository) GetLatest(proj models.ProjectSpec, namespace string, limit int) ([]models.DeployChangelog, error) {
+	args := repo.Called(proj, namespace, limit)
+	return args.Get(0).([]models.DeployChangelog), args.Error(1)
+}
+
+func (repo *DeployChangelogRepository) GetByID(proj models.ProjectSpec, id uuid.UUID) (models.DeployChangelog, error) {
+	args := repo.Called(proj, id)
+	return args.Get(0).(models.DeployChangelog), args.Error(1)
+}
+
+type JobLockRepository struct {
+	mock.Mock
+}
+
+func (repo *JobLockRepository) Acquire(lock models.JobLock, jobNames []string, ttl time.Duration) (*models.JobLock, error) {
+	args := repo.Called(lock, jobNames, ttl)
+	return args.Get(0).(*models.JobLock), args.Error(1)
+}
+
+func (repo *JobLockRepository) Extend(tokens []uuid.UUID, ttl time.Duration) error {
+	return repo.Called(tokens, ttl).Error(0)
+}
+
+func (repo *JobLockRepository) Release(token uuid.UUID) error {
+	return repo.Called(token).Error(0)
+}
+
+type CostCollector struct {
+	mock.Mock
+}
+
+func (c *CostCollector) Collect(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec,
+	scheduledAt time.Time, datastoreJobs []string) error {
+	return c.Called(ctx, namespace, jobSpec, scheduledAt, datastoreJobs).Error(0)
+}
+
+type DependencyFreshnessChecker struct {
+	mock.Mock
+}
+
+func (c *DependencyFreshnessChecker) Check(ctx context.Context, projSpec models.ProjectSpec, jobName,
+	dependencyProjectName, dependencyName string, scheduledAt time.Time) (models.DependencyFreshness, error) {
+	args := c.Called(ctx, projSpec, jobName, dependencyProjectName, dependencyName, scheduledAt)
+	return args.Get(0).(models.DependencyFreshness), args.Error(1)
+}
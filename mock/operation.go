@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type OperationRepository struct {
+	mock.Mock
+}
+
+func (repo *OperationRepository) Insert(proj models.ProjectSpec, op *models.Operation) error {
+	return repo.Called(proj, op).Error(0)
+}
+
+func (repo *OperationRepository) UpdateProgress(id uuid.UUID, progress models.OperationProgress) error {
+	return repo.Called(id, progress).Error(0)
+}
+
+func (repo *OperationRepository) UpdateStatus(id uuid.UUID, status, message string, result []byte) error {
+	return repo.Called(id, status, message, result).Error(0)
+}
+
+func (repo *OperationRepository) GetByID(proj models.ProjectSpec, id uuid.UUID) (models.Operation, error) {
+	args := repo.Called(proj, id)
+	return args.Get(0).(models.Operation), args.Error(1)
+}
+
+func (repo *OperationRepository) GetLatest(proj models.ProjectSpec, kind string, limit int) ([]models.Operation, error) {
+	args := repo.Called(proj, kind, limit)
+	return args.Get(0).([]models.Operation), args.Error(1)
+}
+
+// OperationRunner runs the work of operations it is asked to start right
+// away, recording its progress and outcome for tests to assert on
+type OperationRunner struct {
+	mock.Mock
+
+	Progress []models.OperationProgress
+	Result   interface{}
+	Err      error
+}
+
+func (r *OperationRunner) Start(proj models.ProjectSpec, kind, actor string, params interface{},
+	fn models.OperationFunc) (models.Operation, error) {
+	args := r.Called(proj, kind, actor, params)
+	if args.Error(1) != nil {
+		return models.Operation{}, args.Error(1)
+	}
+	r.Result, r.Err = fn(context.Background(), func(progress models.OperationProgress) {
+		r.Progress = append(r.Progress, progress)
+	})
+	return args.Get(0).(models.Operation), nil
+}
+
+type OperationCanceller struct {
+	mock.Mock
+}
+
+func (c *OperationCanceller) Cancel(proj models.ProjectSpec, id uuid.UUID) error {
+	return c.Called(proj, id).Error(0)
+}
@@ -11,8 +11,8 @@ type SupportedPluginRepo struct {
 	mock.Mock
 }
 
-func (repo *SupportedPluginRepo) Add(plugin models.BasePlugin, mod models.CommandLineMod, mod2 models.DependencyResolverMod) error {
-	return repo.Called(plugin, mod, mod2).Error(0)
+func (repo *SupportedPluginRepo) Add(plugin models.BasePlugin, mod models.CommandLineMod, mod2 models.DependencyResolverMod, mod3 models.ConfigValidatorMod) error {
+	return repo.Called(plugin, mod, mod2, mod3).Error(0)
 }
 
 func (repo *SupportedPluginRepo) GetByName(s string) (*models.Plugin, error) {
@@ -26,7 +26,8 @@ func (repo *SupportedPluginRepo) GetAll() []*models.Plugin {
 }
 
 func (repo *SupportedPluginRepo) GetTasks() []*models.Plugin {
-	panic("implement me")
+	args := repo.Called()
+	return args.Get(0).([]*models.Plugin)
 }
 
 func (repo *SupportedPluginRepo) GetHooks() []*models.Plugin {
@@ -102,3 +103,17 @@ func (repo *DependencyResolverMod) GenerateDependencies(ctx context.Context, inp
 	args := repo.Called(ctx, inp)
 	return args.Get(0).(*models.GenerateDependenciesResponse), args.Error(1)
 }
+
+type ConfigValidatorMod struct {
+	mock.Mock `hash:"-"`
+}
+
+func (repo *ConfigValidatorMod) PluginInfo() (*models.PluginInfoResponse, error) {
+	args := repo.Called()
+	return args.Get(0).(*models.PluginInfoResponse), args.Error(1)
+}
+
+func (repo *ConfigValidatorMod) ValidateConfig(ctx context.Context, inp models.ValidateConfigRequest) (*models.ValidateConfigResponse, error) {
+	args := repo.Called(ctx, inp)
+	return args.Get(0).(*models.ValidateConfigResponse), args.Error(1)
+}
@@ -68,3 +68,37 @@ type PipelineLogObserver struct {
 func (obs *PipelineLogObserver) Notify(evt progress.Event) {
 	obs.Called(evt)
 }
+
+type ProjectFreezeRepository struct {
+	mock.Mock
+}
+
+func (repo *ProjectFreezeRepository) Save(proj models.ProjectSpec, freeze models.ProjectFreeze) error {
+	return repo.Called(proj, freeze).Error(0)
+}
+
+func (repo *ProjectFreezeRepository) GetByProject(proj models.ProjectSpec) (models.ProjectFreeze, error) {
+	args := repo.Called(proj)
+	return args.Get(0).(models.ProjectFreeze), args.Error(1)
+}
+
+func (repo *ProjectFreezeRepository) Delete(proj models.ProjectSpec) error {
+	return repo.Called(proj).Error(0)
+}
+
+type ProjectArchiveRepository struct {
+	mock.Mock
+}
+
+func (repo *ProjectArchiveRepository) Save(proj models.ProjectSpec, archive models.ProjectArchive) error {
+	return repo.Called(proj, archive).Error(0)
+}
+
+func (repo *ProjectArchiveRepository) GetByProject(proj models.ProjectSpec) (models.ProjectArchive, error) {
+	args := repo.Called(proj)
+	return args.Get(0).(models.ProjectArchive), args.Error(1)
+}
+
+func (repo *ProjectArchiveRepository) Delete(proj models.ProjectSpec) error {
+	return repo.Called(proj).Error(0)
+}
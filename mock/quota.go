@@ -0,0 +1,64 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type ProjectQuotaRepository struct {
+	mock.Mock
+}
+
+func (repo *ProjectQuotaRepository) Save(proj models.ProjectSpec, quota models.ProjectQuota) error {
+	return repo.Called(proj, quota).Error(0)
+}
+
+func (repo *ProjectQuotaRepository) GetByProject(proj models.ProjectSpec) (models.ProjectQuota, error) {
+	args := repo.Called(proj)
+	return args.Get(0).(models.ProjectQuota), args.Error(1)
+}
+
+func (repo *ProjectQuotaRepository) AddReplayRuns(proj models.ProjectSpec, day time.Time, runs int) error {
+	return repo.Called(proj, day, runs).Error(0)
+}
+
+func (repo *ProjectQuotaRepository) ReserveReplayRuns(proj models.ProjectSpec, day time.Time, runs, limit int) (bool, error) {
+	args := repo.Called(proj, day, runs, limit)
+	return args.Bool(0), args.Error(1)
+}
+
+func (repo *ProjectQuotaRepository) GetReplayRuns(proj models.ProjectSpec, day time.Time) (int, error) {
+	args := repo.Called(proj, day)
+	return args.Int(0), args.Error(1)
+}
+
+type QuotaService struct {
+	mock.Mock
+}
+
+func (srv *QuotaService) Get(proj models.ProjectSpec) (models.ProjectQuota, error) {
+	args := srv.Called(proj)
+	return args.Get(0).(models.ProjectQuota), args.Error(1)
+}
+
+func (srv *QuotaService) Update(proj models.ProjectSpec, quota models.ProjectQuota) error {
+	return srv.Called(proj, quota).Error(0)
+}
+
+func (srv *QuotaService) CheckJobs(proj models.ProjectSpec, jobs int) error {
+	return srv.Called(proj, jobs).Error(0)
+}
+
+func (srv *QuotaService) CheckResources(proj models.ProjectSpec, resources int) error {
+	return srv.Called(proj, resources).Error(0)
+}
+
+func (srv *QuotaService) ReserveReplayRuns(proj models.ProjectSpec, runs int) error {
+	return srv.Called(proj, runs).Error(0)
+}
+
+func (srv *QuotaService) ReleaseReplayRuns(proj models.ProjectSpec, runs int) error {
+	return srv.Called(proj, runs).Error(0)
+}
@@ -36,6 +36,11 @@ func (repo *ReplayRepository) GetByJobIDAndStatus(jobID uuid.UUID, status []stri
 	return args.Get(0).([]models.ReplaySpec), args.Error(1)
 }
 
+func (repo *ReplayRepository) GetByParentID(parentID uuid.UUID) ([]models.ReplaySpec, error) {
+	args := repo.Called(parentID)
+	return args.Get(0).([]models.ReplaySpec), args.Error(1)
+}
+
 type ReplaySpecRepoFactory struct {
 	mock.Mock
 }
@@ -53,11 +58,25 @@ func (rm *ReplayManager) Replay(ctx context.Context, reqInput *models.ReplayWork
 	return args.Get(0).(string), args.Error(1)
 }
 
+func (rm *ReplayManager) ReplayPlan(ctx context.Context, plan *models.ReplayPlan) (string, error) {
+	args := rm.Called(ctx, plan)
+	return args.Get(0).(string), args.Error(1)
+}
+
+func (rm *ReplayManager) Cancel(ctx context.Context, project models.ProjectSpec, replay models.ReplaySpec) error {
+	return rm.Called(ctx, project, replay).Error(0)
+}
+
 func (rm *ReplayManager) Init() {
 	rm.Called()
 	return
 }
 
+func (rm *ReplayManager) QueuePosition(replayID uuid.UUID) (models.ReplayQueuePosition, bool) {
+	args := rm.Called(replayID)
+	return args.Get(0).(models.ReplayQueuePosition), args.Bool(1)
+}
+
 type ReplayWorker struct {
 	mock.Mock
 }
@@ -0,0 +1,57 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/replication"
+	"github.com/stretchr/testify/mock"
+)
+
+type ReplicationSource struct {
+	mock.Mock
+}
+
+func (s *ReplicationSource) Export(ctx context.Context) ([]replication.Record, error) {
+	args := s.Called(ctx)
+	return args.Get(0).([]replication.Record), args.Error(1)
+}
+
+type ReplicationStore struct {
+	mock.Mock
+}
+
+func (s *ReplicationStore) Projects() ([]string, error) {
+	args := s.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (s *ReplicationStore) Export(projectName string) ([]replication.Record, error) {
+	args := s.Called(projectName)
+	return args.Get(0).([]replication.Record), args.Error(1)
+}
+
+func (s *ReplicationStore) Apply(rec replication.Record) error {
+	return s.Called(rec).Error(0)
+}
+
+func (s *ReplicationStore) Remove(rec replication.Record) error {
+	return s.Called(rec).Error(0)
+}
+
+type ReplicationStateRepository struct {
+	mock.Mock
+}
+
+func (repo *ReplicationStateRepository) Save(projectName string, state models.ReplicationState) error {
+	return repo.Called(projectName, state).Error(0)
+}
+
+func (repo *ReplicationStateRepository) GetAll(projectName string) ([]models.ReplicationState, error) {
+	args := repo.Called(projectName)
+	return args.Get(0).([]models.ReplicationState), args.Error(1)
+}
+
+func (repo *ReplicationStateRepository) Delete(projectName, key string) error {
+	return repo.Called(projectName, key).Error(0)
+}
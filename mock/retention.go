@@ -0,0 +1,48 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type RetentionRepository struct {
+	mock.Mock
+}
+
+func (repo *RetentionRepository) DeleteInstances(project models.ProjectSpec, before time.Time, limit int) (int64, error) {
+	args := repo.Called(project, before, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (repo *RetentionRepository) DeleteReplays(project models.ProjectSpec, before time.Time, limit int) (int64, error) {
+	args := repo.Called(project, before, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (repo *RetentionRepository) DeleteAuditLogs(project models.ProjectSpec, before time.Time, limit int) (int64, error) {
+	args := repo.Called(project, before, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (repo *RetentionRepository) DeleteJobs(project models.ProjectSpec, limit int) (int64, error) {
+	args := repo.Called(project, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type ReclaimRecorder struct {
+	mock.Mock
+}
+
+func (r *ReclaimRecorder) RecordReclaimed(projectName, table string, rows int64) {
+	r.Called(projectName, table, rows)
+}
+
+type PartitionRecorder struct {
+	mock.Mock
+}
+
+func (r *PartitionRecorder) RecordPartitionsDropped(projectName, jobName string, partitions int) {
+	r.Called(projectName, jobName, partitions)
+}
@@ -47,3 +47,38 @@ func (ms *Scheduler) GetDagRunStatus(ctx context.Context, projSpec models.Projec
 	args := ms.Called(ctx, projSpec, jobName, startDate, endDate, batchSize)
 	return args.Get(0).([]models.JobStatus), args.Error(1)
 }
+
+func (ms *Scheduler) GetInstanceLog(ctx context.Context, projSpec models.ProjectSpec, jobName string, scheduledAt time.Time,
+	instanceType models.InstanceType, instanceName string, attempt int) (models.InstanceLog, error) {
+	args := ms.Called(ctx, projSpec, jobName, scheduledAt, instanceType, instanceName, attempt)
+	return args.Get(0).(models.InstanceLog), args.Error(1)
+}
+
+func (ms *Scheduler) IsJobLoaded(ctx context.Context, projSpec models.ProjectSpec, jobName string) (bool, error) {
+	args := ms.Called(ctx, projSpec, jobName)
+	return args.Bool(0), args.Error(1)
+}
+
+func (ms *Scheduler) RunJob(ctx context.Context, projSpec models.ProjectSpec, jobName string, scheduledAt time.Time) error {
+	args := ms.Called(ctx, projSpec, jobName, scheduledAt)
+	return args.Error(0)
+}
+
+func (ms *Scheduler) SetJobPaused(ctx context.Context, projSpec models.ProjectSpec, jobName string, paused bool) error {
+	args := ms.Called(ctx, projSpec, jobName, paused)
+	return args.Error(0)
+}
+
+func (ms *Scheduler) HealthCheck(ctx context.Context, projSpec models.ProjectSpec) error {
+	return ms.Called(ctx, projSpec).Error(0)
+}
+
+func (ms *Scheduler) MarkSensorsSuccess(ctx context.Context, projSpec models.ProjectSpec, jobName string, startDate,
+	endDate time.Time, except []string) error {
+	return ms.Called(ctx, projSpec, jobName, startDate, endDate, except).Error(0)
+}
+
+func (ms *Scheduler) GetJobLoadErrors(ctx context.Context, projSpec models.ProjectSpec) (map[string]string, error) {
+	args := ms.Called(ctx, projSpec)
+	return args.Get(0).(map[string]string), args.Error(1)
+}
@@ -0,0 +1,20 @@
+package mock
+
+import (
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type SearchRepository struct {
+	mock.Mock
+}
+
+func (repo *SearchRepository) SearchJobs(query models.SearchQuery) ([]models.JobSearchResult, error) {
+	args := repo.Called(query)
+	return args.Get(0).([]models.JobSearchResult), args.Error(1)
+}
+
+func (repo *SearchRepository) SearchResources(query models.SearchQuery) ([]models.ResourceSearchResult, error) {
+	args := repo.Called(query)
+	return args.Get(0).([]models.ResourceSearchResult), args.Error(1)
+}
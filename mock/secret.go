@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type SecretBackend struct {
+	mock.Mock
+}
+
+func (b *SecretBackend) Name() string {
+	return b.Called().String(0)
+}
+
+func (b *SecretBackend) Resolve(ctx context.Context, proj models.ProjectSpec, names []string) (map[string]string, error) {
+	args := b.Called(ctx, proj, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+type SecretResolver struct {
+	mock.Mock
+}
+
+func (r *SecretResolver) Resolve(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec,
+	names []string) (map[string]string, error) {
+	args := r.Called(ctx, namespace, jobSpec, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]string), args.Error(1)
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TokenActionRead allows reading jobs, resources, runs and replays
+	TokenActionRead = "read"
+	// TokenActionDeploy allows deploying jobs and resources and registering
+	// projects, namespaces and secrets, it implies read
+	TokenActionDeploy = "deploy"
+	// TokenActionReplay allows replaying jobs, it implies read
+	TokenActionReplay = "replay"
+	// TokenActionAdmin allows calling admin endpoints, e.g. managing
+	// tokens, it implies every other action
+	TokenActionAdmin = "admin"
+
+	// TokenScopeAllProjects as project of a scope applies it to every
+	// project, e.g. read:*
+	TokenScopeAllProjects = "*"
+)
+
+var (
+	// ErrInvalidTokenScope signifies a malformed scope of an api token
+	ErrInvalidTokenScope = errors.New("invalid token scope")
+
+	tokenActions = map[string]bool{
+		TokenActionRead:   true,
+		TokenActionDeploy: true,
+		TokenActionReplay: true,
+		TokenActionAdmin:  true,
+	}
+)
+
+// APIToken is a server managed credential for automation, e.g. CI, acting
+// within its scopes. Only the hash of its secret is stored
+type APIToken struct {
+	ID   uuid.UUID
+	Name string
+	// Hash is the sha256 of the secret of token
+	Hash   string
+	Scopes []TokenScope
+	// Actor is the user who created the token
+	Actor string
+	// ExpiresAt is when the token stops being accepted, zero means it is
+	// accepted till revoked
+	ExpiresAt time.Time
+	// RevokedAt is when the token was revoked, zero if it was not
+	RevokedAt time.Time
+
+	CreatedAt time.Time
+}
+
+// IsActive checks if token is accepted at given time
+func (t APIToken) IsActive(now time.Time) bool {
+	return t.RevokedAt.IsZero() && (t.ExpiresAt.IsZero() || now.Before(t.ExpiresAt))
+}
+
+// Allows checks if any scope of token allows action on project, empty
+// project is a call not made on a single project which only a scope of all
+// projects allows
+func (t APIToken) Allows(action, projectName string) bool {
+	for _, scope := range t.Scopes {
+		if scope.Allows(action, projectName) {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenScope is an action an api token is allowed on a project, written as
+// action:project e.g. deploy:my-project or read:*
+type TokenScope struct {
+	Action  string
+	Project string
+}
+
+func (s TokenScope) String() string {
+	return fmt.Sprintf("%s:%s", s.Action, s.Project)
+}
+
+// Allows checks if scope allows action on project
+func (s TokenScope) Allows(action, projectName string) bool {
+	if s.Project != TokenScopeAllProjects && s.Project != projectName {
+		return false
+	}
+	switch s.Action {
+	case action, TokenActionAdmin:
+		return true
+	case TokenActionDeploy, TokenActionReplay:
+		return action == TokenActionRead
+	}
+	return false
+}
+
+// ParseTokenScope parses a scope written as action:project
+func ParseTokenScope(value string) (TokenScope, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return TokenScope{}, errors.Wrapf(ErrInvalidTokenScope, "%s is not action:project", value)
+	}
+	if !tokenActions[parts[0]] {
+		return TokenScope{}, errors.Wrapf(ErrInvalidTokenScope, "unknown action %s of %s", parts[0], value)
+	}
+	return TokenScope{Action: parts[0], Project: parts[1]}, nil
+}
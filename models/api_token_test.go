@@ -0,0 +1,51 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIToken(t *testing.T) {
+	t.Run("should parse scopes written as action:project", func(t *testing.T) {
+		scope, err := models.ParseTokenScope("deploy:my-project")
+		assert.Nil(t, err)
+		assert.Equal(t, models.TokenScope{Action: models.TokenActionDeploy, Project: "my-project"}, scope)
+		assert.Equal(t, "deploy:my-project", scope.String())
+
+		for _, value := range []string{"deploy", "deploy:", "write:my-project", ":my-project"} {
+			_, err := models.ParseTokenScope(value)
+			assert.True(t, errors.Is(err, models.ErrInvalidTokenScope), value)
+		}
+	})
+	t.Run("should allow actions of scopes on their projects", func(t *testing.T) {
+		token := models.APIToken{Scopes: []models.TokenScope{
+			{Action: models.TokenActionDeploy, Project: "proj-a"},
+			{Action: models.TokenActionRead, Project: models.TokenScopeAllProjects},
+		}}
+		assert.True(t, token.Allows(models.TokenActionDeploy, "proj-a"))
+		assert.False(t, token.Allows(models.TokenActionDeploy, "proj-b"))
+		assert.False(t, token.Allows(models.TokenActionReplay, "proj-a"))
+		assert.True(t, token.Allows(models.TokenActionRead, "proj-b"))
+		assert.True(t, token.Allows(models.TokenActionRead, ""))
+		assert.False(t, token.Allows(models.TokenActionAdmin, ""))
+
+		replayer := models.APIToken{Scopes: []models.TokenScope{{Action: models.TokenActionReplay, Project: "proj-a"}}}
+		assert.True(t, replayer.Allows(models.TokenActionRead, "proj-a"))
+		assert.False(t, replayer.Allows(models.TokenActionRead, ""))
+
+		admin := models.APIToken{Scopes: []models.TokenScope{{Action: models.TokenActionAdmin, Project: models.TokenScopeAllProjects}}}
+		assert.True(t, admin.Allows(models.TokenActionReplay, "proj-a"))
+		assert.True(t, admin.Allows(models.TokenActionAdmin, ""))
+	})
+	t.Run("should be active till it expires or is revoked", func(t *testing.T) {
+		now := time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)
+		assert.True(t, models.APIToken{}.IsActive(now))
+		assert.True(t, models.APIToken{ExpiresAt: now.Add(time.Minute)}.IsActive(now))
+		assert.False(t, models.APIToken{ExpiresAt: now}.IsActive(now))
+		assert.False(t, models.APIToken{RevokedAt: now.Add(-time.Minute)}.IsActive(now))
+	})
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// ProjectArchive records a decommissioned project. Jobs of an archived
+// project are paused and removed from scheduler, while their specifications
+// and runs are retained till PurgeAt so the project can be restored
+type ProjectArchive struct {
+	Reason string
+	// Actor is the user who archived the project
+	Actor string
+	// Snapshot is the serialized job specifications and configs of
+	// namespaces of the project when it was archived, the project is
+	// restored from it
+	Snapshot []byte
+	// PurgeAt is when jobs of the project are deleted for good along with
+	// their runs, zero keeps them till the project is restored
+	PurgeAt time.Time
+	// PurgedAt is when jobs of the project were deleted, purged projects
+	// can't be restored
+	PurgedAt time.Time
+
+	CreatedAt time.Time
+}
+
+// IsPurged checks if jobs of the archived project are deleted
+func (a ProjectArchive) IsPurged() bool {
+	return !a.PurgedAt.IsZero()
+}
+
+// IsPurgeDue checks if jobs of the archived project are to be deleted at
+// given time
+func (a ProjectArchive) IsPurgeDue(now time.Time) bool {
+	return !a.IsPurged() && !a.PurgeAt.IsZero() && !now.Before(a.PurgeAt)
+}
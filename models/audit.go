@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEntry is a record of a mutating api call made to optimus
+type AuditEntry struct {
+	ID uuid.UUID
+
+	// Actor who made the call, as identified by the client
+	Actor string
+	// RPC is the full grpc method name
+	RPC         string
+	ProjectName string
+	// PayloadHash is the sha256 of request payload
+	PayloadHash string
+	Outcome     string
+	Message     string
+
+	CreatedAt time.Time
+}
+
+// AuditFilter narrows down audit entries, zero values are ignored
+type AuditFilter struct {
+	Actor string
+	// RPC matches the suffix of method name, e.g. DeployJobSpecification
+	RPC         string
+	ProjectName string
+	Outcome     string
+	Since       time.Time
+	Until       time.Time
+
+	// Limit is the max number of latest entries returned
+	Limit int
+}
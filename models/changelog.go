@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobChangeType string
+
+const (
+	JobChangeTypeAdded    JobChangeType = "added"
+	JobChangeTypeModified JobChangeType = "modified"
+	JobChangeTypeRemoved  JobChangeType = "removed"
+)
+
+// JobFieldChange is the change in a single field of job specification,
+// Field is a dot separated path like task.config.TABLE
+type JobFieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// JobChange is how a job changed in a deployment, Fields are
+// set only for modified jobs
+type JobChange struct {
+	Name   string
+	Type   JobChangeType
+	Fields []JobFieldChange
+}
+
+// JobDeployResult is the outcome of deploying a job to scheduler
+type JobDeployResult struct {
+	Name    string
+	Success bool
+	Message string
+}
+
+// DeployChangelog is the record of jobs changed by a deployment
+// of a namespace
+type DeployChangelog struct {
+	ID        uuid.UUID
+	Namespace string
+	Actor     string
+	Changes   []JobChange
+
+	// Snapshot is the serialized job specifications deployed, used to
+	// roll back to this deployment, SnapshotHash identifies its content
+	Snapshot     []byte
+	SnapshotHash string
+	Results      []JobDeployResult
+	// Failed is set if any job failed to deploy
+	Failed bool
+	// RollbackOf is the deployment whose snapshot was redeployed, if any
+	RollbackOf uuid.UUID
+
+	CreatedAt time.Time
+}
+
+// CanRollbackTo checks if jobs of namespace can be rolled back to the
+// snapshot of this deployment
+func (c DeployChangelog) CanRollbackTo() bool {
+	return !c.Failed && len(c.Snapshot) > 0
+}
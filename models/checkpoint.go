@@ -0,0 +1,46 @@
+package models
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// JobCheckpointEnvPrefix prefixes names of checkpoints of a job in the
+	// compiled context of its runs, e.g. CHECKPOINT__LAST_OFFSET
+	JobCheckpointEnvPrefix = "CHECKPOINT__"
+
+	// MaxJobCheckpointSize is the max size of value of a checkpoint in bytes,
+	// checkpoints are meant for small state like the last processed offset
+	MaxJobCheckpointSize = 4096
+)
+
+var (
+	// ErrInvalidJobCheckpoint signifies that a checkpoint can't be saved
+	ErrInvalidJobCheckpoint = errors.New("invalid job checkpoint")
+
+	// checkpoint names are used as env names in the compiled context
+	jobCheckpointNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// JobCheckpoint is a small named state persisted by a run of a job, it is
+// available to the later runs of the job, enabling incremental loads
+type JobCheckpoint struct {
+	Name  string
+	Value string
+
+	UpdatedAt time.Time
+}
+
+// Validate checks if checkpoint can be saved
+func (c JobCheckpoint) Validate() error {
+	if !jobCheckpointNameRegex.MatchString(c.Name) {
+		return errors.Wrapf(ErrInvalidJobCheckpoint, "name %q should only have letters, digits and underscores", c.Name)
+	}
+	if len(c.Value) > MaxJobCheckpointSize {
+		return errors.Wrapf(ErrInvalidJobCheckpoint, "value of %s is over %d bytes", c.Name, MaxJobCheckpointSize)
+	}
+	return nil
+}
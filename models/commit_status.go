@@ -0,0 +1,41 @@
+package models
+
+import "context"
+
+const (
+	CommitStateSuccess = "success"
+	CommitStateFailure = "failure"
+)
+
+// CommitRef is the commit of a git repository specifications deployed are
+// of, SpecPath is the directory of job specifications of the namespace in
+// the repository, if known
+type CommitRef struct {
+	Repository string
+	SHA        string
+	SpecPath   string
+}
+
+// CommitAnnotation is a failure of a job deployed from a commit, Path is
+// the file of job specification in the repository
+type CommitAnnotation struct {
+	Path    string
+	Title   string
+	Message string
+}
+
+// CommitStatus is the result of deploying specifications of a commit, Name
+// tells results of namespaces deployed from the same commit apart
+type CommitStatus struct {
+	Ref         CommitRef
+	Name        string
+	State       string
+	Description string
+	Annotations []CommitAnnotation
+}
+
+// CommitStatusPublisher posts results of deployments back to the git host
+// of the repository they are of
+type CommitStatusPublisher interface {
+	Publish(ctx context.Context, projSpec ProjectSpec, status CommitStatus) error
+}
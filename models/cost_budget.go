@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CostBudgetThresholds are percentages of the monthly budget of a project
+// its spend is alerted at, in ascending order
+var CostBudgetThresholds = []int{50, 80, 100}
+
+// byteUnits are the binary units budgets can be written in
+var byteUnits = []struct {
+	suffix string
+	size   float64
+}{
+	{"PIB", 1 << 50},
+	{"TIB", 1 << 40},
+	{"GIB", 1 << 30},
+	{"MIB", 1 << 20},
+	{"KIB", 1 << 10},
+	{"B", 1},
+}
+
+// CostBudgetStatus is what runs of jobs of a project billed in a calendar
+// month(UTC) against its budget
+type CostBudgetStatus struct {
+	Month       time.Time
+	BudgetBytes int64
+	SpentBytes  int64
+}
+
+// Percent is the share of budget spent, zero without a budget
+func (s CostBudgetStatus) Percent() float64 {
+	if s.BudgetBytes <= 0 {
+		return 0
+	}
+	return float64(s.SpentBytes) * 100 / float64(s.BudgetBytes)
+}
+
+// ThresholdsReached returns the thresholds of CostBudgetThresholds spend
+// reached, in ascending order
+func (s CostBudgetStatus) ThresholdsReached() []int {
+	var reached []int
+	if s.BudgetBytes <= 0 {
+		return reached
+	}
+	for _, threshold := range CostBudgetThresholds {
+		if s.Percent() >= float64(threshold) {
+			reached = append(reached, threshold)
+		}
+	}
+	return reached
+}
+
+// MonthStart returns the start of the calendar month(UTC) of the time
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// FormatBytes prints bytes in the largest binary unit they fill
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// parseBytes parses bytes optionally suffixed with a binary unit, e.g.
+// 1024, 500GiB or 2.5TiB
+func parseBytes(value string) (int64, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	size := 1.0
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			size = unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, errors.New("expected bytes like 500GiB or 2TiB")
+	}
+	return int64(n * size), nil
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/odpf/optimus/core/progress"
 
@@ -15,6 +16,8 @@ const (
 	ResourceTypeDataset       ResourceType = "dataset"
 	ResourceTypeView          ResourceType = "view"
 	ResourceTypeExternalTable ResourceType = "external_table"
+
+	ResourceTypeRowAccessPolicy ResourceType = "row_access_policy"
 )
 
 type ResourceType string
@@ -35,6 +38,10 @@ type ResourceSpec struct {
 	Labels map[string]string
 }
 
+func (r ResourceSpec) GetName() string {
+	return r.Name
+}
+
 type ResourceAssets map[string]string
 
 func (r ResourceAssets) GetByName(n string) (string, bool) {
@@ -64,6 +71,81 @@ type Datastorer interface {
 	DeleteResource(context.Context, DeleteResourceRequest) error
 }
 
+// DatastorePlanner is implemented by datastores which can describe the changes
+// an update of resource would make without applying them
+type DatastorePlanner interface {
+	PlanResource(context.Context, UpdateResourceRequest) ([]string, error)
+}
+
+// DatastoreDependencyResolver is implemented by datastores whose resources
+// depend on other resources of the datastore, e.g. a table on its dataset
+type DatastoreDependencyResolver interface {
+	// ResourceDependencies returns names of resources the resource depends on
+	ResourceDependencies(ResourceSpec) []string
+}
+
+// DatastoreCopier is implemented by datastores which can clone a dataset,
+// the resource holding other resources of the datastore, into another
+type DatastoreCopier interface {
+	CopyDataset(context.Context, CopyDatasetRequest) (CopyDatasetResponse, error)
+}
+
+// DatastoreRestorer is implemented by datastores which can restore a
+// resource to how it was at a point in time, e.g. with time travel
+type DatastoreRestorer interface {
+	RestoreResource(context.Context, RestoreResourceRequest) error
+}
+
+// DatastoreImporter is implemented by datastores which can describe their
+// existing resources, ones not created by optimus, as resource specs
+type DatastoreImporter interface {
+	ImportDataset(context.Context, ImportDatasetRequest) (ImportDatasetResponse, error)
+}
+
+// DatastoreUsageReader is implemented by datastores which audit reads of
+// their resources, e.g. bigquery with jobs referencing a table
+type DatastoreUsageReader interface {
+	// LastReadAt returns the latest read of a job destination since a time
+	// by anything but the job writing to it, unsupported if the destination
+	// isn't a resource of the datastore
+	LastReadAt(context.Context, ReadUsageRequest) (ReadUsageResponse, error)
+}
+
+// DatastoreJobCostReader is implemented by datastores which bill the jobs
+// tasks run in them
+type DatastoreJobCostReader interface {
+	// JobCost returns what a job run in the datastore cost, unsupported if
+	// the id isn't of a job of the datastore
+	JobCost(context.Context, JobCostRequest) (JobCostResponse, error)
+}
+
+// DatastorePartitionDropper is implemented by datastores whose resources
+// jobs write to can be partitioned by time, e.g. bigquery tables
+type DatastorePartitionDropper interface {
+	// DropPartitions drops partitions of a job destination whose data is
+	// entirely older than a time, unsupported if the destination isn't a
+	// resource of the datastore
+	DropPartitions(context.Context, DropPartitionsRequest) (DropPartitionsResponse, error)
+}
+
+// DatastoreFreshnessReader is implemented by datastores which track when
+// their resources jobs write to were last modified
+type DatastoreFreshnessReader interface {
+	// LastModifiedAt reads when a job destination was last modified,
+	// unsupported if the destination isn't a resource of the datastore
+	LastModifiedAt(context.Context, ReadFreshnessRequest) (ReadFreshnessResponse, error)
+}
+
+// DatastoreURNResolver is implemented by datastores whose resources jobs
+// write to and read from, naming them with URNs of the datastore's scheme
+type DatastoreURNResolver interface {
+	// ResourceURN returns the URN of a resource of the datastore
+	ResourceURN(ResourceSpec) (URN, error)
+	// ResourceName returns name of the resource a URN refers, failing for
+	// URNs which don't name a resource of the datastore
+	ResourceName(URN) (string, error)
+}
+
 type DatastoreTypeController interface {
 	Adapter() DatastoreSpecAdapter
 	Validator() DatastoreSpecValidator
@@ -90,11 +172,15 @@ type DatastoreSpecValidator func(spec ResourceSpec) error
 type CreateResourceRequest struct {
 	Resource ResourceSpec
 	Project  ProjectSpec
+	// Namespace owning the resource, empty for requests not scoped to one
+	Namespace NamespaceSpec
 }
 
 type UpdateResourceRequest struct {
 	Resource ResourceSpec
 	Project  ProjectSpec
+	// Namespace owning the resource, empty for requests not scoped to one
+	Namespace NamespaceSpec
 }
 
 type ResourceExistsRequest struct {
@@ -105,6 +191,8 @@ type ResourceExistsRequest struct {
 type ReadResourceRequest struct {
 	Resource ResourceSpec
 	Project  ProjectSpec
+	// Namespace owning the resource, empty for requests not scoped to one
+	Namespace NamespaceSpec
 }
 
 type ReadResourceResponse struct {
@@ -114,6 +202,122 @@ type ReadResourceResponse struct {
 type DeleteResourceRequest struct {
 	Resource ResourceSpec
 	Project  ProjectSpec
+	// Namespace owning the resource, empty for requests not scoped to one
+	Namespace NamespaceSpec
+}
+
+type CopyDatasetRequest struct {
+	// Source and Destination are fully qualified names of datasets, the
+	// destination is created if it doesn't exist
+	Source      string
+	Destination string
+
+	// WithData copies data of tables along with their schema
+	WithData bool
+
+	// Tables limits the copy to these tables of the source, all if empty
+	Tables []string
+
+	Project ProjectSpec
+}
+
+type RestoreResourceRequest struct {
+	// Name is the fully qualified name of resource being restored
+	Name string
+	// Destination is the fully qualified name of resource the restored
+	// resource is written to, resource is overwritten in place if empty
+	Destination string
+	PointInTime time.Time
+
+	Project ProjectSpec
+}
+
+type ImportDatasetRequest struct {
+	// Dataset is the fully qualified name of dataset being imported
+	Dataset string
+
+	Project ProjectSpec
+}
+
+type ImportDatasetResponse struct {
+	// Resources are specs of the dataset and resources it holds, Skipped
+	// the resources which can't be described as specs
+	Resources []ResourceSpec
+	Skipped   []string
+}
+
+type ReadUsageRequest struct {
+	// Destination is the destination of a job, e.g. project:dataset.table
+	Destination string
+	Since       time.Time
+
+	Project   ProjectSpec
+	Namespace NamespaceSpec
+}
+
+type ReadUsageResponse struct {
+	// Supported is false if destination isn't a resource of the datastore
+	Supported bool
+	// LastReadAt is zero if destination wasn't read since the time
+	LastReadAt time.Time
+}
+
+type JobCostRequest struct {
+	// JobID identifies the job in datastore, e.g. project:location.job_id
+	JobID string
+	// Since is a time before the job was created, bounding the lookup
+	Since time.Time
+
+	Project   ProjectSpec
+	Namespace NamespaceSpec
+}
+
+type JobCostResponse struct {
+	// Supported is false if id isn't of a job of the datastore
+	Supported   bool
+	BytesBilled int64
+	SlotMillis  int64
+}
+
+type DropPartitionsRequest struct {
+	// Destination is the destination of a job, e.g. bigquery://project:dataset.table
+	Destination string
+	// Before is the time partitions ending before or at it are dropped
+	Before time.Time
+
+	Project   ProjectSpec
+	Namespace NamespaceSpec
+}
+
+type DropPartitionsResponse struct {
+	// Supported is false if destination isn't a resource of the datastore
+	Supported bool
+	// Dropped are ids of the partitions dropped, e.g. 20210601
+	Dropped []string
+	// Skipped tells why partitions of a supported destination aren't
+	// dropped, e.g. it's partitioned by month, empty if they are
+	Skipped string
+}
+
+type ReadFreshnessRequest struct {
+	// Destination is the destination of a job, e.g. bigquery://project:dataset.table
+	Destination string
+
+	Project   ProjectSpec
+	Namespace NamespaceSpec
+}
+
+type ReadFreshnessResponse struct {
+	// Supported is false if destination isn't a resource of the datastore
+	Supported      bool
+	LastModifiedAt time.Time
+}
+
+type CopyDatasetResponse struct {
+	// Copied are the tables, views included, cloned into destination and
+	// Skipped the ones which already existed there or can't be cloned
+	Copied  []string
+	Skipped []string
 }
 
 var (
@@ -168,6 +372,12 @@ type DatastoreService interface {
 
 	CreateResource(ctx context.Context, namespace NamespaceSpec, resourceSpecs []ResourceSpec, obs progress.Observer) error
 	UpdateResource(ctx context.Context, namespace NamespaceSpec, resourceSpecs []ResourceSpec, obs progress.Observer) error
+	// PlanResource notifies the changes updating resources would make without
+	// saving or applying them
+	PlanResource(ctx context.Context, namespace NamespaceSpec, resourceSpecs []ResourceSpec, obs progress.Observer) error
 	ReadResource(ctx context.Context, namespace NamespaceSpec, datastoreName, name string) (ResourceSpec, error)
 	DeleteResource(ctx context.Context, namespace NamespaceSpec, datastoreName, name string) error
+	// Promote clones datasets of manifest one after another, datasets after
+	// the first one failing are left out of results
+	Promote(ctx context.Context, project ProjectSpec, manifest PromotionManifest, obs progress.Observer) ([]PromotionResult, error)
 }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeploymentStatusPending    = "pending"
+	DeploymentStatusInProgress = "in progress"
+	DeploymentStatusSucceeded  = "succeeded"
+	DeploymentStatusFailed     = "failed"
+)
+
+// Deployment tracks a deploy of project specifications requested over
+// http, which runs in background after the request is accepted
+type Deployment struct {
+	ID uuid.UUID
+	// Source describes where specifications are deployed from,
+	// e.g. tarball or a git ref
+	Source string
+	// Actor is the user who requested the deployment
+	Actor   string
+	Status  string
+	Message string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsDone checks if deployment is over, successfully or not
+func (d Deployment) IsDone() bool {
+	return d.Status == DeploymentStatusSucceeded || d.Status == DeploymentStatusFailed
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// DestinationProducer is a job writing to a destination, e.g. a bigquery
+// table. Destinations of jobs of every project are registered together so
+// consumers in any project can find who produces what they read
+type DestinationProducer struct {
+	Destination string
+	Project     string
+	Namespace   string
+	Job         string
+	Owner       string
+	Task        string
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// ProjectFreeze blocks deploys and replays of a project, e.g. during
+// an incident, reads are still allowed
+type ProjectFreeze struct {
+	Reason string
+	// Actor is the user who froze the project
+	Actor string
+	// ExpiresAt is when the freeze is lifted automatically,
+	// zero means it stays until lifted explicitly
+	ExpiresAt time.Time
+
+	CreatedAt time.Time
+}
+
+// IsActive checks if freeze is in effect at given time
+func (f ProjectFreeze) IsActive(now time.Time) bool {
+	return f.ExpiresAt.IsZero() || now.Before(f.ExpiresAt)
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,6 +30,12 @@ const (
 	InstanceStateFailed  = "failed"
 	InstanceStateSuccess = "success"
 
+	// InstanceHeartbeatInterval is how often runs wrapped by optimus exec
+	// report they are alive, a running run which missed
+	// InstanceHeartbeatMissLimit heartbeats in a row is considered hung
+	InstanceHeartbeatInterval  = time.Minute
+	InstanceHeartbeatMissLimit = 3
+
 	// InstanceType is the kind of execution happening at the time
 	InstanceTypeTask InstanceType = "task"
 	InstanceTypeHook InstanceType = "hook"
@@ -59,6 +67,126 @@ type InstanceSpec struct {
 	ScheduledAt time.Time
 	State       string
 	Data        []InstanceSpecData
+
+	// UpdatedAt is the last time task or a hook of the instance was registered
+	UpdatedAt time.Time
+
+	// Cost is what datastore jobs the run reported cost, nil if unknown
+	Cost *InstanceCost
+
+	// HeartbeatAt is the last time the run reported it is alive, zero for
+	// runs not wrapped by optimus exec
+	HeartbeatAt time.Time
+}
+
+// IsHung tells if the run is still running but reported no heartbeat for
+// longer than silence, runs which never reported one can't be told apart
+// from slow ones
+func (j InstanceSpec) IsHung(now time.Time, silence time.Duration) bool {
+	if j.State != InstanceStateRunning || j.HeartbeatAt.IsZero() {
+		return false
+	}
+	return now.Sub(j.HeartbeatAt) > silence
+}
+
+// InstanceCost is what datastore jobs run by the task of a run cost, jobs
+// are identified the way their datastore does, e.g. project:location.job_id
+// in bigquery
+type InstanceCost struct {
+	DatastoreJobs []string `json:"datastore_jobs"`
+	BytesBilled   int64    `json:"bytes_billed"`
+	SlotMillis    int64    `json:"slot_millis"`
+}
+
+// RunDuration is how long a run of a job took
+type RunDuration struct {
+	ScheduledAt time.Time
+	Duration    time.Duration
+}
+
+// RunDurationPercentile returns nearest rank percentile of duration of runs
+func RunDurationPercentile(runs []RunDuration, percentile float64) time.Duration {
+	if len(runs) == 0 {
+		return 0
+	}
+	durations := make([]time.Duration, 0, len(runs))
+	for _, run := range runs {
+		durations = append(durations, run.Duration)
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	rank := int(math.Ceil(percentile*float64(len(durations)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return durations[rank]
+}
+
+// JobRunStats summarizes runs of a job scheduled since a time, runs still
+// running or without a known outcome count only towards Runs
+type JobRunStats struct {
+	Since     time.Time
+	Runs      int
+	Succeeded int
+	Failed    int
+	// Hung counts runs still running which missed their heartbeats
+	Hung int
+
+	// durations of successful runs which could be measured
+	AverageDuration time.Duration
+	P50Duration     time.Duration
+	P95Duration     time.Duration
+
+	// LongestFailureStreak is the most consecutive failed runs and
+	// CurrentFailureStreak the failed runs since the last successful one
+	LongestFailureStreak int
+	CurrentFailureStreak int
+
+	// FailuresByHour counts failed runs by hour of day(UTC) they were
+	// scheduled at
+	FailuresByHour map[int]int
+}
+
+// SuccessRate is the fraction of runs with an outcome which succeeded
+func (s JobRunStats) SuccessRate() float64 {
+	if s.Succeeded+s.Failed == 0 {
+		return 0
+	}
+	return float64(s.Succeeded) / float64(s.Succeeded+s.Failed)
+}
+
+// MostFailingHour is the hour of day(UTC) most runs failed at, earliest
+// among equals, and false if no run failed
+func (s JobRunStats) MostFailingHour() (int, bool) {
+	hour, failures := 0, 0
+	for h := 0; h < 24; h++ {
+		if s.FailuresByHour[h] > failures {
+			hour, failures = h, s.FailuresByHour[h]
+		}
+	}
+	return hour, failures > 0
+}
+
+// JobCostStats sums cost of runs of a job scheduled since a time, runs
+// without a known cost are left out
+type JobCostStats struct {
+	Since       time.Time
+	Runs        int
+	BytesBilled int64
+	SlotMillis  int64
+}
+
+// DurationAnomaly is a run of a job which took much longer than the
+// Baseline of its recent runs
+type DurationAnomaly struct {
+	ProjectName   string
+	NamespaceName string
+	JobName       string
+	ScheduledAt   time.Time
+	Duration      time.Duration
+	Baseline      time.Duration
+	DetectedAt    time.Time
 }
 
 type InstanceSpecData struct {
@@ -78,8 +206,40 @@ type InstanceService interface {
 	Register(jobSpec JobSpec, scheduledAt time.Time, taskType InstanceType) (InstanceSpec, error)
 	Compile(namespaceSpec NamespaceSpec, jobSpec JobSpec, instanceSpec InstanceSpec,
 		runType InstanceType, runName string) (envMap map[string]string, fileMap map[string]string, err error)
+	// GetAverageDuration returns how long latest runs of the job took on average,
+	// zero if no run duration is known
+	GetAverageDuration(jobSpec JobSpec) (time.Duration, error)
+	// GetRunDurations returns how long latest measurable runs among limit
+	// latest runs of the job took, latest scheduled runs first
+	GetRunDurations(jobSpec JobSpec, limit int) ([]RunDuration, error)
+	// UpdateState records the outcome of a run, InstanceStateSuccess or
+	// InstanceStateFailed
+	UpdateState(jobSpec JobSpec, scheduledAt time.Time, state string) error
+	// Heartbeat records that the run of the job is alive
+	Heartbeat(jobSpec JobSpec, scheduledAt time.Time) error
+	// GetHungRuns returns runs of the job scheduled since the time which are
+	// still running but reported no heartbeat for longer than silence
+	GetHungRuns(jobSpec JobSpec, since time.Time, silence time.Duration) ([]InstanceSpec, error)
+	// GetRunStats summarizes outcomes and durations of runs of the job
+	// scheduled since the time
+	GetRunStats(jobSpec JobSpec, since time.Time) (JobRunStats, error)
+	// GetLastSuccess returns when the latest successful run of the job was
+	// scheduled, false if no run of it succeeded
+	GetLastSuccess(jobSpec JobSpec) (time.Time, bool, error)
+	// RecordCost records what datastore jobs run by a run cost
+	RecordCost(jobSpec JobSpec, scheduledAt time.Time, cost InstanceCost) error
+	// GetCostStats sums cost of runs of the job scheduled since the time
+	GetCostStats(jobSpec JobSpec, since time.Time) (JobCostStats, error)
 }
 
+const (
+	// TemplateEngineGo compiles assets as go templates, e.g. {{ .DSTART }}
+	TemplateEngineGo = "go"
+	// TemplateEngineJinja compiles assets as jinja templates, e.g. {{ DSTART }},
+	// for projects migrating from airflow or dbt
+	TemplateEngineJinja = "jinja"
+)
+
 // TemplateEngine compiles raw text templates using provided values
 type TemplateEngine interface {
 	CompileFiles(files map[string]string, context map[string]interface{}) (map[string]string, error)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"time"
 
@@ -41,6 +42,32 @@ const (
 
 	JobEventTypeSLAMiss JobEventType = "sla_miss"
 	JobEventTypeFailure JobEventType = "failure"
+	JobEventTypeSuccess JobEventType = "success"
+
+	// JobEventTypeAutoHeal is raised by optimus when failed runs of
+	// a job are replayed automatically
+	JobEventTypeAutoHeal JobEventType = "auto_heal"
+
+	// JobEventTypeDurationAnomaly is raised by optimus when a run of a job
+	// took much longer than its recent runs
+	JobEventTypeDurationAnomaly JobEventType = "duration_anomaly"
+
+	// JobEventTypeHungRun is raised by optimus when a run of a job stopped
+	// reporting heartbeats and is marked failed
+	JobEventTypeHungRun JobEventType = "hung_run"
+
+	// JobEventTypeReplay is raised by optimus when a replay of a job
+	// changes its status, it is notified to the replay channels of project
+	JobEventTypeReplay JobEventType = "replay"
+
+	// JobEventTypeCostBudget is raised by optimus when spend of a project in
+	// a month crosses a threshold of its budget, it is notified to the cost
+	// budget channels of project
+	JobEventTypeCostBudget JobEventType = "cost_budget"
+
+	// JobEventTypeSensorTimeout is raised when a sensor of a job timed out
+	// waiting on a dependency, it is notified like a sla miss
+	JobEventTypeSensorTimeout JobEventType = "sensor_timeout"
 )
 
 // JobSpec represents a job
@@ -52,12 +79,21 @@ type JobSpec struct {
 	Description  string
 	Labels       map[string]string
 	Owner        string
+	Ownership    JobSpecOwnership
 	Schedule     JobSpecSchedule
 	Behavior     JobSpecBehavior
 	Task         JobSpecTask
 	Dependencies map[string]JobSpecDependency // job name to dependency
 	Assets       JobAssets
 	Hooks        []JobSpecHook
+
+	// Lifecycle is how data job wrote to its destination is managed as
+	// it ages
+	Lifecycle JobSpecLifecycle
+
+	// Tests are cases the transformation of job is tested with locally,
+	// they are not deployed
+	Tests []JobSpecTest
 }
 
 func (js JobSpec) GetName() string {
@@ -81,10 +117,122 @@ func (js JobSpec) GetLabelsAsString() string {
 	return strings.TrimRight(labels, ",")
 }
 
+// GetLabelsAsTags returns labels as sorted key:value pairs, the way they
+// are tagged on compiled dags
+func (js JobSpec) GetLabelsAsTags() []string {
+	var tags []string
+	for k, v := range js.Labels {
+		tags = append(tags, fmt.Sprintf("%s:%s", strings.TrimSpace(k), strings.TrimSpace(v)))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
 type JobSpecSchedule struct {
 	StartDate time.Time
 	EndDate   *time.Time
 	Interval  string
+
+	// Exceptions are the days job deviates from its interval on
+	Exceptions JobSpecScheduleExceptions
+
+	// MaxActiveRuns caps runs of the job scheduler runs at once, including
+	// the ones cleared by replay, zero leaves it to the scheduler default
+	MaxActiveRuns int
+}
+
+// AssetTemplateEngine returns name of the engine assets of the job are
+// compiled with, the one set for the job or else for its project
+func (js JobSpec) AssetTemplateEngine(proj ProjectSpec) string {
+	if js.Task.TemplateEngine != "" {
+		return js.Task.TemplateEngine
+	}
+	return proj.TemplateEngine()
+}
+
+// IsSequential returns true if runs of the job have to be processed one after
+// another, e.g. stateful jobs building on what their previous run wrote
+func (js JobSpec) IsSequential() bool {
+	return js.Behavior.DependsOnPast || js.Schedule.MaxActiveRuns == 1
+}
+
+// JobSpecScheduleExceptions are exceptions to the interval of a job
+type JobSpecScheduleExceptions struct {
+	// SkipDates are days(UTC) runs of the interval are skipped on
+	SkipDates []time.Time
+
+	// ExtraRuns are times job runs at in addition to its interval
+	ExtraRuns []time.Time
+
+	// Calendars are names of holiday calendars of the project, runs of the
+	// interval are skipped on their days as well
+	Calendars []string
+}
+
+// IsEmpty returns true when job runs at its interval only
+func (e JobSpecScheduleExceptions) IsEmpty() bool {
+	return len(e.SkipDates) == 0 && len(e.ExtraRuns) == 0 && len(e.Calendars) == 0
+}
+
+// SkipDays returns the sorted days(UTC), formatted as JobDatetimeLayout,
+// runs of the interval are skipped on, including days of calendars of project
+func (e JobSpecScheduleExceptions) SkipDays(projSpec ProjectSpec) ([]string, error) {
+	days := map[string]bool{}
+	for _, date := range e.SkipDates {
+		days[date.UTC().Format(JobDatetimeLayout)] = true
+	}
+	for _, name := range e.Calendars {
+		calendar, err := projSpec.GetCalendar(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, date := range calendar {
+			days[date.Format(JobDatetimeLayout)] = true
+		}
+	}
+	sorted := make([]string, 0, len(days))
+	for day := range days {
+		sorted = append(sorted, day)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
+}
+
+// JobSpecOwnership is used to reach out to the people responsible for a job
+type JobSpecOwnership struct {
+	Email        string
+	Team         string
+	SlackChannel string
+}
+
+func (o JobSpecOwnership) IsEmpty() bool {
+	return o == JobSpecOwnership{}
+}
+
+// JobSpecLifecycle manages data job wrote to its destination as it ages
+type JobSpecLifecycle struct {
+	Retention JobSpecRetention
+}
+
+// RetentionDisabled as PartitionDays keeps partitions of destination
+// forever even if parent specs set a retention, zero inherits it
+const RetentionDisabled = -1
+
+// JobSpecRetention drops partitions of destination older than
+// PartitionDays days, partitions are kept forever if it is zero or
+// RetentionDisabled. It is enforced by the server for destinations of
+// datastores implementing DatastorePartitionDropper
+type JobSpecRetention struct {
+	PartitionDays int
+}
+
+func (r JobSpecRetention) IsEnabled() bool {
+	return r.PartitionDays > 0
+}
+
+// PartitionRetention returns how long partitions of destination are kept
+func (r JobSpecRetention) PartitionRetention() time.Duration {
+	return time.Hour * 24 * time.Duration(r.PartitionDays)
 }
 
 type JobSpecBehavior struct {
@@ -92,6 +240,10 @@ type JobSpecBehavior struct {
 	CatchUp       bool
 	Retry         JobSpecBehaviorRetry
 	Notify        []JobSpecNotifier
+	AutoHeal      JobSpecBehaviorAutoHeal
+
+	// ReplayPresets are named replays of the job, by name
+	ReplayPresets map[string]JobSpecReplayPreset
 }
 
 type JobSpecBehaviorRetry struct {
@@ -100,6 +252,38 @@ type JobSpecBehaviorRetry struct {
 	ExponentialBackoff bool
 }
 
+// JobSpecBehaviorAutoHeal replays failed runs of the job found in the trailing
+// Window, at most MaxPerDay times a day(UTC). Zero MaxPerDay disables it
+type JobSpecBehaviorAutoHeal struct {
+	Window    time.Duration
+	MaxPerDay int
+}
+
+func (a JobSpecBehaviorAutoHeal) IsEnabled() bool {
+	return a.MaxPerDay > 0
+}
+
+// JobSpecReplayPreset is a replay of the job kept in its spec, common recovery
+// procedures are invoked by name instead of repeating their window and scope
+type JobSpecReplayPreset struct {
+	// Start and End bound replayed runs like the dates of a replay request,
+	// Start can be expressed in runs instead, e.g. last:7, with no End. Empty
+	// Start is the start of the job and empty End its latest run
+	Start string
+	End   string
+
+	// Selector limits replayed dependents to the ones matching it, all are
+	// replayed if empty
+	Selector string
+
+	// Force replays even if runs of the jobs are in progress
+	Force bool
+
+	// ChunkDays splits the window into replays of at most this many days,
+	// replayed one after another. Zero replays the window at once
+	ChunkDays int
+}
+
 type JobSpecNotifier struct {
 	On       JobEventType
 	Config   map[string]string
@@ -111,6 +295,14 @@ type JobSpecTask struct {
 	Config   JobSpecConfigs
 	Window   JobSpecTaskWindow
 	Priority int
+
+	// Version pins the plugin version job is expected to run with,
+	// empty means whichever version is installed on the server
+	Version string
+
+	// TemplateEngine is the engine assets of the job are compiled with,
+	// empty means the one set for the project
+	TemplateEngine string
 }
 
 // using array to keep order, map would be more performant
@@ -131,8 +323,10 @@ type JobSpecConfigItem struct {
 }
 
 type JobSpecTaskWindow struct {
-	Size       time.Duration
-	Offset     time.Duration
+	Size   time.Duration
+	Offset time.Duration
+	// TruncateTo is the unit end of window is truncated to, m(minute),
+	// h(hour), d(day), w(week) or M(month), empty leaves it as is
 	TruncateTo string
 }
 
@@ -147,10 +341,15 @@ func (w *JobSpecTaskWindow) GetEnd(scheduledAt time.Time) time.Time {
 }
 
 func (w *JobSpecTaskWindow) getWindowDate(today time.Time, windowSize, windowOffset time.Duration, windowTruncateTo string) (time.Time, time.Time) {
+	// windows are in UTC, the same as schedules
+	today = today.UTC()
 	floatingEnd := today
 
 	// apply truncation to end
-	if windowTruncateTo == "h" {
+	if windowTruncateTo == "m" {
+		// remove seconds
+		floatingEnd = floatingEnd.Truncate(time.Minute)
+	} else if windowTruncateTo == "h" {
 		// remove time upto hours
 		floatingEnd = floatingEnd.Truncate(time.Hour)
 	} else if windowTruncateTo == "d" {
@@ -201,6 +400,32 @@ func (w *JobSpecTaskWindow) getWindowDate(today time.Time, windowSize, windowOff
 	return windowStart, windowEnd
 }
 
+// JobSpecTest is a test case of the query of a job, the query is run with
+// tables it reads replaced by fixtures and its output compared to the rows
+// expected
+type JobSpecTest struct {
+	Name string
+	// ScheduledAt is the run assets are compiled for, in RFC3339 or a date
+	// for the first run of that day, the first run of job if empty
+	ScheduledAt string
+	Inputs      []JobSpecTestInput
+	Expected    JobSpecTestExpectation
+}
+
+// JobSpecTestInput is a fixture standing in for a table read by the query
+type JobSpecTestInput struct {
+	Table string
+	Rows  []map[string]interface{}
+}
+
+type JobSpecTestExpectation struct {
+	// Rows are compared by the columns they declare, other columns of output
+	// are ignored
+	Rows []map[string]interface{}
+	// Ordered compares rows in order, for queries with an order by
+	Ordered bool
+}
+
 type JobSpecHook struct {
 	Config    JobSpecConfigs
 	Unit      *Plugin
@@ -287,10 +512,116 @@ func (j JobSpecDependencyType) String() string {
 	return string(j)
 }
 
+// JobDestination is the destination a job of a project writes to
+type JobDestination struct {
+	JobName       string
+	NamespaceName string
+	Destination   string
+}
+
 type JobSpecDependency struct {
 	Project *ProjectSpec
 	Job     *JobSpec
 	Type    JobSpecDependencyType
+	Sensor  JobSpecDependencySensor
+	// Freshness of the destination of dependency asserted before the task
+	// of a run starts
+	Freshness JobSpecDependencyFreshness
+}
+
+// JobSpecDependencySensor configures the sensor a job waits on a dependency
+// with, zero values fall back to the defaults of scheduler
+type JobSpecDependencySensor struct {
+	// Timeout after which the sensor fails and a sensor timeout event is
+	// raised for the job
+	Timeout time.Duration
+	// PokeInterval between checks of the run of dependency
+	PokeInterval time.Duration
+}
+
+// JobSpecDependencyFreshness asserts the destination of a dependency was
+// modified after the window of a run started, runs fail before their task
+// starts on stale dependencies instead of producing wrong results
+type JobSpecDependencyFreshness struct {
+	Enabled bool
+	// Tolerance by which the destination may be modified before the window
+	// of a run started and still be fresh
+	Tolerance time.Duration
+}
+
+// IsFresh tells if a destination last modified at a time is fresh for a
+// run whose window starts at windowStart
+func (f JobSpecDependencyFreshness) IsFresh(lastModifiedAt, windowStart time.Time) bool {
+	return lastModifiedAt.After(windowStart.Add(-f.Tolerance))
+}
+
+// DependencyFreshness is the outcome of asserting freshness of destination
+// of a dependency for a run of a job
+type DependencyFreshness struct {
+	Destination string
+	WindowStart time.Time
+
+	// Supported is false if the dependency has no destination or no
+	// datastore tracks modification of it, freshness of such dependencies
+	// isn't asserted
+	Supported      bool
+	LastModifiedAt time.Time
+	Fresh          bool
+}
+
+// JobSpecDependencyReason tells why a job depends on another
+type JobSpecDependencyReason string
+
+const (
+	// JobSpecDependencyReasonDeclared dependencies are listed in job spec
+	JobSpecDependencyReasonDeclared JobSpecDependencyReason = "declared"
+	// JobSpecDependencyReasonInferred dependencies write a destination the
+	// task of the job reads
+	JobSpecDependencyReasonInferred JobSpecDependencyReason = "inferred"
+	// JobSpecDependencyReasonUnresolved are sources the task reads which no
+	// job writes, or declared dependencies which don't exist, the job
+	// doesn't wait for them
+	JobSpecDependencyReasonUnresolved JobSpecDependencyReason = "unresolved"
+)
+
+// JobSpecDependencyExplanation is a dependency of a job along with why it's
+// resolved
+type JobSpecDependencyExplanation struct {
+	// Job and Project depended on, Job is empty for unresolved sources
+	Job     string
+	Project string
+	Type    JobSpecDependencyType
+	Reason  JobSpecDependencyReason
+	// Destination read by the job, empty for declared dependencies
+	Destination string
+	// Assets of the job mentioning Destination
+	Assets []string
+}
+
+func (e JobSpecDependencyExplanation) String() string {
+	switch e.Reason {
+	case JobSpecDependencyReasonDeclared:
+		return "declared in job spec"
+	case JobSpecDependencyReasonInferred:
+		explanation := fmt.Sprintf("inferred from destination %s", e.Destination)
+		if len(e.Assets) > 0 {
+			explanation += fmt.Sprintf(" read by %s", strings.Join(e.Assets, ", "))
+		}
+		if e.Type == JobSpecDependencyTypeInter {
+			explanation += fmt.Sprintf(", written by job of project %s", e.Project)
+		}
+		return explanation
+	case JobSpecDependencyReasonUnresolved:
+		if e.Destination == "" {
+			return fmt.Sprintf("declared in job spec but job %s is not found in project %s", e.Job, e.Project)
+		}
+		explanation := fmt.Sprintf("source %s", e.Destination)
+		if len(e.Assets) > 0 {
+			explanation += fmt.Sprintf(" read by %s", strings.Join(e.Assets, ", "))
+		}
+		return explanation + " is not written by any job, no sensor waits for it"
+	}
+	return string(e.Reason)
 }
 
 // JobService provides a high-level operations on DAGs
@@ -307,16 +638,42 @@ type JobService interface {
 	GetAll(NamespaceSpec) ([]JobSpec, error)
 	// Delete deletes a job spec from all repos
 	Delete(context.Context, NamespaceSpec, JobSpec) error
+	// Rename renames a job of a namespace, rewriting static dependencies of
+	// jobs of the project on it, and returns names of those jobs
+	Rename(ctx context.Context, namespace NamespaceSpec, oldName, newName string, obs progress.Observer) ([]string, error)
+	// DeployCanary deploys canary of jobs, leaving the jobs untouched
+	DeployCanary(context.Context, NamespaceSpec, []JobSpec, progress.Observer) error
+	// DeleteCanary deletes canary of jobs deployed with DeployCanary
+	DeleteCanary(context.Context, NamespaceSpec, []JobSpec) error
+	// CheckDestinations fails if jobs to be deployed in a namespace write to
+	// the destination of another job of the project
+	CheckDestinations(NamespaceSpec, []JobSpec) error
+	// ExplainDependencies lists dependencies of a job along with why each of
+	// them is resolved
+	ExplainDependencies(NamespaceSpec, JobSpec) ([]JobSpecDependencyExplanation, error)
+	// MigrateTemplate recompiles jobs of namespaces of a project against the
+	// scheduler template into a staging scheduler, validates them there and
+	// with swap replaces the deployed jobs if all of them are valid
+	MigrateTemplate(context.Context, ProjectSpec, []NamespaceSpec, bool, progress.Observer) (TemplateMigration, error)
 
 	// following methods are executed at a project level, instead of a client
 	// GetByNameForProject fetches a Job by name for a specific project
 	GetByNameForProject(string, ProjectSpec) (JobSpec, NamespaceSpec, error)
 	Sync(context.Context, NamespaceSpec, progress.Observer) error
+	// Unschedule deletes compiled jobs of a namespace from scheduler, leaving
+	// their specs as they are so the jobs can be synced again
+	Unschedule(context.Context, NamespaceSpec, progress.Observer) error
 	Check(NamespaceSpec, []JobSpec, progress.Observer) error
 	// ReplayDryRun returns the execution tree of jobSpec and its dependencies between start and endDate
 	ReplayDryRun(*ReplayWorkerRequest) (*tree.TreeNode, error)
 	// Replay replays the jobSpec and its dependencies between start and endDate
 	Replay(context.Context, *ReplayWorkerRequest) (string, error)
+	// ReplayPlanDryRun returns execution trees of jobs matching the selector in
+	// the order they are replayed by a replay plan
+	ReplayPlanDryRun(ReplayPlanRequest) ([]*tree.TreeNode, error)
+	// ReplayPlan replays jobs matching the selector in order of their
+	// dependencies under a single replay plan
+	ReplayPlan(context.Context, ReplayPlanRequest) (*ReplayPlan, error)
 }
 
 // JobCompiler takes template file of a scheduler and after applying
@@ -333,6 +690,47 @@ type Job struct {
 	Contents    []byte
 }
 
+const (
+	// JobArtifactFormatSource uploads jobs as source files of the scheduler
+	JobArtifactFormatSource = "source"
+	// JobArtifactFormatZip bundles the source file of each job in a zip,
+	// like packaged dags of airflow
+	JobArtifactFormatZip = "zip"
+	// JobArtifactFormatJSON wraps the source of each job in a json document,
+	// for schedulers loading jobs from a database instead of files
+	JobArtifactFormatJSON = "json"
+
+	JobArtifactCompressionNone = "none"
+	JobArtifactCompressionGzip = "gzip"
+)
+
+// JobArtifact is the format and compression compiled jobs are uploaded in
+type JobArtifact struct {
+	Format      string
+	Compression string
+}
+
+// Validate fails for unknown formats and compressions. Source files are
+// read by scheduler as is, they can't be compressed
+func (a JobArtifact) Validate() error {
+	switch a.Format {
+	case JobArtifactFormatSource, JobArtifactFormatZip, JobArtifactFormatJSON:
+	default:
+		return fmt.Errorf("unknown format %s, expected %s, %s or %s", a.Format,
+			JobArtifactFormatSource, JobArtifactFormatZip, JobArtifactFormatJSON)
+	}
+	switch a.Compression {
+	case JobArtifactCompressionNone, JobArtifactCompressionGzip:
+	default:
+		return fmt.Errorf("unknown compression %s, expected %s or %s", a.Compression,
+			JobArtifactCompressionNone, JobArtifactCompressionGzip)
+	}
+	if a.Format == JobArtifactFormatSource && a.Compression != JobArtifactCompressionNone {
+		return fmt.Errorf("%s format can't be compressed", JobArtifactFormatSource)
+	}
+	return nil
+}
+
 type JobEventType string
 
 // JobEvent refers to status updates related to job
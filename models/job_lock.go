@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobLock is a lock held on jobs of a project by an operation, locks of
+// the same operation are shared. Token identifies a single acquisition and
+// HolderID the operation holding it if it has one, e.g. replay id. Locks
+// not extended before ExpiresAt are released, so ones of a server gone
+// away don't block jobs forever
+type JobLock struct {
+	Token     uuid.UUID
+	Project   string
+	Job       string
+	Operation string
+	HolderID  string
+
+	ExpiresAt time.Time
+}
@@ -51,6 +51,22 @@ func TestJob(t *testing.T) {
 					ExpectedStart:      time.Date(2020, 7, 9, 6, 0, 0, 0, time.UTC),
 					ExpectedEnd:        time.Date(2020, 7, 10, 6, 0, 0, 0, time.UTC),
 				},
+				{
+					Today:              time.Date(2021, 5, 20, 10, 15, 42, 0, time.UTC),
+					WindowSize:         15 * time.Minute,
+					WindowOffset:       0,
+					WindowTruncateUpto: "m",
+					ExpectedStart:      time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC),
+					ExpectedEnd:        time.Date(2021, 5, 20, 10, 15, 0, 0, time.UTC),
+				},
+				{
+					Today:              time.Date(2020, 7, 10, 12, 3, 22, 0, time.FixedZone("IST", 5*60*60+30*60)),
+					WindowSize:         24 * time.Hour,
+					WindowOffset:       0,
+					WindowTruncateUpto: "h",
+					ExpectedStart:      time.Date(2020, 7, 9, 6, 0, 0, 0, time.UTC),
+					ExpectedEnd:        time.Date(2020, 7, 10, 6, 0, 0, 0, time.UTC),
+				},
 				{
 					Today:              time.Date(2020, 7, 10, 6, 33, 22, 0, time.UTC),
 					WindowSize:         24 * time.Hour,
@@ -146,4 +162,31 @@ func TestJob(t *testing.T) {
 			}
 		})
 	})
+	t.Run("JobSpecScheduleExceptions", func(t *testing.T) {
+		projSpec := models.ProjectSpec{
+			Name: "proj",
+			Config: map[string]string{
+				models.ProjectCalendarKeyPrefix + "HOLIDAYS": "2021-12-25, 2022-01-01",
+			},
+		}
+		t.Run("should merge skip dates with days of calendars", func(t *testing.T) {
+			exceptions := models.JobSpecScheduleExceptions{
+				SkipDates: []time.Time{
+					time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC),
+					time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC),
+				},
+				Calendars: []string{"holidays"},
+			}
+			days, err := exceptions.SkipDays(projSpec)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"2021-12-25", "2021-12-31", "2022-01-01"}, days)
+		})
+		t.Run("should fail for calendars not configured in project", func(t *testing.T) {
+			exceptions := models.JobSpecScheduleExceptions{
+				Calendars: []string{"unknown"},
+			}
+			_, err := exceptions.SkipDays(projSpec)
+			assert.NotNil(t, err)
+		})
+	})
 }
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type LabelRequirementOperator string
+
+const (
+	LabelOperatorEquals    LabelRequirementOperator = "="
+	LabelOperatorNotEquals LabelRequirementOperator = "!="
+	LabelOperatorExists    LabelRequirementOperator = "exists"
+)
+
+// LabelRequirement is a condition on a single label of a job
+type LabelRequirement struct {
+	Key      string
+	Operator LabelRequirementOperator
+	Value    string
+}
+
+func (r LabelRequirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case LabelOperatorEquals:
+		return ok && value == r.Value
+	case LabelOperatorNotEquals:
+		// jobs without the label don't have it set to value either
+		return !ok || value != r.Value
+	case LabelOperatorExists:
+		return ok
+	}
+	return false
+}
+
+func (r LabelRequirement) String() string {
+	if r.Operator == LabelOperatorExists {
+		return r.Key
+	}
+	return r.Key + string(r.Operator) + r.Value
+}
+
+// LabelSelector selects jobs by their labels, jobs are selected when all of
+// the requirements match. An empty selector selects every job
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses comma separated requirements in the form of
+// key=value, key!=value or key, e.g. tier=critical,team!=growth,sla
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var requirements LabelSelector
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		requirement := LabelRequirement{Operator: LabelOperatorExists, Key: part}
+		if idx := strings.Index(part, string(LabelOperatorNotEquals)); idx >= 0 {
+			requirement = LabelRequirement{Key: part[:idx], Operator: LabelOperatorNotEquals, Value: part[idx+2:]}
+		} else if idx := strings.Index(part, string(LabelOperatorEquals)); idx >= 0 {
+			requirement = LabelRequirement{Key: part[:idx], Operator: LabelOperatorEquals, Value: part[idx+1:]}
+		}
+		requirement.Key = strings.TrimSpace(requirement.Key)
+		requirement.Value = strings.TrimSpace(requirement.Value)
+		if requirement.Key == "" {
+			return nil, fmt.Errorf("invalid label selector %s: label key is required in %s", selector, part)
+		}
+		if strings.ContainsAny(requirement.Value, "=!") {
+			return nil, fmt.Errorf("invalid label selector %s: invalid value in %s", selector, part)
+		}
+		requirements = append(requirements, requirement)
+	}
+	sort.SliceStable(requirements, func(i, j int) bool {
+		return requirements[i].Key < requirements[j].Key
+	})
+	return requirements, nil
+}
+
+func (s LabelSelector) Empty() bool {
+	return len(s) == 0
+}
+
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, requirement := range s {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats selector the way it's parsed
+func (s LabelSelector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, requirement := range s {
+		parts = append(parts, requirement.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+// SelectJobSpecs returns jobs with labels matching the selector
+func (s LabelSelector) SelectJobSpecs(jobSpecs []JobSpec) []JobSpec {
+	if s.Empty() {
+		return jobSpecs
+	}
+	var selected []JobSpec
+	for _, jobSpec := range jobSpecs {
+		if s.Matches(jobSpec.Labels) {
+			selected = append(selected, jobSpec)
+		}
+	}
+	return selected
+}
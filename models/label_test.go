@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelSelector(t *testing.T) {
+	t.Run("should parse equality, inequality and existence requirements", func(t *testing.T) {
+		selector, err := models.ParseLabelSelector("tier=critical, team != growth,sla")
+		assert.Nil(t, err)
+		assert.Equal(t, models.LabelSelector{
+			{Key: "sla", Operator: models.LabelOperatorExists},
+			{Key: "team", Operator: models.LabelOperatorNotEquals, Value: "growth"},
+			{Key: "tier", Operator: models.LabelOperatorEquals, Value: "critical"},
+		}, selector)
+		assert.Equal(t, "sla,team!=growth,tier=critical", selector.String())
+	})
+	t.Run("should fail for requirements without key", func(t *testing.T) {
+		_, err := models.ParseLabelSelector("=critical")
+		assert.NotNil(t, err)
+		_, err = models.ParseLabelSelector("tier==critical")
+		assert.NotNil(t, err)
+	})
+	t.Run("should match labels satisfying all requirements", func(t *testing.T) {
+		selector, err := models.ParseLabelSelector("tier=critical,team!=growth")
+		assert.Nil(t, err)
+		assert.True(t, selector.Matches(map[string]string{"tier": "critical"}))
+		assert.True(t, selector.Matches(map[string]string{"tier": "critical", "team": "ads"}))
+		assert.False(t, selector.Matches(map[string]string{"tier": "critical", "team": "growth"}))
+		assert.False(t, selector.Matches(nil))
+	})
+	t.Run("should select every job when empty", func(t *testing.T) {
+		selector, err := models.ParseLabelSelector("")
+		assert.Nil(t, err)
+		assert.True(t, selector.Empty())
+		jobs := []models.JobSpec{{Name: "job-1"}, {Name: "job-2", Labels: map[string]string{"tier": "critical"}}}
+		assert.Equal(t, jobs, selector.SelectJobSpecs(jobs))
+
+		selector, err = models.ParseLabelSelector("tier=critical")
+		assert.Nil(t, err)
+		assert.Equal(t, jobs[1:], selector.SelectJobSpecs(jobs))
+	})
+	t.Run("should tag labels as sorted pairs", func(t *testing.T) {
+		jobSpec := models.JobSpec{Labels: map[string]string{"tier": "critical", "orchestrator": "optimus"}}
+		assert.Equal(t, []string{"orchestrator:optimus", "tier:critical"}, jobSpec.GetLabelsAsTags())
+	})
+}
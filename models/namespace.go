@@ -1,6 +1,18 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// NamespaceDatastoreSecretKeyPrefix followed by the upper cased name of
+	// a datastore is the namespace config naming the project secret used as
+	// credentials of the datastore for resources of the namespace, e.g.
+	// DATASTORE_SECRET_BIGQUERY: DATASTORE_BIGQUERY_MARKETING
+	NamespaceDatastoreSecretKeyPrefix = "DATASTORE_SECRET_"
+)
 
 // NamespaceSpec represents a namespace which is an individual or a team with an unique name.
 // A Project can have any number of namespaces (with unique names).
@@ -14,3 +26,13 @@ type NamespaceSpec struct {
 	// ProjectSpec is the project that this namespace belongs to
 	ProjectSpec ProjectSpec
 }
+
+// DatastoreSecretName is the name of project secret holding credentials of
+// datastore for resources of namespace, defaultName unless namespace is
+// configured with a secret of its own
+func (n NamespaceSpec) DatastoreSecretName(datastore, defaultName string) string {
+	if name, ok := n.Config[NamespaceDatastoreSecretKeyPrefix+strings.ToUpper(datastore)]; ok && name != "" {
+		return name
+	}
+	return defaultName
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProjectNamingKeyPrefix in project config followed by what is named
+	// holds the pattern its names should match, NAMING_JOB for names of jobs
+	// and NAMING_<TYPE> for resources of the type, e.g. NAMING_DATASET,
+	// NAMING_TABLE. Patterns are regular expressions matched against the
+	// whole name
+	ProjectNamingKeyPrefix = "NAMING_"
+
+	// NamingKindJob names jobs in naming policy, resources are named by
+	// their type
+	NamingKindJob = "job"
+)
+
+// ErrNamingViolation signifies names which don't follow naming policy of
+// their project
+var ErrNamingViolation = errors.New("names violate naming policy of project")
+
+// NamingPolicy holds the patterns names of jobs and resources of a project
+// should match, kinds without a pattern can be named anything
+type NamingPolicy struct {
+	patterns map[string]*regexp.Regexp
+}
+
+// NamingPolicy parses the naming policy in project config
+func (s ProjectSpec) NamingPolicy() (NamingPolicy, error) {
+	policy := NamingPolicy{patterns: map[string]*regexp.Regexp{}}
+	for key, value := range s.Config {
+		if !strings.HasPrefix(key, ProjectNamingKeyPrefix) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		pattern, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return NamingPolicy{}, errors.Wrapf(err, "invalid %s %s in project %s", key, value, s.Name)
+		}
+		kind := strings.ToLower(strings.TrimPrefix(key, ProjectNamingKeyPrefix))
+		policy.patterns[kind] = pattern
+	}
+	return policy, nil
+}
+
+// CheckJobs fails with ErrNamingViolation listing every job not named as per
+// the policy
+func (p NamingPolicy) CheckJobs(jobSpecs []JobSpec) error {
+	var violations []string
+	for _, jobSpec := range jobSpecs {
+		if violation := p.check(NamingKindJob, jobSpec.Name); violation != "" {
+			violations = append(violations, violation)
+		}
+	}
+	return namingViolations(violations)
+}
+
+// CheckResources fails with ErrNamingViolation listing every resource not
+// named as per the pattern of its type
+func (p NamingPolicy) CheckResources(resourceSpecs []ResourceSpec) error {
+	var violations []string
+	for _, resourceSpec := range resourceSpecs {
+		if violation := p.check(resourceSpec.Type.String(), resourceSpec.Name); violation != "" {
+			violations = append(violations, violation)
+		}
+	}
+	return namingViolations(violations)
+}
+
+func (p NamingPolicy) check(kind, name string) string {
+	pattern, ok := p.patterns[strings.ToLower(kind)]
+	if !ok || pattern.MatchString(name) {
+		return ""
+	}
+	key := ProjectNamingKeyPrefix + strings.ToUpper(kind)
+	return fmt.Sprintf("%s %s doesn't match %s %s", kind, name, key,
+		strings.TrimSuffix(strings.TrimPrefix(pattern.String(), "^(?:"), ")$"))
+}
+
+func namingViolations(violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Strings(violations)
+	return errors.Wrap(ErrNamingViolation, strings.Join(violations, "; "))
+}
@@ -0,0 +1,59 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/models"
+)
+
+func TestNamingPolicy(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		Name: "a-data-project",
+		Config: map[string]string{
+			"bucket":         "gs://some_folder",
+			"NAMING_JOB":     `[a-z]+(-[a-z0-9]+)*`,
+			"NAMING_TABLE":   `proj\.[a-z_]+\.(raw|agg)_[a-z_]+`,
+			"NAMING_DATASET": "",
+		},
+	}
+
+	t.Run("should accept names matching the patterns", func(t *testing.T) {
+		policy, err := projectSpec.NamingPolicy()
+		assert.Nil(t, err)
+
+		assert.Nil(t, policy.CheckJobs([]models.JobSpec{{Name: "daily-orders"}, {Name: "orders"}}))
+		assert.Nil(t, policy.CheckResources([]models.ResourceSpec{
+			{Name: "proj.sales.raw_orders", Type: models.ResourceTypeTable},
+			{Name: "anything.goes", Type: models.ResourceTypeDataset},
+			{Name: "proj.sales.orders_view", Type: models.ResourceTypeView},
+		}))
+	})
+	t.Run("should list every name not matching the whole pattern", func(t *testing.T) {
+		policy, err := projectSpec.NamingPolicy()
+		assert.Nil(t, err)
+
+		err = policy.CheckJobs([]models.JobSpec{{Name: "Daily_Orders"}, {Name: "orders"}, {Name: "orders-"}})
+		assert.True(t, errors.Is(err, models.ErrNamingViolation))
+		assert.Equal(t, "job Daily_Orders doesn't match NAMING_JOB [a-z]+(-[a-z0-9]+)*; "+
+			"job orders- doesn't match NAMING_JOB [a-z]+(-[a-z0-9]+)*: names violate naming policy of project", err.Error())
+
+		err = policy.CheckResources([]models.ResourceSpec{
+			{Name: "proj.sales.orders", Type: models.ResourceTypeTable},
+			{Name: "proj.sales.agg_orders", Type: models.ResourceTypeTable},
+		})
+		assert.True(t, errors.Is(err, models.ErrNamingViolation))
+		assert.Contains(t, err.Error(), "table proj.sales.orders doesn't match NAMING_TABLE")
+		assert.NotContains(t, err.Error(), "agg_orders")
+	})
+	t.Run("should fail for invalid patterns", func(t *testing.T) {
+		_, err := models.ProjectSpec{
+			Name:   "a-data-project",
+			Config: map[string]string{"NAMING_JOB": "[a-z"},
+		}.NamingPolicy()
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "invalid NAMING_JOB [a-z in project a-data-project")
+	})
+}
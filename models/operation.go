@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	OperationStatusPending   = "pending"
+	OperationStatusRunning   = "running"
+	OperationStatusSucceeded = "succeeded"
+	OperationStatusFailed    = "failed"
+	OperationStatusCancelled = "cancelled"
+
+	// OperationKindPromotion clones datasets of a promotion manifest
+	OperationKindPromotion = "promotion"
+	// OperationKindResourceRestore restores a resource to a point in time
+	OperationKindResourceRestore = "resource_restore"
+	// OperationKindTemplateMigration recompiles jobs of a project against
+	// the scheduler template of the server
+	OperationKindTemplateMigration = "template_migration"
+	// OperationKindProjectArchive pauses and removes jobs of a project from
+	// scheduler after snapshotting them
+	OperationKindProjectArchive = "project_archive"
+	// OperationKindProjectRestore redeploys jobs of an archived project
+	OperationKindProjectRestore = "project_restore"
+)
+
+// Operation is a long running task of a project, like copying datasets,
+// which the server runs in background after it is requested. Clients are
+// given its id to follow its progress and result, or to cancel it
+type Operation struct {
+	ID   uuid.UUID
+	Kind string
+	// Actor is the user who requested the operation
+	Actor string
+	// Params are what the operation was requested with and Result is the
+	// outcome it ended with, both json encoded
+	Params   json.RawMessage
+	Result   json.RawMessage
+	Progress OperationProgress
+	Status   string
+	Message  string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OperationProgress is how many of the steps of an operation are done,
+// Total is 0 if an operation can't tell its steps upfront
+type OperationProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// IsDone checks if operation is over, whatever the outcome
+func (o Operation) IsDone() bool {
+	return o.Status == OperationStatusSucceeded || o.Status == OperationStatusFailed ||
+		o.Status == OperationStatusCancelled
+}
+
+// OperationFunc is the work of an operation, it should return once ctx is
+// done and report progress as it completes steps. Result returned is json
+// encoded and stored with the operation even if it fails
+type OperationFunc func(ctx context.Context, progress func(OperationProgress)) (result interface{}, err error)
+
+// OperationRunner runs long running operations of projects in background
+type OperationRunner interface {
+	// Start records the operation requested with params and runs fn in
+	// background, the recorded operation is returned
+	Start(project ProjectSpec, kind, actor string, params interface{}, fn OperationFunc) (Operation, error)
+}
+
+// ResourceRestore is the request of a restore of a resource to a point in
+// time, which runs as an operation
+type ResourceRestore struct {
+	Datastore string `json:"datastore"`
+	// ResourceName is restored as it was at PointInTime into Destination,
+	// or in place if Destination is empty
+	ResourceName string    `json:"resource"`
+	Destination  string    `json:"destination,omitempty"`
+	PointInTime  time.Time `json:"point_in_time"`
+}
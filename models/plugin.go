@@ -15,6 +15,7 @@ const (
 	// plugin modes are optional and implemented as needed
 	ModTypeCLI                PluginMod = "cli"
 	ModTypeDependencyResolver PluginMod = "dependencyresolver"
+	ModTypeConfigValidator    PluginMod = "configvalidator"
 
 	HookTypePre  HookType = "pre"
 	HookTypePost HookType = "post"
@@ -109,6 +110,16 @@ type DependencyResolverMod interface {
 	GenerateDependencies(context.Context, GenerateDependenciesRequest) (*GenerateDependenciesResponse, error)
 }
 
+// ConfigValidatorMod can be implemented by tasks to reject invalid configs
+// and assets of jobs before they are deployed
+type ConfigValidatorMod interface {
+	BasePlugin
+
+	// ValidateConfig returns an actionable message for each problem found
+	// in config and assets, no errors means the config is valid
+	ValidateConfig(context.Context, ValidateConfigRequest) (*ValidateConfigResponse, error)
+}
+
 type PluginOptions struct {
 	DryRun bool
 }
@@ -327,14 +338,26 @@ type GenerateDependenciesResponse struct {
 	Dependencies []string
 }
 
+type ValidateConfigRequest struct {
+	Config PluginConfigs
+	Assets PluginAssets
+
+	PluginOptions
+}
+
+type ValidateConfigResponse struct {
+	Errors []string
+}
+
 var (
 	// PluginRegistry holds all supported plugins for this run
-	PluginRegistry       PluginRepository = NewPluginRepository()
-	ErrUnsupportedPlugin                  = errors.New("unsupported plugin requested, make sure its correctly installed")
+	PluginRegistry              PluginRepository = NewPluginRepository()
+	ErrUnsupportedPlugin                         = errors.New("unsupported plugin requested, make sure its correctly installed")
+	ErrUnsupportedPluginVersion                  = errors.New("unsupported plugin version requested")
 )
 
 type PluginRepository interface {
-	Add(BasePlugin, CommandLineMod, DependencyResolverMod) error
+	Add(BasePlugin, CommandLineMod, DependencyResolverMod, ConfigValidatorMod) error
 	GetByName(string) (*Plugin, error)
 	GetAll() []*Plugin
 	GetTasks() []*Plugin
@@ -352,6 +375,7 @@ type Plugin struct {
 	// can be used in different circumstances
 	CLIMod        CommandLineMod
 	DependencyMod DependencyResolverMod
+	ValidatorMod  ConfigValidatorMod
 }
 
 func (p *Plugin) Info() *PluginInfoResponse {
@@ -418,7 +442,7 @@ func (s *registeredPlugins) GetHooks() []*Plugin {
 	return list
 }
 
-func (s *registeredPlugins) Add(baseMod BasePlugin, cliMod CommandLineMod, drMod DependencyResolverMod) error {
+func (s *registeredPlugins) Add(baseMod BasePlugin, cliMod CommandLineMod, drMod DependencyResolverMod, cvMod ConfigValidatorMod) error {
 	info, err := baseMod.PluginInfo()
 	if err != nil {
 		return err
@@ -454,6 +478,7 @@ func (s *registeredPlugins) Add(baseMod BasePlugin, cliMod CommandLineMod, drMod
 		Base:          baseMod,
 		CLIMod:        cliMod,
 		DependencyMod: drMod,
+		ValidatorMod:  cvMod,
 	}
 	return nil
 }
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -21,8 +23,112 @@ const (
 
 	// Secret used to authenticate with scheduler provided at ProjectSchedulerHost
 	ProjectSchedulerAuth = "SCHEDULER_AUTH"
+
+	// ProjectEnvironmentKey in project config marks the environment of a project,
+	// stricter checks are applied on jobs of production projects
+	ProjectEnvironmentKey        = "ENVIRONMENT"
+	ProjectEnvironmentProduction = "production"
+
+	// ProjectGitSyncURLKey in project config is the git repository server
+	// deploys specifications of the project from, along with the branch and
+	// directory of specifications in it
+	ProjectGitSyncURLKey    = "GIT_SYNC_URL"
+	ProjectGitSyncBranchKey = "GIT_SYNC_BRANCH"
+	ProjectGitSyncPathKey   = "GIT_SYNC_PATH"
+
+	// Secret used to verify webhook calls requesting git sync of a project
+	ProjectSecretGitSyncWebhook = "GIT_SYNC_WEBHOOK"
+
+	// Secret used as bearer token by CI systems deploying a project over http
+	ProjectSecretDeployToken = "DEPLOY_TOKEN"
+
+	// Secret used as token of the git host results of deploying commits of
+	// the project are posted to, along with ProjectCommitStatusProviderKey in
+	// project config being github or gitlab and ProjectCommitStatusAPIURLKey
+	// the api of the host, both are inferred from the repository if not set
+	ProjectSecretCommitStatusToken = "COMMIT_STATUS_TOKEN"
+	ProjectCommitStatusProviderKey = "COMMIT_STATUS_PROVIDER"
+	ProjectCommitStatusAPIURLKey   = "COMMIT_STATUS_API_URL"
+
+	// ProjectCanaryStoragePathKey in project config is the specification store
+	// of a staging scheduler canary jobs are deployed to, along with the host
+	// of that scheduler, canary jobs go to the project scheduler otherwise
+	ProjectCanaryStoragePathKey   = "CANARY_STORAGE_PATH"
+	ProjectCanarySchedulerHostKey = "CANARY_SCHEDULER_HOST"
+
+	// Secrets of the staging scheduler, ProjectSecretStorageKey and
+	// ProjectSchedulerAuth are used when not registered
+	ProjectSecretCanaryStorageKey    = "CANARY_STORAGE"
+	ProjectSecretCanarySchedulerAuth = "CANARY_SCHEDULER_AUTH"
+
+	// ProjectTemplateStagingStoragePathKey in project config is the
+	// specification store of a staging scheduler jobs recompiled against a
+	// new scheduler template are validated with before they replace the
+	// deployed ones, along with the host of that scheduler
+	ProjectTemplateStagingStoragePathKey   = "TEMPLATE_STAGING_STORAGE_PATH"
+	ProjectTemplateStagingSchedulerHostKey = "TEMPLATE_STAGING_SCHEDULER_HOST"
+
+	// Secrets of the template staging scheduler, ProjectSecretStorageKey and
+	// ProjectSchedulerAuth are used when not registered
+	ProjectSecretTemplateStagingStorageKey    = "TEMPLATE_STAGING_STORAGE"
+	ProjectSecretTemplateStagingSchedulerAuth = "TEMPLATE_STAGING_SCHEDULER_AUTH"
+
+	// ProjectJobArtifactFormatKey in project config is the format compiled
+	// jobs are uploaded to the specification store in, along with their
+	// compression. Jobs are source files of the scheduler by default
+	ProjectJobArtifactFormatKey      = "JOB_ARTIFACT_FORMAT"
+	ProjectJobArtifactCompressionKey = "JOB_ARTIFACT_COMPRESSION"
+
+	// ProjectCalendarKeyPrefix in project config followed by the name of a
+	// holiday calendar holds its comma separated days, e.g. CALENDAR_HOLIDAYS:
+	// 2021-12-25,2022-01-01, jobs refer to calendars in their schedule exceptions
+	ProjectCalendarKeyPrefix = "CALENDAR_"
+
+	// ProjectSharedDestinationsKey in project config holds comma separated
+	// destinations more than one job of the project is allowed to write to
+	ProjectSharedDestinationsKey = "SHARED_DESTINATIONS"
+
+	// ProjectReplayNotifyKey in project config holds comma separated channels
+	// status changes of replays of the project are notified to, e.g.
+	// slack://#data-replays, along with ProjectReplayNotifyDigestKey holding
+	// the interval, e.g. 1h, notifications are batched into a digest for
+	ProjectReplayNotifyKey       = "REPLAY_NOTIFY"
+	ProjectReplayNotifyDigestKey = "REPLAY_NOTIFY_DIGEST"
+
+	// ProjectTemplateEngineKey in project config holds the engine assets of
+	// jobs are compiled with unless jobs set their own, go or jinja
+	ProjectTemplateEngineKey = "TEMPLATE_ENGINE"
+
+	// ProjectRetentionInstancesKey, ProjectRetentionReplaysKey and
+	// ProjectRetentionAuditLogsKey in project config hold the days runs of
+	// jobs, replays and audit logs of the project are kept for, overriding
+	// the retention configured for the server, zero keeps them forever
+	ProjectRetentionInstancesKey = "RETENTION_INSTANCES_DAYS"
+	ProjectRetentionReplaysKey   = "RETENTION_REPLAYS_DAYS"
+	ProjectRetentionAuditLogsKey = "RETENTION_AUDIT_LOGS_DAYS"
+
+	// ProjectCostBudgetKey in project config holds the bytes runs of jobs of
+	// the project are expected to bill in a calendar month(UTC), e.g. 50TiB,
+	// with ProjectCostBudgetNotifyKey holding comma separated channels spend
+	// crossing CostBudgetThresholds of it is notified to
+	ProjectCostBudgetKey       = "COST_BUDGET"
+	ProjectCostBudgetNotifyKey = "COST_BUDGET_NOTIFY"
+
+	// ProjectNotifyEmailSubjectKey in project config holds go template of
+	// the subject of emails events of jobs of the project are notified with,
+	// body of emails is templated as any channel, see ProjectNotifyBodyKey
+	ProjectNotifyEmailSubjectKey = "NOTIFY_EMAIL_SUBJECT"
+
+	projectNotifyBodyKeyFormat = "NOTIFY_%s_BODY"
 )
 
+// ProjectNotifyBodyKey is the project config key holding go template of the
+// payload events are notified with to channels of a scheme, e.g.
+// NOTIFY_SLACK_BODY, notifiers use their own format if it is not set
+func ProjectNotifyBodyKey(scheme string) string {
+	return fmt.Sprintf(projectNotifyBodyKeyFormat, strings.ToUpper(scheme))
+}
+
 var (
 	// PluginSecretString generates plugin secret identifier using its type
 	// and name, e.g. task, bq2bq
@@ -38,7 +144,7 @@ type ProjectSpec struct {
 
 	// configuration for the registered projects
 	// - ProjectStoragePathKey: specification store for scheduler inputs
-	// suggested are gcs/s3 or similar object store
+	// supported are gcs, s3 and directories of filesystem
 	// - ProjectSchedulerHost: host url to connect with the scheduler used by
 	// the tenant
 	Config map[string]string
@@ -57,6 +163,150 @@ func (s ProjectSpec) String() string {
 	return fmt.Sprintf("%s, %v", s.Name, s.Config)
 }
 
+// IsProduction checks if project is marked as production in its config
+func (s ProjectSpec) IsProduction() bool {
+	for key, val := range s.Config {
+		if strings.EqualFold(key, ProjectEnvironmentKey) {
+			return strings.EqualFold(val, ProjectEnvironmentProduction)
+		}
+	}
+	return false
+}
+
+// IsSharedDestination returns true if jobs of the project are allowed to
+// write to the same destination, destinations are compared as urns
+func (s ProjectSpec) IsSharedDestination(destination string) bool {
+	destination = CanonicalURN(destination)
+	for _, shared := range strings.Split(s.Config[ProjectSharedDestinationsKey], ",") {
+		if CanonicalURN(shared) == destination {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateEngine returns the engine assets of jobs of the project are compiled
+// with, go templates if not set
+func (s ProjectSpec) TemplateEngine() string {
+	if engine := strings.TrimSpace(s.Config[ProjectTemplateEngineKey]); engine != "" {
+		return strings.ToLower(engine)
+	}
+	return TemplateEngineGo
+}
+
+// ReplayNotifyChannels returns channels replays of the project are notified to
+func (s ProjectSpec) ReplayNotifyChannels() []string {
+	var channels []string
+	for _, channel := range strings.Split(s.Config[ProjectReplayNotifyKey], ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// ReplayNotifyDigest returns the interval replay notifications of the project
+// are batched for, zero if they are sent as they happen
+func (s ProjectSpec) ReplayNotifyDigest() (time.Duration, error) {
+	value := strings.TrimSpace(s.Config[ProjectReplayNotifyDigestKey])
+	if value == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return 0, errors.Errorf("invalid %s %s in project %s, should be a duration like 1h", ProjectReplayNotifyDigestKey, value, s.Name)
+	}
+	return interval, nil
+}
+
+// CostBudget returns the bytes runs of jobs of the project are expected to
+// bill in a month, zero without a budget
+func (s ProjectSpec) CostBudget() (int64, error) {
+	value := strings.TrimSpace(s.Config[ProjectCostBudgetKey])
+	if value == "" {
+		return 0, nil
+	}
+	budget, err := parseBytes(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s %s in project %s", ProjectCostBudgetKey, value, s.Name)
+	}
+	return budget, nil
+}
+
+// CostBudgetNotifyChannels returns channels spend of the project crossing
+// thresholds of its budget is notified to
+func (s ProjectSpec) CostBudgetNotifyChannels() []string {
+	var channels []string
+	for _, channel := range strings.Split(s.Config[ProjectCostBudgetNotifyKey], ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// RetentionPolicy returns how long data of the project is kept, retention
+// set in project config takes precedence over defaults
+func (s ProjectSpec) RetentionPolicy(defaults RetentionPolicy) (RetentionPolicy, error) {
+	policy := defaults
+	for key, retention := range map[string]*time.Duration{
+		ProjectRetentionInstancesKey: &policy.Instances,
+		ProjectRetentionReplaysKey:   &policy.Replays,
+		ProjectRetentionAuditLogsKey: &policy.AuditLogs,
+	} {
+		value := strings.TrimSpace(s.Config[key])
+		if value == "" {
+			continue
+		}
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return RetentionPolicy{}, errors.Errorf("invalid %s %s in project %s, should be days like 90", key, value, s.Name)
+		}
+		*retention = time.Hour * 24 * time.Duration(days)
+	}
+	return policy, nil
+}
+
+// JobArtifact returns the format and compression compiled jobs of the
+// project are uploaded in
+func (s ProjectSpec) JobArtifact() (JobArtifact, error) {
+	artifact := JobArtifact{
+		Format:      JobArtifactFormatSource,
+		Compression: JobArtifactCompressionNone,
+	}
+	if format := strings.TrimSpace(s.Config[ProjectJobArtifactFormatKey]); format != "" {
+		artifact.Format = strings.ToLower(format)
+	}
+	if compression := strings.TrimSpace(s.Config[ProjectJobArtifactCompressionKey]); compression != "" {
+		artifact.Compression = strings.ToLower(compression)
+	}
+	if err := artifact.Validate(); err != nil {
+		return JobArtifact{}, errors.Wrapf(err, "invalid job artifact of project %s", s.Name)
+	}
+	return artifact, nil
+}
+
+// GetCalendar returns days of a holiday calendar of the project
+func (s ProjectSpec) GetCalendar(name string) ([]time.Time, error) {
+	key := ProjectCalendarKeyPrefix + strings.ToUpper(name)
+	value, ok := s.Config[key]
+	if !ok {
+		return nil, errors.Errorf("calendar %s is not configured in project %s", name, s.Name)
+	}
+	var days []time.Time
+	for _, raw := range strings.Split(value, ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		day, err := time.Parse(JobDatetimeLayout, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid day in calendar %s", name)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
 type ProjectSecrets []ProjectSecretItem
 
 func (s ProjectSecrets) String() string {
@@ -3,6 +3,7 @@ package models_test
 import (
 	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/gtank/cryptopasta"
 	"github.com/stretchr/testify/assert"
@@ -102,4 +103,122 @@ func TestProjectSpec(t *testing.T) {
 			assert.Equal(t, rawSecret, string(value))
 		})
 	})
+	t.Run("ReplayNotify", func(t *testing.T) {
+		t.Run("should return channels and digest interval of replay notifications", func(t *testing.T) {
+			proj := models.ProjectSpec{Config: map[string]string{
+				models.ProjectReplayNotifyKey:       "slack://#data-replays, slack://ops@example.com,",
+				models.ProjectReplayNotifyDigestKey: "1h",
+			}}
+			assert.Equal(t, []string{"slack://#data-replays", "slack://ops@example.com"}, proj.ReplayNotifyChannels())
+			interval, err := proj.ReplayNotifyDigest()
+			assert.Nil(t, err)
+			assert.Equal(t, time.Hour, interval)
+		})
+		t.Run("should send replay notifications as they happen without digest interval", func(t *testing.T) {
+			interval, err := models.ProjectSpec{}.ReplayNotifyDigest()
+			assert.Nil(t, err)
+			assert.Equal(t, time.Duration(0), interval)
+			assert.Nil(t, models.ProjectSpec{}.ReplayNotifyChannels())
+		})
+		t.Run("should fail for invalid digest interval", func(t *testing.T) {
+			_, err := models.ProjectSpec{Config: map[string]string{
+				models.ProjectReplayNotifyDigestKey: "hourly",
+			}}.ReplayNotifyDigest()
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("CostBudget", func(t *testing.T) {
+		t.Run("should return monthly budget in bytes and its channels", func(t *testing.T) {
+			proj := models.ProjectSpec{Config: map[string]string{
+				models.ProjectCostBudgetKey:       "2.5TiB",
+				models.ProjectCostBudgetNotifyKey: "slack://#data-costs,",
+			}}
+			budget, err := proj.CostBudget()
+			assert.Nil(t, err)
+			assert.Equal(t, int64(5<<39), budget)
+			assert.Equal(t, []string{"slack://#data-costs"}, proj.CostBudgetNotifyChannels())
+
+			budget, err = models.ProjectSpec{Config: map[string]string{
+				models.ProjectCostBudgetKey: "1048576",
+			}}.CostBudget()
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1<<20), budget)
+		})
+		t.Run("should fail for invalid budget", func(t *testing.T) {
+			_, err := models.ProjectSpec{Config: map[string]string{
+				models.ProjectCostBudgetKey: "lots",
+			}}.CostBudget()
+			assert.NotNil(t, err)
+		})
+		t.Run("should tell thresholds of budget spend reached", func(t *testing.T) {
+			status := models.CostBudgetStatus{BudgetBytes: 1000, SpentBytes: 850}
+			assert.Equal(t, 85.0, status.Percent())
+			assert.Equal(t, []int{50, 80}, status.ThresholdsReached())
+			assert.Nil(t, models.CostBudgetStatus{SpentBytes: 850}.ThresholdsReached())
+		})
+	})
+	t.Run("TemplateEngine", func(t *testing.T) {
+		t.Run("should compile assets with go templates by default", func(t *testing.T) {
+			assert.Equal(t, models.TemplateEngineGo, models.JobSpec{}.AssetTemplateEngine(models.ProjectSpec{}))
+		})
+		t.Run("should prefer engine of the job over the one of project", func(t *testing.T) {
+			proj := models.ProjectSpec{Config: map[string]string{
+				models.ProjectTemplateEngineKey: "Jinja",
+			}}
+			assert.Equal(t, models.TemplateEngineJinja, models.JobSpec{}.AssetTemplateEngine(proj))
+			jobSpec := models.JobSpec{Task: models.JobSpecTask{TemplateEngine: models.TemplateEngineGo}}
+			assert.Equal(t, models.TemplateEngineGo, jobSpec.AssetTemplateEngine(proj))
+		})
+	})
+	t.Run("RetentionPolicy", func(t *testing.T) {
+		defaults := models.RetentionPolicy{Instances: time.Hour * 24 * 90, AuditLogs: time.Hour * 24 * 30}
+		t.Run("should override defaults with retention of project", func(t *testing.T) {
+			proj := models.ProjectSpec{Config: map[string]string{
+				models.ProjectRetentionReplaysKey:   "7",
+				models.ProjectRetentionAuditLogsKey: "0",
+			}}
+			policy, err := proj.RetentionPolicy(defaults)
+			assert.Nil(t, err)
+			assert.Equal(t, models.RetentionPolicy{Instances: time.Hour * 24 * 90, Replays: time.Hour * 24 * 7}, policy)
+		})
+		t.Run("should fail for invalid retention", func(t *testing.T) {
+			_, err := models.ProjectSpec{Config: map[string]string{
+				models.ProjectRetentionInstancesKey: "90d",
+			}}.RetentionPolicy(defaults)
+			assert.NotNil(t, err)
+		})
+	})
+	t.Run("JobArtifact", func(t *testing.T) {
+		t.Run("should upload source files of scheduler by default", func(t *testing.T) {
+			artifact, err := models.ProjectSpec{}.JobArtifact()
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobArtifact{
+				Format:      models.JobArtifactFormatSource,
+				Compression: models.JobArtifactCompressionNone,
+			}, artifact)
+		})
+		t.Run("should return format and compression of project", func(t *testing.T) {
+			artifact, err := models.ProjectSpec{Config: map[string]string{
+				models.ProjectJobArtifactFormatKey:      "JSON",
+				models.ProjectJobArtifactCompressionKey: " gzip",
+			}}.JobArtifact()
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobArtifact{
+				Format:      models.JobArtifactFormatJSON,
+				Compression: models.JobArtifactCompressionGzip,
+			}, artifact)
+		})
+		t.Run("should fail for compressed source files", func(t *testing.T) {
+			_, err := models.ProjectSpec{Config: map[string]string{
+				models.ProjectJobArtifactCompressionKey: "gzip",
+			}}.JobArtifact()
+			assert.NotNil(t, err)
+		})
+		t.Run("should fail for unknown format", func(t *testing.T) {
+			_, err := models.ProjectSpec{Config: map[string]string{
+				models.ProjectJobArtifactFormatKey: "tar",
+			}}.JobArtifact()
+			assert.NotNil(t, err)
+		})
+	})
 }
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"github.com/pkg/errors"
+)
+
+// PromotionManifest lists datasets of a datastore to be cloned from one
+// environment into another, e.g. production datasets into staging
+type PromotionManifest struct {
+	Datastore string             `yaml:"datastore" json:"datastore"`
+	Datasets  []PromotionDataset `yaml:"datasets" json:"datasets"`
+}
+
+// PromotionDataset is a dataset cloned by promotion, schema of its tables
+// and their data too if WithData is set
+type PromotionDataset struct {
+	Source      string   `yaml:"source" json:"source"`
+	Destination string   `yaml:"destination" json:"destination"`
+	WithData    bool     `yaml:"with_data" json:"with_data"`
+	Tables      []string `yaml:"tables" json:"tables"`
+}
+
+// PromotionResult is the outcome of cloning a dataset of manifest
+type PromotionResult struct {
+	Source      string
+	Destination string
+	CopyDatasetResponse
+	Err error
+}
+
+func (m PromotionManifest) Validate() error {
+	if m.Datastore == "" {
+		return errors.New("datastore of promotion is required")
+	}
+	if len(m.Datasets) == 0 {
+		return errors.New("promotion has no datasets")
+	}
+	destinations := map[string]bool{}
+	for _, dataset := range m.Datasets {
+		if dataset.Source == "" || dataset.Destination == "" {
+			return errors.New("source and destination of datasets are required")
+		}
+		if dataset.Source == dataset.Destination {
+			return errors.Errorf("dataset %s can't be promoted into itself", dataset.Source)
+		}
+		if destinations[dataset.Destination] {
+			return errors.Errorf("dataset %s is a destination more than once", dataset.Destination)
+		}
+		destinations[dataset.Destination] = true
+	}
+	return nil
+}
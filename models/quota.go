@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrQuotaExceeded signifies that a request would take a project
+// beyond its quota
+var ErrQuotaExceeded = errors.New("project quota exceeded")
+
+// ProjectQuota caps the usage of shared scheduler and datastore resources
+// by a project, zero value of a limit means it is unlimited
+type ProjectQuota struct {
+	MaxJobs             int
+	MaxResources        int
+	MaxReplayRunsPerDay int
+
+	UpdatedAt time.Time
+}
+
+// QuotaService enforces quota of projects
+type QuotaService interface {
+	// Get returns the quota of project, default quota if the project
+	// doesn't have one
+	Get(ProjectSpec) (ProjectQuota, error)
+	// Update sets the quota of project
+	Update(ProjectSpec, ProjectQuota) error
+
+	// CheckJobs fails if project can't have given number of jobs
+	CheckJobs(proj ProjectSpec, jobs int) error
+	// CheckResources fails if project can't have given number of resources in a datastore
+	CheckResources(proj ProjectSpec, resources int) error
+	// ReserveReplayRuns adds given runs to today's replay usage of the project,
+	// failing without adding them if they would exceed the daily replay quota
+	ReserveReplayRuns(proj ProjectSpec, runs int) error
+	// ReleaseReplayRuns removes runs reserved for a replay which wasn't accepted
+	// from today's replay usage of the project
+	ReleaseReplayRuns(proj ProjectSpec, runs int) error
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,11 +18,68 @@ const (
 	ReplayStatusCancelled = "cancelled" // end state
 )
 
+var (
+	// ErrInvalidReplayTransition signifies a replay can't move to the
+	// requested status from the one it is in, e.g. an ended replay
+	ErrInvalidReplayTransition = errors.New("invalid replay status transition")
+
+	// replayTransitions are the statuses a replay can move to from a status.
+	// A replay is accepted, picked up by a worker and ends as succeeded or
+	// failed, or is cancelled before it ends. End states move nowhere
+	replayTransitions = map[string][]string{
+		ReplayStatusAccepted:   {ReplayStatusInProgress, ReplayStatusFailed, ReplayStatusCancelled},
+		ReplayStatusInProgress: {ReplayStatusSuccess, ReplayStatusFailed, ReplayStatusCancelled},
+		ReplayStatusSuccess:    nil,
+		ReplayStatusFailed:     nil,
+		ReplayStatusCancelled:  nil,
+	}
+)
+
+// IsValidReplayTransition returns true if a replay can move from status to
+// next status
+func IsValidReplayTransition(from, to string) bool {
+	for _, status := range replayTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplayStatusesBefore returns the statuses a replay can move to status from
+func ReplayStatusesBefore(status string) []string {
+	var before []string
+	for from := range replayTransitions {
+		if IsValidReplayTransition(from, status) {
+			before = append(before, from)
+		}
+	}
+	sort.Strings(before)
+	return before
+}
+
+// IsReplayEnded returns true for end states of replay, replays in them
+// don't change anymore
+func IsReplayEnded(status string) bool {
+	next, ok := replayTransitions[status]
+	return ok && len(next) == 0
+}
+
 type ReplayMessage struct {
 	Type    string
 	Message string
 }
 
+// ReplayQueuePosition is the place of an accepted replay in the queue of
+// requests waiting for a free replay worker
+type ReplayQueuePosition struct {
+	// Position is 1 for the request picked up next
+	Position int
+	// ETA is the estimated time till a worker picks up the request, zero
+	// if no request has been processed yet
+	ETA time.Duration
+}
+
 type ReplayWorkerRequest struct {
 	ID         uuid.UUID
 	Job        JobSpec
@@ -29,6 +88,85 @@ type ReplayWorkerRequest struct {
 	Project    ProjectSpec
 	JobSpecMap map[string]JobSpec
 	Force      bool
+
+	// Selector limits replayed dependents of job to the ones matching it,
+	// dependents not matching it are left out with their downstream
+	Selector LabelSelector
+
+	// IgnoreUpstream marks sensors of cleared runs waiting for jobs not
+	// being replayed as succeeded, for upstream data known to be good
+	// whose runs were fixed outside optimus
+	IgnoreUpstream bool
+
+	// ParentID is the id of the replay plan the replay is part of, replays
+	// of a plan replay only their job as dependents have replays of their own
+	ParentID uuid.UUID
+}
+
+// ReplayPlanRequest replays all jobs of a project with labels matching
+// Selector between Start and End days
+type ReplayPlanRequest struct {
+	Project        ProjectSpec
+	Selector       LabelSelector
+	Start          time.Time
+	End            time.Time
+	Force          bool
+	IgnoreUpstream bool
+}
+
+// ReplayPlan is a replay of a set of jobs tracked under a single id, each
+// job is replayed by a replay of its own. Replays are in order of
+// dependencies of their jobs, upstream jobs first, and are processed one
+// after another
+type ReplayPlan struct {
+	ID      uuid.UUID
+	Project ProjectSpec
+	Replays []*ReplayWorkerRequest
+}
+
+// ReplayPlanStatus is the status of a replay plan made of statuses of its
+// replays, a plan ends once all of its replays have ended and is
+// successful only if all of them are
+func ReplayPlanStatus(replays []ReplaySpec) string {
+	var ended, succeeded, failed, cancelled, inProgress int
+	for _, replay := range replays {
+		switch replay.Status {
+		case ReplayStatusSuccess:
+			succeeded++
+		case ReplayStatusFailed:
+			failed++
+		case ReplayStatusCancelled:
+			cancelled++
+		case ReplayStatusInProgress:
+			inProgress++
+		}
+		if IsReplayEnded(replay.Status) {
+			ended++
+		}
+	}
+	switch {
+	case ended < len(replays) && (inProgress > 0 || ended > 0):
+		return ReplayStatusInProgress
+	case ended < len(replays):
+		return ReplayStatusAccepted
+	case failed > 0:
+		return ReplayStatusFailed
+	case cancelled > 0:
+		return ReplayStatusCancelled
+	default:
+		return ReplayStatusSuccess
+	}
+}
+
+// ReplayEstimate is the projected impact of a replay, duration assumes
+// runs are cleared one after another
+type ReplayEstimate struct {
+	Runs     int
+	Duration time.Duration
+
+	// RunsWithoutHistory are runs of jobs with no known duration
+	// of previous runs, they are not included in Duration
+	RunsWithoutHistory int
 }
 
 type ReplaySpec struct {
@@ -39,4 +177,7 @@ type ReplaySpec struct {
 	Status    string
 	Message   ReplayMessage
 	CreatedAt time.Time
+
+	// ParentID is the id of the replay plan the replay is part of, if any
+	ParentID uuid.UUID
 }
@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayStatus(t *testing.T) {
+	t.Run("should allow replays to move forward till they end", func(t *testing.T) {
+		assert.True(t, models.IsValidReplayTransition(models.ReplayStatusAccepted, models.ReplayStatusInProgress))
+		assert.True(t, models.IsValidReplayTransition(models.ReplayStatusAccepted, models.ReplayStatusCancelled))
+		assert.True(t, models.IsValidReplayTransition(models.ReplayStatusInProgress, models.ReplayStatusSuccess))
+		assert.True(t, models.IsValidReplayTransition(models.ReplayStatusInProgress, models.ReplayStatusFailed))
+
+		assert.False(t, models.IsValidReplayTransition(models.ReplayStatusAccepted, models.ReplayStatusSuccess))
+		assert.False(t, models.IsValidReplayTransition(models.ReplayStatusInProgress, models.ReplayStatusAccepted))
+		assert.False(t, models.IsValidReplayTransition(models.ReplayStatusCancelled, models.ReplayStatusInProgress))
+		assert.False(t, models.IsValidReplayTransition(models.ReplayStatusFailed, models.ReplayStatusFailed))
+	})
+	t.Run("should list statuses a replay moves to a status from", func(t *testing.T) {
+		assert.Equal(t, []string{models.ReplayStatusAccepted, models.ReplayStatusInProgress},
+			models.ReplayStatusesBefore(models.ReplayStatusCancelled))
+		assert.Equal(t, []string{models.ReplayStatusInProgress}, models.ReplayStatusesBefore(models.ReplayStatusSuccess))
+		assert.Empty(t, models.ReplayStatusesBefore(models.ReplayStatusAccepted))
+	})
+	t.Run("should tell end states", func(t *testing.T) {
+		assert.True(t, models.IsReplayEnded(models.ReplayStatusSuccess))
+		assert.True(t, models.IsReplayEnded(models.ReplayStatusCancelled))
+		assert.False(t, models.IsReplayEnded(models.ReplayStatusInProgress))
+		assert.False(t, models.IsReplayEnded("unknown"))
+	})
+	t.Run("should make status of a plan from statuses of its replays", func(t *testing.T) {
+		statuses := func(statuses ...string) []models.ReplaySpec {
+			var replays []models.ReplaySpec
+			for _, status := range statuses {
+				replays = append(replays, models.ReplaySpec{Status: status})
+			}
+			return replays
+		}
+		assert.Equal(t, models.ReplayStatusAccepted, models.ReplayPlanStatus(statuses(models.ReplayStatusAccepted,
+			models.ReplayStatusAccepted)))
+		assert.Equal(t, models.ReplayStatusInProgress, models.ReplayPlanStatus(statuses(models.ReplayStatusSuccess,
+			models.ReplayStatusAccepted)))
+		assert.Equal(t, models.ReplayStatusInProgress, models.ReplayPlanStatus(statuses(models.ReplayStatusFailed,
+			models.ReplayStatusInProgress)))
+		assert.Equal(t, models.ReplayStatusFailed, models.ReplayPlanStatus(statuses(models.ReplayStatusSuccess,
+			models.ReplayStatusFailed, models.ReplayStatusCancelled)))
+		assert.Equal(t, models.ReplayStatusCancelled, models.ReplayPlanStatus(statuses(models.ReplayStatusSuccess,
+			models.ReplayStatusCancelled)))
+		assert.Equal(t, models.ReplayStatusSuccess, models.ReplayPlanStatus(statuses(models.ReplayStatusSuccess,
+			models.ReplayStatusSuccess)))
+	})
+}
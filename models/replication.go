@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ReplicationState is a record of a project as it was last replicated from
+// another instance, Checksum is of the record on that instance and
+// LocalChecksum of the record here right after it was replicated. Records
+// changed on both instances since are conflicts
+type ReplicationState struct {
+	Key           string
+	Checksum      string
+	LocalChecksum string
+
+	UpdatedAt time.Time
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// RetentionPolicy is how long data of a project is kept, zero keeps it forever
+type RetentionPolicy struct {
+	// Instances are runs of jobs, by the time they were scheduled at
+	Instances time.Duration
+	// Replays are replay requests which reached an end state, by the time
+	// they were requested at
+	Replays time.Duration
+	// AuditLogs are audit logs of api calls, by the time calls were made at
+	AuditLogs time.Duration
+}
@@ -45,6 +45,38 @@ type SchedulerUnit interface {
 	// GetDagRunStatus should return batch of runs of a job
 	GetDagRunStatus(ctx context.Context, projSpec ProjectSpec, jobName string, startDate time.Time, endDate time.Time,
 		batchSize int) ([]JobStatus, error)
+
+	// GetInstanceLog should return the log of a task or hook of a job run,
+	// attempt 0 means the latest attempt
+	GetInstanceLog(ctx context.Context, projSpec ProjectSpec, jobName string, scheduledAt time.Time,
+		instanceType InstanceType, instanceName string, attempt int) (InstanceLog, error)
+
+	// IsJobLoaded tells if the compiled job has been picked up by scheduler,
+	// jobs failing to load never are
+	IsJobLoaded(ctx context.Context, projSpec ProjectSpec, jobName string) (bool, error)
+
+	// RunJob creates a run of job scheduled at the provided time outside of
+	// its schedule, unpausing the job if needed
+	RunJob(ctx context.Context, projSpec ProjectSpec, jobName string, scheduledAt time.Time) error
+
+	// SetJobPaused pauses or resumes scheduling of job, paused jobs stay
+	// paused across deployments
+	SetJobPaused(ctx context.Context, projSpec ProjectSpec, jobName string, paused bool) error
+}
+
+// SchedulerHealthChecker is implemented by schedulers which can tell if
+// the scheduler of a project is reachable
+type SchedulerHealthChecker interface {
+	HealthCheck(ctx context.Context, projSpec ProjectSpec) error
+}
+
+// SchedulerSensorMarker is implemented by schedulers which can mark sensors
+// of job runs as succeeded, so the runs don't wait for their upstream jobs
+type SchedulerSensorMarker interface {
+	// MarkSensorsSuccess marks sensors of runs of job scheduled between start
+	// and end dates as succeeded, except the ones waiting for jobs in except
+	MarkSensorsSuccess(ctx context.Context, projSpec ProjectSpec, jobName string, startDate, endDate time.Time,
+		except []string) error
 }
 
 type JobStatusState string
@@ -57,3 +89,21 @@ type JobStatus struct {
 	ScheduledAt time.Time
 	State       JobStatusState
 }
+
+// InstanceLog is the log of a task or hook container of a job run
+type InstanceLog struct {
+	JobName      string
+	ScheduledAt  time.Time
+	InstanceType InstanceType
+	InstanceName string
+	Attempt      int
+	Content      string
+}
+
+// SchedulerJobLoadErrorLister is implemented by schedulers which can tell why
+// compiled jobs of a project failed to load
+type SchedulerJobLoadErrorLister interface {
+	// GetJobLoadErrors returns errors of jobs which failed to load by their
+	// names
+	GetJobLoadErrors(ctx context.Context, projSpec ProjectSpec) (map[string]string, error)
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+const (
+	// MaxSearchLimit caps the results returned by a search
+	MaxSearchLimit = 200
+)
+
+// SearchQuery finds jobs and resources across projects, all of the filters
+// set have to match
+type SearchQuery struct {
+	// Text is split in terms each of which has to be part of name, owner,
+	// destination or task of a job, or name, datastore or type of a resource
+	Text string
+	// Labels have to be labels of job or resource with the same value
+	Labels map[string]string
+	// Project limits results to a project, all projects are searched if empty
+	Project string
+	// Owner and Task only apply to jobs
+	Owner string
+	Task  string
+	// Datastore and Type only apply to resources
+	Datastore string
+	Type      string
+	Limit     int
+}
+
+type JobSearchResult struct {
+	Name        string
+	Project     string
+	Namespace   string
+	Owner       string
+	Description string
+	Destination string
+	Task        string
+	Labels      map[string]string
+	UpdatedAt   time.Time
+}
+
+type ResourceSearchResult struct {
+	Name      string
+	Project   string
+	Namespace string
+	Datastore string
+	Type      string
+	Labels    map[string]string
+	UpdatedAt time.Time
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProjectSecretBackendKey in project config selects the secret backend
+	// used to resolve secret macros, e.g. {{.secret.API_KEY}}, of the project
+	// jobs. Secrets registered in optimus are used if not set.
+	ProjectSecretBackendKey = "SECRET_BACKEND"
+
+	// SecretBackendOptimus resolves secrets registered with optimus
+	SecretBackendOptimus = "optimus"
+)
+
+// ErrNoSuchSecret signifies that a requested secret is not present in the backend
+var ErrNoSuchSecret = errors.New("secret not found")
+
+// SecretBackend is a store of project secrets, e.g. HashiCorp Vault
+type SecretBackend interface {
+	Name() string
+
+	// Resolve returns the values of requested secrets of a project, backend
+	// specific configuration is read from the project config and secrets
+	Resolve(ctx context.Context, proj ProjectSpec, names []string) (map[string]string, error)
+}
+
+// SecretResolver resolves secrets used by a job from the backend
+// configured for its project
+type SecretResolver interface {
+	Resolve(ctx context.Context, namespace NamespaceSpec, jobSpec JobSpec, names []string) (map[string]string, error)
+}
+
+// SecretBackendName returns the secret backend configured for project
+func (s ProjectSpec) SecretBackendName() string {
+	for key, val := range s.Config {
+		if strings.EqualFold(key, ProjectSecretBackendKey) && val != "" {
+			return strings.ToLower(val)
+		}
+	}
+	return SecretBackendOptimus
+}
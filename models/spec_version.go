@@ -0,0 +1,146 @@
+package models
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// JobSpecVersion is the latest version of the format of job
+	// specifications, written as apiVersion in their yaml
+	JobSpecVersion = 1
+	// ResourceSpecVersion is the latest version of the format of resource
+	// specifications, written as apiVersion in their yaml
+	ResourceSpecVersion = 1
+)
+
+var (
+	// SpecMigrations upgrades specifications of older versions to the
+	// latest ones, as they are read and deployed
+	SpecMigrations = NewSpecMigrator(JobSpecVersion, ResourceSpecVersion)
+
+	ErrUnsupportedSpecVersion = errors.New("unsupported version of specification")
+)
+
+// JobSpecMigration upgrades a job specification from the version it is
+// registered for to the next one
+type JobSpecMigration func(JobSpec) (JobSpec, error)
+
+// ResourceSpecMigration upgrades a resource specification from the version
+// it is registered for to the next one, resources of all datastores are
+// passed to it
+type ResourceSpecMigration func(ResourceSpec) (ResourceSpec, error)
+
+// SpecMigrator upgrades specifications one version at a time until they are
+// of the latest version, so specifications written before a change of their
+// format keep working without being rewritten. Specifications without a
+// version were written before versions were required and are of the first
+// version, they are left without one unless migrated. Specifications of a
+// version newer than the latest one were written for a newer optimus and
+// are rejected
+type SpecMigrator struct {
+	mu sync.RWMutex
+
+	jobVersion         int
+	jobMigrations      map[int]JobSpecMigration
+	resourceVersion    int
+	resourceMigrations map[int]ResourceSpecMigration
+}
+
+// RegisterJobMigration registers the migration of job specifications of
+// version from to version from+1
+func (m *SpecMigrator) RegisterJobMigration(from int, migration JobSpecMigration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if from < 1 || from >= m.jobVersion {
+		return errors.Errorf("job specifications can't be migrated from version %d, latest version is %d", from, m.jobVersion)
+	}
+	if _, ok := m.jobMigrations[from]; ok {
+		return errors.Errorf("migration of job specifications from version %d is already registered", from)
+	}
+	m.jobMigrations[from] = migration
+	return nil
+}
+
+// RegisterResourceMigration registers the migration of resource
+// specifications of version from to version from+1
+func (m *SpecMigrator) RegisterResourceMigration(from int, migration ResourceSpecMigration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if from < 1 || from >= m.resourceVersion {
+		return errors.Errorf("resource specifications can't be migrated from version %d, latest version is %d", from, m.resourceVersion)
+	}
+	if _, ok := m.resourceMigrations[from]; ok {
+		return errors.Errorf("migration of resource specifications from version %d is already registered", from)
+	}
+	m.resourceMigrations[from] = migration
+	return nil
+}
+
+// MigrateJob returns the job specification upgraded to the latest version
+func (m *SpecMigrator) MigrateJob(spec JobSpec) (JobSpec, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	version := spec.Version
+	if version == 0 {
+		version = 1
+	}
+	if version > m.jobVersion {
+		return spec, errors.Wrapf(ErrUnsupportedSpecVersion, "job %s is of version %d, latest version supported is %d, upgrade optimus to use it",
+			spec.Name, version, m.jobVersion)
+	}
+	for version < m.jobVersion {
+		migration, ok := m.jobMigrations[version]
+		if !ok {
+			return spec, errors.Errorf("no migration of job specifications from version %d", version)
+		}
+		migrated, err := migration(spec)
+		if err != nil {
+			return spec, errors.Wrapf(err, "failed to migrate job %s from version %d", spec.Name, version)
+		}
+		version++
+		migrated.Version = version
+		spec = migrated
+	}
+	return spec, nil
+}
+
+// MigrateResource returns the resource specification upgraded to the latest
+// version
+func (m *SpecMigrator) MigrateResource(spec ResourceSpec) (ResourceSpec, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	version := spec.Version
+	if version == 0 {
+		version = 1
+	}
+	if version > m.resourceVersion {
+		return spec, errors.Wrapf(ErrUnsupportedSpecVersion, "resource %s is of version %d, latest version supported is %d, upgrade optimus to use it",
+			spec.Name, version, m.resourceVersion)
+	}
+	for version < m.resourceVersion {
+		migration, ok := m.resourceMigrations[version]
+		if !ok {
+			return spec, errors.Errorf("no migration of resource specifications from version %d", version)
+		}
+		migrated, err := migration(spec)
+		if err != nil {
+			return spec, errors.Wrapf(err, "failed to migrate resource %s from version %d", spec.Name, version)
+		}
+		version++
+		migrated.Version = version
+		spec = migrated
+	}
+	return spec, nil
+}
+
+// NewSpecMigrator migrates specifications to jobVersion and resourceVersion
+func NewSpecMigrator(jobVersion, resourceVersion int) *SpecMigrator {
+	return &SpecMigrator{
+		jobVersion:         jobVersion,
+		jobMigrations:      map[int]JobSpecMigration{},
+		resourceVersion:    resourceVersion,
+		resourceMigrations: map[int]ResourceSpecMigration{},
+	}
+}
@@ -0,0 +1,88 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecMigrator(t *testing.T) {
+	t.Run("MigrateJob", func(t *testing.T) {
+		newMigrator := func(t *testing.T) *models.SpecMigrator {
+			migrator := models.NewSpecMigrator(3, 1)
+			assert.Nil(t, migrator.RegisterJobMigration(1, func(spec models.JobSpec) (models.JobSpec, error) {
+				spec.Description += " v2"
+				return spec, nil
+			}))
+			assert.Nil(t, migrator.RegisterJobMigration(2, func(spec models.JobSpec) (models.JobSpec, error) {
+				spec.Description += " v3"
+				return spec, nil
+			}))
+			return migrator
+		}
+		t.Run("should upgrade specs one version at a time", func(t *testing.T) {
+			spec, err := newMigrator(t).MigrateJob(models.JobSpec{Name: "job", Version: 2, Description: "desc"})
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobSpec{Name: "job", Version: 3, Description: "desc v3"}, spec)
+		})
+		t.Run("should upgrade specs without a version from the first version", func(t *testing.T) {
+			spec, err := newMigrator(t).MigrateJob(models.JobSpec{Name: "job", Description: "desc"})
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobSpec{Name: "job", Version: 3, Description: "desc v2 v3"}, spec)
+		})
+		t.Run("should leave specs of the latest version as they are", func(t *testing.T) {
+			spec, err := newMigrator(t).MigrateJob(models.JobSpec{Name: "job", Version: 3, Description: "desc"})
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobSpec{Name: "job", Version: 3, Description: "desc"}, spec)
+		})
+		t.Run("should leave specs without a version unversioned if there is nothing to migrate", func(t *testing.T) {
+			spec, err := models.NewSpecMigrator(1, 1).MigrateJob(models.JobSpec{Name: "job"})
+			assert.Nil(t, err)
+			assert.Equal(t, models.JobSpec{Name: "job"}, spec)
+		})
+		t.Run("should reject specs newer than the latest version", func(t *testing.T) {
+			_, err := newMigrator(t).MigrateJob(models.JobSpec{Name: "job", Version: 4})
+			assert.True(t, errors.Is(err, models.ErrUnsupportedSpecVersion))
+		})
+		t.Run("should fail if a migration fails or is missing", func(t *testing.T) {
+			migrator := models.NewSpecMigrator(3, 1)
+			assert.Nil(t, migrator.RegisterJobMigration(1, func(spec models.JobSpec) (models.JobSpec, error) {
+				return spec, assert.AnError
+			}))
+			_, err := migrator.MigrateJob(models.JobSpec{Name: "job", Version: 1})
+			assert.True(t, errors.Is(err, assert.AnError))
+
+			_, err = migrator.MigrateJob(models.JobSpec{Name: "job", Version: 2})
+			assert.Equal(t, "no migration of job specifications from version 2", err.Error())
+		})
+		t.Run("should register a single migration of versions older than the latest one", func(t *testing.T) {
+			migrator := newMigrator(t)
+			noop := func(spec models.JobSpec) (models.JobSpec, error) { return spec, nil }
+			assert.NotNil(t, migrator.RegisterJobMigration(1, noop))
+			assert.NotNil(t, migrator.RegisterJobMigration(3, noop))
+			assert.NotNil(t, migrator.RegisterJobMigration(0, noop))
+		})
+	})
+	t.Run("MigrateResource", func(t *testing.T) {
+		t.Run("should upgrade specs to the latest version", func(t *testing.T) {
+			migrator := models.NewSpecMigrator(1, 2)
+			assert.Nil(t, migrator.RegisterResourceMigration(1, func(spec models.ResourceSpec) (models.ResourceSpec, error) {
+				spec.Labels = map[string]string{"migrated": "true"}
+				return spec, nil
+			}))
+			spec, err := migrator.MigrateResource(models.ResourceSpec{Name: "proj.dataset.table"})
+			assert.Nil(t, err)
+			assert.Equal(t, models.ResourceSpec{
+				Name:    "proj.dataset.table",
+				Version: 2,
+				Labels:  map[string]string{"migrated": "true"},
+			}, spec)
+		})
+		t.Run("should reject specs newer than the latest version", func(t *testing.T) {
+			_, err := models.NewSpecMigrator(1, 1).MigrateResource(models.ResourceSpec{Name: "proj.dataset.table", Version: 2})
+			assert.True(t, errors.Is(err, models.ErrUnsupportedSpecVersion))
+		})
+	})
+}
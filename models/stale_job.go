@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// StaleJobReason is why a job is reported as a candidate for cleanup
+type StaleJobReason string
+
+const (
+	// StaleJobReasonNoSuccessfulRun is of jobs without a successful run
+	// within the window of analysis
+	StaleJobReasonNoSuccessfulRun StaleJobReason = "no_successful_run"
+
+	// StaleJobReasonUnreadDestination is of jobs whose destination no other
+	// job depends on and which wasn't read within the window of analysis, as
+	// far as its datastore audits reads
+	StaleJobReasonUnreadDestination StaleJobReason = "unread_destination"
+)
+
+// StaleJob is a job which looks unused, reported for owners to review
+// and clean up
+type StaleJob struct {
+	ProjectName   string
+	NamespaceName string
+	JobName       string
+	Owner         string
+	Destination   string
+	Reasons       []StaleJobReason
+
+	// runs scheduled within the window of analysis
+	Runs int
+
+	// LastReadAt is the latest read of destination known, zero if unknown
+	LastReadAt time.Time
+}
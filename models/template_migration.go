@@ -0,0 +1,16 @@
+package models
+
+// TemplateMigration is the outcome of recompiling jobs of a project against
+// the scheduler template of the server in a staging scheduler. Jobs replace
+// the deployed ones only if all of them are valid
+type TemplateMigration struct {
+	// Jobs is the number of jobs of the project recompiled
+	Jobs int
+	// Invalid has the reason of jobs which failed to compile or to load in
+	// the staging scheduler by their names
+	Invalid map[string]string
+	// Swapped is set once the staged jobs replaced the deployed ones, with
+	// Uploaded of them differing from what was deployed
+	Swapped  bool
+	Uploaded int
+}
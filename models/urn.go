@@ -0,0 +1,125 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// URNSchemeBigQuery names bigquery tables and datasets, e.g.
+	// bigquery://project:dataset.table
+	URNSchemeBigQuery = "bigquery"
+	// URNSchemeGCS names objects and prefixes of gcs, e.g.
+	// gcs://bucket/path
+	URNSchemeGCS = "gcs"
+
+	urnSeparator = "://"
+)
+
+var (
+	urnSchemeRegex = regexp.MustCompile(`^[a-z][a-z0-9+_-]*$`)
+
+	// destinations of bigquery written without a scheme before urns, e.g.
+	// project:dataset.table
+	legacyBigQueryURNRegex       = regexp.MustCompile(`^[\w-]+:\w+(\.[\w-]+)?$`)
+	legacyBigQueryURNPrefixRegex = regexp.MustCompile(`^[\w-]+:`)
+
+	// schemes destinations were written with before urns
+	legacyURNSchemes = map[string]string{
+		"gs": URNSchemeGCS,
+	}
+)
+
+// URN is the canonical name of a resource of any datastore, written as
+// <scheme>://<name>. Jobs refer what their tasks write to and read from with
+// URNs, so destinations of jobs match sources of others regardless of the
+// task or plugin which generated them
+type URN struct {
+	Scheme string
+	Name   string
+}
+
+func (u URN) String() string {
+	return u.Scheme + urnSeparator + u.Name
+}
+
+// ParseURN parses a canonical URN, destinations written the way they were
+// before URNs are parsed as well, project:dataset.table as a bigquery table
+// and gs://bucket/path as gcs
+func ParseURN(value string) (URN, error) {
+	value = strings.TrimSpace(value)
+	idx := strings.Index(value, urnSeparator)
+	if idx < 0 {
+		if legacyBigQueryURNRegex.MatchString(value) {
+			return URN{Scheme: URNSchemeBigQuery, Name: value}, nil
+		}
+		return URN{}, errors.Errorf("invalid urn %s, expected <scheme>://<name> like bigquery://project:dataset.table", value)
+	}
+
+	urn := URN{
+		Scheme: strings.ToLower(value[:idx]),
+		Name:   value[idx+len(urnSeparator):],
+	}
+	if scheme, ok := legacyURNSchemes[urn.Scheme]; ok {
+		urn.Scheme = scheme
+	}
+	if err := urn.Validate(); err != nil {
+		return URN{}, err
+	}
+	return urn, nil
+}
+
+// Validate fails for URNs without a scheme or name, names are validated
+// further by the datastore of their scheme if there is one
+func (u URN) Validate() error {
+	if !urnSchemeRegex.MatchString(u.Scheme) {
+		return errors.Errorf("invalid scheme of urn %s", u)
+	}
+	if u.Name == "" || strings.ContainsAny(u.Name, " \t\n") {
+		return errors.Errorf("invalid name of urn %s", u)
+	}
+	return nil
+}
+
+// CanonicalURN returns the canonical form of a destination generated by a
+// plugin, destinations which aren't URNs are returned as they are
+func CanonicalURN(destination string) string {
+	urn, err := ParseURN(destination)
+	if err != nil {
+		return strings.TrimSpace(destination)
+	}
+	return urn.String()
+}
+
+// CanonicalURNPrefix returns the canonical form of the start of a urn, e.g.
+// project:dataset. as bigquery://project:dataset., prefixes which aren't of
+// a urn are returned as they are
+func CanonicalURNPrefix(prefix string) string {
+	prefix = strings.TrimSpace(prefix)
+	idx := strings.Index(prefix, urnSeparator)
+	if idx < 0 {
+		if legacyBigQueryURNPrefixRegex.MatchString(prefix) {
+			return URNSchemeBigQuery + urnSeparator + prefix
+		}
+		return prefix
+	}
+	scheme := strings.ToLower(prefix[:idx])
+	if legacy, ok := legacyURNSchemes[scheme]; ok {
+		scheme = legacy
+	}
+	return scheme + prefix[idx:]
+}
+
+// CanonicalURNs returns canonical forms of destinations
+func CanonicalURNs(destinations []string) []string {
+	if destinations == nil {
+		return nil
+	}
+	canonical := make([]string, len(destinations))
+	for idx, destination := range destinations {
+		canonical[idx] = CanonicalURN(destination)
+	}
+	return canonical
+}
@@ -0,0 +1,49 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURN(t *testing.T) {
+	t.Run("ParseURN", func(t *testing.T) {
+		t.Run("should parse canonical urns", func(t *testing.T) {
+			urn, err := models.ParseURN("bigquery://project:dataset.table")
+			assert.Nil(t, err)
+			assert.Equal(t, models.URN{Scheme: models.URNSchemeBigQuery, Name: "project:dataset.table"}, urn)
+			assert.Equal(t, "bigquery://project:dataset.table", urn.String())
+		})
+		t.Run("should parse destinations written before urns", func(t *testing.T) {
+			urn, err := models.ParseURN(" project:dataset.table ")
+			assert.Nil(t, err)
+			assert.Equal(t, "bigquery://project:dataset.table", urn.String())
+
+			urn, err = models.ParseURN("gs://bucket/path")
+			assert.Nil(t, err)
+			assert.Equal(t, models.URN{Scheme: models.URNSchemeGCS, Name: "bucket/path"}, urn)
+		})
+		t.Run("should fail for values which aren't urns", func(t *testing.T) {
+			for _, value := range []string{"", "project.dataset.table", "bigquery://", "://name", "big query://a b"} {
+				_, err := models.ParseURN(value)
+				assert.NotNil(t, err, value)
+			}
+		})
+	})
+	t.Run("CanonicalURN", func(t *testing.T) {
+		t.Run("should return canonical form of urns and other destinations as they are", func(t *testing.T) {
+			assert.Equal(t, []string{
+				"bigquery://project:dataset.table",
+				"gcs://bucket/path",
+				"project.dataset.table",
+			}, models.CanonicalURNs([]string{"project:dataset.table", "GS://bucket/path", "project.dataset.table"}))
+		})
+		t.Run("should return canonical form of start of urns", func(t *testing.T) {
+			assert.Equal(t, "bigquery://project:dataset.", models.CanonicalURNPrefix("project:dataset."))
+			assert.Equal(t, "gcs://bucket/pa", models.CanonicalURNPrefix("gs://bucket/pa"))
+			assert.Equal(t, "bigquery://proj", models.CanonicalURNPrefix("bigquery://proj"))
+			assert.Equal(t, "proj", models.CanonicalURNPrefix("proj"))
+		})
+	})
+}
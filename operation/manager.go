@@ -0,0 +1,176 @@
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/odpf/optimus/utils"
+)
+
+var (
+	// ErrManagerClosed is returned for operations requested while the
+	// server is shutting down
+	ErrManagerClosed = errors.New("server is shutting down")
+
+	// ErrNotRunning is returned when cancelling an operation which is
+	// over or is run by another server
+	ErrNotRunning = errors.New("operation is not running on this server")
+)
+
+// Manager runs long running operations in background so that requests
+// starting them return right away. Operations are recorded in store along
+// with their progress and result, and are cancelled if the server shuts
+// down before they are over
+type Manager struct {
+	mu      sync.Mutex
+	running map[uuid.UUID]context.CancelFunc
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	repo         store.OperationRepository
+	uuidProvider utils.UUIDProvider
+
+	Now func() time.Time
+}
+
+// Start records an operation of kind requested with params and runs fn in
+// background
+func (m *Manager) Start(project models.ProjectSpec, kind, actor string, params interface{},
+	fn models.OperationFunc) (models.Operation, error) {
+	if m.ctx.Err() != nil {
+		return models.Operation{}, ErrManagerClosed
+	}
+	var paramsJSON []byte
+	if params != nil {
+		var err error
+		if paramsJSON, err = json.Marshal(params); err != nil {
+			return models.Operation{}, errors.Wrap(err, "failed to encode params of operation")
+		}
+	}
+	id, err := m.uuidProvider.NewUUID()
+	if err != nil {
+		return models.Operation{}, err
+	}
+	op := models.Operation{
+		ID:        id,
+		Kind:      kind,
+		Actor:     actor,
+		Params:    paramsJSON,
+		Status:    models.OperationStatusPending,
+		CreatedAt: m.Now().UTC(),
+	}
+	if err := m.repo.Insert(project, &op); err != nil {
+		return models.Operation{}, err
+	}
+
+	// closed is checked again along with adding to the wait group under the
+	// lock Close cancels with, so that Close doesn't wait before an operation
+	// being started is counted
+	m.mu.Lock()
+	if m.ctx.Err() != nil {
+		m.mu.Unlock()
+		m.updateStatus(op, models.OperationStatusFailed, ErrManagerClosed.Error(), nil)
+		return models.Operation{}, ErrManagerClosed
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.running[op.ID] = cancel
+	m.wg.Add(1)
+	m.mu.Unlock()
+
+	go func() {
+		defer m.wg.Done()
+		defer m.done(op.ID)
+		m.run(ctx, op, fn)
+	}()
+	return op, nil
+}
+
+func (m *Manager) run(ctx context.Context, op models.Operation, fn models.OperationFunc) {
+	m.updateStatus(op, models.OperationStatusRunning, "", nil)
+	result, err := fn(ctx, func(progress models.OperationProgress) {
+		if err := m.repo.UpdateProgress(op.ID, progress); err != nil {
+			logger.E(errors.Wrapf(err, "failed to update progress of operation %s", op.ID))
+		}
+	})
+
+	var resultJSON []byte
+	if result != nil {
+		var encodeErr error
+		if resultJSON, encodeErr = json.Marshal(result); encodeErr != nil {
+			logger.E(errors.Wrapf(encodeErr, "failed to encode result of operation %s", op.ID))
+		}
+	}
+	switch {
+	case err == nil:
+		m.updateStatus(op, models.OperationStatusSucceeded, "", resultJSON)
+	case ctx.Err() != nil && m.ctx.Err() == nil:
+		// cancelled on request rather than by shutdown
+		m.updateStatus(op, models.OperationStatusCancelled, err.Error(), resultJSON)
+	default:
+		logger.E(errors.Wrapf(err, "%s operation %s failed", op.Kind, op.ID))
+		m.updateStatus(op, models.OperationStatusFailed, err.Error(), resultJSON)
+	}
+}
+
+func (m *Manager) updateStatus(op models.Operation, status, message string, result []byte) {
+	if err := m.repo.UpdateStatus(op.ID, status, message, result); err != nil {
+		logger.E(errors.Wrapf(err, "failed to update status of operation %s", op.ID))
+	}
+}
+
+func (m *Manager) done(id uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cancel, ok := m.running[id]; ok {
+		cancel()
+		delete(m.running, id)
+	}
+}
+
+// Cancel stops an operation of project run by this server, the operation
+// records itself as cancelled once its work returns
+func (m *Manager) Cancel(project models.ProjectSpec, id uuid.UUID) error {
+	// operation should belong to the project
+	if _, err := m.repo.GetByID(project, id); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cancel, ok := m.running[id]
+	if !ok {
+		return ErrNotRunning
+	}
+	cancel()
+	return nil
+}
+
+// Close cancels ongoing operations, waiting for them to record their status
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	m.cancel()
+	m.mu.Unlock()
+	m.wg.Wait()
+	return nil
+}
+
+func NewManager(repo store.OperationRepository, uuidProvider utils.UUIDProvider) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		running:      map[uuid.UUID]context.CancelFunc{},
+		ctx:          ctx,
+		cancel:       cancel,
+		repo:         repo,
+		uuidProvider: uuidProvider,
+		Now:          time.Now,
+	}
+}
@@ -0,0 +1,110 @@
+package operation_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/operation"
+)
+
+func TestManager(t *testing.T) {
+	logger.InitWithWriter("INFO", ioutil.Discard)
+	now := time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	operationID := uuid.Must(uuid.NewRandom())
+
+	t.Run("should record operation and run it in background", func(t *testing.T) {
+		uuidProvider := new(mock.UUIDProvider)
+		uuidProvider.On("NewUUID").Return(operationID, nil)
+
+		repo := new(mock.OperationRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("Insert", projectSpec, &models.Operation{
+			ID:        operationID,
+			Kind:      models.OperationKindPromotion,
+			Actor:     "jane@example.com",
+			Params:    []byte(`{"datastore":"bigquery"}`),
+			Status:    models.OperationStatusPending,
+			CreatedAt: now,
+		}).Return(nil)
+		repo.On("UpdateStatus", operationID, models.OperationStatusRunning, "", []byte(nil)).Return(nil)
+		repo.On("UpdateProgress", operationID, models.OperationProgress{Done: 1, Total: 2}).Return(nil)
+		repo.On("UpdateStatus", operationID, models.OperationStatusFailed, "permission denied",
+			[]byte(`["playground"]`)).Return(nil)
+
+		manager := operation.NewManager(repo, uuidProvider)
+		manager.Now = func() time.Time { return now }
+		op, err := manager.Start(projectSpec, models.OperationKindPromotion, "jane@example.com",
+			map[string]string{"datastore": "bigquery"},
+			func(ctx context.Context, progress func(models.OperationProgress)) (interface{}, error) {
+				progress(models.OperationProgress{Done: 1, Total: 2})
+				return []string{"playground"}, errors.New("permission denied")
+			})
+		assert.Nil(t, err)
+		assert.Equal(t, operationID, op.ID)
+		assert.Nil(t, manager.Close())
+	})
+	t.Run("should cancel running operation on request", func(t *testing.T) {
+		uuidProvider := new(mock.UUIDProvider)
+		uuidProvider.On("NewUUID").Return(operationID, nil)
+
+		repo := new(mock.OperationRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("Insert", projectSpec, mock2.Anything).Return(nil)
+		repo.On("GetByID", projectSpec, operationID).Return(models.Operation{ID: operationID}, nil)
+		repo.On("UpdateStatus", operationID, models.OperationStatusRunning, "", []byte(nil)).Return(nil)
+		repo.On("UpdateStatus", operationID, models.OperationStatusCancelled, context.Canceled.Error(), []byte(nil)).Return(nil)
+
+		manager := operation.NewManager(repo, uuidProvider)
+		started := make(chan struct{})
+		_, err := manager.Start(projectSpec, models.OperationKindPromotion, "", nil,
+			func(ctx context.Context, progress func(models.OperationProgress)) (interface{}, error) {
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+		assert.Nil(t, err)
+		<-started
+		assert.Nil(t, manager.Cancel(projectSpec, operationID))
+		assert.Nil(t, manager.Close())
+		assert.Equal(t, operation.ErrNotRunning, manager.Cancel(projectSpec, operationID))
+	})
+	t.Run("should reject operations once closed", func(t *testing.T) {
+		manager := operation.NewManager(new(mock.OperationRepository), new(mock.UUIDProvider))
+		assert.Nil(t, manager.Close())
+		_, err := manager.Start(projectSpec, models.OperationKindPromotion, "", nil, nil)
+		assert.Equal(t, operation.ErrManagerClosed, err)
+	})
+	t.Run("should fail operations recorded while closing", func(t *testing.T) {
+		uuidProvider := new(mock.UUIDProvider)
+		uuidProvider.On("NewUUID").Return(operationID, nil)
+
+		repo := new(mock.OperationRepository)
+		defer repo.AssertExpectations(t)
+		manager := operation.NewManager(repo, uuidProvider)
+		repo.On("Insert", projectSpec, mock2.Anything).Run(func(args mock2.Arguments) {
+			assert.Nil(t, manager.Close())
+		}).Return(nil)
+		repo.On("UpdateStatus", operationID, models.OperationStatusFailed, operation.ErrManagerClosed.Error(), []byte(nil)).Return(nil)
+
+		_, err := manager.Start(projectSpec, models.OperationKindPromotion, "", nil,
+			func(ctx context.Context, progress func(models.OperationProgress)) (interface{}, error) {
+				t.Error("operation should not run once closed")
+				return nil, nil
+			})
+		assert.Equal(t, operation.ErrManagerClosed, err)
+	})
+}
@@ -2,6 +2,7 @@ package base
 
 import (
 	hplugin "github.com/hashicorp/go-plugin"
+	pbp "github.com/odpf/optimus/api/proto/odpf/optimus/plugins"
 )
 
 const (
@@ -17,6 +18,11 @@ const (
 	MagicCookieValue = "ksxR4BqCT81whVF2dVEUpYZXwM3pazSkP4IbVc6f2Kns57ypp2c0z0GzQNMdHSUk"
 )
 
+// PluginModConfigValidator is the config validator mod in plugin info, it is
+// not part of plugin protos yet but enums are open in proto3 so the value
+// reaches core as is
+const PluginModConfigValidator pbp.PluginMod = 3
+
 var (
 	// Handshake is used to just do a basic handshake between
 	// a plugin and host. If the handshake fails, a user friendly error is shown.
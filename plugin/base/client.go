@@ -46,6 +46,8 @@ func (m *GRPCClient) PluginInfo() (*models.PluginInfoResponse, error) {
 			mtype = append(mtype, models.ModTypeCLI)
 		case pbp.PluginMod_PluginMod_DEPENDENCYRESOLVER:
 			mtype = append(mtype, models.ModTypeDependencyResolver)
+		case PluginModConfigValidator:
+			mtype = append(mtype, models.ModTypeConfigValidator)
 		default:
 			return nil, fmt.Errorf("plugin mod is of unknown type: %q", mod.String())
 		}
@@ -36,6 +36,8 @@ func (s *GRPCServer) PluginInfo(ctx context.Context, req *pbp.PluginInfoRequest)
 			mtype = append(mtype, pbp.PluginMod_PluginMod_CLI)
 		case models.ModTypeDependencyResolver:
 			mtype = append(mtype, pbp.PluginMod_PluginMod_DEPENDENCYRESOLVER)
+		case models.ModTypeConfigValidator:
+			mtype = append(mtype, PluginModConfigValidator)
 		default:
 			return nil, fmt.Errorf("plugin mod is of unknown type: %s", mod)
 		}
@@ -0,0 +1,33 @@
+package configvalidator
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/plugin/base"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// GRPCClient will be used by core to talk over grpc with plugins
+type GRPCClient struct {
+	conn *grpc.ClientConn
+
+	baseClient *base.GRPCClient
+}
+
+func (m *GRPCClient) PluginInfo() (*models.PluginInfoResponse, error) {
+	return m.baseClient.PluginInfo()
+}
+
+func (m *GRPCClient) ValidateConfig(ctx context.Context, request models.ValidateConfigRequest) (*models.ValidateConfigResponse, error) {
+	req, err := adaptRequestToStruct(request)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(structpb.Struct)
+	if err := m.conn.Invoke(ctx, validateConfigMethod, req, resp); err != nil {
+		return nil, err
+	}
+	return adaptResponseFromStruct(resp), nil
+}
@@ -0,0 +1,78 @@
+package configvalidator
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/plugin/base"
+	"github.com/odpf/optimus/plugin/cli"
+	"github.com/odpf/optimus/plugin/dependencyresolver"
+
+	hplugin "github.com/hashicorp/go-plugin"
+	pbp "github.com/odpf/optimus/api/proto/odpf/optimus/plugins"
+	"google.golang.org/grpc"
+)
+
+var _ hplugin.GRPCPlugin = &Connector{}
+
+type Connector struct {
+	hplugin.NetRPCUnsupportedPlugin
+	hplugin.GRPCPlugin
+
+	impl models.ConfigValidatorMod
+
+	logger hclog.Logger
+}
+
+func (p *Connector) GRPCServer(broker *hplugin.GRPCBroker, s *grpc.Server) error {
+	s.RegisterService(&serviceDesc, &GRPCServer{
+		Impl: p.impl,
+	})
+	return nil
+}
+
+func (p *Connector) GRPCClient(ctx context.Context, broker *hplugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &GRPCClient{
+		conn: c,
+		baseClient: &base.GRPCClient{
+			Client: pbp.NewBaseClient(c),
+			Logger: p.logger,
+		},
+	}, nil
+}
+
+func NewPlugin(impl models.ConfigValidatorMod, logger hclog.Logger) *Connector {
+	return &Connector{
+		impl:   impl,
+		logger: logger,
+	}
+}
+
+func NewPluginClient(logger hclog.Logger) *Connector {
+	return &Connector{
+		logger: logger,
+	}
+}
+
+// Serve serves the validator along with cli and dependency resolver mods
+// if the plugin implements them
+func Serve(t models.ConfigValidatorMod, logger hclog.Logger) {
+	mp := map[string]plugin.Plugin{
+		models.PluginTypeBase:                  base.NewPlugin(t, logger),
+		models.ModTypeConfigValidator.String(): NewPlugin(t, logger),
+	}
+	if c, ok := t.(models.CommandLineMod); ok {
+		mp[models.ModTypeCLI.String()] = cli.NewPlugin(c, logger)
+	}
+	if d, ok := t.(models.DependencyResolverMod); ok {
+		mp[models.ModTypeDependencyResolver.String()] = dependencyresolver.NewPlugin(d, logger)
+	}
+	hplugin.Serve(&hplugin.ServeConfig{
+		HandshakeConfig: base.Handshake,
+		Plugins:         mp,
+		GRPCServer:      plugin.DefaultGRPCServer,
+		Logger:          logger,
+	})
+}
@@ -0,0 +1,28 @@
+package configvalidator
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// GRPCServer will be used by plugins this is working as proto adapter
+type GRPCServer struct {
+	// This is the real implementation coming from plugin
+	Impl models.ConfigValidatorMod
+}
+
+func (s *GRPCServer) ValidateConfig(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	request, err := adaptRequestFromStruct(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp, err := s.Impl.ValidateConfig(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return adaptResponseToStruct(resp)
+}
@@ -0,0 +1,115 @@
+package configvalidator
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// the mod is not part of plugin protos yet, so its messages are carried as
+// structs over a service described here the way protoc would generate it
+
+const (
+	serviceName          = "odpf.optimus.plugins.ConfigValidatorMod"
+	validateConfigMethod = "/" + serviceName + "/ValidateConfig"
+)
+
+type validatorServer interface {
+	ValidateConfig(context.Context, *structpb.Struct) (*structpb.Struct, error)
+}
+
+func validateConfigHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(structpb.Struct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(validatorServer).ValidateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: validateConfigMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(validatorServer).ValidateConfig(ctx, req.(*structpb.Struct))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*validatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ValidateConfig",
+			Handler:    validateConfigHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func adaptRequestToStruct(req models.ValidateConfigRequest) (*structpb.Struct, error) {
+	var config, assets []interface{}
+	for _, c := range req.Config {
+		config = append(config, map[string]interface{}{"name": c.Name, "value": c.Value})
+	}
+	for _, a := range req.Assets {
+		assets = append(assets, map[string]interface{}{"name": a.Name, "value": a.Value})
+	}
+	return structpb.NewStruct(map[string]interface{}{
+		"config":  config,
+		"assets":  assets,
+		"dry_run": req.DryRun,
+	})
+}
+
+func adaptRequestFromStruct(st *structpb.Struct) (models.ValidateConfigRequest, error) {
+	var req models.ValidateConfigRequest
+	fields := st.GetFields()
+	for _, item := range fields["config"].GetListValue().GetValues() {
+		name, value, err := nameValueFromStruct(item)
+		if err != nil {
+			return req, errors.Wrap(err, "invalid config")
+		}
+		req.Config = append(req.Config, models.PluginConfig{Name: name, Value: value})
+	}
+	for _, item := range fields["assets"].GetListValue().GetValues() {
+		name, value, err := nameValueFromStruct(item)
+		if err != nil {
+			return req, errors.Wrap(err, "invalid asset")
+		}
+		req.Assets = append(req.Assets, models.PluginAsset{Name: name, Value: value})
+	}
+	req.DryRun = fields["dry_run"].GetBoolValue()
+	return req, nil
+}
+
+func nameValueFromStruct(v *structpb.Value) (string, string, error) {
+	item := v.GetStructValue().GetFields()
+	name := item["name"].GetStringValue()
+	if name == "" {
+		return "", "", errors.New("name is required")
+	}
+	return name, item["value"].GetStringValue(), nil
+}
+
+func adaptResponseToStruct(resp *models.ValidateConfigResponse) (*structpb.Struct, error) {
+	var errs []interface{}
+	for _, e := range resp.Errors {
+		errs = append(errs, e)
+	}
+	return structpb.NewStruct(map[string]interface{}{
+		"errors": errs,
+	})
+}
+
+func adaptResponseFromStruct(st *structpb.Struct) *models.ValidateConfigResponse {
+	resp := &models.ValidateConfigResponse{}
+	for _, e := range st.GetFields()["errors"].GetListValue().GetValues() {
+		resp.Errors = append(resp.Errors, e.GetStringValue())
+	}
+	return resp
+}
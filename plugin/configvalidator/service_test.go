@@ -0,0 +1,42 @@
+package configvalidator
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdapter(t *testing.T) {
+	t.Run("should keep request intact through structs", func(t *testing.T) {
+		req := models.ValidateConfigRequest{
+			Config: models.PluginConfigs{
+				{Name: "TABLE", Value: "orders"},
+				{Name: "LOAD_METHOD", Value: "APPEND"},
+			},
+			Assets: models.PluginAssets{
+				{Name: "query.sql", Value: "select * from `shop.orders`"},
+			},
+			PluginOptions: models.PluginOptions{DryRun: true},
+		}
+		st, err := adaptRequestToStruct(req)
+		assert.Nil(t, err)
+		adapted, err := adaptRequestFromStruct(st)
+		assert.Nil(t, err)
+		assert.Equal(t, req, adapted)
+	})
+	t.Run("should keep response intact through structs", func(t *testing.T) {
+		resp := &models.ValidateConfigResponse{Errors: []string{"TABLE is required"}}
+		st, err := adaptResponseToStruct(resp)
+		assert.Nil(t, err)
+		assert.Equal(t, resp, adaptResponseFromStruct(st))
+	})
+	t.Run("should fail for config without name", func(t *testing.T) {
+		st, err := adaptRequestToStruct(models.ValidateConfigRequest{
+			Config: models.PluginConfigs{{Value: "orders"}},
+		})
+		assert.Nil(t, err)
+		_, err = adaptRequestFromStruct(st)
+		assert.Equal(t, "invalid config: name is required", err.Error())
+	})
+}
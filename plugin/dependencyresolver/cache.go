@@ -0,0 +1,142 @@
+package dependencyresolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/odpf/optimus/models"
+)
+
+var (
+	// DefaultCacheTTL is how long a plugin response is reused, plugins may
+	// depend on external state like views of a datastore so it is kept short
+	DefaultCacheTTL = time.Minute * 10
+
+	// DefaultCacheSize is max number of responses kept per plugin
+	DefaultCacheSize = 10000
+)
+
+// CachedClient memoizes responses of a dependency resolver mod keyed by hash
+// of the request, i.e. configs, assets and project of a job spec.
+// Deploying a project repeatedly asks destinations and dependencies of
+// every job even if only a few of them are changed.
+type CachedClient struct {
+	models.DependencyResolverMod
+
+	destinations *responseCache
+	dependencies *responseCache
+}
+
+func (c *CachedClient) GenerateDestination(ctx context.Context, request models.GenerateDestinationRequest) (*models.GenerateDestinationResponse, error) {
+	key, err := requestHash(request)
+	if err != nil {
+		return c.DependencyResolverMod.GenerateDestination(ctx, request)
+	}
+	if cached, ok := c.destinations.get(key); ok {
+		resp := cached.(models.GenerateDestinationResponse)
+		return &resp, nil
+	}
+
+	resp, err := c.DependencyResolverMod.GenerateDestination(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	c.destinations.set(key, *resp)
+	return resp, nil
+}
+
+func (c *CachedClient) GenerateDependencies(ctx context.Context, request models.GenerateDependenciesRequest) (*models.GenerateDependenciesResponse, error) {
+	key, err := requestHash(request)
+	if err != nil {
+		return c.DependencyResolverMod.GenerateDependencies(ctx, request)
+	}
+	if cached, ok := c.dependencies.get(key); ok {
+		resp := cached.(models.GenerateDependenciesResponse)
+		// callers own the returned slice
+		resp.Dependencies = append([]string(nil), resp.Dependencies...)
+		return &resp, nil
+	}
+
+	resp, err := c.DependencyResolverMod.GenerateDependencies(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	c.dependencies.set(key, models.GenerateDependenciesResponse{
+		Dependencies: append([]string(nil), resp.Dependencies...),
+	})
+	return resp, nil
+}
+
+func requestHash(request interface{}) (string, error) {
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type cacheItem struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// responseCache is a size bounded ttl cache, oldest inserted items are
+// evicted first once it is full
+type responseCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	size  int
+	items map[string]cacheItem
+	keys  []string
+
+	now func() time.Time
+}
+
+func (r *responseCache) get(key string) (interface{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[key]
+	if !ok || r.now().After(item.expiresAt) {
+		return nil, false
+	}
+	return item.value, true
+}
+
+func (r *responseCache) set(key string, value interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[key]; !ok {
+		for len(r.keys) >= r.size {
+			delete(r.items, r.keys[0])
+			r.keys = r.keys[1:]
+		}
+		r.keys = append(r.keys, key)
+	}
+	r.items[key] = cacheItem{
+		value:     value,
+		expiresAt: r.now().Add(r.ttl),
+	}
+}
+
+func newResponseCache(ttl time.Duration, size int) *responseCache {
+	return &responseCache{
+		ttl:   ttl,
+		size:  size,
+		items: map[string]cacheItem{},
+		now:   time.Now,
+	}
+}
+
+// NewCachedClient wraps mod with a cache of its responses
+func NewCachedClient(mod models.DependencyResolverMod, ttl time.Duration, size int) *CachedClient {
+	return &CachedClient{
+		DependencyResolverMod: mod,
+		destinations:          newResponseCache(ttl, size),
+		dependencies:          newResponseCache(ttl, size),
+	}
+}
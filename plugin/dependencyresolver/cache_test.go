@@ -0,0 +1,97 @@
+package dependencyresolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/plugin/dependencyresolver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedClient(t *testing.T) {
+	ctx := context.Background()
+	projectSpec := models.ProjectSpec{
+		Name: "a-data-project",
+	}
+	destinationRequest := func(table string) models.GenerateDestinationRequest {
+		return models.GenerateDestinationRequest{
+			Config:  models.PluginConfigs{{Name: "TABLE", Value: table}},
+			Assets:  models.PluginAssets{{Name: "query.sql", Value: "select * from 1"}},
+			Project: projectSpec,
+		}
+	}
+
+	t.Run("GenerateDestination", func(t *testing.T) {
+		t.Run("should call plugin once for the same request", func(t *testing.T) {
+			depMod := new(mock.DependencyResolverMod)
+			defer depMod.AssertExpectations(t)
+			depMod.On("GenerateDestination", ctx, destinationRequest("tab1")).
+				Return(&models.GenerateDestinationResponse{Destination: "p.d.tab1"}, nil).Once()
+			depMod.On("GenerateDestination", ctx, destinationRequest("tab2")).
+				Return(&models.GenerateDestinationResponse{Destination: "p.d.tab2"}, nil).Once()
+
+			client := dependencyresolver.NewCachedClient(depMod, time.Minute, 10)
+			for i := 0; i < 3; i++ {
+				resp, err := client.GenerateDestination(ctx, destinationRequest("tab1"))
+				assert.Nil(t, err)
+				assert.Equal(t, "p.d.tab1", resp.Destination)
+			}
+			resp, err := client.GenerateDestination(ctx, destinationRequest("tab2"))
+			assert.Nil(t, err)
+			assert.Equal(t, "p.d.tab2", resp.Destination)
+		})
+		t.Run("should not cache errors", func(t *testing.T) {
+			depMod := new(mock.DependencyResolverMod)
+			defer depMod.AssertExpectations(t)
+			depMod.On("GenerateDestination", ctx, destinationRequest("tab1")).
+				Return(&models.GenerateDestinationResponse{}, errors.New("plugin crashed")).Twice()
+
+			client := dependencyresolver.NewCachedClient(depMod, time.Minute, 10)
+			for i := 0; i < 2; i++ {
+				_, err := client.GenerateDestination(ctx, destinationRequest("tab1"))
+				assert.Equal(t, "plugin crashed", err.Error())
+			}
+		})
+		t.Run("should evict oldest responses when cache is full", func(t *testing.T) {
+			depMod := new(mock.DependencyResolverMod)
+			defer depMod.AssertExpectations(t)
+			depMod.On("GenerateDestination", ctx, destinationRequest("tab1")).
+				Return(&models.GenerateDestinationResponse{Destination: "p.d.tab1"}, nil).Twice()
+			depMod.On("GenerateDestination", ctx, destinationRequest("tab2")).
+				Return(&models.GenerateDestinationResponse{Destination: "p.d.tab2"}, nil).Once()
+
+			client := dependencyresolver.NewCachedClient(depMod, time.Minute, 1)
+			for _, table := range []string{"tab1", "tab2", "tab1"} {
+				_, err := client.GenerateDestination(ctx, destinationRequest(table))
+				assert.Nil(t, err)
+			}
+		})
+	})
+	t.Run("GenerateDependencies", func(t *testing.T) {
+		t.Run("should call plugin again once the response is expired", func(t *testing.T) {
+			request := models.GenerateDependenciesRequest{
+				Config:  models.PluginConfigs{{Name: "TABLE", Value: "tab1"}},
+				Project: projectSpec,
+			}
+			depMod := new(mock.DependencyResolverMod)
+			defer depMod.AssertExpectations(t)
+			depMod.On("GenerateDependencies", ctx, request).
+				Return(&models.GenerateDependenciesResponse{Dependencies: []string{"p.d.tab0"}}, nil).Twice()
+
+			client := dependencyresolver.NewCachedClient(depMod, time.Millisecond*50, 10)
+			for i := 0; i < 2; i++ {
+				resp, err := client.GenerateDependencies(ctx, request)
+				assert.Nil(t, err)
+				assert.Equal(t, []string{"p.d.tab0"}, resp.Dependencies)
+			}
+			time.Sleep(time.Millisecond * 60)
+			resp, err := client.GenerateDependencies(ctx, request)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"p.d.tab0"}, resp.Dependencies)
+		})
+	})
+}
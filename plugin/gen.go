@@ -56,21 +56,10 @@ ENTRYPOINT ["/opt/entrypoint.sh"]
 # wait for few seconds to prepare scheduler for the run
 sleep 5
 
-# get resources
-echo "-- initializing optimus assets"
-OPTIMUS_ADMIN_ENABLED=1 /opt/optimus admin build instance $JOB_NAME --project $PROJECT --output-dir $JOB_DIR --type $TASK_TYPE --name $TASK_NAME --scheduled-at $SCHEDULED_AT --host $OPTIMUS_HOSTNAME
-
-# TODO: this doesnt support using back quote sign in env vars, fix it
-echo "-- exporting env"
-set -o allexport
-source $JOB_DIR/in/.env
-set +o allexport
-
-echo "-- current envs"
-printenv
-
-echo "-- running unit"
-exec $(eval echo "$@")
+# register the run, get its resources and run the unit reporting heartbeats
+# and state of it to optimus
+export OPTIMUS_ADMIN_ENABLED=1
+exec /opt/optimus admin exec $JOB_NAME --project $PROJECT --output-dir $JOB_DIR --type $TASK_TYPE --name $TASK_NAME --scheduled-at $SCHEDULED_AT --host $OPTIMUS_HOSTNAME -- "$@"
 `
 
 	BinaryNameFormat = "optimus-%s_%s_%s"
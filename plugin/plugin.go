@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/odpf/optimus/plugin/configvalidator"
 	"github.com/odpf/optimus/plugin/dependencyresolver"
 
 	"github.com/odpf/optimus/plugin/cli"
@@ -35,6 +36,7 @@ func Initialize(pluginLogger hclog.Logger) error {
 		models.PluginTypeBase:                     base.NewPluginClient(pluginLogger),
 		models.ModTypeCLI.String():                cli.NewPluginClient(pluginLogger),
 		models.ModTypeDependencyResolver.String(): dependencyresolver.NewPluginClient(pluginLogger),
+		models.ModTypeConfigValidator.String():    configvalidator.NewPluginClient(pluginLogger),
 	}
 
 	for _, pluginPath := range discoveredPlugins {
@@ -57,6 +59,7 @@ func Initialize(pluginLogger hclog.Logger) error {
 		var baseClient models.BasePlugin
 		var cliClient models.CommandLineMod
 		var drClient models.DependencyResolverMod
+		var cvClient models.ConfigValidatorMod
 
 		// request plugin as base
 		raw, err := rpcClient.Dispense(models.PluginTypeBase)
@@ -88,10 +91,22 @@ func Initialize(pluginLogger hclog.Logger) error {
 				// cache name
 				drGRPCClient := rawMod.(*dependencyresolver.GRPCClient)
 				drGRPCClient.SetName(baseInfo.Name)
+
+				// reuse responses of unchanged jobs across deployments
+				drClient = dependencyresolver.NewCachedClient(drClient, dependencyresolver.DefaultCacheTTL,
+					dependencyresolver.DefaultCacheSize)
+			}
+		}
+
+		if modSupported(baseInfo.PluginMods, models.ModTypeConfigValidator) {
+			// create a client with config validator mod
+			if rawMod, err := rpcClient.Dispense(models.ModTypeConfigValidator.String()); err == nil {
+				cvClient = rawMod.(models.ConfigValidatorMod)
+				pluginLogger.Debug(fmt.Sprintf("%s mod found for: %s", models.ModTypeConfigValidator, baseInfo.Name))
 			}
 		}
 
-		if err := models.PluginRegistry.Add(baseClient, cliClient, drClient); err != nil {
+		if err := models.PluginRegistry.Add(baseClient, cliClient, drClient, cvClient); err != nil {
 			return errors.Wrapf(err, "PluginRegistry.Add: %s", pluginPath)
 		}
 		pluginLogger.Debug("plugin ready: ", baseInfo.Name)
@@ -216,6 +231,8 @@ func Serve(f Factory) {
 
 func servePlugin(plugin interface{}, logger hclog.Logger) {
 	switch p := plugin.(type) {
+	case models.ConfigValidatorMod:
+		configvalidator.Serve(p, logger)
 	case models.DependencyResolverMod:
 		if cliPlugin, ok := plugin.(models.CommandLineMod); ok {
 			dependencyresolver.ServeWithCLI(p, cliPlugin, logger)
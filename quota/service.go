@@ -0,0 +1,110 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+)
+
+// Service enforces quota of projects, quota stored for a project takes
+// precedence over the default one
+type Service struct {
+	repo     store.ProjectQuotaRepository
+	defaults models.ProjectQuota
+	Now      func() time.Time
+}
+
+func (s *Service) Get(proj models.ProjectSpec) (models.ProjectQuota, error) {
+	quota, err := s.repo.GetByProject(proj)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return s.defaults, nil
+		}
+		return models.ProjectQuota{}, errors.Wrapf(err, "failed to get quota of project %s", proj.Name)
+	}
+	return quota, nil
+}
+
+func (s *Service) Update(proj models.ProjectSpec, quota models.ProjectQuota) error {
+	if quota.MaxJobs < 0 || quota.MaxResources < 0 || quota.MaxReplayRunsPerDay < 0 {
+		return errors.New("quota limits cannot be negative")
+	}
+	if err := s.repo.Save(proj, quota); err != nil {
+		return errors.Wrapf(err, "failed to update quota of project %s", proj.Name)
+	}
+	return nil
+}
+
+func (s *Service) CheckJobs(proj models.ProjectSpec, jobs int) error {
+	quota, err := s.Get(proj)
+	if err != nil {
+		return err
+	}
+	return checkLimit(proj, "jobs", quota.MaxJobs, jobs)
+}
+
+func (s *Service) CheckResources(proj models.ProjectSpec, resources int) error {
+	quota, err := s.Get(proj)
+	if err != nil {
+		return err
+	}
+	return checkLimit(proj, "resources", quota.MaxResources, resources)
+}
+
+// ReserveReplayRuns checks the daily replay quota and adds runs to usage in
+// one operation of the repository, so concurrent replays of a project can't
+// exceed the quota together
+func (s *Service) ReserveReplayRuns(proj models.ProjectSpec, runs int) error {
+	quota, err := s.Get(proj)
+	if err != nil {
+		return err
+	}
+	now := s.Now()
+	if quota.MaxReplayRunsPerDay == 0 {
+		if err := s.repo.AddReplayRuns(proj, now, runs); err != nil {
+			return errors.Wrapf(err, "failed to record replay usage of project %s", proj.Name)
+		}
+		return nil
+	}
+	reserved, err := s.repo.ReserveReplayRuns(proj, now, runs, quota.MaxReplayRunsPerDay)
+	if err != nil {
+		return errors.Wrapf(err, "failed to record replay usage of project %s", proj.Name)
+	}
+	if reserved {
+		return nil
+	}
+	used, err := s.repo.GetReplayRuns(proj, now)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get replay usage of project %s", proj.Name)
+	}
+	return errors.Wrapf(models.ErrQuotaExceeded, "project %s can replay at most %d runs a day, %d already replayed today, requested %d",
+		proj.Name, quota.MaxReplayRunsPerDay, used, runs)
+}
+
+func (s *Service) ReleaseReplayRuns(proj models.ProjectSpec, runs int) error {
+	if err := s.repo.AddReplayRuns(proj, s.Now(), -runs); err != nil {
+		return errors.Wrapf(err, "failed to release replay usage of project %s", proj.Name)
+	}
+	return nil
+}
+
+func checkLimit(proj models.ProjectSpec, kind string, limit, requested int) error {
+	if limit == 0 || requested <= limit {
+		return nil
+	}
+	return errors.Wrapf(models.ErrQuotaExceeded, "project %s can have at most %d %s, requested %d",
+		proj.Name, limit, kind, requested)
+}
+
+func NewService(repo store.ProjectQuotaRepository, defaults models.ProjectQuota) *Service {
+	return &Service{
+		repo:     repo,
+		defaults: defaults,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package quota_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/quota"
+	"github.com/odpf/optimus/store"
+)
+
+func TestService(t *testing.T) {
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	defaults := models.ProjectQuota{
+		MaxJobs:             100,
+		MaxResources:        200,
+		MaxReplayRunsPerDay: 50,
+	}
+	now := time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC)
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("should return default quota if project doesn't have one", func(t *testing.T) {
+			repo := new(mock.ProjectQuotaRepository)
+			repo.On("GetByProject", projectSpec).Return(models.ProjectQuota{}, store.ErrResourceNotFound)
+			defer repo.AssertExpectations(t)
+
+			q, err := quota.NewService(repo, defaults).Get(projectSpec)
+			assert.Nil(t, err)
+			assert.Equal(t, defaults, q)
+		})
+	})
+	t.Run("Update", func(t *testing.T) {
+		t.Run("should fail if any limit is negative", func(t *testing.T) {
+			err := quota.NewService(nil, defaults).Update(projectSpec, models.ProjectQuota{MaxJobs: -1})
+			assert.Equal(t, "quota limits cannot be negative", err.Error())
+		})
+	})
+	t.Run("CheckJobs", func(t *testing.T) {
+		t.Run("should fail if jobs exceed project quota", func(t *testing.T) {
+			repo := new(mock.ProjectQuotaRepository)
+			repo.On("GetByProject", projectSpec).Return(models.ProjectQuota{MaxJobs: 2}, nil)
+			defer repo.AssertExpectations(t)
+
+			svc := quota.NewService(repo, defaults)
+			assert.Nil(t, svc.CheckJobs(projectSpec, 2))
+			err := svc.CheckJobs(projectSpec, 3)
+			assert.True(t, errors.Is(err, models.ErrQuotaExceeded))
+			assert.Equal(t, "project a-data-project can have at most 2 jobs, requested 3: project quota exceeded", err.Error())
+		})
+		t.Run("should allow any number of jobs if limit is zero", func(t *testing.T) {
+			repo := new(mock.ProjectQuotaRepository)
+			repo.On("GetByProject", projectSpec).Return(models.ProjectQuota{}, nil)
+			defer repo.AssertExpectations(t)
+
+			assert.Nil(t, quota.NewService(repo, defaults).CheckJobs(projectSpec, 1000))
+		})
+	})
+	t.Run("ReserveReplayRuns", func(t *testing.T) {
+		t.Run("should reserve runs within daily quota", func(t *testing.T) {
+			repo := new(mock.ProjectQuotaRepository)
+			repo.On("GetByProject", projectSpec).Return(models.ProjectQuota{}, store.ErrResourceNotFound)
+			repo.On("ReserveReplayRuns", projectSpec, now, 10, defaults.MaxReplayRunsPerDay).Return(true, nil)
+			defer repo.AssertExpectations(t)
+
+			svc := quota.NewService(repo, defaults)
+			svc.Now = func() time.Time { return now }
+			assert.Nil(t, svc.ReserveReplayRuns(projectSpec, 10))
+		})
+		t.Run("should fail with runs already replayed today if quota is exceeded", func(t *testing.T) {
+			repo := new(mock.ProjectQuotaRepository)
+			repo.On("GetByProject", projectSpec).Return(models.ProjectQuota{}, store.ErrResourceNotFound)
+			repo.On("ReserveReplayRuns", projectSpec, now, 11, defaults.MaxReplayRunsPerDay).Return(false, nil)
+			repo.On("GetReplayRuns", projectSpec, now).Return(40, nil)
+			defer repo.AssertExpectations(t)
+
+			svc := quota.NewService(repo, defaults)
+			svc.Now = func() time.Time { return now }
+			err := svc.ReserveReplayRuns(projectSpec, 11)
+			assert.True(t, errors.Is(err, models.ErrQuotaExceeded))
+			assert.Contains(t, err.Error(), "40 already replayed today")
+		})
+		t.Run("should only record runs if replays are unlimited", func(t *testing.T) {
+			repo := new(mock.ProjectQuotaRepository)
+			repo.On("GetByProject", projectSpec).Return(models.ProjectQuota{}, nil)
+			repo.On("AddReplayRuns", projectSpec, now, 7).Return(nil)
+			defer repo.AssertExpectations(t)
+
+			svc := quota.NewService(repo, defaults)
+			svc.Now = func() time.Time { return now }
+			assert.Nil(t, svc.ReserveReplayRuns(projectSpec, 7))
+		})
+	})
+	t.Run("ReleaseReplayRuns", func(t *testing.T) {
+		t.Run("should remove runs from today's usage", func(t *testing.T) {
+			repo := new(mock.ProjectQuotaRepository)
+			repo.On("AddReplayRuns", projectSpec, now, -7).Return(nil)
+			defer repo.AssertExpectations(t)
+
+			svc := quota.NewService(repo, defaults)
+			svc.Now = func() time.Time { return now }
+			assert.Nil(t, svc.ReleaseReplayRuns(projectSpec, 7))
+		})
+	})
+}
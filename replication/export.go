@@ -0,0 +1,164 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/store"
+)
+
+const (
+	// ExportPath serves records of projects of an instance
+	ExportPath = "/admin/replication/export"
+
+	ndjsonContentType = "application/x-ndjson"
+)
+
+// Source returns records of projects of the instance replicated from
+type Source interface {
+	Export(ctx context.Context) ([]Record, error)
+}
+
+// ExportHandler serves records of projects as newline delimited json on
+// GET, of the project in project query param or of all projects if it is
+// not set. Records are sent with the checksum they were last replicated
+// with to this instance, so the instance applying them can tell its own
+// changes which weren't replicated here from the ones made here
+type ExportHandler struct {
+	store  Store
+	states store.ReplicationStateRepository
+}
+
+func (h *ExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectNames := []string{r.URL.Query().Get("project")}
+	if projectNames[0] == "" {
+		var err error
+		if projectNames, err = h.store.Projects(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// projects are read before anything is sent so a failure fails the
+	// whole export instead of cutting it short
+	var records []Record
+	for _, projectName := range projectNames {
+		exported, err := h.export(projectName)
+		if err != nil {
+			http.Error(w, errors.Wrapf(err, "failed to export project %s", projectName).Error(),
+				http.StatusInternalServerError)
+			return
+		}
+		records = append(records, exported...)
+	}
+
+	w.Header().Set("Content-Type", ndjsonContentType)
+	enc := json.NewEncoder(w)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return
+		}
+	}
+}
+
+func (h *ExportHandler) export(projectName string) ([]Record, error) {
+	records, err := h.store.Export(projectName)
+	if err != nil || len(records) == 0 {
+		return records, err
+	}
+	states, err := h.states.GetAll(projectName)
+	if err != nil {
+		return nil, err
+	}
+	bases := map[string]string{}
+	for _, state := range states {
+		bases[state.Key] = state.Checksum
+	}
+	for idx := range records {
+		records[idx].Base = bases[records[idx].Key()]
+	}
+	return records, nil
+}
+
+func NewExportHandler(store Store, states store.ReplicationStateRepository) *ExportHandler {
+	return &ExportHandler{
+		store:  store,
+		states: states,
+	}
+}
+
+// httpSource reads records from the export endpoint of an instance
+type httpSource struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (s *httpSource) Export(ctx context.Context) ([]Record, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request export")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, errors.Errorf("failed to request export, status: %d, response: %s", resp.StatusCode, string(body))
+	}
+
+	var records []Record
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			if err == io.EOF {
+				return records, nil
+			}
+			return nil, errors.Wrap(err, "failed to read export")
+		}
+		records = append(records, rec)
+	}
+}
+
+// NewHTTPSource reads records of all projects of the instance served at
+// primaryURL, e.g. https://optimus.primary.example.io, with the api token
+// if it is set. Reads taking longer than timeout fail, zero means no timeout
+func NewHTTPSource(primaryURL, token string, timeout time.Duration) (Source, error) {
+	u, err := url.Parse(primaryURL)
+	if err != nil || u.Host == "" {
+		return nil, errors.Errorf("invalid url of primary instance %s", primaryURL)
+	}
+	return &httpSource{
+		url:    fmt.Sprintf("%s%s", strings.TrimSuffix(primaryURL, "/"), ExportPath),
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// projectRecords groups records by their project
+func projectRecords(records []Record) map[string][]Record {
+	byProject := map[string][]Record{}
+	for _, rec := range records {
+		byProject[rec.Project] = append(byProject[rec.Project], rec)
+	}
+	return byProject
+}
@@ -0,0 +1,79 @@
+package replication
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+const (
+	KindProject   = "project"
+	KindNamespace = "namespace"
+	KindResource  = "resource"
+	KindJob       = "job"
+)
+
+// kindOrder applies projects before their namespaces and resources before
+// jobs which may refer to them
+var kindOrder = map[string]int{
+	KindProject:   0,
+	KindNamespace: 1,
+	KindResource:  2,
+	KindJob:       3,
+}
+
+// Record is the metadata of a project, one of its namespaces, resources or
+// jobs as replicated between instances
+type Record struct {
+	Kind      string `json:"kind"`
+	Project   string `json:"project"`
+	Namespace string `json:"namespace,omitempty"`
+	Datastore string `json:"datastore,omitempty"`
+	Name      string `json:"name"`
+
+	// Spec is json encoded config of projects and namespaces, resources
+	// and jobs are serialized the way runtime api sends them
+	Spec     []byte `json:"spec"`
+	Checksum string `json:"checksum"`
+	// Base is the checksum of the record on the instance it was last
+	// replicated from by the instance exporting it, empty if the record
+	// was never replicated to it
+	Base string `json:"base,omitempty"`
+}
+
+// Key identifies the record within its project
+func (r Record) Key() string {
+	switch r.Kind {
+	case KindNamespace:
+		return strings.Join([]string{r.Kind, r.Name}, "/")
+	case KindResource:
+		return strings.Join([]string{r.Kind, r.Namespace, r.Datastore, r.Name}, "/")
+	case KindJob:
+		return strings.Join([]string{r.Kind, r.Namespace, r.Name}, "/")
+	}
+	return r.Kind
+}
+
+func newRecord(kind, project, namespace, datastore, name string, spec []byte) Record {
+	sum := sha256.Sum256(spec)
+	return Record{
+		Kind:      kind,
+		Project:   project,
+		Namespace: namespace,
+		Datastore: datastore,
+		Name:      name,
+		Spec:      spec,
+		Checksum:  hex.EncodeToString(sum[:]),
+	}
+}
+
+// sortRecords orders records in the order they can be applied
+func sortRecords(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if kindOrder[records[i].Kind] != kindOrder[records[j].Kind] {
+			return kindOrder[records[i].Kind] < kindOrder[records[j].Kind]
+		}
+		return records[i].Key() < records[j].Key()
+	})
+}
@@ -0,0 +1,333 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+)
+
+const (
+	conflictChangedHere       = "changed on this instance since it was last replicated"
+	conflictChangedBoth       = "changed on both instances since it was last replicated"
+	conflictDeletedThere      = "deleted on the instance replicated from, but changed on this instance"
+	conflictExistsOnlyHere    = "exists only on this instance"
+	conflictNotReplicatedHere = "changed on this instance and not replicated to the other one"
+)
+
+// Conflict is a record which is left as it is on this instance, as changes
+// made to it here would be lost by replicating it
+type Conflict struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// Status is the outcome of the latest replication of a project
+type Status struct {
+	Project   string     `json:"project"`
+	SyncedAt  time.Time  `json:"synced_at"`
+	Applied   int        `json:"applied"`
+	Removed   int        `json:"removed"`
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// Replicator keeps projects of this instance, e.g. a disaster recovery
+// instance in another region, current with the instance it replicates from.
+// Every interval, records of all projects of that instance are fetched and
+// the ones changed there since they were last replicated are applied here,
+// resources and jobs deleted there are removed.
+// Records changed on this instance since they were last replicated are
+// conflicts and left as they are, unless the other instance was replicating
+// from this one and the change is what it last replicated, as happens on
+// failback. Conflicts are listed in status of the project
+type Replicator struct {
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	source   Source
+	store    Store
+	states   store.ReplicationStateRepository
+	interval time.Duration
+	// project config keys whose value of this instance is kept, e.g.
+	// the scheduler of the project in this region
+	keepProjectConfig []string
+
+	statuses map[string]Status
+	Now      func() time.Time
+}
+
+// Start replicates projects in background every interval until closed
+func (r *Replicator) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.SyncAll(r.ctx); err != nil {
+					logger.E(errors.Wrap(err, "replication failed"))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops replicating, waiting for the ongoing replication to finish
+func (r *Replicator) Close() error {
+	r.cancel()
+	r.wg.Wait()
+	return nil
+}
+
+// SyncAll replicates all projects of the instance replicated from, failing
+// to replicate a project does not stop replicating the rest
+func (r *Replicator) SyncAll(ctx context.Context) error {
+	records, err := r.source.Export(ctx)
+	if err != nil {
+		return err
+	}
+	byProject := projectRecords(records)
+	var projectNames []string
+	for projectName := range byProject {
+		projectNames = append(projectNames, projectName)
+	}
+	sort.Strings(projectNames)
+	for _, projectName := range projectNames {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := r.Sync(projectName, byProject[projectName]); err != nil {
+			logger.E(errors.Wrapf(err, "replication of project %s failed", projectName))
+		}
+	}
+	return nil
+}
+
+// Sync applies records of a project fetched from the instance replicated
+// from, its status is recorded whatever the outcome
+func (r *Replicator) Sync(projectName string, remote []Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := Status{Project: projectName, SyncedAt: r.Now()}
+	err := r.sync(projectName, remote, &status)
+	if err != nil {
+		status.Error = err.Error()
+	}
+	r.statuses[projectName] = status
+	return err
+}
+
+func (r *Replicator) sync(projectName string, remote []Record, status *Status) error {
+	local, err := r.store.Export(projectName)
+	if err != nil {
+		return errors.Wrap(err, "failed to export records of this instance")
+	}
+	stateList, err := r.states.GetAll(projectName)
+	if err != nil {
+		return errors.Wrap(err, "failed to get replication state")
+	}
+	states := map[string]models.ReplicationState{}
+	for _, state := range stateList {
+		states[state.Key] = state
+	}
+	for idx := range remote {
+		remote[idx] = r.withoutKeptConfig(remote[idx])
+	}
+	for idx := range local {
+		local[idx] = r.withoutKeptConfig(local[idx])
+	}
+
+	p := reconcile(remote, local, states)
+	status.Conflicts = p.conflicts
+	for _, rec := range p.remove {
+		if err := r.store.Remove(rec); err != nil {
+			return errors.Wrapf(err, "failed to remove %s", rec.Key())
+		}
+		if err := r.states.Delete(projectName, rec.Key()); err != nil {
+			return err
+		}
+		status.Removed++
+	}
+	for _, rec := range p.apply {
+		if err := r.store.Apply(rec); err != nil {
+			return errors.Wrapf(err, "failed to apply %s", rec.Key())
+		}
+		status.Applied++
+	}
+	if status.Applied == 0 && len(p.settle) == 0 {
+		return nil
+	}
+
+	// records are read back as this instance serializes them, which is
+	// what later changes here are told from
+	if local, err = r.store.Export(projectName); err != nil {
+		return errors.Wrap(err, "failed to export records of this instance")
+	}
+	localChecksums := map[string]string{}
+	for _, rec := range local {
+		localChecksums[rec.Key()] = r.withoutKeptConfig(rec).Checksum
+	}
+	for _, rec := range append(p.apply, p.settle...) {
+		if err := r.states.Save(projectName, models.ReplicationState{
+			Key:           rec.Key(),
+			Checksum:      rec.Checksum,
+			LocalChecksum: localChecksums[rec.Key()],
+			UpdatedAt:     status.SyncedAt,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutKeptConfig drops project config keys whose value of this instance
+// is kept from project records, so they are neither compared nor applied
+func (r *Replicator) withoutKeptConfig(rec Record) Record {
+	if rec.Kind != KindProject || len(r.keepProjectConfig) == 0 {
+		return rec
+	}
+	var config map[string]string
+	if err := json.Unmarshal(rec.Spec, &config); err != nil {
+		return rec
+	}
+	for _, key := range r.keepProjectConfig {
+		delete(config, key)
+	}
+	spec, err := json.Marshal(config)
+	if err != nil {
+		return rec
+	}
+	kept := newRecord(rec.Kind, rec.Project, rec.Namespace, rec.Datastore, rec.Name, spec)
+	if kept.Checksum == rec.Checksum {
+		kept.Base = rec.Base
+	}
+	return kept
+}
+
+// plan is what replicating a project does
+type plan struct {
+	apply  []Record
+	remove []Record
+	// settle are records same on both instances whose state is outdated
+	settle    []Record
+	conflicts []Conflict
+}
+
+// reconcile compares records of the instance replicated from with the ones
+// of this instance and the state they were last replicated with
+func reconcile(remote, local []Record, states map[string]models.ReplicationState) plan {
+	var p plan
+	localByKey := map[string]Record{}
+	for _, rec := range local {
+		localByKey[rec.Key()] = rec
+	}
+	remoteKeys := map[string]bool{}
+	for _, rec := range remote {
+		key := rec.Key()
+		remoteKeys[key] = true
+		loc, exists := localByKey[key]
+		state, replicated := states[key]
+		switch {
+		case exists && loc.Checksum == rec.Checksum:
+			if !replicated || state.Checksum != rec.Checksum || state.LocalChecksum != loc.Checksum {
+				p.settle = append(p.settle, rec)
+			}
+		case !exists:
+			p.apply = append(p.apply, rec)
+		case replicated && loc.Checksum == state.LocalChecksum:
+			if rec.Checksum != state.Checksum {
+				p.apply = append(p.apply, rec)
+			}
+		case rec.Base != "" && loc.Checksum == rec.Base:
+			// the other instance replicated this change from here
+			p.apply = append(p.apply, rec)
+		case replicated && rec.Checksum == state.Checksum:
+			p.conflicts = append(p.conflicts, Conflict{Key: key, Reason: conflictChangedHere})
+		case replicated:
+			p.conflicts = append(p.conflicts, Conflict{Key: key, Reason: conflictChangedBoth})
+		default:
+			p.conflicts = append(p.conflicts, Conflict{Key: key, Reason: conflictNotReplicatedHere})
+		}
+	}
+
+	for _, rec := range local {
+		key := rec.Key()
+		if remoteKeys[key] || (rec.Kind != KindResource && rec.Kind != KindJob) {
+			continue
+		}
+		state, replicated := states[key]
+		switch {
+		case replicated && rec.Checksum == state.LocalChecksum:
+			p.remove = append(p.remove, rec)
+		case replicated:
+			p.conflicts = append(p.conflicts, Conflict{Key: key, Reason: conflictDeletedThere})
+		default:
+			p.conflicts = append(p.conflicts, Conflict{Key: key, Reason: conflictExistsOnlyHere})
+		}
+	}
+	// jobs are removed before resources they may refer to
+	sort.SliceStable(p.remove, func(i, j int) bool {
+		return kindOrder[p.remove[i].Kind] > kindOrder[p.remove[j].Kind]
+	})
+	return p
+}
+
+// ServeHTTP returns status of the latest replication of projects on GET,
+// of the project in project query param or of all projects if it is not set
+func (r *Replicator) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectName := req.URL.Query().Get("project")
+	r.mu.Lock()
+	statuses := []Status{}
+	for name, status := range r.statuses {
+		if projectName == "" || projectName == name {
+			statuses = append(statuses, status)
+		}
+	}
+	r.mu.Unlock()
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Project < statuses[j].Project
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func NewReplicator(source Source, store Store, states store.ReplicationStateRepository, interval time.Duration,
+	keepProjectConfig []string) *Replicator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Replicator{
+		ctx:               ctx,
+		cancel:            cancel,
+		source:            source,
+		store:             store,
+		states:            states,
+		interval:          interval,
+		keepProjectConfig: keepProjectConfig,
+		statuses:          map[string]Status{},
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
@@ -0,0 +1,241 @@
+package replication_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/replication"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestReplicator(t *testing.T) {
+	logger.InitWithWriter(logger.DEBUG, ioutil.Discard)
+	now := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	record := func(kind, namespace, name, checksum string) replication.Record {
+		return replication.Record{Kind: kind, Project: "proj", Namespace: namespace, Name: name, Checksum: checksum}
+	}
+	state := func(rec replication.Record, checksum, localChecksum string) models.ReplicationState {
+		return models.ReplicationState{Key: rec.Key(), Checksum: checksum, LocalChecksum: localChecksum, UpdatedAt: now}
+	}
+	newReplicator := func(source *mock.ReplicationSource, store *mock.ReplicationStore,
+		states *mock.ReplicationStateRepository, keepProjectConfig []string) *replication.Replicator {
+		replicator := replication.NewReplicator(source, store, states, time.Minute, keepProjectConfig)
+		replicator.Now = func() time.Time { return now }
+		return replicator
+	}
+	status := func(t *testing.T, replicator *replication.Replicator) replication.Status {
+		rec := httptest.NewRecorder()
+		replicator.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/replication?project=proj", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var statuses []replication.Status
+		assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &statuses))
+		assert.Len(t, statuses, 1)
+		return statuses[0]
+	}
+
+	t.Run("should apply records of projects new to this instance", func(t *testing.T) {
+		remote := []replication.Record{
+			record(replication.KindProject, "", "proj", "p1"),
+			record(replication.KindNamespace, "", "team-a", "n1"),
+			record(replication.KindJob, "team-a", "job-a", "a1"),
+		}
+		source := new(mock.ReplicationSource)
+		source.On("Export", mock2.Anything).Return(remote, nil)
+		defer source.AssertExpectations(t)
+
+		store := new(mock.ReplicationStore)
+		store.On("Export", "proj").Return([]replication.Record{}, nil).Once()
+		for _, rec := range remote {
+			store.On("Apply", rec).Return(nil).Once()
+		}
+		store.On("Export", "proj").Return(remote, nil).Once()
+		defer store.AssertExpectations(t)
+
+		states := new(mock.ReplicationStateRepository)
+		states.On("GetAll", "proj").Return([]models.ReplicationState{}, nil)
+		for _, rec := range remote {
+			states.On("Save", "proj", state(rec, rec.Checksum, rec.Checksum)).Return(nil).Once()
+		}
+		defer states.AssertExpectations(t)
+
+		replicator := newReplicator(source, store, states, nil)
+		defer replicator.Close()
+		assert.Nil(t, replicator.SyncAll(context.Background()))
+		assert.Equal(t, replication.Status{Project: "proj", SyncedAt: now, Applied: 3}, status(t, replicator))
+	})
+	t.Run("should apply changes, remove deleted records and leave conflicts", func(t *testing.T) {
+		changed := record(replication.KindJob, "team-a", "changed", "c2")
+		conflicting := record(replication.KindJob, "team-a", "conflicting", "x2")
+		changedHere := record(replication.KindJob, "team-a", "changed-here", "h1")
+		deleted := record(replication.KindJob, "team-a", "deleted", "d1")
+		onlyHere := record(replication.KindJob, "team-a", "only-here", "o1")
+		remote := []replication.Record{changed, conflicting, changedHere}
+		local := []replication.Record{
+			record(replication.KindJob, "team-a", "changed", "c1"),
+			record(replication.KindJob, "team-a", "conflicting", "x3"),
+			record(replication.KindJob, "team-a", "changed-here", "h2"),
+			deleted,
+			onlyHere,
+		}
+
+		store := new(mock.ReplicationStore)
+		store.On("Export", "proj").Return(local, nil).Once()
+		store.On("Remove", deleted).Return(nil).Once()
+		store.On("Apply", changed).Return(nil).Once()
+		store.On("Export", "proj").Return([]replication.Record{
+			record(replication.KindJob, "team-a", "changed", "c2"),
+		}, nil).Once()
+		defer store.AssertExpectations(t)
+
+		states := new(mock.ReplicationStateRepository)
+		states.On("GetAll", "proj").Return([]models.ReplicationState{
+			state(changed, "c1", "c1"),
+			state(conflicting, "x1", "x1"),
+			state(changedHere, "h1", "h1"),
+			state(deleted, "d1", "d1"),
+		}, nil)
+		states.On("Delete", "proj", deleted.Key()).Return(nil).Once()
+		states.On("Save", "proj", state(changed, "c2", "c2")).Return(nil).Once()
+		defer states.AssertExpectations(t)
+
+		replicator := newReplicator(nil, store, states, nil)
+		defer replicator.Close()
+		assert.Nil(t, replicator.Sync("proj", remote))
+		assert.Equal(t, replication.Status{
+			Project:  "proj",
+			SyncedAt: now,
+			Applied:  1,
+			Removed:  1,
+			Conflicts: []replication.Conflict{
+				{Key: conflicting.Key(), Reason: "changed on both instances since it was last replicated"},
+				{Key: changedHere.Key(), Reason: "changed on this instance since it was last replicated"},
+				{Key: onlyHere.Key(), Reason: "exists only on this instance"},
+			},
+		}, status(t, replicator))
+	})
+	t.Run("should apply changes of records the other instance replicated from here on failback", func(t *testing.T) {
+		replicated := record(replication.KindJob, "team-a", "replicated", "r2")
+		replicated.Base = "r1"
+		notReplicated := record(replication.KindJob, "team-a", "not-replicated", "n2")
+		notReplicated.Base = "n1"
+		local := []replication.Record{
+			record(replication.KindJob, "team-a", "replicated", "r1"),
+			record(replication.KindJob, "team-a", "not-replicated", "n3"),
+		}
+
+		store := new(mock.ReplicationStore)
+		store.On("Export", "proj").Return(local, nil).Once()
+		store.On("Apply", replicated).Return(nil).Once()
+		store.On("Export", "proj").Return([]replication.Record{
+			record(replication.KindJob, "team-a", "replicated", "r2"),
+			record(replication.KindJob, "team-a", "not-replicated", "n3"),
+		}, nil).Once()
+		defer store.AssertExpectations(t)
+
+		states := new(mock.ReplicationStateRepository)
+		states.On("GetAll", "proj").Return([]models.ReplicationState{}, nil)
+		states.On("Save", "proj", state(replicated, "r2", "r2")).Return(nil).Once()
+		defer states.AssertExpectations(t)
+
+		replicator := newReplicator(nil, store, states, nil)
+		defer replicator.Close()
+		assert.Nil(t, replicator.Sync("proj", []replication.Record{replicated, notReplicated}))
+		assert.Equal(t, []replication.Conflict{
+			{Key: notReplicated.Key(), Reason: "changed on this instance and not replicated to the other one"},
+		}, status(t, replicator).Conflicts)
+	})
+	t.Run("should keep values of project config kept by this instance", func(t *testing.T) {
+		projectRecord := func(config map[string]string) replication.Record {
+			spec, _ := json.Marshal(config)
+			rec := record(replication.KindProject, "", "proj", "")
+			rec.Spec = spec
+			rec.Checksum = string(spec)
+			return rec
+		}
+		remote := projectRecord(map[string]string{"BUCKET": "gs://bucket", models.ProjectSchedulerHost: "http://airflow.primary"})
+		local := projectRecord(map[string]string{"BUCKET": "gs://bucket", models.ProjectSchedulerHost: "http://airflow.dr"})
+
+		store := new(mock.ReplicationStore)
+		store.On("Export", "proj").Return([]replication.Record{local}, nil)
+		defer store.AssertExpectations(t)
+
+		states := new(mock.ReplicationStateRepository)
+		states.On("GetAll", "proj").Return([]models.ReplicationState{}, nil)
+		states.On("Save", "proj", mock2.MatchedBy(func(state models.ReplicationState) bool {
+			return state.Key == "project" && state.Checksum == state.LocalChecksum
+		})).Return(nil).Once()
+		defer states.AssertExpectations(t)
+
+		replicator := newReplicator(nil, store, states, []string{models.ProjectSchedulerHost})
+		defer replicator.Close()
+		assert.Nil(t, replicator.Sync("proj", []replication.Record{remote}))
+		store.AssertNotCalled(t, "Apply", mock2.Anything)
+		assert.Empty(t, status(t, replicator).Conflicts)
+	})
+	t.Run("should record failures in status", func(t *testing.T) {
+		store := new(mock.ReplicationStore)
+		store.On("Export", "proj").Return([]replication.Record{}, assert.AnError)
+
+		replicator := newReplicator(nil, store, nil, nil)
+		defer replicator.Close()
+		assert.NotNil(t, replicator.Sync("proj", []replication.Record{record(replication.KindProject, "", "proj", "p1")}))
+		assert.Contains(t, status(t, replicator).Error, assert.AnError.Error())
+	})
+}
+
+func TestExportHandler(t *testing.T) {
+	records := []replication.Record{
+		{Kind: replication.KindProject, Project: "proj", Name: "proj", Spec: []byte(`{}`), Checksum: "p1"},
+		{Kind: replication.KindJob, Project: "proj", Namespace: "team-a", Name: "job-a", Spec: []byte{0x0a, 0x01}, Checksum: "a1"},
+	}
+	t.Run("should serve records of all projects with checksums they were replicated with", func(t *testing.T) {
+		store := new(mock.ReplicationStore)
+		store.On("Projects").Return([]string{"proj"}, nil)
+		store.On("Export", "proj").Return(records, nil)
+		states := new(mock.ReplicationStateRepository)
+		states.On("GetAll", "proj").Return([]models.ReplicationState{{Key: records[1].Key(), Checksum: "a0"}}, nil)
+
+		srv := httptest.NewServer(replication.NewExportHandler(store, states))
+		defer srv.Close()
+		source, err := replication.NewHTTPSource(srv.URL, "", time.Minute)
+		assert.Nil(t, err)
+
+		exported, err := source.Export(context.Background())
+		assert.Nil(t, err)
+		assert.Len(t, exported, 2)
+		assert.Equal(t, "", exported[0].Base)
+		assert.Equal(t, "a0", exported[1].Base)
+		assert.Equal(t, records[1].Spec, exported[1].Spec)
+	})
+	t.Run("should fail the export if any project fails to export", func(t *testing.T) {
+		store := new(mock.ReplicationStore)
+		store.On("Export", "proj").Return([]replication.Record{}, assert.AnError)
+
+		rec := httptest.NewRecorder()
+		replication.NewExportHandler(store, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+			replication.ExportPath+"?project=proj", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+	t.Run("should fail reads of the primary taking longer than timeout", func(t *testing.T) {
+		done := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-done
+		}))
+		defer srv.Close()
+		defer close(done)
+
+		source, err := replication.NewHTTPSource(srv.URL, "", 50*time.Millisecond)
+		assert.Nil(t, err)
+		_, err = source.Export(context.Background())
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,242 @@
+package replication
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	v1handler "github.com/odpf/optimus/api/handler/v1"
+	pb "github.com/odpf/optimus/api/proto/odpf/optimus"
+	"github.com/odpf/optimus/job"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+)
+
+// Store reads and writes metadata of projects of an instance as records
+type Store interface {
+	// Projects lists names of projects of the instance
+	Projects() ([]string, error)
+	// Export returns records of the project in the order they can be
+	// applied, none if the project doesn't exist
+	Export(projectName string) ([]Record, error)
+	// Apply creates or updates what the record is of
+	Apply(Record) error
+	// Remove deletes what the record is of, only resources and jobs
+	// can be removed
+	Remove(Record) error
+}
+
+type ProjectRepoFactory interface {
+	New() store.ProjectRepository
+}
+
+type NamespaceRepoFactory interface {
+	New(spec models.ProjectSpec) store.NamespaceRepository
+}
+
+type JobSpecRepoFactory interface {
+	New(namespace models.NamespaceSpec) job.SpecRepository
+}
+
+type ResourceSpecRepoFactory interface {
+	New(namespace models.NamespaceSpec, storer models.Datastorer) store.ResourceSpecRepository
+}
+
+// specStore keeps records in the repositories of specifications, records
+// are written without deploying them, so jobs replicated are not uploaded
+// to scheduler and resources are not created in their datastore
+type specStore struct {
+	projectRepoFactory      ProjectRepoFactory
+	namespaceRepoFactory    NamespaceRepoFactory
+	jobSpecRepoFactory      JobSpecRepoFactory
+	resourceSpecRepoFactory ResourceSpecRepoFactory
+	datastoreRepo           models.DatastoreRepo
+	adapter                 v1handler.ProtoAdapter
+}
+
+func (s *specStore) Projects() ([]string, error) {
+	projects, err := s.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, projSpec := range projects {
+		names = append(names, projSpec.Name)
+	}
+	return names, nil
+}
+
+func (s *specStore) Export(projectName string) ([]Record, error) {
+	projSpec, err := s.projectRepoFactory.New().GetByName(projectName)
+	if err != nil {
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	spec, err := json.Marshal(projSpec.Config)
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{newRecord(KindProject, projSpec.Name, "", "", projSpec.Name, spec)}
+
+	namespaces, err := s.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get namespaces")
+	}
+	for _, namespace := range namespaces {
+		spec, err := json.Marshal(namespace.Config)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, newRecord(KindNamespace, projSpec.Name, "", "", namespace.Name, spec))
+
+		for _, ds := range s.datastoreRepo.GetAll() {
+			resourceSpecs, err := s.resourceSpecRepoFactory.New(namespace, ds).GetAll()
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get %s resources of namespace %s", ds.Name(), namespace.Name)
+			}
+			for _, resourceSpec := range resourceSpecs {
+				resourceProto, err := s.adapter.ToResourceProto(resourceSpec)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to serialize resource %s", resourceSpec.Name)
+				}
+				spec, err := proto.MarshalOptions{Deterministic: true}.Marshal(resourceProto)
+				if err != nil {
+					return nil, err
+				}
+				records = append(records, newRecord(KindResource, projSpec.Name, namespace.Name, ds.Name(),
+					resourceSpec.Name, spec))
+			}
+		}
+
+		jobSpecs, err := s.jobSpecRepoFactory.New(namespace).GetAll()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get jobs of namespace %s", namespace.Name)
+		}
+		for _, jobSpec := range jobSpecs {
+			jobProto, err := s.adapter.ToJobProto(jobSpec)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to serialize job %s", jobSpec.Name)
+			}
+			spec, err := proto.MarshalOptions{Deterministic: true}.Marshal(jobProto)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, newRecord(KindJob, projSpec.Name, namespace.Name, "", jobSpec.Name, spec))
+		}
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// Apply merges config of project records into the config of the project,
+// keys missing in the record keep their value
+func (s *specStore) Apply(rec Record) error {
+	projectRepo := s.projectRepoFactory.New()
+	if rec.Kind == KindProject {
+		var config map[string]string
+		if err := json.Unmarshal(rec.Spec, &config); err != nil {
+			return errors.Wrap(err, "invalid project config")
+		}
+		projSpec, err := projectRepo.GetByName(rec.Project)
+		if err != nil && !errors.Is(err, store.ErrResourceNotFound) {
+			return err
+		}
+		projSpec.Name = rec.Project
+		if projSpec.Config == nil {
+			projSpec.Config = map[string]string{}
+		}
+		for key, value := range config {
+			projSpec.Config[key] = value
+		}
+		return projectRepo.Save(projSpec)
+	}
+
+	projSpec, err := projectRepo.GetByName(rec.Project)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get project %s", rec.Project)
+	}
+	namespaceRepo := s.namespaceRepoFactory.New(projSpec)
+	if rec.Kind == KindNamespace {
+		var config map[string]string
+		if err := json.Unmarshal(rec.Spec, &config); err != nil {
+			return errors.Wrap(err, "invalid namespace config")
+		}
+		return namespaceRepo.Save(models.NamespaceSpec{
+			Name:        rec.Name,
+			Config:      config,
+			ProjectSpec: projSpec,
+		})
+	}
+
+	namespace, err := namespaceRepo.GetByName(rec.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get namespace %s", rec.Namespace)
+	}
+	switch rec.Kind {
+	case KindResource:
+		ds, err := s.datastoreRepo.GetByName(rec.Datastore)
+		if err != nil {
+			return err
+		}
+		var resourceProto pb.ResourceSpecification
+		if err := proto.Unmarshal(rec.Spec, &resourceProto); err != nil {
+			return errors.Wrap(err, "invalid resource spec")
+		}
+		resourceSpec, err := s.adapter.FromResourceProto(&resourceProto, rec.Datastore)
+		if err != nil {
+			return err
+		}
+		return s.resourceSpecRepoFactory.New(namespace, ds).Save(resourceSpec)
+	case KindJob:
+		var jobProto pb.JobSpecification
+		if err := proto.Unmarshal(rec.Spec, &jobProto); err != nil {
+			return errors.Wrap(err, "invalid job spec")
+		}
+		jobSpec, err := s.adapter.FromJobProto(&jobProto)
+		if err != nil {
+			return err
+		}
+		return s.jobSpecRepoFactory.New(namespace).Save(jobSpec)
+	}
+	return errors.Errorf("unknown kind of record %s", rec.Kind)
+}
+
+func (s *specStore) Remove(rec Record) error {
+	projSpec, err := s.projectRepoFactory.New().GetByName(rec.Project)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get project %s", rec.Project)
+	}
+	namespace, err := s.namespaceRepoFactory.New(projSpec).GetByName(rec.Namespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get namespace %s", rec.Namespace)
+	}
+	switch rec.Kind {
+	case KindResource:
+		ds, err := s.datastoreRepo.GetByName(rec.Datastore)
+		if err != nil {
+			return err
+		}
+		return s.resourceSpecRepoFactory.New(namespace, ds).Delete(rec.Name)
+	case KindJob:
+		return s.jobSpecRepoFactory.New(namespace).Delete(rec.Name)
+	}
+	return errors.Errorf("%s records can't be removed", rec.Kind)
+}
+
+// NewStore reads and writes records of projects in repositories of their
+// specifications
+func NewStore(projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	jobSpecRepoFactory JobSpecRepoFactory, resourceSpecRepoFactory ResourceSpecRepoFactory,
+	datastoreRepo models.DatastoreRepo, adapter v1handler.ProtoAdapter) Store {
+	return &specStore{
+		projectRepoFactory:      projectRepoFactory,
+		namespaceRepoFactory:    namespaceRepoFactory,
+		jobSpecRepoFactory:      jobSpecRepoFactory,
+		resourceSpecRepoFactory: resourceSpecRepoFactory,
+		datastoreRepo:           datastoreRepo,
+		adapter:                 adapter,
+	}
+}
@@ -0,0 +1,211 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+)
+
+const (
+	// DefaultBatchSize is used when janitor is created without a batch size
+	DefaultBatchSize = 1000
+
+	TableInstance = "instance"
+	TableReplay   = "replay"
+	TableAuditLog = "audit_log"
+	TableJob      = "job"
+)
+
+// ProjectRepoFactory is used to list registered projects
+type ProjectRepoFactory interface {
+	New() store.ProjectRepository
+}
+
+// ReclaimRecorder counts rows deleted from a table for a project
+type ReclaimRecorder interface {
+	RecordReclaimed(projectName, table string, rows int64)
+}
+
+// Janitor periodically deletes runs of jobs, replays and audit logs of
+// projects older than their retention policy, and jobs of archived projects
+// once they are due to be purged. Rows are deleted in batches so a pass
+// doesn't hold locks on tables for long
+type Janitor struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	projectRepoFactory ProjectRepoFactory
+	repo               store.RetentionRepository
+	archives           store.ProjectArchiveRepository
+	recorder           ReclaimRecorder
+	defaults           models.RetentionPolicy
+	interval           time.Duration
+	batchSize          int
+
+	Now func() time.Time
+}
+
+// Start runs cleanup in background every interval until closed
+func (j *Janitor) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := j.Clean(ctx); err != nil {
+					logger.E(errors.Wrap(err, "retention cleanup failed"))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops cleanup, waiting for the ongoing batch to finish
+func (j *Janitor) Close() error {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.wg.Wait()
+	return nil
+}
+
+// Clean deletes expired rows of all projects, failing to clean a project
+// does not stop cleanup of the rest
+func (j *Janitor) Clean(ctx context.Context) error {
+	projects, err := j.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch projects")
+	}
+	now := j.Now()
+	for _, projSpec := range projects {
+		if j.archives != nil {
+			j.purgeArchive(ctx, projSpec, now)
+		}
+		policy, err := projSpec.RetentionPolicy(j.defaults)
+		if err != nil {
+			logger.E(err)
+			continue
+		}
+		for _, target := range []struct {
+			table      string
+			retention  time.Duration
+			deleteRows func(models.ProjectSpec, time.Time, int) (int64, error)
+		}{
+			{TableInstance, policy.Instances, j.repo.DeleteInstances},
+			{TableReplay, policy.Replays, j.repo.DeleteReplays},
+			{TableAuditLog, policy.AuditLogs, j.repo.DeleteAuditLogs},
+		} {
+			if target.retention <= 0 {
+				continue
+			}
+			deleted, err := j.clean(ctx, projSpec, now.Add(-target.retention), target.deleteRows)
+			if deleted > 0 {
+				j.recorder.RecordReclaimed(projSpec.Name, target.table, deleted)
+				logger.I(fmt.Sprintf("deleted %d expired rows of %s of project %s", deleted, target.table, projSpec.Name))
+			}
+			if err != nil {
+				logger.E(errors.Wrapf(err, "failed to delete expired rows of %s of project %s", target.table, projSpec.Name))
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// purgeArchive deletes jobs of an archived project along with their runs and
+// replays once the project is due to be purged, the archive is kept without
+// its snapshot to tell the project can't be restored anymore
+func (j *Janitor) purgeArchive(ctx context.Context, projSpec models.ProjectSpec, now time.Time) {
+	archive, err := j.archives.GetByProject(projSpec)
+	if err != nil {
+		if !errors.Is(err, store.ErrResourceNotFound) {
+			logger.E(errors.Wrapf(err, "failed to fetch archive of project %s", projSpec.Name))
+		}
+		return
+	}
+	if !archive.IsPurgeDue(now) {
+		return
+	}
+	for _, target := range []struct {
+		table      string
+		deleteRows func(models.ProjectSpec, time.Time, int) (int64, error)
+	}{
+		{TableReplay, j.repo.DeleteReplays},
+		{TableJob, func(proj models.ProjectSpec, _ time.Time, limit int) (int64, error) {
+			return j.repo.DeleteJobs(proj, limit)
+		}},
+	} {
+		deleted, err := j.clean(ctx, projSpec, now, target.deleteRows)
+		if deleted > 0 {
+			j.recorder.RecordReclaimed(projSpec.Name, target.table, deleted)
+			logger.I(fmt.Sprintf("purged %d rows of %s of archived project %s", deleted, target.table, projSpec.Name))
+		}
+		if err != nil {
+			logger.E(errors.Wrapf(err, "failed to purge %s of archived project %s", target.table, projSpec.Name))
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+	archive.Snapshot = nil
+	archive.PurgedAt = now
+	if err := j.archives.Save(projSpec, archive); err != nil {
+		logger.E(errors.Wrapf(err, "failed to mark archived project %s purged", projSpec.Name))
+	}
+}
+
+// clean deletes batches till a batch comes back short
+func (j *Janitor) clean(ctx context.Context, projSpec models.ProjectSpec, before time.Time,
+	deleteRows func(models.ProjectSpec, time.Time, int) (int64, error)) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		deleted, err := deleteRows(projSpec, before, j.batchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < int64(j.batchSize) {
+			break
+		}
+	}
+	return total, nil
+}
+
+// NewJanitor creates a janitor cleaning up every interval, defaults apply to
+// projects without retention of their own. Archived projects are purged only
+// if archives is set
+func NewJanitor(projectRepoFactory ProjectRepoFactory, repo store.RetentionRepository, archives store.ProjectArchiveRepository,
+	recorder ReclaimRecorder, defaults models.RetentionPolicy, interval time.Duration, batchSize int) *Janitor {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Janitor{
+		projectRepoFactory: projectRepoFactory,
+		repo:               repo,
+		archives:           archives,
+		recorder:           recorder,
+		defaults:           defaults,
+		interval:           interval,
+		batchSize:          batchSize,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package retention_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/retention"
+)
+
+func TestJanitor(t *testing.T) {
+	logger.InitWithWriter("ERROR", ioutil.Discard)
+
+	ctx := context.Background()
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	day := time.Hour * 24
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+		Config: map[string]string{
+			models.ProjectRetentionReplaysKey: "7",
+		},
+	}
+	defaults := models.RetentionPolicy{Instances: 90 * day, AuditLogs: 30 * day}
+	newProjectRepoFac := func(projects ...models.ProjectSpec) *mock.ProjectRepoFactory {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return(projects, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+		return projectRepoFac
+	}
+
+	t.Run("should delete expired rows in batches as per retention of project", func(t *testing.T) {
+		repo := new(mock.RetentionRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("DeleteInstances", projectSpec, now.Add(-90*day), 2).Return(int64(2), nil).Twice()
+		repo.On("DeleteInstances", projectSpec, now.Add(-90*day), 2).Return(int64(1), nil).Once()
+		repo.On("DeleteReplays", projectSpec, now.Add(-7*day), 2).Return(int64(0), nil).Once()
+		repo.On("DeleteAuditLogs", projectSpec, now.Add(-30*day), 2).Return(int64(1), nil).Once()
+
+		recorder := new(mock.ReclaimRecorder)
+		defer recorder.AssertExpectations(t)
+		recorder.On("RecordReclaimed", projectSpec.Name, retention.TableInstance, int64(5)).Return()
+		recorder.On("RecordReclaimed", projectSpec.Name, retention.TableAuditLog, int64(1)).Return()
+
+		janitor := retention.NewJanitor(newProjectRepoFac(projectSpec), repo, nil, recorder, defaults, time.Hour, 2)
+		janitor.Now = func() time.Time { return now }
+		assert.Nil(t, janitor.Clean(ctx))
+	})
+	t.Run("should keep data forever without retention", func(t *testing.T) {
+		repo := new(mock.RetentionRepository)
+		janitor := retention.NewJanitor(newProjectRepoFac(models.ProjectSpec{Name: "forever"}), repo, nil,
+			new(mock.ReclaimRecorder), models.RetentionPolicy{}, time.Hour, 0)
+		assert.Nil(t, janitor.Clean(ctx))
+		repo.AssertNotCalled(t, "DeleteInstances")
+	})
+	t.Run("should record rows deleted before a batch failed", func(t *testing.T) {
+		repo := new(mock.RetentionRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("DeleteInstances", projectSpec, now.Add(-90*day), 2).Return(int64(2), nil).Once()
+		repo.On("DeleteInstances", projectSpec, now.Add(-90*day), 2).Return(int64(0), errors.New("timeout")).Once()
+		repo.On("DeleteReplays", projectSpec, now.Add(-7*day), 2).Return(int64(0), nil).Once()
+		repo.On("DeleteAuditLogs", projectSpec, now.Add(-30*day), 2).Return(int64(0), nil).Once()
+
+		recorder := new(mock.ReclaimRecorder)
+		defer recorder.AssertExpectations(t)
+		recorder.On("RecordReclaimed", projectSpec.Name, retention.TableInstance, int64(2)).Return()
+
+		janitor := retention.NewJanitor(newProjectRepoFac(projectSpec), repo, nil, recorder, defaults, time.Hour, 2)
+		janitor.Now = func() time.Time { return now }
+		assert.Nil(t, janitor.Clean(ctx))
+	})
+	t.Run("should purge jobs of archived projects once due and keep the archive without snapshot", func(t *testing.T) {
+		archivedProject := models.ProjectSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "archived-project",
+		}
+		archive := models.ProjectArchive{
+			Reason:   "decommissioned",
+			Snapshot: []byte("jobs"),
+			PurgeAt:  now.Add(-day),
+		}
+		archives := new(mock.ProjectArchiveRepository)
+		defer archives.AssertExpectations(t)
+		archives.On("GetByProject", archivedProject).Return(archive, nil)
+		archives.On("Save", archivedProject, models.ProjectArchive{
+			Reason:   "decommissioned",
+			PurgeAt:  now.Add(-day),
+			PurgedAt: now,
+		}).Return(nil)
+
+		repo := new(mock.RetentionRepository)
+		defer repo.AssertExpectations(t)
+		repo.On("DeleteReplays", archivedProject, now, 2).Return(int64(1), nil).Once()
+		repo.On("DeleteJobs", archivedProject, 2).Return(int64(2), nil).Once()
+		repo.On("DeleteJobs", archivedProject, 2).Return(int64(0), nil).Once()
+
+		recorder := new(mock.ReclaimRecorder)
+		defer recorder.AssertExpectations(t)
+		recorder.On("RecordReclaimed", archivedProject.Name, retention.TableReplay, int64(1)).Return()
+		recorder.On("RecordReclaimed", archivedProject.Name, retention.TableJob, int64(2)).Return()
+
+		janitor := retention.NewJanitor(newProjectRepoFac(archivedProject), repo, archives, recorder,
+			models.RetentionPolicy{}, time.Hour, 2)
+		janitor.Now = func() time.Time { return now }
+		assert.Nil(t, janitor.Clean(ctx))
+	})
+}
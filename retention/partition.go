@@ -0,0 +1,204 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+)
+
+// NamespaceRepoFactory is used to look up namespaces jobs belong to
+type NamespaceRepoFactory interface {
+	New(spec models.ProjectSpec) store.NamespaceRepository
+}
+
+// ProjectJobSpecRepoFactory is used to list jobs of a project
+type ProjectJobSpecRepoFactory interface {
+	New(proj models.ProjectSpec) store.ProjectJobSpecRepository
+}
+
+// PartitionRecorder counts partitions dropped from destination of a job
+type PartitionRecorder interface {
+	RecordPartitionsDropped(projectName, jobName string, partitions int)
+}
+
+// PartitionJanitor periodically drops partitions of destinations of jobs
+// older than the retention in lifecycle of their spec. Partitions are
+// dropped by datastores implementing models.DatastorePartitionDropper,
+// retention of destinations of other datastores isn't enforced
+type PartitionJanitor struct {
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	projectRepoFactory        ProjectRepoFactory
+	namespaceRepoFactory      NamespaceRepoFactory
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory
+	datastoreRepo             models.DatastoreRepo
+	recorder                  PartitionRecorder
+	interval                  time.Duration
+
+	Now func() time.Time
+}
+
+// Start drops partitions in background every interval until closed
+func (j *PartitionJanitor) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := j.Clean(ctx); err != nil {
+					logger.E(errors.Wrap(err, "dropping expired partitions failed"))
+				}
+			}
+		}
+	}()
+}
+
+// Close stops dropping partitions, waiting for the ongoing pass to finish
+func (j *PartitionJanitor) Close() error {
+	if j.cancel != nil {
+		j.cancel()
+	}
+	j.wg.Wait()
+	return nil
+}
+
+// Clean drops expired partitions of destinations of all jobs with a
+// retention, failing to clean a project or a job does not stop cleanup of
+// the rest
+func (j *PartitionJanitor) Clean(ctx context.Context) error {
+	projects, err := j.projectRepoFactory.New().GetAll()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch projects")
+	}
+	now := j.Now()
+	for _, projSpec := range projects {
+		if err := j.cleanProject(ctx, projSpec, now); err != nil {
+			logger.E(errors.Wrapf(err, "failed to drop expired partitions of project %s", projSpec.Name))
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (j *PartitionJanitor) cleanProject(ctx context.Context, projSpec models.ProjectSpec, now time.Time) error {
+	projectJobSpecRepo := j.projectJobSpecRepoFactory.New(projSpec)
+	jobSpecs, err := projectJobSpecRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	var retained []models.JobSpec
+	for _, jobSpec := range jobSpecs {
+		if jobSpec.Lifecycle.Retention.IsEnabled() {
+			retained = append(retained, jobSpec)
+		}
+	}
+	if len(retained) == 0 {
+		return nil
+	}
+
+	destinations, err := projectJobSpecRepo.GetDestinations()
+	if err != nil {
+		return err
+	}
+	destinationByJob := map[string]models.JobDestination{}
+	for _, destination := range destinations {
+		destinationByJob[destination.JobName] = destination
+	}
+	namespaces, err := j.namespaceRepoFactory.New(projSpec).GetAll()
+	if err != nil {
+		return err
+	}
+	namespaceByName := map[string]models.NamespaceSpec{}
+	for _, namespace := range namespaces {
+		namespaceByName[namespace.Name] = namespace
+	}
+
+	for _, jobSpec := range retained {
+		destination, ok := destinationByJob[jobSpec.Name]
+		if !ok || destination.Destination == "" {
+			logger.W(fmt.Sprintf("retention of job %s of project %s isn't enforced, it has no destination",
+				jobSpec.Name, projSpec.Name))
+			continue
+		}
+		j.dropPartitions(ctx, models.DropPartitionsRequest{
+			Destination: destination.Destination,
+			Before:      now.Add(-jobSpec.Lifecycle.Retention.PartitionRetention()),
+			Project:     projSpec,
+			Namespace:   namespaceByName[destination.NamespaceName],
+		}, jobSpec.Name)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// dropPartitions asks every datastore able to drop partitions to drop them
+// from destination, datastores tell if destination is one of theirs
+func (j *PartitionJanitor) dropPartitions(ctx context.Context, request models.DropPartitionsRequest, jobName string) {
+	supported := false
+	for _, ds := range j.datastoreRepo.GetAll() {
+		dropper, ok := ds.(models.DatastorePartitionDropper)
+		if !ok {
+			continue
+		}
+		resp, err := dropper.DropPartitions(ctx, request)
+		if len(resp.Dropped) > 0 {
+			j.recorder.RecordPartitionsDropped(request.Project.Name, jobName, len(resp.Dropped))
+			logger.I(fmt.Sprintf("dropped %d expired partitions of %s of job %s of project %s",
+				len(resp.Dropped), request.Destination, jobName, request.Project.Name))
+		}
+		if err != nil {
+			logger.E(errors.Wrapf(err, "failed to drop expired partitions of %s of job %s of project %s",
+				request.Destination, jobName, request.Project.Name))
+			supported = true
+			continue
+		}
+		if resp.Skipped != "" {
+			logger.W(fmt.Sprintf("retention of job %s of project %s isn't enforced, %s",
+				jobName, request.Project.Name, resp.Skipped))
+		}
+		if resp.Supported {
+			supported = true
+		}
+	}
+	if !supported {
+		logger.W(fmt.Sprintf("retention of job %s of project %s isn't enforced, no datastore drops partitions of %s",
+			jobName, request.Project.Name, request.Destination))
+	}
+}
+
+// NewPartitionJanitor creates a janitor dropping expired partitions of
+// destinations of jobs every interval
+func NewPartitionJanitor(projectRepoFactory ProjectRepoFactory, namespaceRepoFactory NamespaceRepoFactory,
+	projectJobSpecRepoFactory ProjectJobSpecRepoFactory, datastoreRepo models.DatastoreRepo,
+	recorder PartitionRecorder, interval time.Duration) *PartitionJanitor {
+	return &PartitionJanitor{
+		projectRepoFactory:        projectRepoFactory,
+		namespaceRepoFactory:      namespaceRepoFactory,
+		projectJobSpecRepoFactory: projectJobSpecRepoFactory,
+		datastoreRepo:             datastoreRepo,
+		recorder:                  recorder,
+		interval:                  interval,
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
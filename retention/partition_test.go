@@ -0,0 +1,158 @@
+package retention_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/retention"
+)
+
+func TestPartitionJanitor(t *testing.T) {
+	logger.InitWithWriter("ERROR", ioutil.Discard)
+
+	ctx := context.Background()
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "a-data-project",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "game_jam",
+	}
+	events := models.JobSpec{
+		Name: "events",
+		Lifecycle: models.JobSpecLifecycle{
+			Retention: models.JobSpecRetention{PartitionDays: 400},
+		},
+	}
+	sessions := models.JobSpec{
+		Name: "sessions",
+		Lifecycle: models.JobSpecLifecycle{
+			Retention: models.JobSpecRetention{PartitionDays: 30},
+		},
+	}
+	revenue := models.JobSpec{
+		Name: "revenue",
+		Lifecycle: models.JobSpecLifecycle{
+			Retention: models.JobSpecRetention{PartitionDays: models.RetentionDisabled},
+		},
+	}
+	newJanitor := func(datastores []models.Datastorer, recorder retention.PartitionRecorder,
+		jobSpecs ...models.JobSpec) *retention.PartitionJanitor {
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projectSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		namespaceRepo := new(mock.NamespaceRepository)
+		namespaceRepo.On("GetAll").Return([]models.NamespaceSpec{namespaceSpec}, nil)
+		namespaceRepoFac := new(mock.NamespaceRepoFactory)
+		namespaceRepoFac.On("New", projectSpec).Return(namespaceRepo)
+
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		projectJobSpecRepo.On("GetAll").Return(jobSpecs, nil)
+		projectJobSpecRepo.On("GetDestinations").Return([]models.JobDestination{
+			{JobName: "events", NamespaceName: namespaceSpec.Name, Destination: "bigquery://proj:raw.events"},
+			{JobName: "sessions", NamespaceName: namespaceSpec.Name, Destination: "bigquery://proj:raw.sessions"},
+			{JobName: "revenue", NamespaceName: namespaceSpec.Name, Destination: "bigquery://proj:finance.revenue"},
+		}, nil)
+		projectJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projectJobSpecRepoFac.On("New", projectSpec).Return(projectJobSpecRepo)
+
+		datastoreRepo := new(mock.SupportedDatastoreRepo)
+		datastoreRepo.On("GetAll").Return(datastores)
+
+		janitor := retention.NewPartitionJanitor(projectRepoFac, namespaceRepoFac, projectJobSpecRepoFac,
+			datastoreRepo, recorder, time.Hour)
+		janitor.Now = func() time.Time { return now }
+		return janitor
+	}
+
+	t.Run("should drop partitions of destinations older than retention of jobs", func(t *testing.T) {
+		dropper := new(mock.DatastorePartitionDropper)
+		defer dropper.AssertExpectations(t)
+		dropper.On("DropPartitions", ctx, models.DropPartitionsRequest{
+			Destination: "bigquery://proj:raw.events", Before: now.AddDate(0, 0, -400),
+			Project: projectSpec, Namespace: namespaceSpec,
+		}).Return(models.DropPartitionsResponse{Supported: true, Dropped: []string{"20200526", "20200527"}}, nil)
+		dropper.On("DropPartitions", ctx, models.DropPartitionsRequest{
+			Destination: "bigquery://proj:raw.sessions", Before: now.AddDate(0, 0, -30),
+			Project: projectSpec, Namespace: namespaceSpec,
+		}).Return(models.DropPartitionsResponse{Supported: true}, nil)
+
+		recorder := new(mock.PartitionRecorder)
+		defer recorder.AssertExpectations(t)
+		recorder.On("RecordPartitionsDropped", projectSpec.Name, "events", 2).Return()
+
+		janitor := newJanitor([]models.Datastorer{new(mock.Datastorer), dropper}, recorder, events, sessions, revenue)
+		assert.Nil(t, janitor.Clean(ctx))
+	})
+	t.Run("should count partitions dropped before failing and carry on with other jobs", func(t *testing.T) {
+		dropper := new(mock.DatastorePartitionDropper)
+		defer dropper.AssertExpectations(t)
+		dropper.On("DropPartitions", ctx, models.DropPartitionsRequest{
+			Destination: "bigquery://proj:raw.events", Before: now.AddDate(0, 0, -400),
+			Project: projectSpec, Namespace: namespaceSpec,
+		}).Return(models.DropPartitionsResponse{Supported: true, Dropped: []string{"20200526"}}, errors.New("access denied"))
+		dropper.On("DropPartitions", ctx, models.DropPartitionsRequest{
+			Destination: "bigquery://proj:raw.sessions", Before: now.AddDate(0, 0, -30),
+			Project: projectSpec, Namespace: namespaceSpec,
+		}).Return(models.DropPartitionsResponse{Supported: true, Dropped: []string{"20210531"}}, nil)
+
+		recorder := new(mock.PartitionRecorder)
+		defer recorder.AssertExpectations(t)
+		recorder.On("RecordPartitionsDropped", projectSpec.Name, "events", 1).Return()
+		recorder.On("RecordPartitionsDropped", projectSpec.Name, "sessions", 1).Return()
+
+		janitor := newJanitor([]models.Datastorer{dropper}, recorder, events, sessions)
+		assert.Nil(t, janitor.Clean(ctx))
+	})
+	t.Run("should carry on with other jobs if partitions of destination are skipped", func(t *testing.T) {
+		dropper := new(mock.DatastorePartitionDropper)
+		defer dropper.AssertExpectations(t)
+		dropper.On("DropPartitions", ctx, models.DropPartitionsRequest{
+			Destination: "bigquery://proj:raw.events", Before: now.AddDate(0, 0, -400),
+			Project: projectSpec, Namespace: namespaceSpec,
+		}).Return(models.DropPartitionsResponse{
+			Supported: true,
+			Skipped:   "table proj:raw.events is partitioned by MONTH, only partitions by DAY and HOUR are dropped",
+		}, nil)
+		dropper.On("DropPartitions", ctx, models.DropPartitionsRequest{
+			Destination: "bigquery://proj:raw.sessions", Before: now.AddDate(0, 0, -30),
+			Project: projectSpec, Namespace: namespaceSpec,
+		}).Return(models.DropPartitionsResponse{Supported: true, Dropped: []string{"20210531"}}, nil)
+
+		recorder := new(mock.PartitionRecorder)
+		defer recorder.AssertExpectations(t)
+		recorder.On("RecordPartitionsDropped", projectSpec.Name, "sessions", 1).Return()
+
+		janitor := newJanitor([]models.Datastorer{dropper}, recorder, events, sessions)
+		assert.Nil(t, janitor.Clean(ctx))
+	})
+	t.Run("should not look up destinations of projects without retention", func(t *testing.T) {
+		projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+		defer projectJobSpecRepo.AssertExpectations(t)
+		projectJobSpecRepo.On("GetAll").Return([]models.JobSpec{revenue}, nil)
+		projectJobSpecRepoFac := new(mock.ProjectJobSpecRepoFactory)
+		projectJobSpecRepoFac.On("New", projectSpec).Return(projectJobSpecRepo)
+
+		projectRepo := new(mock.ProjectRepository)
+		projectRepo.On("GetAll").Return([]models.ProjectSpec{projectSpec}, nil)
+		projectRepoFac := new(mock.ProjectRepoFactory)
+		projectRepoFac.On("New").Return(projectRepo)
+
+		janitor := retention.NewPartitionJanitor(projectRepoFac, new(mock.NamespaceRepoFactory), projectJobSpecRepoFac,
+			new(mock.SupportedDatastoreRepo), new(mock.PartitionRecorder), time.Hour)
+		assert.Nil(t, janitor.Clean(ctx))
+	})
+}
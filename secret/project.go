@@ -0,0 +1,24 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/odpf/optimus/models"
+)
+
+// ProjectBackend resolves secrets registered with optimus for the project
+type ProjectBackend struct{}
+
+func (b *ProjectBackend) Name() string {
+	return models.SecretBackendOptimus
+}
+
+func (b *ProjectBackend) Resolve(_ context.Context, proj models.ProjectSpec, names []string) (map[string]string, error) {
+	resolved := map[string]string{}
+	for _, name := range names {
+		if value, ok := proj.Secret.GetByName(name); ok {
+			resolved[name] = value
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,154 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+)
+
+const (
+	// auditRPC is recorded as the method of audit entries of secret resolutions,
+	// formatted like grpc methods so that audit filters work alike
+	auditRPC = "/optimus.SecretBackend/%s/Resolve"
+)
+
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Resolver resolves secrets of jobs from the backend configured for their project.
+// Resolved values are cached for a while to avoid hitting external stores on every
+// instance compilation and each resolution is recorded in audit log.
+type Resolver struct {
+	backends  map[string]models.SecretBackend
+	auditRepo store.AuditLogRepository
+	cacheTTL  time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+
+	Now func() time.Time
+}
+
+func (r *Resolver) Resolve(ctx context.Context, namespace models.NamespaceSpec, jobSpec models.JobSpec,
+	names []string) (map[string]string, error) {
+	resolved := map[string]string{}
+	if len(names) == 0 {
+		return resolved, nil
+	}
+
+	proj := namespace.ProjectSpec
+	backendName := proj.SecretBackendName()
+	backend, ok := r.backends[backendName]
+	if !ok {
+		return nil, errors.Errorf("unsupported secret backend %s configured for project %s", backendName, proj.Name)
+	}
+
+	now := r.Now()
+	var uncached []string
+	r.mu.Lock()
+	for _, name := range names {
+		cached, ok := r.cache[cacheKey(backendName, proj.Name, name)]
+		if ok && now.Before(cached.expiresAt) {
+			resolved[name] = cached.value
+			continue
+		}
+		uncached = append(uncached, name)
+	}
+	r.mu.Unlock()
+
+	err := r.fetch(ctx, backend, proj, uncached, resolved, now)
+	r.audit(backendName, namespace, jobSpec, names, len(names)-len(uncached), err)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve secrets of job %s", jobSpec.Name)
+	}
+	return resolved, nil
+}
+
+// fetch resolves secrets missing in cache from the backend and caches them
+func (r *Resolver) fetch(ctx context.Context, backend models.SecretBackend, proj models.ProjectSpec,
+	names []string, resolved map[string]string, now time.Time) error {
+	if len(names) == 0 {
+		return nil
+	}
+	fetched, err := backend.Resolve(ctx, proj, names)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		value, ok := fetched[name]
+		if !ok {
+			return errors.Wrapf(models.ErrNoSuchSecret, "%s in %s backend", name, backend.Name())
+		}
+		resolved[name] = value
+		if r.cacheTTL > 0 {
+			r.cache[cacheKey(backend.Name(), proj.Name, name)] = cachedSecret{
+				value:     value,
+				expiresAt: now.Add(r.cacheTTL),
+			}
+		}
+	}
+	return nil
+}
+
+// audit records which secrets were handed out for a job, values are never recorded
+func (r *Resolver) audit(backendName string, namespace models.NamespaceSpec, jobSpec models.JobSpec,
+	names []string, cached int, resolveErr error) {
+	if r.auditRepo == nil {
+		return
+	}
+	entry := &models.AuditEntry{
+		Actor:       fmt.Sprintf("job/%s", jobSpec.Name),
+		RPC:         fmt.Sprintf(auditRPC, backendName),
+		ProjectName: namespace.ProjectSpec.Name,
+		Outcome:     models.AuditOutcomeSuccess,
+		Message: fmt.Sprintf("namespace %s, secrets %s, %d from cache", namespace.Name,
+			strings.Join(names, ", "), cached),
+		CreatedAt: r.Now(),
+	}
+	if resolveErr != nil {
+		entry.Outcome = models.AuditOutcomeFailure
+		entry.Message = fmt.Sprintf("%s: %s", entry.Message, resolveErr.Error())
+	}
+
+	// failing to audit should not fail the resolution itself
+	if err := r.auditRepo.Insert(entry); err != nil {
+		logger.E("failed to record audit entry of secret resolution: ", err)
+	}
+}
+
+func cacheKey(backend, project, name string) string {
+	return fmt.Sprintf("%s/%s/%s", backend, project, name)
+}
+
+// NewResolver creates a resolver over given backends, secrets registered
+// with optimus are always available as the default backend
+func NewResolver(backends []models.SecretBackend, auditRepo store.AuditLogRepository, cacheTTL time.Duration) *Resolver {
+	backendMap := map[string]models.SecretBackend{
+		models.SecretBackendOptimus: &ProjectBackend{},
+	}
+	for _, backend := range backends {
+		backendMap[backend.Name()] = backend
+	}
+	return &Resolver{
+		backends:  backendMap,
+		auditRepo: auditRepo,
+		cacheTTL:  cacheTTL,
+		cache:     map[string]cachedSecret{},
+		Now: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
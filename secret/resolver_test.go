@@ -0,0 +1,128 @@
+package secret_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/secret"
+)
+
+func TestResolver(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC)
+	projectSpec := models.ProjectSpec{
+		Name: "a-data-project",
+		Config: map[string]string{
+			models.ProjectSecretBackendKey: "vault",
+		},
+	}
+	namespaceSpec := models.NamespaceSpec{
+		Name:        "a-namespace",
+		ProjectSpec: projectSpec,
+	}
+	jobSpec := models.JobSpec{
+		Name: "a-job",
+	}
+
+	t.Run("should resolve secrets from optimus if project has no backend configured", func(t *testing.T) {
+		auditRepo := new(mock.AuditLogRepository)
+		auditRepo.On("Insert", mock2.AnythingOfType("*models.AuditEntry")).Return(nil)
+		defer auditRepo.AssertExpectations(t)
+
+		namespace := models.NamespaceSpec{
+			Name: "a-namespace",
+			ProjectSpec: models.ProjectSpec{
+				Name: "a-data-project",
+				Secret: models.ProjectSecrets{
+					{Name: "API_KEY", Value: "secret-key"},
+				},
+			},
+		}
+		resolved, err := secret.NewResolver(nil, auditRepo, time.Minute).Resolve(ctx, namespace, jobSpec, []string{"API_KEY"})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"API_KEY": "secret-key"}, resolved)
+	})
+	t.Run("should cache resolved secrets and audit every resolution", func(t *testing.T) {
+		backend := new(mock.SecretBackend)
+		backend.On("Name").Return("vault")
+		backend.On("Resolve", ctx, projectSpec, []string{"API_KEY"}).Return(map[string]string{
+			"API_KEY": "secret-key",
+		}, nil).Once()
+		backend.On("Resolve", ctx, projectSpec, []string{"TOKEN"}).Return(map[string]string{
+			"TOKEN": "secret-token",
+		}, nil).Once()
+		defer backend.AssertExpectations(t)
+
+		var recorded []*models.AuditEntry
+		auditRepo := new(mock.AuditLogRepository)
+		auditRepo.On("Insert", mock2.AnythingOfType("*models.AuditEntry")).Run(func(args mock2.Arguments) {
+			recorded = append(recorded, args.Get(0).(*models.AuditEntry))
+		}).Return(nil)
+		defer auditRepo.AssertExpectations(t)
+
+		resolver := secret.NewResolver([]models.SecretBackend{backend}, auditRepo, time.Minute)
+		resolver.Now = func() time.Time { return now }
+
+		resolved, err := resolver.Resolve(ctx, namespaceSpec, jobSpec, []string{"API_KEY"})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"API_KEY": "secret-key"}, resolved)
+
+		resolved, err = resolver.Resolve(ctx, namespaceSpec, jobSpec, []string{"API_KEY", "TOKEN"})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"API_KEY": "secret-key", "TOKEN": "secret-token"}, resolved)
+
+		assert.Len(t, recorded, 2)
+		assert.Equal(t, "job/a-job", recorded[1].Actor)
+		assert.Equal(t, "/optimus.SecretBackend/vault/Resolve", recorded[1].RPC)
+		assert.Equal(t, "a-data-project", recorded[1].ProjectName)
+		assert.Equal(t, models.AuditOutcomeSuccess, recorded[1].Outcome)
+		assert.Equal(t, "namespace a-namespace, secrets API_KEY, TOKEN, 1 from cache", recorded[1].Message)
+		assert.NotContains(t, recorded[1].Message, "secret-key")
+	})
+	t.Run("should resolve again once cached secret expires", func(t *testing.T) {
+		backend := new(mock.SecretBackend)
+		backend.On("Name").Return("vault")
+		backend.On("Resolve", ctx, projectSpec, []string{"API_KEY"}).Return(map[string]string{
+			"API_KEY": "secret-key",
+		}, nil).Twice()
+		defer backend.AssertExpectations(t)
+
+		resolver := secret.NewResolver([]models.SecretBackend{backend}, nil, time.Minute)
+		resolver.Now = func() time.Time { return now }
+		_, err := resolver.Resolve(ctx, namespaceSpec, jobSpec, []string{"API_KEY"})
+		assert.Nil(t, err)
+
+		resolver.Now = func() time.Time { return now.Add(time.Minute * 2) }
+		_, err = resolver.Resolve(ctx, namespaceSpec, jobSpec, []string{"API_KEY"})
+		assert.Nil(t, err)
+	})
+	t.Run("should fail if a secret is missing in backend", func(t *testing.T) {
+		backend := new(mock.SecretBackend)
+		backend.On("Name").Return("vault")
+		backend.On("Resolve", ctx, projectSpec, []string{"API_KEY"}).Return(map[string]string{}, nil)
+		defer backend.AssertExpectations(t)
+
+		var recorded *models.AuditEntry
+		auditRepo := new(mock.AuditLogRepository)
+		auditRepo.On("Insert", mock2.AnythingOfType("*models.AuditEntry")).Run(func(args mock2.Arguments) {
+			recorded = args.Get(0).(*models.AuditEntry)
+		}).Return(errors.New("db down"))
+		defer auditRepo.AssertExpectations(t)
+
+		_, err := secret.NewResolver([]models.SecretBackend{backend}, auditRepo, time.Minute).Resolve(ctx, namespaceSpec, jobSpec, []string{"API_KEY"})
+		assert.True(t, errors.Is(err, models.ErrNoSuchSecret))
+		assert.Equal(t, "failed to resolve secrets of job a-job: API_KEY in vault backend: secret not found", err.Error())
+		assert.Equal(t, models.AuditOutcomeFailure, recorded.Outcome)
+	})
+	t.Run("should fail if project uses an unsupported backend", func(t *testing.T) {
+		_, err := secret.NewResolver(nil, nil, time.Minute).Resolve(ctx, namespaceSpec, jobSpec, []string{"API_KEY"})
+		assert.Equal(t, "unsupported secret backend vault configured for project a-data-project", err.Error())
+	})
+}
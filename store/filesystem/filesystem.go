@@ -0,0 +1,102 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/odpf/optimus/store"
+	"github.com/spf13/afero"
+)
+
+// ObjectStore keeps objects as files, buckets are directories of the
+// filesystem. It serves dag folders of schedulers mounted over nfs
+type ObjectStore struct {
+	fs afero.Fs
+}
+
+func (s *ObjectStore) NewWriter(ctx context.Context, bucket, path string) (io.WriteCloser, error) {
+	filePath := s.filePath(bucket, path)
+	if err := s.fs.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return nil, err
+	}
+	return s.fs.Create(filePath)
+}
+
+func (s *ObjectStore) NewObjectReader(ctx context.Context, bucket, path string) (io.ReadCloser, error) {
+	f, err := s.fs.Open(s.filePath(bucket, path))
+	if os.IsNotExist(err) {
+		return nil, store.ErrResourceNotFound
+	}
+	return f, err
+}
+
+func (s *ObjectStore) Delete(ctx context.Context, bucket, path string) error {
+	err := s.fs.Remove(s.filePath(bucket, path))
+	if os.IsNotExist(err) {
+		return store.ErrResourceNotFound
+	}
+	return err
+}
+
+// List returns paths of files relative to bucket starting with prefix,
+// only the directory of prefix is walked
+func (s *ObjectStore) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	bucketDir := s.filePath(bucket, "")
+	walkDir := s.filePath(bucket, prefix[:strings.LastIndex(prefix, "/")+1])
+	var paths []string
+	err := afero.Walk(s.fs, walkDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && filePath == walkDir {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(bucketDir, filePath)
+		if err != nil {
+			return err
+		}
+		if relPath = filepath.ToSlash(relPath); strings.HasPrefix(relPath, prefix) {
+			paths = append(paths, relPath)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// Checksums reads files starting with prefix to hash them
+func (s *ObjectStore) Checksums(ctx context.Context, bucket, prefix string) (map[string]string, error) {
+	paths, err := s.List(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	checksums := map[string]string{}
+	for _, path := range paths {
+		content, err := afero.ReadFile(s.fs, s.filePath(bucket, path))
+		if err != nil {
+			return nil, err
+		}
+		sum := md5.Sum(content)
+		checksums[path] = hex.EncodeToString(sum[:])
+	}
+	return checksums, nil
+}
+
+func (s *ObjectStore) filePath(bucket, path string) string {
+	return filepath.Join(string(filepath.Separator), bucket, filepath.FromSlash(path))
+}
+
+// NewObjectStore constructs store of files on fs, file://host/path is the
+// file /host/path
+func NewObjectStore(fs afero.Fs) *ObjectStore {
+	return &ObjectStore{
+		fs: fs,
+	}
+}
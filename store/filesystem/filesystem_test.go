@@ -0,0 +1,72 @@
+package filesystem_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/odpf/optimus/store"
+	"github.com/odpf/optimus/store/filesystem"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectStore(t *testing.T) {
+	ctx := context.Background()
+	write := func(t *testing.T, objStore *filesystem.ObjectStore, bucket, path, content string) {
+		w, err := objStore.NewWriter(ctx, bucket, path)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+	}
+
+	t.Run("should write objects as files of bucket directory", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		objStore := filesystem.NewObjectStore(fs)
+		write(t, objStore, "", "mnt/airflow/dags/ns/job.py", "dag")
+
+		content, err := afero.ReadFile(fs, "/mnt/airflow/dags/ns/job.py")
+		assert.Nil(t, err)
+		assert.Equal(t, "dag", string(content))
+
+		r, err := objStore.NewObjectReader(ctx, "", "mnt/airflow/dags/ns/job.py")
+		assert.Nil(t, err)
+		content, err = ioutil.ReadAll(r)
+		assert.Nil(t, err)
+		assert.Equal(t, "dag", string(content))
+	})
+	t.Run("should list objects starting with prefix", func(t *testing.T) {
+		objStore := filesystem.NewObjectStore(afero.NewMemMapFs())
+		write(t, objStore, "nfs", "dags/__lib.py", "lib")
+		write(t, objStore, "nfs", "dags/ns-a/job-1.py", "dag")
+		write(t, objStore, "nfs", "dags/ns-b/job-2.py", "dag")
+		write(t, objStore, "nfs", "other/job-3.py", "dag")
+
+		paths, err := objStore.List(ctx, "nfs", "dags/ns-")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"dags/ns-a/job-1.py", "dags/ns-b/job-2.py"}, paths)
+
+		paths, err = objStore.List(ctx, "nfs", "missing/")
+		assert.Nil(t, err)
+		assert.Empty(t, paths)
+	})
+	t.Run("should hash contents of files starting with prefix", func(t *testing.T) {
+		objStore := filesystem.NewObjectStore(afero.NewMemMapFs())
+		write(t, objStore, "nfs", "dags/ns/job.py", "dag")
+		write(t, objStore, "nfs", "other/job.py", "dag")
+
+		checksums, err := objStore.Checksums(ctx, "nfs", "dags/")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"dags/ns/job.py": "b4683fef34f6bb7234f2603699bd0ded"}, checksums)
+	})
+	t.Run("should report objects not found", func(t *testing.T) {
+		objStore := filesystem.NewObjectStore(afero.NewMemMapFs())
+		write(t, objStore, "nfs", "dags/ns/job.py", "dag")
+		assert.Nil(t, objStore.Delete(ctx, "nfs", "dags/ns/job.py"))
+
+		assert.Equal(t, store.ErrResourceNotFound, objStore.Delete(ctx, "nfs", "dags/ns/job.py"))
+		_, err := objStore.NewObjectReader(ctx, "nfs", "dags/ns/job.py")
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+}
@@ -3,6 +3,7 @@ package gcs
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"path"
@@ -156,6 +157,31 @@ func (repo *JobRepository) ListNames(ctx context.Context, namespace models.Names
 	return jobNames, nil
 }
 
+// Checksums are md5 of objects kept by gcs, composite objects have none
+// and are left out
+func (repo *JobRepository) Checksums(ctx context.Context, namespace models.NamespaceSpec) (map[string]string, error) {
+	bucket := repo.Client.Bucket(repo.Bucket)
+	query := storage.Query{
+		Prefix: path.Join(repo.Prefix, namespace.ID.String()) + "/",
+	}
+	it := bucket.Objects(ctx, &query)
+
+	checksums := map[string]string{}
+	for {
+		objAttr, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(objAttr.Name, repo.Suffix) && len(objAttr.MD5) > 0 {
+			checksums[repo.jobNameFromPath(objAttr.Name)] = hex.EncodeToString(objAttr.MD5)
+		}
+	}
+	return checksums, nil
+}
+
 func (repo *JobRepository) GetByName(ctx context.Context, jobName string) (models.Job, error) {
 	if strings.TrimSpace(jobName) == "" {
 		return models.Job{}, errEmptyJobName
@@ -805,4 +805,59 @@ func TestJobRepository(t *testing.T) {
 			assert.Equal(t, jobs, result)
 		})
 	})
+	t.Run("Checksums", func(t *testing.T) {
+		bucket := "scheduled-bucket"
+		prefix := "resources/jobs"
+		namespaceSpec := models.NamespaceSpec{
+			ID:   uuid.Must(uuid.NewRandom()),
+			Name: "dev-team-1",
+		}
+		query := storage.Query{
+			Prefix: fmt.Sprintf("%s/%s/", prefix, namespaceSpec.ID),
+		}
+
+		t.Run("should return md5 of jobs of namespace", func(t *testing.T) {
+			objIterator := newObjectIteratorMock([]*storage.ObjectAttrs{
+				{Name: fmt.Sprintf("%s/%s/job-1.py", prefix, namespaceSpec.ID), MD5: []byte{0xb4, 0x68}},
+				{Name: fmt.Sprintf("%s/%s/job-2.py", prefix, namespaceSpec.ID)},
+				{Name: fmt.Sprintf("%s/%s/readme.md", prefix, namespaceSpec.ID), MD5: []byte{0x01}},
+			})
+			bucketHandle := new(storageBucketMock)
+			defer bucketHandle.AssertExpectations(t)
+			bucketHandle.On("Objects", ctx, &query).Return(objIterator)
+
+			client := new(storageClientMock)
+			defer client.AssertExpectations(t)
+			client.On("Bucket", bucket).Return(bucketHandle)
+
+			repo := &gcsStore.JobRepository{
+				Client: client,
+				Bucket: bucket,
+				Prefix: prefix,
+				Suffix: ".py",
+			}
+			checksums, err := repo.Checksums(ctx, namespaceSpec)
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]string{"job-1": "b468"}, checksums)
+		})
+		t.Run("should return error when failed to list the files", func(t *testing.T) {
+			expected := errors.New("failed to list objects")
+			bucketHandle := new(storageBucketMock)
+			defer bucketHandle.AssertExpectations(t)
+			bucketHandle.On("Objects", ctx, &query).Return(newErroneousObjectIteratorMock(expected))
+
+			client := new(storageClientMock)
+			defer client.AssertExpectations(t)
+			client.On("Bucket", bucket).Return(bucketHandle)
+
+			repo := &gcsStore.JobRepository{
+				Client: client,
+				Bucket: bucket,
+				Prefix: prefix,
+				Suffix: ".py",
+			}
+			_, err := repo.Checksums(ctx, namespaceSpec)
+			assert.Equal(t, expected, err)
+		})
+	})
 }
@@ -1,6 +1,7 @@
 package local
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,7 +16,7 @@ import (
 )
 
 const (
-	JobConfigVersion = 1
+	JobConfigVersion = models.JobSpecVersion
 )
 
 var (
@@ -31,10 +32,16 @@ func init() {
 // Job are inputs from user to create a job
 // yaml representation of the job
 type Job struct {
-	Version      int    `yaml:"version,omitempty" validate:"min=1,max=100"`
-	Name         string `validate:"min=3,max=1024"`
-	Owner        string `yaml:"owner" validate:"min=3,max=1024"`
-	Description  string `yaml:"description,omitempty"`
+	// APIVersion is the version of the format of the spec, specs of older
+	// versions are upgraded as they are read
+	APIVersion int `yaml:"apiVersion,omitempty" validate:"min=0,max=100"`
+	// Version is what apiVersion was written as before it, read only if
+	// apiVersion is not set
+	Version      int          `yaml:"version,omitempty" validate:"min=0,max=100"`
+	Name         string       `validate:"min=3,max=1024"`
+	Owner        string       `yaml:"owner" validate:"min=3,max=1024"`
+	Ownership    JobOwnership `yaml:"ownership,omitempty"`
+	Description  string       `yaml:"description,omitempty"`
 	Schedule     JobSchedule
 	Behavior     JobBehavior
 	Task         JobTask
@@ -42,19 +49,59 @@ type Job struct {
 	Labels       map[string]string `yaml:"labels,omitempty"`
 	Dependencies []JobDependency
 	Hooks        []JobHook
+	Lifecycle    JobLifecycle `yaml:"lifecycle,omitempty"`
+	Tests        []JobTest    `yaml:"tests,omitempty"`
+}
+
+// JobOwnership are contacts of people responsible for the job
+type JobOwnership struct {
+	Email        string `yaml:"email,omitempty" json:"email,omitempty"`
+	Team         string `yaml:"team,omitempty" json:"team,omitempty"`
+	SlackChannel string `yaml:"slack_channel,omitempty" json:"slack_channel,omitempty"`
 }
 
 type JobSchedule struct {
 	StartDate string `yaml:"start_date" json:"start_date" validate:"regexp=^\\d{4}-\\d{2}-\\d{2}$"`
 	EndDate   string `yaml:"end_date,omitempty" json:"end_date"`
 	Interval  string `yaml:"interval" validate:"isCron"`
+
+	Exceptions JobScheduleExceptions `yaml:"exceptions,omitempty" json:"exceptions,omitempty"`
+
+	// MaxActiveRuns caps runs of the job running at once, 1 runs them one
+	// after another, zero leaves it to the scheduler
+	MaxActiveRuns int `yaml:"max_active_runs,omitempty" json:"max_active_runs,omitempty" validate:"min=0"`
+}
+
+// JobScheduleExceptions are days the job deviates from its interval on
+type JobScheduleExceptions struct {
+	// SkipDates are days(YYYY-MM-DD) no run of the interval is scheduled on
+	SkipDates []string `yaml:"skip_dates,omitempty" json:"skip_dates,omitempty"`
+	// ExtraRuns are RFC3339 times job runs at in addition to its interval
+	ExtraRuns []string `yaml:"extra_runs,omitempty" json:"extra_runs,omitempty"`
+	// Calendars are names of holiday calendars configured in the project
+	Calendars []string `yaml:"calendars,omitempty" json:"calendars,omitempty"`
+}
+
+// JobLifecycle manages data the job wrote to its destination as it ages
+type JobLifecycle struct {
+	Retention JobRetention `yaml:"retention,omitempty" json:"retention,omitempty"`
+}
+
+// JobRetention drops partitions of destination once they are older than
+// PartitionDays days, e.g. 400. Zero inherits retention of parent
+// this.yaml, -1 keeps partitions forever whatever parents set
+type JobRetention struct {
+	PartitionDays int `yaml:"partition_days,omitempty" json:"partition_days,omitempty" validate:"min=-1"`
 }
 
 type JobBehavior struct {
-	DependsOnPast bool             `yaml:"depends_on_past" json:"depends_on_past"`
-	Catchup       bool             `yaml:"catch_up" json:"catch_up"`
-	Retry         JobBehaviorRetry `yaml:"retry,omitempty" json:"retry"`
-	Notify        []JobNotifier    `yaml:"notify,omitempty" json:"notify"`
+	DependsOnPast bool                `yaml:"depends_on_past" json:"depends_on_past"`
+	Catchup       bool                `yaml:"catch_up" json:"catch_up"`
+	Retry         JobBehaviorRetry    `yaml:"retry,omitempty" json:"retry"`
+	Notify        []JobNotifier       `yaml:"notify,omitempty" json:"notify"`
+	AutoHeal      JobBehaviorAutoHeal `yaml:"auto_heal,omitempty" json:"auto_heal,omitempty"`
+
+	ReplayPresets map[string]JobReplayPreset `yaml:"replay_presets,omitempty" json:"replay_presets,omitempty"`
 }
 
 type JobBehaviorRetry struct {
@@ -63,6 +110,26 @@ type JobBehaviorRetry struct {
 	ExponentialBackoff bool   `yaml:"exponential_backoff,omitempty" json:"exponential_backoff,omitempty"`
 }
 
+// JobBehaviorAutoHeal opts the job in to replay its failed runs automatically
+type JobBehaviorAutoHeal struct {
+	// Window is how far back failed runs are looked for, defaults to a day
+	Window    string `yaml:"window,omitempty" json:"window,omitempty"`
+	MaxPerDay int    `yaml:"max_per_day,omitempty" json:"max_per_day,omitempty"`
+}
+
+// JobReplayPreset is a replay of the job invoked by name, e.g.
+// optimus replay run <job> --preset last_week
+type JobReplayPreset struct {
+	// Start is a date(YYYY-MM-DD), RFC3339 time of a run or runs like
+	// last:7 and since-last-success, start of the job if empty
+	Start string `yaml:"start,omitempty" json:"start,omitempty"`
+	// End is a date or time of a run, the latest run if empty
+	End       string `yaml:"end,omitempty" json:"end,omitempty"`
+	Selector  string `yaml:"selector,omitempty" json:"selector,omitempty"`
+	Force     bool   `yaml:"force,omitempty" json:"force,omitempty"`
+	ChunkDays int    `yaml:"chunk_days,omitempty" json:"chunk_days,omitempty" validate:"min=0"`
+}
+
 type JobNotifier struct {
 	On       string `yaml:"on" json:"on" validate:"regexp=^(sla_miss|failure|)$"`
 	Config   map[string]string
@@ -70,9 +137,13 @@ type JobNotifier struct {
 }
 
 type JobTask struct {
-	Name   string
-	Config yaml.MapSlice `yaml:"config,omitempty"`
-	Window JobTaskWindow
+	Name    string
+	Version string        `yaml:"version,omitempty"`
+	Config  yaml.MapSlice `yaml:"config,omitempty"`
+	Window  JobTaskWindow
+	// TemplateEngine assets are compiled with, go or jinja, the one set
+	// for the project if empty
+	TemplateEngine string `yaml:"template_engine,omitempty" validate:"regexp=^(go|jinja)?$"`
 }
 
 type JobTaskWindow struct {
@@ -111,7 +182,8 @@ func (a JobHook) FromSpec(spec models.JobSpecHook) (JobHook, error) {
 // - zero values on parent are ignored
 // - slices are merged
 func (conf *Job) MergeFrom(parent Job) {
-	if conf.Version == 0 {
+	if conf.APIVersion == 0 && conf.Version == 0 {
+		conf.APIVersion = parent.APIVersion
 		conf.Version = parent.Version
 	}
 
@@ -124,6 +196,15 @@ func (conf *Job) MergeFrom(parent Job) {
 	if conf.Schedule.EndDate == "" {
 		conf.Schedule.EndDate = parent.Schedule.EndDate
 	}
+	if len(conf.Schedule.Exceptions.SkipDates) == 0 {
+		conf.Schedule.Exceptions.SkipDates = parent.Schedule.Exceptions.SkipDates
+	}
+	if len(conf.Schedule.Exceptions.Calendars) == 0 {
+		conf.Schedule.Exceptions.Calendars = parent.Schedule.Exceptions.Calendars
+	}
+	if conf.Schedule.MaxActiveRuns == 0 {
+		conf.Schedule.MaxActiveRuns = parent.Schedule.MaxActiveRuns
+	}
 
 	if conf.Behavior.Retry.ExponentialBackoff == false {
 		conf.Behavior.Retry.ExponentialBackoff = parent.Behavior.Retry.ExponentialBackoff
@@ -134,6 +215,21 @@ func (conf *Job) MergeFrom(parent Job) {
 	if conf.Behavior.Retry.Count == 0 {
 		conf.Behavior.Retry.Count = parent.Behavior.Retry.Count
 	}
+	if conf.Behavior.AutoHeal.Window == "" {
+		conf.Behavior.AutoHeal.Window = parent.Behavior.AutoHeal.Window
+	}
+	if conf.Behavior.AutoHeal.MaxPerDay == 0 {
+		conf.Behavior.AutoHeal.MaxPerDay = parent.Behavior.AutoHeal.MaxPerDay
+	}
+	for name, preset := range parent.Behavior.ReplayPresets {
+		if _, ok := conf.Behavior.ReplayPresets[name]; ok {
+			continue
+		}
+		if conf.Behavior.ReplayPresets == nil {
+			conf.Behavior.ReplayPresets = map[string]JobReplayPreset{}
+		}
+		conf.Behavior.ReplayPresets[name] = preset
+	}
 	if conf.Behavior.DependsOnPast == false {
 		conf.Behavior.DependsOnPast = parent.Behavior.DependsOnPast
 	}
@@ -182,6 +278,10 @@ func (conf *Job) MergeFrom(parent Job) {
 		}
 	}
 
+	if conf.Lifecycle.Retention.PartitionDays == 0 {
+		conf.Lifecycle.Retention.PartitionDays = parent.Lifecycle.Retention.PartitionDays
+	}
+
 	if conf.Description == "" {
 		conf.Description = parent.Description
 	}
@@ -189,6 +289,15 @@ func (conf *Job) MergeFrom(parent Job) {
 	if conf.Owner == "" {
 		conf.Owner = parent.Owner
 	}
+	if conf.Ownership.Email == "" {
+		conf.Ownership.Email = parent.Ownership.Email
+	}
+	if conf.Ownership.Team == "" {
+		conf.Ownership.Team = parent.Ownership.Team
+	}
+	if conf.Ownership.SlackChannel == "" {
+		conf.Ownership.SlackChannel = parent.Ownership.SlackChannel
+	}
 
 	if parent.Labels != nil {
 		if conf.Labels == nil {
@@ -222,6 +331,13 @@ func (conf *Job) MergeFrom(parent Job) {
 	if conf.Task.Name == "" {
 		conf.Task.Name = parent.Task.Name
 	}
+	// pinned version is only meaningful for the same plugin
+	if conf.Task.Version == "" && conf.Task.Name == parent.Task.Name {
+		conf.Task.Version = parent.Task.Version
+	}
+	if conf.Task.TemplateEngine == "" {
+		conf.Task.TemplateEngine = parent.Task.TemplateEngine
+	}
 	if conf.Task.Window.TruncateTo == "" {
 		conf.Task.Window.TruncateTo = parent.Task.Window.TruncateTo
 	}
@@ -326,8 +442,101 @@ func (conf *Job) prepareWindow() (models.JobSpecTaskWindow, error) {
 }
 
 type JobDependency struct {
-	JobName string `yaml:"job"`
-	Type    string `yaml:"type,omitempty"`
+	JobName   string                 `yaml:"job"`
+	Type      string                 `yaml:"type,omitempty"`
+	Sensor    JobDependencySensor    `yaml:"sensor,omitempty"`
+	Freshness JobDependencyFreshness `yaml:"freshness,omitempty"`
+}
+
+// JobDependencySensor configures the sensor waiting on a dependency, see
+// models.JobSpecDependencySensor
+type JobDependencySensor struct {
+	Timeout      string `yaml:"timeout,omitempty"`
+	PokeInterval string `yaml:"poke_interval,omitempty"`
+}
+
+// ToSpec parses durations of sensor, empty ones are left to the defaults of
+// scheduler
+func (s JobDependencySensor) ToSpec() (models.JobSpecDependencySensor, error) {
+	var sensor models.JobSpecDependencySensor
+	var err error
+	if s.Timeout != "" {
+		if sensor.Timeout, err = time.ParseDuration(s.Timeout); err != nil {
+			return sensor, errors.Wrap(err, "invalid sensor timeout")
+		}
+	}
+	if s.PokeInterval != "" {
+		if sensor.PokeInterval, err = time.ParseDuration(s.PokeInterval); err != nil {
+			return sensor, errors.Wrap(err, "invalid sensor poke interval")
+		}
+	}
+	if sensor.Timeout < 0 || sensor.PokeInterval < 0 {
+		return sensor, errors.New("sensor timeout and poke interval can't be negative")
+	}
+	if sensor.Timeout > 0 && sensor.PokeInterval > sensor.Timeout {
+		return sensor, errors.New("sensor poke interval can't be longer than its timeout")
+	}
+	return sensor, nil
+}
+
+// FromSpec formats durations of sensor, defaults of scheduler are left empty
+func (s JobDependencySensor) FromSpec(sensor models.JobSpecDependencySensor) JobDependencySensor {
+	if sensor.Timeout > 0 {
+		s.Timeout = sensor.Timeout.String()
+	}
+	if sensor.PokeInterval > 0 {
+		s.PokeInterval = sensor.PokeInterval.String()
+	}
+	return s
+}
+
+// JobDependencyFreshness asserts freshness of destination of a dependency,
+// see models.JobSpecDependencyFreshness
+type JobDependencyFreshness struct {
+	Enabled   bool   `yaml:"enabled,omitempty"`
+	Tolerance string `yaml:"tolerance,omitempty"`
+}
+
+// ToSpec parses tolerance of freshness, empty one tolerates no delay
+func (f JobDependencyFreshness) ToSpec() (models.JobSpecDependencyFreshness, error) {
+	freshness := models.JobSpecDependencyFreshness{Enabled: f.Enabled}
+	if f.Tolerance != "" {
+		var err error
+		if freshness.Tolerance, err = time.ParseDuration(f.Tolerance); err != nil {
+			return freshness, errors.Wrap(err, "invalid freshness tolerance")
+		}
+	}
+	if freshness.Tolerance < 0 {
+		return freshness, errors.New("freshness tolerance can't be negative")
+	}
+	return freshness, nil
+}
+
+// FromSpec formats tolerance of freshness, no tolerance is left empty
+func (f JobDependencyFreshness) FromSpec(freshness models.JobSpecDependencyFreshness) JobDependencyFreshness {
+	f.Enabled = freshness.Enabled
+	if freshness.Tolerance > 0 {
+		f.Tolerance = freshness.Tolerance.String()
+	}
+	return f
+}
+
+// JobTest is a test case of the query of job, see models.JobSpecTest
+type JobTest struct {
+	Name        string             `yaml:"name" validate:"min=1"`
+	ScheduledAt string             `yaml:"scheduled_at,omitempty"`
+	Inputs      []JobTestInput     `yaml:"inputs,omitempty"`
+	Expected    JobTestExpectation `yaml:"expected"`
+}
+
+type JobTestInput struct {
+	Table string                   `yaml:"table" validate:"min=1"`
+	Rows  []map[string]interface{} `yaml:"rows"`
+}
+
+type JobTestExpectation struct {
+	Rows    []map[string]interface{} `yaml:"rows"`
+	Ordered bool                     `yaml:"ordered,omitempty"`
 }
 
 type JobSpecAdapter struct {
@@ -363,8 +572,18 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 		case string(models.JobSpecDependencyTypeExtra):
 			depType = models.JobSpecDependencyTypeExtra
 		}
+		sensor, err := dep.Sensor.ToSpec()
+		if err != nil {
+			return models.JobSpec{}, errors.Wrapf(err, "dependency %s", dep.JobName)
+		}
+		freshness, err := dep.Freshness.ToSpec()
+		if err != nil {
+			return models.JobSpec{}, errors.Wrapf(err, "dependency %s", dep.JobName)
+		}
 		dependencies[dep.JobName] = models.JobSpecDependency{
-			Type: depType,
+			Type:      depType,
+			Sensor:    sensor,
+			Freshness: freshness,
 		}
 	}
 
@@ -410,6 +629,51 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 		}
 	}
 
+	autoHealWindow := time.Duration(0)
+	if conf.Behavior.AutoHeal.Window != "" {
+		autoHealWindow, err = time.ParseDuration(conf.Behavior.AutoHeal.Window)
+		if err != nil {
+			return models.JobSpec{}, err
+		}
+	}
+
+	var replayPresets map[string]models.JobSpecReplayPreset
+	for name, preset := range conf.Behavior.ReplayPresets {
+		if _, err := models.ParseLabelSelector(preset.Selector); err != nil {
+			return models.JobSpec{}, errors.Wrapf(err, "invalid selector of replay preset %s", name)
+		}
+		if preset.ChunkDays < 0 {
+			return models.JobSpec{}, errors.Errorf("invalid chunk days of replay preset %s: %d", name, preset.ChunkDays)
+		}
+		if replayPresets == nil {
+			replayPresets = map[string]models.JobSpecReplayPreset{}
+		}
+		replayPresets[name] = models.JobSpecReplayPreset{
+			Start:     preset.Start,
+			End:       preset.End,
+			Selector:  preset.Selector,
+			Force:     preset.Force,
+			ChunkDays: preset.ChunkDays,
+		}
+	}
+
+	var skipDates []time.Time
+	for _, raw := range conf.Schedule.Exceptions.SkipDates {
+		date, err := time.Parse(models.JobDatetimeLayout, raw)
+		if err != nil {
+			return models.JobSpec{}, errors.Wrapf(err, "invalid skip date %s", raw)
+		}
+		skipDates = append(skipDates, date)
+	}
+	var extraRuns []time.Time
+	for _, raw := range conf.Schedule.Exceptions.ExtraRuns {
+		run, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return models.JobSpec{}, errors.Wrapf(err, "invalid extra run %s", raw)
+		}
+		extraRuns = append(extraRuns, run.UTC())
+	}
+
 	var jobNotifiers []models.JobSpecNotifier
 	for _, notify := range conf.Behavior.Notify {
 		jobNotifiers = append(jobNotifiers, models.JobSpecNotifier{
@@ -419,16 +683,50 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 		})
 	}
 
+	var tests []models.JobSpecTest
+	for _, test := range conf.Tests {
+		specTest := models.JobSpecTest{
+			Name:        test.Name,
+			ScheduledAt: test.ScheduledAt,
+			Expected: models.JobSpecTestExpectation{
+				Rows:    testRowsFromYaml(test.Expected.Rows),
+				Ordered: test.Expected.Ordered,
+			},
+		}
+		for _, input := range test.Inputs {
+			specTest.Inputs = append(specTest.Inputs, models.JobSpecTestInput{
+				Table: input.Table,
+				Rows:  testRowsFromYaml(input.Rows),
+			})
+		}
+		tests = append(tests, specTest)
+	}
+
+	version := conf.APIVersion
+	if version == 0 {
+		version = conf.Version
+	}
 	job := models.JobSpec{
-		Version:     conf.Version,
-		Name:        strings.TrimSpace(conf.Name),
-		Owner:       conf.Owner,
+		Version: version,
+		Name:    strings.TrimSpace(conf.Name),
+		Owner:   conf.Owner,
+		Ownership: models.JobSpecOwnership{
+			Email:        conf.Ownership.Email,
+			Team:         conf.Ownership.Team,
+			SlackChannel: conf.Ownership.SlackChannel,
+		},
 		Description: conf.Description,
 		Labels:      labels,
 		Schedule: models.JobSpecSchedule{
 			StartDate: startDate,
 			EndDate:   endDate,
 			Interval:  conf.Schedule.Interval,
+			Exceptions: models.JobSpecScheduleExceptions{
+				SkipDates: skipDates,
+				ExtraRuns: extraRuns,
+				Calendars: conf.Schedule.Exceptions.Calendars,
+			},
+			MaxActiveRuns: conf.Schedule.MaxActiveRuns,
 		},
 		Behavior: models.JobSpecBehavior{
 			CatchUp:       conf.Behavior.Catchup,
@@ -439,17 +737,30 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 				ExponentialBackoff: conf.Behavior.Retry.ExponentialBackoff,
 			},
 			Notify: jobNotifiers,
+			AutoHeal: models.JobSpecBehaviorAutoHeal{
+				Window:    autoHealWindow,
+				MaxPerDay: conf.Behavior.AutoHeal.MaxPerDay,
+			},
+			ReplayPresets: replayPresets,
 		},
 		Task: models.JobSpecTask{
-			Unit:   execUnit,
-			Config: taskConf,
-			Window: window,
+			Unit:           execUnit,
+			Version:        conf.Task.Version,
+			Config:         taskConf,
+			Window:         window,
+			TemplateEngine: conf.Task.TemplateEngine,
 		},
 		Assets:       models.JobAssets{}.FromMap(conf.Asset),
 		Dependencies: dependencies,
 		Hooks:        hooks,
+		Lifecycle: models.JobSpecLifecycle{
+			Retention: models.JobSpecRetention{
+				PartitionDays: conf.Lifecycle.Retention.PartitionDays,
+			},
+		},
+		Tests: tests,
 	}
-	return job, nil
+	return models.SpecMigrations.MigrateJob(job)
 }
 
 func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
@@ -475,6 +786,11 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		retryDelayDuration = spec.Behavior.Retry.Delay.String()
 	}
 
+	autoHealWindow := ""
+	if spec.Behavior.AutoHeal.Window.Nanoseconds() > 0 {
+		autoHealWindow = spec.Behavior.AutoHeal.Window.String()
+	}
+
 	var notifiers []JobNotifier
 	for _, notify := range spec.Behavior.Notify {
 		notifiers = append(notifiers, JobNotifier{
@@ -484,15 +800,35 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		})
 	}
 
+	var replayPresets map[string]JobReplayPreset
+	for name, preset := range spec.Behavior.ReplayPresets {
+		if replayPresets == nil {
+			replayPresets = map[string]JobReplayPreset{}
+		}
+		replayPresets[name] = JobReplayPreset{
+			Start:     preset.Start,
+			End:       preset.End,
+			Selector:  preset.Selector,
+			Force:     preset.Force,
+			ChunkDays: preset.ChunkDays,
+		}
+	}
+
 	parsed := Job{
-		Version:     spec.Version,
-		Name:        spec.Name,
-		Owner:       spec.Owner,
+		APIVersion: spec.Version,
+		Name:       spec.Name,
+		Owner:      spec.Owner,
+		Ownership: JobOwnership{
+			Email:        spec.Ownership.Email,
+			Team:         spec.Ownership.Team,
+			SlackChannel: spec.Ownership.SlackChannel,
+		},
 		Description: spec.Description,
 		Labels:      labels,
 		Schedule: JobSchedule{
-			Interval:  spec.Schedule.Interval,
-			StartDate: spec.Schedule.StartDate.Format(models.JobDatetimeLayout),
+			Interval:      spec.Schedule.Interval,
+			StartDate:     spec.Schedule.StartDate.Format(models.JobDatetimeLayout),
+			MaxActiveRuns: spec.Schedule.MaxActiveRuns,
 		},
 		Behavior: JobBehavior{
 			DependsOnPast: spec.Behavior.DependsOnPast,
@@ -503,10 +839,17 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 				ExponentialBackoff: spec.Behavior.Retry.ExponentialBackoff,
 			},
 			Notify: notifiers,
+			AutoHeal: JobBehaviorAutoHeal{
+				Window:    autoHealWindow,
+				MaxPerDay: spec.Behavior.AutoHeal.MaxPerDay,
+			},
+			ReplayPresets: replayPresets,
 		},
 		Task: JobTask{
-			Name:   spec.Task.Unit.Info().Name,
-			Config: taskConf,
+			Name:           spec.Task.Unit.Info().Name,
+			Version:        spec.Task.Version,
+			Config:         taskConf,
+			TemplateEngine: spec.Task.TemplateEngine,
 			Window: JobTaskWindow{
 				Size:       spec.Task.Window.SizeString(),
 				Offset:     spec.Task.Window.OffsetString(),
@@ -516,15 +859,29 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		Asset:        spec.Assets.ToMap(),
 		Dependencies: []JobDependency{},
 		Hooks:        []JobHook{},
+		Lifecycle: JobLifecycle{
+			Retention: JobRetention{
+				PartitionDays: spec.Lifecycle.Retention.PartitionDays,
+			},
+		},
 	}
 
 	if spec.Schedule.EndDate != nil {
 		parsed.Schedule.EndDate = spec.Schedule.EndDate.Format(models.JobDatetimeLayout)
 	}
+	for _, date := range spec.Schedule.Exceptions.SkipDates {
+		parsed.Schedule.Exceptions.SkipDates = append(parsed.Schedule.Exceptions.SkipDates, date.Format(models.JobDatetimeLayout))
+	}
+	for _, run := range spec.Schedule.Exceptions.ExtraRuns {
+		parsed.Schedule.Exceptions.ExtraRuns = append(parsed.Schedule.Exceptions.ExtraRuns, run.Format(time.RFC3339))
+	}
+	parsed.Schedule.Exceptions.Calendars = spec.Schedule.Exceptions.Calendars
 	for name, dep := range spec.Dependencies {
 		parsed.Dependencies = append(parsed.Dependencies, JobDependency{
-			JobName: name,
-			Type:    dep.Type.String(),
+			JobName:   name,
+			Type:      dep.Type.String(),
+			Sensor:    JobDependencySensor{}.FromSpec(dep.Sensor),
+			Freshness: JobDependencyFreshness{}.FromSpec(dep.Freshness),
 		})
 	}
 
@@ -537,6 +894,24 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		parsed.Hooks = append(parsed.Hooks, h)
 	}
 
+	for _, test := range spec.Tests {
+		localTest := JobTest{
+			Name:        test.Name,
+			ScheduledAt: test.ScheduledAt,
+			Expected: JobTestExpectation{
+				Rows:    test.Expected.Rows,
+				Ordered: test.Expected.Ordered,
+			},
+		}
+		for _, input := range test.Inputs {
+			localTest.Inputs = append(localTest.Inputs, JobTestInput{
+				Table: input.Table,
+				Rows:  input.Rows,
+			})
+		}
+		parsed.Tests = append(parsed.Tests, localTest)
+	}
+
 	return parsed, nil
 }
 
@@ -568,6 +943,38 @@ func JobSpecConfigFromYamlSlice(conf yaml.MapSlice) models.JobSpecConfigs {
 	return conv
 }
 
+// testRowsFromYaml converts maps nested in rows of a test, which yaml decodes
+// with interface keys, to maps with string keys
+func testRowsFromYaml(rows []map[string]interface{}) []map[string]interface{} {
+	var converted []map[string]interface{}
+	for _, row := range rows {
+		convertedRow := map[string]interface{}{}
+		for column, value := range row {
+			convertedRow[column] = testValueFromYaml(value)
+		}
+		converted = append(converted, convertedRow)
+	}
+	return converted
+}
+
+func testValueFromYaml(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := map[string]interface{}{}
+		for key, val := range v {
+			converted[fmt.Sprintf("%v", key)] = testValueFromYaml(val)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, val := range v {
+			converted[i] = testValueFromYaml(val)
+		}
+		return converted
+	}
+	return value
+}
+
 // check if string contains monthly notation
 func tryParsingInMonths(str string) (time.Duration, error) {
 	sz := time.Duration(0)
@@ -3,6 +3,7 @@ package local_test
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/odpf/optimus/models"
 
@@ -16,7 +17,7 @@ import (
 func TestSpecAdapter(t *testing.T) {
 	t.Run("should convert job with task from yaml to optimus model & back successfully", func(t *testing.T) {
 		yamlSpec := `
-version: 1
+apiVersion: 1
 name: test_job
 owner: test@example.com
 schedule:
@@ -69,6 +70,315 @@ hooks: []
 
 		assert.Equal(t, localJobParsed, localJobBack)
 	})
+	t.Run("should convert replay presets of job and reject invalid ones", func(t *testing.T) {
+		yamlSpec := `
+apiVersion: 1
+name: test_job
+owner: test@example.com
+schedule:
+  start_date: "2021-02-03"
+  interval: 0 2 * * *
+behavior:
+  depends_on_past: false
+  catch_up: false
+  replay_presets:
+    last_week:
+      start: last:7
+      selector: tier=critical
+    full_history:
+      force: true
+      chunk_days: 30
+task:
+  name: bq2bq
+  window:
+    size: 24h
+    offset: 0
+    truncate_to: d
+dependencies: []
+hooks: []
+`
+		var localJobParsed local.Job
+		err := yaml.Unmarshal([]byte(yamlSpec), &localJobParsed)
+		assert.Nil(t, err)
+
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "bq2bq",
+		}, nil)
+
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{
+			Base: execUnit,
+		}, nil)
+		adapter := local.NewJobSpecAdapter(pluginRepo)
+
+		modelJob, err := adapter.ToSpec(localJobParsed)
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]models.JobSpecReplayPreset{
+			"last_week":    {Start: "last:7", Selector: "tier=critical"},
+			"full_history": {Force: true, ChunkDays: 30},
+		}, modelJob.Behavior.ReplayPresets)
+
+		localJobBack, err := adapter.FromSpec(modelJob)
+		assert.Nil(t, err)
+		assert.Equal(t, localJobParsed.Behavior.ReplayPresets, localJobBack.Behavior.ReplayPresets)
+
+		localJobParsed.Behavior.ReplayPresets["last_week"] = local.JobReplayPreset{Start: "last:7", Selector: "=critical"}
+		_, err = adapter.ToSpec(localJobParsed)
+		assert.NotNil(t, err)
+	})
+	t.Run("should convert retention of destination of job", func(t *testing.T) {
+		yamlSpec := `
+apiVersion: 1
+name: test_job
+owner: test@example.com
+schedule:
+  start_date: "2021-02-03"
+  interval: 0 2 * * *
+task:
+  name: bq2bq
+  window:
+    size: 24h
+    offset: 0
+    truncate_to: d
+lifecycle:
+  retention:
+    partition_days: 400
+dependencies: []
+hooks: []
+`
+		var localJobParsed local.Job
+		err := yaml.Unmarshal([]byte(yamlSpec), &localJobParsed)
+		assert.Nil(t, err)
+
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "bq2bq",
+		}, nil)
+
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{
+			Base: execUnit,
+		}, nil)
+		adapter := local.NewJobSpecAdapter(pluginRepo)
+
+		modelJob, err := adapter.ToSpec(localJobParsed)
+		assert.Nil(t, err)
+		assert.Equal(t, models.JobSpecRetention{PartitionDays: 400}, modelJob.Lifecycle.Retention)
+		assert.Equal(t, time.Hour*24*400, modelJob.Lifecycle.Retention.PartitionRetention())
+
+		localJobBack, err := adapter.FromSpec(modelJob)
+		assert.Nil(t, err)
+		assert.Equal(t, localJobParsed.Lifecycle, localJobBack.Lifecycle)
+	})
+	t.Run("should convert sensors of dependencies and reject invalid ones", func(t *testing.T) {
+		yamlSpec := `
+apiVersion: 1
+name: test_job
+owner: test@example.com
+schedule:
+  start_date: "2021-02-03"
+  interval: 0 2 * * *
+task:
+  name: bq2bq
+  window:
+    size: 24h
+    offset: 0
+    truncate_to: d
+dependencies:
+  - job: upstream_job
+    sensor:
+      timeout: 6h
+      poke_interval: 10m
+`
+		var localJobParsed local.Job
+		err := yaml.Unmarshal([]byte(yamlSpec), &localJobParsed)
+		assert.Nil(t, err)
+
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "bq2bq",
+		}, nil)
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{
+			Base: execUnit,
+		}, nil)
+		adapter := local.NewJobSpecAdapter(pluginRepo)
+
+		modelJob, err := adapter.ToSpec(localJobParsed)
+		assert.Nil(t, err)
+		assert.Equal(t, models.JobSpecDependencySensor{
+			Timeout:      6 * time.Hour,
+			PokeInterval: 10 * time.Minute,
+		}, modelJob.Dependencies["upstream_job"].Sensor)
+
+		localJobBack, err := adapter.FromSpec(modelJob)
+		assert.Nil(t, err)
+		assert.Equal(t, local.JobDependencySensor{Timeout: "6h0m0s", PokeInterval: "10m0s"}, localJobBack.Dependencies[0].Sensor)
+
+		localJobParsed.Dependencies[0].Sensor = local.JobDependencySensor{Timeout: "10m", PokeInterval: "1h"}
+		_, err = adapter.ToSpec(localJobParsed)
+		assert.NotNil(t, err)
+	})
+	t.Run("should convert freshness of dependencies and reject negative tolerance", func(t *testing.T) {
+		yamlSpec := `
+apiVersion: 1
+name: test_job
+owner: test@example.com
+schedule:
+  start_date: "2021-02-03"
+  interval: 0 2 * * *
+task:
+  name: bq2bq
+  window:
+    size: 24h
+    offset: 0
+    truncate_to: d
+dependencies:
+  - job: upstream_job
+    freshness:
+      enabled: true
+      tolerance: 2h
+  - job: other_upstream_job
+`
+		var localJobParsed local.Job
+		err := yaml.Unmarshal([]byte(yamlSpec), &localJobParsed)
+		assert.Nil(t, err)
+
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "bq2bq",
+		}, nil)
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{
+			Base: execUnit,
+		}, nil)
+		adapter := local.NewJobSpecAdapter(pluginRepo)
+
+		modelJob, err := adapter.ToSpec(localJobParsed)
+		assert.Nil(t, err)
+		assert.Equal(t, models.JobSpecDependencyFreshness{
+			Enabled:   true,
+			Tolerance: 2 * time.Hour,
+		}, modelJob.Dependencies["upstream_job"].Freshness)
+		assert.False(t, modelJob.Dependencies["other_upstream_job"].Freshness.Enabled)
+
+		localJobBack, err := adapter.FromSpec(modelJob)
+		assert.Nil(t, err)
+		for _, dep := range localJobBack.Dependencies {
+			if dep.JobName == "upstream_job" {
+				assert.Equal(t, local.JobDependencyFreshness{Enabled: true, Tolerance: "2h0m0s"}, dep.Freshness)
+			}
+		}
+
+		localJobParsed.Dependencies[0].Freshness.Tolerance = "-1h"
+		_, err = adapter.ToSpec(localJobParsed)
+		assert.NotNil(t, err)
+	})
+	t.Run("should convert tests with nested values of rows keyed by strings", func(t *testing.T) {
+		yamlSpec := `
+apiVersion: 1
+name: test_job
+owner: test@example.com
+schedule:
+  start_date: "2021-02-03"
+  interval: 0 2 * * *
+task:
+  name: bq2bq
+  window:
+    size: 24h
+    offset: 0
+    truncate_to: d
+tests:
+  - name: sums_orders
+    scheduled_at: "2021-02-04"
+    inputs:
+      - table: project.raw.orders
+        rows:
+          - {id: 1, customer: {name: kid}, items: [{sku: toy}]}
+    expected:
+      rows:
+        - {total: 1}
+      ordered: true
+`
+		var localJobParsed local.Job
+		err := yaml.Unmarshal([]byte(yamlSpec), &localJobParsed)
+		assert.Nil(t, err)
+
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "bq2bq",
+		}, nil)
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{
+			Base: execUnit,
+		}, nil)
+		adapter := local.NewJobSpecAdapter(pluginRepo)
+
+		modelJob, err := adapter.ToSpec(localJobParsed)
+		assert.Nil(t, err)
+		assert.Equal(t, []models.JobSpecTest{
+			{
+				Name:        "sums_orders",
+				ScheduledAt: "2021-02-04",
+				Inputs: []models.JobSpecTestInput{
+					{
+						Table: "project.raw.orders",
+						Rows: []map[string]interface{}{{
+							"id":       1,
+							"customer": map[string]interface{}{"name": "kid"},
+							"items":    []interface{}{map[string]interface{}{"sku": "toy"}},
+						}},
+					},
+				},
+				Expected: models.JobSpecTestExpectation{
+					Rows:    []map[string]interface{}{{"total": 1}},
+					Ordered: true,
+				},
+			},
+		}, modelJob.Tests)
+
+		localJobBack, err := adapter.FromSpec(modelJob)
+		assert.Nil(t, err)
+		assert.Equal(t, "sums_orders", localJobBack.Tests[0].Name)
+	})
+	t.Run("should read version of specs written before apiVersion and reject newer versions", func(t *testing.T) {
+		execUnit := new(mock.BasePlugin)
+		execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+			Name: "bq2bq",
+		}, nil)
+		pluginRepo := new(mock.SupportedPluginRepo)
+		pluginRepo.On("GetByName", "bq2bq").Return(&models.Plugin{
+			Base: execUnit,
+		}, nil)
+		adapter := local.NewJobSpecAdapter(pluginRepo)
+		localJob := local.Job{
+			Name:     "test_job",
+			Schedule: local.JobSchedule{StartDate: "2021-02-03", Interval: "0 2 * * *"},
+			Task: local.JobTask{
+				Name:   "bq2bq",
+				Window: local.JobTaskWindow{Size: "24h", Offset: "0", TruncateTo: "d"},
+			},
+		}
+
+		modelJob, err := adapter.ToSpec(localJob)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, modelJob.Version)
+
+		localJob.Version = 1
+		modelJob, err = adapter.ToSpec(localJob)
+		assert.Nil(t, err)
+		assert.Equal(t, models.JobSpecVersion, modelJob.Version)
+
+		localJobBack, err := adapter.FromSpec(modelJob)
+		assert.Nil(t, err)
+		assert.Equal(t, models.JobSpecVersion, localJobBack.APIVersion)
+		assert.Equal(t, 0, localJobBack.Version)
+
+		localJob.APIVersion = models.JobSpecVersion + 1
+		_, err = adapter.ToSpec(localJob)
+		assert.NotNil(t, err)
+	})
 }
 
 func TestJob_MergeFrom(t *testing.T) {
@@ -84,6 +394,46 @@ func TestJob_MergeFrom(t *testing.T) {
 		fields fields
 		args   args
 	}{
+		{
+			name: "should inherit retention of destination if child has none",
+			fields: fields{
+				child: local.Job{},
+				expected: local.Job{
+					Lifecycle: local.JobLifecycle{
+						Retention: local.JobRetention{PartitionDays: 400},
+					},
+				},
+			},
+			args: args{
+				parent: local.Job{
+					Lifecycle: local.JobLifecycle{
+						Retention: local.JobRetention{PartitionDays: 400},
+					},
+				},
+			},
+		},
+		{
+			name: "should keep retention of destination disabled by child",
+			fields: fields{
+				child: local.Job{
+					Lifecycle: local.JobLifecycle{
+						Retention: local.JobRetention{PartitionDays: models.RetentionDisabled},
+					},
+				},
+				expected: local.Job{
+					Lifecycle: local.JobLifecycle{
+						Retention: local.JobRetention{PartitionDays: models.RetentionDisabled},
+					},
+				},
+			},
+			args: args{
+				parent: local.Job{
+					Lifecycle: local.JobLifecycle{
+						Retention: local.JobRetention{PartitionDays: 400},
+					},
+				},
+			},
+		},
 		{
 			name: "should successfully copy version if child has zero value",
 			fields: fields{
@@ -376,6 +726,7 @@ func TestJob_MergeFrom(t *testing.T) {
 			assert.Equal(t, tt.fields.expected.Task.Window.Size, tt.fields.child.Task.Window.Size)
 			assert.Equal(t, tt.fields.expected.Task.Window.TruncateTo, tt.fields.child.Task.Window.TruncateTo)
 			assert.ElementsMatch(t, tt.fields.expected.Task.Config, tt.fields.child.Task.Config)
+			assert.Equal(t, tt.fields.expected.Lifecycle, tt.fields.child.Lifecycle)
 			for idx, eh := range tt.fields.expected.Hooks {
 				assert.Equal(t, eh.Name, tt.fields.child.Hooks[idx].Name)
 				assert.ElementsMatch(t, eh.Config, tt.fields.child.Hooks[idx].Config)
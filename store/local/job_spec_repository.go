@@ -1,11 +1,13 @@
 package local
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/spf13/afero"
@@ -14,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	"gopkg.in/validator.v2"
 	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 const (
@@ -148,11 +151,143 @@ func (repo *jobRepository) GetByDestination(jobName string) (models.JobSpec, mod
 	panic("GetByDestination() should not be invoked with local.JobSpecRepo")
 }
 
+func (repo *jobRepository) GetDestinations() ([]models.JobDestination, error) {
+	panic("GetDestinations() should not be invoked with local.JobSpecRepo")
+}
+
 // Delete deletes a requested job by name
 func (repo *jobRepository) Delete(jobName string) error {
 	panic("unimplemented")
 }
 
+// Rename renames a job, moving its directory along if it is named after the
+// job, and rewrites dependencies of other jobs on it. Job files are edited in
+// place keeping the rest of them, comments included, names of the jobs whose
+// dependencies were rewritten are returned
+func (repo *jobRepository) Rename(oldName, newName string) ([]string, error) {
+	if strings.TrimSpace(newName) == "" || newName == oldName {
+		return nil, errors.Errorf("invalid name %s to rename job %s to", newName, oldName)
+	}
+	if repo.cache.dirty {
+		if err := repo.refreshCache(); err != nil {
+			return nil, err
+		}
+	}
+	existingJob, ok := repo.cache.data[oldName]
+	if !ok {
+		return nil, models.ErrNoSuchSpec
+	}
+	if _, ok := repo.cache.data[newName]; ok {
+		return nil, errors.Errorf("job %s already exists", newName)
+	}
+	defer func() { repo.cache.dirty = true }()
+
+	renamed, err := repo.rewriteJobFile(existingJob.path, func(doc *yamlv3.Node) bool {
+		name := yamlMappingValue(doc, "name")
+		if name == nil || name.Value != oldName {
+			return false
+		}
+		name.Value = newName
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !renamed {
+		return nil, errors.Errorf("name of job %s not found in %s", oldName, repo.jobFilePath(existingJob.path))
+	}
+	oldPath, newPath := existingJob.path, existingJob.path
+	if filepath.Base(oldPath) == oldName {
+		newPath = filepath.Join(filepath.Dir(oldPath), newName)
+		if exists, err := afero.Exists(repo.fs, newPath); err != nil {
+			return nil, err
+		} else if exists {
+			return nil, errors.Errorf("can't move job %s to %s, it already exists", oldName, newPath)
+		}
+		if err := repo.fs.Rename(oldPath, newPath); err != nil {
+			return nil, errors.Wrapf(err, "repo.fs.Rename: %s", oldPath)
+		}
+	}
+
+	var dependents []string
+	for jobName, item := range repo.cache.data {
+		if jobName == oldName {
+			continue
+		}
+		// jobs nested in the directory of the job moved along with it
+		jobPath := item.path
+		if strings.HasPrefix(jobPath, oldPath+string(filepath.Separator)) {
+			jobPath = newPath + strings.TrimPrefix(jobPath, oldPath)
+		}
+		rewritten, err := repo.rewriteJobFile(jobPath, func(doc *yamlv3.Node) bool {
+			deps := yamlMappingValue(doc, "dependencies")
+			if deps == nil || deps.Kind != yamlv3.SequenceNode {
+				return false
+			}
+			rewritten := false
+			for _, dep := range deps.Content {
+				if depName := yamlMappingValue(dep, "job"); depName != nil && depName.Value == oldName {
+					depName.Value = newName
+					rewritten = true
+				}
+			}
+			return rewritten
+		})
+		if err != nil {
+			return dependents, err
+		}
+		if rewritten {
+			dependents = append(dependents, jobName)
+		}
+	}
+	sort.Strings(dependents)
+	return dependents, nil
+}
+
+// rewriteJobFile applies rewrite to the job file of dirName, the file is
+// written back only if rewrite changed it
+func (repo *jobRepository) rewriteJobFile(dirName string, rewrite func(doc *yamlv3.Node) bool) (bool, error) {
+	raw, err := afero.ReadFile(repo.fs, repo.jobFilePath(dirName))
+	if err != nil {
+		return false, err
+	}
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(raw, &root); err != nil {
+		return false, errors.Wrapf(err, "error parsing job spec in %s", dirName)
+	}
+	if len(root.Content) == 0 || !rewrite(root.Content[0]) {
+		return false, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return false, err
+	}
+	if err := enc.Close(); err != nil {
+		return false, err
+	}
+	if err := afero.WriteFile(repo.fs, repo.jobFilePath(dirName), buf.Bytes(), os.FileMode(0755)); err != nil {
+		return false, errors.Wrapf(err, "WriteFile: %s", repo.jobFilePath(dirName))
+	}
+	return true, nil
+}
+
+// yamlMappingValue returns value of key in a yaml mapping, nil if node isn't
+// a mapping or the key is missing
+func yamlMappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
 func (repo *jobRepository) refreshCache() error {
 	repo.cache.dirty = true
 	repo.cache.data = make(map[string]cacheItem)
@@ -18,7 +18,7 @@ import (
 	"github.com/odpf/optimus/mock"
 )
 
-var testJobContents = `version: 1
+var testJobContents = `apiVersion: 1
 name: test
 owner: optimus
 schedule:
@@ -553,4 +553,58 @@ hooks: []`,
 			assert.Equal(t, len(result), len(resultAgain))
 		})
 	})
+	t.Run("Rename", func(t *testing.T) {
+		upstreamContents := "# source of test\n" + strings.Replace(strings.Replace(testJobContents,
+			"name: test", "name: bar", 1), "dependencies:\n- job: bar\n", "", 1)
+		newFs := func(t *testing.T) afero.Fs {
+			appFS := afero.NewBasePathFs(afero.NewOsFs(), t.TempDir())
+			appFS.MkdirAll("bar", 0755)
+			afero.WriteFile(appFS, filepath.Join("bar", local.JobSpecFileName), []byte(upstreamContents), 0644)
+			appFS.MkdirAll(filepath.Join("team", "test"), 0755)
+			afero.WriteFile(appFS, filepath.Join("team", "test", local.JobSpecFileName), []byte(testJobContents), 0644)
+			return appFS
+		}
+		t.Run("should rename job and its directory and rewrite dependencies on it", func(t *testing.T) {
+			appFS := newFs(t)
+			repo := local.NewJobSpecRepository(appFS, adapter)
+			dependents, err := repo.Rename("bar", "baz")
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"test"}, dependents)
+
+			exists, _ := afero.DirExists(appFS, "bar")
+			assert.False(t, exists)
+			raw, err := afero.ReadFile(appFS, filepath.Join("baz", local.JobSpecFileName))
+			assert.Nil(t, err)
+			assert.Contains(t, string(raw), "# source of test\n")
+			assert.Contains(t, string(raw), "name: baz\n")
+
+			renamed, err := repo.GetByName("baz")
+			assert.Nil(t, err)
+			assert.Equal(t, "baz", renamed.Name)
+			_, err = repo.GetByName("bar")
+			assert.Equal(t, models.ErrNoSuchSpec, err)
+			dependent, err := repo.GetByName("test")
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]models.JobSpecDependency{"baz": {}}, dependent.Dependencies)
+		})
+		t.Run("should rename job nested in directories", func(t *testing.T) {
+			appFS := newFs(t)
+			repo := local.NewJobSpecRepository(appFS, adapter)
+			dependents, err := repo.Rename("test", "renamed")
+			assert.Nil(t, err)
+			assert.Empty(t, dependents)
+
+			raw, err := afero.ReadFile(appFS, filepath.Join("team", "renamed", local.JobSpecFileName))
+			assert.Nil(t, err)
+			assert.Contains(t, string(raw), "name: renamed\n")
+		})
+		t.Run("should fail if the job doesn't exist or a job has the new name", func(t *testing.T) {
+			repo := local.NewJobSpecRepository(newFs(t), adapter)
+			_, err := repo.Rename("missing", "baz")
+			assert.Equal(t, models.ErrNoSuchSpec, err)
+
+			_, err = repo.Rename("bar", "test")
+			assert.Equal(t, "job test already exists", err.Error())
+		})
+	})
 }
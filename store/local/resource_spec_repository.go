@@ -224,6 +224,9 @@ func (repo *resourceRepository) findInDir(dirName string) (models.ResourceSpec,
 		}
 	}
 	resourceSpec.Assets = assets
+	if resourceSpec, err = models.SpecMigrations.MigrateResource(resourceSpec); err != nil {
+		return resourceSpec, errors.Wrapf(err, "failed to read spec in: %s", dirName)
+	}
 
 	if _, ok := repo.cache.data[resourceSpec.Name]; ok {
 		return resourceSpec, errors.Errorf("job name should be unique across directories: %s", resourceSpec.Name)
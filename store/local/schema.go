@@ -0,0 +1,52 @@
+package local
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/odpf/optimus/core/jsonschema"
+	"gopkg.in/yaml.v2"
+)
+
+var specReflector = jsonschema.Reflector{
+	KeyTag: "yaml",
+	Overrides: map[reflect.Type]*jsonschema.Schema{
+		// plugin configs are ordered key value pairs
+		reflect.TypeOf(yaml.MapSlice{}): {
+			Type:                 jsonschema.TypeObject,
+			AdditionalProperties: &jsonschema.Schema{},
+		},
+	},
+}
+
+// JobSpecSchema returns json schema of job specification file, fields are
+// not marked required as they can be inherited from parent this.yaml files
+func JobSpecSchema() *jsonschema.Schema {
+	s := specReflector.Reflect(Job{}, "Optimus job specification")
+	s.Description = fmt.Sprintf("schema of %s and %s files", JobSpecFileName, JobSpecParentName)
+	return s
+}
+
+// ResourceSpecSchema returns json schema of resource specification file,
+// spec itself is datastore dependent and is not validated
+func ResourceSpecSchema() *jsonschema.Schema {
+	s := specReflector.Reflect(Resource{}, "Optimus resource specification")
+	s.Description = fmt.Sprintf("schema of %s files", ResourceSpecFileName)
+	s.Required = []string{"version", "name", "type"}
+	return s
+}
+
+// ValidateSpecFile decodes raw yaml content and checks it against the schema
+func ValidateSpecFile(schema *jsonschema.Schema, content []byte) error {
+	var doc interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return err
+	}
+
+	var errs error
+	for _, err := range schema.Validate(doc) {
+		errs = multierror.Append(errs, err)
+	}
+	return errs
+}
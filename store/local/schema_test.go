@@ -0,0 +1,49 @@
+package local_test
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/store/local"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecSchema(t *testing.T) {
+	t.Run("JobSpecSchema", func(t *testing.T) {
+		t.Run("should pass for a valid job specification", func(t *testing.T) {
+			err := local.ValidateSpecFile(local.JobSpecSchema(), []byte(testJobContents))
+			assert.Nil(t, err)
+		})
+		t.Run("should pass for a partial parent specification", func(t *testing.T) {
+			err := local.ValidateSpecFile(local.JobSpecSchema(), []byte("version: 1\nbehavior:\n  catch_up: true\n"))
+			assert.Nil(t, err)
+		})
+		t.Run("should return violations of an invalid job specification", func(t *testing.T) {
+			err := local.ValidateSpecFile(local.JobSpecSchema(), []byte(`version: 1
+name: ab
+schedule:
+  start_date: 02-12-2020
+task:
+  window:
+    truncate_to: x
+dependency: []
+`))
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "<root>: unknown field dependency")
+			assert.Contains(t, err.Error(), "name: should be at least 3 characters long")
+			assert.Contains(t, err.Error(), "schedule.start_date: \"02-12-2020\" does not match pattern")
+			assert.Contains(t, err.Error(), "task.window.truncate_to: \"x\" does not match pattern")
+		})
+	})
+	t.Run("ResourceSpecSchema", func(t *testing.T) {
+		t.Run("should pass for a valid resource specification", func(t *testing.T) {
+			err := local.ValidateSpecFile(local.ResourceSpecSchema(), []byte(testResourceContents))
+			assert.Nil(t, err)
+		})
+		t.Run("should return error if required fields are missing", func(t *testing.T) {
+			err := local.ValidateSpecFile(local.ResourceSpecSchema(), []byte("name: proj.datas.test\n"))
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "<root>: missing required field version")
+			assert.Contains(t, err.Error(), "<root>: missing required field type")
+		})
+	})
+}
@@ -0,0 +1,172 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+var (
+	errEmptyJobName = errors.New("job name cannot be an empty string")
+)
+
+// JobRepository keeps compiled jobs in any object store, each job is an
+// object at prefix/namespace/name with suffix
+type JobRepository struct {
+	store  store.ObjectStore
+	bucket string
+	prefix string
+	suffix string
+}
+
+func (repo *JobRepository) Save(ctx context.Context, j models.Job) (err error) {
+	dst, err := repo.store.NewWriter(ctx, repo.bucket, repo.pathFor(j.NamespaceID, j.Name))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if derr := dst.Close(); derr != nil {
+			if err == nil {
+				err = derr
+			} else {
+				err = errors.Wrap(err, derr.Error())
+			}
+		}
+	}()
+	_, err = io.Copy(dst, bytes.NewBuffer(j.Contents))
+	return err
+}
+
+func (repo *JobRepository) Delete(ctx context.Context, namespace models.NamespaceSpec, jobName string) error {
+	if strings.TrimSpace(jobName) == "" {
+		return errEmptyJobName
+	}
+	err := repo.store.Delete(ctx, repo.bucket, repo.pathFor(namespace.ID.String(), jobName))
+	if err == store.ErrResourceNotFound {
+		return errors.Wrap(models.ErrNoSuchJob, jobName)
+	}
+	return err
+}
+
+func (repo *JobRepository) GetAll(ctx context.Context) ([]models.Job, error) {
+	objPaths, err := repo.list(ctx, repo.prefix)
+	if err != nil {
+		return nil, err
+	}
+	var jobs []models.Job
+	for _, objPath := range objPaths {
+		job, err := repo.read(ctx, objPath)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (repo *JobRepository) ListNames(ctx context.Context, namespace models.NamespaceSpec) ([]string, error) {
+	objPaths, err := repo.list(ctx, path.Join(repo.prefix, namespace.ID.String())+"/")
+	if err != nil {
+		return nil, err
+	}
+	var jobNames []string
+	for _, objPath := range objPaths {
+		jobNames = append(jobNames, repo.jobNameFromPath(objPath))
+	}
+	return jobNames, nil
+}
+
+func (repo *JobRepository) Checksums(ctx context.Context, namespace models.NamespaceSpec) (map[string]string, error) {
+	objChecksums, err := repo.store.Checksums(ctx, repo.bucket, path.Join(repo.prefix, namespace.ID.String())+"/")
+	if err != nil {
+		return nil, err
+	}
+	checksums := map[string]string{}
+	for objPath, checksum := range objChecksums {
+		if strings.HasSuffix(objPath, repo.suffix) {
+			checksums[repo.jobNameFromPath(objPath)] = checksum
+		}
+	}
+	return checksums, nil
+}
+
+// GetByName looks up the job in all namespaces, names of jobs are unique
+// in a project
+func (repo *JobRepository) GetByName(ctx context.Context, jobName string) (models.Job, error) {
+	if strings.TrimSpace(jobName) == "" {
+		return models.Job{}, errEmptyJobName
+	}
+	objPaths, err := repo.list(ctx, repo.prefix)
+	if err != nil {
+		return models.Job{}, err
+	}
+	for _, objPath := range objPaths {
+		if repo.jobNameFromPath(objPath) == jobName {
+			return repo.read(ctx, objPath)
+		}
+	}
+	return models.Job{}, errors.Wrap(models.ErrNoSuchJob, jobName)
+}
+
+// list returns paths of jobs starting with prefix, objects outside folders
+// of namespaces like libraries of scheduler are skipped
+func (repo *JobRepository) list(ctx context.Context, prefix string) ([]string, error) {
+	objPaths, err := repo.store.List(ctx, repo.bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	var jobPaths []string
+	for _, objPath := range objPaths {
+		nsPath := strings.TrimPrefix(objPath, repo.prefix)
+		if strings.Contains(nsPath, "/") && strings.HasSuffix(objPath, repo.suffix) {
+			jobPaths = append(jobPaths, objPath)
+		}
+	}
+	return jobPaths, nil
+}
+
+func (repo *JobRepository) read(ctx context.Context, objPath string) (models.Job, error) {
+	reader, err := repo.store.NewObjectReader(ctx, repo.bucket, objPath)
+	if err != nil {
+		return models.Job{}, err
+	}
+	defer reader.Close()
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(reader); err != nil {
+		return models.Job{}, err
+	}
+	return models.Job{
+		Name:     repo.jobNameFromPath(objPath),
+		Contents: b.Bytes(),
+	}, nil
+}
+
+func (repo *JobRepository) pathFor(namespaceID, jobName string) string {
+	return fmt.Sprintf("%s%s", path.Join(repo.prefix, namespaceID, jobName), repo.suffix)
+}
+
+func (repo *JobRepository) jobNameFromPath(objPath string) string {
+	return strings.TrimSuffix(path.Base(objPath), repo.suffix)
+}
+
+// NewJobRepository constructs repository of jobs kept in bucket of the
+// object store
+func NewJobRepository(objStore store.ObjectStore, bucket, prefix, suffix string) *JobRepository {
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		prefix += "/"
+	}
+	return &JobRepository{
+		store:  objStore,
+		bucket: bucket,
+		prefix: prefix,
+		suffix: suffix,
+	}
+}
@@ -0,0 +1,81 @@
+package object_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store/filesystem"
+	"github.com/odpf/optimus/store/object"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRepository(t *testing.T) {
+	ctx := context.Background()
+	namespace := models.NamespaceSpec{ID: uuid.Must(uuid.NewRandom()), Name: "namespace"}
+	otherNamespace := models.NamespaceSpec{ID: uuid.Must(uuid.NewRandom()), Name: "other-namespace"}
+	job := models.Job{Name: "job-1", NamespaceID: namespace.ID.String(), Contents: []byte("dag of job-1")}
+	otherJob := models.Job{Name: "job-2", NamespaceID: otherNamespace.ID.String(), Contents: []byte("dag of job-2")}
+
+	newRepo := func(t *testing.T) (*object.JobRepository, afero.Fs) {
+		fs := afero.NewMemMapFs()
+		repo := object.NewJobRepository(filesystem.NewObjectStore(fs), "nfs", "/airflow/dags", ".py")
+		assert.Nil(t, afero.WriteFile(fs, "/nfs/airflow/dags/__lib.py", []byte("lib"), 0644))
+		assert.Nil(t, repo.Save(ctx, job))
+		assert.Nil(t, repo.Save(ctx, otherJob))
+		return repo, fs
+	}
+
+	t.Run("should save jobs in folders of their namespaces", func(t *testing.T) {
+		_, fs := newRepo(t)
+		content, err := afero.ReadFile(fs, "/nfs/airflow/dags/"+namespace.ID.String()+"/job-1.py")
+		assert.Nil(t, err)
+		assert.Equal(t, job.Contents, content)
+	})
+	t.Run("should get all jobs skipping libraries of scheduler", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		jobs, err := repo.GetAll(ctx)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []models.Job{
+			{Name: "job-1", Contents: job.Contents},
+			{Name: "job-2", Contents: otherJob.Contents},
+		}, jobs)
+	})
+	t.Run("should get job by name from any namespace", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		found, err := repo.GetByName(ctx, "job-2")
+		assert.Nil(t, err)
+		assert.Equal(t, models.Job{Name: "job-2", Contents: otherJob.Contents}, found)
+
+		_, err = repo.GetByName(ctx, "job-3")
+		assert.Equal(t, models.ErrNoSuchJob, errors.Cause(err))
+	})
+	t.Run("should list names of jobs of namespace", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		names, err := repo.ListNames(ctx, namespace)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"job-1"}, names)
+	})
+	t.Run("should tell checksums of jobs of namespace", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		checksums, err := repo.Checksums(ctx, namespace)
+		assert.Nil(t, err)
+		sum := md5.Sum(job.Contents)
+		assert.Equal(t, map[string]string{"job-1": hex.EncodeToString(sum[:])}, checksums)
+	})
+	t.Run("should delete job of namespace", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		assert.Nil(t, repo.Delete(ctx, namespace, "job-1"))
+		names, err := repo.ListNames(ctx, namespace)
+		assert.Nil(t, err)
+		assert.Empty(t, names)
+
+		err = repo.Delete(ctx, namespace, "job-1")
+		assert.Equal(t, models.ErrNoSuchJob, errors.Cause(err))
+	})
+}
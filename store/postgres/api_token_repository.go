@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"gorm.io/datatypes"
+)
+
+type APIToken struct {
+	ID        uuid.UUID `gorm:"primary_key;type:uuid"`
+	Name      string    `gorm:"not null"`
+	Hash      string    `gorm:"not null"`
+	Scopes    datatypes.JSON
+	Actor     string
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+}
+
+func (t APIToken) FromSpec(spec *models.APIToken) (APIToken, error) {
+	scopes := []string{}
+	for _, scope := range spec.Scopes {
+		scopes = append(scopes, scope.String())
+	}
+	scopesJSON, err := json.Marshal(scopes)
+	if err != nil {
+		return APIToken{}, err
+	}
+	token := APIToken{
+		ID:        spec.ID,
+		Name:      spec.Name,
+		Hash:      spec.Hash,
+		Scopes:    scopesJSON,
+		Actor:     spec.Actor,
+		CreatedAt: spec.CreatedAt.UTC(),
+	}
+	if !spec.ExpiresAt.IsZero() {
+		expiresAt := spec.ExpiresAt.UTC()
+		token.ExpiresAt = &expiresAt
+	}
+	if !spec.RevokedAt.IsZero() {
+		revokedAt := spec.RevokedAt.UTC()
+		token.RevokedAt = &revokedAt
+	}
+	return token, nil
+}
+
+func (t APIToken) ToSpec() (models.APIToken, error) {
+	var scopes []string
+	if err := json.Unmarshal(t.Scopes, &scopes); err != nil {
+		return models.APIToken{}, err
+	}
+	spec := models.APIToken{
+		ID:        t.ID,
+		Name:      t.Name,
+		Hash:      t.Hash,
+		Actor:     t.Actor,
+		CreatedAt: t.CreatedAt,
+	}
+	for _, value := range scopes {
+		scope, err := models.ParseTokenScope(value)
+		if err != nil {
+			return models.APIToken{}, err
+		}
+		spec.Scopes = append(spec.Scopes, scope)
+	}
+	if t.ExpiresAt != nil {
+		spec.ExpiresAt = *t.ExpiresAt
+	}
+	if t.RevokedAt != nil {
+		spec.RevokedAt = *t.RevokedAt
+	}
+	return spec, nil
+}
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func (repo *apiTokenRepository) Insert(token *models.APIToken) error {
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	t, err := APIToken{}.FromSpec(token)
+	if err != nil {
+		return err
+	}
+	return repo.db.Create(&t).Error
+}
+
+func (repo *apiTokenRepository) GetByHash(hash string) (models.APIToken, error) {
+	var t APIToken
+	if err := repo.db.Where("hash = ?", hash).Find(&t).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.APIToken{}, store.ErrResourceNotFound
+		}
+		return models.APIToken{}, err
+	}
+	return t.ToSpec()
+}
+
+func (repo *apiTokenRepository) GetAll() ([]models.APIToken, error) {
+	var tokens []APIToken
+	if err := repo.db.Order("created_at desc").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	specs := []models.APIToken{}
+	for _, t := range tokens {
+		spec, err := t.ToSpec()
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (repo *apiTokenRepository) Revoke(id uuid.UUID, at time.Time) error {
+	result := repo.db.Model(&APIToken{}).Where("id = ?", id).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", at.UTC()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrResourceNotFound
+	}
+	return nil
+}
+
+func NewAPITokenRepository(db *gorm.DB) *apiTokenRepository {
+	return &apiTokenRepository{
+		db: withRepository(db, "api_token"),
+	}
+}
@@ -0,0 +1,66 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITokenRepository(t *testing.T) {
+	t.Parallel()
+	createdAt := time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC)
+	ciToken := &models.APIToken{
+		Name: "ci",
+		Hash: "c1",
+		Scopes: []models.TokenScope{
+			{Action: models.TokenActionDeploy, Project: "t-optimus"},
+			{Action: models.TokenActionRead, Project: models.TokenScopeAllProjects},
+		},
+		Actor:     "alice",
+		ExpiresAt: createdAt.AddDate(0, 3, 0),
+		CreatedAt: createdAt,
+	}
+	replayToken := &models.APIToken{
+		Name:      "replayer",
+		Hash:      "r1",
+		Scopes:    []models.TokenScope{{Action: models.TokenActionReplay, Project: "t-optimus"}},
+		CreatedAt: createdAt.Add(time.Hour),
+	}
+
+	t.Run("Insert, GetByHash, GetAll and Revoke", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		repo := NewAPITokenRepository(db)
+		assert.Nil(t, repo.Insert(ciToken))
+		assert.Nil(t, repo.Insert(replayToken))
+		assert.NotEqual(t, uuid.Nil, ciToken.ID)
+
+		token, err := repo.GetByHash("c1")
+		assert.Nil(t, err)
+		assert.Equal(t, "ci", token.Name)
+		assert.Equal(t, ciToken.Scopes, token.Scopes)
+		assert.True(t, ciToken.ExpiresAt.Equal(token.ExpiresAt))
+		assert.True(t, token.RevokedAt.IsZero())
+		_, err = repo.GetByHash("unknown")
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		tokens, err := repo.GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, tokens, 2)
+		assert.Equal(t, "replayer", tokens[0].Name)
+
+		revokedAt := createdAt.AddDate(0, 0, 1)
+		assert.Nil(t, repo.Revoke(ciToken.ID, revokedAt))
+		assert.Nil(t, repo.Revoke(ciToken.ID, revokedAt.AddDate(0, 0, 1)))
+		token, err = repo.GetByHash("c1")
+		assert.Nil(t, err)
+		assert.True(t, revokedAt.Equal(token.RevokedAt))
+		assert.Equal(t, store.ErrResourceNotFound, repo.Revoke(uuid.New(), revokedAt))
+	})
+}
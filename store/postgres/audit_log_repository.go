@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	// auditLogDefaultLimit caps the number of entries returned if
+	// filter doesn't specify one
+	auditLogDefaultLimit = 100
+)
+
+type AuditLog struct {
+	ID uuid.UUID `gorm:"primary_key;type:uuid"`
+
+	Actor       string `gorm:"not null"`
+	RPC         string `gorm:"column:rpc;not null"`
+	ProjectName string
+	PayloadHash string
+	Outcome     string `gorm:"not null"`
+	Message     string
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+}
+
+func (a AuditLog) FromSpec(spec *models.AuditEntry) AuditLog {
+	return AuditLog{
+		ID:          spec.ID,
+		Actor:       spec.Actor,
+		RPC:         spec.RPC,
+		ProjectName: spec.ProjectName,
+		PayloadHash: spec.PayloadHash,
+		Outcome:     spec.Outcome,
+		Message:     spec.Message,
+		CreatedAt:   spec.CreatedAt.UTC(),
+	}
+}
+
+func (a AuditLog) ToSpec() models.AuditEntry {
+	return models.AuditEntry{
+		ID:          a.ID,
+		Actor:       a.Actor,
+		RPC:         a.RPC,
+		ProjectName: a.ProjectName,
+		PayloadHash: a.PayloadHash,
+		Outcome:     a.Outcome,
+		Message:     a.Message,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+type auditLogRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *auditLogRepository {
+	return &auditLogRepository{
+		DB: withRepository(db, "audit_log"),
+	}
+}
+
+func (repo *auditLogRepository) Insert(entry *models.AuditEntry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	a := AuditLog{}.FromSpec(entry)
+	return repo.DB.Create(&a).Error
+}
+
+// List returns latest audit entries matching the filter, ordered
+// by creation time in descending order
+func (repo *auditLogRepository) List(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query := repo.DB
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.RPC != "" {
+		query = query.Where("rpc LIKE ?", "%"+filter.RPC)
+	}
+	if filter.ProjectName != "" {
+		query = query.Where("project_name = ?", filter.ProjectName)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until.UTC())
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = auditLogDefaultLimit
+	}
+
+	var logs []AuditLog
+	if err := query.Order("created_at desc").Limit(limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	entries := []models.AuditEntry{}
+	for _, l := range logs {
+		entries = append(entries, l.ToSpec())
+	}
+	return entries, nil
+}
@@ -0,0 +1,80 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogRepository(t *testing.T) {
+	t.Parallel()
+	baseTime := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	testEntries := []*models.AuditEntry{
+		{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Actor:       "alice@example.io",
+			RPC:         "/odpf.optimus.RuntimeService/DeployJobSpecification",
+			ProjectName: "t-optimus",
+			PayloadHash: "abcd",
+			Outcome:     models.AuditOutcomeSuccess,
+			CreatedAt:   baseTime,
+		},
+		{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Actor:       "bob@example.io",
+			RPC:         "/odpf.optimus.RuntimeService/RegisterSecret",
+			ProjectName: "t-optimus",
+			Outcome:     models.AuditOutcomeFailure,
+			Message:     "secret name cannot be empty",
+			CreatedAt:   baseTime.Add(time.Hour),
+		},
+		{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Actor:       "alice@example.io",
+			RPC:         "/odpf.optimus.RuntimeService/RegisterProject",
+			ProjectName: "t-optimus-2",
+			Outcome:     models.AuditOutcomeSuccess,
+			CreatedAt:   baseTime.Add(time.Hour * 2),
+		},
+	}
+
+	t.Run("Insert and List", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		repo := NewAuditLogRepository(db)
+		for _, entry := range testEntries {
+			err := repo.Insert(entry)
+			assert.Nil(t, err)
+		}
+
+		t.Run("should return latest entries first", func(t *testing.T) {
+			entries, err := repo.List(models.AuditFilter{})
+			assert.Nil(t, err)
+			assert.Len(t, entries, 3)
+			assert.Equal(t, testEntries[2].ID, entries[0].ID)
+			assert.Equal(t, testEntries[0].ID, entries[2].ID)
+			assert.Equal(t, "abcd", entries[2].PayloadHash)
+		})
+		t.Run("should filter entries", func(t *testing.T) {
+			entries, err := repo.List(models.AuditFilter{Actor: "alice@example.io", ProjectName: "t-optimus"})
+			assert.Nil(t, err)
+			assert.Len(t, entries, 1)
+			assert.Equal(t, testEntries[0].ID, entries[0].ID)
+
+			entries, err = repo.List(models.AuditFilter{RPC: "RegisterSecret"})
+			assert.Nil(t, err)
+			assert.Len(t, entries, 1)
+			assert.Equal(t, "secret name cannot be empty", entries[0].Message)
+
+			entries, err = repo.List(models.AuditFilter{Since: baseTime.Add(time.Minute), Limit: 1})
+			assert.Nil(t, err)
+			assert.Len(t, entries, 1)
+			assert.Equal(t, testEntries[2].ID, entries[0].ID)
+		})
+	})
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"gorm.io/datatypes"
+)
+
+type DeployChangelog struct {
+	ID        uuid.UUID `gorm:"primary_key;type:uuid"`
+	ProjectID uuid.UUID `gorm:"not null"`
+	Namespace string    `gorm:"not null"`
+	Actor     string
+	Changes   datatypes.JSON
+
+	Snapshot     []byte
+	SnapshotHash string
+	Results      datatypes.JSON
+	Failed       bool
+	RollbackOf   *uuid.UUID
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+}
+
+type deployChangelogChange struct {
+	Name   string
+	Type   string
+	Fields []models.JobFieldChange `json:",omitempty"`
+}
+
+func (c DeployChangelog) FromSpec(proj models.ProjectSpec, spec *models.DeployChangelog) (DeployChangelog, error) {
+	changes := []deployChangelogChange{}
+	for _, change := range spec.Changes {
+		changes = append(changes, deployChangelogChange{
+			Name:   change.Name,
+			Type:   string(change.Type),
+			Fields: change.Fields,
+		})
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return DeployChangelog{}, err
+	}
+	var resultsJSON []byte
+	if spec.Results != nil {
+		if resultsJSON, err = json.Marshal(spec.Results); err != nil {
+			return DeployChangelog{}, err
+		}
+	}
+	var rollbackOf *uuid.UUID
+	if spec.RollbackOf != uuid.Nil {
+		rollbackOf = &spec.RollbackOf
+	}
+	return DeployChangelog{
+		ID:           spec.ID,
+		ProjectID:    proj.ID,
+		Namespace:    spec.Namespace,
+		Actor:        spec.Actor,
+		Changes:      changesJSON,
+		Snapshot:     spec.Snapshot,
+		SnapshotHash: spec.SnapshotHash,
+		Results:      resultsJSON,
+		Failed:       spec.Failed,
+		RollbackOf:   rollbackOf,
+		CreatedAt:    spec.CreatedAt.UTC(),
+	}, nil
+}
+
+func (c DeployChangelog) ToSpec() (models.DeployChangelog, error) {
+	var changes []deployChangelogChange
+	if c.Changes != nil {
+		if err := json.Unmarshal(c.Changes, &changes); err != nil {
+			return models.DeployChangelog{}, err
+		}
+	}
+	spec := models.DeployChangelog{
+		ID:           c.ID,
+		Namespace:    c.Namespace,
+		Actor:        c.Actor,
+		Snapshot:     c.Snapshot,
+		SnapshotHash: c.SnapshotHash,
+		Failed:       c.Failed,
+		CreatedAt:    c.CreatedAt,
+	}
+	if c.Results != nil {
+		if err := json.Unmarshal(c.Results, &spec.Results); err != nil {
+			return models.DeployChangelog{}, err
+		}
+	}
+	if c.RollbackOf != nil {
+		spec.RollbackOf = *c.RollbackOf
+	}
+	for _, change := range changes {
+		spec.Changes = append(spec.Changes, models.JobChange{
+			Name:   change.Name,
+			Type:   models.JobChangeType(change.Type),
+			Fields: change.Fields,
+		})
+	}
+	return spec, nil
+}
+
+type deployChangelogRepository struct {
+	db *gorm.DB
+}
+
+func (repo *deployChangelogRepository) Insert(proj models.ProjectSpec, changelog *models.DeployChangelog) error {
+	if changelog.ID == uuid.Nil {
+		changelog.ID = uuid.New()
+	}
+	if changelog.CreatedAt.IsZero() {
+		changelog.CreatedAt = time.Now()
+	}
+	c, err := DeployChangelog{}.FromSpec(proj, changelog)
+	if err != nil {
+		return err
+	}
+	return repo.db.Create(&c).Error
+}
+
+func (repo *deployChangelogRepository) GetLatest(proj models.ProjectSpec, namespace string, limit int) ([]models.DeployChangelog, error) {
+	query := repo.db.Where("project_id = ?", proj.ID)
+	if namespace != "" {
+		query = query.Where("namespace = ?", namespace)
+	}
+	// snapshots are left out of listing as they can be large
+	query = query.Select("id, project_id, namespace, actor, changes, snapshot_hash, results, failed, rollback_of, created_at")
+	var changelogs []DeployChangelog
+	if err := query.Order("created_at desc").Limit(limit).Find(&changelogs).Error; err != nil {
+		return nil, err
+	}
+	specs := []models.DeployChangelog{}
+	for _, c := range changelogs {
+		spec, err := c.ToSpec()
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (repo *deployChangelogRepository) GetByID(proj models.ProjectSpec, id uuid.UUID) (models.DeployChangelog, error) {
+	var c DeployChangelog
+	if err := repo.db.Where("project_id = ? AND id = ?", proj.ID, id).Find(&c).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DeployChangelog{}, store.ErrResourceNotFound
+		}
+		return models.DeployChangelog{}, err
+	}
+	return c.ToSpec()
+}
+
+func NewDeployChangelogRepository(db *gorm.DB) *deployChangelogRepository {
+	return &deployChangelogRepository{
+		db: withRepository(db, "deploy_changelog"),
+	}
+}
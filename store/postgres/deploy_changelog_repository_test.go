@@ -0,0 +1,93 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeployChangelogRepository(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus",
+	}
+	baseTime := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+	testChangelogs := []*models.DeployChangelog{
+		{
+			Namespace: "dev-team-1",
+			Actor:     "alice",
+			Changes: []models.JobChange{
+				{Name: "job-a", Type: models.JobChangeTypeAdded},
+			},
+			CreatedAt: baseTime,
+		},
+		{
+			Namespace: "dev-team-2",
+			Actor:     "bob",
+			CreatedAt: baseTime.Add(time.Hour),
+		},
+		{
+			Namespace: "dev-team-1",
+			Actor:     "alice",
+			Changes: []models.JobChange{
+				{
+					Name: "job-a",
+					Type: models.JobChangeTypeModified,
+					Fields: []models.JobFieldChange{
+						{Field: "schedule.interval", Old: "@daily", New: "@hourly"},
+					},
+				},
+				{Name: "job-b", Type: models.JobChangeTypeRemoved},
+			},
+			Snapshot:     []byte("jobs"),
+			SnapshotHash: "hash",
+			Results: []models.JobDeployResult{
+				{Name: "job-a", Success: true},
+			},
+			CreatedAt: baseTime.Add(time.Hour * 2),
+		},
+	}
+
+	t.Run("Insert and GetLatest", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewDeployChangelogRepository(db)
+		for _, changelog := range testChangelogs {
+			assert.Nil(t, repo.Insert(projectSpec, changelog))
+		}
+
+		changelogs, err := repo.GetLatest(projectSpec, "dev-team-1", 1)
+		assert.Nil(t, err)
+		assert.Len(t, changelogs, 1)
+		assert.Equal(t, testChangelogs[2].ID, changelogs[0].ID)
+		assert.Equal(t, testChangelogs[2].Changes, changelogs[0].Changes)
+
+		changelogs, err = repo.GetLatest(projectSpec, "", 10)
+		assert.Nil(t, err)
+		assert.Len(t, changelogs, 3)
+		assert.Equal(t, "bob", changelogs[1].Actor)
+		assert.Nil(t, changelogs[1].Changes)
+		assert.Equal(t, "hash", changelogs[0].SnapshotHash)
+		assert.Nil(t, changelogs[0].Snapshot)
+
+		changelog, err := repo.GetByID(projectSpec, testChangelogs[2].ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte("jobs"), changelog.Snapshot)
+		assert.Equal(t, testChangelogs[2].Results, changelog.Results)
+		assert.True(t, changelog.CanRollbackTo())
+
+		_, err = repo.GetByID(projectSpec, uuid.New())
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+}
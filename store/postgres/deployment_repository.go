@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+type Deployment struct {
+	ID        uuid.UUID `gorm:"primary_key;type:uuid"`
+	ProjectID uuid.UUID `gorm:"not null"`
+	Source    string    `gorm:"not null"`
+	Actor     string
+	Status    string `gorm:"not null"`
+	Message   string
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (d Deployment) FromSpec(proj models.ProjectSpec, spec *models.Deployment) Deployment {
+	return Deployment{
+		ID:        spec.ID,
+		ProjectID: proj.ID,
+		Source:    spec.Source,
+		Actor:     spec.Actor,
+		Status:    spec.Status,
+		Message:   spec.Message,
+		CreatedAt: spec.CreatedAt.UTC(),
+		UpdatedAt: spec.UpdatedAt.UTC(),
+	}
+}
+
+func (d Deployment) ToSpec() models.Deployment {
+	return models.Deployment{
+		ID:        d.ID,
+		Source:    d.Source,
+		Actor:     d.Actor,
+		Status:    d.Status,
+		Message:   d.Message,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+type deploymentRepository struct {
+	db *gorm.DB
+}
+
+func (repo *deploymentRepository) Insert(proj models.ProjectSpec, deployment *models.Deployment) error {
+	if proj.ID == uuid.Nil {
+		return errors.New("project id cannot be empty")
+	}
+	if deployment.ID == uuid.Nil {
+		deployment.ID = uuid.New()
+	}
+	if deployment.Status == "" {
+		deployment.Status = models.DeploymentStatusPending
+	}
+	if deployment.CreatedAt.IsZero() {
+		deployment.CreatedAt = time.Now()
+	}
+	deployment.UpdatedAt = deployment.CreatedAt
+	d := Deployment{}.FromSpec(proj, deployment)
+	return repo.db.Create(&d).Error
+}
+
+func (repo *deploymentRepository) UpdateStatus(id uuid.UUID, status, message string) error {
+	return repo.db.Model(&Deployment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     status,
+		"message":    message,
+		"updated_at": time.Now().UTC(),
+	}).Error
+}
+
+func (repo *deploymentRepository) GetByID(proj models.ProjectSpec, id uuid.UUID) (models.Deployment, error) {
+	var d Deployment
+	if err := repo.db.Where("project_id = ? AND id = ?", proj.ID, id).Find(&d).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Deployment{}, store.ErrResourceNotFound
+		}
+		return models.Deployment{}, err
+	}
+	return d.ToSpec(), nil
+}
+
+func NewDeploymentRepository(db *gorm.DB) *deploymentRepository {
+	return &deploymentRepository{
+		db: withRepository(db, "deployment"),
+	}
+}
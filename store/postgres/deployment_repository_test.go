@@ -0,0 +1,50 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentRepository(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus",
+	}
+
+	t.Run("Insert, UpdateStatus and GetByID", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewDeploymentRepository(db)
+		_, err = repo.GetByID(projectSpec, uuid.New())
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		deployment := &models.Deployment{Source: "ref v1.0.0", Actor: "ci"}
+		err = repo.Insert(projectSpec, deployment)
+		assert.Nil(t, err)
+
+		stored, err := repo.GetByID(projectSpec, deployment.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, models.DeploymentStatusPending, stored.Status)
+		assert.Equal(t, "ref v1.0.0", stored.Source)
+		assert.Equal(t, "ci", stored.Actor)
+
+		err = repo.UpdateStatus(deployment.ID, models.DeploymentStatusFailed, "namespace team-a not found")
+		assert.Nil(t, err)
+		stored, err = repo.GetByID(projectSpec, deployment.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, models.DeploymentStatusFailed, stored.Status)
+		assert.Equal(t, "namespace team-a not found", stored.Message)
+		assert.True(t, stored.IsDone())
+	})
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+)
+
+const (
+	defaultDestinationListLimit = 100
+	maxDestinationListLimit     = 1000
+)
+
+type destinationProducerRow struct {
+	Destination   string
+	ProjectName   string
+	NamespaceName string
+	Name          string
+	Owner         string
+	TaskName      string
+	UpdatedAt     time.Time
+}
+
+// destinationRegistry is backed by destinations of deployed jobs, they are
+// saved along with jobs on every deployment so the registry is always in sync
+type destinationRegistry struct {
+	db *gorm.DB
+}
+
+func (repo *destinationRegistry) GetProducers(destination string) ([]models.DestinationProducer, error) {
+	producers, err := repo.producers(repo.query().Where("job.destination = ?", models.CanonicalURN(destination)), 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(producers) == 0 {
+		return nil, store.ErrResourceNotFound
+	}
+	return producers, nil
+}
+
+func (repo *destinationRegistry) List(prefix string, limit int) ([]models.DestinationProducer, error) {
+	switch {
+	case limit <= 0:
+		limit = defaultDestinationListLimit
+	case limit > maxDestinationListLimit:
+		limit = maxDestinationListLimit
+	}
+	return repo.producers(repo.query().Where("job.destination LIKE ?", escapeLike(models.CanonicalURNPrefix(prefix))+"%"), limit)
+}
+
+func (repo *destinationRegistry) query() *gorm.DB {
+	return repo.db.Table("job").
+		Select("job.destination, project.name AS project_name, namespace.name AS namespace_name, job.name, " +
+			"coalesce(job.owner, '') AS owner, coalesce(job.task_name, '') AS task_name, job.updated_at").
+		Joins("JOIN project ON project.id = job.project_id").
+		Joins("JOIN namespace ON namespace.id = job.namespace_id").
+		Where("job.deleted_at IS NULL AND job.destination != ''")
+}
+
+func (repo *destinationRegistry) producers(db *gorm.DB, limit int) ([]models.DestinationProducer, error) {
+	db = db.Order("job.destination, project.name, job.name")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	var rows []destinationProducerRow
+	if err := db.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	producers := []models.DestinationProducer{}
+	for _, row := range rows {
+		producers = append(producers, models.DestinationProducer{
+			Destination: row.Destination,
+			Project:     row.ProjectName,
+			Namespace:   row.NamespaceName,
+			Job:         row.Name,
+			Owner:       row.Owner,
+			Task:        row.TaskName,
+			UpdatedAt:   row.UpdatedAt,
+		})
+	}
+	return producers, nil
+}
+
+func NewDestinationRegistry(db *gorm.DB) *destinationRegistry {
+	return &destinationRegistry{
+		db: withRepository(db, "destination_registry"),
+	}
+}
@@ -0,0 +1,114 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDestinationRegistry(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	shopProject := models.ProjectSpec{ID: uuid.Must(uuid.NewRandom()), Name: "shop"}
+	shopNamespace := models.NamespaceSpec{ID: uuid.Must(uuid.NewRandom()), Name: "finance", ProjectSpec: shopProject}
+	adsProject := models.ProjectSpec{ID: uuid.Must(uuid.NewRandom()), Name: "ads"}
+	adsNamespace := models.NamespaceSpec{ID: uuid.Must(uuid.NewRandom()), Name: "campaigns", ProjectSpec: adsProject}
+
+	gTask := "bq2bq"
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: gTask}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", gTask).Return(&models.Plugin{Base: execUnit}, nil)
+	adapter := NewAdapter(pluginRepo)
+	dependencyMod := new(mock.DependencyResolverMod)
+
+	newJob := func(name, owner, destination string) models.JobSpec {
+		spec := models.JobSpec{
+			ID:    uuid.Must(uuid.NewRandom()),
+			Name:  name,
+			Owner: owner,
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{Base: execUnit, DependencyMod: dependencyMod},
+				Config: models.JobSpecConfigs{{Name: "TABLE", Value: name}},
+			},
+		}
+		dependencyMod.On("GenerateDestination", context.TODO(), models.GenerateDestinationRequest{
+			Config: models.PluginConfigs{}.FromJobSpec(spec.Task.Config),
+			Assets: models.PluginAssets{}.FromJobSpec(spec.Assets),
+		}).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+		return spec
+	}
+	revenue := newJob("daily_revenue", "finance@example.com", "shop-project:finance.daily_revenue")
+	orders := newJob("orders_clean", "data@example.com", "shop-project:raw_100.orders")
+	backfill := newJob("revenue_backfill", "ads@example.com", "shop-project:finance.daily_revenue")
+
+	DBSetup := func(t *testing.T) *gorm.DB {
+		db := setupTestDB(t)
+		for _, ns := range []models.NamespaceSpec{shopNamespace, adsNamespace} {
+			assert.Nil(t, NewProjectRepository(db, hash).Save(ns.ProjectSpec))
+			assert.Nil(t, NewNamespaceRepository(db, ns.ProjectSpec, hash).Save(ns))
+		}
+		shopJobs := NewJobSpecRepository(db, shopNamespace, NewProjectJobSpecRepository(db, shopProject, adapter), adapter)
+		assert.Nil(t, shopJobs.Save(revenue))
+		assert.Nil(t, shopJobs.Save(orders))
+		adsJobs := NewJobSpecRepository(db, adsNamespace, NewProjectJobSpecRepository(db, adsProject, adapter), adapter)
+		assert.Nil(t, adsJobs.Save(backfill))
+		return db
+	}
+
+	names := func(producers []models.DestinationProducer) []string {
+		var found []string
+		for _, producer := range producers {
+			found = append(found, producer.Project+"/"+producer.Job)
+		}
+		return found
+	}
+
+	t.Run("should get producers of destination across projects", func(t *testing.T) {
+		registry := NewDestinationRegistry(DBSetup(t))
+		producers, err := registry.GetProducers("shop-project:finance.daily_revenue")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ads/revenue_backfill", "shop/daily_revenue"}, names(producers))
+		assert.Equal(t, "campaigns", producers[0].Namespace)
+		assert.Equal(t, "ads@example.com", producers[0].Owner)
+		assert.Equal(t, gTask, producers[0].Task)
+		assert.Equal(t, "bigquery://shop-project:finance.daily_revenue", producers[0].Destination)
+
+		producers, err = registry.GetProducers("bigquery://shop-project:finance.daily_revenue")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ads/revenue_backfill", "shop/daily_revenue"}, names(producers))
+
+		_, err = registry.GetProducers("shop-project:finance.unknown")
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+	t.Run("should list destinations by prefix", func(t *testing.T) {
+		registry := NewDestinationRegistry(DBSetup(t))
+		producers, err := registry.List("shop-project:raw_1", 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"shop/orders_clean"}, names(producers))
+
+		producers, err = registry.List("", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ads/revenue_backfill"}, names(producers))
+	})
+	t.Run("should resolve shared destination to producer of own project", func(t *testing.T) {
+		db := DBSetup(t)
+		job, project, err := NewProjectJobSpecRepository(db, shopProject, adapter).GetByDestination("shop-project:finance.daily_revenue")
+		assert.Nil(t, err)
+		assert.Equal(t, "daily_revenue", job.Name)
+		assert.Equal(t, "shop", project.Name)
+
+		job, project, err = NewProjectJobSpecRepository(db, adsProject, adapter).GetByDestination("shop-project:finance.daily_revenue")
+		assert.Nil(t, err)
+		assert.Equal(t, "revenue_backfill", job.Name)
+		assert.Equal(t, "ads", project.Name)
+	})
+}
@@ -22,6 +22,8 @@ type Instance struct {
 	ScheduledAt *time.Time `gorm:"not null"`
 	State       string
 	Data        datatypes.JSON
+	Cost        datatypes.JSON
+	HeartbeatAt *time.Time
 
 	CreatedAt time.Time `gorm:"not null" json:"created_at"`
 	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
@@ -36,17 +38,33 @@ func (j Instance) ToSpec(job models.JobSpec) (models.InstanceSpec, error) {
 		}
 	}
 
+	var cost *models.InstanceCost
+	if j.Cost != nil {
+		cost = &models.InstanceCost{}
+		if err := json.Unmarshal(j.Cost, cost); err != nil {
+			return models.InstanceSpec{}, err
+		}
+	}
+
 	var schdAt time.Time
 	if j.ScheduledAt != nil {
 		schdAt = *j.ScheduledAt
 	}
 
+	var heartbeatAt time.Time
+	if j.HeartbeatAt != nil {
+		heartbeatAt = *j.HeartbeatAt
+	}
+
 	return models.InstanceSpec{
 		ID:          j.ID,
 		ScheduledAt: schdAt,
 		State:       j.State,
 		Data:        data,
 		Job:         job,
+		UpdatedAt:   j.UpdatedAt,
+		Cost:        cost,
+		HeartbeatAt: heartbeatAt,
 	}, nil
 }
 
@@ -116,7 +134,7 @@ func (repo *instanceRepository) Clear(scheduled time.Time) error {
 	}
 	var r Instance
 	r.ID = existingJobSpecRun.ID
-	return repo.db.Model(&r).Update(map[string]interface{}{"data": nil}).Error
+	return repo.db.Model(&r).Update(map[string]interface{}{"data": nil, "cost": nil, "heartbeat_at": nil}).Error
 }
 
 func (repo *instanceRepository) GetByScheduledAt(scheduled time.Time) (models.InstanceSpec, error) {
@@ -130,9 +148,80 @@ func (repo *instanceRepository) GetByScheduledAt(scheduled time.Time) (models.In
 	return r.ToSpec(repo.job)
 }
 
+func (repo *instanceRepository) GetLatest(limit int) ([]models.InstanceSpec, error) {
+	var resources []Instance
+	if err := repo.db.Where("job_id = ?", repo.job.ID).Order("scheduled_at desc").Limit(limit).Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	var specs []models.InstanceSpec
+	for _, resource := range resources {
+		spec, err := resource.ToSpec(repo.job)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func (repo *instanceRepository) GetLatestByState(state string) (models.InstanceSpec, error) {
+	var r Instance
+	if err := repo.db.Where("job_id = ? AND state = ?", repo.job.ID, state).Order("scheduled_at desc").
+		First(&r).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.InstanceSpec{}, store.ErrResourceNotFound
+		}
+		return models.InstanceSpec{}, err
+	}
+	return r.ToSpec(repo.job)
+}
+
+func (repo *instanceRepository) Touch(scheduled time.Time) error {
+	return repo.db.Model(&Instance{}).Where("job_id = ? AND scheduled_at = ?", repo.job.ID, scheduled).
+		Update("updated_at", time.Now()).Error
+}
+
+func (repo *instanceRepository) UpdateState(scheduled time.Time, state string) error {
+	return repo.db.Model(&Instance{}).Where("job_id = ? AND scheduled_at = ?", repo.job.ID, scheduled).
+		Update("state", state).Error
+}
+
+// Heartbeat updates the column alone so updated_at keeps marking the last
+// registration of the run
+func (repo *instanceRepository) Heartbeat(scheduled time.Time, at time.Time) error {
+	return repo.db.Model(&Instance{}).Where("job_id = ? AND scheduled_at = ?", repo.job.ID, scheduled).
+		UpdateColumn("heartbeat_at", at).Error
+}
+
+func (repo *instanceRepository) UpdateCost(scheduled time.Time, cost models.InstanceCost) error {
+	costJSON, err := json.Marshal(cost)
+	if err != nil {
+		return err
+	}
+	return repo.db.Model(&Instance{}).Where("job_id = ? AND scheduled_at = ?", repo.job.ID, scheduled).
+		Update("cost", datatypes.JSON(costJSON)).Error
+}
+
+func (repo *instanceRepository) GetSince(since time.Time) ([]models.InstanceSpec, error) {
+	var resources []Instance
+	if err := repo.db.Where("job_id = ? AND scheduled_at >= ?", repo.job.ID, since).Order("scheduled_at asc").
+		Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	var specs []models.InstanceSpec
+	for _, resource := range resources {
+		spec, err := resource.ToSpec(repo.job)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
 func NewInstanceRepository(db *gorm.DB, job models.JobSpec, jobAdapter *JobSpecAdapter) *instanceRepository {
 	return &instanceRepository{
-		db:         db,
+		db:         withRepository(db, "instance"),
 		job:        job,
 		jobAdapter: jobAdapter,
 	}
@@ -4,7 +4,6 @@ package postgres
 
 import (
 	"context"
-	"os"
 	"testing"
 	"time"
 
@@ -12,10 +11,12 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestInstanceRepository(t *testing.T) {
+	t.Parallel()
 	projectSpec := models.ProjectSpec{
 		ID:   uuid.Must(uuid.NewRandom()),
 		Name: "t-optimus-id",
@@ -93,26 +94,8 @@ func TestInstanceRepository(t *testing.T) {
 	}
 	execUnit2.On("GenerateDestination", context.TODO(), unitData2).Return(models.GenerateDestinationResponse{Destination: "p.d.t"}, nil)
 
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-
+	DBSetup := func(t *testing.T) *gorm.DB {
+		dbConn := setupTestDB(t)
 		hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
 		prepo := NewProjectRepository(dbConn, hash)
 		assert.Nil(t, prepo.Save(projectSpec))
@@ -141,8 +124,7 @@ func TestInstanceRepository(t *testing.T) {
 	}
 
 	t.Run("Insert", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 
 		testModels := []models.InstanceSpec{}
 		testModels = append(testModels, testSpecs...)
@@ -166,8 +148,7 @@ func TestInstanceRepository(t *testing.T) {
 		assert.NotNil(t, err)
 	})
 	t.Run("Save", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 
 		testModels := []models.InstanceSpec{}
 		testModels = append(testModels, testSpecs...)
@@ -193,8 +174,7 @@ func TestInstanceRepository(t *testing.T) {
 		assert.Equal(t, testModels[0].Data, checkModel.Data)
 	})
 	t.Run("Clear", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 
 		testModels := []models.InstanceSpec{}
 		testModels = append(testModels, testSpecs...)
@@ -214,4 +194,121 @@ func TestInstanceRepository(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, []models.InstanceSpecData{}, checkModel.Data)
 	})
+	t.Run("GetLatest", func(t *testing.T) {
+		db := DBSetup(t)
+
+		testModels := []models.InstanceSpec{}
+		testModels = append(testModels, testSpecs...)
+
+		iRepo1 := NewInstanceRepository(db, testModels[0].Job, adapter)
+		err := iRepo1.Save(testModels[0])
+		assert.Nil(t, err)
+
+		savedModel, err := iRepo1.GetByScheduledAt(testModels[0].ScheduledAt)
+		assert.Nil(t, err)
+
+		err = iRepo1.Touch(testModels[0].ScheduledAt)
+		assert.Nil(t, err)
+
+		checkModels, err := iRepo1.GetLatest(5)
+		assert.Nil(t, err)
+		assert.Len(t, checkModels, 1)
+		assert.Equal(t, testModels[0].Data, checkModels[0].Data)
+		assert.False(t, checkModels[0].UpdatedAt.Before(savedModel.UpdatedAt))
+	})
+	t.Run("GetSince", func(t *testing.T) {
+		db := DBSetup(t)
+
+		earlier := testSpecs[0]
+		earlier.ID = uuid.Must(uuid.NewRandom())
+		earlier.ScheduledAt = testSpecs[0].ScheduledAt.AddDate(0, 0, -2)
+		later := testSpecs[0]
+		later.ID = uuid.Must(uuid.NewRandom())
+		later.State = models.InstanceStateRunning
+		later.ScheduledAt = testSpecs[0].ScheduledAt.AddDate(0, 0, 1)
+
+		iRepo1 := NewInstanceRepository(db, testSpecs[0].Job, adapter)
+		for _, spec := range []models.InstanceSpec{later, earlier, testSpecs[0]} {
+			assert.Nil(t, iRepo1.Save(spec))
+		}
+		assert.Nil(t, iRepo1.UpdateState(later.ScheduledAt, models.InstanceStateFailed))
+
+		checkModels, err := iRepo1.GetSince(testSpecs[0].ScheduledAt)
+		assert.Nil(t, err)
+		assert.Len(t, checkModels, 2)
+		assert.Equal(t, testSpecs[0].ScheduledAt, checkModels[0].ScheduledAt)
+		assert.Equal(t, models.InstanceStateSuccess, checkModels[0].State)
+		assert.Equal(t, later.ScheduledAt, checkModels[1].ScheduledAt)
+		assert.Equal(t, models.InstanceStateFailed, checkModels[1].State)
+	})
+	t.Run("GetLatestByState", func(t *testing.T) {
+		db := DBSetup(t)
+
+		earlier := testSpecs[0]
+		earlier.ID = uuid.Must(uuid.NewRandom())
+		earlier.ScheduledAt = testSpecs[0].ScheduledAt.AddDate(0, 0, -2)
+		later := testSpecs[0]
+		later.ID = uuid.Must(uuid.NewRandom())
+		later.ScheduledAt = testSpecs[0].ScheduledAt.AddDate(0, 0, 1)
+
+		iRepo1 := NewInstanceRepository(db, testSpecs[0].Job, adapter)
+		_, err := iRepo1.GetLatestByState(models.InstanceStateSuccess)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		for _, spec := range []models.InstanceSpec{later, earlier, testSpecs[0]} {
+			assert.Nil(t, iRepo1.Save(spec))
+		}
+		assert.Nil(t, iRepo1.UpdateState(later.ScheduledAt, models.InstanceStateFailed))
+
+		checkModel, err := iRepo1.GetLatestByState(models.InstanceStateSuccess)
+		assert.Nil(t, err)
+		assert.Equal(t, testSpecs[0].ScheduledAt, checkModel.ScheduledAt)
+
+		_, err = iRepo1.GetLatestByState(models.InstanceStateRunning)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+	t.Run("UpdateCost", func(t *testing.T) {
+		db := DBSetup(t)
+
+		iRepo1 := NewInstanceRepository(db, testSpecs[0].Job, adapter)
+		assert.Nil(t, iRepo1.Save(testSpecs[0]))
+		checkModel, err := iRepo1.GetByScheduledAt(testSpecs[0].ScheduledAt)
+		assert.Nil(t, err)
+		assert.Nil(t, checkModel.Cost)
+
+		cost := models.InstanceCost{DatastoreJobs: []string{"proj:US.job-1"}, BytesBilled: 1024, SlotMillis: 300}
+		assert.Nil(t, iRepo1.UpdateCost(testSpecs[0].ScheduledAt, cost))
+		checkModel, err = iRepo1.GetByScheduledAt(testSpecs[0].ScheduledAt)
+		assert.Nil(t, err)
+		assert.Equal(t, &cost, checkModel.Cost)
+
+		// runs registered again are billed again
+		assert.Nil(t, iRepo1.Clear(testSpecs[0].ScheduledAt))
+		checkModel, err = iRepo1.GetByScheduledAt(testSpecs[0].ScheduledAt)
+		assert.Nil(t, err)
+		assert.Nil(t, checkModel.Cost)
+	})
+	t.Run("Heartbeat", func(t *testing.T) {
+		db := DBSetup(t)
+
+		iRepo1 := NewInstanceRepository(db, testSpecs[0].Job, adapter)
+		assert.Nil(t, iRepo1.Save(testSpecs[0]))
+		saved, err := iRepo1.GetByScheduledAt(testSpecs[0].ScheduledAt)
+		assert.Nil(t, err)
+		assert.True(t, saved.HeartbeatAt.IsZero())
+
+		at := time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+		assert.Nil(t, iRepo1.Heartbeat(testSpecs[0].ScheduledAt, at))
+		checkModel, err := iRepo1.GetByScheduledAt(testSpecs[0].ScheduledAt)
+		assert.Nil(t, err)
+		assert.True(t, at.Equal(checkModel.HeartbeatAt))
+		// heartbeats don't stretch the measured duration of the run
+		assert.True(t, saved.UpdatedAt.Equal(checkModel.UpdatedAt))
+
+		// runs registered again report heartbeats afresh
+		assert.Nil(t, iRepo1.Clear(testSpecs[0].ScheduledAt))
+		checkModel, err = iRepo1.GetByScheduledAt(testSpecs[0].ScheduledAt)
+		assert.Nil(t, err)
+		assert.True(t, checkModel.HeartbeatAt.IsZero())
+	})
 }
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/odpf/optimus/core/logger"
+)
+
+const (
+	// repositoryKey tags connections of a repository with its name, so
+	// queries they run are attributed to it
+	repositoryKey = "optimus:repository"
+	queryStartKey = "optimus:query_started_at"
+
+	// QueryOperations of gorm callbacks observed
+	QueryOperationCreate = "create"
+	QueryOperationQuery  = "query"
+	QueryOperationUpdate = "update"
+	QueryOperationDelete = "delete"
+	QueryOperationRow    = "row_query"
+)
+
+// quotedLiteral matches string literals written in queries, they are
+// redacted along with bound parameters before queries are logged
+var quotedLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// QueryObserver is told of the time taken by every query of repositories
+type QueryObserver func(repository, operation string, elapsed time.Duration)
+
+// Instrument observes queries run over db by repositories, and logs the ones
+// taking longer than slowQueryThreshold with their parameters redacted, zero
+// threshold logs none. Statements run with Exec are not observed as gorm
+// runs no callbacks for them
+func Instrument(db *gorm.DB, observer QueryObserver, slowQueryThreshold time.Duration) {
+	start := func(scope *gorm.Scope) {
+		scope.InstanceSet(queryStartKey, time.Now())
+	}
+	observe := func(operation string) func(scope *gorm.Scope) {
+		return func(scope *gorm.Scope) {
+			startedAt, ok := scope.InstanceGet(queryStartKey)
+			if !ok {
+				return
+			}
+			elapsed := time.Since(startedAt.(time.Time))
+			repository := scopeRepository(scope)
+			if observer != nil {
+				observer(repository, operation, elapsed)
+			}
+			if slowQueryThreshold > 0 && elapsed >= slowQueryThreshold {
+				logger.W(fmt.Sprintf("slow %s of %s repository took %s: %s", operation, repository,
+					elapsed.Round(time.Millisecond), redactQuery(scope.SQL, len(scope.SQLVars))))
+			}
+		}
+	}
+
+	callback := db.Callback()
+	callback.Create().Before("gorm:begin_transaction").Register("optimus:start_create", start)
+	callback.Create().After("gorm:commit_or_rollback_transaction").Register("optimus:observe_create", observe(QueryOperationCreate))
+	callback.Query().Before("gorm:query").Register("optimus:start_query", start)
+	callback.Query().After("gorm:after_query").Register("optimus:observe_query", observe(QueryOperationQuery))
+	callback.Update().Before("gorm:begin_transaction").Register("optimus:start_update", start)
+	callback.Update().After("gorm:commit_or_rollback_transaction").Register("optimus:observe_update", observe(QueryOperationUpdate))
+	callback.Delete().Before("gorm:begin_transaction").Register("optimus:start_delete", start)
+	callback.Delete().After("gorm:commit_or_rollback_transaction").Register("optimus:observe_delete", observe(QueryOperationDelete))
+	callback.RowQuery().Before("gorm:row_query").Register("optimus:start_row_query", start)
+	callback.RowQuery().After("gorm:row_query").Register("optimus:observe_row_query", observe(QueryOperationRow))
+}
+
+// withRepository tags queries run over db with name of the repository
+func withRepository(db *gorm.DB, name string) *gorm.DB {
+	return db.Set(repositoryKey, name)
+}
+
+// scopeRepository is the repository a query is run by, queries of
+// connections not tagged with one are attributed to their table
+func scopeRepository(scope *gorm.Scope) string {
+	if name, ok := scope.Get(repositoryKey); ok {
+		return name.(string)
+	}
+	if scope.Value != nil {
+		return scope.TableName()
+	}
+	return "unknown"
+}
+
+// redactQuery replaces string literals of query, values bound to its
+// parameters are not logged at all
+func redactQuery(query string, params int) string {
+	query = quotedLiteral.ReplaceAllString(query, "'?'")
+	if params == 0 {
+		return query
+	}
+	return fmt.Sprintf("%s [%d params redacted]", query, params)
+}
@@ -0,0 +1,54 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/core/logger"
+	"github.com/odpf/optimus/models"
+)
+
+func TestInstrument(t *testing.T) {
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+
+	t.Run("should observe queries by repository and log slow ones redacted", func(t *testing.T) {
+		skipWithoutTestDB(t)
+		var logs bytes.Buffer
+		logger.InitWithWriter(logger.INFO, &logs)
+
+		db, err := Connect(testDBURL, 1, 1)
+		assert.Nil(t, err)
+		defer db.Close()
+
+		type observation struct {
+			repository string
+			operation  string
+		}
+		var mu sync.Mutex
+		var observed []observation
+		Instrument(db, func(repository, operation string, elapsed time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			observed = append(observed, observation{repository: repository, operation: operation})
+		}, time.Nanosecond)
+
+		_, err = NewProjectRepository(db, hash).GetByName("secret-project-name")
+		assert.NotNil(t, err)
+
+		assert.Equal(t, []observation{{repository: "project", operation: QueryOperationQuery}}, observed)
+		assert.Contains(t, logs.String(), "slow query of project repository")
+		assert.Contains(t, logs.String(), "[1 params redacted]")
+		assert.NotContains(t, logs.String(), "secret-project-name")
+	})
+	t.Run("should redact literals of queries", func(t *testing.T) {
+		assert.Equal(t, `SELECT * FROM "job" WHERE name = '?' AND id = $1 [1 params redacted]`,
+			redactQuery(`SELECT * FROM "job" WHERE name = 'it''s a secret' AND id = $1`, 1))
+		assert.Equal(t, `SELECT 1`, redactQuery(`SELECT 1`, 0))
+	})
+}
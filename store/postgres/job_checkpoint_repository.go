@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+type JobCheckpoint struct {
+	JobID uuid.UUID `gorm:"primary_key;type:uuid"`
+	Name  string    `gorm:"primary_key"`
+	Value string    `gorm:"not null"`
+
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (c JobCheckpoint) ToSpec() models.JobCheckpoint {
+	return models.JobCheckpoint{
+		Name:      c.Name,
+		Value:     c.Value,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+type jobCheckpointRepository struct {
+	db *gorm.DB
+}
+
+func (repo *jobCheckpointRepository) Save(jobSpec models.JobSpec, checkpoint models.JobCheckpoint) error {
+	if jobSpec.ID == uuid.Nil {
+		return errors.New("job id cannot be empty")
+	}
+	c := JobCheckpoint{
+		JobID:     jobSpec.ID,
+		Name:      checkpoint.Name,
+		Value:     checkpoint.Value,
+		UpdatedAt: time.Now().UTC(),
+	}
+	return repo.db.Save(&c).Error
+}
+
+func (repo *jobCheckpointRepository) GetAll(jobSpec models.JobSpec) ([]models.JobCheckpoint, error) {
+	var checkpoints []JobCheckpoint
+	if err := repo.db.Where("job_id = ?", jobSpec.ID).Order("name").Find(&checkpoints).Error; err != nil {
+		return nil, err
+	}
+	specs := []models.JobCheckpoint{}
+	for _, c := range checkpoints {
+		specs = append(specs, c.ToSpec())
+	}
+	return specs, nil
+}
+
+func (repo *jobCheckpointRepository) Delete(jobSpec models.JobSpec, name string) error {
+	return repo.db.Where("job_id = ? AND name = ?", jobSpec.ID, name).Delete(&JobCheckpoint{}).Error
+}
+
+func NewJobCheckpointRepository(db *gorm.DB) *jobCheckpointRepository {
+	return &jobCheckpointRepository{
+		db: withRepository(db, "job_checkpoint"),
+	}
+}
@@ -0,0 +1,84 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobCheckpointRepository(t *testing.T) {
+	t.Parallel()
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus-id",
+	}
+	namespaceSpec := models.NamespaceSpec{
+		ID:          uuid.Must(uuid.NewRandom()),
+		Name:        "dev-team-1",
+		ProjectSpec: projectSpec,
+	}
+
+	gTask := "g-task"
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{
+		Name: gTask,
+	}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", gTask).Return(&models.Plugin{Base: execUnit}, nil)
+	adapter := NewAdapter(pluginRepo)
+
+	jobSpec := models.JobSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "g-optimus-id",
+		Task: models.JobSpecTask{
+			Unit: &models.Plugin{Base: execUnit},
+		},
+	}
+	execUnit.On("GenerateDestination", context.TODO(), models.GenerateDestinationRequest{
+		Config: models.PluginConfigs{}.FromJobSpec(jobSpec.Task.Config),
+		Assets: models.PluginAssets{}.FromJobSpec(jobSpec.Assets),
+	}).Return(models.GenerateDestinationResponse{Destination: "p.d.t"}, nil)
+
+	DBSetup := func(t *testing.T) *gorm.DB {
+		dbConn := setupTestDB(t)
+		hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+		assert.Nil(t, NewProjectRepository(dbConn, hash).Save(projectSpec))
+		projectJobSpecRepo := NewProjectJobSpecRepository(dbConn, projectSpec, adapter)
+		assert.Nil(t, NewJobSpecRepository(dbConn, namespaceSpec, projectJobSpecRepo, adapter).Save(jobSpec))
+		return dbConn
+	}
+
+	t.Run("Save, GetAll and Delete", func(t *testing.T) {
+		db := DBSetup(t)
+
+		repo := NewJobCheckpointRepository(db)
+		checkpoints, err := repo.GetAll(jobSpec)
+		assert.Nil(t, err)
+		assert.Empty(t, checkpoints)
+
+		assert.Nil(t, repo.Save(jobSpec, models.JobCheckpoint{Name: "LAST_OFFSET", Value: "100"}))
+		assert.Nil(t, repo.Save(jobSpec, models.JobCheckpoint{Name: "CURSOR", Value: "abc"}))
+		assert.Nil(t, repo.Save(jobSpec, models.JobCheckpoint{Name: "LAST_OFFSET", Value: "250"}))
+
+		checkpoints, err = repo.GetAll(jobSpec)
+		assert.Nil(t, err)
+		assert.Len(t, checkpoints, 2)
+		assert.Equal(t, "CURSOR", checkpoints[0].Name)
+		assert.Equal(t, "abc", checkpoints[0].Value)
+		assert.Equal(t, "LAST_OFFSET", checkpoints[1].Name)
+		assert.Equal(t, "250", checkpoints[1].Value)
+		assert.False(t, checkpoints[1].UpdatedAt.IsZero())
+
+		assert.Nil(t, repo.Delete(jobSpec, "CURSOR"))
+		checkpoints, err = repo.GetAll(jobSpec)
+		assert.Nil(t, err)
+		assert.Len(t, checkpoints, 1)
+	})
+}
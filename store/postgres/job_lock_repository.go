@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+type JobLock struct {
+	Token       uuid.UUID `gorm:"primary_key;type:uuid"`
+	ProjectName string    `gorm:"not null"`
+	JobName     string    `gorm:"primary_key"`
+	Operation   string    `gorm:"not null"`
+	HolderID    string
+
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+func (l JobLock) ToSpec() models.JobLock {
+	return models.JobLock{
+		Token:     l.Token,
+		Project:   l.ProjectName,
+		Job:       l.JobName,
+		Operation: l.Operation,
+		HolderID:  l.HolderID,
+		ExpiresAt: l.ExpiresAt,
+	}
+}
+
+type jobLockRepository struct {
+	db *gorm.DB
+}
+
+// Acquire serializes acquisitions of locks of a project with an advisory
+// lock of the transaction, expired locks are dropped before looking for
+// conflicts
+func (repo *jobLockRepository) Acquire(lock models.JobLock, jobNames []string, ttl time.Duration) (*models.JobLock, error) {
+	if lock.Token == uuid.Nil || lock.Project == "" || lock.Operation == "" {
+		return nil, errors.New("token, project name and operation cannot be empty")
+	}
+	var conflict *models.JobLock
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "job_lock/"+lock.Project).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_name = ? AND expires_at < NOW()", lock.Project).Delete(&JobLock{}).Error; err != nil {
+			return err
+		}
+
+		var held []JobLock
+		if err := tx.Where("project_name = ? AND job_name IN (?) AND operation <> ?", lock.Project, jobNames, lock.Operation).
+			Order("job_name").Limit(1).Find(&held).Error; err != nil {
+			return err
+		}
+		if len(held) > 0 {
+			spec := held[0].ToSpec()
+			conflict = &spec
+			return nil
+		}
+
+		for _, jobName := range jobNames {
+			if err := tx.Exec("INSERT INTO job_lock (token, project_name, job_name, operation, holder_id, expires_at) "+
+				"VALUES (?, ?, ?, ?, ?, NOW() + ? * INTERVAL '1 second')",
+				lock.Token, lock.Project, jobName, lock.Operation, lock.HolderID, ttl.Seconds()).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
+
+func (repo *jobLockRepository) Extend(tokens []uuid.UUID, ttl time.Duration) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return repo.db.Exec("UPDATE job_lock SET expires_at = NOW() + ? * INTERVAL '1 second' WHERE token IN (?)",
+		ttl.Seconds(), tokens).Error
+}
+
+func (repo *jobLockRepository) Release(token uuid.UUID) error {
+	return repo.db.Where("token = ?", token).Delete(&JobLock{}).Error
+}
+
+func NewJobLockRepository(db *gorm.DB) *jobLockRepository {
+	return &jobLockRepository{
+		db: withRepository(db, "job_lock"),
+	}
+}
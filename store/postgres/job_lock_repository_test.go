@@ -0,0 +1,97 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobLockRepository(t *testing.T) {
+	t.Parallel()
+	newLock := func(project, operation, holderID string) models.JobLock {
+		return models.JobLock{
+			Token:     uuid.New(),
+			Project:   project,
+			Operation: operation,
+			HolderID:  holderID,
+		}
+	}
+	t.Run("Acquire", func(t *testing.T) {
+		t.Run("should share locks of the same operation", func(t *testing.T) {
+			db := setupTestDB(t)
+			repo := NewJobLockRepository(db)
+
+			conflict, err := repo.Acquire(newLock("t-optimus", "replay", "replay-1"), []string{"job-a"}, time.Minute)
+			assert.Nil(t, err)
+			assert.Nil(t, conflict)
+			conflict, err = repo.Acquire(newLock("t-optimus", "replay", "replay-2"), []string{"job-a"}, time.Minute)
+			assert.Nil(t, err)
+			assert.Nil(t, conflict)
+		})
+		t.Run("should lock all jobs or none of them", func(t *testing.T) {
+			db := setupTestDB(t)
+			repo := NewJobLockRepository(db)
+
+			conflict, err := repo.Acquire(newLock("t-optimus", "replay", "replay-1"), []string{"job-b"}, time.Minute)
+			assert.Nil(t, err)
+			assert.Nil(t, conflict)
+
+			conflict, err = repo.Acquire(newLock("t-optimus", "deploy", ""), []string{"job-a", "job-b"}, time.Minute)
+			assert.Nil(t, err)
+			assert.NotNil(t, conflict)
+			assert.Equal(t, "job-b", conflict.Job)
+			assert.Equal(t, "replay", conflict.Operation)
+			assert.Equal(t, "replay-1", conflict.HolderID)
+
+			conflict, err = repo.Acquire(newLock("t-optimus", "replay", "replay-2"), []string{"job-a"}, time.Minute)
+			assert.Nil(t, err)
+			assert.Nil(t, conflict)
+		})
+		t.Run("should not conflict with locks of other projects", func(t *testing.T) {
+			db := setupTestDB(t)
+			repo := NewJobLockRepository(db)
+
+			_, err := repo.Acquire(newLock("t-optimus", "replay", "replay-1"), []string{"job-a"}, time.Minute)
+			assert.Nil(t, err)
+			conflict, err := repo.Acquire(newLock("other-project", "deploy", ""), []string{"job-a"}, time.Minute)
+			assert.Nil(t, err)
+			assert.Nil(t, conflict)
+		})
+		t.Run("should drop expired locks", func(t *testing.T) {
+			db := setupTestDB(t)
+			repo := NewJobLockRepository(db)
+
+			_, err := repo.Acquire(newLock("t-optimus", "replay", "replay-1"), []string{"job-a"}, -time.Minute)
+			assert.Nil(t, err)
+			conflict, err := repo.Acquire(newLock("t-optimus", "deploy", ""), []string{"job-a"}, time.Minute)
+			assert.Nil(t, err)
+			assert.Nil(t, conflict)
+		})
+	})
+	t.Run("Extend and Release", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := NewJobLockRepository(db)
+
+		replayLock := newLock("t-optimus", "replay", "replay-1")
+		_, err := repo.Acquire(replayLock, []string{"job-a", "job-b"}, -time.Minute)
+		assert.Nil(t, err)
+		err = repo.Extend([]uuid.UUID{replayLock.Token}, time.Minute)
+		assert.Nil(t, err)
+
+		conflict, err := repo.Acquire(newLock("t-optimus", "deploy", ""), []string{"job-b"}, time.Minute)
+		assert.Nil(t, err)
+		assert.NotNil(t, conflict)
+		assert.Equal(t, replayLock.Token, conflict.Token)
+
+		err = repo.Release(replayLock.Token)
+		assert.Nil(t, err)
+		conflict, err = repo.Acquire(newLock("t-optimus", "deploy", ""), []string{"job-b"}, time.Minute)
+		assert.Nil(t, err)
+		assert.Nil(t, conflict)
+	})
+}
@@ -14,18 +14,22 @@ import (
 // Job are inputs from user to create a job
 // postgres representation of the job
 type Job struct {
-	ID           uuid.UUID `gorm:"primary_key;type:uuid;"`
-	Version      int
-	Name         string `gorm:"not null" json:"name"`
-	Owner        string
-	Description  string
-	Labels       datatypes.JSON
-	StartDate    time.Time
-	EndDate      *time.Time
-	Interval     string
-	Destination  string
-	Dependencies datatypes.JSON
-	Behavior     datatypes.JSON
+	ID          uuid.UUID `gorm:"primary_key;type:uuid;"`
+	Version     int
+	Name        string `gorm:"not null" json:"name"`
+	Owner       string
+	Ownership   datatypes.JSON
+	Description string
+	Labels      datatypes.JSON
+	StartDate   time.Time
+	EndDate     *time.Time
+	Interval    string
+	// ScheduleExceptions are the days job deviates from its interval on
+	ScheduleExceptions datatypes.JSON
+	MaxActiveRuns      int
+	Destination        string
+	Dependencies       datatypes.JSON
+	Behavior           datatypes.JSON
 
 	ProjectID uuid.UUID
 	Project   Project `gorm:"foreignKey:ProjectID"`
@@ -34,24 +38,71 @@ type Job struct {
 	Namespace   Namespace `gorm:"foreignKey:NamespaceID"`
 
 	TaskName         string
+	TaskVersion      string
 	TaskConfig       datatypes.JSON
 	WindowSize       *int64 //duration in nanos
 	WindowOffset     *int64
 	WindowTruncateTo *string
 
+	// TaskPluginVersion is the version of task plugin installed on
+	// the server when the job was last deployed and compiled
+	TaskPluginVersion string
+
+	// TaskTemplateEngine is the engine assets are compiled with, empty
+	// means the one set for the project
+	TaskTemplateEngine string
+
 	Assets datatypes.JSON
 	Hooks  datatypes.JSON
 
+	// Lifecycle is how data of destination is managed as it ages
+	Lifecycle datatypes.JSON
+
 	CreatedAt time.Time `gorm:"not null" json:"created_at"`
 	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
 	DeletedAt *time.Time
 }
 
+type JobOwnership struct {
+	Email        string
+	Team         string
+	SlackChannel string
+}
+
+type JobScheduleExceptions struct {
+	SkipDates []time.Time
+	ExtraRuns []time.Time
+	Calendars []string
+}
+
+type JobLifecycle struct {
+	Retention JobRetention
+}
+
+type JobRetention struct {
+	PartitionDays int
+}
+
 type JobBehavior struct {
 	DependsOnPast bool
 	CatchUp       bool
 	Retry         JobBehaviorRetry
 	Notify        []JobBehaviorNotifier
+	AutoHeal      JobBehaviorAutoHeal
+	ReplayPresets map[string]JobBehaviorReplayPreset
+}
+
+type JobBehaviorAutoHeal struct {
+	Window    int64
+	MaxPerDay int
+}
+
+type JobBehaviorReplayPreset struct {
+	Start     string
+	End       string
+	Selector  string
+	Force     bool
+	ChunkDays int
 }
 
 type JobBehaviorRetry struct {
@@ -138,6 +189,20 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 		}
 	}
 
+	ownership := JobOwnership{}
+	if conf.Ownership != nil {
+		if err := json.Unmarshal(conf.Ownership, &ownership); err != nil {
+			return models.JobSpec{}, err
+		}
+	}
+
+	exceptions := JobScheduleExceptions{}
+	if conf.ScheduleExceptions != nil {
+		if err := json.Unmarshal(conf.ScheduleExceptions, &exceptions); err != nil {
+			return models.JobSpec{}, err
+		}
+	}
+
 	behavior := JobBehavior{}
 	if conf.Behavior != nil {
 		if err := json.Unmarshal(conf.Behavior, &behavior); err != nil {
@@ -145,6 +210,13 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 		}
 	}
 
+	lifecycle := JobLifecycle{}
+	if conf.Lifecycle != nil {
+		if err := json.Unmarshal(conf.Lifecycle, &lifecycle); err != nil {
+			return models.JobSpec{}, err
+		}
+	}
+
 	// prep dirty dependencies
 	dependencies := map[string]models.JobSpecDependency{}
 	if err := json.Unmarshal(conf.Dependencies, &dependencies); err != nil {
@@ -195,17 +267,42 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 		})
 	}
 
+	var replayPresets map[string]models.JobSpecReplayPreset
+	for name, preset := range behavior.ReplayPresets {
+		if replayPresets == nil {
+			replayPresets = map[string]models.JobSpecReplayPreset{}
+		}
+		replayPresets[name] = models.JobSpecReplayPreset{
+			Start:     preset.Start,
+			End:       preset.End,
+			Selector:  preset.Selector,
+			Force:     preset.Force,
+			ChunkDays: preset.ChunkDays,
+		}
+	}
+
 	job := models.JobSpec{
-		ID:          conf.ID,
-		Version:     conf.Version,
-		Name:        conf.Name,
-		Owner:       conf.Owner,
+		ID:      conf.ID,
+		Version: conf.Version,
+		Name:    conf.Name,
+		Owner:   conf.Owner,
+		Ownership: models.JobSpecOwnership{
+			Email:        ownership.Email,
+			Team:         ownership.Team,
+			SlackChannel: ownership.SlackChannel,
+		},
 		Description: conf.Description,
 		Labels:      labels,
 		Schedule: models.JobSpecSchedule{
 			StartDate: conf.StartDate,
 			EndDate:   conf.EndDate,
 			Interval:  conf.Interval,
+			Exceptions: models.JobSpecScheduleExceptions{
+				SkipDates: exceptions.SkipDates,
+				ExtraRuns: exceptions.ExtraRuns,
+				Calendars: exceptions.Calendars,
+			},
+			MaxActiveRuns: conf.MaxActiveRuns,
 		},
 		Behavior: models.JobSpecBehavior{
 			DependsOnPast: behavior.DependsOnPast,
@@ -216,10 +313,17 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 				ExponentialBackoff: behavior.Retry.ExponentialBackoff,
 			},
 			Notify: notifiers,
+			AutoHeal: models.JobSpecBehaviorAutoHeal{
+				Window:    time.Duration(behavior.AutoHeal.Window),
+				MaxPerDay: behavior.AutoHeal.MaxPerDay,
+			},
+			ReplayPresets: replayPresets,
 		},
 		Task: models.JobSpecTask{
-			Unit:   execUnit,
-			Config: taskConf,
+			Unit:           execUnit,
+			Version:        conf.TaskVersion,
+			TemplateEngine: conf.TaskTemplateEngine,
+			Config:         taskConf,
 			Window: models.JobSpecTaskWindow{
 				Size:       time.Duration(*conf.WindowSize),
 				Offset:     time.Duration(*conf.WindowOffset),
@@ -229,8 +333,14 @@ func (adapt JobSpecAdapter) ToSpec(conf Job) (models.JobSpec, error) {
 		Assets:       *(models.JobAssets{}).New(jobAssets),
 		Dependencies: dependencies,
 		Hooks:        jobHooks,
+		Lifecycle: models.JobSpecLifecycle{
+			Retention: models.JobSpecRetention{
+				PartitionDays: lifecycle.Retention.PartitionDays,
+			},
+		},
 	}
-	return job, nil
+	// specs stored before a change of their format are upgraded as read
+	return models.SpecMigrations.MigrateJob(job)
 }
 
 // FromSpec converts the optimus representation of JobSpec to postgres' Job
@@ -244,6 +354,24 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		return Job{}, err
 	}
 
+	ownershipJSON, err := json.Marshal(JobOwnership{
+		Email:        spec.Ownership.Email,
+		Team:         spec.Ownership.Team,
+		SlackChannel: spec.Ownership.SlackChannel,
+	})
+	if err != nil {
+		return Job{}, err
+	}
+
+	exceptionsJSON, err := json.Marshal(JobScheduleExceptions{
+		SkipDates: spec.Schedule.Exceptions.SkipDates,
+		ExtraRuns: spec.Schedule.Exceptions.ExtraRuns,
+		Calendars: spec.Schedule.Exceptions.Calendars,
+	})
+	if err != nil {
+		return Job{}, err
+	}
+
 	var notifiers []JobBehaviorNotifier
 	for _, notify := range spec.Behavior.Notify {
 		notifiers = append(notifiers, JobBehaviorNotifier{
@@ -253,6 +381,20 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		})
 	}
 
+	var replayPresets map[string]JobBehaviorReplayPreset
+	for name, preset := range spec.Behavior.ReplayPresets {
+		if replayPresets == nil {
+			replayPresets = map[string]JobBehaviorReplayPreset{}
+		}
+		replayPresets[name] = JobBehaviorReplayPreset{
+			Start:     preset.Start,
+			End:       preset.End,
+			Selector:  preset.Selector,
+			Force:     preset.Force,
+			ChunkDays: preset.ChunkDays,
+		}
+	}
+
 	behaviorJSON, err := json.Marshal(JobBehavior{
 		DependsOnPast: spec.Behavior.DependsOnPast,
 		CatchUp:       spec.Behavior.CatchUp,
@@ -262,18 +404,26 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 			ExponentialBackoff: spec.Behavior.Retry.ExponentialBackoff,
 		},
 		Notify: notifiers,
+		AutoHeal: JobBehaviorAutoHeal{
+			Window:    spec.Behavior.AutoHeal.Window.Nanoseconds(),
+			MaxPerDay: spec.Behavior.AutoHeal.MaxPerDay,
+		},
+		ReplayPresets: replayPresets,
 	})
 	if err != nil {
 		return Job{}, err
 	}
 
-	// prep dependencies, make them dirty first(remove job and project)
-	for idx, dep := range spec.Dependencies {
-		dep.Project = nil
-		dep.Job = nil
-		spec.Dependencies[idx] = dep
+	lifecycleJSON, err := json.Marshal(JobLifecycle{
+		Retention: JobRetention{
+			PartitionDays: spec.Lifecycle.Retention.PartitionDays,
+		},
+	})
+	if err != nil {
+		return Job{}, err
 	}
-	dependenciesJSON, err := json.Marshal(spec.Dependencies)
+
+	dependenciesJSON, err := dependenciesToJSON(spec.Dependencies)
 	if err != nil {
 		return Job{}, err
 	}
@@ -319,29 +469,36 @@ func (adapt JobSpecAdapter) FromSpec(spec models.JobSpec) (Job, error) {
 		if err != nil {
 			return Job{}, err
 		}
-		jobDestination = jobDestinationResponse.Destination
+		jobDestination = models.CanonicalURN(jobDestinationResponse.Destination)
 	}
 
 	return Job{
-		ID:               spec.ID,
-		Version:          spec.Version,
-		Name:             spec.Name,
-		Owner:            spec.Owner,
-		Description:      spec.Description,
-		Labels:           labelsJSON,
-		StartDate:        spec.Schedule.StartDate,
-		EndDate:          spec.Schedule.EndDate,
-		Interval:         spec.Schedule.Interval,
-		Behavior:         behaviorJSON,
-		Destination:      jobDestination,
-		Dependencies:     dependenciesJSON,
-		TaskName:         spec.Task.Unit.Info().Name,
-		TaskConfig:       taskConfigJSON,
-		WindowSize:       &wsize,
-		WindowOffset:     &woffset,
-		WindowTruncateTo: &spec.Task.Window.TruncateTo,
-		Assets:           assetsJSON,
-		Hooks:            hooksJSON,
+		ID:                 spec.ID,
+		Version:            spec.Version,
+		Name:               spec.Name,
+		Owner:              spec.Owner,
+		Ownership:          ownershipJSON,
+		Description:        spec.Description,
+		Labels:             labelsJSON,
+		StartDate:          spec.Schedule.StartDate,
+		EndDate:            spec.Schedule.EndDate,
+		Interval:           spec.Schedule.Interval,
+		ScheduleExceptions: exceptionsJSON,
+		MaxActiveRuns:      spec.Schedule.MaxActiveRuns,
+		Behavior:           behaviorJSON,
+		Destination:        jobDestination,
+		Dependencies:       dependenciesJSON,
+		TaskName:           spec.Task.Unit.Info().Name,
+		TaskVersion:        spec.Task.Version,
+		TaskTemplateEngine: spec.Task.TemplateEngine,
+		TaskPluginVersion:  spec.Task.Unit.Info().PluginVersion,
+		TaskConfig:         taskConfigJSON,
+		WindowSize:         &wsize,
+		WindowOffset:       &woffset,
+		WindowTruncateTo:   &spec.Task.Window.TruncateTo,
+		Assets:             assetsJSON,
+		Hooks:              hooksJSON,
+		Lifecycle:          lifecycleJSON,
 	}, nil
 }
 
@@ -369,3 +526,16 @@ func (adapt JobSpecAdapter) FromSpecWithNamespace(spec models.JobSpec, namespace
 
 	return adaptJob, nil
 }
+
+// dependenciesToJSON prepares dependencies to be stored, they are made dirty
+// first removing job and project
+func dependenciesToJSON(dependencies map[string]models.JobSpecDependency) (datatypes.JSON, error) {
+	dirty := map[string]models.JobSpecDependency{}
+	for name, dep := range dependencies {
+		dep.Project = nil
+		dep.Job = nil
+		dirty[name] = dep
+	}
+	raw, err := json.Marshal(dirty)
+	return datatypes.JSON(raw), err
+}
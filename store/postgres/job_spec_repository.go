@@ -14,23 +14,31 @@ type ProjectJobSpecRepository struct {
 	db      *gorm.DB
 	project models.ProjectSpec
 	adapter *JobSpecAdapter
+	cache   *SpecCache
 }
 
 func NewProjectJobSpecRepository(db *gorm.DB, project models.ProjectSpec, adapter *JobSpecAdapter) *ProjectJobSpecRepository {
 	return &ProjectJobSpecRepository{
-		db:      db,
+		db:      withRepository(db, "project_job_spec"),
 		project: project,
 		adapter: adapter,
+		cache:   specCache(db),
 	}
 }
 
 func (repo *ProjectJobSpecRepository) GetByName(name string) (models.JobSpec, models.NamespaceSpec, error) {
 	var r Job
-	if err := repo.db.Preload("Namespace").Where("project_id = ? AND name = ?", repo.project.ID, name).Find(&r).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound
+	key := repo.cacheKey("name/" + name)
+	if cached, ok := repo.cache.get(key); ok {
+		r = cached.(Job)
+	} else {
+		if err := repo.db.Preload("Namespace").Where("project_id = ? AND name = ?", repo.project.ID, name).Find(&r).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.JobSpec{}, models.NamespaceSpec{}, store.ErrResourceNotFound
+			}
+			return models.JobSpec{}, models.NamespaceSpec{}, err
 		}
-		return models.JobSpec{}, models.NamespaceSpec{}, err
+		repo.cache.set(key, r)
 	}
 
 	jobSpec, err := repo.adapter.ToSpec(r)
@@ -49,8 +57,14 @@ func (repo *ProjectJobSpecRepository) GetByName(name string) (models.JobSpec, mo
 func (repo *ProjectJobSpecRepository) GetAll() ([]models.JobSpec, error) {
 	specs := []models.JobSpec{}
 	jobs := []Job{}
-	if err := repo.db.Where("project_id = ?", repo.project.ID).Find(&jobs).Error; err != nil {
-		return specs, err
+	key := repo.cacheKey("*")
+	if cached, ok := repo.cache.get(key); ok {
+		jobs = cached.([]Job)
+	} else {
+		if err := repo.db.Where("project_id = ?", repo.project.ID).Find(&jobs).Error; err != nil {
+			return specs, err
+		}
+		repo.cache.set(key, jobs)
 	}
 
 	for _, job := range jobs {
@@ -65,11 +79,20 @@ func (repo *ProjectJobSpecRepository) GetAll() ([]models.JobSpec, error) {
 
 func (repo *ProjectJobSpecRepository) GetByDestination(destination string) (models.JobSpec, models.ProjectSpec, error) {
 	var r Job
-	if err := repo.db.Preload("Project").Where("destination = ?", destination).Find(&r).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return models.JobSpec{}, models.ProjectSpec{}, store.ErrResourceNotFound
+	key := specCacheKey{cache: specCacheDestination, project: repo.project.ID.String(), key: models.CanonicalURN(destination)}
+	if cached, ok := repo.cache.get(key); ok {
+		r = cached.(Job)
+	} else {
+		// destinations are registered across projects, a destination produced by
+		// more than one job resolves to the one of this project if any
+		if err := repo.db.Preload("Project").Where("destination = ?", models.CanonicalURN(destination)).
+			Order(gorm.Expr("project_id = ? DESC", repo.project.ID)).Order("name").Take(&r).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.JobSpec{}, models.ProjectSpec{}, store.ErrResourceNotFound
+			}
+			return models.JobSpec{}, models.ProjectSpec{}, err
 		}
-		return models.JobSpec{}, models.ProjectSpec{}, err
+		repo.cache.set(key, r)
 	}
 
 	jSpec, err := repo.adapter.ToSpec(r)
@@ -85,14 +108,45 @@ func (repo *ProjectJobSpecRepository) GetByDestination(destination string) (mode
 	return jSpec, pSpec, err
 }
 
+func (repo *ProjectJobSpecRepository) GetDestinations() ([]models.JobDestination, error) {
+	var jobs []Job
+	key := repo.cacheKey("destinations")
+	if cached, ok := repo.cache.get(key); ok {
+		jobs = cached.([]Job)
+	} else {
+		if err := repo.db.Preload("Namespace").Select("name, destination, namespace_id").
+			Where("project_id = ? AND destination != ''", repo.project.ID).Find(&jobs).Error; err != nil {
+			return nil, err
+		}
+		repo.cache.set(key, jobs)
+	}
+
+	destinations := []models.JobDestination{}
+	for _, job := range jobs {
+		destinations = append(destinations, models.JobDestination{
+			JobName:       job.Name,
+			NamespaceName: job.Namespace.Name,
+			Destination:   job.Destination,
+		})
+	}
+	return destinations, nil
+}
+
+// cacheKey of rows of jobs of the project read for the key
+func (repo *ProjectJobSpecRepository) cacheKey(key string) specCacheKey {
+	return specCacheKey{cache: SpecCacheJobSpec, project: repo.project.ID.String(), key: key}
+}
+
 type JobSpecRepository struct {
 	db                 *gorm.DB
 	namespace          models.NamespaceSpec
 	projectJobSpecRepo store.ProjectJobSpecRepository
 	adapter            *JobSpecAdapter
+	cache              *SpecCache
 }
 
 func (repo *JobSpecRepository) Insert(spec models.JobSpec) error {
+	defer repo.cache.InvalidateJobs(repo.namespace.ProjectSpec.ID)
 	resource, err := repo.adapter.FromSpecWithNamespace(spec, repo.namespace)
 	if err != nil {
 		return err
@@ -108,6 +162,11 @@ func (repo *JobSpecRepository) Insert(spec models.JobSpec) error {
 }
 
 func (repo *JobSpecRepository) Save(spec models.JobSpec) error {
+	// jobs are looked up fresh, a job deleted or renamed by another server
+	// since it was cached would be updated rather than inserted
+	repo.cache.InvalidateJobs(repo.namespace.ProjectSpec.ID)
+	defer repo.cache.InvalidateJobs(repo.namespace.ProjectSpec.ID)
+
 	// while saving a JobSpec, we need to ensure that it's name is unique for a project
 	existingJobSpec, namespaceSpec, err := repo.projectJobSpecRepo.GetByName(spec.Name)
 	if errors.Is(err, store.ErrResourceNotFound) {
@@ -154,23 +213,85 @@ func (repo *JobSpecRepository) GetByName(name string) (models.JobSpec, error) {
 }
 
 func (repo *JobSpecRepository) Delete(name string) error {
+	defer repo.cache.InvalidateJobs(repo.namespace.ProjectSpec.ID)
 	return repo.db.Where("namespace_id = ? AND name = ?", repo.namespace.ID, name).Delete(&Job{}).Error
 }
 
+// Rename renames a job of the namespace in place, so its id and everything
+// kept by it like instances and checkpoints stay with the job
+// Rename renames a job of the namespace keeping its id, dependencies of its
+// dependents, which can be of any project, are saved as rewritten in the same
+// transaction so the job is renamed along with all of them or not at all
+func (repo *JobSpecRepository) Rename(oldName, newName string, dependents []models.JobSpec) error {
+	repo.cache.InvalidateJobs(repo.namespace.ProjectSpec.ID)
+	defer repo.cache.InvalidateJobs(repo.namespace.ProjectSpec.ID)
+
+	if _, _, err := repo.projectJobSpecRepo.GetByName(newName); err == nil {
+		return errors.Errorf("job %s already exists for the project %s", newName, repo.namespace.ProjectSpec.Name)
+	} else if !errors.Is(err, store.ErrResourceNotFound) {
+		return errors.Wrap(err, "unable to retrieve spec by name")
+	}
+
+	var dependentProjectIDs []uuid.UUID
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		// if soft deleted earlier
+		if err := repo.hardDelete(tx, newName); err != nil {
+			return err
+		}
+		result := tx.Model(&Job{}).Where("namespace_id = ? AND name = ?", repo.namespace.ID, oldName).
+			Update("name", newName)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return store.ErrResourceNotFound
+		}
+
+		var dependentIDs []uuid.UUID
+		for _, dependent := range dependents {
+			dependenciesJSON, err := dependenciesToJSON(dependent.Dependencies)
+			if err != nil {
+				return errors.Wrapf(err, "failed to rewrite dependencies of job %s", dependent.Name)
+			}
+			result := tx.Model(&Job{}).Where("id = ?", dependent.ID).Update("dependencies", dependenciesJSON)
+			if result.Error != nil {
+				return errors.Wrapf(result.Error, "failed to rewrite dependencies of job %s", dependent.Name)
+			}
+			if result.RowsAffected == 0 {
+				return errors.Wrapf(store.ErrResourceNotFound, "failed to rewrite dependencies of job %s", dependent.Name)
+			}
+			dependentIDs = append(dependentIDs, dependent.ID)
+		}
+		if len(dependentIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&Job{}).Where("id IN (?)", dependentIDs).Pluck("project_id", &dependentProjectIDs).Error
+	})
+	for _, projectID := range dependentProjectIDs {
+		repo.cache.InvalidateJobs(projectID)
+	}
+	return err
+}
+
 func (repo *JobSpecRepository) HardDelete(name string) error {
+	defer repo.cache.InvalidateJobs(repo.namespace.ProjectSpec.ID)
+	return repo.hardDelete(repo.db, name)
+}
+
+func (repo *JobSpecRepository) hardDelete(db *gorm.DB, name string) error {
 	//find the base job
 	var r Job
-	if err := repo.db.Unscoped().Where("project_id = ? AND name = ?", repo.namespace.ProjectSpec.ID, name).Find(&r).Error; err == gorm.ErrRecordNotFound {
+	if err := db.Unscoped().Where("project_id = ? AND name = ?", repo.namespace.ProjectSpec.ID, name).Find(&r).Error; err == gorm.ErrRecordNotFound {
 		// no job exists, inserting for the first time
 		return nil
 	} else if err != nil {
 		return errors.Wrap(err, "failed to fetch soft deleted resource")
 	}
 	// cascade delete instances
-	if err := repo.db.Unscoped().Where("job_id = ?", r.ID).Delete(&Instance{}).Error; err != nil {
+	if err := db.Unscoped().Where("job_id = ?", r.ID).Delete(&Instance{}).Error; err != nil {
 		return errors.Wrap(err, "failed to cascade delete instances for the job")
 	}
-	return repo.db.Unscoped().Where("id = ?", r.ID).Delete(&Job{}).Error
+	return db.Unscoped().Where("id = ?", r.ID).Delete(&Job{}).Error
 }
 
 func (repo *JobSpecRepository) GetAll() ([]models.JobSpec, error) {
@@ -192,9 +313,10 @@ func (repo *JobSpecRepository) GetAll() ([]models.JobSpec, error) {
 
 func NewJobSpecRepository(db *gorm.DB, namespace models.NamespaceSpec, projectJobSpecRepo store.ProjectJobSpecRepository, adapter *JobSpecAdapter) *JobSpecRepository {
 	return &JobSpecRepository{
-		db:                 db,
+		db:                 withRepository(db, "job_spec"),
 		namespace:          namespace,
 		projectJobSpecRepo: projectJobSpecRepo,
 		adapter:            adapter,
+		cache:              specCache(db),
 	}
 }
@@ -4,40 +4,19 @@ package postgres
 
 import (
 	"context"
-	"os"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestJobRepository(t *testing.T) {
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-		return dbConn
-	}
-
+	t.Parallel()
 	projectSpec := models.ProjectSpec{
 		ID:   uuid.Must(uuid.NewRandom()),
 		Name: "t-optimus-id",
@@ -158,8 +137,7 @@ func TestJobRepository(t *testing.T) {
 
 	t.Run("Insert", func(t *testing.T) {
 		t.Run("insert with hooks and assets should return adapted hooks and assets", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 
 			unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[0].Assets)}
 			depMod1.On("GenerateDestination", context.TODO(), unitData1).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
@@ -198,8 +176,7 @@ func TestJobRepository(t *testing.T) {
 			assert.Equal(t, "event_timestamp > 10000", cval)
 		})
 		t.Run("insert when previously soft deleted should hard delete first along with foreign key cascade", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 
 			unitData1 := models.GenerateDestinationRequest{
 				Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config),
@@ -251,10 +228,108 @@ func TestJobRepository(t *testing.T) {
 			assert.Equal(t, "g-optimus-id", checkModel.Name)
 		})
 	})
+	t.Run("Rename", func(t *testing.T) {
+		t.Run("should rename job keeping its id", func(t *testing.T) {
+			db := setupTestDB(t)
+			testModel := testConfigs[0]
+
+			unitData1 := models.GenerateDestinationRequest{
+				Config: models.PluginConfigs{}.FromJobSpec(testModel.Task.Config),
+				Assets: models.PluginAssets{}.FromJobSpec(testModel.Assets),
+			}
+			depMod1.On("GenerateDestination", context.TODO(), unitData1).Return(
+				&models.GenerateDestinationResponse{Destination: destination}, nil)
+			defer depMod1.AssertExpectations(t)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", "g-optimus-renamed").Return(nil, store.ErrResourceNotFound)
+			projectJobSpecRepo.On("GetByName", "g-optimus-missing").Return(nil, store.ErrResourceNotFound)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			assert.Nil(t, repo.Insert(testModel))
+
+			assert.Nil(t, repo.Rename(testModel.Name, "g-optimus-renamed", nil))
+			renamed, err := repo.GetByID(testModel.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, "g-optimus-renamed", renamed.Name)
+			_, err = repo.GetByName(testModel.Name)
+			assert.Equal(t, store.ErrResourceNotFound, err)
+
+			err = repo.Rename(testModel.Name, "g-optimus-missing", nil)
+			assert.Equal(t, store.ErrResourceNotFound, err)
+		})
+		t.Run("should fail if a job of the project has the new name", func(t *testing.T) {
+			db := setupTestDB(t)
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", "taken").Return(testConfigs[2], namespaceSpec, nil)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			assert.NotNil(t, repo.Rename(testConfigs[0].Name, "taken", nil))
+		})
+		t.Run("should save dependents along with the renamed job", func(t *testing.T) {
+			db := setupTestDB(t)
+			testModel, dependent := testConfigs[0], testConfigs[2]
+
+			unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testModel.Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testModel.Assets)}
+			depMod1.On("GenerateDestination", context.TODO(), unitData1).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+			defer depMod1.AssertExpectations(t)
+			unitData2 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(dependent.Task.Config), Assets: models.PluginAssets{}.FromJobSpec(dependent.Assets)}
+			depMod2.On("GenerateDestination", context.TODO(), unitData2).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+			defer depMod2.AssertExpectations(t)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", "g-optimus-renamed").Return(nil, store.ErrResourceNotFound)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			assert.Nil(t, repo.Insert(testModel))
+			assert.Nil(t, repo.Insert(dependent))
+
+			dependent.Dependencies = map[string]models.JobSpecDependency{
+				"g-optimus-renamed": {Type: models.JobSpecDependencyTypeIntra},
+			}
+			assert.Nil(t, repo.Rename(testModel.Name, "g-optimus-renamed", []models.JobSpec{dependent}))
+			rewritten, err := repo.GetByID(dependent.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]models.JobSpecDependency{
+				"g-optimus-renamed": {Type: models.JobSpecDependencyTypeIntra},
+			}, rewritten.Dependencies)
+		})
+		t.Run("should not rename the job if a dependent fails to be saved", func(t *testing.T) {
+			db := setupTestDB(t)
+			testModel := testConfigs[0]
+
+			unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testModel.Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testModel.Assets)}
+			depMod1.On("GenerateDestination", context.TODO(), unitData1).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+			defer depMod1.AssertExpectations(t)
+
+			projectJobSpecRepo := new(mock.ProjectJobSpecRepository)
+			projectJobSpecRepo.On("GetByName", "g-optimus-renamed").Return(nil, store.ErrResourceNotFound)
+			defer projectJobSpecRepo.AssertExpectations(t)
+
+			repo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			assert.Nil(t, repo.Insert(testModel))
+
+			deleted := models.JobSpec{
+				ID:   uuid.Must(uuid.NewRandom()),
+				Name: "deleted",
+				Dependencies: map[string]models.JobSpecDependency{
+					"g-optimus-renamed": {Type: models.JobSpecDependencyTypeIntra},
+				},
+			}
+			err := repo.Rename(testModel.Name, "g-optimus-renamed", []models.JobSpec{deleted})
+			assert.True(t, errors.Is(err, store.ErrResourceNotFound))
+
+			unchanged, err := repo.GetByID(testModel.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, testModel.Name, unchanged.Name)
+		})
+	})
 	t.Run("Upsert", func(t *testing.T) {
 		t.Run("insert different resource should insert two", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := testConfigs[0]
 			testModelB := testConfigs[2]
 
@@ -295,8 +370,7 @@ func TestJobRepository(t *testing.T) {
 			assert.Equal(t, tTask, taskSchema.Name)
 		})
 		t.Run("insert same resource twice should overwrite existing", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := testConfigs[0]
 
 			unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[0].Assets)}
@@ -342,8 +416,7 @@ func TestJobRepository(t *testing.T) {
 			assert.Equal(t, time.Duration(0), checkModel.Task.Window.Size)
 		})
 		t.Run("upsert without ID should auto generate it", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := testConfigs[0]
 			testModelA.ID = uuid.Nil
 
@@ -359,8 +432,7 @@ func TestJobRepository(t *testing.T) {
 			assert.Equal(t, "g-optimus-id", checkModel.Name)
 		})
 		t.Run("should update same job with hooks when provided separately", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModel := testConfigs[2]
 			testModel.Task.Unit.DependencyMod = nil
 			execUnit2.On("PluginInfo").Return(&models.PluginInfoResponse{
@@ -453,8 +525,7 @@ func TestJobRepository(t *testing.T) {
 			assert.Equal(t, "my_topic.name.kafka", val2)
 		})
 		t.Run("should fail if job is already registered for a project with different namespace", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := testConfigs[0]
 
 			unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[0].Assets)}
@@ -484,8 +555,7 @@ func TestJobRepository(t *testing.T) {
 			assert.Equal(t, "job g-optimus-id already exists for the project t-optimus-id", err.Error())
 		})
 		t.Run("should properly insert spec behavior, reading and writing", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := testConfigs[0]
 
 			unitData1 := models.GenerateDestinationRequest{Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config), Assets: models.PluginAssets{}.FromJobSpec(testConfigs[0].Assets)}
@@ -523,8 +593,7 @@ func TestJobRepository(t *testing.T) {
 	})
 
 	t.Run("GetByName", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.JobSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -541,8 +610,7 @@ func TestJobRepository(t *testing.T) {
 	})
 
 	t.Run("GetAll", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.JobSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -561,28 +629,7 @@ func TestJobRepository(t *testing.T) {
 }
 
 func TestProjectJobRepository(t *testing.T) {
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-		return dbConn
-	}
-
+	t.Parallel()
 	projectSpec := models.ProjectSpec{
 		ID:   uuid.Must(uuid.NewRandom()),
 		Name: "t-optimus-id",
@@ -688,8 +735,7 @@ func TestProjectJobRepository(t *testing.T) {
 	}
 
 	t.Run("GetByName", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.JobSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -714,8 +760,7 @@ func TestProjectJobRepository(t *testing.T) {
 	})
 
 	t.Run("GetAll", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.JobSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -747,8 +792,7 @@ func TestProjectJobRepository(t *testing.T) {
 	})
 
 	t.Run("GetByDestination", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 
 		unitData1 := models.GenerateDestinationRequest{
 			Config: models.PluginConfigs{}.FromJobSpec(testConfigs[0].Task.Config),
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+  id UUID PRIMARY KEY NOT NULL,
+  actor varchar(255) NOT NULL,
+  rpc varchar(255) NOT NULL,
+  project_name varchar(100),
+  payload_hash varchar(64),
+  outcome varchar(30) NOT NULL,
+  message TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_log_project_name_created_at_idx ON audit_log (project_name, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS project_replay_usage;
+DROP TABLE IF EXISTS project_quota;
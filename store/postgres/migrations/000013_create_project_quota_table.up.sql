@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS project_quota (
+  project_id UUID PRIMARY KEY NOT NULL REFERENCES project (id),
+  max_jobs INT NOT NULL DEFAULT 0,
+  max_resources INT NOT NULL DEFAULT 0,
+  max_replay_runs_per_day INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS project_replay_usage (
+  project_id UUID NOT NULL REFERENCES project (id),
+  day DATE NOT NULL,
+  runs INT NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (project_id, day)
+);
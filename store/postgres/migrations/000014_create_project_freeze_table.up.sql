@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS project_freeze (
+  project_id UUID PRIMARY KEY NOT NULL REFERENCES project (id),
+  reason TEXT NOT NULL,
+  actor varchar(255),
+  expires_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS deploy_changelog (
+  id UUID PRIMARY KEY NOT NULL,
+  project_id UUID NOT NULL REFERENCES project (id),
+  namespace varchar(100) NOT NULL,
+  actor varchar(255),
+  changes JSONB,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS deploy_changelog_project_id_created_at_idx ON deploy_changelog (project_id, created_at);
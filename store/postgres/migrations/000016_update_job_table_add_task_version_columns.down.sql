@@ -0,0 +1,2 @@
+ALTER TABLE job DROP IF EXISTS task_plugin_version;
+ALTER TABLE job DROP IF EXISTS task_version;
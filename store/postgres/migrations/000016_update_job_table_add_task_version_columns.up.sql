@@ -0,0 +1,2 @@
+ALTER TABLE job ADD IF NOT EXISTS task_version VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE job ADD IF NOT EXISTS task_plugin_version VARCHAR(50) NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS deployment (
+  id UUID PRIMARY KEY NOT NULL,
+  project_id UUID NOT NULL REFERENCES project (id),
+  source TEXT NOT NULL,
+  actor varchar(255),
+  status varchar(30) NOT NULL,
+  message TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,5 @@
+ALTER TABLE deploy_changelog DROP IF EXISTS snapshot;
+ALTER TABLE deploy_changelog DROP IF EXISTS snapshot_hash;
+ALTER TABLE deploy_changelog DROP IF EXISTS results;
+ALTER TABLE deploy_changelog DROP IF EXISTS failed;
+ALTER TABLE deploy_changelog DROP IF EXISTS rollback_of;
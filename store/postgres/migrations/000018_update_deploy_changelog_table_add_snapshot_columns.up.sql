@@ -0,0 +1,5 @@
+ALTER TABLE deploy_changelog ADD IF NOT EXISTS snapshot BYTEA;
+ALTER TABLE deploy_changelog ADD IF NOT EXISTS snapshot_hash VARCHAR(64);
+ALTER TABLE deploy_changelog ADD IF NOT EXISTS results JSONB;
+ALTER TABLE deploy_changelog ADD IF NOT EXISTS failed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE deploy_changelog ADD IF NOT EXISTS rollback_of UUID;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS job_checkpoint (
+  job_id UUID NOT NULL REFERENCES job (id) ON DELETE CASCADE,
+  name varchar(255) NOT NULL,
+  value TEXT NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (job_id, name)
+);
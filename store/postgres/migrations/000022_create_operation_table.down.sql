@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS operation;
+
+CREATE TABLE IF NOT EXISTS resource_restore (
+  id UUID PRIMARY KEY NOT NULL,
+  project_id UUID NOT NULL REFERENCES project (id),
+  datastore varchar(100) NOT NULL,
+  resource_name varchar(255) NOT NULL,
+  destination varchar(255),
+  point_in_time TIMESTAMP WITH TIME ZONE NOT NULL,
+  actor varchar(255),
+  status varchar(30) NOT NULL,
+  message TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS operation (
+  id UUID PRIMARY KEY NOT NULL,
+  project_id UUID NOT NULL REFERENCES project (id),
+  kind varchar(100) NOT NULL,
+  actor varchar(255),
+  params JSONB,
+  result JSONB,
+  progress_done integer NOT NULL DEFAULT 0,
+  progress_total integer NOT NULL DEFAULT 0,
+  status varchar(30) NOT NULL,
+  message TEXT,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS operation_project_id_created_at_idx ON operation (project_id, created_at);
+
+-- restores of resources are tracked as operations
+DROP TABLE IF EXISTS resource_restore;
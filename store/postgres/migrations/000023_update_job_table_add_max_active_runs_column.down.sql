@@ -0,0 +1 @@
+ALTER TABLE job DROP IF EXISTS max_active_runs;
@@ -0,0 +1 @@
+ALTER TABLE job ADD IF NOT EXISTS max_active_runs INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE job ADD IF NOT EXISTS task_template_engine VARCHAR(20) NOT NULL DEFAULT '';
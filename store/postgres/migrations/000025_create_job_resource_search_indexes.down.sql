@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS resource_labels_idx;
+DROP INDEX IF EXISTS resource_search_idx;
+DROP INDEX IF EXISTS job_labels_idx;
+DROP INDEX IF EXISTS job_search_idx;
@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS job_search_idx ON job USING GIN (
+    (lower(name || ' ' || coalesce(owner, '') || ' ' || coalesce(destination, '') || ' ' || coalesce(task_name, ''))) gin_trgm_ops
+);
+CREATE INDEX IF NOT EXISTS job_labels_idx ON job USING GIN (labels jsonb_path_ops);
+
+CREATE INDEX IF NOT EXISTS resource_search_idx ON resource USING GIN (
+    (lower(name || ' ' || datastore || ' ' || type)) gin_trgm_ops
+);
+CREATE INDEX IF NOT EXISTS resource_labels_idx ON resource USING GIN (labels jsonb_path_ops);
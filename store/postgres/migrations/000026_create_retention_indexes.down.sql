@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS replay_job_id_created_at_idx;
+DROP INDEX IF EXISTS instance_job_id_scheduled_at_idx;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS instance_job_id_scheduled_at_idx ON instance (job_id, scheduled_at);
+CREATE INDEX IF NOT EXISTS replay_job_id_created_at_idx ON replay (job_id, created_at);
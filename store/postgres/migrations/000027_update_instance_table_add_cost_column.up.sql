@@ -0,0 +1 @@
+ALTER TABLE instance ADD IF NOT EXISTS cost JSONB;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS project_cost_budget_alert;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS project_cost_budget_alert (
+  project_id UUID NOT NULL REFERENCES project (id),
+  month DATE NOT NULL,
+  threshold INT NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+  PRIMARY KEY (project_id, month, threshold)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS api_token (
+  id UUID PRIMARY KEY NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  hash VARCHAR(64) NOT NULL UNIQUE,
+  scopes JSONB NOT NULL,
+  actor VARCHAR(255),
+  expires_at TIMESTAMP WITH TIME ZONE,
+  revoked_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
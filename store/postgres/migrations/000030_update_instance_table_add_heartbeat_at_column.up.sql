@@ -0,0 +1 @@
+ALTER TABLE instance ADD IF NOT EXISTS heartbeat_at TIMESTAMP WITH TIME ZONE;
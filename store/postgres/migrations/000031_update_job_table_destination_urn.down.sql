@@ -0,0 +1,2 @@
+UPDATE job SET destination = substring(destination FROM 12) WHERE destination LIKE 'bigquery://%';
+UPDATE job SET destination = 'gs://' || substring(destination FROM 7) WHERE destination LIKE 'gcs://%';
@@ -0,0 +1,2 @@
+UPDATE job SET destination = 'bigquery://' || destination WHERE destination ~ '^[A-Za-z0-9_-]+:[A-Za-z0-9_]+(\.[A-Za-z0-9_-]+)?$';
+UPDATE job SET destination = 'gcs://' || substring(destination FROM 6) WHERE destination LIKE 'gs://_%';
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS project_archive (
+  project_id UUID PRIMARY KEY NOT NULL REFERENCES project (id),
+  reason TEXT NOT NULL,
+  actor varchar(255),
+  snapshot BYTEA,
+  purge_at TIMESTAMP WITH TIME ZONE,
+  purged_at TIMESTAMP WITH TIME ZONE,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS replication_state (
+  project_name VARCHAR(100) NOT NULL,
+  key VARCHAR(512) NOT NULL,
+  checksum VARCHAR(64) NOT NULL,
+  local_checksum VARCHAR(64) NOT NULL,
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (project_name, key)
+);
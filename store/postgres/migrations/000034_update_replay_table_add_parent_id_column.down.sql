@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS replay_parent_id_idx;
+ALTER TABLE replay DROP IF EXISTS parent_id;
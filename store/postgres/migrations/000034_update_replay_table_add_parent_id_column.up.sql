@@ -0,0 +1,2 @@
+ALTER TABLE replay ADD IF NOT EXISTS parent_id UUID;
+CREATE INDEX IF NOT EXISTS replay_parent_id_idx ON replay (parent_id);
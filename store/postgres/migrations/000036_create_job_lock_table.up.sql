@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS job_lock (
+  token UUID NOT NULL,
+  project_name VARCHAR(100) NOT NULL,
+  job_name VARCHAR(220) NOT NULL,
+  operation VARCHAR(30) NOT NULL,
+  holder_id VARCHAR(100),
+  expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+  PRIMARY KEY (token, job_name)
+);
+
+CREATE INDEX IF NOT EXISTS job_lock_project_name_job_name_idx ON job_lock (project_name, job_name);
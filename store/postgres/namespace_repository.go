@@ -92,6 +92,8 @@ type namespaceRepository struct {
 	db      *gorm.DB
 	project models.ProjectSpec
 	hash    models.ApplicationKey
+	// jobs of the project are read along with their namespace
+	cache *SpecCache
 }
 
 func (repo *namespaceRepository) Insert(resource models.NamespaceSpec) error {
@@ -106,6 +108,7 @@ func (repo *namespaceRepository) Insert(resource models.NamespaceSpec) error {
 }
 
 func (repo *namespaceRepository) Save(spec models.NamespaceSpec) error {
+	defer repo.cache.InvalidateJobs(repo.project.ID)
 	existingResource, err := repo.GetByName(spec.Name)
 	if errors.Is(err, store.ErrResourceNotFound) {
 		return repo.Insert(spec)
@@ -150,8 +153,9 @@ func (repo *namespaceRepository) GetAll() ([]models.NamespaceSpec, error) {
 
 func NewNamespaceRepository(db *gorm.DB, project models.ProjectSpec, hash models.ApplicationKey) *namespaceRepository {
 	return &namespaceRepository{
-		db:      db,
+		db:      withRepository(db, "namespace"),
 		project: project,
 		hash:    hash,
+		cache:   specCache(db),
 	}
 }
@@ -3,39 +3,15 @@
 package postgres
 
 import (
-	"os"
 	"testing"
 
 	"github.com/google/uuid"
-	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/models"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNamespaceRepository(t *testing.T) {
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-
-		return dbConn
-	}
-
+	t.Parallel()
 	transporterKafkaBrokerKey := "KAFKA_BROKERS"
 	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
 
@@ -86,8 +62,7 @@ func TestNamespaceRepository(t *testing.T) {
 	}
 
 	t.Run("Insert", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.NamespaceSpec{}
 		testModels = append(testModels, namespaceSpecs...)
 
@@ -119,8 +94,7 @@ func TestNamespaceRepository(t *testing.T) {
 
 	t.Run("Upsert", func(t *testing.T) {
 		t.Run("insert different resource should insert two", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := namespaceSpecs[0]
 			testModelB := namespaceSpecs[2]
 
@@ -144,8 +118,7 @@ func TestNamespaceRepository(t *testing.T) {
 			assert.Equal(t, "10.12.12.12:6668,10.12.12.13:6668", checkModel.Config[transporterKafkaBrokerKey])
 		})
 		t.Run("insert same resource twice should overwrite existing", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := namespaceSpecs[2]
 
 			repo := NewNamespaceRepository(db, projectSpec, hash)
@@ -169,8 +142,7 @@ func TestNamespaceRepository(t *testing.T) {
 			assert.Equal(t, "gs://another_folder", checkModel.Config["bucket"])
 		})
 		t.Run("upsert without ID should auto generate it", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := namespaceSpecs[0]
 			testModelA.ID = uuid.Nil
 
@@ -188,8 +160,7 @@ func TestNamespaceRepository(t *testing.T) {
 	})
 
 	t.Run("GetByName", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.NamespaceSpec{}
 		testModels = append(testModels, namespaceSpecs...)
 
@@ -204,8 +175,7 @@ func TestNamespaceRepository(t *testing.T) {
 	})
 
 	t.Run("GetAll", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.NamespaceSpec{}
 		testModels = append(testModels, namespaceSpecs...)
 
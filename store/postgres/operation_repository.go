@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+	"gorm.io/datatypes"
+)
+
+type Operation struct {
+	ID            uuid.UUID `gorm:"primary_key;type:uuid"`
+	ProjectID     uuid.UUID `gorm:"not null"`
+	Kind          string    `gorm:"not null"`
+	Actor         string
+	Params        datatypes.JSON
+	Result        datatypes.JSON
+	ProgressDone  int
+	ProgressTotal int
+	Status        string `gorm:"not null"`
+	Message       string
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (o Operation) FromSpec(proj models.ProjectSpec, spec *models.Operation) Operation {
+	return Operation{
+		ID:            spec.ID,
+		ProjectID:     proj.ID,
+		Kind:          spec.Kind,
+		Actor:         spec.Actor,
+		Params:        datatypes.JSON(spec.Params),
+		Result:        datatypes.JSON(spec.Result),
+		ProgressDone:  spec.Progress.Done,
+		ProgressTotal: spec.Progress.Total,
+		Status:        spec.Status,
+		Message:       spec.Message,
+		CreatedAt:     spec.CreatedAt.UTC(),
+		UpdatedAt:     spec.UpdatedAt.UTC(),
+	}
+}
+
+func (o Operation) ToSpec() models.Operation {
+	return models.Operation{
+		ID:     o.ID,
+		Kind:   o.Kind,
+		Actor:  o.Actor,
+		Params: []byte(o.Params),
+		Result: []byte(o.Result),
+		Progress: models.OperationProgress{
+			Done:  o.ProgressDone,
+			Total: o.ProgressTotal,
+		},
+		Status:    o.Status,
+		Message:   o.Message,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+type operationRepository struct {
+	db *gorm.DB
+}
+
+func (repo *operationRepository) Insert(proj models.ProjectSpec, op *models.Operation) error {
+	if proj.ID == uuid.Nil {
+		return errors.New("project id cannot be empty")
+	}
+	if op.ID == uuid.Nil {
+		op.ID = uuid.New()
+	}
+	if op.Status == "" {
+		op.Status = models.OperationStatusPending
+	}
+	if op.CreatedAt.IsZero() {
+		op.CreatedAt = time.Now()
+	}
+	op.UpdatedAt = op.CreatedAt
+	o := Operation{}.FromSpec(proj, op)
+	return repo.db.Create(&o).Error
+}
+
+func (repo *operationRepository) UpdateProgress(id uuid.UUID, progress models.OperationProgress) error {
+	return repo.db.Model(&Operation{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"progress_done":  progress.Done,
+		"progress_total": progress.Total,
+		"updated_at":     time.Now().UTC(),
+	}).Error
+}
+
+func (repo *operationRepository) UpdateStatus(id uuid.UUID, status, message string, result []byte) error {
+	updates := map[string]interface{}{
+		"status":     status,
+		"message":    message,
+		"updated_at": time.Now().UTC(),
+	}
+	if result != nil {
+		updates["result"] = datatypes.JSON(result)
+	}
+	return repo.db.Model(&Operation{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (repo *operationRepository) GetByID(proj models.ProjectSpec, id uuid.UUID) (models.Operation, error) {
+	var o Operation
+	if err := repo.db.Where("project_id = ? AND id = ?", proj.ID, id).Find(&o).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Operation{}, store.ErrResourceNotFound
+		}
+		return models.Operation{}, err
+	}
+	return o.ToSpec(), nil
+}
+
+func (repo *operationRepository) GetLatest(proj models.ProjectSpec, kind string, limit int) ([]models.Operation, error) {
+	query := repo.db.Where("project_id = ?", proj.ID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	var ops []Operation
+	if err := query.Order("created_at desc").Limit(limit).Find(&ops).Error; err != nil {
+		return nil, err
+	}
+	specs := []models.Operation{}
+	for _, o := range ops {
+		specs = append(specs, o.ToSpec())
+	}
+	return specs, nil
+}
+
+func NewOperationRepository(db *gorm.DB) *operationRepository {
+	return &operationRepository{
+		db: withRepository(db, "operation"),
+	}
+}
@@ -0,0 +1,87 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationRepository(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus",
+	}
+
+	t.Run("Insert, UpdateProgress, UpdateStatus and GetByID", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewOperationRepository(db)
+		_, err = repo.GetByID(projectSpec, uuid.New())
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		op := &models.Operation{
+			Kind:   models.OperationKindPromotion,
+			Actor:  "jane@example.com",
+			Params: []byte(`{"datastore": "bigquery"}`),
+		}
+		err = repo.Insert(projectSpec, op)
+		assert.Nil(t, err)
+
+		stored, err := repo.GetByID(projectSpec, op.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, models.OperationStatusPending, stored.Status)
+		assert.Equal(t, models.OperationKindPromotion, stored.Kind)
+		assert.JSONEq(t, `{"datastore": "bigquery"}`, string(stored.Params))
+
+		_, err = repo.GetByID(models.ProjectSpec{ID: uuid.New()}, op.ID)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		err = repo.UpdateProgress(op.ID, models.OperationProgress{Done: 1, Total: 2})
+		assert.Nil(t, err)
+		err = repo.UpdateStatus(op.ID, models.OperationStatusFailed, "permission denied", []byte(`[{"source": "playground"}]`))
+		assert.Nil(t, err)
+		stored, err = repo.GetByID(projectSpec, op.ID)
+		assert.Nil(t, err)
+		assert.True(t, stored.IsDone())
+		assert.Equal(t, models.OperationProgress{Done: 1, Total: 2}, stored.Progress)
+		assert.Equal(t, "permission denied", stored.Message)
+		assert.JSONEq(t, `[{"source": "playground"}]`, string(stored.Result))
+	})
+	t.Run("GetLatest", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewOperationRepository(db)
+		now := time.Now()
+		for i, kind := range []string{models.OperationKindPromotion, models.OperationKindResourceRestore, models.OperationKindPromotion} {
+			err = repo.Insert(projectSpec, &models.Operation{
+				Kind:      kind,
+				CreatedAt: now.Add(time.Duration(i) * time.Minute),
+			})
+			assert.Nil(t, err)
+		}
+
+		ops, err := repo.GetLatest(projectSpec, "", 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(ops))
+		assert.Equal(t, models.OperationKindPromotion, ops[0].Kind)
+		assert.Equal(t, models.OperationKindResourceRestore, ops[1].Kind)
+
+		ops, err = repo.GetLatest(projectSpec, models.OperationKindResourceRestore, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(ops))
+	})
+}
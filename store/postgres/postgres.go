@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"context"
+	"database/sql"
 	"embed"
 	"fmt"
 	"net/http"
@@ -21,6 +23,10 @@ var migrationFs embed.FS
 
 const (
 	resourcePath = "migrations"
+
+	// migrationLockID is key of the advisory lock held while migrating, so
+	// replicas of server starting together migrate one at a time
+	migrationLockID int64 = 7153110244
 )
 
 // NewHTTPFSMigrator reads the migrations from httpfs and returns the migrate.Migrate
@@ -47,8 +53,27 @@ func Connect(connURL string, maxIdleConnections, maxOpenConnections int) (*gorm.
 	return db, nil
 }
 
-// Migrate to run up migrations
+// Migrate to run up migrations, it waits for migrations run by others on the
+// same database to finish first. Replicas see no change once the first is done
 func Migrate(connURL string) error {
+	db, err := sql.Open("postgres", connURL)
+	if err != nil {
+		return errors.Wrap(err, "db migrator")
+	}
+	defer db.Close()
+
+	// advisory locks are held by a session, lock and unlock on the same conn
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "db migrator")
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return errors.Wrap(err, "failed to acquire migration lock")
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID)
+
 	m, err := NewHTTPFSMigrator(connURL)
 	if err != nil {
 		return errors.Wrap(err, "db migrator")
@@ -0,0 +1,183 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testDBImage   = "postgres"
+	testDBTag     = "12"
+	testDBName    = "optimus"
+	testDBPass    = "secret"
+	testDBExpiry  = 600 // seconds the container is kept around if tests don't purge it
+	testDBMaxConn = 10
+)
+
+// testDB is the connection shared by repository tests, each test works in a
+// transaction of its own on it
+var testDB *gorm.DB
+
+// testDBURL is the database testDB is connected to
+var testDBURL string
+
+// testDBSkipReason is why repository tests are skipped, set when there is
+// no database to run them against
+var testDBSkipReason string
+
+// TestMain migrates the database at TEST_OPTIMUS_DB_URL for repository tests,
+// or a disposable postgres container started with docker when it is not set.
+// Tests needing the database are skipped if neither is available
+func TestMain(m *testing.M) {
+	dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
+	var purge func()
+	if !ok {
+		var err error
+		if dbURL, purge, err = startTestDBContainer(); err != nil {
+			testDBSkipReason = fmt.Sprintf("unable to start postgres container, set TEST_OPTIMUS_DB_URL to use an existing database: %v", err)
+			os.Exit(m.Run())
+		}
+	}
+
+	code := func() int {
+		if purge != nil {
+			defer purge()
+		}
+		dbConn, err := Connect(dbURL, testDBMaxConn, testDBMaxConn)
+		if err != nil {
+			log.Printf("unable to connect to %s: %v", dbURL, err)
+			return 1
+		}
+		defer dbConn.Close()
+		if err := migrateTestDB(dbURL); err != nil {
+			log.Printf("unable to migrate %s: %v", dbURL, err)
+			return 1
+		}
+		testDB = dbConn
+		testDBURL = dbURL
+		return m.Run()
+	}()
+	os.Exit(code)
+}
+
+// startTestDBContainer runs a postgres container and waits till it accepts
+// connections, purge removes the container
+func startTestDBContainer() (dbURL string, purge func(), err error) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return "", nil, err
+	}
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: testDBImage,
+		Tag:        testDBTag,
+		Env: []string{
+			"POSTGRES_PASSWORD=" + testDBPass,
+			"POSTGRES_DB=" + testDBName,
+		},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	purge = func() {
+		if err := pool.Purge(resource); err != nil {
+			log.Printf("unable to purge postgres container: %v", err)
+		}
+	}
+	// container is reaped even if the test binary is killed before purging
+	if err := resource.Expire(testDBExpiry); err != nil {
+		purge()
+		return "", nil, err
+	}
+
+	dbURL = fmt.Sprintf("postgres://postgres:%s@%s/%s?sslmode=disable", testDBPass,
+		resource.GetHostPort("5432/tcp"), testDBName)
+	if err := pool.Retry(func() error {
+		dbConn, err := Connect(dbURL, 1, 1)
+		if err != nil {
+			return err
+		}
+		defer dbConn.Close()
+		return dbConn.DB().Ping()
+	}); err != nil {
+		purge()
+		return "", nil, err
+	}
+	return dbURL, purge, nil
+}
+
+func migrateTestDB(dbURL string) error {
+	m, err := NewHTTPFSMigrator(dbURL)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	if err := m.Drop(); err != nil {
+		return err
+	}
+	return Migrate(dbURL)
+}
+
+func TestMigrate(t *testing.T) {
+	skipWithoutTestDB(t)
+	t.Run("should migrate concurrently with other replicas", func(t *testing.T) {
+		errs := make(chan error, 3)
+		for i := 0; i < cap(errs); i++ {
+			go func() {
+				errs <- Migrate(testDBURL)
+			}()
+		}
+		for i := 0; i < cap(errs); i++ {
+			assert.Nil(t, <-errs)
+		}
+	})
+	t.Run("should release the migration lock once done", func(t *testing.T) {
+		assert.Nil(t, Migrate(testDBURL))
+
+		ctx := context.Background()
+		conn, err := testDB.DB().Conn(ctx)
+		assert.Nil(t, err)
+		defer conn.Close()
+		var locked bool
+		assert.Nil(t, conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationLockID).Scan(&locked))
+		assert.True(t, locked)
+		_, err = conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID)
+		assert.Nil(t, err)
+	})
+}
+
+// skipWithoutTestDB skips a test when there is no database to run it against
+func skipWithoutTestDB(t *testing.T) {
+	t.Helper()
+	if testDB == nil {
+		t.Skip(testDBSkipReason)
+	}
+}
+
+// setupTestDB begins a transaction for a test which is rolled back once the
+// test and its subtests complete, tests don't see rows written by others
+// and can run in parallel as long as they don't write the same rows
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	skipWithoutTestDB(t)
+	tx := testDB.Begin()
+	if tx.Error != nil {
+		t.Fatalf("unable to begin transaction: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		tx.Rollback()
+	})
+	return tx
+}
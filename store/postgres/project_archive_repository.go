@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+type ProjectArchive struct {
+	ProjectID uuid.UUID `gorm:"primary_key;type:uuid"`
+	Reason    string    `gorm:"not null"`
+	Actor     string
+	Snapshot  []byte
+	PurgeAt   *time.Time
+	PurgedAt  *time.Time
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+}
+
+func (a ProjectArchive) FromSpec(proj models.ProjectSpec, spec models.ProjectArchive) ProjectArchive {
+	return ProjectArchive{
+		ProjectID: proj.ID,
+		Reason:    spec.Reason,
+		Actor:     spec.Actor,
+		Snapshot:  spec.Snapshot,
+		PurgeAt:   nullableTime(spec.PurgeAt),
+		PurgedAt:  nullableTime(spec.PurgedAt),
+		CreatedAt: spec.CreatedAt.UTC(),
+	}
+}
+
+func (a ProjectArchive) ToSpec() models.ProjectArchive {
+	spec := models.ProjectArchive{
+		Reason:    a.Reason,
+		Actor:     a.Actor,
+		Snapshot:  a.Snapshot,
+		CreatedAt: a.CreatedAt,
+	}
+	if a.PurgeAt != nil {
+		spec.PurgeAt = *a.PurgeAt
+	}
+	if a.PurgedAt != nil {
+		spec.PurgedAt = *a.PurgedAt
+	}
+	return spec
+}
+
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+type projectArchiveRepository struct {
+	db *gorm.DB
+}
+
+// Save archives the project, replacing the existing archive if any
+func (repo *projectArchiveRepository) Save(proj models.ProjectSpec, archive models.ProjectArchive) error {
+	if proj.ID == uuid.Nil {
+		return errors.New("project id cannot be empty")
+	}
+	if archive.CreatedAt.IsZero() {
+		archive.CreatedAt = time.Now()
+	}
+	a := ProjectArchive{}.FromSpec(proj, archive)
+	return repo.db.Save(&a).Error
+}
+
+func (repo *projectArchiveRepository) GetByProject(proj models.ProjectSpec) (models.ProjectArchive, error) {
+	var a ProjectArchive
+	if err := repo.db.Where("project_id = ?", proj.ID).Find(&a).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ProjectArchive{}, store.ErrResourceNotFound
+		}
+		return models.ProjectArchive{}, err
+	}
+	return a.ToSpec(), nil
+}
+
+func (repo *projectArchiveRepository) Delete(proj models.ProjectSpec) error {
+	return repo.db.Where("project_id = ?", proj.ID).Delete(&ProjectArchive{}).Error
+}
+
+func NewProjectArchiveRepository(db *gorm.DB) *projectArchiveRepository {
+	return &projectArchiveRepository{
+		db: withRepository(db, "project_archive"),
+	}
+}
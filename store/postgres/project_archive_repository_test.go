@@ -0,0 +1,58 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectArchiveRepository(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus",
+	}
+
+	t.Run("Save, GetByProject and Delete", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewProjectArchiveRepository(db)
+		_, err = repo.GetByProject(projectSpec)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		purgeAt := time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC)
+		err = repo.Save(projectSpec, models.ProjectArchive{Reason: "team moved", Actor: "alice",
+			Snapshot: []byte(`{"namespaces":[]}`), PurgeAt: purgeAt})
+		assert.Nil(t, err)
+
+		archive, err := repo.GetByProject(projectSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, "team moved", archive.Reason)
+		assert.Equal(t, []byte(`{"namespaces":[]}`), archive.Snapshot)
+		assert.True(t, purgeAt.Equal(archive.PurgeAt))
+		assert.False(t, archive.IsPurged())
+
+		archive.Snapshot, archive.PurgedAt = nil, purgeAt.Add(time.Hour)
+		err = repo.Save(projectSpec, archive)
+		assert.Nil(t, err)
+		archive, err = repo.GetByProject(projectSpec)
+		assert.Nil(t, err)
+		assert.Nil(t, archive.Snapshot)
+		assert.True(t, archive.IsPurged())
+
+		err = repo.Delete(projectSpec)
+		assert.Nil(t, err)
+		_, err = repo.GetByProject(projectSpec)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+}
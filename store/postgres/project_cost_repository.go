@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// runs of deleted jobs billed the project all the same
+	sumBytesBilledSQL = `SELECT COALESCE(SUM((instance.cost->>'bytes_billed')::BIGINT), 0) AS bytes_billed
+	FROM instance JOIN job ON job.id = instance.job_id
+	WHERE job.project_id = ? AND instance.scheduled_at >= ? AND instance.cost IS NOT NULL
+	AND instance.deleted_at IS NULL`
+
+	projectCostMonthLayout = "2006-01-02"
+)
+
+type projectCostRepository struct {
+	db *gorm.DB
+}
+
+func (repo *projectCostRepository) GetBytesBilled(proj models.ProjectSpec, since time.Time) (int64, error) {
+	var result struct {
+		BytesBilled int64
+	}
+	if err := repo.db.Raw(sumBytesBilledSQL, proj.ID, since.UTC()).Scan(&result).Error; err != nil {
+		return 0, err
+	}
+	return result.BytesBilled, nil
+}
+
+// SaveBudgetAlert inserts the alert unless recorded already, so that only
+// one of the servers sharing the database alerts a threshold
+func (repo *projectCostRepository) SaveBudgetAlert(proj models.ProjectSpec, month time.Time, threshold int) (bool, error) {
+	if proj.ID == uuid.Nil {
+		return false, errors.New("project id cannot be empty")
+	}
+	res := repo.db.Exec(`INSERT INTO project_cost_budget_alert (project_id, month, threshold, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (project_id, month, threshold) DO NOTHING`,
+		proj.ID, models.MonthStart(month).Format(projectCostMonthLayout), threshold, time.Now().UTC())
+	return res.RowsAffected > 0, res.Error
+}
+
+func NewProjectCostRepository(db *gorm.DB) *projectCostRepository {
+	return &projectCostRepository{
+		db: withRepository(db, "project_cost"),
+	}
+}
@@ -0,0 +1,51 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectCostRepository(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus",
+	}
+
+	t.Run("GetBytesBilled", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		bytesBilled, err := NewProjectCostRepository(db).GetBytesBilled(projectSpec, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), bytesBilled)
+	})
+	t.Run("SaveBudgetAlert", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewProjectCostRepository(db)
+		june := time.Date(2021, 6, 10, 8, 0, 0, 0, time.UTC)
+
+		saved, err := repo.SaveBudgetAlert(projectSpec, june, 50)
+		assert.Nil(t, err)
+		assert.True(t, saved)
+		saved, err = repo.SaveBudgetAlert(projectSpec, june.AddDate(0, 0, 5), 50)
+		assert.Nil(t, err)
+		assert.False(t, saved)
+		saved, err = repo.SaveBudgetAlert(projectSpec, june.AddDate(0, 1, 0), 50)
+		assert.Nil(t, err)
+		assert.True(t, saved)
+	})
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+type ProjectFreeze struct {
+	ProjectID uuid.UUID `gorm:"primary_key;type:uuid"`
+	Reason    string    `gorm:"not null"`
+	Actor     string
+	ExpiresAt *time.Time
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+}
+
+func (f ProjectFreeze) FromSpec(proj models.ProjectSpec, spec models.ProjectFreeze) ProjectFreeze {
+	var expiresAt *time.Time
+	if !spec.ExpiresAt.IsZero() {
+		expires := spec.ExpiresAt.UTC()
+		expiresAt = &expires
+	}
+	return ProjectFreeze{
+		ProjectID: proj.ID,
+		Reason:    spec.Reason,
+		Actor:     spec.Actor,
+		ExpiresAt: expiresAt,
+		CreatedAt: spec.CreatedAt.UTC(),
+	}
+}
+
+func (f ProjectFreeze) ToSpec() models.ProjectFreeze {
+	spec := models.ProjectFreeze{
+		Reason:    f.Reason,
+		Actor:     f.Actor,
+		CreatedAt: f.CreatedAt,
+	}
+	if f.ExpiresAt != nil {
+		spec.ExpiresAt = *f.ExpiresAt
+	}
+	return spec
+}
+
+type projectFreezeRepository struct {
+	db *gorm.DB
+}
+
+// Save freezes the project, replacing the existing freeze if any
+func (repo *projectFreezeRepository) Save(proj models.ProjectSpec, freeze models.ProjectFreeze) error {
+	if proj.ID == uuid.Nil {
+		return errors.New("project id cannot be empty")
+	}
+	if freeze.CreatedAt.IsZero() {
+		freeze.CreatedAt = time.Now()
+	}
+	f := ProjectFreeze{}.FromSpec(proj, freeze)
+	return repo.db.Save(&f).Error
+}
+
+func (repo *projectFreezeRepository) GetByProject(proj models.ProjectSpec) (models.ProjectFreeze, error) {
+	var f ProjectFreeze
+	if err := repo.db.Where("project_id = ?", proj.ID).Find(&f).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ProjectFreeze{}, store.ErrResourceNotFound
+		}
+		return models.ProjectFreeze{}, err
+	}
+	return f.ToSpec(), nil
+}
+
+func (repo *projectFreezeRepository) Delete(proj models.ProjectSpec) error {
+	return repo.db.Where("project_id = ?", proj.ID).Delete(&ProjectFreeze{}).Error
+}
+
+func NewProjectFreezeRepository(db *gorm.DB) *projectFreezeRepository {
+	return &projectFreezeRepository{
+		db: withRepository(db, "project_freeze"),
+	}
+}
@@ -0,0 +1,50 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectFreezeRepository(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus",
+	}
+
+	t.Run("Save, GetByProject and Delete", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewProjectFreezeRepository(db)
+		_, err = repo.GetByProject(projectSpec)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		err = repo.Save(projectSpec, models.ProjectFreeze{Reason: "incident", Actor: "alice"})
+		assert.Nil(t, err)
+		expiresAt := time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC)
+		err = repo.Save(projectSpec, models.ProjectFreeze{Reason: "release", Actor: "bob", ExpiresAt: expiresAt})
+		assert.Nil(t, err)
+
+		freeze, err := repo.GetByProject(projectSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, "release", freeze.Reason)
+		assert.Equal(t, "bob", freeze.Actor)
+		assert.True(t, expiresAt.Equal(freeze.ExpiresAt))
+
+		err = repo.Delete(projectSpec)
+		assert.Nil(t, err)
+		_, err = repo.GetByProject(projectSpec)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	projectQuotaDayLayout = "2006-01-02"
+)
+
+type ProjectQuota struct {
+	ProjectID           uuid.UUID `gorm:"primary_key;type:uuid"`
+	MaxJobs             int
+	MaxResources        int
+	MaxReplayRunsPerDay int
+
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+func (q ProjectQuota) ToSpec() models.ProjectQuota {
+	return models.ProjectQuota{
+		MaxJobs:             q.MaxJobs,
+		MaxResources:        q.MaxResources,
+		MaxReplayRunsPerDay: q.MaxReplayRunsPerDay,
+		UpdatedAt:           q.UpdatedAt,
+	}
+}
+
+type ProjectReplayUsage struct {
+	ProjectID uuid.UUID `gorm:"primary_key;type:uuid"`
+	Day       time.Time `gorm:"primary_key;type:date"`
+	Runs      int
+}
+
+type projectQuotaRepository struct {
+	db *gorm.DB
+}
+
+// Save creates quota of the project or overwrites the existing one
+func (repo *projectQuotaRepository) Save(proj models.ProjectSpec, quota models.ProjectQuota) error {
+	if proj.ID == uuid.Nil {
+		return errors.New("project id cannot be empty")
+	}
+	now := time.Now().UTC()
+	return repo.db.Exec(`INSERT INTO project_quota
+		(project_id, max_jobs, max_resources, max_replay_runs_per_day, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (project_id) DO UPDATE SET
+		max_jobs = EXCLUDED.max_jobs,
+		max_resources = EXCLUDED.max_resources,
+		max_replay_runs_per_day = EXCLUDED.max_replay_runs_per_day,
+		updated_at = EXCLUDED.updated_at`,
+		proj.ID, quota.MaxJobs, quota.MaxResources, quota.MaxReplayRunsPerDay, now, now).Error
+}
+
+func (repo *projectQuotaRepository) GetByProject(proj models.ProjectSpec) (models.ProjectQuota, error) {
+	var q ProjectQuota
+	if err := repo.db.Where("project_id = ?", proj.ID).Find(&q).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ProjectQuota{}, store.ErrResourceNotFound
+		}
+		return models.ProjectQuota{}, err
+	}
+	return q.ToSpec(), nil
+}
+
+// AddReplayRuns atomically increments the replay usage of the project in
+// the day, day is truncated to date in UTC
+func (repo *projectQuotaRepository) AddReplayRuns(proj models.ProjectSpec, day time.Time, runs int) error {
+	return repo.db.Exec(`INSERT INTO project_replay_usage (project_id, day, runs)
+		VALUES (?, ?, ?)
+		ON CONFLICT (project_id, day) DO UPDATE SET
+		runs = project_replay_usage.runs + EXCLUDED.runs`,
+		proj.ID, day.UTC().Format(projectQuotaDayLayout), runs).Error
+}
+
+// ReserveReplayRuns increments the replay usage of the project in the day
+// in a single conditional upsert, so concurrent reservations can't exceed
+// limit together
+func (repo *projectQuotaRepository) ReserveReplayRuns(proj models.ProjectSpec, day time.Time, runs, limit int) (bool, error) {
+	res := repo.db.Exec(`INSERT INTO project_replay_usage (project_id, day, runs)
+		SELECT ?::uuid, ?::date, ?::int WHERE ?::int <= ?::int
+		ON CONFLICT (project_id, day) DO UPDATE SET
+		runs = project_replay_usage.runs + EXCLUDED.runs
+		WHERE project_replay_usage.runs + EXCLUDED.runs <= ?::int`,
+		proj.ID, day.UTC().Format(projectQuotaDayLayout), runs, runs, limit, limit)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (repo *projectQuotaRepository) GetReplayRuns(proj models.ProjectSpec, day time.Time) (int, error) {
+	var usage ProjectReplayUsage
+	if err := repo.db.Table("project_replay_usage").
+		Where("project_id = ? AND day = ?", proj.ID, day.UTC().Format(projectQuotaDayLayout)).
+		Find(&usage).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return usage.Runs, nil
+}
+
+func NewProjectQuotaRepository(db *gorm.DB) *projectQuotaRepository {
+	return &projectQuotaRepository{
+		db: withRepository(db, "project_quota"),
+	}
+}
@@ -0,0 +1,92 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectQuotaRepository(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus",
+	}
+
+	t.Run("Save and GetByProject", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewProjectQuotaRepository(db)
+		_, err = repo.GetByProject(projectSpec)
+		assert.Equal(t, store.ErrResourceNotFound, err)
+
+		err = repo.Save(projectSpec, models.ProjectQuota{MaxJobs: 10, MaxResources: 20})
+		assert.Nil(t, err)
+		err = repo.Save(projectSpec, models.ProjectQuota{MaxJobs: 15, MaxReplayRunsPerDay: 100})
+		assert.Nil(t, err)
+
+		quota, err := repo.GetByProject(projectSpec)
+		assert.Nil(t, err)
+		assert.Equal(t, 15, quota.MaxJobs)
+		assert.Equal(t, 0, quota.MaxResources)
+		assert.Equal(t, 100, quota.MaxReplayRunsPerDay)
+	})
+	t.Run("AddReplayRuns and GetReplayRuns", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewProjectQuotaRepository(db)
+		day := time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC)
+
+		runs, err := repo.GetReplayRuns(projectSpec, day)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, runs)
+
+		assert.Nil(t, repo.AddReplayRuns(projectSpec, day, 5))
+		assert.Nil(t, repo.AddReplayRuns(projectSpec, day.Add(time.Hour*10), 3))
+		assert.Nil(t, repo.AddReplayRuns(projectSpec, day.Add(time.Hour*24), 7))
+
+		runs, err = repo.GetReplayRuns(projectSpec, day)
+		assert.Nil(t, err)
+		assert.Equal(t, 8, runs)
+	})
+	t.Run("ReserveReplayRuns", func(t *testing.T) {
+		db := setupTestDB(t)
+
+		err := NewProjectRepository(db, hash).Insert(projectSpec)
+		assert.Nil(t, err)
+
+		repo := NewProjectQuotaRepository(db)
+		day := time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC)
+
+		reserved, err := repo.ReserveReplayRuns(projectSpec, day, 11, 10)
+		assert.Nil(t, err)
+		assert.False(t, reserved)
+
+		reserved, err = repo.ReserveReplayRuns(projectSpec, day, 6, 10)
+		assert.Nil(t, err)
+		assert.True(t, reserved)
+		reserved, err = repo.ReserveReplayRuns(projectSpec, day, 5, 10)
+		assert.Nil(t, err)
+		assert.False(t, reserved)
+		reserved, err = repo.ReserveReplayRuns(projectSpec, day, 4, 10)
+		assert.Nil(t, err)
+		assert.True(t, reserved)
+
+		runs, err := repo.GetReplayRuns(projectSpec, day)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, runs)
+	})
+}
@@ -72,11 +72,13 @@ func (p Project) ToSpecWithSecrets(h models.ApplicationKey) (models.ProjectSpec,
 }
 
 type ProjectRepository struct {
-	db   *gorm.DB
-	hash models.ApplicationKey
+	db    *gorm.DB
+	hash  models.ApplicationKey
+	cache *SpecCache
 }
 
 func (repo *ProjectRepository) Insert(resource models.ProjectSpec) error {
+	defer repo.cache.InvalidateProject(resource.Name)
 	p, err := Project{}.FromSpec(resource)
 	if err != nil {
 		return err
@@ -88,6 +90,7 @@ func (repo *ProjectRepository) Insert(resource models.ProjectSpec) error {
 }
 
 func (repo *ProjectRepository) Save(spec models.ProjectSpec) error {
+	defer repo.cache.InvalidateProject(spec.Name)
 	existingResource, err := repo.GetByName(spec.Name)
 	if errors.Is(err, store.ErrResourceNotFound) {
 		return repo.Insert(spec)
@@ -103,6 +106,11 @@ func (repo *ProjectRepository) Save(spec models.ProjectSpec) error {
 }
 
 func (repo *ProjectRepository) GetByName(name string) (models.ProjectSpec, error) {
+	key := specCacheKey{cache: SpecCacheProject, project: name}
+	if cached, ok := repo.cache.get(key); ok {
+		return cached.(Project).ToSpecWithSecrets(repo.hash)
+	}
+
 	var r Project
 	if err := repo.db.Preload("Secrets").Where("name = ?", name).Find(&r).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -110,6 +118,7 @@ func (repo *ProjectRepository) GetByName(name string) (models.ProjectSpec, error
 		}
 		return models.ProjectSpec{}, err
 	}
+	repo.cache.set(key, r)
 	return r.ToSpecWithSecrets(repo.hash)
 }
 
@@ -127,8 +136,14 @@ func (repo *ProjectRepository) GetByID(id uuid.UUID) (models.ProjectSpec, error)
 func (repo *ProjectRepository) GetAll() ([]models.ProjectSpec, error) {
 	specs := []models.ProjectSpec{}
 	projs := []Project{}
-	if err := repo.db.Preload("Secrets").Find(&projs).Error; err != nil {
-		return specs, err
+	key := specCacheKey{cache: SpecCacheProject, key: "*"}
+	if cached, ok := repo.cache.get(key); ok {
+		projs = cached.([]Project)
+	} else {
+		if err := repo.db.Preload("Secrets").Find(&projs).Error; err != nil {
+			return specs, err
+		}
+		repo.cache.set(key, projs)
 	}
 	for _, proj := range projs {
 		adapt, err := proj.ToSpecWithSecrets(repo.hash)
@@ -142,7 +157,8 @@ func (repo *ProjectRepository) GetAll() ([]models.ProjectSpec, error) {
 
 func NewProjectRepository(db *gorm.DB, hash models.ApplicationKey) *ProjectRepository {
 	return &ProjectRepository{
-		db:   db,
-		hash: hash,
+		db:    withRepository(db, "project"),
+		hash:  hash,
+		cache: specCache(db),
 	}
 }
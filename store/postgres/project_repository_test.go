@@ -3,40 +3,16 @@
 package postgres
 
 import (
-	"os"
 	"sort"
 	"testing"
 
 	"github.com/google/uuid"
-	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/models"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestProjectRepository(t *testing.T) {
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-
-		return dbConn
-	}
-
+	t.Parallel()
 	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
 
 	transporterKafkaBrokerKey := "KAFKA_BROKERS"
@@ -67,8 +43,7 @@ func TestProjectRepository(t *testing.T) {
 	}
 
 	t.Run("Insert", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.ProjectSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -86,8 +61,7 @@ func TestProjectRepository(t *testing.T) {
 	})
 	t.Run("Upsert", func(t *testing.T) {
 		t.Run("insert different resource should insert two", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := testConfigs[0]
 			testModelB := testConfigs[2]
 
@@ -111,8 +85,7 @@ func TestProjectRepository(t *testing.T) {
 			assert.Equal(t, "10.12.12.12:6668,10.12.12.13:6668", checkModel.Config[transporterKafkaBrokerKey])
 		})
 		t.Run("insert same resource twice should overwrite existing", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := testConfigs[2]
 
 			repo := NewProjectRepository(db, hash)
@@ -136,8 +109,7 @@ func TestProjectRepository(t *testing.T) {
 			assert.Equal(t, "gs://another_folder", checkModel.Config["bucket"])
 		})
 		t.Run("upsert without ID should auto generate it", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			testModelA := testConfigs[0]
 			testModelA.ID = uuid.Nil
 
@@ -153,8 +125,7 @@ func TestProjectRepository(t *testing.T) {
 		})
 	})
 	t.Run("GetByName", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.ProjectSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -177,8 +148,7 @@ func TestProjectRepository(t *testing.T) {
 		assert.Equal(t, "v1", sec)
 	})
 	t.Run("GetAll", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		testModels := []models.ProjectSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -3,6 +3,7 @@ package postgres
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"gorm.io/datatypes"
@@ -23,6 +24,7 @@ type Replay struct {
 	EndDate   time.Time `gorm:"not null"`
 	Status    string    `gorm:"not null"`
 	Message   datatypes.JSON
+	ParentID  *uuid.UUID
 
 	CreatedAt time.Time `gorm:"not null" json:"created_at"`
 	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
@@ -33,6 +35,10 @@ func (p Replay) FromSpec(spec *models.ReplaySpec) (Replay, error) {
 	if err != nil {
 		return Replay{}, nil
 	}
+	var parentID *uuid.UUID
+	if spec.ParentID != uuid.Nil {
+		parentID = &spec.ParentID
+	}
 	return Replay{
 		ID:        spec.ID,
 		JobID:     spec.Job.ID,
@@ -40,6 +46,7 @@ func (p Replay) FromSpec(spec *models.ReplaySpec) (Replay, error) {
 		EndDate:   spec.EndDate.UTC(),
 		Status:    spec.Status,
 		Message:   jsonBytes,
+		ParentID:  parentID,
 	}, nil
 }
 
@@ -48,7 +55,7 @@ func (p Replay) ToSpec(jobSpec models.JobSpec) (models.ReplaySpec, error) {
 	if err := json.Unmarshal(p.Message, &message); err != nil {
 		return models.ReplaySpec{}, nil
 	}
-	return models.ReplaySpec{
+	spec := models.ReplaySpec{
 		ID:        p.ID,
 		Job:       jobSpec,
 		Status:    p.Status,
@@ -56,7 +63,11 @@ func (p Replay) ToSpec(jobSpec models.JobSpec) (models.ReplaySpec, error) {
 		EndDate:   p.EndDate,
 		Message:   message,
 		CreatedAt: p.CreatedAt,
-	}, nil
+	}
+	if p.ParentID != nil {
+		spec.ParentID = *p.ParentID
+	}
+	return spec, nil
 }
 
 type replayRepository struct {
@@ -67,7 +78,7 @@ type replayRepository struct {
 
 func NewReplayRepository(db *gorm.DB, jobSpec models.JobSpec, jobAdapter *JobSpecAdapter) *replayRepository {
 	return &replayRepository{
-		DB:      db,
+		DB:      withRepository(db, "replay"),
 		jobSpec: jobSpec,
 		adapter: jobAdapter,
 	}
@@ -83,7 +94,7 @@ func (repo *replayRepository) Insert(replay *models.ReplaySpec) error {
 
 func (repo *replayRepository) GetByID(id uuid.UUID) (models.ReplaySpec, error) {
 	var r Replay
-	if err := repo.DB.Where("id = ?", id).Find(&r).Error; err != nil {
+	if err := repo.DB.Where("id = ? AND job_id = ?", id, repo.jobSpec.ID).Find(&r).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return models.ReplaySpec{}, store.ErrResourceNotFound
 		}
@@ -92,18 +103,32 @@ func (repo *replayRepository) GetByID(id uuid.UUID) (models.ReplaySpec, error) {
 	return r.ToSpec(repo.jobSpec)
 }
 
+// UpdateStatus moves a replay to status, only if the transition is valid
+// from the status it is in. Status is checked and updated in a single
+// statement so that concurrent updates can't both move a replay out of the
+// same status, the one losing gets models.ErrInvalidReplayTransition
 func (repo *replayRepository) UpdateStatus(replayID uuid.UUID, status string, message models.ReplayMessage) error {
-	var r Replay
-	if err := repo.DB.Where("id = ?", replayID).Find(&r).Error; err != nil {
-		return errors.New("could not update non-existing replay")
-	}
 	jsonBytes, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
-	r.Status = status
-	r.Message = jsonBytes
-	return repo.DB.Save(&r).Error
+	result := repo.DB.Model(&Replay{}).Where("id = ? AND status IN (?)", replayID, models.ReplayStatusesBefore(status)).
+		Updates(map[string]interface{}{
+			"status":  status,
+			"message": datatypes.JSON(jsonBytes),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var r Replay
+	if err := repo.DB.Where("id = ?", replayID).Find(&r).Error; err != nil {
+		return errors.New("could not update non-existing replay")
+	}
+	return fmt.Errorf("%w: %s to %s", models.ErrInvalidReplayTransition, r.Status, status)
 }
 
 func (repo *replayRepository) GetByStatus(status []string) ([]models.ReplaySpec, error) {
@@ -115,19 +140,7 @@ func (repo *replayRepository) GetByStatus(status []string) ([]models.ReplaySpec,
 		return []models.ReplaySpec{}, err
 	}
 
-	var replaySpecs []models.ReplaySpec
-	for _, r := range replays {
-		jobSpec, err := repo.adapter.ToSpec(r.Job)
-		if err != nil {
-			return []models.ReplaySpec{}, err
-		}
-		replaySpec, err := r.ToSpec(jobSpec)
-		if err != nil {
-			return []models.ReplaySpec{}, err
-		}
-		replaySpecs = append(replaySpecs, replaySpec)
-	}
-	return replaySpecs, nil
+	return repo.toSpecs(replays)
 }
 
 func (repo *replayRepository) GetByJobIDAndStatus(jobID uuid.UUID, status []string) ([]models.ReplaySpec, error) {
@@ -139,11 +152,32 @@ func (repo *replayRepository) GetByJobIDAndStatus(jobID uuid.UUID, status []stri
 		return []models.ReplaySpec{}, err
 	}
 
+	return repo.toSpecs(replays)
+}
+
+func (repo *replayRepository) GetByParentID(parentID uuid.UUID) ([]models.ReplaySpec, error) {
+	var replays []Replay
+	if err := repo.DB.Where("parent_id = ?", parentID).Order("created_at").Preload("Job").Find(&replays).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return []models.ReplaySpec{}, store.ErrResourceNotFound
+		}
+		return []models.ReplaySpec{}, err
+	}
+
+	return repo.toSpecs(replays)
+}
+
+// toSpecs converts replays along with their jobs, replays of plans have no
+// job of their own
+func (repo *replayRepository) toSpecs(replays []Replay) ([]models.ReplaySpec, error) {
 	var replaySpecs []models.ReplaySpec
 	for _, r := range replays {
-		jobSpec, err := repo.adapter.ToSpec(r.Job)
-		if err != nil {
-			return []models.ReplaySpec{}, err
+		var jobSpec models.JobSpec
+		if r.JobID != uuid.Nil {
+			var err error
+			if jobSpec, err = repo.adapter.ToSpec(r.Job); err != nil {
+				return []models.ReplaySpec{}, err
+			}
 		}
 		replaySpec, err := r.ToSpec(jobSpec)
 		if err != nil {
@@ -4,18 +4,19 @@ package postgres
 
 import (
 	"context"
-	"os"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jinzhu/gorm"
 	"github.com/odpf/optimus/mock"
 	"github.com/odpf/optimus/models"
+	"github.com/odpf/optimus/store"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestReplayRepository(t *testing.T) {
+	t.Parallel()
 	projectSpec := models.ProjectSpec{
 		ID:   uuid.Must(uuid.NewRandom()),
 		Name: "t-optimus-id",
@@ -67,32 +68,8 @@ func TestReplayRepository(t *testing.T) {
 		},
 	}
 
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-
-		return dbConn
-	}
-
 	t.Run("Insert and GetByID", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 
 		execUnit1 := new(mock.BasePlugin)
 		defer execUnit1.AssertExpectations(t)
@@ -116,11 +93,13 @@ func TestReplayRepository(t *testing.T) {
 		checkModel, err := repo.GetByID(testModels[0].ID)
 		assert.Nil(t, err)
 		assert.Equal(t, testModels[0].ID, checkModel.ID)
+
+		_, err = NewReplayRepository(db, jobConfigs[1], adapter).GetByID(testModels[0].ID)
+		assert.Equal(t, store.ErrResourceNotFound, err)
 	})
 
 	t.Run("UpdateStatus", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := setupTestDB(t)
 		var testModels []*models.ReplaySpec
 		testModels = append(testModels, testConfigs...)
 
@@ -152,12 +131,18 @@ func TestReplayRepository(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, models.ReplayStatusFailed, checkModel.Status)
 		assert.Equal(t, errMessage, checkModel.Message.Message)
+
+		// ended replays don't move anymore
+		err = repo.UpdateStatus(testModels[0].ID, models.ReplayStatusInProgress, models.ReplayMessage{})
+		assert.True(t, errors.Is(err, models.ErrInvalidReplayTransition))
+		checkModel, err = repo.GetByID(testModels[0].ID)
+		assert.Nil(t, err)
+		assert.Equal(t, models.ReplayStatusFailed, checkModel.Status)
 	})
 
 	t.Run("GetJobByStatus", func(t *testing.T) {
 		t.Run("should return list of job specs given list of status", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			var testModels []*models.ReplaySpec
 			testModels = append(testModels, testConfigs...)
 
@@ -212,8 +197,7 @@ func TestReplayRepository(t *testing.T) {
 
 	t.Run("GetJobByIDAndStatus", func(t *testing.T) {
 		t.Run("should return list of job specs given job_id and list of status", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := setupTestDB(t)
 			var testModels []*models.ReplaySpec
 			testModels = append(testModels, testConfigs...)
 
@@ -263,4 +247,66 @@ func TestReplayRepository(t *testing.T) {
 			assert.Equal(t, jobConfigs[2].ID, replays[0].Job.ID)
 		})
 	})
+	t.Run("GetByParentID", func(t *testing.T) {
+		t.Run("should return replays of a replay plan", func(t *testing.T) {
+			db := setupTestDB(t)
+
+			execUnit1 := new(mock.BasePlugin)
+			defer execUnit1.AssertExpectations(t)
+			execUnit1.On("PluginInfo").Return(&models.PluginInfoResponse{
+				Name: gTask,
+			}, nil)
+			depMod1 := new(mock.DependencyResolverMod)
+			defer depMod1.AssertExpectations(t)
+			var jobs []models.JobSpec
+			for _, jobConfig := range jobConfigs {
+				jobConfig.Task = models.JobSpecTask{Unit: &models.Plugin{Base: execUnit1, DependencyMod: depMod1}}
+				jobs = append(jobs, jobConfig)
+			}
+
+			pluginRepo := new(mock.SupportedPluginRepo)
+			defer pluginRepo.AssertExpectations(t)
+			pluginRepo.On("GetByName", gTask).Return(&models.Plugin{Base: execUnit1, DependencyMod: depMod1}, nil)
+			adapter := NewAdapter(pluginRepo)
+
+			unitData := models.GenerateDestinationRequest{
+				Config: models.PluginConfigs{}.FromJobSpec(jobConfigs[0].Task.Config),
+				Assets: models.PluginAssets{}.FromJobSpec(jobConfigs[0].Assets),
+			}
+			depMod1.On("GenerateDestination", context.TODO(), unitData).Return(&models.GenerateDestinationResponse{Destination: "p.d.t"}, nil)
+
+			projectJobSpecRepo := NewProjectJobSpecRepository(db, projectSpec, adapter)
+			jobRepo := NewJobSpecRepository(db, namespaceSpec, projectJobSpecRepo, adapter)
+			for _, jobSpec := range jobs {
+				assert.Nil(t, jobRepo.Insert(jobSpec))
+			}
+
+			parentID := uuid.Must(uuid.NewRandom())
+			repo := NewReplayRepository(db, models.JobSpec{}, adapter)
+			for idx, jobSpec := range jobs {
+				replay := &models.ReplaySpec{
+					ID:        uuid.Must(uuid.NewRandom()),
+					Job:       jobSpec,
+					StartDate: startTime,
+					EndDate:   endTime,
+					Status:    models.ReplayStatusAccepted,
+				}
+				if idx < 2 {
+					replay.ParentID = parentID
+				}
+				assert.Nil(t, repo.Insert(replay))
+			}
+
+			replays, err := repo.GetByParentID(parentID)
+			assert.Nil(t, err)
+			assert.Len(t, replays, 2)
+			for _, replay := range replays {
+				assert.Equal(t, parentID, replay.ParentID)
+			}
+
+			replays, err = repo.GetByJobIDAndStatus(jobs[2].ID, []string{models.ReplayStatusAccepted})
+			assert.Nil(t, err)
+			assert.Equal(t, uuid.Nil, replays[0].ParentID)
+		})
+	})
 }
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"github.com/pkg/errors"
+)
+
+type ReplicationState struct {
+	ProjectName   string `gorm:"primary_key"`
+	Key           string `gorm:"primary_key"`
+	Checksum      string `gorm:"not null"`
+	LocalChecksum string `gorm:"not null"`
+
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (s ReplicationState) FromSpec(projectName string, spec models.ReplicationState) ReplicationState {
+	return ReplicationState{
+		ProjectName:   projectName,
+		Key:           spec.Key,
+		Checksum:      spec.Checksum,
+		LocalChecksum: spec.LocalChecksum,
+		UpdatedAt:     spec.UpdatedAt.UTC(),
+	}
+}
+
+func (s ReplicationState) ToSpec() models.ReplicationState {
+	return models.ReplicationState{
+		Key:           s.Key,
+		Checksum:      s.Checksum,
+		LocalChecksum: s.LocalChecksum,
+		UpdatedAt:     s.UpdatedAt,
+	}
+}
+
+type replicationStateRepository struct {
+	db *gorm.DB
+}
+
+// Save records the state of a record, replacing its earlier state
+func (repo *replicationStateRepository) Save(projectName string, state models.ReplicationState) error {
+	if projectName == "" || state.Key == "" {
+		return errors.New("project name and key cannot be empty")
+	}
+	if state.UpdatedAt.IsZero() {
+		state.UpdatedAt = time.Now()
+	}
+	s := ReplicationState{}.FromSpec(projectName, state)
+	return repo.db.Save(&s).Error
+}
+
+func (repo *replicationStateRepository) GetAll(projectName string) ([]models.ReplicationState, error) {
+	var states []ReplicationState
+	if err := repo.db.Where("project_name = ?", projectName).Order("key").Find(&states).Error; err != nil {
+		return nil, err
+	}
+	var specs []models.ReplicationState
+	for _, s := range states {
+		specs = append(specs, s.ToSpec())
+	}
+	return specs, nil
+}
+
+func (repo *replicationStateRepository) Delete(projectName, key string) error {
+	return repo.db.Where("project_name = ? AND key = ?", projectName, key).Delete(&ReplicationState{}).Error
+}
+
+func NewReplicationStateRepository(db *gorm.DB) *replicationStateRepository {
+	return &replicationStateRepository{
+		db: withRepository(db, "replication_state"),
+	}
+}
@@ -0,0 +1,45 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicationStateRepository(t *testing.T) {
+	t.Parallel()
+	t.Run("Save, GetAll and Delete", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := NewReplicationStateRepository(db)
+
+		states, err := repo.GetAll("t-optimus")
+		assert.Nil(t, err)
+		assert.Empty(t, states)
+
+		err = repo.Save("t-optimus", models.ReplicationState{Key: "job/team-a/job-a", Checksum: "a1", LocalChecksum: "a1"})
+		assert.Nil(t, err)
+		err = repo.Save("t-optimus", models.ReplicationState{Key: "project", Checksum: "p1", LocalChecksum: "p1"})
+		assert.Nil(t, err)
+		err = repo.Save("t-optimus", models.ReplicationState{Key: "job/team-a/job-a", Checksum: "a2", LocalChecksum: "a3"})
+		assert.Nil(t, err)
+		err = repo.Save("other-project", models.ReplicationState{Key: "project", Checksum: "o1", LocalChecksum: "o1"})
+		assert.Nil(t, err)
+
+		states, err = repo.GetAll("t-optimus")
+		assert.Nil(t, err)
+		assert.Len(t, states, 2)
+		assert.Equal(t, "job/team-a/job-a", states[0].Key)
+		assert.Equal(t, "a2", states[0].Checksum)
+		assert.Equal(t, "a3", states[0].LocalChecksum)
+		assert.Equal(t, "project", states[1].Key)
+
+		err = repo.Delete("t-optimus", "job/team-a/job-a")
+		assert.Nil(t, err)
+		states, err = repo.GetAll("t-optimus")
+		assert.Nil(t, err)
+		assert.Len(t, states, 1)
+	})
+}
@@ -121,7 +121,8 @@ func (r Resource) ToSpec(ds models.Datastorer) (models.ResourceSpec, error) {
 		return models.ResourceSpec{}, err
 	}
 
-	return models.ResourceSpec{
+	// specs stored before a change of their format are upgraded as read
+	return models.SpecMigrations.MigrateResource(models.ResourceSpec{
 		ID:        r.ID,
 		Version:   r.Version,
 		Name:      r.Name,
@@ -130,7 +131,7 @@ func (r Resource) ToSpec(ds models.Datastorer) (models.ResourceSpec, error) {
 		Spec:      deserializedSpec.Spec,
 		Assets:    assets,
 		Labels:    labels,
-	}, nil
+	})
 }
 
 type projectResourceSpecRepository struct {
@@ -179,7 +180,7 @@ func (repo *projectResourceSpecRepository) GetAll() ([]models.ResourceSpec, erro
 
 func NewProjectResourceSpecRepository(db *gorm.DB, project models.ProjectSpec, ds models.Datastorer) *projectResourceSpecRepository {
 	return &projectResourceSpecRepository{
-		db:        db,
+		db:        withRepository(db, "project_resource_spec"),
 		project:   project,
 		datastore: ds,
 	}
@@ -276,7 +277,7 @@ func (repo *resourceSpecRepository) HardDelete(name string) error {
 
 func NewResourceSpecRepository(db *gorm.DB, namespace models.NamespaceSpec, ds models.Datastorer, projectResourceSpecRepo store.ProjectResourceSpecRepository) *resourceSpecRepository {
 	return &resourceSpecRepository{
-		db:                      db,
+		db:                      withRepository(db, "resource_spec"),
 		namespace:               namespace,
 		datastore:               ds,
 		projectResourceSpecRepo: projectResourceSpecRepo,
@@ -3,7 +3,6 @@
 package postgres
 
 import (
-	"os"
 	"testing"
 
 	"github.com/odpf/optimus/mock"
@@ -15,6 +14,7 @@ import (
 )
 
 func TestResourceSpecRepository(t *testing.T) {
+	t.Parallel()
 	projectSpec := models.ProjectSpec{
 		ID:   uuid.Must(uuid.NewRandom()),
 		Name: "t-optimus-project",
@@ -37,26 +37,8 @@ func TestResourceSpecRepository(t *testing.T) {
 	datastorer.On("Types").Return(dsController)
 	datastorer.On("Name").Return("DS")
 
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-
+	DBSetup := func(t *testing.T) *gorm.DB {
+		dbConn := setupTestDB(t)
 		projRepo := NewProjectRepository(dbConn, hash)
 		assert.Nil(t, projRepo.Save(projectSpec))
 		return dbConn
@@ -114,8 +96,7 @@ func TestResourceSpecRepository(t *testing.T) {
 	dsTypeTableAdapter.On("FromYaml", []byte("some binary data X")).Return(testConfigs[2], nil)
 
 	t.Run("Insert", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 		testModels := []models.ResourceSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -135,8 +116,7 @@ func TestResourceSpecRepository(t *testing.T) {
 
 	t.Run("Upsert", func(t *testing.T) {
 		t.Run("insert different resource should insert two", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := DBSetup(t)
 			testModelA := testConfigs[0]
 			testModelB := testConfigs[2]
 
@@ -161,8 +141,7 @@ func TestResourceSpecRepository(t *testing.T) {
 			assert.Equal(t, "table", checkModel.Type.String())
 		})
 		t.Run("insert same resource twice should overwrite existing", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := DBSetup(t)
 			testModelA := testConfigs[2]
 
 			projectResourceSpecRepo := NewProjectResourceSpecRepository(db, projectSpec, datastorer)
@@ -189,8 +168,7 @@ func TestResourceSpecRepository(t *testing.T) {
 			assert.Equal(t, 6, checkModel.Version)
 		})
 		t.Run("upsert without ID should auto generate it", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := DBSetup(t)
 			resourceSpecWithEmptyUUID := testConfigWithoutAssets[0]
 			resourceSpecWithEmptyUUID.ID = uuid.Nil
 
@@ -222,8 +200,7 @@ func TestResourceSpecRepository(t *testing.T) {
 			assert.Equal(t, "proj.datas.test", checkModel.Name)
 		})
 		t.Run("should fail if resource is already registered for a project with different namespace", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := DBSetup(t)
 			testModelA := testConfigs[2]
 
 			projectResourceSpecRepo := NewProjectResourceSpecRepository(db, projectSpec, datastorer)
@@ -248,8 +225,7 @@ func TestResourceSpecRepository(t *testing.T) {
 	})
 
 	t.Run("GetByName", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 		testModels := []models.ResourceSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -266,6 +242,7 @@ func TestResourceSpecRepository(t *testing.T) {
 }
 
 func TestProjectResourceSpecRepository(t *testing.T) {
+	t.Parallel()
 	projectSpec := models.ProjectSpec{
 		ID:   uuid.Must(uuid.NewRandom()),
 		Name: "t-optimus-project",
@@ -288,26 +265,8 @@ func TestProjectResourceSpecRepository(t *testing.T) {
 	datastorer.On("Types").Return(dsController)
 	datastorer.On("Name").Return("DS")
 
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-
+	DBSetup := func(t *testing.T) *gorm.DB {
+		dbConn := setupTestDB(t)
 		projRepo := NewProjectRepository(dbConn, hash)
 		assert.Nil(t, projRepo.Save(projectSpec))
 		return dbConn
@@ -359,8 +318,7 @@ func TestProjectResourceSpecRepository(t *testing.T) {
 	dsTypeTableAdapter.On("FromYaml", []byte("some binary data X")).Return(testConfigs[2], nil)
 
 	t.Run("GetByName", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 		testModels := []models.ResourceSpec{}
 		testModels = append(testModels, testConfigs...)
 
@@ -383,8 +341,7 @@ func TestProjectResourceSpecRepository(t *testing.T) {
 	})
 
 	t.Run("GetAll", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 		testModels := []models.ResourceSpec{}
 		testModels = append(testModels, testConfigs...)
 
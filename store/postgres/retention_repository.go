@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+)
+
+const (
+	deleteInstancesSQL = `DELETE FROM instance WHERE id IN (
+	SELECT instance.id FROM instance JOIN job ON job.id = instance.job_id
+	WHERE job.project_id = ? AND instance.scheduled_at < ? LIMIT ?)`
+
+	deleteReplaysSQL = `DELETE FROM replay WHERE id IN (
+	SELECT replay.id FROM replay JOIN job ON job.id = replay.job_id
+	WHERE job.project_id = ? AND replay.created_at < ? AND replay.status IN (?) LIMIT ?)`
+
+	deleteAuditLogsSQL = `DELETE FROM audit_log WHERE id IN (
+	SELECT id FROM audit_log WHERE project_name = ? AND created_at < ? LIMIT ?)`
+
+	// runs of the batch of jobs are deleted in the same statement, as they
+	// refer the jobs
+	deleteJobsSQL = `WITH purged AS (SELECT id FROM job WHERE project_id = ? LIMIT ?),
+	runs AS (DELETE FROM instance WHERE job_id IN (SELECT id FROM purged))
+	DELETE FROM job WHERE id IN (SELECT id FROM purged)`
+)
+
+// retentionRepository deletes expired rows for good, soft deleted rows
+// included, rows of deleted jobs are reclaimed too
+type retentionRepository struct {
+	db    *gorm.DB
+	cache *SpecCache
+}
+
+func (repo *retentionRepository) DeleteInstances(project models.ProjectSpec, before time.Time, limit int) (int64, error) {
+	res := repo.db.Exec(deleteInstancesSQL, project.ID, before, limit)
+	return res.RowsAffected, res.Error
+}
+
+func (repo *retentionRepository) DeleteReplays(project models.ProjectSpec, before time.Time, limit int) (int64, error) {
+	res := repo.db.Exec(deleteReplaysSQL, project.ID, before,
+		[]string{models.ReplayStatusSuccess, models.ReplayStatusFailed, models.ReplayStatusCancelled}, limit)
+	return res.RowsAffected, res.Error
+}
+
+func (repo *retentionRepository) DeleteAuditLogs(project models.ProjectSpec, before time.Time, limit int) (int64, error) {
+	res := repo.db.Exec(deleteAuditLogsSQL, project.Name, before, limit)
+	return res.RowsAffected, res.Error
+}
+
+func (repo *retentionRepository) DeleteJobs(project models.ProjectSpec, limit int) (int64, error) {
+	defer repo.cache.InvalidateJobs(project.ID)
+	res := repo.db.Exec(deleteJobsSQL, project.ID, limit)
+	return res.RowsAffected, res.Error
+}
+
+func NewRetentionRepository(db *gorm.DB) *retentionRepository {
+	return &retentionRepository{
+		db:    withRepository(db, "retention"),
+		cache: specCache(db),
+	}
+}
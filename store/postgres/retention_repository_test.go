@@ -0,0 +1,56 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionRepository(t *testing.T) {
+	t.Parallel()
+	projectSpec := models.ProjectSpec{ID: uuid.Must(uuid.NewRandom()), Name: "t-optimus-id"}
+	cutoff := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("DeleteAuditLogs", func(t *testing.T) {
+		t.Run("should delete expired audit logs of project in batches", func(t *testing.T) {
+			db := setupTestDB(t)
+			auditRepo := NewAuditLogRepository(db)
+			for _, entry := range []struct {
+				project   string
+				createdAt time.Time
+			}{
+				{projectSpec.Name, cutoff.AddDate(0, 0, -3)},
+				{projectSpec.Name, cutoff.AddDate(0, 0, -2)},
+				{projectSpec.Name, cutoff.AddDate(0, 0, -1)},
+				{projectSpec.Name, cutoff.AddDate(0, 0, 1)},
+				{"another-project", cutoff.AddDate(0, 0, -1)},
+			} {
+				assert.Nil(t, auditRepo.Insert(&models.AuditEntry{
+					ID:          uuid.Must(uuid.NewRandom()),
+					Actor:       "optimus@example.com",
+					RPC:         "/odpf.optimus.RuntimeService/DeployJobSpecification",
+					ProjectName: entry.project,
+					Outcome:     "OK",
+					CreatedAt:   entry.createdAt,
+				}))
+			}
+
+			repo := NewRetentionRepository(db)
+			deleted, err := repo.DeleteAuditLogs(projectSpec, cutoff, 2)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(2), deleted)
+			deleted, err = repo.DeleteAuditLogs(projectSpec, cutoff, 2)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), deleted)
+
+			remaining, err := auditRepo.List(models.AuditFilter{})
+			assert.Nil(t, err)
+			assert.Equal(t, 2, len(remaining))
+		})
+	})
+}
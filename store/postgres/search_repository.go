@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/models"
+	"gorm.io/datatypes"
+)
+
+const (
+	// searchable text of jobs and resources, same as the expressions their
+	// trigram indexes are created on so the indexes are used
+	jobSearchText      = "lower(job.name || ' ' || coalesce(job.owner, '') || ' ' || coalesce(job.destination, '') || ' ' || coalesce(job.task_name, ''))"
+	resourceSearchText = "lower(resource.name || ' ' || resource.datastore || ' ' || resource.type)"
+
+	defaultSearchLimit = 50
+)
+
+// jobSearchRow is a job found by a search along with names of its project
+// and namespace
+type jobSearchRow struct {
+	Name          string
+	ProjectName   string
+	NamespaceName string
+	Owner         string
+	Description   string
+	Destination   string
+	TaskName      string
+	Labels        datatypes.JSON
+	UpdatedAt     time.Time
+}
+
+type resourceSearchRow struct {
+	Name          string
+	ProjectName   string
+	NamespaceName string
+	Datastore     string
+	Type          string
+	Labels        datatypes.JSON
+	UpdatedAt     time.Time
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func (repo *searchRepository) SearchJobs(query models.SearchQuery) ([]models.JobSearchResult, error) {
+	db := repo.db.Table("job").
+		Select("job.name, project.name AS project_name, namespace.name AS namespace_name, " +
+			"coalesce(job.owner, '') AS owner, coalesce(job.description, '') AS description, " +
+			"coalesce(job.destination, '') AS destination, coalesce(job.task_name, '') AS task_name, job.labels, job.updated_at").
+		Joins("JOIN project ON project.id = job.project_id").
+		Joins("JOIN namespace ON namespace.id = job.namespace_id").
+		Where("job.deleted_at IS NULL")
+	db = searchFilters(db, query, jobSearchText, "job.labels")
+	if query.Owner != "" {
+		db = db.Where("job.owner = ?", query.Owner)
+	}
+	if query.Task != "" {
+		db = db.Where("job.task_name = ?", query.Task)
+	}
+
+	var rows []jobSearchRow
+	if err := db.Order("project.name, job.name").Limit(searchLimit(query)).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := []models.JobSearchResult{}
+	for _, row := range rows {
+		results = append(results, models.JobSearchResult{
+			Name:        row.Name,
+			Project:     row.ProjectName,
+			Namespace:   row.NamespaceName,
+			Owner:       row.Owner,
+			Description: row.Description,
+			Destination: row.Destination,
+			Task:        row.TaskName,
+			Labels:      searchLabels(row.Labels),
+			UpdatedAt:   row.UpdatedAt,
+		})
+	}
+	return results, nil
+}
+
+func (repo *searchRepository) SearchResources(query models.SearchQuery) ([]models.ResourceSearchResult, error) {
+	db := repo.db.Table("resource").
+		Select("resource.name, project.name AS project_name, namespace.name AS namespace_name, resource.datastore, " +
+			"resource.type, resource.labels, resource.updated_at").
+		Joins("JOIN project ON project.id = resource.project_id").
+		Joins("JOIN namespace ON namespace.id = resource.namespace_id").
+		Where("resource.deleted_at IS NULL")
+	db = searchFilters(db, query, resourceSearchText, "resource.labels")
+	if query.Datastore != "" {
+		db = db.Where("resource.datastore = ?", query.Datastore)
+	}
+	if query.Type != "" {
+		db = db.Where("resource.type = ?", query.Type)
+	}
+
+	var rows []resourceSearchRow
+	if err := db.Order("project.name, resource.name").Limit(searchLimit(query)).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := []models.ResourceSearchResult{}
+	for _, row := range rows {
+		results = append(results, models.ResourceSearchResult{
+			Name:      row.Name,
+			Project:   row.ProjectName,
+			Namespace: row.NamespaceName,
+			Datastore: row.Datastore,
+			Type:      row.Type,
+			Labels:    searchLabels(row.Labels),
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+	return results, nil
+}
+
+// searchFilters applies filters common to jobs and resources, every term of
+// text has to be part of the searchable text
+func searchFilters(db *gorm.DB, query models.SearchQuery, text, labelsColumn string) *gorm.DB {
+	for _, term := range strings.Fields(strings.ToLower(query.Text)) {
+		db = db.Where(text+" LIKE ?", "%"+escapeLike(term)+"%")
+	}
+	if len(query.Labels) > 0 {
+		labels, _ := json.Marshal(query.Labels)
+		db = db.Where(labelsColumn+" @> ?::jsonb", string(labels))
+	}
+	if query.Project != "" {
+		db = db.Where("project.name = ?", query.Project)
+	}
+	return db
+}
+
+func searchLimit(query models.SearchQuery) int {
+	switch {
+	case query.Limit <= 0:
+		return defaultSearchLimit
+	case query.Limit > models.MaxSearchLimit:
+		return models.MaxSearchLimit
+	}
+	return query.Limit
+}
+
+func searchLabels(raw datatypes.JSON) map[string]string {
+	labels := map[string]string{}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &labels)
+	}
+	return labels
+}
+
+// escapeLike escapes wildcards of like patterns in term, so they are matched
+// as they are
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+func NewSearchRepository(db *gorm.DB) *searchRepository {
+	return &searchRepository{
+		db: withRepository(db, "search"),
+	}
+}
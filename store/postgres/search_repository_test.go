@@ -0,0 +1,145 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	"github.com/odpf/optimus/mock"
+	"github.com/odpf/optimus/models"
+	"github.com/stretchr/testify/assert"
+	mock2 "github.com/stretchr/testify/mock"
+)
+
+func TestSearchRepository(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	shopProject := models.ProjectSpec{ID: uuid.Must(uuid.NewRandom()), Name: "shop"}
+	shopNamespace := models.NamespaceSpec{ID: uuid.Must(uuid.NewRandom()), Name: "finance", ProjectSpec: shopProject}
+	adsProject := models.ProjectSpec{ID: uuid.Must(uuid.NewRandom()), Name: "ads"}
+	adsNamespace := models.NamespaceSpec{ID: uuid.Must(uuid.NewRandom()), Name: "campaigns", ProjectSpec: adsProject}
+
+	gTask := "bq2bq"
+	execUnit := new(mock.BasePlugin)
+	execUnit.On("PluginInfo").Return(&models.PluginInfoResponse{Name: gTask}, nil)
+	pluginRepo := new(mock.SupportedPluginRepo)
+	pluginRepo.On("GetByName", gTask).Return(&models.Plugin{Base: execUnit}, nil)
+	adapter := NewAdapter(pluginRepo)
+	dependencyMod := new(mock.DependencyResolverMod)
+
+	dsTypeTableAdapter := new(mock.DatastoreTypeAdapter)
+	dsTypeTableAdapter.On("ToYaml", mock2.Anything).Return([]byte("spec"), nil)
+	dsTypeTableController := new(mock.DatastoreTypeController)
+	dsTypeTableController.On("Adapter").Return(dsTypeTableAdapter)
+	datastorer := new(mock.Datastorer)
+	datastorer.On("Types").Return(map[models.ResourceType]models.DatastoreTypeController{
+		models.ResourceTypeTable: dsTypeTableController,
+	})
+	datastorer.On("Name").Return("bigquery")
+
+	newJob := func(name, owner, destination string, labels map[string]string) models.JobSpec {
+		spec := models.JobSpec{
+			ID:     uuid.Must(uuid.NewRandom()),
+			Name:   name,
+			Owner:  owner,
+			Labels: labels,
+			Task: models.JobSpecTask{
+				Unit:   &models.Plugin{Base: execUnit, DependencyMod: dependencyMod},
+				Config: models.JobSpecConfigs{{Name: "TABLE", Value: name}},
+			},
+		}
+		dependencyMod.On("GenerateDestination", context.TODO(), models.GenerateDestinationRequest{
+			Config: models.PluginConfigs{}.FromJobSpec(spec.Task.Config),
+			Assets: models.PluginAssets{}.FromJobSpec(spec.Assets),
+		}).Return(&models.GenerateDestinationResponse{Destination: destination}, nil)
+		return spec
+	}
+	revenue := newJob("daily_revenue", "finance@example.com", "shop-project:finance.daily_revenue",
+		map[string]string{"team": "finance", "tier": "1"})
+	orders := newJob("orders_clean", "data@example.com", "shop-project:raw_100.orders",
+		map[string]string{"team": "data"})
+	spend := newJob("campaign_spend", "ads@example.com", "ads-project:finance.spend",
+		map[string]string{"team": "finance"})
+
+	DBSetup := func(t *testing.T) *gorm.DB {
+		db := setupTestDB(t)
+		for _, ns := range []models.NamespaceSpec{shopNamespace, adsNamespace} {
+			assert.Nil(t, NewProjectRepository(db, hash).Save(ns.ProjectSpec))
+			assert.Nil(t, NewNamespaceRepository(db, ns.ProjectSpec, hash).Save(ns))
+		}
+		shopJobs := NewJobSpecRepository(db, shopNamespace, NewProjectJobSpecRepository(db, shopProject, adapter), adapter)
+		assert.Nil(t, shopJobs.Save(revenue))
+		assert.Nil(t, shopJobs.Save(orders))
+		adsJobs := NewJobSpecRepository(db, adsNamespace, NewProjectJobSpecRepository(db, adsProject, adapter), adapter)
+		assert.Nil(t, adsJobs.Save(spend))
+		shopResources := NewResourceSpecRepository(db, shopNamespace, datastorer, NewProjectResourceSpecRepository(db, shopProject, datastorer))
+		assert.Nil(t, shopResources.Save(models.ResourceSpec{
+			ID:        uuid.Must(uuid.NewRandom()),
+			Version:   1,
+			Name:      "shop-project.finance.daily_revenue",
+			Type:      models.ResourceTypeTable,
+			Datastore: datastorer,
+			Labels:    map[string]string{"team": "finance"},
+		}))
+		return db
+	}
+
+	names := func(results []models.JobSearchResult) []string {
+		var found []string
+		for _, result := range results {
+			found = append(found, result.Project+"/"+result.Name)
+		}
+		return found
+	}
+
+	t.Run("should find jobs across projects by terms of destination", func(t *testing.T) {
+		repo := NewSearchRepository(DBSetup(t))
+		results, err := repo.SearchJobs(models.SearchQuery{Text: "FINANCE."})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ads/campaign_spend", "shop/daily_revenue"}, names(results))
+		assert.Equal(t, "finance", results[1].Namespace)
+		assert.Equal(t, "shop-project:finance.daily_revenue", results[1].Destination)
+		assert.Equal(t, map[string]string{"team": "finance", "tier": "1"}, results[1].Labels)
+	})
+	t.Run("should match every term and filters", func(t *testing.T) {
+		repo := NewSearchRepository(DBSetup(t))
+		results, err := repo.SearchJobs(models.SearchQuery{Text: "finance bq2bq", Project: "shop"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"shop/daily_revenue"}, names(results))
+
+		results, err = repo.SearchJobs(models.SearchQuery{Labels: map[string]string{"team": "finance"}, Owner: "ads@example.com"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"ads/campaign_spend"}, names(results))
+	})
+	t.Run("should match wildcards of like as they are", func(t *testing.T) {
+		repo := NewSearchRepository(DBSetup(t))
+		results, err := repo.SearchJobs(models.SearchQuery{Text: "raw_1"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"shop/orders_clean"}, names(results))
+
+		results, err = repo.SearchJobs(models.SearchQuery{Text: "%"})
+		assert.Nil(t, err)
+		assert.Empty(t, results)
+	})
+	t.Run("should find resources by terms and labels", func(t *testing.T) {
+		repo := NewSearchRepository(DBSetup(t))
+		results, err := repo.SearchResources(models.SearchQuery{Text: "finance.daily", Labels: map[string]string{"team": "finance"}})
+		assert.Nil(t, err)
+		assert.Equal(t, []models.ResourceSearchResult{{
+			Name:      "shop-project.finance.daily_revenue",
+			Project:   "shop",
+			Namespace: "finance",
+			Datastore: "bigquery",
+			Type:      "table",
+			Labels:    map[string]string{"team": "finance"},
+			UpdatedAt: results[0].UpdatedAt,
+		}}, results)
+
+		results, err = repo.SearchResources(models.SearchQuery{Text: "finance", Type: "view"})
+		assert.Nil(t, err)
+		assert.Empty(t, results)
+	})
+}
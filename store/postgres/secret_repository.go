@@ -69,9 +69,12 @@ type secretRepository struct {
 	project models.ProjectSpec
 
 	hash models.ApplicationKey
+	// secrets are read along with their project
+	cache *SpecCache
 }
 
 func (repo *secretRepository) Insert(resource models.ProjectSecretItem) error {
+	defer repo.cache.InvalidateProject(repo.project.Name)
 	p, err := Secret{}.FromSpec(resource, repo.project, repo.hash)
 	if err != nil {
 		return err
@@ -83,6 +86,7 @@ func (repo *secretRepository) Insert(resource models.ProjectSecretItem) error {
 }
 
 func (repo *secretRepository) Save(spec models.ProjectSecretItem) error {
+	defer repo.cache.InvalidateProject(repo.project.Name)
 	existingResource, err := repo.GetByName(spec.Name)
 	if errors.Is(err, store.ErrResourceNotFound) {
 		return repo.Insert(spec)
@@ -139,8 +143,9 @@ func (repo *secretRepository) GetAll() ([]models.ProjectSecretItem, error) {
 
 func NewSecretRepository(db *gorm.DB, project models.ProjectSpec, hash models.ApplicationKey) *secretRepository {
 	return &secretRepository{
-		db:      db,
+		db:      withRepository(db, "secret"),
 		project: project,
 		hash:    hash,
+		cache:   specCache(db),
 	}
 }
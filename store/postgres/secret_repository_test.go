@@ -3,7 +3,6 @@
 package postgres
 
 import (
-	"os"
 	"testing"
 
 	"github.com/google/uuid"
@@ -13,6 +12,7 @@ import (
 )
 
 func TestSecretRepository(t *testing.T) {
+	t.Parallel()
 	projectSpec := models.ProjectSpec{
 		ID:   uuid.Must(uuid.NewRandom()),
 		Name: "t-optimus-project",
@@ -22,26 +22,8 @@ func TestSecretRepository(t *testing.T) {
 	}
 	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
 
-	DBSetup := func() *gorm.DB {
-		dbURL, ok := os.LookupEnv("TEST_OPTIMUS_DB_URL")
-		if !ok {
-			panic("unable to find TEST_OPTIMUS_DB_URL env var")
-		}
-		dbConn, err := Connect(dbURL, 1, 1)
-		if err != nil {
-			panic(err)
-		}
-		m, err := NewHTTPFSMigrator(dbURL)
-		if err != nil {
-			panic(err)
-		}
-		if err := m.Drop(); err != nil {
-			panic(err)
-		}
-		if err := Migrate(dbURL); err != nil {
-			panic(err)
-		}
-
+	DBSetup := func(t *testing.T) *gorm.DB {
+		dbConn := setupTestDB(t)
 		projRepo := NewProjectRepository(dbConn, hash)
 		assert.Nil(t, projRepo.Save(projectSpec))
 		return dbConn
@@ -64,8 +46,7 @@ func TestSecretRepository(t *testing.T) {
 	}
 
 	t.Run("Insert", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 		testModels := []models.ProjectSecretItem{}
 		testModels = append(testModels, testConfigs...)
 
@@ -83,8 +64,7 @@ func TestSecretRepository(t *testing.T) {
 	})
 	t.Run("Upsert", func(t *testing.T) {
 		t.Run("insert different resource should insert two", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := DBSetup(t)
 			testModelA := testConfigs[0]
 			testModelB := testConfigs[2]
 
@@ -108,8 +88,7 @@ func TestSecretRepository(t *testing.T) {
 			assert.Equal(t, "super-secret", checkModel.Value)
 		})
 		t.Run("insert same resource twice should overwrite existing", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := DBSetup(t)
 			testModelA := testConfigs[2]
 
 			repo := NewSecretRepository(db, projectSpec, hash)
@@ -133,8 +112,7 @@ func TestSecretRepository(t *testing.T) {
 			assert.Equal(t, "gs://another_folder", checkModel.Value)
 		})
 		t.Run("upsert without ID should auto generate it", func(t *testing.T) {
-			db := DBSetup()
-			defer db.Close()
+			db := DBSetup(t)
 			testModelA := testConfigs[0]
 			testModelA.ID = uuid.Nil
 
@@ -150,8 +128,7 @@ func TestSecretRepository(t *testing.T) {
 		})
 	})
 	t.Run("GetByName", func(t *testing.T) {
-		db := DBSetup()
-		defer db.Close()
+		db := DBSetup(t)
 		testModels := []models.ProjectSecretItem{}
 		testModels = append(testModels, testConfigs...)
 
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// specCacheSetting attaches a SpecCache to connections of repositories
+	specCacheSetting = "optimus:spec_cache"
+
+	// caches looked up, as told to CacheObserver
+	SpecCacheProject = "project"
+	SpecCacheJobSpec = "job_spec"
+
+	// jobs of a destination may be of any project, they are kept apart from
+	// jobs of projects and dropped on every write to jobs
+	specCacheDestination = "destination"
+)
+
+// CacheObserver is told of every lookup in a cache and whether it was a hit
+type CacheObserver func(cache string, hit bool)
+
+type specCacheKey struct {
+	cache string
+	// name of a project in cache of projects, id of the project otherwise
+	project string
+	key     string
+}
+
+type specCacheItem struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// SpecCache keeps rows of projects and job specifications read on hot paths,
+// e.g. dependency resolution and compilation of instances, for a while. Rows
+// are kept rather than specs so that every read adapts specs of its own which
+// callers are free to modify. Writes through repositories of the server drop
+// rows they affect, writes of other replicas are seen once rows expire
+type SpecCache struct {
+	ttl      time.Duration
+	observer CacheObserver
+
+	mu    sync.Mutex
+	items map[specCacheKey]specCacheItem
+
+	now func() time.Time
+}
+
+func (c *SpecCache) get(key specCacheKey) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	item, ok := c.items[key]
+	hit := ok && c.now().Before(item.expiresAt)
+	if ok && !hit {
+		delete(c.items, key)
+	}
+	c.mu.Unlock()
+
+	if c.observer != nil {
+		cache := key.cache
+		if cache == specCacheDestination {
+			cache = SpecCacheJobSpec
+		}
+		c.observer(cache, hit)
+	}
+	if !hit {
+		return nil, false
+	}
+	return item.value, true
+}
+
+func (c *SpecCache) set(key specCacheKey, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = specCacheItem{
+		value:     value,
+		expiresAt: c.now().Add(c.ttl),
+	}
+}
+
+// InvalidateProject drops the project along with the list of all projects
+func (c *SpecCache) InvalidateProject(name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.items {
+		if key.cache == SpecCacheProject && (key.project == name || key.project == "") {
+			delete(c.items, key)
+		}
+	}
+}
+
+// InvalidateJobs drops job specifications of the project, and jobs of
+// destinations as they may be of the project
+func (c *SpecCache) InvalidateJobs(projectID uuid.UUID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.items {
+		if (key.cache == SpecCacheJobSpec && key.project == projectID.String()) || key.cache == specCacheDestination {
+			delete(c.items, key)
+		}
+	}
+}
+
+// WithSpecCache returns db whose repositories read projects and job
+// specifications through cache
+func WithSpecCache(db *gorm.DB, cache *SpecCache) *gorm.DB {
+	return db.Set(specCacheSetting, cache)
+}
+
+// specCache of connection of a repository, nil if it has none, which
+// caches nothing
+func specCache(db *gorm.DB) *SpecCache {
+	if cache, ok := db.Get(specCacheSetting); ok {
+		return cache.(*SpecCache)
+	}
+	return nil
+}
+
+// NewSpecCache keeps rows read for ttl, observer is told of lookups
+func NewSpecCache(ttl time.Duration, observer CacheObserver) *SpecCache {
+	return &SpecCache{
+		ttl:      ttl,
+		observer: observer,
+		items:    map[specCacheKey]specCacheItem{},
+		now:      time.Now,
+	}
+}
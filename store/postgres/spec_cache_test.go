@@ -0,0 +1,115 @@
+// +build !unit_test
+
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/odpf/optimus/models"
+)
+
+func TestSpecCache(t *testing.T) {
+	t.Parallel()
+	hash, _ := models.NewApplicationSecret("32charshtesthashtesthashtesthash")
+	projectSpec := models.ProjectSpec{
+		ID:   uuid.Must(uuid.NewRandom()),
+		Name: "t-optimus",
+		Config: map[string]string{
+			"bucket": "gs://some_folder",
+		},
+	}
+
+	type lookup struct {
+		cache string
+		hit   bool
+	}
+	newCache := func(ttl time.Duration) (*SpecCache, *[]lookup) {
+		var lookups []lookup
+		return NewSpecCache(ttl, func(cache string, hit bool) {
+			lookups = append(lookups, lookup{cache: cache, hit: hit})
+		}), &lookups
+	}
+
+	t.Run("should read projects from cache until they are saved again", func(t *testing.T) {
+		db := setupTestDB(t)
+		cache, lookups := newCache(time.Minute)
+		repo := NewProjectRepository(WithSpecCache(db, cache), hash)
+		assert.Nil(t, repo.Save(projectSpec))
+
+		spec, err := repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		// specs read are of the caller, changing them doesn't change the cache
+		spec.Config["bucket"] = "gs://changed"
+		spec, err = repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, "gs://some_folder", spec.Config["bucket"])
+
+		// written around the cache, e.g. by another server
+		assert.Nil(t, NewProjectRepository(db, hash).Save(models.ProjectSpec{
+			Name:   projectSpec.Name,
+			Config: map[string]string{"bucket": "gs://other_folder"},
+		}))
+		spec, err = repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, "gs://some_folder", spec.Config["bucket"])
+
+		assert.Nil(t, repo.Save(models.ProjectSpec{
+			Name:   projectSpec.Name,
+			Config: map[string]string{"bucket": "gs://new_folder"},
+		}))
+		spec, err = repo.GetByName(projectSpec.Name)
+		assert.Nil(t, err)
+		assert.Equal(t, "gs://new_folder", spec.Config["bucket"])
+
+		assert.Equal(t, []lookup{
+			{cache: SpecCacheProject, hit: false},
+			{cache: SpecCacheProject, hit: false},
+			{cache: SpecCacheProject, hit: true},
+			{cache: SpecCacheProject, hit: true},
+			// looked up by save
+			{cache: SpecCacheProject, hit: true},
+			{cache: SpecCacheProject, hit: false},
+		}, *lookups)
+	})
+	t.Run("should drop jobs of project once they are written", func(t *testing.T) {
+		db := setupTestDB(t)
+		cache, _ := newCache(time.Minute)
+		cachedDB := WithSpecCache(db, cache)
+		assert.Nil(t, NewProjectRepository(cachedDB, hash).Save(projectSpec))
+		namespaceSpec := models.NamespaceSpec{
+			ID:          uuid.Must(uuid.NewRandom()),
+			Name:        "dev-team-1",
+			ProjectSpec: projectSpec,
+		}
+		assert.Nil(t, NewNamespaceRepository(cachedDB, projectSpec, hash).Insert(namespaceSpec))
+
+		adapter := NewAdapter(nil)
+		projectJobSpecRepo := NewProjectJobSpecRepository(cachedDB, projectSpec, adapter)
+		jobs, err := projectJobSpecRepo.GetAll()
+		assert.Nil(t, err)
+		assert.Len(t, jobs, 0)
+
+		err = NewJobSpecRepository(cachedDB, namespaceSpec, projectJobSpecRepo, adapter).Delete("missing-job")
+		assert.Nil(t, err)
+		_, ok := cache.get(specCacheKey{cache: SpecCacheJobSpec, project: projectSpec.ID.String(), key: "*"})
+		assert.False(t, ok)
+	})
+	t.Run("should expire rows after ttl", func(t *testing.T) {
+		cache, lookups := newCache(time.Minute)
+		now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		cache.now = func() time.Time { return now }
+		key := specCacheKey{cache: SpecCacheProject, project: projectSpec.Name}
+		cache.set(key, Project{Name: projectSpec.Name})
+
+		_, ok := cache.get(key)
+		assert.True(t, ok)
+		now = now.Add(time.Minute)
+		_, ok = cache.get(key)
+		assert.False(t, ok)
+		assert.Equal(t, []lookup{{cache: SpecCacheProject, hit: true}, {cache: SpecCacheProject, hit: false}}, *lookups)
+	})
+}
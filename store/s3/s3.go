@@ -0,0 +1,185 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/odpf/optimus/store"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRegion = "us-east-1"
+)
+
+// Credentials of s3 buckets, stored in project secret as json
+type Credentials struct {
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	SessionToken    string `json:"session_token"`
+	Region          string `json:"region"`
+
+	// Endpoint of s3 compatible storage like minio, buckets are addressed
+	// by path on it. Aws endpoint of the region is used if empty
+	Endpoint string `json:"endpoint"`
+}
+
+// ParseCredentials reads credentials from json secret
+func ParseCredentials(secret string) (Credentials, error) {
+	var creds Credentials
+	if err := json.Unmarshal([]byte(secret), &creds); err != nil {
+		return creds, errors.Wrap(err, "failed to parse s3 credentials")
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return creds, errors.New("access_key_id and secret_access_key are required in s3 credentials")
+	}
+	if creds.Region == "" {
+		creds.Region = defaultRegion
+	}
+	return creds, nil
+}
+
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// ObjectStore reads and writes objects of s3 buckets with the aws sdk
+type ObjectStore struct {
+	client *s3.Client
+}
+
+func (s *ObjectStore) NewWriter(ctx context.Context, bucket, path string) (io.WriteCloser, error) {
+	return &objectWriter{ctx: ctx, store: s, bucket: bucket, path: path}, nil
+}
+
+// objectWriter buffers the object and uploads it once closed, s3 needs
+// the size and hash of payload before it is sent
+type objectWriter struct {
+	bytes.Buffer
+
+	ctx    context.Context
+	store  *ObjectStore
+	bucket string
+	path   string
+}
+
+func (w *objectWriter) Close() error {
+	_, err := w.store.client.PutObject(w.ctx, &s3.PutObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(objectKey(w.path)),
+		Body:   bytes.NewReader(w.Bytes()),
+	})
+	return wrapError(err)
+}
+
+func (s *ObjectStore) NewObjectReader(ctx context.Context, bucket, path string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(objectKey(path)),
+	})
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return out.Body, nil
+}
+
+// Delete deletes the object, s3 doesn't report deleting objects which
+// don't exist so it is looked up first
+func (s *ObjectStore) Delete(ctx context.Context, bucket, path string) error {
+	if _, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(objectKey(path)),
+	}); err != nil {
+		return wrapError(err)
+	}
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(objectKey(path)),
+	})
+	return wrapError(err)
+}
+
+func (s *ObjectStore) List(ctx context.Context, bucket, prefix string) ([]string, error) {
+	objects, err := s.listObjects(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, obj := range objects {
+		paths = append(paths, aws.ToString(obj.Key))
+	}
+	return paths, nil
+}
+
+// Checksums are the etags of objects, which are md5 of objects uploaded
+// in a single part. Etags of multipart uploads aren't md5 and are left out
+func (s *ObjectStore) Checksums(ctx context.Context, bucket, prefix string) (map[string]string, error) {
+	objects, err := s.listObjects(ctx, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	checksums := map[string]string{}
+	for _, obj := range objects {
+		if etag := strings.Trim(aws.ToString(obj.ETag), `"`); !strings.Contains(etag, "-") {
+			checksums[aws.ToString(obj.Key)] = etag
+		}
+	}
+	return checksums, nil
+}
+
+// listObjects lists objects starting with prefix from all pages
+func (s *ObjectStore) listObjects(ctx context.Context, bucket, prefix string) ([]types.Object, error) {
+	var objects []types.Object
+	pages := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(wrapError(err), "failed to list objects of bucket %s", bucket)
+		}
+		objects = append(objects, page.Contents...)
+	}
+	return objects, nil
+}
+
+func objectKey(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
+
+// wrapError reports objects and buckets not found as ErrResourceNotFound
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound {
+		return store.ErrResourceNotFound
+	}
+	return errors.Wrap(err, "s3 request failed")
+}
+
+// NewObjectStore constructs store of s3 buckets reachable with credentials,
+// buckets are addressed by path on endpoint of credentials if set
+func NewObjectStore(creds Credentials, client HTTPClient) *ObjectStore {
+	opts := s3.Options{
+		Region:      creds.Region,
+		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)),
+		HTTPClient:  client,
+	}
+	if creds.Endpoint != "" {
+		opts.EndpointResolver = s3.EndpointResolverFromURL(strings.TrimSuffix(creds.Endpoint, "/"))
+		opts.UsePathStyle = true
+	}
+	return &ObjectStore{client: s3.New(opts)}
+}
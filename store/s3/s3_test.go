@@ -0,0 +1,142 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/odpf/optimus/store"
+	"github.com/stretchr/testify/assert"
+)
+
+type listBucketResult struct {
+	XMLName               xml.Name     `xml:"ListBucketResult"`
+	Contents              []objectInfo `xml:"Contents"`
+	IsTruncated           bool         `xml:"IsTruncated"`
+	NextContinuationToken string       `xml:"NextContinuationToken"`
+}
+
+type objectInfo struct {
+	Key  string `xml:"Key"`
+	ETag string `xml:"ETag"`
+}
+
+// fakeBucket serves objects of one bucket over the s3 rest api, listing
+// a page of two objects at a time
+type fakeBucket struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+}
+
+func (f *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key-id/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"+f.bucket), "/")
+	switch {
+	case r.Method == http.MethodPut:
+		body, _ := ioutil.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.Method == http.MethodGet && key == "":
+		var keys []string
+		for objKey := range f.objects {
+			if strings.HasPrefix(objKey, r.URL.Query().Get("prefix")) && objKey > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, objKey)
+			}
+		}
+		sort.Strings(keys)
+		var result listBucketResult
+		for i, objKey := range keys {
+			if i == 2 {
+				result.IsTruncated = true
+				result.NextContinuationToken = keys[1]
+				break
+			}
+			sum := md5.Sum(f.objects[objKey])
+			etag := `"` + hex.EncodeToString(sum[:]) + `"`
+			if strings.HasSuffix(objKey, ".multipart") {
+				etag = `"` + hex.EncodeToString(sum[:]) + `-2"`
+			}
+			result.Contents = append(result.Contents, objectInfo{Key: objKey, ETag: etag})
+		}
+		xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestObjectStore(t *testing.T) {
+	ctx := context.Background()
+	bucket := &fakeBucket{bucket: "dags", objects: map[string][]byte{}}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+	objStore := NewObjectStore(Credentials{AccessKeyID: "key-id", SecretAccessKey: "secret", Region: "eu-west-1", Endpoint: srv.URL}, srv.Client())
+
+	t.Run("should upload object once written", func(t *testing.T) {
+		w, err := objStore.NewWriter(ctx, "dags", "airflow/dags/ns/job 1.py")
+		assert.Nil(t, err)
+		_, err = w.Write([]byte("dag"))
+		assert.Nil(t, err)
+		assert.Nil(t, w.Close())
+		assert.Equal(t, []byte("dag"), bucket.objects["airflow/dags/ns/job 1.py"])
+
+		r, err := objStore.NewObjectReader(ctx, "dags", "airflow/dags/ns/job 1.py")
+		assert.Nil(t, err)
+		content, err := ioutil.ReadAll(r)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Close())
+		assert.Equal(t, "dag", string(content))
+	})
+	t.Run("should list objects of all pages", func(t *testing.T) {
+		for _, key := range []string{"airflow/dags/a.py", "airflow/dags/b.py", "airflow/dags/c.py", "other/d.py"} {
+			bucket.objects[key] = []byte("dag")
+		}
+		paths, err := objStore.List(ctx, "dags", "airflow/dags/")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"airflow/dags/a.py", "airflow/dags/b.py", "airflow/dags/c.py", "airflow/dags/ns/job 1.py"}, paths)
+	})
+	t.Run("should tell checksums of objects uploaded in single part", func(t *testing.T) {
+		bucket.objects["checksums/a.py"] = []byte("dag")
+		bucket.objects["checksums/b.multipart"] = []byte("dag")
+		checksums, err := objStore.Checksums(ctx, "dags", "checksums/")
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"checksums/a.py": "b4683fef34f6bb7234f2603699bd0ded"}, checksums)
+	})
+	t.Run("should report objects not found", func(t *testing.T) {
+		assert.Nil(t, objStore.Delete(ctx, "dags", "airflow/dags/a.py"))
+		assert.Equal(t, store.ErrResourceNotFound, objStore.Delete(ctx, "dags", "airflow/dags/a.py"))
+
+		_, err := objStore.NewObjectReader(ctx, "dags", "airflow/dags/a.py")
+		assert.Equal(t, store.ErrResourceNotFound, err)
+	})
+}
+
+func TestParseCredentials(t *testing.T) {
+	creds, err := ParseCredentials(`{"access_key_id": "key-id", "secret_access_key": "secret"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{AccessKeyID: "key-id", SecretAccessKey: "secret", Region: defaultRegion}, creds)
+
+	_, err = ParseCredentials(`{"region": "eu-west-1"}`)
+	assert.NotNil(t, err)
+}
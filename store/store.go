@@ -20,6 +20,8 @@ type ProjectJobSpecRepository interface {
 	GetByName(string) (models.JobSpec, models.NamespaceSpec, error)
 	GetAll() ([]models.JobSpec, error)
 	GetByDestination(string) (models.JobSpec, models.ProjectSpec, error)
+	// GetDestinations returns destinations of all jobs of the project
+	GetDestinations() ([]models.JobDestination, error)
 }
 
 // ProjectRepository represents a storage interface for registered projects
@@ -53,11 +55,36 @@ type JobRepository interface {
 	Delete(context.Context, models.NamespaceSpec, string) error
 }
 
+// JobChecksumRepository is a JobRepository able to tell checksums of stored
+// jobs without reading them, jobs compiled to the same contents are not
+// uploaded again
+type JobChecksumRepository interface {
+	// Checksums returns hex encoded md5 of contents of jobs of namespace
+	// by their names
+	Checksums(context.Context, models.NamespaceSpec) (map[string]string, error)
+}
+
 // InstanceSpecRepository represents a storage interface for Job runs generated by
 // a running instance of job
 type InstanceSpecRepository interface {
 	Save(models.InstanceSpec) error
 	GetByScheduledAt(time.Time) (models.InstanceSpec, error)
+	// GetLatest returns instances of latest scheduled runs first
+	GetLatest(limit int) ([]models.InstanceSpec, error)
+	// GetLatestByState returns instance of the latest scheduled run in the
+	// state, store.ErrResourceNotFound if no run is in it
+	GetLatestByState(state string) (models.InstanceSpec, error)
+	// Touch marks the instance as active at current time
+	Touch(time.Time) error
+	// UpdateState records the outcome of the run, e.g. success or failure
+	UpdateState(scheduledAt time.Time, state string) error
+	// Heartbeat records the run reported it is alive at the time, it
+	// doesn't mark the instance as active
+	Heartbeat(scheduledAt time.Time, at time.Time) error
+	// UpdateCost records what datastore jobs run by the run cost
+	UpdateCost(scheduledAt time.Time, cost models.InstanceCost) error
+	// GetSince returns instances of runs scheduled since the time, earliest first
+	GetSince(time.Time) ([]models.InstanceSpec, error)
 
 	// Clear will not delete the record but will reset all the run details
 	Clear(time.Time) error
@@ -88,6 +115,177 @@ type ObjectReader interface {
 	NewReader(bucket, path string) (io.ReadCloser, error)
 }
 
+// ObjectStore reads, lists and deletes objects along with writing them,
+// compiled jobs of schedulers are kept in it
+type ObjectStore interface {
+	ObjectWriter
+
+	// NewObjectReader returns ErrResourceNotFound if object doesn't exist
+	NewObjectReader(ctx context.Context, bucket, path string) (io.ReadCloser, error)
+	// List returns paths of objects in bucket starting with prefix
+	List(ctx context.Context, bucket, prefix string) ([]string, error)
+	// Delete returns ErrResourceNotFound if object doesn't exist
+	Delete(ctx context.Context, bucket, path string) error
+	// Checksums returns hex encoded md5 of objects in bucket starting with
+	// prefix by their paths, objects without md5 are left out
+	Checksums(ctx context.Context, bucket, prefix string) (map[string]string, error)
+}
+
+// AuditLogRepository represents a storage interface for audit entries
+type AuditLogRepository interface {
+	Insert(entry *models.AuditEntry) error
+	List(filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+// APITokenRepository represents a storage interface for api tokens
+type APITokenRepository interface {
+	Insert(token *models.APIToken) error
+	// GetByHash returns the token with the hash of its secret, revoked and
+	// expired ones included
+	GetByHash(hash string) (models.APIToken, error)
+	// GetAll returns tokens latest first
+	GetAll() ([]models.APIToken, error)
+	// Revoke stops accepting the token from at, revoking a revoked token
+	// keeps the time it was first revoked at
+	Revoke(id uuid.UUID, at time.Time) error
+}
+
+// RetentionRepository deletes rows of a project recorded before a time, at
+// most limit rows a call so expired rows are reclaimed in batches
+type RetentionRepository interface {
+	// DeleteInstances deletes instances of jobs scheduled before the time
+	DeleteInstances(project models.ProjectSpec, before time.Time, limit int) (int64, error)
+	// DeleteReplays deletes replays of jobs created before the time which
+	// reached an end state
+	DeleteReplays(project models.ProjectSpec, before time.Time, limit int) (int64, error)
+	// DeleteAuditLogs deletes audit logs of api calls made before the time
+	DeleteAuditLogs(project models.ProjectSpec, before time.Time, limit int) (int64, error)
+	// DeleteJobs deletes jobs of the project along with their runs, it's
+	// used to purge archived projects
+	DeleteJobs(project models.ProjectSpec, limit int) (int64, error)
+}
+
+// ProjectQuotaRepository represents a storage interface for quota and
+// usage of projects
+type ProjectQuotaRepository interface {
+	Save(models.ProjectSpec, models.ProjectQuota) error
+	GetByProject(models.ProjectSpec) (models.ProjectQuota, error)
+
+	// AddReplayRuns increments replay runs of the project in given day
+	AddReplayRuns(proj models.ProjectSpec, day time.Time, runs int) error
+	// ReserveReplayRuns increments replay runs of the project in given day
+	// only if they stay within limit, false if they would exceed it
+	ReserveReplayRuns(proj models.ProjectSpec, day time.Time, runs, limit int) (bool, error)
+	GetReplayRuns(proj models.ProjectSpec, day time.Time) (int, error)
+}
+
+// ProjectCostRepository represents a storage interface for cost of runs of
+// jobs of projects and alerts of their budgets
+type ProjectCostRepository interface {
+	// GetBytesBilled sums bytes billed by runs of jobs of the project
+	// scheduled since the time
+	GetBytesBilled(proj models.ProjectSpec, since time.Time) (int64, error)
+	// SaveBudgetAlert records threshold of budget of the project alerted in
+	// the month, false if it already was
+	SaveBudgetAlert(proj models.ProjectSpec, month time.Time, threshold int) (bool, error)
+}
+
+// ProjectFreezeRepository represents a storage interface for freeze of projects
+type ProjectFreezeRepository interface {
+	Save(models.ProjectSpec, models.ProjectFreeze) error
+	GetByProject(models.ProjectSpec) (models.ProjectFreeze, error)
+	Delete(models.ProjectSpec) error
+}
+
+// ReplicationStateRepository keeps the state records of projects were last
+// replicated with from another instance, projects are identified by name as
+// ids differ across instances
+type ReplicationStateRepository interface {
+	Save(projectName string, state models.ReplicationState) error
+	GetAll(projectName string) ([]models.ReplicationState, error)
+	Delete(projectName, key string) error
+}
+
+// JobLockRepository keeps locks of jobs held by operations, shared by all
+// servers using the database
+type JobLockRepository interface {
+	// Acquire locks all jobs of project for lock.Operation under lock.Token
+	// for ttl, or none of them if any is held by another operation in which
+	// case the conflicting lock is returned
+	Acquire(lock models.JobLock, jobNames []string, ttl time.Duration) (*models.JobLock, error)
+	// Extend pushes expiry of locks acquired under tokens to ttl from now
+	Extend(tokens []uuid.UUID, ttl time.Duration) error
+	Release(token uuid.UUID) error
+}
+
+// ProjectArchiveRepository represents a storage interface for archives of
+// projects
+type ProjectArchiveRepository interface {
+	Save(models.ProjectSpec, models.ProjectArchive) error
+	GetByProject(models.ProjectSpec) (models.ProjectArchive, error)
+	Delete(models.ProjectSpec) error
+}
+
+// DeployChangelogRepository represents a storage interface for changelog
+// of deployments of a project
+type DeployChangelogRepository interface {
+	Insert(models.ProjectSpec, *models.DeployChangelog) error
+	// GetLatest returns latest changelogs of the namespace first, changelogs
+	// of all namespaces are returned if namespace is empty
+	GetLatest(proj models.ProjectSpec, namespace string, limit int) ([]models.DeployChangelog, error)
+	// GetByID returns a changelog along with its snapshot, which is
+	// not loaded by GetLatest
+	GetByID(proj models.ProjectSpec, id uuid.UUID) (models.DeployChangelog, error)
+}
+
+// JobCheckpointRepository represents a storage interface for checkpoints
+// persisted by runs of jobs
+type JobCheckpointRepository interface {
+	// Save creates the checkpoint of job or replaces its value
+	Save(models.JobSpec, models.JobCheckpoint) error
+	GetAll(models.JobSpec) ([]models.JobCheckpoint, error)
+	Delete(jobSpec models.JobSpec, name string) error
+}
+
+// DeploymentRepository represents a storage interface for deployments
+// of a project requested over http
+type DeploymentRepository interface {
+	Insert(models.ProjectSpec, *models.Deployment) error
+	UpdateStatus(id uuid.UUID, status, message string) error
+	GetByID(models.ProjectSpec, uuid.UUID) (models.Deployment, error)
+}
+
+// OperationRepository represents a storage interface for long running
+// operations of projects
+type OperationRepository interface {
+	Insert(models.ProjectSpec, *models.Operation) error
+	UpdateProgress(id uuid.UUID, progress models.OperationProgress) error
+	// UpdateStatus stores status of operation along with its result, which
+	// is left as is if nil
+	UpdateStatus(id uuid.UUID, status, message string, result []byte) error
+	GetByID(models.ProjectSpec, uuid.UUID) (models.Operation, error)
+	// GetLatest returns latest operations of project first, operations of
+	// all kinds are returned if kind is empty
+	GetLatest(proj models.ProjectSpec, kind string, limit int) ([]models.Operation, error)
+}
+
+// SearchRepository represents a storage interface for finding jobs and
+// resources across projects
+type SearchRepository interface {
+	SearchJobs(models.SearchQuery) ([]models.JobSearchResult, error)
+	SearchResources(models.SearchQuery) ([]models.ResourceSearchResult, error)
+}
+
+// DestinationRegistry maps destinations to the jobs writing to them across
+// all projects, destinations shared intentionally have many producers
+type DestinationRegistry interface {
+	// GetProducers returns jobs writing to destination, ErrResourceNotFound
+	// if no job does
+	GetProducers(destination string) ([]models.DestinationProducer, error)
+	// List returns producers of destinations starting with prefix
+	List(prefix string, limit int) ([]models.DestinationProducer, error)
+}
+
 // ReplaySpecRepository represents a storage interface for replay objects
 type ReplaySpecRepository interface {
 	Insert(replay *models.ReplaySpec) error
@@ -95,4 +293,6 @@ type ReplaySpecRepository interface {
 	UpdateStatus(replayID uuid.UUID, status string, message models.ReplayMessage) error
 	GetByStatus(status []string) ([]models.ReplaySpec, error)
 	GetByJobIDAndStatus(jobID uuid.UUID, status []string) ([]models.ReplaySpec, error)
+	// GetByParentID returns replays of the replay plan parentID
+	GetByParentID(parentID uuid.UUID) ([]models.ReplaySpec, error)
 }
@@ -33,7 +33,7 @@ func TestValidator(t *testing.T) {
 				},
 				{
 					TestData: "@every 2h",
-					IsValid:  true,
+					IsValid:  false,
 				},
 				{
 					TestData: "0 2 * * *",
@@ -45,7 +45,7 @@ func TestValidator(t *testing.T) {
 				},
 				{
 					TestData: "@midnight",
-					IsValid:  true,
+					IsValid:  false,
 				},
 				{
 					TestData: "30 3-6,20-23 * * *",
@@ -55,6 +55,10 @@ func TestValidator(t *testing.T) {
 					TestData: "@daily",
 					IsValid:  true,
 				},
+				{
+					TestData: "@quarterly",
+					IsValid:  true,
+				},
 			}
 
 			for _, tcase := range cases {
@@ -133,7 +137,7 @@ func TestValidator(t *testing.T) {
 				},
 				{
 					TestData: "@every 2h",
-					IsValid:  true,
+					IsValid:  false,
 				},
 				{
 					TestData: "0 2 * * *",
@@ -145,7 +149,7 @@ func TestValidator(t *testing.T) {
 				},
 				{
 					TestData: "@midnight",
-					IsValid:  true,
+					IsValid:  false,
 				},
 				{
 					TestData: "30 3-6,20-23 * * *",
@@ -155,6 +159,10 @@ func TestValidator(t *testing.T) {
 					TestData: "@daily",
 					IsValid:  true,
 				},
+				{
+					TestData: "@quarterly",
+					IsValid:  true,
+				},
 			}
 
 			for _, tcase := range cases {
@@ -0,0 +1,287 @@
+// Read-only browser of optimus projects. Pages are addressed by the location
+// hash, e.g. #/project/p/namespace/n/job/j, and read everything from the
+// http api of the server serving the UI.
+"use strict";
+
+const content = document.getElementById("content");
+const breadcrumbs = document.getElementById("breadcrumbs");
+
+// levels of dependencies walked from a job in each direction
+const maxGraphDepth = 3;
+
+async function fetchJSON(path) {
+  const resp = await fetch(path, { headers: { Accept: "application/json" } });
+  const body = await resp.text();
+  if (!resp.ok) {
+    let message = body;
+    try {
+      message = JSON.parse(body).message || body;
+    } catch (e) {
+      // plain text error of a handler
+    }
+    throw new Error(`${path}: ${resp.status} ${message}`);
+  }
+  return JSON.parse(body);
+}
+
+function enc(value) {
+  return encodeURIComponent(value);
+}
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  Object.entries(attrs || {}).forEach(([key, value]) => {
+    if (value !== undefined && value !== null) {
+      node.setAttribute(key, value);
+    }
+  });
+  children.flat().forEach((child) => {
+    if (child === undefined || child === null) {
+      return;
+    }
+    node.appendChild(typeof child === "string" || typeof child === "number" ?
+      document.createTextNode(String(child)) : child);
+  });
+  return node;
+}
+
+function link(href, text) {
+  return el("a", { href }, text);
+}
+
+function state(value) {
+  return el("span", { class: `state-${value}` }, value || "-");
+}
+
+function table(header, rows, empty) {
+  if (rows.length === 0) {
+    return el("p", { class: "muted" }, empty);
+  }
+  return el("table", {},
+    el("thead", {}, el("tr", {}, header.map((h) => el("th", {}, h)))),
+    el("tbody", {}, rows.map((row) => el("tr", {}, row.map((cell) => el("td", {}, cell))))));
+}
+
+function formatLabels(labels) {
+  return Object.keys(labels || {}).sort().map((k) => `${k}=${labels[k]}`).join(", ");
+}
+
+function section(title, ...children) {
+  return [el("h2", {}, title), ...children];
+}
+
+// failing section shows its error instead of failing the whole page
+async function load(render) {
+  try {
+    return await render();
+  } catch (err) {
+    return el("p", { class: "error" }, err.message);
+  }
+}
+
+function replaysTable(replays, projectName, namespace) {
+  return table(["ID", "Job", "Window", "Status", "Queue", "Submitted"], replays.map((r) => [
+    link(`#/project/${enc(projectName)}/namespace/${enc(namespace)}/job/${enc(r.job_name)}/replay/${enc(r.id)}`, r.id),
+    r.job_name,
+    `${r.start_date} - ${r.end_date}`,
+    state(r.status),
+    r.queue_position ? `${r.queue_position}${r.queue_eta ? `, starts in ${r.queue_eta}` : ""}` : "-",
+    r.created_at,
+  ]), "no active replays");
+}
+
+async function projectsPage() {
+  const resp = await fetchJSON("/api/v1/project");
+  const projects = (resp.projects || []).map((p) => p.name).sort();
+  return section("Projects", table(["Project"], projects.map((name) => [
+    link(`#/project/${enc(name)}`, name),
+  ]), "no projects registered"));
+}
+
+async function projectPage(projectName) {
+  const namespaces = await load(async () => {
+    const resp = await fetchJSON(`/api/v1/project/${enc(projectName)}/namespace`);
+    const names = (resp.namespaces || []).map((n) => n.name).sort();
+    return table(["Namespace"], names.map((name) => [
+      link(`#/project/${enc(projectName)}/namespace/${enc(name)}`, name),
+    ]), "no namespaces registered");
+  });
+  return [...section("Namespaces", namespaces)];
+}
+
+async function listJobs(projectName, namespace) {
+  const resp = await fetchJSON(`/api/v1/project/${enc(projectName)}/job?namespace=${enc(namespace)}`);
+  return resp.jobs || [];
+}
+
+async function namespacePage(projectName, namespace) {
+  const [jobs, replays] = await Promise.all([
+    load(async () => {
+      const specs = await listJobs(projectName, namespace);
+      return table(["Job", "Task", "Owner", "Interval", "Labels"], specs.map((job) => [
+        link(`#/project/${enc(projectName)}/namespace/${enc(namespace)}/job/${enc(job.name)}`, job.name),
+        job.taskName || "",
+        job.owner || "",
+        job.interval || "",
+        formatLabels(job.labels),
+      ]), "no jobs deployed");
+    }),
+    load(async () => replaysTable(
+      await fetchJSON(`/replays?project=${enc(projectName)}&namespace=${enc(namespace)}`), projectName, namespace)),
+  ]);
+  return [...section("Jobs", jobs), ...section("Active replays", replays)];
+}
+
+// dependencyLevels walks dependencies of jobs in namespace from jobName, next
+// gives the names of jobs one step away. Levels are nearest first, a job is
+// placed in the nearest level it is reached at
+function dependencyLevels(jobName, next) {
+  const seen = new Set([jobName]);
+  const levels = [];
+  let current = [jobName];
+  for (let depth = 0; depth < maxGraphDepth && current.length > 0; depth++) {
+    const level = [];
+    current.forEach((name) => next(name).forEach((dep) => {
+      if (!seen.has(dep)) {
+        seen.add(dep);
+        level.push(dep);
+      }
+    }));
+    if (level.length > 0) {
+      levels.push(level.sort());
+    }
+    current = level;
+  }
+  return levels;
+}
+
+function dependencyGraph(projectName, namespace, jobName, jobs) {
+  const byName = new Map(jobs.map((job) => [job.name, job]));
+  const upstreams = (name) => ((byName.get(name) || {}).dependencies || []).map((dep) => dep.name);
+  const downstreams = new Map();
+  jobs.forEach((job) => (job.dependencies || []).forEach((dep) => {
+    downstreams.set(dep.name, [...(downstreams.get(dep.name) || []), job.name]);
+  }));
+
+  const node = (name) => (byName.has(name) ?
+    link(`#/project/${enc(projectName)}/namespace/${enc(namespace)}/job/${enc(name)}`, name) :
+    el("span", { title: "outside of namespace" }, name));
+  const up = dependencyLevels(jobName, upstreams).reverse();
+  const down = dependencyLevels(jobName, (name) => downstreams.get(name) || []);
+  if (up.length === 0 && down.length === 0) {
+    return el("p", { class: "muted" }, "no dependencies in namespace");
+  }
+  const columns = [];
+  up.forEach((level) => columns.push(el("div", { class: "level" }, level.map(node)), el("span", { class: "arrow" }, "→")));
+  columns.push(el("div", { class: "level" }, el("span", { class: "current" }, jobName)));
+  down.forEach((level) => columns.push(el("span", { class: "arrow" }, "→"), el("div", { class: "level" }, level.map(node))));
+  return el("div", { class: "graph" }, columns);
+}
+
+async function jobPage(projectName, namespace, jobName) {
+  const [details, runs, stats, replays] = await Promise.all([
+    load(async () => {
+      const jobs = await listJobs(projectName, namespace);
+      const job = jobs.find((j) => j.name === jobName);
+      if (!job) {
+        throw new Error(`job ${jobName} not found in namespace ${namespace}`);
+      }
+      const window = [job.windowSize, job.windowOffset, job.windowTruncateTo].filter(Boolean).join(", ");
+      return [
+        el("dl", {},
+          el("dt", {}, "Task"), el("dd", {}, job.taskName || ""),
+          el("dt", {}, "Owner"), el("dd", {}, job.owner || ""),
+          el("dt", {}, "Interval"), el("dd", {}, job.interval || ""),
+          el("dt", {}, "Start date"), el("dd", {}, job.startDate || ""),
+          el("dt", {}, "End date"), el("dd", {}, job.endDate || "-"),
+          el("dt", {}, "Window"), el("dd", {}, window || "-"),
+          el("dt", {}, "Labels"), el("dd", {}, formatLabels(job.labels) || "-"),
+          el("dt", {}, "Description"), el("dd", {}, job.description || "-")),
+        ...section("Dependency graph", dependencyGraph(projectName, namespace, jobName, jobs)),
+      ];
+    }),
+    load(async () => {
+      const resp = await fetchJSON(`/api/v1/project/${enc(projectName)}/job/${enc(jobName)}/status`);
+      const statuses = (resp.statuses || []).sort((a, b) => (a.scheduledAt < b.scheduledAt ? 1 : -1));
+      return table(["Scheduled at", "State"], statuses.map((s) => [s.scheduledAt, state(s.state)]), "no runs known to scheduler");
+    }),
+    load(async () => {
+      const s = await fetchJSON(`/job-stats?project=${enc(projectName)}&job=${enc(jobName)}`);
+      return el("dl", {},
+        el("dt", {}, "Since"), el("dd", {}, s.since),
+        el("dt", {}, "Runs"), el("dd", {}, `${s.runs}, succeeded ${s.succeeded}, failed ${s.failed}`),
+        el("dt", {}, "Success rate"), el("dd", {}, `${(s.success_rate * 100).toFixed(1)}%`),
+        el("dt", {}, "Duration"), el("dd", {}, `average ${s.average_duration}, p50 ${s.p50_duration}, p95 ${s.p95_duration}`),
+        el("dt", {}, "Failure streak"), el("dd", {}, `current ${s.current_failure_streak}, longest ${s.longest_failure_streak}`));
+    }),
+    load(async () => {
+      const all = await fetchJSON(`/replays?project=${enc(projectName)}&namespace=${enc(namespace)}`);
+      return replaysTable(all.filter((r) => r.job_name === jobName), projectName, namespace);
+    }),
+  ]);
+  return [
+    ...section(`Job ${jobName}`, details),
+    ...section("Run history", runs),
+    ...section("Run stats", stats),
+    ...section("Active replays", replays),
+  ];
+}
+
+async function replayPage(projectName, namespace, jobName, replayID) {
+  const r = await fetchJSON(`/replay-status?project=${enc(projectName)}&job=${enc(jobName)}&id=${enc(replayID)}`);
+  return section(`Replay ${r.id}`, el("dl", {},
+    el("dt", {}, "Job"), el("dd", {}, link(`#/project/${enc(projectName)}/namespace/${enc(namespace)}/job/${enc(jobName)}`, r.job_name)),
+    el("dt", {}, "Window"), el("dd", {}, `${r.start_date} - ${r.end_date}`),
+    el("dt", {}, "Status"), el("dd", {}, state(r.status)),
+    el("dt", {}, "Message"), el("dd", {}, r.message || "-"),
+    el("dt", {}, "Submitted"), el("dd", {}, r.created_at),
+    el("dt", {}, "Queue"), el("dd", {}, r.queue_position ?
+      `${r.queue_position}${r.queue_eta ? `, starts in ${r.queue_eta}` : ""}` : "-")));
+}
+
+// routes are matched against hash path segments, :names are captured in order
+const routes = [
+  ["", projectsPage],
+  ["project/:p", projectPage],
+  ["project/:p/namespace/:n", namespacePage],
+  ["project/:p/namespace/:n/job/:j", jobPage],
+  ["project/:p/namespace/:n/job/:j/replay/:r", replayPage],
+];
+
+function route(path) {
+  const segments = path.split("/").filter(Boolean).map(decodeURIComponent);
+  for (const [pattern, page] of routes) {
+    const parts = pattern.split("/").filter(Boolean);
+    if (parts.length !== segments.length) {
+      continue;
+    }
+    const params = [];
+    if (parts.every((part, i) => (part.startsWith(":") ? params.push(segments[i]) : part === segments[i]))) {
+      return { page, params, segments };
+    }
+  }
+  return null;
+}
+
+function renderBreadcrumbs(segments) {
+  breadcrumbs.replaceChildren();
+  for (let i = 1; i < segments.length; i += 2) {
+    const href = "#/" + segments.slice(0, i + 1).map(enc).join("/");
+    breadcrumbs.appendChild(link(href, segments[i]));
+  }
+}
+
+async function render() {
+  const match = route(location.hash.replace(/^#\/?/, ""));
+  if (!match) {
+    content.replaceChildren(el("p", { class: "error" }, "page not found"));
+    return;
+  }
+  renderBreadcrumbs(match.segments);
+  content.replaceChildren(el("p", { class: "muted" }, "loading..."));
+  const nodes = await load(() => match.page(...match.params));
+  content.replaceChildren(...[nodes].flat(Infinity));
+}
+
+window.addEventListener("hashchange", render);
+render();
@@ -0,0 +1,43 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/odpf/optimus/web"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("should serve index and assets of the UI", func(t *testing.T) {
+		handler := http.StripPrefix("/ui", web.Handler())
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), `<script src="app.js">`))
+		assert.Equal(t, "default-src 'self'", rec.Header().Get("Content-Security-Policy"))
+
+		for path, contentType := range map[string]string{
+			"/ui/app.js":    "javascript",
+			"/ui/style.css": "css",
+		} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+			assert.True(t, strings.Contains(rec.Header().Get("Content-Type"), contentType), path)
+		}
+	})
+	t.Run("should only serve reads", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		web.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+	t.Run("should return not found for unknown files", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		web.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/unknown.js", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}